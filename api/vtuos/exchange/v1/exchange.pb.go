@@ -0,0 +1,871 @@
+// Inter-vault data exchange.
+//
+// VT-UOS serves this API with `vtuos grpc-serve` so vault-to-vault sync tools
+// can read census summaries, resource inventories and facility status. The
+// service is read-only. Every call carries a peer token in the
+// "authorization" metadata ("Bearer <token>"); the token's clearance level
+// must meet the method's, see docs/CONFIGURATION.md.
+//
+// Regenerate the Go code with `make proto`.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.35.2
+// 	protoc        (unknown)
+// source: vtuos/exchange/v1/exchange.proto
+
+package exchangev1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetCensusSummaryRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *GetCensusSummaryRequest) Reset() {
+	*x = GetCensusSummaryRequest{}
+	mi := &file_vtuos_exchange_v1_exchange_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCensusSummaryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCensusSummaryRequest) ProtoMessage() {}
+
+func (x *GetCensusSummaryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vtuos_exchange_v1_exchange_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCensusSummaryRequest.ProtoReflect.Descriptor instead.
+func (*GetCensusSummaryRequest) Descriptor() ([]byte, []int) {
+	return file_vtuos_exchange_v1_exchange_proto_rawDescGZIP(), []int{0}
+}
+
+// GetCensusSummaryResponse counts residents by status.
+type GetCensusSummaryResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	VaultNumber int32 `protobuf:"varint,1,opt,name=vault_number,json=vaultNumber,proto3" json:"vault_number,omitempty"`
+	Total       int32 `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Active      int32 `protobuf:"varint,3,opt,name=active,proto3" json:"active,omitempty"`
+	Deceased    int32 `protobuf:"varint,4,opt,name=deceased,proto3" json:"deceased,omitempty"`
+	Exiled      int32 `protobuf:"varint,5,opt,name=exiled,proto3" json:"exiled,omitempty"`
+	OnMission   int32 `protobuf:"varint,6,opt,name=on_mission,json=onMission,proto3" json:"on_mission,omitempty"`
+	Quarantined int32 `protobuf:"varint,7,opt,name=quarantined,proto3" json:"quarantined,omitempty"`
+}
+
+func (x *GetCensusSummaryResponse) Reset() {
+	*x = GetCensusSummaryResponse{}
+	mi := &file_vtuos_exchange_v1_exchange_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCensusSummaryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCensusSummaryResponse) ProtoMessage() {}
+
+func (x *GetCensusSummaryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vtuos_exchange_v1_exchange_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCensusSummaryResponse.ProtoReflect.Descriptor instead.
+func (*GetCensusSummaryResponse) Descriptor() ([]byte, []int) {
+	return file_vtuos_exchange_v1_exchange_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetCensusSummaryResponse) GetVaultNumber() int32 {
+	if x != nil {
+		return x.VaultNumber
+	}
+	return 0
+}
+
+func (x *GetCensusSummaryResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *GetCensusSummaryResponse) GetActive() int32 {
+	if x != nil {
+		return x.Active
+	}
+	return 0
+}
+
+func (x *GetCensusSummaryResponse) GetDeceased() int32 {
+	if x != nil {
+		return x.Deceased
+	}
+	return 0
+}
+
+func (x *GetCensusSummaryResponse) GetExiled() int32 {
+	if x != nil {
+		return x.Exiled
+	}
+	return 0
+}
+
+func (x *GetCensusSummaryResponse) GetOnMission() int32 {
+	if x != nil {
+		return x.OnMission
+	}
+	return 0
+}
+
+func (x *GetCensusSummaryResponse) GetQuarantined() int32 {
+	if x != nil {
+		return x.Quarantined
+	}
+	return 0
+}
+
+type ListInventoryRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Resource category code, e.g. "FOOD"; empty for every category.
+	CategoryCode string `protobuf:"bytes,1,opt,name=category_code,json=categoryCode,proto3" json:"category_code,omitempty"`
+	// Stocks per page; 0 gives 50, and at most 100 are returned.
+	PageSize int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// next_page_token from the previous response; empty for the first page.
+	PageToken string `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+}
+
+func (x *ListInventoryRequest) Reset() {
+	*x = ListInventoryRequest{}
+	mi := &file_vtuos_exchange_v1_exchange_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListInventoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListInventoryRequest) ProtoMessage() {}
+
+func (x *ListInventoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vtuos_exchange_v1_exchange_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListInventoryRequest.ProtoReflect.Descriptor instead.
+func (*ListInventoryRequest) Descriptor() ([]byte, []int) {
+	return file_vtuos_exchange_v1_exchange_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListInventoryRequest) GetCategoryCode() string {
+	if x != nil {
+		return x.CategoryCode
+	}
+	return ""
+}
+
+func (x *ListInventoryRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListInventoryRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+// InventoryStock is one stock lot of a resource item.
+type InventoryStock struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ItemCode         string  `protobuf:"bytes,1,opt,name=item_code,json=itemCode,proto3" json:"item_code,omitempty"`
+	ItemName         string  `protobuf:"bytes,2,opt,name=item_name,json=itemName,proto3" json:"item_name,omitempty"`
+	CategoryCode     string  `protobuf:"bytes,3,opt,name=category_code,json=categoryCode,proto3" json:"category_code,omitempty"`
+	Unit             string  `protobuf:"bytes,4,opt,name=unit,proto3" json:"unit,omitempty"`
+	Quantity         float64 `protobuf:"fixed64,5,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	QuantityReserved float64 `protobuf:"fixed64,6,opt,name=quantity_reserved,json=quantityReserved,proto3" json:"quantity_reserved,omitempty"`
+	StorageLocation  string  `protobuf:"bytes,7,opt,name=storage_location,json=storageLocation,proto3" json:"storage_location,omitempty"`
+	Status           string  `protobuf:"bytes,8,opt,name=status,proto3" json:"status,omitempty"`
+	LotNumber        string  `protobuf:"bytes,9,opt,name=lot_number,json=lotNumber,proto3" json:"lot_number,omitempty"`
+	// YYYY-MM-DD; empty for non-perishable stock.
+	ExpirationDate string `protobuf:"bytes,10,opt,name=expiration_date,json=expirationDate,proto3" json:"expiration_date,omitempty"`
+}
+
+func (x *InventoryStock) Reset() {
+	*x = InventoryStock{}
+	mi := &file_vtuos_exchange_v1_exchange_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InventoryStock) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InventoryStock) ProtoMessage() {}
+
+func (x *InventoryStock) ProtoReflect() protoreflect.Message {
+	mi := &file_vtuos_exchange_v1_exchange_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InventoryStock.ProtoReflect.Descriptor instead.
+func (*InventoryStock) Descriptor() ([]byte, []int) {
+	return file_vtuos_exchange_v1_exchange_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *InventoryStock) GetItemCode() string {
+	if x != nil {
+		return x.ItemCode
+	}
+	return ""
+}
+
+func (x *InventoryStock) GetItemName() string {
+	if x != nil {
+		return x.ItemName
+	}
+	return ""
+}
+
+func (x *InventoryStock) GetCategoryCode() string {
+	if x != nil {
+		return x.CategoryCode
+	}
+	return ""
+}
+
+func (x *InventoryStock) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+func (x *InventoryStock) GetQuantity() float64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *InventoryStock) GetQuantityReserved() float64 {
+	if x != nil {
+		return x.QuantityReserved
+	}
+	return 0
+}
+
+func (x *InventoryStock) GetStorageLocation() string {
+	if x != nil {
+		return x.StorageLocation
+	}
+	return ""
+}
+
+func (x *InventoryStock) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *InventoryStock) GetLotNumber() string {
+	if x != nil {
+		return x.LotNumber
+	}
+	return ""
+}
+
+func (x *InventoryStock) GetExpirationDate() string {
+	if x != nil {
+		return x.ExpirationDate
+	}
+	return ""
+}
+
+type ListInventoryResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Stocks []*InventoryStock `protobuf:"bytes,1,rep,name=stocks,proto3" json:"stocks,omitempty"`
+	// Token for the next page; empty on the last page.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	TotalCount    int32  `protobuf:"varint,3,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+}
+
+func (x *ListInventoryResponse) Reset() {
+	*x = ListInventoryResponse{}
+	mi := &file_vtuos_exchange_v1_exchange_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListInventoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListInventoryResponse) ProtoMessage() {}
+
+func (x *ListInventoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vtuos_exchange_v1_exchange_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListInventoryResponse.ProtoReflect.Descriptor instead.
+func (*ListInventoryResponse) Descriptor() ([]byte, []int) {
+	return file_vtuos_exchange_v1_exchange_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListInventoryResponse) GetStocks() []*InventoryStock {
+	if x != nil {
+		return x.Stocks
+	}
+	return nil
+}
+
+func (x *ListInventoryResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *ListInventoryResponse) GetTotalCount() int32 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+type GetFacilityStatusRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *GetFacilityStatusRequest) Reset() {
+	*x = GetFacilityStatusRequest{}
+	mi := &file_vtuos_exchange_v1_exchange_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFacilityStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFacilityStatusRequest) ProtoMessage() {}
+
+func (x *GetFacilityStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vtuos_exchange_v1_exchange_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFacilityStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetFacilityStatusRequest) Descriptor() ([]byte, []int) {
+	return file_vtuos_exchange_v1_exchange_proto_rawDescGZIP(), []int{5}
+}
+
+// GetFacilityStatusResponse summarizes every facility category and utility
+// grid.
+type GetFacilityStatusResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Categories []*CategoryStatus `protobuf:"bytes,1,rep,name=categories,proto3" json:"categories,omitempty"`
+	Grids      []*GridBalance    `protobuf:"bytes,2,rep,name=grids,proto3" json:"grids,omitempty"`
+}
+
+func (x *GetFacilityStatusResponse) Reset() {
+	*x = GetFacilityStatusResponse{}
+	mi := &file_vtuos_exchange_v1_exchange_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFacilityStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFacilityStatusResponse) ProtoMessage() {}
+
+func (x *GetFacilityStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vtuos_exchange_v1_exchange_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFacilityStatusResponse.ProtoReflect.Descriptor instead.
+func (*GetFacilityStatusResponse) Descriptor() ([]byte, []int) {
+	return file_vtuos_exchange_v1_exchange_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetFacilityStatusResponse) GetCategories() []*CategoryStatus {
+	if x != nil {
+		return x.Categories
+	}
+	return nil
+}
+
+func (x *GetFacilityStatusResponse) GetGrids() []*GridBalance {
+	if x != nil {
+		return x.Grids
+	}
+	return nil
+}
+
+// CategoryStatus summarizes the systems of one facility category.
+type CategoryStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Category string `protobuf:"bytes,1,opt,name=category,proto3" json:"category,omitempty"`
+	Systems  int32  `protobuf:"varint,2,opt,name=systems,proto3" json:"systems,omitempty"`
+	Running  int32  `protobuf:"varint,3,opt,name=running,proto3" json:"running,omitempty"`
+	// Mean efficiency of all systems, counting stopped systems as 0.
+	EfficiencyPercent float64 `protobuf:"fixed64,4,opt,name=efficiency_percent,json=efficiencyPercent,proto3" json:"efficiency_percent,omitempty"`
+	// Most severe system status in the category.
+	WorstStatus string `protobuf:"bytes,5,opt,name=worst_status,json=worstStatus,proto3" json:"worst_status,omitempty"`
+}
+
+func (x *CategoryStatus) Reset() {
+	*x = CategoryStatus{}
+	mi := &file_vtuos_exchange_v1_exchange_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CategoryStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CategoryStatus) ProtoMessage() {}
+
+func (x *CategoryStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_vtuos_exchange_v1_exchange_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CategoryStatus.ProtoReflect.Descriptor instead.
+func (*CategoryStatus) Descriptor() ([]byte, []int) {
+	return file_vtuos_exchange_v1_exchange_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CategoryStatus) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *CategoryStatus) GetSystems() int32 {
+	if x != nil {
+		return x.Systems
+	}
+	return 0
+}
+
+func (x *CategoryStatus) GetRunning() int32 {
+	if x != nil {
+		return x.Running
+	}
+	return 0
+}
+
+func (x *CategoryStatus) GetEfficiencyPercent() float64 {
+	if x != nil {
+		return x.EfficiencyPercent
+	}
+	return 0
+}
+
+func (x *CategoryStatus) GetWorstStatus() string {
+	if x != nil {
+		return x.WorstStatus
+	}
+	return ""
+}
+
+// GridBalance is the supply and demand position of one utility grid.
+type GridBalance struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Grid      string  `protobuf:"bytes,1,opt,name=grid,proto3" json:"grid,omitempty"`
+	Unit      string  `protobuf:"bytes,2,opt,name=unit,proto3" json:"unit,omitempty"`
+	Capacity  float64 `protobuf:"fixed64,3,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	Supply    float64 `protobuf:"fixed64,4,opt,name=supply,proto3" json:"supply,omitempty"`
+	Demand    float64 `protobuf:"fixed64,5,opt,name=demand,proto3" json:"demand,omitempty"`
+	Producers int32   `protobuf:"varint,6,opt,name=producers,proto3" json:"producers,omitempty"`
+	Stopped   int32   `protobuf:"varint,7,opt,name=stopped,proto3" json:"stopped,omitempty"`
+	// NOMINAL, TIGHT, DEFICIT or NO DATA.
+	State string `protobuf:"bytes,8,opt,name=state,proto3" json:"state,omitempty"`
+}
+
+func (x *GridBalance) Reset() {
+	*x = GridBalance{}
+	mi := &file_vtuos_exchange_v1_exchange_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GridBalance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GridBalance) ProtoMessage() {}
+
+func (x *GridBalance) ProtoReflect() protoreflect.Message {
+	mi := &file_vtuos_exchange_v1_exchange_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GridBalance.ProtoReflect.Descriptor instead.
+func (*GridBalance) Descriptor() ([]byte, []int) {
+	return file_vtuos_exchange_v1_exchange_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GridBalance) GetGrid() string {
+	if x != nil {
+		return x.Grid
+	}
+	return ""
+}
+
+func (x *GridBalance) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+func (x *GridBalance) GetCapacity() float64 {
+	if x != nil {
+		return x.Capacity
+	}
+	return 0
+}
+
+func (x *GridBalance) GetSupply() float64 {
+	if x != nil {
+		return x.Supply
+	}
+	return 0
+}
+
+func (x *GridBalance) GetDemand() float64 {
+	if x != nil {
+		return x.Demand
+	}
+	return 0
+}
+
+func (x *GridBalance) GetProducers() int32 {
+	if x != nil {
+		return x.Producers
+	}
+	return 0
+}
+
+func (x *GridBalance) GetStopped() int32 {
+	if x != nil {
+		return x.Stopped
+	}
+	return 0
+}
+
+func (x *GridBalance) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+var File_vtuos_exchange_v1_exchange_proto protoreflect.FileDescriptor
+
+var file_vtuos_exchange_v1_exchange_proto_rawDesc = []byte{
+	0x0a, 0x20, 0x76, 0x74, 0x75, 0x6f, 0x73, 0x2f, 0x65, 0x78, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65,
+	0x2f, 0x76, 0x31, 0x2f, 0x65, 0x78, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x12, 0x11, 0x76, 0x74, 0x75, 0x6f, 0x73, 0x2e, 0x65, 0x78, 0x63, 0x68, 0x61, 0x6e,
+	0x67, 0x65, 0x2e, 0x76, 0x31, 0x22, 0x19, 0x0a, 0x17, 0x47, 0x65, 0x74, 0x43, 0x65, 0x6e, 0x73,
+	0x75, 0x73, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x22, 0xe0, 0x01, 0x0a, 0x18, 0x47, 0x65, 0x74, 0x43, 0x65, 0x6e, 0x73, 0x75, 0x73, 0x53, 0x75,
+	0x6d, 0x6d, 0x61, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x21, 0x0a,
+	0x0c, 0x76, 0x61, 0x75, 0x6c, 0x74, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x0b, 0x76, 0x61, 0x75, 0x6c, 0x74, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72,
+	0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x12, 0x1a,
+	0x0a, 0x08, 0x64, 0x65, 0x63, 0x65, 0x61, 0x73, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x08, 0x64, 0x65, 0x63, 0x65, 0x61, 0x73, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x65, 0x78,
+	0x69, 0x6c, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x65, 0x78, 0x69, 0x6c,
+	0x65, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x6f, 0x6e, 0x5f, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x6f, 0x6e, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x12, 0x20, 0x0a, 0x0b, 0x71, 0x75, 0x61, 0x72, 0x61, 0x6e, 0x74, 0x69, 0x6e, 0x65, 0x64,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x71, 0x75, 0x61, 0x72, 0x61, 0x6e, 0x74, 0x69,
+	0x6e, 0x65, 0x64, 0x22, 0x77, 0x0a, 0x14, 0x4c, 0x69, 0x73, 0x74, 0x49, 0x6e, 0x76, 0x65, 0x6e,
+	0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x63,
+	0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0c, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x43, 0x6f, 0x64, 0x65,
+	0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x61, 0x67, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x1d, 0x0a,
+	0x0a, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x70, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0xd7, 0x02, 0x0a,
+	0x0e, 0x49, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x12,
+	0x1b, 0x0a, 0x09, 0x69, 0x74, 0x65, 0x6d, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x69, 0x74, 0x65, 0x6d, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x1b, 0x0a, 0x09,
+	0x69, 0x74, 0x65, 0x6d, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x69, 0x74, 0x65, 0x6d, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x63, 0x61, 0x74,
+	0x65, 0x67, 0x6f, 0x72, 0x79, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0c, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x12,
+	0x0a, 0x04, 0x75, 0x6e, 0x69, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x6e,
+	0x69, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x71, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x71, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x2b,
+	0x0a, 0x11, 0x71, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x5f, 0x72, 0x65, 0x73, 0x65, 0x72,
+	0x76, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01, 0x52, 0x10, 0x71, 0x75, 0x61, 0x6e, 0x74,
+	0x69, 0x74, 0x79, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x64, 0x12, 0x29, 0x0a, 0x10, 0x73,
+	0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x5f, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x4c, 0x6f,
+	0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1d,
+	0x0a, 0x0a, 0x6c, 0x6f, 0x74, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x09, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x6c, 0x6f, 0x74, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x27, 0x0a,
+	0x0f, 0x65, 0x78, 0x70, 0x69, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x64, 0x61, 0x74, 0x65,
+	0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x65, 0x78, 0x70, 0x69, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x44, 0x61, 0x74, 0x65, 0x22, 0x9b, 0x01, 0x0a, 0x15, 0x4c, 0x69, 0x73, 0x74, 0x49,
+	0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x39, 0x0a, 0x06, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x21, 0x2e, 0x76, 0x74, 0x75, 0x6f, 0x73, 0x2e, 0x65, 0x78, 0x63, 0x68, 0x61, 0x6e, 0x67,
+	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x53, 0x74,
+	0x6f, 0x63, 0x6b, 0x52, 0x06, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x12, 0x26, 0x0a, 0x0f, 0x6e,
+	0x65, 0x78, 0x74, 0x5f, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x6e, 0x65, 0x78, 0x74, 0x50, 0x61, 0x67, 0x65, 0x54, 0x6f,
+	0x6b, 0x65, 0x6e, 0x12, 0x1f, 0x0a, 0x0b, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x43,
+	0x6f, 0x75, 0x6e, 0x74, 0x22, 0x1a, 0x0a, 0x18, 0x47, 0x65, 0x74, 0x46, 0x61, 0x63, 0x69, 0x6c,
+	0x69, 0x74, 0x79, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x22, 0x94, 0x01, 0x0a, 0x19, 0x47, 0x65, 0x74, 0x46, 0x61, 0x63, 0x69, 0x6c, 0x69, 0x74, 0x79,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x41,
+	0x0a, 0x0a, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x21, 0x2e, 0x76, 0x74, 0x75, 0x6f, 0x73, 0x2e, 0x65, 0x78, 0x63, 0x68, 0x61,
+	0x6e, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x0a, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x69, 0x65,
+	0x73, 0x12, 0x34, 0x0a, 0x05, 0x67, 0x72, 0x69, 0x64, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x1e, 0x2e, 0x76, 0x74, 0x75, 0x6f, 0x73, 0x2e, 0x65, 0x78, 0x63, 0x68, 0x61, 0x6e, 0x67,
+	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x72, 0x69, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65,
+	0x52, 0x05, 0x67, 0x72, 0x69, 0x64, 0x73, 0x22, 0xb2, 0x01, 0x0a, 0x0e, 0x43, 0x61, 0x74, 0x65,
+	0x67, 0x6f, 0x72, 0x79, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x61,
+	0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x61,
+	0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d,
+	0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x73,
+	0x12, 0x18, 0x0a, 0x07, 0x72, 0x75, 0x6e, 0x6e, 0x69, 0x6e, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x07, 0x72, 0x75, 0x6e, 0x6e, 0x69, 0x6e, 0x67, 0x12, 0x2d, 0x0a, 0x12, 0x65, 0x66,
+	0x66, 0x69, 0x63, 0x69, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x11, 0x65, 0x66, 0x66, 0x69, 0x63, 0x69, 0x65, 0x6e,
+	0x63, 0x79, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x77, 0x6f, 0x72,
+	0x73, 0x74, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x77, 0x6f, 0x72, 0x73, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0xcf, 0x01, 0x0a,
+	0x0b, 0x47, 0x72, 0x69, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x67, 0x72, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x67, 0x72, 0x69, 0x64,
+	0x12, 0x12, 0x0a, 0x04, 0x75, 0x6e, 0x69, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x75, 0x6e, 0x69, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79,
+	0x12, 0x16, 0x0a, 0x06, 0x73, 0x75, 0x70, 0x70, 0x6c, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x06, 0x73, 0x75, 0x70, 0x70, 0x6c, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x65, 0x6d, 0x61,
+	0x6e, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x64, 0x65, 0x6d, 0x61, 0x6e, 0x64,
+	0x12, 0x1c, 0x0a, 0x09, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x65, 0x72, 0x73, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x65, 0x72, 0x73, 0x12, 0x18,
+	0x0a, 0x07, 0x73, 0x74, 0x6f, 0x70, 0x70, 0x65, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x07, 0x73, 0x74, 0x6f, 0x70, 0x70, 0x65, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74,
+	0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x32, 0xd2,
+	0x02, 0x0a, 0x0f, 0x45, 0x78, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x12, 0x6b, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x43, 0x65, 0x6e, 0x73, 0x75, 0x73, 0x53,
+	0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x12, 0x2a, 0x2e, 0x76, 0x74, 0x75, 0x6f, 0x73, 0x2e, 0x65,
+	0x78, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x65,
+	0x6e, 0x73, 0x75, 0x73, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x2b, 0x2e, 0x76, 0x74, 0x75, 0x6f, 0x73, 0x2e, 0x65, 0x78, 0x63, 0x68, 0x61,
+	0x6e, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x65, 0x6e, 0x73, 0x75, 0x73,
+	0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x62, 0x0a, 0x0d, 0x4c, 0x69, 0x73, 0x74, 0x49, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79,
+	0x12, 0x27, 0x2e, 0x76, 0x74, 0x75, 0x6f, 0x73, 0x2e, 0x65, 0x78, 0x63, 0x68, 0x61, 0x6e, 0x67,
+	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x49, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f,
+	0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x28, 0x2e, 0x76, 0x74, 0x75, 0x6f,
+	0x73, 0x2e, 0x65, 0x78, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69,
+	0x73, 0x74, 0x49, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x6e, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x46, 0x61, 0x63, 0x69, 0x6c, 0x69,
+	0x74, 0x79, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x2b, 0x2e, 0x76, 0x74, 0x75, 0x6f, 0x73,
+	0x2e, 0x65, 0x78, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74,
+	0x46, 0x61, 0x63, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2c, 0x2e, 0x76, 0x74, 0x75, 0x6f, 0x73, 0x2e, 0x65, 0x78,
+	0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x46, 0x61, 0x63,
+	0x69, 0x6c, 0x69, 0x74, 0x79, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x42, 0x39, 0x5a, 0x37, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
+	0x6d, 0x2f, 0x76, 0x74, 0x75, 0x6f, 0x73, 0x2f, 0x76, 0x74, 0x75, 0x6f, 0x73, 0x2f, 0x61, 0x70,
+	0x69, 0x2f, 0x76, 0x74, 0x75, 0x6f, 0x73, 0x2f, 0x65, 0x78, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65,
+	0x2f, 0x76, 0x31, 0x3b, 0x65, 0x78, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x76, 0x31, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_vtuos_exchange_v1_exchange_proto_rawDescOnce sync.Once
+	file_vtuos_exchange_v1_exchange_proto_rawDescData = file_vtuos_exchange_v1_exchange_proto_rawDesc
+)
+
+func file_vtuos_exchange_v1_exchange_proto_rawDescGZIP() []byte {
+	file_vtuos_exchange_v1_exchange_proto_rawDescOnce.Do(func() {
+		file_vtuos_exchange_v1_exchange_proto_rawDescData = protoimpl.X.CompressGZIP(file_vtuos_exchange_v1_exchange_proto_rawDescData)
+	})
+	return file_vtuos_exchange_v1_exchange_proto_rawDescData
+}
+
+var file_vtuos_exchange_v1_exchange_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_vtuos_exchange_v1_exchange_proto_goTypes = []any{
+	(*GetCensusSummaryRequest)(nil),   // 0: vtuos.exchange.v1.GetCensusSummaryRequest
+	(*GetCensusSummaryResponse)(nil),  // 1: vtuos.exchange.v1.GetCensusSummaryResponse
+	(*ListInventoryRequest)(nil),      // 2: vtuos.exchange.v1.ListInventoryRequest
+	(*InventoryStock)(nil),            // 3: vtuos.exchange.v1.InventoryStock
+	(*ListInventoryResponse)(nil),     // 4: vtuos.exchange.v1.ListInventoryResponse
+	(*GetFacilityStatusRequest)(nil),  // 5: vtuos.exchange.v1.GetFacilityStatusRequest
+	(*GetFacilityStatusResponse)(nil), // 6: vtuos.exchange.v1.GetFacilityStatusResponse
+	(*CategoryStatus)(nil),            // 7: vtuos.exchange.v1.CategoryStatus
+	(*GridBalance)(nil),               // 8: vtuos.exchange.v1.GridBalance
+}
+var file_vtuos_exchange_v1_exchange_proto_depIdxs = []int32{
+	3, // 0: vtuos.exchange.v1.ListInventoryResponse.stocks:type_name -> vtuos.exchange.v1.InventoryStock
+	7, // 1: vtuos.exchange.v1.GetFacilityStatusResponse.categories:type_name -> vtuos.exchange.v1.CategoryStatus
+	8, // 2: vtuos.exchange.v1.GetFacilityStatusResponse.grids:type_name -> vtuos.exchange.v1.GridBalance
+	0, // 3: vtuos.exchange.v1.ExchangeService.GetCensusSummary:input_type -> vtuos.exchange.v1.GetCensusSummaryRequest
+	2, // 4: vtuos.exchange.v1.ExchangeService.ListInventory:input_type -> vtuos.exchange.v1.ListInventoryRequest
+	5, // 5: vtuos.exchange.v1.ExchangeService.GetFacilityStatus:input_type -> vtuos.exchange.v1.GetFacilityStatusRequest
+	1, // 6: vtuos.exchange.v1.ExchangeService.GetCensusSummary:output_type -> vtuos.exchange.v1.GetCensusSummaryResponse
+	4, // 7: vtuos.exchange.v1.ExchangeService.ListInventory:output_type -> vtuos.exchange.v1.ListInventoryResponse
+	6, // 8: vtuos.exchange.v1.ExchangeService.GetFacilityStatus:output_type -> vtuos.exchange.v1.GetFacilityStatusResponse
+	6, // [6:9] is the sub-list for method output_type
+	3, // [3:6] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_vtuos_exchange_v1_exchange_proto_init() }
+func file_vtuos_exchange_v1_exchange_proto_init() {
+	if File_vtuos_exchange_v1_exchange_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_vtuos_exchange_v1_exchange_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_vtuos_exchange_v1_exchange_proto_goTypes,
+		DependencyIndexes: file_vtuos_exchange_v1_exchange_proto_depIdxs,
+		MessageInfos:      file_vtuos_exchange_v1_exchange_proto_msgTypes,
+	}.Build()
+	File_vtuos_exchange_v1_exchange_proto = out.File
+	file_vtuos_exchange_v1_exchange_proto_rawDesc = nil
+	file_vtuos_exchange_v1_exchange_proto_goTypes = nil
+	file_vtuos_exchange_v1_exchange_proto_depIdxs = nil
+}
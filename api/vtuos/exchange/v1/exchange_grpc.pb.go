@@ -0,0 +1,219 @@
+// Inter-vault data exchange.
+//
+// VT-UOS serves this API with `vtuos grpc-serve` so vault-to-vault sync tools
+// can read census summaries, resource inventories and facility status. The
+// service is read-only. Every call carries a peer token in the
+// "authorization" metadata ("Bearer <token>"); the token's clearance level
+// must meet the method's, see docs/CONFIGURATION.md.
+//
+// Regenerate the Go code with `make proto`.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: vtuos/exchange/v1/exchange.proto
+
+package exchangev1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ExchangeService_GetCensusSummary_FullMethodName  = "/vtuos.exchange.v1.ExchangeService/GetCensusSummary"
+	ExchangeService_ListInventory_FullMethodName     = "/vtuos.exchange.v1.ExchangeService/ListInventory"
+	ExchangeService_GetFacilityStatus_FullMethodName = "/vtuos.exchange.v1.ExchangeService/GetFacilityStatus"
+)
+
+// ExchangeServiceClient is the client API for ExchangeService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// ExchangeService exposes vault data to other vaults.
+type ExchangeServiceClient interface {
+	// GetCensusSummary returns resident counts by status. Clearance 2.
+	GetCensusSummary(ctx context.Context, in *GetCensusSummaryRequest, opts ...grpc.CallOption) (*GetCensusSummaryResponse, error)
+	// ListInventory returns resource stocks, a page at a time. Clearance 3.
+	ListInventory(ctx context.Context, in *ListInventoryRequest, opts ...grpc.CallOption) (*ListInventoryResponse, error)
+	// GetFacilityStatus returns facility category and utility grid status.
+	// Clearance 4.
+	GetFacilityStatus(ctx context.Context, in *GetFacilityStatusRequest, opts ...grpc.CallOption) (*GetFacilityStatusResponse, error)
+}
+
+type exchangeServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewExchangeServiceClient(cc grpc.ClientConnInterface) ExchangeServiceClient {
+	return &exchangeServiceClient{cc}
+}
+
+func (c *exchangeServiceClient) GetCensusSummary(ctx context.Context, in *GetCensusSummaryRequest, opts ...grpc.CallOption) (*GetCensusSummaryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCensusSummaryResponse)
+	err := c.cc.Invoke(ctx, ExchangeService_GetCensusSummary_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *exchangeServiceClient) ListInventory(ctx context.Context, in *ListInventoryRequest, opts ...grpc.CallOption) (*ListInventoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListInventoryResponse)
+	err := c.cc.Invoke(ctx, ExchangeService_ListInventory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *exchangeServiceClient) GetFacilityStatus(ctx context.Context, in *GetFacilityStatusRequest, opts ...grpc.CallOption) (*GetFacilityStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetFacilityStatusResponse)
+	err := c.cc.Invoke(ctx, ExchangeService_GetFacilityStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ExchangeServiceServer is the server API for ExchangeService service.
+// All implementations must embed UnimplementedExchangeServiceServer
+// for forward compatibility.
+//
+// ExchangeService exposes vault data to other vaults.
+type ExchangeServiceServer interface {
+	// GetCensusSummary returns resident counts by status. Clearance 2.
+	GetCensusSummary(context.Context, *GetCensusSummaryRequest) (*GetCensusSummaryResponse, error)
+	// ListInventory returns resource stocks, a page at a time. Clearance 3.
+	ListInventory(context.Context, *ListInventoryRequest) (*ListInventoryResponse, error)
+	// GetFacilityStatus returns facility category and utility grid status.
+	// Clearance 4.
+	GetFacilityStatus(context.Context, *GetFacilityStatusRequest) (*GetFacilityStatusResponse, error)
+	mustEmbedUnimplementedExchangeServiceServer()
+}
+
+// UnimplementedExchangeServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedExchangeServiceServer struct{}
+
+func (UnimplementedExchangeServiceServer) GetCensusSummary(context.Context, *GetCensusSummaryRequest) (*GetCensusSummaryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCensusSummary not implemented")
+}
+func (UnimplementedExchangeServiceServer) ListInventory(context.Context, *ListInventoryRequest) (*ListInventoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListInventory not implemented")
+}
+func (UnimplementedExchangeServiceServer) GetFacilityStatus(context.Context, *GetFacilityStatusRequest) (*GetFacilityStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFacilityStatus not implemented")
+}
+func (UnimplementedExchangeServiceServer) mustEmbedUnimplementedExchangeServiceServer() {}
+func (UnimplementedExchangeServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeExchangeServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ExchangeServiceServer will
+// result in compilation errors.
+type UnsafeExchangeServiceServer interface {
+	mustEmbedUnimplementedExchangeServiceServer()
+}
+
+func RegisterExchangeServiceServer(s grpc.ServiceRegistrar, srv ExchangeServiceServer) {
+	// If the following call pancis, it indicates UnimplementedExchangeServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ExchangeService_ServiceDesc, srv)
+}
+
+func _ExchangeService_GetCensusSummary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCensusSummaryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExchangeServiceServer).GetCensusSummary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExchangeService_GetCensusSummary_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExchangeServiceServer).GetCensusSummary(ctx, req.(*GetCensusSummaryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExchangeService_ListInventory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListInventoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExchangeServiceServer).ListInventory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExchangeService_ListInventory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExchangeServiceServer).ListInventory(ctx, req.(*ListInventoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExchangeService_GetFacilityStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFacilityStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExchangeServiceServer).GetFacilityStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExchangeService_GetFacilityStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExchangeServiceServer).GetFacilityStatus(ctx, req.(*GetFacilityStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ExchangeService_ServiceDesc is the grpc.ServiceDesc for ExchangeService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ExchangeService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "vtuos.exchange.v1.ExchangeService",
+	HandlerType: (*ExchangeServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetCensusSummary",
+			Handler:    _ExchangeService_GetCensusSummary_Handler,
+		},
+		{
+			MethodName: "ListInventory",
+			Handler:    _ExchangeService_ListInventory_Handler,
+		},
+		{
+			MethodName: "GetFacilityStatus",
+			Handler:    _ExchangeService_GetFacilityStatus_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "vtuos/exchange/v1/exchange.proto",
+}
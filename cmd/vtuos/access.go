@@ -0,0 +1,306 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/config"
+	"github.com/vtuos/vtuos/internal/database"
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/services/population"
+	"github.com/vtuos/vtuos/internal/services/security"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// runAccessCommand handles `vtuos access <subcommand>`: register doors and
+// airlocks, set their clearance, grant and revoke residents' access, log a
+// credential presented at a point and review the access log.
+func runAccessCommand(ctx context.Context, configPath, profile string, args []string) error {
+	if len(args) == 0 {
+		flag.Usage()
+		return fmt.Errorf("access requires a subcommand: points, add-point, enable, disable, clearance, grant, revoke, grants, try or log")
+	}
+
+	cfg, err := loadConfig(configPath, profile)
+	if err != nil {
+		return fmt.Errorf("loading configuration: %w", err)
+	}
+
+	dbPath, err := config.EnsureDataDir(cfg)
+	if err != nil {
+		return fmt.Errorf("ensuring data directory: %w", err)
+	}
+	backupDir, err := config.BackupDir(cfg)
+	if err != nil {
+		return fmt.Errorf("getting backup directory: %w", err)
+	}
+
+	db, err := database.Open(dbPath, &cfg.Database, backupDir)
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	j, err := openJournal(cfg, nil)
+	if err != nil {
+		return err
+	}
+	defer j.Close()
+
+	svc := security.NewService(db.DB)
+	svc.SetVault(cfg.Vault.Number)
+	svc.SetJournal(j)
+	popSvc := population.NewService(db.DB, cfg.Vault.Number)
+	now := time.Now().UTC()
+	if startTime, err := cfg.Simulation.StartDateTime(); err == nil {
+		svc.SetClock(util.NewVaultClock(startTime, 0))
+		now = startTime.UTC()
+	}
+
+	switch args[0] {
+	case "points":
+		points, err := svc.ListAccessPoints(ctx)
+		if err != nil {
+			return fmt.Errorf("listing access points: %w", err)
+		}
+		if len(points) == 0 {
+			fmt.Println("No access points registered")
+			return nil
+		}
+		for _, p := range points {
+			status := "ACTIVE"
+			if !p.IsActive {
+				status = "DISABLED"
+			}
+			fmt.Printf("%-16s %-28s %-7s %-10s L%-3d clearance %-2d %s\n", p.Code, p.Name, p.Kind,
+				p.LocationSector, p.LocationLevel, p.MinClearance, status)
+		}
+		return nil
+	case "add-point":
+		fs := flag.NewFlagSet("add-point", flag.ContinueOnError)
+		name := fs.String("name", "", "Name of the door or airlock, e.g. \"Surface Airlock\"")
+		sector := fs.String("sector", "", "Sector the point is in")
+		level := fs.Int("level", 0, "Level the point is on")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if fs.NArg() != 3 {
+			return fmt.Errorf("access add-point requires a code, a kind (door or airlock) and a minimum clearance")
+		}
+		kind, err := models.ParseAccessPointKind(fs.Arg(1))
+		if err != nil {
+			return err
+		}
+		clearance, err := strconv.Atoi(fs.Arg(2))
+		if err != nil {
+			return fmt.Errorf("invalid clearance %q: %w", fs.Arg(2), err)
+		}
+		if *name == "" {
+			*name = fs.Arg(0)
+		}
+
+		point, err := svc.CreateAccessPoint(ctx, security.AccessPointInput{
+			Code:         fs.Arg(0),
+			Name:         *name,
+			Kind:         kind,
+			Sector:       *sector,
+			Level:        *level,
+			MinClearance: clearance,
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Registered %s %s (%s), clearance %d\n", point.Kind, point.Code, point.Name, point.MinClearance)
+		return nil
+	case "enable", "disable":
+		if len(args) != 2 {
+			return fmt.Errorf("access %s requires an access point code", args[0])
+		}
+		point, err := svc.GetAccessPointByCode(ctx, args[1])
+		if err != nil {
+			return err
+		}
+		if point, err = svc.SetAccessPointActive(ctx, point.ID, args[0] == "enable"); err != nil {
+			return err
+		}
+		fmt.Printf("%s %sd\n", point.Code, args[0])
+		return nil
+	case "clearance":
+		if len(args) != 3 {
+			return fmt.Errorf("access clearance requires an access point code and a clearance level")
+		}
+		clearance, err := strconv.Atoi(args[2])
+		if err != nil {
+			return fmt.Errorf("invalid clearance %q: %w", args[2], err)
+		}
+		point, err := svc.GetAccessPointByCode(ctx, args[1])
+		if err != nil {
+			return err
+		}
+		if point, err = svc.SetMinClearance(ctx, point.ID, clearance); err != nil {
+			return err
+		}
+		fmt.Printf("%s now admits clearance %d and above\n", point.Code, point.MinClearance)
+		return nil
+	case "grant":
+		fs := flag.NewFlagSet("grant", flag.ContinueOnError)
+		days := fs.Int("days", 0, "Days the grant lasts (default: until revoked)")
+		by := fs.String("by", "", "Registry number of the granting officer")
+		reason := fs.String("reason", "", "Reason recorded with the grant")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if fs.NArg() != 2 {
+			return fmt.Errorf("access grant requires an access point code and a registry number")
+		}
+		point, err := svc.GetAccessPointByCode(ctx, fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		resident, err := popSvc.GetResidentByRegistryNumber(ctx, fs.Arg(1))
+		if err != nil {
+			return fmt.Errorf("resident %s: %w", fs.Arg(1), err)
+		}
+
+		input := security.GrantInput{ResidentID: resident.ID, AccessPointID: point.ID, Reason: *reason}
+		if *by != "" {
+			officer, err := popSvc.GetResidentByRegistryNumber(ctx, *by)
+			if err != nil {
+				return fmt.Errorf("granting officer %s: %w", *by, err)
+			}
+			input.GrantedBy = &officer.ID
+		}
+		if *days > 0 {
+			expires := now.Truncate(time.Second).AddDate(0, 0, *days)
+			input.ExpiresAt = &expires
+		}
+		grant, err := svc.GrantAccess(ctx, input)
+		if err != nil {
+			return err
+		}
+		until := "until revoked"
+		if grant.ExpiresAt != nil {
+			until = "until " + util.FormatDay(*grant.ExpiresAt)
+		}
+		fmt.Printf("Granted %s %s access to %s %s (grant %s)\n", resident.RegistryNumber, resident.FullName(),
+			point.Code, until, grant.ID)
+		return nil
+	case "revoke":
+		if len(args) != 2 {
+			return fmt.Errorf("access revoke requires a grant ID")
+		}
+		if err := svc.RevokeGrant(ctx, args[1]); err != nil {
+			return err
+		}
+		fmt.Printf("Revoked grant %s\n", args[1])
+		return nil
+	case "grants":
+		if len(args) != 2 {
+			return fmt.Errorf("access grants requires a registry number")
+		}
+		resident, err := popSvc.GetResidentByRegistryNumber(ctx, args[1])
+		if err != nil {
+			return fmt.Errorf("resident %s: %w", args[1], err)
+		}
+		grants, err := svc.ListGrants(ctx, resident.ID)
+		if err != nil {
+			return fmt.Errorf("listing grants: %w", err)
+		}
+		fmt.Printf("%s %s: clearance %d\n", resident.RegistryNumber, resident.FullName(), resident.ClearanceLevel)
+		for _, g := range grants {
+			state := "IN FORCE"
+			switch {
+			case g.RevokedAt != nil:
+				state = "REVOKED " + util.FormatDay(*g.RevokedAt)
+			case g.ExpiresAt != nil && !now.Before(*g.ExpiresAt):
+				state = "EXPIRED " + util.FormatDay(*g.ExpiresAt)
+			case g.ExpiresAt != nil:
+				state += " until " + util.FormatDay(*g.ExpiresAt)
+			}
+			fmt.Printf("  %s  %-16s %-28s %s  %s\n", util.FormatDay(g.GrantedAt), g.AccessPoint.Code, state, g.ID, g.Reason)
+		}
+		return nil
+	case "try":
+		if len(args) != 3 {
+			return fmt.Errorf("access try requires an access point code and a registry number")
+		}
+		point, err := svc.GetAccessPointByCode(ctx, args[1])
+		if err != nil {
+			return err
+		}
+		attempt := security.AccessAttempt{AccessPointID: point.ID}
+		resident, err := popSvc.GetResidentByRegistryNumber(ctx, args[2])
+		switch {
+		case err == nil:
+			attempt.ResidentID = resident.ID
+		case !errors.Is(err, repository.ErrNotFound):
+			return fmt.Errorf("resident %s: %w", args[2], err)
+		}
+		event, err := svc.RequestAccess(ctx, attempt)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s at %s: %s (%s)\n", args[2], point.Code, event.Outcome(), event.Reason)
+		return nil
+	case "log":
+		fs := flag.NewFlagSet("log", flag.ContinueOnError)
+		pointCode := fs.String("point", "", "Only events at this access point")
+		reg := fs.String("resident", "", "Only events of this registry number")
+		denied := fs.Bool("denied", false, "Only denied attempts")
+		granted := fs.Bool("granted", false, "Only granted attempts")
+		limit := fs.Int("limit", 50, "Events to list at most")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if *denied && *granted {
+			return fmt.Errorf("--denied and --granted cannot be combined")
+		}
+
+		var filter models.AccessEventFilter
+		if *pointCode != "" {
+			point, err := svc.GetAccessPointByCode(ctx, *pointCode)
+			if err != nil {
+				return err
+			}
+			filter.AccessPointID = point.ID
+		}
+		if *reg != "" {
+			resident, err := popSvc.GetResidentByRegistryNumber(ctx, *reg)
+			if err != nil {
+				return fmt.Errorf("resident %s: %w", *reg, err)
+			}
+			filter.ResidentID = resident.ID
+		}
+		if *denied || *granted {
+			filter.Granted = granted
+		}
+		events, err := svc.ListAccessEvents(ctx, filter, *limit)
+		if err != nil {
+			return fmt.Errorf("listing access events: %w", err)
+		}
+		if len(events) == 0 {
+			fmt.Println("No access events")
+			return nil
+		}
+		for _, e := range events {
+			who := e.RegistryNumber
+			if who == "" {
+				who = "unknown"
+			}
+			drill := ""
+			if e.Simulated {
+				drill = "DRILL"
+			}
+			fmt.Printf("%s  %-16s %-12s %-7s %-18s %s\n", util.FormatDateTime(e.OccurredAt),
+				e.PointCode, who, e.Outcome(), e.Reason, drill)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown access subcommand: %s", args[0])
+	}
+}
@@ -0,0 +1,356 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/config"
+	"github.com/vtuos/vtuos/internal/database"
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/services/population"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// activityAttendanceDays is how far back `activities list` sums attendance.
+const activityAttendanceDays = 30
+
+// runActivitiesCommand handles `vtuos activities <subcommand>`: open
+// venues, schedule school, skills classes and recreation in them, enroll
+// residents and record who attended a session.
+func runActivitiesCommand(ctx context.Context, configPath, profile string, args []string) error {
+	if len(args) == 0 {
+		flag.Usage()
+		return fmt.Errorf("activities requires a subcommand: venues, add-venue, list, schedule, cancel, enroll, leave or attend")
+	}
+
+	cfg, err := loadConfig(configPath, profile)
+	if err != nil {
+		return fmt.Errorf("loading configuration: %w", err)
+	}
+
+	dbPath, err := config.EnsureDataDir(cfg)
+	if err != nil {
+		return fmt.Errorf("ensuring data directory: %w", err)
+	}
+	backupDir, err := config.BackupDir(cfg)
+	if err != nil {
+		return fmt.Errorf("getting backup directory: %w", err)
+	}
+
+	db, err := database.Open(dbPath, &cfg.Database, backupDir)
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	j, err := openJournal(cfg, nil)
+	if err != nil {
+		return err
+	}
+	defer j.Close()
+
+	svc := population.NewService(db.DB, cfg.Vault.Number)
+	svc.SetJournal(j)
+	today := time.Now().UTC()
+	if startTime, err := cfg.Simulation.StartDateTime(); err == nil {
+		svc.SetClock(util.NewVaultClock(startTime, 0))
+		today = startTime.UTC()
+	}
+
+	switch args[0] {
+	case "venues":
+		venues, err := svc.ListVenues(ctx)
+		if err != nil {
+			return err
+		}
+		if len(venues) == 0 {
+			fmt.Println("No venues")
+		}
+		for _, v := range venues {
+			state := ""
+			if !v.IsActive {
+				state = "  closed"
+			}
+			fmt.Printf("%-10s %-28s %-10s sector %-4s %4d places%s\n",
+				v.Code, v.Name, v.VenueType, v.Sector, v.Capacity, state)
+		}
+		return nil
+	case "add-venue":
+		fs := flag.NewFlagSet("add-venue", flag.ContinueOnError)
+		name := fs.String("name", "", "Name of the venue, e.g. \"Vault School\"")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if fs.NArg() != 4 {
+			return fmt.Errorf("activities add-venue requires a code, a type, a sector and a capacity")
+		}
+		capacity, err := strconv.Atoi(fs.Arg(3))
+		if err != nil {
+			return fmt.Errorf("invalid capacity %q: %w", fs.Arg(3), err)
+		}
+		if *name == "" {
+			*name = fs.Arg(0)
+		}
+		venue, err := svc.AddVenue(ctx, population.VenueInput{
+			Code:     fs.Arg(0),
+			Name:     *name,
+			Type:     models.VenueType(strings.ToUpper(fs.Arg(1))),
+			Sector:   fs.Arg(2),
+			Capacity: capacity,
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Opened %s %s (%s) in sector %s for %d\n", venue.VenueType, venue.Code, venue.Name, venue.Sector, venue.Capacity)
+		return nil
+	case "list":
+		fs := flag.NewFlagSet("list", flag.ContinueOnError)
+		all := fs.Bool("all", false, "Include cancelled activities")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		activities, err := svc.ListActivities(ctx, !*all)
+		if err != nil {
+			return err
+		}
+		if len(activities) == 0 {
+			fmt.Println("No activities scheduled")
+			return nil
+		}
+		venues, err := svc.ListVenues(ctx)
+		if err != nil {
+			return err
+		}
+		venueCodes := make(map[string]string, len(venues))
+		for _, v := range venues {
+			venueCodes[v.ID] = v.Code
+		}
+		attendance, err := svc.ActivityAttendance(ctx, activityAttendanceDays)
+		if err != nil {
+			return err
+		}
+		rates := make(map[string]*models.ActivityAttendance, len(attendance))
+		for _, a := range attendance {
+			rates[a.ActivityID] = a
+		}
+
+		for _, a := range activities {
+			enrollees, err := svc.ListEnrollees(ctx, a.ID)
+			if err != nil {
+				return err
+			}
+			line := fmt.Sprintf("%-10s %-28s %-10s %-10s %-20s %4d enrolled",
+				a.Code, a.Name, a.ActivityType, venueCodes[a.VenueID], a.Schedule(), len(enrollees))
+			if r, ok := rates[a.ID]; ok {
+				line += fmt.Sprintf("  %3.0f%% attended over %d session(s)", 100*r.Rate(), r.Sessions)
+			}
+			if !a.IsActive {
+				line += "  cancelled"
+			}
+			fmt.Println(line)
+		}
+		return nil
+	case "schedule":
+		fs := flag.NewFlagSet("schedule", flag.ContinueOnError)
+		vocation := fs.String("vocation", "", "Vocation a skills class trains, by code")
+		instructor := fs.String("instructor", "", "Registry number of the instructor")
+		ages := fs.String("ages", "", "Ages admitted, MIN-MAX or MIN- (default by type)")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if fs.NArg() != 7 {
+			return fmt.Errorf("activities schedule requires a code, a type, a venue, days, a start hour, a length in hours and a name")
+		}
+		days, err := models.ParseWeekdays(fs.Arg(3))
+		if err != nil {
+			return err
+		}
+		hour, err := strconv.Atoi(fs.Arg(4))
+		if err != nil {
+			return fmt.Errorf("invalid start hour %q: %w", fs.Arg(4), err)
+		}
+		hours, err := strconv.ParseFloat(fs.Arg(5), 64)
+		if err != nil {
+			return fmt.Errorf("invalid length %q: %w", fs.Arg(5), err)
+		}
+		input := population.ActivityInput{
+			Code:          fs.Arg(0),
+			Name:          fs.Arg(6),
+			Type:          models.ActivityType(strings.ToUpper(fs.Arg(1))),
+			VenueCode:     strings.ToUpper(fs.Arg(2)),
+			VocationCode:  *vocation,
+			Weekdays:      days,
+			StartHour:     hour,
+			DurationHours: hours,
+		}
+		if *ages != "" {
+			if input.MinAge, input.MaxAge, err = parseAges(*ages); err != nil {
+				return err
+			}
+		}
+		if *instructor != "" {
+			resident, err := svc.GetResidentByRegistryNumber(ctx, *instructor)
+			if err != nil {
+				return fmt.Errorf("instructor %s: %w", *instructor, err)
+			}
+			input.InstructorID = resident.ID
+		}
+		activity, err := svc.ScheduleActivity(ctx, input)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Scheduled %s %s (%s) %s\n", activity.ActivityType, activity.Code, activity.Name, activity.Schedule())
+		return nil
+	case "cancel":
+		if len(args) != 2 {
+			return fmt.Errorf("activities cancel requires an activity code")
+		}
+		activity, err := svc.GetActivityByCode(ctx, args[1])
+		if err != nil {
+			return err
+		}
+		if err := svc.CancelActivity(ctx, activity.ID); err != nil {
+			return err
+		}
+		fmt.Printf("Cancelled %s\n", activity.Code)
+		return nil
+	case "enroll":
+		fs := flag.NewFlagSet("enroll", flag.ContinueOnError)
+		eligible := fs.Bool("eligible", false, "Enroll every resident of the ages admitted, until the venue is full")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if fs.NArg() < 1 || (fs.NArg() == 1) != *eligible {
+			return fmt.Errorf("activities enroll requires an activity code and registry numbers, or --eligible")
+		}
+		activity, err := svc.GetActivityByCode(ctx, fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		if *eligible {
+			n, err := svc.EnrollEligible(ctx, activity.ID)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Enrolled %d resident(s) in %s\n", n, activity.Code)
+			return nil
+		}
+		for _, reg := range fs.Args()[1:] {
+			resident, err := svc.GetResidentByRegistryNumber(ctx, reg)
+			if err != nil {
+				return fmt.Errorf("resident %s: %w", reg, err)
+			}
+			if err := svc.EnrollInActivity(ctx, activity.ID, resident.ID); err != nil {
+				return err
+			}
+			fmt.Printf("Enrolled %s %s in %s\n", resident.RegistryNumber, resident.FullName(), activity.Code)
+		}
+		return nil
+	case "leave":
+		if len(args) != 3 {
+			return fmt.Errorf("activities leave requires an activity code and a registry number")
+		}
+		activity, err := svc.GetActivityByCode(ctx, args[1])
+		if err != nil {
+			return err
+		}
+		resident, err := svc.GetResidentByRegistryNumber(ctx, args[2])
+		if err != nil {
+			return fmt.Errorf("resident %s: %w", args[2], err)
+		}
+		if err := svc.LeaveActivity(ctx, activity.ID, resident.ID); err != nil {
+			return err
+		}
+		fmt.Printf("Removed %s %s from %s\n", resident.RegistryNumber, resident.FullName(), activity.Code)
+		return nil
+	case "attend":
+		fs := flag.NewFlagSet("attend", flag.ContinueOnError)
+		dateStr := fs.String("date", "", "Date of the session YYYY-MM-DD (default: the vault date)")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if fs.NArg() < 1 {
+			return fmt.Errorf("activities attend requires an activity code and the registry numbers present")
+		}
+		activity, err := svc.GetActivityByCode(ctx, fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		day := today.Truncate(24 * time.Hour)
+		if *dateStr != "" {
+			if day, err = time.Parse(time.DateOnly, *dateStr); err != nil {
+				return fmt.Errorf("invalid --date: %w", err)
+			}
+		}
+
+		present := make(map[string]bool)
+		for _, reg := range fs.Args()[1:] {
+			resident, err := svc.GetResidentByRegistryNumber(ctx, reg)
+			if err != nil {
+				return fmt.Errorf("resident %s: %w", reg, err)
+			}
+			present[resident.ID] = true
+		}
+		enrollees, err := svc.ListEnrollees(ctx, activity.ID)
+		if err != nil {
+			return err
+		}
+		input := population.AttendanceInput{
+			ActivityID: activity.ID,
+			SessionAt:  day.Add(time.Duration(activity.StartHour) * time.Hour),
+		}
+		for _, e := range enrollees {
+			if present[e.ResidentID] {
+				input.Present = append(input.Present, e.ResidentID)
+				delete(present, e.ResidentID)
+			} else {
+				input.Absent = append(input.Absent, e.ResidentID)
+			}
+		}
+		for id := range present {
+			input.Present = append(input.Present, id) // Rejected as not enrolled
+		}
+		completed, err := svc.RecordAttendance(ctx, input)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Recorded %s on %s: %d present, %d absent\n",
+			activity.Code, input.SessionAt.Format("2006-01-02 15:04"), len(input.Present), len(input.Absent))
+		for _, a := range completed {
+			resident, err := svc.GetResident(ctx, a.ResidentID)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("  %s %s completed %.0f training hours; awaiting mentor sign-off\n",
+				resident.RegistryNumber, resident.FullName(), a.RequiredHours)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown activities subcommand: %s", args[0])
+	}
+}
+
+// parseAges parses an age range, MIN-MAX or MIN- for no upper age.
+func parseAges(s string) (minAge, maxAge *int, err error) {
+	lo, hi, ok := strings.Cut(s, "-")
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid ages %q: want MIN-MAX or MIN-", s)
+	}
+	n, err := strconv.Atoi(lo)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid ages %q: %w", s, err)
+	}
+	minAge = &n
+	if hi != "" {
+		m, err := strconv.Atoi(hi)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid ages %q: %w", s, err)
+		}
+		maxAge = &m
+	}
+	return minAge, maxAge, nil
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/vtuos/vtuos/internal/config"
+	"github.com/vtuos/vtuos/internal/database"
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/services/governance"
+	"github.com/vtuos/vtuos/internal/services/population"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// runAlertsCommand handles `vtuos alerts <subcommand>`: list the recorded
+// alerts, acknowledge and resolve them for an operator, and manage the
+// rules routing them to departments and operators.
+func runAlertsCommand(ctx context.Context, configPath, profile string, args []string) error {
+	if len(args) == 0 {
+		flag.Usage()
+		return fmt.Errorf("alerts requires a subcommand: list, ack, resolve, routes or route")
+	}
+
+	cfg, err := loadConfig(configPath, profile)
+	if err != nil {
+		return fmt.Errorf("loading configuration: %w", err)
+	}
+
+	dbPath, err := config.EnsureDataDir(cfg)
+	if err != nil {
+		return fmt.Errorf("ensuring data directory: %w", err)
+	}
+	backupDir, err := config.BackupDir(cfg)
+	if err != nil {
+		return fmt.Errorf("getting backup directory: %w", err)
+	}
+
+	db, err := database.Open(dbPath, &cfg.Database, backupDir)
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	j, err := openJournal(cfg, nil)
+	if err != nil {
+		return err
+	}
+	defer j.Close()
+
+	svc := governance.NewService(db.DB, cfg.Vault.Number)
+	svc.SetJournal(j)
+	if startTime, err := cfg.Simulation.StartDateTime(); err == nil {
+		svc.SetClock(util.NewVaultClock(startTime, 0))
+	}
+	popSvc := population.NewService(db.DB, cfg.Vault.Number)
+
+	resident := func(regNum string) (*models.Resident, error) {
+		r, err := popSvc.GetResidentByRegistryNumber(ctx, strings.ToUpper(regNum))
+		if err != nil {
+			return nil, fmt.Errorf("resident %s: %w", regNum, err)
+		}
+		return r, nil
+	}
+
+	switch args[0] {
+	case "list":
+		fs := flag.NewFlagSet("list", flag.ContinueOnError)
+		all := fs.Bool("all", false, "Include resolved alerts")
+		mine := fs.String("for", "", "Only alerts addressed to this operator or their department")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		var alerts []*models.Alert
+		if *mine != "" {
+			r, err := resident(*mine)
+			if err != nil {
+				return err
+			}
+			alerts, err = svc.AlertsAddressedTo(ctx, r.ID)
+			if err != nil {
+				return fmt.Errorf("listing alerts: %w", err)
+			}
+		} else if alerts, err = svc.ListAlerts(ctx, *all); err != nil {
+			return fmt.Errorf("listing alerts: %w", err)
+		}
+		if len(alerts) == 0 {
+			fmt.Println("No alerts")
+			return nil
+		}
+		for _, a := range alerts {
+			printAlert(a)
+		}
+		return nil
+	case "ack", "resolve":
+		if len(args) < 3 || len(args) > 4 {
+			return fmt.Errorf("alerts %s requires a registry number, an alert ID and optionally notes", args[0])
+		}
+		r, err := resident(args[1])
+		if err != nil {
+			return err
+		}
+		input := governance.AlertActionInput{AlertID: args[2], OperatorID: r.ID}
+		if len(args) == 4 {
+			input.Notes = args[3]
+		}
+		if args[0] == "resolve" {
+			a, err := svc.ResolveAlert(ctx, input)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%s resolved %q at %s\n", r.RegistryNumber, a.Message, util.FormatDateTime(*a.ResolvedAt))
+			return nil
+		}
+		a, err := svc.AcknowledgeAlert(ctx, input)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s acknowledged %q at %s\n", r.RegistryNumber, a.Message, util.FormatDateTime(*a.AcknowledgedAt))
+		return nil
+	case "routes":
+		routes, err := svc.ListAlertRoutes(ctx)
+		if err != nil {
+			return fmt.Errorf("listing alert routes: %w", err)
+		}
+		if len(routes) == 0 {
+			fmt.Println("No routing rules: every alert goes to ADMINISTRATION")
+			return nil
+		}
+		for _, r := range routes {
+			after := "default"
+			if r.EscalateAfterHours > 0 {
+				after = fmt.Sprintf("%dh", r.EscalateAfterHours)
+			}
+			fmt.Printf("%-36s %-24s %-8s -> %-15s escalate to %s after %s\n", r.ID, r.Source, r.MinSeverity,
+				r.Recipient(), r.EscalationDepartment(), after)
+		}
+		return nil
+	case "route":
+		if len(args) < 2 {
+			return fmt.Errorf("alerts route requires add or remove")
+		}
+		switch args[1] {
+		case "add":
+			fs := flag.NewFlagSet("route add", flag.ContinueOnError)
+			source := fs.String("source", models.AlertRouteAnySource, "Source of the alerts routed, e.g. \"shortage check\"; * for any")
+			severity := fs.String("severity", "warning", "Least severity routed: warning or critical")
+			escalateTo := fs.String("escalate-to", "", "Department unacknowledged alerts escalate to (default ADMINISTRATION)")
+			hours := fs.Int("escalate-after", 0, "Vault hours before escalating (default: [alerts] escalate_after_hours)")
+			if err := fs.Parse(args[2:]); err != nil {
+				return err
+			}
+			if fs.NArg() != 1 {
+				return fmt.Errorf("alerts route add requires a department or an operator's registry number")
+			}
+			input := governance.AlertRouteInput{Source: *source, EscalateAfterHours: *hours}
+			if input.MinSeverity, err = models.ParseAlertSeverity(*severity); err != nil {
+				return err
+			}
+			if *escalateTo != "" {
+				if input.EscalateTo, err = models.ParseDepartment(*escalateTo); err != nil {
+					return err
+				}
+			}
+			if department, err := models.ParseDepartment(fs.Arg(0)); err == nil {
+				input.Department = department
+			} else {
+				r, err := resident(fs.Arg(0))
+				if err != nil {
+					return err
+				}
+				input.OperatorID = r.ID
+			}
+			route, err := svc.AddAlertRoute(ctx, input)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Alerts of %s from %s up routed to %s (%s)\n", route.Source, route.MinSeverity, route.Recipient(), route.ID)
+			return nil
+		case "remove":
+			if len(args) != 3 {
+				return fmt.Errorf("alerts route remove requires a rule ID")
+			}
+			if err := svc.RemoveAlertRoute(ctx, args[2]); err != nil {
+				return err
+			}
+			fmt.Printf("Routing rule %s removed\n", args[2])
+			return nil
+		default:
+			return fmt.Errorf("unknown alerts route subcommand: %s", args[1])
+		}
+	default:
+		return fmt.Errorf("unknown alerts subcommand: %s", args[0])
+	}
+}
+
+// printAlert prints an alert on one line, its acknowledgment and resolution
+// notes indented beneath.
+func printAlert(a *models.Alert) {
+	status := string(a.Status)
+	if a.EscalatedAt != nil {
+		status += " (escalated)"
+	}
+	fmt.Printf("%-36s %s %-8s %-20s %-15s %-24s %s\n", a.ID, util.FormatDateTime(a.RaisedAt), a.Severity,
+		a.Source, a.Recipient(), status, a.Message)
+	if a.AcknowledgedAt != nil {
+		fmt.Printf("    acknowledged %s by %s", util.FormatDateTime(*a.AcknowledgedAt), a.AcknowledgedRegistry)
+		if a.AcknowledgmentNotes != "" {
+			fmt.Printf(": %s", a.AcknowledgmentNotes)
+		}
+		fmt.Println()
+	}
+	if a.ResolvedAt != nil {
+		fmt.Printf("    resolved %s by %s", util.FormatDateTime(*a.ResolvedAt), a.ResolvedRegistry)
+		if a.ResolutionNotes != "" {
+			fmt.Printf(": %s", a.ResolutionNotes)
+		}
+		fmt.Println()
+	}
+}
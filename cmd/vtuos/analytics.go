@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/analytics"
+	"github.com/vtuos/vtuos/internal/config"
+	"github.com/vtuos/vtuos/internal/database"
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/services/population"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// runExportAnalyticsCommand handles `vtuos export-analytics`, copying
+// datasets to a standalone SQLite file or Parquet files for analysis. With
+// [privacy] pii_clearance set, residents' personal details are redacted
+// unless --operator names an active resident holding it. The database is
+// opened read-only.
+func runExportAnalyticsCommand(ctx context.Context, configPath, profile string, args []string) error {
+	fs := flag.NewFlagSet("export-analytics", flag.ContinueOnError)
+	format := fs.String("format", string(analytics.FormatSQLite), "sqlite or parquet")
+	datasets := fs.String("datasets", strings.Join(analytics.DatasetNames(), ","), "Comma-separated datasets: "+strings.Join(analytics.DatasetNames(), ", "))
+	outDir := fs.String("out", "", "Directory to write the export to (default: analytics-<vault>-<date>)")
+	operator := fs.String("operator", "", "Registry number of the operator making the export, for the PII clearance")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := analytics.Format(*format).Validate(); err != nil {
+		return err
+	}
+	var names []string
+	for _, name := range strings.Split(*datasets, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	cfg, err := loadConfig(configPath, profile)
+	if err != nil {
+		return fmt.Errorf("loading configuration: %w", err)
+	}
+	if *outDir == "" {
+		*outDir = fmt.Sprintf("analytics-%03d-%s", cfg.Vault.Number, time.Now().Format("20060102-150405"))
+	}
+
+	dbPath, err := config.EnsureDataDir(cfg)
+	if err != nil {
+		return fmt.Errorf("ensuring data directory: %w", err)
+	}
+
+	cfg.Database.ReadOnly = true
+	db, err := database.Open(dbPath, &cfg.Database, "")
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		return fmt.Errorf("creating migrator: %w", err)
+	}
+	if err := checkSchemaCurrent(ctx, migrator); err != nil {
+		return err
+	}
+
+	if *operator != "" {
+		regNum := strings.ToUpper(*operator)
+		op, err := population.NewService(db.DB, cfg.Vault.Number).GetResidentByRegistryNumber(ctx, regNum)
+		if err != nil {
+			return fmt.Errorf("operator %s: %w", regNum, err)
+		}
+		if op.Status != models.ResidentStatusActive {
+			return fmt.Errorf("operator %s is %s", regNum, op.Status)
+		}
+		ctx = util.WithOperatorClearance(ctx, op.ClearanceLevel)
+	}
+	redact := !util.Cleared(ctx, cfg.Privacy.PIIClearance)
+
+	manifest, err := analytics.Export(ctx, db.DB, *outDir, analytics.Format(*format), names, redact)
+	if err != nil {
+		return fmt.Errorf("exporting: %w", err)
+	}
+	for _, t := range manifest.Tables {
+		fmt.Printf("  %-13s %-25s %7d row(s)  %s\n", t.Dataset, t.Name, t.Rows, filepath.Join(*outDir, t.File))
+	}
+	if manifest.Redacted {
+		fmt.Printf("Personal details redacted: %s needs clearance %d\n",
+			strings.Join(analytics.PIIColumns, ", "), cfg.Privacy.PIIClearance)
+	}
+	fmt.Printf("Wrote %s analytics export of %s to %s (schema in SCHEMA.md)\n",
+		manifest.Format, strings.Join(manifest.Datasets, ", "), *outDir)
+	return nil
+}
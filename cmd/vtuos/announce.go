@@ -0,0 +1,214 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/config"
+	"github.com/vtuos/vtuos/internal/database"
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/services/governance"
+	"github.com/vtuos/vtuos/internal/services/population"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// runAnnounceCommand handles `vtuos announce <subcommand>`: post overseer
+// broadcasts and department notices, read a resident's inbox and record
+// what they have acknowledged.
+func runAnnounceCommand(ctx context.Context, configPath, profile string, args []string) error {
+	if len(args) == 0 {
+		flag.Usage()
+		return fmt.Errorf("announce requires a subcommand: list, post, inbox or ack")
+	}
+
+	cfg, err := loadConfig(configPath, profile)
+	if err != nil {
+		return fmt.Errorf("loading configuration: %w", err)
+	}
+
+	dbPath, err := config.EnsureDataDir(cfg)
+	if err != nil {
+		return fmt.Errorf("ensuring data directory: %w", err)
+	}
+	backupDir, err := config.BackupDir(cfg)
+	if err != nil {
+		return fmt.Errorf("getting backup directory: %w", err)
+	}
+
+	db, err := database.Open(dbPath, &cfg.Database, backupDir)
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	j, err := openJournal(cfg, nil)
+	if err != nil {
+		return err
+	}
+	defer j.Close()
+
+	svc := governance.NewService(db.DB, cfg.Vault.Number)
+	svc.SetJournal(j)
+	popSvc := population.NewService(db.DB, cfg.Vault.Number)
+	now := time.Now().UTC()
+	if startTime, err := cfg.Simulation.StartDateTime(); err == nil {
+		svc.SetClock(util.NewVaultClock(startTime, 0))
+		now = startTime.UTC()
+	}
+
+	resident := func(regNum string) (*models.Resident, error) {
+		r, err := popSvc.GetResidentByRegistryNumber(ctx, strings.ToUpper(regNum))
+		if err != nil {
+			return nil, fmt.Errorf("resident %s: %w", regNum, err)
+		}
+		return r, nil
+	}
+
+	switch args[0] {
+	case "list":
+		fs := flag.NewFlagSet("list", flag.ContinueOnError)
+		all := fs.Bool("all", false, "Include expired announcements")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		announcements, err := svc.ListAnnouncements(ctx, *all)
+		if err != nil {
+			return fmt.Errorf("listing announcements: %w", err)
+		}
+		if len(announcements) == 0 {
+			fmt.Println("No announcements posted")
+			return nil
+		}
+		for _, a := range announcements {
+			printAnnouncement(a, fmt.Sprintf("ack %d/%d", a.Acknowledgments, a.Recipients))
+		}
+		return nil
+	case "post":
+		fs := flag.NewFlagSet("post", flag.ContinueOnError)
+		notice := fs.Bool("notice", false, "Post a department notice rather than an overseer broadcast")
+		to := fs.String("to", "all", "Audience: all, department:NAME or household:DESIGNATION")
+		by := fs.String("by", "", "Registry number of the issuer")
+		days := fs.Int("days", 0, "Days until the announcement expires (default: never)")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if fs.NArg() < 1 || fs.NArg() > 2 {
+			return fmt.Errorf("announce post requires a title and optionally a body")
+		}
+		input := governance.AnnouncementInput{
+			Kind:  models.AnnouncementBroadcast,
+			Title: fs.Arg(0),
+			Body:  fs.Arg(1),
+		}
+		if *notice {
+			input.Kind = models.AnnouncementNotice
+		}
+		audience, target, _ := strings.Cut(*to, ":")
+		if input.Audience, err = models.ParseAnnouncementAudience(audience); err != nil {
+			return err
+		}
+		switch input.Audience {
+		case models.AudienceDepartment:
+			if input.Department, err = models.ParseDepartment(target); err != nil {
+				return err
+			}
+		case models.AudienceHousehold:
+			household, err := popSvc.GetHouseholdByDesignation(ctx, strings.ToUpper(target))
+			if err != nil {
+				return fmt.Errorf("household %s: %w", target, err)
+			}
+			input.HouseholdID = household.ID
+		}
+		if *by != "" {
+			issuer, err := resident(*by)
+			if err != nil {
+				return err
+			}
+			input.IssuedBy = issuer.ID
+		}
+		if *days > 0 {
+			expires := now.Truncate(time.Second).AddDate(0, 0, *days)
+			input.ExpiresAt = &expires
+		}
+		announcement, err := svc.PostAnnouncement(ctx, input)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s posted to %s: %s\n", announcement.Kind, announcement.AudienceLabel(), announcement.Title)
+		return nil
+	case "inbox":
+		if len(args) != 2 {
+			return fmt.Errorf("announce inbox requires a registry number")
+		}
+		r, err := resident(args[1])
+		if err != nil {
+			return err
+		}
+		inbox, err := svc.Inbox(ctx, r.ID)
+		if err != nil {
+			return fmt.Errorf("reading inbox: %w", err)
+		}
+		if len(inbox) == 0 {
+			fmt.Printf("No announcements for %s\n", r.RegistryNumber)
+			return nil
+		}
+		for _, a := range inbox {
+			state := "NEW"
+			if a.Acknowledged {
+				state = "acknowledged"
+			}
+			printAnnouncement(a, state)
+		}
+		return nil
+	case "ack":
+		if len(args) < 2 || len(args) > 3 {
+			return fmt.Errorf("announce ack requires a registry number and optionally an announcement ID")
+		}
+		r, err := resident(args[1])
+		if err != nil {
+			return err
+		}
+		ids := args[2:]
+		if len(ids) == 0 {
+			inbox, err := svc.Inbox(ctx, r.ID)
+			if err != nil {
+				return fmt.Errorf("reading inbox: %w", err)
+			}
+			for _, a := range inbox {
+				if !a.Acknowledged {
+					ids = append(ids, a.ID)
+				}
+			}
+		}
+		for _, id := range ids {
+			a, err := svc.AcknowledgeAnnouncement(ctx, governance.AcknowledgeInput{AnnouncementID: id, ResidentID: r.ID})
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%s acknowledged %q (%d of %d)\n", r.RegistryNumber, a.Title, a.Acknowledgments, a.Recipients)
+		}
+		if len(ids) == 0 {
+			fmt.Printf("Nothing for %s to acknowledge\n", r.RegistryNumber)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown announce subcommand: %s", args[0])
+	}
+}
+
+// printAnnouncement prints an announcement on one line, its body indented
+// beneath.
+func printAnnouncement(a *models.Announcement, state string) {
+	issuer := a.IssuerRegistry
+	if issuer == "" {
+		issuer = "-"
+	}
+	fmt.Printf("%-36s %s %-9s %-15s %-12s %-40s %s\n", a.ID, util.FormatDay(a.IssuedAt), a.Kind,
+		a.AudienceLabel(), issuer, a.Title, state)
+	if a.Body != "" {
+		fmt.Printf("    %s\n", a.Body)
+	}
+}
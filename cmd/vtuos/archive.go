@@ -0,0 +1,250 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/archive"
+	"github.com/vtuos/vtuos/internal/config"
+	"github.com/vtuos/vtuos/internal/database"
+	"github.com/vtuos/vtuos/internal/services/population"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// runArchiveCommand handles `vtuos archive`: write a holotape of the
+// database, configuration and recent reports for storage off site, and
+// verify one, extracting all of it or only some of its reports.
+func runArchiveCommand(ctx context.Context, configPath, profile string, args []string) error {
+	if len(args) == 0 {
+		flag.Usage()
+		return fmt.Errorf("archive requires a subcommand: write or read")
+	}
+
+	switch args[0] {
+	case "write":
+		fs := flag.NewFlagSet("archive write", flag.ContinueOnError)
+		out := fs.String("out", "", "File to write the holotape to (default: holotape-<vault>-<timestamp>.tar.gz)")
+		reportDays := fs.Int("report-days", 30, "Include reports written in the last N days (0 for every report)")
+		note := fs.String("note", "", "Why the holotape was written, e.g. where it is to be stored")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if fs.NArg() > 0 {
+			return fmt.Errorf("unexpected arguments: %v", fs.Args())
+		}
+		if *reportDays < 0 {
+			return fmt.Errorf("--report-days must not be negative")
+		}
+		return archiveWrite(ctx, configPath, profile, *out, *reportDays, *note)
+	case "read":
+		fs := flag.NewFlagSet("archive read", flag.ContinueOnError)
+		extract := fs.String("extract", "", "Directory to extract the verified files to")
+		reports := fs.String("reports", "", "Extract only the reports whose names match this pattern, e.g. '*' or 'vault-076-daily-report-2077-10-*'")
+		file, err := parseWithName(fs, args[1:])
+		if err != nil {
+			return err
+		}
+		if file == "" {
+			return fmt.Errorf("archive read requires a holotape file")
+		}
+		if *reports != "" && *extract == "" {
+			return fmt.Errorf("--reports needs --extract DIR")
+		}
+		if _, err := path.Match(*reports, ""); err != nil {
+			return fmt.Errorf("invalid --reports pattern %q", *reports)
+		}
+		return archiveRead(file, *extract, *reports)
+	default:
+		return fmt.Errorf("unknown archive subcommand: %s", args[0])
+	}
+}
+
+// archiveWrite writes a holotape of a consistent copy of the database, the
+// configuration with its secrets masked, and the reports written in the
+// last reportDays days.
+func archiveWrite(ctx context.Context, configPath, profile, out string, reportDays int, note string) error {
+	cfg, err := loadConfig(configPath, profile)
+	if err != nil {
+		return fmt.Errorf("loading configuration: %w", err)
+	}
+	dbPath, err := config.EnsureDataDir(cfg)
+	if err != nil {
+		return fmt.Errorf("ensuring data directory: %w", err)
+	}
+	if _, err := os.Stat(dbPath); err != nil {
+		return fmt.Errorf("database not found: %s", dbPath)
+	}
+	reportDir, err := config.ReportDir(cfg)
+	if err != nil {
+		return fmt.Errorf("getting report directory: %w", err)
+	}
+
+	db, err := database.Open(dbPath, &cfg.Database, "")
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	vaultTime, err := cfg.Simulation.StartDateTime()
+	if err != nil {
+		vaultTime = time.Now().UTC()
+	}
+	stats, err := population.NewService(db.DB, cfg.Vault.Number).GetPopulationStats(ctx)
+	if err != nil {
+		return fmt.Errorf("counting population: %w", err)
+	}
+
+	staging, err := os.MkdirTemp("", "vtuos-holotape-")
+	if err != nil {
+		return fmt.Errorf("creating staging directory: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	snap, err := db.CreateSnapshot(ctx, staging, database.SnapshotInfo{
+		Name:       "holotape",
+		Note:       note,
+		VaultTime:  vaultTime,
+		Population: stats.TotalActive,
+	})
+	if err != nil {
+		return err
+	}
+	configFile := filepath.Join(staging, archive.ConfigName)
+	f, err := os.OpenFile(configFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0640)
+	if err != nil {
+		return fmt.Errorf("writing configuration: %w", err)
+	}
+	err = config.Encode(f, cfg.WithoutSecrets())
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("writing configuration: %w", err)
+	}
+
+	files := []archive.File{
+		{Name: archive.DatabaseName, Path: snap.Path},
+		{Name: archive.SnapshotName, Path: filepath.Join(staging, "holotape.json")},
+		{Name: archive.ConfigName, Path: configFile},
+	}
+	reports, err := recentReports(reportDir, reportDays)
+	if err != nil {
+		return err
+	}
+	files = append(files, reports...)
+
+	now := time.Now().UTC()
+	if out == "" {
+		out = fmt.Sprintf("holotape-%03d-%s.tar.gz", cfg.Vault.Number, now.Local().Format("20060102-150405"))
+	}
+	manifest, err := archive.Write(out, archive.Manifest{
+		Vault:       cfg.Vault.Number,
+		Designation: cfg.Vault.Designation,
+		CreatedAt:   now,
+		VaultTime:   vaultTime.UTC(),
+		Note:        note,
+	}, files)
+	if err != nil {
+		return err
+	}
+
+	size := int64(0)
+	if stat, err := os.Stat(out); err == nil {
+		size = stat.Size()
+	}
+	fmt.Printf("Wrote holotape %s (%s)\n", out, formatSize(size))
+	fmt.Printf("  Vault date: %s\n", util.FormatDay(vaultTime))
+	fmt.Printf("  Population: %d\n", snap.Population)
+	fmt.Printf("  Schema:     version %d\n", snap.SchemaVersion)
+	fmt.Printf("  Reports:    %d\n", len(reports))
+	fmt.Printf("  Files:      %d, checksummed in the manifest\n", len(manifest.Entries))
+	fmt.Println("Secrets in the configuration are masked; keep them, and any encryption master key, apart from the holotape.")
+	return nil
+}
+
+// recentReports lists the reports in dir written in the last days days,
+// or every report if days is 0. A missing directory has no reports.
+func recentReports(dir string, days int) ([]archive.File, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading report directory: %w", err)
+	}
+	cutoff := time.Now().AddDate(0, 0, -days)
+	var files []archive.File
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("reading report directory: %w", err)
+		}
+		if days > 0 && info.ModTime().Before(cutoff) {
+			continue
+		}
+		files = append(files, archive.File{
+			Name: archive.ReportsPrefix + entry.Name(),
+			Path: filepath.Join(dir, entry.Name()),
+		})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+	return files, nil
+}
+
+// archiveRead verifies a holotape and lists its files. With extractDir set
+// it extracts the verified files there: every file, or only the reports
+// matching reports if that is set.
+func archiveRead(file, extractDir, reports string) error {
+	var manifest *archive.Manifest
+	var extracted []archive.Entry
+	var err error
+	if extractDir == "" {
+		manifest, err = archive.Verify(file)
+	} else {
+		var match func(archive.Entry) bool
+		if reports != "" {
+			match = func(e archive.Entry) bool {
+				ok, _ := path.Match(reports, path.Base(e.Name))
+				return e.IsReport() && ok
+			}
+		}
+		manifest, extracted, err = archive.Extract(file, extractDir, match)
+	}
+	if err != nil {
+		if errors.Is(err, archive.ErrCorrupt) && len(extracted) > 0 {
+			fmt.Printf("Extracted %d file(s) to %s before the holotape failed verification\n", len(extracted), extractDir)
+		}
+		return err
+	}
+
+	name := manifest.Designation
+	if name == "" {
+		name = fmt.Sprintf("Vault %03d", manifest.Vault)
+	}
+	fmt.Printf("Holotape of %s\n", name)
+	fmt.Printf("  Written:    %s\n", util.FormatDateTime(manifest.CreatedAt))
+	fmt.Printf("  Vault date: %s\n", util.FormatDay(manifest.VaultTime))
+	if manifest.Note != "" {
+		fmt.Printf("  Note:       %s\n", manifest.Note)
+	}
+	fmt.Println()
+	fmt.Printf("%-48s %9s  %s\n", "FILE", "SIZE", "SHA-256")
+	for _, e := range manifest.Entries {
+		fmt.Printf("%-48s %9s  %s\n", e.Name, formatSize(e.Size), e.SHA256[:16])
+	}
+	fmt.Printf("\nVerified %d file(s) against the manifest\n", len(manifest.Entries))
+	if extractDir != "" {
+		fmt.Printf("Extracted %d file(s) to %s\n", len(extracted), extractDir)
+	}
+	return nil
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/config"
+	"github.com/vtuos/vtuos/internal/database"
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/services/population"
+	"github.com/vtuos/vtuos/internal/services/resources"
+	"github.com/vtuos/vtuos/internal/services/security"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// runArmoryCommand handles `vtuos armory <subcommand>`: bring weapons under
+// armory control, issue them for an incident or drill, take them back with
+// the rounds expended and see who holds what.
+func runArmoryCommand(ctx context.Context, configPath, profile string, args []string) error {
+	if len(args) == 0 {
+		flag.Usage()
+		return fmt.Errorf("armory requires a subcommand: list, weapons, authorize, issue or return")
+	}
+
+	cfg, err := loadConfig(configPath, profile)
+	if err != nil {
+		return fmt.Errorf("loading configuration: %w", err)
+	}
+
+	dbPath, err := config.EnsureDataDir(cfg)
+	if err != nil {
+		return fmt.Errorf("ensuring data directory: %w", err)
+	}
+	backupDir, err := config.BackupDir(cfg)
+	if err != nil {
+		return fmt.Errorf("getting backup directory: %w", err)
+	}
+
+	db, err := database.Open(dbPath, &cfg.Database, backupDir)
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	j, err := openJournal(cfg, nil)
+	if err != nil {
+		return err
+	}
+	defer j.Close()
+
+	svc := security.NewService(db.DB)
+	svc.SetVault(cfg.Vault.Number)
+	svc.SetJournal(j)
+	resSvc := resources.NewService(db.DB)
+	resSvc.SetVault(cfg.Vault.Number)
+	popSvc := population.NewService(db.DB, cfg.Vault.Number)
+	now := time.Now().UTC()
+	if startTime, err := cfg.Simulation.StartDateTime(); err == nil {
+		clock := util.NewVaultClock(startTime, 0)
+		svc.SetClock(clock)
+		resSvc.SetClock(clock)
+		now = startTime.UTC()
+	}
+
+	resident := func(regNum string) (*models.Resident, error) {
+		r, err := popSvc.GetResidentByRegistryNumber(ctx, regNum)
+		if err != nil {
+			return nil, fmt.Errorf("resident %s: %w", regNum, err)
+		}
+		return r, nil
+	}
+
+	switch args[0] {
+	case "list":
+		issues, err := svc.ListIssuedWeapons(ctx)
+		if err != nil {
+			return fmt.Errorf("listing issued weapons: %w", err)
+		}
+		if len(issues) == 0 {
+			fmt.Println("All weapons in the armory")
+			return nil
+		}
+		for _, i := range issues {
+			if i.Asset == nil {
+				continue
+			}
+			overdue := ""
+			if i.Custody.IsOverdue(now) {
+				overdue = "OVERDUE"
+			}
+			fmt.Printf("%-16s %-24s %-12s %-24s %-13s approved by %-12s %4d rds  %s %s\n", i.Asset.SerialNumber,
+				i.Asset.Name, i.Custody.Resident.RegistryNumber, i.Custody.Resident.FullName(), i.Purpose(),
+				i.ApproverRegistry, i.RoundsIssued, util.FormatDay(i.Custody.IssuedAt), overdue)
+		}
+		return nil
+	case "weapons":
+		weapons, err := svc.ListWeapons(ctx)
+		if err != nil {
+			return fmt.Errorf("listing weapons: %w", err)
+		}
+		if len(weapons) == 0 {
+			fmt.Println("No weapons under armory control")
+			return nil
+		}
+		for _, w := range weapons {
+			if w.Asset == nil {
+				continue
+			}
+			ammo := "-"
+			if w.AmmoItem != nil {
+				ammo = w.AmmoItem.ItemCode
+			}
+			fmt.Printf("%-16s %-24s clearance %-2d %-16s %-9s %s\n", w.Asset.SerialNumber, w.Asset.Name,
+				w.AuthorizationLevel, ammo, w.Asset.Condition, assetWhereabouts(w.Asset, now))
+		}
+		return nil
+	case "authorize":
+		fs := flag.NewFlagSet("authorize", flag.ContinueOnError)
+		ammo := fs.String("ammo", "", "Item code of the ammunition the weapon fires")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if fs.NArg() != 2 {
+			return fmt.Errorf("armory authorize requires a serial number and a clearance level")
+		}
+		level, err := strconv.Atoi(fs.Arg(1))
+		if err != nil {
+			return fmt.Errorf("invalid clearance level %q", fs.Arg(1))
+		}
+		asset, err := resSvc.GetAssetBySerial(ctx, fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		input := security.WeaponInput{AssetID: asset.ID, AuthorizationLevel: level}
+		if *ammo != "" {
+			item, err := resSvc.GetItemByCode(ctx, *ammo)
+			if err != nil {
+				return fmt.Errorf("item %s: %w", *ammo, err)
+			}
+			input.AmmoItemID = &item.ID
+		}
+		weapon, err := svc.SetWeapon(ctx, input)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s may be carried at clearance %d\n", asset.SerialNumber, weapon.AuthorizationLevel)
+		return nil
+	case "issue":
+		fs := flag.NewFlagSet("issue", flag.ContinueOnError)
+		incident := fs.String("incident", "", "Open incident the weapon is issued for (default: the drill in progress)")
+		rounds := fs.Int("rounds", 0, "Rounds of ammunition issued with the weapon")
+		days := fs.Int("days", 0, "Days until the weapon is due back (default: until returned)")
+		note := fs.String("note", "", "Note recorded with the issue")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if fs.NArg() != 3 {
+			return fmt.Errorf("armory issue requires a serial number, a registry number and an approving officer's registry number")
+		}
+		asset, err := resSvc.GetAssetBySerial(ctx, fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		holder, err := resident(fs.Arg(1))
+		if err != nil {
+			return err
+		}
+		approver, err := resident(fs.Arg(2))
+		if err != nil {
+			return err
+		}
+		input := security.IssueWeaponInput{
+			AssetID:        asset.ID,
+			ResidentID:     holder.ID,
+			ApprovedBy:     approver.ID,
+			IncidentNumber: *incident,
+			RoundsIssued:   *rounds,
+			Notes:          *note,
+		}
+		if *days > 0 {
+			due := now.Truncate(time.Second).AddDate(0, 0, *days)
+			input.DueAt = &due
+		}
+		issue, err := svc.IssueWeapon(ctx, input)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Issued %s to %s %s with %d rounds for %s, approved by %s\n", asset.SerialNumber,
+			holder.RegistryNumber, holder.FullName(), issue.RoundsIssued, issue.Purpose(), approver.RegistryNumber)
+		return nil
+	case "return":
+		fs := flag.NewFlagSet("return", flag.ContinueOnError)
+		rounds := fs.Int("rounds", 0, "Rounds expended while the weapon was out")
+		by := fs.String("by", "", "Registry number of the receiving officer")
+		note := fs.String("note", "", "Note recorded with the return")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if fs.NArg() != 2 {
+			return fmt.Errorf("armory return requires a serial number and a condition")
+		}
+		asset, err := resSvc.GetAssetBySerial(ctx, fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		condition, err := models.ParseAssetCondition(fs.Arg(1))
+		if err != nil {
+			return err
+		}
+		input := security.ReturnWeaponInput{
+			AssetID:        asset.ID,
+			Condition:      condition,
+			RoundsExpended: *rounds,
+			Notes:          *note,
+		}
+		if *by != "" {
+			officer, err := resident(*by)
+			if err != nil {
+				return err
+			}
+			input.ReceivedBy = &officer.ID
+		}
+		issue, err := svc.ReturnWeapon(ctx, input)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Returned %s from %s: %s → %s, %d of %d rounds expended\n", asset.SerialNumber,
+			issue.Custody.Resident.RegistryNumber, issue.Custody.ConditionOut, issue.Custody.ConditionIn,
+			*rounds, issue.RoundsIssued)
+		return nil
+	default:
+		return fmt.Errorf("unknown armory subcommand: %s", args[0])
+	}
+}
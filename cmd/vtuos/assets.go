@@ -0,0 +1,316 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/config"
+	"github.com/vtuos/vtuos/internal/database"
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/services/population"
+	"github.com/vtuos/vtuos/internal/services/resources"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// runAssetsCommand handles `vtuos assets <subcommand>`: register tools,
+// weapons, Pip-Boys and other individually tracked assets, check them out
+// to and in from residents, record their condition, retire them and review
+// the registry and an asset's history.
+func runAssetsCommand(ctx context.Context, configPath, profile string, args []string) error {
+	if len(args) == 0 {
+		flag.Usage()
+		return fmt.Errorf("assets requires a subcommand: list, show, register, checkout, checkin, condition or retire")
+	}
+
+	cfg, err := loadConfig(configPath, profile)
+	if err != nil {
+		return fmt.Errorf("loading configuration: %w", err)
+	}
+
+	dbPath, err := config.EnsureDataDir(cfg)
+	if err != nil {
+		return fmt.Errorf("ensuring data directory: %w", err)
+	}
+	backupDir, err := config.BackupDir(cfg)
+	if err != nil {
+		return fmt.Errorf("getting backup directory: %w", err)
+	}
+
+	db, err := database.Open(dbPath, &cfg.Database, backupDir)
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	j, err := openJournal(cfg, nil)
+	if err != nil {
+		return err
+	}
+	defer j.Close()
+
+	svc := resources.NewService(db.DB)
+	svc.SetVault(cfg.Vault.Number)
+	svc.SetJournal(j)
+	popSvc := population.NewService(db.DB, cfg.Vault.Number)
+	now := time.Now().UTC()
+	if startTime, err := cfg.Simulation.StartDateTime(); err == nil {
+		svc.SetClock(util.NewVaultClock(startTime, 0))
+		now = startTime.UTC()
+	}
+
+	// officer looks up the resident given to --by, nil when none was given
+	officer := func(regNum string) (*string, error) {
+		if regNum == "" {
+			return nil, nil
+		}
+		resident, err := popSvc.GetResidentByRegistryNumber(ctx, regNum)
+		if err != nil {
+			return nil, fmt.Errorf("resident %s: %w", regNum, err)
+		}
+		return &resident.ID, nil
+	}
+
+	switch args[0] {
+	case "list":
+		fs := flag.NewFlagSet("list", flag.ContinueOnError)
+		category := fs.String("category", "", "Only assets of this category")
+		holder := fs.String("holder", "", "Only assets in the custody of this registry number")
+		all := fs.Bool("all", false, "Include retired assets")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+
+		filter := models.AssetFilter{IncludeRetired: *all}
+		if *category != "" {
+			if filter.Category, err = models.ParseAssetCategory(*category); err != nil {
+				return err
+			}
+		}
+		if *holder != "" {
+			resident, err := popSvc.GetResidentByRegistryNumber(ctx, *holder)
+			if err != nil {
+				return fmt.Errorf("resident %s: %w", *holder, err)
+			}
+			filter.ResidentID = resident.ID
+		}
+		assets, err := svc.ListAssets(ctx, filter)
+		if err != nil {
+			return fmt.Errorf("listing assets: %w", err)
+		}
+		if len(assets) == 0 {
+			fmt.Println("No assets")
+			return nil
+		}
+		for _, a := range assets {
+			fmt.Printf("%-16s %-28s %-11s %-9s %s\n", a.SerialNumber, a.Name, a.Category, a.Condition, assetWhereabouts(a, now))
+		}
+		return nil
+	case "show":
+		if len(args) != 2 {
+			return fmt.Errorf("assets show requires a serial number")
+		}
+		asset, err := svc.GetAssetBySerial(ctx, args[1])
+		if err != nil {
+			return err
+		}
+		history, err := svc.ListAssetCustody(ctx, asset.ID)
+		if err != nil {
+			return fmt.Errorf("listing custody: %w", err)
+		}
+		reports, err := svc.ListAssetConditions(ctx, asset.ID)
+		if err != nil {
+			return fmt.Errorf("listing condition reports: %w", err)
+		}
+
+		fmt.Printf("%s %s (%s), %s, %s\n", asset.SerialNumber, asset.Name, asset.Category, asset.Condition,
+			assetWhereabouts(asset, now))
+		if asset.Notes != "" {
+			fmt.Printf("  %s\n", asset.Notes)
+		}
+		fmt.Println("Custody:")
+		for _, c := range history {
+			returned := "held"
+			if c.ReturnedAt != nil {
+				returned = fmt.Sprintf("returned %s %s", util.FormatDay(*c.ReturnedAt), c.ConditionIn)
+			}
+			due := "assignment"
+			if c.DueAt != nil {
+				due = "due " + util.FormatDay(*c.DueAt)
+			}
+			fmt.Printf("  %s  %-12s %-24s %-9s %-16s %s  %s\n", util.FormatDay(c.IssuedAt), c.Resident.RegistryNumber,
+				c.Resident.FullName(), c.ConditionOut, due, returned, c.Notes)
+		}
+		fmt.Println("Condition reports:")
+		for _, r := range reports {
+			fmt.Printf("  %s  %-9s → %-9s %s\n", util.FormatDay(r.RecordedAt), r.Previous, r.Condition, r.Notes)
+		}
+		return nil
+	case "register":
+		fs := flag.NewFlagSet("register", flag.ContinueOnError)
+		condition := fs.String("condition", "good", "Condition on registration")
+		location := fs.String("location", "", "Where the asset is stored")
+		acquired := fs.String("acquired", "", "Acquisition date YYYY-MM-DD")
+		note := fs.String("note", "", "Note recorded with the asset")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if fs.NArg() < 3 {
+			return fmt.Errorf("assets register requires a serial number, a category and a name")
+		}
+		category, err := models.ParseAssetCategory(fs.Arg(1))
+		if err != nil {
+			return err
+		}
+		input := resources.AssetInput{
+			SerialNumber: fs.Arg(0),
+			Name:         strings.Join(fs.Args()[2:], " "),
+			Category:     category,
+			Location:     *location,
+			Notes:        *note,
+		}
+		if input.Condition, err = models.ParseAssetCondition(*condition); err != nil {
+			return err
+		}
+		if *acquired != "" {
+			date, err := time.Parse(time.DateOnly, *acquired)
+			if err != nil {
+				return fmt.Errorf("invalid --acquired: %w", err)
+			}
+			input.AcquiredAt = &date
+		}
+		asset, err := svc.RegisterAsset(ctx, input)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Registered %s %s (%s), %s\n", asset.Category, asset.SerialNumber, asset.Name, asset.Condition)
+		return nil
+	case "checkout":
+		fs := flag.NewFlagSet("checkout", flag.ContinueOnError)
+		days := fs.Int("days", 0, "Days until the asset is due back (default: a standing assignment)")
+		by := fs.String("by", "", "Registry number of the issuing officer")
+		note := fs.String("note", "", "Note recorded with the check-out")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if fs.NArg() != 2 {
+			return fmt.Errorf("assets checkout requires a serial number and a registry number")
+		}
+		asset, err := svc.GetAssetBySerial(ctx, fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		resident, err := popSvc.GetResidentByRegistryNumber(ctx, fs.Arg(1))
+		if err != nil {
+			return fmt.Errorf("resident %s: %w", fs.Arg(1), err)
+		}
+		input := resources.CheckOutInput{AssetID: asset.ID, ResidentID: resident.ID, Notes: *note}
+		if input.IssuedBy, err = officer(*by); err != nil {
+			return err
+		}
+		if *days > 0 {
+			due := now.Truncate(time.Second).AddDate(0, 0, *days)
+			input.DueAt = &due
+		}
+		custody, err := svc.CheckOutAsset(ctx, input)
+		if err != nil {
+			return err
+		}
+		if custody.IsAssignment() {
+			fmt.Printf("Assigned %s to %s %s\n", asset.SerialNumber, resident.RegistryNumber, resident.FullName())
+			return nil
+		}
+		fmt.Printf("Checked out %s to %s %s, due %s\n", asset.SerialNumber, resident.RegistryNumber,
+			resident.FullName(), util.FormatDay(*custody.DueAt))
+		return nil
+	case "checkin", "condition":
+		fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+		by := fs.String("by", "", "Registry number of the receiving or inspecting officer")
+		note := fs.String("note", "", "Note recorded with the condition found")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if fs.NArg() != 2 {
+			return fmt.Errorf("assets %s requires a serial number and a condition", args[0])
+		}
+		asset, err := svc.GetAssetBySerial(ctx, fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		condition, err := models.ParseAssetCondition(fs.Arg(1))
+		if err != nil {
+			return err
+		}
+		officerID, err := officer(*by)
+		if err != nil {
+			return err
+		}
+
+		if args[0] == "condition" {
+			report, err := svc.RecordAssetCondition(ctx, resources.AssetConditionInput{
+				AssetID:    asset.ID,
+				Condition:  condition,
+				RecordedBy: officerID,
+				Notes:      *note,
+			})
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%s: %s → %s\n", asset.SerialNumber, report.Previous, report.Condition)
+			return nil
+		}
+		custody, err := svc.CheckInAsset(ctx, resources.CheckInInput{
+			AssetID:    asset.ID,
+			Condition:  condition,
+			ReceivedBy: officerID,
+			Notes:      *note,
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Checked in %s from %s: %s → %s\n", asset.SerialNumber, custody.Resident.RegistryNumber,
+			custody.ConditionOut, custody.ConditionIn)
+		return nil
+	case "retire":
+		fs := flag.NewFlagSet("retire", flag.ContinueOnError)
+		reason := fs.String("reason", "", "Why the asset is written off")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if fs.NArg() != 1 {
+			return fmt.Errorf("assets retire requires a serial number")
+		}
+		asset, err := svc.GetAssetBySerial(ctx, fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		if err := svc.RetireAsset(ctx, asset.ID, *reason); err != nil {
+			return err
+		}
+		fmt.Printf("Retired %s\n", asset.SerialNumber)
+		return nil
+	default:
+		return fmt.Errorf("unknown assets subcommand: %s", args[0])
+	}
+}
+
+// assetWhereabouts describes where an asset is: retired, in store at its
+// location, or in a resident's custody and when it is due back.
+func assetWhereabouts(a *models.Asset, now time.Time) string {
+	c := a.Custody
+	switch {
+	case a.IsRetired():
+		return "RETIRED " + util.FormatDay(*a.RetiredAt)
+	case c == nil && a.Location != "":
+		return "in store at " + a.Location
+	case c == nil:
+		return "in store"
+	case c.IsOverdue(now):
+		return fmt.Sprintf("%s OVERDUE since %s", c.Resident.RegistryNumber, util.FormatDay(*c.DueAt))
+	case c.DueAt != nil:
+		return fmt.Sprintf("%s due %s", c.Resident.RegistryNumber, util.FormatDay(*c.DueAt))
+	}
+	return c.Resident.RegistryNumber + " (assigned)"
+}
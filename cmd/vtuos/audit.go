@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/vtuos/vtuos/internal/config"
+	"github.com/vtuos/vtuos/internal/database"
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/services/population"
+	"github.com/vtuos/vtuos/internal/services/resources"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// runAuditCommand handles `vtuos audit <subcommand>`, the inventory audit
+// workflow: open a session for a storage location, record lot counts, then
+// close it to apply every correction at once. Counts taken outside a
+// session are imported from a CSV file.
+func runAuditCommand(ctx context.Context, configPath, profile string, args []string) error {
+	if len(args) == 0 {
+		flag.Usage()
+		return fmt.Errorf("audit requires a subcommand: open, list, count, report, close, import or cancel")
+	}
+
+	cfg, err := loadConfig(configPath, profile)
+	if err != nil {
+		return fmt.Errorf("loading configuration: %w", err)
+	}
+
+	dbPath, err := config.EnsureDataDir(cfg)
+	if err != nil {
+		return fmt.Errorf("ensuring data directory: %w", err)
+	}
+	backupDir, err := config.BackupDir(cfg)
+	if err != nil {
+		return fmt.Errorf("getting backup directory: %w", err)
+	}
+
+	db, err := database.Open(dbPath, &cfg.Database, backupDir)
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	j, err := openJournal(cfg, nil)
+	if err != nil {
+		return err
+	}
+	defer j.Close()
+
+	svc := resources.NewService(db.DB)
+	svc.SetVault(cfg.Vault.Number)
+	svc.SetJournal(j)
+
+	switch args[0] {
+	case "open":
+		if len(args) != 2 {
+			return fmt.Errorf("audit open requires a storage location")
+		}
+		audit, err := svc.OpenAudit(ctx, args[1], nil)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Opened audit %s of %s with %d lot(s)\n", audit.ID, audit.StorageLocation, len(audit.Counts))
+		for _, c := range audit.Counts {
+			fmt.Printf("  %-38s %-18s %-14s %10.2f %s\n", c.StockID, c.ItemCode, lotLabel(c.LotNumber), c.ExpectedQuantity, c.UnitOfMeasure)
+		}
+		return nil
+	case "list":
+		return auditList(ctx, svc)
+	case "count":
+		if len(args) != 4 {
+			return fmt.Errorf("audit count requires an audit ID, a stock ID or lot number, and the counted quantity")
+		}
+		counted, err := strconv.ParseFloat(args[3], 64)
+		if err != nil {
+			return fmt.Errorf("invalid quantity: %s", args[3])
+		}
+		count, err := svc.RecordAuditCount(ctx, args[1], args[2], counted)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s %s: expected %.2f, counted %.2f (%+.2f)\n",
+			count.ItemCode, lotLabel(count.LotNumber), count.ExpectedQuantity, counted, count.Variance())
+		return nil
+	case "report", "close":
+		fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+		outPath := fs.String("out", "", "File to write the variance report to (default: stdout)")
+		id, err := parseWithName(fs, args[1:])
+		if err != nil {
+			return err
+		}
+		if id == "" {
+			return fmt.Errorf("audit %s requires an audit ID", args[0])
+		}
+
+		var audit *models.InventoryAudit
+		if args[0] == "close" {
+			audit, err = svc.CloseAudit(ctx, id, nil)
+		} else {
+			audit, err = svc.GetAudit(ctx, id)
+		}
+		if err != nil {
+			return err
+		}
+		return writeAuditReport(audit, *outPath)
+	case "import":
+		fs := flag.NewFlagSet("import", flag.ContinueOnError)
+		by := fs.String("by", "", "Registry number of the operator the corrections are recorded under")
+		countersign := fs.String("countersign", "", "Registry number of a second operator confirming a write-off, asked for their PIN")
+		file, err := parseWithName(fs, args[1:])
+		if err != nil {
+			return err
+		}
+		if file == "" {
+			return fmt.Errorf("audit import requires a CSV file of counts")
+		}
+		if *countersign != "" && *by == "" {
+			return fmt.Errorf("--countersign needs --by")
+		}
+		return auditImport(ctx, svc, population.NewService(db.DB, cfg.Vault.Number), file, *by, *countersign)
+	case "cancel":
+		if len(args) != 2 {
+			return fmt.Errorf("audit cancel requires an audit ID")
+		}
+		if err := svc.CancelAudit(ctx, args[1]); err != nil {
+			return err
+		}
+		fmt.Printf("Cancelled audit %s\n", args[1])
+		return nil
+	default:
+		return fmt.Errorf("unknown audit subcommand: %s", args[0])
+	}
+}
+
+// auditImport sets lots to the physical counts in a CSV file, printing the
+// lines it rejected.
+func auditImport(ctx context.Context, svc *resources.Service, popSvc *population.Service, path, by, countersign string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("opening count file: %w", err)
+	}
+	defer f.Close()
+
+	var authorizedBy *string
+	if by != "" {
+		operator, err := popSvc.GetResidentByRegistryNumber(ctx, by)
+		if err != nil {
+			return fmt.Errorf("operator %s: %w", by, err)
+		}
+		authorizedBy = &operator.ID
+	}
+	if countersign != "" {
+		pin, err := readSecret(fmt.Sprintf("PIN of %s: ", countersign))
+		if err != nil {
+			return err
+		}
+		ctx = util.WithCountersign(ctx, util.Countersign{RequestedBy: by, By: countersign, PIN: pin})
+	}
+
+	result, err := svc.ImportCounts(ctx, f, authorizedBy)
+	if err != nil {
+		return fmt.Errorf("importing counts: %w", err)
+	}
+	for _, e := range result.Errors {
+		fmt.Fprintln(os.Stderr, e.Error())
+	}
+	fmt.Printf("Corrected %d lot(s), %d matched their count\n", result.Applied, result.Skipped)
+	if len(result.Errors) > 0 {
+		return fmt.Errorf("%d line(s) rejected and not applied", len(result.Errors))
+	}
+	return nil
+}
+
+// auditList prints every audit session, most recent first.
+func auditList(ctx context.Context, svc *resources.Service) error {
+	audits, err := svc.ListAudits(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("listing audits: %w", err)
+	}
+	if len(audits) == 0 {
+		fmt.Println("No inventory audits")
+		return nil
+	}
+	for _, a := range audits {
+		fmt.Printf("%-38s %-16s %-10s opened %s\n",
+			a.ID, a.StorageLocation, a.Status, util.FormatDateTime(a.OpenedAt))
+	}
+	return nil
+}
+
+// writeAuditReport prints the variance report of an audit, or writes it to
+// outPath for printing.
+func writeAuditReport(audit *models.InventoryAudit, outPath string) error {
+	report := audit.ReportText()
+	if outPath == "" {
+		fmt.Print(report)
+		return nil
+	}
+	if err := os.WriteFile(outPath, []byte(report), 0640); err != nil {
+		return fmt.Errorf("writing report: %w", err)
+	}
+	fmt.Printf("Wrote variance report of audit %s (%s) to %s\n", audit.ID, audit.Status, outPath)
+	return nil
+}
+
+// lotLabel returns a lot number for display.
+func lotLabel(lot *string) string {
+	if lot == nil {
+		return "-"
+	}
+	return *lot
+}
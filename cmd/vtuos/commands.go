@@ -0,0 +1,750 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/config"
+	"github.com/vtuos/vtuos/internal/database"
+	"github.com/vtuos/vtuos/internal/exchange"
+	"github.com/vtuos/vtuos/internal/services/governance"
+	"github.com/vtuos/vtuos/internal/services/inspections"
+	"github.com/vtuos/vtuos/internal/services/population"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// usage prints command line help, including subcommands.
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage: vtuos [flags] [command]\n\n")
+	fmt.Fprintf(out, "Commands:\n")
+	fmt.Fprintf(out, "  config validate [--quiet]             Check the configuration and print it as in force\n")
+	fmt.Fprintf(out, "  migrate status                        Show applied and pending migrations\n")
+	fmt.Fprintf(out, "  migrate undo-last [--restore-backup]  Revert the most recent migration run\n")
+	fmt.Fprintf(out, "  db optimize [--convert]               Reclaim free space and checkpoint the WAL\n")
+	fmt.Fprintf(out, "  encryption keygen | encryption status Print a new master key / show what is encrypted\n")
+	fmt.Fprintf(out, "  encryption rotate                     Re-encrypt sensitive columns under a new data key\n")
+	fmt.Fprintf(out, "  encryption rewrap --old-key-env VAR | --old-key-file FILE\n")
+	fmt.Fprintf(out, "                                        Wrap the data keys with the configured master key\n")
+	fmt.Fprintf(out, "  encryption decrypt                    Decrypt everything before disabling encryption\n")
+	fmt.Fprintf(out, "  inspections overdue [--as-of DATE]    List scheduled inspections past their date\n")
+	fmt.Fprintf(out, "  report planning [--years N] [--as-of DATE]\n")
+	fmt.Fprintf(out, "                                        Population, workforce and staffing forecast\n")
+	fmt.Fprintf(out, "  report capacity [--years N] [--as-of DATE]\n")
+	fmt.Fprintf(out, "                                        Population forecast against capacity and food production\n")
+	fmt.Fprintf(out, "  snapshot create [NAME] [--note TEXT] [--as-of DATE]\n")
+	fmt.Fprintf(out, "                                        Take a named point-in-time snapshot\n")
+	fmt.Fprintf(out, "  snapshot list                         List snapshots\n")
+	fmt.Fprintf(out, "  snapshot restore NAME [--yes]         Replace the database with a snapshot\n")
+	fmt.Fprintf(out, "  archive write [--out FILE] [--report-days N] [--note TEXT]\n")
+	fmt.Fprintf(out, "                                        Write a checksummed holotape for off-site storage\n")
+	fmt.Fprintf(out, "  archive read FILE [--extract DIR] [--reports PATTERN]\n")
+	fmt.Fprintf(out, "                                        Verify a holotape, extracting it or some of its reports\n")
+	fmt.Fprintf(out, "  audit open LOCATION                   Open an inventory audit of a storage location\n")
+	fmt.Fprintf(out, "  audit count ID LOT QTY                Record the counted quantity of a lot\n")
+	fmt.Fprintf(out, "  audit report|close ID [--out FILE]    Print the variance report / apply corrections\n")
+	fmt.Fprintf(out, "  audit import FILE [--by REG] [--countersign REG]\n")
+	fmt.Fprintf(out, "                                        Set lots to the counts in a CSV of lot,counted[,reason]\n")
+	fmt.Fprintf(out, "  audit list | audit cancel ID          List audits / abandon an open audit\n")
+	fmt.Fprintf(out, "  delivery import FILE [--location L] [--reference REF] [--create-items --category CODE] [--out FILE]\n")
+	fmt.Fprintf(out, "                                        Receive a delivery CSV and report lines not matching the books\n")
+	fmt.Fprintf(out, "  relationship add TYPE REG REG [--since DATE] [--note TEXT]\n")
+	fmt.Fprintf(out, "                                        Record a marriage, partnership, guardianship or next-of-kin\n")
+	fmt.Fprintf(out, "  relationship list REG | relationship end ID [--reason TEXT]\n")
+	fmt.Fprintf(out, "                                        List a resident's relationships / end one\n")
+	fmt.Fprintf(out, "  tags add|remove REG TAG... | tags show REG\n")
+	fmt.Fprintf(out, "                                        Tag a resident or take tags off them / list their tags\n")
+	fmt.Fprintf(out, "  tags list [TAG...] | tags presets     List tags in use, or residents carrying them / census presets\n")
+	fmt.Fprintf(out, "  radiation expose [--source TEXT] [--date DATE] REG MSV\n")
+	fmt.Fprintf(out, "                                        Record a radiation exposure\n")
+	fmt.Fprintf(out, "  radiation treat REG [--units N] [--item CODE]\n")
+	fmt.Fprintf(out, "                                        Decontaminate a resident, drawing RadAway from stock\n")
+	fmt.Fprintf(out, "  radiation show REG | radiation flagged\n")
+	fmt.Fprintf(out, "                                        Show a resident's dose history / list flagged residents\n")
+	fmt.Fprintf(out, "  morale score [--as-of DATE]           Score residents' morale and record the happiness index\n")
+	fmt.Fprintf(out, "  morale index [--limit N] | morale show REG\n")
+	fmt.Fprintf(out, "                                        Show the happiness index by sector / a resident's score\n")
+	fmt.Fprintf(out, "  activities venues | activities add-venue [--name TEXT] CODE TYPE SECTOR CAPACITY\n")
+	fmt.Fprintf(out, "                                        List / open classrooms, workshops and recreation venues\n")
+	fmt.Fprintf(out, "  activities schedule [--vocation CODE] [--instructor REG] [--ages MIN-MAX] CODE TYPE VENUE DAYS HOUR HOURS NAME\n")
+	fmt.Fprintf(out, "                                        Schedule a SCHOOL, SKILLS or RECREATION activity weekly\n")
+	fmt.Fprintf(out, "  activities list [--all] | activities cancel CODE\n")
+	fmt.Fprintf(out, "                                        List the schedule with enrollment and attendance / cancel one\n")
+	fmt.Fprintf(out, "  activities enroll CODE REG... | activities enroll --eligible CODE | activities leave CODE REG\n")
+	fmt.Fprintf(out, "                                        Enroll residents, or everyone of the ages admitted / remove one\n")
+	fmt.Fprintf(out, "  activities attend [--date DATE] CODE REG...\n")
+	fmt.Fprintf(out, "                                        Record a session's attendance, enrollees not listed absent\n")
+	fmt.Fprintf(out, "  maintenance consumable SYSTEM ITEM QTY DAYS\n")
+	fmt.Fprintf(out, "                                        Declare a consumable a system replaces every DAYS days\n")
+	fmt.Fprintf(out, "  maintenance consumables [SYSTEM] | maintenance open\n")
+	fmt.Fprintf(out, "                                        List consumables and when they fall due / open work orders\n")
+	fmt.Fprintf(out, "  maintenance complete ID [--outcome OUTCOME] [--by REG] [--date DATE] [--hours H] [--efficiency PCT]\n")
+	fmt.Fprintf(out, "                                        Close a work order, replacing the consumables due\n")
+	fmt.Fprintf(out, "  maintenance depend|undepend SYSTEM PARENT\n")
+	fmt.Fprintf(out, "                                        Declare / remove a system's dependency on another\n")
+	fmt.Fprintf(out, "  maintenance dependencies              List systems with what they depend on and root causes\n")
+	fmt.Fprintf(out, "  maintenance technician REG SHIFT [CATEGORY=RATING ...]\n")
+	fmt.Fprintf(out, "                                        Set a technician's shift and 1-5 skill ratings\n")
+	fmt.Fprintf(out, "  maintenance technicians | maintenance suggest ID\n")
+	fmt.Fprintf(out, "                                        List the skill matrix / rank technicians for a work order\n")
+	fmt.Fprintf(out, "  maintenance assign ID [REG]           Set or clear a work order's lead technician\n")
+	fmt.Fprintf(out, "  maintenance shed SYSTEM PRIORITY      Set the order a power load is shed in, 1 first, 0 never\n")
+	fmt.Fprintf(out, "  maintenance loads                     List power loads in shedding order\n")
+	fmt.Fprintf(out, "  maintenance duty [--date DATE] SYSTEM PERCENT [HOURS]\n")
+	fmt.Fprintf(out, "                                        Set a system's duty cycle and runtime maintenance interval\n")
+	fmt.Fprintf(out, "  maintenance runtime                   List systems' runtime and when maintenance falls due\n")
+	fmt.Fprintf(out, "  maintenance analytics [--days N] [--as-of DATE]\n")
+	fmt.Fprintf(out, "                                        Show repair times by category and the most failure-prone systems\n")
+	fmt.Fprintf(out, "  maintenance commission [--interval DAYS] [--mtbf HOURS] [--by REG] CODE CATEGORY SECTOR LEVEL NAME\n")
+	fmt.Fprintf(out, "                                        Put a new system into service and raise its inspection\n")
+	fmt.Fprintf(out, "  maintenance decommission SYSTEM --reason TEXT [--dispose-spares] [--by REG] [--countersign REG]\n")
+	fmt.Fprintf(out, "                                        Retire a system, cancelling its work orders\n")
+	fmt.Fprintf(out, "  maintenance environment               Show the latest environment reading of each sector\n")
+	fmt.Fprintf(out, "  lockdown status | lockdown history    Show the vault alert state / its recent changes\n")
+	fmt.Fprintf(out, "  lockdown set STATE REG [--reason TEXT]\n")
+	fmt.Fprintf(out, "                                        Move the vault to NORMAL, DRILL, LOCKDOWN or EMERGENCY\n")
+	fmt.Fprintf(out, "  access points | access add-point CODE door|airlock CLEARANCE [--name TEXT] [--sector S] [--level N]\n")
+	fmt.Fprintf(out, "                                        List / register doors and airlocks\n")
+	fmt.Fprintf(out, "  access enable|disable CODE | access clearance CODE N\n")
+	fmt.Fprintf(out, "                                        Enable or disable a point / set its minimum clearance\n")
+	fmt.Fprintf(out, "  access grant [--days N] [--by REG] [--reason TEXT] CODE REG\n")
+	fmt.Fprintf(out, "                                        Let a resident through a point whatever their clearance\n")
+	fmt.Fprintf(out, "  access grants REG | access revoke ID  List a resident's grants / revoke one\n")
+	fmt.Fprintf(out, "  access try CODE REG                   Log a credential presented at a point\n")
+	fmt.Fprintf(out, "  access log [--point CODE] [--resident REG] [--denied|--granted] [--limit N]\n")
+	fmt.Fprintf(out, "                                        Review the access log\n")
+	fmt.Fprintf(out, "  assets list [--category C] [--holder REG] [--all] | assets show SERIAL\n")
+	fmt.Fprintf(out, "                                        List tracked tools, weapons and Pip-Boys / show one's history\n")
+	fmt.Fprintf(out, "  assets register [--condition C] [--location L] [--acquired DATE] SERIAL CATEGORY NAME\n")
+	fmt.Fprintf(out, "                                        Register an asset tracked by serial number\n")
+	fmt.Fprintf(out, "  assets checkout [--days N] [--by REG] [--note TEXT] SERIAL REG\n")
+	fmt.Fprintf(out, "                                        Issue an asset; without --days a standing assignment\n")
+	fmt.Fprintf(out, "  assets checkin|condition [--by REG] [--note TEXT] SERIAL CONDITION\n")
+	fmt.Fprintf(out, "                                        Return an asset / record its condition found\n")
+	fmt.Fprintf(out, "  assets retire [--reason TEXT] SERIAL  Write an asset off the registry\n")
+	fmt.Fprintf(out, "  armory list | armory weapons          Show who holds which weapon / list armory weapons\n")
+	fmt.Fprintf(out, "  armory authorize [--ammo ITEM] SERIAL CLEARANCE\n")
+	fmt.Fprintf(out, "                                        Set the clearance needed to carry a weapon\n")
+	fmt.Fprintf(out, "  armory issue [--incident INC] [--rounds N] [--days N] [--note TEXT] SERIAL REG APPROVER\n")
+	fmt.Fprintf(out, "                                        Issue a weapon for an open incident or the drill in progress\n")
+	fmt.Fprintf(out, "  armory return [--rounds N] [--by REG] [--note TEXT] SERIAL CONDITION\n")
+	fmt.Fprintf(out, "                                        Take a weapon back, drawing the rounds expended from stock\n")
+	fmt.Fprintf(out, "  announce list [--all] | announce inbox REG\n")
+	fmt.Fprintf(out, "                                        List posted announcements / show a resident's inbox\n")
+	fmt.Fprintf(out, "  announce post [--notice] [--to all|department:NAME|household:H-NNNN] [--by REG] [--days N] TITLE [BODY]\n")
+	fmt.Fprintf(out, "                                        Post an overseer broadcast or department notice\n")
+	fmt.Fprintf(out, "  announce ack REG [ID]                 Acknowledge an announcement, or all in the inbox\n")
+	fmt.Fprintf(out, "  sessions [--days N] [--list]          Summarize operators' terminal sessions and changes made\n")
+	fmt.Fprintf(out, "  alerts list [--all] [--for REG] | alerts routes\n")
+	fmt.Fprintf(out, "                                        List recorded alerts / list the rules routing them\n")
+	fmt.Fprintf(out, "  alerts ack|resolve REG ID [NOTES]     Acknowledge or resolve an alert as an operator\n")
+	fmt.Fprintf(out, "  alerts route add [--source S] [--severity warning|critical] [--escalate-to DEPT] [--escalate-after HOURS] DEPT|REG\n")
+	fmt.Fprintf(out, "                                        Route alerts to a department or operator\n")
+	fmt.Fprintf(out, "  alerts route remove ID                Remove a routing rule\n")
+	fmt.Fprintf(out, "  dualauth policy list | dualauth policy remove OPERATION\n")
+	fmt.Fprintf(out, "                                        List / release the operations needing a second operator\n")
+	fmt.Fprintf(out, "  dualauth policy set [--clearance N] [--threshold QTY] OPERATION\n")
+	fmt.Fprintf(out, "                                        Hold exile, dissolve_household or stock_write_off for two operators\n")
+	fmt.Fprintf(out, "  dualauth pin REG                      Set the PIN an operator confirms held operations with\n")
+	fmt.Fprintf(out, "  badges print-all [--out FILE]         Print ID badges of all active residents\n")
+	fmt.Fprintf(out, "  export hq [--mode MODE] [--datasets LIST] [--out DIR] [--as-of DATE]\n")
+	fmt.Fprintf(out, "                                        Write anonymized datasets for Vault-Tec HQ\n")
+	fmt.Fprintf(out, "  transfer export [--household] [--medical] [--out FILE] [--operator REGNUM] REG\n")
+	fmt.Fprintf(out, "                                        Write a signed bundle transferring a resident to another vault\n")
+	fmt.Fprintf(out, "  transfer import FILE                  Admit the residents of a bundle from another vault\n")
+	fmt.Fprintf(out, "  export-analytics [--format sqlite|parquet] [--datasets LIST] [--out DIR] [--operator REGNUM]\n")
+	fmt.Fprintf(out, "                                        Copy residents, transactions and metrics for offline analysis\n")
+	fmt.Fprintf(out, "  replay JOURNAL [--db PATH] [--quiet]   Re-run a command journal against a fresh database\n")
+	fmt.Fprintf(out, "  grpc-serve [--listen ADDR] [--tls-cert FILE --tls-key FILE] [--client-ca FILE] [--insecure]\n")
+	fmt.Fprintf(out, "                                        Serve census, inventory and facility data to other vaults\n\n")
+	fmt.Fprintf(out, "Flags:\n")
+	flag.PrintDefaults()
+}
+
+// runCommand dispatches a subcommand given after the flags.
+func runCommand(ctx context.Context, configPath, profile string, args []string) error {
+	switch args[0] {
+	case "config":
+		return runConfigCommand(configPath, profile, args[1:])
+	case "migrate":
+		return runMigrateCommand(ctx, configPath, profile, args[1:])
+	case "db":
+		return runDBCommand(ctx, configPath, profile, args[1:])
+	case "encryption":
+		return runEncryptionCommand(ctx, configPath, profile, args[1:])
+	case "inspections":
+		return runInspectionsCommand(ctx, configPath, profile, args[1:])
+	case "report":
+		return runReportCommand(ctx, configPath, profile, args[1:])
+	case "snapshot":
+		return runSnapshotCommand(ctx, configPath, profile, args[1:])
+	case "archive":
+		return runArchiveCommand(ctx, configPath, profile, args[1:])
+	case "audit":
+		return runAuditCommand(ctx, configPath, profile, args[1:])
+	case "delivery":
+		return runDeliveryCommand(ctx, configPath, profile, args[1:])
+	case "relationship":
+		return runRelationshipCommand(ctx, configPath, profile, args[1:])
+	case "tags":
+		return runTagsCommand(ctx, configPath, profile, args[1:])
+	case "radiation":
+		return runRadiationCommand(ctx, configPath, profile, args[1:])
+	case "morale":
+		return runMoraleCommand(ctx, configPath, profile, args[1:])
+	case "activities":
+		return runActivitiesCommand(ctx, configPath, profile, args[1:])
+	case "maintenance":
+		return runMaintenanceCommand(ctx, configPath, profile, args[1:])
+	case "lockdown":
+		return runLockdownCommand(ctx, configPath, profile, args[1:])
+	case "access":
+		return runAccessCommand(ctx, configPath, profile, args[1:])
+	case "assets":
+		return runAssetsCommand(ctx, configPath, profile, args[1:])
+	case "armory":
+		return runArmoryCommand(ctx, configPath, profile, args[1:])
+	case "announce":
+		return runAnnounceCommand(ctx, configPath, profile, args[1:])
+	case "sessions":
+		return runSessionsCommand(ctx, configPath, profile, args[1:])
+	case "alerts":
+		return runAlertsCommand(ctx, configPath, profile, args[1:])
+	case "dualauth":
+		return runDualAuthCommand(ctx, configPath, profile, args[1:])
+	case "badges":
+		return runBadgesCommand(ctx, configPath, profile, args[1:])
+	case "export":
+		return runExportCommand(ctx, configPath, profile, args[1:])
+	case "transfer":
+		return runTransferCommand(ctx, configPath, profile, args[1:])
+	case "export-analytics":
+		return runExportAnalyticsCommand(ctx, configPath, profile, args[1:])
+	case "replay":
+		return runReplayCommand(ctx, configPath, profile, args[1:])
+	case "grpc-serve":
+		return runGRPCServeCommand(ctx, configPath, profile, args[1:])
+	default:
+		flag.Usage()
+		return fmt.Errorf("unknown command: %s", args[0])
+	}
+}
+
+// loadConfig loads the configuration of a subcommand in the named
+// environment profile, as given to --profile, and shows dates and times on
+// its calendar, in its locale.
+func loadConfig(configPath, profile string) (*config.Config, error) {
+	cfg, _, err := config.Load(configPath, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.UseProfile(profile); err != nil {
+		return nil, err
+	}
+	util.SetCalendar(cfg.Calendar())
+	return cfg, nil
+}
+
+// vaultTime returns the vault time a subcommand acts at: the date given as
+// YYYY-MM-DD, or else the simulation start date, or the current time when
+// that is not set.
+func vaultTime(cfg *config.Config, date string) (time.Time, error) {
+	if date != "" {
+		return util.ParseDate(date)
+	}
+	if start, err := cfg.Simulation.StartDateTime(); err == nil {
+		return start, nil
+	}
+	return time.Now().UTC(), nil
+}
+
+// runMigrateCommand handles `vtuos migrate <subcommand>`.
+func runMigrateCommand(ctx context.Context, configPath, profile string, args []string) error {
+	if len(args) == 0 {
+		flag.Usage()
+		return fmt.Errorf("migrate requires a subcommand")
+	}
+
+	cfg, err := loadConfig(configPath, profile)
+	if err != nil {
+		return fmt.Errorf("loading configuration: %w", err)
+	}
+
+	dbPath, err := config.EnsureDataDir(cfg)
+	if err != nil {
+		return fmt.Errorf("ensuring data directory: %w", err)
+	}
+
+	backupDir, err := config.BackupDir(cfg)
+	if err != nil {
+		return fmt.Errorf("getting backup directory: %w", err)
+	}
+
+	switch args[0] {
+	case "status":
+		return migrateStatus(ctx, dbPath, cfg, backupDir)
+	case "undo-last":
+		fs := flag.NewFlagSet("undo-last", flag.ContinueOnError)
+		restore := fs.Bool("restore-backup", false, "Restore the pre-migration backup instead of running down migrations")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		return migrateUndoLast(ctx, dbPath, cfg, backupDir, *restore)
+	default:
+		return fmt.Errorf("unknown migrate subcommand: %s", args[0])
+	}
+}
+
+// migrateStatus prints every known migration and whether it is applied.
+func migrateStatus(ctx context.Context, dbPath string, cfg *config.Config, backupDir string) error {
+	db, err := database.Open(dbPath, &cfg.Database, backupDir)
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		return fmt.Errorf("creating migrator: %w", err)
+	}
+
+	migrations, err := migrator.Status(ctx)
+	if err != nil {
+		return fmt.Errorf("reading migration status: %w", err)
+	}
+
+	for _, mig := range migrations {
+		state := "pending"
+		if mig.Applied {
+			state = "applied " + mig.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		if mig.Drifted() {
+			state += "  DRIFT: file changed since applied"
+		}
+		fmt.Printf("%03d  %-30s %s\n", mig.Version, mig.Description, state)
+	}
+
+	return nil
+}
+
+// migrateUndoLast reverts the most recent migration run, either with down
+// migrations or by restoring the backup taken before it.
+func migrateUndoLast(ctx context.Context, dbPath string, cfg *config.Config, backupDir string, restore bool) error {
+	if _, err := os.Stat(dbPath); err != nil {
+		return fmt.Errorf("database not found: %s", dbPath)
+	}
+
+	db, err := database.Open(dbPath, &cfg.Database, backupDir)
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		db.Close()
+		return fmt.Errorf("creating migrator: %w", err)
+	}
+
+	if !restore {
+		defer db.Close()
+		result, err := migrator.UndoLast(ctx)
+		if err != nil {
+			return fmt.Errorf("undoing migrations: %w", err)
+		}
+		fmt.Printf("Rolled back %d migration(s), schema now at version %d\n",
+			len(result.Applied), result.TargetVersion)
+		return nil
+	}
+
+	versions, backupPath, err := migrator.LastBatch(ctx)
+	db.Close()
+	if err != nil {
+		return fmt.Errorf("finding last migration run: %w", err)
+	}
+	if backupPath == "" {
+		return fmt.Errorf("migration %d was applied without a backup, run undo-last without --restore-backup", versions[0])
+	}
+
+	preserved, err := database.RestoreBackup(dbPath, backupPath)
+	if err != nil {
+		return fmt.Errorf("restoring backup: %w", err)
+	}
+
+	fmt.Printf("Restored %s (undoing %d migration(s))\n", backupPath, len(versions))
+	fmt.Printf("Previous database kept at %s\n", preserved)
+	return nil
+}
+
+// runDBCommand handles `vtuos db <subcommand>`.
+func runDBCommand(ctx context.Context, configPath, profile string, args []string) error {
+	if len(args) == 0 || args[0] != "optimize" {
+		flag.Usage()
+		return fmt.Errorf("db requires a subcommand: optimize")
+	}
+	fs := flag.NewFlagSet("optimize", flag.ContinueOnError)
+	convert := fs.Bool("convert", false, "Rebuild a database created before incremental vacuum so that it allows it")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig(configPath, profile)
+	if err != nil {
+		return fmt.Errorf("loading configuration: %w", err)
+	}
+	if cfg.Database.ReadOnly {
+		return fmt.Errorf("db optimize cannot be used in read-only mode")
+	}
+	dbPath, err := config.EnsureDataDir(cfg)
+	if err != nil {
+		return fmt.Errorf("ensuring data directory: %w", err)
+	}
+
+	db, err := database.Open(dbPath, &cfg.Database, "")
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	result, err := db.Optimize(ctx, *convert)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Size:       %s -> %s\n", formatSize(result.Before.SizeBytes+result.Before.WALSizeBytes), formatSize(result.After.SizeBytes+result.After.WALSizeBytes))
+	fmt.Printf("Free pages: %d -> %d\n", result.Before.FreePageCount, result.After.FreePageCount)
+	fmt.Printf("Optimized in %s: %s\n", result.Duration.Round(time.Millisecond), result)
+	if result.Unvacuumed {
+		fmt.Println("Free pages were kept: the database predates incremental vacuum; run once with --convert")
+	}
+	return nil
+}
+
+// runInspectionsCommand handles `vtuos inspections <subcommand>`.
+func runInspectionsCommand(ctx context.Context, configPath, profile string, args []string) error {
+	if len(args) == 0 || args[0] != "overdue" {
+		flag.Usage()
+		return fmt.Errorf("inspections requires a subcommand: overdue")
+	}
+
+	cfg, err := loadConfig(configPath, profile)
+	if err != nil {
+		return fmt.Errorf("loading configuration: %w", err)
+	}
+
+	fs := flag.NewFlagSet("overdue", flag.ContinueOnError)
+	asOfStr := fs.String("as-of", "", "Vault date to report against (default: simulation start date)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	asOf, err := cfg.Simulation.StartDateTime()
+	if err != nil {
+		asOf = time.Now().UTC()
+	}
+	if *asOfStr != "" {
+		asOf, err = util.ParseDate(*asOfStr)
+		if err != nil {
+			return fmt.Errorf("invalid --as-of date: %s", *asOfStr)
+		}
+	}
+
+	dbPath, err := config.EnsureDataDir(cfg)
+	if err != nil {
+		return fmt.Errorf("ensuring data directory: %w", err)
+	}
+	backupDir, err := config.BackupDir(cfg)
+	if err != nil {
+		return fmt.Errorf("getting backup directory: %w", err)
+	}
+
+	db, err := database.Open(dbPath, &cfg.Database, backupDir)
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	svc := inspections.NewService(db.DB)
+	svc.SetClock(util.NewVaultClock(asOf, 0))
+
+	report, err := svc.OverdueReport(ctx)
+	if err != nil {
+		return fmt.Errorf("building overdue report: %w", err)
+	}
+
+	if len(report) == 0 {
+		fmt.Printf("No overdue inspections as of %s\n", util.FormatDate(asOf))
+		return nil
+	}
+
+	fmt.Printf("%d overdue inspection(s) as of %s\n", len(report), util.FormatDate(asOf))
+	for _, entry := range report {
+		fmt.Printf("  %-14s %-24s due %s  %3d day(s) overdue\n",
+			entry.TemplateCode,
+			entry.Inspection.Location,
+			util.FormatDate(entry.Inspection.ScheduledDate),
+			entry.DaysOverdue,
+		)
+	}
+
+	return nil
+}
+
+// runBadgesCommand handles `vtuos badges print-all`, which writes the ID
+// badge of every active resident for the badge printer, separated by form
+// feeds. The database is opened read-only.
+func runBadgesCommand(ctx context.Context, configPath, profile string, args []string) error {
+	if len(args) == 0 || args[0] != "print-all" {
+		flag.Usage()
+		return fmt.Errorf("badges requires a subcommand: print-all")
+	}
+
+	cfg, err := loadConfig(configPath, profile)
+	if err != nil {
+		return fmt.Errorf("loading configuration: %w", err)
+	}
+
+	fs := flag.NewFlagSet("print-all", flag.ContinueOnError)
+	outPath := fs.String("out", "", "File to write the badges to (default: stdout)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	dbPath, err := config.EnsureDataDir(cfg)
+	if err != nil {
+		return fmt.Errorf("ensuring data directory: %w", err)
+	}
+
+	cfg.Database.ReadOnly = true
+	db, err := database.Open(dbPath, &cfg.Database, "")
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		return fmt.Errorf("creating migrator: %w", err)
+	}
+	if err := checkSchemaCurrent(ctx, migrator); err != nil {
+		return err
+	}
+
+	badges, err := population.NewService(db.DB, cfg.Vault.Number).ActiveIDBadges(ctx)
+	if err != nil {
+		return fmt.Errorf("building badges: %w", err)
+	}
+
+	var out strings.Builder
+	for i, badge := range badges {
+		if i > 0 {
+			out.WriteString("\f")
+		}
+		out.WriteString(badge.Text())
+	}
+
+	if *outPath == "" {
+		fmt.Print(out.String())
+		return nil
+	}
+	if err := os.WriteFile(*outPath, []byte(out.String()), 0640); err != nil {
+		return fmt.Errorf("writing badges: %w", err)
+	}
+	fmt.Printf("Wrote %d badge(s) to %s\n", len(badges), *outPath)
+	return nil
+}
+
+// runReportCommand handles `vtuos report <subcommand>`.
+func runReportCommand(ctx context.Context, configPath, profile string, args []string) error {
+	if len(args) == 0 || (args[0] != "planning" && args[0] != "capacity") {
+		flag.Usage()
+		return fmt.Errorf("report requires a subcommand: planning or capacity")
+	}
+
+	cfg, err := loadConfig(configPath, profile)
+	if err != nil {
+		return fmt.Errorf("loading configuration: %w", err)
+	}
+
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	asOfStr := fs.String("as-of", "", "Vault date to plan from (default: simulation start date)")
+	years := fs.Int("years", governance.DefaultPlanningYears, "Planning horizon in years")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if *years < 1 {
+		return fmt.Errorf("--years must be at least 1")
+	}
+
+	asOf, err := cfg.Simulation.StartDateTime()
+	if err != nil {
+		asOf = time.Now().UTC()
+	}
+	if *asOfStr != "" {
+		asOf, err = util.ParseDate(*asOfStr)
+		if err != nil {
+			return fmt.Errorf("invalid --as-of date: %s", *asOfStr)
+		}
+	}
+
+	dbPath, err := config.EnsureDataDir(cfg)
+	if err != nil {
+		return fmt.Errorf("ensuring data directory: %w", err)
+	}
+	backupDir, err := config.BackupDir(cfg)
+	if err != nil {
+		return fmt.Errorf("getting backup directory: %w", err)
+	}
+
+	db, err := database.Open(dbPath, &cfg.Database, backupDir)
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	svc := governance.NewService(db.DB, cfg.Vault.Number)
+	if args[0] == "capacity" {
+		forecast, err := svc.CapacityForecast(ctx, asOf, *years, cfg.Vault.DesignedCapacity)
+		if err != nil {
+			return fmt.Errorf("forecasting capacity: %w", err)
+		}
+		printCapacityForecast(forecast)
+		return nil
+	}
+
+	report, err := svc.PlanningReport(ctx, asOf, *years)
+	if err != nil {
+		return fmt.Errorf("building planning report: %w", err)
+	}
+
+	printPlanningReport(report)
+	return nil
+}
+
+// printPlanningReport writes the planning report to stdout.
+func printPlanningReport(report *governance.PlanningReport) {
+	pop := report.Population
+	fmt.Printf("Planning report as of %s (%d-year horizon)\n\n", util.FormatDate(report.AsOf), report.Years)
+
+	fmt.Printf("POPULATION\n")
+	fmt.Printf("  Current %d, projected %d in %d (%.2f%%/yr)\n",
+		pop.CurrentPopulation, report.FinalPopulation(), report.AsOf.Year()+report.Years, pop.GrowthRate)
+	for _, concern := range pop.Viability.Concerns {
+		fmt.Printf("  - %s\n", concern)
+	}
+
+	wf := report.Workforce
+	fmt.Printf("\nWORKFORCE\n")
+	fmt.Printf("  Working age %d (training %d), retired %d, dependency ratio %.2f\n",
+		wf.WorkingAge, wf.TrainingAge, wf.RetirementAge, wf.DependencyRatio)
+
+	staffing := report.Staffing
+	fmt.Printf("\nSTAFFING GAPS\n")
+	if len(staffing.Gaps) == 0 {
+		fmt.Printf("  All vocations staffed through %d\n", report.AsOf.Year()+report.Years)
+	}
+	for _, gap := range staffing.Gaps {
+		fmt.Printf("  %-13s %s\n", gap.VocationCode, gap)
+	}
+
+	if len(staffing.Recommendations) > 0 {
+		fmt.Printf("\nTRAINING PIPELINE\n")
+	}
+	for _, rec := range staffing.Recommendations {
+		start := fmt.Sprintf("start %d", rec.StartYear)
+		if rec.Late {
+			start = "start NOW (late)"
+		}
+		fmt.Printf("  %-13s %-16s %2d trainee(s), %d-year program, %d candidate(s)\n",
+			rec.VocationCode, start, rec.Trainees, rec.TrainingYears, rec.Candidates)
+	}
+}
+
+// printCapacityForecast writes the capacity forecast to stdout.
+func printCapacityForecast(forecast *governance.CapacityForecast) {
+	pop := forecast.Forecast
+	fmt.Printf("Capacity forecast as of %s\n\n", util.FormatDate(pop.AsOf))
+
+	rates := pop.Rates
+	source := "defaults, no births or deaths on record"
+	if rates.Historical {
+		source = fmt.Sprintf("%d birth(s) and %d death(s) in the last %d years",
+			rates.Births, rates.Deaths, population.ForecastLookbackYears)
+	}
+	fmt.Printf("Rates: %.3f births per woman 15-44 per year (%s)\n", rates.FertilityRate, source)
+	fmt.Printf("Designed capacity %d, rations %.0f kcal/resident/day, production %.0f kcal/day\n\n",
+		forecast.DesignedCapacity, forecast.CaloriesPerResident, forecast.ProductionCalories)
+
+	fmt.Printf("%-6s %6s %6s %6s %8s %8s %7s %9s %6s\n",
+		"YEAR", "POP", "BIRTHS", "DEATHS", "CHILDREN", "WORKING", "SENIORS", "CAPACITY", "FOOD")
+	fmt.Printf("%-6s %6d\n", "now", pop.Current)
+	for _, y := range forecast.Years {
+		fmt.Printf("%-6d %6d %6d %6d %8d %8d %7d %8.0f%% %5.0f%%\n",
+			y.Year, y.Population, y.Births, y.Deaths, y.Children, y.WorkingAge, y.Seniors,
+			y.CapacityPct, y.FoodCoverage*100)
+	}
+
+	fmt.Println()
+	if forecast.OverCapacityYear != 0 {
+		fmt.Printf("Exceeds designed capacity in %d\n", forecast.OverCapacityYear)
+	}
+	if forecast.ShortfallYear != 0 {
+		fmt.Printf("Food production falls short of rations in %d\n", forecast.ShortfallYear)
+	}
+	if forecast.OverCapacityYear == 0 && forecast.ShortfallYear == 0 {
+		fmt.Printf("Within designed capacity and food production through the horizon\n")
+	}
+}
+
+// runGRPCServeCommand handles `vtuos grpc-serve`. Flags override the [grpc]
+// configuration. The database is opened read-only, so the server can run
+// beside the TUI.
+func runGRPCServeCommand(ctx context.Context, configPath, profile string, args []string) error {
+	cfg, err := loadConfig(configPath, profile)
+	if err != nil {
+		return fmt.Errorf("loading configuration: %w", err)
+	}
+
+	fs := flag.NewFlagSet("grpc-serve", flag.ContinueOnError)
+	listen := fs.String("listen", cfg.GRPC.Listen, "Address to listen on (host:port)")
+	tlsCert := fs.String("tls-cert", cfg.GRPC.TLSCertFile, "Server certificate (PEM)")
+	tlsKey := fs.String("tls-key", cfg.GRPC.TLSKeyFile, "Server private key (PEM)")
+	clientCA := fs.String("client-ca", cfg.GRPC.ClientCAFile, "Require client certificates signed by this CA (PEM)")
+	allowInsecure := fs.Bool("insecure", cfg.GRPC.Insecure, "Serve without TLS (loopback testing only)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	cfg.GRPC.Listen = *listen
+	cfg.GRPC.TLSCertFile = *tlsCert
+	cfg.GRPC.TLSKeyFile = *tlsKey
+	cfg.GRPC.ClientCAFile = *clientCA
+	cfg.GRPC.Insecure = *allowInsecure
+	if err := cfg.GRPC.Validate(); err != nil {
+		return fmt.Errorf("grpc configuration: %w", err)
+	}
+
+	dbPath, err := config.EnsureDataDir(cfg)
+	if err != nil {
+		return fmt.Errorf("ensuring data directory: %w", err)
+	}
+
+	cfg.Database.ReadOnly = true
+	db, err := database.Open(dbPath, &cfg.Database, "")
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		return fmt.Errorf("creating migrator: %w", err)
+	}
+	if err := checkSchemaCurrent(ctx, migrator); err != nil {
+		return err
+	}
+
+	return exchange.Serve(ctx, cfg.GRPC, db.DB, cfg.Vault.Number)
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/vtuos/vtuos/internal/config"
+)
+
+// runConfigCommand handles `vtuos config validate`.
+func runConfigCommand(configPath, profile string, args []string) error {
+	if len(args) == 0 || args[0] != "validate" {
+		flag.Usage()
+		return fmt.Errorf("config requires a subcommand: validate")
+	}
+
+	fs := flag.NewFlagSet("config validate", flag.ContinueOnError)
+	quiet := fs.Bool("quiet", false, "Only report problems, not the effective configuration")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	path := config.ConfigPath(configPath)
+	cfg, _, err := config.Load(configPath, false)
+	if err != nil {
+		var loadErr *config.LoadError
+		if errors.As(err, &loadErr) {
+			err = loadErr.Err
+		}
+		problems := config.Problems(err)
+		printProblems(os.Stderr, path, problems)
+		return fmt.Errorf("%s is not valid: %d problem(s)", path, len(problems))
+	}
+	if err := cfg.UseProfile(profile); err != nil {
+		printProblems(os.Stderr, path, []error{err})
+		return fmt.Errorf("%s is not valid: 1 problem(s)", path)
+	}
+	if *quiet {
+		fmt.Printf("%s is valid\n", path)
+		return nil
+	}
+
+	// Print the configuration in force, defaults and all, secrets masked
+	cfg.Normalize()
+	fmt.Printf("# %s is valid. Effective configuration, with defaults filled in\n", path)
+	fmt.Printf("# and secrets masked:\n")
+	if name, p := cfg.Profile(); name != "" {
+		fmt.Printf("# run in profile %s: data_dir %q, database %q, seed_profile %q\n", name, p.DataDir, p.Database, p.SeedProfile)
+	}
+	fmt.Println()
+	return config.Encode(os.Stdout, cfg.WithoutSecrets())
+}
+
+// printConfigError prints a configuration that failed to load one problem a
+// line, with how to check it again, and reports whether err was one.
+func printConfigError(w io.Writer, err error) bool {
+	var loadErr *config.LoadError
+	if !errors.As(err, &loadErr) {
+		return false
+	}
+	printProblems(w, loadErr.Path, config.Problems(loadErr.Err))
+	fmt.Fprintf(w, "Fix them and check with: vtuos --config %s config validate\n", loadErr.Path)
+	return true
+}
+
+// printProblems prints the problems found in a configuration file.
+func printProblems(w io.Writer, path string, problems []error) {
+	fmt.Fprintf(w, "Configuration %s has %d problem(s):\n", path, len(problems))
+	for _, p := range problems {
+		fmt.Fprintf(w, "  - %v\n", p)
+	}
+}
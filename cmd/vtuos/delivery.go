@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/config"
+	"github.com/vtuos/vtuos/internal/database"
+	"github.com/vtuos/vtuos/internal/services/population"
+	"github.com/vtuos/vtuos/internal/services/resources"
+)
+
+// runDeliveryCommand handles `vtuos delivery import`: receive a supply
+// delivery from a CSV of item codes, quantities, lots and expiration dates,
+// and print the reconciliation report of what did not match the books.
+func runDeliveryCommand(ctx context.Context, configPath, profile string, args []string) error {
+	if len(args) == 0 || args[0] != "import" {
+		flag.Usage()
+		if len(args) > 0 {
+			return fmt.Errorf("unknown delivery subcommand: %s", args[0])
+		}
+		return fmt.Errorf("delivery requires a subcommand: import")
+	}
+
+	fs := flag.NewFlagSet("delivery import", flag.ContinueOnError)
+	location := fs.String("location", "", "Storage location of lines without a location column")
+	reference := fs.String("reference", "", "Manifest or delivery number, recorded with each receipt")
+	receivedOn := fs.String("received", "", "Date the delivery was received, YYYY-MM-DD (default: today)")
+	createItems := fs.Bool("create-items", false, "Add unknown item codes to the catalog instead of reporting them")
+	category := fs.String("category", "", "Category code of the items --create-items adds")
+	by := fs.String("by", "", "Registry number of the receiving operator")
+	outPath := fs.String("out", "", "File to write the reconciliation report to (default: stdout)")
+	file, err := parseWithName(fs, args[1:])
+	if err != nil {
+		return err
+	}
+	if file == "" {
+		return fmt.Errorf("delivery import requires a CSV file")
+	}
+	if *createItems && *category == "" {
+		return fmt.Errorf("--create-items needs --category")
+	}
+
+	opts := resources.DeliveryOptions{
+		Reference:       *reference,
+		StorageLocation: *location,
+		CreateItems:     *createItems,
+		CategoryCode:    *category,
+	}
+	if *receivedOn != "" {
+		if opts.ReceivedDate, err = time.Parse(time.DateOnly, *receivedOn); err != nil {
+			return fmt.Errorf("invalid --received: %w", err)
+		}
+	}
+
+	cfg, err := loadConfig(configPath, profile)
+	if err != nil {
+		return fmt.Errorf("loading configuration: %w", err)
+	}
+	dbPath, err := config.EnsureDataDir(cfg)
+	if err != nil {
+		return fmt.Errorf("ensuring data directory: %w", err)
+	}
+	backupDir, err := config.BackupDir(cfg)
+	if err != nil {
+		return fmt.Errorf("getting backup directory: %w", err)
+	}
+	db, err := database.Open(dbPath, &cfg.Database, backupDir)
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	j, err := openJournal(cfg, nil)
+	if err != nil {
+		return err
+	}
+	defer j.Close()
+
+	svc := resources.NewService(db.DB)
+	svc.SetVault(cfg.Vault.Number)
+	svc.SetJournal(j)
+
+	if *by != "" {
+		operator, err := population.NewService(db.DB, cfg.Vault.Number).GetResidentByRegistryNumber(ctx, *by)
+		if err != nil {
+			return fmt.Errorf("operator %s: %w", *by, err)
+		}
+		opts.AuthorizedBy = &operator.ID
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return fmt.Errorf("opening delivery file: %w", err)
+	}
+	defer f.Close()
+
+	rec, err := svc.ImportDelivery(ctx, f, opts)
+	if err != nil {
+		return fmt.Errorf("importing delivery: %w", err)
+	}
+
+	report := rec.ReportText()
+	if *outPath == "" {
+		fmt.Print(report)
+	} else {
+		if err := os.WriteFile(*outPath, []byte(report), 0640); err != nil {
+			return fmt.Errorf("writing report: %w", err)
+		}
+		s := rec.Summary()
+		fmt.Printf("Received %d of %d line(s); wrote the reconciliation report to %s\n", s.Stocked+s.Extended, s.Lines, *outPath)
+	}
+	if n := len(rec.Mismatches()); n > 0 {
+		return fmt.Errorf("%d line(s) not received; reconcile them with the supplier", n)
+	}
+	return nil
+}
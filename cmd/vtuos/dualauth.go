@@ -0,0 +1,186 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+
+	"github.com/vtuos/vtuos/internal/config"
+	"github.com/vtuos/vtuos/internal/database"
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/services/governance"
+)
+
+// runDualAuthCommand handles `vtuos dualauth <subcommand>`: list, set and
+// remove the policies holding operations for a second operator's
+// confirmation, and set the PIN an operator confirms them with.
+func runDualAuthCommand(ctx context.Context, configPath, profile string, args []string) error {
+	if len(args) == 0 {
+		flag.Usage()
+		return fmt.Errorf("dualauth requires a subcommand: policy or pin")
+	}
+
+	cfg, err := loadConfig(configPath, profile)
+	if err != nil {
+		return fmt.Errorf("loading configuration: %w", err)
+	}
+
+	dbPath, err := config.EnsureDataDir(cfg)
+	if err != nil {
+		return fmt.Errorf("ensuring data directory: %w", err)
+	}
+	backupDir, err := config.BackupDir(cfg)
+	if err != nil {
+		return fmt.Errorf("getting backup directory: %w", err)
+	}
+
+	db, err := database.Open(dbPath, &cfg.Database, backupDir)
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	j, err := openJournal(cfg, nil)
+	if err != nil {
+		return err
+	}
+	defer j.Close()
+
+	svc := governance.NewService(db.DB, cfg.Vault.Number)
+	svc.SetJournal(j)
+
+	switch args[0] {
+	case "policy":
+		return runDualAuthPolicy(ctx, svc, args[1:])
+	case "pin":
+		if len(args) != 2 {
+			return fmt.Errorf("dualauth pin requires the operator's registry number")
+		}
+		pin, err := readPIN()
+		if err != nil {
+			return err
+		}
+		if err := svc.SetOperatorPIN(ctx, args[1], pin); err != nil {
+			return err
+		}
+		fmt.Printf("PIN set for %s\n", args[1])
+		return nil
+	default:
+		return fmt.Errorf("unknown dualauth subcommand: %s", args[0])
+	}
+}
+
+// runDualAuthPolicy handles `vtuos dualauth policy list|set|remove`.
+func runDualAuthPolicy(ctx context.Context, svc *governance.Service, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("dualauth policy requires a subcommand: list, set or remove")
+	}
+	switch args[0] {
+	case "list":
+		policies, err := svc.ListDualAuthPolicies(ctx)
+		if err != nil {
+			return fmt.Errorf("listing policies: %w", err)
+		}
+		if len(policies) == 0 {
+			fmt.Println("No operation needs a second operator")
+			return nil
+		}
+		fmt.Printf("%-20s %9s  %s\n", "OPERATION", "CLEARANCE", "APPLIES")
+		for _, p := range policies {
+			applies := "always"
+			if p.Threshold > 0 {
+				applies = "from " + strconv.FormatFloat(p.Threshold, 'f', -1, 64) + " units"
+			}
+			fmt.Printf("%-20s %9d  %s\n", p.Operation, p.MinClearance, applies)
+		}
+		return nil
+	case "set":
+		fs := flag.NewFlagSet("policy set", flag.ContinueOnError)
+		clearance := fs.Int("clearance", 1, "Clearance both operators must hold, 1-10")
+		threshold := fs.Float64("threshold", 0, "Units written off at once from which a STOCK_WRITE_OFF is held (0 for every write-off)")
+		name, err := parseWithName(fs, args[1:])
+		if err != nil {
+			return err
+		}
+		if name == "" {
+			return fmt.Errorf("dualauth policy set requires an operation: exile, dissolve_household or stock_write_off")
+		}
+		op, err := models.ParseDualAuthOperation(name)
+		if err != nil {
+			return err
+		}
+		policy, err := svc.SetDualAuthPolicy(ctx, governance.DualAuthPolicyInput{
+			Operation:    op,
+			MinClearance: *clearance,
+			Threshold:    *threshold,
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s now needs a second operator of clearance %d or above", policy.Operation, policy.MinClearance)
+		if policy.Threshold > 0 {
+			fmt.Printf(" from %s units", strconv.FormatFloat(policy.Threshold, 'f', -1, 64))
+		}
+		fmt.Println()
+		return nil
+	case "remove":
+		if len(args) != 2 {
+			return fmt.Errorf("dualauth policy remove requires an operation")
+		}
+		op, err := models.ParseDualAuthOperation(args[1])
+		if err != nil {
+			return err
+		}
+		if err := svc.RemoveDualAuthPolicy(ctx, op); err != nil {
+			return err
+		}
+		fmt.Printf("%s no longer needs a second operator\n", op)
+		return nil
+	default:
+		return fmt.Errorf("unknown dualauth policy subcommand: %s", args[0])
+	}
+}
+
+// readPIN reads a new PIN, twice and without echo at a terminal, or once
+// from a line of standard input otherwise.
+func readPIN() (string, error) {
+	pin, err := readSecret("New PIN: ")
+	if err != nil || !term.IsTerminal(int(os.Stdin.Fd())) {
+		return pin, err
+	}
+	again, err := readSecret("Repeat PIN: ")
+	if err != nil {
+		return "", err
+	}
+	if pin != again {
+		return "", fmt.Errorf("the PINs do not match")
+	}
+	return pin, nil
+}
+
+// readSecret reads a PIN without echo at a terminal, prompting on standard
+// error, or from a line of standard input otherwise.
+func readSecret(prompt string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return "", fmt.Errorf("reading PIN: %w", err)
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+
+	fmt.Fprint(os.Stderr, prompt)
+	secret, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("reading PIN: %w", err)
+	}
+	return string(secret), nil
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/vtuos/vtuos/internal/config"
+	"github.com/vtuos/vtuos/internal/database"
+	"github.com/vtuos/vtuos/internal/vaultcrypt"
+)
+
+// runEncryptionCommand handles `vtuos encryption <subcommand>`: generate a
+// master key, show how much of the database is encrypted, rotate the data
+// key, rewrap the data keys under a new master key, or decrypt everything
+// before disabling encryption.
+func runEncryptionCommand(ctx context.Context, configPath, profile string, args []string) error {
+	if len(args) == 0 {
+		flag.Usage()
+		return fmt.Errorf("encryption requires a subcommand: keygen, status, rotate, rewrap or decrypt")
+	}
+	if args[0] == "keygen" {
+		key, err := vaultcrypt.NewKeyString()
+		if err != nil {
+			return err
+		}
+		fmt.Println(key)
+		return nil
+	}
+
+	cfg, err := loadConfig(configPath, profile)
+	if err != nil {
+		return fmt.Errorf("loading configuration: %w", err)
+	}
+	if args[0] != "status" {
+		if cfg.Database.ReadOnly {
+			return fmt.Errorf("encryption %s cannot be used in read-only mode", args[0])
+		}
+		if !cfg.Database.Encryption.Enabled {
+			return fmt.Errorf("encryption %s needs database.encryption enabled", args[0])
+		}
+	}
+
+	var oldKey config.EncryptionConfig
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	if args[0] == "rewrap" {
+		fs.StringVar(&oldKey.KeyEnv, "old-key-env", "", "Environment variable holding the master key being replaced")
+		fs.StringVar(&oldKey.KeyFile, "old-key-file", "", "File holding the master key being replaced")
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	// Rewrapping opens the data keys with the master key being replaced
+	dbCfg := cfg.Database
+	if args[0] == "rewrap" {
+		if oldKey.KeyEnv == "" && oldKey.KeyFile == "" {
+			return fmt.Errorf("encryption rewrap requires --old-key-env or --old-key-file")
+		}
+		oldKey.Enabled = true
+		dbCfg.Encryption = oldKey
+	}
+
+	dbPath, err := config.EnsureDataDir(cfg)
+	if err != nil {
+		return fmt.Errorf("ensuring data directory: %w", err)
+	}
+	db, err := database.Open(dbPath, &dbCfg, "")
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	switch args[0] {
+	case "status":
+		status, err := db.EncryptionStatus(ctx)
+		if err != nil {
+			return err
+		}
+		printEncryptionStatus(cfg, status)
+		return nil
+	case "rotate":
+		id, n, err := db.RotateEncryptionKey(ctx)
+		if err != nil {
+			return fmt.Errorf("rotating data key: %w", err)
+		}
+		fmt.Printf("Data key %s is now primary; %d value(s) re-encrypted and replaced keys deleted\n", id, n)
+		return nil
+	case "rewrap":
+		master, err := cfg.Database.Encryption.MasterKey()
+		if err != nil {
+			return fmt.Errorf("new master key: %w", err)
+		}
+		n, err := db.RewrapEncryptionKeys(ctx, master)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%d data key(s) now wrapped by the configured master key\n", n)
+		return nil
+	case "decrypt":
+		n, err := db.DecryptColumns(ctx)
+		if err != nil {
+			return fmt.Errorf("decrypting: %w", err)
+		}
+		fmt.Printf("%d value(s) decrypted and the data keys deleted; disable database.encryption now\n", n)
+		fmt.Println("Backups and snapshots taken before stay encrypted and still need the master key")
+		return nil
+	default:
+		return fmt.Errorf("unknown encryption subcommand: %s", args[0])
+	}
+}
+
+// printEncryptionStatus prints the data keys and the encrypted and
+// plaintext values of each encrypted column.
+func printEncryptionStatus(cfg *config.Config, status *database.EncryptionStatus) {
+	state := "disabled"
+	if cfg.Database.Encryption.Enabled {
+		state = "enabled"
+	}
+	fmt.Printf("Encryption %s, %d data key(s)\n\n", state, len(status.Keys))
+	for _, k := range status.Keys {
+		primary := ""
+		if k.Primary {
+			primary = "primary"
+		}
+		fmt.Printf("  %-14s %-8s %-26s %6d value(s)\n", k.ID, primary, k.CreatedAt, k.Values)
+	}
+
+	columns := append([]string(nil), database.EncryptedColumns...)
+	sort.Strings(columns)
+	fmt.Printf("\n%-40s %9s %9s\n", "Column", "Encrypted", "Plaintext")
+	for _, column := range columns {
+		fmt.Printf("%-40s %9d %9d\n", column, status.Encrypted[column], status.Plaintext[column])
+	}
+}
+
+// encryptPlaintext encrypts the values of the encrypted columns written in
+// plaintext, such as by seeding or before encryption was enabled.
+func encryptPlaintext(ctx context.Context, db *database.DB, cfg *config.Config) error {
+	if !cfg.Database.Encryption.Enabled || cfg.Database.ReadOnly {
+		return nil
+	}
+	n, err := db.EncryptColumns(ctx)
+	if err != nil {
+		return fmt.Errorf("encrypting sensitive columns: %w", err)
+	}
+	if n > 0 {
+		slog.Info("encrypted sensitive values", "count", n)
+	}
+	return nil
+}
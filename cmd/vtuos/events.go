@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/vtuos/vtuos/internal/config"
+	"github.com/vtuos/vtuos/internal/events"
+)
+
+// openEvents starts the bus forwarding the terminal's events to the
+// configured sinks. It returns nil, publishing nothing, when no sinks are
+// configured or the database is read-only, as a kiosk changes nothing.
+func openEvents(cfg *config.Config) (*events.Bus, error) {
+	if len(cfg.Events.Sinks) == 0 || cfg.Database.ReadOnly {
+		return nil, nil
+	}
+	var routes []events.Route
+	for i, s := range cfg.Events.Sinks {
+		var sink events.Sink
+		switch s.Type {
+		case config.EventSinkFile:
+			file, err := events.NewFileSink(s.Path)
+			if err != nil {
+				for _, r := range routes {
+					r.Sink.Close()
+				}
+				return nil, fmt.Errorf("events.sinks[%d]: %w", i, err)
+			}
+			sink = file
+		case config.EventSinkSocket:
+			sink = events.NewSocketSink(s.Path, s.Timeout())
+		case config.EventSinkWebhook:
+			sink = events.NewWebhookSink(s.URL, s.Secret, s.Timeout())
+		}
+		routes = append(routes, events.Route{Sink: sink, Types: s.Types})
+		slog.Info("forwarding events", "sink", sink.String(), "types", s.Types)
+	}
+	buffer := cfg.Events.Buffer
+	if buffer == 0 {
+		buffer = events.DefaultBuffer
+	}
+	return events.NewBus(buffer, routes...), nil
+}
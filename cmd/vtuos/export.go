@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/config"
+	"github.com/vtuos/vtuos/internal/database"
+	"github.com/vtuos/vtuos/internal/services/governance"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// exportManifest describes an HQ export, written beside its CSV files as
+// manifest.json. It records the policy, but never the pseudonym key.
+type exportManifest struct {
+	VaultNumber   int            `json:"vault_number"`
+	CreatedAt     time.Time      `json:"created_at"`
+	AsOf          string         `json:"as_of"`
+	Mode          string         `json:"mode"`
+	MinGroupSize  int            `json:"min_group_size"`
+	AgeBandYears  int            `json:"age_band_years"`
+	DatePrecision string         `json:"date_precision"`
+	Rows          map[string]int `json:"rows"`
+}
+
+// runExportCommand handles `vtuos export hq`. Flags override the [export]
+// anonymization policy. The database is opened read-only.
+func runExportCommand(ctx context.Context, configPath, profile string, args []string) error {
+	if len(args) == 0 || args[0] != "hq" {
+		flag.Usage()
+		return fmt.Errorf("export requires a subcommand: hq")
+	}
+
+	cfg, err := loadConfig(configPath, profile)
+	if err != nil {
+		return fmt.Errorf("loading configuration: %w", err)
+	}
+
+	fs := flag.NewFlagSet("hq", flag.ContinueOnError)
+	mode := fs.String("mode", string(cfg.Export.Mode), "aggregate or pseudonymized")
+	datasets := fs.String("datasets", strings.Join(cfg.Export.Datasets, ","), "Comma-separated datasets: demographics, consumption, maintenance")
+	outDir := fs.String("out", "", "Directory to write the export to (default: hq-export-<vault>-<date>)")
+	asOfStr := fs.String("as-of", "", "Vault date to take ages at (default: simulation start date)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	cfg.Export.Mode = config.ExportMode(*mode)
+	cfg.Export.Datasets = nil
+	for _, name := range strings.Split(*datasets, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			cfg.Export.Datasets = append(cfg.Export.Datasets, name)
+		}
+	}
+	if len(cfg.Export.Datasets) == 0 {
+		cfg.Export.Datasets = governance.HQDatasets
+	}
+	if err := cfg.Export.Validate(); err != nil {
+		return fmt.Errorf("export policy: %w", err)
+	}
+
+	asOf, err := cfg.Simulation.StartDateTime()
+	if err != nil {
+		asOf = time.Now().UTC()
+	}
+	if *asOfStr != "" {
+		asOf, err = util.ParseDate(*asOfStr)
+		if err != nil {
+			return fmt.Errorf("invalid --as-of date: %s", *asOfStr)
+		}
+	}
+	if *outDir == "" {
+		*outDir = fmt.Sprintf("hq-export-%03d-%s", cfg.Vault.Number, asOf.Format("20060102"))
+	}
+
+	dbPath, err := config.EnsureDataDir(cfg)
+	if err != nil {
+		return fmt.Errorf("ensuring data directory: %w", err)
+	}
+
+	cfg.Database.ReadOnly = true
+	db, err := database.Open(dbPath, &cfg.Database, "")
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		return fmt.Errorf("creating migrator: %w", err)
+	}
+	if err := checkSchemaCurrent(ctx, migrator); err != nil {
+		return err
+	}
+
+	policy := governance.ExportPolicy{
+		Pseudonymize:  cfg.Export.Mode == config.ExportModePseudonymized,
+		PseudonymKey:  []byte(cfg.Export.PseudonymKey),
+		MinGroupSize:  cfg.Export.MinGroupSize,
+		AgeBandYears:  cfg.Export.AgeBandYears,
+		DatePrecision: cfg.Export.DatePrecision,
+	}
+	svc := governance.NewService(db.DB, cfg.Vault.Number)
+	export, err := svc.HQExport(ctx, asOf, policy, cfg.Export.Datasets)
+	if err != nil {
+		return fmt.Errorf("building export: %w", err)
+	}
+
+	if err := os.MkdirAll(*outDir, 0750); err != nil {
+		return fmt.Errorf("creating export directory: %w", err)
+	}
+	manifest := exportManifest{
+		VaultNumber:   cfg.Vault.Number,
+		CreatedAt:     time.Now().UTC(),
+		AsOf:          asOf.Format(time.DateOnly),
+		Mode:          string(cfg.Export.Mode),
+		MinGroupSize:  policy.MinGroupSize,
+		AgeBandYears:  policy.AgeBandYears,
+		DatePrecision: policy.DatePrecision,
+		Rows:          make(map[string]int),
+	}
+	for _, ds := range export {
+		path := filepath.Join(*outDir, ds.Name+".csv")
+		if err := writeDatasetCSV(path, ds); err != nil {
+			return err
+		}
+		manifest.Rows[ds.Name] = len(ds.Rows)
+		fmt.Printf("  %-14s %6d row(s)  %s\n", ds.Name, len(ds.Rows), path)
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding manifest: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(*outDir, "manifest.json"), data, 0640); err != nil {
+		return fmt.Errorf("writing manifest: %w", err)
+	}
+
+	fmt.Printf("Wrote %s HQ export as of %s to %s\n", cfg.Export.Mode, util.FormatDate(asOf), *outDir)
+	return nil
+}
+
+// writeDatasetCSV writes a dataset as CSV with a header row.
+func writeDatasetCSV(path string, ds *governance.Dataset) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0640)
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", path, err)
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	if err := w.Write(ds.Columns); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	if err := w.WriteAll(ds.Rows); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return f.Close()
+}
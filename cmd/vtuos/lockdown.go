@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/vtuos/vtuos/internal/config"
+	"github.com/vtuos/vtuos/internal/database"
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/services/governance"
+	"github.com/vtuos/vtuos/internal/services/population"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// runLockdownCommand handles `vtuos lockdown <subcommand>`: show the vault's
+// alert state, change it on the authority of a resident given by registry
+// number, and list recent changes.
+func runLockdownCommand(ctx context.Context, configPath, profile string, args []string) error {
+	if len(args) == 0 {
+		flag.Usage()
+		return fmt.Errorf("lockdown requires a subcommand: status, set or history")
+	}
+
+	cfg, err := loadConfig(configPath, profile)
+	if err != nil {
+		return fmt.Errorf("loading configuration: %w", err)
+	}
+
+	dbPath, err := config.EnsureDataDir(cfg)
+	if err != nil {
+		return fmt.Errorf("ensuring data directory: %w", err)
+	}
+	backupDir, err := config.BackupDir(cfg)
+	if err != nil {
+		return fmt.Errorf("getting backup directory: %w", err)
+	}
+
+	db, err := database.Open(dbPath, &cfg.Database, backupDir)
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	j, err := openJournal(cfg, nil)
+	if err != nil {
+		return err
+	}
+	defer j.Close()
+
+	svc := governance.NewService(db.DB, cfg.Vault.Number)
+	svc.SetJournal(j)
+
+	switch args[0] {
+	case "status":
+		current, err := svc.GetLockdownState(ctx)
+		if err != nil {
+			return fmt.Errorf("getting vault state: %w", err)
+		}
+		fmt.Printf("Vault state: %s\n", current.State)
+		if current.ID != "" {
+			fmt.Printf("Since %s, authorized by %s\n", util.FormatDateTime(current.ChangedAt), authorizerLabel(current))
+			if current.Reason != "" {
+				fmt.Printf("Reason: %s\n", current.Reason)
+			}
+		}
+		if required := current.State.ModuleClearance(); required > 0 {
+			fmt.Printf("Terminal modules restricted to clearance %d\n", required)
+		}
+		if current.State.PausesNonEssential() {
+			fmt.Println("Non-essential scheduled tasks paused")
+		}
+		return nil
+	case "set":
+		fs := flag.NewFlagSet("set", flag.ContinueOnError)
+		reason := fs.String("reason", "", "Why the state is changing")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if fs.NArg() != 2 {
+			return fmt.Errorf("lockdown set requires a state and the authorizing registry number")
+		}
+		state, err := models.ParseLockdownState(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		operator, err := population.NewService(db.DB, cfg.Vault.Number).GetResidentByRegistryNumber(ctx, fs.Arg(1))
+		if err != nil {
+			return fmt.Errorf("operator %s: %w", fs.Arg(1), err)
+		}
+
+		change, err := svc.SetLockdownState(ctx, governance.LockdownInput{
+			State:        state,
+			AuthorizedBy: operator.ID,
+			Reason:       *reason,
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Vault state %s -> %s, authorized by %s %s\n",
+			change.PreviousState, change.State, operator.RegistryNumber, operator.FullName())
+		return nil
+	case "history":
+		changes, err := svc.ListLockdownChanges(ctx, 20)
+		if err != nil {
+			return fmt.Errorf("listing vault state changes: %w", err)
+		}
+		if len(changes) == 0 {
+			fmt.Println("The vault state has never changed")
+			return nil
+		}
+		for _, c := range changes {
+			fmt.Printf("%s  %-9s -> %-9s  %-28s %s\n", util.FormatDateTime(c.ChangedAt),
+				c.PreviousState, c.State, authorizerLabel(c), c.Reason)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown lockdown subcommand: %s", args[0])
+	}
+}
+
+// authorizerLabel returns "REGISTRY Name" of a change's authorizer.
+func authorizerLabel(c *models.LockdownChange) string {
+	if c.Authorizer == nil {
+		return "unknown"
+	}
+	return c.Authorizer.RegistryNumber + " " + c.Authorizer.FullName()
+}
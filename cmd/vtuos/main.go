@@ -6,17 +6,25 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log/slog"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/vtuos/vtuos/internal/config"
 	"github.com/vtuos/vtuos/internal/database"
 	"github.com/vtuos/vtuos/internal/database/seed"
+	"github.com/vtuos/vtuos/internal/journal"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/services/inspections"
+	"github.com/vtuos/vtuos/internal/services/population"
+	"github.com/vtuos/vtuos/internal/simulation"
+	"github.com/vtuos/vtuos/internal/training"
 	"github.com/vtuos/vtuos/internal/tui"
 	"github.com/vtuos/vtuos/internal/util"
 )
@@ -33,9 +41,19 @@ func main() {
 		configPath  = flag.String("config", "", "Path to configuration file")
 		migrateOnly = flag.Bool("migrate-only", false, "Run migrations and exit")
 		seedData    = flag.Bool("seed", false, "Generate seed data")
+		seedProfile = flag.String("seed-profile", "", "Seed generation profile: "+strings.Join(seed.ProfileNames(), ", ")+" (implies -seed)")
+		importPath  = flag.String("import-residents", "", "Import residents from a CSV manifest and exit")
 		showVersion = flag.Bool("version", false, "Show version and exit")
 		debugMode   = flag.Bool("debug", false, "Enable debug logging")
+		skipBackup  = flag.Bool("skip-migration-backup", false, "Do not back up the database before applying migrations")
+		allowDrift  = flag.Bool("allow-drift", false, "Warn instead of failing when an applied migration's file has changed")
+		readOnly    = flag.Bool("read-only", false, "Open the database read-only and disable all changes (kiosk mode)")
+		scriptPath  = flag.String("script", "", "Run a scenario script of timed simulation events (overrides simulation.script)")
+		trainPath   = flag.String("train", "", "Run a training exercise of a scenario with objectives, scored in a debrief (needs a profile with its own database)")
+		journalPath = flag.String("journal", "", "Record every command to a journal for replay (overrides simulation.journal)")
+		profileName = flag.String("profile", os.Getenv("VTUOS_PROFILE"), "Run in a named environment of [profiles], e.g. training (default $VTUOS_PROFILE)")
 	)
+	flag.Usage = usage
 	flag.Parse()
 
 	// Show version
@@ -64,23 +82,119 @@ func main() {
 		})
 	}()
 
+	// Run a subcommand if one was given
+	if flag.NArg() > 0 {
+		if err := runCommand(ctx, *configPath, *profileName, flag.Args()); err != nil {
+			if !printConfigError(os.Stderr, err) {
+				fmt.Fprintln(os.Stderr, "error:", err)
+			}
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Run the application
-	if err := run(ctx, *configPath, *migrateOnly, *seedData, *debugMode); err != nil {
-		slog.Error("application error", "error", err)
+	opts := runOptions{
+		configPath:  *configPath,
+		migrateOnly: *migrateOnly,
+		seedData:    *seedData || *seedProfile != "",
+		seedProfile: *seedProfile,
+		debugMode:   *debugMode,
+		importPath:  *importPath,
+		skipBackup:  *skipBackup,
+		allowDrift:  *allowDrift,
+		readOnly:    *readOnly,
+		scriptPath:  *scriptPath,
+		trainPath:   *trainPath,
+		journalPath: *journalPath,
+		profile:     *profileName,
+	}
+	if err := run(ctx, opts); err != nil {
+		if !printConfigError(os.Stderr, err) {
+			slog.Error("application error", "error", err)
+		}
 		os.Exit(1)
 	}
 }
 
-func run(ctx context.Context, configPath string, migrateOnly, seedData, debugMode bool) error {
+// runOptions holds the command line options for a normal run.
+type runOptions struct {
+	configPath  string
+	migrateOnly bool
+	seedData    bool
+	seedProfile string
+	debugMode   bool
+	importPath  string
+	skipBackup  bool
+	allowDrift  bool
+	readOnly    bool
+	scriptPath  string
+	trainPath   string
+	journalPath string
+	profile     string
+}
+
+func run(ctx context.Context, opts runOptions) error {
 	// Load configuration
-	cfg, cfgPath, err := config.Load(configPath, true)
+	cfg, cfgPath, err := config.Load(opts.configPath, true)
 	if err != nil {
 		return fmt.Errorf("loading configuration: %w", err)
 	}
+	if err := cfg.UseProfile(opts.profile); err != nil {
+		return err
+	}
+	util.SetCalendar(cfg.Calendar())
+	if opts.readOnly {
+		cfg.Database.ReadOnly = true
+	}
+	if cfg.Database.ReadOnly && (opts.migrateOnly || opts.seedData || opts.importPath != "") {
+		return errors.New("-migrate-only, -seed and -import-residents cannot be used in read-only mode")
+	}
+	if opts.seedProfile == "" {
+		_, profile := cfg.Profile()
+		opts.seedProfile = profile.SeedProfile
+	}
+	if opts.seedProfile != "" {
+		if _, err := seed.LookupProfile(opts.seedProfile); err != nil {
+			return err
+		}
+	}
+	if opts.scriptPath != "" {
+		if cfg.Database.ReadOnly {
+			return errors.New("-script cannot be used in read-only mode")
+		}
+		cfg.Simulation.Script = opts.scriptPath
+	}
+	if opts.trainPath != "" {
+		if opts.scriptPath != "" {
+			return errors.New("-train and -script cannot be used together")
+		}
+		cfg.Simulation.Script = opts.trainPath
+		cfg.Simulation.Training = true
+	}
+	if cfg.Simulation.Training {
+		// An exercise breaks systems and destroys stock, so it must never
+		// run against the vault's own records
+		if cfg.Database.ReadOnly {
+			return errors.New("a training exercise cannot run in read-only mode")
+		}
+		if name, _ := cfg.Profile(); cfg.SharesVault() {
+			if name == "" {
+				return errors.New("a training exercise needs a profile with its own database, e.g. -profile training")
+			}
+			return fmt.Errorf("profile %s shares the vault's database; a training exercise needs one that sets data_dir or database", name)
+		}
+	}
+	if opts.journalPath != "" {
+		if cfg.Database.ReadOnly {
+			return errors.New("-journal cannot be used in read-only mode")
+		}
+		cfg.Simulation.Journal = opts.journalPath
+	}
 
 	// Setup logging
 	logLevel := slog.LevelInfo
-	if debugMode {
+	if opts.debugMode {
 		logLevel = slog.LevelDebug
 	} else {
 		switch cfg.Logging.Level {
@@ -123,6 +237,7 @@ func run(ctx context.Context, configPath string, migrateOnly, seedData, debugMod
 		"version", Version,
 		"build_time", BuildTime,
 		"config_path", cfgPath,
+		"profile", opts.profile,
 	)
 
 	// Get database path
@@ -138,8 +253,9 @@ func run(ctx context.Context, configPath string, migrateOnly, seedData, debugMod
 		backupDir = ""
 	}
 
-	// Attempt database recovery if needed
-	if _, err := os.Stat(dbPath); err == nil {
+	// Attempt database recovery if needed; recovery may restore a backup, so
+	// a read-only terminal leaves it to a writable one
+	if _, err := os.Stat(dbPath); err == nil && !cfg.Database.ReadOnly {
 		report, err := database.AttemptRecovery(dbPath, backupDir)
 		if err != nil {
 			slog.Error("database recovery failed",
@@ -176,27 +292,102 @@ func run(ctx context.Context, configPath string, migrateOnly, seedData, debugMod
 	if err != nil {
 		return fmt.Errorf("creating migrator: %w", err)
 	}
-
-	result, err := migrator.MigrateUp(ctx)
-	if err != nil {
-		return fmt.Errorf("running migrations: %w", err)
+	if err := checkMigrationDrift(ctx, migrator, opts.allowDrift); err != nil {
+		return err
 	}
 
-	if len(result.Applied) > 0 {
-		slog.Info("applied migrations",
-			"count", len(result.Applied),
-			"to_version", result.TargetVersion,
-		)
+	if cfg.Database.ReadOnly {
+		if err := checkSchemaCurrent(ctx, migrator); err != nil {
+			return err
+		}
+		slog.Info("database opened read-only", "path", dbPath)
+	} else {
+		migrator.SetBackupBeforeMigrate(!opts.skipBackup)
+
+		result, err := migrator.MigrateUp(ctx)
+		if err != nil {
+			if result != nil && result.BackupPath != "" {
+				slog.Error("migration failed, restore with: vtuos migrate undo-last --restore-backup",
+					"backup", result.BackupPath,
+				)
+			}
+			return fmt.Errorf("running migrations: %w", err)
+		}
+
+		if len(result.Applied) > 0 {
+			slog.Info("applied migrations",
+				"count", len(result.Applied),
+				"to_version", result.TargetVersion,
+				"backup", result.BackupPath,
+			)
+		}
+
+		// Records from before multi-vault support belong to the primary vault
+		claimed, err := repository.ClaimUnassigned(ctx, db.DB, cfg.Vault.Number)
+		if err != nil {
+			return fmt.Errorf("assigning records to vault %d: %w", cfg.Vault.Number, err)
+		}
+		if claimed > 0 {
+			slog.Info("assigned records to primary vault", "vault", cfg.Vault.Number, "count", claimed)
+		}
+
+		// The data key table may have just been created
+		if err := db.LoadEncryption(ctx); err != nil {
+			return fmt.Errorf("loading encryption keys: %w", err)
+		}
+		if err := encryptPlaintext(ctx, db, cfg); err != nil {
+			return err
+		}
 	}
 
 	// Exit early if migrate-only mode
-	if migrateOnly {
+	if opts.migrateOnly {
 		slog.Info("migrations complete, exiting")
 		return nil
 	}
 
+	// Initialize vault clock
+	startTime, err := cfg.Simulation.StartDateTime()
+	if err != nil {
+		startTime = time.Now()
+	}
+	clock := util.NewVaultClock(startTime, cfg.Simulation.TimeScale)
+
+	if !cfg.Simulation.Enabled {
+		clock.Pause()
+	}
+
+	// Journal the session's commands, from template installation on
+	j, err := openJournal(cfg, clock)
+	if err != nil {
+		return err
+	}
+	defer j.Close()
+
+	// Install standard inspection templates, first inspections a week after sealing
+	if !cfg.Database.ReadOnly {
+		firstInspection, err := cfg.Simulation.StartDateTime()
+		if err != nil {
+			firstInspection = time.Now().UTC()
+		}
+		inspSvc := inspections.NewService(db.DB)
+		inspSvc.SetJournal(j)
+		installed, err := inspSvc.InstallDefaultTemplates(ctx, firstInspection.AddDate(0, 0, 7))
+		if err != nil {
+			return fmt.Errorf("installing inspection templates: %w", err)
+		}
+		if installed > 0 {
+			slog.Info("installed inspection templates", "count", installed)
+		}
+	}
+
+	// Import residents if requested
+	if opts.importPath != "" {
+		return importResidents(ctx, db, cfg, j, opts.importPath)
+	}
+
 	// Generate seed data if requested
-	if seedData {
+	if opts.seedData {
 		slog.Info("generating seed data", "vault", cfg.Vault.Number)
 
 		// Check if data already exists
@@ -206,40 +397,63 @@ func run(ctx context.Context, configPath string, migrateOnly, seedData, debugMod
 			return nil
 		}
 
-		// Get seal date from config
-		sealDate, err := cfg.Simulation.StartDateTime()
+		// The generated history ends at the simulation start date
+		startDate, err := cfg.Simulation.StartDateTime()
 		if err != nil {
-			sealDate = time.Date(2077, 10, 23, 9, 47, 0, 0, time.UTC)
+			startDate = time.Date(2077, 10, 23, 9, 47, 0, 0, time.UTC)
 		}
 
-		seedCfg := seed.Config{
-			VaultNumber:      cfg.Vault.Number,
-			SealDate:         sealDate,
-			TargetPopulation: cfg.Vault.DesignedCapacity,
-			FamilyHouseholds: 100,
-			SingleHouseholds: 80,
-			RandomSeed:       2077,
+		// Without a profile, the standard profile fills the designed capacity
+		profileName := opts.seedProfile
+		if profileName == "" {
+			profileName = seed.DefaultProfile
+		}
+		profile, err := seed.LookupProfile(profileName)
+		if err != nil {
+			return err
 		}
+		if opts.seedProfile == "" {
+			profile.TargetPopulation = cfg.Vault.DesignedCapacity
+		}
+		seedCfg := profile.Config(cfg.Vault.Number, startDate)
+		seedCfg.RegistryFormat = cfg.Vault.RegistryFormat
+		slog.Info("seed profile", "profile", profile.Name, "population", seedCfg.TargetPopulation, "history_days", seedCfg.HistoryDays)
 
 		generator := seed.NewGenerator(db.DB, seedCfg)
+		generator.SetJournal(j)
 		if err := generator.Generate(ctx); err != nil {
 			return fmt.Errorf("generating seed data: %w", err)
 		}
+		if err := encryptPlaintext(ctx, db, cfg); err != nil {
+			return err
+		}
 
 		slog.Info("seed data generation complete")
 		return nil
 	}
 
-	// Initialize vault clock
-	startTime, err := cfg.Simulation.StartDateTime()
-	if err != nil {
-		startTime = time.Now()
+	// Check the scenario script before starting, so a typo fails loudly
+	// rather than as an alert
+	if cfg.Simulation.Training {
+		scenario, err := training.LoadScenario(cfg.Simulation.Script)
+		if err != nil {
+			return fmt.Errorf("loading training scenario: %w", err)
+		}
+		slog.Info("training scenario loaded", "scenario", scenario.Script.Name, "events", len(scenario.Script.Events), "objectives", scenario.Objectives.Count())
+	} else if cfg.Simulation.Script != "" && !cfg.Database.ReadOnly {
+		script, err := simulation.LoadScript(cfg.Simulation.Script)
+		if err != nil {
+			return fmt.Errorf("loading scenario script: %w", err)
+		}
+		slog.Info("scenario script loaded", "script", script.Name, "events", len(script.Events))
 	}
-	clock := util.NewVaultClock(startTime, cfg.Simulation.TimeScale)
 
-	if !cfg.Simulation.Enabled {
-		clock.Pause()
+	// Forward events to external monitoring, delivering those queued on exit
+	bus, err := openEvents(cfg)
+	if err != nil {
+		return err
 	}
+	defer bus.Close()
 
 	// Set version info for TUI
 	tui.Version = Version
@@ -251,10 +465,83 @@ func run(ctx context.Context, configPath string, migrateOnly, seedData, debugMod
 		"simulation", cfg.Simulation.Enabled,
 	)
 
-	if err := tui.Run(ctx, db, cfg, clock); err != nil {
+	if err := tui.Run(ctx, db, cfg, cfgPath, clock, j, bus); err != nil {
 		return fmt.Errorf("TUI error: %w", err)
 	}
 
 	slog.Info("VT-UOS shutdown complete")
 	return nil
 }
+
+// checkSchemaCurrent fails when migrations are pending. A read-only database
+// cannot apply them, so it must already be at the current version.
+func checkSchemaCurrent(ctx context.Context, migrator *database.Migrator) error {
+	pending, err := migrator.PendingMigrations(ctx)
+	if err != nil {
+		return fmt.Errorf("checking migrations: %w", err)
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("database has %d pending migrations; start once without read-only mode to apply them", len(pending))
+	}
+	return nil
+}
+
+// checkMigrationDrift fails when a migration was edited after it was
+// applied, as the schema may not be what the code expects. With allowDrift
+// it only warns.
+func checkMigrationDrift(ctx context.Context, migrator *database.Migrator, allowDrift bool) error {
+	drifted, err := migrator.VerifyChecksums(ctx)
+	if err != nil {
+		return fmt.Errorf("verifying migrations: %w", err)
+	}
+	for _, mig := range drifted {
+		slog.Warn("migration changed since it was applied",
+			"version", mig.Version,
+			"description", mig.Description,
+			"applied_checksum", mig.AppliedChecksum,
+			"checksum", mig.Checksum,
+		)
+	}
+	if len(drifted) > 0 && !allowDrift {
+		return fmt.Errorf("%w: %d migration(s); start with -allow-drift to run anyway", database.ErrMigrationDrift, len(drifted))
+	}
+	return nil
+}
+
+// importResidents loads a CSV resident manifest and prints a per-row report.
+func importResidents(ctx context.Context, db *database.DB, cfg *config.Config, j *journal.Journal, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("opening import file: %w", err)
+	}
+	defer f.Close()
+
+	svc := population.NewService(db.DB, cfg.Vault.Number)
+	svc.SetJournal(j)
+	svc.SetRegistryFormat(cfg.RegistryFormat(cfg.Vault.Number))
+	if startTime, err := cfg.Simulation.StartDateTime(); err == nil {
+		svc.SetClock(util.NewVaultClock(startTime, 0))
+	}
+
+	slog.Info("importing residents", "path", path)
+
+	result, err := svc.ImportResidents(ctx, f)
+	if err != nil {
+		return fmt.Errorf("importing residents: %w", err)
+	}
+
+	if result.HasErrors() {
+		for _, rowErr := range result.Errors {
+			fmt.Fprintln(os.Stderr, rowErr.Error())
+		}
+		return fmt.Errorf("import rejected: %d of %d rows invalid, nothing imported",
+			len(result.Errors), result.RowsRead)
+	}
+
+	fmt.Printf("Imported %d residents (%d new households)\n", result.Imported, result.HouseholdsCreated)
+	slog.Info("resident import complete",
+		"imported", result.Imported,
+		"households_created", result.HouseholdsCreated,
+	)
+	return nil
+}
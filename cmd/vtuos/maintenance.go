@@ -0,0 +1,618 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/config"
+	"github.com/vtuos/vtuos/internal/database"
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/services/facilities"
+	"github.com/vtuos/vtuos/internal/services/population"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// runMaintenanceCommand handles `vtuos maintenance <subcommand>`: declare
+// and list the consumables of facility systems, list open work orders and
+// close them, replacing the consumables that fall due, declare and list the
+// systems each system depends on, rate technicians and assign them to work
+// orders, set the order power loads are shed in, set how much systems run
+// and whether they are maintained by runtime, analyze repair times and
+// failures, commission and decommission systems, and show the latest sector
+// environment readings.
+func runMaintenanceCommand(ctx context.Context, configPath, profile string, args []string) error {
+	if len(args) == 0 {
+		flag.Usage()
+		return fmt.Errorf("maintenance requires a subcommand: consumable, consumables, open, complete, depend, undepend, dependencies, technician, technicians, suggest, assign, shed, loads, duty, runtime, analytics, commission, decommission or environment")
+	}
+
+	cfg, err := loadConfig(configPath, profile)
+	if err != nil {
+		return fmt.Errorf("loading configuration: %w", err)
+	}
+
+	dbPath, err := config.EnsureDataDir(cfg)
+	if err != nil {
+		return fmt.Errorf("ensuring data directory: %w", err)
+	}
+	backupDir, err := config.BackupDir(cfg)
+	if err != nil {
+		return fmt.Errorf("getting backup directory: %w", err)
+	}
+
+	db, err := database.Open(dbPath, &cfg.Database, backupDir)
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	j, err := openJournal(cfg, nil)
+	if err != nil {
+		return err
+	}
+	defer j.Close()
+
+	svc := facilities.NewService(db.DB)
+	svc.SetVault(cfg.Vault.Number)
+	svc.SetJournal(j)
+
+	switch args[0] {
+	case "consumable":
+		if len(args) != 5 {
+			return fmt.Errorf("maintenance consumable requires a system code, an item code, a quantity and an interval in days")
+		}
+		qty, err := strconv.ParseFloat(args[3], 64)
+		if err != nil {
+			return fmt.Errorf("invalid quantity %q: %w", args[3], err)
+		}
+		days, err := strconv.Atoi(args[4])
+		if err != nil {
+			return fmt.Errorf("invalid interval %q: %w", args[4], err)
+		}
+		c, err := svc.DeclareConsumable(ctx, args[1], args[2], qty, days)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s takes %.2f %s of %s every %d day(s)\n",
+			args[1], c.Quantity, c.Item.UnitOfMeasure, c.Item.ItemCode, c.IntervalDays)
+		return nil
+	case "consumables":
+		var system string
+		if len(args) > 1 {
+			system = args[1]
+		}
+		consumables, err := svc.ListConsumables(ctx, system)
+		if err != nil {
+			return err
+		}
+		systems, err := systemCodes(ctx, svc)
+		if err != nil {
+			return err
+		}
+		if len(consumables) == 0 {
+			fmt.Println("No consumables declared")
+			return nil
+		}
+		for _, c := range consumables {
+			last, next := "never", "now"
+			if c.LastReplaced != nil {
+				last = util.FormatDay(*c.LastReplaced)
+			}
+			if c.NextDue != nil {
+				next = util.FormatDay(*c.NextDue)
+			}
+			fmt.Printf("%-18s %-18s %8.2f %-6s every %4d day(s)  last %-10s  due %s\n",
+				systems[c.SystemID], c.Item.ItemCode, c.Quantity, c.Item.UnitOfMeasure, c.IntervalDays, last, next)
+		}
+		return nil
+	case "open":
+		orders, err := svc.ListOpenMaintenance(ctx)
+		if err != nil {
+			return err
+		}
+		systems, err := systemCodes(ctx, svc)
+		if err != nil {
+			return err
+		}
+		leads := make(map[string]string)
+		popSvc := population.NewService(db.DB, cfg.Vault.Number)
+		for _, o := range orders {
+			if id := o.LeadTechnicianID; id != nil && leads[*id] == "" {
+				resident, err := popSvc.GetResident(ctx, *id)
+				if err != nil {
+					return fmt.Errorf("lead technician of %s: %w", o.ID, err)
+				}
+				leads[*id] = resident.RegistryNumber
+			}
+		}
+		if len(orders) == 0 {
+			fmt.Println("No open work orders")
+			return nil
+		}
+		for _, o := range orders {
+			scheduled, lead := "-", "-"
+			if o.ScheduledDate != nil {
+				scheduled = util.FormatDate(*o.ScheduledDate)
+			}
+			if o.LeadTechnicianID != nil {
+				lead = leads[*o.LeadTechnicianID]
+			}
+			fmt.Printf("%s  %-10s  %-18s %-11s %-12s %s\n", o.ID, scheduled, systems[o.SystemID], o.MaintenanceType, lead, o.Description)
+		}
+		return nil
+	case "complete":
+		fs := flag.NewFlagSet("complete", flag.ContinueOnError)
+		outcome := fs.String("outcome", string(models.MaintenanceOutcomeCompleted),
+			"COMPLETED, PARTIAL, FAILED, DEFERRED or CANCELLED")
+		by := fs.String("by", "", "Registry number of the technician who did the work")
+		date := fs.String("date", "", "Completion date YYYY-MM-DD (default: now)")
+		note := fs.String("note", "", "Note recorded with the work order")
+		hours := fs.Float64("hours", 0, "Labor hours the work took")
+		efficiency := fs.Float64("efficiency", 0, "Efficiency percent completed work restores the system to")
+		id, err := parseWithName(fs, args[1:])
+		if err != nil {
+			return err
+		}
+		if id == "" {
+			return fmt.Errorf("maintenance complete requires a work order ID")
+		}
+
+		input := facilities.CompleteMaintenanceInput{
+			Outcome: models.MaintenanceOutcome(strings.ToUpper(*outcome)),
+			Notes:   *note,
+		}
+		fs.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "hours":
+				input.Hours = hours
+			case "efficiency":
+				input.Efficiency = efficiency
+			}
+		})
+		if *date != "" {
+			if input.At, err = time.Parse(time.DateOnly, *date); err != nil {
+				return fmt.Errorf("invalid --date: %w", err)
+			}
+		}
+		if *by != "" {
+			popSvc := population.NewService(db.DB, cfg.Vault.Number)
+			resident, err := popSvc.GetResidentByRegistryNumber(ctx, *by)
+			if err != nil {
+				return fmt.Errorf("resident %s: %w", *by, err)
+			}
+			input.CompletedBy = &resident.ID
+		}
+
+		completion, err := svc.CompleteMaintenance(ctx, id, input)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Closed work order %s %s\n", completion.Record.ID, completion.Record.Outcome)
+		for _, c := range completion.Replaced {
+			fmt.Printf("  replaced %.2f %s of %s, next due %s\n",
+				c.Quantity, c.Item.UnitOfMeasure, c.Item.ItemCode, util.FormatDay(*c.NextDue))
+		}
+		if r := completion.Restored; r != nil {
+			fmt.Printf("  %s\n", r)
+			for _, c := range r.Cascaded {
+				fmt.Printf("  %s\n", c)
+			}
+		}
+		return nil
+	case "depend":
+		if len(args) != 3 {
+			return fmt.Errorf("maintenance depend requires a system code and the code of the system it depends on")
+		}
+		if _, err := svc.AddDependency(ctx, args[1], args[2]); err != nil {
+			return err
+		}
+		fmt.Printf("%s depends on %s\n", args[1], args[2])
+		return nil
+	case "undepend":
+		if len(args) != 3 {
+			return fmt.Errorf("maintenance undepend requires a system code and the code of the system it depends on")
+		}
+		if err := svc.RemoveDependency(ctx, args[1], args[2]); err != nil {
+			return err
+		}
+		fmt.Printf("%s no longer depends on %s\n", args[1], args[2])
+		return nil
+	case "dependencies":
+		m, err := svc.Dependencies(ctx)
+		if err != nil {
+			return err
+		}
+		for _, sys := range m.Systems {
+			var parents []string
+			for _, id := range m.Graph.Parents(sys.ID) {
+				parents = append(parents, m.System(id).SystemCode)
+			}
+			line := fmt.Sprintf("%-18s %-11s %5.1f%%", sys.SystemCode, sys.Status, sys.EfficiencyPercent)
+			if len(parents) > 0 {
+				line += "  depends on " + strings.Join(parents, ", ")
+			}
+			if c := m.Cascades[sys.ID]; c != nil {
+				line += fmt.Sprintf("  (root cause: %s since %s)", m.System(c.RootID).SystemCode, util.FormatDate(c.At))
+			}
+			fmt.Println(line)
+		}
+		return nil
+	case "technician":
+		if len(args) < 3 {
+			return fmt.Errorf("maintenance technician requires a registry number, a shift and CATEGORY=RATING skills")
+		}
+		skills := make(map[models.FacilityCategory]int)
+		for _, arg := range args[3:] {
+			category, value, ok := strings.Cut(arg, "=")
+			if !ok {
+				return fmt.Errorf("invalid skill %q: expected CATEGORY=RATING", arg)
+			}
+			rating, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("invalid rating %q: %w", value, err)
+			}
+			skills[models.FacilityCategory(strings.ToUpper(category))] = rating
+		}
+		t, err := svc.SetTechnician(ctx, args[1], models.Shift(strings.ToUpper(args[2])), skills)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s works %s shift with %d rated skill(s)\n", args[1], t.Shift, len(t.Skills))
+		return nil
+	case "technicians":
+		technicians, err := svc.ListTechnicians(ctx)
+		if err != nil {
+			return err
+		}
+		if len(technicians) == 0 {
+			fmt.Println("No technicians rated")
+			return nil
+		}
+		fmt.Printf("%-12s %-6s", "RESIDENT", "SHIFT")
+		for _, category := range models.FacilityCategories {
+			fmt.Printf(" %4.4s", category)
+		}
+		fmt.Println()
+		for _, t := range technicians {
+			fmt.Printf("%-12s %-6s", t.Resident.RegistryNumber, t.Shift)
+			for _, category := range models.FacilityCategories {
+				rating := "-"
+				if r := t.Skill(category); r > 0 {
+					rating = strconv.Itoa(r)
+				}
+				fmt.Printf(" %4s", rating)
+			}
+			fmt.Println()
+		}
+		return nil
+	case "suggest":
+		if len(args) != 2 {
+			return fmt.Errorf("maintenance suggest requires a work order ID")
+		}
+		suggestions, err := svc.SuggestTechnicians(ctx, args[1])
+		if err != nil {
+			return err
+		}
+		if len(suggestions) == 0 {
+			fmt.Println("No active technician is rated for the system")
+			return nil
+		}
+		for _, sg := range suggestions {
+			shift := "off shift"
+			if sg.OnShift {
+				shift = "on shift"
+			}
+			fmt.Printf("%-12s skill %d  %d open order(s)  %-9s  score %.1f\n",
+				sg.Technician.Resident.RegistryNumber, sg.Rating, sg.Workload, shift, sg.Score)
+		}
+		return nil
+	case "assign":
+		if len(args) < 2 || len(args) > 3 {
+			return fmt.Errorf("maintenance assign requires a work order ID and a registry number, or none to unassign")
+		}
+		var reg string
+		if len(args) == 3 {
+			reg = args[2]
+		}
+		if err := svc.AssignWorkOrder(ctx, args[1], reg); err != nil {
+			return err
+		}
+		if reg == "" {
+			fmt.Printf("Work order %s unassigned\n", args[1])
+		} else {
+			fmt.Printf("Work order %s led by %s\n", args[1], reg)
+		}
+		return nil
+	case "shed":
+		if len(args) != 3 {
+			return fmt.Errorf("maintenance shed requires a system code and a priority, 0 never to shed it")
+		}
+		priority, err := strconv.Atoi(args[2])
+		if err != nil {
+			return fmt.Errorf("invalid priority %q: %w", args[2], err)
+		}
+		if err := svc.SetShedPriority(ctx, args[1], priority); err != nil {
+			return err
+		}
+		if priority == 0 {
+			fmt.Printf("%s is never shed\n", args[1])
+		} else {
+			fmt.Printf("%s sheds at priority %d\n", args[1], priority)
+		}
+		return nil
+	case "loads":
+		loads, err := svc.ShedLoads(ctx)
+		if err != nil {
+			return err
+		}
+		if len(loads) == 0 {
+			fmt.Println("No system draws power")
+			return nil
+		}
+		for _, l := range loads {
+			priority, state := "never", "running"
+			if l.Priority > 0 {
+				priority = strconv.Itoa(l.Priority)
+			}
+			switch {
+			case l.System.IsShed():
+				state = "SHED"
+			case !l.System.IsRunning():
+				state = string(l.System.Status)
+			}
+			fmt.Printf("%-18s %-16s %8.0f kW  priority %-5s  %s\n",
+				l.System.SystemCode, l.System.Category, l.Draw, priority, state)
+		}
+		return nil
+	case "duty":
+		fs := flag.NewFlagSet("duty", flag.ContinueOnError)
+		date := fs.String("date", "", "Vault date to project the next maintenance from, YYYY-MM-DD (default: vault time)")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if fs.NArg() < 2 || fs.NArg() > 3 {
+			return fmt.Errorf("maintenance duty requires a system code, a duty cycle percent and optionally a runtime interval in hours")
+		}
+		duty, err := strconv.ParseFloat(fs.Arg(1), 64)
+		if err != nil {
+			return fmt.Errorf("invalid duty cycle %q: %w", fs.Arg(1), err)
+		}
+		var interval float64
+		if fs.NArg() == 3 {
+			if interval, err = strconv.ParseFloat(fs.Arg(2), 64); err != nil {
+				return fmt.Errorf("invalid interval %q: %w", fs.Arg(2), err)
+			}
+		}
+		at, err := vaultTime(cfg, *date)
+		if err != nil {
+			return fmt.Errorf("invalid --date: %w", err)
+		}
+		sys, err := svc.SetDutyCycle(ctx, fs.Arg(0), duty, interval, at)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s runs %.0f%% of its online time", sys.SystemCode, sys.DutyCycle())
+		if sys.MaintenanceIntervalHours != nil {
+			fmt.Printf(", maintained every %.0f runtime hour(s)", *sys.MaintenanceIntervalHours)
+		} else {
+			fmt.Printf(", maintained every %d day(s)", sys.MaintenanceIntervalDays)
+		}
+		if sys.NextMaintenanceDue != nil {
+			fmt.Printf(", next due %s", sys.NextMaintenanceDue.Format(time.DateOnly))
+		}
+		fmt.Println()
+		return nil
+	case "runtime":
+		systems, err := svc.ListSystems(ctx, nil, models.SortOption{})
+		if err != nil {
+			return err
+		}
+		if len(systems) == 0 {
+			fmt.Println("No facility systems")
+			return nil
+		}
+		fmt.Printf("%-18s %-11s %5s %10s %10s %10s  %s\n",
+			"SYSTEM", "STATUS", "DUTY", "RUNTIME", "SINCE", "INTERVAL", "NEXT DUE")
+		for _, sys := range systems {
+			interval, due := fmt.Sprintf("%dd", sys.MaintenanceIntervalDays), "-"
+			if sys.MaintenanceIntervalHours != nil {
+				interval = fmt.Sprintf("%.0fh", *sys.MaintenanceIntervalHours)
+			}
+			if sys.NextMaintenanceDue != nil {
+				due = sys.NextMaintenanceDue.Format(time.DateOnly)
+			}
+			fmt.Printf("%-18s %-11s %4.0f%% %10.0f %10.0f %10s  %s\n",
+				sys.SystemCode, sys.Status, sys.DutyCycle(), sys.TotalRuntimeHours,
+				sys.RuntimeSinceMaintenance(), interval, due)
+		}
+		return nil
+	case "analytics":
+		fs := flag.NewFlagSet("analytics", flag.ContinueOnError)
+		days := fs.Int("days", 90, "Days of work orders to analyze, up to the --as-of date")
+		asOfStr := fs.String("as-of", "", "Vault date to analyze up to, YYYY-MM-DD (default: vault time)")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		asOf, err := vaultTime(cfg, *asOfStr)
+		if err != nil {
+			return fmt.Errorf("invalid --as-of date: %s", *asOfStr)
+		}
+		m, err := svc.MaintenanceAnalytics(ctx, asOf, *days)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Work orders raised %s to %s\n", util.FormatDay(m.From), util.FormatDay(m.To.AddDate(0, 0, -1)))
+		if len(m.Categories) == 0 {
+			fmt.Println("No work orders raised")
+			return nil
+		}
+		fmt.Printf("\n%-16s %7s %8s %9s %9s %10s\n", "CATEGORY", "ORDERS", "REPAIRS", "MTTR", "LABOR H", "EFF/HOUR")
+		for _, c := range m.Categories {
+			mttr, yield := "-", "-"
+			if c.TimedRepairs > 0 {
+				mttr = fmt.Sprintf("%.1fh", c.MTTR().Hours())
+			}
+			if c.LaborHours > 0 {
+				yield = fmt.Sprintf("%+.1f", c.RecoveredPerHour())
+			}
+			fmt.Printf("%-16s %7d %8d %9s %9.1f %10s\n", c.Category, c.Orders, c.Repairs, mttr, c.LaborHours, yield)
+		}
+		if len(m.Systems) == 0 {
+			return nil
+		}
+		vital := len(m.VitalFew())
+		fmt.Printf("\n%-18s %-16s %8s %6s\n", "SYSTEM", "CATEGORY", "FAILURES", "CUM")
+		for i, f := range m.Systems {
+			mark := ""
+			if i < vital {
+				mark = "  *"
+			}
+			fmt.Printf("%-18s %-16s %8d %5.0f%%%s\n", f.System.SystemCode, f.System.Category, f.Failures, f.Cumulative*100, mark)
+		}
+		fmt.Printf("* %d system(s) account for %.0f%% of %d failure(s): stock their spare parts first\n",
+			vital, m.Systems[vital-1].Cumulative*100, m.Failures)
+		return nil
+	case "commission":
+		fs := flag.NewFlagSet("commission", flag.ContinueOnError)
+		interval := fs.Int("interval", 0, "Days between maintenance (default 90)")
+		mtbf := fs.Int("mtbf", 0, "Rated mean hours between failures")
+		by := fs.String("by", "", "Registry number of the resident commissioning the system")
+		date := fs.String("date", "", "Install date YYYY-MM-DD (default: vault time)")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if fs.NArg() < 5 {
+			return fmt.Errorf("maintenance commission requires a system code, a category, a sector, a level and a name")
+		}
+		level, err := strconv.Atoi(fs.Arg(3))
+		if err != nil {
+			return fmt.Errorf("invalid level %q: %w", fs.Arg(3), err)
+		}
+		input := facilities.CommissionInput{
+			SystemCode:              fs.Arg(0),
+			Category:                models.FacilityCategory(strings.ToUpper(fs.Arg(1))),
+			LocationSector:          fs.Arg(2),
+			LocationLevel:           level,
+			Name:                    strings.Join(fs.Args()[4:], " "),
+			MaintenanceIntervalDays: *interval,
+		}
+		if *mtbf != 0 {
+			input.MTBFHours = mtbf
+		}
+		if input.AuthorizedBy, err = residentID(ctx, db, cfg, *by); err != nil {
+			return err
+		}
+		if *date != "" {
+			if input.At, err = time.Parse(time.DateOnly, *date); err != nil {
+				return fmt.Errorf("invalid --date: %w", err)
+			}
+		} else if startTime, err := cfg.Simulation.StartDateTime(); err == nil {
+			svc.SetClock(util.NewVaultClock(startTime, 0))
+		}
+
+		c, err := svc.CommissionSystem(ctx, input)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Commissioned %s %s, installed %s, maintenance due %s\n", c.System.SystemCode, c.System.Name,
+			util.FormatDay(c.System.InstallDate), util.FormatDay(*c.System.NextMaintenanceDue))
+		fmt.Printf("  inspection work order %s\n", c.Inspection.ID)
+		return nil
+	case "decommission":
+		fs := flag.NewFlagSet("decommission", flag.ContinueOnError)
+		reason := fs.String("reason", "", "Why the system is taken out of service")
+		dispose := fs.Bool("dispose-spares", false, "Write off the stock of consumables no system in service takes")
+		by := fs.String("by", "", "Registry number of the resident decommissioning the system")
+		countersign := fs.String("countersign", "", "Registry number of the second operator of a dual-authorized write-off")
+		date := fs.String("date", "", "Decommissioning date YYYY-MM-DD (default: vault time)")
+		system, err := parseWithName(fs, args[1:])
+		if err != nil {
+			return err
+		}
+		if system == "" {
+			return fmt.Errorf("maintenance decommission requires a system code")
+		}
+		if *countersign != "" && *by == "" {
+			return fmt.Errorf("--countersign needs --by")
+		}
+		input := facilities.DecommissionInput{Reason: *reason, DisposeSpares: *dispose}
+		if input.AuthorizedBy, err = residentID(ctx, db, cfg, *by); err != nil {
+			return err
+		}
+		if *date != "" {
+			if input.At, err = time.Parse(time.DateOnly, *date); err != nil {
+				return fmt.Errorf("invalid --date: %w", err)
+			}
+		} else if startTime, err := cfg.Simulation.StartDateTime(); err == nil {
+			svc.SetClock(util.NewVaultClock(startTime, 0))
+		}
+		if *countersign != "" {
+			pin, err := readSecret(fmt.Sprintf("PIN of %s: ", *countersign))
+			if err != nil {
+				return err
+			}
+			ctx = util.WithCountersign(ctx, util.Countersign{RequestedBy: *by, By: *countersign, PIN: pin})
+		}
+
+		d, err := svc.DecommissionSystem(ctx, system, input)
+		if d != nil {
+			fmt.Printf("Decommissioned %s %s: %s\n", d.System.SystemCode, d.System.Name, d.System.DecommissionReason)
+			for _, rec := range d.Cancelled {
+				fmt.Printf("  cancelled work order %s %s\n", rec.ID, rec.Description)
+			}
+			for _, c := range d.Consumables {
+				fmt.Printf("  no longer takes %.2f %s of %s\n", c.Quantity, c.Item.UnitOfMeasure, c.Item.ItemCode)
+			}
+			for _, spare := range d.Spares {
+				fmt.Printf("  spare %s: %.2f %s available, %.2f disposed of\n",
+					spare.Item.ItemCode, spare.Available, spare.Item.UnitOfMeasure, spare.Disposed)
+			}
+		}
+		return err
+	case "environment":
+		readings, err := svc.Environment(ctx)
+		if err != nil {
+			return err
+		}
+		if len(readings) == 0 {
+			fmt.Println("No environment readings taken")
+			return nil
+		}
+		for _, r := range readings {
+			fmt.Printf("%s  %s\n", r.ReadAt.Format("2006-01-02 15:04"), r)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown maintenance subcommand: %s", args[0])
+	}
+}
+
+// residentID returns the ID of the resident with a registry number, or nil
+// without one.
+func residentID(ctx context.Context, db *database.DB, cfg *config.Config, registryNumber string) (*string, error) {
+	if registryNumber == "" {
+		return nil, nil
+	}
+	resident, err := population.NewService(db.DB, cfg.Vault.Number).GetResidentByRegistryNumber(ctx, registryNumber)
+	if err != nil {
+		return nil, fmt.Errorf("resident %s: %w", registryNumber, err)
+	}
+	return &resident.ID, nil
+}
+
+// systemCodes maps the IDs of the vault's facility systems to their codes.
+func systemCodes(ctx context.Context, svc *facilities.Service) (map[string]string, error) {
+	systems, err := svc.ListSystems(ctx, nil, models.SortOption{})
+	if err != nil {
+		return nil, fmt.Errorf("listing systems: %w", err)
+	}
+	codes := make(map[string]string, len(systems))
+	for _, sys := range systems {
+		codes[sys.ID] = sys.SystemCode
+	}
+	return codes, nil
+}
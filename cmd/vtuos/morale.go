@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/vtuos/vtuos/internal/config"
+	"github.com/vtuos/vtuos/internal/database"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/services/population"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// runMoraleCommand handles `vtuos morale <subcommand>`: score residents'
+// morale, show the vault happiness index with the morale of each sector
+// and the lowest residents, and show a resident's score.
+func runMoraleCommand(ctx context.Context, configPath, profile string, args []string) error {
+	if len(args) == 0 {
+		flag.Usage()
+		return fmt.Errorf("morale requires a subcommand: score, index or show")
+	}
+
+	cfg, err := loadConfig(configPath, profile)
+	if err != nil {
+		return fmt.Errorf("loading configuration: %w", err)
+	}
+
+	dbPath, err := config.EnsureDataDir(cfg)
+	if err != nil {
+		return fmt.Errorf("ensuring data directory: %w", err)
+	}
+	backupDir, err := config.BackupDir(cfg)
+	if err != nil {
+		return fmt.Errorf("getting backup directory: %w", err)
+	}
+
+	db, err := database.Open(dbPath, &cfg.Database, backupDir)
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	j, err := openJournal(cfg, nil)
+	if err != nil {
+		return err
+	}
+	defer j.Close()
+
+	svc := population.NewService(db.DB, cfg.Vault.Number)
+	svc.SetJournal(j)
+
+	switch args[0] {
+	case "score":
+		fs := flag.NewFlagSet("score", flag.ContinueOnError)
+		asOfStr := fs.String("as-of", "", "Score as of this date YYYY-MM-DD (default: vault start date)")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		asOf, err := vaultTime(cfg, *asOfStr)
+		if err != nil {
+			return fmt.Errorf("invalid --as-of: %w", err)
+		}
+		snapshot, err := svc.ScoreMorale(ctx, asOf)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Scored %d resident(s): happiness index %d (%s), %d at low morale\n",
+			snapshot.Population, snapshot.Index, snapshot.Level(), snapshot.Low)
+		return nil
+	case "index":
+		fs := flag.NewFlagSet("index", flag.ContinueOnError)
+		limit := fs.Int("limit", 10, "Lowest residents to list")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		history, err := svc.MoraleHistory(ctx, 1)
+		if err != nil {
+			return err
+		}
+		if len(history) == 0 {
+			fmt.Println("Morale has not been scored; run `vtuos morale score`")
+			return nil
+		}
+		current := history[0]
+		fmt.Printf("Happiness index %d (%s) on %s: %d of %d resident(s) at low morale\n",
+			current.Index, current.Level(), util.FormatDate(current.TakenAt), current.Low, current.Population)
+
+		sectors, err := svc.SectorMorale(ctx)
+		if err != nil {
+			return err
+		}
+		for _, s := range sectors {
+			fmt.Printf("  Sector %-8s %4d resident(s)  index %3d\n", s.Sector, s.Residents, s.Index)
+		}
+
+		lowest, err := svc.LowestMorale(ctx, *limit)
+		if err != nil {
+			return err
+		}
+		if len(lowest) > 0 {
+			fmt.Println("Lowest morale:")
+		}
+		for _, m := range lowest {
+			resident, err := svc.GetResident(ctx, m.ResidentID)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("  %-12s %-28s %3d %-8s  ration %+d  crowding %+d  incidents %+d  workload %+d  activities %+d\n",
+				resident.RegistryNumber, resident.FullName(), m.Score, m.Level,
+				m.RationEffect, m.CrowdingEffect, m.IncidentEffect, m.WorkloadEffect, m.ActivityEffect)
+		}
+		return nil
+	case "show":
+		if len(args) != 2 {
+			return fmt.Errorf("morale show requires a registry number")
+		}
+		resident, err := svc.GetResidentByRegistryNumber(ctx, args[1])
+		if err != nil {
+			return fmt.Errorf("resident %s: %w", args[1], err)
+		}
+		m, err := svc.GetResidentMorale(ctx, resident.ID)
+		if errors.Is(err, repository.ErrNotFound) {
+			fmt.Printf("%s %s has not been scored\n", resident.RegistryNumber, resident.FullName())
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s %s: morale %d (%s) as of %s\n",
+			resident.RegistryNumber, resident.FullName(), m.Score, m.Level, util.FormatDate(m.ScoredAt))
+		fmt.Printf("  Ration class    %+d\n", m.RationEffect)
+		fmt.Printf("  Crowding        %+d\n", m.CrowdingEffect)
+		fmt.Printf("  Incidents       %+d\n", m.IncidentEffect)
+		fmt.Printf("  Workload        %+d\n", m.WorkloadEffect)
+		fmt.Printf("  Activities      %+d\n", m.ActivityEffect)
+		return nil
+	default:
+		return fmt.Errorf("unknown morale subcommand: %s", args[0])
+	}
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/config"
+	"github.com/vtuos/vtuos/internal/database"
+	"github.com/vtuos/vtuos/internal/services/medical"
+	"github.com/vtuos/vtuos/internal/services/population"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// runRadiationCommand handles `vtuos radiation <subcommand>`: record
+// exposures and decontamination treatments of residents given by registry
+// number, show a resident's dose history and list flagged residents.
+func runRadiationCommand(ctx context.Context, configPath, profile string, args []string) error {
+	if len(args) == 0 {
+		flag.Usage()
+		return fmt.Errorf("radiation requires a subcommand: expose, treat, show or flagged")
+	}
+
+	cfg, err := loadConfig(configPath, profile)
+	if err != nil {
+		return fmt.Errorf("loading configuration: %w", err)
+	}
+
+	dbPath, err := config.EnsureDataDir(cfg)
+	if err != nil {
+		return fmt.Errorf("ensuring data directory: %w", err)
+	}
+	backupDir, err := config.BackupDir(cfg)
+	if err != nil {
+		return fmt.Errorf("getting backup directory: %w", err)
+	}
+
+	db, err := database.Open(dbPath, &cfg.Database, backupDir)
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	j, err := openJournal(cfg, nil)
+	if err != nil {
+		return err
+	}
+	defer j.Close()
+
+	svc := medical.NewService(db.DB)
+	svc.SetVault(cfg.Vault.Number)
+	svc.SetJournal(j)
+	popSvc := population.NewService(db.DB, cfg.Vault.Number)
+
+	switch args[0] {
+	case "expose":
+		fs := flag.NewFlagSet("expose", flag.ContinueOnError)
+		source := fs.String("source", "", "What the resident was exposed to, e.g. \"Reactor coolant leak\"")
+		date := fs.String("date", "", "Exposure date YYYY-MM-DD (default: now)")
+		note := fs.String("note", "", "Note recorded with the exposure")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if fs.NArg() != 2 {
+			return fmt.Errorf("radiation expose requires a registry number and a dose in mSv")
+		}
+		dose, err := strconv.ParseFloat(fs.Arg(1), 64)
+		if err != nil {
+			return fmt.Errorf("invalid dose %q: %w", fs.Arg(1), err)
+		}
+		resident, err := popSvc.GetResidentByRegistryNumber(ctx, fs.Arg(0))
+		if err != nil {
+			return fmt.Errorf("resident %s: %w", fs.Arg(0), err)
+		}
+
+		input := medical.ExposureInput{ResidentID: resident.ID, Source: *source, DoseMSv: dose, Notes: *note}
+		if *date != "" {
+			if input.Date, err = time.Parse(time.DateOnly, *date); err != nil {
+				return fmt.Errorf("invalid --date: %w", err)
+			}
+		}
+		_, change, err := svc.RecordExposure(ctx, input)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Recorded %.1f mSv for %s %s: cumulative %.1f mSv (%s)\n", dose,
+			resident.RegistryNumber, resident.FullName(), change.Dose.CumulativeMSv(), change.Dose.Level)
+		if change.Escalated() {
+			fmt.Printf("WARNING: dose raised from %s to %s; medical condition %s opened\n",
+				change.Previous, change.Dose.Level, change.Flag.ID)
+		}
+		return nil
+	case "treat":
+		fs := flag.NewFlagSet("treat", flag.ContinueOnError)
+		units := fs.Float64("units", 1, "Units of the treatment item administered")
+		item := fs.String("item", medical.RadAwayItemCode, "Resource item code of the treatment")
+		note := fs.String("note", "", "Note recorded with the treatment")
+		reg, err := parseWithName(fs, args[1:])
+		if err != nil {
+			return err
+		}
+		if reg == "" {
+			return fmt.Errorf("radiation treat requires a registry number")
+		}
+		resident, err := popSvc.GetResidentByRegistryNumber(ctx, reg)
+		if err != nil {
+			return fmt.Errorf("resident %s: %w", reg, err)
+		}
+
+		treatment, change, err := svc.Decontaminate(ctx, medical.TreatmentInput{
+			ResidentID: resident.ID,
+			ItemCode:   *item,
+			Quantity:   *units,
+			Notes:      *note,
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Treated %s %s with %.0f unit(s) of %s: purged %.1f mSv, cumulative %.1f mSv (%s)\n",
+			resident.RegistryNumber, resident.FullName(), treatment.Quantity, *item,
+			treatment.DoseReducedMSv, change.Dose.CumulativeMSv(), change.Dose.Level)
+		return nil
+	case "show":
+		if len(args) != 2 {
+			return fmt.Errorf("radiation show requires a registry number")
+		}
+		resident, err := popSvc.GetResidentByRegistryNumber(ctx, args[1])
+		if err != nil {
+			return fmt.Errorf("resident %s: %w", args[1], err)
+		}
+		dose, err := svc.GetDose(ctx, resident.ID)
+		if err != nil {
+			return fmt.Errorf("getting dose: %w", err)
+		}
+		exposures, err := svc.ListExposures(ctx, resident.ID)
+		if err != nil {
+			return fmt.Errorf("listing exposures: %w", err)
+		}
+		treatments, err := svc.ListTreatments(ctx, resident.ID)
+		if err != nil {
+			return fmt.Errorf("listing treatments: %w", err)
+		}
+
+		fmt.Printf("%s %s: cumulative %.1f mSv (%s), %.1f exposed, %.1f purged\n",
+			resident.RegistryNumber, resident.FullName(), dose.CumulativeMSv(), dose.Level,
+			dose.ExposedMSv, dose.PurgedMSv)
+		for _, e := range exposures {
+			fmt.Printf("  %s  exposure  %8.1f mSv  %s\n", util.FormatDate(e.ExposureDate), e.DoseMSv, e.Source)
+		}
+		for _, t := range treatments {
+			fmt.Printf("  %s  treatment %8.1f mSv  %.0f unit(s)\n", util.FormatDate(t.TreatmentDate), -t.DoseReducedMSv, t.Quantity)
+		}
+		return nil
+	case "flagged":
+		doses, err := svc.ListFlagged(ctx)
+		if err != nil {
+			return fmt.Errorf("listing flagged residents: %w", err)
+		}
+		if len(doses) == 0 {
+			fmt.Println("No residents above the radiation flag threshold")
+			return nil
+		}
+		for _, d := range doses {
+			last := "-"
+			if d.LastExposure != nil {
+				last = util.FormatDate(*d.LastExposure)
+			}
+			fmt.Printf("%-12s %-28s %9.1f mSv  %-9s last exposed %s\n",
+				d.Resident.RegistryNumber, d.Resident.FullName(), d.CumulativeMSv(), d.Level, last)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown radiation subcommand: %s", args[0])
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/config"
+	"github.com/vtuos/vtuos/internal/database"
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/services/population"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// runRelationshipCommand handles `vtuos relationship <subcommand>`: record
+// marriages, partnerships, guardianships and next-of-kin designations
+// between residents given by registry number, list them and end them.
+func runRelationshipCommand(ctx context.Context, configPath, profile string, args []string) error {
+	if len(args) == 0 {
+		flag.Usage()
+		return fmt.Errorf("relationship requires a subcommand: add, list or end")
+	}
+
+	cfg, err := loadConfig(configPath, profile)
+	if err != nil {
+		return fmt.Errorf("loading configuration: %w", err)
+	}
+
+	dbPath, err := config.EnsureDataDir(cfg)
+	if err != nil {
+		return fmt.Errorf("ensuring data directory: %w", err)
+	}
+	backupDir, err := config.BackupDir(cfg)
+	if err != nil {
+		return fmt.Errorf("getting backup directory: %w", err)
+	}
+
+	db, err := database.Open(dbPath, &cfg.Database, backupDir)
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	j, err := openJournal(cfg, nil)
+	if err != nil {
+		return err
+	}
+	defer j.Close()
+
+	svc := population.NewService(db.DB, cfg.Vault.Number)
+	svc.SetJournal(j)
+
+	switch args[0] {
+	case "add":
+		fs := flag.NewFlagSet("add", flag.ContinueOnError)
+		since := fs.String("since", "", "Start date YYYY-MM-DD (default: today)")
+		note := fs.String("note", "", "Note recorded with the relationship")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if fs.NArg() != 3 {
+			return fmt.Errorf("relationship add requires a type and two registry numbers")
+		}
+		relType := models.RelationshipType(strings.ToUpper(strings.ReplaceAll(fs.Arg(0), "-", "_")))
+		if !relType.Valid() {
+			return fmt.Errorf("invalid relationship type %q: use marriage, partnership, guardianship or next-of-kin", fs.Arg(0))
+		}
+
+		input := population.RelationshipInput{Type: relType, Notes: *note}
+		if *since != "" {
+			if input.StartDate, err = time.Parse(time.DateOnly, *since); err != nil {
+				return fmt.Errorf("invalid --since date: %w", err)
+			}
+		}
+		resident, err := svc.GetResidentByRegistryNumber(ctx, fs.Arg(1))
+		if err != nil {
+			return fmt.Errorf("resident %s: %w", fs.Arg(1), err)
+		}
+		related, err := svc.GetResidentByRegistryNumber(ctx, fs.Arg(2))
+		if err != nil {
+			return fmt.Errorf("resident %s: %w", fs.Arg(2), err)
+		}
+		input.ResidentID, input.RelatedID = resident.ID, related.ID
+
+		rel, err := svc.RecordRelationship(ctx, input)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Recorded %s %s: %s %s and %s %s\n", rel.Type, rel.ID,
+			resident.RegistryNumber, resident.FullName(), related.RegistryNumber, related.FullName())
+		return nil
+	case "list":
+		if len(args) != 2 {
+			return fmt.Errorf("relationship list requires a registry number")
+		}
+		resident, err := svc.GetResidentByRegistryNumber(ctx, args[1])
+		if err != nil {
+			return fmt.Errorf("resident %s: %w", args[1], err)
+		}
+		rels, err := svc.GetRelationships(ctx, resident.ID)
+		if err != nil {
+			return fmt.Errorf("listing relationships: %w", err)
+		}
+		if len(rels) == 0 {
+			fmt.Printf("No relationships recorded for %s\n", resident.RegistryNumber)
+			return nil
+		}
+		for _, rel := range rels {
+			period := "since " + util.FormatDate(rel.StartDate)
+			if !rel.IsActive() {
+				period = fmt.Sprintf("%s to %s", util.FormatDate(rel.StartDate), util.FormatDate(*rel.EndDate))
+			}
+			fmt.Printf("%-38s %-15s %-12s %-28s %s\n",
+				rel.ID, rel.Role, rel.Other.RegistryNumber, rel.Other.FullName(), period)
+		}
+		return nil
+	case "end":
+		fs := flag.NewFlagSet("end", flag.ContinueOnError)
+		reason := fs.String("reason", "", "Why the relationship ended, e.g. Divorce")
+		id, err := parseWithName(fs, args[1:])
+		if err != nil {
+			return err
+		}
+		if id == "" {
+			return fmt.Errorf("relationship end requires a relationship ID")
+		}
+		if err := svc.EndRelationship(ctx, id, *reason); err != nil {
+			return err
+		}
+		fmt.Printf("Ended relationship %s\n", id)
+		return nil
+	default:
+		return fmt.Errorf("unknown relationship subcommand: %s", args[0])
+	}
+}
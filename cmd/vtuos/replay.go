@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/config"
+	"github.com/vtuos/vtuos/internal/database"
+	"github.com/vtuos/vtuos/internal/database/seed"
+	"github.com/vtuos/vtuos/internal/journal"
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/services/facilities"
+	"github.com/vtuos/vtuos/internal/services/governance"
+	"github.com/vtuos/vtuos/internal/services/inspections"
+	"github.com/vtuos/vtuos/internal/services/medical"
+	"github.com/vtuos/vtuos/internal/services/population"
+	"github.com/vtuos/vtuos/internal/services/resources"
+	"github.com/vtuos/vtuos/internal/services/security"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// openJournal opens the command journal configured in simulation.journal,
+// stamping entries with the vault time of clock, or wall time when clock is
+// nil. It returns a nil journal, which records nothing, when none is
+// configured or the database is read-only.
+func openJournal(cfg *config.Config, clock *util.VaultClock) (*journal.Journal, error) {
+	if cfg.Simulation.Journal == "" || cfg.Database.ReadOnly {
+		return nil, nil
+	}
+	now := func() time.Time { return time.Now().UTC() }
+	if clock != nil {
+		now = clock.Now
+	}
+	j, err := journal.Create(cfg.Simulation.Journal, now)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("journaling commands", "path", cfg.Simulation.Journal)
+	return j, nil
+}
+
+// runReplayCommand handles `vtuos replay JOURNAL`: run the journaled
+// commands again, in order and at their vault times, against a fresh
+// database, and report every command whose outcome differs from the one
+// recorded. The database is kept for inspection.
+func runReplayCommand(ctx context.Context, configPath, profile string, args []string) error {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	dbPath := fs.String("db", "", "Database to replay into; must not exist (default: a new temporary file)")
+	quiet := fs.Bool("quiet", false, "Only report commands that diverge")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("replay requires a journal file")
+	}
+
+	cfg, err := loadConfig(configPath, profile)
+	if err != nil {
+		return fmt.Errorf("loading configuration: %w", err)
+	}
+
+	in, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("opening journal: %w", err)
+	}
+	defer in.Close()
+
+	if *dbPath == "" {
+		dir, err := os.MkdirTemp("", "vtuos-replay-")
+		if err != nil {
+			return fmt.Errorf("creating replay directory: %w", err)
+		}
+		*dbPath = filepath.Join(dir, "vault.db")
+	} else if _, err := os.Stat(*dbPath); err == nil {
+		return fmt.Errorf("replay database %s already exists", *dbPath)
+	}
+
+	cfg.Database.ReadOnly = false
+	db, err := database.Open(*dbPath, &cfg.Database, "")
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		return fmt.Errorf("creating migrator: %w", err)
+	}
+	migrator.SetBackupBeforeMigrate(false)
+	if _, err := migrator.MigrateUp(ctx); err != nil {
+		return fmt.Errorf("running migrations: %w", err)
+	}
+
+	// The clock stands still at each command's vault time
+	clock := util.NewVaultClock(time.Time{}, 0)
+	clock.Pause()
+
+	inspSvc := inspections.NewService(db.DB)
+	inspSvc.SetClock(clock)
+
+	replayer := journal.NewReplayer(clock, func(vault int) (journal.Handlers, error) {
+		return replayHandlers(db, cfg, clock, inspSvc, vault), nil
+	})
+
+	fmt.Printf("Replaying %s into %s\n", fs.Arg(0), *dbPath)
+	summary, err := replayer.Replay(ctx, in, func(o journal.Outcome) {
+		if *quiet && !o.Diverged() {
+			return
+		}
+		e := o.Entry
+		result := "ok"
+		if o.Err != nil {
+			result = "error: " + o.Err.Error()
+		}
+		fmt.Printf("%5d  %s  %-40s %s\n", e.Seq, util.FormatDateTime(e.VaultTime), e.Command, result)
+		if o.Diverged() {
+			fmt.Printf("       DIVERGED: %s\n", o.Diff)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("replaying journal: %w", err)
+	}
+
+	fmt.Printf("\n%d command(s) replayed, %d failed, %d diverged\n", summary.Commands, summary.Failed, summary.Diverged)
+	if summary.Diverged > 0 {
+		return errors.New("replay diverged from the journal")
+	}
+	return nil
+}
+
+// replayHandlers returns the command handlers of the services of a vault,
+// set up as a terminal administering it would set them up, with those of
+// the inspection service and seed generation, which cover every vault.
+func replayHandlers(db *database.DB, cfg *config.Config, clock *util.VaultClock, inspSvc *inspections.Service, vault int) journal.Handlers {
+	popSvc := population.NewService(db.DB, vault)
+	popSvc.SetClock(clock)
+	popSvc.SetRegistryFormat(cfg.RegistryFormat(vault))
+
+	resSvc := resources.NewService(db.DB)
+	resSvc.SetVault(vault)
+	resSvc.SetClock(clock)
+	if cfg.Simulation.Consumption.Policy == config.ConsumptionPolicyFIFO {
+		resSvc.SetConsumptionPolicy(models.ConsumptionPolicyFIFO)
+	}
+
+	facSvc := facilities.NewService(db.DB)
+	facSvc.SetVault(vault)
+
+	govSvc := governance.NewService(db.DB, vault)
+	govSvc.SetClock(clock)
+
+	medSvc := medical.NewService(db.DB)
+	medSvc.SetVault(vault)
+	medSvc.SetClock(clock)
+
+	secSvc := security.NewService(db.DB)
+	secSvc.SetVault(vault)
+	secSvc.SetClock(clock)
+
+	handlers := journal.Handlers{}
+	for _, h := range []journal.Handlers{
+		popSvc.Commands(),
+		resSvc.Commands(),
+		facSvc.Commands(),
+		govSvc.Commands(),
+		medSvc.Commands(),
+		secSvc.Commands(),
+		inspSvc.Commands(),
+		seed.Commands(db.DB),
+	} {
+		for command, handler := range h {
+			handlers[command] = handler
+		}
+	}
+	return handlers
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/config"
+	"github.com/vtuos/vtuos/internal/database"
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/services/governance"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// runSessionsCommand handles `vtuos sessions`: summarize who used the
+// vault's terminals over the last days, with each operator's sessions,
+// time signed on, changes made and modules used, then list the sessions.
+func runSessionsCommand(ctx context.Context, configPath, profile string, args []string) error {
+	fs := flag.NewFlagSet("sessions", flag.ContinueOnError)
+	days := fs.Int("days", models.DefaultSessionDays, "Days of sessions to cover")
+	list := fs.Bool("list", false, "List every session after the summary")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig(configPath, profile)
+	if err != nil {
+		return fmt.Errorf("loading configuration: %w", err)
+	}
+
+	dbPath, err := config.EnsureDataDir(cfg)
+	if err != nil {
+		return fmt.Errorf("ensuring data directory: %w", err)
+	}
+	backupDir, err := config.BackupDir(cfg)
+	if err != nil {
+		return fmt.Errorf("getting backup directory: %w", err)
+	}
+
+	db, err := database.Open(dbPath, &cfg.Database, backupDir)
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	svc := governance.NewService(db.DB, cfg.Vault.Number)
+	report, err := svc.SessionActivity(ctx, *days, "")
+	if err != nil {
+		return err
+	}
+	if len(report.Sessions) == 0 {
+		fmt.Printf("No terminal sessions in the last %d day(s)\n", report.Days)
+		return nil
+	}
+
+	fmt.Printf("Terminal sessions active since %s (%d days)\n\n", util.FormatDateTime(report.Since), report.Days)
+	fmt.Printf("%-12s %-24s %8s %9s %7s %8s %-19s %s\n",
+		"REGISTRY", "NAME", "SESSIONS", "DURATION", "CHANGES", "UNCLOSED", "LAST ACTIVE", "MODULES")
+	for _, op := range report.Operators {
+		registry, name := op.Registry, op.Name
+		if op.OperatorID == nil {
+			registry, name = "-", "(no operator)"
+		} else if registry == "" {
+			registry = "(deleted)"
+		}
+		fmt.Printf("%-12s %-24s %8d %9s %7d %8d %-19s %s\n",
+			registry, name, op.Sessions, formatDuration(op.Duration), op.Mutations, op.Unclosed,
+			util.FormatDateTime(op.LastActive), strings.Join(op.Modules, ","))
+	}
+
+	if *list {
+		fmt.Printf("\n%-19s %-19s %-12s %9s %7s %s\n", "STARTED", "ENDED", "OPERATOR", "DURATION", "CHANGES", "MODULES")
+		for _, s := range report.Sessions {
+			ended := "never closed"
+			if s.EndedAt != nil {
+				ended = util.FormatDateTime(*s.EndedAt)
+			}
+			operator := s.OperatorRegistry
+			if s.OperatorID == nil {
+				operator = "-"
+			}
+			fmt.Printf("%-19s %-19s %-12s %9s %7d %s\n",
+				util.FormatDateTime(s.StartedAt), ended, operator, formatDuration(s.Duration()),
+				s.Mutations, strings.Join(s.Modules, ","))
+		}
+	}
+	return nil
+}
+
+// formatDuration formats a length of time in hours and minutes.
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
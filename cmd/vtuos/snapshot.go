@@ -0,0 +1,218 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/config"
+	"github.com/vtuos/vtuos/internal/database"
+	"github.com/vtuos/vtuos/internal/services/population"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// runSnapshotCommand handles `vtuos snapshot <subcommand>`.
+func runSnapshotCommand(ctx context.Context, configPath, profile string, args []string) error {
+	if len(args) == 0 {
+		flag.Usage()
+		return fmt.Errorf("snapshot requires a subcommand: create, list or restore")
+	}
+
+	cfg, err := loadConfig(configPath, profile)
+	if err != nil {
+		return fmt.Errorf("loading configuration: %w", err)
+	}
+
+	dbPath, err := config.EnsureDataDir(cfg)
+	if err != nil {
+		return fmt.Errorf("ensuring data directory: %w", err)
+	}
+	snapshotDir, err := config.SnapshotDir(cfg)
+	if err != nil {
+		return fmt.Errorf("getting snapshot directory: %w", err)
+	}
+
+	switch args[0] {
+	case "create":
+		fs := flag.NewFlagSet("create", flag.ContinueOnError)
+		note := fs.String("note", "", "Why the snapshot was taken, e.g. the policy change it precedes")
+		asOfStr := fs.String("as-of", "", "Vault date to record (default: simulation start date)")
+		name, err := parseWithName(fs, args[1:])
+		if err != nil {
+			return err
+		}
+		if name == "" {
+			name = "snapshot-" + time.Now().Format("20060102-150405")
+		}
+
+		vaultTime, err := cfg.Simulation.StartDateTime()
+		if err != nil {
+			vaultTime = time.Now().UTC()
+		}
+		if *asOfStr != "" {
+			vaultTime, err = util.ParseDate(*asOfStr)
+			if err != nil {
+				return fmt.Errorf("invalid --as-of date: %s", *asOfStr)
+			}
+		}
+		return snapshotCreate(ctx, cfg, dbPath, snapshotDir, database.SnapshotInfo{
+			Name:      name,
+			Note:      *note,
+			VaultTime: vaultTime,
+		})
+	case "list":
+		return snapshotList(snapshotDir)
+	case "restore":
+		fs := flag.NewFlagSet("restore", flag.ContinueOnError)
+		yes := fs.Bool("yes", false, "Restore without asking for confirmation")
+		name, err := parseWithName(fs, args[1:])
+		if err != nil {
+			return err
+		}
+		if name == "" {
+			return fmt.Errorf("snapshot restore requires a snapshot name")
+		}
+		return snapshotRestore(dbPath, snapshotDir, name, *yes, os.Stdin)
+	default:
+		return fmt.Errorf("unknown snapshot subcommand: %s", args[0])
+	}
+}
+
+// parseWithName parses flags given before or after a single name argument.
+func parseWithName(fs *flag.FlagSet, args []string) (string, error) {
+	if err := fs.Parse(args); err != nil {
+		return "", err
+	}
+	name := fs.Arg(0)
+	if fs.NArg() > 0 {
+		if err := fs.Parse(fs.Args()[1:]); err != nil {
+			return "", err
+		}
+	}
+	if fs.NArg() > 0 {
+		return "", fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+	return name, nil
+}
+
+// snapshotCreate takes a snapshot of the vault database, recording the
+// active population with it.
+func snapshotCreate(ctx context.Context, cfg *config.Config, dbPath, snapshotDir string, info database.SnapshotInfo) error {
+	if _, err := os.Stat(dbPath); err != nil {
+		return fmt.Errorf("database not found: %s", dbPath)
+	}
+
+	db, err := database.Open(dbPath, &cfg.Database, "")
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	stats, err := population.NewService(db.DB, cfg.Vault.Number).GetPopulationStats(ctx)
+	if err != nil {
+		return fmt.Errorf("counting population: %w", err)
+	}
+	info.Population = stats.TotalActive
+
+	snap, err := db.CreateSnapshot(ctx, snapshotDir, info)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Created snapshot %s (%s)\n", snap.Name, snap.Path)
+	printSnapshot(snap)
+	return nil
+}
+
+// snapshotList prints every snapshot, oldest first.
+func snapshotList(snapshotDir string) error {
+	snaps, err := database.ListSnapshots(snapshotDir)
+	if err != nil {
+		return err
+	}
+	if len(snaps) == 0 {
+		fmt.Println("No snapshots")
+		return nil
+	}
+
+	fmt.Printf("%-28s %-19s %-10s %6s %6s %9s  %s\n", "NAME", "CREATED", "VAULT DATE", "POP", "SCHEMA", "SIZE", "NOTE")
+	for _, s := range snaps {
+		fmt.Printf("%-28s %-19s %-10s %6d %6d %9s  %s\n",
+			s.Name,
+			s.CreatedAt.Local().Format("2006-01-02 15:04:05"),
+			util.FormatDay(s.VaultTime),
+			s.Population,
+			s.SchemaVersion,
+			formatSize(s.SizeBytes),
+			s.Note,
+		)
+	}
+	return nil
+}
+
+// snapshotRestore replaces the vault database with a snapshot after the
+// operator confirms on in.
+func snapshotRestore(dbPath, snapshotDir, name string, yes bool, in io.Reader) error {
+	snap, err := database.GetSnapshot(snapshotDir, name)
+	if err != nil {
+		if errors.Is(err, database.ErrSnapshotNotFound) {
+			return fmt.Errorf("no snapshot named %s; see vtuos snapshot list", name)
+		}
+		return err
+	}
+
+	fmt.Printf("Snapshot %s\n", snap.Name)
+	printSnapshot(snap)
+	fmt.Printf("\nThis replaces %s with the snapshot. The current database is kept\n", dbPath)
+	fmt.Printf("alongside it, but changes since the snapshot will no longer be in use.\n")
+	fmt.Printf("Stop every VT-UOS terminal using this database before restoring.\n")
+
+	if !yes {
+		fmt.Printf("\nType the snapshot name to confirm: ")
+		answer, _ := bufio.NewReader(in).ReadString('\n')
+		if strings.TrimSpace(answer) != snap.Name {
+			return errors.New("restore cancelled")
+		}
+	}
+
+	_, preserved, err := database.RestoreSnapshot(dbPath, snapshotDir, name)
+	if err != nil {
+		return fmt.Errorf("restoring snapshot: %w", err)
+	}
+
+	fmt.Printf("Restored snapshot %s\n", snap.Name)
+	fmt.Printf("Previous database preserved as %s\n", preserved)
+	return nil
+}
+
+// printSnapshot prints the vault state recorded with a snapshot.
+func printSnapshot(s *database.Snapshot) {
+	fmt.Printf("  Created:    %s\n", s.CreatedAt.Local().Format("2006-01-02 15:04:05"))
+	fmt.Printf("  Vault date: %s\n", util.FormatDay(s.VaultTime))
+	fmt.Printf("  Population: %d\n", s.Population)
+	fmt.Printf("  Schema:     version %d\n", s.SchemaVersion)
+	fmt.Printf("  Size:       %s\n", formatSize(s.SizeBytes))
+	if s.Note != "" {
+		fmt.Printf("  Note:       %s\n", s.Note)
+	}
+}
+
+// formatSize formats a byte count, e.g. "2.4 MB".
+func formatSize(n int64) string {
+	switch {
+	case n >= 1<<30:
+		return fmt.Sprintf("%.1f GB", float64(n)/(1<<30))
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/vtuos/vtuos/internal/config"
+	"github.com/vtuos/vtuos/internal/database"
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/services/population"
+)
+
+// runTagsCommand handles `vtuos tags <subcommand>`: tag residents given by
+// registry number and take tags off them, list the tags in use and who
+// carries them, and list the saved census presets.
+func runTagsCommand(ctx context.Context, configPath, profile string, args []string) error {
+	if len(args) == 0 {
+		flag.Usage()
+		return fmt.Errorf("tags requires a subcommand: add, remove, list, show or presets")
+	}
+
+	cfg, err := loadConfig(configPath, profile)
+	if err != nil {
+		return fmt.Errorf("loading configuration: %w", err)
+	}
+
+	dbPath, err := config.EnsureDataDir(cfg)
+	if err != nil {
+		return fmt.Errorf("ensuring data directory: %w", err)
+	}
+	backupDir, err := config.BackupDir(cfg)
+	if err != nil {
+		return fmt.Errorf("getting backup directory: %w", err)
+	}
+
+	db, err := database.Open(dbPath, &cfg.Database, backupDir)
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	j, err := openJournal(cfg, nil)
+	if err != nil {
+		return err
+	}
+	defer j.Close()
+
+	svc := population.NewService(db.DB, cfg.Vault.Number)
+	svc.SetJournal(j)
+
+	resident := func(regNum string) (*models.Resident, error) {
+		r, err := svc.GetResidentByRegistryNumber(ctx, strings.ToUpper(regNum))
+		if err != nil {
+			return nil, fmt.Errorf("resident %s: %w", regNum, err)
+		}
+		return r, nil
+	}
+
+	switch args[0] {
+	case "add", "remove":
+		if len(args) < 3 {
+			return fmt.Errorf("tags %s requires a registry number and at least one tag", args[0])
+		}
+		r, err := resident(args[1])
+		if err != nil {
+			return err
+		}
+		var add, remove []string
+		if args[0] == "add" {
+			add = args[2:]
+		} else {
+			remove = args[2:]
+		}
+		if err := svc.TagResidents(ctx, []string{r.ID}, add, remove); err != nil {
+			return err
+		}
+		tags, err := svc.GetResidentTags(ctx, r.ID)
+		if err != nil {
+			return fmt.Errorf("listing tags: %w", err)
+		}
+		fmt.Printf("%s %s: %s\n", r.RegistryNumber, r.FullName(), formatTags(tags))
+		return nil
+	case "show":
+		if len(args) != 2 {
+			return fmt.Errorf("tags show requires a registry number")
+		}
+		r, err := resident(args[1])
+		if err != nil {
+			return err
+		}
+		tags, err := svc.GetResidentTags(ctx, r.ID)
+		if err != nil {
+			return fmt.Errorf("listing tags: %w", err)
+		}
+		fmt.Printf("%s %s: %s\n", r.RegistryNumber, r.FullName(), formatTags(tags))
+		return nil
+	case "list":
+		if len(args) == 1 {
+			counts, err := svc.ListTags(ctx)
+			if err != nil {
+				return fmt.Errorf("listing tags: %w", err)
+			}
+			if len(counts) == 0 {
+				fmt.Println("No residents are tagged")
+				return nil
+			}
+			for _, c := range counts {
+				fmt.Printf("%-40s %5d\n", c.Tag, c.Residents)
+			}
+			return nil
+		}
+		tags, err := models.ParseTags(strings.Join(args[1:], ","))
+		if err != nil {
+			return err
+		}
+		filter := models.ResidentFilter{Tags: tags}
+		page := models.Pagination{Page: 1, PageSize: 100}
+		for {
+			list, err := svc.ListResidents(ctx, filter, page)
+			if err != nil {
+				return fmt.Errorf("listing residents: %w", err)
+			}
+			if list.Total == 0 {
+				fmt.Printf("No residents tagged %s\n", strings.Join(tags, ", "))
+				return nil
+			}
+			for _, r := range list.Residents {
+				fmt.Printf("%-12s %-40s %s\n", r.RegistryNumber, r.FullName(), r.Status)
+			}
+			if list.NextCursor == "" {
+				return nil
+			}
+			page.After = list.NextCursor
+			page.Page++
+		}
+	case "presets":
+		presets, err := svc.ListCensusPresets(ctx)
+		if err != nil {
+			return fmt.Errorf("listing census presets: %w", err)
+		}
+		if len(presets) == 0 {
+			fmt.Println("No census presets")
+			return nil
+		}
+		for _, p := range presets {
+			fmt.Printf("%-20s %s\n", p.Name, p.Describe())
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown tags subcommand: %s", args[0])
+	}
+}
+
+// formatTags joins tags for printing, or returns "(none)".
+func formatTags(tags []string) string {
+	if len(tags) == 0 {
+		return "(none)"
+	}
+	return strings.Join(tags, ", ")
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/vtuos/vtuos/internal/config"
+	"github.com/vtuos/vtuos/internal/database"
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/services/population"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// runTransferCommand handles `vtuos transfer`: write a signed bundle of a
+// resident, with their household and medical history if asked, for
+// another vault, and admit the residents of a bundle from one. Bundles
+// are signed and checked with [transfer] key.
+func runTransferCommand(ctx context.Context, configPath, profile string, args []string) error {
+	if len(args) == 0 || (args[0] != "export" && args[0] != "import") {
+		flag.Usage()
+		return fmt.Errorf("transfer requires a subcommand: export or import")
+	}
+
+	fs := flag.NewFlagSet("transfer "+args[0], flag.ContinueOnError)
+	household := fs.Bool("household", false, "Transfer every living member of the resident's household with them")
+	medical := fs.Bool("medical", false, "Include medical conditions and radiation exposures")
+	out := fs.String("out", "", "File to write the bundle to (default: transfer-<registry number>.json)")
+	operator := fs.String("operator", "", "Registry number of the operator making the transfer, for the PII clearance")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		if args[0] == "export" {
+			return fmt.Errorf("transfer export requires a registry number")
+		}
+		return fmt.Errorf("transfer import requires a bundle file")
+	}
+
+	cfg, err := loadConfig(configPath, profile)
+	if err != nil {
+		return fmt.Errorf("loading configuration: %w", err)
+	}
+	if cfg.Transfer.Key == "" {
+		return fmt.Errorf("no transfer key configured; set [transfer] key to the secret shared with the other vault")
+	}
+
+	dbPath, err := config.EnsureDataDir(cfg)
+	if err != nil {
+		return fmt.Errorf("ensuring data directory: %w", err)
+	}
+	backupDir, err := config.BackupDir(cfg)
+	if err != nil {
+		return fmt.Errorf("getting backup directory: %w", err)
+	}
+
+	db, err := database.Open(dbPath, &cfg.Database, backupDir)
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	j, err := openJournal(cfg, nil)
+	if err != nil {
+		return err
+	}
+	defer j.Close()
+
+	svc := population.NewService(db.DB, cfg.Vault.Number)
+	svc.SetJournal(j)
+	svc.SetRegistryFormat(cfg.RegistryFormat(cfg.Vault.Number))
+	svc.SetPIIClearance(cfg.Privacy.PIIClearance)
+	if startTime, err := cfg.Simulation.StartDateTime(); err == nil {
+		svc.SetClock(util.NewVaultClock(startTime, 0))
+	}
+
+	if args[0] == "import" {
+		data, err := os.ReadFile(fs.Arg(0))
+		if err != nil {
+			return fmt.Errorf("reading transfer bundle: %w", err)
+		}
+		bundle, err := population.OpenTransfer(data, cfg.Transfer.Key)
+		if err != nil {
+			return err
+		}
+		result, err := svc.ImportTransfer(ctx, bundle)
+		if err != nil {
+			return fmt.Errorf("admitting transfer from Vault %03d: %w", bundle.SourceVault, err)
+		}
+		for _, regNum := range result.Duplicates {
+			fmt.Printf("  %-12s already registered here, skipped\n", regNum)
+		}
+		for i, r := range result.Admitted {
+			fmt.Printf("  %-12s admitted as %s  %s, in quarantine until %s\n", result.From[i], r.RegistryNumber,
+				r.FullName(), util.FormatDate(result.Intakes[i].QuarantineEnd))
+		}
+		if result.Household != nil {
+			fmt.Printf("Household %s formed\n", result.Household.Designation)
+		}
+		fmt.Printf("Admitted %d resident(s) from Vault %03d, %d duplicate(s) skipped\n",
+			len(result.Admitted), bundle.SourceVault, len(result.Duplicates))
+		return nil
+	}
+
+	regNum := strings.ToUpper(fs.Arg(0))
+	if *operator != "" {
+		opNum := strings.ToUpper(*operator)
+		op, err := svc.GetResidentByRegistryNumber(ctx, opNum)
+		if err != nil {
+			return fmt.Errorf("operator %s: %w", opNum, err)
+		}
+		if op.Status != models.ResidentStatusActive {
+			return fmt.Errorf("operator %s is %s", opNum, op.Status)
+		}
+		ctx = util.WithOperatorClearance(ctx, op.ClearanceLevel)
+	}
+	bundle, err := svc.ExportTransfer(ctx, regNum, population.TransferOptions{Household: *household, Medical: *medical})
+	if err != nil {
+		return fmt.Errorf("resident %s: %w", regNum, err)
+	}
+	data, err := population.SealTransfer(bundle, cfg.Transfer.Key)
+	if err != nil {
+		return err
+	}
+	if *out == "" {
+		*out = fmt.Sprintf("transfer-%s.json", regNum)
+	}
+	if err := os.WriteFile(*out, data, 0600); err != nil {
+		return fmt.Errorf("writing transfer bundle: %w", err)
+	}
+	fmt.Printf("Wrote transfer bundle of %d resident(s) to %s\n", len(bundle.Residents), *out)
+	return nil
+}
@@ -0,0 +1,427 @@
+// Package analytics copies tables of the vault database to a standalone
+// SQLite file or to Parquet files, with a description of their schema, for
+// analysis away from the live database.
+package analytics
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/vaultcrypt"
+)
+
+// Format is the file format of an analytics export.
+type Format string
+
+// Export formats.
+const (
+	FormatSQLite  Format = "sqlite"
+	FormatParquet Format = "parquet"
+)
+
+// Validate checks the format is one of the export formats.
+func (f Format) Validate() error {
+	switch f {
+	case FormatSQLite, FormatParquet:
+		return nil
+	}
+	return fmt.Errorf("unknown format %q (want sqlite or parquet)", f)
+}
+
+// Dataset is a group of tables exported together.
+type Dataset struct {
+	Name        string
+	Description string
+	Tables      []string
+}
+
+// Datasets are the datasets that can be exported, in export order.
+var Datasets = []Dataset{
+	{
+		Name:        "residents",
+		Description: "Residents, their households, quarters, vocations and work, morale and status changes.",
+		Tables: []string{
+			"residents", "households", "quarters", "vocations",
+			"work_assignments", "resident_morale", "status_history",
+		},
+	},
+	{
+		Name:        "transactions",
+		Description: "Resource items and stocks with every movement in and out, and ration draws.",
+		Tables: []string{
+			"resource_categories", "resource_items", "resource_stocks",
+			"resource_transactions", "ration_draws", "ration_draw_departments",
+		},
+	},
+	{
+		Name:        "metrics",
+		Description: "Readings sampled over vault time: morale, environment, genetic health and power grid flows.",
+		Tables: []string{
+			"facility_systems", "facility_grid_flows", "environment_readings",
+			"morale_snapshots", "genetic_health_snapshots",
+		},
+	},
+}
+
+// DatasetNames returns the names of all datasets.
+func DatasetNames() []string {
+	names := make([]string, len(Datasets))
+	for i, ds := range Datasets {
+		names[i] = ds.Name
+	}
+	return names
+}
+
+// tableDescriptions describe each exported table in the schema docs.
+var tableDescriptions = map[string]string{
+	"residents":                "One row per resident, living, deceased or departed.",
+	"households":               "Households residents belong to.",
+	"quarters":                 "Living quarters households are assigned to.",
+	"vocations":                "Vocations residents can be assigned to, by department.",
+	"work_assignments":         "Residents' assignments to vocations, current and past.",
+	"resident_morale":          "Each resident's current morale score and its factors.",
+	"status_history":           "Every change of a resident's status, with its reason.",
+	"resource_categories":      "Categories resource items are grouped in.",
+	"resource_items":           "Resource items tracked, with their unit.",
+	"resource_stocks":          "Stock lots of each item, with quantity and expiry.",
+	"resource_transactions":    "Every movement of stock: production, consumption, rations, spoilage and adjustments.",
+	"ration_draws":             "Ration draws made for the vault on a day.",
+	"ration_draw_departments":  "Each department's share of a ration draw.",
+	"facility_systems":         "Facility systems with their capacity, efficiency and status.",
+	"facility_grid_flows":      "Power grid supply and demand sampled over vault time.",
+	"environment_readings":     "O2, CO2, temperature and radiation sampled by sector.",
+	"morale_snapshots":         "The vault happiness index sampled over vault time.",
+	"genetic_health_snapshots": "Genetic health of the population sampled over vault time.",
+}
+
+// PIIColumns are the columns, as "table.column", of residents' personal
+// details: blood types, notes and the reasons of status periods. A redacted
+// export replaces their values with models.Redacted.
+var PIIColumns = []string{"residents.blood_type", "residents.notes", "status_history.reason"}
+
+// Column is a column of an exported table.
+type Column struct {
+	Name         string `json:"name"`
+	Type         string `json:"type"`          // INTEGER, REAL, TEXT or BLOB
+	DeclaredType string `json:"declared_type"` // As declared in the vault database
+	NotNull      bool   `json:"not_null"`
+	PrimaryKey   bool   `json:"primary_key"`
+	References   string `json:"references,omitempty"` // table(column) of a foreign key
+	Redacted     bool   `json:"redacted,omitempty"`   // Values replaced by models.Redacted
+
+	key string // table.column, which encrypted values are sealed under
+}
+
+// Table is an exported table.
+type Table struct {
+	Name        string    `json:"name"`
+	Dataset     string    `json:"dataset"`
+	Description string    `json:"description"`
+	File        string    `json:"file"` // Relative to the export directory
+	Rows        int       `json:"rows"`
+	Columns     []*Column `json:"columns"`
+}
+
+// Manifest describes an analytics export, written beside it as
+// manifest.json.
+type Manifest struct {
+	CreatedAt     time.Time `json:"created_at"`
+	SchemaVersion int       `json:"schema_version"`
+	Format        Format    `json:"format"`
+	Datasets      []string  `json:"datasets"`
+	Redacted      bool      `json:"redacted"` // PIIColumns were redacted
+	Tables        []*Table  `json:"tables"`
+}
+
+// tableWriter writes exported tables in one format.
+type tableWriter interface {
+	// writeTable writes the table's rows, setting its file and row count.
+	writeTable(ctx context.Context, t *Table, rows *sql.Rows) error
+	close() error
+}
+
+// Export copies the tables of the named datasets, all if none are named,
+// from db into dir in the format, with a manifest.json and a SCHEMA.md
+// describing them. Encrypted values are decrypted, and with redact the
+// values of PIIColumns are redacted. The tables are read in one
+// transaction, so they agree with each other even while the vault runs.
+// Dir is created if needed, but an export already there is not overwritten.
+func Export(ctx context.Context, db *sql.DB, dir string, format Format, datasets []string, redact bool) (*Manifest, error) {
+	if err := format.Validate(); err != nil {
+		return nil, err
+	}
+	if len(datasets) == 0 {
+		datasets = DatasetNames()
+	}
+	var selected []Dataset
+	for _, ds := range Datasets {
+		if slices.Contains(datasets, ds.Name) {
+			selected = append(selected, ds)
+		}
+	}
+	for _, name := range datasets {
+		if !slices.ContainsFunc(selected, func(ds Dataset) bool { return ds.Name == name }) {
+			return nil, fmt.Errorf("unknown dataset %q (want %s)", name, strings.Join(DatasetNames(), ", "))
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "manifest.json")); err == nil {
+		return nil, fmt.Errorf("%s already holds an export", dir)
+	}
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, fmt.Errorf("creating export directory: %w", err)
+	}
+
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("beginning read: %w", err)
+	}
+	defer tx.Rollback()
+
+	manifest := &Manifest{
+		CreatedAt: time.Now().UTC(),
+		Format:    format,
+		Redacted:  redact,
+	}
+	if err := tx.QueryRowContext(ctx,
+		"SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&manifest.SchemaVersion); err != nil {
+		return nil, fmt.Errorf("reading schema version: %w", err)
+	}
+
+	var w tableWriter
+	switch format {
+	case FormatSQLite:
+		w, err = newSQLiteWriter(ctx, dir)
+	case FormatParquet:
+		w = &parquetWriter{dir: dir}
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer w.close()
+
+	for _, ds := range selected {
+		manifest.Datasets = append(manifest.Datasets, ds.Name)
+		for _, name := range ds.Tables {
+			t := &Table{Name: name, Dataset: ds.Name, Description: tableDescriptions[name]}
+			if t.Columns, err = tableColumns(ctx, tx, name); err != nil {
+				return nil, err
+			}
+			for _, c := range t.Columns {
+				c.Redacted = redact && slices.Contains(PIIColumns, c.key)
+			}
+			if err := exportTable(ctx, tx, w, t); err != nil {
+				return nil, err
+			}
+			manifest.Tables = append(manifest.Tables, t)
+		}
+	}
+	if err := w.close(); err != nil {
+		return nil, err
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "SCHEMA.md"), []byte(schemaDoc(manifest)), 0640); err != nil {
+		return nil, fmt.Errorf("writing schema docs: %w", err)
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding manifest: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "manifest.json"), data, 0640); err != nil {
+		return nil, fmt.Errorf("writing manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+// exportTable reads every row of the table and writes it with w.
+func exportTable(ctx context.Context, tx *sql.Tx, w tableWriter, t *Table) error {
+	names := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		names[i] = quote(c.Name)
+		if c.Redacted {
+			names[i] = fmt.Sprintf("CASE WHEN COALESCE(%[1]s, '') = '' THEN %[1]s ELSE '%[2]s' END", quote(c.Name), models.Redacted)
+		}
+	}
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf("SELECT %s FROM %s", strings.Join(names, ", "), quote(t.Name)))
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", t.Name, err)
+	}
+	defer rows.Close()
+	if err := w.writeTable(ctx, t, rows); err != nil {
+		return fmt.Errorf("exporting %s: %w", t.Name, err)
+	}
+	return rows.Close()
+}
+
+// tableColumns describes the columns of a table, with their foreign keys.
+func tableColumns(ctx context.Context, tx *sql.Tx, table string) ([]*Column, error) {
+	rows, err := tx.QueryContext(ctx, "SELECT name, type, \"notnull\", pk FROM pragma_table_info(?)", table)
+	if err != nil {
+		return nil, fmt.Errorf("describing %s: %w", table, err)
+	}
+	defer rows.Close()
+	var columns []*Column
+	for rows.Next() {
+		var c Column
+		var pk int
+		if err := rows.Scan(&c.Name, &c.DeclaredType, &c.NotNull, &pk); err != nil {
+			return nil, fmt.Errorf("describing %s: %w", table, err)
+		}
+		c.PrimaryKey = pk > 0
+		c.Type = affinity(c.DeclaredType)
+		c.key = table + "." + c.Name
+		columns = append(columns, &c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("describing %s: %w", table, err)
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("table %s not found", table)
+	}
+
+	fks, err := tx.QueryContext(ctx, "SELECT \"from\", \"table\", COALESCE(\"to\", '') FROM pragma_foreign_key_list(?)", table)
+	if err != nil {
+		return nil, fmt.Errorf("describing %s foreign keys: %w", table, err)
+	}
+	defer fks.Close()
+	for fks.Next() {
+		var from, parent, to string
+		if err := fks.Scan(&from, &parent, &to); err != nil {
+			return nil, fmt.Errorf("describing %s foreign keys: %w", table, err)
+		}
+		if to == "" {
+			to = "id"
+		}
+		for _, c := range columns {
+			if c.Name == from {
+				c.References = parent + "(" + to + ")"
+			}
+		}
+	}
+	return columns, fks.Err()
+}
+
+// affinity returns the storage type SQLite gives a declared column type,
+// folding NUMERIC into REAL and untyped columns into TEXT.
+func affinity(declared string) string {
+	t := strings.ToUpper(declared)
+	switch {
+	case strings.Contains(t, "INT"):
+		return "INTEGER"
+	case strings.Contains(t, "CHAR"), strings.Contains(t, "CLOB"), strings.Contains(t, "TEXT"):
+		return "TEXT"
+	case strings.Contains(t, "BLOB"):
+		return "BLOB"
+	case strings.Contains(t, "REAL"), strings.Contains(t, "FLOA"), strings.Contains(t, "DOUB"),
+		strings.Contains(t, "NUMERIC"), strings.Contains(t, "DECIMAL"):
+		return "REAL"
+	}
+	return "TEXT"
+}
+
+// scanRow scans the current row into values converted to the columns'
+// types: nil, int64, float64, string or []byte. Encrypted values are
+// decrypted.
+func scanRow(rows *sql.Rows, columns []*Column) ([]any, error) {
+	values := make([]any, len(columns))
+	ptrs := make([]any, len(columns))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	if err := rows.Scan(ptrs...); err != nil {
+		return nil, err
+	}
+	for i, c := range columns {
+		v, err := convert(c.Type, values[i])
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %w", c.Name, err)
+		}
+		if s, ok := v.(string); ok && vaultcrypt.IsSealed(s) {
+			if v, err = vaultcrypt.Open(c.key, s); err != nil {
+				return nil, fmt.Errorf("column %s: %w", c.Name, err)
+			}
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+// convert converts a value read from SQLite to the Go type of a column's
+// storage type. A value stored with another type, which SQLite allows, is
+// converted where it can be without loss.
+func convert(typ string, v any) (any, error) {
+	if t, ok := v.(time.Time); ok {
+		v = t.UTC().Format(time.RFC3339)
+	}
+	if v == nil {
+		return nil, nil
+	}
+	switch typ {
+	case "INTEGER":
+		switch v := v.(type) {
+		case int64:
+			return v, nil
+		case float64:
+			if v == float64(int64(v)) {
+				return int64(v), nil
+			}
+		case bool:
+			if v {
+				return int64(1), nil
+			}
+			return int64(0), nil
+		case string:
+			if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+				return n, nil
+			}
+		}
+		return nil, fmt.Errorf("%v is not an integer", v)
+	case "REAL":
+		switch v := v.(type) {
+		case float64:
+			return v, nil
+		case int64:
+			return float64(v), nil
+		case string:
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				return f, nil
+			}
+		}
+		return nil, fmt.Errorf("%v is not a number", v)
+	case "BLOB":
+		switch v := v.(type) {
+		case []byte:
+			return v, nil
+		case string:
+			return []byte(v), nil
+		}
+		return nil, fmt.Errorf("%v is not a blob", v)
+	}
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	}
+	return nil, fmt.Errorf("unsupported value %T", v)
+}
+
+// quote quotes an SQL identifier.
+func quote(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
@@ -0,0 +1,253 @@
+package analytics
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/vtuos/vtuos/internal/config"
+	"github.com/vtuos/vtuos/internal/database"
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// openTestDB opens a migrated vault database with a category, an item and
+// two transactions, one with a reason.
+func openTestDB(t *testing.T) *database.DB {
+	t.Helper()
+	ctx := context.Background()
+	cfg := config.Default().Database
+	db, err := database.Open(filepath.Join(t.TempDir(), "vault.db"), &cfg, "")
+	if err != nil {
+		t.Fatalf("opening database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	m, err := database.NewMigrator(db)
+	if err != nil {
+		t.Fatalf("creating migrator: %v", err)
+	}
+	if _, err := m.MigrateUp(ctx); err != nil {
+		t.Fatalf("migrating: %v", err)
+	}
+
+	for _, stmt := range []string{
+		`INSERT INTO resource_categories (id, code, name, unit_of_measure) VALUES ('cat-1', 'FOOD', 'Food', 'kg')`,
+		`INSERT INTO resource_items (id, category_id, item_code, name, unit_of_measure)
+			VALUES ('item-1', 'cat-1', 'FOOD-001', 'Nutrient Paste', 'kg')`,
+		`INSERT INTO resource_transactions (id, item_id, transaction_type, quantity, balance_after, timestamp, reason)
+			VALUES ('tx-1', 'item-1', 'PRODUCTION', 12.5, 12.5, '2077-10-23T08:00:00Z', 'first harvest')`,
+		`INSERT INTO resource_transactions (id, item_id, transaction_type, quantity, balance_after, timestamp)
+			VALUES ('tx-2', 'item-1', 'CONSUMPTION', -2, 10.5, '2077-10-24T08:00:00Z')`,
+	} {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			t.Fatalf("seeding: %v\n%s", err, stmt)
+		}
+	}
+	return db
+}
+
+func TestExportSQLite(t *testing.T) {
+	db := openTestDB(t)
+	dir := filepath.Join(t.TempDir(), "out")
+
+	manifest, err := Export(context.Background(), db.DB, dir, FormatSQLite, []string{"transactions"}, false)
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if manifest.SchemaVersion == 0 {
+		t.Error("manifest schema version = 0")
+	}
+	if len(manifest.Tables) != len(Datasets[1].Tables) {
+		t.Errorf("exported %d tables, want %d", len(manifest.Tables), len(Datasets[1].Tables))
+	}
+
+	out, err := sql.Open("sqlite", filepath.Join(dir, sqliteFile))
+	if err != nil {
+		t.Fatalf("opening export: %v", err)
+	}
+	defer out.Close()
+
+	var count int
+	var total float64
+	if err := out.QueryRow("SELECT COUNT(*), SUM(quantity) FROM resource_transactions").Scan(&count, &total); err != nil {
+		t.Fatalf("reading export: %v", err)
+	}
+	if count != 2 || total != 10.5 {
+		t.Errorf("exported transactions = %d totalling %v, want 2 totalling 10.5", count, total)
+	}
+	var rows int
+	if err := out.QueryRow("SELECT row_count FROM export_tables WHERE name = 'resource_transactions'").Scan(&rows); err != nil {
+		t.Fatalf("reading export_tables: %v", err)
+	}
+	if rows != 2 {
+		t.Errorf("export_tables row_count = %d, want 2", rows)
+	}
+	var refs string
+	if err := out.QueryRow(`SELECT refs FROM export_columns
+		WHERE table_name = 'resource_transactions' AND name = 'item_id'`).Scan(&refs); err != nil {
+		t.Fatalf("reading export_columns: %v", err)
+	}
+	if refs != "resource_items(id)" {
+		t.Errorf("item_id references %q, want resource_items(id)", refs)
+	}
+	if err := out.QueryRow("SELECT COUNT(*) FROM residents").Scan(&count); err == nil {
+		t.Error("residents exported without its dataset selected")
+	}
+
+	doc, err := os.ReadFile(filepath.Join(dir, "SCHEMA.md"))
+	if err != nil {
+		t.Fatalf("reading schema docs: %v", err)
+	}
+	if !strings.Contains(string(doc), "### resource_transactions") {
+		t.Error("SCHEMA.md does not describe resource_transactions")
+	}
+
+	if _, err := Export(context.Background(), db.DB, dir, FormatSQLite, nil, false); err == nil {
+		t.Error("Export() over an existing export succeeded")
+	}
+}
+
+func TestExportParquet(t *testing.T) {
+	db := openTestDB(t)
+	dir := t.TempDir()
+
+	if _, err := Export(context.Background(), db.DB, dir, FormatParquet, nil, false); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "manifest.json"))
+	if err != nil {
+		t.Fatalf("reading manifest: %v", err)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		t.Fatalf("decoding manifest: %v", err)
+	}
+	if manifest.Format != FormatParquet || len(manifest.Datasets) != len(Datasets) {
+		t.Errorf("manifest = %s with datasets %v", manifest.Format, manifest.Datasets)
+	}
+
+	f, err := os.Open(filepath.Join(dir, "resource_transactions.parquet"))
+	if err != nil {
+		t.Fatalf("opening Parquet file: %v", err)
+	}
+	defer f.Close()
+	stat, err := f.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pf, err := parquet.OpenFile(f, stat.Size())
+	if err != nil {
+		t.Fatalf("reading Parquet file: %v", err)
+	}
+	if pf.NumRows() != 2 {
+		t.Errorf("Parquet rows = %d, want 2", pf.NumRows())
+	}
+
+	type transaction struct {
+		ID       string  `parquet:"id"`
+		Quantity float64 `parquet:"quantity"`
+		Reason   *string `parquet:"reason,optional"`
+	}
+	rows, err := parquet.Read[transaction](f, stat.Size())
+	if err != nil {
+		t.Fatalf("reading Parquet rows: %v", err)
+	}
+	byID := make(map[string]transaction)
+	for _, r := range rows {
+		byID[r.ID] = r
+	}
+	if tx := byID["tx-1"]; tx.Quantity != 12.5 || tx.Reason == nil || *tx.Reason != "first harvest" {
+		t.Errorf("tx-1 = %+v, want 12.5 with a reason", tx)
+	}
+	if tx := byID["tx-2"]; tx.Quantity != -2 || tx.Reason != nil {
+		t.Errorf("tx-2 = %+v, want -2 without a reason", tx)
+	}
+}
+
+func TestExportRedacted(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	if _, err := db.ExecContext(ctx, `
+		INSERT INTO residents (id, registry_number, surname, given_names, date_of_birth, sex,
+			blood_type, entry_type, entry_date, status, notes)
+		VALUES ('r-1', 'V076-00001', 'Smith', 'Ann', '2050-01-01', 'F', 'O+', 'ORIGINAL', '2077-10-23', 'ACTIVE',
+			'Reprimanded for sleeping on watch'),
+			('r-2', 'V076-00002', 'Jones', 'Bo', '2051-01-01', 'M', NULL, 'ORIGINAL', '2077-10-23', 'ACTIVE', '')`); err != nil {
+		t.Fatalf("seeding: %v", err)
+	}
+
+	dir := filepath.Join(t.TempDir(), "out")
+	manifest, err := Export(ctx, db.DB, dir, FormatSQLite, []string{"residents"}, true)
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if !manifest.Redacted {
+		t.Error("manifest not marked redacted")
+	}
+
+	out, err := sql.Open("sqlite", filepath.Join(dir, sqliteFile))
+	if err != nil {
+		t.Fatalf("opening export: %v", err)
+	}
+	defer out.Close()
+
+	var surname string
+	var bloodType, notes sql.NullString
+	if err := out.QueryRow("SELECT surname, blood_type, notes FROM residents WHERE id = 'r-1'").Scan(&surname, &bloodType, &notes); err != nil {
+		t.Fatalf("reading export: %v", err)
+	}
+	if surname != "Smith" || bloodType.String != models.Redacted || notes.String != models.Redacted {
+		t.Errorf("r-1 = %s, %q, %q, want Smith with blood type and notes redacted", surname, bloodType.String, notes.String)
+	}
+	if err := out.QueryRow("SELECT blood_type, notes FROM residents WHERE id = 'r-2'").Scan(&bloodType, &notes); err != nil {
+		t.Fatalf("reading export: %v", err)
+	}
+	if bloodType.Valid || notes.String != "" {
+		t.Errorf("r-2 = %v, %q, want no blood type and empty notes left as they were", bloodType, notes.String)
+	}
+}
+
+func TestExportUnknownDataset(t *testing.T) {
+	db := openTestDB(t)
+	if _, err := Export(context.Background(), db.DB, t.TempDir(), FormatSQLite, []string{"secrets"}, false); err == nil {
+		t.Error("Export() of an unknown dataset succeeded")
+	}
+	if _, err := Export(context.Background(), db.DB, t.TempDir(), "csv", nil, false); err == nil {
+		t.Error("Export() in an unknown format succeeded")
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		typ     string
+		in      any
+		want    any
+		wantErr bool
+	}{
+		{"INTEGER", int64(3), int64(3), false},
+		{"INTEGER", float64(4), int64(4), false},
+		{"INTEGER", "5", int64(5), false},
+		{"INTEGER", 1.5, nil, true},
+		{"REAL", int64(2), 2.0, false},
+		{"REAL", "x", nil, true},
+		{"TEXT", int64(7), "7", false},
+		{"TEXT", []byte("abc"), "abc", false},
+		{"TEXT", nil, nil, false},
+	}
+	for _, tt := range tests {
+		got, err := convert(tt.typ, tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("convert(%s, %v) error = %v, wantErr %v", tt.typ, tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("convert(%s, %v) = %v (%T), want %v (%T)", tt.typ, tt.in, got, got, tt.want, tt.want)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package analytics
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// parquetBatch is how many rows are buffered before writing to a Parquet
+// file.
+const parquetBatch = 1000
+
+// parquetWriter writes each exported table to its own Parquet file in dir,
+// Snappy compressed. Every column is optional, and Parquet orders them by
+// name.
+type parquetWriter struct {
+	dir string
+}
+
+// parquetNode returns the Parquet node of a column's storage type.
+func parquetNode(typ string) parquet.Node {
+	switch typ {
+	case "INTEGER":
+		return parquet.Optional(parquet.Int(64))
+	case "REAL":
+		return parquet.Optional(parquet.Leaf(parquet.DoubleType))
+	case "BLOB":
+		return parquet.Optional(parquet.Leaf(parquet.ByteArrayType))
+	}
+	return parquet.Optional(parquet.String())
+}
+
+// parquetValue returns the Parquet value of a converted column value.
+func parquetValue(v any) parquet.Value {
+	switch v := v.(type) {
+	case int64:
+		return parquet.Int64Value(v)
+	case float64:
+		return parquet.DoubleValue(v)
+	case string:
+		return parquet.ByteArrayValue([]byte(v))
+	case []byte:
+		return parquet.ByteArrayValue(v)
+	}
+	return parquet.NullValue()
+}
+
+// writeTable writes the table's rows to <table>.parquet.
+func (w *parquetWriter) writeTable(_ context.Context, t *Table, rows *sql.Rows) error {
+	t.File = t.Name + ".parquet"
+
+	group := make(parquet.Group, len(t.Columns))
+	for _, c := range t.Columns {
+		group[c.Name] = parquetNode(c.Type)
+	}
+	schema := parquet.NewSchema(t.Name, group)
+	index := make([]int, len(t.Columns))
+	for i, c := range t.Columns {
+		leaf, ok := schema.Lookup(c.Name)
+		if !ok {
+			return fmt.Errorf("column %s missing from the Parquet schema", c.Name)
+		}
+		index[i] = leaf.ColumnIndex
+	}
+
+	path := filepath.Join(w.dir, t.File)
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0640)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	pw := parquet.NewWriter(f, schema, parquet.Compression(&parquet.Snappy))
+
+	builder := parquet.NewRowBuilder(schema)
+	batch := make([]parquet.Row, 0, parquetBatch)
+	flush := func() error {
+		if _, err := pw.WriteRows(batch); err != nil {
+			return err
+		}
+		batch = batch[:0]
+		return nil
+	}
+	for rows.Next() {
+		values, err := scanRow(rows, t.Columns)
+		if err != nil {
+			return err
+		}
+		builder.Reset()
+		for i, v := range values {
+			if v != nil {
+				builder.Add(index[i], parquetValue(v))
+			}
+		}
+		batch = append(batch, builder.Row())
+		t.Rows++
+		if len(batch) == parquetBatch {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+	if err := pw.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// close does nothing, as each file is closed once written.
+func (w *parquetWriter) close() error {
+	return nil
+}
@@ -0,0 +1,66 @@
+package analytics
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// schemaDoc renders the SCHEMA.md of an export: each dataset's tables with
+// their columns, types and references.
+func schemaDoc(m *Manifest) string {
+	var b strings.Builder
+	b.WriteString("# Vault Analytics Export\n\n")
+	fmt.Fprintf(&b, "Exported %s from schema version %d as %s.\n\n",
+		m.CreatedAt.Format("2006-01-02 15:04 MST"), m.SchemaVersion, m.Format)
+	b.WriteString("Timestamps are RFC 3339 text, in vault time unless the column says otherwise. ")
+	b.WriteString("Dates are YYYY-MM-DD text. Flags are INTEGER 0 or 1. ")
+	b.WriteString("IDs are text and join across tables as the references below show.\n")
+	if m.Redacted {
+		fmt.Fprintf(&b, "\nResidents' personal details are redacted: the non-empty values of %s read `%s`.\n",
+			strings.Join(PIIColumns, ", "), models.Redacted)
+	}
+	switch m.Format {
+	case FormatSQLite:
+		fmt.Fprintf(&b, "\nAll tables are in `%s`, which also describes them in the tables `export_tables` and `export_columns`. ", sqliteFile)
+		b.WriteString("Column types are kept, constraints are not.\n")
+	case FormatParquet:
+		b.WriteString("\nEach table is in its own `<table>.parquet` file, Snappy compressed. ")
+		b.WriteString("INTEGER columns are INT64, REAL are DOUBLE, TEXT are UTF-8 strings and BLOB are byte arrays. ")
+		b.WriteString("Every column is optional, and columns are ordered by name.\n")
+	}
+
+	for _, ds := range Datasets {
+		var tables []*Table
+		for _, t := range m.Tables {
+			if t.Dataset == ds.Name {
+				tables = append(tables, t)
+			}
+		}
+		if len(tables) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n## %s\n\n%s\n", ds.Name, ds.Description)
+		for _, t := range tables {
+			fmt.Fprintf(&b, "\n### %s\n\n", t.Name)
+			if t.Description != "" {
+				b.WriteString(t.Description + " ")
+			}
+			fmt.Fprintf(&b, "%d row(s) in `%s`.\n\n", t.Rows, t.File)
+			b.WriteString("| Column | Type | Null | Key | References |\n")
+			b.WriteString("|--------|------|------|-----|------------|\n")
+			for _, c := range t.Columns {
+				null, key := "yes", ""
+				if c.NotNull {
+					null = "no"
+				}
+				if c.PrimaryKey {
+					key = "PK"
+				}
+				fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n", c.Name, c.Type, null, key, c.References)
+			}
+		}
+	}
+	return b.String()
+}
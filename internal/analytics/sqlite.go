@@ -0,0 +1,133 @@
+package analytics
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	_ "modernc.org/sqlite" // SQLite driver
+)
+
+// sqliteFile is the name of the SQLite file of an export.
+const sqliteFile = "analytics.sqlite"
+
+// sqliteWriter writes exported tables into one standalone SQLite file,
+// with the tables export_tables and export_columns describing them. Tables
+// keep their column types but not their constraints, so rows can be added
+// or removed freely during analysis.
+type sqliteWriter struct {
+	db *sql.DB
+}
+
+// newSQLiteWriter creates the SQLite file in dir, failing if it exists.
+func newSQLiteWriter(ctx context.Context, dir string) (*sqliteWriter, error) {
+	path := filepath.Join(dir, sqliteFile)
+	db, err := sql.Open("sqlite", "file:"+path+"?mode=rwc")
+	if err != nil {
+		return nil, fmt.Errorf("creating %s: %w", path, err)
+	}
+	var tables int
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM sqlite_master").Scan(&tables); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("creating %s: %w", path, err)
+	}
+	if tables > 0 {
+		db.Close()
+		return nil, fmt.Errorf("%s already exists", path)
+	}
+	_, err = db.ExecContext(ctx, `
+		PRAGMA journal_mode = OFF;
+		PRAGMA synchronous = OFF;
+		CREATE TABLE export_tables (
+			name TEXT PRIMARY KEY,
+			dataset TEXT NOT NULL,
+			description TEXT NOT NULL,
+			row_count INTEGER NOT NULL
+		);
+		CREATE TABLE export_columns (
+			table_name TEXT NOT NULL,
+			position INTEGER NOT NULL,
+			name TEXT NOT NULL,
+			type TEXT NOT NULL,
+			not_null INTEGER NOT NULL,
+			primary_key INTEGER NOT NULL,
+			refs TEXT,
+			PRIMARY KEY (table_name, name)
+		);`)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("creating %s: %w", path, err)
+	}
+	return &sqliteWriter{db: db}, nil
+}
+
+// writeTable creates the table and copies its rows in one transaction.
+func (w *sqliteWriter) writeTable(ctx context.Context, t *Table, rows *sql.Rows) error {
+	t.File = sqliteFile
+
+	tx, err := w.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	defs := make([]string, len(t.Columns))
+	params := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		defs[i] = quote(c.Name) + " " + c.Type
+		params[i] = "?"
+	}
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("CREATE TABLE %s (%s)", quote(t.Name), strings.Join(defs, ", "))); err != nil {
+		return err
+	}
+	insert, err := tx.PrepareContext(ctx, fmt.Sprintf("INSERT INTO %s VALUES (%s)", quote(t.Name), strings.Join(params, ", ")))
+	if err != nil {
+		return err
+	}
+	defer insert.Close()
+
+	for rows.Next() {
+		values, err := scanRow(rows, t.Columns)
+		if err != nil {
+			return err
+		}
+		if _, err := insert.ExecContext(ctx, values...); err != nil {
+			return err
+		}
+		t.Rows++
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		"INSERT INTO export_tables (name, dataset, description, row_count) VALUES (?, ?, ?, ?)",
+		t.Name, t.Dataset, t.Description, t.Rows); err != nil {
+		return err
+	}
+	for i, c := range t.Columns {
+		var refs any
+		if c.References != "" {
+			refs = c.References
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO export_columns (table_name, position, name, type, not_null, primary_key, refs)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			t.Name, i+1, c.Name, c.Type, c.NotNull, c.PrimaryKey, refs); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// close closes the SQLite file. It may be called more than once.
+func (w *sqliteWriter) close() error {
+	if w.db == nil {
+		return nil
+	}
+	err := w.db.Close()
+	w.db = nil
+	return err
+}
@@ -0,0 +1,317 @@
+// Package archive writes and reads holotapes: single compressed files
+// holding a copy of the vault database, its configuration and recent
+// reports, for storage off site. Every file is checksummed in a manifest
+// that leads the holotape, and reading verifies each against it.
+package archive
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Format identifies the holotape format.
+const Format = "vtuos-holotape/1"
+
+// manifestName is the name of the manifest, the first file of a holotape.
+const manifestName = "manifest.json"
+
+// Names of the files of a holotape.
+const (
+	DatabaseName  = "vault.db"
+	SnapshotName  = "snapshot.json" // Vault state recorded with the database copy
+	ConfigName    = "vault.toml"
+	ReportsPrefix = "reports/"
+)
+
+// ErrCorrupt is returned for a holotape whose files do not match its
+// manifest.
+var ErrCorrupt = errors.New("holotape is corrupt")
+
+// Manifest describes a holotape and checksums its files.
+type Manifest struct {
+	Format      string    `json:"format"`
+	Vault       int       `json:"vault"`
+	Designation string    `json:"designation"`
+	CreatedAt   time.Time `json:"created_at"`
+	VaultTime   time.Time `json:"vault_time"`
+	Note        string    `json:"note,omitempty"`
+	Entries     []Entry   `json:"entries"`
+}
+
+// Entry is a file of a holotape.
+type Entry struct {
+	Name   string `json:"name"` // Slash-separated, e.g. "reports/vault-076-daily-report-2077-10-23.txt"
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// IsReport reports whether the entry is a report.
+func (e Entry) IsReport() bool {
+	return strings.HasPrefix(e.Name, ReportsPrefix)
+}
+
+// File is a file to write to a holotape.
+type File struct {
+	Name string // Name in the holotape
+	Path string // File on disk
+}
+
+// Write writes a holotape of files to dest, checksumming each in the
+// manifest, and returns the manifest. The holotape is written beside dest
+// and renamed into place, so a failed write leaves no partial file.
+func Write(dest string, manifest Manifest, files []File) (*Manifest, error) {
+	manifest.Format = Format
+	manifest.Entries = nil
+	seen := make(map[string]bool)
+	for _, f := range files {
+		if !validName(f.Name) || f.Name == manifestName {
+			return nil, fmt.Errorf("invalid holotape file name %q", f.Name)
+		}
+		if seen[f.Name] {
+			return nil, fmt.Errorf("holotape file %s listed twice", f.Name)
+		}
+		seen[f.Name] = true
+		entry, err := checksum(f)
+		if err != nil {
+			return nil, err
+		}
+		manifest.Entries = append(manifest.Entries, entry)
+	}
+	meta, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding manifest: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(dest), "."+filepath.Base(dest)+"-*")
+	if err != nil {
+		return nil, fmt.Errorf("creating holotape: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	gz := gzip.NewWriter(tmp)
+	tw := tar.NewWriter(gz)
+	if err := writeEntry(tw, manifestName, int64(len(meta)), manifest.CreatedAt, strings.NewReader(string(meta))); err != nil {
+		return nil, err
+	}
+	for i, f := range files {
+		if err := writeFile(tw, f, manifest.Entries[i], manifest.CreatedAt); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("writing holotape: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("writing holotape: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		return nil, fmt.Errorf("writing holotape: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, fmt.Errorf("writing holotape: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0640); err != nil {
+		return nil, fmt.Errorf("writing holotape: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), dest); err != nil {
+		return nil, fmt.Errorf("writing holotape: %w", err)
+	}
+	return &manifest, nil
+}
+
+// checksum reads a file to record its size and SHA-256.
+func checksum(f File) (Entry, error) {
+	in, err := os.Open(f.Path)
+	if err != nil {
+		return Entry{}, fmt.Errorf("reading %s: %w", f.Name, err)
+	}
+	defer in.Close()
+	h := sha256.New()
+	n, err := io.Copy(h, in)
+	if err != nil {
+		return Entry{}, fmt.Errorf("reading %s: %w", f.Name, err)
+	}
+	return Entry{Name: f.Name, Size: n, SHA256: hex.EncodeToString(h.Sum(nil))}, nil
+}
+
+// writeFile writes a file to the holotape, failing if it changed since it
+// was checksummed.
+func writeFile(tw *tar.Writer, f File, entry Entry, modTime time.Time) error {
+	in, err := os.Open(f.Path)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", f.Name, err)
+	}
+	defer in.Close()
+	h := sha256.New()
+	if err := writeEntry(tw, f.Name, entry.Size, modTime, io.TeeReader(in, h)); err != nil {
+		return err
+	}
+	if hex.EncodeToString(h.Sum(nil)) != entry.SHA256 {
+		return fmt.Errorf("%s changed while the holotape was written", f.Name)
+	}
+	return nil
+}
+
+// writeEntry writes a file of size bytes read from r to the holotape.
+func writeEntry(tw *tar.Writer, name string, size int64, modTime time.Time, r io.Reader) error {
+	hdr := &tar.Header{Name: name, Mode: 0640, Size: size, ModTime: modTime, Typeflag: tar.TypeReg}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("writing %s: %w", name, err)
+	}
+	if _, err := io.CopyN(tw, r, size); err != nil {
+		return fmt.Errorf("writing %s: %w", name, err)
+	}
+	return nil
+}
+
+// Verify reads a holotape through, checking every file against the
+// manifest, and returns the manifest.
+func Verify(src string) (*Manifest, error) {
+	manifest, _, err := read(src, "", nil)
+	return manifest, err
+}
+
+// Extract verifies a holotape and extracts the files match selects, every
+// file if match is nil, into dir, keeping their paths within the holotape.
+// A file is only written once its checksum is verified, and nothing is
+// extracted after the first file that fails. It returns the manifest and
+// the entries extracted.
+func Extract(src, dir string, match func(Entry) bool) (*Manifest, []Entry, error) {
+	if match == nil {
+		match = func(Entry) bool { return true }
+	}
+	return read(src, dir, match)
+}
+
+// read reads a holotape, verifying each file, writes those extract selects
+// into dir, and returns the manifest and the entries written.
+func read(src, dir string, extract func(Entry) bool) (*Manifest, []Entry, error) {
+	f, err := os.Open(src)
+	if err != nil {
+		return nil, nil, fmt.Errorf("opening holotape: %w", err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrCorrupt, err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+
+	hdr, err := tr.Next()
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrCorrupt, err)
+	}
+	if hdr.Name != manifestName {
+		return nil, nil, fmt.Errorf("%w: first file is %s, not the manifest", ErrCorrupt, hdr.Name)
+	}
+	var manifest Manifest
+	if err := json.NewDecoder(tr).Decode(&manifest); err != nil {
+		return nil, nil, fmt.Errorf("%w: reading manifest: %w", ErrCorrupt, err)
+	}
+	if manifest.Format != Format {
+		return nil, nil, fmt.Errorf("unsupported holotape format %q (want %s)", manifest.Format, Format)
+	}
+	entries := make(map[string]Entry, len(manifest.Entries))
+	for _, e := range manifest.Entries {
+		if !validName(e.Name) {
+			return nil, nil, fmt.Errorf("%w: invalid file name %q in manifest", ErrCorrupt, e.Name)
+		}
+		entries[e.Name] = e
+	}
+
+	seen := make(map[string]bool)
+	var extracted []Entry
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return &manifest, extracted, fmt.Errorf("%w: %w", ErrCorrupt, err)
+		}
+		entry, ok := entries[hdr.Name]
+		if !ok || seen[hdr.Name] {
+			return &manifest, extracted, fmt.Errorf("%w: %s is not in the manifest", ErrCorrupt, hdr.Name)
+		}
+		seen[hdr.Name] = true
+
+		var out string
+		if extract != nil && extract(entry) {
+			out = filepath.Join(dir, filepath.FromSlash(entry.Name))
+		}
+		if err := readEntry(tr, entry, out); err != nil {
+			return &manifest, extracted, err
+		}
+		if out != "" {
+			extracted = append(extracted, entry)
+		}
+	}
+	for _, e := range manifest.Entries {
+		if !seen[e.Name] {
+			return &manifest, extracted, fmt.Errorf("%w: %s is missing", ErrCorrupt, e.Name)
+		}
+	}
+	return &manifest, extracted, nil
+}
+
+// readEntry reads a file of the holotape and checks it against its entry.
+// If out is set the file is written there, once verified.
+func readEntry(r io.Reader, entry Entry, out string) error {
+	h := sha256.New()
+	w := io.Writer(h)
+	var tmp *os.File
+	if out != "" {
+		if err := os.MkdirAll(filepath.Dir(out), 0750); err != nil {
+			return fmt.Errorf("extracting %s: %w", entry.Name, err)
+		}
+		var err error
+		tmp, err = os.CreateTemp(filepath.Dir(out), "."+filepath.Base(out)+"-*")
+		if err != nil {
+			return fmt.Errorf("extracting %s: %w", entry.Name, err)
+		}
+		defer os.Remove(tmp.Name())
+		defer tmp.Close()
+		w = io.MultiWriter(h, tmp)
+	}
+
+	n, err := io.Copy(w, r)
+	if err != nil {
+		return fmt.Errorf("%w: reading %s: %w", ErrCorrupt, entry.Name, err)
+	}
+	if n != entry.Size || hex.EncodeToString(h.Sum(nil)) != entry.SHA256 {
+		return fmt.Errorf("%w: %s does not match its checksum", ErrCorrupt, entry.Name)
+	}
+	if tmp == nil {
+		return nil
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("extracting %s: %w", entry.Name, err)
+	}
+	if err := os.Chmod(tmp.Name(), 0640); err != nil {
+		return fmt.Errorf("extracting %s: %w", entry.Name, err)
+	}
+	if err := os.Rename(tmp.Name(), out); err != nil {
+		return fmt.Errorf("extracting %s: %w", entry.Name, err)
+	}
+	return nil
+}
+
+// validName reports whether a file name stays within the directory a
+// holotape is extracted to.
+func validName(name string) bool {
+	return name != "" && !strings.HasPrefix(name, "/") && !strings.Contains(name, `\`) &&
+		path.Clean(name) == name && name != ".." && !strings.HasPrefix(name, "../")
+}
@@ -0,0 +1,194 @@
+package archive
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeHolotape writes a holotape of a database, a configuration and two
+// reports and returns its path.
+func writeHolotape(t *testing.T) string {
+	t.Helper()
+	src := t.TempDir()
+	files := map[string]string{
+		DatabaseName: "database pages",
+		ConfigName:   "[vault]\nnumber = 76\n",
+		ReportsPrefix + "vault-076-daily-report-2077-10-22.txt": "report of the 22nd",
+		ReportsPrefix + "vault-076-daily-report-2077-10-23.txt": "report of the 23rd",
+	}
+	var list []File
+	for _, name := range []string{DatabaseName, ConfigName, ReportsPrefix + "vault-076-daily-report-2077-10-22.txt", ReportsPrefix + "vault-076-daily-report-2077-10-23.txt"} {
+		path := filepath.Join(src, filepath.Base(name))
+		if err := os.WriteFile(path, []byte(files[name]), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		list = append(list, File{Name: name, Path: path})
+	}
+
+	dest := filepath.Join(t.TempDir(), "holotape.tar.gz")
+	manifest, err := Write(dest, Manifest{Vault: 76, Designation: "VAULT-TEC 076", CreatedAt: time.Now().UTC()}, list)
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if manifest.Format != Format || len(manifest.Entries) != 4 {
+		t.Fatalf("manifest = %+v", manifest)
+	}
+	return dest
+}
+
+func TestWriteAndExtract(t *testing.T) {
+	holotape := writeHolotape(t)
+
+	manifest, err := Verify(holotape)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if manifest.Vault != 76 || manifest.Entries[0].Name != DatabaseName {
+		t.Errorf("manifest = %+v", manifest)
+	}
+
+	dir := t.TempDir()
+	_, extracted, err := Extract(holotape, dir, nil)
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	if len(extracted) != 4 {
+		t.Errorf("extracted %d files, want 4", len(extracted))
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "reports", "vault-076-daily-report-2077-10-23.txt"))
+	if err != nil || string(data) != "report of the 23rd" {
+		t.Errorf("extracted report = %q, %v", data, err)
+	}
+}
+
+func TestExtractReports(t *testing.T) {
+	holotape := writeHolotape(t)
+
+	dir := t.TempDir()
+	_, extracted, err := Extract(holotape, dir, func(e Entry) bool {
+		return e.Name == ReportsPrefix+"vault-076-daily-report-2077-10-22.txt"
+	})
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	if len(extracted) != 1 {
+		t.Fatalf("extracted %v, want one report", extracted)
+	}
+	if _, err := os.Stat(filepath.Join(dir, DatabaseName)); !os.IsNotExist(err) {
+		t.Errorf("database extracted with only a report selected")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "reports", "vault-076-daily-report-2077-10-22.txt")); err != nil {
+		t.Errorf("report not extracted: %v", err)
+	}
+}
+
+// rewrite copies a holotape, letting edit change each file's contents.
+func rewrite(t *testing.T, src string, edit func(name string, data []byte) []byte) string {
+	t.Helper()
+	f, err := os.Open(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(zw)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data = edit(hdr.Name, data)
+		if data == nil {
+			continue
+		}
+		hdr.Size = int64(len(data))
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	dest := filepath.Join(t.TempDir(), "tampered.tar.gz")
+	if err := os.WriteFile(dest, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return dest
+}
+
+func TestVerifyDetectsTampering(t *testing.T) {
+	holotape := writeHolotape(t)
+
+	changed := rewrite(t, holotape, func(name string, data []byte) []byte {
+		if name == ReportsPrefix+"vault-076-daily-report-2077-10-22.txt" {
+			return []byte("report of the 21st")
+		}
+		return data
+	})
+	if _, err := Verify(changed); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("Verify of a changed report: err = %v, want ErrCorrupt", err)
+	}
+
+	missing := rewrite(t, holotape, func(name string, data []byte) []byte {
+		if name == ConfigName {
+			return nil
+		}
+		return data
+	})
+	if _, err := Verify(missing); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("Verify of a holotape missing a file: err = %v, want ErrCorrupt", err)
+	}
+
+	// Files after the first that fails are not extracted
+	dir := t.TempDir()
+	_, extracted, err := Extract(changed, dir, nil)
+	if !errors.Is(err, ErrCorrupt) {
+		t.Fatalf("Extract: err = %v, want ErrCorrupt", err)
+	}
+	if len(extracted) != 2 {
+		t.Errorf("extracted %v, want the database and configuration only", extracted)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "reports", "vault-076-daily-report-2077-10-22.txt")); !os.IsNotExist(err) {
+		t.Errorf("changed report extracted")
+	}
+}
+
+func TestWriteRejectsUnsafeNames(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(path, []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"../escape", "/etc/passwd", "reports/../../escape", manifestName} {
+		_, err := Write(filepath.Join(t.TempDir(), "h.tar.gz"), Manifest{}, []File{{Name: name, Path: path}})
+		if err == nil {
+			t.Errorf("Write accepted file name %q", name)
+		}
+	}
+}
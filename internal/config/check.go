@@ -0,0 +1,239 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/vtuos/vtuos/internal/i18n"
+)
+
+// masked replaces secrets in a configuration shown to the operator.
+const masked = "********"
+
+// within prefixes each problem of err with the section it was found in, so
+// that every problem of a joined error still names its section when the
+// problems are printed one per line.
+func within(section string, err error) error {
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return fmt.Errorf("%s: %w", section, err)
+	}
+	var errs []error
+	for _, e := range joined.Unwrap() {
+		errs = append(errs, within(section, e))
+	}
+	return errors.Join(errs...)
+}
+
+// choices lists the values a setting accepts, e.g. "amber, blue or white".
+func choices[T ~string](values []T) string {
+	names := make([]string, len(values))
+	for i, v := range values {
+		names[i] = string(v)
+	}
+	if len(names) < 2 {
+		return strings.Join(names, "")
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " or " + names[len(names)-1]
+}
+
+// Problems splits an error from Load or Validate into its problems, one per
+// setting at fault. An error that is not a list of problems, such as a file
+// that is not TOML, is returned as the only one.
+func Problems(err error) []error {
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if joined, ok := e.(interface{ Unwrap() []error }); ok {
+			var problems []error
+			for _, p := range joined.Unwrap() {
+				problems = append(problems, Problems(p)...)
+			}
+			return problems
+		}
+	}
+	return []error{err}
+}
+
+// unknownKeys reports the keys of a file that no setting reads, such as a
+// misspelt key or one in the wrong section, suggesting the key meant.
+func unknownKeys(md toml.MetaData) error {
+	known := settingKeys(reflect.TypeOf(Config{}), "")
+	undecoded := make(map[string]bool)
+	for _, key := range md.Undecoded() {
+		undecoded[key.String()] = true
+	}
+
+	var errs []error
+	for _, key := range md.Undecoded() {
+		// A section that is unknown as a whole is reported once
+		if len(key) > 1 && undecoded[key[:len(key)-1].String()] {
+			continue
+		}
+		msg := fmt.Sprintf("unknown setting %s", key)
+		if meant := suggestKey(key.String(), known); meant != "" {
+			msg += fmt.Sprintf(" (did you mean %s?)", meant)
+		}
+		errs = append(errs, errors.New(msg))
+	}
+	return errors.Join(errs...)
+}
+
+// settingKeys lists the dotted keys of every setting of a configuration
+// type, sections included. The tables of an array share their keys, and
+// those of a map of tables, such as profiles, are keyed by *.
+func settingKeys(t reflect.Type, prefix string) []string {
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("toml"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		key := prefix + name
+		keys = append(keys, key)
+
+		ft := t.Field(i).Type
+		switch ft.Kind() {
+		case reflect.Slice:
+			ft = ft.Elem()
+		case reflect.Map:
+			ft, key = ft.Elem(), key+".*"
+		}
+		if ft.Kind() == reflect.Struct {
+			keys = append(keys, settingKeys(ft, key+".")...)
+		}
+	}
+	return keys
+}
+
+// suggestKey returns the setting an unknown key was likely meant to be: one
+// in the same section spelt at most two letters differently, or else one of
+// the same name in another section. It returns "" if there is none.
+func suggestKey(key string, known []string) string {
+	section, name := splitKey(key)
+	best, bestDistance := "", 3
+	for _, k := range known {
+		s, n := splitKey(k)
+		if !sameSection(s, section) {
+			continue
+		}
+		if d := editDistance(name, n); d < bestDistance {
+			best, bestDistance = strings.TrimPrefix(section+"."+n, "."), d
+		}
+	}
+	if best != "" {
+		return best
+	}
+	for _, k := range known {
+		if _, n := splitKey(k); n == name {
+			return k
+		}
+	}
+	return ""
+}
+
+// sameSection reports whether a section of the file is the known section,
+// whose * matches any name.
+func sameSection(known, section string) bool {
+	knownParts, parts := strings.Split(known, "."), strings.Split(section, ".")
+	if len(knownParts) != len(parts) {
+		return false
+	}
+	for i := range parts {
+		if knownParts[i] != "*" && knownParts[i] != parts[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// splitKey splits a dotted key into its section and name.
+func splitKey(key string) (section, name string) {
+	if i := strings.LastIndex(key, "."); i >= 0 {
+		return key[:i], key[i+1:]
+	}
+	return "", key
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+// Normalize fills the settings left empty with the values they take effect
+// as, e.g. color_scheme green_phosphor, and lowercases the SQLite pragmas,
+// so that the configuration reads as the one in force.
+func (c *Config) Normalize() {
+	if c.Simulation.EventFrequency == "" {
+		c.Simulation.EventFrequency = EventFrequencyNormal
+	}
+	if c.Simulation.Consumption.Policy == "" {
+		c.Simulation.Consumption.Policy = ConsumptionPolicyFEFO
+	}
+	if c.Display.ColorScheme == "" {
+		c.Display.ColorScheme = ColorSchemeGreenPhosphor
+	}
+	if c.Display.Locale == "" {
+		c.Display.Locale = i18n.English
+	}
+	if c.Display.Calendar == "" {
+		c.Display.Calendar = CalendarGregorian
+	}
+	if c.Logging.Level == "" {
+		c.Logging.Level = LogLevelInfo
+	}
+	c.Database.JournalMode = strings.ToLower(c.Database.JournalMode)
+	if c.Database.JournalMode == "" {
+		c.Database.JournalMode = "wal"
+	}
+	c.Database.Synchronous = strings.ToLower(c.Database.Synchronous)
+	if c.Database.Synchronous == "" {
+		c.Database.Synchronous = "normal"
+	}
+	if c.Export.Mode == "" {
+		c.Export.Mode = ExportModeAggregate
+	}
+}
+
+// WithoutSecrets returns a copy of the configuration fit to show: the
+// pseudonym key, the transfer key, the tokens of gRPC peers and the secrets
+// of webhooks are masked where set.
+func (c *Config) WithoutSecrets() *Config {
+	out := *c
+	if out.Export.PseudonymKey != "" {
+		out.Export.PseudonymKey = masked
+	}
+	if out.Transfer.Key != "" {
+		out.Transfer.Key = masked
+	}
+	out.GRPC.Peers = append([]GRPCPeer(nil), c.GRPC.Peers...)
+	for i := range out.GRPC.Peers {
+		if out.GRPC.Peers[i].Token != "" {
+			out.GRPC.Peers[i].Token = masked
+		}
+	}
+	out.Events.Sinks = append([]EventSink(nil), c.Events.Sinks...)
+	for i := range out.Events.Sinks {
+		if out.Events.Sinks[i].Secret != "" {
+			out.Events.Sinks[i].Secret = masked
+		}
+	}
+	return &out
+}
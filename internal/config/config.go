@@ -5,18 +5,41 @@ package config
 import (
 	"errors"
 	"fmt"
+	"os"
+	"slices"
+	"strings"
 	"time"
+	_ "time/tzdata" // time_zone without the host's zone database
+
+	"github.com/vtuos/vtuos/internal/i18n"
+	"github.com/vtuos/vtuos/internal/util"
+	"github.com/vtuos/vtuos/internal/vaultcrypt"
 )
 
 // Config holds the complete application configuration.
 type Config struct {
-	Vault      VaultConfig      `toml:"vault"`
-	Overseer   OverseerConfig   `toml:"overseer"`
-	Experiment ExperimentConfig `toml:"experiment"`
-	Simulation SimulationConfig `toml:"simulation"`
-	Display    DisplayConfig    `toml:"display"`
-	Logging    LoggingConfig    `toml:"logging"`
-	Database   DatabaseConfig   `toml:"database"`
+	Vault      VaultConfig          `toml:"vault"`
+	Vaults     []ManagedVaultConfig `toml:"vaults,omitempty"`
+	Overseer   OverseerConfig       `toml:"overseer"`
+	Experiment ExperimentConfig     `toml:"experiment"`
+	Simulation SimulationConfig     `toml:"simulation"`
+	Display    DisplayConfig        `toml:"display"`
+	Logging    LoggingConfig        `toml:"logging"`
+	Database   DatabaseConfig       `toml:"database"`
+	GRPC       GRPCConfig           `toml:"grpc"`
+	Export     ExportConfig         `toml:"export"`
+	Transfer   TransferConfig       `toml:"transfer"`
+	Privacy    PrivacyConfig        `toml:"privacy"`
+	Events     EventsConfig         `toml:"events"`
+	Alerts     AlertsConfig         `toml:"alerts"`
+	Timeouts   TimeoutsConfig       `toml:"timeouts"`
+
+	// Profiles are named environments run in with --profile, such as a
+	// training sandbox kept apart from the live vault's records.
+	Profiles map[string]ProfileConfig `toml:"profiles,omitempty"`
+
+	// profile is the name of the profile in use, "" for none.
+	profile string
 }
 
 // VaultConfig contains vault identity and physical specifications.
@@ -29,6 +52,89 @@ type VaultConfig struct {
 	DesignedCapacity int           `toml:"designed_capacity"`
 	VaultType        VaultType     `toml:"vault_type"`
 	Location         VaultLocation `toml:"location"`
+
+	// RegistryFormat is the template of the registry numbers issued to new
+	// residents, e.g. "V{vault:03d}-{seq:05d}" or "{vault}-{year}-{seq}".
+	RegistryFormat string `toml:"registry_format"`
+}
+
+// ManagedVaultConfig is a further vault administered from this
+// installation, sharing its database with the primary [vault]. Each is
+// configured as a [[vaults]] table.
+type ManagedVaultConfig struct {
+	Designation      string `toml:"designation"`
+	Number           int    `toml:"number"`
+	DesignedCapacity int    `toml:"designed_capacity"`
+	RegistryFormat   string `toml:"registry_format,omitempty"` // The primary vault's when empty
+}
+
+// ManagedVaults returns every vault administered from this installation,
+// the primary vault first.
+func (c *Config) ManagedVaults() []ManagedVaultConfig {
+	vaults := []ManagedVaultConfig{{
+		Designation:      c.Vault.Designation,
+		Number:           c.Vault.Number,
+		DesignedCapacity: c.Vault.DesignedCapacity,
+		RegistryFormat:   c.Vault.RegistryFormat,
+	}}
+	for _, v := range c.Vaults {
+		if v.RegistryFormat == "" {
+			v.RegistryFormat = c.Vault.RegistryFormat
+		}
+		vaults = append(vaults, v)
+	}
+	return vaults
+}
+
+// UseProfile selects the profile of [profiles] the configuration is run in;
+// "" selects none.
+func (c *Config) UseProfile(name string) error {
+	if _, ok := c.Profiles[name]; !ok && name != "" {
+		if len(c.Profiles) == 0 {
+			return fmt.Errorf("unknown profile %q: no [profiles] are configured", name)
+		}
+		return fmt.Errorf("unknown profile %q (want %s)", name, choices(c.ProfileNames()))
+	}
+	c.profile = name
+	return nil
+}
+
+// Profile returns the name and settings of the profile in use, "" and no
+// settings when there is none.
+func (c *Config) Profile() (string, ProfileConfig) {
+	return c.profile, c.Profiles[c.profile]
+}
+
+// SharesVault reports whether the profile in use runs on the live vault's
+// database, as no profile does.
+func (c *Config) SharesVault() bool {
+	_, p := c.Profile()
+	return profileStore(c, p) == profileStore(c, ProfileConfig{})
+}
+
+// ProfileNames returns the names of the configured profiles, sorted.
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// RegistryFormat returns the registry number format of a managed vault,
+// util.DefaultRegistryFormat for an unknown vault or one without a format.
+// Call it on a validated configuration.
+func (c *Config) RegistryFormat(vaultNumber int) *util.RegistryFormat {
+	for _, v := range c.ManagedVaults() {
+		if v.Number != vaultNumber || v.RegistryFormat == "" {
+			continue
+		}
+		if format, err := util.ParseRegistryFormat(v.RegistryFormat); err == nil {
+			return format
+		}
+	}
+	return util.MustParseRegistryFormat(util.DefaultRegistryFormat)
 }
 
 // VaultLocation specifies the physical location of the vault.
@@ -76,16 +182,34 @@ type SimulationConfig struct {
 	AutoEvents     bool              `toml:"auto_events"`
 	EventFrequency EventFrequency    `toml:"event_frequency"`
 	StartDate      string            `toml:"start_date"`
+	Script         string            `toml:"script"`   // Scenario script of timed events; empty for none
+	Training       bool              `toml:"training"` // Run the script as a scored training exercise
+	Journal        string            `toml:"journal"`  // Command journal to record for replay; empty for none
 	Consumption    ConsumptionConfig `toml:"consumption"`
+
+	// GeneticHealthThreshold is the genetic health score, 0-100, below
+	// which the monthly genetic health check alerts
+	GeneticHealthThreshold int `toml:"genetic_health_threshold"`
 }
 
-// ConsumptionConfig controls resource consumption variance.
+// ConsumptionConfig controls resource consumption variance, the order lots
+// are consumed in and how early expiring stock raises alerts.
 type ConsumptionConfig struct {
-	CalorieVariance     float64 `toml:"calorie_variance"`
-	WaterVariance       float64 `toml:"water_variance"`
-	EfficiencyDecayRate float64 `toml:"efficiency_decay_rate"`
+	CalorieVariance       float64           `toml:"calorie_variance"`
+	WaterVariance         float64           `toml:"water_variance"`
+	EfficiencyDecayRate   float64           `toml:"efficiency_decay_rate"`
+	Policy                ConsumptionPolicy `toml:"policy"`
+	ExpirationWarningDays int               `toml:"expiration_warning_days"`
 }
 
+// ConsumptionPolicy decides which lots consumption draws from first.
+type ConsumptionPolicy string
+
+const (
+	ConsumptionPolicyFIFO ConsumptionPolicy = "fifo" // Oldest received first
+	ConsumptionPolicyFEFO ConsumptionPolicy = "fefo" // Soonest-expiring first
+)
+
 // EventFrequency controls how often random events occur.
 type EventFrequency string
 
@@ -97,13 +221,140 @@ const (
 	EventFrequencyChaotic   EventFrequency = "chaotic"
 )
 
+// EventFrequencies lists the event frequencies, least eventful first.
+var EventFrequencies = []EventFrequency{
+	EventFrequencyMinimal,
+	EventFrequencyReduced,
+	EventFrequencyNormal,
+	EventFrequencyIncreased,
+	EventFrequencyChaotic,
+}
+
+// Multiplier returns how much the frequency scales random event rates.
+func (f EventFrequency) Multiplier() float64 {
+	switch f {
+	case EventFrequencyMinimal:
+		return 0.25
+	case EventFrequencyReduced:
+		return 0.5
+	case EventFrequencyIncreased:
+		return 2
+	case EventFrequencyChaotic:
+		return 4
+	default:
+		return 1
+	}
+}
+
 // DisplayConfig controls TUI appearance.
 type DisplayConfig struct {
-	ColorScheme ColorScheme `toml:"color_scheme"`
-	ScanLines   bool        `toml:"scan_lines"`
-	Flicker     bool        `toml:"flicker"`
-	DateFormat  string      `toml:"date_format"`
-	TimeFormat  string      `toml:"time_format"`
+	ColorScheme     ColorScheme      `toml:"color_scheme"`
+	ScanLines       bool             `toml:"scan_lines"`
+	Flicker         bool             `toml:"flicker"`
+	ScreenReader    bool             `toml:"screen_reader"` // Plain text for screen readers: no box drawing or color
+	Locale          i18n.Locale      `toml:"locale"`        // Language of the interface: en, es or zh
+	DateFormat      string           `toml:"date_format"`   // Go layout; empty for the locale's
+	TimeFormat      string           `toml:"time_format"`   // Go layout; empty for the locale's
+	TimeZone        string           `toml:"time_zone"`     // IANA zone times are shown in; empty for UTC
+	Calendar        CalendarMode     `toml:"calendar"`
+	DashboardPanels []DashboardPanel `toml:"dashboard_panels"` // Shown in order; empty for DefaultDashboardPanels
+}
+
+// Location returns the time zone times are shown in.
+func (d *DisplayConfig) Location() (*time.Location, error) {
+	if d.TimeZone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(d.TimeZone)
+}
+
+// Language returns the locale the interface is shown in.
+func (d *DisplayConfig) Language() i18n.Locale {
+	if d.Locale == "" {
+		return i18n.English
+	}
+	return d.Locale
+}
+
+// DateLayout returns the Go layout dates are shown in: date_format, or the
+// locale's if it is not set.
+func (d *DisplayConfig) DateLayout() string {
+	if d.DateFormat == "" {
+		return d.Language().DateLayout()
+	}
+	return d.DateFormat
+}
+
+// TimeLayout returns the Go layout times of day are shown in: time_format,
+// or the locale's if it is not set.
+func (d *DisplayConfig) TimeLayout() string {
+	if d.TimeFormat == "" {
+		return d.Language().TimeLayout()
+	}
+	return d.TimeFormat
+}
+
+// CalendarMode selects how dates are shown.
+type CalendarMode string
+
+const (
+	// CalendarGregorian shows dates with date_format.
+	CalendarGregorian CalendarMode = "gregorian"
+	// CalendarVault shows dates from the seal on as days since the vault
+	// was sealed, e.g. "Day 4512".
+	CalendarVault CalendarMode = "vault"
+)
+
+// Valid returns true if the calendar mode is known.
+func (m CalendarMode) Valid() bool {
+	return m == CalendarGregorian || m == CalendarVault
+}
+
+// Panels returns the dashboard panels to show, in order.
+func (d *DisplayConfig) Panels() []DashboardPanel {
+	if len(d.DashboardPanels) == 0 {
+		return DefaultDashboardPanels
+	}
+	return d.DashboardPanels
+}
+
+// DashboardPanel names a panel of the TUI dashboard.
+type DashboardPanel string
+
+const (
+	DashboardPanelPopulation DashboardPanel = "population"
+	DashboardPanelFacilities DashboardPanel = "facilities"
+	DashboardPanelResources  DashboardPanel = "resources"
+	DashboardPanelSimulation DashboardPanel = "simulation"
+	DashboardPanelEfficiency DashboardPanel = "efficiency"
+	DashboardPanelAlerts     DashboardPanel = "alerts"
+	DashboardPanelShortages  DashboardPanel = "shortages"
+)
+
+// DashboardPanels lists every dashboard panel.
+var DashboardPanels = []DashboardPanel{
+	DashboardPanelPopulation,
+	DashboardPanelFacilities,
+	DashboardPanelResources,
+	DashboardPanelSimulation,
+	DashboardPanelEfficiency,
+	DashboardPanelAlerts,
+	DashboardPanelShortages,
+}
+
+// DefaultDashboardPanels are the dashboard panels shown, in order, when
+// none are configured.
+var DefaultDashboardPanels = []DashboardPanel{
+	DashboardPanelPopulation,
+	DashboardPanelFacilities,
+	DashboardPanelResources,
+	DashboardPanelSimulation,
+	DashboardPanelEfficiency,
+}
+
+// Valid returns true if the panel is one of DashboardPanels.
+func (p DashboardPanel) Valid() bool {
+	return slices.Contains(DashboardPanels, p)
 }
 
 // ColorScheme defines the terminal color palette.
@@ -112,9 +363,48 @@ type ColorScheme string
 const (
 	ColorSchemeGreenPhosphor ColorScheme = "green_phosphor"
 	ColorSchemeAmber         ColorScheme = "amber"
+	ColorSchemeBlue          ColorScheme = "blue"
 	ColorSchemeWhite         ColorScheme = "white"
 )
 
+// ColorSchemes lists the available color schemes in display order.
+var ColorSchemes = []ColorScheme{
+	ColorSchemeGreenPhosphor,
+	ColorSchemeAmber,
+	ColorSchemeBlue,
+	ColorSchemeWhite,
+}
+
+// Valid returns true if the color scheme is one of ColorSchemes.
+func (c ColorScheme) Valid() bool {
+	for _, scheme := range ColorSchemes {
+		if c == scheme {
+			return true
+		}
+	}
+	return false
+}
+
+// Next returns the color scheme after c in ColorSchemes, wrapping around.
+func (c ColorScheme) Next() ColorScheme {
+	for i, scheme := range ColorSchemes {
+		if c == scheme {
+			return ColorSchemes[(i+1)%len(ColorSchemes)]
+		}
+	}
+	return ColorSchemes[0]
+}
+
+// Prev returns the color scheme before c in ColorSchemes, wrapping around.
+func (c ColorScheme) Prev() ColorScheme {
+	for i, scheme := range ColorSchemes {
+		if c == scheme {
+			return ColorSchemes[(i+len(ColorSchemes)-1)%len(ColorSchemes)]
+		}
+	}
+	return ColorSchemes[0]
+}
+
 // LoggingConfig controls application logging.
 type LoggingConfig struct {
 	Level      LogLevel `toml:"level"`
@@ -138,30 +428,309 @@ type DatabaseConfig struct {
 	Path                string `toml:"path"`
 	BackupIntervalHours int    `toml:"backup_interval_hours"`
 	BackupRetentionDays int    `toml:"backup_retention_days"`
+
+	// ReadOnly opens the database read-only for kiosk terminals: no
+	// migrations, backups or simulation writes, and the TUI disables every
+	// form and action that would change data.
+	ReadOnly bool `toml:"read_only"`
+
+	// SQLite tuning, applied as PRAGMAs when the database opens.
+	JournalMode   string `toml:"journal_mode"`    // wal | delete | truncate | persist | memory
+	Synchronous   string `toml:"synchronous"`     // off | normal | full | extra
+	BusyTimeoutMS int    `toml:"busy_timeout_ms"` // Wait for locks before SQLITE_BUSY; 0 fails at once
+	CacheSizeMB   int    `toml:"cache_size_mb"`   // Page cache; 0 keeps SQLite's default
+	MmapSizeMB    int    `toml:"mmap_size_mb"`    // Memory-mapped reads; 0 disables
+
+	// Connections is how many connections reads share; writes queue for one
+	// at a time. 1 serializes reads with writes.
+	Connections int `toml:"connections"`
+
+	// SlowQueryMS is how long a statement may run before it is logged, its
+	// parameters redacted; 0 logs none.
+	SlowQueryMS int `toml:"slow_query_ms"`
+
+	Encryption EncryptionConfig `toml:"encryption"`
+}
+
+// EncryptionConfig configures the encryption at rest of sensitive columns,
+// such as resident notes and medical findings. The master key is never
+// stored in the configuration: it is read from an environment variable or,
+// when that is unset, from a key file.
+type EncryptionConfig struct {
+	Enabled bool   `toml:"enabled"`
+	KeyEnv  string `toml:"key_env"`  // Environment variable holding the base64 master key
+	KeyFile string `toml:"key_file"` // File holding it, when the variable is unset
+}
+
+// MasterKey reads the master key from the environment or the key file.
+func (e *EncryptionConfig) MasterKey() ([]byte, error) {
+	if e.KeyEnv != "" {
+		if encoded := os.Getenv(e.KeyEnv); encoded != "" {
+			key, err := vaultcrypt.ParseMasterKey(encoded)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", e.KeyEnv, err)
+			}
+			return key, nil
+		}
+	}
+	if e.KeyFile == "" {
+		return nil, fmt.Errorf("no master key: set %s or encryption.key_file", e.KeyEnv)
+	}
+	data, err := os.ReadFile(e.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading master key: %w", err)
+	}
+	key, err := vaultcrypt.ParseMasterKey(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", e.KeyFile, err)
+	}
+	return key, nil
+}
+
+// GRPCConfig configures the inter-vault exchange server, `vtuos grpc-serve`.
+type GRPCConfig struct {
+	Listen       string     `toml:"listen"`         // host:port
+	TLSCertFile  string     `toml:"tls_cert_file"`  // Server certificate (PEM)
+	TLSKeyFile   string     `toml:"tls_key_file"`   // Server private key (PEM)
+	ClientCAFile string     `toml:"client_ca_file"` // Require client certificates signed by this CA
+	Insecure     bool       `toml:"insecure"`       // Serve without TLS; for testing on loopback only
+	Peers        []GRPCPeer `toml:"peers"`
+}
+
+// GRPCPeer is a vault allowed to call the exchange server. Calls present the
+// token and are limited to methods at or below the peer's clearance.
+type GRPCPeer struct {
+	Name      string `toml:"name"`
+	Token     string `toml:"token"`
+	Clearance int    `toml:"clearance"` // 1-10, as for residents
+}
+
+// ExportConfig is the anonymization policy of `vtuos export hq`, the
+// reports sent to Vault-Tec headquarters.
+type ExportConfig struct {
+	Mode          ExportMode `toml:"mode"`
+	MinGroupSize  int        `toml:"min_group_size"` // Suppress demographic groups smaller than this
+	AgeBandYears  int        `toml:"age_band_years"` // Width of the age bands that replace birth dates
+	DatePrecision string     `toml:"date_precision"` // day | month | year
+	PseudonymKey  string     `toml:"pseudonym_key"`  // Secret keying resident and household pseudonyms
+	Datasets      []string   `toml:"datasets"`       // demographics | consumption | maintenance
+}
+
+// TransferConfig is the key of the resident transfer bundles exchanged with
+// other vaults by `vtuos transfer`.
+type TransferConfig struct {
+	// Key is the secret shared with the vaults residents are transferred
+	// to and from. Bundles are signed with it, and only bundles signed with
+	// it are admitted.
+	Key string `toml:"key"`
+}
+
+// ExportMode selects how much detail an HQ export contains.
+type ExportMode string
+
+const (
+	// ExportModeAggregate exports counts and totals only.
+	ExportModeAggregate ExportMode = "aggregate"
+	// ExportModePseudonymized exports one row per record, with residents and
+	// households replaced by keyed pseudonyms.
+	ExportModePseudonymized ExportMode = "pseudonymized"
+)
+
+// PrivacyConfig gates residents' personal details by the clearance of the
+// operator signed on: blood types, notes, where disciplinary history is
+// kept, medical officers' notes and the reasons of status periods.
+type PrivacyConfig struct {
+	// Clearance, 1-10, an operator needs to see personal details, in the
+	// terminal and in analytics exports; below it they are redacted. 0 shows
+	// them to everyone.
+	PIIClearance int `toml:"pii_clearance"`
+}
+
+// ProfileConfig is a named environment sharing the rest of the
+// configuration, e.g. a training vault with its own data directory and a
+// small seeded population, or development against a throwaway database.
+type ProfileConfig struct {
+	DataDir     string `toml:"data_dir"`     // Directory of the database, backups, snapshots, reports and badges
+	Database    string `toml:"database"`     // Database file, in data_dir if relative; defaults to database.path
+	SeedProfile string `toml:"seed_profile"` // Seed generation profile of -seed without -seed-profile
+}
+
+// EventsConfig configures where the terminal forwards vault events, such as
+// resident.created, stock.depleted and system.failed, for external
+// monitoring. With no sinks no events are published.
+type EventsConfig struct {
+	Buffer int         `toml:"buffer"` // Events held for slow sinks; further events are dropped
+	Sinks  []EventSink `toml:"sinks"`
+}
+
+// EventSink is a destination of vault events, configured as an
+// [[events.sinks]] table.
+type EventSink struct {
+	Type           EventSinkType `toml:"type"`
+	Path           string        `toml:"path"`            // File to append to, or UNIX socket to write to
+	URL            string        `toml:"url"`             // Webhook to POST to
+	Secret         string        `toml:"secret"`          // Signs webhook requests with HMAC-SHA256; empty for none
+	TimeoutSeconds int           `toml:"timeout_seconds"` // Bounds each webhook call or socket write; 0 for 5
+	Types          []string      `toml:"types"`           // Event types forwarded, e.g. "stock.*"; empty for all
 }
 
+// EventSinkType is where an event sink delivers events.
+type EventSinkType string
+
+const (
+	// EventSinkFile appends events to a file as JSON lines.
+	EventSinkFile EventSinkType = "file"
+	// EventSinkSocket writes events as JSON lines to a UNIX socket.
+	EventSinkSocket EventSinkType = "socket"
+	// EventSinkWebhook POSTs each event as JSON to a URL.
+	EventSinkWebhook EventSinkType = "webhook"
+)
+
+// Timeout returns the bound on each webhook call or socket write, 0 for the
+// sink's default.
+func (e EventSink) Timeout() time.Duration {
+	return time.Duration(e.TimeoutSeconds) * time.Second
+}
+
+// AlertsConfig configures the recorded alerts' escalation.
+type AlertsConfig struct {
+	// Vault hours an alert may stay unacknowledged before it is escalated,
+	// unless its routing rule sets its own; 0 escalates only alerts whose
+	// rule does
+	EscalateAfterHours int `toml:"escalate_after_hours"`
+}
+
+// EscalateAfter returns how long an alert may stay unacknowledged, in vault
+// time, 0 if alerts are not escalated.
+func (a AlertsConfig) EscalateAfter() time.Duration {
+	return time.Duration(a.EscalateAfterHours) * time.Hour
+}
+
+// TimeoutsConfig bounds how long an operation started from the terminal may
+// run before it is cancelled. A bound of 0 lets it run until it finishes or
+// the terminal shuts down.
+type TimeoutsConfig struct {
+	OperationSeconds int `toml:"operation_seconds"` // Lookups, lists and commands
+	ReportSeconds    int `toml:"report_seconds"`    // Reports, forecasts, simulation ticks and scheduled jobs
+}
+
+// Operation returns the bound on lookups, lists and commands.
+func (t TimeoutsConfig) Operation() time.Duration {
+	return time.Duration(t.OperationSeconds) * time.Second
+}
+
+// Report returns the bound on reports and batch work.
+func (t TimeoutsConfig) Report() time.Duration {
+	return time.Duration(t.ReportSeconds) * time.Second
+}
+
+// Valid SQLite journal modes and synchronous levels.
+var (
+	journalModes      = map[string]bool{"wal": true, "delete": true, "truncate": true, "persist": true, "memory": true}
+	synchronousLevels = map[string]bool{"off": true, "normal": true, "full": true, "extra": true}
+)
+
+// Valid export date precisions and datasets.
+var (
+	exportDatePrecisions = map[string]bool{"day": true, "month": true, "year": true}
+	exportDatasets       = map[string]bool{"demographics": true, "consumption": true, "maintenance": true}
+)
+
 // Validate checks that the configuration is valid.
 func (c *Config) Validate() error {
 	var errs []error
 
 	if err := c.Vault.Validate(); err != nil {
-		errs = append(errs, fmt.Errorf("vault: %w", err))
+		errs = append(errs, within("vault", err))
+	}
+
+	numbers := map[int]bool{c.Vault.Number: true}
+	for i, v := range c.Vaults {
+		if err := v.Validate(); err != nil {
+			errs = append(errs, within(fmt.Sprintf("vaults[%d]", i), err))
+		}
+		if numbers[v.Number] {
+			errs = append(errs, fmt.Errorf("vaults[%d]: vault number %d is already managed", i, v.Number))
+		}
+		numbers[v.Number] = true
+	}
+
+	// Vaults share the registry, so their numbers must tell them apart
+	if len(c.Vaults) > 0 {
+		for _, v := range c.ManagedVaults() {
+			format, err := util.ParseRegistryFormat(v.RegistryFormat)
+			if err == nil && !format.HasVault() {
+				errs = append(errs, fmt.Errorf("vault %d: registry_format %q must hold {vault} when several vaults are managed", v.Number, v.RegistryFormat))
+			}
+		}
 	}
 
 	if err := c.Simulation.Validate(); err != nil {
-		errs = append(errs, fmt.Errorf("simulation: %w", err))
+		errs = append(errs, within("simulation", err))
 	}
 
 	if err := c.Display.Validate(); err != nil {
-		errs = append(errs, fmt.Errorf("display: %w", err))
+		errs = append(errs, within("display", err))
+	}
+	if c.Display.Calendar == CalendarVault {
+		if _, err := c.Vault.SealedDateTime(); err != nil {
+			errs = append(errs, fmt.Errorf("display: the vault calendar needs vault.sealed_date: %w", err))
+		}
 	}
 
 	if err := c.Logging.Validate(); err != nil {
-		errs = append(errs, fmt.Errorf("logging: %w", err))
+		errs = append(errs, within("logging", err))
 	}
 
 	if err := c.Database.Validate(); err != nil {
-		errs = append(errs, fmt.Errorf("database: %w", err))
+		errs = append(errs, within("database", err))
+	}
+
+	if err := c.GRPC.Validate(); err != nil {
+		errs = append(errs, within("grpc", err))
+	}
+
+	if err := c.Export.Validate(); err != nil {
+		errs = append(errs, within("export", err))
+	}
+
+	if n := len(c.Transfer.Key); n > 0 && n < 16 {
+		errs = append(errs, fmt.Errorf("transfer: key must be at least 16 characters (got %d)", n))
+	}
+
+	if err := c.Events.Validate(); err != nil {
+		errs = append(errs, within("events", err))
+	}
+
+	if c.Privacy.PIIClearance < 0 || c.Privacy.PIIClearance > 10 {
+		errs = append(errs, fmt.Errorf("privacy: pii_clearance must be between 0 and 10 (got %d)", c.Privacy.PIIClearance))
+	}
+
+	if c.Alerts.EscalateAfterHours < 0 {
+		errs = append(errs, fmt.Errorf("alerts: escalate_after_hours must be non-negative (got %d)", c.Alerts.EscalateAfterHours))
+	}
+
+	if err := c.Timeouts.Validate(); err != nil {
+		errs = append(errs, within("timeouts", err))
+	}
+
+	// A profile is meant to keep its records apart, so two may not share
+	// a database, nor may one naming a database name the live vault's; one
+	// setting neither shares the live vault's on purpose
+	live := profileStore(c, ProfileConfig{})
+	stores := make(map[string]string)
+	for _, name := range c.ProfileNames() {
+		p := c.Profiles[name]
+		if p.DataDir == "" && p.Database == "" {
+			continue
+		}
+		store := profileStore(c, p)
+		if store == live {
+			errs = append(errs, fmt.Errorf("profiles.%s: database %s is the live vault's", name, store))
+		} else if other, ok := stores[store]; ok {
+			errs = append(errs, fmt.Errorf("profiles.%s: database %s is that of profile %s", name, store, other))
+		}
+		stores[store] = name
 	}
 
 	if len(errs) > 0 {
@@ -180,15 +749,15 @@ func (v *VaultConfig) Validate() error {
 	}
 
 	if v.Number < 1 || v.Number > 999 {
-		errs = append(errs, errors.New("number must be between 1 and 999"))
+		errs = append(errs, fmt.Errorf("number must be between 1 and 999 (got %d)", v.Number))
 	}
 
 	if v.DesignedCapacity < 1 {
-		errs = append(errs, errors.New("designed_capacity must be positive"))
+		errs = append(errs, fmt.Errorf("designed_capacity must be greater than 0 (got %d)", v.DesignedCapacity))
 	}
 
 	if v.VaultType != VaultTypeControl && v.VaultType != VaultTypeExperimental {
-		errs = append(errs, fmt.Errorf("invalid vault_type: %s", v.VaultType))
+		errs = append(errs, fmt.Errorf("invalid vault_type %q (want %s)", v.VaultType, choices([]VaultType{VaultTypeControl, VaultTypeExperimental})))
 	}
 
 	if v.SealedDate != "" {
@@ -197,6 +766,39 @@ func (v *VaultConfig) Validate() error {
 		}
 	}
 
+	if _, err := util.ParseRegistryFormat(v.RegistryFormat); err != nil {
+		errs = append(errs, err)
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	return nil
+}
+
+// Validate checks that the managed vault configuration is valid.
+func (v *ManagedVaultConfig) Validate() error {
+	var errs []error
+
+	if v.Designation == "" {
+		errs = append(errs, errors.New("designation is required"))
+	}
+
+	if v.Number < 1 || v.Number > 999 {
+		errs = append(errs, fmt.Errorf("number must be between 1 and 999 (got %d)", v.Number))
+	}
+
+	if v.DesignedCapacity < 1 {
+		errs = append(errs, fmt.Errorf("designed_capacity must be greater than 0 (got %d)", v.DesignedCapacity))
+	}
+
+	if v.RegistryFormat != "" {
+		if _, err := util.ParseRegistryFormat(v.RegistryFormat); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
 	if len(errs) > 0 {
 		return errors.Join(errs...)
 	}
@@ -208,8 +810,8 @@ func (v *VaultConfig) Validate() error {
 func (s *SimulationConfig) Validate() error {
 	var errs []error
 
-	if s.TimeScale < 0 {
-		errs = append(errs, errors.New("time_scale must be non-negative"))
+	if s.TimeScale <= 0 {
+		errs = append(errs, fmt.Errorf("time_scale must be greater than 0 (got %g); 1 runs in real time, 60 an hour a minute", s.TimeScale))
 	}
 
 	validFrequencies := map[EventFrequency]bool{
@@ -221,7 +823,11 @@ func (s *SimulationConfig) Validate() error {
 	}
 
 	if !validFrequencies[s.EventFrequency] && s.EventFrequency != "" {
-		errs = append(errs, fmt.Errorf("invalid event_frequency: %s", s.EventFrequency))
+		errs = append(errs, fmt.Errorf("invalid event_frequency %q (want %s)", s.EventFrequency, choices(EventFrequencies)))
+	}
+
+	if s.Training && s.Script == "" {
+		errs = append(errs, errors.New("training requires a script: the scenario to train on"))
 	}
 
 	if s.StartDate != "" {
@@ -231,11 +837,25 @@ func (s *SimulationConfig) Validate() error {
 	}
 
 	if s.Consumption.CalorieVariance < 0 || s.Consumption.CalorieVariance > 1 {
-		errs = append(errs, errors.New("calorie_variance must be between 0 and 1"))
+		errs = append(errs, fmt.Errorf("consumption.calorie_variance must be between 0 and 1 (got %g)", s.Consumption.CalorieVariance))
 	}
 
 	if s.Consumption.WaterVariance < 0 || s.Consumption.WaterVariance > 1 {
-		errs = append(errs, errors.New("water_variance must be between 0 and 1"))
+		errs = append(errs, fmt.Errorf("consumption.water_variance must be between 0 and 1 (got %g)", s.Consumption.WaterVariance))
+	}
+
+	switch s.Consumption.Policy {
+	case "", ConsumptionPolicyFIFO, ConsumptionPolicyFEFO:
+	default:
+		errs = append(errs, fmt.Errorf("invalid consumption.policy %q (want %s)", s.Consumption.Policy, choices([]ConsumptionPolicy{ConsumptionPolicyFEFO, ConsumptionPolicyFIFO})))
+	}
+
+	if s.Consumption.ExpirationWarningDays < 0 {
+		errs = append(errs, fmt.Errorf("consumption.expiration_warning_days must be non-negative (got %d)", s.Consumption.ExpirationWarningDays))
+	}
+
+	if s.GeneticHealthThreshold < 0 || s.GeneticHealthThreshold > 100 {
+		errs = append(errs, fmt.Errorf("genetic_health_threshold must be between 0 and 100 (got %d)", s.GeneticHealthThreshold))
 	}
 
 	if len(errs) > 0 {
@@ -249,14 +869,36 @@ func (s *SimulationConfig) Validate() error {
 func (d *DisplayConfig) Validate() error {
 	var errs []error
 
-	validSchemes := map[ColorScheme]bool{
-		ColorSchemeGreenPhosphor: true,
-		ColorSchemeAmber:         true,
-		ColorSchemeWhite:         true,
+	if !d.ColorScheme.Valid() && d.ColorScheme != "" {
+		errs = append(errs, fmt.Errorf("invalid color_scheme %q (want %s)", d.ColorScheme, choices(ColorSchemes)))
+	}
+
+	if !d.Locale.Valid() && d.Locale != "" {
+		errs = append(errs, fmt.Errorf("invalid locale %q (want %s)", d.Locale, choices(i18n.Locales)))
+	}
+
+	if d.DateFormat != "" && !validLayout(d.DateFormat) {
+		errs = append(errs, fmt.Errorf("invalid date_format (expected a Go time layout, e.g. 2006-01-02): %s", d.DateFormat))
+	}
+
+	if d.TimeFormat != "" && !validLayout(d.TimeFormat) {
+		errs = append(errs, fmt.Errorf("invalid time_format (expected a Go time layout, e.g. 15:04:05): %s", d.TimeFormat))
 	}
 
-	if !validSchemes[d.ColorScheme] && d.ColorScheme != "" {
-		errs = append(errs, fmt.Errorf("invalid color_scheme: %s", d.ColorScheme))
+	if _, err := d.Location(); err != nil {
+		errs = append(errs, fmt.Errorf("invalid time_zone (expected an IANA zone, e.g. America/New_York): %s", d.TimeZone))
+	}
+
+	if !d.Calendar.Valid() && d.Calendar != "" {
+		errs = append(errs, fmt.Errorf("invalid calendar %q (want %s)", d.Calendar, choices([]CalendarMode{CalendarGregorian, CalendarVault})))
+	}
+
+	for i, panel := range d.DashboardPanels {
+		if !panel.Valid() {
+			errs = append(errs, fmt.Errorf("invalid dashboard_panels entry %q (want %s)", panel, choices(DashboardPanels)))
+		} else if slices.Contains(d.DashboardPanels[:i], panel) {
+			errs = append(errs, fmt.Errorf("dashboard_panels lists %s twice", panel))
+		}
 	}
 
 	if len(errs) > 0 {
@@ -266,6 +908,13 @@ func (d *DisplayConfig) Validate() error {
 	return nil
 }
 
+// validLayout reports whether a time layout formats any part of a time,
+// rather than being text that formats as itself.
+func validLayout(layout string) bool {
+	ref := time.Date(2077, 10, 23, 9, 47, 0, 0, time.UTC)
+	return ref.Format(layout) != layout
+}
+
 // Validate checks that the logging configuration is valid.
 func (l *LoggingConfig) Validate() error {
 	var errs []error
@@ -278,7 +927,7 @@ func (l *LoggingConfig) Validate() error {
 	}
 
 	if !validLevels[l.Level] && l.Level != "" {
-		errs = append(errs, fmt.Errorf("invalid log level: %s", l.Level))
+		errs = append(errs, fmt.Errorf("invalid level %q (want %s)", l.Level, choices([]LogLevel{LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError})))
 	}
 
 	if l.MaxSizeMB < 0 {
@@ -312,6 +961,175 @@ func (d *DatabaseConfig) Validate() error {
 		errs = append(errs, errors.New("backup_retention_days must be non-negative"))
 	}
 
+	if d.JournalMode != "" && !journalModes[strings.ToLower(d.JournalMode)] {
+		errs = append(errs, fmt.Errorf("invalid journal_mode %q (want %s)", d.JournalMode, choices([]string{"wal", "delete", "truncate", "persist", "memory"})))
+	}
+
+	if d.Synchronous != "" && !synchronousLevels[strings.ToLower(d.Synchronous)] {
+		errs = append(errs, fmt.Errorf("invalid synchronous %q (want %s)", d.Synchronous, choices([]string{"off", "normal", "full", "extra"})))
+	}
+
+	if d.BusyTimeoutMS < 0 {
+		errs = append(errs, errors.New("busy_timeout_ms must be non-negative"))
+	}
+
+	if d.CacheSizeMB < 0 {
+		errs = append(errs, errors.New("cache_size_mb must be non-negative"))
+	}
+
+	if d.MmapSizeMB < 0 {
+		errs = append(errs, errors.New("mmap_size_mb must be non-negative"))
+	}
+
+	if d.Connections < 0 {
+		errs = append(errs, errors.New("connections must be non-negative"))
+	}
+
+	if d.SlowQueryMS < 0 {
+		errs = append(errs, errors.New("slow_query_ms must be non-negative"))
+	}
+
+	if d.Encryption.Enabled && d.Encryption.KeyEnv == "" && d.Encryption.KeyFile == "" {
+		errs = append(errs, errors.New("encryption.enabled needs encryption.key_env or encryption.key_file"))
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	return nil
+}
+
+// Validate checks that the gRPC configuration is valid. Whether TLS is
+// configured is checked when the server starts, as the TUI never uses it.
+func (g *GRPCConfig) Validate() error {
+	var errs []error
+
+	if (g.TLSCertFile == "") != (g.TLSKeyFile == "") {
+		errs = append(errs, errors.New("tls_cert_file and tls_key_file must be set together"))
+	}
+
+	if g.ClientCAFile != "" && g.TLSCertFile == "" {
+		errs = append(errs, errors.New("client_ca_file requires tls_cert_file and tls_key_file"))
+	}
+
+	names := make(map[string]bool)
+	tokens := make(map[string]bool)
+	for i, p := range g.Peers {
+		if p.Name == "" {
+			errs = append(errs, fmt.Errorf("peers[%d]: name is required", i))
+		} else if names[p.Name] {
+			errs = append(errs, fmt.Errorf("peers[%d]: duplicate name %s", i, p.Name))
+		}
+		names[p.Name] = true
+
+		if p.Token == "" {
+			errs = append(errs, fmt.Errorf("peers[%d]: token is required", i))
+		} else if tokens[p.Token] {
+			errs = append(errs, fmt.Errorf("peers[%d]: token is shared with another peer", i))
+		}
+		tokens[p.Token] = true
+
+		if p.Clearance < 1 || p.Clearance > 10 {
+			errs = append(errs, fmt.Errorf("peers[%d]: clearance must be between 1 and 10", i))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	return nil
+}
+
+// Validate checks that the export policy is valid. The pseudonym key is
+// only required when an export is pseudonymized.
+func (e *ExportConfig) Validate() error {
+	var errs []error
+
+	if e.Mode != ExportModeAggregate && e.Mode != ExportModePseudonymized && e.Mode != "" {
+		errs = append(errs, fmt.Errorf("invalid mode %q (want %s)", e.Mode, choices([]ExportMode{ExportModeAggregate, ExportModePseudonymized})))
+	}
+
+	if e.Mode == ExportModePseudonymized && len(e.PseudonymKey) < 16 {
+		errs = append(errs, errors.New("pseudonym_key of at least 16 characters is required in pseudonymized mode"))
+	}
+
+	if e.MinGroupSize < 1 {
+		errs = append(errs, errors.New("min_group_size must be at least 1"))
+	}
+
+	if e.AgeBandYears < 1 {
+		errs = append(errs, errors.New("age_band_years must be at least 1"))
+	}
+
+	if e.DatePrecision != "" && !exportDatePrecisions[e.DatePrecision] {
+		errs = append(errs, fmt.Errorf("invalid date_precision %q (want %s)", e.DatePrecision, choices([]string{"day", "month", "year"})))
+	}
+
+	for _, name := range e.Datasets {
+		if !exportDatasets[name] {
+			errs = append(errs, fmt.Errorf("unknown dataset %q in datasets (want %s)", name, choices([]string{"demographics", "consumption", "maintenance"})))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	return nil
+}
+
+// Validate checks that the event sinks are valid.
+func (e *EventsConfig) Validate() error {
+	var errs []error
+
+	if e.Buffer < 0 {
+		errs = append(errs, errors.New("buffer must be non-negative"))
+	}
+
+	for i, sink := range e.Sinks {
+		switch sink.Type {
+		case EventSinkFile, EventSinkSocket:
+			if sink.Path == "" {
+				errs = append(errs, fmt.Errorf("sinks[%d]: path is required for a %s sink", i, sink.Type))
+			}
+		case EventSinkWebhook:
+			if !strings.HasPrefix(sink.URL, "http://") && !strings.HasPrefix(sink.URL, "https://") {
+				errs = append(errs, fmt.Errorf("sinks[%d]: url must be an http or https URL", i))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("sinks[%d]: invalid type: %q (want file, socket or webhook)", i, sink.Type))
+		}
+		if sink.TimeoutSeconds < 0 {
+			errs = append(errs, fmt.Errorf("sinks[%d]: timeout_seconds must be non-negative", i))
+		}
+		for _, pattern := range sink.Types {
+			if pattern == "" {
+				errs = append(errs, fmt.Errorf("sinks[%d]: empty event type", i))
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	return nil
+}
+
+// Validate checks that the timeouts are valid.
+func (t *TimeoutsConfig) Validate() error {
+	var errs []error
+
+	if t.OperationSeconds < 0 {
+		errs = append(errs, errors.New("operation_seconds must be non-negative"))
+	}
+
+	if t.ReportSeconds < 0 {
+		errs = append(errs, errors.New("report_seconds must be non-negative"))
+	}
+
 	if len(errs) > 0 {
 		return errors.Join(errs...)
 	}
@@ -330,6 +1148,7 @@ func Default() *Config {
 			SealedDate:       "2077-10-23T09:47:00Z",
 			DesignedCapacity: 500,
 			VaultType:        VaultTypeControl,
+			RegistryFormat:   util.DefaultRegistryFormat,
 			Location: VaultLocation{
 				Latitude:    39.6295,
 				Longitude:   -79.9559,
@@ -352,17 +1171,22 @@ func Default() *Config {
 			EventFrequency: EventFrequencyNormal,
 			StartDate:      "2077-10-23T09:47:00Z",
 			Consumption: ConsumptionConfig{
-				CalorieVariance:     0.1,
-				WaterVariance:       0.1,
-				EfficiencyDecayRate: 0.001,
+				CalorieVariance:       0.1,
+				WaterVariance:         0.1,
+				EfficiencyDecayRate:   0.001,
+				Policy:                ConsumptionPolicyFEFO,
+				ExpirationWarningDays: 14,
 			},
+			GeneticHealthThreshold: 60,
 		},
 		Display: DisplayConfig{
-			ColorScheme: ColorSchemeGreenPhosphor,
-			ScanLines:   true,
-			Flicker:     false,
-			DateFormat:  "2006-01-02",
-			TimeFormat:  "15:04:05",
+			ColorScheme:     ColorSchemeGreenPhosphor,
+			ScanLines:       true,
+			Flicker:         false,
+			Locale:          i18n.English,
+			TimeZone:        "UTC",
+			Calendar:        CalendarGregorian,
+			DashboardPanels: slices.Clone(DefaultDashboardPanels),
 		},
 		Logging: LoggingConfig{
 			Level:      LogLevelInfo,
@@ -374,6 +1198,36 @@ func Default() *Config {
 			Path:                "vault.db",
 			BackupIntervalHours: 24,
 			BackupRetentionDays: 30,
+			JournalMode:         "wal",
+			Synchronous:         "normal",
+			BusyTimeoutMS:       10000,
+			CacheSizeMB:         16,
+			MmapSizeMB:          256,
+			Connections:         4,
+			SlowQueryMS:         250,
+			Encryption: EncryptionConfig{
+				KeyEnv: "VTUOS_ENCRYPTION_KEY",
+			},
+		},
+		GRPC: GRPCConfig{
+			Listen: "127.0.0.1:7076",
+		},
+		Export: ExportConfig{
+			Mode:          ExportModeAggregate,
+			MinGroupSize:  5,
+			AgeBandYears:  10,
+			DatePrecision: "month",
+			Datasets:      []string{"demographics", "consumption", "maintenance"},
+		},
+		Events: EventsConfig{
+			Buffer: 256,
+		},
+		Alerts: AlertsConfig{
+			EscalateAfterHours: 4,
+		},
+		Timeouts: TimeoutsConfig{
+			OperationSeconds: 30,
+			ReportSeconds:    300,
 		},
 	}
 }
@@ -386,6 +1240,20 @@ func (v *VaultConfig) SealedDateTime() (time.Time, error) {
 	return time.Parse(time.RFC3339, v.SealedDate)
 }
 
+// Calendar returns the calendar the configuration shows dates and times
+// in. The configuration must be valid.
+func (c *Config) Calendar() util.Calendar {
+	cal := util.Calendar{
+		DateLayout: c.Display.DateLayout(),
+		TimeLayout: c.Display.TimeLayout(),
+		VaultDays:  c.Display.Calendar == CalendarVault,
+		DayFormat:  c.Display.Language().VaultDay(),
+	}
+	cal.Location, _ = c.Display.Location()
+	cal.Sealed, _ = c.Vault.SealedDateTime()
+	return cal
+}
+
 // StartDateTime returns the simulation start date as a time.Time.
 func (s *SimulationConfig) StartDateTime() (time.Time, error) {
 	if s.StartDate == "" {
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidate_ProfileStores(t *testing.T) {
+	data := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", data)
+	vault := filepath.Join(data, XDGConfigSubdir)
+
+	tests := []struct {
+		name     string
+		profiles map[string]ProfileConfig
+		wantErr  string // "" for none
+	}{
+		{"Own data directory", map[string]ProfileConfig{"training": {DataDir: t.TempDir()}}, ""},
+		{"Own database", map[string]ProfileConfig{"training": {Database: "training.db"}}, ""},
+		{"Shares the live vault on purpose", map[string]ProfileConfig{"readonly": {}}, ""},
+		{"Live database by name", map[string]ProfileConfig{"training": {Database: "vault.db"}}, "profiles.training: database"},
+		{"Live data directory", map[string]ProfileConfig{"training": {DataDir: vault}}, "is the live vault's"},
+		{"Live database by path", map[string]ProfileConfig{"training": {Database: filepath.Join(vault, ".", "vault.db")}}, "is the live vault's"},
+		{"Two profiles on one database", map[string]ProfileConfig{
+			"a": {Database: "training.db"},
+			"b": {DataDir: vault, Database: "training.db"},
+		}, "profiles.b: database " + filepath.Join(vault, "training.db") + " is that of profile a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			cfg.Profiles = tt.profiles
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSharesVault(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	cfg := Default()
+	cfg.Profiles = map[string]ProfileConfig{
+		"readonly": {},
+		"training": {DataDir: t.TempDir()},
+	}
+	for _, tt := range []struct {
+		profile string
+		want    bool
+	}{{"", true}, {"readonly", true}, {"training", false}} {
+		if err := cfg.UseProfile(tt.profile); err != nil {
+			t.Fatalf("UseProfile(%q) error = %v", tt.profile, err)
+		}
+		if got := cfg.SharesVault(); got != tt.want {
+			t.Errorf("SharesVault() in profile %q = %v, want %v", tt.profile, got, tt.want)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 
@@ -108,12 +109,14 @@ func loadFromFile(path string) (*Config, error) {
 	}
 
 	// Parse TOML
-	if _, err := toml.Decode(string(data), cfg); err != nil {
+	md, err := toml.Decode(string(data), cfg)
+	if err != nil {
 		return nil, fmt.Errorf("parsing TOML: %w", err)
 	}
 
-	// Validate
-	if err := cfg.Validate(); err != nil {
+	// Validate, reporting misspelt settings with the invalid ones rather
+	// than leaving them at their defaults unnoticed
+	if err := errors.Join(unknownKeys(md), cfg.Validate()); err != nil {
 		return nil, fmt.Errorf("validating config: %w", err)
 	}
 
@@ -149,12 +152,14 @@ func Save(cfg *Config, path string) error {
 		return fmt.Errorf("writing header: %w", err)
 	}
 
-	// Encode TOML
-	encoder := toml.NewEncoder(f)
-	if err := encoder.Encode(cfg); err != nil {
+	return Encode(f, cfg)
+}
+
+// Encode writes a configuration as TOML.
+func Encode(w io.Writer, cfg *Config) error {
+	if err := toml.NewEncoder(w).Encode(cfg); err != nil {
 		return fmt.Errorf("encoding TOML: %w", err)
 	}
-
 	return nil
 }
 
@@ -213,7 +218,7 @@ func ConfigPath(explicitPath string) string {
 // EnsureDataDir creates the data directory for the database if needed.
 // Returns the absolute path to the database file.
 func EnsureDataDir(cfg *Config) (string, error) {
-	dbPath := cfg.Database.Path
+	dbPath := databasePath(cfg)
 
 	// If absolute path, use as-is
 	if filepath.IsAbs(dbPath) {
@@ -224,17 +229,17 @@ func EnsureDataDir(cfg *Config) (string, error) {
 		return dbPath, nil
 	}
 
-	// For relative paths, check if we should use XDG data directory
-	xdgData := os.Getenv("XDG_DATA_HOME")
-	if xdgData == "" {
-		home, err := os.UserHomeDir()
-		if err == nil {
-			xdgData = filepath.Join(home, ".local", "share")
+	// A profile's data directory must hold its database, rather than fall
+	// back on the current directory and mix its records with others'
+	if _, profile := cfg.Profile(); profile.DataDir != "" {
+		if err := os.MkdirAll(profile.DataDir, 0750); err != nil {
+			return "", fmt.Errorf("creating profile data directory: %w", err)
 		}
+		return filepath.Join(profile.DataDir, dbPath), nil
 	}
 
-	if xdgData != "" {
-		dataDir := filepath.Join(xdgData, XDGConfigSubdir)
+	// For relative paths, check if we should use XDG data directory
+	if dataDir := dataDir(cfg); dataDir != "" {
 		if err := os.MkdirAll(dataDir, 0750); err != nil {
 			// Fall back to current directory
 			return dbPath, nil
@@ -246,6 +251,61 @@ func EnsureDataDir(cfg *Config) (string, error) {
 	return dbPath, nil
 }
 
+// databasePath returns the configured database path: the profile's in use,
+// if it names one.
+func databasePath(cfg *Config) string {
+	if _, profile := cfg.Profile(); profile.Database != "" {
+		return profile.Database
+	}
+	return cfg.Database.Path
+}
+
+// profileStore returns the cleaned absolute path of the database profile p
+// runs on, resolved as EnsureDataDir does, so that two spellings of one
+// file compare equal.
+func profileStore(cfg *Config, p ProfileConfig) string {
+	dbPath := p.Database
+	if dbPath == "" {
+		dbPath = cfg.Database.Path
+	}
+	if !filepath.IsAbs(dbPath) {
+		if p.DataDir != "" {
+			dbPath = filepath.Join(p.DataDir, dbPath)
+		} else if dir := xdgDataDir(); dir != "" {
+			dbPath = filepath.Join(dir, dbPath)
+		}
+	}
+	if abs, err := filepath.Abs(dbPath); err == nil {
+		return abs
+	}
+	return filepath.Clean(dbPath)
+}
+
+// dataDir returns the directory of a database with a relative path and of
+// its backups: the data_dir of the profile in use, or vtuos in the XDG data
+// directory. It returns "" if there is neither.
+func dataDir(cfg *Config) string {
+	if _, profile := cfg.Profile(); profile.DataDir != "" {
+		return profile.DataDir
+	}
+	return xdgDataDir()
+}
+
+// xdgDataDir returns vtuos in the XDG data directory, "" if there is none.
+func xdgDataDir() string {
+	xdgData := os.Getenv("XDG_DATA_HOME")
+	if xdgData == "" {
+		home, err := os.UserHomeDir()
+		if err == nil {
+			xdgData = filepath.Join(home, ".local", "share")
+		}
+	}
+	if xdgData == "" {
+		return ""
+	}
+	return filepath.Join(xdgData, XDGConfigSubdir)
+}
+
 // EnsureLogDir creates the log directory if needed.
 // Returns the absolute path to the log file.
 func EnsureLogDir(cfg *Config) (string, error) {
@@ -278,27 +338,16 @@ func EnsureLogDir(cfg *Config) (string, error) {
 
 // BackupDir returns the directory for database backups.
 func BackupDir(cfg *Config) (string, error) {
-	dbPath := cfg.Database.Path
+	dbPath := databasePath(cfg)
 
 	// Put backups next to the database
 	var backupDir string
 	if filepath.IsAbs(dbPath) {
 		backupDir = filepath.Join(filepath.Dir(dbPath), "backups")
+	} else if dataDir := dataDir(cfg); dataDir != "" {
+		backupDir = filepath.Join(dataDir, "backups")
 	} else {
-		// Check XDG data directory
-		xdgData := os.Getenv("XDG_DATA_HOME")
-		if xdgData == "" {
-			home, err := os.UserHomeDir()
-			if err == nil {
-				xdgData = filepath.Join(home, ".local", "share")
-			}
-		}
-
-		if xdgData != "" {
-			backupDir = filepath.Join(xdgData, XDGConfigSubdir, "backups")
-		} else {
-			backupDir = "backups"
-		}
+		backupDir = "backups"
 	}
 
 	if err := os.MkdirAll(backupDir, 0750); err != nil {
@@ -307,3 +356,34 @@ func BackupDir(cfg *Config) (string, error) {
 
 	return backupDir, nil
 }
+
+// SnapshotDir returns the directory for named snapshots, inside the backup
+// directory but apart from scheduled backups so retention never prunes them.
+func SnapshotDir(cfg *Config) (string, error) {
+	backupDir, err := BackupDir(cfg)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(backupDir, "snapshots"), nil
+}
+
+// BadgeDir returns the directory printed ID badges are written to, beside
+// the backup directory. It is created when the first badge is written.
+func BadgeDir(cfg *Config) (string, error) {
+	backupDir, err := BackupDir(cfg)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(backupDir), "badges"), nil
+}
+
+// ReportDir returns the directory daily vault reports are written to,
+// beside the backup directory. It is created when the first report is
+// written.
+func ReportDir(cfg *Config) (string, error) {
+	backupDir, err := BackupDir(cfg)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(backupDir), "reports"), nil
+}
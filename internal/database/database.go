@@ -11,12 +11,13 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/vtuos/vtuos/internal/config"
 
-	_ "modernc.org/sqlite"
+	"modernc.org/sqlite"
 )
 
 // DB wraps a sql.DB with additional functionality for mission-critical operations.
@@ -34,32 +35,51 @@ type DB struct {
 	// Backup scheduling
 	backupTicker *time.Ticker
 	backupDone   chan struct{}
+
+	// Connections, the queue their writes wait on and the timings of
+	// their statements
+	connector *connector
+	queue     *writeQueue
+	queries   *queryLog
 }
 
 // Open creates a new database connection with WAL mode enabled for power-loss resilience.
 // It performs integrity checks and enables all safety pragmas.
+//
+// With cfg.ReadOnly the database must already exist; it is opened with
+// mode=ro, so SQLite rejects every write, and no backups are scheduled.
 func Open(dbPath string, cfg *config.DatabaseConfig, backupDir string) (*DB, error) {
-	// Ensure directory exists
-	dir := filepath.Dir(dbPath)
-	if dir != "." && dir != "" {
-		if err := os.MkdirAll(dir, 0750); err != nil {
-			return nil, fmt.Errorf("creating database directory: %w", err)
-		}
-	}
-
 	// Build connection string with parameters
 	connStr := fmt.Sprintf("file:%s?_txlock=immediate&_timeout=5000&_fk=true", dbPath)
-
-	// Open database connection
-	sqlDB, err := sql.Open("sqlite", connStr)
-	if err != nil {
-		return nil, fmt.Errorf("opening database: %w", err)
+	if cfg.ReadOnly {
+		if _, err := os.Stat(dbPath); err != nil {
+			return nil, fmt.Errorf("opening read-only database: %w", err)
+		}
+		connStr = fmt.Sprintf("file:%s?mode=ro&_fk=true", dbPath)
+	} else {
+		// Ensure directory exists
+		dir := filepath.Dir(dbPath)
+		if dir != "." && dir != "" {
+			if err := os.MkdirAll(dir, 0750); err != nil {
+				return nil, fmt.Errorf("creating database directory: %w", err)
+			}
+		}
 	}
 
-	// Configure connection pool
-	sqlDB.SetMaxOpenConns(1) // SQLite only supports one writer
-	sqlDB.SetMaxIdleConns(1)
-	sqlDB.SetConnMaxLifetime(0) // Keep connection open
+	// Open a pool of connections that reads share and writes take turns on,
+	// SQLite allowing one writer at a time. Each connection to an in-memory
+	// database would open a database of its own, so it gets one.
+	queue := newWriteQueue()
+	queries := newQueryLog(time.Duration(cfg.SlowQueryMS) * time.Millisecond)
+	conn := &connector{dsn: connStr, driver: &sqlite.Driver{}, queue: queue, log: queries}
+	sqlDB := sql.OpenDB(conn)
+	conns := max(cfg.Connections, 1)
+	if isMemory(dbPath) {
+		conns = 1
+	}
+	sqlDB.SetMaxOpenConns(conns)
+	sqlDB.SetMaxIdleConns(conns)
+	sqlDB.SetConnMaxLifetime(0) // Keep connections open
 
 	db := &DB{
 		DB:        sqlDB,
@@ -67,6 +87,9 @@ func Open(dbPath string, cfg *config.DatabaseConfig, backupDir string) (*DB, err
 		config:    cfg,
 		backupDir: backupDir,
 		closeChan: make(chan struct{}),
+		connector: conn,
+		queue:     queue,
+		queries:   queries,
 	}
 
 	// Initialize with safety pragmas
@@ -81,8 +104,21 @@ func Open(dbPath string, cfg *config.DatabaseConfig, backupDir string) (*DB, err
 		// Don't fail here - recovery will be attempted by caller
 	}
 
+	// Sensitive columns are encrypted with the database's data keys
+	if err := db.LoadEncryption(context.Background()); err != nil {
+		sqlDB.Close()
+		return nil, fmt.Errorf("loading encryption keys: %w", err)
+	}
+
+	// New keys sort after the stored ones whatever the system clock says
+	if !cfg.ReadOnly {
+		if err := db.ResumeIDs(context.Background()); err != nil {
+			slog.Warn("resuming id generation failed", "error", err)
+		}
+	}
+
 	// Start backup scheduler if configured
-	if cfg.BackupIntervalHours > 0 && backupDir != "" {
+	if cfg.BackupIntervalHours > 0 && backupDir != "" && !cfg.ReadOnly {
 		db.startBackupScheduler()
 	}
 
@@ -90,38 +126,140 @@ func Open(dbPath string, cfg *config.DatabaseConfig, backupDir string) (*DB, err
 }
 
 // initPragmas sets all critical SQLite pragmas for mission-critical operation.
+// Journal mode, synchronous level, busy timeout, cache and mmap sizes come
+// from the database configuration; the effective values are logged.
 func (db *DB) initPragmas() error {
-	pragmas := []struct {
+	if err := db.config.Validate(); err != nil {
+		return fmt.Errorf("database config: %w", err)
+	}
+
+	// WAL mode for power-loss resilience, with NORMAL synchronous balancing
+	// safety and performance, unless configured otherwise
+	journalMode := "WAL"
+	if db.config.JournalMode != "" {
+		journalMode = strings.ToUpper(db.config.JournalMode)
+	}
+	synchronous := "NORMAL"
+	if db.config.Synchronous != "" {
+		synchronous = strings.ToUpper(db.config.Synchronous)
+	}
+
+	type setting struct {
 		name   string
 		pragma string
-	}{
-		// WAL mode for power-loss resilience
-		{"journal_mode", "PRAGMA journal_mode=WAL"},
-		// Synchronous NORMAL balances safety and performance
-		{"synchronous", "PRAGMA synchronous=NORMAL"},
-		// 5 second busy timeout for concurrent access
-		{"busy_timeout", "PRAGMA busy_timeout=5000"},
+	}
+	pragmas := []setting{
+		{"journal_mode", "PRAGMA journal_mode=" + journalMode},
+		{"synchronous", "PRAGMA synchronous=" + synchronous},
+		// Wait for locks held by other connections before SQLITE_BUSY
+		{"busy_timeout", fmt.Sprintf("PRAGMA busy_timeout=%d", db.config.BusyTimeoutMS)},
 		// Enable foreign key constraints
 		{"foreign_keys", "PRAGMA foreign_keys=ON"},
 		// Use 4KB page size (matches typical filesystem block size)
 		{"page_size", "PRAGMA page_size=4096"},
-		// 16MB cache for good performance
-		{"cache_size", "PRAGMA cache_size=-16000"},
-		// Enable memory-mapped I/O for reads (256MB)
-		{"mmap_size", "PRAGMA mmap_size=268435456"},
+		// Memory-mapped I/O for reads
+		{"mmap_size", fmt.Sprintf("PRAGMA mmap_size=%d", int64(db.config.MmapSizeMB)<<20)},
 		// Secure delete for sensitive data
 		{"secure_delete", "PRAGMA secure_delete=ON"},
+		// Let Optimize return free pages to the filesystem; takes effect on
+		// databases created from now on
+		{"auto_vacuum", "PRAGMA auto_vacuum=INCREMENTAL"},
+	}
+	if db.config.CacheSizeMB > 0 {
+		// A negative cache size is in KiB rather than pages
+		pragmas = append(pragmas, setting{"cache_size", fmt.Sprintf("PRAGMA cache_size=-%d", db.config.CacheSizeMB*1024)})
 	}
 
+	// Connection pragmas run as each connection of the pool opens; the
+	// first opens here, and database pragmas then run once on it
+	var database []setting
 	for _, p := range pragmas {
+		switch {
+		case !writesDatabase[p.name]:
+			db.connector.pragmas = append(db.connector.pragmas, p.pragma)
+		case !db.config.ReadOnly:
+			database = append(database, p)
+		}
+	}
+	if err := db.Ping(); err != nil {
+		return fmt.Errorf("connecting: %w", err)
+	}
+	for _, p := range database {
 		if _, err := db.Exec(p.pragma); err != nil {
 			return fmt.Errorf("setting %s: %w", p.name, err)
 		}
 	}
 
+	db.logPragmas()
 	return nil
 }
 
+// writesDatabase lists the pragmas that change the database file rather than
+// the connection; they are skipped when the database is read-only.
+var writesDatabase = map[string]bool{
+	"journal_mode": true,
+	"page_size":    true,
+	"auto_vacuum":  true,
+}
+
+// WriteQueueStats returns the metrics of the queue writes wait their turn
+// on.
+func (db *DB) WriteQueueStats() WriteQueueStats {
+	return db.queue.stats()
+}
+
+// QueryStats returns the counters of every statement run on the database,
+// most total time first.
+func (db *DB) QueryStats() []QueryStats {
+	return db.queries.stats()
+}
+
+// ResetQueryStats clears the statement counters.
+func (db *DB) ResetQueryStats() {
+	db.queries.reset()
+}
+
+// ReadOnly reports whether the database was opened read-only.
+func (db *DB) ReadOnly() bool {
+	return db.config.ReadOnly
+}
+
+// logPragmas logs the tuning pragmas SQLite actually applied, which can
+// differ from the configuration, e.g. WAL is unavailable for in-memory
+// databases.
+func (db *DB) logPragmas() {
+	var journalMode string
+	var synchronous, busyTimeout, cacheSize int
+	var mmapSize int64
+	for _, p := range []struct {
+		name string
+		dest any
+	}{
+		{"journal_mode", &journalMode},
+		{"synchronous", &synchronous},
+		{"busy_timeout", &busyTimeout},
+		{"cache_size", &cacheSize},
+		{"mmap_size", &mmapSize},
+	} {
+		if err := db.QueryRow("PRAGMA " + p.name).Scan(p.dest); err != nil {
+			slog.Warn("reading database pragma", "pragma", p.name, "error", err)
+			return
+		}
+	}
+
+	levels := []string{"OFF", "NORMAL", "FULL", "EXTRA"}
+	syncName := fmt.Sprint(synchronous)
+	if synchronous >= 0 && synchronous < len(levels) {
+		syncName = levels[synchronous]
+	}
+	slog.Info("database pragmas",
+		"journal_mode", journalMode,
+		"synchronous", syncName,
+		"busy_timeout_ms", busyTimeout,
+		"cache_size", cacheSize,
+		"mmap_size", mmapSize)
+}
+
 // CheckIntegrity performs a database integrity check.
 func (db *DB) CheckIntegrity(ctx context.Context) error {
 	rows, err := db.QueryContext(ctx, "PRAGMA integrity_check")
@@ -162,23 +300,57 @@ func (db *DB) Checkpoint(ctx context.Context) error {
 
 // Backup creates a backup of the database to the backup directory.
 func (db *DB) Backup(ctx context.Context) (string, error) {
+	// Generate backup filename with timestamp
+	timestamp := time.Now().Format("20060102-150405")
+	return db.backupAs(ctx, fmt.Sprintf("vault-%s.db", timestamp))
+}
+
+// PreMigrationBackup creates a backup tagged with the schema version it was
+// taken at, so that a bad upgrade can be reverted by restoring it.
+func (db *DB) PreMigrationBackup(ctx context.Context, version int) (string, error) {
+	timestamp := time.Now().Format("20060102-150405")
+	return db.backupAs(ctx, fmt.Sprintf("vault-%s-premigrate-v%03d.db", timestamp, version))
+}
+
+// LatestBackup returns the path and modification time of the newest backup
+// in the backup directory, or an empty path if there is none.
+func (db *DB) LatestBackup() (string, time.Time, error) {
 	if db.backupDir == "" {
-		return "", errors.New("backup directory not configured")
+		return "", time.Time{}, nil
+	}
+	entries, err := os.ReadDir(db.backupDir)
+	if os.IsNotExist(err) {
+		return "", time.Time{}, nil
+	}
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("reading backup directory: %w", err)
 	}
 
-	// Generate backup filename with timestamp
-	timestamp := time.Now().Format("20060102-150405")
-	backupName := fmt.Sprintf("vault-%s.db", timestamp)
-	backupPath := filepath.Join(db.backupDir, backupName)
+	var latest string
+	var latestAt time.Time
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".db") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if info.ModTime().After(latestAt) {
+			latest, latestAt = filepath.Join(db.backupDir, entry.Name()), info.ModTime()
+		}
+	}
+	return latest, latestAt, nil
+}
 
-	// Checkpoint first to ensure WAL is flushed
-	if err := db.Checkpoint(ctx); err != nil {
-		slog.Warn("checkpoint before backup failed", "error", err)
+// backupAs writes a consistent copy of the database into the backup directory.
+func (db *DB) backupAs(ctx context.Context, backupName string) (string, error) {
+	if db.backupDir == "" {
+		return "", errors.New("backup directory not configured")
 	}
 
-	// Use SQLite backup API via VACUUM INTO
-	_, err := db.ExecContext(ctx, fmt.Sprintf("VACUUM INTO '%s'", backupPath))
-	if err != nil {
+	backupPath := filepath.Join(db.backupDir, backupName)
+	if err := db.vacuumInto(ctx, backupPath); err != nil {
 		return "", fmt.Errorf("creating backup: %w", err)
 	}
 
@@ -192,6 +364,18 @@ func (db *DB) Backup(ctx context.Context) (string, error) {
 	return backupPath, nil
 }
 
+// vacuumInto writes a consistent, compacted copy of the database to path.
+func (db *DB) vacuumInto(ctx context.Context, path string) error {
+	// Checkpoint first to ensure WAL is flushed
+	if err := db.Checkpoint(ctx); err != nil {
+		slog.Warn("checkpoint before backup failed", "error", err)
+	}
+
+	// Use SQLite backup API via VACUUM INTO
+	_, err := db.ExecContext(ctx, fmt.Sprintf("VACUUM INTO '%s'", strings.ReplaceAll(path, "'", "''")))
+	return err
+}
+
 // cleanOldBackups removes backups older than the retention period.
 func (db *DB) cleanOldBackups() {
 	if db.backupDir == "" {
@@ -230,25 +414,46 @@ func (db *DB) cleanOldBackups() {
 // startBackupScheduler starts the background backup scheduler.
 func (db *DB) startBackupScheduler() {
 	interval := time.Duration(db.config.BackupIntervalHours) * time.Hour
-	db.backupTicker = time.NewTicker(interval)
-	db.backupDone = make(chan struct{})
+	ticker, done := time.NewTicker(interval), make(chan struct{})
+	db.backupTicker, db.backupDone = ticker, done
 
 	go func() {
 		for {
 			select {
-			case <-db.backupTicker.C:
+			case <-ticker.C:
 				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
 				if _, err := db.Backup(ctx); err != nil {
 					slog.Error("scheduled backup failed", "error", err)
 				}
 				cancel()
-			case <-db.backupDone:
+			case <-done:
 				return
 			}
 		}
 	}()
 }
 
+// SetBackupInterval reschedules automatic backups to run every hours hours
+// from now, or stops them for 0. A read-only database, or one without a
+// backup directory, is never backed up automatically.
+func (db *DB) SetBackupInterval(hours int) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if db.closed || db.config.ReadOnly || db.backupDir == "" {
+		return
+	}
+
+	db.config.BackupIntervalHours = hours
+	if db.backupTicker != nil {
+		db.backupTicker.Stop()
+		close(db.backupDone)
+		db.backupTicker = nil
+	}
+	if hours > 0 {
+		db.startBackupScheduler()
+	}
+}
+
 // Close gracefully closes the database connection.
 // It ensures all pending transactions are complete and performs a final WAL checkpoint.
 func (db *DB) Close() error {
@@ -368,11 +573,12 @@ type Stats struct {
 	PageSize      int64
 	SchemaVersion int64
 	JournalMode   string
+	WriteQueue    WriteQueueStats
 }
 
 // GetStats retrieves current database statistics.
 func (db *DB) GetStats(ctx context.Context) (*Stats, error) {
-	stats := &Stats{Path: db.path}
+	stats := &Stats{Path: db.path, WriteQueue: db.WriteQueueStats()}
 
 	// Get file sizes
 	if info, err := os.Stat(db.path); err == nil {
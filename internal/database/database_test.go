@@ -0,0 +1,71 @@
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vtuos/vtuos/internal/config"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+func TestLatestBackup(t *testing.T) {
+	cfg := config.Default().Database
+	cfg.BackupIntervalHours = 0
+	db, err := Open(filepath.Join(t.TempDir(), "vault.db"), &cfg, t.TempDir())
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer db.Close()
+
+	if path, _, err := db.LatestBackup(); err != nil || path != "" {
+		t.Fatalf("LatestBackup before any backup = %q, %v; want none", path, err)
+	}
+
+	ctx := context.Background()
+	if _, err := db.PreMigrationBackup(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	backup, err := db.Backup(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path, at, err := db.LatestBackup()
+	if err != nil || path != backup || at.IsZero() {
+		t.Errorf("LatestBackup = %q, %v, %v; want %q", path, at, err, backup)
+	}
+}
+
+func TestResumeIDs(t *testing.T) {
+	cfg := config.Default().Database
+	cfg.BackupIntervalHours = 0
+	path := filepath.Join(t.TempDir(), "vault.db")
+	db, err := Open(path, &cfg, "")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	// A key written under a clock far ahead, and a random key of an older
+	// release, which sorts after it but carries no time
+	ahead := "0" + strings.Repeat("f", 7) + "-ffff-7000-8000-000000000000"
+	ctx := context.Background()
+	for _, stmt := range []string{
+		`CREATE TABLE records (id TEXT PRIMARY KEY)`,
+		`INSERT INTO records (id) VALUES ('` + ahead + `'), ('f47ac10b-58cc-4372-a567-0e02b2c3d479')`,
+	} {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	db.Close()
+
+	db, err = Open(path, &cfg, "")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer db.Close()
+	if id := util.NewID(); id <= ahead {
+		t.Errorf("NewID() after reopening = %s, want after %s", id, ahead)
+	}
+}
@@ -0,0 +1,441 @@
+package database
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/vtuos/vtuos/internal/vaultcrypt"
+)
+
+// EncryptedColumns are the columns, as "table.column", whose values are
+// encrypted when encryption at rest is enabled: free-text notes about
+// residents and their medical findings. Repositories encrypt and decrypt
+// them with vaultcrypt as they write and read them.
+var EncryptedColumns = []string{
+	"residents.notes",
+	"medical_conditions.treatment_plan",
+	"medical_conditions.notes",
+	"medical_records.chief_complaint",
+	"medical_records.diagnosis_text",
+	"medical_records.treatment_provided",
+	"medical_records.medications_prescribed",
+	"medical_records.notes",
+	"radiation_exposures.notes",
+	"decontamination_treatments.notes",
+}
+
+// ErrEncrypted is returned when opening an encrypted database with
+// encryption disabled.
+var ErrEncrypted = errors.New("database is encrypted: enable database.encryption with its master key")
+
+// EncryptionKey describes a data key of the database.
+type EncryptionKey struct {
+	ID        string
+	Primary   bool
+	CreatedAt string
+	Values    int // Values the key encrypted
+}
+
+// EncryptionStatus describes the encryption of the database.
+type EncryptionStatus struct {
+	Keys      []EncryptionKey
+	Encrypted map[string]int // Encrypted values by column
+	Plaintext map[string]int // Values written before encryption was enabled, by column
+}
+
+// LoadEncryption installs the keyring of the database's data keys, opened
+// with the configured master key, creating the first data key of a writable
+// database that has none. With encryption disabled it removes any keyring
+// installed, failing with ErrEncrypted if the database holds data keys.
+// Before the migration adding data keys it does nothing.
+func (db *DB) LoadEncryption(ctx context.Context) error {
+	if !db.hasEncryptionKeys(ctx) {
+		vaultcrypt.Install(nil)
+		return nil
+	}
+	stored, err := db.readEncryptionKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	enc := db.config.Encryption
+	if !enc.Enabled {
+		vaultcrypt.Install(nil)
+		if len(stored) > 0 {
+			return ErrEncrypted
+		}
+		return nil
+	}
+
+	master, err := enc.MasterKey()
+	if err != nil {
+		return err
+	}
+	keyring, err := openKeyring(master, stored)
+	if err != nil {
+		return err
+	}
+	if keyring.Primary() == "" && !db.config.ReadOnly {
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("beginning transaction: %w", err)
+		}
+		defer tx.Rollback()
+		if _, err := addDataKey(ctx, tx, master, keyring); err != nil {
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("committing data key: %w", err)
+		}
+	}
+	vaultcrypt.Install(keyring)
+	return nil
+}
+
+// EncryptColumns encrypts the values of the encrypted columns still in
+// plaintext, such as those written before encryption was enabled or by
+// seeding, returning how many it encrypted.
+func (db *DB) EncryptColumns(ctx context.Context) (int, error) {
+	keyring := vaultcrypt.Installed()
+	if keyring == nil {
+		return 0, errors.New("encryption is not enabled")
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	n, err := rewriteColumns(ctx, tx, func(column, value string) (string, bool, error) {
+		if vaultcrypt.IsSealed(value) {
+			return "", false, nil
+		}
+		sealed, err := keyring.Seal(column, value)
+		return sealed, true, err
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("committing encryption: %w", err)
+	}
+	return n, nil
+}
+
+// RotateEncryptionKey creates a new primary data key, encrypts every value
+// of the encrypted columns with it and deletes the data keys it replaced,
+// in one transaction. It returns the new key's ID and the number of values
+// encrypted.
+func (db *DB) RotateEncryptionKey(ctx context.Context) (string, int, error) {
+	if vaultcrypt.Installed() == nil {
+		return "", 0, errors.New("encryption is not enabled")
+	}
+	master, err := db.config.Encryption.MasterKey()
+	if err != nil {
+		return "", 0, err
+	}
+	stored, err := db.readEncryptionKeys(ctx)
+	if err != nil {
+		return "", 0, err
+	}
+	keyring, err := openKeyring(master, stored)
+	if err != nil {
+		return "", 0, err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", 0, fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	id, err := addDataKey(ctx, tx, master, keyring)
+	if err != nil {
+		return "", 0, err
+	}
+	n, err := rewriteColumns(ctx, tx, func(column, value string) (string, bool, error) {
+		plaintext, err := keyring.Open(column, value)
+		if err != nil {
+			return "", false, err
+		}
+		sealed, err := keyring.Seal(column, plaintext)
+		return sealed, true, err
+	})
+	if err != nil {
+		return "", 0, err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM encryption_keys WHERE id != ?`, id); err != nil {
+		return "", 0, fmt.Errorf("deleting replaced data keys: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return "", 0, fmt.Errorf("committing key rotation: %w", err)
+	}
+
+	vaultcrypt.Install(keyring)
+	return id, n, nil
+}
+
+// RewrapEncryptionKeys wraps the data keys, wrapped by the master key the
+// database was opened with, with newMaster, so that the master key can be
+// replaced without encrypting every value again. It returns the number of
+// keys rewrapped.
+func (db *DB) RewrapEncryptionKeys(ctx context.Context, newMaster []byte) (int, error) {
+	oldMaster, err := db.config.Encryption.MasterKey()
+	if err != nil {
+		return 0, err
+	}
+	stored, err := db.readEncryptionKeys(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, k := range stored {
+		dataKey, err := vaultcrypt.UnwrapKey(oldMaster, k.wrapped)
+		if err != nil {
+			return 0, fmt.Errorf("data key %s: master key: %w", k.id, err)
+		}
+		wrapped, err := vaultcrypt.WrapKey(newMaster, dataKey)
+		if err != nil {
+			return 0, err
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE encryption_keys SET wrapped_key = ? WHERE id = ?`, wrapped, k.id); err != nil {
+			return 0, fmt.Errorf("rewrapping data key %s: %w", k.id, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("committing rewrapped keys: %w", err)
+	}
+	return len(stored), nil
+}
+
+// DecryptColumns writes every encrypted value back in plaintext and
+// deletes the data keys, so that encryption can be disabled. It returns the
+// number of values decrypted.
+func (db *DB) DecryptColumns(ctx context.Context) (int, error) {
+	keyring := vaultcrypt.Installed()
+	if keyring == nil {
+		return 0, errors.New("encryption is not enabled")
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	n, err := rewriteColumns(ctx, tx, func(column, value string) (string, bool, error) {
+		if !vaultcrypt.IsSealed(value) {
+			return "", false, nil
+		}
+		plaintext, err := keyring.Open(column, value)
+		return plaintext, true, err
+	})
+	if err != nil {
+		return 0, err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM encryption_keys`); err != nil {
+		return 0, fmt.Errorf("deleting data keys: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("committing decryption: %w", err)
+	}
+	vaultcrypt.Install(nil)
+	return n, nil
+}
+
+// EncryptionStatus counts the encrypted and plaintext values of each
+// encrypted column and the values of each data key.
+func (db *DB) EncryptionStatus(ctx context.Context) (*EncryptionStatus, error) {
+	status := &EncryptionStatus{Encrypted: make(map[string]int), Plaintext: make(map[string]int)}
+	if !db.hasEncryptionKeys(ctx) {
+		return status, nil
+	}
+
+	rows, err := db.QueryContext(ctx, `SELECT id, is_primary, created_at FROM encryption_keys ORDER BY created_at, id`)
+	if err != nil {
+		return nil, fmt.Errorf("reading data keys: %w", err)
+	}
+	defer rows.Close()
+	index := make(map[string]int)
+	for rows.Next() {
+		var k EncryptionKey
+		if err := rows.Scan(&k.ID, &k.Primary, &k.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scanning data key: %w", err)
+		}
+		index[k.ID] = len(status.Keys)
+		status.Keys = append(status.Keys, k)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading data keys: %w", err)
+	}
+
+	for _, column := range EncryptedColumns {
+		values, err := columnValues(ctx, db.DB, column)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range values {
+			id, sealed := vaultcrypt.KeyID(v.value)
+			if !sealed {
+				status.Plaintext[column]++
+				continue
+			}
+			status.Encrypted[column]++
+			if i, ok := index[id]; ok {
+				status.Keys[i].Values++
+			}
+		}
+	}
+	return status, nil
+}
+
+// storedKey is a data key as stored, wrapped by the master key.
+type storedKey struct {
+	id      string
+	wrapped string
+	primary bool
+}
+
+// hasEncryptionKeys reports whether the database has the data key table,
+// added by a migration.
+func (db *DB) hasEncryptionKeys(ctx context.Context) bool {
+	var n int
+	err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'encryption_keys'`).Scan(&n)
+	return err == nil && n > 0
+}
+
+func (db *DB) readEncryptionKeys(ctx context.Context) ([]storedKey, error) {
+	rows, err := db.QueryContext(ctx, `SELECT id, wrapped_key, is_primary FROM encryption_keys ORDER BY created_at, id`)
+	if err != nil {
+		return nil, fmt.Errorf("reading data keys: %w", err)
+	}
+	defer rows.Close()
+	var keys []storedKey
+	for rows.Next() {
+		var k storedKey
+		if err := rows.Scan(&k.id, &k.wrapped, &k.primary); err != nil {
+			return nil, fmt.Errorf("scanning data key: %w", err)
+		}
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
+}
+
+// openKeyring unwraps stored data keys with the master key.
+func openKeyring(master []byte, stored []storedKey) (*vaultcrypt.Keyring, error) {
+	keyring := vaultcrypt.NewKeyring()
+	for _, k := range stored {
+		dataKey, err := vaultcrypt.UnwrapKey(master, k.wrapped)
+		if err != nil {
+			return nil, fmt.Errorf("data key %s: master key: %w", k.id, err)
+		}
+		if err := keyring.Add(k.id, dataKey); err != nil {
+			return nil, err
+		}
+	}
+	for _, k := range stored {
+		if k.primary {
+			if err := keyring.SetPrimary(k.id); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return keyring, nil
+}
+
+// addDataKey stores a new random data key, wrapped by the master key, as
+// the primary key and adds it to the keyring as its primary key.
+func addDataKey(ctx context.Context, tx *sql.Tx, master []byte, keyring *vaultcrypt.Keyring) (string, error) {
+	dataKey, err := vaultcrypt.NewKey()
+	if err != nil {
+		return "", err
+	}
+	wrapped, err := vaultcrypt.WrapKey(master, dataKey)
+	if err != nil {
+		return "", err
+	}
+	var raw [6]byte
+	if _, err := rand.Read(raw[:]); err != nil {
+		return "", fmt.Errorf("generating data key ID: %w", err)
+	}
+	id := hex.EncodeToString(raw[:])
+
+	if _, err := tx.ExecContext(ctx, `UPDATE encryption_keys SET is_primary = 0 WHERE is_primary = 1`); err != nil {
+		return "", fmt.Errorf("retiring primary data key: %w", err)
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO encryption_keys (id, wrapped_key, is_primary, created_at)
+		VALUES (?, ?, 1, strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))`, id, wrapped)
+	if err != nil {
+		return "", fmt.Errorf("storing data key: %w", err)
+	}
+	if err := keyring.Add(id, dataKey); err != nil {
+		return "", err
+	}
+	return id, keyring.SetPrimary(id)
+}
+
+// columnValue is a non-empty value of an encrypted column.
+type columnValue struct {
+	rowid int64
+	value string
+}
+
+// columnValues reads the non-empty values of an encrypted column.
+func columnValues(ctx context.Context, q interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}, column string) ([]columnValue, error) {
+	table, col, _ := strings.Cut(column, ".")
+	rows, err := q.QueryContext(ctx, `SELECT rowid, "`+col+`" FROM "`+table+`" WHERE "`+col+`" != ''`)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", column, err)
+	}
+	defer rows.Close()
+	var values []columnValue
+	for rows.Next() {
+		var v columnValue
+		if err := rows.Scan(&v.rowid, &v.value); err != nil {
+			return nil, fmt.Errorf("scanning %s: %w", column, err)
+		}
+		values = append(values, v)
+	}
+	return values, rows.Err()
+}
+
+// rewriteColumns passes every value of the encrypted columns through
+// rewrite, storing those it changes, and returns how many it stored.
+func rewriteColumns(ctx context.Context, tx *sql.Tx, rewrite func(column, value string) (string, bool, error)) (int, error) {
+	n := 0
+	for _, column := range EncryptedColumns {
+		values, err := columnValues(ctx, tx, column)
+		if err != nil {
+			return 0, err
+		}
+		table, col, _ := strings.Cut(column, ".")
+		update := `UPDATE "` + table + `" SET "` + col + `" = ? WHERE rowid = ?`
+		for _, v := range values {
+			value, changed, err := rewrite(column, v.value)
+			if err != nil {
+				return 0, fmt.Errorf("%s row %d: %w", column, v.rowid, err)
+			}
+			if !changed {
+				continue
+			}
+			if _, err := tx.ExecContext(ctx, update, value, v.rowid); err != nil {
+				return 0, fmt.Errorf("writing %s: %w", column, err)
+			}
+			n++
+		}
+	}
+	return n, nil
+}
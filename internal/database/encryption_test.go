@@ -0,0 +1,157 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vtuos/vtuos/internal/config"
+	"github.com/vtuos/vtuos/internal/vaultcrypt"
+)
+
+func TestEncryptionLifecycle(t *testing.T) {
+	defer vaultcrypt.Install(nil)
+
+	master, _ := vaultcrypt.NewKeyString()
+	t.Setenv("VTUOS_TEST_KEY", master)
+	cfg := config.Default().Database
+	cfg.BackupIntervalHours = 0
+	cfg.Encryption = config.EncryptionConfig{Enabled: true, KeyEnv: "VTUOS_TEST_KEY"}
+	path := filepath.Join(t.TempDir(), "vault.db")
+	ctx := context.Background()
+
+	db, err := Open(path, &cfg, "")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	m, err := NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.SetBackupBeforeMigrate(false)
+	if _, err := m.MigrateUp(ctx); err != nil {
+		t.Fatalf("MigrateUp: %v", err)
+	}
+
+	// A resident written before encryption was enabled
+	if _, err := db.ExecContext(ctx, `
+		INSERT INTO residents (id, registry_number, surname, given_names, date_of_birth, sex,
+			entry_type, entry_date, status, notes)
+		VALUES ('r', 'V076-00001', 'Smith', 'Ann', '2050-01-01', 'F', 'ORIGINAL', '2077-10-23', 'ACTIVE',
+			'Claustrophobic')`); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.LoadEncryption(ctx); err != nil {
+		t.Fatalf("LoadEncryption: %v", err)
+	}
+	if n, err := db.EncryptColumns(ctx); err != nil || n != 1 {
+		t.Fatalf("EncryptColumns = %d, %v, want 1", n, err)
+	}
+	firstKey := storedNotesKey(t, db)
+
+	id, n, err := db.RotateEncryptionKey(ctx)
+	if err != nil || n != 1 || id == firstKey {
+		t.Fatalf("RotateEncryptionKey = %s, %d, %v, want a new key and 1 value", id, n, err)
+	}
+	if got := storedNotesKey(t, db); got != id {
+		t.Errorf("notes encrypted with key %s after rotation, want %s", got, id)
+	}
+	db.Close()
+
+	// The master key is replaced: data keys are rewrapped under the new one
+	newMaster, _ := vaultcrypt.NewKeyString()
+	t.Setenv("VTUOS_TEST_OLD_KEY", master)
+	t.Setenv("VTUOS_TEST_KEY", newMaster)
+	if _, err := Open(path, &cfg, ""); err == nil {
+		t.Fatal("Open with another master key succeeded")
+	}
+	oldCfg := cfg
+	oldCfg.Encryption.KeyEnv = "VTUOS_TEST_OLD_KEY"
+	db, err = Open(path, &oldCfg, "")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	newKey, _ := vaultcrypt.ParseMasterKey(newMaster)
+	if n, err := db.RewrapEncryptionKeys(ctx, newKey); err != nil || n != 1 {
+		t.Fatalf("RewrapEncryptionKeys = %d, %v, want 1", n, err)
+	}
+	db.Close()
+
+	db, err = Open(path, &cfg, "")
+	if err != nil {
+		t.Fatalf("Open with the new master key: %v", err)
+	}
+	status, err := db.EncryptionStatus(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(status.Keys) != 1 || status.Keys[0].Values != 1 || status.Encrypted["residents.notes"] != 1 {
+		t.Errorf("EncryptionStatus = %+v, want one key of one value", status)
+	}
+	if n, err := db.DecryptColumns(ctx); err != nil || n != 1 {
+		t.Fatalf("DecryptColumns = %d, %v, want 1", n, err)
+	}
+	var notes string
+	if err := db.QueryRowContext(ctx, `SELECT notes FROM residents`).Scan(&notes); err != nil || notes != "Claustrophobic" {
+		t.Errorf("notes after decrypting = %q, %v", notes, err)
+	}
+	db.Close()
+
+	cfg.Encryption.Enabled = false
+	db, err = Open(path, &cfg, "")
+	if err != nil {
+		t.Fatalf("Open decrypted with encryption disabled: %v", err)
+	}
+	db.Close()
+}
+
+func TestOpenEncryptedWithEncryptionDisabled(t *testing.T) {
+	defer vaultcrypt.Install(nil)
+
+	master, _ := vaultcrypt.NewKeyString()
+	t.Setenv("VTUOS_TEST_KEY", master)
+	cfg := config.Default().Database
+	cfg.BackupIntervalHours = 0
+	cfg.Encryption = config.EncryptionConfig{Enabled: true, KeyEnv: "VTUOS_TEST_KEY"}
+	path := filepath.Join(t.TempDir(), "vault.db")
+	ctx := context.Background()
+
+	db, err := Open(path, &cfg, "")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	m, _ := NewMigrator(db)
+	m.SetBackupBeforeMigrate(false)
+	if _, err := m.MigrateUp(ctx); err != nil {
+		t.Fatalf("MigrateUp: %v", err)
+	}
+	if err := db.LoadEncryption(ctx); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	cfg.Encryption.Enabled = false
+	if _, err := Open(path, &cfg, ""); !errors.Is(err, ErrEncrypted) {
+		t.Errorf("Open error = %v, want ErrEncrypted", err)
+	}
+}
+
+// storedNotesKey returns the data key the resident's notes are stored
+// encrypted with.
+func storedNotesKey(t *testing.T, db *DB) string {
+	t.Helper()
+	var notes string
+	if err := db.QueryRow(`SELECT notes FROM residents`).Scan(&notes); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(notes, "Claustrophobic") {
+		t.Fatalf("notes stored in plaintext: %q", notes)
+	}
+	id, ok := vaultcrypt.KeyID(notes)
+	if !ok {
+		t.Fatalf("notes not encrypted: %q", notes)
+	}
+	return id
+}
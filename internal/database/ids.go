@@ -0,0 +1,59 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// ResumeIDs moves ID generation past the newest UUIDv7 key of every table
+// keyed by a TEXT id, so that rows created from now on sort after those
+// already stored even if the system clock has stepped back since. Keys of
+// other kinds, such as the random UUIDs of older databases, stay as they
+// are and are passed over: they remain valid keys, they only do not sort
+// by time.
+func (db *DB) ResumeIDs(ctx context.Context) error {
+	rows, err := db.QueryContext(ctx, `
+		SELECT m.name FROM sqlite_master m, pragma_table_info(m.name) c
+		WHERE m.type = 'table' AND c.name = 'id' AND c.pk = 1 AND upper(c.type) = 'TEXT'
+		ORDER BY m.name`)
+	if err != nil {
+		return fmt.Errorf("listing tables: %w", err)
+	}
+	tables, err := scanNames(rows)
+	if err != nil {
+		return fmt.Errorf("listing tables: %w", err)
+	}
+
+	for _, table := range tables {
+		// The version digit of a UUIDv7; the primary key index is read
+		// from the top down, so the scan stops at the newest one
+		var id string
+		err := db.QueryRowContext(ctx, `SELECT id FROM "`+table+`"
+			WHERE substr(id, 15, 1) = '7' ORDER BY id DESC LIMIT 1`).Scan(&id)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("reading newest id of %s: %w", table, err)
+		}
+		util.ObserveID(id)
+	}
+	return nil
+}
+
+// scanNames reads and closes rows of one text column.
+func scanNames(rows *sql.Rows) ([]string, error) {
+	defer rows.Close()
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
@@ -0,0 +1,173 @@
+package database
+
+import (
+	"cmp"
+	"database/sql/driver"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// QueryStats describes the runs of one SQL statement since the database
+// opened or its counters were last reset.
+type QueryStats struct {
+	Query string        // Statement with its whitespace collapsed
+	Calls int64         // Times it ran
+	Slow  int64         // Runs at or over the slow query threshold
+	Total time.Duration // Time spent running it
+	Max   time.Duration // Longest run
+}
+
+// Mean returns the mean time of a run.
+func (s QueryStats) Mean() time.Duration {
+	if s.Calls == 0 {
+		return 0
+	}
+	return s.Total / time.Duration(s.Calls)
+}
+
+// queryLog times every statement run on the database, counting the runs of
+// each and logging those slower than a threshold. A query is timed until its
+// rows are closed, since SQLite does most of its work as they are read.
+type queryLog struct {
+	slow time.Duration // Zero logs no slow queries
+
+	mu      sync.Mutex
+	queries map[string]*QueryStats
+}
+
+func newQueryLog(slow time.Duration) *queryLog {
+	return &queryLog{slow: slow, queries: make(map[string]*QueryStats)}
+}
+
+// record counts a run of query that took elapsed, logging it with its
+// parameters redacted if it was slow.
+func (l *queryLog) record(query string, args []driver.NamedValue, elapsed time.Duration) {
+	query = normalizeQuery(query)
+	slow := l.slow > 0 && elapsed >= l.slow
+
+	l.mu.Lock()
+	stats, ok := l.queries[query]
+	if !ok {
+		stats = &QueryStats{Query: query}
+		l.queries[query] = stats
+	}
+	stats.Calls++
+	stats.Total += elapsed
+	stats.Max = max(stats.Max, elapsed)
+	if slow {
+		stats.Slow++
+	}
+	l.mu.Unlock()
+
+	if slow {
+		slog.Warn("slow database query", "query", query, "args", redactArgs(args),
+			"elapsed", elapsed.Round(time.Microsecond))
+	}
+}
+
+// stats returns the counters of every statement run, most total time first.
+func (l *queryLog) stats() []QueryStats {
+	l.mu.Lock()
+	stats := make([]QueryStats, 0, len(l.queries))
+	for _, s := range l.queries {
+		stats = append(stats, *s)
+	}
+	l.mu.Unlock()
+
+	slices.SortFunc(stats, func(a, b QueryStats) int {
+		if c := cmp.Compare(b.Total, a.Total); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Query, b.Query)
+	})
+	return stats
+}
+
+// reset clears the counters.
+func (l *queryLog) reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	clear(l.queries)
+}
+
+// normalizeQuery collapses the whitespace of a statement, so that the same
+// statement written across several lines is counted as one.
+func normalizeQuery(query string) string {
+	return strings.Join(strings.Fields(query), " ")
+}
+
+// redactArgs describes the parameters of a statement by type and size only,
+// e.g. "text(36)", since they may hold residents' personal records.
+func redactArgs(args []driver.NamedValue) string {
+	parts := make([]string, len(args))
+	for i, arg := range args {
+		switch v := arg.Value.(type) {
+		case nil:
+			parts[i] = "null"
+		case string:
+			parts[i] = fmt.Sprintf("text(%d)", len(v))
+		case []byte:
+			parts[i] = fmt.Sprintf("blob(%d)", len(v))
+		case int64:
+			parts[i] = "int"
+		case float64:
+			parts[i] = "real"
+		case bool:
+			parts[i] = "bool"
+		case time.Time:
+			parts[i] = "time"
+		default:
+			parts[i] = fmt.Sprintf("%T", v)
+		}
+	}
+	return "[" + strings.Join(parts, " ") + "]"
+}
+
+// timedRows records the run of its query when the rows are closed.
+type timedRows struct {
+	driver.Rows
+	done func()
+}
+
+func (r *timedRows) Close() error {
+	err := r.Rows.Close()
+	if r.done != nil {
+		r.done()
+		r.done = nil
+	}
+	return err
+}
+
+// columnTyper is the column type information SQLite's rows report.
+type columnTyper interface {
+	driver.RowsColumnTypeDatabaseTypeName
+	driver.RowsColumnTypeLength
+	driver.RowsColumnTypeNullable
+	driver.RowsColumnTypePrecisionScale
+	driver.RowsColumnTypeScanType
+}
+
+func (r *timedRows) ColumnTypeDatabaseTypeName(index int) string {
+	return r.Rows.(columnTyper).ColumnTypeDatabaseTypeName(index)
+}
+
+func (r *timedRows) ColumnTypeLength(index int) (int64, bool) {
+	return r.Rows.(columnTyper).ColumnTypeLength(index)
+}
+
+func (r *timedRows) ColumnTypeNullable(index int) (bool, bool) {
+	return r.Rows.(columnTyper).ColumnTypeNullable(index)
+}
+
+func (r *timedRows) ColumnTypePrecisionScale(index int) (int64, int64, bool) {
+	return r.Rows.(columnTyper).ColumnTypePrecisionScale(index)
+}
+
+func (r *timedRows) ColumnTypeScanType(index int) reflect.Type {
+	return r.Rows.(columnTyper).ColumnTypeScanType(index)
+}
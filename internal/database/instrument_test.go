@@ -0,0 +1,105 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/config"
+)
+
+func TestQueryStatsCountsStatements(t *testing.T) {
+	cfg := config.Default().Database
+	cfg.BackupIntervalHours = 0
+	db, err := Open(filepath.Join(t.TempDir(), "vault.db"), &cfg, "")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	if _, err := db.ExecContext(ctx, `CREATE TABLE log (name TEXT)`); err != nil {
+		t.Fatal(err)
+	}
+	db.ResetQueryStats()
+
+	for _, name := range []string{"Amata", "Butch", "Jonas"} {
+		if _, err := db.ExecContext(ctx, `INSERT INTO log
+			(name) VALUES (?)`, name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rows, err := db.QueryContext(ctx, `SELECT name FROM log`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	types, err := rows.ColumnTypes()
+	if err != nil || types[0].DatabaseTypeName() != "TEXT" {
+		t.Errorf("ColumnTypes = %v, %v; want the TEXT column", types, err)
+	}
+	for rows.Next() {
+	}
+	rows.Close()
+
+	calls := map[string]int64{}
+	for _, s := range db.QueryStats() {
+		calls[s.Query] = s.Calls
+		if s.Max > s.Total || s.Mean() > s.Max {
+			t.Errorf("%q: max %v, mean %v, total %v", s.Query, s.Max, s.Mean(), s.Total)
+		}
+	}
+	if calls["INSERT INTO log (name) VALUES (?)"] != 3 {
+		t.Errorf("insert calls = %d, want 3 (stats %v)", calls["INSERT INTO log (name) VALUES (?)"], calls)
+	}
+	if calls["SELECT name FROM log"] != 1 {
+		t.Errorf("select calls = %d, want 1 (stats %v)", calls["SELECT name FROM log"], calls)
+	}
+
+	db.ResetQueryStats()
+	if stats := db.QueryStats(); len(stats) != 0 {
+		t.Errorf("after reset, stats = %v", stats)
+	}
+}
+
+func TestQueryLogRedactsSlowQueries(t *testing.T) {
+	var buf bytes.Buffer
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})))
+
+	log := newQueryLog(100 * time.Millisecond)
+	args := []driver.NamedValue{
+		{Ordinal: 1, Value: "V076-00412"},
+		{Ordinal: 2, Value: int64(42)},
+		{Ordinal: 3, Value: nil},
+	}
+	log.record("SELECT * FROM residents WHERE registry_number = ?", args, 10*time.Millisecond)
+	if buf.Len() != 0 {
+		t.Fatalf("fast query logged: %s", buf.String())
+	}
+	log.record("SELECT * FROM residents WHERE registry_number = ?", args, 150*time.Millisecond)
+
+	out := buf.String()
+	if !strings.Contains(out, "slow database query") || !strings.Contains(out, "[text(10) int null]") {
+		t.Errorf("slow query log = %q, want the query with redacted args", out)
+	}
+	if strings.Contains(out, "V076-00412") || strings.Contains(out, "42") {
+		t.Errorf("slow query log %q shows a parameter", out)
+	}
+
+	stats := log.stats()
+	if len(stats) != 1 || stats[0].Calls != 2 || stats[0].Slow != 1 || stats[0].Max != 150*time.Millisecond {
+		t.Errorf("stats = %+v, want 2 calls, 1 slow, max 150ms", stats)
+	}
+}
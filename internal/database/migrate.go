@@ -2,8 +2,10 @@ package database
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
 	"embed"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io/fs"
@@ -19,14 +21,32 @@ import (
 //go:embed migrations/*.sql
 var migrationsFS embed.FS
 
+// ErrMigrationDrift indicates an applied migration whose embedded file has
+// changed since it was applied.
+var ErrMigrationDrift = errors.New("applied migrations differ from their files")
+
 // Migration represents a database migration.
 type Migration struct {
-	Version     int
-	Description string
-	UpSQL       string
-	DownSQL     string
-	Applied     bool
-	AppliedAt   time.Time
+	Version         int
+	Description     string
+	UpSQL           string
+	DownSQL         string
+	Checksum        string // SHA-256 of the migration file, hex encoded
+	Applied         bool
+	AppliedAt       time.Time
+	AppliedChecksum string // Checksum recorded when applied, empty if never recorded
+}
+
+// Drifted returns true if the migration was applied from a file that differs
+// from the embedded one.
+func (m Migration) Drifted() bool {
+	return m.Applied && m.AppliedChecksum != "" && m.AppliedChecksum != m.Checksum
+}
+
+// migrationChecksum returns the checksum of a migration file's content.
+func migrationChecksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
 }
 
 // MigrationResult contains the result of running migrations.
@@ -34,6 +54,7 @@ type MigrationResult struct {
 	Applied        []Migration
 	CurrentVersion int
 	TargetVersion  int
+	BackupPath     string // Pre-migration backup, empty if none was taken
 	Error          error
 }
 
@@ -41,18 +62,26 @@ type MigrationResult struct {
 type Migrator struct {
 	db         *DB
 	migrations []Migration
+
+	// backupBeforeMigrate takes a backup before applying pending migrations
+	backupBeforeMigrate bool
 }
 
 // NewMigrator creates a new Migrator for the given database.
+// Pre-migration backups are enabled by default.
 func NewMigrator(db *DB) (*Migrator, error) {
-	m := &Migrator{db: db}
+	m := &Migrator{db: db, backupBeforeMigrate: true}
 
 	// Load migrations from embedded filesystem
 	if err := m.loadMigrations(); err != nil {
 		return nil, fmt.Errorf("loading migrations: %w", err)
 	}
 
-	// Ensure migrations table exists
+	// Ensure migrations table exists; a read-only database can only be
+	// inspected, so it must already have one
+	if db.ReadOnly() {
+		return m, nil
+	}
 	if err := m.ensureMigrationsTable(); err != nil {
 		return nil, fmt.Errorf("creating migrations table: %w", err)
 	}
@@ -60,6 +89,12 @@ func NewMigrator(db *DB) (*Migrator, error) {
 	return m, nil
 }
 
+// SetBackupBeforeMigrate enables or disables the automatic backup taken
+// before MigrateUp applies pending migrations.
+func (m *Migrator) SetBackupBeforeMigrate(enabled bool) {
+	m.backupBeforeMigrate = enabled
+}
+
 // loadMigrations reads all migration files from the embedded filesystem.
 func (m *Migrator) loadMigrations() error {
 	entries, err := fs.ReadDir(migrationsFS, "migrations")
@@ -96,6 +131,7 @@ func (m *Migrator) loadMigrations() error {
 			Description: description,
 			UpSQL:       upSQL,
 			DownSQL:     downSQL,
+			Checksum:    migrationChecksum(content),
 		})
 	}
 
@@ -150,9 +186,25 @@ func (m *Migrator) ensureMigrationsTable() error {
 			version INTEGER PRIMARY KEY,
 			description TEXT NOT NULL,
 			applied_at TEXT NOT NULL DEFAULT (datetime('now')),
-			checksum TEXT
+			checksum TEXT,
+			backup_path TEXT
 		)
 	`)
+	if err != nil {
+		return err
+	}
+
+	// Databases created before pre-migration backups lack the backup_path column
+	var hasBackupPath int
+	err = m.db.QueryRow(
+		"SELECT COUNT(*) FROM pragma_table_info('schema_migrations') WHERE name = 'backup_path'",
+	).Scan(&hasBackupPath)
+	if err != nil {
+		return err
+	}
+	if hasBackupPath == 0 {
+		_, err = m.db.Exec("ALTER TABLE schema_migrations ADD COLUMN backup_path TEXT")
+	}
 	return err
 }
 
@@ -209,13 +261,24 @@ func (m *Migrator) MigrateUp(ctx context.Context) (*MigrationResult, error) {
 
 	result.TargetVersion = pending[len(pending)-1].Version
 
+	// Back up existing data before touching the schema. A fresh database has
+	// nothing worth preserving.
+	if m.backupBeforeMigrate && current > 0 {
+		backupPath, err := m.db.PreMigrationBackup(ctx, current)
+		if err != nil {
+			result.Error = fmt.Errorf("pre-migration backup failed: %w", err)
+			return result, result.Error
+		}
+		result.BackupPath = backupPath
+	}
+
 	for _, mig := range pending {
 		slog.Info("applying migration",
 			"version", mig.Version,
 			"description", mig.Description,
 		)
 
-		if err := m.applyMigration(ctx, mig); err != nil {
+		if err := m.applyMigration(ctx, mig, result.BackupPath); err != nil {
 			result.Error = fmt.Errorf("migration %d failed: %w", mig.Version, err)
 			return result, result.Error
 		}
@@ -235,7 +298,8 @@ func (m *Migrator) MigrateUp(ctx context.Context) (*MigrationResult, error) {
 }
 
 // applyMigration applies a single migration within a transaction.
-func (m *Migrator) applyMigration(ctx context.Context, mig Migration) error {
+// backupPath records the pre-migration backup that precedes it, if any.
+func (m *Migrator) applyMigration(ctx context.Context, mig Migration, backupPath string) error {
 	return m.db.WithTransaction(ctx, func(tx *sql.Tx) error {
 		// Execute the migration SQL
 		// Split by semicolon to handle multiple statements
@@ -252,8 +316,8 @@ func (m *Migrator) applyMigration(ctx context.Context, mig Migration) error {
 
 		// Record the migration
 		_, err := tx.ExecContext(ctx,
-			"INSERT INTO schema_migrations (version, description) VALUES (?, ?)",
-			mig.Version, mig.Description,
+			"INSERT INTO schema_migrations (version, description, checksum, backup_path) VALUES (?, ?, ?, ?)",
+			mig.Version, mig.Description, mig.Checksum, sql.NullString{String: backupPath, Valid: backupPath != ""},
 		)
 		if err != nil {
 			return fmt.Errorf("recording migration: %w", err)
@@ -358,7 +422,7 @@ func (m *Migrator) MigrateTo(ctx context.Context, targetVersion int) (*Migration
 		// Migrate up
 		for _, mig := range m.migrations {
 			if mig.Version > current && mig.Version <= targetVersion {
-				if err := m.applyMigration(ctx, mig); err != nil {
+				if err := m.applyMigration(ctx, mig, ""); err != nil {
 					result.Error = err
 					return result, err
 				}
@@ -388,6 +452,96 @@ func (m *Migrator) MigrateTo(ctx context.Context, targetVersion int) (*Migration
 	return result, nil
 }
 
+// LastBatch returns the versions applied by the most recent MigrateUp run,
+// newest first, together with the backup taken before that run. Migrations
+// applied without a backup are treated as a batch of one.
+func (m *Migrator) LastBatch(ctx context.Context) ([]int, string, error) {
+	var latest int
+	var backupPath sql.NullString
+	err := m.db.QueryRowContext(ctx,
+		"SELECT version, backup_path FROM schema_migrations ORDER BY version DESC LIMIT 1",
+	).Scan(&latest, &backupPath)
+	if err == sql.ErrNoRows {
+		return nil, "", errors.New("no migrations have been applied")
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("querying last migration: %w", err)
+	}
+
+	if !backupPath.Valid {
+		return []int{latest}, "", nil
+	}
+
+	rows, err := m.db.QueryContext(ctx,
+		"SELECT version FROM schema_migrations WHERE backup_path = ? ORDER BY version DESC",
+		backupPath.String,
+	)
+	if err != nil {
+		return nil, "", fmt.Errorf("querying migration batch: %w", err)
+	}
+	defer rows.Close()
+
+	var versions []int
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return nil, "", fmt.Errorf("scanning row: %w", err)
+		}
+		versions = append(versions, version)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", fmt.Errorf("iterating rows: %w", err)
+	}
+
+	return versions, backupPath.String, nil
+}
+
+// UndoLast rolls back every migration applied by the most recent MigrateUp
+// run using their down SQL.
+func (m *Migrator) UndoLast(ctx context.Context) (*MigrationResult, error) {
+	versions, backupPath, err := m.LastBatch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &MigrationResult{
+		CurrentVersion: versions[0],
+		TargetVersion:  versions[len(versions)-1] - 1,
+		BackupPath:     backupPath,
+	}
+
+	for _, version := range versions {
+		var mig *Migration
+		for i := range m.migrations {
+			if m.migrations[i].Version == version {
+				mig = &m.migrations[i]
+				break
+			}
+		}
+		if mig == nil {
+			result.Error = fmt.Errorf("migration %d not found", version)
+			return result, result.Error
+		}
+		if mig.DownSQL == "" {
+			result.Error = fmt.Errorf("migration %d has no rollback SQL", version)
+			return result, result.Error
+		}
+
+		slog.Info("rolling back migration",
+			"version", mig.Version,
+			"description", mig.Description,
+		)
+
+		if err := m.rollbackMigration(ctx, *mig); err != nil {
+			result.Error = fmt.Errorf("rollback %d failed: %w", mig.Version, err)
+			return result, result.Error
+		}
+		result.Applied = append(result.Applied, *mig)
+	}
+
+	return result, nil
+}
+
 // DryRun shows what migrations would be applied without applying them.
 func (m *Migrator) DryRun(ctx context.Context) ([]Migration, error) {
 	return m.PendingMigrations(ctx)
@@ -400,66 +554,134 @@ func (m *Migrator) Status(ctx context.Context) ([]Migration, error) {
 		return nil, err
 	}
 
-	// Get applied migrations from database
+	applied, err := m.appliedMigrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build status for all migrations
+	result := make([]Migration, len(m.migrations))
+	for i, mig := range m.migrations {
+		result[i] = mig
+		if a, ok := applied[mig.Version]; ok {
+			result[i].Applied = true
+			result[i].AppliedAt = a.AppliedAt
+			result[i].AppliedChecksum = a.AppliedChecksum
+		}
+	}
+
+	_ = current // Used for logging if needed
+
+	return result, nil
+}
+
+// appliedMigrations returns the applied migrations recorded in
+// schema_migrations by version, with when they were applied and the
+// checksum recorded then.
+func (m *Migrator) appliedMigrations(ctx context.Context) (map[int]Migration, error) {
 	rows, err := m.db.QueryContext(ctx,
-		"SELECT version, applied_at FROM schema_migrations ORDER BY version",
+		"SELECT version, applied_at, checksum FROM schema_migrations ORDER BY version",
 	)
 	if err != nil {
 		return nil, fmt.Errorf("querying applied migrations: %w", err)
 	}
 	defer rows.Close()
 
-	applied := make(map[int]time.Time)
+	applied := make(map[int]Migration)
 	for rows.Next() {
-		var version int
+		var mig Migration
 		var appliedAt string
-		if err := rows.Scan(&version, &appliedAt); err != nil {
+		var checksum sql.NullString
+		if err := rows.Scan(&mig.Version, &appliedAt, &checksum); err != nil {
 			return nil, fmt.Errorf("scanning row: %w", err)
 		}
-		t, _ := time.Parse("2006-01-02 15:04:05", appliedAt)
-		applied[version] = t
+		mig.Applied = true
+		mig.AppliedAt, _ = time.Parse("2006-01-02 15:04:05", appliedAt)
+		mig.AppliedChecksum = checksum.String
+		applied[mig.Version] = mig
 	}
-
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("iterating rows: %w", err)
 	}
 
-	// Build status for all migrations
-	result := make([]Migration, len(m.migrations))
-	for i, mig := range m.migrations {
-		result[i] = mig
-		if t, ok := applied[mig.Version]; ok {
-			result[i].Applied = true
-			result[i].AppliedAt = t
+	return applied, nil
+}
+
+// VerifyChecksums compares each applied migration with its embedded file
+// and returns those that drifted: edited after they were applied. Migrations
+// applied before checksums were recorded are trusted and given the checksum
+// of their file, unless the database is read-only.
+func (m *Migrator) VerifyChecksums(ctx context.Context) ([]Migration, error) {
+	migrations, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var drifted, unrecorded []Migration
+	for _, mig := range migrations {
+		switch {
+		case mig.Drifted():
+			drifted = append(drifted, mig)
+		case mig.Applied && mig.AppliedChecksum == "":
+			unrecorded = append(unrecorded, mig)
 		}
 	}
 
-	_ = current // Used for logging if needed
+	if len(unrecorded) > 0 && !m.db.ReadOnly() {
+		err := m.db.WithTransaction(ctx, func(tx *sql.Tx) error {
+			for _, mig := range unrecorded {
+				if _, err := tx.ExecContext(ctx,
+					"UPDATE schema_migrations SET checksum = ? WHERE version = ?",
+					mig.Checksum, mig.Version,
+				); err != nil {
+					return fmt.Errorf("recording checksum of migration %d: %w", mig.Version, err)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		slog.Info("recorded migration checksums", "count", len(unrecorded))
+	}
 
-	return result, nil
+	return drifted, nil
 }
 
 // splitStatements splits SQL content into individual statements.
-// Handles semicolons properly (not those inside strings).
+// Handles semicolons properly (not those inside strings, comments or trigger
+// bodies).
 func splitStatements(sql string) []string {
 	var statements []string
 	var current strings.Builder
 	inString := false
+	inComment := false
 	stringChar := rune(0)
 
 	for i, ch := range sql {
-		if inString {
+		if inComment {
+			// A quote or semicolon in a comment is prose
+			current.WriteRune(ch)
+			inComment = ch != '\n'
+		} else if inString {
 			current.WriteRune(ch)
 			if ch == stringChar && (i == 0 || sql[i-1] != '\\') {
 				inString = false
 			}
 		} else {
-			if ch == '\'' || ch == '"' {
+			if ch == '-' && strings.HasPrefix(sql[i:], "--") {
+				inComment = true
+				current.WriteRune(ch)
+			} else if ch == '\'' || ch == '"' {
 				inString = true
 				stringChar = ch
 				current.WriteRune(ch)
 			} else if ch == ';' {
 				stmt := strings.TrimSpace(current.String())
+				if isOpenTrigger(stmt) {
+					current.WriteRune(ch)
+					continue
+				}
 				if stmt != "" {
 					statements = append(statements, stmt)
 				}
@@ -478,3 +700,79 @@ func splitStatements(sql string) []string {
 
 	return statements
 }
+
+// isOpenTrigger reports whether stmt is a CREATE TRIGGER whose BEGIN...END
+// body has not been closed yet, meaning a semicolon belongs to the body.
+// A CASE expression in the body ends with END too, so the body is closed
+// only once every BEGIN and CASE has met its END.
+func isOpenTrigger(stmt string) bool {
+	words := sqlWords(stmt)
+	if !isCreateTrigger(words) {
+		return false
+	}
+	depth, begun := 0, false
+	for _, w := range words {
+		switch w {
+		case "BEGIN":
+			depth++
+			begun = true
+		case "CASE":
+			depth++
+		case "END":
+			depth--
+		}
+	}
+	return !begun || depth > 0
+}
+
+// isCreateTrigger reports whether the words of a statement, as returned by
+// sqlWords, create a trigger.
+func isCreateTrigger(words []string) bool {
+	if len(words) < 2 || words[0] != "CREATE" {
+		return false
+	}
+	if words[1] == "TEMP" || words[1] == "TEMPORARY" {
+		words = words[1:]
+	}
+	return len(words) > 1 && words[1] == "TRIGGER"
+}
+
+// sqlWords returns the keywords and identifiers of stmt, upper-cased, in
+// order. Quoted strings and identifiers and -- comments are skipped, so an
+// 'END' in a message or a comment is not taken for a keyword.
+func sqlWords(stmt string) []string {
+	var words []string
+	for i := 0; i < len(stmt); {
+		ch := stmt[i]
+		switch {
+		case ch == '\'' || ch == '"' || ch == '`':
+			end := strings.IndexByte(stmt[i+1:], ch)
+			if end < 0 {
+				return words
+			}
+			i += end + 2
+		case strings.HasPrefix(stmt[i:], "--"):
+			end := strings.IndexByte(stmt[i:], '\n')
+			if end < 0 {
+				return words
+			}
+			i += end + 1
+		case isWordByte(ch):
+			start := i
+			for i < len(stmt) && isWordByte(stmt[i]) {
+				i++
+			}
+			if ch < '0' || ch > '9' { // Not a number
+				words = append(words, strings.ToUpper(stmt[start:i]))
+			}
+		default:
+			i++
+		}
+	}
+	return words
+}
+
+// isWordByte reports whether ch can be part of an SQL keyword or identifier.
+func isWordByte(ch byte) bool {
+	return ch == '_' || ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9'
+}
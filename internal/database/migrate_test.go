@@ -0,0 +1,168 @@
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vtuos/vtuos/internal/config"
+)
+
+func TestSplitStatements(t *testing.T) {
+	sql := `-- A resident's rows; gone with them
+CREATE TABLE a (id TEXT);
+INSERT INTO a VALUES ('x;y'); -- don't split here
+CREATE TRIGGER t AFTER INSERT ON a
+BEGIN
+    DELETE FROM a WHERE id = 'z';
+END;`
+	got := splitStatements(sql)
+	if len(got) != 3 {
+		t.Fatalf("got %d statements, want 3: %q", len(got), got)
+	}
+	if got[1] != `INSERT INTO a VALUES ('x;y')` {
+		t.Errorf("statement 2 = %q", got[1])
+	}
+}
+
+func TestSplitStatements_TriggerWithCase(t *testing.T) {
+	sql := `CREATE TRIGGER t AFTER UPDATE ON a
+BEGIN
+    UPDATE a SET status = CASE WHEN NEW.n > 0 THEN 'OPEN' ELSE 'END' END;
+    -- Closed when the CASE ends; END
+    INSERT INTO log VALUES (CASE NEW.n WHEN 0 THEN 'x' END);
+END;
+CREATE TEMP TRIGGER u BEFORE DELETE ON a BEGIN SELECT 1; END;
+DELETE FROM a WHERE status = 'END'`
+	got := splitStatements(sql)
+	if len(got) != 3 {
+		t.Fatalf("got %d statements, want 3: %q", len(got), got)
+	}
+	if !strings.HasSuffix(got[0], "END") || !strings.Contains(got[0], "INSERT INTO log") {
+		t.Errorf("statement 1 = %q, want the whole trigger", got[0])
+	}
+	if got[1] != "CREATE TEMP TRIGGER u BEFORE DELETE ON a BEGIN SELECT 1; END" {
+		t.Errorf("statement 2 = %q", got[1])
+	}
+}
+
+func TestMigrateRebuildsStocksKeepingReferences(t *testing.T) {
+	cfg := config.Default().Database
+	cfg.BackupIntervalHours = 0
+	db, err := Open(filepath.Join(t.TempDir(), "vault.db"), &cfg, "")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer db.Close()
+	m, err := NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.SetBackupBeforeMigrate(false)
+
+	ctx := context.Background()
+	if _, err := m.MigrateTo(ctx, 26); err != nil {
+		t.Fatalf("MigrateTo(26): %v", err)
+	}
+	for _, stmt := range []string{
+		`INSERT INTO resource_categories (id, code, name, unit_of_measure) VALUES ('c', 'FOOD', 'Food', 'kg')`,
+		`INSERT INTO resource_items (id, category_id, item_code, name, unit_of_measure) VALUES ('i', 'c', 'RICE', 'Rice', 'kg')`,
+		`INSERT INTO resource_stocks (id, item_id, quantity, storage_location, received_date, vault_id)
+			VALUES ('s', 'i', 10, 'A-1', '2077-10-23', 76)`,
+		`INSERT INTO resource_transactions (id, stock_id, item_id, transaction_type, quantity, balance_after)
+			VALUES ('t', 's', 'i', 'PRODUCTION', 10, 10)`,
+	} {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := m.MigrateUp(ctx); err != nil {
+		t.Fatalf("MigrateUp: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, `UPDATE resource_stocks SET status = 'DAMAGED' WHERE id = 's'`); err != nil {
+		t.Fatalf("setting DAMAGED: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, `INSERT INTO resource_transactions (id, stock_id, item_id, transaction_type, quantity, balance_after)
+		VALUES ('u', 'missing', 'i', 'ADJUSTMENT', 0, 0)`); err == nil {
+		t.Error("a transaction of a missing lot was accepted after the rebuild")
+	}
+
+	for current, _ := m.CurrentVersion(ctx); current > 26; current, _ = m.CurrentVersion(ctx) {
+		if _, err := m.MigrateDown(ctx); err != nil {
+			t.Fatalf("MigrateDown from %d: %v", current, err)
+		}
+	}
+	var status string
+	var vault int
+	if err := db.QueryRowContext(ctx, `SELECT status, vault_id FROM resource_stocks WHERE id = 's'`).Scan(&status, &vault); err != nil {
+		t.Fatal(err)
+	}
+	if status != "QUARANTINE" || vault != 76 {
+		t.Errorf("after rollback the lot is %s in vault %d, want QUARANTINE in 76", status, vault)
+	}
+}
+
+func TestMigrateRebuildsFacilitySystemsKeepingReferences(t *testing.T) {
+	cfg := config.Default().Database
+	cfg.BackupIntervalHours = 0
+	db, err := Open(filepath.Join(t.TempDir(), "vault.db"), &cfg, "")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer db.Close()
+	m, err := NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.SetBackupBeforeMigrate(false)
+
+	ctx := context.Background()
+	if _, err := m.MigrateTo(ctx, 44); err != nil {
+		t.Fatalf("MigrateTo(44): %v", err)
+	}
+	for _, stmt := range []string{
+		`INSERT INTO facility_systems (id, system_code, name, category, location_sector, location_level, install_date, vault_id)
+			VALUES ('f', 'WTR-PURIFIER-02', 'Water Purifier', 'WATER', 'B', 2, '2077-10-23', 76)`,
+		`INSERT INTO maintenance_records (id, system_id, maintenance_type, description)
+			VALUES ('r', 'f', 'INSPECTION', 'Inspect purifier')`,
+	} {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := m.MigrateUp(ctx); err != nil {
+		t.Fatalf("MigrateUp: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, `UPDATE facility_systems
+		SET status = 'RETIRED', decommissioned_at = '2078-01-05T08:00:00Z', decommission_reason = 'Replaced' WHERE id = 'f'`); err != nil {
+		t.Fatalf("retiring: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, `INSERT INTO maintenance_records (id, system_id, maintenance_type, description)
+		VALUES ('s', 'missing', 'INSPECTION', 'Inspect nothing')`); err == nil {
+		t.Error("a work order of a missing system was accepted after the rebuild")
+	}
+
+	for current, _ := m.CurrentVersion(ctx); current > 44; current, _ = m.CurrentVersion(ctx) {
+		if _, err := m.MigrateDown(ctx); err != nil {
+			t.Fatalf("MigrateDown from %d: %v", current, err)
+		}
+	}
+	var status string
+	var vault int
+	if err := db.QueryRowContext(ctx, `SELECT status, vault_id FROM facility_systems WHERE id = 'f'`).Scan(&status, &vault); err != nil {
+		t.Fatal(err)
+	}
+	if status != "OFFLINE" || vault != 76 {
+		t.Errorf("after rollback the system is %s in vault %d, want OFFLINE in 76", status, vault)
+	}
+	var records int
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM maintenance_records WHERE system_id = 'f'`).Scan(&records); err != nil {
+		t.Fatal(err)
+	}
+	if records != 1 {
+		t.Errorf("after rollback the system has %d work order(s), want 1", records)
+	}
+}
@@ -0,0 +1,28 @@
+-- +migrate Up
+-- Ration Class Reviews
+-- Suggested household ration class changes raised when births, deaths, or
+-- member transfers alter a household's composition. Reviews stay PENDING
+-- until the Overseer approves or rejects them.
+
+CREATE TABLE ration_class_reviews (
+    id TEXT PRIMARY KEY,
+    household_id TEXT NOT NULL REFERENCES households(id),
+    current_class TEXT NOT NULL CHECK (current_class IN ('MINIMAL', 'STANDARD', 'ENHANCED', 'MEDICAL', 'LABOR_INTENSIVE')),
+    recommended_class TEXT NOT NULL CHECK (recommended_class IN ('MINIMAL', 'STANDARD', 'ENHANCED', 'MEDICAL', 'LABOR_INTENSIVE')),
+    reason TEXT NOT NULL,
+    trigger_event TEXT NOT NULL CHECK (trigger_event IN ('BIRTH', 'DEATH', 'TRANSFER', 'MANUAL')),
+    status TEXT NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'APPROVED', 'REJECTED', 'SUPERSEDED')),
+    decided_by TEXT REFERENCES residents(id),
+    decided_at TEXT,
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    updated_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX idx_ration_reviews_household ON ration_class_reviews(household_id, status);
+CREATE INDEX idx_ration_reviews_pending ON ration_class_reviews(created_at)
+    WHERE status = 'PENDING';
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_ration_reviews_pending;
+DROP INDEX IF EXISTS idx_ration_reviews_household;
+DROP TABLE IF EXISTS ration_class_reviews;
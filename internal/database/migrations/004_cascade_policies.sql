@@ -0,0 +1,105 @@
+-- +migrate Up
+-- Referential Cascade Policies
+-- SQLite cannot alter existing foreign key actions without rebuilding tables,
+-- so the delete/deactivate policy for each relationship is enforced with
+-- triggers. RESTRICT triggers raise a stable message that the repository
+-- layer maps to a typed error. CASCADE and SET NULL triggers run before the
+-- row is removed so the plain foreign keys never fire.
+--
+-- Policy summary:
+--   households  <- residents.household_id               RESTRICT
+--   households  <- ration_class_reviews.household_id    CASCADE
+--   households  <- quarters.assigned_household_id       SET NULL
+--   residents   <- residents.biological_parent_*_id     RESTRICT
+--   residents   <- households.head_of_household_id      SET NULL
+--   residents   <- ration_class_reviews.decided_by      SET NULL
+--   vocations   <- residents.primary_vocation_id        SET NULL (on delete and on deactivation)
+--   vocations   <- work_assignments.vocation_id         RESTRICT
+--   quarters    <- households.quarters_id               RESTRICT
+--   quarters    <- residents.quarters_id                RESTRICT
+
+-- ============================================================================
+-- HOUSEHOLDS
+-- ============================================================================
+
+CREATE TRIGGER trg_households_restrict_members
+BEFORE DELETE ON households
+WHEN EXISTS (SELECT 1 FROM residents WHERE household_id = OLD.id)
+BEGIN
+    SELECT RAISE(ABORT, 'restrict: household has members');
+END;
+
+CREATE TRIGGER trg_households_cascade
+BEFORE DELETE ON households
+BEGIN
+    DELETE FROM ration_class_reviews WHERE household_id = OLD.id;
+    UPDATE quarters SET assigned_household_id = NULL WHERE assigned_household_id = OLD.id;
+END;
+
+-- ============================================================================
+-- RESIDENTS
+-- ============================================================================
+
+CREATE TRIGGER trg_residents_restrict_parent
+BEFORE DELETE ON residents
+WHEN EXISTS (
+    SELECT 1 FROM residents
+    WHERE biological_parent_1_id = OLD.id OR biological_parent_2_id = OLD.id
+)
+BEGIN
+    SELECT RAISE(ABORT, 'restrict: resident is a recorded parent');
+END;
+
+CREATE TRIGGER trg_residents_set_null
+BEFORE DELETE ON residents
+BEGIN
+    UPDATE households SET head_of_household_id = NULL WHERE head_of_household_id = OLD.id;
+    UPDATE ration_class_reviews SET decided_by = NULL WHERE decided_by = OLD.id;
+END;
+
+-- ============================================================================
+-- VOCATIONS
+-- ============================================================================
+
+CREATE TRIGGER trg_vocations_restrict_assignments
+BEFORE DELETE ON vocations
+WHEN EXISTS (SELECT 1 FROM work_assignments WHERE vocation_id = OLD.id)
+BEGIN
+    SELECT RAISE(ABORT, 'restrict: vocation has work assignments');
+END;
+
+CREATE TRIGGER trg_vocations_set_null
+BEFORE DELETE ON vocations
+BEGIN
+    UPDATE residents SET primary_vocation_id = NULL WHERE primary_vocation_id = OLD.id;
+END;
+
+CREATE TRIGGER trg_vocations_deactivate
+AFTER UPDATE OF is_active ON vocations
+WHEN OLD.is_active = 1 AND NEW.is_active = 0
+BEGIN
+    UPDATE residents SET primary_vocation_id = NULL, updated_at = datetime('now')
+    WHERE primary_vocation_id = NEW.id;
+END;
+
+-- ============================================================================
+-- QUARTERS
+-- ============================================================================
+
+CREATE TRIGGER trg_quarters_restrict_occupants
+BEFORE DELETE ON quarters
+WHEN EXISTS (SELECT 1 FROM households WHERE quarters_id = OLD.id)
+    OR EXISTS (SELECT 1 FROM residents WHERE quarters_id = OLD.id)
+BEGIN
+    SELECT RAISE(ABORT, 'restrict: quarters are occupied');
+END;
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS trg_quarters_restrict_occupants;
+DROP TRIGGER IF EXISTS trg_vocations_deactivate;
+DROP TRIGGER IF EXISTS trg_vocations_set_null;
+DROP TRIGGER IF EXISTS trg_vocations_restrict_assignments;
+DROP TRIGGER IF EXISTS trg_residents_set_null;
+DROP TRIGGER IF EXISTS trg_residents_restrict_parent;
+DROP TRIGGER IF EXISTS trg_households_cascade;
+DROP TRIGGER IF EXISTS trg_households_restrict_members;
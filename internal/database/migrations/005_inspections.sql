@@ -0,0 +1,69 @@
+-- +migrate Up
+-- Inspections
+-- Checklist templates (safety walks, food storage audits, quarters
+-- inspections) are scheduled on the vault calendar. Completing an inspection
+-- records its findings, and a finding may raise a work order in
+-- maintenance_records or an incident in security_incidents.
+
+CREATE TABLE inspection_templates (
+    id TEXT PRIMARY KEY,
+    code TEXT UNIQUE NOT NULL,
+    name TEXT NOT NULL,
+    category TEXT NOT NULL CHECK (category IN ('SAFETY_WALK', 'FOOD_STORAGE', 'QUARTERS')),
+    description TEXT,
+    interval_days INTEGER NOT NULL CHECK (interval_days >= 1),
+    is_active INTEGER NOT NULL DEFAULT 1,
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    updated_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE TABLE inspection_checklist_items (
+    id TEXT PRIMARY KEY,
+    template_id TEXT NOT NULL REFERENCES inspection_templates(id),
+    sequence INTEGER NOT NULL,
+    description TEXT NOT NULL,
+    is_critical INTEGER NOT NULL DEFAULT 0,
+    UNIQUE (template_id, sequence)
+);
+
+CREATE TABLE inspections (
+    id TEXT PRIMARY KEY,
+    template_id TEXT NOT NULL REFERENCES inspection_templates(id),
+    location TEXT,
+    scheduled_date TEXT NOT NULL,
+    assigned_to TEXT REFERENCES residents(id),
+    status TEXT NOT NULL DEFAULT 'SCHEDULED' CHECK (status IN ('SCHEDULED', 'COMPLETED', 'CANCELLED')),
+    completed_at TEXT,
+    completed_by TEXT REFERENCES residents(id),
+    passed INTEGER,
+    notes TEXT,
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    updated_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX idx_inspections_template ON inspections(template_id);
+CREATE INDEX idx_inspections_scheduled ON inspections(scheduled_date)
+    WHERE status = 'SCHEDULED';
+
+CREATE TABLE inspection_findings (
+    id TEXT PRIMARY KEY,
+    inspection_id TEXT NOT NULL REFERENCES inspections(id),
+    checklist_item_id TEXT REFERENCES inspection_checklist_items(id),
+    severity TEXT NOT NULL CHECK (severity IN ('MINOR', 'MODERATE', 'MAJOR', 'CRITICAL')),
+    description TEXT NOT NULL,
+    action TEXT NOT NULL DEFAULT 'NONE' CHECK (action IN ('NONE', 'WORK_ORDER', 'INCIDENT')),
+    maintenance_record_id TEXT REFERENCES maintenance_records(id),
+    incident_id TEXT REFERENCES security_incidents(id),
+    created_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX idx_inspection_findings_inspection ON inspection_findings(inspection_id);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_inspection_findings_inspection;
+DROP TABLE IF EXISTS inspection_findings;
+DROP INDEX IF EXISTS idx_inspections_scheduled;
+DROP INDEX IF EXISTS idx_inspections_template;
+DROP TABLE IF EXISTS inspections;
+DROP TABLE IF EXISTS inspection_checklist_items;
+DROP TABLE IF EXISTS inspection_templates;
@@ -0,0 +1,31 @@
+-- +migrate Up
+-- Facility Grid
+-- Facility systems declare what they supply to or draw from the vault's
+-- power and water grids. Rates are nameplate values in the grid's unit, kW
+-- for POWER and liters/day for WATER. A system may supply one grid and draw
+-- from another, e.g. water purification outputs water and draws power.
+
+CREATE TABLE facility_grid_flows (
+    id TEXT PRIMARY KEY,
+    system_id TEXT NOT NULL REFERENCES facility_systems(id),
+    grid TEXT NOT NULL CHECK (grid IN ('POWER', 'WATER')),
+    direction TEXT NOT NULL CHECK (direction IN ('OUTPUT', 'DRAW')),
+    rate REAL NOT NULL CHECK (rate >= 0),
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    updated_at TEXT NOT NULL DEFAULT (datetime('now')),
+    UNIQUE (system_id, grid, direction)
+);
+
+CREATE INDEX idx_facility_grid_flows_grid ON facility_grid_flows(grid);
+
+-- Flows are part of the system declaration and go with it.
+CREATE TRIGGER trg_facility_systems_cascade_flows
+BEFORE DELETE ON facility_systems
+BEGIN
+    DELETE FROM facility_grid_flows WHERE system_id = OLD.id;
+END;
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS trg_facility_systems_cascade_flows;
+DROP INDEX IF EXISTS idx_facility_grid_flows_grid;
+DROP TABLE IF EXISTS facility_grid_flows;
@@ -0,0 +1,49 @@
+-- +migrate Up
+-- Stock Reservations
+-- A reservation holds item quantity for planned consumption, e.g. medical
+-- supplies for a scheduled surgery. The reserved quantity is allocated
+-- across stock lots, and each lot's quantity_reserved is the sum of its
+-- active allocations. A reservation ends when it is committed (consumed),
+-- released, or expires unused.
+
+CREATE TABLE stock_reservations (
+    id TEXT PRIMARY KEY,
+    item_id TEXT NOT NULL REFERENCES resource_items(id),
+    quantity REAL NOT NULL CHECK (quantity > 0),
+    purpose TEXT NOT NULL,
+    related_entity_type TEXT,
+    related_entity_id TEXT,
+    reserved_by TEXT REFERENCES residents(id),
+    status TEXT NOT NULL DEFAULT 'ACTIVE' CHECK (status IN ('ACTIVE', 'COMMITTED', 'RELEASED', 'EXPIRED')),
+    expires_at TEXT NOT NULL,
+    closed_at TEXT,
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    updated_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX idx_stock_reservations_item ON stock_reservations(item_id);
+CREATE INDEX idx_stock_reservations_active ON stock_reservations(status, expires_at);
+
+CREATE TABLE stock_reservation_allocations (
+    reservation_id TEXT NOT NULL REFERENCES stock_reservations(id),
+    stock_id TEXT NOT NULL REFERENCES resource_stocks(id),
+    quantity REAL NOT NULL CHECK (quantity > 0),
+    PRIMARY KEY (reservation_id, stock_id)
+);
+
+CREATE INDEX idx_stock_reservation_allocations_stock ON stock_reservation_allocations(stock_id);
+
+-- Allocations are part of the reservation and go with it.
+CREATE TRIGGER trg_stock_reservations_cascade_allocations
+BEFORE DELETE ON stock_reservations
+BEGIN
+    DELETE FROM stock_reservation_allocations WHERE reservation_id = OLD.id;
+END;
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS trg_stock_reservations_cascade_allocations;
+DROP INDEX IF EXISTS idx_stock_reservation_allocations_stock;
+DROP TABLE IF EXISTS stock_reservation_allocations;
+DROP INDEX IF EXISTS idx_stock_reservations_active;
+DROP INDEX IF EXISTS idx_stock_reservations_item;
+DROP TABLE IF EXISTS stock_reservations;
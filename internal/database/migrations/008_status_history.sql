@@ -0,0 +1,39 @@
+-- +migrate Up
+-- Status History
+-- Temporary resident statuses (quarantine, surface missions) are scheduled
+-- with an expected end date. Each row is one period in the status. When the
+-- vault calendar passes the expected end, the resident is returned to ACTIVE,
+-- or the operator is prompted to confirm the return if auto_revert is off.
+-- A resident has at most one open period.
+
+CREATE TABLE status_history (
+    id TEXT PRIMARY KEY,
+    resident_id TEXT NOT NULL REFERENCES residents(id),
+    status TEXT NOT NULL CHECK (status IN ('QUARANTINE', 'SURFACE_MISSION')),
+    reason TEXT,
+    started_at TEXT NOT NULL,
+    expected_end TEXT NOT NULL,
+    auto_revert INTEGER NOT NULL DEFAULT 1,
+    prompted_at TEXT,
+    ended_at TEXT,
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    updated_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX idx_status_history_resident ON status_history(resident_id);
+CREATE INDEX idx_status_history_expected_end ON status_history(expected_end);
+CREATE UNIQUE INDEX idx_status_history_open ON status_history(resident_id) WHERE ended_at IS NULL;
+
+-- A resident's status history goes with them.
+CREATE TRIGGER trg_residents_cascade_status_history
+BEFORE DELETE ON residents
+BEGIN
+    DELETE FROM status_history WHERE resident_id = OLD.id;
+END;
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS trg_residents_cascade_status_history;
+DROP INDEX IF EXISTS idx_status_history_open;
+DROP INDEX IF EXISTS idx_status_history_expected_end;
+DROP INDEX IF EXISTS idx_status_history_resident;
+DROP TABLE IF EXISTS status_history;
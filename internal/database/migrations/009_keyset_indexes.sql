@@ -0,0 +1,16 @@
+-- +migrate Up
+-- Keyset Pagination Indexes
+-- The census and transaction history page by cursor: each page continues
+-- after the last row seen, compared on the full sort key. These indexes
+-- match the default sort keys so a page is an index range scan of page-size
+-- rows rather than a sort of the whole table.
+
+-- Census: surname, given names, registry number
+CREATE INDEX idx_residents_name_keyset ON residents(surname, given_names, registry_number);
+
+-- Transaction history: most recent first, ID as tiebreak
+CREATE INDEX idx_resource_transactions_keyset ON resource_transactions(timestamp DESC, id DESC);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_resource_transactions_keyset;
+DROP INDEX IF EXISTS idx_residents_name_keyset;
@@ -0,0 +1,46 @@
+-- +migrate Up
+-- Inventory Audits
+-- An audit session counts every lot in one storage location. Opening the
+-- audit freezes each lot's book quantity as expected_quantity; counts are
+-- recorded against it, and closing the audit applies an AUDIT_CORRECTION to
+-- every counted lot in one transaction. A location has at most one open
+-- audit.
+
+CREATE TABLE inventory_audits (
+    id TEXT PRIMARY KEY,
+    storage_location TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'OPEN' CHECK (status IN ('OPEN', 'CLOSED', 'CANCELLED')),
+    opened_by TEXT REFERENCES residents(id),
+    opened_at TEXT NOT NULL,
+    closed_at TEXT,
+    closed_by TEXT REFERENCES residents(id),
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    updated_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE UNIQUE INDEX idx_inventory_audits_open_location ON inventory_audits(storage_location) WHERE status = 'OPEN';
+
+CREATE TABLE inventory_audit_counts (
+    audit_id TEXT NOT NULL REFERENCES inventory_audits(id),
+    stock_id TEXT NOT NULL REFERENCES resource_stocks(id),
+    expected_quantity REAL NOT NULL CHECK (expected_quantity >= 0),
+    counted_quantity REAL CHECK (counted_quantity >= 0),
+    counted_at TEXT,
+    PRIMARY KEY (audit_id, stock_id)
+);
+
+CREATE INDEX idx_inventory_audit_counts_stock ON inventory_audit_counts(stock_id);
+
+-- Counts are part of the audit and go with it.
+CREATE TRIGGER trg_inventory_audits_cascade_counts
+BEFORE DELETE ON inventory_audits
+BEGIN
+    DELETE FROM inventory_audit_counts WHERE audit_id = OLD.id;
+END;
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS trg_inventory_audits_cascade_counts;
+DROP INDEX IF EXISTS idx_inventory_audit_counts_stock;
+DROP TABLE IF EXISTS inventory_audit_counts;
+DROP INDEX IF EXISTS idx_inventory_audits_open_location;
+DROP TABLE IF EXISTS inventory_audits;
@@ -0,0 +1,42 @@
+-- +migrate Up
+-- Resident Relationships
+-- Relationships beyond biological parentage, which stays on residents.
+-- resident_id and related_id read as:
+--   MARRIAGE, PARTNERSHIP: the two partners, in no particular order
+--   GUARDIANSHIP: guardian, ward
+--   NEXT_OF_KIN: the resident designating, their next of kin
+-- A relationship ends with end_date and end_reason rather than being
+-- deleted. A resident designates at most one current next of kin.
+
+CREATE TABLE resident_relationships (
+    id TEXT PRIMARY KEY,
+    relationship_type TEXT NOT NULL CHECK (relationship_type IN ('MARRIAGE', 'PARTNERSHIP', 'GUARDIANSHIP', 'NEXT_OF_KIN')),
+    resident_id TEXT NOT NULL REFERENCES residents(id),
+    related_id TEXT NOT NULL REFERENCES residents(id),
+    start_date TEXT NOT NULL,
+    end_date TEXT,
+    end_reason TEXT,
+    notes TEXT,
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    updated_at TEXT NOT NULL DEFAULT (datetime('now')),
+    CHECK (resident_id <> related_id)
+);
+
+CREATE INDEX idx_resident_relationships_resident ON resident_relationships(resident_id);
+CREATE INDEX idx_resident_relationships_related ON resident_relationships(related_id);
+CREATE UNIQUE INDEX idx_resident_relationships_next_of_kin ON resident_relationships(resident_id)
+    WHERE relationship_type = 'NEXT_OF_KIN' AND end_date IS NULL;
+
+-- A resident's relationships go with them.
+CREATE TRIGGER trg_residents_cascade_relationships
+BEFORE DELETE ON residents
+BEGIN
+    DELETE FROM resident_relationships WHERE resident_id = OLD.id OR related_id = OLD.id;
+END;
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS trg_residents_cascade_relationships;
+DROP INDEX IF EXISTS idx_resident_relationships_next_of_kin;
+DROP INDEX IF EXISTS idx_resident_relationships_related;
+DROP INDEX IF EXISTS idx_resident_relationships_resident;
+DROP TABLE IF EXISTS resident_relationships;
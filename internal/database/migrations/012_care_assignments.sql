@@ -0,0 +1,45 @@
+-- +migrate Up
+-- Care Assignments
+-- When a death leaves a minor with no living parent and no current
+-- guardian, a PENDING care assignment queues the dependent for the overseer.
+-- Assigning a guardian records a GUARDIANSHIP relationship and moves the
+-- dependent into the guardian's household. A dependent has at most one
+-- pending assignment.
+
+CREATE TABLE care_assignments (
+    id TEXT PRIMARY KEY,
+    dependent_id TEXT NOT NULL REFERENCES residents(id),
+    status TEXT NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'ASSIGNED', 'CANCELLED')),
+    reason TEXT NOT NULL,
+    opened_at TEXT NOT NULL,
+    guardian_id TEXT REFERENCES residents(id),
+    household_id TEXT REFERENCES households(id),
+    resolved_at TEXT,
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    updated_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX idx_care_assignments_status ON care_assignments(status);
+CREATE UNIQUE INDEX idx_care_assignments_pending ON care_assignments(dependent_id) WHERE status = 'PENDING';
+
+-- A dependent's care assignments go with them; a deleted guardian or
+-- household is cleared from the record.
+CREATE TRIGGER trg_residents_cascade_care_assignments
+BEFORE DELETE ON residents
+BEGIN
+    DELETE FROM care_assignments WHERE dependent_id = OLD.id;
+    UPDATE care_assignments SET guardian_id = NULL WHERE guardian_id = OLD.id;
+END;
+
+CREATE TRIGGER trg_households_set_null_care_assignments
+BEFORE DELETE ON households
+BEGIN
+    UPDATE care_assignments SET household_id = NULL WHERE household_id = OLD.id;
+END;
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS trg_households_set_null_care_assignments;
+DROP TRIGGER IF EXISTS trg_residents_cascade_care_assignments;
+DROP INDEX IF EXISTS idx_care_assignments_pending;
+DROP INDEX IF EXISTS idx_care_assignments_status;
+DROP TABLE IF EXISTS care_assignments;
@@ -0,0 +1,54 @@
+-- +migrate Up
+-- Radiation Exposure
+-- Each exposure event records a dose received; each decontamination
+-- treatment records the dose it purged and the resource item, e.g. RadAway,
+-- consumed to do it. A resident's cumulative dose is exposures less
+-- treatments. Crossing a dose threshold opens a RAD-EXPOSURE condition in
+-- medical_conditions, so the medical module sees the health flag.
+
+CREATE TABLE radiation_exposures (
+    id TEXT PRIMARY KEY,
+    resident_id TEXT NOT NULL REFERENCES residents(id),
+    source TEXT NOT NULL,
+    dose_msv REAL NOT NULL CHECK (dose_msv > 0),
+    exposure_date TEXT NOT NULL,
+    recorded_by TEXT REFERENCES residents(id),
+    notes TEXT,
+    created_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX idx_radiation_exposures_resident ON radiation_exposures(resident_id);
+CREATE INDEX idx_radiation_exposures_date ON radiation_exposures(exposure_date);
+
+CREATE TABLE decontamination_treatments (
+    id TEXT PRIMARY KEY,
+    resident_id TEXT NOT NULL REFERENCES residents(id),
+    item_id TEXT NOT NULL REFERENCES resource_items(id),
+    quantity REAL NOT NULL CHECK (quantity > 0),
+    dose_reduced_msv REAL NOT NULL CHECK (dose_reduced_msv >= 0),
+    treatment_date TEXT NOT NULL,
+    provider_id TEXT REFERENCES residents(id),
+    notes TEXT,
+    created_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX idx_decontamination_treatments_resident ON decontamination_treatments(resident_id);
+
+-- A resident's dose history goes with them; a deleted recorder or provider
+-- is cleared from the record.
+CREATE TRIGGER trg_residents_cascade_radiation
+BEFORE DELETE ON residents
+BEGIN
+    DELETE FROM radiation_exposures WHERE resident_id = OLD.id;
+    DELETE FROM decontamination_treatments WHERE resident_id = OLD.id;
+    UPDATE radiation_exposures SET recorded_by = NULL WHERE recorded_by = OLD.id;
+    UPDATE decontamination_treatments SET provider_id = NULL WHERE provider_id = OLD.id;
+END;
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS trg_residents_cascade_radiation;
+DROP INDEX IF EXISTS idx_decontamination_treatments_resident;
+DROP TABLE IF EXISTS decontamination_treatments;
+DROP INDEX IF EXISTS idx_radiation_exposures_date;
+DROP INDEX IF EXISTS idx_radiation_exposures_resident;
+DROP TABLE IF EXISTS radiation_exposures;
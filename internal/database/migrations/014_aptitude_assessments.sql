@@ -0,0 +1,50 @@
+-- +migrate Up
+-- Aptitude Assessments
+-- The vocational aptitude test residents sit when they turn 16: a score of
+-- 0-100 for each department, and the open vocation it recommends given the
+-- resident's clearance and vacancies. applied_at records when the
+-- recommended vocation was assigned. A resident may be reassessed; the most
+-- recent assessment stands.
+
+CREATE TABLE aptitude_assessments (
+    id TEXT PRIMARY KEY,
+    resident_id TEXT NOT NULL REFERENCES residents(id),
+    assessed_at TEXT NOT NULL,
+    administered_by TEXT REFERENCES residents(id),
+    score_engineering INTEGER NOT NULL CHECK (score_engineering BETWEEN 0 AND 100),
+    score_medical INTEGER NOT NULL CHECK (score_medical BETWEEN 0 AND 100),
+    score_security INTEGER NOT NULL CHECK (score_security BETWEEN 0 AND 100),
+    score_food_production INTEGER NOT NULL CHECK (score_food_production BETWEEN 0 AND 100),
+    score_administration INTEGER NOT NULL CHECK (score_administration BETWEEN 0 AND 100),
+    score_education INTEGER NOT NULL CHECK (score_education BETWEEN 0 AND 100),
+    score_sanitation INTEGER NOT NULL CHECK (score_sanitation BETWEEN 0 AND 100),
+    score_research INTEGER NOT NULL CHECK (score_research BETWEEN 0 AND 100),
+    recommended_vocation_id TEXT REFERENCES vocations(id),
+    applied_at TEXT,
+    notes TEXT,
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    updated_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX idx_aptitude_assessments_resident ON aptitude_assessments(resident_id, assessed_at);
+
+-- A resident's assessments go with them; a deleted administrator or
+-- vocation is cleared from the record.
+CREATE TRIGGER trg_residents_cascade_aptitude_assessments
+BEFORE DELETE ON residents
+BEGIN
+    DELETE FROM aptitude_assessments WHERE resident_id = OLD.id;
+    UPDATE aptitude_assessments SET administered_by = NULL WHERE administered_by = OLD.id;
+END;
+
+CREATE TRIGGER trg_vocations_set_null_aptitude_assessments
+BEFORE DELETE ON vocations
+BEGIN
+    UPDATE aptitude_assessments SET recommended_vocation_id = NULL WHERE recommended_vocation_id = OLD.id;
+END;
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS trg_vocations_set_null_aptitude_assessments;
+DROP TRIGGER IF EXISTS trg_residents_cascade_aptitude_assessments;
+DROP INDEX IF EXISTS idx_aptitude_assessments_resident;
+DROP TABLE IF EXISTS aptitude_assessments;
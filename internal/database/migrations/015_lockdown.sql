@@ -0,0 +1,29 @@
+-- +migrate Up
+-- Vault Lockdown
+-- Every change of the vault's alert state (NORMAL, DRILL, LOCKDOWN,
+-- EMERGENCY) with the operator who authorized it. The most recent change is
+-- the current state; a vault with no changes is NORMAL.
+
+CREATE TABLE lockdown_changes (
+    id TEXT PRIMARY KEY,
+    state TEXT NOT NULL CHECK (state IN ('NORMAL', 'DRILL', 'LOCKDOWN', 'EMERGENCY')),
+    previous_state TEXT NOT NULL CHECK (previous_state IN ('NORMAL', 'DRILL', 'LOCKDOWN', 'EMERGENCY')),
+    authorized_by TEXT REFERENCES residents(id),
+    reason TEXT,
+    changed_at TEXT NOT NULL,
+    created_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX idx_lockdown_changes_changed ON lockdown_changes(changed_at);
+
+-- The log outlives the operator; a deleted operator is cleared from it.
+CREATE TRIGGER trg_residents_set_null_lockdown_changes
+BEFORE DELETE ON residents
+BEGIN
+    UPDATE lockdown_changes SET authorized_by = NULL WHERE authorized_by = OLD.id;
+END;
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS trg_residents_set_null_lockdown_changes;
+DROP INDEX IF EXISTS idx_lockdown_changes_changed;
+DROP TABLE IF EXISTS lockdown_changes;
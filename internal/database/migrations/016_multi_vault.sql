@@ -0,0 +1,34 @@
+-- +migrate Up
+-- Multi-Vault Scoping
+-- Residents, households, resource stocks and facility systems belong to the
+-- vault numbered by vault_id, so one database can hold several vaults. The
+-- catalogs they share (vocations, resource items, quarters) stay unscoped.
+-- Residents take the vault of their registry number, V<vault>-<serial>, and
+-- households the vault of their head. Rows left at 0 are claimed by the
+-- primary vault on startup.
+
+ALTER TABLE residents ADD COLUMN vault_id INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE households ADD COLUMN vault_id INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE resource_stocks ADD COLUMN vault_id INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE facility_systems ADD COLUMN vault_id INTEGER NOT NULL DEFAULT 0;
+
+UPDATE residents SET vault_id = CAST(substr(registry_number, 2, 3) AS INTEGER)
+WHERE registry_number GLOB 'V[0-9][0-9][0-9]-*';
+
+UPDATE households SET vault_id = COALESCE(
+    (SELECT r.vault_id FROM residents r WHERE r.id = households.head_of_household_id), 0);
+
+CREATE INDEX idx_residents_vault ON residents(vault_id, status);
+CREATE INDEX idx_households_vault ON households(vault_id);
+CREATE INDEX idx_resource_stocks_vault ON resource_stocks(vault_id);
+CREATE INDEX idx_facility_systems_vault ON facility_systems(vault_id);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_facility_systems_vault;
+DROP INDEX IF EXISTS idx_resource_stocks_vault;
+DROP INDEX IF EXISTS idx_households_vault;
+DROP INDEX IF EXISTS idx_residents_vault;
+ALTER TABLE facility_systems DROP COLUMN vault_id;
+ALTER TABLE resource_stocks DROP COLUMN vault_id;
+ALTER TABLE households DROP COLUMN vault_id;
+ALTER TABLE residents DROP COLUMN vault_id;
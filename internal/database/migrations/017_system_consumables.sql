@@ -0,0 +1,35 @@
+-- +migrate Up
+-- System Consumables
+-- Filters, membranes and other parts a facility system wears through on a
+-- fixed cycle, e.g. the carbon filters of water purification or the HEPA
+-- cartridges of air filtration. Each declares the resource item it uses,
+-- the quantity one replacement takes and the days between replacements.
+-- Completing maintenance of the system replaces them, drawing the items
+-- from stock and restarting the cycle.
+
+CREATE TABLE system_consumables (
+    id TEXT PRIMARY KEY,
+    system_id TEXT NOT NULL REFERENCES facility_systems(id),
+    item_id TEXT NOT NULL REFERENCES resource_items(id),
+    quantity REAL NOT NULL CHECK (quantity > 0),
+    interval_days INTEGER NOT NULL CHECK (interval_days > 0),
+    last_replaced_at TEXT,
+    next_replacement_due TEXT,
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    updated_at TEXT NOT NULL DEFAULT (datetime('now')),
+    UNIQUE (system_id, item_id)
+);
+
+CREATE INDEX idx_system_consumables_item ON system_consumables(item_id);
+
+-- Consumables are part of the system declaration and go with it.
+CREATE TRIGGER trg_facility_systems_cascade_consumables
+BEFORE DELETE ON facility_systems
+BEGIN
+    DELETE FROM system_consumables WHERE system_id = OLD.id;
+END;
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS trg_facility_systems_cascade_consumables;
+DROP INDEX IF EXISTS idx_system_consumables_item;
+DROP TABLE IF EXISTS system_consumables;
@@ -0,0 +1,88 @@
+-- +migrate Up
+-- Vocational Training
+-- A training program sets the hours an apprentice trains before taking up
+-- a vocation, and how many of them a vault day of training counts for. An
+-- apprenticeship enrolls a resident in a vocation under a mentor who holds
+-- it; hours accrue with vault time, and the mentor's sign-off once they are
+-- complete graduates the apprentice to the vocation. The program's hours are
+-- copied to the apprenticeship at enrollment, so changing a program does not
+-- move the goalposts of apprentices already training.
+
+CREATE TABLE training_programs (
+    id TEXT PRIMARY KEY,
+    vocation_id TEXT NOT NULL UNIQUE REFERENCES vocations(id),
+    required_hours REAL NOT NULL CHECK (required_hours > 0),
+    hours_per_day REAL NOT NULL CHECK (hours_per_day > 0 AND hours_per_day <= 24),
+    description TEXT,
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    updated_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE TABLE apprenticeships (
+    id TEXT PRIMARY KEY,
+    resident_id TEXT NOT NULL REFERENCES residents(id),
+    vocation_id TEXT NOT NULL REFERENCES vocations(id),
+    mentor_id TEXT REFERENCES residents(id),
+    status TEXT NOT NULL DEFAULT 'ACTIVE' CHECK (status IN ('ACTIVE', 'GRADUATED', 'WITHDRAWN')),
+    enrolled_at TEXT NOT NULL,
+    required_hours REAL NOT NULL CHECK (required_hours > 0),
+    hours_per_day REAL NOT NULL CHECK (hours_per_day > 0 AND hours_per_day <= 24),
+    hours_completed REAL NOT NULL DEFAULT 0 CHECK (hours_completed >= 0),
+    signed_off_by TEXT REFERENCES residents(id),
+    ended_at TEXT,
+    notes TEXT,
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    updated_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX idx_apprenticeships_resident ON apprenticeships(resident_id);
+CREATE INDEX idx_apprenticeships_vocation ON apprenticeships(vocation_id);
+CREATE UNIQUE INDEX idx_apprenticeships_active ON apprenticeships(resident_id) WHERE status = 'ACTIVE';
+
+-- Every existing vocation gets a program: 400 hours per clearance level
+-- required, plus 200 to 600 for hazardous work, trained 6 hours a day.
+-- models.DefaultTrainingHours gives vocations seeded later the same.
+INSERT INTO training_programs (id, vocation_id, required_hours, hours_per_day)
+SELECT 'TP-' || id, id,
+    400 * required_clearance + CASE hazard_level
+        WHEN 'MODERATE' THEN 200
+        WHEN 'HIGH' THEN 400
+        WHEN 'EXTREME' THEN 600
+        ELSE 0
+    END,
+    6
+FROM vocations;
+
+-- An apprentice's training goes with them; a deleted mentor or signer is
+-- cleared from the record.
+CREATE TRIGGER trg_residents_cascade_apprenticeships
+BEFORE DELETE ON residents
+BEGIN
+    DELETE FROM apprenticeships WHERE resident_id = OLD.id;
+    UPDATE apprenticeships SET mentor_id = NULL WHERE mentor_id = OLD.id;
+    UPDATE apprenticeships SET signed_off_by = NULL WHERE signed_off_by = OLD.id;
+END;
+
+-- A vocation's program goes with it; its apprenticeships hold it back.
+CREATE TRIGGER trg_vocations_restrict_apprenticeships
+BEFORE DELETE ON vocations
+WHEN EXISTS (SELECT 1 FROM apprenticeships WHERE vocation_id = OLD.id)
+BEGIN
+    SELECT RAISE(ABORT, 'restrict: vocation has apprenticeships');
+END;
+
+CREATE TRIGGER trg_vocations_cascade_training_programs
+BEFORE DELETE ON vocations
+BEGIN
+    DELETE FROM training_programs WHERE vocation_id = OLD.id;
+END;
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS trg_vocations_cascade_training_programs;
+DROP TRIGGER IF EXISTS trg_vocations_restrict_apprenticeships;
+DROP TRIGGER IF EXISTS trg_residents_cascade_apprenticeships;
+DROP INDEX IF EXISTS idx_apprenticeships_active;
+DROP INDEX IF EXISTS idx_apprenticeships_vocation;
+DROP INDEX IF EXISTS idx_apprenticeships_resident;
+DROP TABLE IF EXISTS apprenticeships;
+DROP TABLE IF EXISTS training_programs;
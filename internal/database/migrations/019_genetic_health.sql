@@ -0,0 +1,27 @@
+-- +migrate Up
+-- Genetic Health
+-- Periodic snapshots of the genetic diversity of each vault's living
+-- population: founder representation, mean kinship across breeding-age
+-- pairs and how many of those pairs are viable, scored 0-100. The series
+-- of snapshots gives the trend.
+
+CREATE TABLE genetic_health_snapshots (
+    id TEXT PRIMARY KEY,
+    vault_id INTEGER NOT NULL DEFAULT 0,
+    taken_at TEXT NOT NULL,
+    population INTEGER NOT NULL CHECK (population >= 0),
+    founders INTEGER NOT NULL CHECK (founders >= 0),
+    founders_represented INTEGER NOT NULL CHECK (founders_represented BETWEEN 0 AND founders),
+    effective_founders REAL NOT NULL CHECK (effective_founders >= 0),
+    breeding_pairs INTEGER NOT NULL CHECK (breeding_pairs >= 0),
+    viable_pairs INTEGER NOT NULL CHECK (viable_pairs BETWEEN 0 AND breeding_pairs),
+    mean_kinship REAL NOT NULL CHECK (mean_kinship BETWEEN 0 AND 1),
+    score INTEGER NOT NULL CHECK (score BETWEEN 0 AND 100),
+    created_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX idx_genetic_health_snapshots_taken ON genetic_health_snapshots(vault_id, taken_at);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_genetic_health_snapshots_taken;
+DROP TABLE IF EXISTS genetic_health_snapshots;
@@ -0,0 +1,38 @@
+-- +migrate Up
+-- Ration Policies
+-- The daily calorie and water targets of each ration class, set by the
+-- overseer from an effective date. The policy in force for a class on a
+-- given day is its latest with effective_from at or before that day; later
+-- policies are scheduled changes, and earlier ones the history. Every vault
+-- administered from the database rations by the same policies.
+
+CREATE TABLE ration_policies (
+    id TEXT PRIMARY KEY,
+    ration_class TEXT NOT NULL CHECK (ration_class IN ('MINIMAL', 'STANDARD', 'ENHANCED', 'MEDICAL', 'LABOR_INTENSIVE')),
+    calorie_target INTEGER NOT NULL CHECK (calorie_target > 0),
+    water_target_l REAL NOT NULL CHECK (water_target_l > 0),
+    effective_from TEXT NOT NULL,
+    set_by TEXT REFERENCES residents(id),
+    reason TEXT,
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    UNIQUE (ration_class, effective_from)
+);
+
+-- The targets ration classes had before policies, in force from the start
+INSERT INTO ration_policies (id, ration_class, calorie_target, water_target_l, effective_from, reason) VALUES
+    ('RP-MINIMAL', 'MINIMAL', 1500, 2.0, '0001-01-01T00:00:00Z', 'Initial policy'),
+    ('RP-STANDARD', 'STANDARD', 2000, 3.0, '0001-01-01T00:00:00Z', 'Initial policy'),
+    ('RP-ENHANCED', 'ENHANCED', 2500, 3.5, '0001-01-01T00:00:00Z', 'Initial policy'),
+    ('RP-MEDICAL', 'MEDICAL', 2000, 3.0, '0001-01-01T00:00:00Z', 'Initial policy'),
+    ('RP-LABOR_INTENSIVE', 'LABOR_INTENSIVE', 3000, 4.0, '0001-01-01T00:00:00Z', 'Initial policy');
+
+-- The history outlives the overseer; a deleted setter is cleared from it.
+CREATE TRIGGER trg_residents_set_null_ration_policies
+BEFORE DELETE ON residents
+BEGIN
+    UPDATE ration_policies SET set_by = NULL WHERE set_by = OLD.id;
+END;
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS trg_residents_set_null_ration_policies;
+DROP TABLE IF EXISTS ration_policies;
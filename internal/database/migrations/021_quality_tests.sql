@@ -0,0 +1,31 @@
+-- +migrate Up
+-- Quality Tests
+-- Water and food quality tests of consumable lots: when and by whom the lot
+-- was tested, the contamination measured and whether it passed. A failed
+-- test quarantines the lot in the same transaction. Tests are never edited;
+-- a retest is a new row.
+
+CREATE TABLE quality_tests (
+    id TEXT PRIMARY KEY,
+    stock_id TEXT NOT NULL REFERENCES resource_stocks(id),
+    tested_at TEXT NOT NULL,
+    tested_by TEXT REFERENCES residents(id),
+    contamination_level REAL NOT NULL CHECK (contamination_level >= 0),
+    passed INTEGER NOT NULL CHECK (passed IN (0, 1)),
+    notes TEXT,
+    created_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX idx_quality_tests_stock ON quality_tests(stock_id, tested_at);
+
+-- The record outlives the tester; a deleted tester is cleared from it.
+CREATE TRIGGER trg_residents_set_null_quality_tests
+BEFORE DELETE ON residents
+BEGIN
+    UPDATE quality_tests SET tested_by = NULL WHERE tested_by = OLD.id;
+END;
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS trg_residents_set_null_quality_tests;
+DROP INDEX IF EXISTS idx_quality_tests_stock;
+DROP TABLE IF EXISTS quality_tests;
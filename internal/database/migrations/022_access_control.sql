@@ -0,0 +1,77 @@
+-- +migrate Up
+-- Access Control
+-- Doors and airlocks of a vault, each admitting residents who hold its
+-- minimum clearance. A grant lets one resident through one point whatever
+-- their clearance, until it expires or is revoked. Every attempt to pass a
+-- point, granted or denied, is logged; attempts raised by a drill are
+-- marked simulated. Events are never edited.
+
+CREATE TABLE access_points (
+    id TEXT PRIMARY KEY,
+    code TEXT UNIQUE NOT NULL,
+    name TEXT NOT NULL,
+    kind TEXT NOT NULL CHECK (kind IN ('DOOR', 'AIRLOCK')),
+    location_sector TEXT,
+    location_level INTEGER NOT NULL DEFAULT 0,
+    min_clearance INTEGER NOT NULL CHECK (min_clearance BETWEEN 1 AND 10),
+    is_active INTEGER NOT NULL DEFAULT 1 CHECK (is_active IN (0, 1)),
+    vault_id INTEGER NOT NULL DEFAULT 0,
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    updated_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX idx_access_points_vault ON access_points(vault_id);
+
+CREATE TABLE access_grants (
+    id TEXT PRIMARY KEY,
+    resident_id TEXT NOT NULL REFERENCES residents(id),
+    access_point_id TEXT NOT NULL REFERENCES access_points(id),
+    granted_by TEXT REFERENCES residents(id),
+    granted_at TEXT NOT NULL,
+    expires_at TEXT,
+    revoked_at TEXT,
+    reason TEXT,
+    created_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX idx_access_grants_resident ON access_grants(resident_id, access_point_id);
+CREATE INDEX idx_access_grants_point ON access_grants(access_point_id);
+
+CREATE TABLE access_events (
+    id TEXT PRIMARY KEY,
+    access_point_id TEXT NOT NULL REFERENCES access_points(id),
+    resident_id TEXT REFERENCES residents(id),
+    occurred_at TEXT NOT NULL,
+    granted INTEGER NOT NULL CHECK (granted IN (0, 1)),
+    reason TEXT NOT NULL CHECK (reason IN ('CLEARANCE', 'GRANT', 'INSUFFICIENT',
+        'RESIDENT_INACTIVE', 'POINT_DISABLED', 'UNKNOWN_CREDENTIAL')),
+    simulated INTEGER NOT NULL DEFAULT 0 CHECK (simulated IN (0, 1)),
+    created_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX idx_access_events_point ON access_events(access_point_id, occurred_at);
+CREATE INDEX idx_access_events_resident ON access_events(resident_id, occurred_at);
+CREATE INDEX idx_access_events_occurred ON access_events(occurred_at);
+
+-- A deleted resident's grants go with them; the log outlives them and the
+-- grants they gave, clearing them from both. Points are deactivated, never
+-- deleted.
+CREATE TRIGGER trg_residents_cascade_access_grants
+BEFORE DELETE ON residents
+BEGIN
+    DELETE FROM access_grants WHERE resident_id = OLD.id;
+    UPDATE access_grants SET granted_by = NULL WHERE granted_by = OLD.id;
+    UPDATE access_events SET resident_id = NULL WHERE resident_id = OLD.id;
+END;
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS trg_residents_cascade_access_grants;
+DROP INDEX IF EXISTS idx_access_events_occurred;
+DROP INDEX IF EXISTS idx_access_events_resident;
+DROP INDEX IF EXISTS idx_access_events_point;
+DROP TABLE IF EXISTS access_events;
+DROP INDEX IF EXISTS idx_access_grants_point;
+DROP INDEX IF EXISTS idx_access_grants_resident;
+DROP TABLE IF EXISTS access_grants;
+DROP INDEX IF EXISTS idx_access_points_vault;
+DROP TABLE IF EXISTS access_points;
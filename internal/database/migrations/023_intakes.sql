@@ -0,0 +1,56 @@
+-- +migrate Up
+-- Admission Intake
+-- An outsider admitted to the vault, a visitor or a survivor found on the
+-- surface, enters as a QUARANTINE resident under an intake: a quarantine of
+-- at least 14 days and the medical screenings scheduled within it. A
+-- medical officer clears the intake once every screening has passed, and
+-- the resident is promoted to ACTIVE when the quarantine has run. The
+-- quarantine itself is the resident's open status_history period.
+
+CREATE TABLE intakes (
+    id TEXT PRIMARY KEY,
+    resident_id TEXT NOT NULL UNIQUE REFERENCES residents(id),
+    admitted_at TEXT NOT NULL,
+    quarantine_days INTEGER NOT NULL CHECK (quarantine_days >= 14),
+    quarantine_end TEXT NOT NULL,
+    cleared_at TEXT,
+    cleared_by TEXT REFERENCES residents(id),
+    notes TEXT,
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    updated_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX idx_intakes_pending ON intakes(admitted_at) WHERE cleared_at IS NULL;
+
+CREATE TABLE intake_screenings (
+    id TEXT PRIMARY KEY,
+    intake_id TEXT NOT NULL REFERENCES intakes(id),
+    screening TEXT NOT NULL CHECK (screening IN (
+        'PHYSICAL', 'RADIATION', 'INFECTIOUS_DISEASE', 'PSYCHOLOGICAL'
+    )),
+    due_date TEXT NOT NULL,
+    completed_at TEXT,
+    completed_by TEXT REFERENCES residents(id),
+    passed INTEGER CHECK (passed IN (0, 1)),
+    notes TEXT,
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    updated_at TEXT NOT NULL DEFAULT (datetime('now')),
+    UNIQUE (intake_id, screening)
+);
+
+-- A resident's intake and its screenings go with them; a deleted medical
+-- officer is cleared from the intakes they cleared or screened.
+CREATE TRIGGER trg_residents_cascade_intakes
+BEFORE DELETE ON residents
+BEGIN
+    DELETE FROM intake_screenings WHERE intake_id IN (SELECT id FROM intakes WHERE resident_id = OLD.id);
+    DELETE FROM intakes WHERE resident_id = OLD.id;
+    UPDATE intakes SET cleared_by = NULL WHERE cleared_by = OLD.id;
+    UPDATE intake_screenings SET completed_by = NULL WHERE completed_by = OLD.id;
+END;
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS trg_residents_cascade_intakes;
+DROP TABLE IF EXISTS intake_screenings;
+DROP INDEX IF EXISTS idx_intakes_pending;
+DROP TABLE IF EXISTS intakes;
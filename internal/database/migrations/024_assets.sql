@@ -0,0 +1,92 @@
+-- +migrate Up
+-- Asset Registry
+-- Non-consumable items tracked one by one by serial number: tools, weapons,
+-- Pip-Boys and the like, which the lot and quantity based resource stock
+-- cannot represent. Each period an asset spends in a resident's custody is
+-- kept: a check-out due back by a date, or a standing assignment with no
+-- due date. At most one custody of an asset is open at a time. The
+-- condition found at every inspection and return is logged.
+
+CREATE TABLE assets (
+    id TEXT PRIMARY KEY,
+    serial_number TEXT UNIQUE NOT NULL,
+    name TEXT NOT NULL,
+    category TEXT NOT NULL CHECK (category IN ('TOOL', 'WEAPON', 'PIP_BOY',
+        'ELECTRONICS', 'MEDICAL', 'PROTECTIVE', 'OTHER')),
+    condition TEXT NOT NULL CHECK (condition IN ('EXCELLENT', 'GOOD', 'FAIR', 'POOR', 'BROKEN')),
+    location TEXT,
+    acquired_at TEXT,
+    retired_at TEXT,
+    notes TEXT,
+    vault_id INTEGER NOT NULL DEFAULT 0,
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    updated_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX idx_assets_vault ON assets(vault_id, category);
+
+CREATE TABLE asset_custody (
+    id TEXT PRIMARY KEY,
+    asset_id TEXT NOT NULL REFERENCES assets(id),
+    resident_id TEXT NOT NULL REFERENCES residents(id),
+    issued_at TEXT NOT NULL,
+    issued_by TEXT REFERENCES residents(id),
+    due_at TEXT,
+    condition_out TEXT NOT NULL CHECK (condition_out IN ('EXCELLENT', 'GOOD', 'FAIR', 'POOR', 'BROKEN')),
+    returned_at TEXT,
+    received_by TEXT REFERENCES residents(id),
+    condition_in TEXT CHECK (condition_in IN ('EXCELLENT', 'GOOD', 'FAIR', 'POOR', 'BROKEN')),
+    notes TEXT,
+    created_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX idx_asset_custody_asset ON asset_custody(asset_id, issued_at);
+CREATE INDEX idx_asset_custody_resident ON asset_custody(resident_id, returned_at);
+CREATE UNIQUE INDEX idx_asset_custody_open ON asset_custody(asset_id) WHERE returned_at IS NULL;
+
+CREATE TABLE asset_condition_reports (
+    id TEXT PRIMARY KEY,
+    asset_id TEXT NOT NULL REFERENCES assets(id),
+    condition TEXT NOT NULL CHECK (condition IN ('EXCELLENT', 'GOOD', 'FAIR', 'POOR', 'BROKEN')),
+    previous TEXT NOT NULL CHECK (previous IN ('EXCELLENT', 'GOOD', 'FAIR', 'POOR', 'BROKEN')),
+    recorded_at TEXT NOT NULL,
+    recorded_by TEXT REFERENCES residents(id),
+    custody_id TEXT REFERENCES asset_custody(id),
+    notes TEXT,
+    created_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX idx_asset_condition_reports_asset ON asset_condition_reports(asset_id, recorded_at);
+
+-- A resident still holding an asset cannot be deleted; check it in first.
+-- Otherwise their custody history goes with them, and they are cleared
+-- from the custody they issued or received and the reports they recorded.
+CREATE TRIGGER trg_residents_restrict_asset_custody
+BEFORE DELETE ON residents
+WHEN EXISTS (SELECT 1 FROM asset_custody WHERE resident_id = OLD.id AND returned_at IS NULL)
+BEGIN
+    SELECT RAISE(ABORT, 'restrict: resident holds assets');
+END;
+
+CREATE TRIGGER trg_residents_cascade_asset_custody
+BEFORE DELETE ON residents
+BEGIN
+    UPDATE asset_condition_reports SET custody_id = NULL
+    WHERE custody_id IN (SELECT id FROM asset_custody WHERE resident_id = OLD.id AND returned_at IS NOT NULL);
+    DELETE FROM asset_custody WHERE resident_id = OLD.id AND returned_at IS NOT NULL;
+    UPDATE asset_custody SET issued_by = NULL WHERE issued_by = OLD.id;
+    UPDATE asset_custody SET received_by = NULL WHERE received_by = OLD.id;
+    UPDATE asset_condition_reports SET recorded_by = NULL WHERE recorded_by = OLD.id;
+END;
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS trg_residents_cascade_asset_custody;
+DROP TRIGGER IF EXISTS trg_residents_restrict_asset_custody;
+DROP INDEX IF EXISTS idx_asset_condition_reports_asset;
+DROP TABLE IF EXISTS asset_condition_reports;
+DROP INDEX IF EXISTS idx_asset_custody_open;
+DROP INDEX IF EXISTS idx_asset_custody_resident;
+DROP INDEX IF EXISTS idx_asset_custody_asset;
+DROP TABLE IF EXISTS asset_custody;
+DROP INDEX IF EXISTS idx_assets_vault;
+DROP TABLE IF EXISTS assets;
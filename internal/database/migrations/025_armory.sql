@@ -0,0 +1,50 @@
+-- +migrate Up
+-- Armory
+-- WEAPON assets under armory control. A weapon record sets the clearance a
+-- resident needs to carry it and the ammunition it fires, a resource item.
+-- Every weapon custody carries an issue record: the officer who approved
+-- it, the open security incident it was issued for or a drill, the rounds
+-- issued, and on return the rounds expended, which are drawn from stock.
+
+CREATE TABLE armory_weapons (
+    asset_id TEXT PRIMARY KEY REFERENCES assets(id),
+    authorization_level INTEGER NOT NULL CHECK (authorization_level BETWEEN 1 AND 10),
+    ammo_item_id TEXT REFERENCES resource_items(id),
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    updated_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE TABLE armory_issues (
+    custody_id TEXT PRIMARY KEY REFERENCES asset_custody(id),
+    approved_by TEXT REFERENCES residents(id),
+    incident_id TEXT REFERENCES security_incidents(id),
+    drill INTEGER NOT NULL DEFAULT 0 CHECK (drill IN (0, 1)),
+    rounds_issued INTEGER NOT NULL DEFAULT 0 CHECK (rounds_issued >= 0),
+    rounds_expended INTEGER CHECK (rounds_expended BETWEEN 0 AND rounds_issued),
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    CHECK ((incident_id IS NULL) = (drill = 1))
+);
+
+CREATE INDEX idx_armory_issues_incident ON armory_issues(incident_id);
+
+-- An issue record goes with its custody when a resident's returned custody
+-- is deleted with them; an approving officer's deletion clears them from
+-- the issues they approved.
+CREATE TRIGGER trg_asset_custody_cascade_armory_issues
+BEFORE DELETE ON asset_custody
+BEGIN
+    DELETE FROM armory_issues WHERE custody_id = OLD.id;
+END;
+
+CREATE TRIGGER trg_residents_cascade_armory_issues
+BEFORE DELETE ON residents
+BEGIN
+    UPDATE armory_issues SET approved_by = NULL WHERE approved_by = OLD.id;
+END;
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS trg_residents_cascade_armory_issues;
+DROP TRIGGER IF EXISTS trg_asset_custody_cascade_armory_issues;
+DROP INDEX IF EXISTS idx_armory_issues_incident;
+DROP TABLE IF EXISTS armory_issues;
+DROP TABLE IF EXISTS armory_weapons;
@@ -0,0 +1,68 @@
+-- +migrate Up
+-- Announcements
+-- Overseer broadcasts, department notices and notices the simulation raises
+-- itself, e.g. for scheduled maintenance downtime, each addressed to every
+-- resident of a vault, the residents whose primary vocation is in one
+-- department, or the members of one household. A system notice names what
+-- raised it, so it is raised once. Residents acknowledge what they have
+-- read; announcements are never edited.
+
+CREATE TABLE announcements (
+    id TEXT PRIMARY KEY,
+    kind TEXT NOT NULL CHECK (kind IN ('BROADCAST', 'NOTICE', 'SYSTEM')),
+    title TEXT NOT NULL,
+    body TEXT,
+    audience TEXT NOT NULL CHECK (audience IN ('ALL', 'DEPARTMENT', 'HOUSEHOLD')),
+    department TEXT CHECK (department IN ('ENGINEERING', 'MEDICAL', 'SECURITY', 'FOOD_PRODUCTION',
+        'ADMINISTRATION', 'EDUCATION', 'SANITATION', 'RESEARCH')),
+    household_id TEXT REFERENCES households(id),
+    issued_by TEXT REFERENCES residents(id),
+    issued_at TEXT NOT NULL,
+    expires_at TEXT,
+    source TEXT,
+    vault_id INTEGER NOT NULL DEFAULT 0,
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    CHECK ((audience = 'DEPARTMENT') = (department IS NOT NULL)),
+    CHECK ((audience = 'HOUSEHOLD') = (household_id IS NOT NULL))
+);
+
+CREATE INDEX idx_announcements_vault ON announcements(vault_id, issued_at);
+CREATE INDEX idx_announcements_household ON announcements(household_id);
+CREATE UNIQUE INDEX idx_announcements_source ON announcements(source) WHERE source IS NOT NULL;
+
+CREATE TABLE announcement_acks (
+    announcement_id TEXT NOT NULL REFERENCES announcements(id),
+    resident_id TEXT NOT NULL REFERENCES residents(id),
+    acknowledged_at TEXT NOT NULL,
+    PRIMARY KEY (announcement_id, resident_id)
+);
+
+CREATE INDEX idx_announcement_acks_resident ON announcement_acks(resident_id);
+
+-- A deleted resident's acknowledgments go with them and they are cleared
+-- from the announcements they issued. Announcements to a deleted household
+-- go with it, acknowledgments and all.
+CREATE TRIGGER trg_residents_cascade_announcements
+BEFORE DELETE ON residents
+BEGIN
+    DELETE FROM announcement_acks WHERE resident_id = OLD.id;
+    UPDATE announcements SET issued_by = NULL WHERE issued_by = OLD.id;
+END;
+
+CREATE TRIGGER trg_households_cascade_announcements
+BEFORE DELETE ON households
+BEGIN
+    DELETE FROM announcement_acks WHERE announcement_id IN
+        (SELECT id FROM announcements WHERE household_id = OLD.id);
+    DELETE FROM announcements WHERE household_id = OLD.id;
+END;
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS trg_households_cascade_announcements;
+DROP TRIGGER IF EXISTS trg_residents_cascade_announcements;
+DROP INDEX IF EXISTS idx_announcement_acks_resident;
+DROP TABLE IF EXISTS announcement_acks;
+DROP INDEX IF EXISTS idx_announcements_source;
+DROP INDEX IF EXISTS idx_announcements_household;
+DROP INDEX IF EXISTS idx_announcements_vault;
+DROP TABLE IF EXISTS announcements;
@@ -0,0 +1,94 @@
+-- +migrate Up
+-- Damaged Stock
+-- A lot found damaged is set DAMAGED from the stock detail view, taking it
+-- off the books like a quarantined lot until an operator writes it off or
+-- releases it. SQLite cannot alter a CHECK constraint, so resource_stocks is
+-- rebuilt with DAMAGED added. Foreign keys stay on inside the migration's
+-- transaction: deferring them lets the table be dropped and refilled, and
+-- every row referencing a lot finds it again by commit.
+
+PRAGMA defer_foreign_keys = ON;
+
+CREATE TEMP TABLE resource_stocks_027 AS SELECT * FROM resource_stocks;
+
+DROP TABLE resource_stocks;
+
+CREATE TABLE resource_stocks (
+    id TEXT PRIMARY KEY,
+    item_id TEXT NOT NULL REFERENCES resource_items(id),
+    lot_number TEXT,
+    quantity REAL NOT NULL CHECK (quantity >= 0),
+    quantity_reserved REAL NOT NULL DEFAULT 0 CHECK (quantity_reserved >= 0),
+    storage_location TEXT NOT NULL,
+    received_date TEXT NOT NULL,
+    expiration_date TEXT,
+    status TEXT NOT NULL DEFAULT 'AVAILABLE' CHECK (status IN ('AVAILABLE', 'RESERVED', 'QUARANTINE', 'EXPIRED', 'DEPLETED', 'DAMAGED')),
+    last_audit_date TEXT,
+    last_audit_by TEXT REFERENCES residents(id),
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    updated_at TEXT NOT NULL DEFAULT (datetime('now')),
+    vault_id INTEGER NOT NULL DEFAULT 0
+);
+
+INSERT INTO resource_stocks SELECT * FROM resource_stocks_027;
+
+DROP TABLE resource_stocks_027;
+
+CREATE INDEX idx_resource_stocks_item ON resource_stocks(item_id);
+CREATE INDEX idx_resource_stocks_status ON resource_stocks(status);
+CREATE INDEX idx_resource_stocks_expiration ON resource_stocks(expiration_date);
+CREATE INDEX idx_resource_stocks_location ON resource_stocks(storage_location);
+CREATE INDEX idx_resource_stocks_available ON resource_stocks(item_id, status, quantity)
+    WHERE status = 'AVAILABLE' AND quantity > 0;
+CREATE INDEX idx_resource_stocks_expiring ON resource_stocks(status, expiration_date)
+    WHERE status = 'AVAILABLE' AND expiration_date IS NOT NULL;
+CREATE INDEX idx_resource_stocks_fifo ON resource_stocks(item_id, received_date)
+    WHERE status = 'AVAILABLE';
+CREATE INDEX idx_resource_stocks_audit ON resource_stocks(last_audit_by)
+    WHERE last_audit_by IS NOT NULL;
+CREATE INDEX idx_resource_stocks_vault ON resource_stocks(vault_id);
+
+-- +migrate Down
+-- Damaged lots go back to quarantine, the nearest status the old schema has.
+PRAGMA defer_foreign_keys = ON;
+
+CREATE TEMP TABLE resource_stocks_027 AS SELECT * FROM resource_stocks;
+
+UPDATE resource_stocks_027 SET status = 'QUARANTINE' WHERE status = 'DAMAGED';
+
+DROP TABLE resource_stocks;
+
+CREATE TABLE resource_stocks (
+    id TEXT PRIMARY KEY,
+    item_id TEXT NOT NULL REFERENCES resource_items(id),
+    lot_number TEXT,
+    quantity REAL NOT NULL CHECK (quantity >= 0),
+    quantity_reserved REAL NOT NULL DEFAULT 0 CHECK (quantity_reserved >= 0),
+    storage_location TEXT NOT NULL,
+    received_date TEXT NOT NULL,
+    expiration_date TEXT,
+    status TEXT NOT NULL DEFAULT 'AVAILABLE' CHECK (status IN ('AVAILABLE', 'RESERVED', 'QUARANTINE', 'EXPIRED', 'DEPLETED')),
+    last_audit_date TEXT,
+    last_audit_by TEXT REFERENCES residents(id),
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    updated_at TEXT NOT NULL DEFAULT (datetime('now')),
+    vault_id INTEGER NOT NULL DEFAULT 0
+);
+
+INSERT INTO resource_stocks SELECT * FROM resource_stocks_027;
+
+DROP TABLE resource_stocks_027;
+
+CREATE INDEX idx_resource_stocks_item ON resource_stocks(item_id);
+CREATE INDEX idx_resource_stocks_status ON resource_stocks(status);
+CREATE INDEX idx_resource_stocks_expiration ON resource_stocks(expiration_date);
+CREATE INDEX idx_resource_stocks_location ON resource_stocks(storage_location);
+CREATE INDEX idx_resource_stocks_available ON resource_stocks(item_id, status, quantity)
+    WHERE status = 'AVAILABLE' AND quantity > 0;
+CREATE INDEX idx_resource_stocks_expiring ON resource_stocks(status, expiration_date)
+    WHERE status = 'AVAILABLE' AND expiration_date IS NOT NULL;
+CREATE INDEX idx_resource_stocks_fifo ON resource_stocks(item_id, received_date)
+    WHERE status = 'AVAILABLE';
+CREATE INDEX idx_resource_stocks_audit ON resource_stocks(last_audit_by)
+    WHERE last_audit_by IS NOT NULL;
+CREATE INDEX idx_resource_stocks_vault ON resource_stocks(vault_id);
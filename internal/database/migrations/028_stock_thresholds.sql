@@ -0,0 +1,35 @@
+-- +migrate Up
+-- Stock Thresholds
+-- An item may set the minimum stock a vault keeps of it and the target to
+-- restore it to. A daily check compares each vault's available stock with
+-- the minimum: a producible item short of it gets a production run queued
+-- for the difference, anything else a procurement alert. A queued run is
+-- completed by the next production recorded of its item, or cancelled once
+-- stock is back above the minimum. At most one run of an item is queued in
+-- a vault at a time.
+
+ALTER TABLE resource_items ADD COLUMN min_stock REAL CHECK (min_stock >= 0);
+ALTER TABLE resource_items ADD COLUMN target_stock REAL CHECK (target_stock >= 0);
+
+CREATE TABLE production_runs (
+    id TEXT PRIMARY KEY,
+    item_id TEXT NOT NULL REFERENCES resource_items(id),
+    quantity REAL NOT NULL CHECK (quantity > 0),
+    status TEXT NOT NULL DEFAULT 'QUEUED' CHECK (status IN ('QUEUED', 'COMPLETED', 'CANCELLED')),
+    reason TEXT NOT NULL,
+    queued_at TEXT NOT NULL,
+    closed_at TEXT,
+    stock_id TEXT REFERENCES resource_stocks(id),
+    vault_id INTEGER NOT NULL DEFAULT 0,
+    created_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX idx_production_runs_vault ON production_runs(vault_id, status, queued_at);
+CREATE UNIQUE INDEX idx_production_runs_queued ON production_runs(vault_id, item_id) WHERE status = 'QUEUED';
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_production_runs_queued;
+DROP INDEX IF EXISTS idx_production_runs_vault;
+DROP TABLE IF EXISTS production_runs;
+ALTER TABLE resource_items DROP COLUMN target_stock;
+ALTER TABLE resource_items DROP COLUMN min_stock;
@@ -0,0 +1,48 @@
+-- +migrate Up
+-- Ration Draws
+-- The daily ration deduction draws each household's rations separately and
+-- names the household in the related entity of every CONSUMPTION
+-- transaction it makes. A draw records what the household's class called
+-- for, what stock covered, and how many of its members work in each
+-- department, by their primary vocation, so consumption can be reported by
+-- household, department and ration class. A deleted household's draws stay
+-- in the vault's totals without it.
+
+CREATE TABLE ration_draws (
+    id TEXT PRIMARY KEY,
+    day TEXT NOT NULL,
+    household_id TEXT REFERENCES households(id),
+    ration_class TEXT NOT NULL CHECK (ration_class IN ('MINIMAL', 'STANDARD', 'ENHANCED', 'MEDICAL', 'LABOR_INTENSIVE')),
+    residents INTEGER NOT NULL CHECK (residents > 0),
+    calories_required REAL NOT NULL CHECK (calories_required >= 0),
+    calories_drawn REAL NOT NULL CHECK (calories_drawn >= 0),
+    water_required_l REAL NOT NULL CHECK (water_required_l >= 0),
+    water_drawn_l REAL NOT NULL CHECK (water_drawn_l >= 0),
+    vault_id INTEGER NOT NULL DEFAULT 0,
+    created_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE TABLE ration_draw_departments (
+    draw_id TEXT NOT NULL REFERENCES ration_draws(id),
+    department TEXT NOT NULL,
+    residents INTEGER NOT NULL CHECK (residents > 0),
+    PRIMARY KEY (draw_id, department)
+);
+
+CREATE INDEX idx_ration_draws_vault ON ration_draws(vault_id, day);
+CREATE INDEX idx_ration_draws_household ON ration_draws(household_id, day);
+CREATE INDEX idx_resource_transactions_related ON resource_transactions(related_entity_type, related_entity_id);
+
+CREATE TRIGGER trg_households_set_null_ration_draws
+BEFORE DELETE ON households
+BEGIN
+    UPDATE ration_draws SET household_id = NULL WHERE household_id = OLD.id;
+END;
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS trg_households_set_null_ration_draws;
+DROP INDEX IF EXISTS idx_resource_transactions_related;
+DROP INDEX IF EXISTS idx_ration_draws_household;
+DROP INDEX IF EXISTS idx_ration_draws_vault;
+DROP TABLE IF EXISTS ration_draw_departments;
+DROP TABLE IF EXISTS ration_draws;
@@ -0,0 +1,47 @@
+-- +migrate Up
+-- Nutrition
+-- A food item may record the protein, carbohydrate and fat in grams and
+-- the share of a resident's daily vitamin allowance a unit of it holds. A
+-- ration policy may set nutrient targets per resident; classes without them
+-- use their built-in targets. The daily ration deduction plans the day's
+-- menu from the food in stock against the day's nutrient targets, and each
+-- ration draw records the nutrients it called for and drew, so deficits
+-- show in the medical analytics.
+
+ALTER TABLE resource_items ADD COLUMN protein_g_per_unit REAL CHECK (protein_g_per_unit >= 0);
+ALTER TABLE resource_items ADD COLUMN carbs_g_per_unit REAL CHECK (carbs_g_per_unit >= 0);
+ALTER TABLE resource_items ADD COLUMN fat_g_per_unit REAL CHECK (fat_g_per_unit >= 0);
+ALTER TABLE resource_items ADD COLUMN vitamins_pct_per_unit REAL CHECK (vitamins_pct_per_unit >= 0);
+
+ALTER TABLE ration_policies ADD COLUMN protein_target_g REAL CHECK (protein_target_g >= 0);
+ALTER TABLE ration_policies ADD COLUMN carbs_target_g REAL CHECK (carbs_target_g >= 0);
+ALTER TABLE ration_policies ADD COLUMN fat_target_g REAL CHECK (fat_target_g >= 0);
+ALTER TABLE ration_policies ADD COLUMN vitamins_target_pct REAL CHECK (vitamins_target_pct >= 0);
+
+-- Draws made before nutrients were tracked call for and draw none
+ALTER TABLE ration_draws ADD COLUMN protein_required_g REAL NOT NULL DEFAULT 0 CHECK (protein_required_g >= 0);
+ALTER TABLE ration_draws ADD COLUMN protein_drawn_g REAL NOT NULL DEFAULT 0 CHECK (protein_drawn_g >= 0);
+ALTER TABLE ration_draws ADD COLUMN carbs_required_g REAL NOT NULL DEFAULT 0 CHECK (carbs_required_g >= 0);
+ALTER TABLE ration_draws ADD COLUMN carbs_drawn_g REAL NOT NULL DEFAULT 0 CHECK (carbs_drawn_g >= 0);
+ALTER TABLE ration_draws ADD COLUMN fat_required_g REAL NOT NULL DEFAULT 0 CHECK (fat_required_g >= 0);
+ALTER TABLE ration_draws ADD COLUMN fat_drawn_g REAL NOT NULL DEFAULT 0 CHECK (fat_drawn_g >= 0);
+ALTER TABLE ration_draws ADD COLUMN vitamins_required_pct REAL NOT NULL DEFAULT 0 CHECK (vitamins_required_pct >= 0);
+ALTER TABLE ration_draws ADD COLUMN vitamins_drawn_pct REAL NOT NULL DEFAULT 0 CHECK (vitamins_drawn_pct >= 0);
+
+-- +migrate Down
+ALTER TABLE ration_draws DROP COLUMN vitamins_drawn_pct;
+ALTER TABLE ration_draws DROP COLUMN vitamins_required_pct;
+ALTER TABLE ration_draws DROP COLUMN fat_drawn_g;
+ALTER TABLE ration_draws DROP COLUMN fat_required_g;
+ALTER TABLE ration_draws DROP COLUMN carbs_drawn_g;
+ALTER TABLE ration_draws DROP COLUMN carbs_required_g;
+ALTER TABLE ration_draws DROP COLUMN protein_drawn_g;
+ALTER TABLE ration_draws DROP COLUMN protein_required_g;
+ALTER TABLE ration_policies DROP COLUMN vitamins_target_pct;
+ALTER TABLE ration_policies DROP COLUMN fat_target_g;
+ALTER TABLE ration_policies DROP COLUMN carbs_target_g;
+ALTER TABLE ration_policies DROP COLUMN protein_target_g;
+ALTER TABLE resource_items DROP COLUMN vitamins_pct_per_unit;
+ALTER TABLE resource_items DROP COLUMN fat_g_per_unit;
+ALTER TABLE resource_items DROP COLUMN carbs_g_per_unit;
+ALTER TABLE resource_items DROP COLUMN protein_g_per_unit;
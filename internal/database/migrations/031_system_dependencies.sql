@@ -0,0 +1,44 @@
+-- +migrate Up
+-- System Dependencies
+-- A facility system may need others running to run at full efficiency,
+-- e.g. water purification needs the reactor that powers it. When a parent
+-- degrades or stops running, every running system that depends on it,
+-- directly or through others, degrades with it. A cascade records the
+-- root cause of such a degradation and what the system was before, so
+-- that it is restored once the root cause is back in operation.
+
+CREATE TABLE system_dependencies (
+    system_id TEXT NOT NULL REFERENCES facility_systems(id),
+    depends_on_id TEXT NOT NULL REFERENCES facility_systems(id),
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    PRIMARY KEY (system_id, depends_on_id),
+    CHECK (system_id <> depends_on_id)
+);
+
+CREATE INDEX idx_system_dependencies_depends_on ON system_dependencies(depends_on_id);
+
+CREATE TABLE system_cascades (
+    system_id TEXT PRIMARY KEY REFERENCES facility_systems(id),
+    root_id TEXT NOT NULL REFERENCES facility_systems(id),
+    status_before TEXT NOT NULL,
+    efficiency_before REAL NOT NULL CHECK (efficiency_before BETWEEN 0 AND 100),
+    degraded_at TEXT NOT NULL
+);
+
+CREATE INDEX idx_system_cascades_root ON system_cascades(root_id);
+
+-- Dependencies and cascades are part of the system declaration and go with
+-- either end.
+CREATE TRIGGER trg_facility_systems_cascade_dependencies
+BEFORE DELETE ON facility_systems
+BEGIN
+    DELETE FROM system_dependencies WHERE system_id = OLD.id OR depends_on_id = OLD.id;
+    DELETE FROM system_cascades WHERE system_id = OLD.id OR root_id = OLD.id;
+END;
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS trg_facility_systems_cascade_dependencies;
+DROP INDEX IF EXISTS idx_system_cascades_root;
+DROP TABLE IF EXISTS system_cascades;
+DROP INDEX IF EXISTS idx_system_dependencies_depends_on;
+DROP TABLE IF EXISTS system_dependencies;
@@ -0,0 +1,40 @@
+-- +migrate Up
+-- Technicians
+-- The residents who maintain facility systems: the shift each works and
+-- their skill in each facility category, rated 1 (apprentice) to 5
+-- (master). Maintenance planning suggests the lead technician of a work
+-- order from the ratings in the system's category, the open work orders
+-- each already leads and whether they are on shift when the work starts.
+
+CREATE TABLE technicians (
+    resident_id TEXT PRIMARY KEY REFERENCES residents(id),
+    shift TEXT NOT NULL CHECK (shift IN ('ALPHA', 'BETA', 'GAMMA')),
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    updated_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE TABLE technician_skills (
+    resident_id TEXT NOT NULL REFERENCES technicians(resident_id),
+    category TEXT NOT NULL CHECK (category IN (
+        'POWER', 'WATER', 'HVAC', 'SECURITY', 'MEDICAL',
+        'FOOD_PRODUCTION', 'WASTE', 'COMMUNICATIONS', 'STRUCTURAL'
+    )),
+    rating INTEGER NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    PRIMARY KEY (resident_id, category)
+);
+
+CREATE INDEX idx_technician_skills_category ON technician_skills(category, rating);
+
+-- A resident's skills go with them.
+CREATE TRIGGER trg_residents_cascade_technicians
+BEFORE DELETE ON residents
+BEGIN
+    DELETE FROM technician_skills WHERE resident_id = OLD.id;
+    DELETE FROM technicians WHERE resident_id = OLD.id;
+END;
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS trg_residents_cascade_technicians;
+DROP INDEX IF EXISTS idx_technician_skills_category;
+DROP TABLE IF EXISTS technician_skills;
+DROP TABLE IF EXISTS technicians;
@@ -0,0 +1,30 @@
+-- +migrate Up
+-- Load Shedding
+-- When the power grid's supply falls below its demand, running systems that
+-- draw power are shed in ascending priority (1 sheds first) until supply
+-- meets demand, and restored in the reverse order once there is supply to
+-- spare. A system without a priority is never shed; life support (HVAC,
+-- water and medical) cannot be given one. A shed system keeps its status
+-- and has current_output set to 0, neither drawing nor supplying; a NULL
+-- current_output runs it at full output.
+
+CREATE TABLE load_shed_priorities (
+    system_id TEXT PRIMARY KEY REFERENCES facility_systems(id),
+    priority INTEGER NOT NULL CHECK (priority >= 1),
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    updated_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX idx_load_shed_priorities_priority ON load_shed_priorities(priority);
+
+-- A system's place in the shedding order goes with it.
+CREATE TRIGGER trg_facility_systems_cascade_load_shed_priorities
+BEFORE DELETE ON facility_systems
+BEGIN
+    DELETE FROM load_shed_priorities WHERE system_id = OLD.id;
+END;
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS trg_facility_systems_cascade_load_shed_priorities;
+DROP INDEX IF EXISTS idx_load_shed_priorities_priority;
+DROP TABLE IF EXISTS load_shed_priorities;
@@ -0,0 +1,26 @@
+-- +migrate Up
+-- Environment Monitoring
+-- Sensor readings of oxygen, carbon dioxide, temperature and radiation in
+-- each sector, sampled hourly over vault time. Each reading is graded
+-- against its metric's safe range when taken: a WARNING or CRITICAL reading
+-- raises an alert, flags the residents housed in the sector with a medical
+-- condition, and for radiation records the dose they received.
+
+CREATE TABLE environment_readings (
+    id TEXT PRIMARY KEY,
+    sector TEXT NOT NULL,
+    metric TEXT NOT NULL CHECK (metric IN ('O2', 'CO2', 'TEMPERATURE', 'RADIATION')),
+    value REAL NOT NULL CHECK (value >= 0),
+    level TEXT NOT NULL CHECK (level IN ('NOMINAL', 'WARNING', 'CRITICAL')),
+    read_at TEXT NOT NULL,
+    vault_id INTEGER NOT NULL DEFAULT 0,
+    created_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX idx_environment_readings_vault ON environment_readings(vault_id, read_at);
+CREATE INDEX idx_environment_readings_sector ON environment_readings(sector, metric, read_at);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_environment_readings_sector;
+DROP INDEX IF EXISTS idx_environment_readings_vault;
+DROP TABLE IF EXISTS environment_readings;
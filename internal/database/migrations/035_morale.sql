@@ -0,0 +1,47 @@
+-- +migrate Up
+-- Morale
+-- Each active resident's morale score, 0-100, with the effect on it of
+-- their household's ration class, crowding in their quarters, security
+-- incidents in their sector and their workload, rescored daily; and daily
+-- snapshots of each vault's happiness index, the mean score. A low index
+-- makes security incidents break out more often.
+
+CREATE TABLE resident_morale (
+    resident_id TEXT PRIMARY KEY REFERENCES residents(id),
+    score INTEGER NOT NULL CHECK (score BETWEEN 0 AND 100),
+    level TEXT NOT NULL CHECK (level IN ('HIGH', 'STEADY', 'LOW', 'CRITICAL')),
+    ration_effect INTEGER NOT NULL,
+    crowding_effect INTEGER NOT NULL CHECK (crowding_effect <= 0),
+    incident_effect INTEGER NOT NULL CHECK (incident_effect <= 0),
+    workload_effect INTEGER NOT NULL CHECK (workload_effect <= 0),
+    scored_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX idx_resident_morale_score ON resident_morale(score);
+
+CREATE TABLE morale_snapshots (
+    id TEXT PRIMARY KEY,
+    vault_id INTEGER NOT NULL DEFAULT 0,
+    taken_at TEXT NOT NULL,
+    population INTEGER NOT NULL CHECK (population >= 0),
+    happiness_index INTEGER NOT NULL CHECK (happiness_index BETWEEN 0 AND 100),
+    low_morale INTEGER NOT NULL CHECK (low_morale BETWEEN 0 AND population),
+    created_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX idx_morale_snapshots_taken ON morale_snapshots(vault_id, taken_at);
+
+-- A resident's score goes with them.
+CREATE TRIGGER trg_residents_cascade_morale
+BEFORE DELETE ON residents
+BEGIN
+    DELETE FROM resident_morale WHERE resident_id = OLD.id;
+END;
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS trg_residents_cascade_morale;
+DROP INDEX IF EXISTS idx_morale_snapshots_taken;
+DROP TABLE IF EXISTS morale_snapshots;
+DROP INDEX IF EXISTS idx_resident_morale_score;
+DROP TABLE IF EXISTS resident_morale;
@@ -0,0 +1,116 @@
+-- +migrate Up
+-- Recreation and education
+-- Venues classes and recreation are held in, the activities scheduled in
+-- them each week (school for minors, skills classes for adults and
+-- recreation for all), the residents enrolled in each and whether they
+-- attended each session. Attending skills classes counts toward an
+-- apprenticeship in the class's vocation, and the hours attended over the
+-- last week lift morale.
+
+CREATE TABLE venues (
+    id TEXT PRIMARY KEY,
+    vault_id INTEGER NOT NULL DEFAULT 0,
+    code TEXT NOT NULL,
+    name TEXT NOT NULL,
+    venue_type TEXT NOT NULL CHECK (venue_type IN ('CLASSROOM', 'WORKSHOP', 'GYMNASIUM', 'LIBRARY', 'THEATER', 'LOUNGE')),
+    sector TEXT NOT NULL,
+    capacity INTEGER NOT NULL CHECK (capacity > 0),
+    is_active INTEGER NOT NULL DEFAULT 1,
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    updated_at TEXT NOT NULL DEFAULT (datetime('now')),
+    UNIQUE (vault_id, code)
+);
+
+CREATE TABLE activities (
+    id TEXT PRIMARY KEY,
+    venue_id TEXT NOT NULL REFERENCES venues(id),
+    code TEXT NOT NULL,
+    name TEXT NOT NULL,
+    activity_type TEXT NOT NULL CHECK (activity_type IN ('SCHOOL', 'SKILLS', 'RECREATION')),
+    vocation_id TEXT REFERENCES vocations(id),
+    instructor_id TEXT REFERENCES residents(id),
+    weekdays INTEGER NOT NULL CHECK (weekdays BETWEEN 1 AND 127),
+    start_hour INTEGER NOT NULL CHECK (start_hour BETWEEN 0 AND 23),
+    duration_hours REAL NOT NULL CHECK (duration_hours > 0 AND duration_hours <= 12),
+    min_age INTEGER CHECK (min_age >= 0),
+    max_age INTEGER CHECK (max_age >= min_age),
+    is_active INTEGER NOT NULL DEFAULT 1,
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    updated_at TEXT NOT NULL DEFAULT (datetime('now')),
+    CHECK (vocation_id IS NULL OR activity_type = 'SKILLS')
+);
+
+CREATE INDEX idx_activities_venue ON activities(venue_id);
+
+CREATE TABLE activity_enrollments (
+    activity_id TEXT NOT NULL REFERENCES activities(id),
+    resident_id TEXT NOT NULL REFERENCES residents(id),
+    enrolled_at TEXT NOT NULL,
+    PRIMARY KEY (activity_id, resident_id)
+);
+
+CREATE INDEX idx_activity_enrollments_resident ON activity_enrollments(resident_id);
+
+CREATE TABLE activity_attendance (
+    id TEXT PRIMARY KEY,
+    activity_id TEXT NOT NULL REFERENCES activities(id),
+    resident_id TEXT NOT NULL REFERENCES residents(id),
+    session_at TEXT NOT NULL,
+    present INTEGER NOT NULL,
+    hours REAL NOT NULL CHECK (hours >= 0),
+    recorded_at TEXT NOT NULL DEFAULT (datetime('now')),
+    UNIQUE (activity_id, resident_id, session_at)
+);
+
+CREATE INDEX idx_activity_attendance_resident ON activity_attendance(resident_id, session_at);
+CREATE INDEX idx_activity_attendance_session ON activity_attendance(activity_id, session_at);
+
+ALTER TABLE resident_morale ADD COLUMN activity_effect INTEGER NOT NULL DEFAULT 0 CHECK (activity_effect >= 0);
+
+-- A resident's enrollments and attendance go with them; the classes they
+-- taught are left unstaffed.
+CREATE TRIGGER trg_residents_cascade_activities
+BEFORE DELETE ON residents
+BEGIN
+    DELETE FROM activity_enrollments WHERE resident_id = OLD.id;
+    DELETE FROM activity_attendance WHERE resident_id = OLD.id;
+    UPDATE activities SET instructor_id = NULL WHERE instructor_id = OLD.id;
+END;
+
+-- An activity's enrollments and attendance go with it, and a venue's
+-- activities with the venue.
+CREATE TRIGGER trg_activities_cascade_attendance
+BEFORE DELETE ON activities
+BEGIN
+    DELETE FROM activity_enrollments WHERE activity_id = OLD.id;
+    DELETE FROM activity_attendance WHERE activity_id = OLD.id;
+END;
+
+CREATE TRIGGER trg_venues_cascade_activities
+BEFORE DELETE ON venues
+BEGIN
+    DELETE FROM activities WHERE venue_id = OLD.id;
+END;
+
+-- A vocation taught in skills classes cannot be deleted.
+CREATE TRIGGER trg_vocations_restrict_activities
+BEFORE DELETE ON vocations
+WHEN EXISTS (SELECT 1 FROM activities WHERE vocation_id = OLD.id)
+BEGIN
+    SELECT RAISE(ABORT, 'restrict: vocation has skills classes');
+END;
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS trg_vocations_restrict_activities;
+DROP TRIGGER IF EXISTS trg_venues_cascade_activities;
+DROP TRIGGER IF EXISTS trg_activities_cascade_attendance;
+DROP TRIGGER IF EXISTS trg_residents_cascade_activities;
+ALTER TABLE resident_morale DROP COLUMN activity_effect;
+DROP INDEX IF EXISTS idx_activity_attendance_session;
+DROP INDEX IF EXISTS idx_activity_attendance_resident;
+DROP TABLE IF EXISTS activity_attendance;
+DROP INDEX IF EXISTS idx_activity_enrollments_resident;
+DROP TABLE IF EXISTS activity_enrollments;
+DROP INDEX IF EXISTS idx_activities_venue;
+DROP TABLE IF EXISTS activities;
+DROP TABLE IF EXISTS venues;
@@ -0,0 +1,37 @@
+-- +migrate Up
+-- Terminal Sessions
+-- Each stretch of time one operator, or no one, used a terminal: when it
+-- started, when it was last saved and ended, the modules opened and how
+-- many changes were made, for reviewing who did what after an incident. A
+-- terminal starts a session on opening and on every change of operator.
+-- A session left without an end was never closed, e.g. on a crash.
+
+CREATE TABLE terminal_sessions (
+    id TEXT PRIMARY KEY,
+    operator_id TEXT REFERENCES residents(id),
+    started_at TEXT NOT NULL,
+    last_active_at TEXT NOT NULL,
+    ended_at TEXT,
+    modules TEXT NOT NULL DEFAULT '',
+    mutations INTEGER NOT NULL DEFAULT 0 CHECK (mutations >= 0),
+    vault_id INTEGER NOT NULL DEFAULT 0,
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    CHECK (last_active_at >= started_at),
+    CHECK (ended_at IS NULL OR ended_at >= started_at)
+);
+
+CREATE INDEX idx_terminal_sessions_vault ON terminal_sessions(vault_id, last_active_at);
+CREATE INDEX idx_terminal_sessions_operator ON terminal_sessions(operator_id);
+
+-- Sessions outlive the operator's record, which is cleared from them.
+CREATE TRIGGER trg_residents_cascade_terminal_sessions
+BEFORE DELETE ON residents
+BEGIN
+    UPDATE terminal_sessions SET operator_id = NULL WHERE operator_id = OLD.id;
+END;
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS trg_residents_cascade_terminal_sessions;
+DROP INDEX IF EXISTS idx_terminal_sessions_operator;
+DROP INDEX IF EXISTS idx_terminal_sessions_vault;
+DROP TABLE IF EXISTS terminal_sessions;
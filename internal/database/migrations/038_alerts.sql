@@ -0,0 +1,90 @@
+-- +migrate Up
+-- Alert Routing
+-- The warnings and critical alerts the simulation raises, kept until
+-- someone deals with them. Routing rules send the alerts of a source, such
+-- as "shortage check", from a severity up to a department or one operator;
+-- the most specific rule matching an alert routes it, and an alert no rule
+-- matches goes to ADMINISTRATION. An alert is acknowledged, then resolved,
+-- each with who, when and notes; resolving an open alert acknowledges it
+-- too. One left unacknowledged longer than its rule allows, in vault time,
+-- is escalated once to the rule's escalation department, ADMINISTRATION by
+-- default.
+
+CREATE TABLE alert_routes (
+    id TEXT PRIMARY KEY,
+    source TEXT NOT NULL DEFAULT '*',
+    min_severity TEXT NOT NULL DEFAULT 'WARNING' CHECK (min_severity IN ('WARNING', 'CRITICAL')),
+    department TEXT CHECK (department IN ('ENGINEERING', 'MEDICAL', 'SECURITY', 'FOOD_PRODUCTION',
+        'ADMINISTRATION', 'EDUCATION', 'SANITATION', 'RESEARCH')),
+    operator_id TEXT REFERENCES residents(id),
+    escalate_to TEXT CHECK (escalate_to IN ('ENGINEERING', 'MEDICAL', 'SECURITY', 'FOOD_PRODUCTION',
+        'ADMINISTRATION', 'EDUCATION', 'SANITATION', 'RESEARCH')),
+    escalate_after_hours INTEGER CHECK (escalate_after_hours > 0),
+    vault_id INTEGER NOT NULL DEFAULT 0,
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    CHECK ((department IS NULL) != (operator_id IS NULL))
+);
+
+CREATE INDEX idx_alert_routes_vault ON alert_routes(vault_id);
+CREATE INDEX idx_alert_routes_operator ON alert_routes(operator_id);
+
+CREATE TABLE alerts (
+    id TEXT PRIMARY KEY,
+    source TEXT NOT NULL,
+    severity TEXT NOT NULL CHECK (severity IN ('WARNING', 'CRITICAL')),
+    message TEXT NOT NULL,
+    raised_at TEXT NOT NULL,
+    route_id TEXT REFERENCES alert_routes(id),
+    department TEXT CHECK (department IN ('ENGINEERING', 'MEDICAL', 'SECURITY', 'FOOD_PRODUCTION',
+        'ADMINISTRATION', 'EDUCATION', 'SANITATION', 'RESEARCH')),
+    operator_id TEXT REFERENCES residents(id),
+    status TEXT NOT NULL DEFAULT 'OPEN' CHECK (status IN ('OPEN', 'ACKNOWLEDGED', 'RESOLVED')),
+    acknowledged_at TEXT,
+    acknowledged_by TEXT REFERENCES residents(id),
+    acknowledgment_notes TEXT,
+    resolved_at TEXT,
+    resolved_by TEXT REFERENCES residents(id),
+    resolution_notes TEXT,
+    escalated_at TEXT,
+    vault_id INTEGER NOT NULL DEFAULT 0,
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    CHECK ((department IS NULL) != (operator_id IS NULL)),
+    CHECK ((status = 'OPEN') = (acknowledged_at IS NULL)),
+    CHECK ((status = 'RESOLVED') = (resolved_at IS NOT NULL)),
+    CHECK (acknowledged_at IS NULL OR acknowledged_at >= raised_at),
+    CHECK (resolved_at IS NULL OR resolved_at >= acknowledged_at),
+    CHECK (escalated_at IS NULL OR escalated_at >= raised_at)
+);
+
+CREATE INDEX idx_alerts_vault ON alerts(vault_id, status, raised_at);
+CREATE INDEX idx_alerts_route ON alerts(route_id);
+CREATE INDEX idx_alerts_operator ON alerts(operator_id);
+
+-- Alerts outlive the residents named on them, who are cleared from them;
+-- an alert routed to a deleted operator goes back to ADMINISTRATION, and
+-- their routing rules go with them. Alerts outlive their rules.
+CREATE TRIGGER trg_residents_cascade_alerts
+BEFORE DELETE ON residents
+BEGIN
+    UPDATE alerts SET department = 'ADMINISTRATION', operator_id = NULL WHERE operator_id = OLD.id;
+    UPDATE alerts SET acknowledged_by = NULL WHERE acknowledged_by = OLD.id;
+    UPDATE alerts SET resolved_by = NULL WHERE resolved_by = OLD.id;
+    DELETE FROM alert_routes WHERE operator_id = OLD.id;
+END;
+
+CREATE TRIGGER trg_alert_routes_cascade_alerts
+BEFORE DELETE ON alert_routes
+BEGIN
+    UPDATE alerts SET route_id = NULL WHERE route_id = OLD.id;
+END;
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS trg_alert_routes_cascade_alerts;
+DROP TRIGGER IF EXISTS trg_residents_cascade_alerts;
+DROP INDEX IF EXISTS idx_alerts_operator;
+DROP INDEX IF EXISTS idx_alerts_route;
+DROP INDEX IF EXISTS idx_alerts_vault;
+DROP TABLE IF EXISTS alerts;
+DROP INDEX IF EXISTS idx_alert_routes_operator;
+DROP INDEX IF EXISTS idx_alert_routes_vault;
+DROP TABLE IF EXISTS alert_routes;
@@ -0,0 +1,39 @@
+-- +migrate Up
+-- Resident Tags
+-- Free-form labels on residents, such as "council" or "radiation-exposed",
+-- matched regardless of case; the census filters on them. Census presets
+-- save a filter and sort of the census under a name, unique in the vault,
+-- the filter kept as JSON.
+
+CREATE TABLE resident_tags (
+    resident_id TEXT NOT NULL REFERENCES residents(id),
+    tag TEXT NOT NULL COLLATE NOCASE CHECK (length(tag) BETWEEN 1 AND 40),
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    PRIMARY KEY (resident_id, tag)
+);
+
+CREATE INDEX idx_resident_tags_tag ON resident_tags(tag);
+
+-- A resident's tags go with them
+CREATE TRIGGER trg_residents_cascade_tags
+BEFORE DELETE ON residents
+BEGIN
+    DELETE FROM resident_tags WHERE resident_id = OLD.id;
+END;
+
+CREATE TABLE census_presets (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL COLLATE NOCASE CHECK (length(name) BETWEEN 1 AND 40),
+    filter TEXT NOT NULL DEFAULT '{}',
+    sort_column TEXT,
+    sort_direction TEXT CHECK (sort_direction IN ('ASC', 'DESC')),
+    vault_id INTEGER NOT NULL DEFAULT 0,
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    UNIQUE (vault_id, name)
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS census_presets;
+DROP TRIGGER IF EXISTS trg_residents_cascade_tags;
+DROP INDEX IF EXISTS idx_resident_tags_tag;
+DROP TABLE IF EXISTS resident_tags;
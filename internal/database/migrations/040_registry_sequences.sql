@@ -0,0 +1,17 @@
+-- +migrate Up
+-- Registry Sequences
+-- The last registry sequence number issued by each vault. Numbers are
+-- reserved by moving the sequence forward in the transaction creating the
+-- resident, so concurrent creations cannot read the same maximum, and a
+-- number is never issued again, even after its resident is deleted. A
+-- vault's row is taken on first use from the highest sequence among its
+-- registry numbers in the configured format.
+
+CREATE TABLE registry_sequences (
+    vault_id INTEGER PRIMARY KEY,
+    last_seq INTEGER NOT NULL CHECK (last_seq >= 0),
+    updated_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS registry_sequences;
@@ -0,0 +1,19 @@
+-- +migrate Up
+-- Encryption Keys
+-- The data keys sensitive columns are encrypted with when encryption at
+-- rest is enabled, each wrapped (encrypted) by the master key held outside
+-- the database. New values are encrypted with the primary key; the others
+-- remain until no value they encrypted is left.
+
+CREATE TABLE encryption_keys (
+    id TEXT PRIMARY KEY,
+    wrapped_key TEXT NOT NULL,
+    is_primary INTEGER NOT NULL DEFAULT 0 CHECK (is_primary IN (0, 1)),
+    created_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE UNIQUE INDEX idx_encryption_keys_primary ON encryption_keys(is_primary) WHERE is_primary = 1;
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_encryption_keys_primary;
+DROP TABLE IF EXISTS encryption_keys;
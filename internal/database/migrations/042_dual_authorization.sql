@@ -0,0 +1,38 @@
+-- +migrate Up
+-- Two-Person Authorization
+-- Operations a policy names, such as exile or a large stock write-off, need
+-- a second operator to confirm them with their authorization PIN. A policy
+-- sets the clearance both operators must hold and, for stock write-offs,
+-- the quantity from which it applies; an operation without a policy needs
+-- one operator as before. Each confirmation is recorded in the audit log.
+-- PINs are stored salted and hashed, never as entered.
+
+CREATE TABLE dual_auth_policies (
+    operation TEXT NOT NULL CHECK (operation IN ('EXILE', 'DISSOLVE_HOUSEHOLD', 'STOCK_WRITE_OFF')),
+    min_clearance INTEGER NOT NULL DEFAULT 1 CHECK (min_clearance BETWEEN 1 AND 10),
+    threshold REAL NOT NULL DEFAULT 0 CHECK (threshold >= 0),
+    vault_id INTEGER NOT NULL DEFAULT 0,
+    updated_at TEXT NOT NULL,
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    PRIMARY KEY (vault_id, operation),
+    CHECK (threshold = 0 OR operation = 'STOCK_WRITE_OFF')
+);
+
+CREATE TABLE operator_pins (
+    resident_id TEXT PRIMARY KEY REFERENCES residents(id),
+    pin_hash TEXT NOT NULL,
+    salt TEXT NOT NULL,
+    set_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+-- A PIN goes with its operator's record.
+CREATE TRIGGER trg_residents_cascade_operator_pins
+BEFORE DELETE ON residents
+BEGIN
+    DELETE FROM operator_pins WHERE resident_id = OLD.id;
+END;
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS trg_residents_cascade_operator_pins;
+DROP TABLE IF EXISTS operator_pins;
+DROP TABLE IF EXISTS dual_auth_policies;
@@ -0,0 +1,16 @@
+-- +migrate Up
+-- Facility Runtime
+-- A system runs for its duty cycle's share of the vault time it is online
+-- and not shed, and that time accrues to its total runtime. A system may be
+-- maintained every so many runtime hours instead of every so many days: its
+-- next maintenance is then the day the interval is reached, projected at its
+-- duty cycle from the runtime at its last maintenance.
+
+ALTER TABLE facility_systems ADD COLUMN duty_cycle_percent REAL NOT NULL DEFAULT 100 CHECK (duty_cycle_percent > 0 AND duty_cycle_percent <= 100);
+ALTER TABLE facility_systems ADD COLUMN maintenance_interval_hours REAL CHECK (maintenance_interval_hours > 0);
+ALTER TABLE facility_systems ADD COLUMN runtime_at_last_maintenance REAL NOT NULL DEFAULT 0;
+
+-- +migrate Down
+ALTER TABLE facility_systems DROP COLUMN runtime_at_last_maintenance;
+ALTER TABLE facility_systems DROP COLUMN maintenance_interval_hours;
+ALTER TABLE facility_systems DROP COLUMN duty_cycle_percent;
@@ -0,0 +1,11 @@
+-- +migrate Up
+-- Maintenance Analytics
+-- A work order records the vault time it was raised, so that repair time
+-- runs from the failure or finding to completion. Closing an order records
+-- the labor hours it took and the system's efficiency before and after the
+-- work, in the columns kept for them since the initial schema.
+
+ALTER TABLE maintenance_records ADD COLUMN raised_at TEXT;
+
+-- +migrate Down
+ALTER TABLE maintenance_records DROP COLUMN raised_at;
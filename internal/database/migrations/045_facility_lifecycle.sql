@@ -0,0 +1,195 @@
+-- +migrate Up
+-- Facility Lifecycle
+-- Systems are commissioned into service and decommissioned out of it. A
+-- decommissioned system is RETIRED: it stays on record, with its work
+-- orders and audit history, but no longer runs, falls due for maintenance
+-- or counts in status roll-ups. It records when and why it was retired.
+-- SQLite cannot alter a CHECK constraint, so facility_systems is rebuilt
+-- with RETIRED added, as resource_stocks was for DAMAGED; the indexes and
+-- the delete triggers go with the old table and are created again.
+
+PRAGMA defer_foreign_keys = ON;
+
+CREATE TEMP TABLE facility_systems_045 AS SELECT * FROM facility_systems;
+
+DROP TABLE facility_systems;
+
+CREATE TABLE facility_systems (
+    id TEXT PRIMARY KEY,
+    system_code TEXT UNIQUE NOT NULL,
+    name TEXT NOT NULL,
+    category TEXT NOT NULL CHECK (category IN ('POWER', 'WATER', 'HVAC', 'SECURITY', 'MEDICAL', 'FOOD_PRODUCTION', 'WASTE', 'COMMUNICATIONS', 'STRUCTURAL')),
+    location_sector TEXT NOT NULL,
+    location_level INTEGER NOT NULL,
+    status TEXT NOT NULL DEFAULT 'OPERATIONAL' CHECK (status IN ('OPERATIONAL', 'DEGRADED', 'MAINTENANCE', 'OFFLINE', 'FAILED', 'DESTROYED', 'RETIRED')),
+    efficiency_percent REAL NOT NULL DEFAULT 100.0 CHECK (efficiency_percent BETWEEN 0 AND 100),
+    capacity_rating REAL,
+    capacity_unit TEXT,
+    current_output REAL,
+    install_date TEXT NOT NULL,
+    last_maintenance_date TEXT,
+    next_maintenance_due TEXT,
+    maintenance_interval_days INTEGER NOT NULL DEFAULT 90,
+    mtbf_hours INTEGER,
+    total_runtime_hours REAL NOT NULL DEFAULT 0,
+    telemetry_json TEXT,
+    telemetry_updated_at TEXT,
+    notes TEXT,
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    updated_at TEXT NOT NULL DEFAULT (datetime('now')),
+    vault_id INTEGER NOT NULL DEFAULT 0,
+    duty_cycle_percent REAL NOT NULL DEFAULT 100 CHECK (duty_cycle_percent > 0 AND duty_cycle_percent <= 100),
+    maintenance_interval_hours REAL CHECK (maintenance_interval_hours > 0),
+    runtime_at_last_maintenance REAL NOT NULL DEFAULT 0,
+    decommissioned_at TEXT,
+    decommission_reason TEXT
+);
+
+INSERT INTO facility_systems (
+    id, system_code, name, category, location_sector, location_level,
+    status, efficiency_percent, capacity_rating, capacity_unit, current_output,
+    install_date, last_maintenance_date, next_maintenance_due, maintenance_interval_days,
+    mtbf_hours, total_runtime_hours, telemetry_json, telemetry_updated_at, notes,
+    created_at, updated_at, vault_id, duty_cycle_percent, maintenance_interval_hours,
+    runtime_at_last_maintenance
+)
+SELECT
+    id, system_code, name, category, location_sector, location_level,
+    status, efficiency_percent, capacity_rating, capacity_unit, current_output,
+    install_date, last_maintenance_date, next_maintenance_due, maintenance_interval_days,
+    mtbf_hours, total_runtime_hours, telemetry_json, telemetry_updated_at, notes,
+    created_at, updated_at, vault_id, duty_cycle_percent, maintenance_interval_hours,
+    runtime_at_last_maintenance
+FROM facility_systems_045;
+
+DROP TABLE facility_systems_045;
+
+CREATE INDEX idx_facility_systems_category ON facility_systems(category);
+CREATE INDEX idx_facility_systems_status ON facility_systems(status);
+CREATE INDEX idx_facility_critical ON facility_systems(category, status, efficiency_percent)
+    WHERE category IN ('POWER', 'WATER', 'HVAC', 'WASTE', 'SECURITY');
+CREATE INDEX idx_facility_maintenance_due ON facility_systems(next_maintenance_due, status)
+    WHERE status IN ('OPERATIONAL', 'DEGRADED');
+CREATE INDEX idx_facility_location ON facility_systems(location_sector, location_level);
+CREATE INDEX idx_facility_degraded ON facility_systems(status, efficiency_percent)
+    WHERE status = 'DEGRADED' OR efficiency_percent < 80;
+CREATE INDEX idx_facility_systems_vault ON facility_systems(vault_id);
+
+CREATE TRIGGER trg_facility_systems_cascade_flows
+BEFORE DELETE ON facility_systems
+BEGIN
+    DELETE FROM facility_grid_flows WHERE system_id = OLD.id;
+END;
+
+CREATE TRIGGER trg_facility_systems_cascade_consumables
+BEFORE DELETE ON facility_systems
+BEGIN
+    DELETE FROM system_consumables WHERE system_id = OLD.id;
+END;
+
+CREATE TRIGGER trg_facility_systems_cascade_dependencies
+BEFORE DELETE ON facility_systems
+BEGIN
+    DELETE FROM system_dependencies WHERE system_id = OLD.id OR depends_on_id = OLD.id;
+    DELETE FROM system_cascades WHERE system_id = OLD.id OR root_id = OLD.id;
+END;
+
+CREATE TRIGGER trg_facility_systems_cascade_load_shed_priorities
+BEFORE DELETE ON facility_systems
+BEGIN
+    DELETE FROM load_shed_priorities WHERE system_id = OLD.id;
+END;
+
+-- +migrate Down
+-- Retired systems go back to OFFLINE, the nearest status the old schema has.
+PRAGMA defer_foreign_keys = ON;
+
+CREATE TEMP TABLE facility_systems_045 AS SELECT * FROM facility_systems;
+
+UPDATE facility_systems_045 SET status = 'OFFLINE' WHERE status = 'RETIRED';
+
+DROP TABLE facility_systems;
+
+CREATE TABLE facility_systems (
+    id TEXT PRIMARY KEY,
+    system_code TEXT UNIQUE NOT NULL,
+    name TEXT NOT NULL,
+    category TEXT NOT NULL CHECK (category IN ('POWER', 'WATER', 'HVAC', 'SECURITY', 'MEDICAL', 'FOOD_PRODUCTION', 'WASTE', 'COMMUNICATIONS', 'STRUCTURAL')),
+    location_sector TEXT NOT NULL,
+    location_level INTEGER NOT NULL,
+    status TEXT NOT NULL DEFAULT 'OPERATIONAL' CHECK (status IN ('OPERATIONAL', 'DEGRADED', 'MAINTENANCE', 'OFFLINE', 'FAILED', 'DESTROYED')),
+    efficiency_percent REAL NOT NULL DEFAULT 100.0 CHECK (efficiency_percent BETWEEN 0 AND 100),
+    capacity_rating REAL,
+    capacity_unit TEXT,
+    current_output REAL,
+    install_date TEXT NOT NULL,
+    last_maintenance_date TEXT,
+    next_maintenance_due TEXT,
+    maintenance_interval_days INTEGER NOT NULL DEFAULT 90,
+    mtbf_hours INTEGER,
+    total_runtime_hours REAL NOT NULL DEFAULT 0,
+    telemetry_json TEXT,
+    telemetry_updated_at TEXT,
+    notes TEXT,
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    updated_at TEXT NOT NULL DEFAULT (datetime('now')),
+    vault_id INTEGER NOT NULL DEFAULT 0,
+    duty_cycle_percent REAL NOT NULL DEFAULT 100 CHECK (duty_cycle_percent > 0 AND duty_cycle_percent <= 100),
+    maintenance_interval_hours REAL CHECK (maintenance_interval_hours > 0),
+    runtime_at_last_maintenance REAL NOT NULL DEFAULT 0
+);
+
+INSERT INTO facility_systems (
+    id, system_code, name, category, location_sector, location_level,
+    status, efficiency_percent, capacity_rating, capacity_unit, current_output,
+    install_date, last_maintenance_date, next_maintenance_due, maintenance_interval_days,
+    mtbf_hours, total_runtime_hours, telemetry_json, telemetry_updated_at, notes,
+    created_at, updated_at, vault_id, duty_cycle_percent, maintenance_interval_hours,
+    runtime_at_last_maintenance
+)
+SELECT
+    id, system_code, name, category, location_sector, location_level,
+    status, efficiency_percent, capacity_rating, capacity_unit, current_output,
+    install_date, last_maintenance_date, next_maintenance_due, maintenance_interval_days,
+    mtbf_hours, total_runtime_hours, telemetry_json, telemetry_updated_at, notes,
+    created_at, updated_at, vault_id, duty_cycle_percent, maintenance_interval_hours,
+    runtime_at_last_maintenance
+FROM facility_systems_045;
+
+DROP TABLE facility_systems_045;
+
+CREATE INDEX idx_facility_systems_category ON facility_systems(category);
+CREATE INDEX idx_facility_systems_status ON facility_systems(status);
+CREATE INDEX idx_facility_critical ON facility_systems(category, status, efficiency_percent)
+    WHERE category IN ('POWER', 'WATER', 'HVAC', 'WASTE', 'SECURITY');
+CREATE INDEX idx_facility_maintenance_due ON facility_systems(next_maintenance_due, status)
+    WHERE status IN ('OPERATIONAL', 'DEGRADED');
+CREATE INDEX idx_facility_location ON facility_systems(location_sector, location_level);
+CREATE INDEX idx_facility_degraded ON facility_systems(status, efficiency_percent)
+    WHERE status = 'DEGRADED' OR efficiency_percent < 80;
+CREATE INDEX idx_facility_systems_vault ON facility_systems(vault_id);
+
+CREATE TRIGGER trg_facility_systems_cascade_flows
+BEFORE DELETE ON facility_systems
+BEGIN
+    DELETE FROM facility_grid_flows WHERE system_id = OLD.id;
+END;
+
+CREATE TRIGGER trg_facility_systems_cascade_consumables
+BEFORE DELETE ON facility_systems
+BEGIN
+    DELETE FROM system_consumables WHERE system_id = OLD.id;
+END;
+
+CREATE TRIGGER trg_facility_systems_cascade_dependencies
+BEFORE DELETE ON facility_systems
+BEGIN
+    DELETE FROM system_dependencies WHERE system_id = OLD.id OR depends_on_id = OLD.id;
+    DELETE FROM system_cascades WHERE system_id = OLD.id OR root_id = OLD.id;
+END;
+
+CREATE TRIGGER trg_facility_systems_cascade_load_shed_priorities
+BEFORE DELETE ON facility_systems
+BEGIN
+    DELETE FROM load_shed_priorities WHERE system_id = OLD.id;
+END;
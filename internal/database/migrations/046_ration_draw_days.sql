@@ -0,0 +1,28 @@
+-- +migrate Up
+-- Ration Draw Days
+-- A household draws its rations once a day. The scheduler keeps no record
+-- of its last run, and starts again from the configured start date at every
+-- launch, so the daily deduction could draw a day already drawn. The
+-- deduction now skips a day the vault has drawn, and a unique index backs
+-- it. Draws repeated before the migration are dropped, keeping the first;
+-- the stock they drew stays consumed, in the transaction log.
+
+DELETE FROM ration_draw_departments WHERE draw_id IN (
+    SELECT d.id FROM ration_draws d
+    WHERE EXISTS (
+        SELECT 1 FROM ration_draws e
+        WHERE e.vault_id = d.vault_id AND e.day = d.day
+            AND e.household_id = d.household_id AND e.rowid < d.rowid
+    )
+);
+
+DELETE FROM ration_draws WHERE EXISTS (
+    SELECT 1 FROM ration_draws e
+    WHERE e.vault_id = ration_draws.vault_id AND e.day = ration_draws.day
+        AND e.household_id = ration_draws.household_id AND e.rowid < ration_draws.rowid
+);
+
+CREATE UNIQUE INDEX idx_ration_draws_day_household ON ration_draws(vault_id, day, household_id);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_ration_draws_day_household;
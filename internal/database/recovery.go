@@ -318,6 +318,44 @@ func restoreFromBackup(dbPath string, backupDir string) (string, error) {
 	return "", errors.New("no valid backup found")
 }
 
+// RestoreBackup replaces the database at dbPath with the given backup file.
+// The current database is preserved alongside it with a .pre-restore suffix.
+// The database must be closed before calling this.
+func RestoreBackup(dbPath, backupPath string) (string, error) {
+	result, err := checkDatabaseIntegrity(backupPath)
+	if err != nil {
+		return "", fmt.Errorf("checking backup integrity: %w", err)
+	}
+	if result != "ok" {
+		return "", fmt.Errorf("backup failed integrity check: %s", result)
+	}
+
+	// Flush any WAL content into the database before moving it aside
+	if _, err := attemptWALRecovery(dbPath); err != nil {
+		slog.Warn("checkpoint before restore failed", "path", dbPath, "error", err)
+	}
+
+	preservedPath := dbPath + ".pre-restore." + time.Now().Format("20060102-150405")
+	if err := moveFile(dbPath, preservedPath); err != nil {
+		return "", fmt.Errorf("preserving current database: %w", err)
+	}
+
+	os.Remove(dbPath + "-wal")
+	os.Remove(dbPath + "-shm")
+
+	if err := copyFile(backupPath, dbPath); err != nil {
+		return "", fmt.Errorf("copying backup: %w", err)
+	}
+
+	slog.Info("database restored from backup",
+		"path", dbPath,
+		"backup", backupPath,
+		"preserved", preservedPath,
+	)
+
+	return preservedPath, nil
+}
+
 // moveFile moves a file from src to dst.
 func moveFile(src, dst string) error {
 	// Try rename first (fastest, same filesystem)
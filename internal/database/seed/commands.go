@@ -0,0 +1,25 @@
+package seed
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/vtuos/vtuos/internal/journal"
+)
+
+// CommandGenerate is the journaled command that generates seed data.
+const CommandGenerate = "seed.generate"
+
+// SetJournal records generation in j.
+func (g *Generator) SetJournal(j *journal.Journal) {
+	g.journal = j
+}
+
+// Commands returns the handler that replays seed generation into db.
+func Commands(db *sql.DB) journal.Handlers {
+	return journal.Handlers{
+		CommandGenerate: journal.Handle(func(ctx context.Context, cfg Config) error {
+			return NewGenerator(db, cfg).Generate(ctx)
+		}),
+	}
+}
@@ -5,9 +5,11 @@ import (
 	"database/sql"
 	"fmt"
 	"log/slog"
+	"math"
 	"math/rand"
 	"time"
 
+	"github.com/vtuos/vtuos/internal/journal"
 	"github.com/vtuos/vtuos/internal/models"
 	"github.com/vtuos/vtuos/internal/util"
 )
@@ -20,18 +22,20 @@ type Config struct {
 	FamilyHouseholds int
 	SingleHouseholds int
 	RandomSeed       int64
+
+	EmploymentRate float64 // Share of working-age adults given a vocation beyond minimum staffing
+	ConditionRate  float64 // Share of residents entering with a chronic condition
+	HistoryDays    int     // Days of operation generated after SealDate
+	CheckupDays    int     // Interval of routine examinations during the history, 0 for none
+	FailureFactor  float64 // Multiplier of the rated failure rate of facility systems
+	RegistryFormat string  // Registry number template, util.DefaultRegistryFormat when empty
 }
 
-// DefaultConfig returns a default seed configuration.
+// DefaultConfig returns a default seed configuration: the standard-500
+// profile with its history ending on 23 October 2077.
 func DefaultConfig(vaultNumber int) Config {
-	return Config{
-		VaultNumber:      vaultNumber,
-		SealDate:         time.Date(2077, 10, 23, 9, 47, 0, 0, time.UTC),
-		TargetPopulation: 500,
-		FamilyHouseholds: 100,
-		SingleHouseholds: 80,
-		RandomSeed:       2077,
-	}
+	profile, _ := LookupProfile(DefaultProfile)
+	return profile.Config(vaultNumber, time.Date(2077, 10, 23, 9, 47, 0, 0, time.UTC))
 }
 
 // Generator generates seed data for a vault.
@@ -41,11 +45,13 @@ type Generator struct {
 	rng       *rand.Rand
 	idGen     *util.IDGenerator
 	regNumGen *util.RegistryNumberGenerator
+	journal   *journal.Journal
 
 	// Tracking
 	residentCount int
 	residents     []*models.Resident
 	households    []*models.Household
+	vocations     []seedVocation
 }
 
 // NewGenerator creates a new seed data generator.
@@ -60,12 +66,23 @@ func NewGenerator(db *sql.DB, cfg Config) *Generator {
 }
 
 // Generate creates all seed data.
-func (g *Generator) Generate(ctx context.Context) error {
+func (g *Generator) Generate(ctx context.Context) (err error) {
+	ctx, cmd := g.journal.Begin(ctx, g.cfg.VaultNumber, CommandGenerate, g.cfg)
+	defer func() { cmd.End(err) }()
+
 	slog.Info("starting seed data generation",
 		"vault", g.cfg.VaultNumber,
 		"target_population", g.cfg.TargetPopulation,
 	)
 
+	if g.cfg.RegistryFormat != "" {
+		format, err := util.ParseRegistryFormat(g.cfg.RegistryFormat)
+		if err != nil {
+			return err
+		}
+		g.regNumGen.SetFormat(format)
+	}
+
 	// Start transaction
 	tx, err := g.db.BeginTx(ctx, nil)
 	if err != nil {
@@ -73,6 +90,12 @@ func (g *Generator) Generate(ctx context.Context) error {
 	}
 	defer tx.Rollback()
 
+	// Continue the vault's registry sequence, so that the numbers of
+	// residents deleted before seeding are not issued again
+	if err := g.claimRegistrySequence(ctx, tx); err != nil {
+		return err
+	}
+
 	// Generate quarters first
 	if err := g.generateQuarters(ctx, tx); err != nil {
 		return fmt.Errorf("generating quarters: %w", err)
@@ -100,11 +123,49 @@ func (g *Generator) Generate(ctx context.Context) error {
 		}
 	}
 
+	// House each household in quarters
+	if err := g.assignQuarters(ctx, tx); err != nil {
+		return fmt.Errorf("assigning quarters: %w", err)
+	}
+
+	// Staff the vocations and record intake examinations
+	if err := g.assignVocations(ctx, tx); err != nil {
+		return fmt.Errorf("assigning vocations: %w", err)
+	}
+	if err := g.generateMedicalRecords(ctx, tx); err != nil {
+		return fmt.Errorf("generating medical records: %w", err)
+	}
+
 	// Generate resources
 	if err := g.generateResources(ctx, tx); err != nil {
 		return fmt.Errorf("generating resources: %w", err)
 	}
 
+	// Generate facility systems and their grid flows
+	if err := g.generateFacilities(ctx, tx); err != nil {
+		return fmt.Errorf("generating facilities: %w", err)
+	}
+
+	// Rate the staff who maintain the systems
+	if err := g.generateTechnicians(ctx, tx); err != nil {
+		return fmt.Errorf("generating technicians: %w", err)
+	}
+
+	// Open the venues and schedule classes and recreation in them
+	if err := g.generateActivities(ctx, tx); err != nil {
+		return fmt.Errorf("generating activities: %w", err)
+	}
+
+	// Generate doors and airlocks
+	if err := g.generateAccessPoints(ctx, tx); err != nil {
+		return fmt.Errorf("generating access points: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE registry_sequences SET last_seq = ? WHERE vault_id = ?`,
+		g.regNumGen.LastSequence(), g.cfg.VaultNumber); err != nil {
+		return fmt.Errorf("recording registry sequence: %w", err)
+	}
+
 	// Commit transaction
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("committing transaction: %w", err)
@@ -167,6 +228,72 @@ func (g *Generator) generateQuarters(ctx context.Context, tx *sql.Tx) error {
 	return nil
 }
 
+// assignQuarters houses each household in the smallest available quarters
+// that fit its members. Units of a size are taken level by level across the
+// sectors, so households spread through every sector.
+func (g *Generator) assignQuarters(ctx context.Context, tx *sql.Tx) error {
+	slog.Debug("assigning quarters")
+
+	members := make(map[string]int)
+	rows, err := tx.QueryContext(ctx, `SELECT household_id, COUNT(*) FROM residents WHERE household_id IS NOT NULL GROUP BY household_id`)
+	if err != nil {
+		return fmt.Errorf("counting household members: %w", err)
+	}
+	for rows.Next() {
+		var id string
+		var count int
+		if err := rows.Scan(&id, &count); err != nil {
+			rows.Close()
+			return fmt.Errorf("scanning household members: %w", err)
+		}
+		members[id] = count
+	}
+	rows.Close()
+
+	type unit struct {
+		id       string
+		capacity int
+		taken    bool
+	}
+	var units []*unit
+	rows, err = tx.QueryContext(ctx, `SELECT id, capacity FROM quarters WHERE status = 'AVAILABLE' ORDER BY capacity, level, sector, unit_code`)
+	if err != nil {
+		return fmt.Errorf("listing quarters: %w", err)
+	}
+	for rows.Next() {
+		u := &unit{}
+		if err := rows.Scan(&u.id, &u.capacity); err != nil {
+			rows.Close()
+			return fmt.Errorf("scanning quarters: %w", err)
+		}
+		units = append(units, u)
+	}
+	rows.Close()
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	housed := 0
+	for _, h := range g.households {
+		for _, u := range units {
+			if u.taken || u.capacity < members[h.ID] {
+				continue
+			}
+			u.taken = true
+			if _, err := tx.ExecContext(ctx, `UPDATE households SET quarters_id = ?, updated_at = ? WHERE id = ?`, u.id, now, h.ID); err != nil {
+				return fmt.Errorf("housing %s: %w", h.Designation, err)
+			}
+			if _, err := tx.ExecContext(ctx, `UPDATE quarters SET status = 'OCCUPIED', assigned_household_id = ?, updated_at = ? WHERE id = ?`, h.ID, now, u.id); err != nil {
+				return fmt.Errorf("occupying quarters of %s: %w", h.Designation, err)
+			}
+			h.QuartersID = &u.id
+			housed++
+			break
+		}
+	}
+
+	slog.Debug("quarters assigned", "households", housed)
+	return nil
+}
+
 func (g *Generator) generateVocations(ctx context.Context, tx *sql.Tx) error {
 	slog.Debug("generating vocations")
 
@@ -175,12 +302,15 @@ func (g *Generator) generateVocations(ctx context.Context, tx *sql.Tx) error {
 		headcount_authorized, headcount_minimum, shift_pattern,
 		hazard_level, is_active, created_at, updated_at
 	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	programQuery := `INSERT INTO training_programs (
+		id, vocation_id, required_hours, hours_per_day, created_at, updated_at
+	) VALUES (?, ?, ?, ?, ?, ?)`
 
 	now := time.Now().UTC().Format(time.RFC3339)
 	count := 0
 
-	for dept, vocations := range DepartmentVocations {
-		for _, voc := range vocations {
+	for _, dept := range Departments {
+		for _, voc := range DepartmentVocations[dept] {
 			id := g.idGen.NewID()
 
 			// Calculate headcounts based on department size
@@ -203,6 +333,15 @@ func (g *Generator) generateVocations(ctx context.Context, tx *sql.Tx) error {
 			if err != nil {
 				return fmt.Errorf("inserting vocation %s: %w", voc.Code, err)
 			}
+			hours := models.DefaultTrainingHours(&models.Vocation{RequiredClearance: voc.Clearance, HazardLevel: voc.HazardLevel})
+			if _, err := tx.ExecContext(ctx, programQuery,
+				g.idGen.NewID(), id, hours, models.DefaultTrainingHoursPerDay, now, now,
+			); err != nil {
+				return fmt.Errorf("inserting training program %s: %w", voc.Code, err)
+			}
+			g.vocations = append(g.vocations, seedVocation{
+				ID: id, Code: voc.Code, Department: dept, Clearance: voc.Clearance, Minimum: minimum,
+			})
 			count++
 		}
 	}
@@ -348,6 +487,23 @@ func (g *Generator) generateSingleHousehold(ctx context.Context, tx *sql.Tx) err
 	return nil
 }
 
+// claimRegistrySequence claims the vault's registry sequence, starting the
+// generator's numbers after the last issued.
+func (g *Generator) claimRegistrySequence(ctx context.Context, tx *sql.Tx) error {
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO registry_sequences (vault_id, last_seq) VALUES (?, 0)
+		ON CONFLICT (vault_id) DO NOTHING`, g.cfg.VaultNumber); err != nil {
+		return fmt.Errorf("claiming registry sequence: %w", err)
+	}
+	var last int
+	if err := tx.QueryRowContext(ctx, `SELECT last_seq FROM registry_sequences WHERE vault_id = ?`,
+		g.cfg.VaultNumber).Scan(&last); err != nil {
+		return fmt.Errorf("reading registry sequence: %w", err)
+	}
+	g.regNumGen.SetLastSequence(last)
+	return nil
+}
+
 func (g *Generator) generateResident(surname string, sex models.Sex, age int, parent1ID, parent2ID *string) *models.Resident {
 	var givenName string
 	if sex == models.SexMale {
@@ -381,7 +537,7 @@ func (g *Generator) generateResident(surname string, sex models.Sex, age int, pa
 	}
 
 	id := g.idGen.NewID()
-	regNum := g.regNumGen.Next()
+	regNum := g.regNumGen.Next(g.cfg.SealDate.Year())
 
 	return &models.Resident{
 		ID:                  id,
@@ -423,8 +579,8 @@ func (g *Generator) insertResident(ctx context.Context, tx *sql.Tx, r *models.Re
 		id, registry_number, surname, given_names, date_of_birth,
 		sex, blood_type, entry_type, entry_date, status,
 		biological_parent_1_id, biological_parent_2_id,
-		household_id, clearance_level, created_at, updated_at
-	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+		household_id, clearance_level, vault_id, created_at, updated_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
 	now := time.Now().UTC().Format(time.RFC3339)
 
@@ -434,7 +590,7 @@ func (g *Generator) insertResident(ctx context.Context, tx *sql.Tx, r *models.Re
 		string(r.Sex), string(r.BloodType), string(r.EntryType),
 		r.EntryDate.Format(time.RFC3339), string(r.Status),
 		r.BiologicalParent1ID, r.BiologicalParent2ID,
-		r.HouseholdID, r.ClearanceLevel, now, now,
+		r.HouseholdID, r.ClearanceLevel, g.cfg.VaultNumber, now, now,
 	)
 	if err != nil {
 		return fmt.Errorf("inserting resident %s: %w", r.RegistryNumber, err)
@@ -449,15 +605,15 @@ func (g *Generator) insertResident(ctx context.Context, tx *sql.Tx, r *models.Re
 func (g *Generator) insertHousehold(ctx context.Context, tx *sql.Tx, h *models.Household) error {
 	query := `INSERT INTO households (
 		id, designation, household_type, head_of_household_id,
-		ration_class, status, formed_date, created_at, updated_at
-	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+		ration_class, status, formed_date, vault_id, created_at, updated_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
 	now := time.Now().UTC().Format(time.RFC3339)
 
 	_, err := tx.ExecContext(ctx, query,
 		h.ID, h.Designation, string(h.HouseholdType), h.HeadOfHouseholdID,
 		string(h.RationClass), string(h.Status),
-		h.FormedDate.Format(time.DateOnly), now, now,
+		h.FormedDate.Format(time.DateOnly), g.cfg.VaultNumber, now, now,
 	)
 	if err != nil {
 		return fmt.Errorf("inserting household %s: %w", h.Designation, err)
@@ -510,15 +666,11 @@ func (g *Generator) generateResources(ctx context.Context, tx *sql.Tx) error {
 	itemQuery := `INSERT INTO resource_items (
 		id, category_id, item_code, name, description, unit_of_measure,
 		calories_per_unit, shelf_life_days, storage_requirements,
-		is_producible, production_rate_per_day, created_at, updated_at
-	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
-
-	stockQuery := `INSERT INTO resource_stocks (
-		id, item_id, lot_number, quantity, quantity_reserved,
-		storage_location, received_date, expiration_date, status,
-		created_at, updated_at
-	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+		is_producible, production_rate_per_day, protein_g_per_unit, carbs_g_per_unit,
+		fat_g_per_unit, vitamins_pct_per_unit, created_at, updated_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
+	transactions := 0
 	for _, item := range ResourceItems {
 		categoryID := categoryIDs[item.CategoryCode]
 		if categoryID == "" {
@@ -541,6 +693,11 @@ func (g *Generator) generateResources(ctx context.Context, tx *sql.Tx) error {
 			shelfLife = item.ShelfLifeDays
 		}
 
+		var protein, carbs, fat, vitamins interface{}
+		if n, ok := FoodNutrients[item.ItemCode]; ok {
+			protein, carbs, fat, vitamins = n.ProteinG, n.CarbsG, n.FatG, n.VitaminsPct
+		}
+
 		isProducible := 0
 		if item.IsProducible {
 			isProducible = 1
@@ -549,16 +706,12 @@ func (g *Generator) generateResources(ctx context.Context, tx *sql.Tx) error {
 		_, err := tx.ExecContext(ctx, itemQuery,
 			itemID, categoryID, item.ItemCode, item.Name, item.Description,
 			item.UnitOfMeasure, calories, shelfLife, nil,
-			isProducible, prodRate, now, now,
+			isProducible, prodRate, protein, carbs, fat, vitamins, now, now,
 		)
 		if err != nil {
 			return fmt.Errorf("inserting item %s: %w", item.ItemCode, err)
 		}
 
-		// Create initial stock for this item
-		stockID := g.idGen.NewID()
-		lotNumber := fmt.Sprintf("LOT-%s-2077", item.ItemCode)
-
 		// Calculate initial quantity based on population needs
 		var quantity float64
 		switch item.CategoryCode {
@@ -576,26 +729,287 @@ func (g *Generator) generateResources(ctx context.Context, tx *sql.Tx) error {
 			quantity = float64(g.cfg.TargetPopulation) * 0.5
 		}
 
-		// Calculate expiration date if applicable
-		var expirationDate interface{}
-		if item.ShelfLifeDays > 0 {
-			expDate := g.cfg.SealDate.AddDate(0, 0, item.ShelfLifeDays)
-			expirationDate = expDate.Format(time.RFC3339)
+		// Receive the initial stock and draw it down over the history
+		storageLocation := fmt.Sprintf("STORAGE-%s-01", item.CategoryCode[:4])
+		n, err := g.insertStockHistory(ctx, tx, item, itemID, quantity, storageLocation)
+		if err != nil {
+			return err
 		}
+		transactions += n
+	}
 
-		storageLocation := fmt.Sprintf("STORAGE-%s-01", item.CategoryCode[:4])
+	slog.Debug("items and stocks generated", "count", len(ResourceItems), "transactions", transactions)
+
+	return nil
+}
+
+func (g *Generator) generateFacilities(ctx context.Context, tx *sql.Tx) error {
+	slog.Debug("generating facility systems")
+
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	sysQuery := `INSERT INTO facility_systems (
+		id, system_code, name, category, location_sector, location_level,
+		status, efficiency_percent, install_date, last_maintenance_date, next_maintenance_due,
+		maintenance_interval_days, mtbf_hours, total_runtime_hours, vault_id, created_at, updated_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	flowQuery := `INSERT INTO facility_grid_flows (
+		id, system_id, grid, direction, rate, created_at, updated_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	maintQuery := `INSERT INTO maintenance_records (
+		id, system_id, maintenance_type, description, work_performed, lead_technician_id,
+		scheduled_date, started_at, completed_at, estimated_hours, actual_hours, outcome,
+		system_status_before, system_status_after, efficiency_before, efficiency_after,
+		created_at, updated_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	technicians := g.staff("ENGINEERING")
+	historyEnd := g.cfg.SealDate.AddDate(0, 0, g.cfg.HistoryDays)
+	records := 0
+	systemIDs := make(map[string]string, len(FacilitySystems))
+
+	for _, sys := range FacilitySystems {
+		sysID := g.idGen.NewID()
+		systemIDs[sys.Code] = sysID
+
+		interval := MaintenanceIntervals[sys.Category]
+		if interval == 0 {
+			interval = 90
+		}
+		wear := 0.02 + 0.04*g.rng.Float64() // Efficiency percent lost a day
+		history := g.maintenanceHistory(sys, interval, wear)
+
+		// Efficiency wears down from the last work order; preventive
+		// maintenance falls due an interval after the last one.
+		lastWork, lastPreventive := g.cfg.SealDate, g.cfg.SealDate
+		var lastMaintenance interface{}
+		runtime := 0.0
+		for _, m := range history {
+			lastWork = m.Date
+			lastMaintenance = m.Date.Format(time.DateOnly)
+			if m.Type == models.MaintenanceTypePreventive {
+				lastPreventive = m.Date
+			}
+			runtime -= m.Hours
+		}
+		if sys.Status == string(models.FacilityStatusOperational) {
+			runtime += historyEnd.Sub(g.cfg.SealDate).Hours()
+		} else {
+			runtime = 0
+		}
+		efficiency := math.Round((100-wear*historyEnd.Sub(lastWork).Hours()/24)*10) / 10
 
-		_, err = tx.ExecContext(ctx, stockQuery,
-			stockID, itemID, lotNumber, quantity, 0,
-			storageLocation, g.cfg.SealDate.Format(time.RFC3339), expirationDate,
-			"AVAILABLE", now, now,
+		_, err := tx.ExecContext(ctx, sysQuery,
+			sysID, sys.Code, sys.Name, sys.Category, sys.Sector, sys.Level,
+			sys.Status, efficiency, g.cfg.SealDate.Format(time.DateOnly), lastMaintenance,
+			lastPreventive.AddDate(0, 0, interval).Format(time.DateOnly),
+			interval, sys.MTBF, max(runtime, 0), g.cfg.VaultNumber, now, now,
 		)
 		if err != nil {
-			return fmt.Errorf("inserting stock for %s: %w", item.ItemCode, err)
+			return fmt.Errorf("inserting facility system %s: %w", sys.Code, err)
+		}
+
+		for _, flow := range sys.Flows {
+			_, err := tx.ExecContext(ctx, flowQuery,
+				g.idGen.NewID(), sysID, flow.Grid, flow.Direction, flow.Rate, now, now,
+			)
+			if err != nil {
+				return fmt.Errorf("inserting %s flow for %s: %w", flow.Grid, sys.Code, err)
+			}
+		}
+
+		for _, m := range history {
+			started := time.Date(m.Date.Year(), m.Date.Month(), m.Date.Day(), 8, 0, 0, 0, time.UTC)
+			completed := started.Add(time.Duration(m.Hours * float64(time.Hour)))
+			_, err := tx.ExecContext(ctx, maintQuery,
+				g.idGen.NewID(), sysID, string(m.Type), m.Description, m.WorkPerformed, g.pick(technicians),
+				m.Date.Format(time.DateOnly), started.Format(time.RFC3339), completed.Format(time.RFC3339),
+				m.Hours, m.Hours, string(models.MaintenanceOutcomeCompleted),
+				m.StatusBefore, sys.Status, m.EfficiencyBefore, 100.0,
+				now, now,
+			)
+			if err != nil {
+				return fmt.Errorf("inserting maintenance of %s: %w", sys.Code, err)
+			}
+			records++
 		}
 	}
 
-	slog.Debug("items and stocks generated", "count", len(ResourceItems))
+	for _, dep := range FacilityDependencies {
+		_, err := tx.ExecContext(ctx, `INSERT INTO system_dependencies (system_id, depends_on_id, created_at) VALUES (?, ?, ?)`,
+			systemIDs[dep[0]], systemIDs[dep[1]], now,
+		)
+		if err != nil {
+			return fmt.Errorf("inserting dependency of %s on %s: %w", dep[0], dep[1], err)
+		}
+	}
+
+	for i, code := range LoadShedPriorities {
+		_, err := tx.ExecContext(ctx, `INSERT INTO load_shed_priorities (system_id, priority, created_at, updated_at) VALUES (?, ?, ?, ?)`,
+			systemIDs[code], i+1, now, now,
+		)
+		if err != nil {
+			return fmt.Errorf("inserting load shed priority of %s: %w", code, err)
+		}
+	}
+
+	slog.Debug("facility systems generated", "count", len(FacilitySystems), "maintenance_records", records,
+		"dependencies", len(FacilityDependencies), "shed_priorities", len(LoadShedPriorities))
+
+	return nil
+}
+
+// generateTechnicians rates the staff of each vocation in TechnicianSkills
+// in its categories, at the vocation's rating plus up to one, and spreads
+// them across the three shifts in turn.
+func (g *Generator) generateTechnicians(ctx context.Context, tx *sql.Tx) error {
+	slog.Debug("generating technicians")
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	count := 0
+	for _, v := range g.vocations {
+		skills := TechnicianSkills[v.Code]
+		if len(skills) == 0 {
+			continue
+		}
+		for _, r := range v.Staff {
+			shift := models.Shifts[count%len(models.Shifts)]
+			_, err := tx.ExecContext(ctx, `INSERT INTO technicians (resident_id, shift, created_at, updated_at) VALUES (?, ?, ?, ?)`,
+				r.ID, string(shift), now, now,
+			)
+			if err != nil {
+				return fmt.Errorf("inserting technician %s: %w", r.RegistryNumber, err)
+			}
+			for _, category := range models.FacilityCategories {
+				rating, ok := skills[string(category)]
+				if !ok {
+					continue
+				}
+				rating = min(rating+g.rng.Intn(2), models.MaxSkillRating)
+				_, err := tx.ExecContext(ctx, `INSERT INTO technician_skills (resident_id, category, rating) VALUES (?, ?, ?)`,
+					r.ID, string(category), rating,
+				)
+				if err != nil {
+					return fmt.Errorf("inserting %s skill of %s: %w", category, r.RegistryNumber, err)
+				}
+			}
+			count++
+		}
+	}
+
+	slog.Debug("technicians generated", "count", count)
+	return nil
+}
+
+// generateActivities opens the Venues and schedules the Activities in them,
+// enrolling residents of the ages each admits as of the seal date.
+func (g *Generator) generateActivities(ctx context.Context, tx *sql.Tx) error {
+	slog.Debug("generating activities")
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	venues := make(map[string]struct {
+		ID       string
+		Capacity int
+	}, len(Venues))
+	for _, v := range Venues {
+		id := g.idGen.NewID()
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO venues (id, vault_id, code, name, venue_type, sector, capacity, is_active, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, 1, ?, ?)`,
+			id, g.cfg.VaultNumber, v.Code, v.Name, v.Type, v.Sector, v.Capacity, now, now,
+		)
+		if err != nil {
+			return fmt.Errorf("inserting venue %s: %w", v.Code, err)
+		}
+		venues[v.Code] = struct {
+			ID       string
+			Capacity int
+		}{id, v.Capacity}
+	}
+
+	vocations := make(map[string]seedVocation, len(g.vocations))
+	for _, v := range g.vocations {
+		vocations[v.Code] = v
+	}
+
+	enrolled := 0
+	sealed := g.cfg.SealDate.Format(time.RFC3339)
+	for _, a := range Activities {
+		days, err := models.ParseWeekdays(a.Days)
+		if err != nil {
+			return fmt.Errorf("activity %s: %w", a.Code, err)
+		}
+		var vocationID, instructorID, maxAge any
+		if v, ok := vocations[a.Vocation]; ok {
+			vocationID = v.ID
+		}
+		if v, ok := vocations[a.Instructor]; ok && len(v.Staff) > 0 {
+			instructorID = v.Staff[g.rng.Intn(len(v.Staff))].ID
+		}
+		if a.MaxAge > 0 {
+			maxAge = a.MaxAge
+		}
+
+		id := g.idGen.NewID()
+		venue := venues[a.Venue]
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO activities (
+				id, venue_id, code, name, activity_type, vocation_id, instructor_id,
+				weekdays, start_hour, duration_hours, min_age, max_age, is_active,
+				created_at, updated_at
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 1, ?, ?)`,
+			id, venue.ID, a.Code, a.Name, a.Type, vocationID, instructorID,
+			int(days), a.StartHour, a.Hours, a.MinAge, maxAge, now, now,
+		)
+		if err != nil {
+			return fmt.Errorf("inserting activity %s: %w", a.Code, err)
+		}
+
+		places := venue.Capacity
+		for _, r := range g.residents {
+			if places == 0 {
+				break
+			}
+			age := r.Age(g.cfg.SealDate)
+			if age < a.MinAge || (a.MaxAge > 0 && age > a.MaxAge) || g.rng.Float64() >= a.Share {
+				continue
+			}
+			_, err := tx.ExecContext(ctx, `INSERT INTO activity_enrollments (activity_id, resident_id, enrolled_at) VALUES (?, ?, ?)`,
+				id, r.ID, sealed,
+			)
+			if err != nil {
+				return fmt.Errorf("enrolling %s in %s: %w", r.RegistryNumber, a.Code, err)
+			}
+			places--
+			enrolled++
+		}
+	}
+
+	slog.Debug("activities generated", "venues", len(Venues), "activities", len(Activities), "enrollments", enrolled)
+	return nil
+}
+
+func (g *Generator) generateAccessPoints(ctx context.Context, tx *sql.Tx) error {
+	slog.Debug("generating access points")
+
+	query := `INSERT INTO access_points (
+		id, code, name, kind, location_sector, location_level,
+		min_clearance, is_active, vault_id, created_at, updated_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, 1, ?, ?, ?)`
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	for _, p := range AccessPoints {
+		_, err := tx.ExecContext(ctx, query,
+			g.idGen.NewID(), p.Code, p.Name, p.Kind, p.Sector, p.Level,
+			p.MinClearance, g.cfg.VaultNumber, now, now,
+		)
+		if err != nil {
+			return fmt.Errorf("inserting access point %s: %w", p.Code, err)
+		}
+	}
 
+	slog.Debug("access points generated", "count", len(AccessPoints))
 	return nil
 }
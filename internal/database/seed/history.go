@@ -0,0 +1,432 @@
+package seed
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// overseerVocation is left vacant by seeding; the overseer is appointed on
+// first initialization.
+const overseerVocation = "ADM-OVSR-01"
+
+// seedVocation is a generated vocation, kept to staff it.
+type seedVocation struct {
+	ID         string
+	Code       string
+	Department string
+	Clearance  int
+	Minimum    int
+	Staff      []*models.Resident
+}
+
+// staff returns the residents holding a vocation of the department.
+func (g *Generator) staff(department string) []*models.Resident {
+	var staff []*models.Resident
+	for _, v := range g.vocations {
+		if v.Department == department {
+			staff = append(staff, v.Staff...)
+		}
+	}
+	return staff
+}
+
+// pick returns a random resident of the list, or nil if it is empty.
+func (g *Generator) pick(residents []*models.Resident) *string {
+	if len(residents) == 0 {
+		return nil
+	}
+	return &residents[g.rng.Intn(len(residents))].ID
+}
+
+// assignVocations staffs each vocation but the overseer's, one resident per
+// vocation in turn until each has its minimum headcount or the working-age
+// adults run out. Senior staff are raised to the clearance their vocation
+// requires. EmploymentRate of the remaining working-age adults then take a
+// vocation within their clearance.
+func (g *Generator) assignVocations(ctx context.Context, tx *sql.Tx) error {
+	slog.Debug("assigning vocations")
+
+	var workers []*models.Resident
+	for _, r := range g.residents {
+		if r.IsAdult(g.cfg.SealDate) && r.IsWorkingAge(g.cfg.SealDate) {
+			workers = append(workers, r)
+		}
+	}
+	g.rng.Shuffle(len(workers), func(i, j int) { workers[i], workers[j] = workers[j], workers[i] })
+
+	assign := func(r *models.Resident, v *seedVocation) {
+		r.PrimaryVocationID = &v.ID
+		r.ClearanceLevel = max(r.ClearanceLevel, v.Clearance)
+		v.Staff = append(v.Staff, r)
+	}
+
+	next := 0
+	for round := 0; next < len(workers); round++ {
+		staffed := false
+		for i := range g.vocations {
+			v := &g.vocations[i]
+			if v.Code == overseerVocation || round >= v.Minimum || next >= len(workers) {
+				continue
+			}
+			assign(workers[next], v)
+			next++
+			staffed = true
+		}
+		if !staffed {
+			break
+		}
+	}
+
+	for _, r := range workers[next:] {
+		if g.rng.Float64() >= g.cfg.EmploymentRate {
+			continue
+		}
+		var eligible []int
+		for i, v := range g.vocations {
+			if v.Code != overseerVocation && v.Clearance <= r.ClearanceLevel {
+				eligible = append(eligible, i)
+			}
+		}
+		if len(eligible) > 0 {
+			assign(r, &g.vocations[eligible[g.rng.Intn(len(eligible))]])
+		}
+	}
+
+	query := `UPDATE residents SET primary_vocation_id = ?, clearance_level = ? WHERE id = ?`
+	employed := 0
+	for _, r := range workers {
+		if r.PrimaryVocationID == nil {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, query, *r.PrimaryVocationID, r.ClearanceLevel, r.ID); err != nil {
+			return fmt.Errorf("assigning vocation to %s: %w", r.RegistryNumber, err)
+		}
+		employed++
+	}
+
+	slog.Debug("vocations assigned", "employed", employed, "working_age", len(workers))
+	return nil
+}
+
+// generateMedicalRecords records each resident's intake physical on entry,
+// the chronic conditions ConditionRate of them entered with and, every
+// CheckupDays of the history, a routine checkup.
+func (g *Generator) generateMedicalRecords(ctx context.Context, tx *sql.Tx) error {
+	slog.Debug("generating medical records")
+
+	recordQuery := `INSERT INTO medical_records (
+		id, resident_id, record_type, chief_complaint, diagnosis_codes, diagnosis_text,
+		treatment_provided, vitals_json, provider_id, facility_location, encounter_date,
+		status, created_at, updated_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	conditionQuery := `INSERT INTO medical_conditions (
+		id, resident_id, condition_code, condition_name, onset_date, severity,
+		is_chronic, is_genetic, is_contagious, treatment_plan, created_at, updated_at
+	) VALUES (?, ?, ?, ?, ?, ?, 1, ?, 0, ?, ?, ?)`
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	physicians := g.staff("MEDICAL")
+	records, conditions := 0, 0
+
+	examine := func(r *models.Resident, complaint string, date time.Time) error {
+		vitals := fmt.Sprintf(`{"pulse":%d,"blood_pressure":"%d/%d","temperature_c":%.1f}`,
+			58+g.rng.Intn(30), 105+g.rng.Intn(35), 65+g.rng.Intn(20), 36.3+g.rng.Float64())
+		_, err := tx.ExecContext(ctx, recordQuery,
+			g.idGen.NewID(), r.ID, "EXAMINATION", complaint, nil, "No acute findings",
+			nil, vitals, g.pick(physicians), "MED-BAY-01", date.Format(time.DateOnly),
+			"RESOLVED", now, now,
+		)
+		if err != nil {
+			return fmt.Errorf("inserting examination of %s: %w", r.RegistryNumber, err)
+		}
+		records++
+		return nil
+	}
+
+	for _, r := range g.residents {
+		if err := examine(r, "Intake physical", r.EntryDate); err != nil {
+			return err
+		}
+
+		if g.rng.Float64() < g.cfg.ConditionRate {
+			c := ChronicConditions[g.rng.Intn(len(ChronicConditions))]
+			onset := r.DateOfBirth
+			if !c.Genetic {
+				onset = onset.AddDate(g.rng.Intn(r.Age(r.EntryDate)+1), 0, 0)
+				if onset.After(r.EntryDate) {
+					onset = r.EntryDate
+				}
+			}
+
+			_, err := tx.ExecContext(ctx, conditionQuery,
+				g.idGen.NewID(), r.ID, c.Code, c.Name, onset.Format(time.DateOnly), c.Severity,
+				boolInt(c.Genetic), c.Plan, now, now,
+			)
+			if err != nil {
+				return fmt.Errorf("inserting condition of %s: %w", r.RegistryNumber, err)
+			}
+			_, err = tx.ExecContext(ctx, recordQuery,
+				g.idGen.NewID(), r.ID, "CHRONIC_CONDITION", "Pre-existing condition", c.Code, c.Name,
+				c.Plan, nil, g.pick(physicians), "MED-BAY-01", r.EntryDate.Format(time.DateOnly),
+				"CHRONIC", now, now,
+			)
+			if err != nil {
+				return fmt.Errorf("inserting condition record of %s: %w", r.RegistryNumber, err)
+			}
+			conditions++
+			records++
+		}
+
+		for day := g.cfg.CheckupDays; g.cfg.CheckupDays > 0 && day <= g.cfg.HistoryDays; day += g.cfg.CheckupDays {
+			if err := examine(r, "Routine checkup", g.cfg.SealDate.AddDate(0, 0, day)); err != nil {
+				return err
+			}
+		}
+	}
+
+	slog.Debug("medical records generated", "records", records, "conditions", conditions)
+	return nil
+}
+
+// seedLot is a stock lot followed through the generated history.
+type seedLot struct {
+	ID       string
+	Number   string
+	Quantity float64
+	Received time.Time
+	Expires  *time.Time
+	Spoiled  bool
+}
+
+// seedTransaction is a resource transaction of the generated history.
+type seedTransaction struct {
+	Lot       *seedLot
+	Type      models.TransactionType
+	Quantity  float64
+	Balance   float64
+	Reason    string
+	Timestamp time.Time
+}
+
+// dailyDemand returns the quantity of an item the population uses a day.
+// Non-consumables are drawn only by work orders, so have none.
+func (g *Generator) dailyDemand(item ResourceItemSpec) float64 {
+	population := float64(g.cfg.TargetPopulation)
+	switch item.CategoryCode {
+	case "FOOD":
+		foodItems := 0
+		for _, i := range ResourceItems {
+			if i.CategoryCode == "FOOD" {
+				foodItems++
+			}
+		}
+		return population * 0.5 / float64(foodItems) // 0.5 kg per person per day
+	case "WATER":
+		return population * 3.0 // 3 liters per person per day
+	case "MEDICAL":
+		return population * 0.005
+	case "CHEMICALS":
+		return population * 0.02
+	default:
+		return 0
+	}
+}
+
+// roundQuantity rounds a quantity to whole units, or to the hundredth of
+// items measured by weight or volume.
+func roundQuantity(item ResourceItemSpec, quantity float64) float64 {
+	if item.UnitOfMeasure == "units" {
+		return math.Round(quantity)
+	}
+	return math.Round(quantity*100) / 100
+}
+
+// newLot returns a lot of the item received at the given time.
+func (g *Generator) newLot(item ResourceItemSpec, number string, quantity float64, received time.Time) *seedLot {
+	lot := &seedLot{ID: g.idGen.NewID(), Number: number, Quantity: quantity, Received: received}
+	if item.ShelfLifeDays > 0 {
+		expires := received.AddDate(0, 0, item.ShelfLifeDays)
+		lot.Expires = &expires
+	}
+	return lot
+}
+
+// stockHistory follows an item's stock from its receipt at the seal through
+// HistoryDays: each day lots past their expiration spoil and the population
+// draws its demand from the oldest lots, and each week producible items
+// yield a new lot of about a week's demand, up to their production rate.
+func (g *Generator) stockHistory(item ResourceItemSpec, initial float64) ([]*seedLot, []seedTransaction) {
+	first := g.newLot(item, fmt.Sprintf("LOT-%s-2077", item.ItemCode), initial, g.cfg.SealDate)
+	lots := []*seedLot{first}
+	txns := []seedTransaction{{first, models.TransactionTypeProduction, initial, initial, "Initial stock receipt", g.cfg.SealDate}}
+
+	demand := g.dailyDemand(item)
+	for day := 1; day <= g.cfg.HistoryDays; day++ {
+		at := g.cfg.SealDate.AddDate(0, 0, day)
+
+		for _, lot := range lots {
+			if lot.Expires != nil && !lot.Expires.After(at) && !lot.Spoiled {
+				lot.Spoiled = true
+				if lot.Quantity > 0 {
+					txns = append(txns, seedTransaction{lot, models.TransactionTypeSpoilage, -lot.Quantity, 0, "Expired", *lot.Expires})
+					lot.Quantity = 0
+				}
+			}
+		}
+		if demand == 0 {
+			continue
+		}
+
+		if item.IsProducible && day%7 == 0 {
+			yield := roundQuantity(item, min(item.ProdRatePerDay, demand*(0.9+0.2*g.rng.Float64()))*7)
+			if yield > 0 {
+				lot := g.newLot(item, fmt.Sprintf("LOT-%s-%s", item.ItemCode, at.Format("20060102")), yield, at)
+				lots = append(lots, lot)
+				txns = append(txns, seedTransaction{lot, models.TransactionTypeProduction, yield, yield, "Weekly production", at})
+			}
+		}
+
+		need := roundQuantity(item, demand*(0.85+0.3*g.rng.Float64()))
+		for _, lot := range lots {
+			if need <= 0 {
+				break
+			}
+			if lot.Quantity <= 0 || lot.Spoiled {
+				continue
+			}
+			take := min(need, lot.Quantity)
+			lot.Quantity -= take
+			need -= take
+			txns = append(txns, seedTransaction{lot, models.TransactionTypeConsumption, -take, lot.Quantity, "Daily use", at})
+		}
+	}
+
+	return lots, txns
+}
+
+// lotStatus returns the status of a lot at the end of the history.
+func lotStatus(lot *seedLot) string {
+	switch {
+	case lot.Spoiled:
+		return string(models.StockStatusExpired)
+	case lot.Quantity <= 0:
+		return string(models.StockStatusDepleted)
+	default:
+		return string(models.StockStatusAvailable)
+	}
+}
+
+// insertStockHistory inserts an item's lots and the transactions of its
+// history, starting from the initial stock received at the seal.
+func (g *Generator) insertStockHistory(ctx context.Context, tx *sql.Tx, item ResourceItemSpec, itemID string, initial float64, location string) (int, error) {
+	stockQuery := `INSERT INTO resource_stocks (
+		id, item_id, lot_number, quantity, quantity_reserved,
+		storage_location, received_date, expiration_date, status,
+		vault_id, created_at, updated_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	txnQuery := `INSERT INTO resource_transactions (
+		id, stock_id, item_id, transaction_type, quantity, balance_after,
+		reason, timestamp, created_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	lots, txns := g.stockHistory(item, initial)
+
+	for _, lot := range lots {
+		var expirationDate interface{}
+		if lot.Expires != nil {
+			expirationDate = lot.Expires.Format(time.RFC3339)
+		}
+		_, err := tx.ExecContext(ctx, stockQuery,
+			lot.ID, itemID, lot.Number, lot.Quantity, 0,
+			location, lot.Received.Format(time.RFC3339), expirationDate,
+			lotStatus(lot), g.cfg.VaultNumber, now, now,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("inserting stock %s: %w", lot.Number, err)
+		}
+	}
+
+	for _, t := range txns {
+		_, err := tx.ExecContext(ctx, txnQuery,
+			g.idGen.NewID(), t.Lot.ID, itemID, string(t.Type), t.Quantity, t.Balance,
+			t.Reason, t.Timestamp.Format(time.RFC3339), now,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("inserting %s transaction for %s: %w", t.Type, item.ItemCode, err)
+		}
+	}
+
+	return len(txns), nil
+}
+
+// seedMaintenance is a completed work order of the generated history.
+type seedMaintenance struct {
+	Type             models.MaintenanceType
+	Description      string
+	WorkPerformed    string
+	Date             time.Time
+	Hours            float64
+	StatusBefore     string
+	EfficiencyBefore float64
+}
+
+// maintenanceHistory returns a system's completed work orders over
+// HistoryDays, in date order: preventive maintenance every interval days,
+// and corrective repairs of faults arriving at the system's rated failure
+// rate times FailureFactor. Efficiency falls by wear percent a day between
+// work orders.
+func (g *Generator) maintenanceHistory(sys FacilitySystemSpec, interval int, wear float64) []seedMaintenance {
+	var history []seedMaintenance
+
+	for day := interval; day <= g.cfg.HistoryDays; day += interval {
+		history = append(history, seedMaintenance{
+			Type:             models.MaintenanceTypePreventive,
+			Description:      "Scheduled preventive maintenance of " + sys.Name,
+			WorkPerformed:    "Inspected, cleaned and lubricated; replaced worn consumables",
+			Date:             g.cfg.SealDate.AddDate(0, 0, day),
+			Hours:            float64(2 + g.rng.Intn(7)),
+			StatusBefore:     sys.Status,
+			EfficiencyBefore: 100 - wear*float64(interval),
+		})
+	}
+
+	if sys.Status == string(models.FacilityStatusOperational) && sys.MTBF > 0 {
+		faultChance := 24 / float64(sys.MTBF) * g.cfg.FailureFactor
+		for day := 1; day <= g.cfg.HistoryDays; day++ {
+			if g.rng.Float64() >= faultChance {
+				continue
+			}
+			fault := MaintenanceFaults[g.rng.Intn(len(MaintenanceFaults))]
+			history = append(history, seedMaintenance{
+				Type:             models.MaintenanceTypeCorrective,
+				Description:      fault + " in " + sys.Name,
+				WorkPerformed:    "Replaced the failed component and returned the system to service",
+				Date:             g.cfg.SealDate.AddDate(0, 0, day),
+				Hours:            float64(4 + g.rng.Intn(13)),
+				StatusBefore:     string(models.FacilityStatusDegraded),
+				EfficiencyBefore: float64(55 + g.rng.Intn(25)),
+			})
+		}
+	}
+
+	sort.SliceStable(history, func(i, j int) bool { return history[i].Date.Before(history[j].Date) })
+	return history
+}
+
+// boolInt returns 1 for true and 0 for false.
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,61 @@
+package seed
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+func TestProfileConfig(t *testing.T) {
+	start := time.Date(2078, 4, 21, 9, 0, 0, 0, time.UTC)
+	for _, p := range Profiles {
+		cfg := p.Config(76, start)
+		if got := cfg.SealDate.AddDate(0, 0, cfg.HistoryDays); !got.Equal(start) {
+			t.Errorf("%s: history ends %v, want %v", p.Name, got, start)
+		}
+		if cfg.TargetPopulation != p.TargetPopulation {
+			t.Errorf("%s: population = %d, want %d", p.Name, cfg.TargetPopulation, p.TargetPopulation)
+		}
+	}
+
+	if _, err := LookupProfile("vault-of-one"); err == nil {
+		t.Error("LookupProfile() of an unknown profile succeeded")
+	}
+}
+
+func TestStockHistory(t *testing.T) {
+	profile, err := LookupProfile("small-outpost")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := NewGenerator(nil, profile.Config(76, time.Date(2078, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	for _, item := range ResourceItems {
+		lots, txns := g.stockHistory(item, 100)
+
+		balances := make(map[*seedLot]float64)
+		for _, txn := range txns {
+			balances[txn.Lot] += txn.Quantity
+			if math.Abs(balances[txn.Lot]-txn.Balance) > 1e-6 {
+				t.Fatalf("%s: %s balance %v, want %v", item.ItemCode, txn.Type, txn.Balance, balances[txn.Lot])
+			}
+			if txn.Balance < 0 {
+				t.Fatalf("%s: negative balance %v", item.ItemCode, txn.Balance)
+			}
+		}
+		for _, lot := range lots {
+			if math.Abs(balances[lot]-lot.Quantity) > 1e-6 {
+				t.Errorf("%s: lot %s holds %v, transactions total %v", item.ItemCode, lot.Number, lot.Quantity, balances[lot])
+			}
+			if lot.Spoiled && lot.Quantity != 0 {
+				t.Errorf("%s: spoiled lot %s holds %v", item.ItemCode, lot.Number, lot.Quantity)
+			}
+		}
+
+		if item.ItemCode == "FOOD-VEGET-001" && lotStatus(lots[0]) != string(models.StockStatusExpired) {
+			t.Errorf("initial vegetables are %s after %d days, want EXPIRED", lotStatus(lots[0]), profile.HistoryDays)
+		}
+	}
+}
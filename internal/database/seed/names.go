@@ -178,8 +178,8 @@ var ResourceCategories = []struct {
 	{"CHEMICALS", "Chemicals", "Cleaning agents, industrial chemicals, and compounds", "liters", true, false},
 }
 
-// ResourceItems defines the resource items for seeding.
-var ResourceItems = []struct {
+// ResourceItemSpec is a resource item for seeding.
+type ResourceItemSpec struct {
 	CategoryCode    string
 	ItemCode        string
 	Name            string
@@ -189,7 +189,10 @@ var ResourceItems = []struct {
 	ShelfLifeDays   int
 	IsProducible    bool
 	ProdRatePerDay  float64
-}{
+}
+
+// ResourceItems defines the resource items for seeding.
+var ResourceItems = []ResourceItemSpec{
 	// Food items
 	{"FOOD", "FOOD-PROTEIN-001", "Protein Rations", "Processed protein supplement bars", "kg", 3500, 365, true, 50},
 	{"FOOD", "FOOD-CARBS-001", "Carbohydrate Mix", "Dehydrated carbohydrate powder", "kg", 3800, 730, true, 100},
@@ -234,3 +237,217 @@ var ResourceItems = []struct {
 	{"CHEMICALS", "CHEM-CLEAN-001", "Cleaning Solution", "Multi-purpose cleaning agent", "liters", 0, 365, true, 50},
 	{"CHEMICALS", "CHEM-SANIT-001", "Sanitizer", "Antibacterial sanitizing solution", "liters", 0, 730, true, 25},
 }
+
+// FoodNutrientSpec is the nutrient content of a unit of a seeded food item.
+type FoodNutrientSpec struct {
+	ProteinG, CarbsG, FatG float64
+	VitaminsPct            float64 // Of a resident's daily allowance
+}
+
+// FoodNutrients are the nutrient contents of the seeded food items, by item
+// code.
+var FoodNutrients = map[string]FoodNutrientSpec{
+	"FOOD-PROTEIN-001": {350, 150, 160, 100},
+	"FOOD-CARBS-001":   {80, 800, 30, 50},
+	"FOOD-VEGET-001":   {15, 45, 3, 800},
+	"FOOD-FRUIT-001":   {5, 110, 2, 400},
+	"FOOD-MEALS-001":   {50, 150, 45, 60},
+	"FOOD-SUGAR-001":   {0, 1000, 0, 0},
+}
+
+// FacilityFlow is a grid flow declared by a seeded facility system. Rates are
+// in kW for POWER and liters/day for WATER.
+type FacilityFlow struct {
+	Grid      string
+	Direction string
+	Rate      float64
+}
+
+// FacilitySystemSpec is a facility system and its grid flows for seeding.
+type FacilitySystemSpec struct {
+	Code     string
+	Name     string
+	Category string
+	Sector   string
+	Level    int
+	Status   string
+	MTBF     int // Rated mean time between failures, hours
+	Flows    []FacilityFlow
+}
+
+// FacilitySystems defines the facility systems and grid flows for seeding.
+var FacilitySystems = []FacilitySystemSpec{
+	// Power
+	{"PWR-REACTOR-01", "Fusion Reactor", "POWER", "CORE", 5, "OPERATIONAL", 26280,
+		[]FacilityFlow{{"POWER", "OUTPUT", 2500}}},
+	{"PWR-BACKUP-01", "Backup Generator", "POWER", "CORE", 5, "OFFLINE", 8760,
+		[]FacilityFlow{{"POWER", "OUTPUT", 600}}},
+
+	// Water
+	{"WTR-PURIF-01", "Water Purification Plant", "WATER", "CORE", 4, "OPERATIONAL", 4380,
+		[]FacilityFlow{{"WATER", "OUTPUT", 30000}, {"POWER", "DRAW", 350}}},
+	{"WTR-RECYC-01", "Gray Water Recycler", "WATER", "CORE", 4, "OPERATIONAL", 4380,
+		[]FacilityFlow{{"WATER", "OUTPUT", 12000}, {"POWER", "DRAW", 150}}},
+
+	// HVAC
+	{"HVAC-AIR-01", "Primary Air Handler", "HVAC", "CORE", 3, "OPERATIONAL", 6570,
+		[]FacilityFlow{{"POWER", "DRAW", 420}}},
+	{"HVAC-SCRUB-01", "CO2 Scrubber Array", "HVAC", "CORE", 3, "OPERATIONAL", 4380,
+		[]FacilityFlow{{"POWER", "DRAW", 180}}},
+
+	// Security
+	{"SEC-DOOR-01", "Vault Door Mechanism", "SECURITY", "ENTRANCE", 1, "OPERATIONAL", 17520,
+		[]FacilityFlow{{"POWER", "DRAW", 60}}},
+	{"SEC-MONITOR-01", "Surveillance Network", "SECURITY", "ENTRANCE", 1, "OPERATIONAL", 8760,
+		[]FacilityFlow{{"POWER", "DRAW", 40}}},
+
+	// Consumers
+	{"FOOD-HYDRO-01", "Hydroponics Bay", "FOOD_PRODUCTION", "CORE", 2, "OPERATIONAL", 4380,
+		[]FacilityFlow{{"POWER", "DRAW", 380}, {"WATER", "DRAW", 8000}}},
+	{"MED-BAY-01", "Medical Bay", "MEDICAL", "A", 1, "OPERATIONAL", 8760,
+		[]FacilityFlow{{"POWER", "DRAW", 90}, {"WATER", "DRAW", 1500}}},
+	{"STR-RESID-01", "Residential Distribution", "STRUCTURAL", "A", 1, "OPERATIONAL", 17520,
+		[]FacilityFlow{{"POWER", "DRAW", 500}, {"WATER", "DRAW", 25000}}},
+}
+
+// FacilityDependencies are the systems each seeded system needs running, as
+// system code and the code of the system it depends on.
+var FacilityDependencies = [][2]string{
+	{"WTR-PURIF-01", "PWR-REACTOR-01"},
+	{"WTR-RECYC-01", "PWR-REACTOR-01"},
+	{"HVAC-AIR-01", "PWR-REACTOR-01"},
+	{"HVAC-SCRUB-01", "PWR-REACTOR-01"},
+	{"SEC-DOOR-01", "PWR-REACTOR-01"},
+	{"SEC-MONITOR-01", "PWR-REACTOR-01"},
+	{"FOOD-HYDRO-01", "PWR-REACTOR-01"},
+	{"FOOD-HYDRO-01", "WTR-PURIF-01"},
+	{"MED-BAY-01", "PWR-REACTOR-01"},
+	{"MED-BAY-01", "WTR-PURIF-01"},
+	{"STR-RESID-01", "WTR-PURIF-01"},
+}
+
+// TechnicianSkills are the facility categories the staff of each vocation
+// that maintains systems are rated in, at the rating of a fresh hand. Staff
+// are rated up to one point higher.
+var TechnicianSkills = map[string]map[string]int{
+	"ENG-MAINT-01": {"POWER": 2, "WATER": 2, "HVAC": 2, "WASTE": 2, "STRUCTURAL": 2},
+	"ENG-POWER-01": {"POWER": 4},
+	"ENG-HVAC-01":  {"HVAC": 4, "WASTE": 2},
+	"ENG-WATER-01": {"WATER": 4, "WASTE": 3},
+	"ENG-ELEC-01":  {"POWER": 3, "SECURITY": 3, "COMMUNICATIONS": 3},
+	"ENG-MECH-01":  {"STRUCTURAL": 4, "HVAC": 3, "WASTE": 3, "FOOD_PRODUCTION": 2},
+	"MED-TECH-01":  {"MEDICAL": 3},
+	"FOOD-FARM-01": {"FOOD_PRODUCTION": 2},
+	"SAN-WAST-01":  {"WASTE": 3},
+}
+
+// LoadShedPriorities are the seeded systems shed when power runs short, by
+// system code, in the order they are shed. Quarters lighting goes last; life
+// support is never shed.
+var LoadShedPriorities = []string{
+	"SEC-MONITOR-01",
+	"FOOD-HYDRO-01",
+	"SEC-DOOR-01",
+	"STR-RESID-01",
+}
+
+// AccessPoints are the doors and airlocks seeded with their minimum
+// clearance.
+var AccessPoints = []struct {
+	Code         string
+	Name         string
+	Kind         string
+	Sector       string
+	Level        int
+	MinClearance int
+}{
+	{"AL-VAULT-01", "Vault Door Airlock", "AIRLOCK", "ENTRANCE", 1, 10},
+	{"AL-SURFACE-01", "Surface Access Airlock", "AIRLOCK", "ENTRANCE", 1, 9},
+	{"DR-COMMAND-01", "Command Center", "DOOR", "A", 1, 8},
+	{"DR-ARMORY-01", "Armory", "DOOR", "ENTRANCE", 1, 7},
+	{"DR-REACTOR-01", "Reactor Access", "DOOR", "CORE", 5, 6},
+	{"DR-ENGINE-01", "Engineering Bay", "DOOR", "CORE", 4, 4},
+	{"DR-RESID-01", "Residential Wing", "DOOR", "A", 1, 1},
+}
+
+// Venues are the classrooms, workshop and recreation facilities seeded.
+var Venues = []struct {
+	Code     string
+	Name     string
+	Type     string
+	Sector   string
+	Capacity int
+}{
+	{"CLS-01", "Vault-Tec Schoolhouse", "CLASSROOM", "A", 60},
+	{"WKS-01", "Training Workshop", "WORKSHOP", "B", 20},
+	{"GYM-01", "Gymnasium", "GYMNASIUM", "B", 50},
+	{"LIB-01", "Library", "LIBRARY", "C", 30},
+	{"THR-01", "Atrium Theater", "THEATER", "A", 120},
+}
+
+// Activities are the weekly activities seeded in the venues. Instructors
+// are drawn from the staff of the Instructor vocation; SCHOOL classes enroll
+// every resident of their ages, and other activities a Share of those
+// admitted, up to the venue's capacity.
+var Activities = []struct {
+	Code       string
+	Name       string
+	Type       string
+	Venue      string
+	Vocation   string // Trained by a SKILLS class
+	Instructor string
+	Days       string
+	StartHour  int
+	Hours      float64
+	MinAge     int
+	MaxAge     int // 0 for no upper age
+	Share      float64
+}{
+	{"SCH-PRI", "Primary School", "SCHOOL", "CLS-01", "", "EDU-TCHR-01", "WEEKDAYS", 8, 4, 6, 11, 1},
+	{"SCH-SEC", "Secondary School", "SCHOOL", "CLS-01", "", "EDU-TCHR-01", "WEEKDAYS", 13, 4, 12, 15, 1},
+	{"SKL-MAINT", "Maintenance Fundamentals", "SKILLS", "WKS-01", "ENG-MAINT-01", "ENG-MAINT-01", "TUE,THU", 18, 2, 16, 0, 0.03},
+	{"SKL-AID", "First Aid", "SKILLS", "CLS-01", "MED-NURS-01", "MED-NURS-01", "WED", 18, 2, 16, 0, 0.05},
+	{"REC-FIT", "Fitness Hour", "RECREATION", "GYM-01", "", "", "DAILY", 7, 1, 12, 0, 0.08},
+	{"REC-READ", "Reading Circle", "RECREATION", "LIB-01", "", "EDU-LIBR-01", "SUN", 15, 1.5, 0, 0, 0.05},
+	{"REC-FILM", "Film Night", "RECREATION", "THR-01", "", "", "SAT", 19, 2, 0, 0, 0.2},
+}
+
+// MaintenanceIntervals are the preventive maintenance intervals, in days, of
+// facility system categories that differ from the default of 90.
+var MaintenanceIntervals = map[string]int{
+	"WATER":           45,
+	"HVAC":            30,
+	"FOOD_PRODUCTION": 30,
+	"MEDICAL":         60,
+}
+
+// MaintenanceFaults are the faults corrective maintenance records repair.
+var MaintenanceFaults = []string{
+	"Bearing wear",
+	"Seal leak",
+	"Control board fault",
+	"Sensor drift",
+	"Pump cavitation",
+	"Relay failure",
+	"Filter blockage",
+	"Coolant loss",
+}
+
+// ChronicConditions are the chronic conditions residents may enter the
+// vault with.
+var ChronicConditions = []struct {
+	Code     string
+	Name     string
+	Severity string
+	Genetic  bool
+	Plan     string
+}{
+	{"J45", "Asthma", "MILD", false, "Inhaler as needed; avoid HVAC filter bays"},
+	{"I10", "Hypertension", "MODERATE", false, "Daily medication; monthly blood pressure check"},
+	{"E11", "Type 2 diabetes", "MODERATE", false, "Dietary ration adjustment; glucose checks"},
+	{"M54.5", "Chronic low back pain", "MILD", false, "Light duty restrictions; physiotherapy"},
+	{"F41.1", "Generalized anxiety disorder", "MILD", false, "Counseling with the vault psychologist"},
+	{"H52.1", "Myopia", "MILD", true, "Corrective lenses"},
+	{"D57.3", "Sickle cell trait", "MILD", true, "Genetic counseling before pairing"},
+	{"N18.3", "Chronic kidney disease", "SEVERE", false, "Restricted protein ration; quarterly labs"},
+}
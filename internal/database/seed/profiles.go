@@ -0,0 +1,110 @@
+package seed
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultProfile is the profile DefaultConfig generates.
+const DefaultProfile = "standard-500"
+
+// Profile is a named set of generation options describing a kind of vault.
+type Profile struct {
+	Name             string
+	Description      string
+	TargetPopulation int
+	FamilyHouseholds int
+	SingleHouseholds int
+	EmploymentRate   float64
+	ConditionRate    float64
+	HistoryDays      int
+	CheckupDays      int
+	FailureFactor    float64
+}
+
+// Profiles are the named generation profiles.
+var Profiles = []Profile{
+	{
+		Name:             "small-outpost",
+		Description:      "60 residents, nearly all employed, three months of history",
+		TargetPopulation: 60,
+		FamilyHouseholds: 10,
+		SingleHouseholds: 15,
+		EmploymentRate:   0.95,
+		ConditionRate:    0.10,
+		HistoryDays:      90,
+		FailureFactor:    1,
+	},
+	{
+		Name:             "standard-500",
+		Description:      "500 residents at designed capacity, six months of history",
+		TargetPopulation: 500,
+		FamilyHouseholds: 100,
+		SingleHouseholds: 80,
+		EmploymentRate:   0.85,
+		ConditionRate:    0.12,
+		HistoryDays:      180,
+		FailureFactor:    1,
+	},
+	{
+		Name:             "overcrowded-1000",
+		Description:      "1000 residents in a vault built for 500: jobs run short, illness spreads and systems fail twice as often",
+		TargetPopulation: 1000,
+		FamilyHouseholds: 220,
+		SingleHouseholds: 120,
+		EmploymentRate:   0.60,
+		ConditionRate:    0.20,
+		HistoryDays:      180,
+		FailureFactor:    2,
+	},
+	{
+		Name:             "control-vault-experiment",
+		Description:      "Vault-Tec control group: 500 residents, fully staffed, quarterly checkups and a year of well-kept history",
+		TargetPopulation: 500,
+		FamilyHouseholds: 100,
+		SingleHouseholds: 80,
+		EmploymentRate:   0.95,
+		ConditionRate:    0.12,
+		HistoryDays:      365,
+		CheckupDays:      90,
+		FailureFactor:    0.5,
+	},
+}
+
+// LookupProfile returns the profile with the given name.
+func LookupProfile(name string) (Profile, error) {
+	for _, p := range Profiles {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+	return Profile{}, fmt.Errorf("unknown seed profile %q (available: %s)", name, strings.Join(ProfileNames(), ", "))
+}
+
+// ProfileNames returns the names of the generation profiles.
+func ProfileNames() []string {
+	names := make([]string, len(Profiles))
+	for i, p := range Profiles {
+		names[i] = p.Name
+	}
+	return names
+}
+
+// Config returns the seed configuration of the profile for a vault. The vault
+// seals HistoryDays before start, so its generated history ends at start.
+func (p Profile) Config(vaultNumber int, start time.Time) Config {
+	return Config{
+		VaultNumber:      vaultNumber,
+		SealDate:         start.AddDate(0, 0, -p.HistoryDays),
+		TargetPopulation: p.TargetPopulation,
+		FamilyHouseholds: p.FamilyHouseholds,
+		SingleHouseholds: p.SingleHouseholds,
+		RandomSeed:       2077,
+		EmploymentRate:   p.EmploymentRate,
+		ConditionRate:    p.ConditionRate,
+		HistoryDays:      p.HistoryDays,
+		CheckupDays:      p.CheckupDays,
+		FailureFactor:    p.FailureFactor,
+	}
+}
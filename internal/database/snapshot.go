@@ -0,0 +1,186 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// snapshotNamePattern limits snapshot names to characters that are safe in
+// file names.
+var snapshotNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+// ErrSnapshotNotFound is returned when no snapshot has the requested name.
+var ErrSnapshotNotFound = errors.New("snapshot not found")
+
+// Snapshot is a named point-in-time copy of the vault database. Unlike
+// scheduled backups, snapshots are taken on request and are never pruned.
+type Snapshot struct {
+	Name          string    `json:"name"`
+	Note          string    `json:"note,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	VaultTime     time.Time `json:"vault_time"`
+	Population    int       `json:"population"`
+	SchemaVersion int       `json:"schema_version"`
+
+	Path      string `json:"-"` // Database file of the snapshot
+	SizeBytes int64  `json:"-"`
+}
+
+// SnapshotInfo is the vault state recorded with a new snapshot.
+type SnapshotInfo struct {
+	Name       string
+	Note       string
+	VaultTime  time.Time
+	Population int
+}
+
+// ValidSnapshotName reports whether name can be used for a snapshot.
+func ValidSnapshotName(name string) bool {
+	return snapshotNamePattern.MatchString(name)
+}
+
+// CreateSnapshot writes a consistent copy of the database to dir as
+// <name>.db, with its metadata alongside in <name>.json.
+func (db *DB) CreateSnapshot(ctx context.Context, dir string, info SnapshotInfo) (*Snapshot, error) {
+	if !ValidSnapshotName(info.Name) {
+		return nil, fmt.Errorf("invalid snapshot name %q: use letters, digits, '.', '_' and '-'", info.Name)
+	}
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, fmt.Errorf("creating snapshot directory: %w", err)
+	}
+
+	snap := &Snapshot{
+		Name:       info.Name,
+		Note:       info.Note,
+		CreatedAt:  time.Now().UTC(),
+		VaultTime:  info.VaultTime.UTC(),
+		Population: info.Population,
+		Path:       filepath.Join(dir, info.Name+".db"),
+	}
+	if _, err := os.Stat(snap.Path); err == nil {
+		return nil, fmt.Errorf("snapshot %s already exists", info.Name)
+	}
+
+	err := db.QueryRowContext(ctx,
+		"SELECT COALESCE(MAX(version), 0) FROM schema_migrations",
+	).Scan(&snap.SchemaVersion)
+	if err != nil {
+		return nil, fmt.Errorf("reading schema version: %w", err)
+	}
+
+	if err := db.vacuumInto(ctx, snap.Path); err != nil {
+		return nil, fmt.Errorf("creating snapshot: %w", err)
+	}
+
+	meta, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		os.Remove(snap.Path)
+		return nil, fmt.Errorf("encoding snapshot metadata: %w", err)
+	}
+	if err := os.WriteFile(snapshotMetaPath(dir, info.Name), meta, 0640); err != nil {
+		os.Remove(snap.Path)
+		return nil, fmt.Errorf("writing snapshot metadata: %w", err)
+	}
+
+	if stat, err := os.Stat(snap.Path); err == nil {
+		snap.SizeBytes = stat.Size()
+	}
+
+	slog.Info("database snapshot created",
+		"name", snap.Name,
+		"path", snap.Path,
+		"schema_version", snap.SchemaVersion,
+	)
+	return snap, nil
+}
+
+// ListSnapshots returns the snapshots in dir, oldest first. A missing
+// directory has no snapshots.
+func ListSnapshots(dir string) ([]*Snapshot, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading snapshot directory: %w", err)
+	}
+
+	var snaps []*Snapshot
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".json")
+		if entry.IsDir() || !ok {
+			continue
+		}
+		snap, err := GetSnapshot(dir, name)
+		if err != nil {
+			slog.Warn("skipping unreadable snapshot", "name", name, "error", err)
+			continue
+		}
+		snaps = append(snaps, snap)
+	}
+
+	sort.Slice(snaps, func(i, j int) bool {
+		return snaps[i].CreatedAt.Before(snaps[j].CreatedAt)
+	})
+	return snaps, nil
+}
+
+// GetSnapshot reads the metadata of the named snapshot in dir.
+func GetSnapshot(dir, name string) (*Snapshot, error) {
+	if !ValidSnapshotName(name) {
+		return nil, fmt.Errorf("%w: %s", ErrSnapshotNotFound, name)
+	}
+
+	meta, err := os.ReadFile(snapshotMetaPath(dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrSnapshotNotFound, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading snapshot metadata: %w", err)
+	}
+
+	snap := &Snapshot{}
+	if err := json.Unmarshal(meta, snap); err != nil {
+		return nil, fmt.Errorf("decoding snapshot metadata: %w", err)
+	}
+	snap.Path = filepath.Join(dir, name+".db")
+
+	stat, err := os.Stat(snap.Path)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot database: %w", err)
+	}
+	snap.SizeBytes = stat.Size()
+	return snap, nil
+}
+
+// RestoreSnapshot replaces the database at dbPath with the named snapshot,
+// preserving the current database as RestoreBackup does. The database must
+// be closed before calling this.
+func RestoreSnapshot(dbPath, dir, name string) (*Snapshot, string, error) {
+	snap, err := GetSnapshot(dir, name)
+	if err != nil {
+		return nil, "", err
+	}
+
+	preserved, err := RestoreBackup(dbPath, snap.Path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	slog.Info("database snapshot restored", "name", snap.Name, "preserved", preserved)
+	return snap, preserved, nil
+}
+
+// snapshotMetaPath returns the metadata file of the named snapshot.
+func snapshotMetaPath(dir, name string) string {
+	return filepath.Join(dir, name+".json")
+}
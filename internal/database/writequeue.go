@@ -0,0 +1,337 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"modernc.org/sqlite"
+)
+
+// slowWriteWait is how long a write may wait its turn before the wait is
+// logged.
+const slowWriteWait = time.Second
+
+// WriteQueueStats describes the writes waiting their turn on the database.
+type WriteQueueStats struct {
+	Depth     int           // Writes waiting now
+	MaxDepth  int           // Most writes ever waiting at once
+	Writing   bool          // Whether a write holds the database now
+	Writes    int64         // Writes run since the database opened
+	TotalWait time.Duration // Time writes have spent waiting
+	MaxWait   time.Duration // Longest any write waited
+}
+
+// writeQueue lets one write at a time run on the database, so that the
+// simulation, the TUI and the API take turns in the order they ask rather
+// than failing with SQLITE_BUSY. Reads do not queue: in WAL mode they see
+// the last commit while a write is under way.
+type writeQueue struct {
+	slot chan struct{} // Holds a token while a write runs
+
+	waiting  atomic.Int64
+	maxDepth atomic.Int64
+	writes   atomic.Int64
+	waited   atomic.Int64 // Nanoseconds
+	maxWait  atomic.Int64 // Nanoseconds
+}
+
+func newWriteQueue() *writeQueue {
+	return &writeQueue{slot: make(chan struct{}, 1)}
+}
+
+// acquire waits for the write's turn, or for ctx to end.
+func (q *writeQueue) acquire(ctx context.Context) error {
+	depth := q.waiting.Add(1)
+	raise(&q.maxDepth, depth)
+	start := time.Now()
+
+	select {
+	case q.slot <- struct{}{}:
+	case <-ctx.Done():
+		q.waiting.Add(-1)
+		return ctx.Err()
+	}
+	q.waiting.Add(-1)
+
+	wait := time.Since(start)
+	q.writes.Add(1)
+	q.waited.Add(int64(wait))
+	raise(&q.maxWait, int64(wait))
+	if wait >= slowWriteWait {
+		slog.Warn("database write waited for its turn", "wait", wait.Round(time.Millisecond), "queued", depth-1)
+	}
+	return nil
+}
+
+// release ends the write holding the database.
+func (q *writeQueue) release() {
+	<-q.slot
+}
+
+// stats returns the queue's metrics.
+func (q *writeQueue) stats() WriteQueueStats {
+	return WriteQueueStats{
+		Depth:     int(q.waiting.Load()),
+		MaxDepth:  int(q.maxDepth.Load()),
+		Writing:   len(q.slot) > 0,
+		Writes:    q.writes.Load(),
+		TotalWait: time.Duration(q.waited.Load()),
+		MaxWait:   time.Duration(q.maxWait.Load()),
+	}
+}
+
+// raise sets v to n if n is larger.
+func raise(v *atomic.Int64, n int64) {
+	for {
+		old := v.Load()
+		if n <= old || v.CompareAndSwap(old, n) {
+			return
+		}
+	}
+}
+
+// connector opens SQLite connections that run the connection pragmas when
+// they open, queue their writes on a shared writeQueue and time their
+// statements in a shared queryLog.
+type connector struct {
+	dsn     string
+	driver  *sqlite.Driver
+	queue   *writeQueue
+	log     *queryLog
+	pragmas []string // Run on every new connection
+}
+
+// Connect opens a connection.
+func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
+	inner, err := c.driver.Open(c.dsn)
+	if err != nil {
+		return nil, err
+	}
+	conn := &queuedConn{inner: inner, queue: c.queue, log: c.log}
+	// Connection pragmas change no data, so they need not wait their turn
+	for _, pragma := range c.pragmas {
+		if _, err := conn.conn().ExecContext(ctx, pragma, nil); err != nil {
+			inner.Close()
+			return nil, fmt.Errorf("running %s: %w", pragma, err)
+		}
+	}
+	return conn, nil
+}
+
+// Driver returns the SQLite driver.
+func (c *connector) Driver() driver.Driver {
+	return c.driver
+}
+
+// queuedConn is a connection whose transactions and statements outside a
+// transaction wait their turn on the write queue. Queries outside a
+// transaction run at once unless they write, as INSERT ... RETURNING does.
+// Every statement is timed, not counting its wait.
+type queuedConn struct {
+	inner driver.Conn
+	queue *writeQueue
+	log   *queryLog
+	inTx  bool // Holds the queue for a transaction
+}
+
+// conn returns the SQLite connection with its context methods.
+func (c *queuedConn) conn() interface {
+	driver.ConnBeginTx
+	driver.ConnPrepareContext
+	driver.ExecerContext
+	driver.QueryerContext
+	driver.Pinger
+} {
+	return c.inner.(interface {
+		driver.ConnBeginTx
+		driver.ConnPrepareContext
+		driver.ExecerContext
+		driver.QueryerContext
+		driver.Pinger
+	})
+}
+
+func (c *queuedConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *queuedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	stmt, err := c.conn().PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return &queuedStmt{Stmt: stmt, conn: c, query: query}, nil
+}
+
+func (c *queuedConn) Close() error {
+	if c.inTx {
+		c.inTx = false
+		c.queue.release()
+	}
+	return c.inner.Close()
+}
+
+func (c *queuedConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+// BeginTx waits for the transaction's turn and holds the queue until it
+// commits or rolls back.
+func (c *queuedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if err := c.queue.acquire(ctx); err != nil {
+		return nil, err
+	}
+	tx, err := c.conn().BeginTx(ctx, opts)
+	if err != nil {
+		c.queue.release()
+		return nil, err
+	}
+	c.inTx = true
+	return &queuedTx{Tx: tx, conn: c}, nil
+}
+
+// ExecContext runs a statement, waiting its turn outside a transaction.
+func (c *queuedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	release, err := c.turn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	defer c.timer(query, args)()
+	return c.conn().ExecContext(ctx, query, args)
+}
+
+// QueryContext runs a query, waiting its turn outside a transaction if it
+// writes. A write holds the queue until its rows are closed.
+func (c *queuedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	release, err := c.queryTurn(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	done := c.timer(query, args)
+	rows, err := c.conn().QueryContext(ctx, query, args)
+	if err != nil {
+		done()
+		release()
+		return nil, err
+	}
+	return &timedRows{Rows: rows, done: func() { done(); release() }}, nil
+}
+
+func (c *queuedConn) Ping(ctx context.Context) error {
+	return c.conn().Ping(ctx)
+}
+
+// turn waits for a write's turn unless the connection's transaction
+// already holds the queue, and returns the func that ends it.
+func (c *queuedConn) turn(ctx context.Context) (func(), error) {
+	if c.inTx {
+		return func() {}, nil
+	}
+	if err := c.queue.acquire(ctx); err != nil {
+		return nil, err
+	}
+	return c.queue.release, nil
+}
+
+// queryTurn is turn for a query: only a query that writes waits.
+func (c *queuedConn) queryTurn(ctx context.Context, query string) (func(), error) {
+	if !isWrite(query) {
+		return func() {}, nil
+	}
+	return c.turn(ctx)
+}
+
+// isWrite reports whether query changes data: it starts with INSERT,
+// UPDATE, DELETE or REPLACE, after any WITH clause, or returns what it
+// wrote with RETURNING.
+func isWrite(query string) bool {
+	words := sqlWords(query)
+	if len(words) == 0 {
+		return false
+	}
+	for i, w := range words {
+		switch w {
+		case "INSERT", "UPDATE", "DELETE", "REPLACE":
+			if i == 0 || words[0] == "WITH" {
+				return true
+			}
+		case "RETURNING":
+			return true
+		}
+	}
+	return false
+}
+
+// timer starts timing a run of query and returns the func that records it.
+func (c *queuedConn) timer(query string, args []driver.NamedValue) func() {
+	start := time.Now()
+	return func() { c.log.record(query, args, time.Since(start)) }
+}
+
+// queuedTx releases the write queue when its transaction ends.
+type queuedTx struct {
+	driver.Tx
+	conn *queuedConn
+}
+
+func (t *queuedTx) Commit() error {
+	defer t.end()
+	return t.Tx.Commit()
+}
+
+func (t *queuedTx) Rollback() error {
+	defer t.end()
+	return t.Tx.Rollback()
+}
+
+func (t *queuedTx) end() {
+	if t.conn.inTx {
+		t.conn.inTx = false
+		t.conn.queue.release()
+	}
+}
+
+// queuedStmt is a prepared statement whose executions outside a
+// transaction, and queries that write, wait their turn on the write queue.
+type queuedStmt struct {
+	driver.Stmt
+	conn  *queuedConn
+	query string
+}
+
+func (s *queuedStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	release, err := s.conn.turn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	defer s.conn.timer(s.query, args)()
+	return s.Stmt.(driver.StmtExecContext).ExecContext(ctx, args)
+}
+
+func (s *queuedStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	release, err := s.conn.queryTurn(ctx, s.query)
+	if err != nil {
+		return nil, err
+	}
+	done := s.conn.timer(s.query, args)
+	rows, err := s.Stmt.(driver.StmtQueryContext).QueryContext(ctx, args)
+	if err != nil {
+		done()
+		release()
+		return nil, err
+	}
+	return &timedRows{Rows: rows, done: func() { done(); release() }}, nil
+}
+
+// isMemory reports whether a database path names an in-memory database,
+// which each connection would open afresh.
+func isMemory(path string) bool {
+	return path == ":memory:" || strings.Contains(path, "mode=memory")
+}
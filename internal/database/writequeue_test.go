@@ -0,0 +1,184 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/config"
+)
+
+func TestWriteQueueSerializesWriters(t *testing.T) {
+	cfg := config.Default().Database
+	cfg.BackupIntervalHours = 0
+	cfg.BusyTimeoutMS = 0 // Any SQLITE_BUSY fails the test at once
+	db, err := Open(filepath.Join(t.TempDir(), "vault.db"), &cfg, "")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	if _, err := db.ExecContext(ctx, `CREATE TABLE log (writer INTEGER, n INTEGER)`); err != nil {
+		t.Fatal(err)
+	}
+
+	const writers, writes = 8, 25
+	var wg sync.WaitGroup
+	errs := make(chan error, writers*2)
+	for w := 0; w < writers; w++ {
+		wg.Add(2)
+		go func(w int) {
+			defer wg.Done()
+			for n := 0; n < writes; n++ {
+				err := db.WithTransaction(ctx, func(tx *sql.Tx) error {
+					var count int
+					if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM log`).Scan(&count); err != nil {
+						return err
+					}
+					time.Sleep(time.Millisecond) // Let the others queue
+					_, err := tx.ExecContext(ctx, `INSERT INTO log VALUES (?, ?)`, w, count)
+					return err
+				})
+				if err != nil {
+					errs <- fmt.Errorf("writer %d: %w", w, err)
+					return
+				}
+			}
+		}(w)
+		go func() {
+			defer wg.Done()
+			for n := 0; n < writes; n++ {
+				var count int
+				if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM log`).Scan(&count); err != nil {
+					errs <- fmt.Errorf("reader: %w", err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	// Each transaction saw every earlier commit, so the counts it read are
+	// all different
+	var rows, distinct int
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*), COUNT(DISTINCT n) FROM log`).Scan(&rows, &distinct); err != nil {
+		t.Fatal(err)
+	}
+	if rows != writers*writes || distinct != rows {
+		t.Errorf("rows = %d, distinct counts = %d, want %d", rows, distinct, writers*writes)
+	}
+
+	stats := db.WriteQueueStats()
+	if stats.Depth != 0 || stats.Writing {
+		t.Errorf("queue not drained: %+v", stats)
+	}
+	if stats.Writes < writers*writes {
+		t.Errorf("Writes = %d, want at least %d", stats.Writes, writers*writes)
+	}
+	if stats.MaxDepth < 2 {
+		t.Errorf("MaxDepth = %d, want writers to have queued", stats.MaxDepth)
+	}
+}
+
+func TestWriteQueueQueuesReturningWrites(t *testing.T) {
+	cfg := config.Default().Database
+	cfg.BackupIntervalHours = 0
+	cfg.BusyTimeoutMS = 0
+	db, err := Open(filepath.Join(t.TempDir(), "vault.db"), &cfg, "")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	if _, err := db.ExecContext(ctx, `CREATE TABLE counters (name TEXT PRIMARY KEY, n INTEGER NOT NULL)`); err != nil {
+		t.Fatal(err)
+	}
+
+	// Upserts that read back what they wrote, outside a transaction,
+	// alongside transactions that write the same row
+	const writers, writes = 8, 25
+	var wg sync.WaitGroup
+	errs := make(chan error, writers*2)
+	for w := 0; w < writers; w++ {
+		wg.Add(2)
+		go func(w int) {
+			defer wg.Done()
+			for n := 0; n < writes; n++ {
+				var got int
+				err := db.QueryRowContext(ctx, `
+					INSERT INTO counters (name, n) VALUES ('c', 1)
+					ON CONFLICT (name) DO UPDATE SET n = n + 1
+					RETURNING n`).Scan(&got)
+				if err != nil {
+					errs <- fmt.Errorf("upsert %d: %w", w, err)
+					return
+				}
+			}
+		}(w)
+		go func(w int) {
+			defer wg.Done()
+			for n := 0; n < writes; n++ {
+				err := db.WithTransaction(ctx, func(tx *sql.Tx) error {
+					time.Sleep(100 * time.Microsecond)
+					_, err := tx.ExecContext(ctx, `
+						INSERT INTO counters (name, n) VALUES ('c', 1)
+						ON CONFLICT (name) DO UPDATE SET n = n + 1`)
+					return err
+				})
+				if err != nil {
+					errs <- fmt.Errorf("transaction %d: %w", w, err)
+					return
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	var n int
+	if err := db.QueryRowContext(ctx, `SELECT n FROM counters WHERE name = 'c'`).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	if n != writers*writes*2 {
+		t.Errorf("n = %d, want %d", n, writers*writes*2)
+	}
+	if stats := db.WriteQueueStats(); stats.Depth != 0 || stats.Writing {
+		t.Errorf("queue not drained: %+v", stats)
+	}
+}
+
+func TestIsWrite(t *testing.T) {
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{`SELECT * FROM residents`, false},
+		{`  select id FROM residents WHERE status = 'UPDATE'`, false},
+		{`-- DELETE old rows
+		SELECT 1`, false},
+		{`INSERT INTO log VALUES (1, 2)`, true},
+		{`update log SET n = 1 RETURNING n`, true},
+		{`DELETE FROM log`, true},
+		{`REPLACE INTO log VALUES (1, 2)`, true},
+		{`WITH old AS (SELECT 1) DELETE FROM log WHERE n IN old`, true},
+		{`WITH recent AS (SELECT 1) SELECT * FROM recent`, false},
+	}
+	for _, tt := range tests {
+		if got := isWrite(tt.query); got != tt.want {
+			t.Errorf("isWrite(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+// Package dualauth holds operations for two-person authorization. A policy
+// names an operation, such as exiling a resident, that a second operator
+// must confirm with their PIN before it runs; the confirmation travels on
+// the context as a util.Countersign and is recorded in the audit log with
+// the change it confirmed.
+package dualauth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+var (
+	// ErrRequired is returned when an operation held by a policy runs
+	// without a countersign. Run it again with one.
+	ErrRequired = errors.New("two-person authorization required")
+
+	// ErrDenied is returned when a countersign does not confirm an
+	// operation: the PIN does not match, or an operator is not cleared.
+	ErrDenied = errors.New("two-person authorization refused")
+)
+
+// MinPINLength is the shortest PIN an operator may set.
+const MinPINLength = 6
+
+// pinIterations is the number of PBKDF2 rounds a PIN is hashed with.
+const pinIterations = 100_000
+
+// Authorizer checks operations against the two-person authorization
+// policies and records the confirmations.
+type Authorizer struct {
+	policies    *repository.DualAuthRepository
+	residents   *repository.ResidentRepository
+	audit       *repository.AuditRepository
+	idGenerator *util.IDGenerator
+}
+
+// New creates an authorizer covering every vault.
+func New(db *sql.DB) *Authorizer {
+	return &Authorizer{
+		policies:    repository.NewDualAuthRepository(db),
+		residents:   repository.NewResidentRepository(db),
+		audit:       repository.NewAuditRepository(db),
+		idGenerator: util.NewIDGenerator(),
+	}
+}
+
+// ForVault returns a copy of the authorizer applying the policies of the
+// vault, to operations confirmed by its residents.
+func (a *Authorizer) ForVault(vault int) *Authorizer {
+	scoped := *a
+	scoped.policies = a.policies.ForVault(vault)
+	scoped.residents = a.residents.ForVault(vault)
+	return &scoped
+}
+
+// Authorization is a confirmed operation, to be recorded with Record.
+type Authorization struct {
+	Policy    *models.DualAuthPolicy
+	Requester *models.Resident
+	Confirmer *models.Resident
+	Amount    float64
+}
+
+// Authorize checks an operation on amount, the units written off for a
+// stock write-off and 0 otherwise, against its policy. It returns nil when
+// no policy holds the operation, and otherwise the confirmation of the
+// context's countersign: two different active operators, both holding the
+// policy's clearance, the second giving their PIN. A replayed countersign
+// was confirmed when recorded and its PIN is not checked again. It reads
+// within tx if given, so that it can be called from the transaction
+// making the change.
+func (a *Authorizer) Authorize(ctx context.Context, tx *sql.Tx, op models.DualAuthOperation, amount float64) (*Authorization, error) {
+	policy, err := a.policies.GetPolicy(ctx, tx, op)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !policy.Applies(amount) {
+		return nil, nil
+	}
+
+	cs, ok := util.CountersignOf(ctx)
+	if !ok || cs.By == "" {
+		return nil, fmt.Errorf("%w: %w: %s needs a second operator of clearance %d or above",
+			repository.ErrValidation, ErrRequired, describe(op), policy.MinClearance)
+	}
+	if cs.RequestedBy == "" {
+		return nil, fmt.Errorf("%w: %w: the operator making the change must be named",
+			repository.ErrValidation, ErrRequired)
+	}
+	if strings.EqualFold(cs.RequestedBy, cs.By) {
+		return nil, fmt.Errorf("%w: %w: the confirming operator must be someone else",
+			repository.ErrValidation, ErrDenied)
+	}
+
+	requester, err := a.operator(ctx, tx, cs.RequestedBy, policy.MinClearance)
+	if err != nil {
+		return nil, err
+	}
+	confirmer, err := a.operator(ctx, tx, cs.By, policy.MinClearance)
+	if err != nil {
+		return nil, err
+	}
+	if !cs.Replayed {
+		if err := a.checkPIN(ctx, tx, confirmer, cs.PIN); err != nil {
+			return nil, err
+		}
+	}
+	return &Authorization{Policy: policy, Requester: requester, Confirmer: confirmer, Amount: amount}, nil
+}
+
+// operator retrieves an operator of a countersign by registry number,
+// checking they are active and hold clearance.
+func (a *Authorizer) operator(ctx context.Context, tx *sql.Tx, registryNumber string, clearance int) (*models.Resident, error) {
+	r, err := a.residents.GetByRegistryNumber(ctx, tx, strings.TrimSpace(registryNumber))
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %w: no operator %s", repository.ErrValidation, ErrDenied, registryNumber)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if r.Status != models.ResidentStatusActive {
+		return nil, fmt.Errorf("%w: %w: operator %s is %s", repository.ErrValidation, ErrDenied, r.RegistryNumber, r.Status)
+	}
+	if r.ClearanceLevel < clearance {
+		return nil, fmt.Errorf("%w: %w: operator %s holds clearance %d, %d needed",
+			repository.ErrValidation, ErrDenied, r.RegistryNumber, r.ClearanceLevel, clearance)
+	}
+	return r, nil
+}
+
+// checkPIN checks a PIN against the operator's.
+func (a *Authorizer) checkPIN(ctx context.Context, tx *sql.Tx, r *models.Resident, pin string) error {
+	hash, salt, err := a.policies.GetPIN(ctx, tx, r.ID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("%w: %w: operator %s has no PIN set", repository.ErrValidation, ErrDenied, r.RegistryNumber)
+	}
+	if err != nil {
+		return err
+	}
+	saltBytes, err := hex.DecodeString(salt)
+	if err != nil {
+		return fmt.Errorf("decoding PIN salt of %s: %w", r.RegistryNumber, err)
+	}
+	if subtle.ConstantTimeCompare([]byte(hashPIN(pin, saltBytes)), []byte(hash)) != 1 {
+		return fmt.Errorf("%w: %w: PIN does not match", repository.ErrValidation, ErrDenied)
+	}
+	return nil
+}
+
+// Record writes the confirmation of an operation on an entity to the audit
+// log within tx, the requesting operator as its actor. A nil authorization,
+// for an operation no policy held, records nothing.
+func (a *Authorizer) Record(ctx context.Context, tx *sql.Tx, auth *Authorization, entityType, entityID string, at time.Time) error {
+	if auth == nil {
+		return nil
+	}
+	values, err := json.Marshal(models.DualAuthValues{
+		Operation:   auth.Policy.Operation,
+		RequestedBy: auth.Requester.RegistryNumber,
+		ConfirmedBy: auth.Confirmer.RegistryNumber,
+		Amount:      auth.Amount,
+	})
+	if err != nil {
+		return fmt.Errorf("encoding authorization: %w", err)
+	}
+	entry := &models.AuditEntry{
+		ID:         a.idGenerator.NewID(),
+		Timestamp:  at,
+		ActorType:  models.AuditActorUser,
+		ActorID:    &auth.Requester.ID,
+		Action:     models.AuditActionDualAuthorization,
+		EntityType: entityType,
+		EntityID:   entityID,
+		NewValues:  string(values),
+	}
+	if err := a.audit.Create(ctx, tx, entry); err != nil {
+		return fmt.Errorf("auditing authorization: %w", err)
+	}
+	return nil
+}
+
+// SetPIN sets the PIN an operator confirms operations with, replacing any
+// earlier one.
+func (a *Authorizer) SetPIN(ctx context.Context, registryNumber, pin string) error {
+	if len(pin) < MinPINLength {
+		return fmt.Errorf("%w: PIN must be at least %d characters", repository.ErrValidation, MinPINLength)
+	}
+	r, err := a.residents.GetByRegistryNumber(ctx, nil, strings.TrimSpace(registryNumber))
+	if err != nil {
+		return fmt.Errorf("getting operator %s: %w", registryNumber, err)
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return fmt.Errorf("generating PIN salt: %w", err)
+	}
+	return a.policies.SetPIN(ctx, r.ID, hashPIN(pin, salt), hex.EncodeToString(salt))
+}
+
+// hashPIN derives the stored hash of a PIN, PBKDF2-HMAC-SHA256 of one
+// block, in hex.
+func hashPIN(pin string, salt []byte) string {
+	mac := hmac.New(sha256.New, []byte(pin))
+	mac.Write(salt)
+	mac.Write(binary.BigEndian.AppendUint32(nil, 1))
+	u := mac.Sum(nil)
+	out := append([]byte(nil), u...)
+	for i := 1; i < pinIterations; i++ {
+		mac.Reset()
+		mac.Write(u)
+		u = mac.Sum(u[:0])
+		for j := range out {
+			out[j] ^= u[j]
+		}
+	}
+	return hex.EncodeToString(out)
+}
+
+// describe names an operation in an error message.
+func describe(op models.DualAuthOperation) string {
+	switch op {
+	case models.DualAuthExile:
+		return "exile"
+	case models.DualAuthDissolveHousehold:
+		return "dissolving a household"
+	case models.DualAuthStockWriteOff:
+		return "this write-off"
+	default:
+		return string(op)
+	}
+}
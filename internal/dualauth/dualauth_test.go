@@ -0,0 +1,96 @@
+package dualauth
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/testutil"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+func TestAuthorize(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	defer db.Close(t)
+	db.RunMigrations(t, filepath.Join("..", "database", "migrations"))
+	ctx := context.Background()
+
+	residents := repository.NewResidentRepository(db.DB)
+	requester := testutil.FixtureResident(func(r *models.Resident) { r.ClearanceLevel = 6 })
+	confirmer := testutil.FixtureResident(func(r *models.Resident) { r.ClearanceLevel = 7 })
+	junior := testutil.FixtureResident(func(r *models.Resident) { r.ClearanceLevel = 2 })
+	for _, r := range []*models.Resident{requester, confirmer, junior} {
+		if err := residents.Create(ctx, nil, r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	a := New(db.DB)
+	if err := a.SetPIN(ctx, confirmer.RegistryNumber, "12345"); !errors.Is(err, repository.ErrValidation) {
+		t.Errorf("SetPIN of a short PIN: err = %v, want ErrValidation", err)
+	}
+	for _, r := range []*models.Resident{confirmer, junior} {
+		if err := a.SetPIN(ctx, r.RegistryNumber, "246810"); err != nil {
+			t.Fatalf("SetPIN: %v", err)
+		}
+	}
+
+	// Without a policy nothing is held
+	if auth, err := a.Authorize(ctx, nil, models.DualAuthStockWriteOff, 500); auth != nil || err != nil {
+		t.Fatalf("Authorize without a policy = %v, %v", auth, err)
+	}
+	policies := repository.NewDualAuthRepository(db.DB)
+	if err := policies.SetPolicy(ctx, &models.DualAuthPolicy{Operation: models.DualAuthStockWriteOff, MinClearance: 5, Threshold: 100}); err != nil {
+		t.Fatalf("SetPolicy: %v", err)
+	}
+	if auth, err := a.Authorize(ctx, nil, models.DualAuthStockWriteOff, 40); auth != nil || err != nil {
+		t.Errorf("Authorize below the threshold = %v, %v", auth, err)
+	}
+	if _, err := a.Authorize(ctx, nil, models.DualAuthStockWriteOff, 150); !errors.Is(err, ErrRequired) {
+		t.Errorf("Authorize without a countersign: err = %v, want ErrRequired", err)
+	}
+
+	sign := func(by, pin string) context.Context {
+		return util.WithCountersign(ctx, util.Countersign{RequestedBy: requester.RegistryNumber, By: by, PIN: pin})
+	}
+	for name, c := range map[string]context.Context{
+		"wrong PIN":         sign(confirmer.RegistryNumber, "135790"),
+		"same operator":     sign(requester.RegistryNumber, "246810"),
+		"without clearance": sign(junior.RegistryNumber, "246810"),
+		"unknown operator":  sign("VT-076-NOBODY", "246810"),
+	} {
+		if _, err := a.Authorize(c, nil, models.DualAuthStockWriteOff, 150); !errors.Is(err, ErrDenied) {
+			t.Errorf("Authorize with %s: err = %v, want ErrDenied", name, err)
+		}
+	}
+
+	auth, err := a.Authorize(sign(confirmer.RegistryNumber, "246810"), nil, models.DualAuthStockWriteOff, 150)
+	if err != nil || auth == nil || auth.Confirmer.ID != confirmer.ID {
+		t.Fatalf("Authorize = %+v, %v", auth, err)
+	}
+	at := time.Date(2078, 3, 1, 8, 0, 0, 0, time.UTC)
+	if err := a.Record(ctx, nil, auth, models.AuditEntityStock, "stock-1", at); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	entries, err := repository.NewAuditRepository(db.DB).ListBetween(ctx, at, at.Add(time.Second), models.AuditActionDualAuthorization)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("audit entries = %v, %v", entries, err)
+	}
+	var values models.DualAuthValues
+	if err := entries[0].DecodeValues(&struct{}{}, &values); err != nil {
+		t.Fatal(err)
+	}
+	if values.RequestedBy != requester.RegistryNumber || values.ConfirmedBy != confirmer.RegistryNumber || values.Amount != 150 {
+		t.Errorf("audited values = %+v", values)
+	}
+
+	// A replayed countersign was confirmed when recorded
+	replayed := util.WithCountersign(ctx, util.Countersign{RequestedBy: requester.RegistryNumber, By: confirmer.RegistryNumber, Replayed: true})
+	if _, err := a.Authorize(replayed, nil, models.DualAuthStockWriteOff, 150); err != nil {
+		t.Errorf("Authorize of a replayed countersign: %v", err)
+	}
+}
@@ -0,0 +1,196 @@
+// Package events forwards what happens in the vault, such as residents
+// created, stock running out and systems failing, to sinks outside it: a
+// log file, a UNIX socket or an HTTP webhook, for external monitoring to
+// react to.
+package events
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/journal"
+)
+
+// Event types.
+const (
+	ResidentCreated = "resident.created"
+	StockDepleted   = "stock.depleted"
+	SystemFailed    = "system.failed"
+)
+
+// Types are the event types published, in the order documented.
+var Types = []string{ResidentCreated, StockDepleted, SystemFailed}
+
+// DefaultBuffer is how many events a bus holds for its sinks by default.
+const DefaultBuffer = 256
+
+// Event is something that happened in a vault.
+type Event struct {
+	Seq     int64          `json:"seq"`  // Order published since the terminal started
+	Type    string         `json:"type"` // e.g. "stock.depleted"
+	Time    time.Time      `json:"time"` // Vault time
+	Vault   int            `json:"vault"`
+	Subject string         `json:"subject"` // ID of the record it concerns
+	Data    map[string]any `json:"data,omitempty"`
+}
+
+// Match reports whether an event type matches any of the patterns: a type,
+// a prefix ending in ".*", e.g. "stock.*", or "*". No patterns match every
+// type.
+func Match(patterns []string, typ string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, p := range patterns {
+		switch {
+		case p == "*", p == typ:
+			return true
+		case strings.HasSuffix(p, ".*") && strings.HasPrefix(typ, strings.TrimSuffix(p, "*")):
+			return true
+		}
+	}
+	return false
+}
+
+// Route sends the events of the matching types to a sink.
+type Route struct {
+	Sink  Sink
+	Types []string // Patterns as for Match; empty for every type
+}
+
+// route is a route with its delivery state.
+type route struct {
+	Route
+	failing bool // Last delivery failed
+	lost    int  // Events not delivered since
+}
+
+// Bus delivers published events to the sinks of its routes in the order
+// published. Publishing never waits on a sink: a bus holds a buffer of
+// events, and drops new ones while it is full. A sink that fails loses the
+// event; the failure is logged once until the sink recovers. A nil bus
+// publishes nothing.
+type Bus struct {
+	routes []*route
+	queue  chan Event
+	done   chan struct{}
+
+	mu      sync.Mutex
+	closed  bool
+	seq     int64
+	dropped atomic.Int64
+}
+
+// NewBus starts a bus delivering to the routes, holding up to buffer
+// events for them.
+func NewBus(buffer int, routes ...Route) *Bus {
+	b := &Bus{
+		queue: make(chan Event, max(buffer, 1)),
+		done:  make(chan struct{}),
+	}
+	for _, r := range routes {
+		b.routes = append(b.routes, &route{Route: r})
+	}
+	go b.run()
+	return b
+}
+
+// Publish queues an event for delivery, numbering it.
+func (b *Bus) Publish(e Event) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	b.seq++
+	e.Seq = b.seq
+	select {
+	case b.queue <- e:
+	default:
+		if b.dropped.Add(1) == 1 {
+			slog.Warn("event buffer full, dropping events", "type", e.Type, "seq", e.Seq)
+		}
+	}
+}
+
+// Emit publishes an event once the journaled command running in ctx ends
+// without error, so that no event announces a change rolled back. Outside
+// a command it publishes at once.
+func (b *Bus) Emit(ctx context.Context, e Event) {
+	if b == nil {
+		return
+	}
+	journal.OnSuccess(ctx, func() { b.Publish(e) })
+}
+
+// Dropped returns how many events were dropped as the buffer was full.
+func (b *Bus) Dropped() int64 {
+	if b == nil {
+		return 0
+	}
+	return b.dropped.Load()
+}
+
+// run delivers queued events until the bus is closed.
+func (b *Bus) run() {
+	defer close(b.done)
+	for e := range b.queue {
+		for _, r := range b.routes {
+			if Match(r.Types, e.Type) {
+				b.deliver(r, e)
+			}
+		}
+	}
+}
+
+// deliver sends an event to a route's sink, logging when the sink starts
+// failing and when it recovers.
+func (b *Bus) deliver(r *route, e Event) {
+	err := r.Sink.Send(e)
+	switch {
+	case err != nil && !r.failing:
+		slog.Warn("event sink failed", "sink", r.Sink.String(), "type", e.Type, "seq", e.Seq, "error", err)
+		r.failing, r.lost = true, 1
+	case err != nil:
+		r.lost++
+	case r.failing:
+		slog.Info("event sink recovered", "sink", r.Sink.String(), "lost", r.lost)
+		r.failing, r.lost = false, 0
+	}
+}
+
+// Close stops the bus accepting events, delivers those queued and closes
+// the sinks.
+func (b *Bus) Close() error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	close(b.queue)
+	b.mu.Unlock()
+
+	<-b.done
+	if n := b.dropped.Load(); n > 0 {
+		slog.Warn("events dropped while the buffer was full", "count", n)
+	}
+	var errs []error
+	for _, r := range b.routes {
+		if err := r.Sink.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
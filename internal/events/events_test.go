@@ -0,0 +1,227 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/journal"
+)
+
+// memorySink records the events sent to it, failing while fail is set.
+type memorySink struct {
+	mu     sync.Mutex
+	events []Event
+	fail   bool
+	closed bool
+}
+
+func (s *memorySink) Send(e Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fail {
+		return errors.New("unavailable")
+	}
+	s.events = append(s.events, e)
+	return nil
+}
+
+func (s *memorySink) Close() error {
+	s.closed = true
+	return nil
+}
+
+func (s *memorySink) String() string { return "memory" }
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		patterns []string
+		typ      string
+		want     bool
+	}{
+		{nil, StockDepleted, true},
+		{[]string{"*"}, SystemFailed, true},
+		{[]string{StockDepleted}, StockDepleted, true},
+		{[]string{"stock.*"}, StockDepleted, true},
+		{[]string{"stock.*"}, "stockpile.low", false},
+		{[]string{"resident.*", SystemFailed}, StockDepleted, false},
+	}
+	for _, tt := range tests {
+		if got := Match(tt.patterns, tt.typ); got != tt.want {
+			t.Errorf("Match(%v, %s) = %v, want %v", tt.patterns, tt.typ, got, tt.want)
+		}
+	}
+}
+
+func TestBusRoutes(t *testing.T) {
+	all, stock := &memorySink{}, &memorySink{}
+	bus := NewBus(16, Route{Sink: all}, Route{Sink: stock, Types: []string{"stock.*"}})
+	bus.Publish(Event{Type: ResidentCreated, Subject: "r1"})
+	bus.Publish(Event{Type: StockDepleted, Subject: "s1"})
+	if err := bus.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	bus.Publish(Event{Type: SystemFailed})
+
+	if len(all.events) != 2 || all.events[0].Seq != 1 || all.events[1].Seq != 2 {
+		t.Errorf("all received %+v, want events 1 and 2", all.events)
+	}
+	if len(stock.events) != 1 || stock.events[0].Subject != "s1" {
+		t.Errorf("stock received %+v, want the depleted stock only", stock.events)
+	}
+	if !all.closed || !stock.closed {
+		t.Error("Close() left sinks open")
+	}
+}
+
+func TestBusFailingSink(t *testing.T) {
+	failing, ok := &memorySink{fail: true}, &memorySink{}
+	bus := NewBus(16, Route{Sink: failing}, Route{Sink: ok})
+	bus.Publish(Event{Type: SystemFailed})
+	bus.Close()
+	if len(ok.events) != 1 {
+		t.Errorf("healthy sink received %d events, want 1 despite the other failing", len(ok.events))
+	}
+}
+
+func TestBusEmit(t *testing.T) {
+	sink := &memorySink{}
+	bus := NewBus(16, Route{Sink: sink})
+
+	var j *journal.Journal
+	for _, fail := range []bool{true, false} {
+		ctx, cmd := j.Begin(context.Background(), 76, "test.command", nil)
+		bus.Emit(ctx, Event{Type: StockDepleted, Subject: "s1"})
+		var err error
+		if fail {
+			err = errors.New("rolled back")
+		}
+		cmd.End(err)
+	}
+	bus.Close()
+
+	if len(sink.events) != 1 {
+		t.Errorf("sink received %d events, want 1 from the command that succeeded", len(sink.events))
+	}
+
+	var nilBus *Bus
+	nilBus.Emit(context.Background(), Event{Type: StockDepleted})
+	if err := nilBus.Close(); err != nil {
+		t.Errorf("nil Close() error = %v", err)
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	sink, err := NewFileSink(path)
+	if err != nil {
+		t.Fatalf("NewFileSink() error = %v", err)
+	}
+	bus := NewBus(4, Route{Sink: sink})
+	bus.Publish(Event{Type: ResidentCreated, Subject: "r1", Data: map[string]any{"registry_number": "V076-00501"}})
+	bus.Publish(Event{Type: SystemFailed, Subject: "sys1"})
+	bus.Close()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var got []Event
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("decoding %q: %v", scanner.Text(), err)
+		}
+		got = append(got, e)
+	}
+	if len(got) != 2 || got[0].Data["registry_number"] != "V076-00501" || got[1].Type != SystemFailed {
+		t.Errorf("file holds %+v", got)
+	}
+}
+
+func TestSocketSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "relay.sock")
+	sink := NewSocketSink(path, time.Second)
+	defer sink.Close()
+
+	if err := sink.Send(Event{Type: StockDepleted}); err == nil {
+		t.Error("Send() with no listener succeeded")
+	}
+
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Skipf("UNIX sockets unavailable: %v", err)
+	}
+	defer ln.Close()
+	received := make(chan Event, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		var e Event
+		if json.NewDecoder(conn).Decode(&e) == nil {
+			received <- e
+		}
+	}()
+
+	if err := sink.Send(Event{Type: StockDepleted, Subject: "s1"}); err != nil {
+		t.Fatalf("Send() once listening error = %v", err)
+	}
+	select {
+	case e := <-received:
+		if e.Subject != "s1" {
+			t.Errorf("relay received %+v", e)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("relay received nothing")
+	}
+}
+
+func TestWebhookSink(t *testing.T) {
+	secret := "relay-secret"
+	var got Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get(HeaderSignature) != Sign([]byte(secret), body) {
+			http.Error(w, "bad signature", http.StatusUnauthorized)
+			return
+		}
+		if r.Header.Get(HeaderEvent) != SystemFailed {
+			http.Error(w, "bad type", http.StatusBadRequest)
+			return
+		}
+		json.Unmarshal(body, &got)
+	}))
+	defer server.Close()
+
+	sink := NewWebhookSink(server.URL, secret, time.Second)
+	if err := sink.Send(Event{Type: SystemFailed, Subject: "sys1"}); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if got.Subject != "sys1" {
+		t.Errorf("webhook received %+v", got)
+	}
+
+	unsigned := NewWebhookSink(server.URL, "wrong-secret", time.Second)
+	if err := unsigned.Send(Event{Type: SystemFailed}); err == nil {
+		t.Error("Send() with the wrong secret succeeded")
+	}
+
+	if s := NewWebhookSink("https://relay.example/hook?token=abc", "", 0).String(); s != "webhook https://relay.example/hook" {
+		t.Errorf("String() = %q, want the URL without its token", s)
+	}
+}
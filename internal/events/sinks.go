@@ -0,0 +1,202 @@
+package events
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+)
+
+// DefaultTimeout bounds a webhook call or socket write when none is set.
+const DefaultTimeout = 5 * time.Second
+
+// Sink is a destination of events. The bus sends it one event at a time.
+type Sink interface {
+	Send(e Event) error
+	Close() error
+	String() string // Describes the sink in logs
+}
+
+// FileSink appends events to a file, one JSON object per line.
+type FileSink struct {
+	path string
+	f    *os.File
+}
+
+// NewFileSink opens the file at path for appending, creating it if needed.
+func NewFileSink(path string) (*FileSink, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o640)
+	if err != nil {
+		return nil, fmt.Errorf("opening event log: %w", err)
+	}
+	return &FileSink{path: path, f: f}, nil
+}
+
+// Send appends the event to the file.
+func (s *FileSink) Send(e Event) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = s.f.Write(append(line, '\n'))
+	return err
+}
+
+// Close closes the file.
+func (s *FileSink) Close() error {
+	return s.f.Close()
+}
+
+func (s *FileSink) String() string {
+	return "file " + s.path
+}
+
+// SocketSink writes events, one JSON object per line, to a UNIX socket a
+// listener such as a Pip-Boy relay holds open. It connects on the first
+// event, and again on the next event after the connection fails, so the
+// listener can start and restart at any time.
+type SocketSink struct {
+	path    string
+	timeout time.Duration
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// NewSocketSink creates a sink writing to the UNIX socket at path, each
+// connection and write bounded by timeout (DefaultTimeout if 0).
+func NewSocketSink(path string, timeout time.Duration) *SocketSink {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &SocketSink{path: path, timeout: timeout}
+}
+
+// Send writes the event to the socket, connecting first if needed.
+func (s *SocketSink) Send(e Event) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		conn, err := net.DialTimeout("unix", s.path, s.timeout)
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+	}
+	err = s.conn.SetWriteDeadline(time.Now().Add(s.timeout))
+	if err == nil {
+		_, err = s.conn.Write(append(line, '\n'))
+	}
+	if err != nil {
+		s.conn.Close()
+		s.conn = nil
+	}
+	return err
+}
+
+// Close closes the connection, if open.
+func (s *SocketSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+func (s *SocketSink) String() string {
+	return "socket " + s.path
+}
+
+// Webhook request headers.
+const (
+	HeaderEvent     = "X-Vtuos-Event"     // Event type
+	HeaderSignature = "X-Vtuos-Signature" // "sha256=" and the hex HMAC-SHA256 of the body
+)
+
+// WebhookSink POSTs each event as JSON to a URL. With a secret, each
+// request carries the HMAC-SHA256 of its body, keyed with the secret, so
+// the receiver can check it came from the vault. A response other than 2xx
+// fails the event.
+type WebhookSink struct {
+	url    string
+	secret []byte
+	client *http.Client
+}
+
+// NewWebhookSink creates a sink posting to the target URL, each request
+// bounded by timeout (DefaultTimeout if 0). An empty secret leaves requests
+// unsigned.
+func NewWebhookSink(target, secret string, timeout time.Duration) *WebhookSink {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	s := &WebhookSink{url: target, client: &http.Client{Timeout: timeout}}
+	if secret != "" {
+		s.secret = []byte(secret)
+	}
+	return s
+}
+
+// Send posts the event.
+func (s *WebhookSink) Send(e Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, e.Type)
+	if s.secret != nil {
+		req.Header.Set(HeaderSignature, Sign(s.secret, body))
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// Close releases idle connections.
+func (s *WebhookSink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
+
+// String describes the webhook without its query, which may hold a token.
+func (s *WebhookSink) String() string {
+	u, err := url.Parse(s.url)
+	if err != nil {
+		return "webhook"
+	}
+	u.RawQuery, u.User = "", nil
+	return "webhook " + u.String()
+}
+
+// Sign returns the signature header value of a webhook body.
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
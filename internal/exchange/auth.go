@@ -0,0 +1,78 @@
+package exchange
+
+import (
+	"context"
+	"crypto/subtle"
+	"log/slog"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	exchangev1 "github.com/vtuos/vtuos/api/vtuos/exchange/v1"
+	"github.com/vtuos/vtuos/internal/config"
+)
+
+// methodClearance is the clearance a peer needs for each method. Census
+// counts are the least sensitive; facility status shows where the vault is
+// weak. Methods not listed are refused.
+var methodClearance = map[string]int{
+	exchangev1.ExchangeService_GetCensusSummary_FullMethodName:  2,
+	exchangev1.ExchangeService_ListInventory_FullMethodName:     3,
+	exchangev1.ExchangeService_GetFacilityStatus_FullMethodName: 4,
+}
+
+// clearanceInterceptor authenticates each call by its peer token and checks
+// the peer's clearance against the method's.
+func clearanceInterceptor(peers []config.GRPCPeer) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		required, ok := methodClearance[info.FullMethod]
+		if !ok {
+			return nil, status.Errorf(codes.PermissionDenied, "method %s is not exposed", info.FullMethod)
+		}
+
+		peer, ok := authenticate(ctx, peers)
+		if !ok {
+			slog.Warn("exchange call rejected", "method", info.FullMethod, "reason", "unknown token")
+			return nil, status.Error(codes.Unauthenticated, "missing or unknown peer token")
+		}
+		if peer.Clearance < required {
+			slog.Warn("exchange call denied",
+				"peer", peer.Name,
+				"method", info.FullMethod,
+				"clearance", peer.Clearance,
+				"required", required,
+			)
+			return nil, status.Errorf(codes.PermissionDenied,
+				"%s requires clearance %d, peer %s has %d", info.FullMethod, required, peer.Name, peer.Clearance)
+		}
+
+		slog.Debug("exchange call", "peer", peer.Name, "method", info.FullMethod)
+		return handler(ctx, req)
+	}
+}
+
+// authenticate finds the peer whose token is in the call's authorization
+// metadata, as "Bearer <token>". Every token is compared in constant time.
+func authenticate(ctx context.Context, peers []config.GRPCPeer) (config.GRPCPeer, bool) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return config.GRPCPeer{}, false
+	}
+	token, ok := strings.CutPrefix(values[0], "Bearer ")
+	if !ok || token == "" {
+		return config.GRPCPeer{}, false
+	}
+
+	var found config.GRPCPeer
+	var match bool
+	for _, p := range peers {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(p.Token)) == 1 {
+			found, match = p, true
+		}
+	}
+	return found, match
+}
@@ -0,0 +1,69 @@
+package exchange
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	exchangev1 "github.com/vtuos/vtuos/api/vtuos/exchange/v1"
+	"github.com/vtuos/vtuos/internal/config"
+)
+
+func TestClearanceInterceptor(t *testing.T) {
+	peers := []config.GRPCPeer{
+		{Name: "vault-081", Token: "token-081", Clearance: 3},
+		{Name: "vault-101", Token: "token-101", Clearance: 5},
+	}
+	interceptor := clearanceInterceptor(peers)
+
+	tests := []struct {
+		name     string
+		method   string
+		auth     string // Authorization metadata, none if empty
+		wantCode codes.Code
+	}{
+		{"Cleared peer", exchangev1.ExchangeService_ListInventory_FullMethodName, "Bearer token-081", codes.OK},
+		{"Peer above the minimum", exchangev1.ExchangeService_GetFacilityStatus_FullMethodName, "Bearer token-101", codes.OK},
+		{"Missing token", exchangev1.ExchangeService_GetCensusSummary_FullMethodName, "", codes.Unauthenticated},
+		{"Unknown token", exchangev1.ExchangeService_GetCensusSummary_FullMethodName, "Bearer token-999", codes.Unauthenticated},
+		{"Token without Bearer", exchangev1.ExchangeService_GetCensusSummary_FullMethodName, "token-081", codes.Unauthenticated},
+		{"Empty token", exchangev1.ExchangeService_GetCensusSummary_FullMethodName, "Bearer ", codes.Unauthenticated},
+		{"Clearance below the minimum", exchangev1.ExchangeService_GetFacilityStatus_FullMethodName, "Bearer token-081", codes.PermissionDenied},
+		{"Method not listed", "/vtuos.exchange.v1.ExchangeService/DeleteResidents", "Bearer token-101", codes.PermissionDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.auth != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", tt.auth))
+			}
+			called := false
+			handler := func(ctx context.Context, req any) (any, error) {
+				called = true
+				return "ok", nil
+			}
+
+			_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+			if got := status.Code(err); got != tt.wantCode {
+				t.Errorf("code = %v, want %v (err %v)", got, tt.wantCode, err)
+			}
+			if want := tt.wantCode == codes.OK; called != want {
+				t.Errorf("handler called = %v, want %v", called, want)
+			}
+		})
+	}
+}
+
+func TestMethodClearance_CoversService(t *testing.T) {
+	for _, method := range exchangev1.ExchangeService_ServiceDesc.Methods {
+		name := "/" + exchangev1.ExchangeService_ServiceDesc.ServiceName + "/" + method.MethodName
+		if _, ok := methodClearance[name]; !ok {
+			t.Errorf("method %s has no clearance and is refused to every peer", name)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package exchange
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	exchangev1 "github.com/vtuos/vtuos/api/vtuos/exchange/v1"
+	"github.com/vtuos/vtuos/internal/config"
+)
+
+// Serve runs the exchange server until ctx is cancelled, then stops it
+// gracefully, letting in-flight calls finish.
+func Serve(ctx context.Context, cfg config.GRPCConfig, db *sql.DB, vaultNumber int) error {
+	if len(cfg.Peers) == 0 {
+		return errors.New("no peers configured; add [[grpc.peers]] entries")
+	}
+
+	creds, err := serverCredentials(cfg)
+	if err != nil {
+		return err
+	}
+
+	lis, err := net.Listen("tcp", cfg.Listen)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", cfg.Listen, err)
+	}
+
+	srv := grpc.NewServer(
+		grpc.Creds(creds),
+		grpc.UnaryInterceptor(clearanceInterceptor(cfg.Peers)),
+	)
+	exchangev1.RegisterExchangeServiceServer(srv, NewServer(db, vaultNumber))
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			slog.Info("stopping exchange server")
+			srv.GracefulStop()
+		case <-done:
+		}
+	}()
+
+	slog.Info("exchange server listening",
+		"address", lis.Addr().String(),
+		"tls", cfg.TLSCertFile != "",
+		"client_certificates", cfg.ClientCAFile != "",
+		"peers", len(cfg.Peers),
+	)
+	if err := srv.Serve(lis); err != nil {
+		return fmt.Errorf("serving: %w", err)
+	}
+	return nil
+}
+
+// serverCredentials builds the transport credentials. TLS is required unless
+// the configuration explicitly allows plaintext; a client CA additionally
+// requires every client to present a certificate it signed.
+func serverCredentials(cfg config.GRPCConfig) (credentials.TransportCredentials, error) {
+	if cfg.TLSCertFile == "" {
+		if !cfg.Insecure {
+			return nil, errors.New("tls_cert_file and tls_key_file are required unless insecure is set")
+		}
+		slog.Warn("exchange server is running without TLS")
+		return insecure.NewCredentials(), nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(cfg.TLSCertFile, cfg.TLSKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("loading TLS key pair: %w", err)
+	}
+	tlsCfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if cfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(cfg.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading client CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates in client CA file %s", cfg.ClientCAFile)
+		}
+		tlsCfg.ClientCAs = pool
+		tlsCfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return credentials.NewTLS(tlsCfg), nil
+}
@@ -0,0 +1,193 @@
+// Package exchange serves vault data to other vaults over gRPC, for
+// vault-to-vault sync tools. The API is defined in api/vtuos/exchange/v1.
+package exchange
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	exchangev1 "github.com/vtuos/vtuos/api/vtuos/exchange/v1"
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/services/facilities"
+	"github.com/vtuos/vtuos/internal/services/population"
+	"github.com/vtuos/vtuos/internal/services/resources"
+)
+
+// defaultPageSize is the inventory page size when a request leaves it unset.
+const defaultPageSize = 50
+
+// Server implements the exchange service on top of the population,
+// resources and facilities services. It only reads.
+type Server struct {
+	exchangev1.UnimplementedExchangeServiceServer
+
+	vaultNumber int
+	population  *population.Service
+	resources   *resources.Service
+	facilities  *facilities.Service
+}
+
+// NewServer creates an exchange server for the vault's database. It serves
+// the vault's own residents, stock and systems.
+func NewServer(db *sql.DB, vaultNumber int) *Server {
+	s := &Server{
+		vaultNumber: vaultNumber,
+		population:  population.NewService(db, vaultNumber),
+		resources:   resources.NewService(db),
+		facilities:  facilities.NewService(db),
+	}
+	s.resources.SetVault(vaultNumber)
+	s.facilities.SetVault(vaultNumber)
+	return s
+}
+
+// GetCensusSummary returns resident counts by status.
+func (s *Server) GetCensusSummary(ctx context.Context, _ *exchangev1.GetCensusSummaryRequest) (*exchangev1.GetCensusSummaryResponse, error) {
+	stats, err := s.population.GetPopulationStats(ctx)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return &exchangev1.GetCensusSummaryResponse{
+		VaultNumber: int32(s.vaultNumber),
+		Total:       int32(stats.Total),
+		Active:      int32(stats.TotalActive),
+		Deceased:    int32(stats.TotalDeceased),
+		Exiled:      int32(stats.TotalExiled),
+		OnMission:   int32(stats.OnMission),
+		Quarantined: int32(stats.Quarantined),
+	}, nil
+}
+
+// ListInventory returns a page of resource stocks. The page token is the
+// number of the next page.
+func (s *Server) ListInventory(ctx context.Context, req *exchangev1.ListInventoryRequest) (*exchangev1.ListInventoryResponse, error) {
+	page := models.Pagination{Page: 1, PageSize: int(req.GetPageSize())}
+	if page.PageSize == 0 {
+		page.PageSize = defaultPageSize
+	}
+	page.PageSize = page.Limit()
+	if token := req.GetPageToken(); token != "" {
+		n, err := strconv.Atoi(token)
+		if err != nil || n < 1 {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid page token: %q", token)
+		}
+		page.Page = n
+	}
+
+	categories, err := s.resources.ListCategories(ctx)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	categoryCodes := make(map[string]string, len(categories))
+	for _, c := range categories {
+		categoryCodes[c.ID] = c.Code
+	}
+
+	var filter models.StockFilter
+	if code := req.GetCategoryCode(); code != "" {
+		category, err := s.resources.GetCategoryByCode(ctx, code)
+		if err != nil {
+			return nil, statusError(err)
+		}
+		filter.CategoryID = category.ID
+	}
+
+	list, err := s.resources.ListStocks(ctx, filter, page)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	resp := &exchangev1.ListInventoryResponse{
+		Stocks:     make([]*exchangev1.InventoryStock, 0, len(list.Stocks)),
+		TotalCount: int32(list.Total),
+	}
+	for _, stock := range list.Stocks {
+		resp.Stocks = append(resp.Stocks, inventoryStock(stock, categoryCodes))
+	}
+	if list.Page < list.TotalPages {
+		resp.NextPageToken = strconv.Itoa(list.Page + 1)
+	}
+	return resp, nil
+}
+
+// inventoryStock converts a stock; categoryCodes maps category IDs to codes.
+func inventoryStock(stock *models.ResourceStock, categoryCodes map[string]string) *exchangev1.InventoryStock {
+	out := &exchangev1.InventoryStock{
+		Quantity:         stock.Quantity,
+		QuantityReserved: stock.QuantityReserved,
+		StorageLocation:  stock.StorageLocation,
+		Status:           string(stock.Status),
+	}
+	if stock.Item != nil {
+		out.ItemCode = stock.Item.ItemCode
+		out.ItemName = stock.Item.Name
+		out.CategoryCode = categoryCodes[stock.Item.CategoryID]
+		out.Unit = stock.Item.UnitOfMeasure
+	}
+	if stock.LotNumber != nil {
+		out.LotNumber = *stock.LotNumber
+	}
+	if stock.ExpirationDate != nil {
+		out.ExpirationDate = stock.ExpirationDate.Format("2006-01-02")
+	}
+	return out
+}
+
+// GetFacilityStatus returns the status of every facility category and grid.
+func (s *Server) GetFacilityStatus(ctx context.Context, _ *exchangev1.GetFacilityStatusRequest) (*exchangev1.GetFacilityStatusResponse, error) {
+	grids, err := s.facilities.GridStatus(ctx)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	resp := &exchangev1.GetFacilityStatusResponse{}
+	for _, category := range models.FacilityCategories {
+		cs, err := s.facilities.CategoryStatus(ctx, category)
+		if err != nil {
+			return nil, statusError(err)
+		}
+		resp.Categories = append(resp.Categories, &exchangev1.CategoryStatus{
+			Category:          string(cs.Category),
+			Systems:           int32(cs.Systems),
+			Running:           int32(cs.Running),
+			EfficiencyPercent: cs.Efficiency,
+			WorstStatus:       string(cs.Worst),
+		})
+	}
+	for _, g := range grids {
+		resp.Grids = append(resp.Grids, &exchangev1.GridBalance{
+			Grid:      string(g.Grid),
+			Unit:      g.Unit,
+			Capacity:  g.Capacity,
+			Supply:    g.Supply,
+			Demand:    g.Demand,
+			Producers: int32(g.Producers),
+			Stopped:   int32(g.Stopped),
+			State:     string(g.State),
+		})
+	}
+	return resp, nil
+}
+
+// statusError maps a service error to a gRPC status.
+func statusError(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, repository.ErrValidation):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
@@ -0,0 +1,241 @@
+package i18n
+
+// spanish is the Spanish catalog.
+var spanish = &catalog{
+	name:       "Español",
+	dateLayout: "02/01/2006",
+	timeLayout: "15:04:05",
+	vaultDay:   "Día %d",
+	plural:     oneOther,
+	messages: map[string]string{
+		// Header, alert bar and dialogs
+		"VAULT-TEC UNIFIED OPERATING SYSTEM":  "SISTEMA OPERATIVO UNIFICADO VAULT-TEC",
+		"%s │ POP: %d":                        "%s │ POB: %d",
+		"POP:%d":                              "POB:%d",
+		"READ-ONLY":                           "SOLO LECTURA",
+		"CRITICAL: %s":                        "CRÍTICO: %s",
+		"WARNING: %s":                         "AVISO: %s",
+		"INFO: %s":                            "INFO: %s",
+		"All systems operational":             "Todos los sistemas operativos",
+		"This module is not yet implemented.": "Este módulo aún no está implementado.",
+		"Press F2 to return to Dashboard":     "Pulse F2 para volver al panel",
+		"CONFIRM EXIT":                        "CONFIRMAR SALIDA",
+		"Are you sure you want to exit?":      "¿Seguro que desea salir?",
+		"[Y]es  [N]o":                         "[Y] Sí  [N] No",
+
+		// Status bar
+		"Help":       "Ayuda",
+		"Dashboard":  "Panel",
+		"Dash":       "Panel",
+		"Population": "Población",
+		"Pop":        "Pob",
+		"Resources":  "Recursos",
+		"Res":        "Rec",
+		"Facilities": "Instalaciones",
+		"Fac":        "Inst",
+		"Labor":      "Trabajo",
+		"Lab":        "Trab",
+		"Medical":    "Médico",
+		"Med":        "Méd",
+		"Security":   "Seguridad",
+		"Sec":        "Seg",
+		"Governance": "Gobierno",
+		"Gov":        "Gob",
+		"Quit":       "Salir",
+
+		// Help
+		"HELP":                                      "AYUDA",
+		"NAVIGATION":                                "NAVEGACIÓN",
+		"CONTROLS":                                  "CONTROLES",
+		"Press Esc to return":                       "Pulse Esc para volver",
+		"Population Registry":                       "Registro de población",
+		"Resource Management":                       "Gestión de recursos",
+		"Facility Operations":                       "Operación de instalaciones",
+		"Labor Allocation":                          "Asignación de trabajo",
+		"Medical Records":                           "Historiales médicos",
+		"Settings":                                  "Ajustes",
+		"Navigate lists":                            "Recorrer listas",
+		"Select / Confirm":                          "Seleccionar / Confirmar",
+		"Back / Cancel":                             "Atrás / Cancelar",
+		"Search in lists":                           "Buscar en listas",
+		"Next field in forms":                       "Siguiente campo del formulario",
+		"Page navigation":                           "Navegar por páginas",
+		"Add new resident":                          "Añadir residente",
+		"Mark row / all rows on page":               "Marcar fila / toda la página",
+		"Edit selected":                             "Editar selección",
+		"Delete / Death record":                     "Borrar / Registro de defunción",
+		"Cycle category filter":                     "Cambiar filtro de categoría",
+		"Suspend to shell":                          "Suspender al shell",
+		"Undo / redo recent change":                 "Deshacer / rehacer cambio",
+		"Command palette":                           "Paleta de comandos",
+		"Reassign household":                        "Reasignar hogar",
+		"Assign vocation":                           "Asignar vocación",
+		"Adjust stock quantity":                     "Ajustar existencias",
+		"Move stock location":                       "Mover existencias",
+		"Set stock status":                          "Estado de existencias",
+		"Set household ration class":                "Clase de ración del hogar",
+		"Quarantine / surface mission":              "Cuarentena / misión en superficie",
+		"Return resident to active":                 "Reactivar residente",
+		"Filter panel (census)":                     "Panel de filtros (censo)",
+		"Tag / filter by tags / presets (census)":   "Etiquetar / filtrar por etiquetas / preajustes (censo)",
+		"Cycle sort column / reverse sort":          "Columna de orden / invertir orden",
+		"Dissolve / merge / split household":        "Disolver / fusionar / dividir hogar",
+		"Census / households / intake (population)": "Censo / hogares / ingresos (población)",
+		"New intake / screen / clear (intake)":      "Nuevo ingreso / examen / alta (ingresos)",
+		"Stock / assets (resources)":                "Existencias / activos (recursos)",
+		"New item / category (resources)":           "Nuevo artículo / categoría (recursos)",
+		"Edit item / category (resources)":          "Editar artículo / categoría (recursos)",
+		"Register / check out / check in / condition / retire (assets)": "Registrar / prestar / devolver / estado / retirar (activos)",
+		"Daily digest (dashboard)":                                      "Resumen diario (panel)",
+		"Scheduled tasks (dashboard)":                                   "Tareas programadas (panel)",
+		"Care assignments (dashboard)":                                  "Asignaciones de tutela (panel)",
+		"Aptitude assessments (dashboard)":                              "Evaluaciones de aptitud (panel)",
+		"Switch managed vault (dashboard)":                              "Cambiar de refugio (panel)",
+		"Diagnostics (dashboard)":                                       "Diagnóstico (panel)",
+		"Consumption analytics (dashboard)":                             "Análisis de consumo (panel)",
+		"Operator session activity (dashboard)":                         "Actividad de sesiones de operadores (panel)",
+		"Alert queue and routing rules (dashboard)":                     "Cola de alertas y reglas de encaminamiento (panel)",
+		"System dependencies (facilities)":                              "Dependencias de sistemas (instalaciones)",
+		"Change vault state (security)":                                 "Cambiar estado del refugio (seguridad)",
+		"Issue / turn in weapon (security)":                             "Entregar / devolver arma (seguridad)",
+		"Broadcast / notice / acknowledge (governance)":                 "Difusión / aviso / acuse (gobierno)",
+		"ID badge / print badge (resident)":                             "Credencial / imprimir credencial (residente)",
+
+		// Action bars
+		"Acknowledge":          "Acusar recibo",
+		"Add rule":             "Añadir regla",
+		"Adjust":               "Ajustar",
+		"Apply recommendation": "Aplicar recomendación",
+		"Assign guardian":      "Asignar tutor",
+		"Broadcast":            "Difusión",
+		"Cancel":               "Cancelar",
+		"Change vault state":   "Cambiar estado",
+		"Check in":             "Devolver",
+		"Check out":            "Prestar",
+		"Clear":                "Dar de alta",
+		"Condition":            "Estado físico",
+		"Death":                "Defunción",
+		"Delete":               "Borrar",
+		"Dept notice":          "Aviso de depto.",
+		"Dissolve":             "Disolver",
+		"Enable/disable":       "Activar/desactivar",
+		"Edit item":            "Editar artículo",
+		"Enroll":               "Inscribir",
+		"Grant":                "Conceder",
+		"Household":            "Hogar",
+		"Issue weapon":         "Entregar arma",
+		"Log attempt":          "Registrar intento",
+		"Mentor":               "Mentor",
+		"Merge":                "Fusionar",
+		"Mine/all":             "Mías/todas",
+		"Move":                 "Mover",
+		"New intake":           "Nuevo ingreso",
+		"New item":             "Nuevo artículo",
+		"Quarantine":           "Cuarentena",
+		"Rations":              "Raciones",
+		"Record scores":        "Anotar puntuaciones",
+		"Register":             "Registrar",
+		"Remove rule":          "Quitar regla",
+		"Resolve":              "Resolver",
+		"Retire":               "Retirar",
+		"Return":               "Reactivar",
+		"Save current":         "Guardar actual",
+		"Screen":               "Examinar",
+		"Set policy":           "Fijar política",
+		"Sign off":             "Aprobar",
+		"Sign on":              "Identificarse",
+		"Split":                "Dividir",
+		"Status":               "Estado",
+		"Surface":              "Superficie",
+		"Tag":                  "Etiquetar",
+		"Turn in weapon":       "Devolver arma",
+		"Vocation":             "Vocación",
+		"Withdraw":             "Retirar del programa",
+
+		// Screen footers
+		"↑/↓ select  r reload":                                                    "↑/↓ seleccionar  r recargar",
+		"↑/↓ select  r reload  Esc back":                                          "↑/↓ seleccionar  r recargar  Esc volver",
+		"↑/↓ select  Enter apply  Esc close":                                      "↑/↓ seleccionar  Enter aplicar  Esc cerrar",
+		"[/] period  r reload  Esc back":                                          "[/] periodo  r recargar  Esc volver",
+		"d system dependencies  w work orders  m maintenance analytics":           "d dependencias de sistemas  w órdenes de trabajo  m análisis de mantenimiento",
+		"↑/↓ select  Enter assign lead  r reload  Esc back":                       "↑/↓ seleccionar  Enter asignar responsable  r recargar  Esc volver",
+		"↑/↓ select  Enter administer  r reload  Esc back":                        "↑/↓ seleccionar  Enter administrar  r recargar  Esc volver",
+		"↑/↓ select class  r reload":                                              "↑/↓ seleccionar clase  r recargar",
+		"↑/↓ select  f filter by category  r reload":                              "↑/↓ seleccionar  f filtrar por categoría  r recargar",
+		"↑/↓ select  s records the next screening due  r reload":                  "↑/↓ seleccionar  s anota el próximo examen  r recargar",
+		"↑/↓ select point  f granted/denied  p this point only  r reload":         "↑/↓ seleccionar punto  f concedidos/denegados  p solo este punto  r recargar",
+		"↑/↓ select  Esc back  (read-only: tasks do not run)":                     "↑/↓ seleccionar  Esc volver  (solo lectura: las tareas no se ejecutan)",
+		"↑/↓ select  Enter/r run now  Esc back":                                   "↑/↓ seleccionar  Enter/r ejecutar ya  Esc volver",
+		"←/→ previous/next day  t today  r refresh  p printable report  Esc back": "←/→ día anterior/siguiente  t hoy  r actualizar  p informe imprimible  Esc volver",
+		"↑/↓ scroll  ←/→ previous/next day  w write to file  p digest  Esc back":  "↑/↓ desplazar  ←/→ día anterior/siguiente  w guardar en archivo  p resumen  Esc volver",
+
+		// Diagnostics
+		"SYSTEM DIAGNOSTICS": "DIAGNÓSTICO DEL SISTEMA",
+		"System":             "Sistema",
+		"Queries":            "Consultas",
+		"Tab system  ↑/↓ scroll  r reset counters  Esc back": "Tab sistema  ↑/↓ desplazar  r reiniciar contadores  Esc volver",
+		"Tab queries  r check again  Esc back":               "Tab consultas  r comprobar de nuevo  Esc volver",
+		"slow query log off":                                 "registro de consultas lentas desactivado",
+		"slow from %dms":                                     "lentas desde %d ms",
+		"%d slow":                                            "%d lentas",
+
+		// Settings
+		"SETTINGS":                "AJUSTES",
+		"COLOR SCHEME":            "ESQUEMA DE COLOR",
+		"(saved)":                 "(guardado)",
+		"DASHBOARD PANELS":        "PANELES DEL PANEL PRINCIPAL",
+		"GENERAL":                 "GENERAL",
+		"PREVIEW":                 "VISTA PREVIA",
+		"Green Phosphor":          "Fósforo verde",
+		"Amber Phosphor":          "Fósforo ámbar",
+		"Blue Phosphor":           "Fósforo azul",
+		"White Phosphor":          "Fósforo blanco",
+		"Critical systems":        "Sistemas críticos",
+		"Resource runway":         "Autonomía de recursos",
+		"Simulation":              "Simulación",
+		"System efficiency":       "Eficiencia de sistemas",
+		"Alerts":                  "Alertas",
+		"Shortages":               "Faltantes",
+		"Vault designation":       "Designación del refugio",
+		"Time scale":              "Escala de tiempo",
+		"Date format":             "Formato de fecha",
+		"Time format":             "Formato de hora",
+		"Time zone":               "Zona horaria",
+		"Calendar":                "Calendario",
+		"Backup interval (hours)": "Intervalo de copia (horas)",
+		"Language":                "Idioma",
+		"x vault time":            "x tiempo del refugio",
+		"e.g. %s":                 "p. ej. %s",
+		"today %s":                "hoy %s",
+		"(no automatic backups)":  "(sin copias automáticas)",
+		"Label":                   "Etiqueta",
+		"Value":                   "Valor",
+		"Accent":                  "Acento",
+		"NOMINAL":                 "NOMINAL",
+		"DEGRADED":                "DEGRADADO",
+		"CRITICAL":                "CRÍTICO",
+		"←/→ cycle schemes  Tab next section":                     "←/→ cambiar esquema  Tab siguiente sección",
+		"↑/↓ select  Space show/hide  [/] move  Tab next section": "↑/↓ seleccionar  Espacio mostrar/ocultar  [/] mover  Tab siguiente sección",
+		"↑/↓ select  e edit  Tab next section":                    "↑/↓ seleccionar  e editar  Tab siguiente sección",
+		"(read-only: changes are not saved)":                      "(solo lectura: los cambios no se guardan)",
+		"Unsaved change — Enter to save, Esc to revert":           "Cambio sin guardar — Enter para guardar, Esc para deshacer",
+		"Enter save": "Enter guardar",
+
+		// Screen reader mode
+		"Screen: %s":            "Pantalla: %s",
+		"OK":                    "BIEN",
+		"LOW":                   "BAJO",
+		"Daily digest":          "Resumen diario",
+		"Scheduled tasks":       "Tareas programadas",
+		"Care assignments":      "Asignaciones de tutela",
+		"Aptitude assessments":  "Evaluaciones de aptitud",
+		"Managed vaults":        "Refugios gestionados",
+		"Diagnostics":           "Diagnóstico",
+		"Consumption analytics": "Análisis de consumo",
+		"Screen reader":         "Lector de pantalla",
+	},
+	plurals: map[string][]string{
+		"%d statements": {"%d sentencia", "%d sentencias"},
+		"%d runs":       {"%d ejecución", "%d ejecuciones"},
+	},
+}
@@ -0,0 +1,114 @@
+// Package i18n translates the text of the terminal interface. Messages are
+// written in English where they are used, and the English text is the key
+// each locale's catalog translates; a message a catalog lacks is shown in
+// English.
+package i18n
+
+import "fmt"
+
+// Locale is a language the interface can be shown in.
+type Locale string
+
+const (
+	English Locale = "en"
+	Spanish Locale = "es"
+	Chinese Locale = "zh"
+)
+
+// Locales are the locales with a catalog, in the order settings cycle them.
+var Locales = []Locale{English, Spanish, Chinese}
+
+// catalog is the translation of the interface into one locale.
+type catalog struct {
+	name       string              // Of the language, in the language
+	dateLayout string              // Go layout of dates
+	timeLayout string              // Go layout of times of day
+	vaultDay   string              // Format of a day of the vault calendar
+	plural     func(n int) int     // Index into a plural's forms for n
+	messages   map[string]string   // Translations by English text
+	plurals    map[string][]string // Forms of plurals by English plural form
+}
+
+// catalogs holds the catalog of every locale. English translates nothing.
+var catalogs = map[Locale]*catalog{
+	English: {
+		name:       "English",
+		dateLayout: "2006-01-02",
+		timeLayout: "15:04:05",
+		vaultDay:   "Day %d",
+		plural:     oneOther,
+	},
+	Spanish: spanish,
+	Chinese: chinese,
+}
+
+// oneOther is the plural rule of languages with a singular for one and a
+// plural for every other count, such as English and Spanish.
+func oneOther(n int) int {
+	if n == 1 {
+		return 0
+	}
+	return 1
+}
+
+// Valid returns true if the locale has a catalog.
+func (l Locale) Valid() bool {
+	_, ok := catalogs[l]
+	return ok
+}
+
+// catalog returns the locale's catalog, English's for an unknown locale.
+func (l Locale) catalog() *catalog {
+	if c, ok := catalogs[l]; ok {
+		return c
+	}
+	return catalogs[English]
+}
+
+// Name returns the name of the locale's language, in the language.
+func (l Locale) Name() string {
+	return l.catalog().name
+}
+
+// DateLayout returns the Go layout dates are written in.
+func (l Locale) DateLayout() string {
+	return l.catalog().dateLayout
+}
+
+// TimeLayout returns the Go layout times of day are written in.
+func (l Locale) TimeLayout() string {
+	return l.catalog().timeLayout
+}
+
+// VaultDay returns the format of a day of the vault calendar, e.g. "Day %d".
+func (l Locale) VaultDay() string {
+	return l.catalog().vaultDay
+}
+
+// T translates a message, formatting it with args if there are any.
+func (l Locale) T(msg string, args ...any) string {
+	if translated, ok := l.catalog().messages[msg]; ok {
+		msg = translated
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// N translates the form of a message for a count of n, e.g.
+// N(3, "%d statement", "%d statements"), formatting it with args, or with
+// n if there are none.
+func (l Locale) N(n int, one, other string, args ...any) string {
+	if len(args) == 0 {
+		args = []any{n}
+	}
+	c := l.catalog()
+	if forms, ok := c.plurals[other]; ok {
+		return fmt.Sprintf(forms[min(c.plural(n), len(forms)-1)], args...)
+	}
+	if n == 1 {
+		return fmt.Sprintf(one, args...)
+	}
+	return fmt.Sprintf(other, args...)
+}
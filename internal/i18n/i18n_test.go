@@ -0,0 +1,89 @@
+package i18n
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTranslate(t *testing.T) {
+	if got := Spanish.T("Help"); got != "Ayuda" {
+		t.Errorf("es Help = %q", got)
+	}
+	if got := Chinese.T("%s │ POP: %d", "Vault 76", 500); got != "Vault 76 │ 人口: 500" {
+		t.Errorf("zh header = %q", got)
+	}
+	if got := Spanish.T("Not in any catalog %d", 3); got != "Not in any catalog 3" {
+		t.Errorf("missing message = %q, want the English text", got)
+	}
+	if got := Locale("fr").T("Help"); got != "Help" {
+		t.Errorf("unknown locale = %q, want English", got)
+	}
+}
+
+func TestPlurals(t *testing.T) {
+	tests := []struct {
+		locale Locale
+		n      int
+		want   string
+	}{
+		{English, 1, "1 statement"},
+		{English, 0, "0 statements"},
+		{English, 2, "2 statements"},
+		{Spanish, 1, "1 sentencia"},
+		{Spanish, 5, "5 sentencias"},
+		{Chinese, 1, "1 条语句"},
+		{Chinese, 5, "5 条语句"},
+	}
+	for _, tt := range tests {
+		if got := tt.locale.N(tt.n, "%d statement", "%d statements"); got != tt.want {
+			t.Errorf("%s N(%d) = %q, want %q", tt.locale, tt.n, got, tt.want)
+		}
+	}
+	if got := Spanish.N(2, "%d item", "%d items"); got != "2 items" {
+		t.Errorf("missing plural = %q, want the English form", got)
+	}
+}
+
+func TestCatalogsMatch(t *testing.T) {
+	en := catalogs[English]
+	for _, l := range Locales {
+		if !l.Valid() {
+			t.Errorf("%s has no catalog", l)
+		}
+		c := l.catalog()
+		if c.name == "" || c.dateLayout == "" || c.timeLayout == "" || !strings.Contains(c.vaultDay, "%d") {
+			t.Errorf("%s catalog is missing its name or layouts", l)
+		}
+		if l == English {
+			continue
+		}
+		for msg, translated := range c.messages {
+			if strings.Count(msg, "%") != strings.Count(translated, "%") {
+				t.Errorf("%s %q → %q changes the format verbs", l, msg, translated)
+			}
+		}
+		for other, forms := range c.plurals {
+			for _, form := range forms {
+				if strings.Count(other, "%") != strings.Count(form, "%") {
+					t.Errorf("%s %q → %q changes the format verbs", l, other, form)
+				}
+			}
+		}
+		for _, o := range Locales {
+			if o == English || o == l {
+				continue
+			}
+			for msg := range c.messages {
+				if _, ok := o.catalog().messages[msg]; !ok {
+					t.Errorf("%s translates %q but %s does not", l, msg, o)
+				}
+			}
+		}
+	}
+	if len(en.messages) != 0 {
+		t.Errorf("English catalog translates %d messages", len(en.messages))
+	}
+	if Locale("fr").Valid() {
+		t.Error("fr is valid without a catalog")
+	}
+}
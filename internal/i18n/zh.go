@@ -0,0 +1,241 @@
+package i18n
+
+// chinese is the Simplified Chinese catalog.
+var chinese = &catalog{
+	name:       "中文",
+	dateLayout: "2006-01-02",
+	timeLayout: "15:04:05",
+	vaultDay:   "第%d天",
+	plural:     func(int) int { return 0 },
+	messages: map[string]string{
+		// Header, alert bar and dialogs
+		"VAULT-TEC UNIFIED OPERATING SYSTEM":  "VAULT-TEC 统一操作系统",
+		"%s │ POP: %d":                        "%s │ 人口: %d",
+		"POP:%d":                              "人口:%d",
+		"READ-ONLY":                           "只读",
+		"CRITICAL: %s":                        "严重: %s",
+		"WARNING: %s":                         "警告: %s",
+		"INFO: %s":                            "信息: %s",
+		"All systems operational":             "所有系统运行正常",
+		"This module is not yet implemented.": "此模块尚未实现。",
+		"Press F2 to return to Dashboard":     "按 F2 返回仪表盘",
+		"CONFIRM EXIT":                        "确认退出",
+		"Are you sure you want to exit?":      "确定要退出吗？",
+		"[Y]es  [N]o":                         "[Y] 是  [N] 否",
+
+		// Status bar
+		"Help":       "帮助",
+		"Dashboard":  "仪表盘",
+		"Dash":       "仪表",
+		"Population": "人口",
+		"Pop":        "人口",
+		"Resources":  "资源",
+		"Res":        "资源",
+		"Facilities": "设施",
+		"Fac":        "设施",
+		"Labor":      "劳动",
+		"Lab":        "劳动",
+		"Medical":    "医疗",
+		"Med":        "医疗",
+		"Security":   "安保",
+		"Sec":        "安保",
+		"Governance": "治理",
+		"Gov":        "治理",
+		"Quit":       "退出",
+
+		// Help
+		"HELP":                                      "帮助",
+		"NAVIGATION":                                "导航",
+		"CONTROLS":                                  "操作",
+		"Press Esc to return":                       "按 Esc 返回",
+		"Population Registry":                       "人口登记",
+		"Resource Management":                       "资源管理",
+		"Facility Operations":                       "设施运行",
+		"Labor Allocation":                          "劳动分配",
+		"Medical Records":                           "医疗记录",
+		"Settings":                                  "设置",
+		"Navigate lists":                            "浏览列表",
+		"Select / Confirm":                          "选择 / 确认",
+		"Back / Cancel":                             "返回 / 取消",
+		"Search in lists":                           "在列表中搜索",
+		"Next field in forms":                       "表单下一字段",
+		"Page navigation":                           "翻页",
+		"Add new resident":                          "新增居民",
+		"Mark row / all rows on page":               "标记行 / 本页所有行",
+		"Edit selected":                             "编辑所选",
+		"Delete / Death record":                     "删除 / 死亡登记",
+		"Cycle category filter":                     "切换类别筛选",
+		"Suspend to shell":                          "挂起到 shell",
+		"Undo / redo recent change":                 "撤销 / 重做最近更改",
+		"Command palette":                           "命令面板",
+		"Reassign household":                        "重新分配家庭",
+		"Assign vocation":                           "分配职业",
+		"Adjust stock quantity":                     "调整库存数量",
+		"Move stock location":                       "移动库存位置",
+		"Set stock status":                          "设置库存状态",
+		"Set household ration class":                "设置家庭配给等级",
+		"Quarantine / surface mission":              "隔离 / 地表任务",
+		"Return resident to active":                 "恢复居民为在册",
+		"Filter panel (census)":                     "筛选面板（普查）",
+		"Tag / filter by tags / presets (census)":   "标签 / 按标签筛选 / 预设（普查）",
+		"Cycle sort column / reverse sort":          "切换排序列 / 反向排序",
+		"Dissolve / merge / split household":        "解散 / 合并 / 拆分家庭",
+		"Census / households / intake (population)": "普查 / 家庭 / 接收（人口）",
+		"New intake / screen / clear (intake)":      "新接收 / 筛查 / 放行（接收）",
+		"Stock / assets (resources)":                "库存 / 资产（资源）",
+		"New item / category (resources)":           "新物品 / 类别（资源）",
+		"Edit item / category (resources)":          "编辑物品 / 类别（资源）",
+		"Register / check out / check in / condition / retire (assets)": "登记 / 借出 / 归还 / 状况 / 报废（资产）",
+		"Daily digest (dashboard)":                                      "每日摘要（仪表盘）",
+		"Scheduled tasks (dashboard)":                                   "计划任务（仪表盘）",
+		"Care assignments (dashboard)":                                  "监护安排（仪表盘）",
+		"Aptitude assessments (dashboard)":                              "能力评估（仪表盘）",
+		"Switch managed vault (dashboard)":                              "切换避难所（仪表盘）",
+		"Diagnostics (dashboard)":                                       "诊断（仪表盘）",
+		"Consumption analytics (dashboard)":                             "消耗分析（仪表盘）",
+		"Operator session activity (dashboard)":                         "操作员会话活动（仪表盘）",
+		"Alert queue and routing rules (dashboard)":                     "警报队列和路由规则（仪表盘）",
+		"System dependencies (facilities)":                              "系统依赖（设施）",
+		"Change vault state (security)":                                 "更改避难所状态（安保）",
+		"Issue / turn in weapon (security)":                             "发放 / 归还武器（安保）",
+		"Broadcast / notice / acknowledge (governance)":                 "广播 / 通知 / 确认（治理）",
+		"ID badge / print badge (resident)":                             "身份卡 / 打印身份卡（居民）",
+
+		// Action bars
+		"Acknowledge":          "确认",
+		"Add rule":             "添加规则",
+		"Adjust":               "调整",
+		"Apply recommendation": "采纳建议",
+		"Assign guardian":      "指定监护人",
+		"Broadcast":            "广播",
+		"Cancel":               "取消",
+		"Change vault state":   "更改状态",
+		"Check in":             "归还",
+		"Check out":            "借出",
+		"Clear":                "放行",
+		"Condition":            "状况",
+		"Death":                "死亡",
+		"Delete":               "删除",
+		"Dept notice":          "部门通知",
+		"Dissolve":             "解散",
+		"Enable/disable":       "启用/停用",
+		"Edit item":            "编辑物品",
+		"Enroll":               "登记学徒",
+		"Grant":                "授权",
+		"Household":            "家庭",
+		"Issue weapon":         "发放武器",
+		"Log attempt":          "记录尝试",
+		"Mentor":               "导师",
+		"Merge":                "合并",
+		"Mine/all":             "我的/全部",
+		"Move":                 "移动",
+		"New intake":           "新接收",
+		"New item":             "新物品",
+		"Quarantine":           "隔离",
+		"Rations":              "配给",
+		"Record scores":        "记录分数",
+		"Register":             "登记",
+		"Remove rule":          "删除规则",
+		"Resolve":              "解决",
+		"Retire":               "报废",
+		"Return":               "恢复",
+		"Save current":         "保存当前",
+		"Screen":               "筛查",
+		"Set policy":           "设置政策",
+		"Sign off":             "结业",
+		"Sign on":              "登录",
+		"Split":                "拆分",
+		"Status":               "状态",
+		"Surface":              "地表",
+		"Tag":                  "标签",
+		"Turn in weapon":       "归还武器",
+		"Vocation":             "职业",
+		"Withdraw":             "退出培训",
+
+		// Screen footers
+		"↑/↓ select  r reload":                                                    "↑/↓ 选择  r 重新加载",
+		"↑/↓ select  r reload  Esc back":                                          "↑/↓ 选择  r 重新加载  Esc 返回",
+		"↑/↓ select  Enter apply  Esc close":                                      "↑/↓ 选择  Enter 应用  Esc 关闭",
+		"[/] period  r reload  Esc back":                                          "[/] 周期  r 重新加载  Esc 返回",
+		"d system dependencies  w work orders  m maintenance analytics":           "d 系统依赖  w 工单  m 维护分析",
+		"↑/↓ select  Enter assign lead  r reload  Esc back":                       "↑/↓ 选择  Enter 指派负责人  r 重新加载  Esc 返回",
+		"↑/↓ select  Enter administer  r reload  Esc back":                        "↑/↓ 选择  Enter 管理  r 重新加载  Esc 返回",
+		"↑/↓ select class  r reload":                                              "↑/↓ 选择等级  r 重新加载",
+		"↑/↓ select  f filter by category  r reload":                              "↑/↓ 选择  f 按类别筛选  r 重新加载",
+		"↑/↓ select  s records the next screening due  r reload":                  "↑/↓ 选择  s 记录下一次筛查  r 重新加载",
+		"↑/↓ select point  f granted/denied  p this point only  r reload":         "↑/↓ 选择门禁点  f 允许/拒绝  p 仅此门禁点  r 重新加载",
+		"↑/↓ select  Esc back  (read-only: tasks do not run)":                     "↑/↓ 选择  Esc 返回  （只读：任务不运行）",
+		"↑/↓ select  Enter/r run now  Esc back":                                   "↑/↓ 选择  Enter/r 立即运行  Esc 返回",
+		"←/→ previous/next day  t today  r refresh  p printable report  Esc back": "←/→ 前一天/后一天  t 今天  r 刷新  p 可打印报告  Esc 返回",
+		"↑/↓ scroll  ←/→ previous/next day  w write to file  p digest  Esc back":  "↑/↓ 滚动  ←/→ 前一天/后一天  w 写入文件  p 摘要  Esc 返回",
+
+		// Diagnostics
+		"SYSTEM DIAGNOSTICS": "系统诊断",
+		"System":             "系统",
+		"Queries":            "查询",
+		"Tab system  ↑/↓ scroll  r reset counters  Esc back": "Tab 系统  ↑/↓ 滚动  r 重置计数  Esc 返回",
+		"Tab queries  r check again  Esc back":               "Tab 查询  r 重新检查  Esc 返回",
+		"slow query log off":                                 "慢查询日志已关闭",
+		"slow from %dms":                                     "慢查询阈值 %d 毫秒",
+		"%d slow":                                            "%d 次慢查询",
+
+		// Settings
+		"SETTINGS":                "设置",
+		"COLOR SCHEME":            "配色方案",
+		"(saved)":                 "（已保存）",
+		"DASHBOARD PANELS":        "仪表盘面板",
+		"GENERAL":                 "常规",
+		"PREVIEW":                 "预览",
+		"Green Phosphor":          "绿色荧光",
+		"Amber Phosphor":          "琥珀荧光",
+		"Blue Phosphor":           "蓝色荧光",
+		"White Phosphor":          "白色荧光",
+		"Critical systems":        "关键系统",
+		"Resource runway":         "资源可用天数",
+		"Simulation":              "模拟",
+		"System efficiency":       "系统效率",
+		"Alerts":                  "警报",
+		"Shortages":               "库存短缺",
+		"Vault designation":       "避难所名称",
+		"Time scale":              "时间倍率",
+		"Date format":             "日期格式",
+		"Time format":             "时间格式",
+		"Time zone":               "时区",
+		"Calendar":                "历法",
+		"Backup interval (hours)": "备份间隔（小时）",
+		"Language":                "语言",
+		"x vault time":            "倍避难所时间",
+		"e.g. %s":                 "例如 %s",
+		"today %s":                "今天 %s",
+		"(no automatic backups)":  "（无自动备份）",
+		"Label":                   "标签",
+		"Value":                   "数值",
+		"Accent":                  "强调",
+		"NOMINAL":                 "正常",
+		"DEGRADED":                "降级",
+		"CRITICAL":                "严重",
+		"←/→ cycle schemes  Tab next section":                     "←/→ 切换方案  Tab 下一部分",
+		"↑/↓ select  Space show/hide  [/] move  Tab next section": "↑/↓ 选择  空格 显示/隐藏  [/] 移动  Tab 下一部分",
+		"↑/↓ select  e edit  Tab next section":                    "↑/↓ 选择  e 编辑  Tab 下一部分",
+		"(read-only: changes are not saved)":                      "（只读：更改不会保存）",
+		"Unsaved change — Enter to save, Esc to revert":           "有未保存的更改 — Enter 保存，Esc 还原",
+		"Enter save": "Enter 保存",
+
+		// Screen reader mode
+		"Screen: %s":            "界面: %s",
+		"OK":                    "正常",
+		"LOW":                   "偏低",
+		"Daily digest":          "每日摘要",
+		"Scheduled tasks":       "计划任务",
+		"Care assignments":      "监护安排",
+		"Aptitude assessments":  "能力评估",
+		"Managed vaults":        "管理的避难所",
+		"Diagnostics":           "诊断",
+		"Consumption analytics": "消耗分析",
+		"Screen reader":         "屏幕阅读器",
+	},
+	plurals: map[string][]string{
+		"%d statements": {"%d 条语句"},
+		"%d runs":       {"%d 次执行"},
+	},
+}
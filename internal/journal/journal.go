@@ -0,0 +1,252 @@
+// Package journal records the commands services run, with the vault time
+// and the IDs each generated, so that a session can be replayed against a
+// fresh database to reproduce it.
+package journal
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// Entry is one command in a journal.
+type Entry struct {
+	Seq       int             `json:"seq"`
+	VaultTime time.Time       `json:"vault_time"`
+	Vault     int             `json:"vault,omitempty"` // Vault the service was limited to, 0 for all
+	Command   string          `json:"command"`         // e.g. "population.create_resident"
+	Args      json.RawMessage `json:"args"`
+	IDs       []string        `json:"ids,omitempty"`   // IDs generated while it ran, in order
+	Error     string          `json:"error,omitempty"` // Error the command returned
+
+	// Countersign is the second operator's confirmation of a command held
+	// for two-person authorization, without the PIN they gave.
+	Countersign *util.Countersign `json:"countersign,omitempty"`
+}
+
+// Journal appends the commands services run to a file, one JSON entry per
+// line. Commands are journaled one at a time: Begin holds the journal until
+// End, so the IDs generated in between belong to that command.
+type Journal struct {
+	mu     sync.Mutex
+	w      io.Writer
+	closer io.Closer
+	now    func() time.Time
+	seq    int
+
+	idsMu   sync.Mutex
+	current *Command
+}
+
+// Create opens the journal at path, appending to any entries it holds, and
+// installs the hook that records generated IDs. now is the vault time
+// entries are stamped with.
+func Create(path string, now func() time.Time) (*Journal, error) {
+	seq := 0
+	if f, err := os.Open(path); err == nil {
+		err := Read(f, func(e Entry) error {
+			seq = e.Seq
+			return nil
+		})
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading journal %s: %w", path, err)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("opening journal: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o640)
+	if err != nil {
+		return nil, fmt.Errorf("opening journal: %w", err)
+	}
+	j := New(f, now)
+	j.closer = f
+	j.seq = seq
+	return j, nil
+}
+
+// New creates a journal writing to w and installs the hook that records
+// generated IDs.
+func New(w io.Writer, now func() time.Time) *Journal {
+	j := &Journal{w: w, now: now}
+	util.SetIDHook(j.captureID)
+	return j
+}
+
+// Close removes the ID hook and closes the journal file.
+func (j *Journal) Close() error {
+	if j == nil {
+		return nil
+	}
+	util.SetIDHook(nil)
+	if j.closer != nil {
+		return j.closer.Close()
+	}
+	return nil
+}
+
+// captureID records a generated ID on the command being journaled.
+func (j *Journal) captureID(id string) string {
+	j.idsMu.Lock()
+	defer j.idsMu.Unlock()
+	if j.current != nil {
+		j.current.entry.IDs = append(j.current.entry.IDs, id)
+	}
+	return id
+}
+
+// commandKey marks a context as running a journaled command.
+type commandKey struct{}
+
+// observerKey marks a context whose commands are reported to an observer.
+type observerKey struct{}
+
+// Observe returns a context whose journaled commands are reported to fn as
+// they end, with the error each returned, whether or not a journal records
+// them. Commands a command runs in turn are not reported. fn may be called
+// from any goroutine.
+func Observe(ctx context.Context, fn func(command string, err error)) context.Context {
+	return context.WithValue(ctx, observerKey{}, fn)
+}
+
+// OnSuccess runs fn once the command running in ctx ends without error,
+// after it is journaled, or at once when no command is running. A failed
+// command drops fn, as its changes were rolled back.
+func OnSuccess(ctx context.Context, fn func()) {
+	c, _ := ctx.Value(commandKey{}).(*Command)
+	if c == nil {
+		fn()
+		return
+	}
+	c.successMu.Lock()
+	defer c.successMu.Unlock()
+	c.success = append(c.success, fn)
+}
+
+// Command is a command being journaled.
+type Command struct {
+	journal *Journal // Nil for a command not journaled
+	entry   Entry
+	observe func(command string, err error)
+
+	successMu sync.Mutex
+	success   []func()
+}
+
+// Begin starts journaling a command run by a service limited to vault, with
+// the arguments needed to run it again. The returned context marks the
+// command as running: commands it runs in turn are not journaled, as
+// replaying it runs them again, and Begin returns a nil Command for them. A
+// nil journal journals nothing, though an observer of the context still
+// learns of the command and OnSuccess still waits for it. Callers must call
+// End, usually deferred.
+func (j *Journal) Begin(ctx context.Context, vault int, command string, args any) (context.Context, *Command) {
+	if ctx.Value(commandKey{}) != nil {
+		return ctx, nil
+	}
+	observe, _ := ctx.Value(observerKey{}).(func(string, error))
+	if j == nil {
+		c := &Command{entry: Entry{Command: command}, observe: observe}
+		return context.WithValue(ctx, commandKey{}, c), c
+	}
+
+	raw, err := json.Marshal(args)
+	if err != nil {
+		slog.Warn("journal arguments not recorded", "command", command, "error", err)
+		raw = json.RawMessage("null")
+	}
+
+	j.mu.Lock()
+	c := &Command{journal: j, entry: Entry{
+		VaultTime: j.now(),
+		Vault:     vault,
+		Command:   command,
+		Args:      raw,
+	}, observe: observe}
+	if cs, ok := util.CountersignOf(ctx); ok {
+		c.entry.Countersign = &cs
+	}
+	j.idsMu.Lock()
+	j.current = c
+	j.idsMu.Unlock()
+
+	return context.WithValue(ctx, commandKey{}, c), c
+}
+
+// End writes the command to the journal with the error it returned,
+// reports it to the context's observer and, if it succeeded, runs the
+// functions left for it by OnSuccess.
+func (c *Command) End(err error) {
+	if c == nil {
+		return
+	}
+	if c.observe != nil {
+		defer c.observe(c.entry.Command, err)
+	}
+	if err == nil {
+		defer c.succeeded()
+	}
+	j := c.journal
+	if j == nil {
+		return
+	}
+	defer j.mu.Unlock()
+
+	j.idsMu.Lock()
+	j.current = nil
+	j.idsMu.Unlock()
+
+	if err != nil {
+		c.entry.Error = err.Error()
+	}
+	j.seq++
+	c.entry.Seq = j.seq
+
+	line, err := json.Marshal(c.entry)
+	if err == nil {
+		_, err = j.w.Write(append(line, '\n'))
+	}
+	if err != nil {
+		slog.Error("writing journal entry", "command", c.entry.Command, "error", err)
+	}
+}
+
+// succeeded runs the functions left for the command by OnSuccess.
+func (c *Command) succeeded() {
+	c.successMu.Lock()
+	success := c.success
+	c.success = nil
+	c.successMu.Unlock()
+	for _, fn := range success {
+		fn()
+	}
+}
+
+// Read calls fn with each entry of a journal in order.
+func Read(r io.Reader, fn func(Entry) error) error {
+	dec := json.NewDecoder(bufio.NewReader(r))
+	last := 0
+	for {
+		var e Entry
+		if err := dec.Decode(&e); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("decoding entry after %d: %w", last, err)
+		}
+		if err := fn(e); err != nil {
+			return err
+		}
+		last = e.Seq
+	}
+}
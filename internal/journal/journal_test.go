@@ -0,0 +1,227 @@
+package journal
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// counter is a service whose commands are journaled.
+type counter struct {
+	journal *Journal
+	ids     *util.IDGenerator
+	created []string
+}
+
+type addArgs struct {
+	Count int `json:"count"`
+}
+
+// Add creates count records, each through a nested create command.
+func (c *counter) Add(ctx context.Context, count int) (err error) {
+	ctx, cmd := c.journal.Begin(ctx, 76, "counter.add", addArgs{count})
+	defer func() { cmd.End(err) }()
+
+	for i := 0; i < count; i++ {
+		c.create(ctx)
+	}
+	if count > 2 {
+		return errors.New("too many")
+	}
+	return nil
+}
+
+func (c *counter) create(ctx context.Context) {
+	_, cmd := c.journal.Begin(ctx, 76, "counter.create", nil)
+	defer cmd.End(nil)
+	c.created = append(c.created, c.ids.NewID())
+}
+
+func (c *counter) handlers() Handlers {
+	return Handlers{
+		"counter.add": Handle(func(ctx context.Context, args addArgs) error {
+			return c.Add(ctx, args.Count)
+		}),
+	}
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	vaultTime := time.Date(2078, 3, 1, 8, 0, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	j := New(&buf, func() time.Time { return vaultTime })
+
+	recorded := &counter{journal: j, ids: util.NewIDGenerator()}
+	ctx := context.Background()
+	if err := recorded.Add(ctx, 2); err != nil {
+		t.Fatalf("Add(2) error = %v", err)
+	}
+	if err := recorded.Add(ctx, 3); err == nil {
+		t.Fatal("Add(3) succeeded, want error")
+	}
+	if err := j.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var entries []Entry
+	if err := Read(bytes.NewReader(buf.Bytes()), func(e Entry) error {
+		entries = append(entries, e)
+		return nil
+	}); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("journaled %d entries, want 2 (nested commands are not journaled)", len(entries))
+	}
+	if entries[0].Seq != 1 || len(entries[0].IDs) != 2 || entries[1].Error != "too many" {
+		t.Errorf("entries = %+v", entries)
+	}
+
+	clock := util.NewVaultClock(time.Time{}, 0)
+	clock.Pause()
+	replayed := &counter{ids: util.NewIDGenerator()}
+	var outcomes []Outcome
+	summary, err := NewReplayer(clock, func(vault int) (Handlers, error) {
+		if vault != 76 {
+			t.Errorf("handlers of vault %d, want 76", vault)
+		}
+		return replayed.handlers(), nil
+	}).Replay(ctx, bytes.NewReader(buf.Bytes()), func(o Outcome) { outcomes = append(outcomes, o) })
+	if err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+
+	if summary.Commands != 2 || summary.Failed != 1 || summary.Diverged != 0 {
+		t.Errorf("summary = %+v, outcomes = %+v", summary, outcomes)
+	}
+	if strings.Join(replayed.created, ",") != strings.Join(recorded.created, ",") {
+		t.Errorf("replayed IDs %v, recorded %v", replayed.created, recorded.created)
+	}
+	if !clock.Now().Equal(vaultTime) {
+		t.Errorf("vault time = %v, want %v", clock.Now(), vaultTime)
+	}
+}
+
+func TestReplayDivergence(t *testing.T) {
+	journal := `{"seq":1,"vault_time":"2078-03-01T08:00:00Z","vault":76,"command":"counter.add","args":{"count":3}}
+{"seq":2,"vault_time":"2078-03-01T09:00:00Z","vault":76,"command":"counter.add","args":{"count":1},"ids":["a","b"]}
+{"seq":3,"vault_time":"2078-03-01T10:00:00Z","command":"counter.remove","args":null}
+`
+	clock := util.NewVaultClock(time.Time{}, 0)
+	clock.Pause()
+	c := &counter{ids: util.NewIDGenerator()}
+	var diffs []string
+	summary, err := NewReplayer(clock, func(int) (Handlers, error) { return c.handlers(), nil }).
+		Replay(context.Background(), strings.NewReader(journal), func(o Outcome) { diffs = append(diffs, o.Diff) })
+	if err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+	if summary.Diverged != 3 {
+		t.Errorf("diverged = %d, want 3: %q", summary.Diverged, diffs)
+	}
+	want := []string{"failed, recorded as succeeded", "generated 1 ID(s), recorded 2", "command is not replayable"}
+	for i := range want {
+		if i >= len(diffs) || diffs[i] != want[i] {
+			t.Errorf("diff %d = %q, want %q", i, diffs, want[i])
+		}
+	}
+}
+
+func TestObserve(t *testing.T) {
+	vaultTime := time.Date(2078, 3, 1, 8, 0, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	journaled := New(&buf, func() time.Time { return vaultTime })
+	defer journaled.Close()
+
+	for _, j := range []*Journal{nil, journaled} {
+		var observed []string
+		ctx := Observe(context.Background(), func(command string, err error) {
+			if err != nil {
+				command += " failed"
+			}
+			observed = append(observed, command)
+		})
+		c := &counter{journal: j, ids: util.NewIDGenerator()}
+		c.Add(ctx, 1)
+		c.Add(ctx, 3)
+		c.Add(context.Background(), 1)
+
+		want := "counter.add,counter.add failed"
+		if got := strings.Join(observed, ","); got != want {
+			t.Errorf("journal %v: observed %q, want %q (nested and unobserved commands are not reported)", j != nil, got, want)
+		}
+	}
+}
+
+func TestOnSuccess(t *testing.T) {
+	vaultTime := time.Date(2078, 3, 1, 8, 0, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	journaled := New(&buf, func() time.Time { return vaultTime })
+	defer journaled.Close()
+
+	for _, j := range []*Journal{nil, journaled} {
+		var ran []string
+		run := func(name string, fail bool) {
+			ctx, cmd := j.Begin(context.Background(), 76, name, nil)
+			nested, inner := j.Begin(ctx, 76, name+".nested", nil)
+			OnSuccess(nested, func() { ran = append(ran, name+".nested") })
+			inner.End(nil)
+			OnSuccess(ctx, func() { ran = append(ran, name) })
+			if len(ran) != 0 {
+				t.Errorf("journal %v: %v ran before %s ended", j != nil, ran, name)
+			}
+			var err error
+			if fail {
+				err = errors.New("failed")
+			}
+			cmd.End(err)
+		}
+
+		run("failed", true)
+		run("done", false)
+		OnSuccess(context.Background(), func() { ran = append(ran, "outside") })
+
+		want := "done.nested,done,outside"
+		if got := strings.Join(ran, ","); got != want {
+			t.Errorf("journal %v: ran %q, want %q", j != nil, got, want)
+		}
+	}
+}
+
+func TestCountersign(t *testing.T) {
+	var buf bytes.Buffer
+	j := New(&buf, func() time.Time { return time.Date(2078, 3, 1, 8, 0, 0, 0, time.UTC) })
+	c := &counter{journal: j, ids: util.NewIDGenerator()}
+	ctx := util.WithCountersign(context.Background(), util.Countersign{RequestedBy: "V076-00001", By: "V076-00002", PIN: "246810"})
+	if err := c.Add(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "246810") {
+		t.Fatalf("journal records the PIN: %s", buf.String())
+	}
+
+	var got []util.Countersign
+	replayed := &counter{ids: util.NewIDGenerator()}
+	handlers := Handlers{"counter.add": Handle(func(ctx context.Context, args addArgs) error {
+		cs, _ := util.CountersignOf(ctx)
+		got = append(got, cs)
+		return replayed.Add(ctx, args.Count)
+	})}
+	clock := util.NewVaultClock(time.Time{}, 0)
+	clock.Pause()
+	if _, err := NewReplayer(clock, func(int) (Handlers, error) { return handlers, nil }).
+		Replay(context.Background(), bytes.NewReader(buf.Bytes()), nil); err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+	want := util.Countersign{RequestedBy: "V076-00001", By: "V076-00002", Replayed: true}
+	if len(got) != 1 || got[0] != want {
+		t.Errorf("replayed countersign = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,172 @@
+package journal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// Handler runs a journaled command again from its arguments.
+type Handler func(ctx context.Context, args json.RawMessage) error
+
+// Handlers maps command names to their handlers.
+type Handlers map[string]Handler
+
+// Handle returns the handler of a command whose arguments decode into T.
+func Handle[T any](fn func(ctx context.Context, args T) error) Handler {
+	return func(ctx context.Context, raw json.RawMessage) error {
+		var args T
+		if err := json.Unmarshal(raw, &args); err != nil {
+			return fmt.Errorf("decoding arguments: %w", err)
+		}
+		return fn(ctx, args)
+	}
+}
+
+// Clock is the vault clock a replay sets to each command's vault time.
+type Clock interface {
+	SetTime(t time.Time) error
+}
+
+// Outcome is the result of replaying one entry.
+type Outcome struct {
+	Entry Entry
+	Err   error  // Error the replayed command returned
+	Diff  string // How the replay diverged from the journal, empty if it did not
+}
+
+// Diverged returns true if the replayed command did not do what the journal
+// recorded.
+func (o Outcome) Diverged() bool {
+	return o.Diff != ""
+}
+
+// Summary totals a replay.
+type Summary struct {
+	Commands int
+	Failed   int // Commands that returned an error, as recorded or not
+	Diverged int
+}
+
+// Replayer runs the commands of a journal again, in order, at their vault
+// times and with the IDs they generated when recorded.
+type Replayer struct {
+	clock    Clock
+	handlers func(vault int) (Handlers, error)
+	byVault  map[int]Handlers
+
+	mu      sync.Mutex
+	pending []string // Recorded IDs of the running command not yet handed out
+	extra   int      // IDs generated beyond those recorded
+}
+
+// NewReplayer creates a replayer. handlers returns the command handlers of
+// services limited to a vault; it is called once per vault the journal
+// names.
+func NewReplayer(clock Clock, handlers func(vault int) (Handlers, error)) *Replayer {
+	return &Replayer{clock: clock, handlers: handlers, byVault: make(map[int]Handlers)}
+}
+
+// replayID hands out the recorded IDs of the running command in order.
+func (r *Replayer) replayID(id string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.pending) == 0 {
+		r.extra++
+		return id
+	}
+	id, r.pending = r.pending[0], r.pending[1:]
+	return id
+}
+
+// Replay runs each command of the journal read from in and reports its
+// outcome. A command that diverges is reported and the replay goes on, as
+// later commands may still reproduce what was recorded.
+func (r *Replayer) Replay(ctx context.Context, in io.Reader, report func(Outcome)) (*Summary, error) {
+	util.SetIDHook(r.replayID)
+	defer util.SetIDHook(nil)
+
+	summary := &Summary{}
+	err := Read(in, func(e Entry) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		outcome, err := r.run(ctx, e)
+		if err != nil {
+			return err
+		}
+		summary.Commands++
+		if outcome.Err != nil {
+			summary.Failed++
+		}
+		if outcome.Diverged() {
+			summary.Diverged++
+		}
+		if report != nil {
+			report(outcome)
+		}
+		return nil
+	})
+	return summary, err
+}
+
+// run replays one entry.
+func (r *Replayer) run(ctx context.Context, e Entry) (Outcome, error) {
+	handlers, ok := r.byVault[e.Vault]
+	if !ok {
+		var err error
+		if handlers, err = r.handlers(e.Vault); err != nil {
+			return Outcome{}, fmt.Errorf("services of vault %d: %w", e.Vault, err)
+		}
+		r.byVault[e.Vault] = handlers
+	}
+
+	outcome := Outcome{Entry: e}
+	handler, ok := handlers[e.Command]
+	if !ok {
+		outcome.Err = fmt.Errorf("unknown command %q", e.Command)
+		outcome.Diff = "command is not replayable"
+		return outcome, nil
+	}
+	if err := r.clock.SetTime(e.VaultTime); err != nil {
+		return Outcome{}, fmt.Errorf("setting vault time of entry %d: %w", e.Seq, err)
+	}
+
+	r.mu.Lock()
+	r.pending, r.extra = append([]string(nil), e.IDs...), 0
+	r.mu.Unlock()
+
+	runCtx := ctx
+	if e.Countersign != nil {
+		cs := *e.Countersign
+		cs.Replayed = true
+		runCtx = util.WithCountersign(ctx, cs)
+	}
+	outcome.Err = handler(runCtx, e.Args)
+
+	r.mu.Lock()
+	unused, extra := len(r.pending), r.extra
+	r.pending = nil
+	r.mu.Unlock()
+
+	replayed := ""
+	if outcome.Err != nil {
+		replayed = outcome.Err.Error()
+	}
+	switch {
+	case replayed != e.Error && e.Error == "":
+		outcome.Diff = "failed, recorded as succeeded"
+	case replayed != e.Error && replayed == "":
+		outcome.Diff = "succeeded, recorded as failed: " + e.Error
+	case replayed != e.Error:
+		outcome.Diff = "failed differently, recorded: " + e.Error
+	case unused > 0 || extra > 0:
+		outcome.Diff = fmt.Sprintf("generated %d ID(s), recorded %d", len(e.IDs)-unused+extra, len(e.IDs))
+	}
+	return outcome, nil
+}
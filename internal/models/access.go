@@ -0,0 +1,212 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AccessPointKind represents the kind of securable location.
+type AccessPointKind string
+
+const (
+	AccessPointDoor    AccessPointKind = "DOOR"
+	AccessPointAirlock AccessPointKind = "AIRLOCK"
+)
+
+// Valid returns true if the access point kind is valid.
+func (k AccessPointKind) Valid() bool {
+	return k == AccessPointDoor || k == AccessPointAirlock
+}
+
+// AccessPoint is a door or airlock that admits residents holding its
+// minimum clearance or a grant for it.
+type AccessPoint struct {
+	ID             string          `json:"id"`
+	Code           string          `json:"code"` // "AL-SURFACE-01"
+	Name           string          `json:"name"`
+	Kind           AccessPointKind `json:"kind"`
+	LocationSector string          `json:"location_sector,omitempty"`
+	LocationLevel  int             `json:"location_level"`
+	MinClearance   int             `json:"min_clearance"`
+	IsActive       bool            `json:"is_active"` // Inactive points admit no one
+	VaultID        int             `json:"vault_id"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+// Validate checks if the access point data is valid.
+func (p *AccessPoint) Validate() error {
+	if p.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if p.Code == "" {
+		return fmt.Errorf("code is required")
+	}
+	if p.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if !p.Kind.Valid() {
+		return fmt.Errorf("invalid kind: %s", p.Kind)
+	}
+	if p.MinClearance < 1 || p.MinClearance > 10 {
+		return fmt.Errorf("min_clearance must be between 1 and 10")
+	}
+	return nil
+}
+
+// AccessGrant lets a resident through one access point whatever their
+// clearance, until it expires or is revoked.
+type AccessGrant struct {
+	ID            string     `json:"id"`
+	ResidentID    string     `json:"resident_id"`
+	AccessPointID string     `json:"access_point_id"`
+	GrantedBy     *string    `json:"granted_by,omitempty"`
+	GrantedAt     time.Time  `json:"granted_at"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"`
+	RevokedAt     *time.Time `json:"revoked_at,omitempty"`
+	Reason        string     `json:"reason,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+
+	// Joined fields
+	Resident    *Resident    `json:"resident,omitempty"`
+	AccessPoint *AccessPoint `json:"access_point,omitempty"`
+}
+
+// Validate checks if the grant data is valid.
+func (g *AccessGrant) Validate() error {
+	if g.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if g.ResidentID == "" {
+		return fmt.Errorf("resident_id is required")
+	}
+	if g.AccessPointID == "" {
+		return fmt.Errorf("access_point_id is required")
+	}
+	if g.GrantedAt.IsZero() {
+		return fmt.Errorf("granted_at is required")
+	}
+	if g.ExpiresAt != nil && !g.ExpiresAt.After(g.GrantedAt) {
+		return fmt.Errorf("expires_at must be after granted_at")
+	}
+	return nil
+}
+
+// ActiveAt returns true if the grant is in force at t: granted, not yet
+// expired and not revoked.
+func (g *AccessGrant) ActiveAt(t time.Time) bool {
+	if g.GrantedAt.After(t) {
+		return false
+	}
+	if g.ExpiresAt != nil && !t.Before(*g.ExpiresAt) {
+		return false
+	}
+	return g.RevokedAt == nil || t.Before(*g.RevokedAt)
+}
+
+// AccessReason is why an access attempt was granted or denied.
+type AccessReason string
+
+const (
+	AccessReasonClearance         AccessReason = "CLEARANCE"          // Granted: holds the minimum clearance
+	AccessReasonGrant             AccessReason = "GRANT"              // Granted: holds a grant for the point
+	AccessReasonInsufficient      AccessReason = "INSUFFICIENT"       // Denied: neither clearance nor grant
+	AccessReasonResidentInactive  AccessReason = "RESIDENT_INACTIVE"  // Denied: not an active resident
+	AccessReasonPointDisabled     AccessReason = "POINT_DISABLED"     // Denied: the point admits no one
+	AccessReasonUnknownCredential AccessReason = "UNKNOWN_CREDENTIAL" // Denied: no resident matches
+)
+
+// Valid returns true if the access reason is valid.
+func (r AccessReason) Valid() bool {
+	switch r {
+	case AccessReasonClearance, AccessReasonGrant, AccessReasonInsufficient,
+		AccessReasonResidentInactive, AccessReasonPointDisabled, AccessReasonUnknownCredential:
+		return true
+	default:
+		return false
+	}
+}
+
+// DecideAccess decides whether a resident may pass an access point at t,
+// given the resident's grants. A nil resident is an unknown credential.
+func DecideAccess(point *AccessPoint, resident *Resident, grants []*AccessGrant, t time.Time) (bool, AccessReason) {
+	switch {
+	case !point.IsActive:
+		return false, AccessReasonPointDisabled
+	case resident == nil:
+		return false, AccessReasonUnknownCredential
+	case resident.Status != ResidentStatusActive:
+		return false, AccessReasonResidentInactive
+	case resident.ClearanceLevel >= point.MinClearance:
+		return true, AccessReasonClearance
+	}
+	for _, g := range grants {
+		if g.ResidentID == resident.ID && g.AccessPointID == point.ID && g.ActiveAt(t) {
+			return true, AccessReasonGrant
+		}
+	}
+	return false, AccessReasonInsufficient
+}
+
+// AccessEvent is one attempt to pass an access point, granted or denied.
+type AccessEvent struct {
+	ID            string       `json:"id"`
+	AccessPointID string       `json:"access_point_id"`
+	ResidentID    *string      `json:"resident_id,omitempty"` // NULL for an unknown credential
+	OccurredAt    time.Time    `json:"occurred_at"`
+	Granted       bool         `json:"granted"`
+	Reason        AccessReason `json:"reason"`
+	Simulated     bool         `json:"simulated"` // Raised by a drill
+	CreatedAt     time.Time    `json:"created_at"`
+
+	// Joined fields
+	PointCode      string `json:"point_code,omitempty"`
+	RegistryNumber string `json:"registry_number,omitempty"`
+}
+
+// Validate checks if the access event data is valid.
+func (e *AccessEvent) Validate() error {
+	if e.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if e.AccessPointID == "" {
+		return fmt.Errorf("access_point_id is required")
+	}
+	if e.OccurredAt.IsZero() {
+		return fmt.Errorf("occurred_at is required")
+	}
+	if !e.Reason.Valid() {
+		return fmt.Errorf("invalid reason: %s", e.Reason)
+	}
+	if e.Granted != (e.Reason == AccessReasonClearance || e.Reason == AccessReasonGrant) {
+		return fmt.Errorf("reason %s does not match the outcome", e.Reason)
+	}
+	return nil
+}
+
+// Outcome returns GRANTED or DENIED.
+func (e *AccessEvent) Outcome() string {
+	if e.Granted {
+		return "GRANTED"
+	}
+	return "DENIED"
+}
+
+// AccessEventFilter defines filters for querying access events.
+type AccessEventFilter struct {
+	AccessPointID string
+	ResidentID    string
+	Granted       *bool
+	From          *time.Time
+	To            *time.Time // Exclusive
+}
+
+// ParseAccessPointKind reads a kind name in any case.
+func ParseAccessPointKind(name string) (AccessPointKind, error) {
+	kind := AccessPointKind(strings.ToUpper(strings.TrimSpace(name)))
+	if !kind.Valid() {
+		return "", fmt.Errorf("invalid access point kind %q: use door or airlock", name)
+	}
+	return kind, nil
+}
@@ -0,0 +1,112 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseAccessPointKind(t *testing.T) {
+	for input, want := range map[string]AccessPointKind{"door": AccessPointDoor, " Airlock ": AccessPointAirlock} {
+		got, err := ParseAccessPointKind(input)
+		if err != nil || got != want {
+			t.Errorf("ParseAccessPointKind(%q) = %q, %v, want %q", input, got, err, want)
+		}
+	}
+	if _, err := ParseAccessPointKind("hatch"); err == nil {
+		t.Error("ParseAccessPointKind(\"hatch\") expected error")
+	}
+}
+
+func TestAccessPoint_Validate(t *testing.T) {
+	valid := func() *AccessPoint {
+		return &AccessPoint{ID: "ap-1", Code: "AL-SURFACE-01", Name: "Surface Airlock", Kind: AccessPointAirlock, MinClearance: 7}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*AccessPoint)
+		wantErr bool
+	}{
+		{"Valid point", func(p *AccessPoint) {}, false},
+		{"Missing code", func(p *AccessPoint) { p.Code = "" }, true},
+		{"Invalid kind", func(p *AccessPoint) { p.Kind = "HATCH" }, true},
+		{"Clearance too low", func(p *AccessPoint) { p.MinClearance = 0 }, true},
+		{"Clearance too high", func(p *AccessPoint) { p.MinClearance = 11 }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := valid()
+			tt.modify(p)
+			if err := p.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAccessGrant_ActiveAt(t *testing.T) {
+	granted := time.Date(2077, 11, 1, 8, 0, 0, 0, time.UTC)
+	expires := granted.Add(48 * time.Hour)
+	revoked := granted.Add(24 * time.Hour)
+
+	tests := []struct {
+		name  string
+		grant AccessGrant
+		at    time.Time
+		want  bool
+	}{
+		{"Before granted", AccessGrant{GrantedAt: granted}, granted.Add(-time.Hour), false},
+		{"Open-ended", AccessGrant{GrantedAt: granted}, granted.AddDate(1, 0, 0), true},
+		{"Before expiry", AccessGrant{GrantedAt: granted, ExpiresAt: &expires}, expires.Add(-time.Second), true},
+		{"At expiry", AccessGrant{GrantedAt: granted, ExpiresAt: &expires}, expires, false},
+		{"Before revocation", AccessGrant{GrantedAt: granted, RevokedAt: &revoked}, granted.Add(time.Hour), true},
+		{"After revocation", AccessGrant{GrantedAt: granted, RevokedAt: &revoked}, revoked.Add(time.Hour), false},
+	}
+	for _, tt := range tests {
+		if got := tt.grant.ActiveAt(tt.at); got != tt.want {
+			t.Errorf("%s: ActiveAt() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDecideAccess(t *testing.T) {
+	at := time.Date(2077, 11, 1, 8, 0, 0, 0, time.UTC)
+	point := &AccessPoint{ID: "ap-1", MinClearance: 7, IsActive: true}
+	resident := &Resident{ID: "res-1", Status: ResidentStatusActive, ClearanceLevel: 3}
+	grant := &AccessGrant{ResidentID: "res-1", AccessPointID: "ap-1", GrantedAt: at.Add(-time.Hour)}
+
+	tests := []struct {
+		name     string
+		point    AccessPoint
+		resident *Resident
+		grants   []*AccessGrant
+		granted  bool
+		reason   AccessReason
+	}{
+		{"Cleared", *point, &Resident{ID: "res-2", Status: ResidentStatusActive, ClearanceLevel: 7}, nil, true, AccessReasonClearance},
+		{"Granted", *point, resident, []*AccessGrant{grant}, true, AccessReasonGrant},
+		{"Grant for another point", *point, resident, []*AccessGrant{{ResidentID: "res-1", AccessPointID: "ap-2", GrantedAt: grant.GrantedAt}}, false, AccessReasonInsufficient},
+		{"Insufficient", *point, resident, nil, false, AccessReasonInsufficient},
+		{"Unknown credential", *point, nil, nil, false, AccessReasonUnknownCredential},
+		{"Quarantined", *point, &Resident{ID: "res-1", Status: ResidentStatusQuarantine, ClearanceLevel: 9}, nil, false, AccessReasonResidentInactive},
+		{"Disabled point", AccessPoint{ID: "ap-1", MinClearance: 1}, resident, nil, false, AccessReasonPointDisabled},
+	}
+	for _, tt := range tests {
+		granted, reason := DecideAccess(&tt.point, tt.resident, tt.grants, at)
+		if granted != tt.granted || reason != tt.reason {
+			t.Errorf("%s: DecideAccess() = %v, %s, want %v, %s", tt.name, granted, reason, tt.granted, tt.reason)
+		}
+	}
+}
+
+func TestAccessEvent_Validate(t *testing.T) {
+	event := &AccessEvent{ID: "ae-1", AccessPointID: "ap-1", OccurredAt: time.Now(), Granted: true, Reason: AccessReasonGrant}
+	if err := event.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	event.Reason = AccessReasonInsufficient
+	if err := event.Validate(); err == nil {
+		t.Error("Validate() should reject a granted event with a denial reason")
+	}
+}
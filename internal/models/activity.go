@@ -0,0 +1,294 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Default ages of school classes: from six until residents sit the
+// aptitude assessment.
+const (
+	SchoolMinAge = 6
+	SchoolMaxAge = AptitudeTestAge - 1
+)
+
+// VenueType classifies a recreational or educational facility.
+type VenueType string
+
+const (
+	VenueTypeClassroom VenueType = "CLASSROOM"
+	VenueTypeWorkshop  VenueType = "WORKSHOP"
+	VenueTypeGymnasium VenueType = "GYMNASIUM"
+	VenueTypeLibrary   VenueType = "LIBRARY"
+	VenueTypeTheater   VenueType = "THEATER"
+	VenueTypeLounge    VenueType = "LOUNGE"
+)
+
+// Valid returns true if the venue type is valid.
+func (t VenueType) Valid() bool {
+	switch t {
+	case VenueTypeClassroom, VenueTypeWorkshop, VenueTypeGymnasium,
+		VenueTypeLibrary, VenueTypeTheater, VenueTypeLounge:
+		return true
+	default:
+		return false
+	}
+}
+
+// Venue is a facility classes and recreation are held in.
+type Venue struct {
+	ID        string    `json:"id"`
+	Code      string    `json:"code"`
+	Name      string    `json:"name"`
+	VenueType VenueType `json:"venue_type"`
+	Sector    string    `json:"sector"`
+	Capacity  int       `json:"capacity"` // Residents a session can hold
+	IsActive  bool      `json:"is_active"`
+	VaultID   int       `json:"vault_id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate checks if the venue data is valid.
+func (v *Venue) Validate() error {
+	if v.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if v.Code == "" {
+		return fmt.Errorf("code is required")
+	}
+	if v.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if !v.VenueType.Valid() {
+		return fmt.Errorf("invalid venue_type: %s", v.VenueType)
+	}
+	if v.Sector == "" {
+		return fmt.Errorf("sector is required")
+	}
+	if v.Capacity <= 0 {
+		return fmt.Errorf("capacity must be positive")
+	}
+	return nil
+}
+
+// ActivityType classifies a scheduled activity.
+type ActivityType string
+
+const (
+	ActivityTypeSchool     ActivityType = "SCHOOL"     // Classes for minors
+	ActivityTypeSkills     ActivityType = "SKILLS"     // Classes for adults, counting toward training in a vocation
+	ActivityTypeRecreation ActivityType = "RECREATION" // Open to all ages
+)
+
+// Valid returns true if the activity type is valid.
+func (t ActivityType) Valid() bool {
+	switch t {
+	case ActivityTypeSchool, ActivityTypeSkills, ActivityTypeRecreation:
+		return true
+	default:
+		return false
+	}
+}
+
+// Weekdays is a set of days of the week, one bit per time.Weekday.
+type Weekdays uint8
+
+// AllWeekdays holds every day of the week, and WorkWeek Monday to Friday.
+const (
+	AllWeekdays Weekdays = 1<<7 - 1
+	WorkWeek    Weekdays = AllWeekdays &^ (1<<time.Sunday | 1<<time.Saturday)
+)
+
+// weekdayNames are the abbreviations of the days, by time.Weekday.
+var weekdayNames = [7]string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}
+
+// ParseWeekdays parses a comma-separated list of day abbreviations, e.g.
+// "MON,WED,FRI", "DAILY" for every day or "WEEKDAYS" for Monday to Friday.
+func ParseWeekdays(s string) (Weekdays, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	switch s {
+	case "DAILY":
+		return AllWeekdays, nil
+	case "WEEKDAYS":
+		return WorkWeek, nil
+	}
+	var days Weekdays
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		found := false
+		for d, name := range weekdayNames {
+			if part == name {
+				days |= 1 << d
+				found = true
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("invalid weekday: %q", part)
+		}
+	}
+	return days, nil
+}
+
+// Has returns true if the set holds the day.
+func (w Weekdays) Has(d time.Weekday) bool {
+	return w&(1<<d) != 0
+}
+
+// String lists the days, Sunday first, or returns DAILY for every day and
+// WEEKDAYS for Monday to Friday.
+func (w Weekdays) String() string {
+	switch w {
+	case AllWeekdays:
+		return "DAILY"
+	case WorkWeek:
+		return "WEEKDAYS"
+	}
+	var names []string
+	for d, name := range weekdayNames {
+		if w.Has(time.Weekday(d)) {
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// Activity is a class or recreation held in a venue on a weekly schedule.
+type Activity struct {
+	ID            string       `json:"id"`
+	VenueID       string       `json:"venue_id"`
+	Code          string       `json:"code"`
+	Name          string       `json:"name"`
+	ActivityType  ActivityType `json:"activity_type"`
+	VocationID    *string      `json:"vocation_id,omitempty"`   // SKILLS classes only: attendance counts as training in it
+	InstructorID  *string      `json:"instructor_id,omitempty"` // Nil if unstaffed or the instructor's record was deleted
+	Weekdays      Weekdays     `json:"weekdays"`
+	StartHour     int          `json:"start_hour"` // Vault time, 0-23
+	DurationHours float64      `json:"duration_hours"`
+	MinAge        *int         `json:"min_age,omitempty"`
+	MaxAge        *int         `json:"max_age,omitempty"`
+	IsActive      bool         `json:"is_active"`
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+}
+
+// Validate checks if the activity data is valid.
+func (a *Activity) Validate() error {
+	if a.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if a.VenueID == "" {
+		return fmt.Errorf("venue_id is required")
+	}
+	if a.Code == "" {
+		return fmt.Errorf("code is required")
+	}
+	if a.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if !a.ActivityType.Valid() {
+		return fmt.Errorf("invalid activity_type: %s", a.ActivityType)
+	}
+	if a.VocationID != nil && a.ActivityType != ActivityTypeSkills {
+		return fmt.Errorf("only skills classes train a vocation")
+	}
+	if a.Weekdays == 0 || a.Weekdays > AllWeekdays {
+		return fmt.Errorf("weekdays are required")
+	}
+	if a.StartHour < 0 || a.StartHour > 23 {
+		return fmt.Errorf("start_hour must be between 0 and 23")
+	}
+	if a.DurationHours <= 0 || a.DurationHours > 12 {
+		return fmt.Errorf("duration_hours must be more than 0 and at most 12")
+	}
+	if a.MinAge != nil && *a.MinAge < 0 {
+		return fmt.Errorf("min_age cannot be negative")
+	}
+	if a.MinAge != nil && a.MaxAge != nil && *a.MaxAge < *a.MinAge {
+		return fmt.Errorf("max_age cannot be below min_age")
+	}
+	return nil
+}
+
+// AdmitsAge returns true if a resident of the age may enroll.
+func (a *Activity) AdmitsAge(age int) bool {
+	if a.MinAge != nil && age < *a.MinAge {
+		return false
+	}
+	if a.MaxAge != nil && age > *a.MaxAge {
+		return false
+	}
+	return true
+}
+
+// SessionsBetween returns the start times of the sessions after from up to
+// and including to, earliest first.
+func (a *Activity) SessionsBetween(from, to time.Time) []time.Time {
+	var sessions []time.Time
+	from, to = from.UTC(), to.UTC()
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	for ; !day.After(to); day = day.AddDate(0, 0, 1) {
+		if !a.Weekdays.Has(day.Weekday()) {
+			continue
+		}
+		start := day.Add(time.Duration(a.StartHour) * time.Hour)
+		if start.After(from) && !start.After(to) {
+			sessions = append(sessions, start)
+		}
+	}
+	return sessions
+}
+
+// Schedule describes when the activity is held, e.g. "MON,WED 09:00 2h".
+func (a *Activity) Schedule() string {
+	return fmt.Sprintf("%s %02d:00 %gh", a.Weekdays, a.StartHour, a.DurationHours)
+}
+
+// Attendance is whether an enrolled resident attended a session.
+type Attendance struct {
+	ID         string    `json:"id"`
+	ActivityID string    `json:"activity_id"`
+	ResidentID string    `json:"resident_id"`
+	SessionAt  time.Time `json:"session_at"`
+	Present    bool      `json:"present"`
+	Hours      float64   `json:"hours"` // Attended; 0 when absent
+	RecordedAt time.Time `json:"recorded_at"`
+}
+
+// Enrollee is a resident enrolled in an activity, with their latest morale
+// score.
+type Enrollee struct {
+	ResidentID string
+	Morale     int // MoraleBaseline if never scored
+}
+
+// AttendanceChance returns the chance an enrollee attends a session of an
+// activity: school is compulsory and missed only through sickness or
+// truancy, while adults turn up to classes and recreation more the higher
+// their morale, from half at 0 to nineteen in twenty at 100.
+func AttendanceChance(t ActivityType, morale int) float64 {
+	if t == ActivityTypeSchool {
+		return 0.95
+	}
+	morale = max(0, min(morale, 100))
+	return 0.5 + 0.45*float64(morale)/100
+}
+
+// ActivityAttendance is an activity's attendance over a span of sessions.
+type ActivityAttendance struct {
+	ActivityID string
+	Sessions   int
+	Present    int // Enrollee-sessions attended
+	Absent     int
+	Hours      float64 // Attended
+}
+
+// Rate returns the share of enrollee-sessions attended, 0-1, or 0 with no
+// sessions recorded.
+func (a *ActivityAttendance) Rate() float64 {
+	if a.Present+a.Absent == 0 {
+		return 0
+	}
+	return float64(a.Present) / float64(a.Present+a.Absent)
+}
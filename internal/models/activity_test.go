@@ -0,0 +1,112 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseWeekdays(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"MON,WED,FRI", "MON,WED,FRI", false},
+		{"fri, mon", "MON,FRI", false},
+		{"SUN", "SUN", false},
+		{"daily", "DAILY", false},
+		{"WEEKDAYS", "WEEKDAYS", false},
+		{"MON,TUE,WED,THU,FRI", "WEEKDAYS", false},
+		{"SUN,MON,TUE,WED,THU,FRI,SAT", "DAILY", false},
+		{"MONDAY", "", true},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseWeekdays(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseWeekdays(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if err == nil && got.String() != tt.want {
+				t.Errorf("ParseWeekdays(%q) = %s, want %s", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestActivitySessionsBetween(t *testing.T) {
+	days, _ := ParseWeekdays("MON,WED")
+	a := &Activity{Weekdays: days, StartHour: 9, DurationHours: 2}
+	monday := time.Date(2077, 10, 25, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		want     int
+	}{
+		{"Before the session", monday, monday.Add(8 * time.Hour), 0},
+		{"Up to the start", monday, monday.Add(9 * time.Hour), 1},
+		{"From the start", monday.Add(9 * time.Hour), monday.Add(24 * time.Hour), 0},
+		{"Tuesday", monday.AddDate(0, 0, 1), monday.AddDate(0, 0, 2), 0},
+		{"A week", monday, monday.AddDate(0, 0, 7), 2},
+		{"Two weeks", monday.Add(-time.Hour), monday.AddDate(0, 0, 14), 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := a.SessionsBetween(tt.from, tt.to)
+			if len(got) != tt.want {
+				t.Fatalf("SessionsBetween() = %v, want %d sessions", got, tt.want)
+			}
+			for _, s := range got {
+				if s.Hour() != 9 || !a.Weekdays.Has(s.Weekday()) {
+					t.Errorf("session at %v is off the schedule", s)
+				}
+			}
+		})
+	}
+}
+
+func TestActivityAdmitsAge(t *testing.T) {
+	minAge, maxAge := SchoolMinAge, SchoolMaxAge
+	school := &Activity{MinAge: &minAge, MaxAge: &maxAge}
+	open := &Activity{}
+
+	tests := []struct {
+		activity *Activity
+		age      int
+		want     bool
+	}{
+		{school, 5, false},
+		{school, 6, true},
+		{school, 15, true},
+		{school, 16, false},
+		{open, 0, true},
+		{open, 90, true},
+	}
+	for _, tt := range tests {
+		if got := tt.activity.AdmitsAge(tt.age); got != tt.want {
+			t.Errorf("AdmitsAge(%d) = %v, want %v", tt.age, got, tt.want)
+		}
+	}
+}
+
+func TestAttendanceChance(t *testing.T) {
+	tests := []struct {
+		activityType ActivityType
+		morale       int
+		want         float64
+	}{
+		{ActivityTypeSchool, 0, 0.95},
+		{ActivityTypeSchool, 100, 0.95},
+		{ActivityTypeSkills, 0, 0.5},
+		{ActivityTypeRecreation, 100, 0.95},
+		{ActivityTypeRecreation, 120, 0.95},
+		{ActivityTypeSkills, -10, 0.5},
+	}
+	for _, tt := range tests {
+		got := AttendanceChance(tt.activityType, tt.morale)
+		if got < tt.want-1e-9 || got > tt.want+1e-9 {
+			t.Errorf("AttendanceChance(%s, %d) = %v, want %v", tt.activityType, tt.morale, got, tt.want)
+		}
+	}
+}
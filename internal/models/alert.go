@@ -0,0 +1,308 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// AlertSeverity is how serious a recorded alert is. Informational alerts
+// are shown on the alert bar but not recorded.
+type AlertSeverity string
+
+const (
+	AlertSeverityWarning  AlertSeverity = "WARNING"
+	AlertSeverityCritical AlertSeverity = "CRITICAL"
+)
+
+// Valid returns true if the severity is valid.
+func (s AlertSeverity) Valid() bool {
+	switch s {
+	case AlertSeverityWarning, AlertSeverityCritical:
+		return true
+	default:
+		return false
+	}
+}
+
+// AtLeast returns true if the severity is min or more serious.
+func (s AlertSeverity) AtLeast(min AlertSeverity) bool {
+	return s == AlertSeverityCritical || min == AlertSeverityWarning
+}
+
+// ParseAlertSeverity reads a severity name in any case.
+func ParseAlertSeverity(name string) (AlertSeverity, error) {
+	severity := AlertSeverity(strings.ToUpper(strings.TrimSpace(name)))
+	if !severity.Valid() {
+		return "", fmt.Errorf("invalid severity %q: use warning or critical", name)
+	}
+	return severity, nil
+}
+
+// AlertStatus is where an alert is in being dealt with.
+type AlertStatus string
+
+const (
+	AlertOpen         AlertStatus = "OPEN"         // Not yet acknowledged
+	AlertAcknowledged AlertStatus = "ACKNOWLEDGED" // Someone is dealing with it
+	AlertResolved     AlertStatus = "RESOLVED"
+)
+
+// Valid returns true if the status is valid.
+func (s AlertStatus) Valid() bool {
+	switch s {
+	case AlertOpen, AlertAcknowledged, AlertResolved:
+		return true
+	default:
+		return false
+	}
+}
+
+// AlertRouteAnySource is the source of a routing rule matching every source.
+const AlertRouteAnySource = "*"
+
+// AlertRoute is a routing rule: it sends alerts of a source, from a
+// severity up, to a department or to one operator, and says who they go to
+// if left unacknowledged too long.
+type AlertRoute struct {
+	ID                 string        `json:"id"`
+	Source             string        `json:"source"` // e.g. "shortage check", or "*" for any
+	MinSeverity        AlertSeverity `json:"min_severity"`
+	Department         Department    `json:"department,omitempty"`  // Either a department
+	OperatorID         *string       `json:"operator_id,omitempty"` // or an operator
+	EscalateTo         Department    `json:"escalate_to,omitempty"` // Empty for ADMINISTRATION
+	EscalateAfterHours int           `json:"escalate_after_hours"`  // Vault hours; 0 for the configured default
+	VaultID            int           `json:"vault_id"`
+	CreatedAt          time.Time     `json:"created_at"`
+
+	// Joined fields
+	OperatorRegistry string `json:"operator_registry,omitempty"`
+}
+
+// Validate checks if the routing rule data is valid.
+func (r *AlertRoute) Validate() error {
+	if r.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if strings.TrimSpace(r.Source) == "" {
+		return fmt.Errorf("source is required")
+	}
+	if !r.MinSeverity.Valid() {
+		return fmt.Errorf("invalid minimum severity: %s", r.MinSeverity)
+	}
+	if (r.Department == "") == (r.OperatorID == nil) {
+		return fmt.Errorf("either a department or an operator is required")
+	}
+	if r.Department != "" && !slices.Contains(Departments, r.Department) {
+		return fmt.Errorf("invalid department: %s", r.Department)
+	}
+	if r.EscalateTo != "" && !slices.Contains(Departments, r.EscalateTo) {
+		return fmt.Errorf("invalid escalation department: %s", r.EscalateTo)
+	}
+	if r.EscalateAfterHours < 0 {
+		return fmt.Errorf("escalate_after_hours cannot be negative")
+	}
+	return nil
+}
+
+// Matches returns true if the rule routes alerts of the source and severity.
+func (r *AlertRoute) Matches(source string, severity AlertSeverity) bool {
+	return (r.Source == AlertRouteAnySource || strings.EqualFold(r.Source, source)) && severity.AtLeast(r.MinSeverity)
+}
+
+// Recipient describes who the rule routes alerts to: a department, or an
+// operator's registry number.
+func (r *AlertRoute) Recipient() string {
+	if r.OperatorID != nil {
+		return r.OperatorRegistry
+	}
+	return string(r.Department)
+}
+
+// EscalationDepartment returns the department the rule's alerts are
+// escalated to.
+func (r *AlertRoute) EscalationDepartment() Department {
+	if r.EscalateTo == "" {
+		return DepartmentAdministration
+	}
+	return r.EscalateTo
+}
+
+// specificity ranks rules matching the same alert: one naming its source
+// beats one for any source, one for critical alerts only beats one for
+// every severity, and one for an operator beats one for a department.
+func (r *AlertRoute) specificity() int {
+	rank := 0
+	if r.Source != AlertRouteAnySource {
+		rank += 4
+	}
+	if r.MinSeverity == AlertSeverityCritical {
+		rank += 2
+	}
+	if r.OperatorID != nil {
+		rank++
+	}
+	return rank
+}
+
+// SelectAlertRoute returns the most specific rule routing alerts of the
+// source and severity, the earliest created among equals, nil if none does.
+func SelectAlertRoute(routes []*AlertRoute, source string, severity AlertSeverity) *AlertRoute {
+	var selected *AlertRoute
+	for _, r := range routes {
+		if !r.Matches(source, severity) {
+			continue
+		}
+		if selected == nil || r.specificity() > selected.specificity() ||
+			(r.specificity() == selected.specificity() && r.CreatedAt.Before(selected.CreatedAt)) {
+			selected = r
+		}
+	}
+	return selected
+}
+
+// Alert is a warning or critical alert the simulation raised, routed to a
+// department or operator, with its acknowledgment, resolution and
+// escalation. Times are vault time.
+type Alert struct {
+	ID                  string        `json:"id"`
+	Source              string        `json:"source"` // What raised it, e.g. "environment monitoring"
+	Severity            AlertSeverity `json:"severity"`
+	Message             string        `json:"message"`
+	RaisedAt            time.Time     `json:"raised_at"`
+	RouteID             *string       `json:"route_id,omitempty"`    // Nil if no rule matched, or once the rule is removed
+	Department          Department    `json:"department,omitempty"`  // Either the department responsible
+	OperatorID          *string       `json:"operator_id,omitempty"` // or the operator
+	Status              AlertStatus   `json:"status"`
+	AcknowledgedAt      *time.Time    `json:"acknowledged_at,omitempty"`
+	AcknowledgedBy      *string       `json:"acknowledged_by,omitempty"`
+	AcknowledgmentNotes string        `json:"acknowledgment_notes,omitempty"`
+	ResolvedAt          *time.Time    `json:"resolved_at,omitempty"`
+	ResolvedBy          *string       `json:"resolved_by,omitempty"`
+	ResolutionNotes     string        `json:"resolution_notes,omitempty"`
+	EscalatedAt         *time.Time    `json:"escalated_at,omitempty"`
+	VaultID             int           `json:"vault_id"`
+	CreatedAt           time.Time     `json:"created_at"`
+
+	// Joined fields
+	OperatorRegistry     string `json:"operator_registry,omitempty"`
+	AcknowledgedRegistry string `json:"acknowledged_registry,omitempty"`
+	ResolvedRegistry     string `json:"resolved_registry,omitempty"`
+}
+
+// Validate checks if the alert data is valid.
+func (a *Alert) Validate() error {
+	if a.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if strings.TrimSpace(a.Source) == "" {
+		return fmt.Errorf("source is required")
+	}
+	if !a.Severity.Valid() {
+		return fmt.Errorf("invalid severity: %s", a.Severity)
+	}
+	if strings.TrimSpace(a.Message) == "" {
+		return fmt.Errorf("message is required")
+	}
+	if a.RaisedAt.IsZero() {
+		return fmt.Errorf("raised_at is required")
+	}
+	if (a.Department == "") == (a.OperatorID == nil) {
+		return fmt.Errorf("either a department or an operator is required")
+	}
+	if a.Department != "" && !slices.Contains(Departments, a.Department) {
+		return fmt.Errorf("invalid department: %s", a.Department)
+	}
+	if !a.Status.Valid() {
+		return fmt.Errorf("invalid status: %s", a.Status)
+	}
+	if (a.Status == AlertOpen) != (a.AcknowledgedAt == nil) {
+		return fmt.Errorf("acknowledged_at is required once, and only once, acknowledged")
+	}
+	if (a.Status == AlertResolved) != (a.ResolvedAt != nil) {
+		return fmt.Errorf("resolved_at is required for, and only for, a resolved alert")
+	}
+	if a.AcknowledgedAt != nil && a.AcknowledgedAt.Before(a.RaisedAt) {
+		return fmt.Errorf("acknowledged_at cannot be before raised_at")
+	}
+	if a.ResolvedAt != nil && a.AcknowledgedAt != nil && a.ResolvedAt.Before(*a.AcknowledgedAt) {
+		return fmt.Errorf("resolved_at cannot be before acknowledged_at")
+	}
+	return nil
+}
+
+// Route assigns the alert to the rule's recipient, or to ADMINISTRATION
+// if no rule matched.
+func (a *Alert) Route(route *AlertRoute) {
+	if route == nil {
+		a.RouteID, a.Department, a.OperatorID = nil, DepartmentAdministration, nil
+		return
+	}
+	a.RouteID = &route.ID
+	a.Department, a.OperatorID, a.OperatorRegistry = route.Department, route.OperatorID, route.OperatorRegistry
+}
+
+// Recipient describes who the alert is routed to: a department, or an
+// operator's registry number.
+func (a *Alert) Recipient() string {
+	if a.OperatorID != nil {
+		return a.OperatorRegistry
+	}
+	return string(a.Department)
+}
+
+// AddressedTo returns true if the alert is routed to the operator, or to
+// their department.
+func (a *Alert) AddressedTo(operatorID string, department Department) bool {
+	if a.OperatorID != nil {
+		return *a.OperatorID == operatorID
+	}
+	return department != "" && a.Department == department
+}
+
+// Acknowledge records that an operator has taken the open alert on.
+func (a *Alert) Acknowledge(by string, at time.Time, notes string) error {
+	if a.Status != AlertOpen {
+		return fmt.Errorf("alert is already %s", strings.ToLower(string(a.Status)))
+	}
+	if at.Before(a.RaisedAt) {
+		at = a.RaisedAt
+	}
+	a.Status = AlertAcknowledged
+	a.AcknowledgedAt, a.AcknowledgedBy = &at, &by
+	a.AcknowledgmentNotes = strings.TrimSpace(notes)
+	return nil
+}
+
+// Resolve records that an operator has dealt with the alert, acknowledging
+// it at the same time if still open.
+func (a *Alert) Resolve(by string, at time.Time, notes string) error {
+	if a.Status == AlertResolved {
+		return fmt.Errorf("alert is already resolved")
+	}
+	if a.Status == AlertOpen {
+		if err := a.Acknowledge(by, at, ""); err != nil {
+			return err
+		}
+	}
+	if at.Before(*a.AcknowledgedAt) {
+		at = *a.AcknowledgedAt
+	}
+	a.Status = AlertResolved
+	a.ResolvedAt, a.ResolvedBy = &at, &by
+	a.ResolutionNotes = strings.TrimSpace(notes)
+	return nil
+}
+
+// EscalationDue returns true if the alert has been left unacknowledged for
+// at least after by now, and has not been escalated yet.
+func (a *Alert) EscalationDue(now time.Time, after time.Duration) bool {
+	return a.Status == AlertOpen && a.EscalatedAt == nil && after > 0 && !now.Before(a.RaisedAt.Add(after))
+}
+
+// Escalate reassigns the alert to a department, marking it escalated.
+func (a *Alert) Escalate(to Department, at time.Time) {
+	a.Department, a.OperatorID, a.OperatorRegistry = to, nil, ""
+	a.EscalatedAt = &at
+}
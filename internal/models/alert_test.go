@@ -0,0 +1,123 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAlert_Validate(t *testing.T) {
+	raised := time.Date(2077, 10, 23, 9, 0, 0, 0, time.UTC)
+	operator := "resident-1"
+	valid := func() *Alert {
+		return &Alert{
+			ID:         "alert-1",
+			Source:     "shortage check",
+			Severity:   AlertSeverityWarning,
+			Message:    "Purified water below minimum stock",
+			RaisedAt:   raised,
+			Department: DepartmentFoodProduction,
+			Status:     AlertOpen,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*Alert)
+		wantErr bool
+	}{
+		{"Valid open alert", func(a *Alert) {}, false},
+		{"Valid operator alert", func(a *Alert) { a.Department, a.OperatorID = "", &operator }, false},
+		{"Valid resolved alert", func(a *Alert) {
+			at := raised.Add(time.Hour)
+			a.Status, a.AcknowledgedAt, a.ResolvedAt = AlertResolved, &at, &at
+		}, false},
+		{"Missing message", func(a *Alert) { a.Message = " " }, true},
+		{"Info severity", func(a *Alert) { a.Severity = "INFO" }, true},
+		{"No recipient", func(a *Alert) { a.Department = "" }, true},
+		{"Department and operator", func(a *Alert) { a.OperatorID = &operator }, true},
+		{"Acknowledged without time", func(a *Alert) { a.Status = AlertAcknowledged }, true},
+		{"Resolved without time", func(a *Alert) {
+			at := raised.Add(time.Hour)
+			a.Status, a.AcknowledgedAt = AlertResolved, &at
+		}, true},
+		{"Acknowledged before raised", func(a *Alert) {
+			at := raised.Add(-time.Hour)
+			a.Status, a.AcknowledgedAt = AlertAcknowledged, &at
+		}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := valid()
+			tt.modify(a)
+			err := a.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSelectAlertRoute(t *testing.T) {
+	created := time.Date(2077, 10, 23, 0, 0, 0, 0, time.UTC)
+	operator := "resident-1"
+	fallback := &AlertRoute{ID: "fallback", Source: AlertRouteAnySource, MinSeverity: AlertSeverityWarning, Department: DepartmentAdministration, CreatedAt: created}
+	shortage := &AlertRoute{ID: "shortage", Source: "Shortage Check", MinSeverity: AlertSeverityWarning, Department: DepartmentFoodProduction, CreatedAt: created}
+	critical := &AlertRoute{ID: "critical", Source: "shortage check", MinSeverity: AlertSeverityCritical, OperatorID: &operator, CreatedAt: created}
+	later := &AlertRoute{ID: "later", Source: "shortage check", MinSeverity: AlertSeverityWarning, Department: DepartmentResearch, CreatedAt: created.Add(time.Hour)}
+	routes := []*AlertRoute{later, critical, fallback, shortage}
+
+	tests := []struct {
+		source   string
+		severity AlertSeverity
+		want     string
+	}{
+		{"shortage check", AlertSeverityWarning, "shortage"},
+		{"shortage check", AlertSeverityCritical, "critical"},
+		{"environment monitoring", AlertSeverityCritical, "fallback"},
+	}
+	for _, tt := range tests {
+		if got := SelectAlertRoute(routes, tt.source, tt.severity); got == nil || got.ID != tt.want {
+			t.Errorf("SelectAlertRoute(%s, %s) = %+v, want %s", tt.source, tt.severity, got, tt.want)
+		}
+	}
+	if got := SelectAlertRoute([]*AlertRoute{critical}, "shortage check", AlertSeverityWarning); got != nil {
+		t.Errorf("SelectAlertRoute() = %s, want no rule for a warning", got.ID)
+	}
+}
+
+func TestAlert_Workflow(t *testing.T) {
+	raised := time.Date(2077, 10, 23, 9, 0, 0, 0, time.UTC)
+	a := &Alert{ID: "alert-1", Source: "morale", Severity: AlertSeverityWarning, Message: "Morale falling", RaisedAt: raised, Status: AlertOpen}
+	a.Route(nil)
+	if a.Department != DepartmentAdministration {
+		t.Errorf("unrouted alert went to %s, want ADMINISTRATION", a.Department)
+	}
+
+	if a.EscalationDue(raised.Add(3*time.Hour), 4*time.Hour) {
+		t.Error("EscalationDue() before the escalation time")
+	}
+	if !a.EscalationDue(raised.Add(4*time.Hour), 4*time.Hour) {
+		t.Error("EscalationDue() = false once the escalation time passed")
+	}
+	a.Escalate(DepartmentSecurity, raised.Add(4*time.Hour))
+	if a.EscalationDue(raised.Add(9*time.Hour), 4*time.Hour) {
+		t.Error("EscalationDue() for an alert already escalated")
+	}
+
+	if err := a.Resolve("resident-1", raised.Add(5*time.Hour), "Counselling scheduled"); err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if a.Status != AlertResolved || a.AcknowledgedAt == nil || !a.AcknowledgedAt.Equal(*a.ResolvedAt) {
+		t.Errorf("resolving an open alert left %+v, want it acknowledged and resolved", a)
+	}
+	if err := a.Validate(); err != nil {
+		t.Errorf("Validate() after resolving error = %v", err)
+	}
+	if err := a.Acknowledge("resident-1", raised.Add(6*time.Hour), ""); err == nil {
+		t.Error("Acknowledge() of a resolved alert succeeded")
+	}
+	if err := a.Resolve("resident-1", raised.Add(6*time.Hour), ""); err == nil {
+		t.Error("Resolve() of a resolved alert succeeded")
+	}
+}
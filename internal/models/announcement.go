@@ -0,0 +1,165 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// AnnouncementKind represents who an announcement comes from.
+type AnnouncementKind string
+
+const (
+	AnnouncementBroadcast AnnouncementKind = "BROADCAST" // From the overseer
+	AnnouncementNotice    AnnouncementKind = "NOTICE"    // From a department
+	AnnouncementSystem    AnnouncementKind = "SYSTEM"    // Generated by the simulation
+)
+
+// Valid returns true if the announcement kind is valid.
+func (k AnnouncementKind) Valid() bool {
+	switch k {
+	case AnnouncementBroadcast, AnnouncementNotice, AnnouncementSystem:
+		return true
+	default:
+		return false
+	}
+}
+
+// AnnouncementAudience represents who an announcement is addressed to.
+type AnnouncementAudience string
+
+const (
+	AudienceAll        AnnouncementAudience = "ALL"        // Every resident of the vault
+	AudienceDepartment AnnouncementAudience = "DEPARTMENT" // Residents whose primary vocation is in the department
+	AudienceHousehold  AnnouncementAudience = "HOUSEHOLD"  // Members of the household
+)
+
+// Valid returns true if the audience is valid.
+func (a AnnouncementAudience) Valid() bool {
+	switch a {
+	case AudienceAll, AudienceDepartment, AudienceHousehold:
+		return true
+	default:
+		return false
+	}
+}
+
+// ParseAnnouncementAudience reads an audience name in any case.
+func ParseAnnouncementAudience(name string) (AnnouncementAudience, error) {
+	audience := AnnouncementAudience(strings.ToUpper(strings.TrimSpace(name)))
+	if !audience.Valid() {
+		return "", fmt.Errorf("invalid audience %q: use all, department or household", name)
+	}
+	return audience, nil
+}
+
+// ParseDepartment reads a department name in any case.
+func ParseDepartment(name string) (Department, error) {
+	department := Department(strings.ToUpper(strings.TrimSpace(name)))
+	if !slices.Contains(Departments, department) {
+		return "", fmt.Errorf("invalid department %q", name)
+	}
+	return department, nil
+}
+
+// Announcement is an overseer broadcast, department notice or system notice
+// addressed to the whole vault, one department or one household. Residents
+// acknowledge the announcements they have read.
+type Announcement struct {
+	ID          string               `json:"id"`
+	Kind        AnnouncementKind     `json:"kind"`
+	Title       string               `json:"title"`
+	Body        string               `json:"body,omitempty"`
+	Audience    AnnouncementAudience `json:"audience"`
+	Department  Department           `json:"department,omitempty"`   // For a DEPARTMENT audience
+	HouseholdID *string              `json:"household_id,omitempty"` // For a HOUSEHOLD audience
+	IssuedBy    *string              `json:"issued_by,omitempty"`    // NULL for system notices
+	IssuedAt    time.Time            `json:"issued_at"`
+	ExpiresAt   *time.Time           `json:"expires_at,omitempty"`
+	Source      string               `json:"source,omitempty"` // What raised a system notice, e.g. "maintenance:<id>"
+	VaultID     int                  `json:"vault_id"`
+	CreatedAt   time.Time            `json:"created_at"`
+
+	// Joined fields
+	IssuerRegistry       string `json:"issuer_registry,omitempty"`
+	HouseholdDesignation string `json:"household_designation,omitempty"`
+	Recipients           int    `json:"recipients"`             // Active residents addressed
+	Acknowledgments      int    `json:"acknowledgments"`        // Residents who acknowledged
+	Acknowledged         bool   `json:"acknowledged,omitempty"` // By the resident whose inbox was read
+}
+
+// Validate checks if the announcement data is valid.
+func (a *Announcement) Validate() error {
+	if a.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if !a.Kind.Valid() {
+		return fmt.Errorf("invalid kind: %s", a.Kind)
+	}
+	if strings.TrimSpace(a.Title) == "" {
+		return fmt.Errorf("title is required")
+	}
+	if !a.Audience.Valid() {
+		return fmt.Errorf("invalid audience: %s", a.Audience)
+	}
+	if (a.Audience == AudienceDepartment) != (a.Department != "") {
+		return fmt.Errorf("department is required for, and only for, a department audience")
+	}
+	if a.Department != "" && !slices.Contains(Departments, a.Department) {
+		return fmt.Errorf("invalid department: %s", a.Department)
+	}
+	if (a.Audience == AudienceHousehold) != (a.HouseholdID != nil) {
+		return fmt.Errorf("household_id is required for, and only for, a household audience")
+	}
+	if a.Kind == AnnouncementSystem && a.IssuedBy != nil {
+		return fmt.Errorf("system notices have no issuer")
+	}
+	if a.IssuedAt.IsZero() {
+		return fmt.Errorf("issued_at is required")
+	}
+	if a.ExpiresAt != nil && !a.ExpiresAt.After(a.IssuedAt) {
+		return fmt.Errorf("expires_at must be after issued_at")
+	}
+	return nil
+}
+
+// ActiveAt returns true if the announcement is posted at t: issued and not
+// yet expired.
+func (a *Announcement) ActiveAt(t time.Time) bool {
+	if a.IssuedAt.After(t) {
+		return false
+	}
+	return a.ExpiresAt == nil || t.Before(*a.ExpiresAt)
+}
+
+// Addresses returns true if the announcement reaches a resident of the
+// household and department given, either empty if the resident has none.
+func (a *Announcement) Addresses(householdID string, department Department) bool {
+	switch a.Audience {
+	case AudienceAll:
+		return true
+	case AudienceDepartment:
+		return department != "" && a.Department == department
+	case AudienceHousehold:
+		return householdID != "" && a.HouseholdID != nil && *a.HouseholdID == householdID
+	default:
+		return false
+	}
+}
+
+// AudienceLabel returns who the announcement is addressed to, e.g. "ALL",
+// "ENGINEERING" or "H-0042".
+func (a *Announcement) AudienceLabel() string {
+	switch a.Audience {
+	case AudienceDepartment:
+		return string(a.Department)
+	case AudienceHousehold:
+		if a.HouseholdDesignation != "" {
+			return a.HouseholdDesignation
+		}
+		return "HOUSEHOLD"
+	default:
+		return string(a.Audience)
+	}
+}
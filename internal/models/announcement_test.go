@@ -0,0 +1,106 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAnnouncement_Validate(t *testing.T) {
+	issued := time.Date(2077, 10, 23, 9, 0, 0, 0, time.UTC)
+	household := "household-1"
+	overseer := "resident-1"
+	valid := func() *Announcement {
+		return &Announcement{
+			ID:       "announcement-1",
+			Kind:     AnnouncementBroadcast,
+			Title:    "Water chip inspection",
+			Audience: AudienceAll,
+			IssuedBy: &overseer,
+			IssuedAt: issued,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*Announcement)
+		wantErr bool
+	}{
+		{"Valid broadcast", func(a *Announcement) {}, false},
+		{"Valid department notice", func(a *Announcement) {
+			a.Kind, a.Audience, a.Department = AnnouncementNotice, AudienceDepartment, DepartmentEngineering
+		}, false},
+		{"Valid household notice", func(a *Announcement) { a.Audience, a.HouseholdID = AudienceHousehold, &household }, false},
+		{"Valid system notice", func(a *Announcement) { a.Kind, a.IssuedBy, a.Source = AnnouncementSystem, nil, "maintenance:1" }, false},
+		{"Missing title", func(a *Announcement) { a.Title = "  " }, true},
+		{"Invalid kind", func(a *Announcement) { a.Kind = "MEMO" }, true},
+		{"Invalid audience", func(a *Announcement) { a.Audience = "SECTOR" }, true},
+		{"Department audience without department", func(a *Announcement) { a.Audience = AudienceDepartment }, true},
+		{"Department on an ALL audience", func(a *Announcement) { a.Department = DepartmentMedical }, true},
+		{"Invalid department", func(a *Announcement) { a.Audience, a.Department = AudienceDepartment, "CATERING" }, true},
+		{"Household audience without household", func(a *Announcement) { a.Audience = AudienceHousehold }, true},
+		{"System notice with issuer", func(a *Announcement) { a.Kind = AnnouncementSystem }, true},
+		{"Missing issued_at", func(a *Announcement) { a.IssuedAt = time.Time{} }, true},
+		{"Expires before issue", func(a *Announcement) {
+			expires := issued.Add(-time.Hour)
+			a.ExpiresAt = &expires
+		}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := valid()
+			tt.modify(a)
+			err := a.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAnnouncement_Addresses(t *testing.T) {
+	household := "household-1"
+	tests := []struct {
+		name         string
+		announcement *Announcement
+		household    string
+		department   Department
+		want         bool
+	}{
+		{"Everyone", &Announcement{Audience: AudienceAll}, "", "", true},
+		{"Department member", &Announcement{Audience: AudienceDepartment, Department: DepartmentMedical}, "household-2", DepartmentMedical, true},
+		{"Other department", &Announcement{Audience: AudienceDepartment, Department: DepartmentMedical}, "household-2", DepartmentSecurity, false},
+		{"No vocation", &Announcement{Audience: AudienceDepartment, Department: DepartmentMedical}, "household-2", "", false},
+		{"Household member", &Announcement{Audience: AudienceHousehold, HouseholdID: &household}, "household-1", "", true},
+		{"Other household", &Announcement{Audience: AudienceHousehold, HouseholdID: &household}, "household-2", "", false},
+		{"No household", &Announcement{Audience: AudienceHousehold, HouseholdID: &household}, "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.announcement.Addresses(tt.household, tt.department); got != tt.want {
+				t.Errorf("Addresses() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnnouncement_ActiveAt(t *testing.T) {
+	issued := time.Date(2077, 10, 23, 9, 0, 0, 0, time.UTC)
+	expires := issued.AddDate(0, 0, 2)
+	a := &Announcement{IssuedAt: issued, ExpiresAt: &expires}
+
+	if a.ActiveAt(issued.Add(-time.Minute)) {
+		t.Error("ActiveAt() before issue = true, want false")
+	}
+	if !a.ActiveAt(issued) {
+		t.Error("ActiveAt() at issue = false, want true")
+	}
+	if a.ActiveAt(expires) {
+		t.Error("ActiveAt() at expiry = true, want false")
+	}
+	a.ExpiresAt = nil
+	if !a.ActiveAt(issued.AddDate(10, 0, 0)) {
+		t.Error("ActiveAt() without expiry = false, want true")
+	}
+}
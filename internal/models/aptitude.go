@@ -0,0 +1,100 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AptitudeTestAge is the age residents sit the aptitude assessment at.
+const AptitudeTestAge = 16
+
+// AptitudeScores are a resident's aptitude scores, 0-100, by department.
+type AptitudeScores map[Department]int
+
+// ParseAptitudeScores reads one score per department, in the order of
+// Departments, separated by spaces or commas.
+func ParseAptitudeScores(input string) (AptitudeScores, error) {
+	fields := strings.FieldsFunc(input, func(r rune) bool { return r == ' ' || r == ',' })
+	if len(fields) != len(Departments) {
+		return nil, fmt.Errorf("expected %d scores, got %d", len(Departments), len(fields))
+	}
+
+	scores := make(AptitudeScores, len(Departments))
+	for i, f := range fields {
+		score, err := strconv.Atoi(f)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s score %q", Departments[i], f)
+		}
+		scores[Departments[i]] = score
+	}
+	return scores, scores.Validate()
+}
+
+// Validate checks that every department has a score of 0-100.
+func (s AptitudeScores) Validate() error {
+	for _, dept := range Departments {
+		score, ok := s[dept]
+		if !ok {
+			return fmt.Errorf("%s score is required", dept)
+		}
+		if score < 0 || score > 100 {
+			return fmt.Errorf("%s score must be 0-100, got %d", dept, score)
+		}
+	}
+	if len(s) != len(Departments) {
+		return fmt.Errorf("scores include an unknown department")
+	}
+	return nil
+}
+
+// Ranked returns the departments from highest score to lowest, ties in the
+// order of Departments.
+func (s AptitudeScores) Ranked() []Department {
+	ranked := make([]Department, len(Departments))
+	copy(ranked, Departments)
+	sort.SliceStable(ranked, func(i, j int) bool { return s[ranked[i]] > s[ranked[j]] })
+	return ranked
+}
+
+// AptitudeAssessment is the result of a resident's vocational aptitude test
+// with the vocation it recommends.
+type AptitudeAssessment struct {
+	ID                    string         `json:"id"`
+	ResidentID            string         `json:"resident_id"`
+	AssessedAt            time.Time      `json:"assessed_at"`
+	AdministeredBy        *string        `json:"administered_by,omitempty"`
+	Scores                AptitudeScores `json:"scores"`
+	RecommendedVocationID *string        `json:"recommended_vocation_id,omitempty"` // Nil when no vocation was open
+	AppliedAt             *time.Time     `json:"applied_at,omitempty"`
+	Notes                 string         `json:"notes,omitempty"`
+	CreatedAt             time.Time      `json:"created_at"`
+	UpdatedAt             time.Time      `json:"updated_at"`
+}
+
+// Validate checks if the assessment data is valid.
+func (a *AptitudeAssessment) Validate() error {
+	if a.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if a.ResidentID == "" {
+		return fmt.Errorf("resident_id is required")
+	}
+	if a.AssessedAt.IsZero() {
+		return fmt.Errorf("assessed_at is required")
+	}
+	if err := a.Scores.Validate(); err != nil {
+		return err
+	}
+	if a.AppliedAt != nil && a.RecommendedVocationID == nil {
+		return fmt.Errorf("an assessment without a recommendation cannot be applied")
+	}
+	return nil
+}
+
+// IsApplied returns true if the recommended vocation has been assigned.
+func (a *AptitudeAssessment) IsApplied() bool {
+	return a.AppliedAt != nil
+}
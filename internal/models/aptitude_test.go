@@ -0,0 +1,84 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseAptitudeScores(t *testing.T) {
+	scores, err := ParseAptitudeScores("72, 55 40 90 61,33 20 85")
+	if err != nil {
+		t.Fatalf("ParseAptitudeScores() error = %v", err)
+	}
+	if scores[DepartmentEngineering] != 72 || scores[DepartmentFoodProduction] != 90 || scores[DepartmentResearch] != 85 {
+		t.Errorf("ParseAptitudeScores() = %v", scores)
+	}
+
+	for _, input := range []string{"72 55 40", "72 55 40 90 61 33 20 x", "72 55 40 90 61 33 20 101"} {
+		if _, err := ParseAptitudeScores(input); err == nil {
+			t.Errorf("ParseAptitudeScores(%q) expected error", input)
+		}
+	}
+}
+
+func TestAptitudeScores_Ranked(t *testing.T) {
+	scores := AptitudeScores{}
+	for _, dept := range Departments {
+		scores[dept] = 50
+	}
+	scores[DepartmentMedical] = 80
+	scores[DepartmentResearch] = 80
+	scores[DepartmentSanitation] = 10
+
+	ranked := scores.Ranked()
+	want := []Department{DepartmentMedical, DepartmentResearch, DepartmentEngineering}
+	if !reflect.DeepEqual(ranked[:3], want) {
+		t.Errorf("Ranked()[:3] = %v, want %v", ranked[:3], want)
+	}
+	if ranked[len(ranked)-1] != DepartmentSanitation {
+		t.Errorf("Ranked() last = %s, want SANITATION", ranked[len(ranked)-1])
+	}
+}
+
+func TestAptitudeAssessment_Validate(t *testing.T) {
+	vocation := "voc-1"
+	applied := time.Date(2078, 6, 2, 0, 0, 0, 0, time.UTC)
+	valid := func() *AptitudeAssessment {
+		scores := AptitudeScores{}
+		for _, dept := range Departments {
+			scores[dept] = 50
+		}
+		return &AptitudeAssessment{
+			ID:         "apt-1",
+			ResidentID: "res-1",
+			AssessedAt: time.Date(2078, 6, 1, 9, 0, 0, 0, time.UTC),
+			Scores:     scores,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*AptitudeAssessment)
+		wantErr bool
+	}{
+		{"Valid", func(a *AptitudeAssessment) {}, false},
+		{"Missing resident", func(a *AptitudeAssessment) { a.ResidentID = "" }, true},
+		{"Missing score", func(a *AptitudeAssessment) { delete(a.Scores, DepartmentEducation) }, true},
+		{"Score out of range", func(a *AptitudeAssessment) { a.Scores[DepartmentSecurity] = -5 }, true},
+		{"Applied without recommendation", func(a *AptitudeAssessment) { a.AppliedAt = &applied }, true},
+		{"Applied", func(a *AptitudeAssessment) {
+			a.RecommendedVocationID, a.AppliedAt = &vocation, &applied
+		}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := valid()
+			tt.modify(a)
+			if err := a.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// ArmoryApprovalClearance is the lowest clearance of an officer who may
+// approve the issue of a weapon, whatever the weapon's authorization level.
+const ArmoryApprovalClearance = 7
+
+// ArmoryWeapon is the armory record of a WEAPON asset: the clearance a
+// resident needs to be issued it and the ammunition it fires, a resource
+// item drawn from stock as rounds are expended.
+type ArmoryWeapon struct {
+	AssetID            string    `json:"asset_id"`
+	AuthorizationLevel int       `json:"authorization_level"` // Clearance needed to carry it
+	AmmoItemID         *string   `json:"ammo_item_id,omitempty"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+
+	// Joined fields
+	Asset    *Asset        `json:"asset,omitempty"`
+	AmmoItem *ResourceItem `json:"ammo_item,omitempty"`
+}
+
+// Validate checks if the weapon data is valid.
+func (w *ArmoryWeapon) Validate() error {
+	if w.AssetID == "" {
+		return fmt.Errorf("asset_id is required")
+	}
+	if w.AuthorizationLevel < 1 || w.AuthorizationLevel > 10 {
+		return fmt.Errorf("authorization_level must be between 1 and 10")
+	}
+	return nil
+}
+
+// CheckWeaponIssue checks that a resident may be issued a weapon on an
+// officer's approval: both active, the resident holding the weapon's
+// authorization level and the officer, someone else, at least that and
+// ArmoryApprovalClearance.
+func CheckWeaponIssue(w *ArmoryWeapon, resident, approver *Resident) error {
+	switch {
+	case resident.Status != ResidentStatusActive:
+		return fmt.Errorf("resident %s is %s", resident.RegistryNumber, resident.Status)
+	case resident.ClearanceLevel < w.AuthorizationLevel:
+		return fmt.Errorf("resident %s holds clearance %d; the weapon needs %d",
+			resident.RegistryNumber, resident.ClearanceLevel, w.AuthorizationLevel)
+	case approver.ID == resident.ID:
+		return fmt.Errorf("resident %s cannot approve their own issue", resident.RegistryNumber)
+	case approver.Status != ResidentStatusActive:
+		return fmt.Errorf("approving officer %s is %s", approver.RegistryNumber, approver.Status)
+	}
+	if need := max(w.AuthorizationLevel, ArmoryApprovalClearance); approver.ClearanceLevel < need {
+		return fmt.Errorf("approving officer %s holds clearance %d; approval needs %d",
+			approver.RegistryNumber, approver.ClearanceLevel, need)
+	}
+	return nil
+}
+
+// ArmoryIssue is the armory's record of a weapon custody: who approved it,
+// the open security incident or drill it was issued for, and the rounds
+// issued with it and expended by its return.
+type ArmoryIssue struct {
+	CustodyID      string    `json:"custody_id"`
+	ApprovedBy     *string   `json:"approved_by,omitempty"`
+	IncidentID     *string   `json:"incident_id,omitempty"`
+	Drill          bool      `json:"drill"` // Issued for a drill rather than an incident
+	RoundsIssued   int       `json:"rounds_issued"`
+	RoundsExpended *int      `json:"rounds_expended,omitempty"` // Nil until returned
+	CreatedAt      time.Time `json:"created_at"`
+
+	// Joined fields
+	IncidentNumber   string        `json:"incident_number,omitempty"`
+	ApproverRegistry string        `json:"approver_registry,omitempty"`
+	Asset            *Asset        `json:"asset,omitempty"`
+	Custody          *AssetCustody `json:"custody,omitempty"`
+}
+
+// Validate checks if the issue data is valid.
+func (i *ArmoryIssue) Validate() error {
+	if i.CustodyID == "" {
+		return fmt.Errorf("custody_id is required")
+	}
+	if (i.IncidentID == nil) == !i.Drill {
+		return fmt.Errorf("an issue is for either an incident or a drill")
+	}
+	if i.RoundsIssued < 0 {
+		return fmt.Errorf("rounds_issued cannot be negative")
+	}
+	if i.RoundsExpended != nil && (*i.RoundsExpended < 0 || *i.RoundsExpended > i.RoundsIssued) {
+		return fmt.Errorf("rounds_expended must be between 0 and the %d rounds issued", i.RoundsIssued)
+	}
+	return nil
+}
+
+// Purpose returns the incident number the weapon was issued for, or DRILL.
+func (i *ArmoryIssue) Purpose() string {
+	if i.Drill {
+		return "DRILL"
+	}
+	return i.IncidentNumber
+}
@@ -0,0 +1,72 @@
+package models
+
+import "testing"
+
+func TestCheckWeaponIssue(t *testing.T) {
+	weapon := &ArmoryWeapon{AssetID: "asset-1", AuthorizationLevel: 5}
+	resident := func(id string, clearance int) *Resident {
+		return &Resident{ID: id, RegistryNumber: "V076-" + id, Status: ResidentStatusActive, ClearanceLevel: clearance}
+	}
+
+	tests := []struct {
+		name     string
+		resident *Resident
+		approver *Resident
+		wantErr  bool
+	}{
+		{"Cleared resident and officer", resident("00001", 5), resident("00002", 7), false},
+		{"Resident below authorization", resident("00001", 4), resident("00002", 9), true},
+		{"Resident not active", &Resident{ID: "00001", Status: ResidentStatusQuarantine, ClearanceLevel: 8}, resident("00002", 9), true},
+		{"Self approval", resident("00001", 9), resident("00001", 9), true},
+		{"Officer below approval clearance", resident("00001", 5), resident("00002", 6), true},
+		{"Officer not active", resident("00001", 5), &Resident{ID: "00002", Status: ResidentStatusDeceased, ClearanceLevel: 9}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckWeaponIssue(weapon, tt.resident, tt.approver)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckWeaponIssue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// A weapon above the approval clearance needs an officer holding its level
+	heavy := &ArmoryWeapon{AssetID: "asset-2", AuthorizationLevel: 9}
+	if err := CheckWeaponIssue(heavy, resident("00001", 9), resident("00002", 8)); err == nil {
+		t.Error("CheckWeaponIssue() expected error for an officer below the weapon's level")
+	}
+}
+
+func TestArmoryIssue_Validate(t *testing.T) {
+	incident := "inc-1"
+	rounds := func(n int) *int { return &n }
+	valid := func() *ArmoryIssue {
+		return &ArmoryIssue{CustodyID: "custody-1", IncidentID: &incident, RoundsIssued: 30}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*ArmoryIssue)
+		wantErr bool
+	}{
+		{"Valid incident issue", func(i *ArmoryIssue) {}, false},
+		{"Valid drill issue", func(i *ArmoryIssue) { i.IncidentID, i.Drill = nil, true }, false},
+		{"Neither incident nor drill", func(i *ArmoryIssue) { i.IncidentID = nil }, true},
+		{"Both incident and drill", func(i *ArmoryIssue) { i.Drill = true }, true},
+		{"Missing custody", func(i *ArmoryIssue) { i.CustodyID = "" }, true},
+		{"Negative rounds", func(i *ArmoryIssue) { i.RoundsIssued = -1 }, true},
+		{"Rounds expended", func(i *ArmoryIssue) { i.RoundsExpended = rounds(12) }, false},
+		{"More expended than issued", func(i *ArmoryIssue) { i.RoundsExpended = rounds(31) }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := valid()
+			tt.modify(i)
+			if err := i.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AssetCategory classifies an individually tracked asset.
+type AssetCategory string
+
+const (
+	AssetCategoryTool        AssetCategory = "TOOL"
+	AssetCategoryWeapon      AssetCategory = "WEAPON"
+	AssetCategoryPipBoy      AssetCategory = "PIP_BOY"
+	AssetCategoryElectronics AssetCategory = "ELECTRONICS"
+	AssetCategoryMedical     AssetCategory = "MEDICAL"
+	AssetCategoryProtective  AssetCategory = "PROTECTIVE" // Armor, hazmat and power suits
+	AssetCategoryOther       AssetCategory = "OTHER"
+)
+
+// AssetCategories lists the asset categories in display order.
+var AssetCategories = []AssetCategory{
+	AssetCategoryTool, AssetCategoryWeapon, AssetCategoryPipBoy, AssetCategoryElectronics,
+	AssetCategoryMedical, AssetCategoryProtective, AssetCategoryOther,
+}
+
+// Valid returns true if the asset category is valid.
+func (c AssetCategory) Valid() bool {
+	for _, category := range AssetCategories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseAssetCategory reads a category name in any case, accepting PIPBOY
+// and PIP-BOY for PIP_BOY.
+func ParseAssetCategory(name string) (AssetCategory, error) {
+	normalized := strings.ToUpper(strings.TrimSpace(name))
+	if normalized == "PIPBOY" || normalized == "PIP-BOY" {
+		normalized = string(AssetCategoryPipBoy)
+	}
+	category := AssetCategory(normalized)
+	if !category.Valid() {
+		return "", fmt.Errorf("invalid asset category %q", name)
+	}
+	return category, nil
+}
+
+// AssetCondition grades the state of an asset.
+type AssetCondition string
+
+const (
+	AssetConditionExcellent AssetCondition = "EXCELLENT"
+	AssetConditionGood      AssetCondition = "GOOD"
+	AssetConditionFair      AssetCondition = "FAIR"
+	AssetConditionPoor      AssetCondition = "POOR"
+	AssetConditionBroken    AssetCondition = "BROKEN" // Cannot be issued until repaired
+)
+
+// Valid returns true if the asset condition is valid.
+func (c AssetCondition) Valid() bool {
+	switch c {
+	case AssetConditionExcellent, AssetConditionGood, AssetConditionFair,
+		AssetConditionPoor, AssetConditionBroken:
+		return true
+	default:
+		return false
+	}
+}
+
+// Serviceable returns true if an asset in this condition may be issued.
+func (c AssetCondition) Serviceable() bool {
+	return c.Valid() && c != AssetConditionBroken
+}
+
+// ParseAssetCondition reads a condition name in any case.
+func ParseAssetCondition(name string) (AssetCondition, error) {
+	condition := AssetCondition(strings.ToUpper(strings.TrimSpace(name)))
+	if !condition.Valid() {
+		return "", fmt.Errorf("invalid asset condition %q: use excellent, good, fair, poor or broken", name)
+	}
+	return condition, nil
+}
+
+// Asset is a non-consumable item tracked individually by serial number,
+// such as a tool, a weapon or a Pip-Boy, unlike consumable stock, which is
+// tracked by lot and quantity.
+type Asset struct {
+	ID           string         `json:"id"`
+	SerialNumber string         `json:"serial_number"` // "PB3000-0042"
+	Name         string         `json:"name"`
+	Category     AssetCategory  `json:"category"`
+	Condition    AssetCondition `json:"condition"`
+	Location     string         `json:"location,omitempty"` // Where it is stored when not in custody
+	AcquiredAt   *time.Time     `json:"acquired_at,omitempty"`
+	RetiredAt    *time.Time     `json:"retired_at,omitempty"`
+	Notes        string         `json:"notes,omitempty"`
+	VaultID      int            `json:"vault_id"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+
+	// Populated by the service
+	Custody *AssetCustody `json:"custody,omitempty"` // Open custody, nil when in store
+}
+
+// Validate checks if the asset data is valid.
+func (a *Asset) Validate() error {
+	if a.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if strings.TrimSpace(a.SerialNumber) == "" {
+		return fmt.Errorf("serial_number is required")
+	}
+	if a.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if !a.Category.Valid() {
+		return fmt.Errorf("invalid category: %s", a.Category)
+	}
+	if !a.Condition.Valid() {
+		return fmt.Errorf("invalid condition: %s", a.Condition)
+	}
+	if a.AcquiredAt != nil && a.RetiredAt != nil && a.RetiredAt.Before(*a.AcquiredAt) {
+		return fmt.Errorf("retired_at cannot be before acquired_at")
+	}
+	return nil
+}
+
+// IsRetired returns true if the asset has been written off.
+func (a *Asset) IsRetired() bool {
+	return a.RetiredAt != nil
+}
+
+// IsAvailable returns true if the asset can be issued: in service, in
+// store and in serviceable condition.
+func (a *Asset) IsAvailable() bool {
+	return !a.IsRetired() && a.Custody == nil && a.Condition.Serviceable()
+}
+
+// AssetCustody is a period an asset is in a resident's custody. A check-out
+// has a due date; a custodianship assignment, such as a resident's own
+// Pip-Boy, has none and runs until the asset is checked back in.
+type AssetCustody struct {
+	ID           string         `json:"id"`
+	AssetID      string         `json:"asset_id"`
+	ResidentID   string         `json:"resident_id"`
+	IssuedAt     time.Time      `json:"issued_at"`
+	IssuedBy     *string        `json:"issued_by,omitempty"`
+	DueAt        *time.Time     `json:"due_at,omitempty"` // Nil for an assignment
+	ConditionOut AssetCondition `json:"condition_out"`
+	ReturnedAt   *time.Time     `json:"returned_at,omitempty"`
+	ReceivedBy   *string        `json:"received_by,omitempty"`
+	ConditionIn  AssetCondition `json:"condition_in,omitempty"` // Set on return
+	Notes        string         `json:"notes,omitempty"`
+	CreatedAt    time.Time      `json:"created_at"`
+
+	// Joined fields
+	Resident *Resident `json:"resident,omitempty"`
+}
+
+// Validate checks if the custody data is valid.
+func (c *AssetCustody) Validate() error {
+	if c.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if c.AssetID == "" {
+		return fmt.Errorf("asset_id is required")
+	}
+	if c.ResidentID == "" {
+		return fmt.Errorf("resident_id is required")
+	}
+	if c.IssuedAt.IsZero() {
+		return fmt.Errorf("issued_at is required")
+	}
+	if !c.ConditionOut.Valid() {
+		return fmt.Errorf("invalid condition_out: %s", c.ConditionOut)
+	}
+	if c.DueAt != nil && !c.DueAt.After(c.IssuedAt) {
+		return fmt.Errorf("due_at must be after issued_at")
+	}
+	if c.ReturnedAt == nil {
+		if c.ConditionIn != "" || c.ReceivedBy != nil {
+			return fmt.Errorf("condition_in and received_by require returned_at")
+		}
+		return nil
+	}
+	if c.ReturnedAt.Before(c.IssuedAt) {
+		return fmt.Errorf("returned_at cannot be before issued_at")
+	}
+	if !c.ConditionIn.Valid() {
+		return fmt.Errorf("invalid condition_in: %s", c.ConditionIn)
+	}
+	return nil
+}
+
+// IsOpen returns true if the asset has not been checked back in.
+func (c *AssetCustody) IsOpen() bool {
+	return c.ReturnedAt == nil
+}
+
+// IsAssignment returns true if the custody is a standing assignment rather
+// than a check-out due back.
+func (c *AssetCustody) IsAssignment() bool {
+	return c.DueAt == nil
+}
+
+// IsOverdue returns true if a check-out is still open past its due date.
+func (c *AssetCustody) IsOverdue(now time.Time) bool {
+	return c.IsOpen() && c.DueAt != nil && now.After(*c.DueAt)
+}
+
+// AssetConditionReport records an asset's condition as found at an
+// inspection or on its return.
+type AssetConditionReport struct {
+	ID         string         `json:"id"`
+	AssetID    string         `json:"asset_id"`
+	Condition  AssetCondition `json:"condition"`
+	Previous   AssetCondition `json:"previous"`
+	RecordedAt time.Time      `json:"recorded_at"`
+	RecordedBy *string        `json:"recorded_by,omitempty"`
+	CustodyID  *string        `json:"custody_id,omitempty"` // The return it was found at
+	Notes      string         `json:"notes,omitempty"`
+	CreatedAt  time.Time      `json:"created_at"`
+}
+
+// Validate checks if the condition report data is valid.
+func (r *AssetConditionReport) Validate() error {
+	if r.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if r.AssetID == "" {
+		return fmt.Errorf("asset_id is required")
+	}
+	if !r.Condition.Valid() {
+		return fmt.Errorf("invalid condition: %s", r.Condition)
+	}
+	if !r.Previous.Valid() {
+		return fmt.Errorf("invalid previous condition: %s", r.Previous)
+	}
+	if r.RecordedAt.IsZero() {
+		return fmt.Errorf("recorded_at is required")
+	}
+	return nil
+}
+
+// AssetFilter specifies criteria for listing assets.
+type AssetFilter struct {
+	Category       AssetCategory // Empty for every category
+	ResidentID     string        // Only assets in this resident's custody
+	IncludeRetired bool
+}
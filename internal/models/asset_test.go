@@ -0,0 +1,173 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseAssetCategory(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    AssetCategory
+		wantErr bool
+	}{
+		{"Upper case", "WEAPON", AssetCategoryWeapon, false},
+		{"Lower case", "tool", AssetCategoryTool, false},
+		{"Pip-Boy", "pip_boy", AssetCategoryPipBoy, false},
+		{"Pip-Boy hyphenated", "Pip-Boy", AssetCategoryPipBoy, false},
+		{"Pip-Boy joined", "pipboy", AssetCategoryPipBoy, false},
+		{"Unknown", "vehicle", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseAssetCategory(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAssetCategory() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseAssetCategory() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAsset_Validate(t *testing.T) {
+	valid := func() *Asset {
+		return &Asset{
+			ID:           "asset-1",
+			SerialNumber: "PB3000-0042",
+			Name:         "Pip-Boy 3000",
+			Category:     AssetCategoryPipBoy,
+			Condition:    AssetConditionGood,
+		}
+	}
+	acquired := time.Date(2077, 10, 1, 0, 0, 0, 0, time.UTC)
+	before := time.Date(2077, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		modify  func(*Asset)
+		wantErr bool
+	}{
+		{"Valid", func(a *Asset) {}, false},
+		{"Valid retired", func(a *Asset) { a.AcquiredAt, a.RetiredAt = &acquired, &acquired }, false},
+		{"Missing ID", func(a *Asset) { a.ID = "" }, true},
+		{"Blank serial number", func(a *Asset) { a.SerialNumber = "  " }, true},
+		{"Missing name", func(a *Asset) { a.Name = "" }, true},
+		{"Invalid category", func(a *Asset) { a.Category = "VEHICLE" }, true},
+		{"Invalid condition", func(a *Asset) { a.Condition = "MINT" }, true},
+		{"Retired before acquired", func(a *Asset) { a.AcquiredAt, a.RetiredAt = &acquired, &before }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := valid()
+			tt.modify(a)
+			if err := a.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Asset.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAsset_IsAvailable(t *testing.T) {
+	retired := time.Date(2078, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		asset Asset
+		want  bool
+	}{
+		{"In store", Asset{Condition: AssetConditionFair}, true},
+		{"In custody", Asset{Condition: AssetConditionFair, Custody: &AssetCustody{}}, false},
+		{"Broken", Asset{Condition: AssetConditionBroken}, false},
+		{"Retired", Asset{Condition: AssetConditionGood, RetiredAt: &retired}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.asset.IsAvailable(); got != tt.want {
+				t.Errorf("Asset.IsAvailable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAssetCustody_Validate(t *testing.T) {
+	issued := time.Date(2077, 11, 1, 8, 0, 0, 0, time.UTC)
+	due := issued.AddDate(0, 0, 7)
+	returned := issued.AddDate(0, 0, 3)
+	early := issued.Add(-time.Hour)
+	clerk := "clerk-1"
+
+	valid := func() *AssetCustody {
+		return &AssetCustody{
+			ID:           "custody-1",
+			AssetID:      "asset-1",
+			ResidentID:   "resident-1",
+			IssuedAt:     issued,
+			ConditionOut: AssetConditionGood,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*AssetCustody)
+		wantErr bool
+	}{
+		{"Valid assignment", func(c *AssetCustody) {}, false},
+		{"Valid check-out", func(c *AssetCustody) { c.DueAt = &due }, false},
+		{"Valid return", func(c *AssetCustody) {
+			c.ReturnedAt, c.ReceivedBy, c.ConditionIn = &returned, &clerk, AssetConditionPoor
+		}, false},
+		{"Missing asset", func(c *AssetCustody) { c.AssetID = "" }, true},
+		{"Missing resident", func(c *AssetCustody) { c.ResidentID = "" }, true},
+		{"Missing issue time", func(c *AssetCustody) { c.IssuedAt = time.Time{} }, true},
+		{"Invalid condition out", func(c *AssetCustody) { c.ConditionOut = "" }, true},
+		{"Due before issue", func(c *AssetCustody) { c.DueAt = &early }, true},
+		{"Returned before issue", func(c *AssetCustody) { c.ReturnedAt, c.ConditionIn = &early, AssetConditionGood }, true},
+		{"Returned without condition", func(c *AssetCustody) { c.ReturnedAt = &returned }, true},
+		{"Condition without return", func(c *AssetCustody) { c.ConditionIn = AssetConditionGood }, true},
+		{"Receiver without return", func(c *AssetCustody) { c.ReceivedBy = &clerk }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := valid()
+			tt.modify(c)
+			if err := c.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("AssetCustody.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAssetCustody_IsOverdue(t *testing.T) {
+	issued := time.Date(2077, 11, 1, 8, 0, 0, 0, time.UTC)
+	due := issued.AddDate(0, 0, 7)
+	returned := issued.AddDate(0, 0, 9)
+	late := issued.AddDate(0, 0, 10)
+
+	tests := []struct {
+		name    string
+		custody AssetCustody
+		now     time.Time
+		want    bool
+	}{
+		{"Before due", AssetCustody{IssuedAt: issued, DueAt: &due}, issued.AddDate(0, 0, 6), false},
+		{"At due", AssetCustody{IssuedAt: issued, DueAt: &due}, due, false},
+		{"Past due", AssetCustody{IssuedAt: issued, DueAt: &due}, late, true},
+		{"Returned late", AssetCustody{IssuedAt: issued, DueAt: &due, ReturnedAt: &returned}, late, false},
+		{"Assignment", AssetCustody{IssuedAt: issued}, late, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.custody.IsOverdue(tt.now); got != tt.want {
+				t.Errorf("AssetCustody.IsOverdue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
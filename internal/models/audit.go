@@ -0,0 +1,112 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// AuditActorType identifies who made an audited change.
+type AuditActorType string
+
+const (
+	AuditActorUser       AuditActorType = "USER"
+	AuditActorSystem     AuditActorType = "SYSTEM"
+	AuditActorSimulation AuditActorType = "SIMULATION"
+)
+
+// Valid returns true if the actor type is valid.
+func (a AuditActorType) Valid() bool {
+	switch a {
+	case AuditActorUser, AuditActorSystem, AuditActorSimulation:
+		return true
+	default:
+		return false
+	}
+}
+
+// Audit actions.
+const (
+	AuditActionStatusChange      = "STATUS_CHANGE"
+	AuditActionDualAuthorization = "DUAL_AUTHORIZATION" // An operation confirmed by a second operator
+)
+
+// Audited entity types.
+const (
+	AuditEntityResident       = "resident"
+	AuditEntityFacilitySystem = "facility_system"
+	AuditEntityVault          = "vault" // Vault alert state; the entity ID is the vault number
+	AuditEntityHousehold      = "household"
+	AuditEntityStock          = "resource_stock"
+)
+
+// AuditEntry is one change in the audit log. Timestamp is vault time.
+// OldValues and NewValues hold JSON objects of the changed fields.
+type AuditEntry struct {
+	ID         string         `json:"id"`
+	Timestamp  time.Time      `json:"timestamp"`
+	ActorType  AuditActorType `json:"actor_type"`
+	ActorID    *string        `json:"actor_id,omitempty"`
+	Action     string         `json:"action"`
+	EntityType string         `json:"entity_type"`
+	EntityID   string         `json:"entity_id"`
+	OldValues  string         `json:"old_values,omitempty"`
+	NewValues  string         `json:"new_values,omitempty"`
+}
+
+// Validate checks if the audit entry data is valid.
+func (e *AuditEntry) Validate() error {
+	if e.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if e.Timestamp.IsZero() {
+		return fmt.Errorf("timestamp is required")
+	}
+	if !e.ActorType.Valid() {
+		return fmt.Errorf("invalid actor_type: %s", e.ActorType)
+	}
+	if e.Action == "" {
+		return fmt.Errorf("action is required")
+	}
+	if e.EntityType == "" || e.EntityID == "" {
+		return fmt.Errorf("entity_type and entity_id are required")
+	}
+	return nil
+}
+
+// SetValues stores the old and new values of the change as JSON.
+func (e *AuditEntry) SetValues(oldValues, newValues any) error {
+	oldJSON, err := json.Marshal(oldValues)
+	if err != nil {
+		return fmt.Errorf("encoding old values: %w", err)
+	}
+	newJSON, err := json.Marshal(newValues)
+	if err != nil {
+		return fmt.Errorf("encoding new values: %w", err)
+	}
+	e.OldValues = string(oldJSON)
+	e.NewValues = string(newJSON)
+	return nil
+}
+
+// DecodeValues decodes the old and new values of the change. Missing values
+// leave their target untouched.
+func (e *AuditEntry) DecodeValues(oldValues, newValues any) error {
+	if e.OldValues != "" {
+		if err := json.Unmarshal([]byte(e.OldValues), oldValues); err != nil {
+			return fmt.Errorf("decoding old values: %w", err)
+		}
+	}
+	if e.NewValues != "" {
+		if err := json.Unmarshal([]byte(e.NewValues), newValues); err != nil {
+			return fmt.Errorf("decoding new values: %w", err)
+		}
+	}
+	return nil
+}
+
+// StatusValues are the audited values of a status change.
+type StatusValues struct {
+	Status     string   `json:"status"`
+	Efficiency *float64 `json:"efficiency_percent,omitempty"`
+}
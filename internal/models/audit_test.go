@@ -0,0 +1,70 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAuditEntry_Validate(t *testing.T) {
+	valid := func() *AuditEntry {
+		return &AuditEntry{
+			ID:         "entry-1",
+			Timestamp:  time.Date(2077, 10, 23, 9, 0, 0, 0, time.UTC),
+			ActorType:  AuditActorSimulation,
+			Action:     AuditActionStatusChange,
+			EntityType: AuditEntityFacilitySystem,
+			EntityID:   "system-1",
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*AuditEntry)
+		wantErr bool
+	}{
+		{"Valid entry", func(e *AuditEntry) {}, false},
+		{"Missing ID", func(e *AuditEntry) { e.ID = "" }, true},
+		{"Missing timestamp", func(e *AuditEntry) { e.Timestamp = time.Time{} }, true},
+		{"Invalid actor", func(e *AuditEntry) { e.ActorType = "ROBOT" }, true},
+		{"Missing action", func(e *AuditEntry) { e.Action = "" }, true},
+		{"Missing entity", func(e *AuditEntry) { e.EntityID = "" }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := valid()
+			tt.modify(entry)
+			err := entry.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAuditEntry_Values(t *testing.T) {
+	efficiency := 72.0
+	entry := &AuditEntry{}
+	if err := entry.SetValues(
+		StatusValues{Status: "OPERATIONAL"},
+		StatusValues{Status: "DEGRADED", Efficiency: &efficiency},
+	); err != nil {
+		t.Fatalf("SetValues() error = %v", err)
+	}
+
+	var before, after StatusValues
+	if err := entry.DecodeValues(&before, &after); err != nil {
+		t.Fatalf("DecodeValues() error = %v", err)
+	}
+	if before.Status != "OPERATIONAL" || before.Efficiency != nil {
+		t.Errorf("old values = %+v, want OPERATIONAL without efficiency", before)
+	}
+	if after.Status != "DEGRADED" || after.Efficiency == nil || *after.Efficiency != 72 {
+		t.Errorf("new values = %+v, want DEGRADED at 72%%", after)
+	}
+
+	entry.NewValues = "{"
+	if err := entry.DecodeValues(&before, &after); err == nil {
+		t.Error("DecodeValues() with malformed JSON should fail")
+	}
+}
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// BadgeWidth is the width of a printed ID badge in characters, including
+// its frame.
+const BadgeWidth = 44
+
+// badgeSigilBits is the number of bars in a badge sigil.
+const badgeSigilBits = 36
+
+// IDBadge is the printable identification badge of a resident.
+type IDBadge struct {
+	VaultNumber    int
+	RegistryNumber string
+	Name           string
+	Clearance      int
+	Vocation       string // Empty when unassigned
+	Status         ResidentStatus
+}
+
+// Sigil returns the barcode-like mark of the badge: bars derived from the
+// registry number, so a forged number does not match its sigil.
+func (b *IDBadge) Sigil() string {
+	sum := sha256.Sum256([]byte(b.RegistryNumber))
+	var s strings.Builder
+	for i := 0; i < badgeSigilBits; i++ {
+		if sum[i/8]&(1<<(7-i%8)) != 0 {
+			s.WriteByte('|')
+		} else {
+			s.WriteByte(' ')
+		}
+	}
+	return s.String()
+}
+
+// Lines renders the badge as plain text, one string per line, each
+// BadgeWidth characters wide, for the badge printer and the terminal.
+func (b *IDBadge) Lines() []string {
+	inner := BadgeWidth - 4
+	border := "+" + strings.Repeat("=", BadgeWidth-2) + "+"
+	rule := "+" + strings.Repeat("-", BadgeWidth-2) + "+"
+	row := func(text string) string {
+		runes := []rune(text)
+		if len(runes) > inner {
+			runes = runes[:inner]
+		}
+		return "| " + string(runes) + strings.Repeat(" ", inner-len(runes)) + " |"
+	}
+	center := func(text string) string {
+		pad := (inner - utf8.RuneCountInString(text)) / 2
+		if pad < 0 {
+			pad = 0
+		}
+		return row(strings.Repeat(" ", pad) + text)
+	}
+	field := func(label, value string) string {
+		return row(fmt.Sprintf("%-10s %s", label, value))
+	}
+
+	vocation := b.Vocation
+	if vocation == "" {
+		vocation = "UNASSIGNED"
+	}
+	sigil := b.Sigil()
+
+	return []string{
+		border,
+		center(fmt.Sprintf("VAULT-TEC  *  VAULT %03d", b.VaultNumber)),
+		center("RESIDENT IDENTIFICATION"),
+		rule,
+		field("REGISTRY", b.RegistryNumber),
+		field("NAME", strings.ToUpper(b.Name)),
+		field("CLEARANCE", fmt.Sprintf("LEVEL %d", b.Clearance)),
+		field("VOCATION", strings.ToUpper(vocation)),
+		field("STATUS", string(b.Status)),
+		row(""),
+		center(sigil),
+		center(sigil),
+		center(b.RegistryNumber),
+		border,
+	}
+}
+
+// Text renders the badge as plain text with a trailing newline.
+func (b *IDBadge) Text() string {
+	return strings.Join(b.Lines(), "\n") + "\n"
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestIDBadge_Lines(t *testing.T) {
+	badge := &IDBadge{
+		VaultNumber:    76,
+		RegistryNumber: "V076-00042",
+		Name:           "Müller-Ramírez, Anna Katharina Josephine Elisabeth",
+		Clearance:      5,
+		Status:         ResidentStatusActive,
+	}
+
+	lines := badge.Lines()
+	for i, line := range lines {
+		if n := utf8.RuneCountInString(line); n != BadgeWidth {
+			t.Errorf("line %d is %d wide, want %d: %q", i, n, BadgeWidth, line)
+		}
+	}
+
+	text := badge.Text()
+	for _, want := range []string{"VAULT 076", "V076-00042", "LEVEL 5", "UNASSIGNED", "ACTIVE"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("badge missing %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestIDBadge_Sigil(t *testing.T) {
+	a := &IDBadge{RegistryNumber: "V076-00042"}
+	b := &IDBadge{RegistryNumber: "V076-00043"}
+
+	if got := len(a.Sigil()); got != badgeSigilBits {
+		t.Errorf("Sigil() length = %d, want %d", got, badgeSigilBits)
+	}
+	if a.Sigil() != (&IDBadge{RegistryNumber: "V076-00042"}).Sigil() {
+		t.Error("Sigil() differs for the same registry number")
+	}
+	if a.Sigil() == b.Sigil() {
+		t.Error("Sigil() matches for different registry numbers")
+	}
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// CareAssignmentStatus represents the state of a care assignment.
+type CareAssignmentStatus string
+
+const (
+	CareAssignmentStatusPending   CareAssignmentStatus = "PENDING"
+	CareAssignmentStatusAssigned  CareAssignmentStatus = "ASSIGNED"
+	CareAssignmentStatusCancelled CareAssignmentStatus = "CANCELLED"
+)
+
+// Valid returns true if the status is valid.
+func (s CareAssignmentStatus) Valid() bool {
+	switch s {
+	case CareAssignmentStatusPending, CareAssignmentStatusAssigned, CareAssignmentStatusCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// CareAssignment queues a dependent minor left without a living parent or
+// guardian until the overseer assigns a guardian household.
+type CareAssignment struct {
+	ID          string               `json:"id"`
+	DependentID string               `json:"dependent_id"`
+	Status      CareAssignmentStatus `json:"status"`
+	Reason      string               `json:"reason"`
+	OpenedAt    time.Time            `json:"opened_at"`
+	GuardianID  *string              `json:"guardian_id,omitempty"`
+	HouseholdID *string              `json:"household_id,omitempty"`
+	ResolvedAt  *time.Time           `json:"resolved_at,omitempty"`
+	CreatedAt   time.Time            `json:"created_at"`
+	UpdatedAt   time.Time            `json:"updated_at"`
+}
+
+// Validate checks if the care assignment data is valid.
+func (c *CareAssignment) Validate() error {
+	if c.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if c.DependentID == "" {
+		return fmt.Errorf("dependent_id is required")
+	}
+	if !c.Status.Valid() {
+		return fmt.Errorf("invalid status: %s", c.Status)
+	}
+	if c.Reason == "" {
+		return fmt.Errorf("reason is required")
+	}
+	if c.OpenedAt.IsZero() {
+		return fmt.Errorf("opened_at is required")
+	}
+	if c.Status == CareAssignmentStatusAssigned && (c.GuardianID == nil || c.HouseholdID == nil) {
+		return fmt.Errorf("an assigned dependent needs a guardian and household")
+	}
+	return nil
+}
+
+// IsPending returns true if the dependent is still awaiting a guardian.
+func (c *CareAssignment) IsPending() bool {
+	return c.Status == CareAssignmentStatusPending
+}
+
+// NeedsGuardian returns true if a living minor has none of the given carers,
+// their biological parents and current guardians, alive.
+func NeedsGuardian(dependent *Resident, carers []*Resident, asOf time.Time) bool {
+	if !dependent.IsAlive() || dependent.IsAdult(asOf) {
+		return false
+	}
+	for _, c := range carers {
+		if c.IsAlive() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCareAssignment_Validate(t *testing.T) {
+	guardian, household := "res-2", "hh-1"
+	valid := func() *CareAssignment {
+		return &CareAssignment{
+			ID:          "care-1",
+			DependentID: "res-1",
+			Status:      CareAssignmentStatusPending,
+			Reason:      "Death of V076-00012",
+			OpenedAt:    time.Date(2078, 3, 1, 0, 0, 0, 0, time.UTC),
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*CareAssignment)
+		wantErr bool
+	}{
+		{"Valid pending", func(c *CareAssignment) {}, false},
+		{"Missing dependent", func(c *CareAssignment) { c.DependentID = "" }, true},
+		{"Invalid status", func(c *CareAssignment) { c.Status = "OPEN" }, true},
+		{"Missing reason", func(c *CareAssignment) { c.Reason = "" }, true},
+		{"Assigned without guardian", func(c *CareAssignment) { c.Status = CareAssignmentStatusAssigned }, true},
+		{"Assigned", func(c *CareAssignment) {
+			c.Status = CareAssignmentStatusAssigned
+			c.GuardianID, c.HouseholdID = &guardian, &household
+		}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := valid()
+			tt.modify(c)
+			if err := c.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNeedsGuardian(t *testing.T) {
+	asOf := time.Date(2078, 3, 1, 0, 0, 0, 0, time.UTC)
+	child := &Resident{DateOfBirth: time.Date(2070, 5, 1, 0, 0, 0, 0, time.UTC), Status: ResidentStatusActive}
+	adult := &Resident{DateOfBirth: time.Date(2050, 5, 1, 0, 0, 0, 0, time.UTC), Status: ResidentStatusActive}
+	living := &Resident{Status: ResidentStatusActive}
+	dead := &Resident{Status: ResidentStatusDeceased}
+
+	tests := []struct {
+		name      string
+		dependent *Resident
+		carers    []*Resident
+		want      bool
+	}{
+		{"Orphaned minor", child, []*Resident{dead, dead}, true},
+		{"Minor with no recorded parents", child, nil, true},
+		{"One living parent", child, []*Resident{dead, living}, false},
+		{"Adult", adult, []*Resident{dead, dead}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NeedsGuardian(tt.dependent, tt.carers, asOf); got != tt.want {
+				t.Errorf("NeedsGuardian() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,9 +1,16 @@
 package models
 
 // Pagination holds pagination parameters.
+//
+// Lists that support keyset pagination return a NextCursor with each page.
+// Passing it back as After continues from the last row seen rather than
+// skipping Page-1 pages of rows, which keeps paging cost at the page size
+// however deep the list; Page is then only reported back for display.
 type Pagination struct {
 	Page     int
 	PageSize int
+	Sort     SortOption // Zero value keeps the list's default order
+	After    string     // Keyset cursor from the previous page's NextCursor
 }
 
 // DefaultPagination returns default pagination settings.
@@ -56,8 +63,57 @@ const (
 	SortDesc SortDirection = "DESC"
 )
 
-// SortOption defines a column sort option.
+// SortOption defines a column sort option. Column is one of the sort keys a
+// list accepts, e.g. ResidentSortColumns; an empty direction sorts ascending.
 type SortOption struct {
 	Column    string
 	Direction SortDirection
 }
+
+// IsSet returns true if a sort column is chosen.
+func (o SortOption) IsSet() bool {
+	return o.Column != ""
+}
+
+// Descending returns true if the sort is in descending order.
+func (o SortOption) Descending() bool {
+	return o.Direction == SortDesc
+}
+
+// Next moves to the next of the given columns in ascending order. After the
+// last column it returns the zero value, the list's default order.
+func (o SortOption) Next(columns []string) SortOption {
+	for i, c := range columns {
+		if c == o.Column {
+			if i+1 < len(columns) {
+				return SortOption{Column: columns[i+1], Direction: SortAsc}
+			}
+			return SortOption{}
+		}
+	}
+	if len(columns) == 0 {
+		return SortOption{}
+	}
+	return SortOption{Column: columns[0], Direction: SortAsc}
+}
+
+// Reversed returns the same column sorted in the other direction.
+func (o SortOption) Reversed() SortOption {
+	if o.Descending() {
+		o.Direction = SortAsc
+	} else {
+		o.Direction = SortDesc
+	}
+	return o
+}
+
+// String describes the sort, e.g. "surname ▲".
+func (o SortOption) String() string {
+	if !o.IsSet() {
+		return "default"
+	}
+	if o.Descending() {
+		return o.Column + " ▼"
+	}
+	return o.Column + " ▲"
+}
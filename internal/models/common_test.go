@@ -0,0 +1,57 @@
+package models
+
+import "testing"
+
+func TestSortOption_Next(t *testing.T) {
+	columns := []string{"surname", "age"}
+
+	want := []SortOption{
+		{Column: "surname", Direction: SortAsc},
+		{Column: "age", Direction: SortAsc},
+		{},
+	}
+
+	var sort SortOption
+	for i, w := range want {
+		sort = sort.Next(columns)
+		if sort != w {
+			t.Errorf("step %d: Next() = %+v, want %+v", i, sort, w)
+		}
+	}
+
+	t.Run("Unknown column starts over", func(t *testing.T) {
+		got := SortOption{Column: "blood_type", Direction: SortDesc}.Next(columns)
+		if got != want[0] {
+			t.Errorf("Next() = %+v, want %+v", got, want[0])
+		}
+	})
+}
+
+func TestSortOption_Reversed(t *testing.T) {
+	asc := SortOption{Column: "surname", Direction: SortAsc}
+
+	desc := asc.Reversed()
+	if !desc.Descending() {
+		t.Errorf("Reversed() = %+v, want descending", desc)
+	}
+	if back := desc.Reversed(); back != asc {
+		t.Errorf("Reversed() twice = %+v, want %+v", back, asc)
+	}
+}
+
+func TestSortOption_String(t *testing.T) {
+	tests := []struct {
+		sort SortOption
+		want string
+	}{
+		{SortOption{}, "default"},
+		{SortOption{Column: "surname", Direction: SortAsc}, "surname ▲"},
+		{SortOption{Column: "surname", Direction: SortDesc}, "surname ▼"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.sort.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DeliveryOutcome is what a delivery import did with a line of the
+// delivery.
+type DeliveryOutcome string
+
+const (
+	DeliveryStocked            DeliveryOutcome = "STOCKED"             // Received as a new lot
+	DeliveryExtended           DeliveryOutcome = "EXTENDED"            // Added to a lot on file
+	DeliveryUnknownCode        DeliveryOutcome = "UNKNOWN_CODE"        // Item code not in the catalog
+	DeliveryLotMismatch        DeliveryOutcome = "LOT_MISMATCH"        // Lot number on file for another item, or off the books
+	DeliveryExpirationMismatch DeliveryOutcome = "EXPIRATION_MISMATCH" // Lot on file with another expiration date
+	DeliveryInvalid            DeliveryOutcome = "INVALID"             // Line could not be read
+)
+
+// Received reports whether the line's quantity was taken into stock.
+func (o DeliveryOutcome) Received() bool {
+	return o == DeliveryStocked || o == DeliveryExtended
+}
+
+// DeliveryLine is a line of a supply delivery and what became of it.
+type DeliveryLine struct {
+	Line           int // In the delivery file, the header being line 1
+	ItemCode       string
+	LotNumber      string
+	Quantity       float64
+	ExpirationDate *time.Time
+	Outcome        DeliveryOutcome
+	ItemCreated    bool   // The item was added to the catalog for this line
+	StockID        string // Lot received into, if any
+	Detail         string // Why a line was not received
+}
+
+// DeliveryReconciliation is the result of importing a supply delivery:
+// every line with its outcome, for reconciling against the manifest.
+type DeliveryReconciliation struct {
+	Reference       string // Manifest or delivery number
+	StorageLocation string
+	ReceivedAt      time.Time
+	Lines           []DeliveryLine
+}
+
+// DeliverySummary counts the outcomes of a delivery.
+type DeliverySummary struct {
+	Lines        int
+	Stocked      int
+	Extended     int
+	ItemsCreated int
+	Mismatched   int
+	Quantity     float64 // Units received
+}
+
+// Summary counts the lines of the delivery by outcome.
+func (r *DeliveryReconciliation) Summary() DeliverySummary {
+	s := DeliverySummary{Lines: len(r.Lines)}
+	for i := range r.Lines {
+		l := &r.Lines[i]
+		switch l.Outcome {
+		case DeliveryStocked:
+			s.Stocked++
+		case DeliveryExtended:
+			s.Extended++
+		default:
+			s.Mismatched++
+		}
+		if l.Outcome.Received() {
+			s.Quantity += l.Quantity
+		}
+		if l.ItemCreated {
+			s.ItemsCreated++
+		}
+	}
+	return s
+}
+
+// Mismatches returns the lines that were not received, in file order.
+func (r *DeliveryReconciliation) Mismatches() []DeliveryLine {
+	var lines []DeliveryLine
+	for _, l := range r.Lines {
+		if !l.Outcome.Received() {
+			lines = append(lines, l)
+		}
+	}
+	return lines
+}
+
+// ReportText renders the reconciliation report as plain text for printing:
+// what was received, then every line to take up with the supplier.
+func (r *DeliveryReconciliation) ReportText() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "DELIVERY RECONCILIATION REPORT\n")
+	if r.Reference != "" {
+		fmt.Fprintf(&b, "Reference: %s\n", r.Reference)
+	}
+	fmt.Fprintf(&b, "Location:  %s\n", r.StorageLocation)
+	fmt.Fprintf(&b, "Received:  %s\n", r.ReceivedAt.Format("2006-01-02 15:04"))
+	b.WriteString("\n")
+
+	fmt.Fprintf(&b, "%5s %-18s %-14s %12s %-10s %s\n", "LINE", "ITEM", "LOT", "QUANTITY", "EXPIRES", "OUTCOME")
+	for i := range r.Lines {
+		l := &r.Lines[i]
+		lot, expires := "-", "-"
+		if l.LotNumber != "" {
+			lot = l.LotNumber
+		}
+		if l.ExpirationDate != nil {
+			expires = l.ExpirationDate.Format("2006-01-02")
+		}
+		outcome := string(l.Outcome)
+		if l.ItemCreated {
+			outcome += " (new item)"
+		}
+		fmt.Fprintf(&b, "%5d %-18s %-14s %12.2f %-10s %s\n", l.Line, l.ItemCode, lot, l.Quantity, expires, outcome)
+	}
+
+	if mismatches := r.Mismatches(); len(mismatches) > 0 {
+		fmt.Fprintf(&b, "\nTO RECONCILE\n")
+		for _, l := range mismatches {
+			fmt.Fprintf(&b, "  line %d, %s: %s\n", l.Line, l.Outcome, l.Detail)
+		}
+	}
+
+	s := r.Summary()
+	fmt.Fprintf(&b, "\n%d line(s): %d new lot(s), %d lot(s) extended, %.2f units received; %d item(s) created, %d to reconcile\n",
+		s.Lines, s.Stocked, s.Extended, s.Quantity, s.ItemsCreated, s.Mismatched)
+	return b.String()
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDeliveryOutcome_Received(t *testing.T) {
+	tests := []struct {
+		outcome DeliveryOutcome
+		want    bool
+	}{
+		{DeliveryStocked, true},
+		{DeliveryExtended, true},
+		{DeliveryUnknownCode, false},
+		{DeliveryLotMismatch, false},
+		{DeliveryExpirationMismatch, false},
+		{DeliveryInvalid, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.outcome.Received(); got != tt.want {
+			t.Errorf("DeliveryOutcome(%q).Received() = %v, want %v", tt.outcome, got, tt.want)
+		}
+	}
+}
+
+func deliveryFixture() *DeliveryReconciliation {
+	expires := time.Date(2079, 1, 31, 0, 0, 0, 0, time.UTC)
+	return &DeliveryReconciliation{
+		Reference:       "MANIFEST-0042",
+		StorageLocation: "STORAGE-A-12",
+		ReceivedAt:      time.Date(2077, 10, 23, 9, 0, 0, 0, time.UTC),
+		Lines: []DeliveryLine{
+			{Line: 2, ItemCode: "FOOD-001", LotNumber: "L-100", Quantity: 40, ExpirationDate: &expires, Outcome: DeliveryStocked, StockID: "stock-1"},
+			{Line: 3, ItemCode: "FOOD-001", LotNumber: "L-100", Quantity: 10, Outcome: DeliveryExtended, StockID: "stock-1"},
+			{Line: 4, ItemCode: "MED-099", Quantity: 5, Outcome: DeliveryStocked, ItemCreated: true, StockID: "stock-2"},
+			{Line: 5, ItemCode: "WATER-9", Quantity: 20, Outcome: DeliveryUnknownCode, Detail: "item code WATER-9 is not in the catalog"},
+			{Line: 6, ItemCode: "FOOD-002", LotNumber: "L-100", Quantity: 3, Outcome: DeliveryLotMismatch, Detail: "lot L-100 is on file for FOOD-001"},
+		},
+	}
+}
+
+func TestDeliveryReconciliation_Summary(t *testing.T) {
+	got := deliveryFixture().Summary()
+	want := DeliverySummary{Lines: 5, Stocked: 2, Extended: 1, ItemsCreated: 1, Mismatched: 2, Quantity: 55}
+	if got != want {
+		t.Errorf("Summary() = %+v, want %+v", got, want)
+	}
+}
+
+func TestDeliveryReconciliation_Mismatches(t *testing.T) {
+	mismatches := deliveryFixture().Mismatches()
+	if len(mismatches) != 2 || mismatches[0].Line != 5 || mismatches[1].Line != 6 {
+		t.Errorf("Mismatches() = %+v, want lines 5 and 6", mismatches)
+	}
+}
+
+func TestDeliveryReconciliation_ReportText(t *testing.T) {
+	report := deliveryFixture().ReportText()
+	for _, want := range []string{
+		"DELIVERY RECONCILIATION REPORT",
+		"Reference: MANIFEST-0042",
+		"2079-01-31",
+		"STOCKED (new item)",
+		"TO RECONCILE",
+		"line 5, UNKNOWN_CODE: item code WATER-9 is not in the catalog",
+		"line 6, LOT_MISMATCH: lot L-100 is on file for FOOD-001",
+		"5 line(s): 2 new lot(s), 1 lot(s) extended, 55.00 units received; 1 item(s) created, 2 to reconcile",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report missing %q:\n%s", want, report)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DualAuthOperation is an operation a policy can hold for two-person
+// authorization.
+type DualAuthOperation string
+
+const (
+	DualAuthExile             DualAuthOperation = "EXILE"              // Setting a resident's status to EXILED
+	DualAuthDissolveHousehold DualAuthOperation = "DISSOLVE_HOUSEHOLD" // Dissolving a household
+	DualAuthStockWriteOff     DualAuthOperation = "STOCK_WRITE_OFF"    // Adjusting a lot down or marking it damaged
+)
+
+// Valid returns true if the operation is valid.
+func (o DualAuthOperation) Valid() bool {
+	switch o {
+	case DualAuthExile, DualAuthDissolveHousehold, DualAuthStockWriteOff:
+		return true
+	default:
+		return false
+	}
+}
+
+// ParseDualAuthOperation reads an operation name in any case, with '-' for
+// '_'.
+func ParseDualAuthOperation(name string) (DualAuthOperation, error) {
+	op := DualAuthOperation(strings.ReplaceAll(strings.ToUpper(strings.TrimSpace(name)), "-", "_"))
+	if !op.Valid() {
+		return "", fmt.Errorf("invalid operation %q: use exile, dissolve_household or stock_write_off", name)
+	}
+	return op, nil
+}
+
+// DualAuthPolicy requires a second operator to confirm an operation. Both
+// operators must hold MinClearance. A stock write-off policy applies from
+// Threshold units written off at once; 0 applies it to every write-off.
+type DualAuthPolicy struct {
+	Operation    DualAuthOperation `json:"operation"`
+	MinClearance int               `json:"min_clearance"`
+	Threshold    float64           `json:"threshold"`
+	VaultID      int               `json:"vault_id"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+	CreatedAt    time.Time         `json:"created_at"`
+}
+
+// Validate checks if the policy data is valid.
+func (p *DualAuthPolicy) Validate() error {
+	if !p.Operation.Valid() {
+		return fmt.Errorf("invalid operation: %s", p.Operation)
+	}
+	if p.MinClearance < 1 || p.MinClearance > 10 {
+		return fmt.Errorf("min_clearance must be between 1 and 10")
+	}
+	if p.Threshold < 0 {
+		return fmt.Errorf("threshold must not be negative")
+	}
+	if p.Threshold > 0 && p.Operation != DualAuthStockWriteOff {
+		return fmt.Errorf("only %s policies take a threshold", DualAuthStockWriteOff)
+	}
+	return nil
+}
+
+// Applies reports whether the policy holds an operation on amount, the
+// units written off for a stock write-off, for two-person authorization.
+func (p *DualAuthPolicy) Applies(amount float64) bool {
+	return p.Threshold == 0 || amount >= p.Threshold-QuantityEpsilon
+}
+
+// DualAuthValues are the audited values of a confirmed operation.
+type DualAuthValues struct {
+	Operation   DualAuthOperation `json:"operation"`
+	RequestedBy string            `json:"requested_by"` // Registry number of the operator making the change
+	ConfirmedBy string            `json:"confirmed_by"` // Registry number of the operator confirming it
+	Amount      float64           `json:"amount,omitempty"`
+}
@@ -0,0 +1,52 @@
+package models
+
+import "testing"
+
+func TestParseDualAuthOperation(t *testing.T) {
+	for name, want := range map[string]DualAuthOperation{
+		"exile":              DualAuthExile,
+		"Dissolve-Household": DualAuthDissolveHousehold,
+		"STOCK_WRITE_OFF":    DualAuthStockWriteOff,
+	} {
+		if got, err := ParseDualAuthOperation(name); err != nil || got != want {
+			t.Errorf("ParseDualAuthOperation(%q) = %q, %v, want %q", name, got, err, want)
+		}
+	}
+	if _, err := ParseDualAuthOperation("demolish"); err == nil {
+		t.Error("ParseDualAuthOperation accepted an unknown operation")
+	}
+}
+
+func TestDualAuthPolicy_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  DualAuthPolicy
+		wantErr bool
+	}{
+		{"Valid", DualAuthPolicy{Operation: DualAuthExile, MinClearance: 5}, false},
+		{"Write-off threshold", DualAuthPolicy{Operation: DualAuthStockWriteOff, MinClearance: 3, Threshold: 50}, false},
+		{"Invalid operation", DualAuthPolicy{Operation: "DEMOLISH", MinClearance: 1}, true},
+		{"No clearance", DualAuthPolicy{Operation: DualAuthExile}, true},
+		{"Clearance above 10", DualAuthPolicy{Operation: DualAuthExile, MinClearance: 11}, true},
+		{"Negative threshold", DualAuthPolicy{Operation: DualAuthStockWriteOff, MinClearance: 1, Threshold: -1}, true},
+		{"Threshold on exile", DualAuthPolicy{Operation: DualAuthExile, MinClearance: 1, Threshold: 10}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.policy.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDualAuthPolicy_Applies(t *testing.T) {
+	always := DualAuthPolicy{Operation: DualAuthStockWriteOff, MinClearance: 1}
+	if !always.Applies(0.5) {
+		t.Error("policy without a threshold does not apply to a small write-off")
+	}
+	large := DualAuthPolicy{Operation: DualAuthStockWriteOff, MinClearance: 1, Threshold: 100}
+	if large.Applies(99) || !large.Applies(100) || !large.Applies(250) {
+		t.Error("policy with a threshold of 100 applies to the wrong write-offs")
+	}
+}
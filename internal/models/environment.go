@@ -0,0 +1,184 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// EnvironmentSampleInterval is the vault time between the readings the
+// sensors of each sector take.
+const EnvironmentSampleInterval = time.Hour
+
+// EnvironmentMetric is a quantity the vault's environmental sensors read in
+// each sector.
+type EnvironmentMetric string
+
+const (
+	MetricOxygen      EnvironmentMetric = "O2"          // Percent of air
+	MetricCO2         EnvironmentMetric = "CO2"         // Parts per million
+	MetricTemperature EnvironmentMetric = "TEMPERATURE" // Degrees Celsius
+	MetricRadiation   EnvironmentMetric = "RADIATION"   // mSv per hour
+)
+
+// EnvironmentMetrics lists every environment metric.
+var EnvironmentMetrics = []EnvironmentMetric{
+	MetricOxygen,
+	MetricCO2,
+	MetricTemperature,
+	MetricRadiation,
+}
+
+// Valid returns true if the metric is valid.
+func (m EnvironmentMetric) Valid() bool {
+	_, ok := environmentThresholds[m]
+	return ok
+}
+
+// Unit returns the unit readings of the metric are in.
+func (m EnvironmentMetric) Unit() string {
+	switch m {
+	case MetricOxygen:
+		return "%"
+	case MetricCO2:
+		return "ppm"
+	case MetricTemperature:
+		return "°C"
+	case MetricRadiation:
+		return "mSv/h"
+	default:
+		return ""
+	}
+}
+
+// ConditionCode returns the medical condition code of the health flag a
+// breach of the metric raises. Radiation raises none of its own: the dose
+// it delivers is recorded as a radiation exposure instead.
+func (m EnvironmentMetric) ConditionCode() string {
+	switch m {
+	case MetricOxygen:
+		return "ENV-HYPOXIA"
+	case MetricCO2:
+		return "ENV-HYPERCAPNIA"
+	case MetricTemperature:
+		return "ENV-THERMAL"
+	default:
+		return ""
+	}
+}
+
+// EnvironmentLevel grades an environment reading against its thresholds.
+type EnvironmentLevel string
+
+const (
+	EnvironmentNominal  EnvironmentLevel = "NOMINAL"
+	EnvironmentWarning  EnvironmentLevel = "WARNING"
+	EnvironmentCritical EnvironmentLevel = "CRITICAL"
+)
+
+// Breached returns true if the level is outside the safe range.
+func (l EnvironmentLevel) Breached() bool {
+	return l == EnvironmentWarning || l == EnvironmentCritical
+}
+
+// ConditionSeverity returns the severity of the health flag the level
+// raises. A NOMINAL reading raises none.
+func (l EnvironmentLevel) ConditionSeverity() ConditionSeverity {
+	switch l {
+	case EnvironmentWarning:
+		return ConditionSeverityModerate
+	case EnvironmentCritical:
+		return ConditionSeveritySevere
+	default:
+		return ""
+	}
+}
+
+// EnvironmentThreshold is the safe range of a metric. A reading outside
+// the warning range is a WARNING and one outside the critical range is
+// CRITICAL; a bound of nil leaves that side open.
+type EnvironmentThreshold struct {
+	WarningLow   *float64
+	WarningHigh  *float64
+	CriticalLow  *float64
+	CriticalHigh *float64
+}
+
+// bound returns a pointer to a threshold bound.
+func bound(v float64) *float64 {
+	return &v
+}
+
+// environmentThresholds are the safe ranges of each metric: oxygen below
+// 19.5% impairs and below 16% incapacitates, carbon dioxide over 2,500 ppm
+// causes headaches and over 5,000 ppm exceeds the exposure limit.
+var environmentThresholds = map[EnvironmentMetric]EnvironmentThreshold{
+	MetricOxygen:      {WarningLow: bound(19.5), CriticalLow: bound(16), WarningHigh: bound(23.5), CriticalHigh: bound(25)},
+	MetricCO2:         {WarningHigh: bound(2500), CriticalHigh: bound(5000)},
+	MetricTemperature: {WarningLow: bound(16), CriticalLow: bound(10), WarningHigh: bound(28), CriticalHigh: bound(35)},
+	MetricRadiation:   {WarningHigh: bound(0.01), CriticalHigh: bound(0.1)},
+}
+
+// Threshold returns the safe range of the metric.
+func (m EnvironmentMetric) Threshold() EnvironmentThreshold {
+	return environmentThresholds[m]
+}
+
+// Classify grades a reading of the metric.
+func (m EnvironmentMetric) Classify(value float64) EnvironmentLevel {
+	t := m.Threshold()
+	outside := func(low, high *float64) bool {
+		return (low != nil && value < *low) || (high != nil && value > *high)
+	}
+	switch {
+	case outside(t.CriticalLow, t.CriticalHigh):
+		return EnvironmentCritical
+	case outside(t.WarningLow, t.WarningHigh):
+		return EnvironmentWarning
+	default:
+		return EnvironmentNominal
+	}
+}
+
+// EnvironmentReading is one sensor reading of a metric in a sector.
+type EnvironmentReading struct {
+	ID        string            `json:"id"`
+	Sector    string            `json:"sector"`
+	Metric    EnvironmentMetric `json:"metric"`
+	Value     float64           `json:"value"`
+	Level     EnvironmentLevel  `json:"level"`
+	ReadAt    time.Time         `json:"read_at"`
+	VaultID   int               `json:"vault_id"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// Validate checks if the reading data is valid.
+func (r *EnvironmentReading) Validate() error {
+	if r.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if r.Sector == "" {
+		return fmt.Errorf("sector is required")
+	}
+	if !r.Metric.Valid() {
+		return fmt.Errorf("invalid metric: %s", r.Metric)
+	}
+	if r.Value < 0 {
+		return fmt.Errorf("value cannot be negative")
+	}
+	if r.ReadAt.IsZero() {
+		return fmt.Errorf("read_at is required")
+	}
+	return nil
+}
+
+// String describes the reading, e.g. "CORE CO2 3120 ppm (WARNING)".
+func (r *EnvironmentReading) String() string {
+	format := "%s %s %.1f %s (%s)"
+	switch r.Metric {
+	case MetricCO2:
+		format = "%s %s %.0f %s (%s)"
+	case MetricRadiation:
+		format = "%s %s %.4f %s (%s)"
+	}
+	return fmt.Sprintf(format, r.Sector, r.Metric, r.Value, r.Metric.Unit(), r.Level)
+}
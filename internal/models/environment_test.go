@@ -0,0 +1,76 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEnvironmentMetric_Classify(t *testing.T) {
+	tests := []struct {
+		metric   EnvironmentMetric
+		value    float64
+		want     EnvironmentLevel
+		severity ConditionSeverity
+	}{
+		{MetricOxygen, 20.9, EnvironmentNominal, ""},
+		{MetricOxygen, 19.4, EnvironmentWarning, ConditionSeverityModerate},
+		{MetricOxygen, 15.9, EnvironmentCritical, ConditionSeveritySevere},
+		{MetricOxygen, 24, EnvironmentWarning, ConditionSeverityModerate},
+		{MetricCO2, 800, EnvironmentNominal, ""},
+		{MetricCO2, 2500, EnvironmentNominal, ""},
+		{MetricCO2, 3100, EnvironmentWarning, ConditionSeverityModerate},
+		{MetricCO2, 6000, EnvironmentCritical, ConditionSeveritySevere},
+		{MetricTemperature, 12, EnvironmentWarning, ConditionSeverityModerate},
+		{MetricTemperature, 36, EnvironmentCritical, ConditionSeveritySevere},
+		{MetricRadiation, 0.0003, EnvironmentNominal, ""},
+		{MetricRadiation, 0.05, EnvironmentWarning, ConditionSeverityModerate},
+	}
+
+	for _, tt := range tests {
+		got := tt.metric.Classify(tt.value)
+		if got != tt.want {
+			t.Errorf("%s.Classify(%v) = %s, want %s", tt.metric, tt.value, got, tt.want)
+		}
+		if got.ConditionSeverity() != tt.severity {
+			t.Errorf("%s.ConditionSeverity() = %q, want %q", got, got.ConditionSeverity(), tt.severity)
+		}
+		if got.Breached() != (tt.severity != "") {
+			t.Errorf("%s.Breached() = %v", got, got.Breached())
+		}
+	}
+}
+
+func TestEnvironmentReading_Validate(t *testing.T) {
+	valid := func() *EnvironmentReading {
+		return &EnvironmentReading{
+			ID:     "env-1",
+			Sector: "CORE",
+			Metric: MetricCO2,
+			Value:  820,
+			ReadAt: time.Date(2078, 3, 1, 14, 0, 0, 0, time.UTC),
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*EnvironmentReading)
+		wantErr bool
+	}{
+		{"Valid reading", func(*EnvironmentReading) {}, false},
+		{"Missing sector", func(r *EnvironmentReading) { r.Sector = "" }, true},
+		{"Invalid metric", func(r *EnvironmentReading) { r.Metric = "HUMIDITY" }, true},
+		{"Negative value", func(r *EnvironmentReading) { r.Value = -1 }, true},
+		{"Missing time", func(r *EnvironmentReading) { r.ReadAt = time.Time{} }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := valid()
+			tt.modify(r)
+			err := r.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,691 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// FacilityCategory represents the category of a facility system.
+type FacilityCategory string
+
+const (
+	FacilityCategoryPower          FacilityCategory = "POWER"
+	FacilityCategoryWater          FacilityCategory = "WATER"
+	FacilityCategoryHVAC           FacilityCategory = "HVAC"
+	FacilityCategorySecurity       FacilityCategory = "SECURITY"
+	FacilityCategoryMedical        FacilityCategory = "MEDICAL"
+	FacilityCategoryFoodProduction FacilityCategory = "FOOD_PRODUCTION"
+	FacilityCategoryWaste          FacilityCategory = "WASTE"
+	FacilityCategoryCommunications FacilityCategory = "COMMUNICATIONS"
+	FacilityCategoryStructural     FacilityCategory = "STRUCTURAL"
+)
+
+// FacilityCategories lists every facility category.
+var FacilityCategories = []FacilityCategory{
+	FacilityCategoryPower,
+	FacilityCategoryWater,
+	FacilityCategoryHVAC,
+	FacilityCategorySecurity,
+	FacilityCategoryMedical,
+	FacilityCategoryFoodProduction,
+	FacilityCategoryWaste,
+	FacilityCategoryCommunications,
+	FacilityCategoryStructural,
+}
+
+// Valid returns true if the category is valid.
+func (c FacilityCategory) Valid() bool {
+	for _, category := range FacilityCategories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+// LifeSupport returns true for the categories that keep residents alive:
+// air, water and medical care. Their systems are never shed.
+func (c FacilityCategory) LifeSupport() bool {
+	switch c {
+	case FacilityCategoryHVAC, FacilityCategoryWater, FacilityCategoryMedical:
+		return true
+	default:
+		return false
+	}
+}
+
+// FacilityStatus represents the operational status of a facility system.
+type FacilityStatus string
+
+const (
+	FacilityStatusOperational FacilityStatus = "OPERATIONAL"
+	FacilityStatusDegraded    FacilityStatus = "DEGRADED"
+	FacilityStatusMaintenance FacilityStatus = "MAINTENANCE"
+	FacilityStatusOffline     FacilityStatus = "OFFLINE"
+	FacilityStatusFailed      FacilityStatus = "FAILED"
+	FacilityStatusDestroyed   FacilityStatus = "DESTROYED"
+	FacilityStatusRetired     FacilityStatus = "RETIRED" // Decommissioned, kept on record
+)
+
+// Valid returns true if the status is valid.
+func (s FacilityStatus) Valid() bool {
+	switch s {
+	case FacilityStatusOperational, FacilityStatusDegraded, FacilityStatusMaintenance,
+		FacilityStatusOffline, FacilityStatusFailed, FacilityStatusDestroyed, FacilityStatusRetired:
+		return true
+	default:
+		return false
+	}
+}
+
+// FacilitySystem represents a piece of vault infrastructure.
+type FacilitySystem struct {
+	ID                       string           `json:"id"`
+	SystemCode               string           `json:"system_code"`
+	Name                     string           `json:"name"`
+	Category                 FacilityCategory `json:"category"`
+	LocationSector           string           `json:"location_sector"`
+	LocationLevel            int              `json:"location_level"`
+	Status                   FacilityStatus   `json:"status"`
+	EfficiencyPercent        float64          `json:"efficiency_percent"`
+	InstallDate              time.Time        `json:"install_date"`
+	LastMaintenanceDate      *time.Time       `json:"last_maintenance_date,omitempty"`
+	NextMaintenanceDue       *time.Time       `json:"next_maintenance_due,omitempty"`
+	MaintenanceIntervalDays  int              `json:"maintenance_interval_days"`
+	MTBFHours                *int             `json:"mtbf_hours,omitempty"`
+	TotalRuntimeHours        float64          `json:"total_runtime_hours"`
+	DutyCyclePercent         float64          `json:"duty_cycle_percent"`                   // Share of its online time the system runs
+	MaintenanceIntervalHours *float64         `json:"maintenance_interval_hours,omitempty"` // Runtime between maintenance, in place of the calendar interval
+	RuntimeAtLastMaintenance float64          `json:"runtime_at_last_maintenance"`
+	CurrentOutput            *float64         `json:"current_output,omitempty"` // Percent of rated flows when limited, 0 while shed
+	DecommissionedAt         *time.Time       `json:"decommissioned_at,omitempty"`
+	DecommissionReason       string           `json:"decommission_reason,omitempty"`
+	Notes                    string           `json:"notes,omitempty"`
+	VaultID                  int              `json:"vault_id"`
+	CreatedAt                time.Time        `json:"created_at"`
+	UpdatedAt                time.Time        `json:"updated_at"`
+}
+
+// FacilitySystemSortColumns are the sort keys accepted by facility system
+// lists.
+var FacilitySystemSortColumns = []string{
+	"system_code", "name", "category", "location", "status",
+	"efficiency", "next_maintenance_due", "runtime",
+}
+
+// IsRunning returns true if the system is online, i.e. operational or degraded.
+func (s *FacilitySystem) IsRunning() bool {
+	return s.Status == FacilityStatusOperational || s.Status == FacilityStatusDegraded
+}
+
+// IsRetired returns true if the system has been decommissioned. A retired
+// system is kept on record but left out of status roll-ups.
+func (s *FacilitySystem) IsRetired() bool {
+	return s.Status == FacilityStatusRetired
+}
+
+// IsShed returns true if load shedding has powered the system down.
+func (s *FacilitySystem) IsShed() bool {
+	return s.CurrentOutput != nil && *s.CurrentOutput == 0
+}
+
+// FailureProbability returns the chance that the system fails within the
+// given running hours. The rated MTBF is shortened by lost efficiency and by
+// wear, one extra MTBF's worth of failure rate for every MTBF of runtime
+// since the system was last maintained. Systems without an MTBF rating never
+// fail at random.
+func (s *FacilitySystem) FailureProbability(hours float64) float64 {
+	if s.MTBFHours == nil || *s.MTBFHours <= 0 || hours <= 0 {
+		return 0
+	}
+	mtbf := float64(*s.MTBFHours)
+	effective := mtbf * s.EfficiencyPercent / 100 / (1 + s.RuntimeSinceMaintenance()/mtbf)
+	if effective <= 0 {
+		return 1
+	}
+	return 1 - math.Exp(-hours/effective)
+}
+
+// DutyCycle returns the percentage of its online time the system runs, 100
+// when not set.
+func (s *FacilitySystem) DutyCycle() float64 {
+	if s.DutyCyclePercent <= 0 {
+		return 100
+	}
+	return s.DutyCyclePercent
+}
+
+// RunningHours returns how many of the given hours of vault time the system
+// runs: its duty cycle's share while it is online and not shed, none
+// otherwise.
+func (s *FacilitySystem) RunningHours(hours float64) float64 {
+	if !s.IsRunning() || s.IsShed() || hours <= 0 {
+		return 0
+	}
+	return hours * s.DutyCycle() / 100
+}
+
+// RuntimeSinceMaintenance returns the hours the system has run since it was
+// last maintained.
+func (s *FacilitySystem) RuntimeSinceMaintenance() float64 {
+	return math.Max(0, s.TotalRuntimeHours-s.RuntimeAtLastMaintenance)
+}
+
+// NextMaintenanceFrom returns when the system next falls due for
+// maintenance, as of at. A system maintained by runtime falls due once it
+// has run its interval since its last maintenance, projected at its duty
+// cycle, or at at when it already has; any other system its calendar
+// interval after at.
+func (s *FacilitySystem) NextMaintenanceFrom(at time.Time) time.Time {
+	if s.MaintenanceIntervalHours == nil {
+		return at.AddDate(0, 0, s.MaintenanceIntervalDays)
+	}
+	remaining := *s.MaintenanceIntervalHours - s.RuntimeSinceMaintenance()
+	if remaining <= 0 {
+		return at
+	}
+	hours := remaining * 100 / s.DutyCycle()
+	return at.AddDate(0, 0, int(hours/24)).Add(time.Duration(math.Mod(hours, 24) * float64(time.Hour)))
+}
+
+// Run accrues the running hours of the given hours of vault time, ending at
+// at, to the system's runtime, and moves the next maintenance of a system
+// maintained by runtime to when it is now projected. It reports whether
+// the system reached its runtime interval on this run.
+func (s *FacilitySystem) Run(hours float64, at time.Time) bool {
+	running := s.RunningHours(hours)
+	if running <= 0 {
+		return false
+	}
+	before := s.RuntimeSinceMaintenance()
+	s.TotalRuntimeHours += running
+	if s.MaintenanceIntervalHours == nil {
+		return false
+	}
+	due := s.NextMaintenanceFrom(at)
+	s.NextMaintenanceDue = &due
+	return before < *s.MaintenanceIntervalHours && s.RuntimeSinceMaintenance() >= *s.MaintenanceIntervalHours
+}
+
+// Validate checks a facility system for required fields and value ranges.
+func (s *FacilitySystem) Validate() error {
+	if s.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if s.SystemCode == "" {
+		return fmt.Errorf("system_code is required")
+	}
+	if s.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if !s.Category.Valid() {
+		return fmt.Errorf("invalid category: %s", s.Category)
+	}
+	if s.LocationSector == "" {
+		return fmt.Errorf("location_sector is required")
+	}
+	if !s.Status.Valid() {
+		return fmt.Errorf("invalid status: %s", s.Status)
+	}
+	if s.EfficiencyPercent < 0 || s.EfficiencyPercent > 100 {
+		return fmt.Errorf("efficiency_percent must be 0-100")
+	}
+	if s.MaintenanceIntervalDays < 1 {
+		return fmt.Errorf("maintenance_interval_days must be at least 1")
+	}
+	if s.MTBFHours != nil && *s.MTBFHours <= 0 {
+		return fmt.Errorf("mtbf_hours must be positive")
+	}
+	return nil
+}
+
+// Grid identifies a vault utility grid.
+type Grid string
+
+const (
+	GridPower Grid = "POWER"
+	GridWater Grid = "WATER"
+)
+
+// Valid returns true if the grid is valid.
+func (g Grid) Valid() bool {
+	switch g {
+	case GridPower, GridWater:
+		return true
+	default:
+		return false
+	}
+}
+
+// Unit returns the unit grid flow rates are measured in.
+func (g Grid) Unit() string {
+	switch g {
+	case GridPower:
+		return "kW"
+	case GridWater:
+		return "L/day"
+	default:
+		return ""
+	}
+}
+
+// FlowDirection is whether a system supplies or draws from a grid.
+type FlowDirection string
+
+const (
+	FlowOutput FlowDirection = "OUTPUT"
+	FlowDraw   FlowDirection = "DRAW"
+)
+
+// Valid returns true if the flow direction is valid.
+func (d FlowDirection) Valid() bool {
+	return d == FlowOutput || d == FlowDraw
+}
+
+// GridFlow declares what a facility system supplies to or draws from a grid.
+// Rate is the nameplate rate in the grid's unit.
+type GridFlow struct {
+	ID        string        `json:"id"`
+	SystemID  string        `json:"system_id"`
+	Grid      Grid          `json:"grid"`
+	Direction FlowDirection `json:"direction"`
+	Rate      float64       `json:"rate"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// Validate checks if the grid flow data is valid.
+func (f *GridFlow) Validate() error {
+	if f.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if f.SystemID == "" {
+		return fmt.Errorf("system_id is required")
+	}
+	if !f.Grid.Valid() {
+		return fmt.Errorf("invalid grid: %s", f.Grid)
+	}
+	if !f.Direction.Valid() {
+		return fmt.Errorf("invalid direction: %s", f.Direction)
+	}
+	if f.Rate < 0 {
+		return fmt.Errorf("rate cannot be negative")
+	}
+	return nil
+}
+
+// EffectiveRate returns the flow's current rate given the state of its system.
+// A running system supplies at its efficiency but draws its full rate; a
+// stopped system neither supplies nor draws. A system whose output is
+// limited runs every flow at that share of its rate, so a shed system
+// neither supplies nor draws either.
+func (f *GridFlow) EffectiveRate(sys *FacilitySystem) float64 {
+	if !sys.IsRunning() {
+		return 0
+	}
+	rate := f.Rate
+	if sys.CurrentOutput != nil {
+		rate *= *sys.CurrentOutput / 100
+	}
+	if f.Direction == FlowOutput {
+		return rate * sys.EfficiencyPercent / 100
+	}
+	return rate
+}
+
+// MaintenanceType represents the kind of maintenance work.
+type MaintenanceType string
+
+const (
+	MaintenanceTypePreventive MaintenanceType = "PREVENTIVE"
+	MaintenanceTypeCorrective MaintenanceType = "CORRECTIVE"
+	MaintenanceTypeEmergency  MaintenanceType = "EMERGENCY"
+	MaintenanceTypeInspection MaintenanceType = "INSPECTION"
+	MaintenanceTypeUpgrade    MaintenanceType = "UPGRADE"
+)
+
+// Valid returns true if the maintenance type is valid.
+func (m MaintenanceType) Valid() bool {
+	switch m {
+	case MaintenanceTypePreventive, MaintenanceTypeCorrective, MaintenanceTypeEmergency,
+		MaintenanceTypeInspection, MaintenanceTypeUpgrade:
+		return true
+	default:
+		return false
+	}
+}
+
+// MaintenanceOutcome is how a work order was closed.
+type MaintenanceOutcome string
+
+const (
+	MaintenanceOutcomeCompleted MaintenanceOutcome = "COMPLETED"
+	MaintenanceOutcomePartial   MaintenanceOutcome = "PARTIAL"
+	MaintenanceOutcomeFailed    MaintenanceOutcome = "FAILED"
+	MaintenanceOutcomeDeferred  MaintenanceOutcome = "DEFERRED"
+	MaintenanceOutcomeCancelled MaintenanceOutcome = "CANCELLED"
+)
+
+// Valid returns true if the maintenance outcome is valid.
+func (o MaintenanceOutcome) Valid() bool {
+	switch o {
+	case MaintenanceOutcomeCompleted, MaintenanceOutcomePartial, MaintenanceOutcomeFailed,
+		MaintenanceOutcomeDeferred, MaintenanceOutcomeCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// MaintenanceRecord is a work order against a facility system. Records without
+// an outcome are open work orders.
+type MaintenanceRecord struct {
+	ID               string             `json:"id"`
+	SystemID         string             `json:"system_id"`
+	MaintenanceType  MaintenanceType    `json:"maintenance_type"`
+	Description      string             `json:"description"`
+	LeadTechnicianID *string            `json:"lead_technician_id,omitempty"`
+	ScheduledDate    *time.Time         `json:"scheduled_date,omitempty"`
+	RaisedAt         *time.Time         `json:"raised_at,omitempty"` // Vault time; nil on orders raised before it was recorded
+	CompletedAt      *time.Time         `json:"completed_at,omitempty"`
+	Outcome          MaintenanceOutcome `json:"outcome,omitempty"`
+	ActualHours      *float64           `json:"actual_hours,omitempty"`      // Labor hours the work took
+	EfficiencyBefore *float64           `json:"efficiency_before,omitempty"` // System efficiency when the order closed
+	EfficiencyAfter  *float64           `json:"efficiency_after,omitempty"`  // System efficiency the work left
+	Notes            string             `json:"notes,omitempty"`
+	CreatedAt        time.Time          `json:"created_at"`
+	UpdatedAt        time.Time          `json:"updated_at"`
+}
+
+// IsRepair returns true if the work order repairs a fault: corrective or
+// emergency work.
+func (m *MaintenanceRecord) IsRepair() bool {
+	return m.MaintenanceType == MaintenanceTypeCorrective || m.MaintenanceType == MaintenanceTypeEmergency
+}
+
+// RepairTime returns the time from raising the work order to completing
+// it, counted from its scheduled date when it has no raise time. It reports
+// false for an order not completed or with neither.
+func (m *MaintenanceRecord) RepairTime() (time.Duration, bool) {
+	if m.Outcome != MaintenanceOutcomeCompleted || m.CompletedAt == nil {
+		return 0, false
+	}
+	raised := m.RaisedAt
+	if raised == nil {
+		raised = m.ScheduledDate
+	}
+	if raised == nil || m.CompletedAt.Before(*raised) {
+		return 0, false
+	}
+	return m.CompletedAt.Sub(*raised), true
+}
+
+// Validate checks if the maintenance record data is valid.
+func (m *MaintenanceRecord) Validate() error {
+	if m.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if m.SystemID == "" {
+		return fmt.Errorf("system_id is required")
+	}
+	if !m.MaintenanceType.Valid() {
+		return fmt.Errorf("invalid maintenance_type: %s", m.MaintenanceType)
+	}
+	if m.Description == "" {
+		return fmt.Errorf("description is required")
+	}
+	return nil
+}
+
+// SystemConsumable is a part a facility system wears through on a fixed
+// cycle, such as a filter cartridge, drawn from stock of a resource item.
+type SystemConsumable struct {
+	ID           string     `json:"id"`
+	SystemID     string     `json:"system_id"`
+	ItemID       string     `json:"item_id"`
+	Quantity     float64    `json:"quantity"`      // Units of the item one replacement takes
+	IntervalDays int        `json:"interval_days"` // Days between replacements
+	LastReplaced *time.Time `json:"last_replaced,omitempty"`
+	NextDue      *time.Time `json:"next_due,omitempty"` // Nil until first replaced
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+
+	// Joined fields
+	Item *ResourceItem
+}
+
+// Validate checks if the consumable data is valid.
+func (c *SystemConsumable) Validate() error {
+	if c.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if c.SystemID == "" {
+		return fmt.Errorf("system_id is required")
+	}
+	if c.ItemID == "" {
+		return fmt.Errorf("item_id is required")
+	}
+	if c.Quantity <= 0 {
+		return fmt.Errorf("quantity must be positive")
+	}
+	if c.IntervalDays <= 0 {
+		return fmt.Errorf("interval_days must be positive")
+	}
+	return nil
+}
+
+// DueBy returns true if the consumable needs replacing at or before t. One
+// never replaced is always due.
+func (c *SystemConsumable) DueBy(t time.Time) bool {
+	return c.NextDue == nil || !c.NextDue.After(t)
+}
+
+// Replace records a replacement at the given time, restarting the cycle.
+func (c *SystemConsumable) Replace(at time.Time) {
+	next := at.AddDate(0, 0, c.IntervalDays)
+	c.LastReplaced = &at
+	c.NextDue = &next
+}
+
+// SystemDependency records that a facility system needs another, its
+// parent, running to run at full efficiency, e.g. water purification on the
+// reactor that powers it.
+type SystemDependency struct {
+	SystemID    string    `json:"system_id"`
+	DependsOnID string    `json:"depends_on_id"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Validate checks if the dependency data is valid.
+func (d *SystemDependency) Validate() error {
+	if d.SystemID == "" {
+		return fmt.Errorf("system_id is required")
+	}
+	if d.DependsOnID == "" {
+		return fmt.Errorf("depends_on_id is required")
+	}
+	if d.SystemID == d.DependsOnID {
+		return fmt.Errorf("a system cannot depend on itself")
+	}
+	return nil
+}
+
+// DependencyGraph indexes system dependencies by system and by parent.
+type DependencyGraph struct {
+	parents    map[string][]string
+	dependents map[string][]string
+}
+
+// NewDependencyGraph builds the graph of the dependencies.
+func NewDependencyGraph(deps []*SystemDependency) *DependencyGraph {
+	g := &DependencyGraph{
+		parents:    make(map[string][]string),
+		dependents: make(map[string][]string),
+	}
+	for _, d := range deps {
+		g.parents[d.SystemID] = append(g.parents[d.SystemID], d.DependsOnID)
+		g.dependents[d.DependsOnID] = append(g.dependents[d.DependsOnID], d.SystemID)
+	}
+	return g
+}
+
+// Parents returns the systems a system depends on directly.
+func (g *DependencyGraph) Parents(systemID string) []string {
+	return g.parents[systemID]
+}
+
+// Dependents returns the systems that depend directly on a system.
+func (g *DependencyGraph) Dependents(systemID string) []string {
+	return g.dependents[systemID]
+}
+
+// Downstream returns every system that depends on a system, directly or
+// through others, nearest first. Each is listed once, after all the
+// systems it depends on through the given one.
+func (g *DependencyGraph) Downstream(systemID string) []string {
+	// Kahn's order over the subgraph reachable from systemID
+	reached := map[string]bool{}
+	stack := []string{systemID}
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, d := range g.dependents[id] {
+			if !reached[d] && d != systemID {
+				reached[d] = true
+				stack = append(stack, d)
+			}
+		}
+	}
+	waiting := make(map[string]int, len(reached))
+	for id := range reached {
+		for _, p := range g.parents[id] {
+			if reached[p] || p == systemID {
+				waiting[id]++
+			}
+		}
+	}
+
+	var order []string
+	queue := []string{systemID}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, d := range g.dependents[id] {
+			if !reached[d] {
+				continue
+			}
+			if waiting[d]--; waiting[d] == 0 {
+				order = append(order, d)
+				queue = append(queue, d)
+			}
+		}
+	}
+	return order
+}
+
+// DependsOn returns true if a system depends on another, directly or
+// through others.
+func (g *DependencyGraph) DependsOn(systemID, parentID string) bool {
+	for _, id := range g.Downstream(parentID) {
+		if id == systemID {
+			return true
+		}
+	}
+	return false
+}
+
+// SystemCascade records a running system degraded because a system it
+// depends on, its root cause, stopped running or degraded, and what it was
+// before so it can be restored once the root cause is back.
+type SystemCascade struct {
+	SystemID         string         `json:"system_id"`
+	RootID           string         `json:"root_id"`
+	StatusBefore     FacilityStatus `json:"status_before"`
+	EfficiencyBefore float64        `json:"efficiency_before"`
+	At               time.Time      `json:"at"`
+}
+
+// ShedPriority places a system that draws power in the load-shedding order:
+// when the power grid cannot meet demand, systems are powered down lowest
+// priority first. Systems without a priority are never shed.
+type ShedPriority struct {
+	SystemID  string    `json:"system_id"`
+	Priority  int       `json:"priority"` // 1 is shed first
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate checks if the shed priority data is valid.
+func (p *ShedPriority) Validate() error {
+	if p.SystemID == "" {
+		return fmt.Errorf("system_id is required")
+	}
+	if p.Priority < 1 {
+		return fmt.Errorf("priority must be at least 1")
+	}
+	return nil
+}
+
+// ShedLoad is a system that draws power, with its place in the
+// load-shedding order.
+type ShedLoad struct {
+	System   *FacilitySystem
+	Priority int     // 0 when never shed
+	Draw     float64 // Power drawn at full output
+}
+
+// SortShedLoads sorts loads into the load-shedding order, first shed
+// first, with the loads never shed last.
+func SortShedLoads(loads []*ShedLoad) {
+	sort.SliceStable(loads, func(i, j int) bool {
+		a, b := loads[i], loads[j]
+		if a.Priority != b.Priority {
+			return b.Priority == 0 || (a.Priority > 0 && a.Priority < b.Priority)
+		}
+		return a.System.SystemCode < b.System.SystemCode
+	})
+}
+
+// PlanLoadShed returns the loads to shed to bring a negative power margin
+// back to zero, lowest priority first, or, with margin to spare, the shed
+// loads it can power up again, highest priority first. Restoring stops at
+// the first load that does not fit, so loads come back in the reverse of
+// the order they go. A deficit the sheddable loads cannot cover sheds them
+// all. A shed load since taken out of the order is restored first.
+func PlanLoadShed(margin float64, loads []*ShedLoad) (shed, restore []*ShedLoad) {
+	ordered := make([]*ShedLoad, 0, len(loads))
+	for _, l := range loads {
+		if l.System.IsShed() || (l.Priority > 0 && l.System.IsRunning()) {
+			ordered = append(ordered, l)
+		}
+	}
+	SortShedLoads(ordered)
+
+	if margin < 0 {
+		for _, l := range ordered {
+			if margin >= 0 {
+				break
+			}
+			if l.Priority > 0 && !l.System.IsShed() {
+				shed = append(shed, l)
+				margin += l.Draw
+			}
+		}
+		return shed, nil
+	}
+
+	for i := len(ordered) - 1; i >= 0; i-- {
+		l := ordered[i]
+		if !l.System.IsShed() {
+			continue
+		}
+		if l.Draw > margin {
+			break
+		}
+		restore = append(restore, l)
+		margin -= l.Draw
+	}
+	return nil, restore
+}
@@ -0,0 +1,473 @@
+package models
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGridFlow_Validate(t *testing.T) {
+	valid := func() *GridFlow {
+		return &GridFlow{
+			ID:        "flow-1",
+			SystemID:  "sys-1",
+			Grid:      GridPower,
+			Direction: FlowOutput,
+			Rate:      2000,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*GridFlow)
+		wantErr bool
+	}{
+		{"Valid flow", func(*GridFlow) {}, false},
+		{"Zero rate", func(f *GridFlow) { f.Rate = 0 }, false},
+		{"Missing system", func(f *GridFlow) { f.SystemID = "" }, true},
+		{"Invalid grid", func(f *GridFlow) { f.Grid = "STEAM" }, true},
+		{"Invalid direction", func(f *GridFlow) { f.Direction = "BOTH" }, true},
+		{"Negative rate", func(f *GridFlow) { f.Rate = -1 }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flow := valid()
+			tt.modify(flow)
+			err := flow.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGridFlow_EffectiveRate(t *testing.T) {
+	shed, half := 0.0, 50.0
+
+	tests := []struct {
+		name       string
+		direction  FlowDirection
+		status     FacilityStatus
+		efficiency float64
+		output     *float64
+		expected   float64
+	}{
+		{"Operational output", FlowOutput, FacilityStatusOperational, 100, nil, 1000},
+		{"Degraded output scales with efficiency", FlowOutput, FacilityStatusDegraded, 60, nil, 600},
+		{"Offline output", FlowOutput, FacilityStatusOffline, 100, nil, 0},
+		{"Failed output", FlowOutput, FacilityStatusFailed, 100, nil, 0},
+		{"Degraded draw is full rate", FlowDraw, FacilityStatusDegraded, 60, nil, 1000},
+		{"Maintenance draw", FlowDraw, FacilityStatusMaintenance, 100, nil, 0},
+		{"Shed draw", FlowDraw, FacilityStatusOperational, 100, &shed, 0},
+		{"Limited draw", FlowDraw, FacilityStatusDegraded, 60, &half, 500},
+		{"Limited output scales with efficiency", FlowOutput, FacilityStatusDegraded, 60, &half, 300},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flow := &GridFlow{Grid: GridPower, Direction: tt.direction, Rate: 1000}
+			sys := &FacilitySystem{Status: tt.status, EfficiencyPercent: tt.efficiency, CurrentOutput: tt.output}
+			if got := flow.EffectiveRate(sys); got != tt.expected {
+				t.Errorf("EffectiveRate() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFacilitySystem_FailureProbability(t *testing.T) {
+	mtbf := 1000
+
+	tests := []struct {
+		name       string
+		mtbf       *int
+		efficiency float64
+		runtime    float64
+		maintained float64
+		hours      float64
+		expected   float64
+	}{
+		{"No MTBF rating", nil, 100, 0, 0, 1000, 0},
+		{"No elapsed time", &mtbf, 100, 0, 0, 0, 0},
+		{"One MTBF at full efficiency", &mtbf, 100, 0, 0, 1000, 0.6321},
+		{"Half efficiency halves MTBF", &mtbf, 50, 0, 0, 500, 0.6321},
+		{"Wear after one MTBF of runtime", &mtbf, 100, 1000, 0, 500, 0.6321},
+		{"Maintenance clears wear", &mtbf, 100, 1000, 1000, 1000, 0.6321},
+		{"Zero efficiency always fails", &mtbf, 0, 0, 0, 1, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sys := &FacilitySystem{
+				MTBFHours:                tt.mtbf,
+				EfficiencyPercent:        tt.efficiency,
+				TotalRuntimeHours:        tt.runtime,
+				RuntimeAtLastMaintenance: tt.maintained,
+			}
+			got := sys.FailureProbability(tt.hours)
+			if math.Abs(got-tt.expected) > 0.0001 {
+				t.Errorf("FailureProbability(%v) = %.4f, want %.4f", tt.hours, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFacilitySystem_RunningHours(t *testing.T) {
+	shed := 0.0
+
+	tests := []struct {
+		name     string
+		status   FacilityStatus
+		duty     float64
+		output   *float64
+		expected float64
+	}{
+		{"Operational at full duty", FacilityStatusOperational, 100, nil, 10},
+		{"Duty cycle not set runs full time", FacilityStatusOperational, 0, nil, 10},
+		{"Degraded at partial duty", FacilityStatusDegraded, 40, nil, 4},
+		{"Shed system does not run", FacilityStatusOperational, 100, &shed, 0},
+		{"Offline system does not run", FacilityStatusOffline, 100, nil, 0},
+		{"Failed system does not run", FacilityStatusFailed, 100, nil, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sys := &FacilitySystem{Status: tt.status, DutyCyclePercent: tt.duty, CurrentOutput: tt.output}
+			if got := sys.RunningHours(10); got != tt.expected {
+				t.Errorf("RunningHours(10) = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFacilitySystem_NextMaintenanceFrom(t *testing.T) {
+	at := time.Date(2077, 10, 23, 6, 0, 0, 0, time.UTC)
+	interval := 100.0
+
+	tests := []struct {
+		name     string
+		interval *float64
+		duty     float64
+		runtime  float64
+		expected time.Time
+	}{
+		{"Calendar interval", nil, 50, 0, at.AddDate(0, 0, 30)},
+		{"Runtime interval at full duty", &interval, 100, 52, at.Add(48 * time.Hour)},
+		{"Runtime interval at half duty", &interval, 50, 52, at.Add(96 * time.Hour)},
+		{"Runtime interval reached", &interval, 100, 120, at},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sys := &FacilitySystem{
+				MaintenanceIntervalDays:  30,
+				MaintenanceIntervalHours: tt.interval,
+				DutyCyclePercent:         tt.duty,
+				TotalRuntimeHours:        1000 + tt.runtime,
+				RuntimeAtLastMaintenance: 1000,
+			}
+			if got := sys.NextMaintenanceFrom(at); !got.Equal(tt.expected) {
+				t.Errorf("NextMaintenanceFrom() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFacilitySystem_Run(t *testing.T) {
+	at := time.Date(2077, 10, 23, 6, 0, 0, 0, time.UTC)
+	interval := 100.0
+	sys := &FacilitySystem{
+		Status:                   FacilityStatusOperational,
+		DutyCyclePercent:         50,
+		MaintenanceIntervalHours: &interval,
+		TotalRuntimeHours:        90,
+	}
+
+	if sys.Run(10, at) {
+		t.Error("Run() reached the interval at 95 hours")
+	}
+	if sys.TotalRuntimeHours != 95 {
+		t.Errorf("TotalRuntimeHours = %v, want 95", sys.TotalRuntimeHours)
+	}
+	if want := at.Add(10 * time.Hour); sys.NextMaintenanceDue == nil || !sys.NextMaintenanceDue.Equal(want) {
+		t.Errorf("NextMaintenanceDue = %v, want %v", sys.NextMaintenanceDue, want)
+	}
+	if !sys.Run(10, at) {
+		t.Error("Run() did not reach the interval at 100 hours")
+	}
+	if sys.Run(10, at) {
+		t.Error("Run() reached the interval again past it")
+	}
+
+	sys.Status = FacilityStatusFailed
+	if sys.Run(10, at); sys.TotalRuntimeHours != 105 {
+		t.Errorf("failed system accrued runtime: %v", sys.TotalRuntimeHours)
+	}
+}
+
+func TestFacilitySystem_Validate(t *testing.T) {
+	valid := func() *FacilitySystem {
+		return &FacilitySystem{
+			ID:                      "sys-1",
+			SystemCode:              "WTR-PURIF-02",
+			Name:                    "Secondary Water Purifier",
+			Category:                FacilityCategoryWater,
+			LocationSector:          "CORE",
+			Status:                  FacilityStatusOperational,
+			EfficiencyPercent:       100,
+			MaintenanceIntervalDays: 60,
+		}
+	}
+	zero := 0
+
+	tests := []struct {
+		name    string
+		modify  func(*FacilitySystem)
+		wantErr bool
+	}{
+		{"Valid system", func(*FacilitySystem) {}, false},
+		{"Retired", func(s *FacilitySystem) { s.Status = FacilityStatusRetired }, false},
+		{"Missing code", func(s *FacilitySystem) { s.SystemCode = "" }, true},
+		{"Missing name", func(s *FacilitySystem) { s.Name = "" }, true},
+		{"Invalid category", func(s *FacilitySystem) { s.Category = "STEAM" }, true},
+		{"Missing sector", func(s *FacilitySystem) { s.LocationSector = "" }, true},
+		{"Invalid status", func(s *FacilitySystem) { s.Status = "STANDBY" }, true},
+		{"Efficiency over 100", func(s *FacilitySystem) { s.EfficiencyPercent = 101 }, true},
+		{"Zero interval", func(s *FacilitySystem) { s.MaintenanceIntervalDays = 0 }, true},
+		{"Zero MTBF", func(s *FacilitySystem) { s.MTBFHours = &zero }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sys := valid()
+			tt.modify(sys)
+			err := sys.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSystemConsumable_Validate(t *testing.T) {
+	valid := func() *SystemConsumable {
+		return &SystemConsumable{ID: "c1", SystemID: "sys1", ItemID: "item1", Quantity: 2, IntervalDays: 30}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*SystemConsumable)
+		wantErr bool
+	}{
+		{"Valid consumable", func(*SystemConsumable) {}, false},
+		{"Missing system", func(c *SystemConsumable) { c.SystemID = "" }, true},
+		{"Missing item", func(c *SystemConsumable) { c.ItemID = "" }, true},
+		{"Zero quantity", func(c *SystemConsumable) { c.Quantity = 0 }, true},
+		{"Zero interval", func(c *SystemConsumable) { c.IntervalDays = 0 }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := valid()
+			tt.modify(c)
+			err := c.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSystemConsumable_Replace(t *testing.T) {
+	c := &SystemConsumable{IntervalDays: 30}
+	at := time.Date(2077, 10, 23, 9, 0, 0, 0, time.UTC)
+	if !c.DueBy(at) {
+		t.Error("Expected a consumable never replaced to be due")
+	}
+
+	c.Replace(at)
+	if want := at.AddDate(0, 0, 30); !c.NextDue.Equal(want) {
+		t.Errorf("NextDue = %v, want %v", c.NextDue, want)
+	}
+	if c.DueBy(at.AddDate(0, 0, 29)) {
+		t.Error("Expected the consumable not to be due before its interval")
+	}
+	if !c.DueBy(at.AddDate(0, 0, 30)) {
+		t.Error("Expected the consumable to be due at its interval")
+	}
+}
+
+func TestSystemDependency_Validate(t *testing.T) {
+	valid := func() *SystemDependency {
+		return &SystemDependency{SystemID: "water", DependsOnID: "reactor"}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*SystemDependency)
+		wantErr bool
+	}{
+		{"Valid dependency", func(*SystemDependency) {}, false},
+		{"Missing system", func(d *SystemDependency) { d.SystemID = "" }, true},
+		{"Missing parent", func(d *SystemDependency) { d.DependsOnID = "" }, true},
+		{"Depends on itself", func(d *SystemDependency) { d.DependsOnID = d.SystemID }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := valid()
+			tt.modify(d)
+			err := d.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestShedPriority_Validate(t *testing.T) {
+	valid := func() *ShedPriority {
+		return &ShedPriority{SystemID: "lights", Priority: 3}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*ShedPriority)
+		wantErr bool
+	}{
+		{"Valid priority", func(*ShedPriority) {}, false},
+		{"Missing system", func(p *ShedPriority) { p.SystemID = "" }, true},
+		{"Zero priority", func(p *ShedPriority) { p.Priority = 0 }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := valid()
+			tt.modify(p)
+			err := p.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPlanLoadShed(t *testing.T) {
+	off := 0.0
+	load := func(code string, priority int, draw float64, shed bool) *ShedLoad {
+		sys := &FacilitySystem{SystemCode: code, Status: FacilityStatusOperational}
+		if shed {
+			sys.CurrentOutput = &off
+		}
+		return &ShedLoad{System: sys, Priority: priority, Draw: draw}
+	}
+	codes := func(loads []*ShedLoad) string {
+		var s []string
+		for _, l := range loads {
+			s = append(s, l.System.SystemCode)
+		}
+		return strings.Join(s, ",")
+	}
+
+	tests := []struct {
+		name        string
+		margin      float64
+		loads       []*ShedLoad
+		wantShed    string
+		wantRestore string
+	}{
+		{
+			name:     "Sheds lowest priority first until the deficit is covered",
+			margin:   -50,
+			loads:    []*ShedLoad{load("quarters", 3, 100, false), load("monitor", 1, 30, false), load("hydro", 2, 40, false)},
+			wantShed: "monitor,hydro",
+		},
+		{
+			name:     "Never sheds loads without a priority",
+			margin:   -500,
+			loads:    []*ShedLoad{load("air", 0, 300, false), load("monitor", 1, 30, false)},
+			wantShed: "monitor",
+		},
+		{
+			name:     "Skips loads already shed",
+			margin:   -20,
+			loads:    []*ShedLoad{load("monitor", 1, 30, true), load("hydro", 2, 40, false)},
+			wantShed: "hydro",
+		},
+		{
+			name:        "Waits until the last load shed fits",
+			margin:      75,
+			loads:       []*ShedLoad{load("monitor", 1, 30, true), load("hydro", 2, 40, true), load("quarters", 3, 100, true)},
+			wantRestore: "",
+		},
+		{
+			name:        "Restores in reverse shedding order",
+			margin:      75,
+			loads:       []*ShedLoad{load("monitor", 1, 30, true), load("hydro", 2, 40, true), load("quarters", 3, 10, false)},
+			wantRestore: "hydro,monitor",
+		},
+		{
+			name:        "Restores loads taken out of the order first",
+			margin:      35,
+			loads:       []*ShedLoad{load("monitor", 1, 30, true), load("air", 0, 30, true)},
+			wantRestore: "air",
+		},
+		{
+			name:   "Nothing to do at zero margin",
+			margin: 0,
+			loads:  []*ShedLoad{load("monitor", 1, 30, false)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shed, restore := PlanLoadShed(tt.margin, tt.loads)
+			if got := codes(shed); got != tt.wantShed {
+				t.Errorf("shed = %q, want %q", got, tt.wantShed)
+			}
+			if got := codes(restore); got != tt.wantRestore {
+				t.Errorf("restore = %q, want %q", got, tt.wantRestore)
+			}
+		})
+	}
+}
+
+func TestDependencyGraph_Downstream(t *testing.T) {
+	// reactor <- water <- hydro, reactor <- hydro, reactor <- air
+	g := NewDependencyGraph([]*SystemDependency{
+		{SystemID: "water", DependsOnID: "reactor"},
+		{SystemID: "hydro", DependsOnID: "water"},
+		{SystemID: "hydro", DependsOnID: "reactor"},
+		{SystemID: "air", DependsOnID: "reactor"},
+		{SystemID: "door", DependsOnID: "backup"},
+	})
+
+	got := g.Downstream("reactor")
+	if len(got) != 3 {
+		t.Fatalf("Downstream(reactor) = %v, want 3 systems", got)
+	}
+	position := map[string]int{}
+	for i, id := range got {
+		position[id] = i
+	}
+	if _, ok := position["door"]; ok {
+		t.Errorf("Downstream(reactor) = %v, want no door", got)
+	}
+	if position["water"] > position["hydro"] {
+		t.Errorf("Downstream(reactor) = %v, want water before hydro", got)
+	}
+
+	if got := g.Downstream("hydro"); len(got) != 0 {
+		t.Errorf("Downstream(hydro) = %v, want none", got)
+	}
+	if !g.DependsOn("hydro", "reactor") {
+		t.Error("Expected hydro to depend on the reactor through water")
+	}
+	if g.DependsOn("reactor", "hydro") {
+		t.Error("Expected the reactor not to depend on hydro")
+	}
+	if g.DependsOn("door", "reactor") {
+		t.Error("Expected the door not to depend on the reactor")
+	}
+}
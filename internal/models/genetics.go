@@ -0,0 +1,239 @@
+package models
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Breeding ages, inclusive, of the pairings the genetic health report
+// considers.
+const (
+	BreedingAgeMin = 18
+	BreedingAgeMax = 44
+)
+
+// ViablePairingKinship is the highest kinship, and so offspring COI, of a
+// pairing counted as viable: second cousins.
+const ViablePairingKinship = 0.0156
+
+// Pedigree holds residents' recorded biological parents for kinship
+// calculations. Parents missing from it count as unrelated founders.
+type Pedigree struct {
+	residents map[string]*Resident
+	kinship   map[[2]string]float64
+}
+
+// NewPedigree creates a pedigree of the residents.
+func NewPedigree(residents []*Resident) *Pedigree {
+	p := &Pedigree{
+		residents: make(map[string]*Resident, len(residents)),
+		kinship:   make(map[[2]string]float64),
+	}
+	for _, r := range residents {
+		p.residents[r.ID] = r
+	}
+	return p
+}
+
+// Kinship returns the coefficient of kinship of two residents: the chance
+// that alleles drawn at random from each are identical by descent, which is
+// also the COI of their offspring.
+func (p *Pedigree) Kinship(a, b string) float64 {
+	ra, rb := p.residents[a], p.residents[b]
+	if ra == nil || rb == nil {
+		return 0
+	}
+	if a == b {
+		return 0.5 * (1 + p.Inbreeding(a))
+	}
+
+	// Recurse through the parents of the younger resident, who cannot be
+	// an ancestor of the other
+	if ra.DateOfBirth.Before(rb.DateOfBirth) || (ra.DateOfBirth.Equal(rb.DateOfBirth) && a < b) {
+		ra, rb = rb, ra
+	}
+	key := [2]string{ra.ID, rb.ID}
+	if f, ok := p.kinship[key]; ok {
+		return f
+	}
+
+	var f float64
+	for _, parent := range []*string{ra.BiologicalParent1ID, ra.BiologicalParent2ID} {
+		if parent != nil {
+			f += 0.5 * p.Kinship(*parent, rb.ID)
+		}
+	}
+	p.kinship[key] = f
+	return f
+}
+
+// Inbreeding returns a resident's coefficient of inbreeding, the kinship of
+// their parents.
+func (p *Pedigree) Inbreeding(id string) float64 {
+	r := p.residents[id]
+	if r == nil || r.BiologicalParent1ID == nil || r.BiologicalParent2ID == nil {
+		return 0
+	}
+	return p.Kinship(*r.BiologicalParent1ID, *r.BiologicalParent2ID)
+}
+
+// IsFounder returns true if the resident has no recorded biological parent.
+func (p *Pedigree) IsFounder(id string) bool {
+	r := p.residents[id]
+	return r != nil && r.BiologicalParent1ID == nil && r.BiologicalParent2ID == nil
+}
+
+// FounderContributions returns the expected share of a resident's genome
+// each founder contributed: half through each recorded parent. Halves from
+// parents missing from the pedigree are left out.
+func (p *Pedigree) FounderContributions(id string) map[string]float64 {
+	return p.founderContributions(id, make(map[string]map[string]float64))
+}
+
+func (p *Pedigree) founderContributions(id string, memo map[string]map[string]float64) map[string]float64 {
+	if c, ok := memo[id]; ok {
+		return c
+	}
+	r := p.residents[id]
+	if r == nil {
+		return nil
+	}
+
+	contributions := make(map[string]float64)
+	if p.IsFounder(id) {
+		contributions[id] = 1
+	}
+	for _, parent := range []*string{r.BiologicalParent1ID, r.BiologicalParent2ID} {
+		if parent == nil {
+			continue
+		}
+		for founder, share := range p.founderContributions(*parent, memo) {
+			contributions[founder] += 0.5 * share
+		}
+	}
+	memo[id] = contributions
+	return contributions
+}
+
+// GeneticHealth is a snapshot of the genetic diversity of a vault's living
+// population.
+type GeneticHealth struct {
+	ID                  string    `json:"id"`
+	TakenAt             time.Time `json:"taken_at"`
+	Population          int       `json:"population"`           // Active residents
+	Founders            int       `json:"founders"`             // Residents ever recorded without parents
+	FoundersRepresented int       `json:"founders_represented"` // Founders active or with an active descendant
+	EffectiveFounders   float64   `json:"effective_founders"`   // Founders equally represented who would give the same diversity
+	BreedingPairs       int       `json:"breeding_pairs"`       // Active opposite-sex pairs of breeding age
+	ViablePairs         int       `json:"viable_pairs"`         // Breeding pairs within ViablePairingKinship
+	MeanKinship         float64   `json:"mean_kinship"`         // Average over breeding pairs
+	Score               int       `json:"score"`                // 0-100
+	CreatedAt           time.Time `json:"created_at"`
+}
+
+// FounderRepresentation returns the share of founders still represented in
+// the population, 0-1.
+func (g *GeneticHealth) FounderRepresentation() float64 {
+	if g.Founders == 0 {
+		return 0
+	}
+	return float64(g.FoundersRepresented) / float64(g.Founders)
+}
+
+// ViablePairShare returns the share of breeding pairs that are viable, 0-1.
+func (g *GeneticHealth) ViablePairShare() float64 {
+	if g.BreedingPairs == 0 {
+		return 0
+	}
+	return float64(g.ViablePairs) / float64(g.BreedingPairs)
+}
+
+// AssessGeneticHealth measures the genetic diversity of the active residents
+// as of asOf, against the pedigree of every resident ever recorded. The
+// score weighs founder representation at 40, mean kinship at 40, scaled from
+// 0 up to first cousins (0.0625), and the viable share of breeding pairs at
+// 20.
+func AssessGeneticHealth(residents []*Resident, asOf time.Time) *GeneticHealth {
+	pedigree := NewPedigree(residents)
+	health := &GeneticHealth{TakenAt: asOf}
+
+	var active, men, women []*Resident
+	for _, r := range residents {
+		if pedigree.IsFounder(r.ID) {
+			health.Founders++
+		}
+		if r.Status != ResidentStatusActive {
+			continue
+		}
+		active = append(active, r)
+		if age := r.Age(asOf); age >= BreedingAgeMin && age <= BreedingAgeMax {
+			switch r.Sex {
+			case SexMale:
+				men = append(men, r)
+			case SexFemale:
+				women = append(women, r)
+			}
+		}
+	}
+	health.Population = len(active)
+	if health.Population == 0 {
+		return health
+	}
+
+	// Founder shares of the population's genome, averaged over the active
+	// residents
+	memo := make(map[string]map[string]float64)
+	shares := make(map[string]float64)
+	for _, r := range active {
+		for founder, share := range pedigree.founderContributions(r.ID, memo) {
+			shares[founder] += share / float64(len(active))
+		}
+	}
+	var total, sumSquares float64
+	for _, share := range shares {
+		total += share
+	}
+	for _, share := range shares {
+		if share > 0 {
+			health.FoundersRepresented++
+			sumSquares += (share / total) * (share / total)
+		}
+	}
+	if sumSquares > 0 {
+		health.EffectiveFounders = 1 / sumSquares
+	}
+
+	var kinship float64
+	for _, m := range men {
+		for _, w := range women {
+			f := pedigree.Kinship(m.ID, w.ID)
+			kinship += f
+			health.BreedingPairs++
+			if f <= ViablePairingKinship {
+				health.ViablePairs++
+			}
+		}
+	}
+	if health.BreedingPairs > 0 {
+		health.MeanKinship = kinship / float64(health.BreedingPairs)
+	}
+
+	score := 40*health.FounderRepresentation() +
+		40*(1-min(health.MeanKinship/0.0625, 1)) +
+		20*health.ViablePairShare()
+	health.Score = int(math.Round(score))
+	return health
+}
+
+// GeneticTrend returns the change in score from the earliest to the latest
+// of the snapshots, 0 with fewer than two.
+func GeneticTrend(snapshots []*GeneticHealth) int {
+	if len(snapshots) < 2 {
+		return 0
+	}
+	sorted := make([]*GeneticHealth, len(snapshots))
+	copy(sorted, snapshots)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].TakenAt.Before(sorted[j].TakenAt) })
+	return sorted[len(sorted)-1].Score - sorted[0].Score
+}
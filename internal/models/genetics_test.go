@@ -0,0 +1,191 @@
+package models
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// geneticsFamily is two founder couples; each has a son and a daughter, and
+// the first couple's son has a son and a daughter with the second couple's
+// daughter:
+//
+//	f1 + f2 -> s1, d1
+//	f3 + f4 -> s2, d2
+//	s1 + d2 -> c1 (son), c2 (daughter)
+func geneticsFamily() []*Resident {
+	born := func(year int) time.Time { return time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC) }
+	person := func(id string, sex Sex, year int, parents ...string) *Resident {
+		r := &Resident{ID: id, Sex: sex, DateOfBirth: born(year), Status: ResidentStatusActive}
+		if len(parents) == 2 {
+			r.BiologicalParent1ID, r.BiologicalParent2ID = &parents[0], &parents[1]
+		}
+		return r
+	}
+	return []*Resident{
+		person("f1", SexMale, 2030),
+		person("f2", SexFemale, 2031),
+		person("f3", SexMale, 2030),
+		person("f4", SexFemale, 2032),
+		person("s1", SexMale, 2050, "f1", "f2"),
+		person("d1", SexFemale, 2051, "f1", "f2"),
+		person("s2", SexMale, 2052, "f3", "f4"),
+		person("d2", SexFemale, 2053, "f3", "f4"),
+		person("c1", SexMale, 2080, "s1", "d2"),
+		person("c2", SexFemale, 2081, "s1", "d2"),
+	}
+}
+
+func TestPedigree_Kinship(t *testing.T) {
+	p := NewPedigree(geneticsFamily())
+
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"f1", "f2", 0},      // Unrelated founders
+		{"f1", "f1", 0.5},    // Self
+		{"f1", "s1", 0.25},   // Parent and child
+		{"s1", "d1", 0.25},   // Full siblings
+		{"c1", "c2", 0.25},   // Full siblings
+		{"c1", "d1", 0.125},  // Aunt and nephew
+		{"c1", "f4", 0.125},  // Grandparent
+		{"d1", "s2", 0},      // Unrelated
+		{"c1", "unknown", 0}, // Missing from the pedigree
+		{"unknown", "c1", 0}, // Either way round
+		{"c2", "c1", 0.25},   // Symmetric
+		{"d2", "c1", 0.25},   // Parent and child
+		{"f3", "c2", 0.125},  // Grandparent
+		{"s1", "s2", 0},      // In-laws
+		{"d1", "c2", 0.125},  // Aunt and niece
+		{"s2", "c2", 0.125},  // Uncle and niece
+		{"s1", "c1", 0.25},   // Parent and child
+		{"f2", "d2", 0},      // Unrelated
+		{"c1", "c1", 0.5},    // Self, parents unrelated
+	}
+
+	for _, tt := range tests {
+		if got := p.Kinship(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Kinship(%s, %s) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestPedigree_Inbreeding(t *testing.T) {
+	family := geneticsFamily()
+	c1, c2 := "c1", "c2"
+	family = append(family, &Resident{
+		ID:                  "g1",
+		DateOfBirth:         time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC),
+		BiologicalParent1ID: &c1,
+		BiologicalParent2ID: &c2,
+	})
+	p := NewPedigree(family)
+
+	if got := p.Inbreeding("g1"); got != 0.25 {
+		t.Errorf("Inbreeding of a child of full siblings = %v, want 0.25", got)
+	}
+	if got := p.Inbreeding("c1"); got != 0 {
+		t.Errorf("Inbreeding of a child of unrelated parents = %v, want 0", got)
+	}
+	if got := p.Kinship("g1", "g1"); got != 0.625 {
+		t.Errorf("Kinship of an inbred resident with themselves = %v, want 0.625", got)
+	}
+}
+
+func TestPedigree_FounderContributions(t *testing.T) {
+	p := NewPedigree(geneticsFamily())
+
+	got := p.FounderContributions("c1")
+	want := map[string]float64{"f1": 0.25, "f2": 0.25, "f3": 0.25, "f4": 0.25}
+	if len(got) != len(want) {
+		t.Fatalf("FounderContributions(c1) = %v, want %v", got, want)
+	}
+	for founder, share := range want {
+		if got[founder] != share {
+			t.Errorf("Contribution of %s = %v, want %v", founder, got[founder], share)
+		}
+	}
+	if got := p.FounderContributions("f1"); got["f1"] != 1 {
+		t.Errorf("Founder's own contribution = %v, want 1", got["f1"])
+	}
+}
+
+func TestAssessGeneticHealth(t *testing.T) {
+	asOf := time.Date(2100, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("Founders and descendants", func(t *testing.T) {
+		family := geneticsFamily()
+		// The founders and their children are past breeding age; only the
+		// grandchildren, full siblings, remain
+		health := AssessGeneticHealth(family, asOf)
+
+		if health.Population != 10 || health.Founders != 4 || health.FoundersRepresented != 4 {
+			t.Errorf("Population/Founders/Represented = %d/%d/%d, want 10/4/4",
+				health.Population, health.Founders, health.FoundersRepresented)
+		}
+		if health.BreedingPairs != 1 || health.ViablePairs != 0 {
+			t.Errorf("BreedingPairs/ViablePairs = %d/%d, want 1/0", health.BreedingPairs, health.ViablePairs)
+		}
+		if health.MeanKinship != 0.25 {
+			t.Errorf("MeanKinship = %v, want 0.25", health.MeanKinship)
+		}
+		// Representation 40, kinship past first cousins 0, no viable pairs 0
+		if health.Score != 40 {
+			t.Errorf("Score = %d, want 40", health.Score)
+		}
+	})
+
+	t.Run("Lost founder line", func(t *testing.T) {
+		family := geneticsFamily()
+		for _, r := range family {
+			if r.ID != "d1" && r.ID != "s2" {
+				r.Status = ResidentStatusDeceased
+			}
+		}
+		health := AssessGeneticHealth(family, asOf.AddDate(-30, 0, 0))
+
+		if health.Population != 2 || health.FoundersRepresented != 4 {
+			t.Errorf("Population/Represented = %d/%d, want 2/4", health.Population, health.FoundersRepresented)
+		}
+		if health.EffectiveFounders != 4 {
+			t.Errorf("EffectiveFounders = %v, want 4", health.EffectiveFounders)
+		}
+		// Unrelated breeding pair: full marks
+		if health.BreedingPairs != 1 || health.ViablePairs != 1 || health.Score != 100 {
+			t.Errorf("Pairs/Viable/Score = %d/%d/%d, want 1/1/100",
+				health.BreedingPairs, health.ViablePairs, health.Score)
+		}
+
+		family[5].Status = ResidentStatusDeceased // d1
+		health = AssessGeneticHealth(family, asOf.AddDate(-30, 0, 0))
+		if health.FoundersRepresented != 2 || health.EffectiveFounders != 2 {
+			t.Errorf("Represented/Effective = %d/%v, want 2/2", health.FoundersRepresented, health.EffectiveFounders)
+		}
+		// Half the founders 20, no pairs leaves kinship 40 and pairing 0
+		if health.Score != 60 {
+			t.Errorf("Score = %d, want 60", health.Score)
+		}
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		if health := AssessGeneticHealth(nil, asOf); health.Score != 0 || health.Population != 0 {
+			t.Errorf("Empty population scored %d", health.Score)
+		}
+	})
+}
+
+func TestGeneticTrend(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2100, 1, d, 0, 0, 0, 0, time.UTC) }
+	snapshots := []*GeneticHealth{
+		{TakenAt: day(3), Score: 70},
+		{TakenAt: day(1), Score: 82},
+		{TakenAt: day(2), Score: 75},
+	}
+	if got := GeneticTrend(snapshots); got != -12 {
+		t.Errorf("GeneticTrend() = %d, want -12", got)
+	}
+	if got := GeneticTrend(snapshots[:1]); got != 0 {
+		t.Errorf("GeneticTrend() of one snapshot = %d, want 0", got)
+	}
+}
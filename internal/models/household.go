@@ -2,6 +2,7 @@ package models
 
 import (
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -47,7 +48,8 @@ func (r RationClass) Valid() bool {
 	}
 }
 
-// CalorieTarget returns the daily calorie target for this ration class.
+// CalorieTarget returns the built-in daily calorie target for this ration
+// class, which applies until a ration policy sets one (see RationTargets).
 func (r RationClass) CalorieTarget() int {
 	switch r {
 	case RationClassMinimal:
@@ -65,7 +67,8 @@ func (r RationClass) CalorieTarget() int {
 	}
 }
 
-// WaterTarget returns the daily water allocation in liters for this ration class.
+// WaterTarget returns the built-in daily water allocation in liters for this
+// ration class, which applies until a ration policy sets one.
 func (r RationClass) WaterTarget() float64 {
 	switch r {
 	case RationClassMinimal:
@@ -83,6 +86,24 @@ func (r RationClass) WaterTarget() float64 {
 	}
 }
 
+// NutrientTargets returns the built-in daily nutrient targets per resident
+// of this ration class, which apply until a ration policy sets them. The
+// macronutrients make up most of the class's calorie target.
+func (r RationClass) NutrientTargets() Nutrients {
+	switch r {
+	case RationClassMinimal:
+		return Nutrients{ProteinG: 45, CarbsG: 205, FatG: 50, VitaminsPct: 100}
+	case RationClassEnhanced:
+		return Nutrients{ProteinG: 75, CarbsG: 345, FatG: 80, VitaminsPct: 100}
+	case RationClassLaborIntensive:
+		return Nutrients{ProteinG: 90, CarbsG: 410, FatG: 100, VitaminsPct: 100}
+	case RationClassMedical:
+		return Nutrients{ProteinG: 70, CarbsG: 260, FatG: 65, VitaminsPct: 100} // Recovery favors protein
+	default:
+		return Nutrients{ProteinG: 60, CarbsG: 275, FatG: 65, VitaminsPct: 100}
+	}
+}
+
 // HouseholdStatus represents the status of a household.
 type HouseholdStatus string
 
@@ -108,6 +129,7 @@ type Household struct {
 	Status            HouseholdStatus `json:"status"`
 	FormedDate        time.Time       `json:"formed_date"`
 	DissolvedDate     *time.Time      `json:"dissolved_date,omitempty"`
+	VaultID           int             `json:"vault_id"`
 	CreatedAt         time.Time       `json:"created_at"`
 	UpdatedAt         time.Time       `json:"updated_at"`
 
@@ -137,9 +159,9 @@ func (h *Household) Validate() error {
 		return fmt.Errorf("formed_date is required")
 	}
 
-	// Dissolved households must have dissolved date
-	if h.Status == HouseholdStatusDissolved && h.DissolvedDate == nil {
-		return fmt.Errorf("dissolved households must have dissolved_date")
+	// Dissolved and merged households must have dissolved date
+	if !h.IsActive() && h.DissolvedDate == nil {
+		return fmt.Errorf("%s households must have dissolved_date", strings.ToLower(string(h.Status)))
 	}
 
 	return nil
@@ -159,6 +181,12 @@ type HouseholdFilter struct {
 	SearchTerm    string // Searches designation
 }
 
+// HouseholdSortColumns are the sort keys accepted by household lists.
+var HouseholdSortColumns = []string{
+	"designation", "household_type", "member_count", "ration_class",
+	"status", "formed_date", "dissolved_date",
+}
+
 // HouseholdList represents a paginated list of households.
 type HouseholdList struct {
 	Households []*Household
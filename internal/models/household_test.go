@@ -265,6 +265,19 @@ func TestHousehold_Validate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Merged without dissolved date",
+			household: &Household{
+				ID:            "hh-001",
+				Designation:   "Smith Family",
+				HouseholdType: HouseholdTypeFamily,
+				RationClass:   RationClassStandard,
+				Status:        HouseholdStatusMerged,
+				FormedDate:    now.AddDate(-1, 0, 0),
+			},
+			wantErr: true,
+			errMsg:  "merged households must have dissolved_date",
+		},
 	}
 
 	for _, tt := range tests {
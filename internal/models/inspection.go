@@ -0,0 +1,210 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// InspectionCategory represents the kind of inspection a template covers.
+type InspectionCategory string
+
+const (
+	InspectionCategorySafetyWalk  InspectionCategory = "SAFETY_WALK"
+	InspectionCategoryFoodStorage InspectionCategory = "FOOD_STORAGE"
+	InspectionCategoryQuarters    InspectionCategory = "QUARTERS"
+)
+
+// Valid returns true if the inspection category is valid.
+func (c InspectionCategory) Valid() bool {
+	switch c {
+	case InspectionCategorySafetyWalk, InspectionCategoryFoodStorage, InspectionCategoryQuarters:
+		return true
+	default:
+		return false
+	}
+}
+
+// InspectionStatus represents the state of a scheduled inspection.
+type InspectionStatus string
+
+const (
+	InspectionStatusScheduled InspectionStatus = "SCHEDULED"
+	InspectionStatusCompleted InspectionStatus = "COMPLETED"
+	InspectionStatusCancelled InspectionStatus = "CANCELLED"
+)
+
+// Valid returns true if the inspection status is valid.
+func (s InspectionStatus) Valid() bool {
+	switch s {
+	case InspectionStatusScheduled, InspectionStatusCompleted, InspectionStatusCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// FindingAction is the follow-up raised from an inspection finding.
+type FindingAction string
+
+const (
+	FindingActionNone      FindingAction = "NONE"
+	FindingActionWorkOrder FindingAction = "WORK_ORDER"
+	FindingActionIncident  FindingAction = "INCIDENT"
+)
+
+// Valid returns true if the finding action is valid.
+func (a FindingAction) Valid() bool {
+	switch a {
+	case FindingActionNone, FindingActionWorkOrder, FindingActionIncident:
+		return true
+	default:
+		return false
+	}
+}
+
+// InspectionTemplate defines a reusable inspection checklist.
+type InspectionTemplate struct {
+	ID           string             `json:"id"`
+	Code         string             `json:"code"`
+	Name         string             `json:"name"`
+	Category     InspectionCategory `json:"category"`
+	Description  string             `json:"description,omitempty"`
+	IntervalDays int                `json:"interval_days"`
+	IsActive     bool               `json:"is_active"`
+	CreatedAt    time.Time          `json:"created_at"`
+	UpdatedAt    time.Time          `json:"updated_at"`
+
+	// Loaded relation
+	Items []*InspectionChecklistItem `json:"items,omitempty"`
+}
+
+// Validate checks if the template data is valid.
+func (t *InspectionTemplate) Validate() error {
+	if t.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if t.Code == "" {
+		return fmt.Errorf("code is required")
+	}
+	if t.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if !t.Category.Valid() {
+		return fmt.Errorf("invalid category: %s", t.Category)
+	}
+	if t.IntervalDays < 1 {
+		return fmt.Errorf("interval_days must be at least 1")
+	}
+	for i, item := range t.Items {
+		if item.Description == "" {
+			return fmt.Errorf("item %d: description is required", i+1)
+		}
+	}
+	return nil
+}
+
+// InspectionChecklistItem is a single check on an inspection template.
+type InspectionChecklistItem struct {
+	ID          string `json:"id"`
+	TemplateID  string `json:"template_id"`
+	Sequence    int    `json:"sequence"`
+	Description string `json:"description"`
+	IsCritical  bool   `json:"is_critical"`
+}
+
+// Inspection is a scheduled or completed run of an inspection template.
+type Inspection struct {
+	ID            string           `json:"id"`
+	TemplateID    string           `json:"template_id"`
+	Location      string           `json:"location,omitempty"`
+	ScheduledDate time.Time        `json:"scheduled_date"`
+	AssignedTo    *string          `json:"assigned_to,omitempty"`
+	Status        InspectionStatus `json:"status"`
+	CompletedAt   *time.Time       `json:"completed_at,omitempty"`
+	CompletedBy   *string          `json:"completed_by,omitempty"`
+	Passed        *bool            `json:"passed,omitempty"`
+	Notes         string           `json:"notes,omitempty"`
+	CreatedAt     time.Time        `json:"created_at"`
+	UpdatedAt     time.Time        `json:"updated_at"`
+
+	// Loaded relations
+	Template *InspectionTemplate  `json:"template,omitempty"`
+	Findings []*InspectionFinding `json:"findings,omitempty"`
+}
+
+// Validate checks if the inspection data is valid.
+func (i *Inspection) Validate() error {
+	if i.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if i.TemplateID == "" {
+		return fmt.Errorf("template_id is required")
+	}
+	if i.ScheduledDate.IsZero() {
+		return fmt.Errorf("scheduled_date is required")
+	}
+	if !i.Status.Valid() {
+		return fmt.Errorf("invalid status: %s", i.Status)
+	}
+	if i.Status == InspectionStatusCompleted && i.CompletedAt == nil {
+		return fmt.Errorf("completed_at is required for completed inspections")
+	}
+	return nil
+}
+
+// IsOverdue returns true if the inspection is still scheduled after its date.
+func (i *Inspection) IsOverdue(asOf time.Time) bool {
+	if i.Status != InspectionStatusScheduled {
+		return false
+	}
+	return asOf.Truncate(24 * time.Hour).After(i.ScheduledDate.Truncate(24 * time.Hour))
+}
+
+// DaysOverdue returns the number of whole days past the scheduled date, or 0.
+func (i *Inspection) DaysOverdue(asOf time.Time) int {
+	if !i.IsOverdue(asOf) {
+		return 0
+	}
+	return int(asOf.Truncate(24*time.Hour).Sub(i.ScheduledDate.Truncate(24*time.Hour)).Hours() / 24)
+}
+
+// InspectionFinding records a deficiency observed during an inspection.
+type InspectionFinding struct {
+	ID                  string        `json:"id"`
+	InspectionID        string        `json:"inspection_id"`
+	ChecklistItemID     *string       `json:"checklist_item_id,omitempty"`
+	Severity            Severity      `json:"severity"`
+	Description         string        `json:"description"`
+	Action              FindingAction `json:"action"`
+	MaintenanceRecordID *string       `json:"maintenance_record_id,omitempty"`
+	IncidentID          *string       `json:"incident_id,omitempty"`
+	CreatedAt           time.Time     `json:"created_at"`
+}
+
+// Validate checks if the finding data is valid.
+func (f *InspectionFinding) Validate() error {
+	if f.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if f.InspectionID == "" {
+		return fmt.Errorf("inspection_id is required")
+	}
+	if !f.Severity.Valid() {
+		return fmt.Errorf("invalid severity: %s", f.Severity)
+	}
+	if f.Description == "" {
+		return fmt.Errorf("description is required")
+	}
+	if !f.Action.Valid() {
+		return fmt.Errorf("invalid action: %s", f.Action)
+	}
+	return nil
+}
+
+// InspectionFilter contains filter options for listing inspections.
+type InspectionFilter struct {
+	Status     *InspectionStatus
+	TemplateID *string
+	From       *time.Time // scheduled on or after
+	To         *time.Time // scheduled on or before
+}
@@ -0,0 +1,186 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInspectionTemplate_Validate(t *testing.T) {
+	valid := func() *InspectionTemplate {
+		return &InspectionTemplate{
+			ID:           "tmpl-1",
+			Code:         "SAFETY-WALK",
+			Name:         "Safety Walk",
+			Category:     InspectionCategorySafetyWalk,
+			IntervalDays: 7,
+			Items: []*InspectionChecklistItem{
+				{ID: "item-1", Description: "Exits unobstructed", IsCritical: true},
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*InspectionTemplate)
+		wantErr bool
+	}{
+		{"Valid template", func(*InspectionTemplate) {}, false},
+		{"Missing code", func(tmpl *InspectionTemplate) { tmpl.Code = "" }, true},
+		{"Missing name", func(tmpl *InspectionTemplate) { tmpl.Name = "" }, true},
+		{"Invalid category", func(tmpl *InspectionTemplate) { tmpl.Category = "REACTOR" }, true},
+		{"Zero interval", func(tmpl *InspectionTemplate) { tmpl.IntervalDays = 0 }, true},
+		{"Empty checklist item", func(tmpl *InspectionTemplate) { tmpl.Items[0].Description = "" }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := valid()
+			tt.modify(tmpl)
+			err := tmpl.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestInspection_Validate(t *testing.T) {
+	completedAt := time.Date(2077, 11, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		inspection Inspection
+		wantErr    bool
+	}{
+		{
+			name: "Scheduled inspection",
+			inspection: Inspection{
+				ID: "insp-1", TemplateID: "tmpl-1",
+				ScheduledDate: completedAt, Status: InspectionStatusScheduled,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Completed with timestamp",
+			inspection: Inspection{
+				ID: "insp-1", TemplateID: "tmpl-1",
+				ScheduledDate: completedAt, Status: InspectionStatusCompleted,
+				CompletedAt: &completedAt,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Completed without timestamp",
+			inspection: Inspection{
+				ID: "insp-1", TemplateID: "tmpl-1",
+				ScheduledDate: completedAt, Status: InspectionStatusCompleted,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Missing scheduled date",
+			inspection: Inspection{
+				ID: "insp-1", TemplateID: "tmpl-1", Status: InspectionStatusScheduled,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Invalid status",
+			inspection: Inspection{
+				ID: "insp-1", TemplateID: "tmpl-1",
+				ScheduledDate: completedAt, Status: "PENDING",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.inspection.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestInspection_DaysOverdue(t *testing.T) {
+	scheduled := time.Date(2077, 11, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		status      InspectionStatus
+		asOf        time.Time
+		wantOverdue bool
+		wantDays    int
+	}{
+		{"Before scheduled date", InspectionStatusScheduled, scheduled.AddDate(0, 0, -1), false, 0},
+		{"Later on scheduled day", InspectionStatusScheduled, scheduled.Add(20 * time.Hour), false, 0},
+		{"Day after", InspectionStatusScheduled, scheduled.AddDate(0, 0, 1), true, 1},
+		{"Ten days after", InspectionStatusScheduled, scheduled.AddDate(0, 0, 10).Add(3 * time.Hour), true, 10},
+		{"Completed is never overdue", InspectionStatusCompleted, scheduled.AddDate(0, 0, 10), false, 0},
+		{"Cancelled is never overdue", InspectionStatusCancelled, scheduled.AddDate(0, 0, 10), false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			insp := &Inspection{ScheduledDate: scheduled, Status: tt.status}
+			if got := insp.IsOverdue(tt.asOf); got != tt.wantOverdue {
+				t.Errorf("IsOverdue() = %v, want %v", got, tt.wantOverdue)
+			}
+			if got := insp.DaysOverdue(tt.asOf); got != tt.wantDays {
+				t.Errorf("DaysOverdue() = %d, want %d", got, tt.wantDays)
+			}
+		})
+	}
+}
+
+func TestInspectionFinding_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		finding InspectionFinding
+		wantErr bool
+	}{
+		{
+			name: "Valid work order finding",
+			finding: InspectionFinding{
+				ID: "f-1", InspectionID: "insp-1", Severity: SeverityModerate,
+				Description: "Conduit leaking", Action: FindingActionWorkOrder,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Invalid severity",
+			finding: InspectionFinding{
+				ID: "f-1", InspectionID: "insp-1", Severity: "LOW",
+				Description: "Conduit leaking", Action: FindingActionNone,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Invalid action",
+			finding: InspectionFinding{
+				ID: "f-1", InspectionID: "insp-1", Severity: SeverityMinor,
+				Description: "Conduit leaking", Action: "ESCALATE",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Missing description",
+			finding: InspectionFinding{
+				ID: "f-1", InspectionID: "insp-1", Severity: SeverityMinor,
+				Action: FindingActionNone,
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.finding.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
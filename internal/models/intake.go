@@ -0,0 +1,185 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// MinIntakeQuarantineDays is the shortest quarantine an admitted resident
+// serves before they can be promoted to ACTIVE.
+const MinIntakeQuarantineDays = 14
+
+// ScreeningType is a medical screening an admitted resident passes during
+// intake quarantine.
+type ScreeningType string
+
+const (
+	ScreeningPhysical      ScreeningType = "PHYSICAL"
+	ScreeningRadiation     ScreeningType = "RADIATION"
+	ScreeningInfectious    ScreeningType = "INFECTIOUS_DISEASE"
+	ScreeningPsychological ScreeningType = "PSYCHOLOGICAL"
+)
+
+// IntakeScreenings are the screenings scheduled for every intake, in the
+// order they fall due.
+var IntakeScreenings = []ScreeningType{
+	ScreeningPhysical, ScreeningRadiation, ScreeningPsychological, ScreeningInfectious,
+}
+
+// Valid returns true if the screening type is valid.
+func (t ScreeningType) Valid() bool {
+	switch t {
+	case ScreeningPhysical, ScreeningRadiation, ScreeningInfectious, ScreeningPsychological:
+		return true
+	default:
+		return false
+	}
+}
+
+// DueDate returns the day a screening falls due in a quarantine from
+// admitted to end, its last day: physical and radiation screenings on the
+// first day, the psychological evaluation a week in and the infectious
+// disease panel on the last day, once any incubation period has run.
+func (t ScreeningType) DueDate(admitted, end time.Time) time.Time {
+	day := time.Date(admitted.Year(), admitted.Month(), admitted.Day(), 0, 0, 0, 0, time.UTC)
+	switch t {
+	case ScreeningPsychological:
+		if week := day.AddDate(0, 0, 7); week.Before(end) {
+			return week
+		}
+		return end
+	case ScreeningInfectious:
+		return end
+	default:
+		return day
+	}
+}
+
+// Intake is the admission of an outsider into the vault: the quarantine
+// they serve as a QUARANTINE resident and the screenings a medical officer
+// must pass before clearing them to ACTIVE.
+type Intake struct {
+	ID             string     `json:"id"`
+	ResidentID     string     `json:"resident_id"`
+	AdmittedAt     time.Time  `json:"admitted_at"`
+	QuarantineDays int        `json:"quarantine_days"`
+	QuarantineEnd  time.Time  `json:"quarantine_end"` // Last day of the quarantine
+	ClearedAt      *time.Time `json:"cleared_at,omitempty"`
+	ClearedBy      *string    `json:"cleared_by,omitempty"`
+	Notes          string     `json:"notes,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+
+	// Populated by the service
+	Resident   *Resident          `json:"resident,omitempty"`
+	Screenings []*IntakeScreening `json:"screenings,omitempty"`
+}
+
+// Validate checks if the intake data is valid.
+func (i *Intake) Validate() error {
+	if i.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if i.ResidentID == "" {
+		return fmt.Errorf("resident_id is required")
+	}
+	if i.AdmittedAt.IsZero() {
+		return fmt.Errorf("admitted_at is required")
+	}
+	if i.QuarantineDays < MinIntakeQuarantineDays {
+		return fmt.Errorf("quarantine_days must be at least %d", MinIntakeQuarantineDays)
+	}
+	if i.QuarantineEnd.IsZero() {
+		return fmt.Errorf("quarantine_end is required")
+	}
+	if i.ClearedBy != nil && i.ClearedAt == nil {
+		return fmt.Errorf("cleared_by requires cleared_at")
+	}
+	return nil
+}
+
+// IsCleared returns true if a medical officer has cleared the intake.
+func (i *Intake) IsCleared() bool {
+	return i.ClearedAt != nil
+}
+
+// QuarantineServed returns true if the quarantine has run by now: it is
+// the day after the quarantine's last day or later.
+func (i *Intake) QuarantineServed(now time.Time) bool {
+	end := i.QuarantineEnd
+	return !now.Before(time.Date(end.Year(), end.Month(), end.Day()+1, 0, 0, 0, 0, end.Location()))
+}
+
+// PendingScreenings returns the screenings not yet passed: those never
+// done and those failed and awaiting a retest.
+func (i *Intake) PendingScreenings() []*IntakeScreening {
+	var pending []*IntakeScreening
+	for _, s := range i.Screenings {
+		if !s.IsPassed() {
+			pending = append(pending, s)
+		}
+	}
+	return pending
+}
+
+// IntakeScreening is one medical screening of an intake. A failed
+// screening is retested in place; the latest result stands.
+type IntakeScreening struct {
+	ID          string        `json:"id"`
+	IntakeID    string        `json:"intake_id"`
+	Screening   ScreeningType `json:"screening"`
+	DueDate     time.Time     `json:"due_date"`
+	CompletedAt *time.Time    `json:"completed_at,omitempty"`
+	CompletedBy *string       `json:"completed_by,omitempty"`
+	Passed      *bool         `json:"passed,omitempty"` // Nil until the screening is done
+	Notes       string        `json:"notes,omitempty"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+}
+
+// Validate checks if the screening data is valid.
+func (s *IntakeScreening) Validate() error {
+	if s.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if s.IntakeID == "" {
+		return fmt.Errorf("intake_id is required")
+	}
+	if !s.Screening.Valid() {
+		return fmt.Errorf("invalid screening: %s", s.Screening)
+	}
+	if s.DueDate.IsZero() {
+		return fmt.Errorf("due_date is required")
+	}
+	if (s.CompletedAt == nil) != (s.Passed == nil) {
+		return fmt.Errorf("completed_at and passed must be set together")
+	}
+	return nil
+}
+
+// IsPassed returns true if the screening's latest result is a pass.
+func (s *IntakeScreening) IsPassed() bool {
+	return s.Passed != nil && *s.Passed
+}
+
+// Result returns PENDING, PASSED or FAILED.
+func (s *IntakeScreening) Result() string {
+	switch {
+	case s.Passed == nil:
+		return "PENDING"
+	case *s.Passed:
+		return "PASSED"
+	default:
+		return "FAILED"
+	}
+}
+
+// IsOverdue returns true if the screening is not yet passed and its due
+// date is before the date of now.
+func (s *IntakeScreening) IsOverdue(now time.Time) bool {
+	if s.IsPassed() {
+		return false
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return s.DueDate.Before(today)
+}
@@ -0,0 +1,141 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScreeningType_DueDate(t *testing.T) {
+	admitted := time.Date(2077, 11, 1, 14, 30, 0, 0, time.UTC)
+	end := time.Date(2077, 11, 14, 0, 0, 0, 0, time.UTC)
+	short := time.Date(2077, 11, 5, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		screening ScreeningType
+		end       time.Time
+		want      time.Time
+	}{
+		{"Physical on the first day", ScreeningPhysical, end, time.Date(2077, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{"Radiation on the first day", ScreeningRadiation, end, time.Date(2077, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{"Psychological a week in", ScreeningPsychological, end, time.Date(2077, 11, 8, 0, 0, 0, 0, time.UTC)},
+		{"Psychological no later than the end", ScreeningPsychological, short, short},
+		{"Infectious disease on the last day", ScreeningInfectious, end, end},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.screening.DueDate(admitted, tt.end); !got.Equal(tt.want) {
+				t.Errorf("ScreeningType.DueDate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIntake_Validate(t *testing.T) {
+	valid := func() *Intake {
+		return &Intake{
+			ID:             "intake-1",
+			ResidentID:     "resident-1",
+			AdmittedAt:     time.Date(2077, 11, 1, 14, 0, 0, 0, time.UTC),
+			QuarantineDays: MinIntakeQuarantineDays,
+			QuarantineEnd:  time.Date(2077, 11, 14, 0, 0, 0, 0, time.UTC),
+		}
+	}
+	officer := "officer-1"
+	cleared := time.Date(2077, 11, 12, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		modify  func(*Intake)
+		wantErr bool
+	}{
+		{"Valid", func(i *Intake) {}, false},
+		{"Valid cleared", func(i *Intake) { i.ClearedAt, i.ClearedBy = &cleared, &officer }, false},
+		{"Missing ID", func(i *Intake) { i.ID = "" }, true},
+		{"Missing resident", func(i *Intake) { i.ResidentID = "" }, true},
+		{"Missing admission", func(i *Intake) { i.AdmittedAt = time.Time{} }, true},
+		{"Quarantine too short", func(i *Intake) { i.QuarantineDays = MinIntakeQuarantineDays - 1 }, true},
+		{"Missing quarantine end", func(i *Intake) { i.QuarantineEnd = time.Time{} }, true},
+		{"Officer without clearance", func(i *Intake) { i.ClearedBy = &officer }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := valid()
+			tt.modify(i)
+			if err := i.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Intake.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIntake_QuarantineServed(t *testing.T) {
+	intake := &Intake{QuarantineEnd: time.Date(2077, 11, 14, 0, 0, 0, 0, time.UTC)}
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want bool
+	}{
+		{"Before the last day", time.Date(2077, 11, 13, 12, 0, 0, 0, time.UTC), false},
+		{"On the last day", time.Date(2077, 11, 14, 23, 59, 0, 0, time.UTC), false},
+		{"The day after", time.Date(2077, 11, 15, 0, 0, 0, 0, time.UTC), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := intake.QuarantineServed(tt.now); got != tt.want {
+				t.Errorf("Intake.QuarantineServed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIntake_PendingScreenings(t *testing.T) {
+	passed, failed := true, false
+	intake := &Intake{Screenings: []*IntakeScreening{
+		{Screening: ScreeningPhysical, Passed: &passed},
+		{Screening: ScreeningRadiation, Passed: &failed},
+		{Screening: ScreeningPsychological},
+	}}
+
+	pending := intake.PendingScreenings()
+	if len(pending) != 2 {
+		t.Fatalf("PendingScreenings() returned %d screenings, want 2", len(pending))
+	}
+	if pending[0].Screening != ScreeningRadiation || pending[1].Screening != ScreeningPsychological {
+		t.Errorf("PendingScreenings() = %s, %s, want RADIATION, PSYCHOLOGICAL", pending[0].Screening, pending[1].Screening)
+	}
+}
+
+func TestIntakeScreening_Result(t *testing.T) {
+	passed, failed := true, false
+	due := time.Date(2077, 11, 8, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		passed      *bool
+		now         time.Time
+		wantResult  string
+		wantOverdue bool
+	}{
+		{"Pending on the due date", nil, time.Date(2077, 11, 8, 23, 0, 0, 0, time.UTC), "PENDING", false},
+		{"Pending past the due date", nil, time.Date(2077, 11, 9, 0, 0, 0, 0, time.UTC), "PENDING", true},
+		{"Failed past the due date", &failed, time.Date(2077, 11, 9, 0, 0, 0, 0, time.UTC), "FAILED", true},
+		{"Passed past the due date", &passed, time.Date(2077, 11, 9, 0, 0, 0, 0, time.UTC), "PASSED", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &IntakeScreening{Screening: ScreeningPsychological, DueDate: due, Passed: tt.passed}
+			if got := s.Result(); got != tt.wantResult {
+				t.Errorf("IntakeScreening.Result() = %s, want %s", got, tt.wantResult)
+			}
+			if got := s.IsOverdue(tt.now); got != tt.wantOverdue {
+				t.Errorf("IntakeScreening.IsOverdue() = %v, want %v", got, tt.wantOverdue)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// InventoryAuditStatus represents the state of an inventory audit session.
+type InventoryAuditStatus string
+
+const (
+	InventoryAuditStatusOpen      InventoryAuditStatus = "OPEN"
+	InventoryAuditStatusClosed    InventoryAuditStatus = "CLOSED"
+	InventoryAuditStatusCancelled InventoryAuditStatus = "CANCELLED"
+)
+
+// Valid returns true if the audit status is valid.
+func (s InventoryAuditStatus) Valid() bool {
+	switch s {
+	case InventoryAuditStatusOpen, InventoryAuditStatusClosed, InventoryAuditStatusCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// InventoryAudit is a physical count of every lot in one storage location.
+// The book quantity of each lot is frozen when the audit opens; counts are
+// recorded against it, and closing the audit corrects every counted lot at
+// once.
+type InventoryAudit struct {
+	ID              string
+	StorageLocation string
+	Status          InventoryAuditStatus
+	OpenedBy        *string
+	OpenedAt        time.Time
+	ClosedAt        *time.Time
+	ClosedBy        *string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+
+	// Lots in the location when the audit opened
+	Counts []InventoryAuditCount
+}
+
+// InventoryAuditCount is the expected and counted quantity of one lot.
+type InventoryAuditCount struct {
+	StockID          string
+	ExpectedQuantity float64  // Book quantity when the audit opened
+	CountedQuantity  *float64 // NULL until counted
+	CountedAt        *time.Time
+
+	// Joined fields
+	ItemCode      string
+	ItemName      string
+	UnitOfMeasure string
+	LotNumber     *string
+}
+
+// Validate checks if the audit data is valid.
+func (a *InventoryAudit) Validate() error {
+	if a.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if a.StorageLocation == "" {
+		return fmt.Errorf("storage_location is required")
+	}
+	if !a.Status.Valid() {
+		return fmt.Errorf("invalid status: %s", a.Status)
+	}
+	if a.OpenedAt.IsZero() {
+		return fmt.Errorf("opened_at is required")
+	}
+	for _, c := range a.Counts {
+		if c.StockID == "" || c.ExpectedQuantity < 0 {
+			return fmt.Errorf("counts need a stock_id and a non-negative expected quantity")
+		}
+		if c.CountedQuantity != nil && *c.CountedQuantity < 0 {
+			return fmt.Errorf("counted quantity cannot be negative")
+		}
+	}
+	return nil
+}
+
+// IsOpen returns true if counts can still be recorded.
+func (a *InventoryAudit) IsOpen() bool {
+	return a.Status == InventoryAuditStatusOpen
+}
+
+// Count returns the count of a lot given its stock ID or lot number, or nil
+// if the lot is not in the audit.
+func (a *InventoryAudit) Count(ref string) *InventoryAuditCount {
+	for i := range a.Counts {
+		c := &a.Counts[i]
+		if c.StockID == ref || (c.LotNumber != nil && *c.LotNumber == ref) {
+			return c
+		}
+	}
+	return nil
+}
+
+// IsCounted returns true if the lot has been counted.
+func (c *InventoryAuditCount) IsCounted() bool {
+	return c.CountedQuantity != nil
+}
+
+// Variance returns counted less expected quantity; negative is shrinkage.
+// An uncounted lot has no variance.
+func (c *InventoryAuditCount) Variance() float64 {
+	if c.CountedQuantity == nil {
+		return 0
+	}
+	return *c.CountedQuantity - c.ExpectedQuantity
+}
+
+// ShrinkagePercent returns the share of the expected quantity missing, as a
+// percentage. A surplus is negative shrinkage. A lot expected empty has no
+// shrinkage.
+func (c *InventoryAuditCount) ShrinkagePercent() float64 {
+	if c.ExpectedQuantity <= 0 {
+		return 0
+	}
+	return -c.Variance() / c.ExpectedQuantity * 100
+}
+
+// AuditVarianceSummary totals an audit's counts.
+type AuditVarianceSummary struct {
+	Lots      int
+	Counted   int
+	Variances int // Counted lots whose count differs from the book
+}
+
+// Summary totals the audit's counts.
+func (a *InventoryAudit) Summary() AuditVarianceSummary {
+	summary := AuditVarianceSummary{Lots: len(a.Counts)}
+	for i := range a.Counts {
+		c := &a.Counts[i]
+		if !c.IsCounted() {
+			continue
+		}
+		summary.Counted++
+		if math.Abs(c.Variance()) > QuantityEpsilon {
+			summary.Variances++
+		}
+	}
+	return summary
+}
+
+// ReportText renders the variance report as plain text for printing.
+func (a *InventoryAudit) ReportText() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "INVENTORY AUDIT VARIANCE REPORT\n")
+	fmt.Fprintf(&b, "Audit:     %s\n", a.ID)
+	fmt.Fprintf(&b, "Location:  %s\n", a.StorageLocation)
+	fmt.Fprintf(&b, "Status:    %s\n", a.Status)
+	fmt.Fprintf(&b, "Opened:    %s\n", a.OpenedAt.Format("2006-01-02 15:04"))
+	if a.ClosedAt != nil {
+		fmt.Fprintf(&b, "Closed:    %s\n", a.ClosedAt.Format("2006-01-02 15:04"))
+	}
+	b.WriteString("\n")
+
+	fmt.Fprintf(&b, "%-18s %-14s %12s %12s %12s %8s\n", "ITEM", "LOT", "EXPECTED", "COUNTED", "VARIANCE", "SHRINK")
+	for i := range a.Counts {
+		c := &a.Counts[i]
+		lot := "-"
+		if c.LotNumber != nil {
+			lot = *c.LotNumber
+		}
+		if !c.IsCounted() {
+			fmt.Fprintf(&b, "%-18s %-14s %12.2f %12s %12s %8s\n",
+				c.ItemCode, lot, c.ExpectedQuantity, "NOT COUNTED", "", "")
+			continue
+		}
+		fmt.Fprintf(&b, "%-18s %-14s %12.2f %12.2f %+12.2f %7.1f%%\n",
+			c.ItemCode, lot, c.ExpectedQuantity, *c.CountedQuantity, c.Variance(), c.ShrinkagePercent())
+	}
+
+	summary := a.Summary()
+	fmt.Fprintf(&b, "\n%d lot(s), %d counted, %d with variance\n", summary.Lots, summary.Counted, summary.Variances)
+	return b.String()
+}
@@ -0,0 +1,130 @@
+package models
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInventoryAuditStatus_Valid(t *testing.T) {
+	tests := []struct {
+		status InventoryAuditStatus
+		want   bool
+	}{
+		{InventoryAuditStatusOpen, true},
+		{InventoryAuditStatusClosed, true},
+		{InventoryAuditStatusCancelled, true},
+		{InventoryAuditStatus(""), false},
+		{InventoryAuditStatus("PENDING"), false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.status.Valid(); got != tt.want {
+			t.Errorf("InventoryAuditStatus(%q).Valid() = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestInventoryAudit_Validate(t *testing.T) {
+	valid := func() *InventoryAudit {
+		return &InventoryAudit{
+			ID:              "audit-1",
+			StorageLocation: "STORAGE-A-12",
+			Status:          InventoryAuditStatusOpen,
+			OpenedAt:        time.Date(2077, 10, 23, 9, 0, 0, 0, time.UTC),
+			Counts: []InventoryAuditCount{
+				{StockID: "stock-1", ExpectedQuantity: 40},
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*InventoryAudit)
+		wantErr bool
+	}{
+		{"Valid audit", func(a *InventoryAudit) {}, false},
+		{"Missing location", func(a *InventoryAudit) { a.StorageLocation = "" }, true},
+		{"Invalid status", func(a *InventoryAudit) { a.Status = "PENDING" }, true},
+		{"Missing opened_at", func(a *InventoryAudit) { a.OpenedAt = time.Time{} }, true},
+		{"Count without stock", func(a *InventoryAudit) { a.Counts[0].StockID = "" }, true},
+		{"Negative count", func(a *InventoryAudit) { a.Counts[0].CountedQuantity = floatPtr(-1.0) }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := valid()
+			tt.modify(a)
+			if err := a.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestInventoryAuditCount_Variance(t *testing.T) {
+	tests := []struct {
+		name      string
+		count     InventoryAuditCount
+		variance  float64
+		shrinkPct float64
+	}{
+		{"Not counted", InventoryAuditCount{ExpectedQuantity: 40}, 0, 0},
+		{"Shrinkage", InventoryAuditCount{ExpectedQuantity: 40, CountedQuantity: floatPtr(36.0)}, -4, 10},
+		{"Surplus", InventoryAuditCount{ExpectedQuantity: 40, CountedQuantity: floatPtr(42.0)}, 2, -5},
+		{"Expected empty", InventoryAuditCount{ExpectedQuantity: 0, CountedQuantity: floatPtr(3.0)}, 3, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.count.Variance(); math.Abs(got-tt.variance) > QuantityEpsilon {
+				t.Errorf("Variance() = %v, want %v", got, tt.variance)
+			}
+			if got := tt.count.ShrinkagePercent(); math.Abs(got-tt.shrinkPct) > QuantityEpsilon {
+				t.Errorf("ShrinkagePercent() = %v, want %v", got, tt.shrinkPct)
+			}
+		})
+	}
+}
+
+func TestInventoryAudit_Report(t *testing.T) {
+	lot := "LOT-2077-114"
+	audit := &InventoryAudit{
+		ID:              "audit-1",
+		StorageLocation: "STORAGE-A-12",
+		Status:          InventoryAuditStatusOpen,
+		OpenedAt:        time.Date(2077, 10, 23, 9, 0, 0, 0, time.UTC),
+		Counts: []InventoryAuditCount{
+			{StockID: "stock-1", ItemCode: "FOOD-PROTEIN-001", LotNumber: &lot, ExpectedQuantity: 40, CountedQuantity: floatPtr(36.0)},
+			{StockID: "stock-2", ItemCode: "FOOD-CARBS-001", ExpectedQuantity: 25, CountedQuantity: floatPtr(25.0)},
+			{StockID: "stock-3", ItemCode: "FOOD-SUGAR-001", ExpectedQuantity: 10},
+		},
+	}
+
+	if got := audit.Count(lot); got == nil || got.StockID != "stock-1" {
+		t.Errorf("Count(lot number) = %+v, want stock-1", got)
+	}
+	if got := audit.Count("stock-3"); got == nil || got.ItemCode != "FOOD-SUGAR-001" {
+		t.Errorf("Count(stock ID) = %+v, want stock-3", got)
+	}
+	if got := audit.Count("stock-9"); got != nil {
+		t.Errorf("Count(unknown) = %+v, want nil", got)
+	}
+
+	want := AuditVarianceSummary{Lots: 3, Counted: 2, Variances: 1}
+	if got := audit.Summary(); got != want {
+		t.Errorf("Summary() = %+v, want %+v", got, want)
+	}
+
+	report := audit.ReportText()
+	for _, s := range []string{"STORAGE-A-12", "LOT-2077-114", "-4.00", "10.0%", "NOT COUNTED", "3 lot(s), 2 counted, 1 with variance"} {
+		if !strings.Contains(report, s) {
+			t.Errorf("report missing %q:\n%s", s, report)
+		}
+	}
+}
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
@@ -0,0 +1,128 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// LockdownState is the vault's alert state.
+type LockdownState string
+
+const (
+	LockdownNormal    LockdownState = "NORMAL"
+	LockdownDrill     LockdownState = "DRILL"     // Rehearsal: terminals restricted, jobs keep running
+	LockdownLockdown  LockdownState = "LOCKDOWN"  // Terminals restricted, non-essential jobs paused
+	LockdownEmergency LockdownState = "EMERGENCY" // As LOCKDOWN, with a higher clearance to use terminals
+)
+
+// Valid returns true if the state is valid.
+func (s LockdownState) Valid() bool {
+	switch s {
+	case LockdownNormal, LockdownDrill, LockdownLockdown, LockdownEmergency:
+		return true
+	default:
+		return false
+	}
+}
+
+// ParseLockdownState reads a state name in any case.
+func ParseLockdownState(name string) (LockdownState, error) {
+	state := LockdownState(strings.ToUpper(strings.TrimSpace(name)))
+	if !state.Valid() {
+		return "", fmt.Errorf("invalid vault state %q: use normal, drill, lockdown or emergency", name)
+	}
+	return state, nil
+}
+
+// lockdownTransitions lists the states each state can move to. A real
+// lockdown or emergency cannot be downgraded to a drill.
+var lockdownTransitions = map[LockdownState][]LockdownState{
+	LockdownNormal:    {LockdownDrill, LockdownLockdown, LockdownEmergency},
+	LockdownDrill:     {LockdownNormal, LockdownLockdown, LockdownEmergency},
+	LockdownLockdown:  {LockdownNormal, LockdownEmergency},
+	LockdownEmergency: {LockdownNormal, LockdownLockdown},
+}
+
+// CanTransitionTo returns true if the vault can move from s to the state.
+func (s LockdownState) CanTransitionTo(to LockdownState) bool {
+	for _, next := range lockdownTransitions[s] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// authorityClearance is the clearance needed to enter or leave the state.
+func (s LockdownState) authorityClearance() int {
+	switch s {
+	case LockdownDrill:
+		return 6
+	case LockdownLockdown:
+		return 7
+	case LockdownEmergency:
+		return 9
+	default:
+		return 0
+	}
+}
+
+// TransitionClearance returns the clearance an operator needs to authorize a
+// move between two states: the higher of the two states' authority.
+func TransitionClearance(from, to LockdownState) int {
+	return max(from.authorityClearance(), to.authorityClearance())
+}
+
+// ModuleClearance returns the clearance an operator needs to use restricted
+// terminal modules in the state, 0 if nothing is restricted.
+func (s LockdownState) ModuleClearance() int {
+	switch s {
+	case LockdownDrill, LockdownLockdown:
+		return 5
+	case LockdownEmergency:
+		return 8
+	default:
+		return 0
+	}
+}
+
+// PausesNonEssential returns true if the state pauses non-essential
+// scheduled jobs.
+func (s LockdownState) PausesNonEssential() bool {
+	return s == LockdownLockdown || s == LockdownEmergency
+}
+
+// LockdownChange is one change of the vault's alert state.
+type LockdownChange struct {
+	ID            string        `json:"id"`
+	State         LockdownState `json:"state"`
+	PreviousState LockdownState `json:"previous_state"`
+	AuthorizedBy  *string       `json:"authorized_by,omitempty"` // Nil once the operator's record is deleted
+	Reason        string        `json:"reason,omitempty"`
+	ChangedAt     time.Time     `json:"changed_at"`
+	CreatedAt     time.Time     `json:"created_at"`
+
+	// Joined fields
+	Authorizer *Resident `json:"authorizer,omitempty"`
+}
+
+// Validate checks if the change data is valid.
+func (c *LockdownChange) Validate() error {
+	if c.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if !c.State.Valid() {
+		return fmt.Errorf("invalid state: %s", c.State)
+	}
+	if !c.PreviousState.Valid() {
+		return fmt.Errorf("invalid previous_state: %s", c.PreviousState)
+	}
+	if !c.PreviousState.CanTransitionTo(c.State) {
+		return fmt.Errorf("vault cannot move from %s to %s", c.PreviousState, c.State)
+	}
+	if c.ChangedAt.IsZero() {
+		return fmt.Errorf("changed_at is required")
+	}
+	return nil
+}
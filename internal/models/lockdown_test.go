@@ -0,0 +1,94 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseLockdownState(t *testing.T) {
+	for input, want := range map[string]LockdownState{"lockdown": LockdownLockdown, " Drill ": LockdownDrill, "EMERGENCY": LockdownEmergency} {
+		got, err := ParseLockdownState(input)
+		if err != nil || got != want {
+			t.Errorf("ParseLockdownState(%q) = %q, %v, want %q", input, got, err, want)
+		}
+	}
+	if _, err := ParseLockdownState("red alert"); err == nil {
+		t.Error("ParseLockdownState(\"red alert\") expected error")
+	}
+}
+
+func TestLockdownState_CanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from, to LockdownState
+		want     bool
+	}{
+		{LockdownNormal, LockdownDrill, true},
+		{LockdownNormal, LockdownNormal, false},
+		{LockdownDrill, LockdownEmergency, true},
+		{LockdownLockdown, LockdownDrill, false},
+		{LockdownEmergency, LockdownLockdown, true},
+		{LockdownEmergency, LockdownDrill, false},
+	}
+	for _, tt := range tests {
+		if got := tt.from.CanTransitionTo(tt.to); got != tt.want {
+			t.Errorf("%s.CanTransitionTo(%s) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestTransitionClearance(t *testing.T) {
+	if got := TransitionClearance(LockdownNormal, LockdownDrill); got != 6 {
+		t.Errorf("NORMAL -> DRILL clearance = %d, want 6", got)
+	}
+	// Standing down from an emergency takes the authority that declared it
+	if got := TransitionClearance(LockdownEmergency, LockdownNormal); got != 9 {
+		t.Errorf("EMERGENCY -> NORMAL clearance = %d, want 9", got)
+	}
+}
+
+func TestLockdownState_Restrictions(t *testing.T) {
+	if LockdownNormal.ModuleClearance() != 0 || LockdownNormal.PausesNonEssential() {
+		t.Error("NORMAL should restrict nothing")
+	}
+	if LockdownDrill.ModuleClearance() == 0 || LockdownDrill.PausesNonEssential() {
+		t.Error("DRILL should restrict terminals without pausing jobs")
+	}
+	if !LockdownLockdown.PausesNonEssential() || !LockdownEmergency.PausesNonEssential() {
+		t.Error("LOCKDOWN and EMERGENCY should pause non-essential jobs")
+	}
+	if LockdownEmergency.ModuleClearance() <= LockdownLockdown.ModuleClearance() {
+		t.Error("EMERGENCY should need a higher module clearance than LOCKDOWN")
+	}
+}
+
+func TestLockdownChange_Validate(t *testing.T) {
+	valid := func() *LockdownChange {
+		return &LockdownChange{
+			ID:            "ld-1",
+			State:         LockdownLockdown,
+			PreviousState: LockdownNormal,
+			ChangedAt:     time.Date(2078, 3, 1, 14, 0, 0, 0, time.UTC),
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*LockdownChange)
+		wantErr bool
+	}{
+		{"Valid", func(c *LockdownChange) {}, false},
+		{"Invalid state", func(c *LockdownChange) { c.State = "RED" }, true},
+		{"Same state", func(c *LockdownChange) { c.PreviousState = LockdownLockdown }, true},
+		{"Lockdown to drill", func(c *LockdownChange) { c.PreviousState, c.State = LockdownLockdown, LockdownDrill }, true},
+		{"Missing time", func(c *LockdownChange) { c.ChangedAt = time.Time{} }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := valid()
+			tt.modify(c)
+			if err := c.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
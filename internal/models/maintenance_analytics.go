@@ -0,0 +1,132 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// ParetoCutoff is the share of failures the vital few systems of a Pareto
+// analysis account for between them.
+const ParetoCutoff = 0.8
+
+// CategoryMaintenance summarizes the work orders against the systems of a
+// facility category.
+type CategoryMaintenance struct {
+	Category         FacilityCategory
+	Orders           int           // Work orders raised
+	Repairs          int           // Corrective and emergency orders raised
+	TimedRepairs     int           // Repairs completed with a raise time or scheduled date
+	RepairTime       time.Duration // Total time to complete the timed repairs
+	LaborHours       float64       // Hours logged on closed orders with efficiency before and after
+	EfficiencyGained float64       // Efficiency points those orders recovered, net of any lost
+}
+
+// MTTR returns the mean time to repair: the mean time from raising a
+// repair to completing it, 0 without timed repairs.
+func (c *CategoryMaintenance) MTTR() time.Duration {
+	if c.TimedRepairs == 0 {
+		return 0
+	}
+	return c.RepairTime / time.Duration(c.TimedRepairs)
+}
+
+// RecoveredPerHour returns the efficiency points recovered per labor hour,
+// 0 without labor logged.
+func (c *CategoryMaintenance) RecoveredPerHour() float64 {
+	if c.LaborHours <= 0 {
+		return 0
+	}
+	return c.EfficiencyGained / c.LaborHours
+}
+
+// SystemFailures counts the repairs raised against a system.
+type SystemFailures struct {
+	System     *FacilitySystem
+	Failures   int
+	Cumulative float64 // Share of all failures of this system and those ahead of it, 0-1
+}
+
+// MaintenanceAnalytics summarizes the work orders raised over a period.
+type MaintenanceAnalytics struct {
+	From, To   time.Time              // Orders raised in [From, To)
+	Categories []*CategoryMaintenance // In FacilityCategories order, those with orders only
+	Systems    []*SystemFailures      // Systems with failures, most first
+	Failures   int
+}
+
+// VitalFew returns the systems that, most failure-prone first, account
+// for the first ParetoCutoff of the failures, including the one crossing it.
+func (a *MaintenanceAnalytics) VitalFew() []*SystemFailures {
+	running := 0
+	for i, s := range a.Systems {
+		running += s.Failures
+		if float64(running) >= ParetoCutoff*float64(a.Failures) {
+			return a.Systems[:i+1]
+		}
+	}
+	return a.Systems
+}
+
+// AnalyzeMaintenance summarizes work orders by the category of their
+// systems and ranks the systems by the repairs raised against them, ties
+// by system code. Orders against systems not given are left out.
+func AnalyzeMaintenance(from, to time.Time, records []*MaintenanceRecord, systems []*FacilitySystem) *MaintenanceAnalytics {
+	a := &MaintenanceAnalytics{From: from, To: to}
+	byID := make(map[string]*FacilitySystem, len(systems))
+	for _, sys := range systems {
+		byID[sys.ID] = sys
+	}
+
+	categories := make(map[FacilityCategory]*CategoryMaintenance)
+	failures := make(map[string]*SystemFailures)
+	for _, rec := range records {
+		sys, ok := byID[rec.SystemID]
+		if !ok {
+			continue
+		}
+		c := categories[sys.Category]
+		if c == nil {
+			c = &CategoryMaintenance{Category: sys.Category}
+			categories[sys.Category] = c
+		}
+		c.Orders++
+
+		if rec.IsRepair() {
+			c.Repairs++
+			a.Failures++
+			f := failures[sys.ID]
+			if f == nil {
+				f = &SystemFailures{System: sys}
+				failures[sys.ID] = f
+				a.Systems = append(a.Systems, f)
+			}
+			f.Failures++
+			if d, ok := rec.RepairTime(); ok {
+				c.TimedRepairs++
+				c.RepairTime += d
+			}
+		}
+		if rec.ActualHours != nil && rec.EfficiencyBefore != nil && rec.EfficiencyAfter != nil {
+			c.LaborHours += *rec.ActualHours
+			c.EfficiencyGained += *rec.EfficiencyAfter - *rec.EfficiencyBefore
+		}
+	}
+
+	for _, category := range FacilityCategories {
+		if c := categories[category]; c != nil {
+			a.Categories = append(a.Categories, c)
+		}
+	}
+	sort.SliceStable(a.Systems, func(i, j int) bool {
+		if a.Systems[i].Failures != a.Systems[j].Failures {
+			return a.Systems[i].Failures > a.Systems[j].Failures
+		}
+		return a.Systems[i].System.SystemCode < a.Systems[j].System.SystemCode
+	})
+	running := 0
+	for _, f := range a.Systems {
+		running += f.Failures
+		f.Cumulative = float64(running) / float64(a.Failures)
+	}
+	return a
+}
@@ -0,0 +1,104 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMaintenanceRecord_RepairTime(t *testing.T) {
+	raised := time.Date(2077, 10, 23, 14, 0, 0, 0, time.UTC)
+	scheduled := time.Date(2077, 10, 23, 0, 0, 0, 0, time.UTC)
+	completed := time.Date(2077, 10, 24, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		rec    MaintenanceRecord
+		want   time.Duration
+		wantOK bool
+	}{
+		{"From raise time", MaintenanceRecord{RaisedAt: &raised, ScheduledDate: &scheduled, CompletedAt: &completed, Outcome: MaintenanceOutcomeCompleted}, 19 * time.Hour, true},
+		{"From scheduled date", MaintenanceRecord{ScheduledDate: &scheduled, CompletedAt: &completed, Outcome: MaintenanceOutcomeCompleted}, 33 * time.Hour, true},
+		{"Not completed", MaintenanceRecord{RaisedAt: &raised, CompletedAt: &completed, Outcome: MaintenanceOutcomeDeferred}, 0, false},
+		{"Open", MaintenanceRecord{RaisedAt: &raised}, 0, false},
+		{"No start", MaintenanceRecord{CompletedAt: &completed, Outcome: MaintenanceOutcomeCompleted}, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.rec.RepairTime()
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("RepairTime() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestAnalyzeMaintenance(t *testing.T) {
+	from := time.Date(2077, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	at := func(day, hour int) *time.Time {
+		t := time.Date(2077, 10, day, hour, 0, 0, 0, time.UTC)
+		return &t
+	}
+	f := func(v float64) *float64 { return &v }
+
+	systems := []*FacilitySystem{
+		{ID: "reactor", SystemCode: "PWR-REACTOR-01", Category: FacilityCategoryPower},
+		{ID: "purifier", SystemCode: "WTR-PURIF-01", Category: FacilityCategoryWater},
+		{ID: "recycler", SystemCode: "WTR-RECYC-01", Category: FacilityCategoryWater},
+	}
+	repair := func(system string, raised, completed *time.Time) *MaintenanceRecord {
+		rec := &MaintenanceRecord{SystemID: system, MaintenanceType: MaintenanceTypeCorrective, RaisedAt: raised, CompletedAt: completed}
+		if completed != nil {
+			rec.Outcome = MaintenanceOutcomeCompleted
+		}
+		return rec
+	}
+	records := []*MaintenanceRecord{
+		repair("purifier", at(2, 8), at(2, 12)),
+		repair("purifier", at(9, 8), at(9, 20)),
+		repair("purifier", at(20, 8), nil),
+		repair("reactor", at(5, 0), at(6, 0)),
+		repair("recycler", at(7, 0), nil),
+		{SystemID: "reactor", MaintenanceType: MaintenanceTypePreventive, RaisedAt: at(1, 0), CompletedAt: at(1, 6),
+			Outcome: MaintenanceOutcomeCompleted, ActualHours: f(4), EfficiencyBefore: f(80), EfficiencyAfter: f(100)},
+		{SystemID: "unknown", MaintenanceType: MaintenanceTypeCorrective},
+	}
+	records[3].ActualHours, records[3].EfficiencyBefore, records[3].EfficiencyAfter = f(6), f(40), f(70)
+
+	a := AnalyzeMaintenance(from, to, records, systems)
+
+	if a.Failures != 5 {
+		t.Errorf("Failures = %d, want 5", a.Failures)
+	}
+	if len(a.Categories) != 2 || a.Categories[0].Category != FacilityCategoryPower || a.Categories[1].Category != FacilityCategoryWater {
+		t.Fatalf("Categories = %+v, want POWER then WATER", a.Categories)
+	}
+	power, water := a.Categories[0], a.Categories[1]
+	if power.Orders != 2 || power.Repairs != 1 || power.MTTR() != 24*time.Hour {
+		t.Errorf("POWER = %+v, MTTR %v; want 2 orders, 1 repair, MTTR 24h", power, power.MTTR())
+	}
+	if got := power.RecoveredPerHour(); got != 5 {
+		t.Errorf("POWER RecoveredPerHour() = %v, want 5", got)
+	}
+	if water.Repairs != 4 || water.TimedRepairs != 2 || water.MTTR() != 8*time.Hour {
+		t.Errorf("WATER = %+v, MTTR %v; want 4 repairs, 2 timed, MTTR 8h", water, water.MTTR())
+	}
+	if water.RecoveredPerHour() != 0 {
+		t.Errorf("WATER RecoveredPerHour() = %v, want 0 without labor logged", water.RecoveredPerHour())
+	}
+
+	var codes []string
+	for _, s := range a.Systems {
+		codes = append(codes, s.System.SystemCode)
+	}
+	if len(codes) != 3 || codes[0] != "WTR-PURIF-01" || codes[1] != "PWR-REACTOR-01" || codes[2] != "WTR-RECYC-01" {
+		t.Errorf("Systems = %v, want the purifier then the reactor and recycler by code", codes)
+	}
+	if a.Systems[0].Cumulative != 0.6 || a.Systems[2].Cumulative != 1 {
+		t.Errorf("Cumulative = %v, %v, want 0.6 and 1", a.Systems[0].Cumulative, a.Systems[2].Cumulative)
+	}
+	if vital := a.VitalFew(); len(vital) != 2 {
+		t.Errorf("VitalFew() = %d systems, want 2", len(vital))
+	}
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// ConditionSeverity is the severity of a medical condition.
+type ConditionSeverity string
+
+const (
+	ConditionSeverityMild     ConditionSeverity = "MILD"
+	ConditionSeverityModerate ConditionSeverity = "MODERATE"
+	ConditionSeveritySevere   ConditionSeverity = "SEVERE"
+	ConditionSeverityCritical ConditionSeverity = "CRITICAL"
+)
+
+// Valid returns true if the severity is valid.
+func (s ConditionSeverity) Valid() bool {
+	switch s {
+	case ConditionSeverityMild, ConditionSeverityModerate, ConditionSeveritySevere, ConditionSeverityCritical:
+		return true
+	default:
+		return false
+	}
+}
+
+// MedicalCondition is a diagnosed condition of a resident. It is open until
+// its resolution date is set.
+type MedicalCondition struct {
+	ID             string            `json:"id"`
+	ResidentID     string            `json:"resident_id"`
+	ConditionCode  string            `json:"condition_code"`
+	ConditionName  string            `json:"condition_name"`
+	OnsetDate      time.Time         `json:"onset_date"`
+	ResolutionDate *time.Time        `json:"resolution_date,omitempty"`
+	Severity       ConditionSeverity `json:"severity"`
+	IsChronic      bool              `json:"is_chronic"`
+	IsGenetic      bool              `json:"is_genetic"`
+	IsContagious   bool              `json:"is_contagious"`
+	TreatmentPlan  string            `json:"treatment_plan,omitempty"`
+	Notes          string            `json:"notes,omitempty"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+}
+
+// Validate checks if the condition data is valid.
+func (c *MedicalCondition) Validate() error {
+	if c.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if c.ResidentID == "" {
+		return fmt.Errorf("resident_id is required")
+	}
+	if c.ConditionCode == "" || c.ConditionName == "" {
+		return fmt.Errorf("condition code and name are required")
+	}
+	if c.OnsetDate.IsZero() {
+		return fmt.Errorf("onset_date is required")
+	}
+	if !c.Severity.Valid() {
+		return fmt.Errorf("invalid severity: %s", c.Severity)
+	}
+	if c.ResolutionDate != nil && c.ResolutionDate.Before(c.OnsetDate) {
+		return fmt.Errorf("resolution_date cannot be before onset_date")
+	}
+	return nil
+}
+
+// IsOpen returns true if the condition is not resolved.
+func (c *MedicalCondition) IsOpen() bool {
+	return c.ResolutionDate == nil
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMedicalCondition_Validate(t *testing.T) {
+	onset := time.Date(2078, 3, 1, 0, 0, 0, 0, time.UTC)
+	before := onset.AddDate(0, 0, -1)
+	valid := func() *MedicalCondition {
+		return &MedicalCondition{
+			ID:            "cond-1",
+			ResidentID:    "res-1",
+			ConditionCode: RadiationConditionCode,
+			ConditionName: "Radiation exposure (HIGH)",
+			OnsetDate:     onset,
+			Severity:      ConditionSeverityModerate,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*MedicalCondition)
+		wantErr bool
+	}{
+		{"Valid", func(c *MedicalCondition) {}, false},
+		{"Missing code", func(c *MedicalCondition) { c.ConditionCode = "" }, true},
+		{"Invalid severity", func(c *MedicalCondition) { c.Severity = "MINOR" }, true},
+		{"Resolved before onset", func(c *MedicalCondition) { c.ResolutionDate = &before }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := valid()
+			tt.modify(c)
+			if err := c.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
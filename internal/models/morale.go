@@ -0,0 +1,196 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+// MoraleIncidentDays is how far back the security incidents in a resident's
+// sector weigh on their morale.
+const MoraleIncidentDays = 30
+
+// MoraleActivityDays is how far back the classes and recreation a resident
+// attended lift their morale.
+const MoraleActivityDays = 7
+
+// MoraleBaseline is the morale score of a resident on a standard ration, in
+// quarters within capacity, with one job, no incidents nearby and no
+// classes or recreation attended.
+const MoraleBaseline = 70
+
+// MoraleLevel grades a morale score.
+type MoraleLevel string
+
+const (
+	MoraleHigh     MoraleLevel = "HIGH"     // 75 and over
+	MoraleSteady   MoraleLevel = "STEADY"   // 50-74
+	MoraleLow      MoraleLevel = "LOW"      // 30-49
+	MoraleCritical MoraleLevel = "CRITICAL" // Under 30
+)
+
+// MoraleLevelOf grades a morale score.
+func MoraleLevelOf(score int) MoraleLevel {
+	switch {
+	case score >= 75:
+		return MoraleHigh
+	case score >= 50:
+		return MoraleSteady
+	case score >= 30:
+		return MoraleLow
+	default:
+		return MoraleCritical
+	}
+}
+
+// Strained returns true if the level is LOW or CRITICAL.
+func (l MoraleLevel) Strained() bool {
+	return l == MoraleLow || l == MoraleCritical
+}
+
+// MoraleFactors are the conditions a resident's morale is scored from.
+type MoraleFactors struct {
+	ResidentID  string
+	RationClass RationClass // Of the resident's household; empty without one
+	Sector      string      // Of the resident's quarters; empty when unhoused
+	Occupants   int         // Active residents housed in those quarters
+	Capacity    int         // Of those quarters; 0 when unhoused
+	Incidents   []Severity  // In the sector over the last MoraleIncidentDays
+	Assignments int         // Active work assignments, training excluded
+	// Hours of classes and recreation attended over the last
+	// MoraleActivityDays
+	ActivityHours float64
+}
+
+// moraleRationEffects are the effects of each ration class on morale.
+var moraleRationEffects = map[RationClass]int{
+	RationClassMinimal:        -20,
+	RationClassStandard:       0,
+	RationClassEnhanced:       10,
+	RationClassMedical:        -5,
+	RationClassLaborIntensive: 5,
+}
+
+// moraleIncidentWeights are the morale each incident costs the residents of
+// its sector, by severity.
+var moraleIncidentWeights = map[Severity]int{
+	SeverityMinor:    2,
+	SeverityModerate: 4,
+	SeverityMajor:    8,
+	SeverityCritical: 12,
+}
+
+// Limits of the morale effects.
+const (
+	moraleUnhousedEffect   = -15
+	moraleCrowdingPerShare = 50 // Lost per 100% over capacity
+	moraleMaxCrowding      = 30
+	moraleMaxIncidents     = 25
+	moraleExtraJobEffect   = -10 // Per active assignment beyond the first
+	moraleMaxWorkload      = 20
+	moraleHoursPerActivity = 2 // Attended per point gained
+	moraleMaxActivity      = 10
+)
+
+// ResidentMorale is a resident's morale score with the effect of each
+// factor on it.
+type ResidentMorale struct {
+	ResidentID     string      `json:"resident_id"`
+	Score          int         `json:"score"` // 0-100
+	Level          MoraleLevel `json:"level"`
+	RationEffect   int         `json:"ration_effect"`
+	CrowdingEffect int         `json:"crowding_effect"`
+	IncidentEffect int         `json:"incident_effect"`
+	WorkloadEffect int         `json:"workload_effect"`
+	ActivityEffect int         `json:"activity_effect"`
+	ScoredAt       time.Time   `json:"scored_at"`
+	UpdatedAt      time.Time   `json:"updated_at"`
+}
+
+// ScoreMorale scores a resident's morale as of at: MoraleBaseline adjusted
+// for their ration class, for crowding past their quarters' capacity or
+// having none, for the incidents in their sector, for holding more than
+// one job and for the classes and recreation they attended, within 0-100.
+func ScoreMorale(f MoraleFactors, at time.Time) *ResidentMorale {
+	m := &ResidentMorale{
+		ResidentID:   f.ResidentID,
+		RationEffect: moraleRationEffects[f.RationClass],
+		ScoredAt:     at,
+	}
+
+	switch {
+	case f.Capacity <= 0:
+		m.CrowdingEffect = moraleUnhousedEffect
+	case f.Occupants > f.Capacity:
+		over := float64(f.Occupants-f.Capacity) / float64(f.Capacity)
+		m.CrowdingEffect = -min(int(math.Round(over*moraleCrowdingPerShare)), moraleMaxCrowding)
+	}
+
+	var incidents int
+	for _, severity := range f.Incidents {
+		incidents += moraleIncidentWeights[severity]
+	}
+	m.IncidentEffect = -min(incidents, moraleMaxIncidents)
+
+	if f.Assignments > 1 {
+		m.WorkloadEffect = max((f.Assignments-1)*moraleExtraJobEffect, -moraleMaxWorkload)
+	}
+
+	m.ActivityEffect = min(int(f.ActivityHours/moraleHoursPerActivity), moraleMaxActivity)
+
+	score := MoraleBaseline + m.RationEffect + m.CrowdingEffect + m.IncidentEffect + m.WorkloadEffect + m.ActivityEffect
+	m.Score = max(0, min(score, 100))
+	m.Level = MoraleLevelOf(m.Score)
+	return m
+}
+
+// MoraleSnapshot is the vault happiness index on a day: the mean morale
+// score of its active residents.
+type MoraleSnapshot struct {
+	ID         string    `json:"id"`
+	TakenAt    time.Time `json:"taken_at"`
+	Population int       `json:"population"` // Residents scored
+	Index      int       `json:"index"`      // Mean score, 0-100
+	Low        int       `json:"low"`        // Residents at LOW or CRITICAL morale
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// SummarizeMorale takes the vault happiness index of the scores. With no
+// scores the index is MoraleBaseline.
+func SummarizeMorale(scores []*ResidentMorale, at time.Time) *MoraleSnapshot {
+	snap := &MoraleSnapshot{TakenAt: at, Population: len(scores), Index: MoraleBaseline}
+	if len(scores) == 0 {
+		return snap
+	}
+	var total int
+	for _, m := range scores {
+		total += m.Score
+		if m.Level.Strained() {
+			snap.Low++
+		}
+	}
+	snap.Index = int(math.Round(float64(total) / float64(len(scores))))
+	return snap
+}
+
+// Level grades the happiness index.
+func (s *MoraleSnapshot) Level() MoraleLevel {
+	return MoraleLevelOf(s.Index)
+}
+
+// IncidentFactor returns how much more often security incidents break out
+// at a happiness index than at a content vault's: 1 at 60 and over, rising
+// linearly to 3 at 0.
+func IncidentFactor(index int) float64 {
+	const content = 60
+	if index >= content {
+		return 1
+	}
+	return 1 + 2*float64(content-max(index, 0))/content
+}
+
+// SectorMorale is the morale of the residents housed in a sector.
+type SectorMorale struct {
+	Sector    string
+	Residents int
+	Index     int // Mean score, 0-100
+}
@@ -0,0 +1,104 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScoreMorale(t *testing.T) {
+	at := time.Date(2077, 10, 23, 0, 0, 0, 0, time.UTC)
+	housed := MoraleFactors{RationClass: RationClassStandard, Occupants: 3, Capacity: 4, Assignments: 1}
+
+	tests := []struct {
+		name    string
+		factors func(f MoraleFactors) MoraleFactors
+		want    int
+		level   MoraleLevel
+	}{
+		{"Baseline", func(f MoraleFactors) MoraleFactors { return f }, 70, MoraleSteady},
+		{"Enhanced ration", func(f MoraleFactors) MoraleFactors { f.RationClass = RationClassEnhanced; return f }, 80, MoraleHigh},
+		{"Minimal ration", func(f MoraleFactors) MoraleFactors { f.RationClass = RationClassMinimal; return f }, 50, MoraleSteady},
+		{"No household", func(f MoraleFactors) MoraleFactors { f.RationClass = ""; return f }, 70, MoraleSteady},
+		{"Unhoused", func(f MoraleFactors) MoraleFactors { f.Capacity = 0; return f }, 55, MoraleSteady},
+		{"At capacity", func(f MoraleFactors) MoraleFactors { f.Occupants = 4; return f }, 70, MoraleSteady},
+		{"Half over capacity", func(f MoraleFactors) MoraleFactors { f.Occupants = 6; return f }, 45, MoraleLow},
+		{"Crowding capped", func(f MoraleFactors) MoraleFactors { f.Occupants = 12; return f }, 40, MoraleLow},
+		{"Incidents", func(f MoraleFactors) MoraleFactors {
+			f.Incidents = []Severity{SeverityMinor, SeverityMajor}
+			return f
+		}, 60, MoraleSteady},
+		{"Incidents capped", func(f MoraleFactors) MoraleFactors {
+			f.Incidents = []Severity{SeverityCritical, SeverityCritical, SeverityCritical}
+			return f
+		}, 45, MoraleLow},
+		{"No job", func(f MoraleFactors) MoraleFactors { f.Assignments = 0; return f }, 70, MoraleSteady},
+		{"Two jobs", func(f MoraleFactors) MoraleFactors { f.Assignments = 2; return f }, 60, MoraleSteady},
+		{"Workload capped", func(f MoraleFactors) MoraleFactors { f.Assignments = 5; return f }, 50, MoraleSteady},
+		{"Recreation", func(f MoraleFactors) MoraleFactors { f.ActivityHours = 9; return f }, 74, MoraleSteady},
+		{"Recreation capped", func(f MoraleFactors) MoraleFactors { f.ActivityHours = 40; return f }, 80, MoraleHigh},
+		{"Everything at once", func(f MoraleFactors) MoraleFactors {
+			f.RationClass = RationClassMinimal
+			f.Occupants = 12
+			f.Incidents = []Severity{SeverityCritical, SeverityCritical, SeverityCritical}
+			f.Assignments = 3
+			return f
+		}, 0, MoraleCritical},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ScoreMorale(tt.factors(housed), at)
+			if got.Score != tt.want || got.Level != tt.level {
+				t.Errorf("ScoreMorale() = %d %s, want %d %s", got.Score, got.Level, tt.want, tt.level)
+			}
+			effects := got.RationEffect + got.CrowdingEffect + got.IncidentEffect + got.WorkloadEffect + got.ActivityEffect
+			if got.Score > 0 && got.Score < 100 && got.Score != MoraleBaseline+effects {
+				t.Errorf("effects sum to %d, score %d", effects, got.Score)
+			}
+			if !got.ScoredAt.Equal(at) {
+				t.Errorf("ScoredAt = %v, want %v", got.ScoredAt, at)
+			}
+		})
+	}
+}
+
+func TestSummarizeMorale(t *testing.T) {
+	at := time.Date(2077, 10, 23, 0, 0, 0, 0, time.UTC)
+	scores := []*ResidentMorale{
+		{Score: 80, Level: MoraleHigh},
+		{Score: 61, Level: MoraleSteady},
+		{Score: 40, Level: MoraleLow},
+		{Score: 20, Level: MoraleCritical},
+	}
+	snap := SummarizeMorale(scores, at)
+	if snap.Population != 4 || snap.Index != 50 || snap.Low != 2 {
+		t.Errorf("SummarizeMorale() = population %d, index %d, low %d; want 4, 50, 2",
+			snap.Population, snap.Index, snap.Low)
+	}
+	if snap.Level() != MoraleSteady {
+		t.Errorf("Level() = %s, want %s", snap.Level(), MoraleSteady)
+	}
+
+	empty := SummarizeMorale(nil, at)
+	if empty.Population != 0 || empty.Index != MoraleBaseline {
+		t.Errorf("SummarizeMorale(nil) = population %d, index %d; want 0, %d",
+			empty.Population, empty.Index, MoraleBaseline)
+	}
+}
+
+func TestIncidentFactor(t *testing.T) {
+	tests := []struct {
+		index int
+		want  float64
+	}{
+		{100, 1},
+		{60, 1},
+		{30, 2},
+		{0, 3},
+		{-5, 3},
+	}
+	for _, tt := range tests {
+		if got := IncidentFactor(tt.index); got != tt.want {
+			t.Errorf("IncidentFactor(%d) = %v, want %v", tt.index, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Nutrient is a nutrient rations are planned and reported against.
+type Nutrient string
+
+const (
+	NutrientProtein  Nutrient = "PROTEIN"
+	NutrientCarbs    Nutrient = "CARBS"
+	NutrientFat      Nutrient = "FAT"
+	NutrientVitamins Nutrient = "VITAMINS"
+)
+
+// TrackedNutrients are the nutrients rations track, in display order.
+var TrackedNutrients = []Nutrient{NutrientProtein, NutrientCarbs, NutrientFat, NutrientVitamins}
+
+// NutrientDeficitShare is the fraction of its target below which a
+// nutrient drawn counts as a deficit.
+const NutrientDeficitShare = 0.9
+
+// Nutrients is the nutrient content of a unit of food, or the nutrients a
+// ration calls for or drew. Vitamins are a percentage of one resident's
+// daily allowance.
+type Nutrients struct {
+	ProteinG    float64 `json:"protein_g"`
+	CarbsG      float64 `json:"carbs_g"`
+	FatG        float64 `json:"fat_g"`
+	VitaminsPct float64 `json:"vitamins_pct"`
+}
+
+// Validate checks that no nutrient is negative.
+func (n Nutrients) Validate() error {
+	for _, nutrient := range TrackedNutrients {
+		if n.Get(nutrient) < 0 {
+			return fmt.Errorf("%s cannot be negative", nutrient)
+		}
+	}
+	return nil
+}
+
+// Get returns the amount of a nutrient.
+func (n Nutrients) Get(nutrient Nutrient) float64 {
+	switch nutrient {
+	case NutrientProtein:
+		return n.ProteinG
+	case NutrientCarbs:
+		return n.CarbsG
+	case NutrientFat:
+		return n.FatG
+	case NutrientVitamins:
+		return n.VitaminsPct
+	default:
+		return 0
+	}
+}
+
+// Add returns the sum of two amounts of nutrients.
+func (n Nutrients) Add(o Nutrients) Nutrients {
+	return Nutrients{
+		ProteinG:    n.ProteinG + o.ProteinG,
+		CarbsG:      n.CarbsG + o.CarbsG,
+		FatG:        n.FatG + o.FatG,
+		VitaminsPct: n.VitaminsPct + o.VitaminsPct,
+	}
+}
+
+// Scale returns the nutrients multiplied by f, e.g. a unit's content by the
+// units drawn.
+func (n Nutrients) Scale(f float64) Nutrients {
+	return Nutrients{
+		ProteinG:    n.ProteinG * f,
+		CarbsG:      n.CarbsG * f,
+		FatG:        n.FatG * f,
+		VitaminsPct: n.VitaminsPct * f,
+	}
+}
+
+// Coverage returns the amount of a nutrient as a fraction of its amount in
+// target, or 0 when the target has none.
+func (n Nutrients) Coverage(target Nutrients, nutrient Nutrient) float64 {
+	want := target.Get(nutrient)
+	if want <= 0 {
+		return 0
+	}
+	return n.Get(nutrient) / want
+}
+
+// Deficits returns the nutrients, in display order, that fall short of
+// NutrientDeficitShare of target. Nutrients the target has none of are
+// never short.
+func (n Nutrients) Deficits(target Nutrients) []Nutrient {
+	var short []Nutrient
+	for _, nutrient := range TrackedNutrients {
+		if target.Get(nutrient) > 0 && n.Coverage(target, nutrient) < NutrientDeficitShare {
+			short = append(short, nutrient)
+		}
+	}
+	return short
+}
+
+// String formats the nutrients as protein, carbohydrate and fat grams and
+// vitamin percent separated by slashes, e.g. "60/275/65/100".
+func (n Nutrients) String() string {
+	parts := make([]string, len(TrackedNutrients))
+	for i, nutrient := range TrackedNutrients {
+		parts[i] = strconv.FormatFloat(n.Get(nutrient), 'f', -1, 64)
+	}
+	return strings.Join(parts, "/")
+}
+
+// ParseNutrients parses nutrients written the way String formats them.
+func ParseNutrients(s string) (Nutrients, error) {
+	parts := strings.Split(strings.TrimSpace(s), "/")
+	if len(parts) != len(TrackedNutrients) {
+		return Nutrients{}, fmt.Errorf("expected nutrients as PROTEIN/CARBS/FAT/VITAMINS, got %q", s)
+	}
+	var values [4]float64
+	for i, part := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return Nutrients{}, fmt.Errorf("invalid %s %q", strings.ToLower(string(TrackedNutrients[i])), part)
+		}
+		values[i] = v
+	}
+	n := Nutrients{ProteinG: values[0], CarbsG: values[1], FatG: values[2], VitaminsPct: values[3]}
+	return n, n.Validate()
+}
@@ -0,0 +1,100 @@
+package models
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestNutrients_Arithmetic(t *testing.T) {
+	unit := Nutrients{ProteinG: 20, CarbsG: 50, FatG: 10, VitaminsPct: 25}
+	got := unit.Scale(2).Add(Nutrients{ProteinG: 5})
+	want := Nutrients{ProteinG: 45, CarbsG: 100, FatG: 20, VitaminsPct: 50}
+	if got != want {
+		t.Errorf("Scale(2).Add() = %+v, want %+v", got, want)
+	}
+	if err := got.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	if err := (Nutrients{VitaminsPct: -1}).Validate(); err == nil {
+		t.Error("Validate() of negative vitamins succeeded")
+	}
+}
+
+func TestNutrients_Deficits(t *testing.T) {
+	target := Nutrients{ProteinG: 60, CarbsG: 275, FatG: 65, VitaminsPct: 100}
+	tests := []struct {
+		name   string
+		drawn  Nutrients
+		target Nutrients
+		want   []Nutrient
+	}{
+		{"Met", target, target, nil},
+		{"Just above the share", target.Scale(NutrientDeficitShare + 0.01), target, nil},
+		{"Short of everything", Nutrients{}, target, TrackedNutrients},
+		{"Short of protein and vitamins", Nutrients{ProteinG: 30, CarbsG: 300, FatG: 65, VitaminsPct: 40}, target,
+			[]Nutrient{NutrientProtein, NutrientVitamins}},
+		{"No target", Nutrients{}, Nutrients{}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.drawn.Deficits(tt.target); !slices.Equal(got, tt.want) {
+				t.Errorf("Deficits() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	drawn := Nutrients{ProteinG: 45}
+	if got := drawn.Coverage(target, NutrientProtein); math.Abs(got-0.75) > 1e-9 {
+		t.Errorf("Coverage(PROTEIN) = %v, want 0.75", got)
+	}
+	if got := drawn.Coverage(Nutrients{}, NutrientProtein); got != 0 {
+		t.Errorf("Coverage() without a target = %v, want 0", got)
+	}
+}
+
+func TestRationClass_NutrientTargets(t *testing.T) {
+	// The macronutrients of every class stay within its calorie target
+	for _, class := range RationClasses {
+		n := class.NutrientTargets()
+		kcal := 4*n.ProteinG + 4*n.CarbsG + 9*n.FatG
+		if kcal > float64(class.CalorieTarget()) {
+			t.Errorf("%s nutrient targets are %.0f kcal, over the %d kcal target", class, kcal, class.CalorieTarget())
+		}
+		if n.VitaminsPct != 100 {
+			t.Errorf("%s vitamins target = %v, want 100", class, n.VitaminsPct)
+		}
+	}
+}
+
+func TestParseNutrients(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Nutrients
+		wantErr bool
+	}{
+		{"60/275/65/100", Nutrients{ProteinG: 60, CarbsG: 275, FatG: 65, VitaminsPct: 100}, false},
+		{" 12.5/0/3/40 ", Nutrients{ProteinG: 12.5, FatG: 3, VitaminsPct: 40}, false},
+		{"60/275/65", Nutrients{}, true},
+		{"60/lots/65/100", Nutrients{}, true},
+		{"60/275/-1/100", Nutrients{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseNutrients(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseNutrients() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseNutrients() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	n := Nutrients{ProteinG: 60, CarbsG: 275, FatG: 65.5, VitaminsPct: 100}
+	if got := n.String(); got != "60/275/65.5/100" {
+		t.Errorf("String() = %q, want 60/275/65.5/100", got)
+	}
+}
@@ -0,0 +1,36 @@
+package models
+
+// Redacted stands in for a personal detail the operator lacks the clearance
+// to see. Written back in an update, it leaves the detail unchanged.
+const Redacted = "[REDACTED]"
+
+// redact replaces a non-empty detail with Redacted.
+func redact(s *string) {
+	if *s != "" {
+		*s = Redacted
+	}
+}
+
+// Redact hides the resident's blood type and notes.
+func (r *Resident) Redact() {
+	if r.BloodType != "" {
+		r.BloodType = BloodType(Redacted)
+	}
+	redact(&r.Notes)
+}
+
+// Redact hides the reason of the status period, e.g. why a resident was
+// confined to quarters under quarantine.
+func (t *StatusTransition) Redact() {
+	redact(&t.Reason)
+}
+
+// Redact hides the medical officer's notes on the exposure.
+func (e *RadiationExposure) Redact() {
+	redact(&e.Notes)
+}
+
+// Redact hides the medical officer's notes on the treatment.
+func (t *DecontaminationTreatment) Redact() {
+	redact(&t.Notes)
+}
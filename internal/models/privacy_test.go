@@ -0,0 +1,25 @@
+package models
+
+import "testing"
+
+func TestResident_Redact(t *testing.T) {
+	r := &Resident{BloodType: BloodTypeOPos, Notes: "Allergic to mutfruit"}
+	r.Redact()
+	if r.BloodType != BloodType(Redacted) || r.Notes != Redacted {
+		t.Errorf("Redact() left blood type %q, notes %q", r.BloodType, r.Notes)
+	}
+
+	r = &Resident{}
+	r.Redact()
+	if r.BloodType != "" || r.Notes != "" {
+		t.Errorf("Redact() of empty details = blood type %q, notes %q, want empty", r.BloodType, r.Notes)
+	}
+}
+
+func TestStatusTransition_Redact(t *testing.T) {
+	tr := &StatusTransition{Status: ResidentStatusQuarantine, Reason: "Confined after an altercation"}
+	tr.Redact()
+	if tr.Reason != Redacted {
+		t.Errorf("Redact() left reason %q", tr.Reason)
+	}
+}
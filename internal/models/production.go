@@ -0,0 +1,80 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// ProductionRunStatus represents the state of a production run.
+type ProductionRunStatus string
+
+const (
+	ProductionRunQueued    ProductionRunStatus = "QUEUED"
+	ProductionRunCompleted ProductionRunStatus = "COMPLETED"
+	ProductionRunCancelled ProductionRunStatus = "CANCELLED"
+)
+
+// Valid returns true if the production run status is valid.
+func (s ProductionRunStatus) Valid() bool {
+	switch s {
+	case ProductionRunQueued, ProductionRunCompleted, ProductionRunCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// ProductionRun is production of an item queued to restore its stock,
+// completed by the next production recorded of the item or cancelled once
+// the stock recovers.
+type ProductionRun struct {
+	ID       string
+	ItemID   string
+	Quantity float64
+	Status   ProductionRunStatus
+	Reason   string     // "Below minimum: 40.00 of 100.00 kg"
+	QueuedAt time.Time  // Vault time
+	ClosedAt *time.Time // When completed or cancelled
+	StockID  *string    // Lot produced, when completed
+	VaultID  int
+
+	// Joined fields
+	Item *ResourceItem
+}
+
+// Validate checks if the production run data is valid.
+func (r *ProductionRun) Validate() error {
+	if r.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if r.ItemID == "" {
+		return fmt.Errorf("item_id is required")
+	}
+	if r.Quantity <= 0 {
+		return fmt.Errorf("quantity must be positive")
+	}
+	if !r.Status.Valid() {
+		return fmt.Errorf("invalid status: %s", r.Status)
+	}
+	if r.Reason == "" {
+		return fmt.Errorf("reason is required")
+	}
+	if r.QueuedAt.IsZero() {
+		return fmt.Errorf("queued_at is required")
+	}
+	return nil
+}
+
+// Shortage is an item whose available stock in a vault is below its
+// minimum, with the production run queued for it, if any.
+type Shortage struct {
+	Item      *ResourceItem
+	Available float64
+	Shortfall float64        // Quantity restoring the item to its target
+	Run       *ProductionRun // Nil for an item that is not producible
+}
+
+// Critical returns true if the item is out of stock.
+func (s *Shortage) Critical() bool {
+	return s.Available < QuantityEpsilon
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestProductionRun_Validate(t *testing.T) {
+	valid := func() *ProductionRun {
+		return &ProductionRun{
+			ID:       "run-1",
+			ItemID:   "item-1",
+			Quantity: 60,
+			Status:   ProductionRunQueued,
+			Reason:   "Below minimum: 40.00 of 100.00 kg",
+			QueuedAt: time.Date(2077, 11, 1, 0, 0, 0, 0, time.UTC),
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*ProductionRun)
+		wantErr bool
+	}{
+		{"Valid run", func(r *ProductionRun) {}, false},
+		{"Missing item", func(r *ProductionRun) { r.ItemID = "" }, true},
+		{"Zero quantity", func(r *ProductionRun) { r.Quantity = 0 }, true},
+		{"Invalid status", func(r *ProductionRun) { r.Status = "RUNNING" }, true},
+		{"Missing reason", func(r *ProductionRun) { r.Reason = "" }, true},
+		{"Missing queue time", func(r *ProductionRun) { r.QueuedAt = time.Time{} }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := valid()
+			tt.modify(r)
+			if err := r.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestShortage_Critical(t *testing.T) {
+	if !(&Shortage{Available: 0}).Critical() {
+		t.Error("Expected an item out of stock to be a critical shortage")
+	}
+	if (&Shortage{Available: 12}).Critical() {
+		t.Error("Expected an item with stock left not to be a critical shortage")
+	}
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// QualityTest is a water or food quality test of a consumable lot. A failed
+// test quarantines the lot.
+type QualityTest struct {
+	ID                 string
+	StockID            string
+	TestedAt           time.Time
+	TestedBy           *string // Resident who ran the test
+	ContaminationLevel float64 // Measured contamination, ppm
+	Passed             bool
+	Notes              string
+	CreatedAt          time.Time
+}
+
+// Validate checks if the quality test data is valid.
+func (q *QualityTest) Validate() error {
+	if q.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if q.StockID == "" {
+		return fmt.Errorf("stock_id is required")
+	}
+	if q.TestedAt.IsZero() {
+		return fmt.Errorf("tested_at is required")
+	}
+	if q.ContaminationLevel < 0 {
+		return fmt.Errorf("contamination_level cannot be negative")
+	}
+	return nil
+}
+
+// Result returns PASS or FAIL.
+func (q *QualityTest) Result() string {
+	if q.Passed {
+		return "PASS"
+	}
+	return "FAIL"
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQualityTest_Validate(t *testing.T) {
+	valid := func() *QualityTest {
+		return &QualityTest{
+			ID:                 "qt-1",
+			StockID:            "stock-1",
+			TestedAt:           time.Date(2077, 10, 23, 9, 0, 0, 0, time.UTC),
+			ContaminationLevel: 0.4,
+			Passed:             true,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*QualityTest)
+		wantErr bool
+	}{
+		{"Valid test", func(q *QualityTest) {}, false},
+		{"Failed test", func(q *QualityTest) { q.Passed = false }, false},
+		{"Missing ID", func(q *QualityTest) { q.ID = "" }, true},
+		{"Missing stock", func(q *QualityTest) { q.StockID = "" }, true},
+		{"Missing tested_at", func(q *QualityTest) { q.TestedAt = time.Time{} }, true},
+		{"Negative contamination", func(q *QualityTest) { q.ContaminationLevel = -0.1 }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := valid()
+			tt.modify(q)
+			if err := q.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestQualityTest_Result(t *testing.T) {
+	if got := (&QualityTest{Passed: true}).Result(); got != "PASS" {
+		t.Errorf("Result() = %q, want PASS", got)
+	}
+	if got := (&QualityTest{}).Result(); got != "FAIL" {
+		t.Errorf("Result() = %q, want FAIL", got)
+	}
+}
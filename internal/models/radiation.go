@@ -0,0 +1,173 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// RadiationConditionCode is the medical condition code of the health flag
+// raised by a resident's cumulative radiation dose.
+const RadiationConditionCode = "RAD-EXPOSURE"
+
+// RadiationFlagMSv is the cumulative dose, in mSv, at which a resident is
+// flagged for the medical module.
+const RadiationFlagMSv = 100
+
+// RadiationLevel grades a cumulative radiation dose.
+type RadiationLevel string
+
+const (
+	RadiationLevelNormal   RadiationLevel = "NORMAL"
+	RadiationLevelElevated RadiationLevel = "ELEVATED"
+	RadiationLevelHigh     RadiationLevel = "HIGH"
+	RadiationLevelSevere   RadiationLevel = "SEVERE"
+	RadiationLevelCritical RadiationLevel = "CRITICAL"
+)
+
+// radiationThresholds are the cumulative doses, in mSv, at which each
+// level starts, highest first.
+var radiationThresholds = []struct {
+	Level   RadiationLevel
+	MinDose float64
+}{
+	{RadiationLevelCritical, 4000},
+	{RadiationLevelSevere, 1000},
+	{RadiationLevelHigh, 500},
+	{RadiationLevelElevated, RadiationFlagMSv},
+}
+
+// RadiationLevelFor returns the level of a cumulative dose in mSv.
+func RadiationLevelFor(doseMSv float64) RadiationLevel {
+	for _, t := range radiationThresholds {
+		if doseMSv >= t.MinDose {
+			return t.Level
+		}
+	}
+	return RadiationLevelNormal
+}
+
+// Rank orders levels from NORMAL, 0, to CRITICAL, 4.
+func (l RadiationLevel) Rank() int {
+	switch l {
+	case RadiationLevelElevated:
+		return 1
+	case RadiationLevelHigh:
+		return 2
+	case RadiationLevelSevere:
+		return 3
+	case RadiationLevelCritical:
+		return 4
+	default:
+		return 0
+	}
+}
+
+// Flagged returns true if the level raises a health flag.
+func (l RadiationLevel) Flagged() bool {
+	return l.Rank() > 0
+}
+
+// ConditionSeverity returns the severity of the health flag the level
+// raises. A NORMAL dose raises none.
+func (l RadiationLevel) ConditionSeverity() ConditionSeverity {
+	switch l {
+	case RadiationLevelElevated:
+		return ConditionSeverityMild
+	case RadiationLevelHigh:
+		return ConditionSeverityModerate
+	case RadiationLevelSevere:
+		return ConditionSeveritySevere
+	case RadiationLevelCritical:
+		return ConditionSeverityCritical
+	default:
+		return ""
+	}
+}
+
+// RadiationExposure is a radiation dose a resident received.
+type RadiationExposure struct {
+	ID           string    `json:"id"`
+	ResidentID   string    `json:"resident_id"`
+	Source       string    `json:"source"` // e.g. "Reactor coolant leak", "Surface expedition"
+	DoseMSv      float64   `json:"dose_msv"`
+	ExposureDate time.Time `json:"exposure_date"`
+	RecordedBy   *string   `json:"recorded_by,omitempty"`
+	Notes        string    `json:"notes,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// Validate checks if the exposure data is valid.
+func (e *RadiationExposure) Validate() error {
+	if e.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if e.ResidentID == "" {
+		return fmt.Errorf("resident_id is required")
+	}
+	if e.Source == "" {
+		return fmt.Errorf("source is required")
+	}
+	if e.DoseMSv <= 0 {
+		return fmt.Errorf("dose must be positive")
+	}
+	if e.ExposureDate.IsZero() {
+		return fmt.Errorf("exposure_date is required")
+	}
+	return nil
+}
+
+// DecontaminationTreatment is a treatment that purged radiation from a
+// resident by consuming a resource item such as RadAway.
+type DecontaminationTreatment struct {
+	ID             string    `json:"id"`
+	ResidentID     string    `json:"resident_id"`
+	ItemID         string    `json:"item_id"`
+	Quantity       float64   `json:"quantity"`
+	DoseReducedMSv float64   `json:"dose_reduced_msv"`
+	TreatmentDate  time.Time `json:"treatment_date"`
+	ProviderID     *string   `json:"provider_id,omitempty"`
+	Notes          string    `json:"notes,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// Validate checks if the treatment data is valid.
+func (t *DecontaminationTreatment) Validate() error {
+	if t.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if t.ResidentID == "" {
+		return fmt.Errorf("resident_id is required")
+	}
+	if t.ItemID == "" {
+		return fmt.Errorf("item_id is required")
+	}
+	if t.Quantity <= 0 {
+		return fmt.Errorf("quantity must be positive")
+	}
+	if t.DoseReducedMSv < 0 {
+		return fmt.Errorf("dose reduced cannot be negative")
+	}
+	if t.TreatmentDate.IsZero() {
+		return fmt.Errorf("treatment_date is required")
+	}
+	return nil
+}
+
+// RadiationDose is a resident's cumulative radiation dose.
+type RadiationDose struct {
+	ResidentID   string         `json:"resident_id"`
+	ExposedMSv   float64        `json:"exposed_msv"` // Total of exposures
+	PurgedMSv    float64        `json:"purged_msv"`  // Total removed by treatment
+	Exposures    int            `json:"exposures"`   // Number of exposure events
+	LastExposure *time.Time     `json:"last_exposure,omitempty"`
+	Level        RadiationLevel `json:"level"`
+
+	// Joined fields
+	Resident *Resident `json:"resident,omitempty"`
+}
+
+// CumulativeMSv returns the dose still carried: exposures less treatment,
+// never below zero.
+func (d *RadiationDose) CumulativeMSv() float64 {
+	return max(d.ExposedMSv-d.PurgedMSv, 0)
+}
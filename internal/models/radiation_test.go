@@ -0,0 +1,114 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRadiationLevelFor(t *testing.T) {
+	tests := []struct {
+		dose     float64
+		want     RadiationLevel
+		severity ConditionSeverity
+	}{
+		{0, RadiationLevelNormal, ""},
+		{99.9, RadiationLevelNormal, ""},
+		{100, RadiationLevelElevated, ConditionSeverityMild},
+		{500, RadiationLevelHigh, ConditionSeverityModerate},
+		{1500, RadiationLevelSevere, ConditionSeveritySevere},
+		{4000, RadiationLevelCritical, ConditionSeverityCritical},
+	}
+
+	for _, tt := range tests {
+		got := RadiationLevelFor(tt.dose)
+		if got != tt.want {
+			t.Errorf("RadiationLevelFor(%v) = %s, want %s", tt.dose, got, tt.want)
+		}
+		if got.ConditionSeverity() != tt.severity {
+			t.Errorf("%s.ConditionSeverity() = %q, want %q", got, got.ConditionSeverity(), tt.severity)
+		}
+		if got.Flagged() != (tt.severity != "") {
+			t.Errorf("%s.Flagged() = %v", got, got.Flagged())
+		}
+	}
+}
+
+func TestRadiationDose_CumulativeMSv(t *testing.T) {
+	dose := &RadiationDose{ExposedMSv: 650, PurgedMSv: 200}
+	if got := dose.CumulativeMSv(); got != 450 {
+		t.Errorf("CumulativeMSv() = %v, want 450", got)
+	}
+
+	// Treatment never takes the dose below zero
+	dose.PurgedMSv = 800
+	if got := dose.CumulativeMSv(); got != 0 {
+		t.Errorf("CumulativeMSv() = %v, want 0", got)
+	}
+}
+
+func TestRadiationExposure_Validate(t *testing.T) {
+	valid := func() *RadiationExposure {
+		return &RadiationExposure{
+			ID:           "exp-1",
+			ResidentID:   "res-1",
+			Source:       "Reactor coolant leak",
+			DoseMSv:      120,
+			ExposureDate: time.Date(2078, 3, 1, 14, 0, 0, 0, time.UTC),
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*RadiationExposure)
+		wantErr bool
+	}{
+		{"Valid", func(e *RadiationExposure) {}, false},
+		{"Missing source", func(e *RadiationExposure) { e.Source = "" }, true},
+		{"Zero dose", func(e *RadiationExposure) { e.DoseMSv = 0 }, true},
+		{"Missing date", func(e *RadiationExposure) { e.ExposureDate = time.Time{} }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := valid()
+			tt.modify(e)
+			if err := e.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDecontaminationTreatment_Validate(t *testing.T) {
+	valid := func() *DecontaminationTreatment {
+		return &DecontaminationTreatment{
+			ID:             "trt-1",
+			ResidentID:     "res-1",
+			ItemID:         "item-1",
+			Quantity:       2,
+			DoseReducedMSv: 200,
+			TreatmentDate:  time.Date(2078, 3, 2, 9, 0, 0, 0, time.UTC),
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*DecontaminationTreatment)
+		wantErr bool
+	}{
+		{"Valid", func(tr *DecontaminationTreatment) {}, false},
+		{"Missing item", func(tr *DecontaminationTreatment) { tr.ItemID = "" }, true},
+		{"Zero quantity", func(tr *DecontaminationTreatment) { tr.Quantity = 0 }, true},
+		{"Negative reduction", func(tr *DecontaminationTreatment) { tr.DoseReducedMSv = -1 }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := valid()
+			tt.modify(tr)
+			if err := tr.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
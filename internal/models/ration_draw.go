@@ -0,0 +1,147 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// RationDraw is one household's share of a day's rations: what its class's
+// policy called for, what stock covered, and the departments its members
+// work in, so consumption can be attributed to households and departments.
+type RationDraw struct {
+	ID                string
+	Day               time.Time // Vault day drawn for
+	HouseholdID       *string   // Nil once the household is deleted
+	RationClass       RationClass
+	Residents         int
+	CaloriesRequired  float64
+	CaloriesDrawn     float64
+	WaterRequiredL    float64
+	WaterDrawnL       float64
+	NutrientsRequired Nutrients
+	NutrientsDrawn    Nutrients
+	VaultID           int
+
+	// Residents by the department of their primary vocation, "" for those
+	// without one; the counts add up to Residents
+	Departments map[Department]int
+}
+
+// Validate checks if the ration draw data is valid.
+func (d *RationDraw) Validate() error {
+	if d.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if d.Day.IsZero() {
+		return fmt.Errorf("day is required")
+	}
+	if d.HouseholdID == nil || *d.HouseholdID == "" {
+		return fmt.Errorf("household_id is required")
+	}
+	if !d.RationClass.Valid() {
+		return fmt.Errorf("invalid ration class: %s", d.RationClass)
+	}
+	if d.Residents <= 0 {
+		return fmt.Errorf("residents must be positive")
+	}
+	if d.CaloriesRequired < 0 || d.CaloriesDrawn < 0 || d.WaterRequiredL < 0 || d.WaterDrawnL < 0 {
+		return fmt.Errorf("quantities cannot be negative")
+	}
+	if d.CaloriesDrawn > d.CaloriesRequired+QuantityEpsilon || d.WaterDrawnL > d.WaterRequiredL+QuantityEpsilon {
+		return fmt.Errorf("drawn cannot exceed required")
+	}
+	if err := d.NutrientsRequired.Validate(); err != nil {
+		return fmt.Errorf("nutrients required: %w", err)
+	}
+	if err := d.NutrientsDrawn.Validate(); err != nil {
+		return fmt.Errorf("nutrients drawn: %w", err)
+	}
+
+	total := 0
+	for dept, count := range d.Departments {
+		if count <= 0 {
+			return fmt.Errorf("department %q residents must be positive", dept)
+		}
+		total += count
+	}
+	if total != d.Residents {
+		return fmt.Errorf("department residents add up to %d, not %d", total, d.Residents)
+	}
+	return nil
+}
+
+// RationTotals sums the rations drawn for a group of residents over a
+// period.
+type RationTotals struct {
+	ResidentDays int // Residents fed, summed over the days
+	Calories     float64
+	WaterL       float64
+}
+
+// CaloriesPerCapita returns the calories drawn per resident per day.
+func (t RationTotals) CaloriesPerCapita() float64 {
+	if t.ResidentDays == 0 {
+		return 0
+	}
+	return t.Calories / float64(t.ResidentDays)
+}
+
+// WaterLPerCapita returns the liters of water drawn per resident per day.
+func (t RationTotals) WaterLPerCapita() float64 {
+	if t.ResidentDays == 0 {
+		return 0
+	}
+	return t.WaterL / float64(t.ResidentDays)
+}
+
+// HouseholdConsumption is the rations a household drew over a period.
+type HouseholdConsumption struct {
+	RationTotals
+	HouseholdID string
+	Designation string
+	RationClass RationClass // The household's class now
+}
+
+// DepartmentConsumption is the rations drawn over a period for the
+// residents of a department, apportioned from each household's draw by the
+// share of its members working there.
+type DepartmentConsumption struct {
+	RationTotals
+	Department Department // Empty for residents without a vocation
+}
+
+// ClassConsumption compares the rations drawn per resident of a ration
+// class over a period with the class's daily targets, and the nutrients
+// drawn with those its draws called for.
+type ClassConsumption struct {
+	RationTotals
+	RationClass       RationClass
+	CalorieTarget     int
+	WaterTargetL      float64
+	NutrientsRequired Nutrients
+	NutrientsDrawn    Nutrients
+}
+
+// CalorieCoverage returns the calories drawn per resident per day as a
+// fraction of the target, or 0 without one.
+func (c *ClassConsumption) CalorieCoverage() float64 {
+	if c.CalorieTarget <= 0 {
+		return 0
+	}
+	return c.CaloriesPerCapita() / float64(c.CalorieTarget)
+}
+
+// WaterCoverage returns the water drawn per resident per day as a fraction
+// of the target, or 0 without one.
+func (c *ClassConsumption) WaterCoverage() float64 {
+	if c.WaterTargetL <= 0 {
+		return 0
+	}
+	return c.WaterLPerCapita() / c.WaterTargetL
+}
+
+// NutrientDeficits returns the nutrients drawn short of what the class's
+// draws called for, in display order.
+func (c *ClassConsumption) NutrientDeficits() []Nutrient {
+	return c.NutrientsDrawn.Deficits(c.NutrientsRequired)
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestRationDraw_Validate(t *testing.T) {
+	valid := func() *RationDraw {
+		household := "hh-1"
+		return &RationDraw{
+			ID:               "draw-1",
+			Day:              time.Date(2077, 11, 1, 0, 0, 0, 0, time.UTC),
+			HouseholdID:      &household,
+			RationClass:      RationClassStandard,
+			Residents:        3,
+			CaloriesRequired: 6000,
+			CaloriesDrawn:    4500,
+			WaterRequiredL:   9,
+			WaterDrawnL:      9,
+			Departments:      map[Department]int{DepartmentEngineering: 2, "": 1},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*RationDraw)
+		wantErr bool
+	}{
+		{"Valid draw", func(d *RationDraw) {}, false},
+		{"Missing day", func(d *RationDraw) { d.Day = time.Time{} }, true},
+		{"Missing household", func(d *RationDraw) { d.HouseholdID = nil }, true},
+		{"Invalid ration class", func(d *RationDraw) { d.RationClass = "FEAST" }, true},
+		{"No residents", func(d *RationDraw) { d.Residents = 0; d.Departments = nil }, true},
+		{"Negative water", func(d *RationDraw) { d.WaterDrawnL = -1 }, true},
+		{"Drawn over required", func(d *RationDraw) { d.CaloriesDrawn = 6500 }, true},
+		{"Negative nutrients drawn", func(d *RationDraw) { d.NutrientsDrawn.ProteinG = -1 }, true},
+		{"Departments short of residents", func(d *RationDraw) { d.Departments[""] = 0 }, true},
+		{"Departments over residents", func(d *RationDraw) { d.Departments[DepartmentMedical] = 1 }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := valid()
+			tt.modify(d)
+			if err := d.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClassConsumption_Coverage(t *testing.T) {
+	c := &ClassConsumption{
+		RationTotals:  RationTotals{ResidentDays: 30, Calories: 45000, WaterL: 90},
+		CalorieTarget: 2000,
+		WaterTargetL:  3,
+	}
+	if got := c.CaloriesPerCapita(); got != 1500 {
+		t.Errorf("CaloriesPerCapita() = %v, want 1500", got)
+	}
+	if got := c.CalorieCoverage(); math.Abs(got-0.75) > 1e-9 {
+		t.Errorf("CalorieCoverage() = %v, want 0.75", got)
+	}
+	if got := c.WaterCoverage(); math.Abs(got-1) > 1e-9 {
+		t.Errorf("WaterCoverage() = %v, want 1", got)
+	}
+
+	if got := (&ClassConsumption{}).CalorieCoverage(); got != 0 {
+		t.Errorf("CalorieCoverage() without a target = %v, want 0", got)
+	}
+	if got := (RationTotals{}).WaterLPerCapita(); got != 0 {
+		t.Errorf("WaterLPerCapita() of no residents = %v, want 0", got)
+	}
+}
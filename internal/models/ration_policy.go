@@ -0,0 +1,97 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// RationClasses are the ration classes in order of allocation.
+var RationClasses = []RationClass{
+	RationClassMinimal, RationClassStandard, RationClassEnhanced,
+	RationClassMedical, RationClassLaborIntensive,
+}
+
+// RationPolicy sets the daily targets of a ration class from its effective
+// date until the class's next policy.
+type RationPolicy struct {
+	ID            string      `json:"id"`
+	RationClass   RationClass `json:"ration_class"`
+	CalorieTarget int         `json:"calorie_target"`
+	WaterTargetL  float64     `json:"water_target_l"`
+	Nutrients     *Nutrients  `json:"nutrients,omitempty"` // Daily targets per resident, nil for the class's built-in ones
+	EffectiveFrom time.Time   `json:"effective_from"`
+	SetBy         *string     `json:"set_by,omitempty"`
+	Reason        string      `json:"reason,omitempty"`
+	CreatedAt     time.Time   `json:"created_at"`
+}
+
+// Validate checks if the ration policy data is valid.
+func (p *RationPolicy) Validate() error {
+	if p.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if !p.RationClass.Valid() {
+		return fmt.Errorf("invalid ration class: %s", p.RationClass)
+	}
+	if p.CalorieTarget <= 0 {
+		return fmt.Errorf("calorie_target must be positive")
+	}
+	if p.WaterTargetL <= 0 {
+		return fmt.Errorf("water_target_l must be positive")
+	}
+	if p.Nutrients != nil {
+		if err := p.Nutrients.Validate(); err != nil {
+			return fmt.Errorf("nutrient targets: %w", err)
+		}
+	}
+	if p.EffectiveFrom.IsZero() {
+		return fmt.Errorf("effective_from is required")
+	}
+	return nil
+}
+
+// RationTargets are the policies in force, by ration class.
+type RationTargets map[RationClass]*RationPolicy
+
+// ActiveRationPolicies returns the policy of each class in force at at: the
+// latest effective at or before it.
+func ActiveRationPolicies(policies []*RationPolicy, at time.Time) RationTargets {
+	targets := make(RationTargets)
+	for _, p := range policies {
+		if p.EffectiveFrom.After(at) {
+			continue
+		}
+		if current, ok := targets[p.RationClass]; !ok || p.EffectiveFrom.After(current.EffectiveFrom) {
+			targets[p.RationClass] = p
+		}
+	}
+	return targets
+}
+
+// Calories returns the daily calorie target of a class, the class's
+// built-in target if no policy is in force.
+func (t RationTargets) Calories(class RationClass) int {
+	if p, ok := t[class]; ok {
+		return p.CalorieTarget
+	}
+	return class.CalorieTarget()
+}
+
+// WaterL returns the daily water target in liters of a class, the class's
+// built-in target if no policy is in force.
+func (t RationTargets) WaterL(class RationClass) float64 {
+	if p, ok := t[class]; ok {
+		return p.WaterTargetL
+	}
+	return class.WaterTarget()
+}
+
+// Nutrients returns the daily nutrient targets per resident of a class: the
+// policy's if the policy in force sets them, the class's built-in targets
+// otherwise.
+func (t RationTargets) Nutrients(class RationClass) Nutrients {
+	if p, ok := t[class]; ok && p.Nutrients != nil {
+		return *p.Nutrients
+	}
+	return class.NutrientTargets()
+}
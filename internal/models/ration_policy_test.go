@@ -0,0 +1,107 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRationPolicy_Validate(t *testing.T) {
+	valid := func() *RationPolicy {
+		return &RationPolicy{
+			ID:            "rp-1",
+			RationClass:   RationClassStandard,
+			CalorieTarget: 1900,
+			WaterTargetL:  2.8,
+			EffectiveFrom: time.Date(2078, 1, 1, 0, 0, 0, 0, time.UTC),
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*RationPolicy)
+		wantErr bool
+	}{
+		{"Valid", func(p *RationPolicy) {}, false},
+		{"Invalid class", func(p *RationPolicy) { p.RationClass = "LUXURY" }, true},
+		{"No calories", func(p *RationPolicy) { p.CalorieTarget = 0 }, true},
+		{"Negative water", func(p *RationPolicy) { p.WaterTargetL = -1 }, true},
+		{"Nutrient targets", func(p *RationPolicy) { p.Nutrients = &Nutrients{ProteinG: 55, VitaminsPct: 100} }, false},
+		{"Negative nutrient target", func(p *RationPolicy) { p.Nutrients = &Nutrients{FatG: -1} }, true},
+		{"No effective date", func(p *RationPolicy) { p.EffectiveFrom = time.Time{} }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := valid()
+			tt.modify(p)
+			if err := p.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestActiveRationPolicies(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2078, 3, d, 0, 0, 0, 0, time.UTC) }
+	policies := []*RationPolicy{
+		{ID: "std-1", RationClass: RationClassStandard, CalorieTarget: 2000, WaterTargetL: 3, EffectiveFrom: day(1)},
+		{ID: "std-3", RationClass: RationClassStandard, CalorieTarget: 1800, WaterTargetL: 2.5, EffectiveFrom: day(10)},
+		{ID: "std-2", RationClass: RationClassStandard, CalorieTarget: 1900, WaterTargetL: 2.8, EffectiveFrom: day(5)},
+		{ID: "min-1", RationClass: RationClassMinimal, CalorieTarget: 1400, WaterTargetL: 1.8, EffectiveFrom: day(7)},
+	}
+
+	tests := []struct {
+		at           time.Time
+		wantStandard string
+		wantMinimal  string
+	}{
+		{day(1), "std-1", ""},
+		{day(6), "std-2", ""},
+		{day(7), "std-2", "min-1"},
+		{day(20), "std-3", "min-1"},
+	}
+
+	for _, tt := range tests {
+		targets := ActiveRationPolicies(policies, tt.at)
+		if got := targets[RationClassStandard]; got == nil || got.ID != tt.wantStandard {
+			t.Errorf("STANDARD policy at %s = %v, want %s", tt.at.Format(time.DateOnly), got, tt.wantStandard)
+		}
+		if got, ok := targets[RationClassMinimal]; ok != (tt.wantMinimal != "") || (ok && got.ID != tt.wantMinimal) {
+			t.Errorf("MINIMAL policy at %s = %v, want %q", tt.at.Format(time.DateOnly), got, tt.wantMinimal)
+		}
+	}
+
+	if targets := ActiveRationPolicies(policies, day(2)); len(targets) != 1 {
+		t.Errorf("Expected only STANDARD in force on day 2, got %d policies", len(targets))
+	}
+}
+
+func TestRationTargets(t *testing.T) {
+	targets := RationTargets{
+		RationClassStandard: {RationClass: RationClassStandard, CalorieTarget: 1800, WaterTargetL: 2.5},
+	}
+
+	if got := targets.Calories(RationClassStandard); got != 1800 {
+		t.Errorf("Calories(STANDARD) = %d, want 1800", got)
+	}
+	if got := targets.WaterL(RationClassStandard); got != 2.5 {
+		t.Errorf("WaterL(STANDARD) = %v, want 2.5", got)
+	}
+	// Classes without a policy fall back to their built-in targets
+	if got := targets.Calories(RationClassLaborIntensive); got != RationClassLaborIntensive.CalorieTarget() {
+		t.Errorf("Calories(LABOR_INTENSIVE) = %d, want built-in %d", got, RationClassLaborIntensive.CalorieTarget())
+	}
+	if got := targets.WaterL(RationClassEnhanced); got != RationClassEnhanced.WaterTarget() {
+		t.Errorf("WaterL(ENHANCED) = %v, want built-in %v", got, RationClassEnhanced.WaterTarget())
+	}
+
+	// A policy without nutrient targets keeps the built-in ones
+	if got := targets.Nutrients(RationClassStandard); got != RationClassStandard.NutrientTargets() {
+		t.Errorf("Nutrients(STANDARD) = %+v, want built-in %+v", got, RationClassStandard.NutrientTargets())
+	}
+	set := Nutrients{ProteinG: 50, CarbsG: 250, FatG: 60, VitaminsPct: 90}
+	targets[RationClassStandard].Nutrients = &set
+	if got := targets.Nutrients(RationClassStandard); got != set {
+		t.Errorf("Nutrients(STANDARD) = %+v, want %+v", got, set)
+	}
+}
@@ -0,0 +1,132 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// RationReviewTrigger identifies the composition change that prompted a review.
+type RationReviewTrigger string
+
+const (
+	RationReviewTriggerBirth    RationReviewTrigger = "BIRTH"
+	RationReviewTriggerDeath    RationReviewTrigger = "DEATH"
+	RationReviewTriggerTransfer RationReviewTrigger = "TRANSFER"
+	RationReviewTriggerManual   RationReviewTrigger = "MANUAL"
+)
+
+// Valid returns true if the trigger is valid.
+func (t RationReviewTrigger) Valid() bool {
+	switch t {
+	case RationReviewTriggerBirth, RationReviewTriggerDeath,
+		RationReviewTriggerTransfer, RationReviewTriggerManual:
+		return true
+	default:
+		return false
+	}
+}
+
+// RationReviewStatus represents the approval state of a ration class review.
+type RationReviewStatus string
+
+const (
+	RationReviewStatusPending    RationReviewStatus = "PENDING"
+	RationReviewStatusApproved   RationReviewStatus = "APPROVED"
+	RationReviewStatusRejected   RationReviewStatus = "REJECTED"
+	RationReviewStatusSuperseded RationReviewStatus = "SUPERSEDED"
+)
+
+// Valid returns true if the status is valid.
+func (s RationReviewStatus) Valid() bool {
+	switch s {
+	case RationReviewStatusPending, RationReviewStatusApproved,
+		RationReviewStatusRejected, RationReviewStatusSuperseded:
+		return true
+	default:
+		return false
+	}
+}
+
+// RationClassReview is a suggested ration class change awaiting approval.
+type RationClassReview struct {
+	ID               string              `json:"id"`
+	HouseholdID      string              `json:"household_id"`
+	CurrentClass     RationClass         `json:"current_class"`
+	RecommendedClass RationClass         `json:"recommended_class"`
+	Reason           string              `json:"reason"`
+	Trigger          RationReviewTrigger `json:"trigger"`
+	Status           RationReviewStatus  `json:"status"`
+	DecidedBy        *string             `json:"decided_by,omitempty"`
+	DecidedAt        *time.Time          `json:"decided_at,omitempty"`
+	CreatedAt        time.Time           `json:"created_at"`
+	UpdatedAt        time.Time           `json:"updated_at"`
+}
+
+// Validate checks if the review data is valid.
+func (r *RationClassReview) Validate() error {
+	if r.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if r.HouseholdID == "" {
+		return fmt.Errorf("household_id is required")
+	}
+	if !r.CurrentClass.Valid() {
+		return fmt.Errorf("invalid current_class: %s", r.CurrentClass)
+	}
+	if !r.RecommendedClass.Valid() {
+		return fmt.Errorf("invalid recommended_class: %s", r.RecommendedClass)
+	}
+	if !r.Trigger.Valid() {
+		return fmt.Errorf("invalid trigger: %s", r.Trigger)
+	}
+	if !r.Status.Valid() {
+		return fmt.Errorf("invalid status: %s", r.Status)
+	}
+	return nil
+}
+
+// IsPending returns true if the review is awaiting a decision.
+func (r *RationClassReview) IsPending() bool {
+	return r.Status == RationReviewStatusPending
+}
+
+// InfantAgeLimit is the age below which a household member counts as an infant
+// for ration purposes.
+const InfantAgeLimit = 2
+
+// RecommendRationClass applies the composition rules to a household and returns
+// the ration class it should hold along with the reason for it.
+//
+// Rules are evaluated in priority order:
+//  1. Any living member with medical needs (listed in medicalNeeds, or held in
+//     quarantine) requires MEDICAL rations.
+//  2. Any living infant requires ENHANCED rations for nursing and growth.
+//  3. MINIMAL and LABOR_INTENSIVE are discretionary assignments made by the
+//     Overseer, and are kept unless a higher-priority rule applies.
+//  4. Everything else is STANDARD.
+func RecommendRationClass(current RationClass, members []*Resident, medicalNeeds map[string]bool, asOf time.Time) (RationClass, string) {
+	var living []*Resident
+	for _, m := range members {
+		if m.IsAlive() && m.Status != ResidentStatusExiled {
+			living = append(living, m)
+		}
+	}
+
+	for _, m := range living {
+		if medicalNeeds[m.ID] || m.Status == ResidentStatusQuarantine {
+			return RationClassMedical, fmt.Sprintf("member %s has medical needs", m.RegistryNumber)
+		}
+	}
+
+	for _, m := range living {
+		if m.Age(asOf) < InfantAgeLimit {
+			return RationClassEnhanced, fmt.Sprintf("member %s is an infant", m.RegistryNumber)
+		}
+	}
+
+	if current == RationClassMinimal || current == RationClassLaborIntensive {
+		return current, "discretionary class retained"
+	}
+
+	return RationClassStandard, "no special dietary needs"
+}
@@ -0,0 +1,106 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRationReviewTrigger_Valid(t *testing.T) {
+	tests := []struct {
+		name    string
+		trigger RationReviewTrigger
+		want    bool
+	}{
+		{"Birth is valid", RationReviewTriggerBirth, true},
+		{"Death is valid", RationReviewTriggerDeath, true},
+		{"Transfer is valid", RationReviewTriggerTransfer, true},
+		{"Manual is valid", RationReviewTriggerManual, true},
+		{"Empty string is invalid", RationReviewTrigger(""), false},
+		{"Invalid trigger", RationReviewTrigger("MARRIAGE"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.trigger.Valid(); got != tt.want {
+				t.Errorf("RationReviewTrigger.Valid() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRationClassReview_Validate(t *testing.T) {
+	valid := func() *RationClassReview {
+		return &RationClassReview{
+			ID:               "review-1",
+			HouseholdID:      "household-1",
+			CurrentClass:     RationClassStandard,
+			RecommendedClass: RationClassEnhanced,
+			Reason:           "member V076-00001 is an infant",
+			Trigger:          RationReviewTriggerBirth,
+			Status:           RationReviewStatusPending,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*RationClassReview)
+		wantErr bool
+	}{
+		{"Valid review", func(r *RationClassReview) {}, false},
+		{"Missing ID", func(r *RationClassReview) { r.ID = "" }, true},
+		{"Missing household", func(r *RationClassReview) { r.HouseholdID = "" }, true},
+		{"Invalid recommended class", func(r *RationClassReview) { r.RecommendedClass = "LUXURY" }, true},
+		{"Invalid trigger", func(r *RationClassReview) { r.Trigger = "" }, true},
+		{"Invalid status", func(r *RationClassReview) { r.Status = "MAYBE" }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			review := valid()
+			tt.modify(review)
+			err := review.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRecommendRationClass(t *testing.T) {
+	asOf := time.Date(2077, 10, 23, 0, 0, 0, 0, time.UTC)
+
+	adult := &Resident{ID: "adult", RegistryNumber: "V076-00001", DateOfBirth: time.Date(2040, 1, 1, 0, 0, 0, 0, time.UTC), Status: ResidentStatusActive}
+	infant := &Resident{ID: "infant", RegistryNumber: "V076-00002", DateOfBirth: time.Date(2077, 3, 1, 0, 0, 0, 0, time.UTC), Status: ResidentStatusActive}
+	deadInfant := &Resident{ID: "dead", RegistryNumber: "V076-00003", DateOfBirth: time.Date(2077, 3, 1, 0, 0, 0, 0, time.UTC), Status: ResidentStatusDeceased}
+	quarantined := &Resident{ID: "quarantined", RegistryNumber: "V076-00004", DateOfBirth: time.Date(2050, 1, 1, 0, 0, 0, 0, time.UTC), Status: ResidentStatusQuarantine}
+
+	tests := []struct {
+		name         string
+		current      RationClass
+		members      []*Resident
+		medicalNeeds map[string]bool
+		want         RationClass
+	}{
+		{"Adults only is standard", RationClassEnhanced, []*Resident{adult}, nil, RationClassStandard},
+		{"Infant requires enhanced", RationClassStandard, []*Resident{adult, infant}, nil, RationClassEnhanced},
+		{"Deceased infant is ignored", RationClassEnhanced, []*Resident{adult, deadInfant}, nil, RationClassStandard},
+		{"Medical needs take priority", RationClassStandard, []*Resident{adult, infant}, map[string]bool{"adult": true}, RationClassMedical},
+		{"Quarantine requires medical", RationClassStandard, []*Resident{quarantined}, nil, RationClassMedical},
+		{"Labor intensive is retained", RationClassLaborIntensive, []*Resident{adult}, nil, RationClassLaborIntensive},
+		{"Minimal is retained", RationClassMinimal, []*Resident{adult}, nil, RationClassMinimal},
+		{"Infant overrides minimal", RationClassMinimal, []*Resident{adult, infant}, nil, RationClassEnhanced},
+		{"Medical reverts to standard", RationClassMedical, []*Resident{adult}, nil, RationClassStandard},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, reason := RecommendRationClass(tt.current, tt.members, tt.medicalNeeds, asOf)
+			if got != tt.want {
+				t.Errorf("RecommendRationClass() = %v, want %v", got, tt.want)
+			}
+			if reason == "" {
+				t.Error("RecommendRationClass() returned empty reason")
+			}
+		})
+	}
+}
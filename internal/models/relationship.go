@@ -0,0 +1,113 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// RelationshipType is a relationship between residents beyond biological
+// parentage.
+type RelationshipType string
+
+const (
+	RelationshipMarriage     RelationshipType = "MARRIAGE"
+	RelationshipPartnership  RelationshipType = "PARTNERSHIP"
+	RelationshipGuardianship RelationshipType = "GUARDIANSHIP"
+	RelationshipNextOfKin    RelationshipType = "NEXT_OF_KIN"
+)
+
+// Valid returns true if the relationship type is valid.
+func (t RelationshipType) Valid() bool {
+	switch t {
+	case RelationshipMarriage, RelationshipPartnership, RelationshipGuardianship, RelationshipNextOfKin:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsUnion returns true for marriages and partnerships, where both parties
+// hold the same role and a resident has at most one at a time.
+func (t RelationshipType) IsUnion() bool {
+	return t == RelationshipMarriage || t == RelationshipPartnership
+}
+
+// Relationship is a recorded relationship between two residents.
+// ResidentID and RelatedID are the two partners of a union, the guardian and
+// ward of a guardianship, or the resident and their designated next of kin.
+type Relationship struct {
+	ID         string
+	Type       RelationshipType
+	ResidentID string
+	RelatedID  string
+	StartDate  time.Time
+	EndDate    *time.Time
+	EndReason  string
+	Notes      string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// Validate checks if the relationship data is valid.
+func (r *Relationship) Validate() error {
+	if r.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if !r.Type.Valid() {
+		return fmt.Errorf("invalid relationship type: %s", r.Type)
+	}
+	if r.ResidentID == "" || r.RelatedID == "" {
+		return fmt.Errorf("both residents are required")
+	}
+	if r.ResidentID == r.RelatedID {
+		return fmt.Errorf("a resident cannot be in a relationship with themselves")
+	}
+	if r.StartDate.IsZero() {
+		return fmt.Errorf("start_date is required")
+	}
+	if r.EndDate != nil && r.EndDate.Before(r.StartDate) {
+		return fmt.Errorf("end_date is before start_date")
+	}
+	return nil
+}
+
+// IsActive returns true if the relationship has not ended.
+func (r *Relationship) IsActive() bool {
+	return r.EndDate == nil
+}
+
+// Involves returns true if the resident is a party to the relationship.
+func (r *Relationship) Involves(residentID string) bool {
+	return r.ResidentID == residentID || r.RelatedID == residentID
+}
+
+// OtherParty returns the ID of the party that is not residentID.
+func (r *Relationship) OtherParty(residentID string) string {
+	if r.ResidentID == residentID {
+		return r.RelatedID
+	}
+	return r.ResidentID
+}
+
+// RoleOfOther names what the other party is to residentID, e.g. "Spouse",
+// "Ward" or "Next of kin".
+func (r *Relationship) RoleOfOther(residentID string) string {
+	switch r.Type {
+	case RelationshipMarriage:
+		return "Spouse"
+	case RelationshipPartnership:
+		return "Partner"
+	case RelationshipGuardianship:
+		if r.ResidentID == residentID {
+			return "Ward"
+		}
+		return "Guardian"
+	case RelationshipNextOfKin:
+		if r.ResidentID == residentID {
+			return "Next of kin"
+		}
+		return "Next of kin to"
+	default:
+		return string(r.Type)
+	}
+}
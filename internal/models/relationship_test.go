@@ -0,0 +1,73 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRelationship_Validate(t *testing.T) {
+	start := time.Date(2078, 6, 1, 0, 0, 0, 0, time.UTC)
+	before := start.AddDate(0, 0, -1)
+
+	valid := func() *Relationship {
+		return &Relationship{
+			ID:         "rel-1",
+			Type:       RelationshipMarriage,
+			ResidentID: "res-1",
+			RelatedID:  "res-2",
+			StartDate:  start,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*Relationship)
+		wantErr bool
+	}{
+		{"Valid marriage", func(r *Relationship) {}, false},
+		{"Missing ID", func(r *Relationship) { r.ID = "" }, true},
+		{"Invalid type", func(r *Relationship) { r.Type = "FRIENDSHIP" }, true},
+		{"Missing party", func(r *Relationship) { r.RelatedID = "" }, true},
+		{"Self relation", func(r *Relationship) { r.RelatedID = r.ResidentID }, true},
+		{"Missing start", func(r *Relationship) { r.StartDate = time.Time{} }, true},
+		{"Ends before start", func(r *Relationship) { r.EndDate = &before }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := valid()
+			tt.modify(r)
+			if err := r.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRelationship_RoleOfOther(t *testing.T) {
+	tests := []struct {
+		relType    RelationshipType
+		viewedFrom string
+		wantRole   string
+		wantOther  string
+	}{
+		{RelationshipMarriage, "res-2", "Spouse", "res-1"},
+		{RelationshipPartnership, "res-1", "Partner", "res-2"},
+		{RelationshipGuardianship, "res-1", "Ward", "res-2"},
+		{RelationshipGuardianship, "res-2", "Guardian", "res-1"},
+		{RelationshipNextOfKin, "res-1", "Next of kin", "res-2"},
+		{RelationshipNextOfKin, "res-2", "Next of kin to", "res-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.relType)+" from "+tt.viewedFrom, func(t *testing.T) {
+			r := &Relationship{Type: tt.relType, ResidentID: "res-1", RelatedID: "res-2"}
+			if got := r.RoleOfOther(tt.viewedFrom); got != tt.wantRole {
+				t.Errorf("RoleOfOther() = %q, want %q", got, tt.wantRole)
+			}
+			if got := r.OtherParty(tt.viewedFrom); got != tt.wantOther {
+				t.Errorf("OtherParty() = %q, want %q", got, tt.wantOther)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// QuantityEpsilon absorbs floating-point error when stock quantities are
+// split and summed; quantities closer than this are equal.
+const QuantityEpsilon = 1e-6
+
+// ReservationStatus represents the state of a stock reservation.
+type ReservationStatus string
+
+const (
+	ReservationStatusActive    ReservationStatus = "ACTIVE"
+	ReservationStatusCommitted ReservationStatus = "COMMITTED"
+	ReservationStatusReleased  ReservationStatus = "RELEASED"
+	ReservationStatusExpired   ReservationStatus = "EXPIRED"
+)
+
+// Valid returns true if the reservation status is valid.
+func (s ReservationStatus) Valid() bool {
+	switch s {
+	case ReservationStatusActive, ReservationStatusCommitted, ReservationStatusReleased, ReservationStatusExpired:
+		return true
+	default:
+		return false
+	}
+}
+
+// StockReservation holds item quantity for planned consumption until it is
+// committed, released or expires.
+type StockReservation struct {
+	ID                string
+	ItemID            string
+	Quantity          float64
+	Purpose           string  // "Appendectomy, V076-00123"
+	RelatedEntityType *string // 'RESIDENT', 'FACILITY', etc.
+	RelatedEntityID   *string
+	ReservedBy        *string
+	Status            ReservationStatus
+	ExpiresAt         time.Time
+	ClosedAt          *time.Time
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+
+	// Stock lots the quantity is held in
+	Allocations []ReservationAllocation
+}
+
+// ReservationAllocation is the part of a reservation held in one stock lot.
+type ReservationAllocation struct {
+	StockID  string
+	Quantity float64
+}
+
+// Validate checks if the reservation data is valid.
+func (r *StockReservation) Validate() error {
+	if r.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if r.ItemID == "" {
+		return fmt.Errorf("item_id is required")
+	}
+	if r.Quantity <= 0 {
+		return fmt.Errorf("quantity must be positive")
+	}
+	if r.Purpose == "" {
+		return fmt.Errorf("purpose is required")
+	}
+	if !r.Status.Valid() {
+		return fmt.Errorf("invalid status: %s", r.Status)
+	}
+	if r.ExpiresAt.IsZero() {
+		return fmt.Errorf("expires_at is required")
+	}
+
+	var allocated float64
+	for _, a := range r.Allocations {
+		if a.StockID == "" || a.Quantity <= 0 {
+			return fmt.Errorf("allocations need a stock_id and a positive quantity")
+		}
+		allocated += a.Quantity
+	}
+	if len(r.Allocations) > 0 && math.Abs(allocated-r.Quantity) > QuantityEpsilon {
+		return fmt.Errorf("allocations total %.2f, reservation is for %.2f", allocated, r.Quantity)
+	}
+	return nil
+}
+
+// IsActive returns true if the reservation still holds stock.
+func (r *StockReservation) IsActive() bool {
+	return r.Status == ReservationStatusActive
+}
+
+// IsExpired returns true if an active reservation has passed its expiry.
+func (r *StockReservation) IsExpired(now time.Time) bool {
+	return r.IsActive() && !now.Before(r.ExpiresAt)
+}
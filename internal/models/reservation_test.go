@@ -0,0 +1,99 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReservationStatus_Valid(t *testing.T) {
+	tests := []struct {
+		name   string
+		status ReservationStatus
+		want   bool
+	}{
+		{"Active is valid", ReservationStatusActive, true},
+		{"Committed is valid", ReservationStatusCommitted, true},
+		{"Released is valid", ReservationStatusReleased, true},
+		{"Expired is valid", ReservationStatusExpired, true},
+		{"Empty string is invalid", ReservationStatus(""), false},
+		{"Invalid status", ReservationStatus("PENDING"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.status.Valid(); got != tt.want {
+				t.Errorf("ReservationStatus.Valid() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStockReservation_Validate(t *testing.T) {
+	valid := func() *StockReservation {
+		return &StockReservation{
+			ID:        "reservation-1",
+			ItemID:    "item-1",
+			Quantity:  10.3,
+			Purpose:   "Appendectomy",
+			Status:    ReservationStatusActive,
+			ExpiresAt: time.Date(2077, 10, 26, 9, 0, 0, 0, time.UTC),
+			Allocations: []ReservationAllocation{
+				{StockID: "stock-1", Quantity: 5.1},
+				{StockID: "stock-2", Quantity: 5.2},
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*StockReservation)
+		wantErr bool
+	}{
+		{"Valid reservation", func(r *StockReservation) {}, false},
+		{"No allocations yet", func(r *StockReservation) { r.Allocations = nil }, false},
+		{"Missing ID", func(r *StockReservation) { r.ID = "" }, true},
+		{"Missing item", func(r *StockReservation) { r.ItemID = "" }, true},
+		{"Zero quantity", func(r *StockReservation) { r.Quantity = 0 }, true},
+		{"Missing purpose", func(r *StockReservation) { r.Purpose = "" }, true},
+		{"Invalid status", func(r *StockReservation) { r.Status = "PENDING" }, true},
+		{"Missing expiry", func(r *StockReservation) { r.ExpiresAt = time.Time{} }, true},
+		{"Allocations short", func(r *StockReservation) { r.Allocations = r.Allocations[:1] }, true},
+		{"Allocation without stock", func(r *StockReservation) { r.Allocations[0].StockID = "" }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := valid()
+			tt.modify(res)
+			err := res.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStockReservation_IsExpired(t *testing.T) {
+	expires := time.Date(2077, 10, 26, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		status ReservationStatus
+		now    time.Time
+		want   bool
+	}{
+		{"Active before expiry", ReservationStatusActive, expires.Add(-time.Minute), false},
+		{"Active at expiry", ReservationStatusActive, expires, true},
+		{"Active after expiry", ReservationStatusActive, expires.Add(time.Hour), true},
+		{"Committed after expiry", ReservationStatusCommitted, expires.Add(time.Hour), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := &StockReservation{Status: tt.status, ExpiresAt: expires}
+			if got := res.IsExpired(tt.now); got != tt.want {
+				t.Errorf("IsExpired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
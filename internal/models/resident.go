@@ -97,6 +97,12 @@ func (s ResidentStatus) IsAlive() bool {
 	return s != ResidentStatusDeceased
 }
 
+// IsTemporary returns true for statuses that are scheduled with an expected
+// end and revert to ACTIVE.
+func (s ResidentStatus) IsTemporary() bool {
+	return s == ResidentStatusQuarantine || s == ResidentStatusSurfaceMission
+}
+
 // Resident represents a vault dweller.
 type Resident struct {
 	// Identity
@@ -125,6 +131,7 @@ type Resident struct {
 	QuartersID        *string `json:"quarters_id,omitempty"`
 	PrimaryVocationID *string `json:"primary_vocation_id,omitempty"`
 	ClearanceLevel    int     `json:"clearance_level"`
+	VaultID           int     `json:"vault_id"`
 
 	// Metadata
 	Notes     string    `json:"notes,omitempty"`
@@ -215,14 +222,62 @@ func (r *Resident) Validate() error {
 
 // ResidentFilter defines filtering options for resident queries.
 type ResidentFilter struct {
-	Status      *ResidentStatus
-	HouseholdID *string
-	VocationID  *string
-	Sex         *Sex
-	MinAge      *int
-	MaxAge      *int
-	SearchTerm  string // Searches surname and given_names
-	EntryType   *EntryType
+	Status        *ResidentStatus `json:"status,omitempty"`
+	HouseholdID   *string         `json:"household_id,omitempty"`
+	VocationID    *string         `json:"vocation_id,omitempty"`
+	VocationCode  string          `json:"vocation_code,omitempty"` // Primary vocation, by code
+	Sex           *Sex            `json:"sex,omitempty"`
+	MinAge        *int            `json:"min_age,omitempty"`
+	MaxAge        *int            `json:"max_age,omitempty"`
+	MinClearance  *int            `json:"min_clearance,omitempty"`
+	MaxClearance  *int            `json:"max_clearance,omitempty"`
+	HouseholdType *HouseholdType  `json:"household_type,omitempty"`
+	Sector        string          `json:"sector,omitempty"`      // Sector of the quarters housing the resident, their own or their household's
+	SearchTerm    string          `json:"search_term,omitempty"` // Searches surname, given_names and registry_number
+	EntryType     *EntryType      `json:"entry_type,omitempty"`
+	Tags          []string        `json:"tags,omitempty"` // Residents carrying every one of the tags
+
+	// AsOf is the date ages are reckoned at, the vault date when zero. It
+	// is not saved with a census preset.
+	AsOf time.Time `json:"-"`
+}
+
+// Validate checks that the filter's ranges are in order and its values
+// known.
+func (f *ResidentFilter) Validate() error {
+	if f.MinAge != nil && *f.MinAge < 0 {
+		return fmt.Errorf("min_age cannot be negative")
+	}
+	if f.MaxAge != nil && *f.MaxAge < 0 {
+		return fmt.Errorf("max_age cannot be negative")
+	}
+	if f.MinAge != nil && f.MaxAge != nil && *f.MinAge > *f.MaxAge {
+		return fmt.Errorf("min_age %d is above max_age %d", *f.MinAge, *f.MaxAge)
+	}
+	for _, c := range []*int{f.MinClearance, f.MaxClearance} {
+		if c != nil && (*c < 1 || *c > 10) {
+			return fmt.Errorf("clearance must be between 1 and 10")
+		}
+	}
+	if f.MinClearance != nil && f.MaxClearance != nil && *f.MinClearance > *f.MaxClearance {
+		return fmt.Errorf("min_clearance %d is above max_clearance %d", *f.MinClearance, *f.MaxClearance)
+	}
+	if f.HouseholdType != nil && !f.HouseholdType.Valid() {
+		return fmt.Errorf("invalid household_type: %s", *f.HouseholdType)
+	}
+	for _, tag := range f.Tags {
+		if _, err := NormalizeTag(tag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ResidentSortColumns are the sort keys accepted by resident lists, in the
+// order the census cycles through them.
+var ResidentSortColumns = []string{
+	"registry_number", "surname", "given_names", "age", "sex",
+	"blood_type", "status", "entry_type", "clearance_level",
 }
 
 // ResidentList represents a paginated list of residents.
@@ -232,4 +287,5 @@ type ResidentList struct {
 	Page       int
 	PageSize   int
 	TotalPages int
+	NextCursor string // Pagination.After for the next page; empty on the last
 }
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"fmt"
+	"strings"
 	"time"
 )
 
@@ -16,6 +18,37 @@ type ResourceCategory struct {
 	CreatedAt     time.Time
 }
 
+// Validate checks the category's required fields and code.
+func (c *ResourceCategory) Validate() error {
+	if c.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if err := validCatalogCode("code", c.Code); err != nil {
+		return err
+	}
+	if strings.TrimSpace(c.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if strings.TrimSpace(c.UnitOfMeasure) == "" {
+		return fmt.Errorf("unit_of_measure is required")
+	}
+	return nil
+}
+
+// validCatalogCode checks a category or item code: upper-case letters,
+// digits, hyphens and underscores, e.g. FOOD or FOOD-PROTEIN-001.
+func validCatalogCode(field, code string) error {
+	if code == "" {
+		return fmt.Errorf("%s is required", field)
+	}
+	for _, r := range code {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '-' && r != '_' {
+			return fmt.Errorf("%s %q may only hold A-Z, 0-9, - and _", field, code)
+		}
+	}
+	return nil
+}
+
 // ResourceItem represents a specific resource item within a category.
 type ResourceItem struct {
 	ID                   string
@@ -24,11 +57,14 @@ type ResourceItem struct {
 	Name                 string
 	Description          string
 	UnitOfMeasure        string
-	CaloriesPerUnit      *float64 // For food items
-	ShelfLifeDays        *int     // NULL for non-perishables
-	StorageRequirements  string   // JSON: {"temp_max_c": 4, "humidity_max_pct": 60}
-	IsProducible         bool     // Can vault produce this?
-	ProductionRatePerDay *float64 // If producible
+	CaloriesPerUnit      *float64   // For food items
+	Nutrients            *Nutrients // Per unit, for food items; nil if not recorded
+	ShelfLifeDays        *int       // NULL for non-perishables
+	StorageRequirements  string     // JSON: {"temp_max_c": 4, "humidity_max_pct": 60}
+	IsProducible         bool       // Can vault produce this?
+	ProductionRatePerDay *float64   // If producible
+	MinStock             *float64   // Stock a vault keeps at least, NULL for none
+	TargetStock          *float64   // Stock a shortage is restored to, NULL for the minimum
 	CreatedAt            time.Time
 	UpdatedAt            time.Time
 
@@ -36,6 +72,72 @@ type ResourceItem struct {
 	Category *ResourceCategory
 }
 
+// Validate checks the item's required fields, code and quantities. Only a
+// producible item has a production rate, and only an item with a minimum
+// stock a target, no lower than the minimum.
+func (i *ResourceItem) Validate() error {
+	if i.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if i.CategoryID == "" {
+		return fmt.Errorf("category_id is required")
+	}
+	if err := validCatalogCode("item_code", i.ItemCode); err != nil {
+		return err
+	}
+	if strings.TrimSpace(i.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if strings.TrimSpace(i.UnitOfMeasure) == "" {
+		return fmt.Errorf("unit_of_measure is required")
+	}
+	if i.CaloriesPerUnit != nil && *i.CaloriesPerUnit < 0 {
+		return fmt.Errorf("calories_per_unit cannot be negative")
+	}
+	if i.Nutrients != nil {
+		if err := i.Nutrients.Validate(); err != nil {
+			return fmt.Errorf("nutrients: %w", err)
+		}
+	}
+	if i.ShelfLifeDays != nil && *i.ShelfLifeDays < 1 {
+		return fmt.Errorf("shelf_life_days must be at least 1")
+	}
+	if i.ProductionRatePerDay != nil {
+		if !i.IsProducible {
+			return fmt.Errorf("production_rate_per_day requires a producible item")
+		}
+		if *i.ProductionRatePerDay <= 0 {
+			return fmt.Errorf("production_rate_per_day must be positive")
+		}
+	}
+	if i.MinStock != nil && *i.MinStock < 0 {
+		return fmt.Errorf("min_stock cannot be negative")
+	}
+	if i.TargetStock != nil {
+		if i.MinStock == nil {
+			return fmt.Errorf("target_stock requires a min_stock")
+		}
+		if *i.TargetStock < *i.MinStock {
+			return fmt.Errorf("target_stock cannot be below min_stock")
+		}
+	}
+	return nil
+}
+
+// Shortfall returns how much stock would restore the item to its target,
+// or to its minimum without one, when the available quantity is below the
+// minimum; 0 when it is not, or the item has no minimum.
+func (i *ResourceItem) Shortfall(available float64) float64 {
+	if i.MinStock == nil || available >= *i.MinStock-QuantityEpsilon {
+		return 0
+	}
+	target := *i.MinStock
+	if i.TargetStock != nil {
+		target = *i.TargetStock
+	}
+	return target - available
+}
+
 // StockStatus represents the status of a resource stock.
 type StockStatus string
 
@@ -45,12 +147,35 @@ const (
 	StockStatusQuarantine StockStatus = "QUARANTINE"
 	StockStatusExpired    StockStatus = "EXPIRED"
 	StockStatusDepleted   StockStatus = "DEPLETED"
+	StockStatusDamaged    StockStatus = "DAMAGED"
 )
 
 func (s StockStatus) String() string {
 	return string(s)
 }
 
+// ConsumptionPolicy decides which lots consumption draws from first.
+type ConsumptionPolicy string
+
+const (
+	ConsumptionPolicyFIFO ConsumptionPolicy = "FIFO" // First in, first out: oldest received first
+	ConsumptionPolicyFEFO ConsumptionPolicy = "FEFO" // First expired, first out: soonest-expiring first
+)
+
+// Valid returns true if the consumption policy is valid.
+func (p ConsumptionPolicy) Valid() bool {
+	return p == ConsumptionPolicyFIFO || p == ConsumptionPolicyFEFO
+}
+
+// StockOrder returns the stock list order the policy draws lots in. Lots
+// without an expiration date are drawn last under FEFO.
+func (p ConsumptionPolicy) StockOrder() SortOption {
+	if p == ConsumptionPolicyFIFO {
+		return SortOption{Column: "received_date"}
+	}
+	return SortOption{Column: "expiration_date"}
+}
+
 // ResourceStock represents inventory of a specific resource item.
 type ResourceStock struct {
 	ID               string
@@ -64,6 +189,7 @@ type ResourceStock struct {
 	Status           StockStatus
 	LastAuditDate    *time.Time
 	LastAuditBy      *string
+	VaultID          int // Number of the vault holding the stock
 	CreatedAt        time.Time
 	UpdatedAt        time.Time
 
@@ -150,6 +276,20 @@ type TransactionFilter struct {
 	RelatedEntityID   string
 }
 
+// StockSortColumns are the sort keys accepted by stock lists. The default
+// order is soonest-expiring first.
+var StockSortColumns = []string{
+	"item_code", "name", "category", "quantity", "unit",
+	"status", "expiration_date", "storage_location", "received_date",
+}
+
+// ItemSortColumns are the sort keys accepted by item lists.
+var ItemSortColumns = []string{"item_code", "name", "unit", "shelf_life_days"}
+
+// TransactionSortColumns are the sort keys accepted by transaction lists.
+// The default order is most recent first.
+var TransactionSortColumns = []string{"timestamp", "item_code", "transaction_type", "quantity"}
+
 // StockList represents a paginated list of stocks.
 type StockList struct {
 	Stocks     []*ResourceStock
@@ -164,6 +304,7 @@ type TransactionList struct {
 	Total        int
 	Page         int
 	TotalPages   int
+	NextCursor   string // Pagination.After for the next page; empty on the last
 }
 
 // ItemList represents a paginated list of resource items.
@@ -176,9 +317,10 @@ type ItemList struct {
 
 // DailyRequirements represents the vault's daily resource requirements.
 type DailyRequirements struct {
-	TotalCalories float64
-	TotalWaterL   float64
-	ByHousehold   map[string]HouseholdRequirement
+	TotalCalories  float64
+	TotalWaterL    float64
+	TotalNutrients Nutrients
+	ByHousehold    map[string]HouseholdRequirement
 }
 
 // HouseholdRequirement represents a single household's requirements.
@@ -188,6 +330,8 @@ type HouseholdRequirement struct {
 	MemberCount int
 	CaloriesDay float64
 	WaterLDay   float64
+	Nutrients   Nutrients          // Daily nutrient targets of all members
+	Departments map[Department]int // Members by primary vocation's department, "" for none
 }
 
 // RunwayProjection represents how long resources will last.
@@ -16,6 +16,7 @@ func TestStockStatus_String(t *testing.T) {
 		{"Quarantine", StockStatusQuarantine, "QUARANTINE"},
 		{"Expired", StockStatusExpired, "EXPIRED"},
 		{"Depleted", StockStatusDepleted, "DEPLETED"},
+		{"Damaged", StockStatusDamaged, "DAMAGED"},
 	}
 
 	for _, tt := range tests {
@@ -27,6 +28,29 @@ func TestStockStatus_String(t *testing.T) {
 	}
 }
 
+func TestConsumptionPolicy(t *testing.T) {
+	tests := []struct {
+		policy ConsumptionPolicy
+		valid  bool
+		column string
+	}{
+		{ConsumptionPolicyFIFO, true, "received_date"},
+		{ConsumptionPolicyFEFO, true, "expiration_date"},
+		{ConsumptionPolicy("LIFO"), false, "expiration_date"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			if got := tt.policy.Valid(); got != tt.valid {
+				t.Errorf("Valid() = %v, want %v", got, tt.valid)
+			}
+			if got := tt.policy.StockOrder(); got.Column != tt.column || got.Descending() {
+				t.Errorf("StockOrder() = %+v, want %s ascending", got, tt.column)
+			}
+		})
+	}
+}
+
 func TestResourceStock_AvailableQuantity(t *testing.T) {
 	tests := []struct {
 		name             string
@@ -188,6 +212,102 @@ func TestTransactionType_String(t *testing.T) {
 	}
 }
 
+func TestResourceCategory_Validate(t *testing.T) {
+	valid := func() *ResourceCategory {
+		return &ResourceCategory{ID: "cat-1", Code: "FOOD", Name: "Food", UnitOfMeasure: "kg"}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*ResourceCategory)
+		wantErr bool
+	}{
+		{"Valid category", func(c *ResourceCategory) {}, false},
+		{"Underscore in code", func(c *ResourceCategory) { c.Code = "SPARE_PARTS" }, false},
+		{"Missing code", func(c *ResourceCategory) { c.Code = "" }, true},
+		{"Lower-case code", func(c *ResourceCategory) { c.Code = "food" }, true},
+		{"Space in code", func(c *ResourceCategory) { c.Code = "DRY FOOD" }, true},
+		{"Missing name", func(c *ResourceCategory) { c.Name = " " }, true},
+		{"Missing unit", func(c *ResourceCategory) { c.UnitOfMeasure = "" }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := valid()
+			tt.modify(c)
+			if err := c.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResourceItem_Validate(t *testing.T) {
+	valid := func() *ResourceItem {
+		return &ResourceItem{ID: "item-1", CategoryID: "cat-1", ItemCode: "FOOD-PROTEIN-001", Name: "Protein paste", UnitOfMeasure: "kg"}
+	}
+	rate, zero, negative := 2.5, 0.0, -1.0
+	days, noDays := 365, 0
+
+	tests := []struct {
+		name    string
+		modify  func(*ResourceItem)
+		wantErr bool
+	}{
+		{"Valid item", func(i *ResourceItem) {}, false},
+		{"Missing category", func(i *ResourceItem) { i.CategoryID = "" }, true},
+		{"Invalid code", func(i *ResourceItem) { i.ItemCode = "food-001" }, true},
+		{"Zero calories", func(i *ResourceItem) { i.CaloriesPerUnit = &zero }, false},
+		{"Negative calories", func(i *ResourceItem) { i.CaloriesPerUnit = &negative }, true},
+		{"Nutrients", func(i *ResourceItem) { i.Nutrients = &Nutrients{ProteinG: 300, FatG: 120} }, false},
+		{"Negative nutrients", func(i *ResourceItem) { i.Nutrients = &Nutrients{CarbsG: -5} }, true},
+		{"Shelf life", func(i *ResourceItem) { i.ShelfLifeDays = &days }, false},
+		{"Zero shelf life", func(i *ResourceItem) { i.ShelfLifeDays = &noDays }, true},
+		{"Producible with rate", func(i *ResourceItem) { i.IsProducible = true; i.ProductionRatePerDay = &rate }, false},
+		{"Producible without rate", func(i *ResourceItem) { i.IsProducible = true }, false},
+		{"Rate without producible", func(i *ResourceItem) { i.ProductionRatePerDay = &rate }, true},
+		{"Zero rate", func(i *ResourceItem) { i.IsProducible = true; i.ProductionRatePerDay = &zero }, true},
+		{"Minimum and target", func(i *ResourceItem) { i.MinStock = &rate; i.TargetStock = &rate }, false},
+		{"Negative minimum", func(i *ResourceItem) { i.MinStock = &negative }, true},
+		{"Target without minimum", func(i *ResourceItem) { i.TargetStock = &rate }, true},
+		{"Target below minimum", func(i *ResourceItem) { i.MinStock = &rate; i.TargetStock = &zero }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := valid()
+			tt.modify(i)
+			if err := i.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResourceItem_Shortfall(t *testing.T) {
+	min, target := 100.0, 250.0
+	tests := []struct {
+		name      string
+		item      ResourceItem
+		available float64
+		want      float64
+	}{
+		{"No minimum", ResourceItem{}, 0, 0},
+		{"At minimum", ResourceItem{MinStock: &min}, 100, 0},
+		{"Below minimum", ResourceItem{MinStock: &min}, 40, 60},
+		{"Below minimum with target", ResourceItem{MinStock: &min, TargetStock: &target}, 40, 210},
+		{"Above minimum with target", ResourceItem{MinStock: &min, TargetStock: &target}, 180, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.item.Shortfall(tt.available); got != tt.want {
+				t.Errorf("Shortfall(%v) = %v, want %v", tt.available, got, tt.want)
+			}
+		})
+	}
+}
+
 // Helper function for tests
 func timePtr(t time.Time) *time.Time {
 	return &t
@@ -0,0 +1,106 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// IncidentType represents the category of a security incident.
+type IncidentType string
+
+const (
+	IncidentTypeAltercation        IncidentType = "ALTERCATION"
+	IncidentTypeTheft              IncidentType = "THEFT"
+	IncidentTypeVandalism          IncidentType = "VANDALISM"
+	IncidentTypeUnauthorizedAccess IncidentType = "UNAUTHORIZED_ACCESS"
+	IncidentTypeContraband         IncidentType = "CONTRABAND"
+	IncidentTypeInsubordination    IncidentType = "INSUBORDINATION"
+	IncidentTypeAssault            IncidentType = "ASSAULT"
+	IncidentTypeOther              IncidentType = "OTHER"
+)
+
+// Valid returns true if the incident type is valid.
+func (t IncidentType) Valid() bool {
+	switch t {
+	case IncidentTypeAltercation, IncidentTypeTheft, IncidentTypeVandalism,
+		IncidentTypeUnauthorizedAccess, IncidentTypeContraband,
+		IncidentTypeInsubordination, IncidentTypeAssault, IncidentTypeOther:
+		return true
+	default:
+		return false
+	}
+}
+
+// Severity is a four-level severity scale shared by incidents and findings.
+type Severity string
+
+const (
+	SeverityMinor    Severity = "MINOR"
+	SeverityModerate Severity = "MODERATE"
+	SeverityMajor    Severity = "MAJOR"
+	SeverityCritical Severity = "CRITICAL"
+)
+
+// Valid returns true if the severity is valid.
+func (s Severity) Valid() bool {
+	switch s {
+	case SeverityMinor, SeverityModerate, SeverityMajor, SeverityCritical:
+		return true
+	default:
+		return false
+	}
+}
+
+// IncidentStatus represents the investigation state of an incident.
+type IncidentStatus string
+
+const (
+	IncidentStatusOpen          IncidentStatus = "OPEN"
+	IncidentStatusInvestigating IncidentStatus = "INVESTIGATING"
+	IncidentStatusPendingReview IncidentStatus = "PENDING_REVIEW"
+	IncidentStatusResolved      IncidentStatus = "RESOLVED"
+	IncidentStatusClosed        IncidentStatus = "CLOSED"
+)
+
+// SecurityIncident represents a reported security incident.
+type SecurityIncident struct {
+	ID             string         `json:"id"`
+	IncidentNumber string         `json:"incident_number"`
+	IncidentType   IncidentType   `json:"incident_type"`
+	Severity       Severity       `json:"severity"`
+	Description    string         `json:"description"`
+	LocationSector string         `json:"location_sector,omitempty"`
+	LocationDetail string         `json:"location_detail,omitempty"`
+	ReportedBy     *string        `json:"reported_by,omitempty"`
+	Status         IncidentStatus `json:"status"`
+	OccurredAt     time.Time      `json:"occurred_at"`
+	ReportedAt     time.Time      `json:"reported_at"`
+	Notes          string         `json:"notes,omitempty"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+// Validate checks if the incident data is valid.
+func (i *SecurityIncident) Validate() error {
+	if i.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if i.IncidentNumber == "" {
+		return fmt.Errorf("incident_number is required")
+	}
+	if !i.IncidentType.Valid() {
+		return fmt.Errorf("invalid incident_type: %s", i.IncidentType)
+	}
+	if !i.Severity.Valid() {
+		return fmt.Errorf("invalid severity: %s", i.Severity)
+	}
+	if i.Description == "" {
+		return fmt.Errorf("description is required")
+	}
+	return nil
+}
+
+// IsOpen returns true if the incident is not yet resolved or closed.
+func (i *SecurityIncident) IsOpen() bool {
+	return i.Status != IncidentStatusResolved && i.Status != IncidentStatusClosed
+}
@@ -0,0 +1,137 @@
+package models
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// DefaultSessionDays is how many days of terminal sessions the session
+// activity summary covers by default.
+const DefaultSessionDays = 7
+
+// TerminalSession is a stretch of time one operator, or no one, used a
+// terminal: the modules opened and the changes made from it. A terminal
+// starts a session on opening and on every change of operator or vault, and
+// ends it on closing.
+type TerminalSession struct {
+	ID               string     `json:"id"`
+	OperatorID       *string    `json:"operator_id,omitempty"` // Nil before anyone signed on, or once the operator's record was deleted
+	OperatorRegistry string     `json:"operator_registry,omitempty"`
+	OperatorName     string     `json:"operator_name,omitempty"`
+	StartedAt        time.Time  `json:"started_at"`
+	LastActiveAt     time.Time  `json:"last_active_at"`     // When the session was last saved
+	EndedAt          *time.Time `json:"ended_at,omitempty"` // Nil while open, or when the terminal never closed it
+	Modules          []string   `json:"modules"`            // In the order first opened
+	Mutations        int        `json:"mutations"`          // Changes made that succeeded
+	VaultID          int        `json:"vault_id"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+// Validate checks if the session data is valid.
+func (s *TerminalSession) Validate() error {
+	if s.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if s.StartedAt.IsZero() {
+		return fmt.Errorf("started_at is required")
+	}
+	if s.LastActiveAt.Before(s.StartedAt) {
+		return fmt.Errorf("last_active_at cannot be before started_at")
+	}
+	if s.EndedAt != nil && s.EndedAt.Before(s.StartedAt) {
+		return fmt.Errorf("ended_at cannot be before started_at")
+	}
+	if s.Mutations < 0 {
+		return fmt.Errorf("mutations cannot be negative")
+	}
+	return nil
+}
+
+// Duration returns how long the session lasted, up to its end or, for a
+// session never closed, up to when it was last saved.
+func (s *TerminalSession) Duration() time.Duration {
+	end := s.LastActiveAt
+	if s.EndedAt != nil {
+		end = *s.EndedAt
+	}
+	return end.Sub(s.StartedAt)
+}
+
+// OperatorActivity summarizes the sessions of one operator, or of the
+// terminals used with no one signed on.
+type OperatorActivity struct {
+	OperatorID *string // Nil for sessions with no operator
+	Registry   string
+	Name       string
+	Sessions   int
+	Unclosed   int // Sessions whose terminal never closed them, e.g. on a crash
+	Duration   time.Duration
+	Mutations  int
+	Modules    []string // Opened in any session, most sessions first
+	LastActive time.Time
+}
+
+// SummarizeSessions totals sessions by operator, the operator last active
+// first and sessions with no operator last. Open is the ID of a session
+// still in progress, which is not counted as unclosed.
+func SummarizeSessions(sessions []*TerminalSession, open string) []*OperatorActivity {
+	byOperator := make(map[string]*OperatorActivity)
+	visits := make(map[string]map[string]int)
+	var order []string
+	for _, s := range sessions {
+		key := ""
+		if s.OperatorID != nil {
+			key = *s.OperatorID
+		}
+		activity, ok := byOperator[key]
+		if !ok {
+			activity = &OperatorActivity{
+				OperatorID: s.OperatorID,
+				Registry:   s.OperatorRegistry,
+				Name:       s.OperatorName,
+			}
+			byOperator[key] = activity
+			visits[key] = make(map[string]int)
+			order = append(order, key)
+		}
+		activity.Sessions++
+		if s.EndedAt == nil && s.ID != open {
+			activity.Unclosed++
+		}
+		activity.Duration += s.Duration()
+		activity.Mutations += s.Mutations
+		for _, m := range s.Modules {
+			visits[key][m]++
+		}
+		if s.LastActiveAt.After(activity.LastActive) {
+			activity.LastActive = s.LastActiveAt
+		}
+	}
+
+	summary := make([]*OperatorActivity, 0, len(order))
+	for _, key := range order {
+		activity := byOperator[key]
+		for m := range visits[key] {
+			activity.Modules = append(activity.Modules, m)
+		}
+		slices.SortFunc(activity.Modules, func(a, b string) int {
+			if c := cmp.Compare(visits[key][b], visits[key][a]); c != 0 {
+				return c
+			}
+			return cmp.Compare(a, b)
+		})
+		summary = append(summary, activity)
+	}
+	slices.SortStableFunc(summary, func(a, b *OperatorActivity) int {
+		if (a.OperatorID == nil) != (b.OperatorID == nil) {
+			if a.OperatorID == nil {
+				return 1
+			}
+			return -1
+		}
+		return b.LastActive.Compare(a.LastActive)
+	})
+	return summary
+}
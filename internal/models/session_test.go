@@ -0,0 +1,105 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTerminalSessionValidate(t *testing.T) {
+	start := time.Date(2077, 10, 23, 8, 0, 0, 0, time.UTC)
+	before := start.Add(-time.Minute)
+
+	tests := []struct {
+		name    string
+		session TerminalSession
+		wantErr bool
+	}{
+		{"Valid", TerminalSession{ID: "s1", StartedAt: start, LastActiveAt: start}, false},
+		{"Missing ID", TerminalSession{StartedAt: start, LastActiveAt: start}, true},
+		{"Missing start", TerminalSession{ID: "s1"}, true},
+		{"Active before start", TerminalSession{ID: "s1", StartedAt: start, LastActiveAt: before}, true},
+		{"Ended before start", TerminalSession{ID: "s1", StartedAt: start, LastActiveAt: start, EndedAt: &before}, true},
+		{"Negative mutations", TerminalSession{ID: "s1", StartedAt: start, LastActiveAt: start, Mutations: -1}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.session.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTerminalSessionDuration(t *testing.T) {
+	start := time.Date(2077, 10, 23, 8, 0, 0, 0, time.UTC)
+	end := start.Add(2 * time.Hour)
+	s := &TerminalSession{StartedAt: start, LastActiveAt: start.Add(30 * time.Minute)}
+	if got := s.Duration(); got != 30*time.Minute {
+		t.Errorf("Duration() of an unclosed session = %v, want 30m", got)
+	}
+	s.EndedAt = &end
+	if got := s.Duration(); got != 2*time.Hour {
+		t.Errorf("Duration() = %v, want 2h", got)
+	}
+}
+
+func TestSummarizeSessions(t *testing.T) {
+	start := time.Date(2077, 10, 23, 8, 0, 0, 0, time.UTC)
+	alice, bob := "r-alice", "r-bob"
+	session := func(id string, operator *string, offset, length time.Duration, closed bool, mutations int, modules ...string) *TerminalSession {
+		s := &TerminalSession{
+			ID:           id,
+			OperatorID:   operator,
+			StartedAt:    start.Add(offset),
+			LastActiveAt: start.Add(offset + length),
+			Modules:      modules,
+			Mutations:    mutations,
+		}
+		if operator != nil {
+			s.OperatorRegistry = "V076-" + *operator
+		}
+		if closed {
+			end := s.LastActiveAt
+			s.EndedAt = &end
+		}
+		return s
+	}
+	sessions := []*TerminalSession{
+		session("s1", nil, 0, time.Hour, true, 0, "dashboard"),
+		session("s2", &alice, time.Hour, time.Hour, true, 3, "population", "resources"),
+		session("s3", &bob, 2*time.Hour, time.Hour, false, 1, "security"),
+		session("s4", &alice, 4*time.Hour, 30*time.Minute, false, 2, "resources"),
+		session("s5", nil, 5*time.Hour, time.Hour, false, 0, "dashboard"),
+	}
+
+	got := SummarizeSessions(sessions, "s4")
+	if len(got) != 3 {
+		t.Fatalf("SummarizeSessions() = %d operators, want 3", len(got))
+	}
+	if got[0].OperatorID == nil || *got[0].OperatorID != alice {
+		t.Fatalf("first operator = %v, want the one last active", got[0].OperatorID)
+	}
+	if got[2].OperatorID != nil {
+		t.Errorf("last row = %v, want the sessions with no operator", got[2].OperatorID)
+	}
+
+	a := got[0]
+	if a.Sessions != 2 || a.Mutations != 5 || a.Duration != 90*time.Minute {
+		t.Errorf("alice = %d sessions, %d mutations, %v; want 2, 5, 1h30m", a.Sessions, a.Mutations, a.Duration)
+	}
+	if a.Unclosed != 0 {
+		t.Errorf("alice unclosed = %d, want 0 for the session in progress", a.Unclosed)
+	}
+	if len(a.Modules) != 2 || a.Modules[0] != "resources" {
+		t.Errorf("alice modules = %v, want resources first", a.Modules)
+	}
+	if !a.LastActive.Equal(start.Add(270 * time.Minute)) {
+		t.Errorf("alice last active = %v", a.LastActive)
+	}
+	if got[1].Unclosed != 1 {
+		t.Errorf("bob unclosed = %d, want 1", got[1].Unclosed)
+	}
+	if got[2].Sessions != 2 || got[2].Unclosed != 1 {
+		t.Errorf("no operator = %d sessions, %d unclosed; want 2, 1", got[2].Sessions, got[2].Unclosed)
+	}
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// StatusTransition is one scheduled period of a temporary resident status,
+// such as a quarantine or surface mission, from status_history.
+type StatusTransition struct {
+	ID          string         `json:"id"`
+	ResidentID  string         `json:"resident_id"`
+	Status      ResidentStatus `json:"status"`
+	Reason      string         `json:"reason,omitempty"`
+	StartedAt   time.Time      `json:"started_at"`
+	ExpectedEnd time.Time      `json:"expected_end"` // Last day of the period
+	AutoRevert  bool           `json:"auto_revert"`  // Return to ACTIVE without asking the operator
+	PromptedAt  *time.Time     `json:"prompted_at,omitempty"`
+	EndedAt     *time.Time     `json:"ended_at,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+// Validate checks if the transition data is valid.
+func (t *StatusTransition) Validate() error {
+	if t.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if t.ResidentID == "" {
+		return fmt.Errorf("resident_id is required")
+	}
+	if !t.Status.IsTemporary() {
+		return fmt.Errorf("status %s cannot be scheduled", t.Status)
+	}
+	if t.StartedAt.IsZero() {
+		return fmt.Errorf("started_at is required")
+	}
+	if t.ExpectedEnd.IsZero() {
+		return fmt.Errorf("expected_end is required")
+	}
+	if t.EndDue().Before(t.StartedAt) {
+		return fmt.Errorf("expected_end is before started_at")
+	}
+	return nil
+}
+
+// IsOpen returns true if the resident is still in the status.
+func (t *StatusTransition) IsOpen() bool {
+	return t.EndedAt == nil
+}
+
+// EndDue returns when the period is over: the start of the day after the
+// expected end date.
+func (t *StatusTransition) EndDue() time.Time {
+	end := t.ExpectedEnd
+	return time.Date(end.Year(), end.Month(), end.Day()+1, 0, 0, 0, 0, end.Location())
+}
+
+// IsDue returns true if an open period has passed its expected end date.
+func (t *StatusTransition) IsDue(now time.Time) bool {
+	return t.IsOpen() && !now.Before(t.EndDue())
+}
@@ -0,0 +1,99 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResidentStatus_IsTemporary(t *testing.T) {
+	tests := []struct {
+		status ResidentStatus
+		want   bool
+	}{
+		{ResidentStatusQuarantine, true},
+		{ResidentStatusSurfaceMission, true},
+		{ResidentStatusActive, false},
+		{ResidentStatusDeceased, false},
+		{ResidentStatusExiled, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			if got := tt.status.IsTemporary(); got != tt.want {
+				t.Errorf("ResidentStatus.IsTemporary() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatusTransition_Validate(t *testing.T) {
+	valid := func() *StatusTransition {
+		return &StatusTransition{
+			ID:          "transition-1",
+			ResidentID:  "resident-1",
+			Status:      ResidentStatusQuarantine,
+			StartedAt:   time.Date(2077, 11, 1, 14, 0, 0, 0, time.UTC),
+			ExpectedEnd: time.Date(2077, 11, 14, 0, 0, 0, 0, time.UTC),
+			AutoRevert:  true,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*StatusTransition)
+		wantErr bool
+	}{
+		{"Valid quarantine", func(s *StatusTransition) {}, false},
+		{"Valid surface mission", func(s *StatusTransition) { s.Status = ResidentStatusSurfaceMission }, false},
+		{"Ends on start day", func(s *StatusTransition) { s.ExpectedEnd = time.Date(2077, 11, 1, 0, 0, 0, 0, time.UTC) }, false},
+		{"Missing ID", func(s *StatusTransition) { s.ID = "" }, true},
+		{"Missing resident", func(s *StatusTransition) { s.ResidentID = "" }, true},
+		{"Active cannot be scheduled", func(s *StatusTransition) { s.Status = ResidentStatusActive }, true},
+		{"Missing start", func(s *StatusTransition) { s.StartedAt = time.Time{} }, true},
+		{"Missing expected end", func(s *StatusTransition) { s.ExpectedEnd = time.Time{} }, true},
+		{"Ends before start", func(s *StatusTransition) { s.ExpectedEnd = time.Date(2077, 10, 31, 0, 0, 0, 0, time.UTC) }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := valid()
+			tt.modify(s)
+			err := s.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStatusTransition_IsDue(t *testing.T) {
+	ended := time.Date(2077, 11, 10, 8, 0, 0, 0, time.UTC)
+	transition := &StatusTransition{
+		ExpectedEnd: time.Date(2077, 11, 14, 0, 0, 0, 0, time.UTC),
+	}
+
+	if want := time.Date(2077, 11, 15, 0, 0, 0, 0, time.UTC); !transition.EndDue().Equal(want) {
+		t.Errorf("EndDue() = %v, want %v", transition.EndDue(), want)
+	}
+
+	tests := []struct {
+		name  string
+		now   time.Time
+		ended *time.Time
+		want  bool
+	}{
+		{"During period", time.Date(2077, 11, 5, 12, 0, 0, 0, time.UTC), nil, false},
+		{"Last day", time.Date(2077, 11, 14, 23, 59, 0, 0, time.UTC), nil, false},
+		{"Day after", time.Date(2077, 11, 15, 0, 0, 0, 0, time.UTC), nil, true},
+		{"Ended early", time.Date(2077, 11, 20, 0, 0, 0, 0, time.UTC), &ended, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transition.EndedAt = tt.ended
+			if got := transition.IsDue(tt.now); got != tt.want {
+				t.Errorf("IsDue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
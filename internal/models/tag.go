@@ -0,0 +1,185 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// MaxTagLength is the longest a resident tag or census preset name may be.
+const MaxTagLength = 40
+
+// NormalizeTag trims a resident tag and collapses its inner spaces, e.g.
+// " G.O.A.T.  pending" to "G.O.A.T. pending". Tags are free-form but may
+// not be empty, hold commas, or run past MaxTagLength.
+func NormalizeTag(tag string) (string, error) {
+	tag = strings.Join(strings.Fields(tag), " ")
+	switch {
+	case tag == "":
+		return "", fmt.Errorf("tag is required")
+	case strings.Contains(tag, ","):
+		return "", fmt.Errorf("tag %q may not contain a comma", tag)
+	case len([]rune(tag)) > MaxTagLength:
+		return "", fmt.Errorf("tag %q is longer than %d characters", tag, MaxTagLength)
+	}
+	return tag, nil
+}
+
+// ParseTags parses a comma-separated list of tags, e.g. "council, medical
+// staff", dropping repeats, which are matched regardless of case.
+func ParseTags(s string) ([]string, error) {
+	var tags []string
+	for _, part := range strings.Split(s, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		tag, err := NormalizeTag(part)
+		if err != nil {
+			return nil, err
+		}
+		if !HasTag(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
+
+// ParseTagEdits parses a comma-separated list of tags to add and, prefixed
+// with "-", to remove, e.g. "council, -G.O.A.T. pending". A "+" prefix is
+// accepted on tags to add.
+func ParseTagEdits(s string) (add, remove []string, err error) {
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		removing := strings.HasPrefix(part, "-")
+		tag, err := NormalizeTag(strings.TrimLeft(part, "+-"))
+		if err != nil {
+			return nil, nil, err
+		}
+		if removing {
+			if !HasTag(remove, tag) {
+				remove = append(remove, tag)
+			}
+		} else if !HasTag(add, tag) {
+			add = append(add, tag)
+		}
+	}
+	if len(add) == 0 && len(remove) == 0 {
+		return nil, nil, fmt.Errorf("no tags given")
+	}
+	return add, remove, nil
+}
+
+// HasTag returns true if tags holds tag, regardless of case.
+func HasTag(tags []string, tag string) bool {
+	return slices.ContainsFunc(tags, func(t string) bool { return strings.EqualFold(t, tag) })
+}
+
+// TagCount is a tag in use with the number of residents carrying it.
+type TagCount struct {
+	Tag       string
+	Residents int
+}
+
+// CensusPreset is a census filter and sort saved under a name, to be
+// applied again from the census.
+type CensusPreset struct {
+	ID        string
+	Name      string
+	Filter    ResidentFilter
+	Sort      SortOption // Zero value for the census's default order
+	VaultID   int
+	CreatedAt time.Time
+}
+
+// Validate checks if the preset data is valid.
+func (p *CensusPreset) Validate() error {
+	if p.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if name := strings.TrimSpace(p.Name); name == "" {
+		return fmt.Errorf("name is required")
+	} else if len([]rune(name)) > MaxTagLength {
+		return fmt.Errorf("name is longer than %d characters", MaxTagLength)
+	}
+	if p.Sort.IsSet() && !slices.Contains(ResidentSortColumns, p.Sort.Column) {
+		return fmt.Errorf("invalid sort column: %s", p.Sort.Column)
+	}
+	if p.Sort.Direction != "" && p.Sort.Direction != SortAsc && p.Sort.Direction != SortDesc {
+		return fmt.Errorf("invalid sort direction: %s", p.Sort.Direction)
+	}
+	return p.Filter.Validate()
+}
+
+// Describe summarizes what the preset selects, e.g. "status ACTIVE; age
+// 18-65; tags council; sort surname ▲".
+func (p *CensusPreset) Describe() string {
+	var parts []string
+	if filter := p.Filter.Describe(); filter != "" {
+		parts = append(parts, filter)
+	}
+	if p.Sort.IsSet() {
+		parts = append(parts, "sort "+p.Sort.String())
+	}
+	if len(parts) == 0 {
+		return "every resident"
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Describe summarizes what the filter selects, e.g. "status ACTIVE; age
+// 18-65", or returns "" for a filter selecting every resident.
+func (f *ResidentFilter) Describe() string {
+	var parts []string
+	if f.SearchTerm != "" {
+		parts = append(parts, fmt.Sprintf("search %q", f.SearchTerm))
+	}
+	if f.Status != nil {
+		parts = append(parts, "status "+string(*f.Status))
+	}
+	if f.Sex != nil {
+		parts = append(parts, "sex "+string(*f.Sex))
+	}
+	if f.EntryType != nil {
+		parts = append(parts, "entry "+string(*f.EntryType))
+	}
+	if r := describeRange(f.MinAge, f.MaxAge); r != "" {
+		parts = append(parts, "age "+r)
+	}
+	if r := describeRange(f.MinClearance, f.MaxClearance); r != "" {
+		parts = append(parts, "clearance "+r)
+	}
+	if f.VocationCode != "" {
+		parts = append(parts, "vocation "+f.VocationCode)
+	}
+	if f.HouseholdType != nil {
+		parts = append(parts, "household "+string(*f.HouseholdType))
+	}
+	if f.Sector != "" {
+		parts = append(parts, "sector "+f.Sector)
+	}
+	if f.HouseholdID != nil || f.VocationID != nil {
+		parts = append(parts, "one household or vocation")
+	}
+	if len(f.Tags) > 0 {
+		parts = append(parts, "tags "+strings.Join(f.Tags, ", "))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// describeRange formats an inclusive range with optional bounds, e.g.
+// "18-65", "18+" or "up to 65", or "" with neither bound.
+func describeRange(lo, hi *int) string {
+	switch {
+	case lo != nil && hi != nil:
+		return fmt.Sprintf("%d-%d", *lo, *hi)
+	case lo != nil:
+		return fmt.Sprintf("%d+", *lo)
+	case hi != nil:
+		return fmt.Sprintf("up to %d", *hi)
+	}
+	return ""
+}
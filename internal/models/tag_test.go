@@ -0,0 +1,107 @@
+package models
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseTags(t *testing.T) {
+	tags, err := ParseTags(" council,  G.O.A.T.   pending ,, Council")
+	if err != nil {
+		t.Fatalf("ParseTags() error = %v", err)
+	}
+	if want := []string{"council", "G.O.A.T. pending"}; !slices.Equal(tags, want) {
+		t.Errorf("ParseTags() = %q, want %q", tags, want)
+	}
+	if _, err := ParseTags(strings.Repeat("x", MaxTagLength+1)); err == nil {
+		t.Error("ParseTags() accepted a tag too long")
+	}
+}
+
+func TestParseTagEdits(t *testing.T) {
+	add, remove, err := ParseTagEdits("council, +radiation-exposed, -G.O.A.T. pending")
+	if err != nil {
+		t.Fatalf("ParseTagEdits() error = %v", err)
+	}
+	if want := []string{"council", "radiation-exposed"}; !slices.Equal(add, want) {
+		t.Errorf("add = %q, want %q", add, want)
+	}
+	if want := []string{"G.O.A.T. pending"}; !slices.Equal(remove, want) {
+		t.Errorf("remove = %q, want %q", remove, want)
+	}
+	if _, _, err := ParseTagEdits(" , "); err == nil {
+		t.Error("ParseTagEdits() accepted no tags")
+	}
+	if _, _, err := ParseTagEdits("-"); err == nil {
+		t.Error("ParseTagEdits() accepted an empty tag")
+	}
+}
+
+func TestCensusPreset_Validate(t *testing.T) {
+	valid := func() *CensusPreset {
+		return &CensusPreset{
+			ID:     "preset-1",
+			Name:   "Council",
+			Filter: ResidentFilter{Tags: []string{"council"}},
+			Sort:   SortOption{Column: "surname", Direction: SortDesc},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*CensusPreset)
+		wantErr bool
+	}{
+		{"Valid preset", func(p *CensusPreset) {}, false},
+		{"Default order", func(p *CensusPreset) { p.Sort = SortOption{} }, false},
+		{"Missing name", func(p *CensusPreset) { p.Name = " " }, true},
+		{"Name too long", func(p *CensusPreset) { p.Name = strings.Repeat("x", MaxTagLength+1) }, true},
+		{"Unknown sort column", func(p *CensusPreset) { p.Sort.Column = "notes" }, true},
+		{"Invalid direction", func(p *CensusPreset) { p.Sort.Direction = "UP" }, true},
+		{"Invalid tag", func(p *CensusPreset) { p.Filter.Tags = []string{"a,b"} }, true},
+		{"Age range", func(p *CensusPreset) { p.Filter.MinAge, p.Filter.MaxAge = intPtr(18), intPtr(65) }, false},
+		{"Ages out of order", func(p *CensusPreset) { p.Filter.MinAge, p.Filter.MaxAge = intPtr(65), intPtr(18) }, true},
+		{"Negative age", func(p *CensusPreset) { p.Filter.MaxAge = intPtr(-1) }, true},
+		{"Clearance out of range", func(p *CensusPreset) { p.Filter.MinClearance = intPtr(11) }, true},
+		{"Clearances out of order", func(p *CensusPreset) { p.Filter.MinClearance, p.Filter.MaxClearance = intPtr(7), intPtr(3) }, true},
+		{"Invalid household type", func(p *CensusPreset) { t := HouseholdType("NOMAD"); p.Filter.HouseholdType = &t }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := valid()
+			tt.modify(p)
+			err := p.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCensusPreset_Describe(t *testing.T) {
+	status := ResidentStatusActive
+	p := &CensusPreset{
+		Filter: ResidentFilter{Status: &status, Tags: []string{"council", "G.O.A.T. pending"}},
+		Sort:   SortOption{Column: "surname", Direction: SortAsc},
+	}
+	if got, want := p.Describe(), "status ACTIVE; tags council, G.O.A.T. pending; sort surname ▲"; got != want {
+		t.Errorf("Describe() = %q, want %q", got, want)
+	}
+	family := HouseholdTypeFamily
+	p = &CensusPreset{Filter: ResidentFilter{
+		MinAge: intPtr(18), MaxClearance: intPtr(5), VocationCode: "MED-DOC",
+		HouseholdType: &family, Sector: "B",
+	}}
+	if got, want := p.Describe(), "age 18+; clearance up to 5; vocation MED-DOC; household FAMILY; sector B"; got != want {
+		t.Errorf("Describe() = %q, want %q", got, want)
+	}
+	if got := (&CensusPreset{}).Describe(); got != "every resident" {
+		t.Errorf("Describe() of an empty preset = %q, want every resident", got)
+	}
+}
+
+func intPtr(n int) *int {
+	return &n
+}
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Shift is one of the vault's three eight-hour work shifts.
+type Shift string
+
+const (
+	ShiftAlpha Shift = "ALPHA" // 0600-1400
+	ShiftBeta  Shift = "BETA"  // 1400-2200
+	ShiftGamma Shift = "GAMMA" // 2200-0600
+)
+
+// Shifts lists the shifts in the order they run from the start of the day
+// shift.
+var Shifts = []Shift{ShiftAlpha, ShiftBeta, ShiftGamma}
+
+// Valid returns true if the shift is valid.
+func (s Shift) Valid() bool {
+	switch s {
+	case ShiftAlpha, ShiftBeta, ShiftGamma:
+		return true
+	default:
+		return false
+	}
+}
+
+// StartHour returns the hour of the day the shift starts.
+func (s Shift) StartHour() int {
+	switch s {
+	case ShiftBeta:
+		return 14
+	case ShiftGamma:
+		return 22
+	default:
+		return 6
+	}
+}
+
+// ShiftAt returns the shift on duty at t.
+func ShiftAt(t time.Time) Shift {
+	switch h := t.UTC().Hour(); {
+	case h >= 6 && h < 14:
+		return ShiftAlpha
+	case h >= 14 && h < 22:
+		return ShiftBeta
+	default:
+		return ShiftGamma
+	}
+}
+
+// MaxSkillRating is the highest skill rating, a master of the trade.
+const MaxSkillRating = 5
+
+// Technician is a resident's skill in maintaining each facility category,
+// rated 1 to MaxSkillRating, and the shift they work.
+type Technician struct {
+	ResidentID string                   `json:"resident_id"`
+	Shift      Shift                    `json:"shift"`
+	Skills     map[FacilityCategory]int `json:"skills"` // Categories without a rating are left out
+	UpdatedAt  time.Time                `json:"updated_at"`
+
+	// Joined fields
+	Resident *Resident `json:"-"`
+}
+
+// Validate checks if the technician data is valid.
+func (t *Technician) Validate() error {
+	if t.ResidentID == "" {
+		return fmt.Errorf("resident_id is required")
+	}
+	if !t.Shift.Valid() {
+		return fmt.Errorf("invalid shift: %s", t.Shift)
+	}
+	for category, rating := range t.Skills {
+		if !category.Valid() {
+			return fmt.Errorf("invalid facility category: %s", category)
+		}
+		if rating < 1 || rating > MaxSkillRating {
+			return fmt.Errorf("%s skill must be 1-%d", category, MaxSkillRating)
+		}
+	}
+	return nil
+}
+
+// Skill returns the technician's rating in a category, 0 when unrated.
+func (t *Technician) Skill(category FacilityCategory) int {
+	return t.Skills[category]
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTechnician_Validate(t *testing.T) {
+	valid := func() *Technician {
+		return &Technician{
+			ResidentID: "res1",
+			Shift:      ShiftAlpha,
+			Skills:     map[FacilityCategory]int{FacilityCategoryPower: 5, FacilityCategoryWater: 1},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*Technician)
+		wantErr bool
+	}{
+		{"Valid technician", func(*Technician) {}, false},
+		{"No skills", func(tech *Technician) { tech.Skills = nil }, false},
+		{"Missing resident", func(tech *Technician) { tech.ResidentID = "" }, true},
+		{"Invalid shift", func(tech *Technician) { tech.Shift = "DELTA" }, true},
+		{"Invalid category", func(tech *Technician) { tech.Skills["PLUMBING"] = 3 }, true},
+		{"Zero rating", func(tech *Technician) { tech.Skills[FacilityCategoryHVAC] = 0 }, true},
+		{"Rating above maximum", func(tech *Technician) { tech.Skills[FacilityCategoryHVAC] = MaxSkillRating + 1 }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tech := valid()
+			tt.modify(tech)
+			err := tech.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestShiftAt(t *testing.T) {
+	tests := []struct {
+		hour     int
+		expected Shift
+	}{
+		{0, ShiftGamma},
+		{5, ShiftGamma},
+		{6, ShiftAlpha},
+		{13, ShiftAlpha},
+		{14, ShiftBeta},
+		{21, ShiftBeta},
+		{22, ShiftGamma},
+	}
+
+	for _, tt := range tests {
+		at := time.Date(2077, 10, 23, tt.hour, 30, 0, 0, time.UTC)
+		if got := ShiftAt(at); got != tt.expected {
+			t.Errorf("ShiftAt(%02d:30) = %s, want %s", tt.hour, got, tt.expected)
+		}
+		if got := ShiftAt(time.Date(2077, 10, 23, ShiftAt(at).StartHour(), 0, 0, 0, time.UTC)); got != tt.expected {
+			t.Errorf("ShiftAt(start of %s) = %s", tt.expected, got)
+		}
+	}
+}
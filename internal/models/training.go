@@ -0,0 +1,165 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultTrainingHoursPerDay is the hours of training a vault day counts for
+// in a default training program.
+const DefaultTrainingHoursPerDay = 6.0
+
+// DefaultTrainingHours returns the training a vocation's default program
+// requires: 400 hours per clearance level the vocation requires, plus 200
+// for moderately hazardous work, 400 for highly and 600 for extremely
+// hazardous work.
+func DefaultTrainingHours(v *Vocation) float64 {
+	hours := 400.0 * float64(v.RequiredClearance)
+	switch v.HazardLevel {
+	case "MODERATE":
+		hours += 200
+	case "HIGH":
+		hours += 400
+	case "EXTREME":
+		hours += 600
+	}
+	return hours
+}
+
+// TrainingProgram is the training an apprentice completes before taking up
+// a vocation.
+type TrainingProgram struct {
+	ID            string    `json:"id"`
+	VocationID    string    `json:"vocation_id"`
+	RequiredHours float64   `json:"required_hours"`
+	HoursPerDay   float64   `json:"hours_per_day"` // Training hours a vault day counts for
+	Description   string    `json:"description,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// Validate checks if the training program data is valid.
+func (p *TrainingProgram) Validate() error {
+	if p.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if p.VocationID == "" {
+		return fmt.Errorf("vocation_id is required")
+	}
+	if p.RequiredHours <= 0 {
+		return fmt.Errorf("required_hours must be positive")
+	}
+	if p.HoursPerDay <= 0 || p.HoursPerDay > 24 {
+		return fmt.Errorf("hours_per_day must be more than 0 and at most 24")
+	}
+	return nil
+}
+
+// ApprenticeshipStatus represents the state of an apprenticeship.
+type ApprenticeshipStatus string
+
+const (
+	ApprenticeshipStatusActive    ApprenticeshipStatus = "ACTIVE"
+	ApprenticeshipStatusGraduated ApprenticeshipStatus = "GRADUATED"
+	ApprenticeshipStatusWithdrawn ApprenticeshipStatus = "WITHDRAWN"
+)
+
+// Valid returns true if the status is valid.
+func (s ApprenticeshipStatus) Valid() bool {
+	switch s {
+	case ApprenticeshipStatusActive, ApprenticeshipStatusGraduated, ApprenticeshipStatusWithdrawn:
+		return true
+	default:
+		return false
+	}
+}
+
+// Apprenticeship is a resident's training in a vocation under a mentor. The
+// program's hours are copied at enrollment.
+type Apprenticeship struct {
+	ID             string               `json:"id"`
+	ResidentID     string               `json:"resident_id"`
+	VocationID     string               `json:"vocation_id"`
+	MentorID       *string              `json:"mentor_id,omitempty"`
+	Status         ApprenticeshipStatus `json:"status"`
+	EnrolledAt     time.Time            `json:"enrolled_at"`
+	RequiredHours  float64              `json:"required_hours"`
+	HoursPerDay    float64              `json:"hours_per_day"`
+	HoursCompleted float64              `json:"hours_completed"`
+	SignedOffBy    *string              `json:"signed_off_by,omitempty"`
+	EndedAt        *time.Time           `json:"ended_at,omitempty"`
+	Notes          string               `json:"notes,omitempty"`
+	CreatedAt      time.Time            `json:"created_at"`
+	UpdatedAt      time.Time            `json:"updated_at"`
+}
+
+// Validate checks if the apprenticeship data is valid.
+func (a *Apprenticeship) Validate() error {
+	if a.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if a.ResidentID == "" {
+		return fmt.Errorf("resident_id is required")
+	}
+	if a.VocationID == "" {
+		return fmt.Errorf("vocation_id is required")
+	}
+	if !a.Status.Valid() {
+		return fmt.Errorf("invalid status: %s", a.Status)
+	}
+	if a.EnrolledAt.IsZero() {
+		return fmt.Errorf("enrolled_at is required")
+	}
+	if a.RequiredHours <= 0 {
+		return fmt.Errorf("required_hours must be positive")
+	}
+	if a.HoursPerDay <= 0 || a.HoursPerDay > 24 {
+		return fmt.Errorf("hours_per_day must be more than 0 and at most 24")
+	}
+	if a.HoursCompleted < 0 {
+		return fmt.Errorf("hours_completed cannot be negative")
+	}
+	if a.MentorID != nil && *a.MentorID == a.ResidentID {
+		return fmt.Errorf("an apprentice cannot mentor themselves")
+	}
+	if a.Status == ApprenticeshipStatusGraduated && (a.SignedOffBy == nil || !a.HoursComplete()) {
+		return fmt.Errorf("a graduate needs completed hours and a sign-off")
+	}
+	return nil
+}
+
+// IsActive returns true if the apprentice is still training.
+func (a *Apprenticeship) IsActive() bool {
+	return a.Status == ApprenticeshipStatusActive
+}
+
+// HoursComplete returns true if the apprentice has trained the required
+// hours.
+func (a *Apprenticeship) HoursComplete() bool {
+	return a.HoursCompleted >= a.RequiredHours-QuantityEpsilon
+}
+
+// Progress returns the share of the required hours trained, 0-1.
+func (a *Apprenticeship) Progress() float64 {
+	return min(a.HoursCompleted/a.RequiredHours, 1)
+}
+
+// Train adds the training of the elapsed vault time, at the apprenticeship's
+// hours per day, up to the required hours. It returns true if that completes
+// the hours.
+func (a *Apprenticeship) Train(elapsed time.Duration) bool {
+	if a.HoursComplete() {
+		return false
+	}
+	return a.Credit(a.HoursPerDay * elapsed.Hours() / 24)
+}
+
+// Credit adds hours of training, e.g. of a skills class attended, up to the
+// required hours. It returns true if that completes the hours.
+func (a *Apprenticeship) Credit(hours float64) bool {
+	if a.HoursComplete() {
+		return false
+	}
+	a.HoursCompleted = min(a.HoursCompleted+hours, a.RequiredHours)
+	return a.HoursComplete()
+}
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDefaultTrainingHours(t *testing.T) {
+	tests := []struct {
+		clearance int
+		hazard    string
+		want      float64
+	}{
+		{1, "NONE", 400},
+		{2, "MODERATE", 1000},
+		{4, "HIGH", 2000},
+		{3, "EXTREME", 1800},
+	}
+
+	for _, tt := range tests {
+		v := &Vocation{RequiredClearance: tt.clearance, HazardLevel: tt.hazard}
+		if got := DefaultTrainingHours(v); got != tt.want {
+			t.Errorf("DefaultTrainingHours(%d, %s) = %v, want %v", tt.clearance, tt.hazard, got, tt.want)
+		}
+	}
+}
+
+func TestApprenticeship_Validate(t *testing.T) {
+	mentor, self := "res-2", "res-1"
+	valid := func() *Apprenticeship {
+		return &Apprenticeship{
+			ID:            "app-1",
+			ResidentID:    "res-1",
+			VocationID:    "voc-1",
+			MentorID:      &mentor,
+			Status:        ApprenticeshipStatusActive,
+			EnrolledAt:    time.Date(2078, 3, 1, 0, 0, 0, 0, time.UTC),
+			RequiredHours: 400,
+			HoursPerDay:   6,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*Apprenticeship)
+		wantErr bool
+	}{
+		{"Valid active", func(a *Apprenticeship) {}, false},
+		{"Missing vocation", func(a *Apprenticeship) { a.VocationID = "" }, true},
+		{"Invalid status", func(a *Apprenticeship) { a.Status = "PAUSED" }, true},
+		{"Day over 24 hours", func(a *Apprenticeship) { a.HoursPerDay = 25 }, true},
+		{"Own mentor", func(a *Apprenticeship) { a.MentorID = &self }, true},
+		{"Graduated short of hours", func(a *Apprenticeship) {
+			a.Status = ApprenticeshipStatusGraduated
+			a.SignedOffBy = &mentor
+		}, true},
+		{"Graduated", func(a *Apprenticeship) {
+			a.Status = ApprenticeshipStatusGraduated
+			a.SignedOffBy = &mentor
+			a.HoursCompleted = 400
+		}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := valid()
+			tt.modify(a)
+			if err := a.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestApprenticeship_Train(t *testing.T) {
+	a := &Apprenticeship{RequiredHours: 12, HoursPerDay: 6}
+
+	if a.Train(12 * time.Hour) {
+		t.Error("Expected half a day not to complete the hours")
+	}
+	if a.HoursCompleted != 3 {
+		t.Errorf("Expected 3 hours after half a day, got %v", a.HoursCompleted)
+	}
+	if !a.Train(48 * time.Hour) {
+		t.Error("Expected two days to complete the hours")
+	}
+	if a.HoursCompleted != 12 {
+		t.Errorf("Expected hours capped at 12, got %v", a.HoursCompleted)
+	}
+	if a.Train(24 * time.Hour) {
+		t.Error("Expected completed hours not to complete again")
+	}
+}
+
+func TestApprenticeship_Credit(t *testing.T) {
+	a := &Apprenticeship{RequiredHours: 12, HoursPerDay: 6, HoursCompleted: 8}
+
+	if a.Credit(2) {
+		t.Error("Expected a two-hour class not to complete the hours")
+	}
+	if !a.Credit(3) {
+		t.Error("Expected a three-hour class to complete the hours")
+	}
+	if a.HoursCompleted != 12 {
+		t.Errorf("Expected hours capped at 12, got %v", a.HoursCompleted)
+	}
+	if a.Credit(2) {
+		t.Error("Expected completed hours not to complete again")
+	}
+}
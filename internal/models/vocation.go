@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// Department represents a vault department that vocations belong to.
+type Department string
+
+const (
+	DepartmentEngineering    Department = "ENGINEERING"
+	DepartmentMedical        Department = "MEDICAL"
+	DepartmentSecurity       Department = "SECURITY"
+	DepartmentFoodProduction Department = "FOOD_PRODUCTION"
+	DepartmentAdministration Department = "ADMINISTRATION"
+	DepartmentEducation      Department = "EDUCATION"
+	DepartmentSanitation     Department = "SANITATION"
+	DepartmentResearch       Department = "RESEARCH"
+)
+
+// Departments lists every department, in the order aptitude scores are
+// entered and shown.
+var Departments = []Department{
+	DepartmentEngineering, DepartmentMedical, DepartmentSecurity, DepartmentFoodProduction,
+	DepartmentAdministration, DepartmentEducation, DepartmentSanitation, DepartmentResearch,
+}
+
+// Vocation represents a job role residents can be assigned to.
+type Vocation struct {
+	ID                  string     `json:"id"`
+	Code                string     `json:"code"`
+	Title               string     `json:"title"`
+	Department          Department `json:"department"`
+	RequiredClearance   int        `json:"required_clearance"`
+	HeadcountAuthorized int        `json:"headcount_authorized"`
+	HeadcountMinimum    int        `json:"headcount_minimum"`
+	ShiftPattern        string     `json:"shift_pattern"`
+	HazardLevel         string     `json:"hazard_level"`
+	Description         string     `json:"description,omitempty"`
+	IsActive            bool       `json:"is_active"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,376 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// AccessRepository handles access point, grant and access event data access.
+type AccessRepository struct {
+	db    *sql.DB
+	vault int // Vault lists are limited to, 0 for every vault
+}
+
+// NewAccessRepository creates a new access control repository.
+func NewAccessRepository(db *sql.DB) *AccessRepository {
+	return &AccessRepository{db: db}
+}
+
+// ForVault returns a copy of the repository whose point and event lists are
+// limited to access points of the vault, and which creates points there.
+// Lookups by ID or code still find points of any vault.
+func (r *AccessRepository) ForVault(vault int) *AccessRepository {
+	scoped := *r
+	scoped.vault = vault
+	return &scoped
+}
+
+// ============================================================================
+// ACCESS POINTS
+// ============================================================================
+
+// CreatePoint inserts a new access point.
+func (r *AccessRepository) CreatePoint(ctx context.Context, tx *sql.Tx, p *models.AccessPoint) error {
+	if err := p.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	now := time.Now().UTC()
+	p.CreatedAt = now
+	p.UpdatedAt = now
+	if p.VaultID == 0 {
+		p.VaultID = r.vault
+	}
+
+	_, err := r.getExecer(tx).ExecContext(ctx, `
+		INSERT INTO access_points (
+			id, code, name, kind, location_sector, location_level,
+			min_clearance, is_active, vault_id, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		p.ID,
+		p.Code,
+		p.Name,
+		string(p.Kind),
+		nullableString(p.LocationSector),
+		p.LocationLevel,
+		p.MinClearance,
+		boolToInt(p.IsActive),
+		p.VaultID,
+		p.CreatedAt.Format(time.RFC3339),
+		p.UpdatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("inserting access point: %w", constraintError(err))
+	}
+	return nil
+}
+
+// UpdatePoint updates an access point's name, location, minimum clearance
+// and whether it is active.
+func (r *AccessRepository) UpdatePoint(ctx context.Context, tx *sql.Tx, p *models.AccessPoint) error {
+	if err := p.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	p.UpdatedAt = time.Now().UTC()
+
+	result, err := r.getExecer(tx).ExecContext(ctx, `
+		UPDATE access_points SET
+			name = ?, location_sector = ?, location_level = ?, min_clearance = ?,
+			is_active = ?, updated_at = ?
+		WHERE id = ?`,
+		p.Name,
+		nullableString(p.LocationSector),
+		p.LocationLevel,
+		p.MinClearance,
+		boolToInt(p.IsActive),
+		p.UpdatedAt.Format(time.RFC3339),
+		p.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("updating access point: %w", constraintError(err))
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("access point %w: %s", ErrNotFound, p.ID)
+	}
+	return nil
+}
+
+// GetPoint retrieves an access point by ID.
+func (r *AccessRepository) GetPoint(ctx context.Context, id string) (*models.AccessPoint, error) {
+	p, err := r.scanPoint(r.db.QueryRowContext(ctx, accessPointSelect+" WHERE id = ?", id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("access point %w: %s", ErrNotFound, id)
+	}
+	return p, err
+}
+
+// GetPointByCode retrieves an access point by its code.
+func (r *AccessRepository) GetPointByCode(ctx context.Context, code string) (*models.AccessPoint, error) {
+	p, err := r.scanPoint(r.db.QueryRowContext(ctx, accessPointSelect+" WHERE code = ?", code))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("access point %w: %s", ErrNotFound, code)
+	}
+	return p, err
+}
+
+// ListPoints retrieves access points, ordered by code.
+func (r *AccessRepository) ListPoints(ctx context.Context) ([]*models.AccessPoint, error) {
+	rows, err := r.db.QueryContext(ctx, accessPointSelect+`
+		WHERE `+vaultCondition+`
+		ORDER BY code`, r.vault, r.vault)
+	if err != nil {
+		return nil, fmt.Errorf("querying access points: %w", err)
+	}
+	return collect(rows, r.scanPoint)
+}
+
+const accessPointSelect = `
+	SELECT id, code, name, kind, location_sector, location_level,
+		min_clearance, is_active, vault_id, created_at, updated_at
+	FROM access_points`
+
+// scanPoint scans an access point from a single row or a rows iterator.
+func (r *AccessRepository) scanPoint(row rowScanner) (*models.AccessPoint, error) {
+	var p models.AccessPoint
+	var kind, createdStr, updatedStr string
+	var sector sql.NullString
+	var active int
+
+	err := row.Scan(&p.ID, &p.Code, &p.Name, &kind, &sector, &p.LocationLevel,
+		&p.MinClearance, &active, &p.VaultID, &createdStr, &updatedStr)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scanning access point: %w", err)
+	}
+
+	p.Kind = models.AccessPointKind(kind)
+	p.LocationSector = sector.String
+	p.IsActive = active == 1
+	p.CreatedAt = parseTime(time.RFC3339, createdStr)
+	p.UpdatedAt = parseTime(time.RFC3339, updatedStr)
+	return &p, nil
+}
+
+// ============================================================================
+// ACCESS GRANTS
+// ============================================================================
+
+// CreateGrant inserts a new access grant.
+func (r *AccessRepository) CreateGrant(ctx context.Context, tx *sql.Tx, g *models.AccessGrant) error {
+	if err := g.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	g.CreatedAt = time.Now().UTC()
+
+	_, err := r.getExecer(tx).ExecContext(ctx, `
+		INSERT INTO access_grants (
+			id, resident_id, access_point_id, granted_by, granted_at,
+			expires_at, revoked_at, reason, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		g.ID,
+		g.ResidentID,
+		g.AccessPointID,
+		g.GrantedBy,
+		g.GrantedAt.UTC().Format(time.RFC3339),
+		nullableTimePtrRFC3339(g.ExpiresAt),
+		nullableTimePtrRFC3339(g.RevokedAt),
+		nullableString(g.Reason),
+		g.CreatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("inserting access grant: %w", constraintError(err))
+	}
+	return nil
+}
+
+// GetGrant retrieves an access grant by ID.
+func (r *AccessRepository) GetGrant(ctx context.Context, id string) (*models.AccessGrant, error) {
+	g, err := r.scanGrant(r.db.QueryRowContext(ctx, accessGrantSelect+" WHERE id = ?", id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("access grant %w: %s", ErrNotFound, id)
+	}
+	return g, err
+}
+
+// RevokeGrant revokes a grant not yet revoked at the given time.
+func (r *AccessRepository) RevokeGrant(ctx context.Context, tx *sql.Tx, id string, at time.Time) error {
+	result, err := r.getExecer(tx).ExecContext(ctx, `
+		UPDATE access_grants SET revoked_at = ?
+		WHERE id = ? AND revoked_at IS NULL`,
+		at.UTC().Format(time.RFC3339), id)
+	if err != nil {
+		return fmt.Errorf("revoking access grant: %w", constraintError(err))
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("unrevoked access grant %w: %s", ErrNotFound, id)
+	}
+	return nil
+}
+
+// ListGrantsByResident retrieves a resident's grants, newest first.
+func (r *AccessRepository) ListGrantsByResident(ctx context.Context, residentID string) ([]*models.AccessGrant, error) {
+	rows, err := r.db.QueryContext(ctx, accessGrantSelect+`
+		WHERE resident_id = ?
+		ORDER BY granted_at DESC, id`, residentID)
+	if err != nil {
+		return nil, fmt.Errorf("querying access grants: %w", err)
+	}
+	return collect(rows, r.scanGrant)
+}
+
+// ListGrantsByPoint retrieves the grants for an access point, newest first.
+func (r *AccessRepository) ListGrantsByPoint(ctx context.Context, pointID string) ([]*models.AccessGrant, error) {
+	rows, err := r.db.QueryContext(ctx, accessGrantSelect+`
+		WHERE access_point_id = ?
+		ORDER BY granted_at DESC, id`, pointID)
+	if err != nil {
+		return nil, fmt.Errorf("querying access grants: %w", err)
+	}
+	return collect(rows, r.scanGrant)
+}
+
+const accessGrantSelect = `
+	SELECT id, resident_id, access_point_id, granted_by, granted_at,
+		expires_at, revoked_at, reason, created_at
+	FROM access_grants`
+
+// scanGrant scans an access grant from a single row or a rows iterator.
+func (r *AccessRepository) scanGrant(row rowScanner) (*models.AccessGrant, error) {
+	var g models.AccessGrant
+	var grantedBy, expiresAt, revokedAt, reason sql.NullString
+	var grantedStr, createdStr string
+
+	err := row.Scan(&g.ID, &g.ResidentID, &g.AccessPointID, &grantedBy, &grantedStr,
+		&expiresAt, &revokedAt, &reason, &createdStr)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scanning access grant: %w", err)
+	}
+
+	g.GrantedBy = stringPtr(grantedBy)
+	g.GrantedAt = parseTime(time.RFC3339, grantedStr)
+	g.ExpiresAt = timePtr(time.RFC3339, expiresAt)
+	g.RevokedAt = timePtr(time.RFC3339, revokedAt)
+	g.Reason = reason.String
+	g.CreatedAt = parseTime(time.RFC3339, createdStr)
+	return &g, nil
+}
+
+// ============================================================================
+// ACCESS EVENTS
+// ============================================================================
+
+// CreateEvent inserts a new access event.
+func (r *AccessRepository) CreateEvent(ctx context.Context, tx *sql.Tx, e *models.AccessEvent) error {
+	if err := e.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	e.CreatedAt = time.Now().UTC()
+
+	_, err := r.getExecer(tx).ExecContext(ctx, `
+		INSERT INTO access_events (
+			id, access_point_id, resident_id, occurred_at, granted, reason, simulated, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		e.ID,
+		e.AccessPointID,
+		e.ResidentID,
+		e.OccurredAt.UTC().Format(time.RFC3339),
+		boolToInt(e.Granted),
+		string(e.Reason),
+		boolToInt(e.Simulated),
+		e.CreatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("inserting access event: %w", constraintError(err))
+	}
+	return nil
+}
+
+// ListEvents retrieves up to limit access events matching the filter, most
+// recent first, with the code of their point and the registry number of
+// their resident.
+func (r *AccessRepository) ListEvents(ctx context.Context, filter models.AccessEventFilter, limit int) ([]*models.AccessEvent, error) {
+	query := `
+		SELECT e.id, e.access_point_id, e.resident_id, e.occurred_at, e.granted,
+			e.reason, e.simulated, e.created_at, p.code, COALESCE(r.registry_number, '')
+		FROM access_events e
+		JOIN access_points p ON p.id = e.access_point_id
+		LEFT JOIN residents r ON r.id = e.resident_id
+		WHERE ` + vaultAccessPointCondition
+	args := []any{r.vault, r.vault}
+
+	if filter.AccessPointID != "" {
+		query += " AND e.access_point_id = ?"
+		args = append(args, filter.AccessPointID)
+	}
+	if filter.ResidentID != "" {
+		query += " AND e.resident_id = ?"
+		args = append(args, filter.ResidentID)
+	}
+	if filter.Granted != nil {
+		query += " AND e.granted = ?"
+		args = append(args, boolToInt(*filter.Granted))
+	}
+	if filter.From != nil {
+		query += " AND e.occurred_at >= ?"
+		args = append(args, filter.From.UTC().Format(time.RFC3339))
+	}
+	if filter.To != nil {
+		query += " AND e.occurred_at < ?"
+		args = append(args, filter.To.UTC().Format(time.RFC3339))
+	}
+	query += " ORDER BY e.occurred_at DESC, e.created_at DESC LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying access events: %w", err)
+	}
+	return collect(rows, r.scanEvent)
+}
+
+// scanEvent scans a joined access event from a rows iterator.
+func (r *AccessRepository) scanEvent(row rowScanner) (*models.AccessEvent, error) {
+	var e models.AccessEvent
+	var residentID sql.NullString
+	var occurredStr, reason, createdStr string
+	var granted, simulated int
+
+	err := row.Scan(&e.ID, &e.AccessPointID, &residentID, &occurredStr, &granted,
+		&reason, &simulated, &createdStr, &e.PointCode, &e.RegistryNumber)
+	if err != nil {
+		return nil, fmt.Errorf("scanning access event: %w", err)
+	}
+
+	e.ResidentID = stringPtr(residentID)
+	e.OccurredAt = parseTime(time.RFC3339, occurredStr)
+	e.Granted = granted == 1
+	e.Reason = models.AccessReason(reason)
+	e.Simulated = simulated == 1
+	e.CreatedAt = parseTime(time.RFC3339, createdStr)
+	return &e, nil
+}
+
+func (r *AccessRepository) getExecer(tx *sql.Tx) interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+} {
+	if tx != nil {
+		return tx
+	}
+	return r.db
+}
@@ -0,0 +1,403 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// ActivityRepository handles venue, scheduled activity, enrollment and
+// attendance data access.
+type ActivityRepository struct {
+	db    *sql.DB
+	vault int // Vault venues and activities are limited to, 0 for every vault
+}
+
+// NewActivityRepository creates a new activity repository.
+func NewActivityRepository(db *sql.DB) *ActivityRepository {
+	return &ActivityRepository{db: db}
+}
+
+// ForVault returns a copy of the repository whose venues, and activities
+// through their venues, are limited to the vault, and which creates venues
+// there. Lookups by ID still find any vault's.
+func (r *ActivityRepository) ForVault(vault int) *ActivityRepository {
+	scoped := *r
+	scoped.vault = vault
+	return &scoped
+}
+
+// ============================================================================
+// VENUES
+// ============================================================================
+
+// CreateVenue inserts a new venue.
+func (r *ActivityRepository) CreateVenue(ctx context.Context, tx *sql.Tx, v *models.Venue) error {
+	if err := v.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	now := time.Now().UTC()
+	v.CreatedAt = now
+	v.UpdatedAt = now
+	if v.VaultID == 0 {
+		v.VaultID = r.vault
+	}
+
+	_, err := r.getExecer(tx).ExecContext(ctx, `
+		INSERT INTO venues (
+			id, vault_id, code, name, venue_type, sector, capacity,
+			is_active, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		v.ID,
+		v.VaultID,
+		v.Code,
+		v.Name,
+		string(v.VenueType),
+		v.Sector,
+		v.Capacity,
+		boolToInt(v.IsActive),
+		v.CreatedAt.Format(time.RFC3339),
+		v.UpdatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("inserting venue: %w", constraintError(err))
+	}
+	return nil
+}
+
+// GetVenue retrieves a venue by ID.
+func (r *ActivityRepository) GetVenue(ctx context.Context, id string) (*models.Venue, error) {
+	v, err := r.scanVenue(r.db.QueryRowContext(ctx, venueSelect+" WHERE id = ?", id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("venue %w: %s", ErrNotFound, id)
+	}
+	return v, err
+}
+
+// GetVenueByCode retrieves a venue of the vault by its code.
+func (r *ActivityRepository) GetVenueByCode(ctx context.Context, code string) (*models.Venue, error) {
+	v, err := r.scanVenue(r.db.QueryRowContext(ctx, venueSelect+`
+		WHERE code = ? AND `+vaultCondition, code, r.vault, r.vault))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("venue %w: %s", ErrNotFound, code)
+	}
+	return v, err
+}
+
+// ListVenues retrieves venues, ordered by code.
+func (r *ActivityRepository) ListVenues(ctx context.Context) ([]*models.Venue, error) {
+	rows, err := r.db.QueryContext(ctx, venueSelect+`
+		WHERE `+vaultCondition+`
+		ORDER BY code`, r.vault, r.vault)
+	if err != nil {
+		return nil, fmt.Errorf("querying venues: %w", err)
+	}
+	return collect(rows, r.scanVenue)
+}
+
+const venueSelect = `
+	SELECT id, vault_id, code, name, venue_type, sector, capacity,
+		is_active, created_at, updated_at
+	FROM venues`
+
+// scanVenue scans a venue from a single row or a rows iterator.
+func (r *ActivityRepository) scanVenue(row rowScanner) (*models.Venue, error) {
+	var v models.Venue
+	var venueType, createdStr, updatedStr string
+	var active int
+
+	err := row.Scan(&v.ID, &v.VaultID, &v.Code, &v.Name, &venueType, &v.Sector,
+		&v.Capacity, &active, &createdStr, &updatedStr)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scanning venue: %w", err)
+	}
+
+	v.VenueType = models.VenueType(venueType)
+	v.IsActive = active == 1
+	v.CreatedAt = parseTime(time.RFC3339, createdStr)
+	v.UpdatedAt = parseTime(time.RFC3339, updatedStr)
+	return &v, nil
+}
+
+// ============================================================================
+// ACTIVITIES
+// ============================================================================
+
+// CreateActivity inserts a new activity.
+func (r *ActivityRepository) CreateActivity(ctx context.Context, tx *sql.Tx, a *models.Activity) error {
+	if err := a.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	now := time.Now().UTC()
+	a.CreatedAt = now
+	a.UpdatedAt = now
+
+	_, err := r.getExecer(tx).ExecContext(ctx, `
+		INSERT INTO activities (
+			id, venue_id, code, name, activity_type, vocation_id, instructor_id,
+			weekdays, start_hour, duration_hours, min_age, max_age,
+			is_active, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		a.ID,
+		a.VenueID,
+		a.Code,
+		a.Name,
+		string(a.ActivityType),
+		a.VocationID,
+		a.InstructorID,
+		int(a.Weekdays),
+		a.StartHour,
+		a.DurationHours,
+		a.MinAge,
+		a.MaxAge,
+		boolToInt(a.IsActive),
+		a.CreatedAt.Format(time.RFC3339),
+		a.UpdatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("inserting activity: %w", constraintError(err))
+	}
+	return nil
+}
+
+// SetActive puts an activity back on the schedule or takes it off.
+func (r *ActivityRepository) SetActive(ctx context.Context, tx *sql.Tx, id string, active bool) error {
+	result, err := r.getExecer(tx).ExecContext(ctx, `
+		UPDATE activities SET is_active = ?, updated_at = ? WHERE id = ?`,
+		boolToInt(active),
+		time.Now().UTC().Format(time.RFC3339),
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("updating activity: %w", constraintError(err))
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("activity %w: %s", ErrNotFound, id)
+	}
+	return nil
+}
+
+// GetActivity retrieves an activity by ID.
+func (r *ActivityRepository) GetActivity(ctx context.Context, id string) (*models.Activity, error) {
+	a, err := r.scanActivity(r.db.QueryRowContext(ctx, activitySelect+" WHERE a.id = ?", id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("activity %w: %s", ErrNotFound, id)
+	}
+	return a, err
+}
+
+// GetActivityByCode retrieves an activity held in a venue of the vault by
+// its code.
+func (r *ActivityRepository) GetActivityByCode(ctx context.Context, code string) (*models.Activity, error) {
+	a, err := r.scanActivity(r.db.QueryRowContext(ctx, activitySelect+`
+		WHERE a.code = ? AND `+venueVaultCondition, code, r.vault, r.vault))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("activity %w: %s", ErrNotFound, code)
+	}
+	return a, err
+}
+
+// ListActivities retrieves activities, ordered by start hour and code. With
+// activeOnly set, activities taken off the schedule or held in closed
+// venues are left out.
+func (r *ActivityRepository) ListActivities(ctx context.Context, activeOnly bool) ([]*models.Activity, error) {
+	query := activitySelect + " WHERE " + venueVaultCondition
+	if activeOnly {
+		query += " AND a.is_active = 1 AND v.is_active = 1"
+	}
+	query += " ORDER BY a.start_hour, a.code"
+
+	rows, err := r.db.QueryContext(ctx, query, r.vault, r.vault)
+	if err != nil {
+		return nil, fmt.Errorf("querying activities: %w", err)
+	}
+	return collect(rows, r.scanActivity)
+}
+
+// venueVaultCondition limits activities, aliased a and joined to their
+// venue v, to those held in venues of one vault, taking the vault number
+// twice like vaultCondition.
+const venueVaultCondition = "(? = 0 OR v.vault_id = ?)"
+
+const activitySelect = `
+	SELECT a.id, a.venue_id, a.code, a.name, a.activity_type, a.vocation_id,
+		a.instructor_id, a.weekdays, a.start_hour, a.duration_hours, a.min_age,
+		a.max_age, a.is_active, a.created_at, a.updated_at
+	FROM activities a
+	JOIN venues v ON v.id = a.venue_id`
+
+// scanActivity scans an activity from a single row or a rows iterator.
+func (r *ActivityRepository) scanActivity(row rowScanner) (*models.Activity, error) {
+	var a models.Activity
+	var activityType, createdStr, updatedStr string
+	var vocationID, instructorID sql.NullString
+	var minAge, maxAge sql.NullInt64
+	var weekdays, active int
+
+	err := row.Scan(
+		&a.ID, &a.VenueID, &a.Code, &a.Name, &activityType, &vocationID,
+		&instructorID, &weekdays, &a.StartHour, &a.DurationHours, &minAge,
+		&maxAge, &active, &createdStr, &updatedStr,
+	)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scanning activity: %w", err)
+	}
+
+	a.ActivityType = models.ActivityType(activityType)
+	a.VocationID = stringPtr(vocationID)
+	a.InstructorID = stringPtr(instructorID)
+	a.Weekdays = models.Weekdays(weekdays)
+	a.MinAge = intPtr(minAge)
+	a.MaxAge = intPtr(maxAge)
+	a.IsActive = active == 1
+	a.CreatedAt = parseTime(time.RFC3339, createdStr)
+	a.UpdatedAt = parseTime(time.RFC3339, updatedStr)
+	return &a, nil
+}
+
+// ============================================================================
+// ENROLLMENTS
+// ============================================================================
+
+// Enroll enrolls a resident in an activity.
+func (r *ActivityRepository) Enroll(ctx context.Context, tx *sql.Tx, activityID, residentID string, at time.Time) error {
+	_, err := r.getExecer(tx).ExecContext(ctx, `
+		INSERT INTO activity_enrollments (activity_id, resident_id, enrolled_at)
+		VALUES (?, ?, ?)`,
+		activityID,
+		residentID,
+		at.UTC().Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("inserting enrollment: %w", constraintError(err))
+	}
+	return nil
+}
+
+// Unenroll removes a resident from an activity.
+func (r *ActivityRepository) Unenroll(ctx context.Context, tx *sql.Tx, activityID, residentID string) error {
+	result, err := r.getExecer(tx).ExecContext(ctx, `
+		DELETE FROM activity_enrollments WHERE activity_id = ? AND resident_id = ?`,
+		activityID, residentID)
+	if err != nil {
+		return fmt.Errorf("deleting enrollment: %w", constraintError(err))
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("enrollment %w: %s in %s", ErrNotFound, residentID, activityID)
+	}
+	return nil
+}
+
+// CountEnrolled counts the residents enrolled in an activity.
+func (r *ActivityRepository) CountEnrolled(ctx context.Context, activityID string) (int, error) {
+	var n int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM activity_enrollments WHERE activity_id = ?`, activityID).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("counting enrollments: %w", err)
+	}
+	return n, nil
+}
+
+// ListEnrollees retrieves the ACTIVE residents enrolled in an activity with
+// their latest morale score, by registry number. Residents away from the
+// vault or in quarantine cannot attend and are left out.
+func (r *ActivityRepository) ListEnrollees(ctx context.Context, activityID string) ([]*models.Enrollee, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT e.resident_id, COALESCE(m.score, ?)
+		FROM activity_enrollments e
+		JOIN residents res ON res.id = e.resident_id
+		LEFT JOIN resident_morale m ON m.resident_id = e.resident_id
+		WHERE e.activity_id = ? AND res.status = 'ACTIVE'
+		ORDER BY res.registry_number`,
+		models.MoraleBaseline, activityID)
+	if err != nil {
+		return nil, fmt.Errorf("querying enrollees: %w", err)
+	}
+	return collect(rows, func(row rowScanner) (*models.Enrollee, error) {
+		var e models.Enrollee
+		if err := row.Scan(&e.ResidentID, &e.Morale); err != nil {
+			return nil, fmt.Errorf("scanning enrollee: %w", err)
+		}
+		return &e, nil
+	})
+}
+
+// ============================================================================
+// ATTENDANCE
+// ============================================================================
+
+// CreateAttendance records whether a resident attended a session.
+func (r *ActivityRepository) CreateAttendance(ctx context.Context, tx *sql.Tx, a *models.Attendance) error {
+	if a.ID == "" || a.ActivityID == "" || a.ResidentID == "" || a.SessionAt.IsZero() {
+		return fmt.Errorf("%w: id, activity_id, resident_id and session_at are required", ErrValidation)
+	}
+
+	a.RecordedAt = time.Now().UTC()
+
+	_, err := r.getExecer(tx).ExecContext(ctx, `
+		INSERT INTO activity_attendance (id, activity_id, resident_id, session_at, present, hours, recorded_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		a.ID,
+		a.ActivityID,
+		a.ResidentID,
+		a.SessionAt.UTC().Format(time.RFC3339),
+		boolToInt(a.Present),
+		a.Hours,
+		a.RecordedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("inserting attendance: %w", constraintError(err))
+	}
+	return nil
+}
+
+// ListAttendance sums the attendance of each activity of the vault over
+// the sessions since a time, by activity code.
+func (r *ActivityRepository) ListAttendance(ctx context.Context, since time.Time) ([]*models.ActivityAttendance, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT a.id, COUNT(DISTINCT t.session_at),
+			COALESCE(SUM(t.present), 0), COALESCE(SUM(1 - t.present), 0),
+			COALESCE(SUM(t.hours), 0)
+		FROM activity_attendance t
+		JOIN activities a ON a.id = t.activity_id
+		JOIN venues v ON v.id = a.venue_id
+		WHERE t.session_at >= ? AND `+venueVaultCondition+`
+		GROUP BY a.id
+		ORDER BY a.code`,
+		since.UTC().Format(time.RFC3339), r.vault, r.vault)
+	if err != nil {
+		return nil, fmt.Errorf("querying attendance: %w", err)
+	}
+	return collect(rows, func(row rowScanner) (*models.ActivityAttendance, error) {
+		var a models.ActivityAttendance
+		if err := row.Scan(&a.ActivityID, &a.Sessions, &a.Present, &a.Absent, &a.Hours); err != nil {
+			return nil, fmt.Errorf("scanning attendance: %w", err)
+		}
+		return &a, nil
+	})
+}
+
+func (r *ActivityRepository) getExecer(tx *sql.Tx) interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+} {
+	if tx != nil {
+		return tx
+	}
+	return r.db
+}
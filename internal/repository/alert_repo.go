@@ -0,0 +1,277 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// AlertRepository handles recorded alert and routing rule data access.
+type AlertRepository struct {
+	db    *sql.DB
+	vault int // Vault lists are limited to, 0 for every vault
+}
+
+// NewAlertRepository creates a new alert repository.
+func NewAlertRepository(db *sql.DB) *AlertRepository {
+	return &AlertRepository{db: db}
+}
+
+// ForVault returns a copy of the repository whose lists are limited to
+// alerts and rules of the vault, and which records them there.
+func (r *AlertRepository) ForVault(vault int) *AlertRepository {
+	scoped := *r
+	scoped.vault = vault
+	return &scoped
+}
+
+// CreateRoute inserts a new routing rule.
+func (r *AlertRepository) CreateRoute(ctx context.Context, route *models.AlertRoute) error {
+	if err := route.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	route.CreatedAt = time.Now().UTC()
+	if route.VaultID == 0 {
+		route.VaultID = r.vault
+	}
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO alert_routes (
+			id, source, min_severity, department, operator_id, escalate_to,
+			escalate_after_hours, vault_id, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		route.ID,
+		route.Source,
+		string(route.MinSeverity),
+		nullableString(string(route.Department)),
+		route.OperatorID,
+		nullableString(string(route.EscalateTo)),
+		sql.NullInt64{Int64: int64(route.EscalateAfterHours), Valid: route.EscalateAfterHours > 0},
+		route.VaultID,
+		route.CreatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("inserting alert route: %w", constraintError(err))
+	}
+	return nil
+}
+
+// DeleteRoute removes a routing rule. Alerts it routed stay with whoever
+// they were routed to.
+func (r *AlertRepository) DeleteRoute(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM alert_routes WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("deleting alert route: %w", constraintError(err))
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("alert route %w: %s", ErrNotFound, id)
+	}
+	return nil
+}
+
+// ListRoutes retrieves the routing rules, earliest created first, with
+// their operator's registry number.
+func (r *AlertRepository) ListRoutes(ctx context.Context) ([]*models.AlertRoute, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT r.id, r.source, r.min_severity, r.department, r.operator_id, r.escalate_to,
+			r.escalate_after_hours, r.vault_id, r.created_at, COALESCE(o.registry_number, '')
+		FROM alert_routes r
+		LEFT JOIN residents o ON o.id = r.operator_id
+		WHERE (? = 0 OR r.vault_id = ?)
+		ORDER BY r.created_at, r.id`,
+		r.vault, r.vault)
+	if err != nil {
+		return nil, fmt.Errorf("querying alert routes: %w", err)
+	}
+	return collect(rows, r.scanRoute)
+}
+
+// Create inserts a new alert.
+func (r *AlertRepository) Create(ctx context.Context, tx *sql.Tx, a *models.Alert) error {
+	if err := a.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	a.CreatedAt = time.Now().UTC()
+	if a.VaultID == 0 {
+		a.VaultID = r.vault
+	}
+
+	_, err := r.getExecer(tx).ExecContext(ctx, `
+		INSERT INTO alerts (
+			id, source, severity, message, raised_at, route_id, department,
+			operator_id, status, vault_id, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		a.ID,
+		a.Source,
+		string(a.Severity),
+		a.Message,
+		a.RaisedAt.UTC().Format(time.RFC3339),
+		a.RouteID,
+		nullableString(string(a.Department)),
+		a.OperatorID,
+		string(a.Status),
+		a.VaultID,
+		a.CreatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("inserting alert: %w", constraintError(err))
+	}
+	return nil
+}
+
+// Update saves an alert's recipient, acknowledgment, resolution and
+// escalation.
+func (r *AlertRepository) Update(ctx context.Context, tx *sql.Tx, a *models.Alert) error {
+	if err := a.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	result, err := r.getExecer(tx).ExecContext(ctx, `
+		UPDATE alerts
+		SET department = ?, operator_id = ?, status = ?, acknowledged_at = ?,
+			acknowledged_by = ?, acknowledgment_notes = ?, resolved_at = ?,
+			resolved_by = ?, resolution_notes = ?, escalated_at = ?
+		WHERE id = ?`,
+		nullableString(string(a.Department)),
+		a.OperatorID,
+		string(a.Status),
+		nullableTimePtrRFC3339(a.AcknowledgedAt),
+		a.AcknowledgedBy,
+		nullableString(a.AcknowledgmentNotes),
+		nullableTimePtrRFC3339(a.ResolvedAt),
+		a.ResolvedBy,
+		nullableString(a.ResolutionNotes),
+		nullableTimePtrRFC3339(a.EscalatedAt),
+		a.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("updating alert: %w", constraintError(err))
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("alert %w: %s", ErrNotFound, a.ID)
+	}
+	return nil
+}
+
+// GetByID retrieves an alert by ID.
+func (r *AlertRepository) GetByID(ctx context.Context, id string) (*models.Alert, error) {
+	a, err := r.scanAlert(r.db.QueryRowContext(ctx, alertSelect+" WHERE a.id = ?", id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("alert %w: %s", ErrNotFound, id)
+	}
+	return a, err
+}
+
+// List retrieves the alerts not yet resolved, or every alert, open ones
+// first, then latest raised first.
+func (r *AlertRepository) List(ctx context.Context, includeResolved bool) ([]*models.Alert, error) {
+	rows, err := r.db.QueryContext(ctx, alertSelect+`
+		WHERE (? = 0 OR a.vault_id = ?) AND (? OR a.status != 'RESOLVED')
+		ORDER BY a.status != 'OPEN', a.raised_at DESC, a.id`,
+		r.vault, r.vault, includeResolved)
+	if err != nil {
+		return nil, fmt.Errorf("querying alerts: %w", err)
+	}
+	return collect(rows, r.scanAlert)
+}
+
+// ListUnescalated retrieves the open alerts raised at or before until that
+// have not been escalated, earliest raised first.
+func (r *AlertRepository) ListUnescalated(ctx context.Context, until time.Time) ([]*models.Alert, error) {
+	rows, err := r.db.QueryContext(ctx, alertSelect+`
+		WHERE (? = 0 OR a.vault_id = ?) AND a.status = 'OPEN' AND a.escalated_at IS NULL
+			AND a.raised_at <= ?
+		ORDER BY a.raised_at, a.id`,
+		r.vault, r.vault, until.UTC().Format(time.RFC3339))
+	if err != nil {
+		return nil, fmt.Errorf("querying alerts: %w", err)
+	}
+	return collect(rows, r.scanAlert)
+}
+
+// alertSelect selects alerts, aliased a, with the registry numbers of the
+// operator they are routed to and of who acknowledged and resolved them.
+const alertSelect = `
+	SELECT a.id, a.source, a.severity, a.message, a.raised_at, a.route_id, a.department,
+		a.operator_id, a.status, a.acknowledged_at, a.acknowledged_by, a.acknowledgment_notes,
+		a.resolved_at, a.resolved_by, a.resolution_notes, a.escalated_at, a.vault_id, a.created_at,
+		COALESCE(o.registry_number, ''), COALESCE(k.registry_number, ''), COALESCE(v.registry_number, '')
+	FROM alerts a
+	LEFT JOIN residents o ON o.id = a.operator_id
+	LEFT JOIN residents k ON k.id = a.acknowledged_by
+	LEFT JOIN residents v ON v.id = a.resolved_by`
+
+// scanAlert scans an alert from a single row or a rows iterator.
+func (r *AlertRepository) scanAlert(row rowScanner) (*models.Alert, error) {
+	var a models.Alert
+	var severity, status, raisedStr, createdStr string
+	var routeID, department, operatorID, ackStr, ackBy, ackNotes sql.NullString
+	var resolvedStr, resolvedBy, resolution, escalatedStr sql.NullString
+
+	err := row.Scan(
+		&a.ID, &a.Source, &severity, &a.Message, &raisedStr, &routeID, &department,
+		&operatorID, &status, &ackStr, &ackBy, &ackNotes,
+		&resolvedStr, &resolvedBy, &resolution, &escalatedStr, &a.VaultID, &createdStr,
+		&a.OperatorRegistry, &a.AcknowledgedRegistry, &a.ResolvedRegistry,
+	)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scanning alert: %w", err)
+	}
+
+	a.Severity = models.AlertSeverity(severity)
+	a.RaisedAt = parseTime(time.RFC3339, raisedStr)
+	a.RouteID = stringPtr(routeID)
+	a.Department = models.Department(department.String)
+	a.OperatorID = stringPtr(operatorID)
+	a.Status = models.AlertStatus(status)
+	a.AcknowledgedAt = timePtr(time.RFC3339, ackStr)
+	a.AcknowledgedBy = stringPtr(ackBy)
+	a.AcknowledgmentNotes = ackNotes.String
+	a.ResolvedAt = timePtr(time.RFC3339, resolvedStr)
+	a.ResolvedBy = stringPtr(resolvedBy)
+	a.ResolutionNotes = resolution.String
+	a.EscalatedAt = timePtr(time.RFC3339, escalatedStr)
+	a.CreatedAt = parseTime(time.RFC3339, createdStr)
+	return &a, nil
+}
+
+// scanRoute scans a routing rule from a rows iterator.
+func (r *AlertRepository) scanRoute(row rowScanner) (*models.AlertRoute, error) {
+	var route models.AlertRoute
+	var severity, createdStr string
+	var department, operatorID, escalateTo sql.NullString
+	var escalateAfter sql.NullInt64
+
+	err := row.Scan(
+		&route.ID, &route.Source, &severity, &department, &operatorID, &escalateTo,
+		&escalateAfter, &route.VaultID, &createdStr, &route.OperatorRegistry,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("scanning alert route: %w", err)
+	}
+
+	route.MinSeverity = models.AlertSeverity(severity)
+	route.Department = models.Department(department.String)
+	route.OperatorID = stringPtr(operatorID)
+	route.EscalateTo = models.Department(escalateTo.String)
+	route.EscalateAfterHours = int(escalateAfter.Int64)
+	route.CreatedAt = parseTime(time.RFC3339, createdStr)
+	return &route, nil
+}
+
+func (r *AlertRepository) getExecer(tx *sql.Tx) interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+} {
+	if tx != nil {
+		return tx
+	}
+	return r.db
+}
@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// AnnouncementRepository handles announcement and acknowledgment data
+// access.
+type AnnouncementRepository struct {
+	db    *sql.DB
+	vault int // Vault lists are limited to, 0 for every vault
+}
+
+// NewAnnouncementRepository creates a new announcement repository.
+func NewAnnouncementRepository(db *sql.DB) *AnnouncementRepository {
+	return &AnnouncementRepository{db: db}
+}
+
+// ForVault returns a copy of the repository whose lists are limited to
+// announcements of the vault, and which posts announcements there.
+func (r *AnnouncementRepository) ForVault(vault int) *AnnouncementRepository {
+	scoped := *r
+	scoped.vault = vault
+	return &scoped
+}
+
+// audienceCondition matches announcements, aliased a, addressed to a
+// resident, aliased res: every resident of the vault, the resident's
+// household, or the department of the resident's primary vocation.
+const audienceCondition = `res.vault_id = a.vault_id AND (a.audience = 'ALL'
+	OR (a.audience = 'HOUSEHOLD' AND a.household_id = res.household_id)
+	OR (a.audience = 'DEPARTMENT' AND a.department =
+		(SELECT v.department FROM vocations v WHERE v.id = res.primary_vocation_id)))`
+
+// postedCondition matches announcements, aliased a, posted at a time given
+// twice: issued and not yet expired.
+const postedCondition = `a.issued_at <= ? AND (a.expires_at IS NULL OR a.expires_at > ?)`
+
+// Create inserts a new announcement.
+func (r *AnnouncementRepository) Create(ctx context.Context, tx *sql.Tx, a *models.Announcement) error {
+	if err := a.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	a.CreatedAt = time.Now().UTC()
+	if a.VaultID == 0 {
+		a.VaultID = r.vault
+	}
+
+	_, err := r.getExecer(tx).ExecContext(ctx, `
+		INSERT INTO announcements (
+			id, kind, title, body, audience, department, household_id,
+			issued_by, issued_at, expires_at, source, vault_id, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		a.ID,
+		string(a.Kind),
+		a.Title,
+		nullableString(a.Body),
+		string(a.Audience),
+		nullableString(string(a.Department)),
+		a.HouseholdID,
+		a.IssuedBy,
+		a.IssuedAt.Format(time.RFC3339),
+		nullableTimePtrRFC3339(a.ExpiresAt),
+		nullableString(a.Source),
+		a.VaultID,
+		a.CreatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("inserting announcement: %w", constraintError(err))
+	}
+	return nil
+}
+
+// GetByID retrieves an announcement by ID, with its recipients and
+// acknowledgments.
+func (r *AnnouncementRepository) GetByID(ctx context.Context, id string) (*models.Announcement, error) {
+	a, err := r.scanAnnouncement(r.db.QueryRowContext(ctx, announcementSelect+" WHERE a.id = ?", "", id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("announcement %w: %s", ErrNotFound, id)
+	}
+	return a, err
+}
+
+// HasSource reports whether a system notice raised by source has been
+// posted.
+func (r *AnnouncementRepository) HasSource(ctx context.Context, source string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM announcements WHERE source = ?)`, source).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("checking announcement source: %w", err)
+	}
+	return exists, nil
+}
+
+// List retrieves the announcements posted at at, or every announcement if
+// at is nil, latest issued first, with their recipients and
+// acknowledgments.
+func (r *AnnouncementRepository) List(ctx context.Context, at *time.Time) ([]*models.Announcement, error) {
+	query := announcementSelect + " WHERE " + vaultAnnouncementCondition
+	args := []any{"", r.vault, r.vault}
+	if at != nil {
+		stamp := at.UTC().Format(time.RFC3339)
+		query += " AND " + postedCondition
+		args = append(args, stamp, stamp)
+	}
+	query += " ORDER BY a.issued_at DESC, a.id"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying announcements: %w", err)
+	}
+	return collect(rows, r.scanAnnouncement)
+}
+
+// Inbox retrieves the announcements posted at at that are addressed to a
+// resident, unacknowledged first, then latest issued first. Each is marked
+// with whether the resident has acknowledged it.
+func (r *AnnouncementRepository) Inbox(ctx context.Context, residentID string, at time.Time) ([]*models.Announcement, error) {
+	stamp := at.UTC().Format(time.RFC3339)
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT * FROM (`+announcementSelect+`
+			JOIN residents res ON res.id = ?
+			WHERE `+audienceCondition+` AND `+postedCondition+`)
+		ORDER BY acknowledged, issued_at DESC, id`,
+		residentID, residentID, stamp, stamp)
+	if err != nil {
+		return nil, fmt.Errorf("querying inbox: %w", err)
+	}
+	return collect(rows, r.scanAnnouncement)
+}
+
+// Acknowledge records that a resident has read an announcement. A second
+// acknowledgment keeps the first.
+func (r *AnnouncementRepository) Acknowledge(ctx context.Context, tx *sql.Tx, announcementID, residentID string, at time.Time) error {
+	_, err := r.getExecer(tx).ExecContext(ctx, `
+		INSERT INTO announcement_acks (announcement_id, resident_id, acknowledged_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT (announcement_id, resident_id) DO NOTHING`,
+		announcementID,
+		residentID,
+		at.UTC().Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("acknowledging announcement: %w", constraintError(err))
+	}
+	return nil
+}
+
+// vaultAnnouncementCondition limits announcements, aliased a, to one vault,
+// taking the vault number twice like vaultCondition.
+const vaultAnnouncementCondition = "(? = 0 OR a.vault_id = ?)"
+
+// announcementSelect selects announcements, aliased a, with the issuer's
+// registry number, the household's designation, the active residents
+// addressed, the acknowledgments, and whether the resident given as its
+// first argument acknowledged it. Queries for no resident pass "", leaving
+// it false.
+const announcementSelect = `
+	SELECT a.id, a.kind, a.title, a.body, a.audience, a.department, a.household_id,
+		a.issued_by, a.issued_at, a.expires_at, a.source, a.vault_id, a.created_at,
+		COALESCE(o.registry_number, ''), COALESCE(h.designation, ''),
+		(SELECT COUNT(*) FROM residents res WHERE res.status = 'ACTIVE' AND ` + audienceCondition + `) AS recipients,
+		(SELECT COUNT(*) FROM announcement_acks k WHERE k.announcement_id = a.id) AS acknowledgments,
+		EXISTS (SELECT 1 FROM announcement_acks k WHERE k.announcement_id = a.id AND k.resident_id = ?) AS acknowledged
+	FROM announcements a
+	LEFT JOIN residents o ON o.id = a.issued_by
+	LEFT JOIN households h ON h.id = a.household_id`
+
+// scanAnnouncement scans an announcement from a single row or a rows
+// iterator.
+func (r *AnnouncementRepository) scanAnnouncement(row rowScanner) (*models.Announcement, error) {
+	var a models.Announcement
+	var kind, audience, issuedStr, createdStr string
+	var body, department, householdID, issuedBy, expiresStr, source sql.NullString
+	var acknowledged int
+
+	err := row.Scan(
+		&a.ID, &kind, &a.Title, &body, &audience, &department, &householdID,
+		&issuedBy, &issuedStr, &expiresStr, &source, &a.VaultID, &createdStr,
+		&a.IssuerRegistry, &a.HouseholdDesignation, &a.Recipients, &a.Acknowledgments, &acknowledged,
+	)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scanning announcement: %w", err)
+	}
+
+	a.Kind = models.AnnouncementKind(kind)
+	a.Body = body.String
+	a.Audience = models.AnnouncementAudience(audience)
+	a.Department = models.Department(department.String)
+	a.HouseholdID = stringPtr(householdID)
+	a.IssuedBy = stringPtr(issuedBy)
+	a.IssuedAt = parseTime(time.RFC3339, issuedStr)
+	a.ExpiresAt = timePtr(time.RFC3339, expiresStr)
+	a.Source = source.String
+	a.CreatedAt = parseTime(time.RFC3339, createdStr)
+	a.Acknowledged = acknowledged == 1
+	return &a, nil
+}
+
+func (r *AnnouncementRepository) getExecer(tx *sql.Tx) interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+} {
+	if tx != nil {
+		return tx
+	}
+	return r.db
+}
@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// AptitudeRepository handles vocational aptitude assessment data access.
+type AptitudeRepository struct {
+	db *sql.DB
+}
+
+// NewAptitudeRepository creates a new aptitude repository.
+func NewAptitudeRepository(db *sql.DB) *AptitudeRepository {
+	return &AptitudeRepository{db: db}
+}
+
+// Create inserts a new assessment.
+func (r *AptitudeRepository) Create(ctx context.Context, tx *sql.Tx, a *models.AptitudeAssessment) error {
+	if err := a.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	now := time.Now().UTC()
+	a.CreatedAt = now
+	a.UpdatedAt = now
+
+	_, err := r.getExecer(tx).ExecContext(ctx, `
+		INSERT INTO aptitude_assessments (
+			id, resident_id, assessed_at, administered_by,
+			score_engineering, score_medical, score_security, score_food_production,
+			score_administration, score_education, score_sanitation, score_research,
+			recommended_vocation_id, applied_at, notes, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		a.ID,
+		a.ResidentID,
+		a.AssessedAt.Format(time.RFC3339),
+		a.AdministeredBy,
+		a.Scores[models.DepartmentEngineering],
+		a.Scores[models.DepartmentMedical],
+		a.Scores[models.DepartmentSecurity],
+		a.Scores[models.DepartmentFoodProduction],
+		a.Scores[models.DepartmentAdministration],
+		a.Scores[models.DepartmentEducation],
+		a.Scores[models.DepartmentSanitation],
+		a.Scores[models.DepartmentResearch],
+		a.RecommendedVocationID,
+		nullableTimePtrRFC3339(a.AppliedAt),
+		nullableString(a.Notes),
+		a.CreatedAt.Format(time.RFC3339),
+		a.UpdatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("inserting aptitude assessment: %w", constraintError(err))
+	}
+	return nil
+}
+
+// GetByID retrieves an assessment by ID.
+func (r *AptitudeRepository) GetByID(ctx context.Context, id string) (*models.AptitudeAssessment, error) {
+	a, err := r.scan(r.db.QueryRowContext(ctx, aptitudeSelect+" WHERE id = ?", id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("aptitude assessment %w: %s", ErrNotFound, id)
+	}
+	return a, err
+}
+
+// GetLatest retrieves a resident's most recent assessment.
+func (r *AptitudeRepository) GetLatest(ctx context.Context, residentID string) (*models.AptitudeAssessment, error) {
+	a, err := r.scan(r.db.QueryRowContext(ctx, aptitudeSelect+`
+		WHERE resident_id = ?
+		ORDER BY assessed_at DESC, created_at DESC
+		LIMIT 1`, residentID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("aptitude assessment %w: resident %s", ErrNotFound, residentID)
+	}
+	return a, err
+}
+
+// ListLatest retrieves the most recent assessment of every assessed
+// resident, keyed by resident ID.
+func (r *AptitudeRepository) ListLatest(ctx context.Context) (map[string]*models.AptitudeAssessment, error) {
+	rows, err := r.db.QueryContext(ctx, aptitudeSelect+`
+		ORDER BY resident_id, assessed_at DESC, created_at DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("querying aptitude assessments: %w", err)
+	}
+	defer rows.Close()
+
+	latest := make(map[string]*models.AptitudeAssessment)
+	for rows.Next() {
+		a, err := r.scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := latest[a.ResidentID]; !ok {
+			latest[a.ResidentID] = a
+		}
+	}
+	return latest, rows.Err()
+}
+
+// MarkApplied records that an assessment's recommended vocation was
+// assigned at the given time.
+func (r *AptitudeRepository) MarkApplied(ctx context.Context, tx *sql.Tx, id string, at time.Time) error {
+	result, err := r.getExecer(tx).ExecContext(ctx, `
+		UPDATE aptitude_assessments SET applied_at = ?, updated_at = ?
+		WHERE id = ? AND applied_at IS NULL AND recommended_vocation_id IS NOT NULL`,
+		at.Format(time.RFC3339),
+		time.Now().UTC().Format(time.RFC3339),
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("updating aptitude assessment: %w", constraintError(err))
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("unapplied aptitude assessment %w: %s", ErrNotFound, id)
+	}
+	return nil
+}
+
+const aptitudeSelect = `
+	SELECT id, resident_id, assessed_at, administered_by,
+		score_engineering, score_medical, score_security, score_food_production,
+		score_administration, score_education, score_sanitation, score_research,
+		recommended_vocation_id, applied_at, notes, created_at, updated_at
+	FROM aptitude_assessments`
+
+func (r *AptitudeRepository) getExecer(tx *sql.Tx) interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+} {
+	if tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+// scan scans an assessment from a single row or a rows iterator.
+func (r *AptitudeRepository) scan(row rowScanner) (*models.AptitudeAssessment, error) {
+	var a models.AptitudeAssessment
+	var administeredBy, vocationID, appliedAt, notes sql.NullString
+	var assessedStr, createdStr, updatedStr string
+	var eng, med, sec, food, adm, edu, san, res int
+
+	err := row.Scan(
+		&a.ID, &a.ResidentID, &assessedStr, &administeredBy,
+		&eng, &med, &sec, &food, &adm, &edu, &san, &res,
+		&vocationID, &appliedAt, &notes, &createdStr, &updatedStr,
+	)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scanning aptitude assessment: %w", err)
+	}
+
+	a.AssessedAt = parseTime(time.RFC3339, assessedStr)
+	a.AdministeredBy = stringPtr(administeredBy)
+	a.Scores = models.AptitudeScores{
+		models.DepartmentEngineering:    eng,
+		models.DepartmentMedical:        med,
+		models.DepartmentSecurity:       sec,
+		models.DepartmentFoodProduction: food,
+		models.DepartmentAdministration: adm,
+		models.DepartmentEducation:      edu,
+		models.DepartmentSanitation:     san,
+		models.DepartmentResearch:       res,
+	}
+	a.RecommendedVocationID = stringPtr(vocationID)
+	a.AppliedAt = timePtr(time.RFC3339, appliedAt)
+	a.Notes = notes.String
+	a.CreatedAt = parseTime(time.RFC3339, createdStr)
+	a.UpdatedAt = parseTime(time.RFC3339, updatedStr)
+	return &a, nil
+}
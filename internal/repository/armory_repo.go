@@ -0,0 +1,233 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// ArmoryRepository handles armory weapon and issue data access.
+type ArmoryRepository struct {
+	db    *sql.DB
+	vault int // Vault lists are limited to, 0 for every vault
+}
+
+// NewArmoryRepository creates a new armory repository.
+func NewArmoryRepository(db *sql.DB) *ArmoryRepository {
+	return &ArmoryRepository{db: db}
+}
+
+// ForVault returns a copy of the repository whose weapon and issue lists
+// are limited to weapons of the vault.
+func (r *ArmoryRepository) ForVault(vault int) *ArmoryRepository {
+	scoped := *r
+	scoped.vault = vault
+	return &scoped
+}
+
+// ============================================================================
+// WEAPONS
+// ============================================================================
+
+// SetWeapon inserts a weapon record, or updates the authorization level and
+// ammunition of the asset's existing one.
+func (r *ArmoryRepository) SetWeapon(ctx context.Context, tx *sql.Tx, w *models.ArmoryWeapon) error {
+	if err := w.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	now := time.Now().UTC()
+	w.CreatedAt = now
+	w.UpdatedAt = now
+
+	_, err := r.getExecer(tx).ExecContext(ctx, `
+		INSERT INTO armory_weapons (
+			asset_id, authorization_level, ammo_item_id, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (asset_id) DO UPDATE SET
+			authorization_level = excluded.authorization_level,
+			ammo_item_id = excluded.ammo_item_id,
+			updated_at = excluded.updated_at`,
+		w.AssetID,
+		w.AuthorizationLevel,
+		w.AmmoItemID,
+		w.CreatedAt.Format(time.RFC3339),
+		w.UpdatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("setting armory weapon: %w", constraintError(err))
+	}
+	return nil
+}
+
+// GetWeapon retrieves the weapon record of an asset.
+func (r *ArmoryRepository) GetWeapon(ctx context.Context, assetID string) (*models.ArmoryWeapon, error) {
+	w, err := r.scanWeapon(r.db.QueryRowContext(ctx, weaponSelect+" WHERE w.asset_id = ?", assetID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("armory weapon %w: asset %s", ErrNotFound, assetID)
+	}
+	return w, err
+}
+
+// ListWeapons retrieves every weapon record, ordered by serial number.
+func (r *ArmoryRepository) ListWeapons(ctx context.Context) ([]*models.ArmoryWeapon, error) {
+	rows, err := r.db.QueryContext(ctx, weaponSelect+`
+		JOIN assets a ON a.id = w.asset_id
+		WHERE (? = 0 OR a.vault_id = ?)
+		ORDER BY a.serial_number`, r.vault, r.vault)
+	if err != nil {
+		return nil, fmt.Errorf("querying armory weapons: %w", err)
+	}
+	return collect(rows, r.scanWeapon)
+}
+
+const weaponSelect = `
+	SELECT w.asset_id, w.authorization_level, w.ammo_item_id, w.created_at, w.updated_at
+	FROM armory_weapons w`
+
+// scanWeapon scans a weapon record from a single row or a rows iterator.
+func (r *ArmoryRepository) scanWeapon(row rowScanner) (*models.ArmoryWeapon, error) {
+	var w models.ArmoryWeapon
+	var ammoItemID sql.NullString
+	var createdStr, updatedStr string
+
+	err := row.Scan(&w.AssetID, &w.AuthorizationLevel, &ammoItemID, &createdStr, &updatedStr)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scanning armory weapon: %w", err)
+	}
+
+	w.AmmoItemID = stringPtr(ammoItemID)
+	w.CreatedAt = parseTime(time.RFC3339, createdStr)
+	w.UpdatedAt = parseTime(time.RFC3339, updatedStr)
+	return &w, nil
+}
+
+// ============================================================================
+// ISSUES
+// ============================================================================
+
+// CreateIssue inserts the issue record of a weapon custody.
+func (r *ArmoryRepository) CreateIssue(ctx context.Context, tx *sql.Tx, i *models.ArmoryIssue) error {
+	if err := i.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	i.CreatedAt = time.Now().UTC()
+
+	_, err := r.getExecer(tx).ExecContext(ctx, `
+		INSERT INTO armory_issues (
+			custody_id, approved_by, incident_id, drill, rounds_issued,
+			rounds_expended, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		i.CustodyID,
+		i.ApprovedBy,
+		i.IncidentID,
+		boolToInt(i.Drill),
+		i.RoundsIssued,
+		i.RoundsExpended,
+		i.CreatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("inserting armory issue: %w", constraintError(err))
+	}
+	return nil
+}
+
+// ReturnIssue records the rounds expended by the return of an issued
+// weapon.
+func (r *ArmoryRepository) ReturnIssue(ctx context.Context, tx *sql.Tx, i *models.ArmoryIssue) error {
+	if err := i.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	result, err := r.getExecer(tx).ExecContext(ctx, `
+		UPDATE armory_issues SET rounds_expended = ?
+		WHERE custody_id = ? AND rounds_expended IS NULL`,
+		i.RoundsExpended,
+		i.CustodyID,
+	)
+	if err != nil {
+		return fmt.Errorf("updating armory issue: %w", constraintError(err))
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("open armory issue %w: custody %s", ErrNotFound, i.CustodyID)
+	}
+	return nil
+}
+
+// GetIssue retrieves the issue record of a weapon custody.
+func (r *ArmoryRepository) GetIssue(ctx context.Context, custodyID string) (*models.ArmoryIssue, error) {
+	i, err := r.scanIssue(r.db.QueryRowContext(ctx, issueSelect+" WHERE i.custody_id = ?", custodyID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("armory issue %w: custody %s", ErrNotFound, custodyID)
+	}
+	return i, err
+}
+
+// ListOpenIssues retrieves the issue records of the weapons still in
+// custody, earliest issued first.
+func (r *ArmoryRepository) ListOpenIssues(ctx context.Context) ([]*models.ArmoryIssue, error) {
+	rows, err := r.db.QueryContext(ctx, issueSelect+`
+		JOIN asset_custody c ON c.id = i.custody_id
+		JOIN assets a ON a.id = c.asset_id
+		WHERE c.returned_at IS NULL AND (? = 0 OR a.vault_id = ?)
+		ORDER BY c.issued_at, c.id`, r.vault, r.vault)
+	if err != nil {
+		return nil, fmt.Errorf("querying armory issues: %w", err)
+	}
+	return collect(rows, r.scanIssue)
+}
+
+const issueSelect = `
+	SELECT i.custody_id, i.approved_by, i.incident_id, i.drill, i.rounds_issued,
+		i.rounds_expended, i.created_at,
+		COALESCE(inc.incident_number, ''), COALESCE(o.registry_number, '')
+	FROM armory_issues i
+	LEFT JOIN security_incidents inc ON inc.id = i.incident_id
+	LEFT JOIN residents o ON o.id = i.approved_by`
+
+// scanIssue scans an issue record, with its incident number and approving
+// officer's registry number, from a single row or a rows iterator.
+func (r *ArmoryRepository) scanIssue(row rowScanner) (*models.ArmoryIssue, error) {
+	var i models.ArmoryIssue
+	var approvedBy, incidentID sql.NullString
+	var expended sql.NullInt64
+	var drill int
+	var createdStr string
+
+	err := row.Scan(
+		&i.CustodyID, &approvedBy, &incidentID, &drill, &i.RoundsIssued,
+		&expended, &createdStr,
+		&i.IncidentNumber, &i.ApproverRegistry,
+	)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scanning armory issue: %w", err)
+	}
+
+	i.ApprovedBy = stringPtr(approvedBy)
+	i.IncidentID = stringPtr(incidentID)
+	i.Drill = drill == 1
+	i.RoundsExpended = intPtr(expended)
+	i.CreatedAt = parseTime(time.RFC3339, createdStr)
+	return &i, nil
+}
+
+func (r *ArmoryRepository) getExecer(tx *sql.Tx) interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+} {
+	if tx != nil {
+		return tx
+	}
+	return r.db
+}
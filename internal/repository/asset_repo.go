@@ -0,0 +1,396 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// AssetRepository handles asset, custody and condition report data access.
+type AssetRepository struct {
+	db    *sql.DB
+	vault int // Vault lists are limited to, 0 for every vault
+}
+
+// NewAssetRepository creates a new asset repository.
+func NewAssetRepository(db *sql.DB) *AssetRepository {
+	return &AssetRepository{db: db}
+}
+
+// ForVault returns a copy of the repository whose asset and custody lists
+// are limited to assets of the vault, and which registers assets there.
+// Lookups by ID or serial number still find assets of any vault.
+func (r *AssetRepository) ForVault(vault int) *AssetRepository {
+	scoped := *r
+	scoped.vault = vault
+	return &scoped
+}
+
+// ============================================================================
+// ASSETS
+// ============================================================================
+
+// Create inserts a new asset. A serial number already registered is
+// reported as ErrDuplicate.
+func (r *AssetRepository) Create(ctx context.Context, tx *sql.Tx, a *models.Asset) error {
+	if err := a.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	now := time.Now().UTC()
+	a.CreatedAt = now
+	a.UpdatedAt = now
+	if a.VaultID == 0 {
+		a.VaultID = r.vault
+	}
+
+	_, err := r.getExecer(tx).ExecContext(ctx, `
+		INSERT INTO assets (
+			id, serial_number, name, category, condition, location,
+			acquired_at, retired_at, notes, vault_id, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		a.ID,
+		a.SerialNumber,
+		a.Name,
+		string(a.Category),
+		string(a.Condition),
+		nullableString(a.Location),
+		nullableTime(a.AcquiredAt),
+		nullableTimePtrRFC3339(a.RetiredAt),
+		nullableString(a.Notes),
+		a.VaultID,
+		a.CreatedAt.Format(time.RFC3339),
+		a.UpdatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("inserting asset: %w", constraintError(err))
+	}
+	return nil
+}
+
+// Update updates an asset's name, condition, location, retirement and
+// notes. The serial number and category are fixed at registration.
+func (r *AssetRepository) Update(ctx context.Context, tx *sql.Tx, a *models.Asset) error {
+	if err := a.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	a.UpdatedAt = time.Now().UTC()
+
+	result, err := r.getExecer(tx).ExecContext(ctx, `
+		UPDATE assets SET
+			name = ?, condition = ?, location = ?, retired_at = ?, notes = ?, updated_at = ?
+		WHERE id = ?`,
+		a.Name,
+		string(a.Condition),
+		nullableString(a.Location),
+		nullableTimePtrRFC3339(a.RetiredAt),
+		nullableString(a.Notes),
+		a.UpdatedAt.Format(time.RFC3339),
+		a.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("updating asset: %w", constraintError(err))
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("asset %w: %s", ErrNotFound, a.ID)
+	}
+	return nil
+}
+
+// GetByID retrieves an asset by ID.
+func (r *AssetRepository) GetByID(ctx context.Context, id string) (*models.Asset, error) {
+	a, err := r.scan(r.db.QueryRowContext(ctx, assetSelect+" WHERE id = ?", id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("asset %w: %s", ErrNotFound, id)
+	}
+	return a, err
+}
+
+// GetBySerial retrieves an asset by its serial number.
+func (r *AssetRepository) GetBySerial(ctx context.Context, serial string) (*models.Asset, error) {
+	a, err := r.scan(r.db.QueryRowContext(ctx, assetSelect+" WHERE serial_number = ?", serial))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("asset %w: %s", ErrNotFound, serial)
+	}
+	return a, err
+}
+
+// List retrieves the assets matching the filter, ordered by category and
+// serial number.
+func (r *AssetRepository) List(ctx context.Context, filter models.AssetFilter) ([]*models.Asset, error) {
+	query := assetSelect + " WHERE " + vaultCondition
+	args := []any{r.vault, r.vault}
+	if filter.Category != "" {
+		query += " AND category = ?"
+		args = append(args, string(filter.Category))
+	}
+	if filter.ResidentID != "" {
+		query += " AND id IN (SELECT asset_id FROM asset_custody WHERE resident_id = ? AND returned_at IS NULL)"
+		args = append(args, filter.ResidentID)
+	}
+	if !filter.IncludeRetired {
+		query += " AND retired_at IS NULL"
+	}
+	query += " ORDER BY category, serial_number"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying assets: %w", err)
+	}
+	return collect(rows, r.scan)
+}
+
+const assetSelect = `
+	SELECT id, serial_number, name, category, condition, location,
+		acquired_at, retired_at, notes, vault_id, created_at, updated_at
+	FROM assets`
+
+// scan scans an asset from a single row or a rows iterator.
+func (r *AssetRepository) scan(row rowScanner) (*models.Asset, error) {
+	var a models.Asset
+	var location, acquiredAt, retiredAt, notes sql.NullString
+	var createdStr, updatedStr string
+
+	err := row.Scan(
+		&a.ID, &a.SerialNumber, &a.Name, &a.Category, &a.Condition, &location,
+		&acquiredAt, &retiredAt, &notes, &a.VaultID, &createdStr, &updatedStr,
+	)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scanning asset: %w", err)
+	}
+
+	a.Location = location.String
+	a.AcquiredAt = timePtr(time.DateOnly, acquiredAt)
+	a.RetiredAt = timePtr(time.RFC3339, retiredAt)
+	a.Notes = notes.String
+	a.CreatedAt = parseTime(time.RFC3339, createdStr)
+	a.UpdatedAt = parseTime(time.RFC3339, updatedStr)
+	return &a, nil
+}
+
+// ============================================================================
+// CUSTODY
+// ============================================================================
+
+// CreateCustody inserts a new custody period. Issuing an asset already in
+// custody is reported as ErrDuplicate.
+func (r *AssetRepository) CreateCustody(ctx context.Context, tx *sql.Tx, c *models.AssetCustody) error {
+	if err := c.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	c.CreatedAt = time.Now().UTC()
+
+	_, err := r.getExecer(tx).ExecContext(ctx, `
+		INSERT INTO asset_custody (
+			id, asset_id, resident_id, issued_at, issued_by, due_at, condition_out,
+			returned_at, received_by, condition_in, notes, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		c.ID,
+		c.AssetID,
+		c.ResidentID,
+		c.IssuedAt.UTC().Format(time.RFC3339),
+		c.IssuedBy,
+		nullableTimePtrRFC3339(c.DueAt),
+		string(c.ConditionOut),
+		nullableTimePtrRFC3339(c.ReturnedAt),
+		c.ReceivedBy,
+		nullableString(string(c.ConditionIn)),
+		nullableString(c.Notes),
+		c.CreatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("inserting asset custody: %w", constraintError(err))
+	}
+	return nil
+}
+
+// GetOpenCustody retrieves the open custody of an asset.
+func (r *AssetRepository) GetOpenCustody(ctx context.Context, assetID string) (*models.AssetCustody, error) {
+	c, err := r.scanCustody(r.db.QueryRowContext(ctx, custodySelect+`
+		WHERE c.asset_id = ? AND c.returned_at IS NULL`, assetID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("open custody %w: asset %s", ErrNotFound, assetID)
+	}
+	return c, err
+}
+
+// CloseCustody records the return of an asset in open custody: when, to
+// whom, in what condition and the receiver's notes.
+func (r *AssetRepository) CloseCustody(ctx context.Context, tx *sql.Tx, c *models.AssetCustody) error {
+	if err := c.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	result, err := r.getExecer(tx).ExecContext(ctx, `
+		UPDATE asset_custody SET returned_at = ?, received_by = ?, condition_in = ?, notes = ?
+		WHERE id = ? AND returned_at IS NULL`,
+		nullableTimePtrRFC3339(c.ReturnedAt),
+		c.ReceivedBy,
+		nullableString(string(c.ConditionIn)),
+		nullableString(c.Notes),
+		c.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("updating asset custody: %w", constraintError(err))
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("open custody %w: %s", ErrNotFound, c.ID)
+	}
+	return nil
+}
+
+// ListCustody retrieves an asset's custody history, most recent first.
+func (r *AssetRepository) ListCustody(ctx context.Context, assetID string) ([]*models.AssetCustody, error) {
+	rows, err := r.db.QueryContext(ctx, custodySelect+`
+		WHERE c.asset_id = ?
+		ORDER BY c.issued_at DESC, c.id`, assetID)
+	if err != nil {
+		return nil, fmt.Errorf("querying asset custody: %w", err)
+	}
+	return collect(rows, r.scanCustody)
+}
+
+// ListOpenCustody retrieves the open custody of every asset, earliest
+// issued first.
+func (r *AssetRepository) ListOpenCustody(ctx context.Context) ([]*models.AssetCustody, error) {
+	rows, err := r.db.QueryContext(ctx, custodySelect+`
+		WHERE c.returned_at IS NULL
+			AND c.asset_id IN (SELECT id FROM assets WHERE `+vaultCondition+`)
+		ORDER BY c.issued_at, c.id`, r.vault, r.vault)
+	if err != nil {
+		return nil, fmt.Errorf("querying asset custody: %w", err)
+	}
+	return collect(rows, r.scanCustody)
+}
+
+const custodySelect = `
+	SELECT c.id, c.asset_id, c.resident_id, c.issued_at, c.issued_by, c.due_at,
+		c.condition_out, c.returned_at, c.received_by, c.condition_in, c.notes, c.created_at,
+		r.registry_number, r.surname, r.given_names
+	FROM asset_custody c
+	JOIN residents r ON r.id = c.resident_id`
+
+// scanCustody scans a custody period, with its custodian's registry number
+// and name, from a single row or a rows iterator.
+func (r *AssetRepository) scanCustody(row rowScanner) (*models.AssetCustody, error) {
+	var c models.AssetCustody
+	var resident models.Resident
+	var issuedBy, dueAt, returnedAt, receivedBy, conditionIn, notes sql.NullString
+	var issuedStr, createdStr string
+
+	err := row.Scan(
+		&c.ID, &c.AssetID, &c.ResidentID, &issuedStr, &issuedBy, &dueAt,
+		&c.ConditionOut, &returnedAt, &receivedBy, &conditionIn, &notes, &createdStr,
+		&resident.RegistryNumber, &resident.Surname, &resident.GivenNames,
+	)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scanning asset custody: %w", err)
+	}
+
+	c.IssuedAt = parseTime(time.RFC3339, issuedStr)
+	c.IssuedBy = stringPtr(issuedBy)
+	c.DueAt = timePtr(time.RFC3339, dueAt)
+	c.ReturnedAt = timePtr(time.RFC3339, returnedAt)
+	c.ReceivedBy = stringPtr(receivedBy)
+	c.ConditionIn = models.AssetCondition(conditionIn.String)
+	c.Notes = notes.String
+	c.CreatedAt = parseTime(time.RFC3339, createdStr)
+	resident.ID = c.ResidentID
+	c.Resident = &resident
+	return &c, nil
+}
+
+// ============================================================================
+// CONDITION REPORTS
+// ============================================================================
+
+// CreateConditionReport inserts a new condition report.
+func (r *AssetRepository) CreateConditionReport(ctx context.Context, tx *sql.Tx, cr *models.AssetConditionReport) error {
+	if err := cr.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	cr.CreatedAt = time.Now().UTC()
+
+	_, err := r.getExecer(tx).ExecContext(ctx, `
+		INSERT INTO asset_condition_reports (
+			id, asset_id, condition, previous, recorded_at, recorded_by,
+			custody_id, notes, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		cr.ID,
+		cr.AssetID,
+		string(cr.Condition),
+		string(cr.Previous),
+		cr.RecordedAt.UTC().Format(time.RFC3339),
+		cr.RecordedBy,
+		cr.CustodyID,
+		nullableString(cr.Notes),
+		cr.CreatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("inserting asset condition report: %w", constraintError(err))
+	}
+	return nil
+}
+
+// ListConditionReports retrieves an asset's condition reports, most recent
+// first.
+func (r *AssetRepository) ListConditionReports(ctx context.Context, assetID string) ([]*models.AssetConditionReport, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, asset_id, condition, previous, recorded_at, recorded_by,
+			custody_id, notes, created_at
+		FROM asset_condition_reports
+		WHERE asset_id = ?
+		ORDER BY recorded_at DESC, id`, assetID)
+	if err != nil {
+		return nil, fmt.Errorf("querying asset condition reports: %w", err)
+	}
+	return collect(rows, r.scanConditionReport)
+}
+
+// scanConditionReport scans a condition report from a single row or a rows
+// iterator.
+func (r *AssetRepository) scanConditionReport(row rowScanner) (*models.AssetConditionReport, error) {
+	var cr models.AssetConditionReport
+	var recordedBy, custodyID, notes sql.NullString
+	var recordedStr, createdStr string
+
+	err := row.Scan(
+		&cr.ID, &cr.AssetID, &cr.Condition, &cr.Previous, &recordedStr, &recordedBy,
+		&custodyID, &notes, &createdStr,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("scanning asset condition report: %w", err)
+	}
+
+	cr.RecordedAt = parseTime(time.RFC3339, recordedStr)
+	cr.RecordedBy = stringPtr(recordedBy)
+	cr.CustodyID = stringPtr(custodyID)
+	cr.Notes = notes.String
+	cr.CreatedAt = parseTime(time.RFC3339, createdStr)
+	return &cr, nil
+}
+
+func (r *AssetRepository) getExecer(tx *sql.Tx) interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+} {
+	if tx != nil {
+		return tx
+	}
+	return r.db
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// AuditRepository handles audit log data access.
+type AuditRepository struct {
+	db *sql.DB
+}
+
+// NewAuditRepository creates a new audit repository.
+func NewAuditRepository(db *sql.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// Create appends an entry to the audit log.
+func (r *AuditRepository) Create(ctx context.Context, tx *sql.Tx, entry *models.AuditEntry) error {
+	if err := entry.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	query := `
+		INSERT INTO audit_log (
+			id, timestamp, actor_type, actor_id, action, entity_type, entity_id,
+			old_values, new_values
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	var execer interface {
+		ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	}
+	if tx != nil {
+		execer = tx
+	} else {
+		execer = r.db
+	}
+
+	_, err := execer.ExecContext(ctx, query,
+		entry.ID,
+		entry.Timestamp.UTC().Format(time.RFC3339),
+		string(entry.ActorType),
+		entry.ActorID,
+		entry.Action,
+		entry.EntityType,
+		entry.EntityID,
+		nullableString(entry.OldValues),
+		nullableString(entry.NewValues),
+	)
+	if err != nil {
+		return fmt.Errorf("inserting audit entry: %w", constraintError(err))
+	}
+
+	return nil
+}
+
+// ListBetween retrieves entries with a timestamp in [from, to) in time
+// order, optionally limited to one action.
+func (r *AuditRepository) ListBetween(ctx context.Context, from, to time.Time, action string) ([]*models.AuditEntry, error) {
+	query := `
+		SELECT id, timestamp, actor_type, actor_id, action, entity_type, entity_id,
+			old_values, new_values
+		FROM audit_log
+		WHERE timestamp >= ? AND timestamp < ?`
+	args := []any{from.UTC().Format(time.RFC3339), to.UTC().Format(time.RFC3339)}
+	if action != "" {
+		query += " AND action = ?"
+		args = append(args, action)
+	}
+	query += " ORDER BY timestamp, id"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying audit log: %w", err)
+	}
+	return collect(rows, r.scanEntry)
+}
+
+// scanEntry scans an audit entry from a rows iterator.
+func (r *AuditRepository) scanEntry(row rowScanner) (*models.AuditEntry, error) {
+	var entry models.AuditEntry
+	var timestampStr string
+	var actorID, oldValues, newValues sql.NullString
+	if err := row.Scan(
+		&entry.ID,
+		&timestampStr,
+		&entry.ActorType,
+		&actorID,
+		&entry.Action,
+		&entry.EntityType,
+		&entry.EntityID,
+		&oldValues,
+		&newValues,
+	); err != nil {
+		return nil, fmt.Errorf("scanning audit entry: %w", err)
+	}
+	entry.Timestamp = parseTime(time.RFC3339, timestampStr)
+	entry.ActorID = stringPtr(actorID)
+	entry.OldValues = oldValues.String
+	entry.NewValues = newValues.String
+	return &entry, nil
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// CareAssignmentRepository handles care assignment data access.
+type CareAssignmentRepository struct {
+	db *sql.DB
+}
+
+// NewCareAssignmentRepository creates a new care assignment repository.
+func NewCareAssignmentRepository(db *sql.DB) *CareAssignmentRepository {
+	return &CareAssignmentRepository{db: db}
+}
+
+// Create inserts a new care assignment. A second pending assignment for the
+// same dependent is reported as ErrDuplicate.
+func (r *CareAssignmentRepository) Create(ctx context.Context, tx *sql.Tx, care *models.CareAssignment) error {
+	if err := care.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	query := `
+		INSERT INTO care_assignments (
+			id, dependent_id, status, reason, opened_at, guardian_id,
+			household_id, resolved_at, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	now := time.Now().UTC()
+	care.CreatedAt = now
+	care.UpdatedAt = now
+
+	_, err := r.getExecer(tx).ExecContext(ctx, query,
+		care.ID,
+		care.DependentID,
+		string(care.Status),
+		care.Reason,
+		care.OpenedAt.Format(time.RFC3339),
+		care.GuardianID,
+		care.HouseholdID,
+		nullableTimePtrRFC3339(care.ResolvedAt),
+		care.CreatedAt.Format(time.RFC3339),
+		care.UpdatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("inserting care assignment: %w", constraintError(err))
+	}
+	return nil
+}
+
+// GetByID retrieves a care assignment by ID.
+func (r *CareAssignmentRepository) GetByID(ctx context.Context, id string) (*models.CareAssignment, error) {
+	care, err := r.scan(r.db.QueryRowContext(ctx, careAssignmentSelect+" WHERE id = ?", id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("care assignment %w: %s", ErrNotFound, id)
+	}
+	return care, err
+}
+
+// ListPending retrieves all pending care assignments, oldest first.
+func (r *CareAssignmentRepository) ListPending(ctx context.Context) ([]*models.CareAssignment, error) {
+	rows, err := r.db.QueryContext(ctx, careAssignmentSelect+`
+		WHERE status = 'PENDING'
+		ORDER BY opened_at, id`)
+	if err != nil {
+		return nil, fmt.Errorf("querying pending care assignments: %w", err)
+	}
+	return collect(rows, r.scan)
+}
+
+// CountPending returns the number of pending care assignments.
+func (r *CareAssignmentRepository) CountPending(ctx context.Context) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM care_assignments WHERE status = 'PENDING'").Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("counting pending care assignments: %w", err)
+	}
+	return count, nil
+}
+
+// Resolve records the outcome of a pending care assignment.
+func (r *CareAssignmentRepository) Resolve(ctx context.Context, tx *sql.Tx, care *models.CareAssignment) error {
+	if err := care.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	care.UpdatedAt = time.Now().UTC()
+
+	result, err := r.getExecer(tx).ExecContext(ctx, `
+		UPDATE care_assignments SET
+			status = ?, guardian_id = ?, household_id = ?, resolved_at = ?, updated_at = ?
+		WHERE id = ? AND status = 'PENDING'`,
+		string(care.Status),
+		care.GuardianID,
+		care.HouseholdID,
+		nullableTimePtrRFC3339(care.ResolvedAt),
+		care.UpdatedAt.Format(time.RFC3339),
+		care.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("updating care assignment: %w", constraintError(err))
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("pending care assignment %w: %s", ErrNotFound, care.ID)
+	}
+	return nil
+}
+
+const careAssignmentSelect = `
+	SELECT id, dependent_id, status, reason, opened_at, guardian_id,
+		household_id, resolved_at, created_at, updated_at
+	FROM care_assignments`
+
+func (r *CareAssignmentRepository) getExecer(tx *sql.Tx) interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+} {
+	if tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+// scan scans a care assignment from a single row or a rows iterator.
+func (r *CareAssignmentRepository) scan(row rowScanner) (*models.CareAssignment, error) {
+	var care models.CareAssignment
+	var guardianID, householdID, resolvedAt sql.NullString
+	var openedStr, createdStr, updatedStr string
+
+	err := row.Scan(
+		&care.ID, &care.DependentID, &care.Status, &care.Reason, &openedStr, &guardianID,
+		&householdID, &resolvedAt, &createdStr, &updatedStr,
+	)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scanning care assignment: %w", err)
+	}
+
+	care.OpenedAt = parseTime(time.RFC3339, openedStr)
+	care.GuardianID = stringPtr(guardianID)
+	care.HouseholdID = stringPtr(householdID)
+	care.ResolvedAt = timePtr(time.RFC3339, resolvedAt)
+	care.CreatedAt = parseTime(time.RFC3339, createdStr)
+	care.UpdatedAt = parseTime(time.RFC3339, updatedStr)
+	return &care, nil
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// CensusPresetRepository handles saved census filter data access.
+type CensusPresetRepository struct {
+	db    *sql.DB
+	vault int // Vault presets are limited to, 0 for every vault
+}
+
+// NewCensusPresetRepository creates a new census preset repository.
+func NewCensusPresetRepository(db *sql.DB) *CensusPresetRepository {
+	return &CensusPresetRepository{db: db}
+}
+
+// ForVault returns a copy of the repository whose lookups and lists are
+// limited to presets of the vault, and which saves them there.
+func (r *CensusPresetRepository) ForVault(vault int) *CensusPresetRepository {
+	scoped := *r
+	scoped.vault = vault
+	return &scoped
+}
+
+// Save inserts a preset, or replaces the filter and sort of the vault's
+// preset of the same name, regardless of case, keeping its ID.
+func (r *CensusPresetRepository) Save(ctx context.Context, p *models.CensusPreset) error {
+	if err := p.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	filter, err := json.Marshal(p.Filter)
+	if err != nil {
+		return fmt.Errorf("encoding census filter: %w", err)
+	}
+	p.CreatedAt = time.Now().UTC()
+	if p.VaultID == 0 {
+		p.VaultID = r.vault
+	}
+
+	var createdStr string
+	err = r.db.QueryRowContext(ctx, `
+		INSERT INTO census_presets (id, name, filter, sort_column, sort_direction, vault_id, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (vault_id, name) DO UPDATE
+		SET filter = excluded.filter, sort_column = excluded.sort_column,
+			sort_direction = excluded.sort_direction
+		RETURNING id, created_at`,
+		p.ID,
+		p.Name,
+		string(filter),
+		nullableString(p.Sort.Column),
+		nullableString(string(p.Sort.Direction)),
+		p.VaultID,
+		p.CreatedAt.Format(time.RFC3339),
+	).Scan(&p.ID, &createdStr)
+	if err != nil {
+		return fmt.Errorf("saving census preset: %w", constraintError(err))
+	}
+	p.CreatedAt = parseTime(time.RFC3339, createdStr)
+	return nil
+}
+
+// Delete removes a preset.
+func (r *CensusPresetRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM census_presets WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("deleting census preset: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("census preset %w: %s", ErrNotFound, id)
+	}
+	return nil
+}
+
+// GetByName retrieves the vault's preset of a name, regardless of case.
+func (r *CensusPresetRepository) GetByName(ctx context.Context, name string) (*models.CensusPreset, error) {
+	p, err := r.scan(r.db.QueryRowContext(ctx, censusPresetSelect+`
+		WHERE (? = 0 OR vault_id = ?) AND name = ?`,
+		r.vault, r.vault, name))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("census preset %w: %s", ErrNotFound, name)
+	}
+	return p, err
+}
+
+// List retrieves the presets in alphabetical order.
+func (r *CensusPresetRepository) List(ctx context.Context) ([]*models.CensusPreset, error) {
+	rows, err := r.db.QueryContext(ctx, censusPresetSelect+`
+		WHERE (? = 0 OR vault_id = ?)
+		ORDER BY name`,
+		r.vault, r.vault)
+	if err != nil {
+		return nil, fmt.Errorf("querying census presets: %w", err)
+	}
+	return collect(rows, r.scan)
+}
+
+const censusPresetSelect = `
+	SELECT id, name, filter, sort_column, sort_direction, vault_id, created_at
+	FROM census_presets`
+
+// scan scans a preset from a single row or a rows iterator.
+func (r *CensusPresetRepository) scan(row rowScanner) (*models.CensusPreset, error) {
+	var p models.CensusPreset
+	var filter, createdStr string
+	var sortColumn, sortDirection sql.NullString
+
+	err := row.Scan(&p.ID, &p.Name, &filter, &sortColumn, &sortDirection, &p.VaultID, &createdStr)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scanning census preset: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(filter), &p.Filter); err != nil {
+		return nil, fmt.Errorf("decoding census preset %s: %w", p.Name, err)
+	}
+	p.Sort = models.SortOption{Column: sortColumn.String, Direction: models.SortDirection(sortDirection.String)}
+	p.CreatedAt = parseTime(time.RFC3339, createdStr)
+	return &p, nil
+}
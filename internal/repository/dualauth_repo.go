@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// DualAuthRepository handles two-person authorization policies and operator
+// PINs.
+type DualAuthRepository struct {
+	db    *sql.DB
+	vault int // Vault policies are limited to, 0 for every vault
+}
+
+// NewDualAuthRepository creates a new dual authorization repository.
+func NewDualAuthRepository(db *sql.DB) *DualAuthRepository {
+	return &DualAuthRepository{db: db}
+}
+
+// ForVault returns a copy of the repository whose lookups and lists are
+// limited to policies of the vault, and which sets them there.
+func (r *DualAuthRepository) ForVault(vault int) *DualAuthRepository {
+	scoped := *r
+	scoped.vault = vault
+	return &scoped
+}
+
+// SetPolicy inserts a policy, or replaces the clearance and threshold of the
+// vault's policy for the same operation.
+func (r *DualAuthRepository) SetPolicy(ctx context.Context, p *models.DualAuthPolicy) error {
+	if err := p.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	p.UpdatedAt = time.Now().UTC()
+	if p.VaultID == 0 {
+		p.VaultID = r.vault
+	}
+
+	var createdStr string
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO dual_auth_policies (operation, min_clearance, threshold, vault_id, updated_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (vault_id, operation) DO UPDATE
+		SET min_clearance = excluded.min_clearance, threshold = excluded.threshold,
+			updated_at = excluded.updated_at
+		RETURNING created_at`,
+		string(p.Operation),
+		p.MinClearance,
+		p.Threshold,
+		p.VaultID,
+		p.UpdatedAt.Format(time.RFC3339),
+		p.UpdatedAt.Format(time.RFC3339),
+	).Scan(&createdStr)
+	if err != nil {
+		return fmt.Errorf("setting dual authorization policy: %w", constraintError(err))
+	}
+	p.CreatedAt = parseTime(time.RFC3339, createdStr)
+	return nil
+}
+
+// DeletePolicy removes the vault's policy for an operation.
+func (r *DualAuthRepository) DeletePolicy(ctx context.Context, op models.DualAuthOperation) error {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM dual_auth_policies WHERE `+vaultCondition+` AND operation = ?`,
+		r.vault, r.vault, string(op))
+	if err != nil {
+		return fmt.Errorf("deleting dual authorization policy: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("dual authorization policy %w: %s", ErrNotFound, op)
+	}
+	return nil
+}
+
+// GetPolicy retrieves the vault's policy for an operation, within tx if
+// given.
+func (r *DualAuthRepository) GetPolicy(ctx context.Context, tx *sql.Tx, op models.DualAuthOperation) (*models.DualAuthPolicy, error) {
+	p, err := r.scan(r.getQuerier(tx).QueryRowContext(ctx, dualAuthPolicySelect+`
+		WHERE `+vaultCondition+` AND operation = ?
+		ORDER BY vault_id DESC
+		LIMIT 1`,
+		r.vault, r.vault, string(op)))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("dual authorization policy %w: %s", ErrNotFound, op)
+	}
+	return p, err
+}
+
+// ListPolicies retrieves the policies in operation order.
+func (r *DualAuthRepository) ListPolicies(ctx context.Context) ([]*models.DualAuthPolicy, error) {
+	rows, err := r.db.QueryContext(ctx, dualAuthPolicySelect+`
+		WHERE `+vaultCondition+`
+		ORDER BY operation, vault_id`,
+		r.vault, r.vault)
+	if err != nil {
+		return nil, fmt.Errorf("querying dual authorization policies: %w", err)
+	}
+	return collect(rows, r.scan)
+}
+
+// SetPIN stores an operator's salted PIN hash, replacing any earlier one.
+func (r *DualAuthRepository) SetPIN(ctx context.Context, residentID, hash, salt string) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO operator_pins (resident_id, pin_hash, salt, set_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (resident_id) DO UPDATE
+		SET pin_hash = excluded.pin_hash, salt = excluded.salt, set_at = excluded.set_at`,
+		residentID, hash, salt, time.Now().UTC().Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("setting operator PIN: %w", constraintError(err))
+	}
+	return nil
+}
+
+// GetPIN retrieves an operator's PIN hash and its salt, within tx if given.
+func (r *DualAuthRepository) GetPIN(ctx context.Context, tx *sql.Tx, residentID string) (hash, salt string, err error) {
+	err = r.getQuerier(tx).QueryRowContext(ctx, `
+		SELECT pin_hash, salt FROM operator_pins WHERE resident_id = ?`, residentID).Scan(&hash, &salt)
+	if err == sql.ErrNoRows {
+		return "", "", fmt.Errorf("operator PIN %w: %s", ErrNotFound, residentID)
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("querying operator PIN: %w", err)
+	}
+	return hash, salt, nil
+}
+
+// getQuerier returns tx if given, otherwise the database.
+func (r *DualAuthRepository) getQuerier(tx *sql.Tx) interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+} {
+	if tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+const dualAuthPolicySelect = `
+	SELECT operation, min_clearance, threshold, vault_id, updated_at, created_at
+	FROM dual_auth_policies`
+
+// scan scans a policy from a single row or a rows iterator.
+func (r *DualAuthRepository) scan(row rowScanner) (*models.DualAuthPolicy, error) {
+	var p models.DualAuthPolicy
+	var updatedStr, createdStr string
+
+	err := row.Scan(&p.Operation, &p.MinClearance, &p.Threshold, &p.VaultID, &updatedStr, &createdStr)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scanning dual authorization policy: %w", err)
+	}
+
+	p.UpdatedAt = parseTime(time.RFC3339, updatedStr)
+	p.CreatedAt = parseTime(time.RFC3339, createdStr)
+	return &p, nil
+}
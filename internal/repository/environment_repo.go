@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// EnvironmentRepository handles environment sensor reading data access.
+type EnvironmentRepository struct {
+	db    *sql.DB
+	vault int // Vault readings and sectors are limited to, 0 for every vault
+}
+
+// NewEnvironmentRepository creates a new environment repository.
+func NewEnvironmentRepository(db *sql.DB) *EnvironmentRepository {
+	return &EnvironmentRepository{db: db}
+}
+
+// ForVault returns a copy of the repository whose readings and sectors are
+// limited to the vault, and which records readings there.
+func (r *EnvironmentRepository) ForVault(vault int) *EnvironmentRepository {
+	scoped := *r
+	scoped.vault = vault
+	return &scoped
+}
+
+// CreateReading inserts a new sensor reading.
+func (r *EnvironmentRepository) CreateReading(ctx context.Context, tx *sql.Tx, reading *models.EnvironmentReading) error {
+	if err := reading.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	reading.CreatedAt = time.Now().UTC()
+	if reading.VaultID == 0 {
+		reading.VaultID = r.vault
+	}
+
+	_, err := r.getExecer(tx).ExecContext(ctx, `
+		INSERT INTO environment_readings (id, sector, metric, value, level, read_at, vault_id, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		reading.ID,
+		reading.Sector,
+		string(reading.Metric),
+		reading.Value,
+		string(reading.Level),
+		reading.ReadAt.UTC().Format(time.RFC3339),
+		reading.VaultID,
+		reading.CreatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("inserting environment reading: %w", constraintError(err))
+	}
+	return nil
+}
+
+// DeleteReadingsBefore deletes the readings taken before a time, returning
+// how many it deleted.
+func (r *EnvironmentRepository) DeleteReadingsBefore(ctx context.Context, tx *sql.Tx, before time.Time) (int64, error) {
+	result, err := r.getExecer(tx).ExecContext(ctx, `
+		DELETE FROM environment_readings WHERE read_at < ? AND `+vaultCondition,
+		before.UTC().Format(time.RFC3339), r.vault, r.vault)
+	if err != nil {
+		return 0, fmt.Errorf("deleting environment readings: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// LatestReadings retrieves the latest reading of each sector and metric
+// taken at or before a time, by sector and metric.
+func (r *EnvironmentRepository) LatestReadings(ctx context.Context, at time.Time) ([]*models.EnvironmentReading, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, sector, metric, value, level, read_at, vault_id, created_at
+		FROM environment_readings e
+		WHERE `+vaultCondition+`
+			AND read_at = (
+				SELECT MAX(x.read_at) FROM environment_readings x
+				WHERE x.vault_id = e.vault_id AND x.sector = e.sector AND x.metric = e.metric
+					AND x.read_at <= ?)
+		ORDER BY sector, metric`,
+		r.vault, r.vault, at.UTC().Format(time.RFC3339))
+	if err != nil {
+		return nil, fmt.Errorf("querying latest environment readings: %w", err)
+	}
+	return collect(rows, r.scanReading)
+}
+
+// ListReadings retrieves the readings taken after from and up to and
+// including to, oldest first.
+func (r *EnvironmentRepository) ListReadings(ctx context.Context, from, to time.Time) ([]*models.EnvironmentReading, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, sector, metric, value, level, read_at, vault_id, created_at
+		FROM environment_readings
+		WHERE read_at > ? AND read_at <= ? AND `+vaultCondition+`
+		ORDER BY read_at, sector, metric`,
+		from.UTC().Format(time.RFC3339), to.UTC().Format(time.RFC3339), r.vault, r.vault)
+	if err != nil {
+		return nil, fmt.Errorf("querying environment readings: %w", err)
+	}
+	return collect(rows, r.scanReading)
+}
+
+// LastReadAt returns when the latest reading was taken, or nil if none has
+// been.
+func (r *EnvironmentRepository) LastReadAt(ctx context.Context) (*time.Time, error) {
+	var last sql.NullString
+	err := r.db.QueryRowContext(ctx, `
+		SELECT MAX(read_at) FROM environment_readings WHERE `+vaultCondition,
+		r.vault, r.vault).Scan(&last)
+	if err != nil {
+		return nil, fmt.Errorf("querying last environment reading: %w", err)
+	}
+	return timePtr(time.RFC3339, last), nil
+}
+
+// ListSectors retrieves the sectors with sensors: those of the quarters
+// housing active residents and those facility systems are located in.
+func (r *EnvironmentRepository) ListSectors(ctx context.Context) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT q.sector FROM residents res
+		LEFT JOIN households h ON h.id = res.household_id
+		JOIN quarters q ON q.id = COALESCE(res.quarters_id, h.quarters_id)
+		WHERE res.status = 'ACTIVE' AND (? = 0 OR res.vault_id = ?)
+		UNION
+		SELECT location_sector FROM facility_systems WHERE `+vaultCondition+`
+		ORDER BY 1`,
+		r.vault, r.vault, r.vault, r.vault)
+	if err != nil {
+		return nil, fmt.Errorf("querying sectors: %w", err)
+	}
+	return collect(rows, func(row rowScanner) (string, error) {
+		var sector string
+		if err := row.Scan(&sector); err != nil {
+			return "", fmt.Errorf("scanning sector: %w", err)
+		}
+		return sector, nil
+	})
+}
+
+// ListSectorResidents retrieves the IDs of the active residents housed in
+// a sector, in their own quarters or their household's.
+func (r *EnvironmentRepository) ListSectorResidents(ctx context.Context, sector string) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT res.id FROM residents res
+		LEFT JOIN households h ON h.id = res.household_id
+		JOIN quarters q ON q.id = COALESCE(res.quarters_id, h.quarters_id)
+		WHERE q.sector = ? AND res.status = 'ACTIVE' AND (? = 0 OR res.vault_id = ?)
+		ORDER BY res.registry_number`,
+		sector, r.vault, r.vault)
+	if err != nil {
+		return nil, fmt.Errorf("querying sector residents: %w", err)
+	}
+	return collect(rows, func(row rowScanner) (string, error) {
+		var id string
+		if err := row.Scan(&id); err != nil {
+			return "", fmt.Errorf("scanning sector resident: %w", err)
+		}
+		return id, nil
+	})
+}
+
+func (r *EnvironmentRepository) getExecer(tx *sql.Tx) interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+} {
+	if tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+func (r *EnvironmentRepository) scanReading(row rowScanner) (*models.EnvironmentReading, error) {
+	var reading models.EnvironmentReading
+	var readStr, createdStr string
+	err := row.Scan(
+		&reading.ID,
+		&reading.Sector,
+		&reading.Metric,
+		&reading.Value,
+		&reading.Level,
+		&readStr,
+		&reading.VaultID,
+		&createdStr,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("scanning environment reading: %w", err)
+	}
+	reading.ReadAt = parseTime(time.RFC3339, readStr)
+	reading.CreatedAt = parseTime(time.RFC3339, createdStr)
+	return &reading, nil
+}
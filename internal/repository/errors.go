@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Repository errors. Every repository wraps these with context, e.g.
+// "resident not found: <id>", so callers branch with errors.Is rather than
+// matching message text.
+var (
+	ErrNotFound   = errors.New("not found")
+	ErrDuplicate  = errors.New("duplicate")
+	ErrValidation = errors.New("validation failed")
+	ErrReadOnly   = errors.New("database is read-only")
+)
+
+// Restrict-policy errors. The schema's referential triggers (migrations 004,
+// 018, 024 and 036) abort deletes with these messages; constraintError maps them
+// back so callers can test with errors.Is.
+var (
+	ErrHouseholdHasMembers        = errors.New("household has members")
+	ErrResidentIsParent           = errors.New("resident is a recorded parent")
+	ErrVocationHasAssignments     = errors.New("vocation has work assignments")
+	ErrQuartersOccupied           = errors.New("quarters are occupied")
+	ErrVocationHasApprenticeships = errors.New("vocation has apprenticeships")
+	ErrResidentHoldsAssets        = errors.New("resident holds assets")
+	ErrVocationHasSkillsClasses   = errors.New("vocation has skills classes")
+)
+
+var restrictErrors = []error{
+	ErrHouseholdHasMembers,
+	ErrResidentIsParent,
+	ErrVocationHasAssignments,
+	ErrQuartersOccupied,
+	ErrVocationHasApprenticeships,
+	ErrResidentHoldsAssets,
+	ErrVocationHasSkillsClasses,
+}
+
+// constraintError translates a SQLite constraint failure into its typed
+// error. Restrict-trigger failures map to their restrict error, UNIQUE
+// violations to ErrDuplicate and CHECK, NOT NULL and FOREIGN KEY violations
+// to ErrValidation, and writes to a read-only database to ErrReadOnly. Other
+// errors are returned unchanged.
+func constraintError(err error) error {
+	if err == nil {
+		return nil
+	}
+	msg := err.Error()
+	for _, restrict := range restrictErrors {
+		if strings.Contains(msg, "restrict: "+restrict.Error()) {
+			return restrict
+		}
+	}
+	switch {
+	case strings.Contains(msg, "UNIQUE constraint failed"):
+		return fmt.Errorf("%w: %w", ErrDuplicate, err)
+	case strings.Contains(msg, "CHECK constraint failed"),
+		strings.Contains(msg, "NOT NULL constraint failed"),
+		strings.Contains(msg, "FOREIGN KEY constraint failed"):
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	case strings.Contains(msg, "attempt to write a readonly database"):
+		return fmt.Errorf("%w: %w", ErrReadOnly, err)
+	}
+	return err
+}
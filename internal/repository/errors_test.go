@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/testutil"
+)
+
+func TestConstraintError_ReadOnly(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vault.db")
+
+	rw, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	setup := &testutil.TestDB{DB: rw}
+	setup.RunMigrations(t, filepath.Join("..", "..", "internal", "database", "migrations"))
+	if err := rw.Close(); err != nil {
+		t.Fatalf("failed to close database: %v", err)
+	}
+
+	ro, err := sql.Open("sqlite", "file:"+path+"?mode=ro")
+	if err != nil {
+		t.Fatalf("failed to open read-only database: %v", err)
+	}
+	defer ro.Close()
+
+	repo := NewResidentRepository(ro)
+	ctx := context.Background()
+
+	if _, err := repo.List(ctx, models.ResidentFilter{}, models.Pagination{Page: 1, PageSize: 10}); err != nil {
+		t.Fatalf("expected reads to succeed, got %v", err)
+	}
+
+	err = repo.Create(ctx, nil, testutil.FixtureResident())
+	if !errors.Is(err, ErrReadOnly) {
+		t.Errorf("expected ErrReadOnly, got %v", err)
+	}
+}
@@ -0,0 +1,1156 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// FacilityRepository handles facility system and maintenance data access.
+type FacilityRepository struct {
+	db    *sql.DB
+	vault int // Vault lists are limited to, 0 for every vault
+}
+
+// NewFacilityRepository creates a new facility repository.
+func NewFacilityRepository(db *sql.DB) *FacilityRepository {
+	return &FacilityRepository{db: db}
+}
+
+// ForVault returns a copy of the repository whose system, grid flow and
+// maintenance lists are limited to systems of the vault.
+func (r *FacilityRepository) ForVault(vault int) *FacilityRepository {
+	scoped := *r
+	scoped.vault = vault
+	return &scoped
+}
+
+// ============================================================================
+// SYSTEMS
+// ============================================================================
+
+// GetSystem retrieves a facility system by ID.
+func (r *FacilityRepository) GetSystem(ctx context.Context, id string) (*models.FacilitySystem, error) {
+	query := `
+		SELECT id, system_code, name, category, location_sector, location_level,
+			status, efficiency_percent, current_output, install_date, last_maintenance_date,
+			next_maintenance_due, maintenance_interval_days, mtbf_hours, total_runtime_hours,
+			duty_cycle_percent, maintenance_interval_hours, runtime_at_last_maintenance,
+			decommissioned_at, decommission_reason, notes, vault_id, created_at, updated_at
+		FROM facility_systems
+		WHERE id = ?`
+
+	return r.scanSystem(r.db.QueryRowContext(ctx, query, id))
+}
+
+// GetSystemByCode retrieves a facility system by its system code.
+func (r *FacilityRepository) GetSystemByCode(ctx context.Context, code string) (*models.FacilitySystem, error) {
+	query := `
+		SELECT id, system_code, name, category, location_sector, location_level,
+			status, efficiency_percent, current_output, install_date, last_maintenance_date,
+			next_maintenance_due, maintenance_interval_days, mtbf_hours, total_runtime_hours,
+			duty_cycle_percent, maintenance_interval_hours, runtime_at_last_maintenance,
+			decommissioned_at, decommission_reason, notes, vault_id, created_at, updated_at
+		FROM facility_systems
+		WHERE system_code = ?`
+
+	return r.scanSystem(r.db.QueryRowContext(ctx, query, code))
+}
+
+// facilitySystemSortColumns maps models.FacilitySystemSortColumns to SQL.
+var facilitySystemSortColumns = sortColumns{
+	"system_code":          "system_code",
+	"name":                 "name",
+	"category":             "category",
+	"location":             "location_sector || '-' || printf('%03d', location_level)",
+	"status":               "status",
+	"efficiency":           "efficiency_percent",
+	"next_maintenance_due": "COALESCE(next_maintenance_due, '9999-12-31')",
+	"runtime":              "total_runtime_hours",
+}
+
+// ListSystems retrieves facility systems, optionally limited to one category,
+// within tx if given. They are ordered by system code unless sort picks one
+// of models.FacilitySystemSortColumns.
+func (r *FacilityRepository) ListSystems(ctx context.Context, tx *sql.Tx, category *models.FacilityCategory, sort models.SortOption) ([]*models.FacilitySystem, error) {
+	orderBy, err := facilitySystemSortColumns.orderBy(sort, "system_code", "system_code")
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT id, system_code, name, category, location_sector, location_level,
+			status, efficiency_percent, current_output, install_date, last_maintenance_date,
+			next_maintenance_due, maintenance_interval_days, mtbf_hours, total_runtime_hours,
+			duty_cycle_percent, maintenance_interval_hours, runtime_at_last_maintenance,
+			decommissioned_at, decommission_reason, notes, vault_id, created_at, updated_at
+		FROM facility_systems
+		WHERE ` + vaultCondition
+	args := []any{r.vault, r.vault}
+	if category != nil {
+		query += " AND category = ?"
+		args = append(args, string(*category))
+	}
+	query += " " + orderBy
+
+	rows, err := r.getQuerier(tx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying facility systems: %w", err)
+	}
+	return collect(rows, r.scanSystem)
+}
+
+// CreateSystem inserts a new facility system. A system without a vault
+// goes to the repository's.
+func (r *FacilityRepository) CreateSystem(ctx context.Context, tx *sql.Tx, sys *models.FacilitySystem) error {
+	if err := sys.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	now := time.Now().UTC()
+	sys.CreatedAt = now
+	sys.UpdatedAt = now
+	if sys.VaultID == 0 {
+		sys.VaultID = r.vault
+	}
+
+	_, err := r.getExecer(tx).ExecContext(ctx, `
+		INSERT INTO facility_systems (
+			id, system_code, name, category, location_sector, location_level,
+			status, efficiency_percent, install_date, next_maintenance_due,
+			maintenance_interval_days, mtbf_hours, duty_cycle_percent, notes,
+			vault_id, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		sys.ID,
+		sys.SystemCode,
+		sys.Name,
+		string(sys.Category),
+		sys.LocationSector,
+		sys.LocationLevel,
+		string(sys.Status),
+		sys.EfficiencyPercent,
+		sys.InstallDate.Format(time.DateOnly),
+		nullableTime(sys.NextMaintenanceDue),
+		sys.MaintenanceIntervalDays,
+		sys.MTBFHours,
+		sys.DutyCycle(),
+		nullableString(sys.Notes),
+		sys.VaultID,
+		sys.CreatedAt.Format(time.RFC3339),
+		sys.UpdatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("inserting facility system: %w", constraintError(err))
+	}
+	return nil
+}
+
+// RetireSystem records a system's decommissioning: it is RETIRED, at no
+// efficiency and without output or a next maintenance, as of
+// sys.DecommissionedAt for sys.DecommissionReason.
+func (r *FacilityRepository) RetireSystem(ctx context.Context, tx *sql.Tx, sys *models.FacilitySystem) error {
+	result, err := r.getExecer(tx).ExecContext(ctx, `
+		UPDATE facility_systems
+		SET status = ?, efficiency_percent = 0, current_output = NULL, next_maintenance_due = NULL,
+			decommissioned_at = ?, decommission_reason = ?, updated_at = ?
+		WHERE id = ?`,
+		string(models.FacilityStatusRetired),
+		nullableTimePtrRFC3339(sys.DecommissionedAt),
+		nullableString(sys.DecommissionReason),
+		time.Now().UTC().Format(time.RFC3339),
+		sys.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("retiring facility system: %w", constraintError(err))
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("facility system %w: %s", ErrNotFound, sys.ID)
+	}
+	return nil
+}
+
+// UpdateSystemStatus sets a system's status and efficiency.
+func (r *FacilityRepository) UpdateSystemStatus(ctx context.Context, tx *sql.Tx, id string, status models.FacilityStatus, efficiency float64) error {
+	query := `
+		UPDATE facility_systems
+		SET status = ?, efficiency_percent = ?, updated_at = ?
+		WHERE id = ?`
+
+	var execer interface {
+		ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	}
+	if tx != nil {
+		execer = tx
+	} else {
+		execer = r.db
+	}
+
+	result, err := execer.ExecContext(ctx, query,
+		string(status),
+		efficiency,
+		time.Now().UTC().Format(time.RFC3339),
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("updating facility system: %w", constraintError(err))
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("facility system %w: %s", ErrNotFound, id)
+	}
+
+	return nil
+}
+
+// RecordSystemMaintenance sets the date a system was last maintained, when
+// its next maintenance falls due, and its runtime when maintained.
+func (r *FacilityRepository) RecordSystemMaintenance(ctx context.Context, tx *sql.Tx, id string, date, nextDue time.Time, runtime float64) error {
+	query := `
+		UPDATE facility_systems
+		SET last_maintenance_date = ?, next_maintenance_due = ?, runtime_at_last_maintenance = ?, updated_at = ?
+		WHERE id = ?`
+
+	var execer interface {
+		ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	}
+	if tx != nil {
+		execer = tx
+	} else {
+		execer = r.db
+	}
+
+	result, err := execer.ExecContext(ctx, query,
+		date.Format(time.DateOnly),
+		nextDue.Format(time.DateOnly),
+		runtime,
+		time.Now().UTC().Format(time.RFC3339),
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("updating facility system: %w", constraintError(err))
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("facility system %w: %s", ErrNotFound, id)
+	}
+
+	return nil
+}
+
+// UpdateSystemRuntime saves a system's runtime, duty cycle, runtime
+// maintenance interval and next maintenance due date.
+func (r *FacilityRepository) UpdateSystemRuntime(ctx context.Context, tx *sql.Tx, sys *models.FacilitySystem) error {
+	query := `
+		UPDATE facility_systems
+		SET total_runtime_hours = ?, duty_cycle_percent = ?, maintenance_interval_hours = ?,
+			next_maintenance_due = ?, updated_at = ?
+		WHERE id = ?`
+
+	var execer interface {
+		ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	}
+	if tx != nil {
+		execer = tx
+	} else {
+		execer = r.db
+	}
+
+	result, err := execer.ExecContext(ctx, query,
+		sys.TotalRuntimeHours,
+		sys.DutyCycle(),
+		sys.MaintenanceIntervalHours,
+		nullableTime(sys.NextMaintenanceDue),
+		time.Now().UTC().Format(time.RFC3339),
+		sys.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("updating facility system: %w", constraintError(err))
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("facility system %w: %s", ErrNotFound, sys.ID)
+	}
+
+	return nil
+}
+
+// ============================================================================
+// GRID FLOWS
+// ============================================================================
+
+// SetGridFlow declares a system's rate on a grid. An earlier declaration for
+// the same system, grid and direction keeps its ID and takes the new rate.
+func (r *FacilityRepository) SetGridFlow(ctx context.Context, tx *sql.Tx, flow *models.GridFlow) error {
+	if err := flow.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	query := `
+		INSERT INTO facility_grid_flows (
+			id, system_id, grid, direction, rate, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (system_id, grid, direction) DO UPDATE SET
+			rate = excluded.rate,
+			updated_at = excluded.updated_at`
+
+	var execer interface {
+		ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	}
+	if tx != nil {
+		execer = tx
+	} else {
+		execer = r.db
+	}
+
+	now := time.Now().UTC()
+	flow.CreatedAt = now
+	flow.UpdatedAt = now
+
+	_, err := execer.ExecContext(ctx, query,
+		flow.ID,
+		flow.SystemID,
+		string(flow.Grid),
+		string(flow.Direction),
+		flow.Rate,
+		flow.CreatedAt.Format(time.RFC3339),
+		flow.UpdatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("setting grid flow: %w", constraintError(err))
+	}
+
+	return nil
+}
+
+// DeleteGridFlows takes a system off every grid it is declared on.
+func (r *FacilityRepository) DeleteGridFlows(ctx context.Context, tx *sql.Tx, systemID string) error {
+	_, err := r.getExecer(tx).ExecContext(ctx, `DELETE FROM facility_grid_flows WHERE system_id = ?`, systemID)
+	if err != nil {
+		return fmt.Errorf("deleting grid flows: %w", err)
+	}
+	return nil
+}
+
+// ListGridFlows retrieves every declared grid flow, optionally limited to one grid.
+func (r *FacilityRepository) ListGridFlows(ctx context.Context, grid *models.Grid) ([]*models.GridFlow, error) {
+	query := `
+		SELECT id, system_id, grid, direction, rate, created_at, updated_at
+		FROM facility_grid_flows
+		WHERE ` + vaultSystemCondition
+	args := []any{r.vault, r.vault}
+	if grid != nil {
+		query += " AND grid = ?"
+		args = append(args, string(*grid))
+	}
+	query += " ORDER BY grid, system_id, direction"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying grid flows: %w", err)
+	}
+	return collect(rows, r.scanFlow)
+}
+
+// ============================================================================
+// MAINTENANCE RECORDS
+// ============================================================================
+
+// CreateMaintenanceRecord inserts a new work order.
+func (r *FacilityRepository) CreateMaintenanceRecord(ctx context.Context, tx *sql.Tx, rec *models.MaintenanceRecord) error {
+	if err := rec.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	query := `
+		INSERT INTO maintenance_records (
+			id, system_id, maintenance_type, description, lead_technician_id,
+			scheduled_date, raised_at, notes, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	var execer interface {
+		ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	}
+	if tx != nil {
+		execer = tx
+	} else {
+		execer = r.db
+	}
+
+	now := time.Now().UTC()
+	rec.CreatedAt = now
+	rec.UpdatedAt = now
+
+	_, err := execer.ExecContext(ctx, query,
+		rec.ID,
+		rec.SystemID,
+		string(rec.MaintenanceType),
+		rec.Description,
+		rec.LeadTechnicianID,
+		nullableTime(rec.ScheduledDate),
+		nullableTimePtrRFC3339(rec.RaisedAt),
+		nullableString(rec.Notes),
+		rec.CreatedAt.Format(time.RFC3339),
+		rec.UpdatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("inserting maintenance record: %w", constraintError(err))
+	}
+
+	return nil
+}
+
+// ListCompletedMaintenance retrieves work orders closed with an outcome at a
+// time in [from, to), in completion order.
+func (r *FacilityRepository) ListCompletedMaintenance(ctx context.Context, from, to time.Time) ([]*models.MaintenanceRecord, error) {
+	query := maintenanceSelect + `
+		WHERE outcome IS NOT NULL AND completed_at >= ? AND completed_at < ?
+			AND ` + vaultSystemCondition + `
+		ORDER BY completed_at`
+
+	rows, err := r.db.QueryContext(ctx, query,
+		from.UTC().Format(time.RFC3339), to.UTC().Format(time.RFC3339), r.vault, r.vault)
+	if err != nil {
+		return nil, fmt.Errorf("querying maintenance records: %w", err)
+	}
+	return collect(rows, r.scanMaintenance)
+}
+
+// ListRaisedMaintenance retrieves work orders raised at a time in [from,
+// to), open or closed, in the order raised. Orders raised before raise
+// times were recorded count from their scheduled date, or failing that
+// their creation.
+func (r *FacilityRepository) ListRaisedMaintenance(ctx context.Context, from, to time.Time) ([]*models.MaintenanceRecord, error) {
+	query := maintenanceSelect + `
+		WHERE datetime(COALESCE(raised_at, scheduled_date, created_at)) >= datetime(?)
+			AND datetime(COALESCE(raised_at, scheduled_date, created_at)) < datetime(?)
+			AND ` + vaultSystemCondition + `
+		ORDER BY datetime(COALESCE(raised_at, scheduled_date, created_at)), id`
+
+	rows, err := r.db.QueryContext(ctx, query,
+		from.UTC().Format(time.RFC3339), to.UTC().Format(time.RFC3339), r.vault, r.vault)
+	if err != nil {
+		return nil, fmt.Errorf("querying maintenance records: %w", err)
+	}
+	return collect(rows, r.scanMaintenance)
+}
+
+// GetMaintenanceRecord retrieves a work order by ID.
+func (r *FacilityRepository) GetMaintenanceRecord(ctx context.Context, id string) (*models.MaintenanceRecord, error) {
+	rec, err := r.scanMaintenance(r.db.QueryRowContext(ctx, maintenanceSelect+" WHERE id = ?", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("maintenance record %w: %s", ErrNotFound, id)
+	}
+	return rec, err
+}
+
+// ListOpenMaintenance retrieves the open work orders, soonest scheduled
+// first and unscheduled orders last.
+func (r *FacilityRepository) ListOpenMaintenance(ctx context.Context) ([]*models.MaintenanceRecord, error) {
+	query := maintenanceSelect + `
+		WHERE outcome IS NULL AND ` + vaultSystemCondition + `
+		ORDER BY scheduled_date IS NULL, scheduled_date, created_at, id`
+
+	rows, err := r.db.QueryContext(ctx, query, r.vault, r.vault)
+	if err != nil {
+		return nil, fmt.Errorf("querying maintenance records: %w", err)
+	}
+	return collect(rows, r.scanMaintenance)
+}
+
+// CloseMaintenanceRecord closes an open work order with its outcome,
+// completion time, labor hours, the system's efficiency before and after,
+// and notes.
+func (r *FacilityRepository) CloseMaintenanceRecord(ctx context.Context, tx *sql.Tx, rec *models.MaintenanceRecord) error {
+	if !rec.Outcome.Valid() {
+		return fmt.Errorf("%w: invalid outcome: %s", ErrValidation, rec.Outcome)
+	}
+
+	query := `
+		UPDATE maintenance_records
+		SET outcome = ?, completed_at = ?, actual_hours = ?, efficiency_before = ?,
+			efficiency_after = ?, notes = ?, updated_at = ?
+		WHERE id = ? AND outcome IS NULL`
+
+	var execer interface {
+		ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	}
+	if tx != nil {
+		execer = tx
+	} else {
+		execer = r.db
+	}
+
+	rec.UpdatedAt = time.Now().UTC()
+	result, err := execer.ExecContext(ctx, query,
+		string(rec.Outcome),
+		nullableTimePtrRFC3339(rec.CompletedAt),
+		rec.ActualHours,
+		rec.EfficiencyBefore,
+		rec.EfficiencyAfter,
+		nullableString(rec.Notes),
+		rec.UpdatedAt.Format(time.RFC3339),
+		rec.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("closing maintenance record: %w", constraintError(err))
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("open maintenance record %w: %s", ErrNotFound, rec.ID)
+	}
+	return nil
+}
+
+// HasOpenMaintenance reports whether a system has an open work order of the
+// given type.
+func (r *FacilityRepository) HasOpenMaintenance(ctx context.Context, systemID string, maintenanceType models.MaintenanceType) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM maintenance_records
+			WHERE system_id = ? AND maintenance_type = ? AND outcome IS NULL
+		)`
+
+	var open bool
+	err := r.db.QueryRowContext(ctx, query, systemID, string(maintenanceType)).Scan(&open)
+	if err != nil {
+		return false, fmt.Errorf("checking open maintenance: %w", err)
+	}
+	return open, nil
+}
+
+// ============================================================================
+// CONSUMABLES
+// ============================================================================
+
+// SetConsumable declares a consumable of a system. An earlier declaration of
+// the same item for the system keeps its ID and replacement history and
+// takes the new quantity and interval.
+func (r *FacilityRepository) SetConsumable(ctx context.Context, tx *sql.Tx, c *models.SystemConsumable) error {
+	if err := c.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	query := `
+		INSERT INTO system_consumables (
+			id, system_id, item_id, quantity, interval_days, last_replaced_at,
+			next_replacement_due, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (system_id, item_id) DO UPDATE SET
+			quantity = excluded.quantity,
+			interval_days = excluded.interval_days,
+			updated_at = excluded.updated_at`
+
+	var execer interface {
+		ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	}
+	if tx != nil {
+		execer = tx
+	} else {
+		execer = r.db
+	}
+
+	now := time.Now().UTC()
+	c.CreatedAt = now
+	c.UpdatedAt = now
+
+	_, err := execer.ExecContext(ctx, query,
+		c.ID,
+		c.SystemID,
+		c.ItemID,
+		c.Quantity,
+		c.IntervalDays,
+		nullableTimePtrRFC3339(c.LastReplaced),
+		nullableTimePtrRFC3339(c.NextDue),
+		c.CreatedAt.Format(time.RFC3339),
+		c.UpdatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("setting consumable: %w", constraintError(err))
+	}
+
+	return nil
+}
+
+// ListConsumables retrieves the consumables of a system with their items,
+// or of every system when systemID is empty, soonest due first and those
+// never replaced before them.
+func (r *FacilityRepository) ListConsumables(ctx context.Context, systemID string) ([]*models.SystemConsumable, error) {
+	query := `
+		SELECT c.id, c.system_id, c.item_id, c.quantity, c.interval_days,
+			c.last_replaced_at, c.next_replacement_due, c.created_at, c.updated_at,
+			i.item_code, i.name, i.unit_of_measure
+		FROM system_consumables c
+		JOIN resource_items i ON c.item_id = i.id
+		WHERE ` + vaultSystemCondition
+	args := []any{r.vault, r.vault}
+	if systemID != "" {
+		query += " AND c.system_id = ?"
+		args = append(args, systemID)
+	}
+	query += " ORDER BY c.next_replacement_due IS NOT NULL, c.next_replacement_due, c.system_id, i.item_code"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying consumables: %w", err)
+	}
+	return collect(rows, r.scanConsumable)
+}
+
+// DeleteConsumables removes every consumable declared for a system.
+func (r *FacilityRepository) DeleteConsumables(ctx context.Context, tx *sql.Tx, systemID string) error {
+	_, err := r.getExecer(tx).ExecContext(ctx, `DELETE FROM system_consumables WHERE system_id = ?`, systemID)
+	if err != nil {
+		return fmt.Errorf("deleting consumables: %w", err)
+	}
+	return nil
+}
+
+// ReplaceConsumable records a consumable's replacement: when it was last
+// replaced and when it next falls due.
+func (r *FacilityRepository) ReplaceConsumable(ctx context.Context, tx *sql.Tx, c *models.SystemConsumable) error {
+	query := `
+		UPDATE system_consumables
+		SET last_replaced_at = ?, next_replacement_due = ?, updated_at = ?
+		WHERE id = ?`
+
+	var execer interface {
+		ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	}
+	if tx != nil {
+		execer = tx
+	} else {
+		execer = r.db
+	}
+
+	c.UpdatedAt = time.Now().UTC()
+	result, err := execer.ExecContext(ctx, query,
+		nullableTimePtrRFC3339(c.LastReplaced),
+		nullableTimePtrRFC3339(c.NextDue),
+		c.UpdatedAt.Format(time.RFC3339),
+		c.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("replacing consumable: %w", constraintError(err))
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("consumable %w: %s", ErrNotFound, c.ID)
+	}
+	return nil
+}
+
+// SetCurrentOutput limits a system to a percentage of its rated flows, 0
+// shedding it, or with nil runs it at full output again.
+func (r *FacilityRepository) SetCurrentOutput(ctx context.Context, tx *sql.Tx, id string, output *float64) error {
+	result, err := r.getExecer(tx).ExecContext(ctx, `
+		UPDATE facility_systems SET current_output = ?, updated_at = ? WHERE id = ?`,
+		output, time.Now().UTC().Format(time.RFC3339), id)
+	if err != nil {
+		return fmt.Errorf("setting facility system output: %w", err)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("facility system %w: %s", ErrNotFound, id)
+	}
+	return nil
+}
+
+// ============================================================================
+// LOAD SHEDDING
+// ============================================================================
+
+// SetShedPriority sets a system's place in the load-shedding order,
+// replacing any set before.
+func (r *FacilityRepository) SetShedPriority(ctx context.Context, tx *sql.Tx, p *models.ShedPriority) error {
+	if err := p.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	now := time.Now().UTC()
+	p.UpdatedAt = now
+	if p.CreatedAt.IsZero() {
+		p.CreatedAt = now
+	}
+	_, err := r.getExecer(tx).ExecContext(ctx, `
+		INSERT INTO load_shed_priorities (system_id, priority, created_at, updated_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (system_id) DO UPDATE SET
+			priority = excluded.priority,
+			updated_at = excluded.updated_at`,
+		p.SystemID, p.Priority, p.CreatedAt.Format(time.RFC3339), p.UpdatedAt.Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("setting load shed priority: %w", constraintError(err))
+	}
+	return nil
+}
+
+// DeleteShedPriority takes a system out of the load-shedding order, if it
+// is in it.
+func (r *FacilityRepository) DeleteShedPriority(ctx context.Context, tx *sql.Tx, systemID string) error {
+	_, err := r.getExecer(tx).ExecContext(ctx, `DELETE FROM load_shed_priorities WHERE system_id = ?`, systemID)
+	if err != nil {
+		return fmt.Errorf("clearing load shed priority: %w", err)
+	}
+	return nil
+}
+
+// ListShedPriorities retrieves the load-shedding order, first shed first.
+func (r *FacilityRepository) ListShedPriorities(ctx context.Context) ([]*models.ShedPriority, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT system_id, priority, created_at, updated_at
+		FROM load_shed_priorities
+		WHERE `+vaultSystemCondition+`
+		ORDER BY priority, system_id`,
+		r.vault, r.vault)
+	if err != nil {
+		return nil, fmt.Errorf("querying load shed priorities: %w", err)
+	}
+	return collect(rows, func(row rowScanner) (*models.ShedPriority, error) {
+		var p models.ShedPriority
+		var createdStr, updatedStr string
+		if err := row.Scan(&p.SystemID, &p.Priority, &createdStr, &updatedStr); err != nil {
+			return nil, fmt.Errorf("scanning load shed priority: %w", err)
+		}
+		p.CreatedAt = parseTime(time.RFC3339, createdStr)
+		p.UpdatedAt = parseTime(time.RFC3339, updatedStr)
+		return &p, nil
+	})
+}
+
+// ============================================================================
+// DEPENDENCIES
+// ============================================================================
+
+// AddDependency records that a system depends on another. Adding a
+// dependency already recorded does nothing.
+func (r *FacilityRepository) AddDependency(ctx context.Context, tx *sql.Tx, d *models.SystemDependency) error {
+	if err := d.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	d.CreatedAt = time.Now().UTC()
+	_, err := r.getExecer(tx).ExecContext(ctx, `
+		INSERT INTO system_dependencies (system_id, depends_on_id, created_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT (system_id, depends_on_id) DO NOTHING`,
+		d.SystemID, d.DependsOnID, d.CreatedAt.Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("adding system dependency: %w", constraintError(err))
+	}
+	return nil
+}
+
+// RemoveDependency removes a system's dependency on another.
+func (r *FacilityRepository) RemoveDependency(ctx context.Context, tx *sql.Tx, systemID, dependsOnID string) error {
+	result, err := r.getExecer(tx).ExecContext(ctx, `
+		DELETE FROM system_dependencies WHERE system_id = ? AND depends_on_id = ?`,
+		systemID, dependsOnID)
+	if err != nil {
+		return fmt.Errorf("removing system dependency: %w", err)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("system dependency %w: %s on %s", ErrNotFound, systemID, dependsOnID)
+	}
+	return nil
+}
+
+// DeleteDependencies removes every dependency of a system on others.
+func (r *FacilityRepository) DeleteDependencies(ctx context.Context, tx *sql.Tx, systemID string) error {
+	_, err := r.getExecer(tx).ExecContext(ctx, `DELETE FROM system_dependencies WHERE system_id = ?`, systemID)
+	if err != nil {
+		return fmt.Errorf("deleting system dependencies: %w", err)
+	}
+	return nil
+}
+
+// ListDependencies retrieves every system dependency, by system and parent.
+func (r *FacilityRepository) ListDependencies(ctx context.Context) ([]*models.SystemDependency, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT system_id, depends_on_id, created_at
+		FROM system_dependencies
+		WHERE `+vaultSystemCondition+`
+		ORDER BY system_id, depends_on_id`,
+		r.vault, r.vault)
+	if err != nil {
+		return nil, fmt.Errorf("querying system dependencies: %w", err)
+	}
+	return collect(rows, func(row rowScanner) (*models.SystemDependency, error) {
+		var d models.SystemDependency
+		var createdStr string
+		if err := row.Scan(&d.SystemID, &d.DependsOnID, &createdStr); err != nil {
+			return nil, fmt.Errorf("scanning system dependency: %w", err)
+		}
+		d.CreatedAt = parseTime(time.RFC3339, createdStr)
+		return &d, nil
+	})
+}
+
+// SetCascade records that a system is degraded by a root cause, replacing
+// any cascade recorded for it before.
+func (r *FacilityRepository) SetCascade(ctx context.Context, tx *sql.Tx, c *models.SystemCascade) error {
+	_, err := r.getExecer(tx).ExecContext(ctx, `
+		INSERT INTO system_cascades (system_id, root_id, status_before, efficiency_before, degraded_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (system_id) DO UPDATE SET
+			root_id = excluded.root_id,
+			status_before = excluded.status_before,
+			efficiency_before = excluded.efficiency_before,
+			degraded_at = excluded.degraded_at`,
+		c.SystemID, c.RootID, string(c.StatusBefore), c.EfficiencyBefore, c.At.UTC().Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("setting system cascade: %w", constraintError(err))
+	}
+	return nil
+}
+
+// DeleteCascade clears the cascade recorded for a system, if any.
+func (r *FacilityRepository) DeleteCascade(ctx context.Context, tx *sql.Tx, systemID string) error {
+	_, err := r.getExecer(tx).ExecContext(ctx, `DELETE FROM system_cascades WHERE system_id = ?`, systemID)
+	if err != nil {
+		return fmt.Errorf("clearing system cascade: %w", err)
+	}
+	return nil
+}
+
+// ListCascades retrieves the systems degraded by a root cause, oldest first.
+func (r *FacilityRepository) ListCascades(ctx context.Context) ([]*models.SystemCascade, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT system_id, root_id, status_before, efficiency_before, degraded_at
+		FROM system_cascades
+		WHERE `+vaultSystemCondition+`
+		ORDER BY degraded_at, system_id`,
+		r.vault, r.vault)
+	if err != nil {
+		return nil, fmt.Errorf("querying system cascades: %w", err)
+	}
+	return collect(rows, func(row rowScanner) (*models.SystemCascade, error) {
+		var c models.SystemCascade
+		var atStr string
+		if err := row.Scan(&c.SystemID, &c.RootID, &c.StatusBefore, &c.EfficiencyBefore, &atStr); err != nil {
+			return nil, fmt.Errorf("scanning system cascade: %w", err)
+		}
+		c.At = parseTime(time.RFC3339, atStr)
+		return &c, nil
+	})
+}
+
+// ============================================================================
+// TECHNICIANS
+// ============================================================================
+
+// SetTechnician records a resident's shift and replaces their skill ratings.
+// Pass a transaction, as the ratings are replaced in several statements.
+func (r *FacilityRepository) SetTechnician(ctx context.Context, tx *sql.Tx, t *models.Technician) error {
+	if err := t.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	execer := r.getExecer(tx)
+	t.UpdatedAt = time.Now().UTC()
+	now := t.UpdatedAt.Format(time.RFC3339)
+	_, err := execer.ExecContext(ctx, `
+		INSERT INTO technicians (resident_id, shift, created_at, updated_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (resident_id) DO UPDATE SET
+			shift = excluded.shift,
+			updated_at = excluded.updated_at`,
+		t.ResidentID, string(t.Shift), now, now)
+	if err != nil {
+		return fmt.Errorf("setting technician: %w", constraintError(err))
+	}
+
+	if _, err := execer.ExecContext(ctx, `DELETE FROM technician_skills WHERE resident_id = ?`, t.ResidentID); err != nil {
+		return fmt.Errorf("clearing technician skills: %w", err)
+	}
+	for category, rating := range t.Skills {
+		_, err := execer.ExecContext(ctx, `
+			INSERT INTO technician_skills (resident_id, category, rating) VALUES (?, ?, ?)`,
+			t.ResidentID, string(category), rating)
+		if err != nil {
+			return fmt.Errorf("setting technician skill: %w", constraintError(err))
+		}
+	}
+	return nil
+}
+
+// ListTechnicians retrieves the technicians of residents of the vault, with
+// their registry number, name and status, by registry number.
+func (r *FacilityRepository) ListTechnicians(ctx context.Context) ([]*models.Technician, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT t.resident_id, t.shift, t.updated_at,
+			r.registry_number, r.surname, r.given_names, r.status
+		FROM technicians t
+		JOIN residents r ON r.id = t.resident_id
+		WHERE (? = 0 OR r.vault_id = ?)
+		ORDER BY r.registry_number`,
+		r.vault, r.vault)
+	if err != nil {
+		return nil, fmt.Errorf("querying technicians: %w", err)
+	}
+	technicians, err := collect(rows, func(row rowScanner) (*models.Technician, error) {
+		var t models.Technician
+		var resident models.Resident
+		var updatedStr string
+		if err := row.Scan(&t.ResidentID, &t.Shift, &updatedStr,
+			&resident.RegistryNumber, &resident.Surname, &resident.GivenNames, &resident.Status); err != nil {
+			return nil, fmt.Errorf("scanning technician: %w", err)
+		}
+		t.UpdatedAt = parseTime(time.RFC3339, updatedStr)
+		t.Skills = make(map[models.FacilityCategory]int)
+		resident.ID = t.ResidentID
+		t.Resident = &resident
+		return &t, nil
+	})
+	if err != nil || len(technicians) == 0 {
+		return technicians, err
+	}
+
+	byID := make(map[string]*models.Technician, len(technicians))
+	for _, t := range technicians {
+		byID[t.ResidentID] = t
+	}
+	rows, err = r.db.QueryContext(ctx, `SELECT resident_id, category, rating FROM technician_skills`)
+	if err != nil {
+		return nil, fmt.Errorf("querying technician skills: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var residentID string
+		var category models.FacilityCategory
+		var rating int
+		if err := rows.Scan(&residentID, &category, &rating); err != nil {
+			return nil, fmt.Errorf("scanning technician skill: %w", err)
+		}
+		if t := byID[residentID]; t != nil {
+			t.Skills[category] = rating
+		}
+	}
+	return technicians, rows.Err()
+}
+
+// OpenWorkload counts the open work orders each technician leads, by
+// resident ID.
+func (r *FacilityRepository) OpenWorkload(ctx context.Context) (map[string]int, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT lead_technician_id, COUNT(*)
+		FROM maintenance_records
+		WHERE outcome IS NULL AND lead_technician_id IS NOT NULL
+		GROUP BY lead_technician_id`)
+	if err != nil {
+		return nil, fmt.Errorf("querying technician workload: %w", err)
+	}
+	defer rows.Close()
+
+	workload := make(map[string]int)
+	for rows.Next() {
+		var residentID string
+		var open int
+		if err := rows.Scan(&residentID, &open); err != nil {
+			return nil, fmt.Errorf("scanning technician workload: %w", err)
+		}
+		workload[residentID] = open
+	}
+	return workload, rows.Err()
+}
+
+// SetLeadTechnician sets or, given nil, clears the lead technician of an
+// open work order.
+func (r *FacilityRepository) SetLeadTechnician(ctx context.Context, tx *sql.Tx, recordID string, residentID *string) error {
+	result, err := r.getExecer(tx).ExecContext(ctx, `
+		UPDATE maintenance_records
+		SET lead_technician_id = ?, updated_at = ?
+		WHERE id = ? AND outcome IS NULL`,
+		residentID, time.Now().UTC().Format(time.RFC3339), recordID)
+	if err != nil {
+		return fmt.Errorf("assigning maintenance record: %w", constraintError(err))
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("open maintenance record %w: %s", ErrNotFound, recordID)
+	}
+	return nil
+}
+
+func (r *FacilityRepository) getExecer(tx *sql.Tx) interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+} {
+	if tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+func (r *FacilityRepository) getQuerier(tx *sql.Tx) interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+} {
+	if tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+// scanSystem scans a facility system from a single row or a rows iterator.
+func (r *FacilityRepository) scanSystem(row rowScanner) (*models.FacilitySystem, error) {
+	var sys models.FacilitySystem
+	var installStr, createdStr, updatedStr string
+	var lastMaint, nextDue, decommissioned, reason, notes sql.NullString
+	var mtbf sql.NullInt64
+	var output, intervalHours sql.NullFloat64
+
+	err := row.Scan(
+		&sys.ID,
+		&sys.SystemCode,
+		&sys.Name,
+		&sys.Category,
+		&sys.LocationSector,
+		&sys.LocationLevel,
+		&sys.Status,
+		&sys.EfficiencyPercent,
+		&output,
+		&installStr,
+		&lastMaint,
+		&nextDue,
+		&sys.MaintenanceIntervalDays,
+		&mtbf,
+		&sys.TotalRuntimeHours,
+		&sys.DutyCyclePercent,
+		&intervalHours,
+		&sys.RuntimeAtLastMaintenance,
+		&decommissioned,
+		&reason,
+		&notes,
+		&sys.VaultID,
+		&createdStr,
+		&updatedStr,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("facility system %w", ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scanning facility system: %w", err)
+	}
+
+	sys.InstallDate = parseTime(time.DateOnly, installStr)
+	sys.LastMaintenanceDate = timePtr(time.DateOnly, lastMaint)
+	sys.NextMaintenanceDue = timePtr(time.DateOnly, nextDue)
+	sys.MTBFHours = intPtr(mtbf)
+	sys.CurrentOutput = floatPtr(output)
+	sys.MaintenanceIntervalHours = floatPtr(intervalHours)
+	sys.DecommissionedAt = timePtr(time.RFC3339, decommissioned)
+	sys.DecommissionReason = reason.String
+	sys.Notes = notes.String
+	sys.CreatedAt = parseTime(time.RFC3339, createdStr)
+	sys.UpdatedAt = parseTime(time.RFC3339, updatedStr)
+	return &sys, nil
+}
+
+// scanFlow scans a grid flow from a rows iterator.
+func (r *FacilityRepository) scanFlow(row rowScanner) (*models.GridFlow, error) {
+	var flow models.GridFlow
+	var createdStr, updatedStr string
+	if err := row.Scan(
+		&flow.ID,
+		&flow.SystemID,
+		&flow.Grid,
+		&flow.Direction,
+		&flow.Rate,
+		&createdStr,
+		&updatedStr,
+	); err != nil {
+		return nil, fmt.Errorf("scanning grid flow: %w", err)
+	}
+	flow.CreatedAt = parseTime(time.RFC3339, createdStr)
+	flow.UpdatedAt = parseTime(time.RFC3339, updatedStr)
+	return &flow, nil
+}
+
+// scanMaintenance scans a maintenance record from a rows iterator.
+func (r *FacilityRepository) scanMaintenance(row rowScanner) (*models.MaintenanceRecord, error) {
+	var rec models.MaintenanceRecord
+	var leadTech, scheduled, raised, completed, outcome, notes sql.NullString
+	var hours, before, after sql.NullFloat64
+	var createdStr, updatedStr string
+	if err := row.Scan(
+		&rec.ID,
+		&rec.SystemID,
+		&rec.MaintenanceType,
+		&rec.Description,
+		&leadTech,
+		&scheduled,
+		&raised,
+		&completed,
+		&outcome,
+		&hours,
+		&before,
+		&after,
+		&notes,
+		&createdStr,
+		&updatedStr,
+	); err != nil {
+		return nil, fmt.Errorf("scanning maintenance record: %w", err)
+	}
+	rec.LeadTechnicianID = stringPtr(leadTech)
+	rec.ScheduledDate = timePtr(time.DateOnly, scheduled)
+	rec.RaisedAt = timePtr(time.RFC3339, raised)
+	rec.CompletedAt = timePtr(time.RFC3339, completed)
+	rec.Outcome = models.MaintenanceOutcome(outcome.String)
+	rec.ActualHours = floatPtr(hours)
+	rec.EfficiencyBefore = floatPtr(before)
+	rec.EfficiencyAfter = floatPtr(after)
+	rec.Notes = notes.String
+	rec.CreatedAt = parseTime(time.RFC3339, createdStr)
+	rec.UpdatedAt = parseTime(time.RFC3339, updatedStr)
+	return &rec, nil
+}
+
+const maintenanceSelect = `
+	SELECT id, system_id, maintenance_type, description, lead_technician_id,
+		scheduled_date, raised_at, completed_at, outcome, actual_hours,
+		efficiency_before, efficiency_after, notes, created_at, updated_at
+	FROM maintenance_records`
+
+// scanConsumable scans a consumable, with its item, from a rows iterator.
+func (r *FacilityRepository) scanConsumable(row rowScanner) (*models.SystemConsumable, error) {
+	var c models.SystemConsumable
+	var item models.ResourceItem
+	var lastReplaced, nextDue sql.NullString
+	var createdStr, updatedStr string
+	if err := row.Scan(
+		&c.ID,
+		&c.SystemID,
+		&c.ItemID,
+		&c.Quantity,
+		&c.IntervalDays,
+		&lastReplaced,
+		&nextDue,
+		&createdStr,
+		&updatedStr,
+		&item.ItemCode,
+		&item.Name,
+		&item.UnitOfMeasure,
+	); err != nil {
+		return nil, fmt.Errorf("scanning consumable: %w", err)
+	}
+	c.LastReplaced = timePtr(time.RFC3339, lastReplaced)
+	c.NextDue = timePtr(time.RFC3339, nextDue)
+	c.CreatedAt = parseTime(time.RFC3339, createdStr)
+	c.UpdatedAt = parseTime(time.RFC3339, updatedStr)
+	item.ID = c.ItemID
+	c.Item = &item
+	return &c, nil
+}
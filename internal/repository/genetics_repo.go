@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// GeneticsRepository handles genetic health snapshot data access.
+type GeneticsRepository struct {
+	db    *sql.DB
+	vault int // Vault snapshots are recorded for and listed of, 0 for every vault
+}
+
+// NewGeneticsRepository creates a new genetics repository.
+func NewGeneticsRepository(db *sql.DB) *GeneticsRepository {
+	return &GeneticsRepository{db: db}
+}
+
+// ForVault returns a copy of the repository that records snapshots for the
+// vault and lists only its snapshots.
+func (r *GeneticsRepository) ForVault(vault int) *GeneticsRepository {
+	scoped := *r
+	scoped.vault = vault
+	return &scoped
+}
+
+// Create inserts a new snapshot.
+func (r *GeneticsRepository) Create(ctx context.Context, tx *sql.Tx, g *models.GeneticHealth) error {
+	if g.ID == "" || g.TakenAt.IsZero() {
+		return fmt.Errorf("%w: id and taken_at are required", ErrValidation)
+	}
+
+	g.CreatedAt = time.Now().UTC()
+
+	_, err := r.getExecer(tx).ExecContext(ctx, `
+		INSERT INTO genetic_health_snapshots (
+			id, vault_id, taken_at, population, founders, founders_represented,
+			effective_founders, breeding_pairs, viable_pairs, mean_kinship, score, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		g.ID,
+		r.vault,
+		g.TakenAt.Format(time.RFC3339),
+		g.Population,
+		g.Founders,
+		g.FoundersRepresented,
+		g.EffectiveFounders,
+		g.BreedingPairs,
+		g.ViablePairs,
+		g.MeanKinship,
+		g.Score,
+		g.CreatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("inserting genetic health snapshot: %w", constraintError(err))
+	}
+	return nil
+}
+
+// ListRecent retrieves up to limit snapshots, most recent first.
+func (r *GeneticsRepository) ListRecent(ctx context.Context, limit int) ([]*models.GeneticHealth, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, taken_at, population, founders, founders_represented,
+			effective_founders, breeding_pairs, viable_pairs, mean_kinship, score, created_at
+		FROM genetic_health_snapshots
+		WHERE `+vaultCondition+`
+		ORDER BY taken_at DESC, created_at DESC
+		LIMIT ?`, r.vault, r.vault, limit)
+	if err != nil {
+		return nil, fmt.Errorf("querying genetic health snapshots: %w", err)
+	}
+	return collect(rows, r.scan)
+}
+
+func (r *GeneticsRepository) getExecer(tx *sql.Tx) interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+} {
+	if tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+// scan scans a snapshot from a single row or a rows iterator.
+func (r *GeneticsRepository) scan(row rowScanner) (*models.GeneticHealth, error) {
+	var g models.GeneticHealth
+	var takenStr, createdStr string
+
+	err := row.Scan(
+		&g.ID, &takenStr, &g.Population, &g.Founders, &g.FoundersRepresented,
+		&g.EffectiveFounders, &g.BreedingPairs, &g.ViablePairs, &g.MeanKinship, &g.Score, &createdStr,
+	)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scanning genetic health snapshot: %w", err)
+	}
+
+	g.TakenAt = parseTime(time.RFC3339, takenStr)
+	g.CreatedAt = parseTime(time.RFC3339, createdStr)
+	return &g, nil
+}
@@ -12,7 +12,8 @@ import (
 
 // HouseholdRepository handles household data access.
 type HouseholdRepository struct {
-	db *sql.DB
+	db    *sql.DB
+	vault int // Vault lists are limited to, 0 for every vault
 }
 
 // NewHouseholdRepository creates a new household repository.
@@ -20,17 +21,25 @@ func NewHouseholdRepository(db *sql.DB) *HouseholdRepository {
 	return &HouseholdRepository{db: db}
 }
 
+// ForVault returns a copy of the repository whose lists and counts are
+// limited to households of the vault, and which creates households there.
+func (r *HouseholdRepository) ForVault(vault int) *HouseholdRepository {
+	scoped := *r
+	scoped.vault = vault
+	return &scoped
+}
+
 // Create inserts a new household into the database.
 func (r *HouseholdRepository) Create(ctx context.Context, tx *sql.Tx, household *models.Household) error {
 	if err := household.Validate(); err != nil {
-		return fmt.Errorf("validation failed: %w", err)
+		return fmt.Errorf("%w: %w", ErrValidation, err)
 	}
 
 	query := `
 		INSERT INTO households (
 			id, designation, household_type, head_of_household_id, quarters_id,
-			ration_class, status, formed_date, dissolved_date, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+			ration_class, status, formed_date, dissolved_date, vault_id, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
 	var execer interface {
 		ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
@@ -44,6 +53,9 @@ func (r *HouseholdRepository) Create(ctx context.Context, tx *sql.Tx, household
 	now := time.Now().UTC()
 	household.CreatedAt = now
 	household.UpdatedAt = now
+	if household.VaultID == 0 {
+		household.VaultID = r.vault
+	}
 
 	_, err := execer.ExecContext(ctx, query,
 		household.ID,
@@ -54,12 +66,13 @@ func (r *HouseholdRepository) Create(ctx context.Context, tx *sql.Tx, household
 		string(household.RationClass),
 		string(household.Status),
 		household.FormedDate.Format(time.DateOnly),
-		nullableTimePtr(household.DissolvedDate),
+		nullableTime(household.DissolvedDate),
+		household.VaultID,
 		household.CreatedAt.Format(time.RFC3339),
 		household.UpdatedAt.Format(time.RFC3339),
 	)
 	if err != nil {
-		return fmt.Errorf("inserting household: %w", err)
+		return fmt.Errorf("inserting household: %w", constraintError(err))
 	}
 
 	return nil
@@ -69,7 +82,7 @@ func (r *HouseholdRepository) Create(ctx context.Context, tx *sql.Tx, household
 func (r *HouseholdRepository) GetByID(ctx context.Context, id string) (*models.Household, error) {
 	query := `
 		SELECT id, designation, household_type, head_of_household_id, quarters_id,
-			ration_class, status, formed_date, dissolved_date, created_at, updated_at
+			ration_class, status, formed_date, dissolved_date, vault_id, created_at, updated_at
 		FROM households
 		WHERE id = ?`
 
@@ -91,7 +104,7 @@ func (r *HouseholdRepository) GetByID(ctx context.Context, id string) (*models.H
 func (r *HouseholdRepository) GetByDesignation(ctx context.Context, designation string) (*models.Household, error) {
 	query := `
 		SELECT id, designation, household_type, head_of_household_id, quarters_id,
-			ration_class, status, formed_date, dissolved_date, created_at, updated_at
+			ration_class, status, formed_date, dissolved_date, vault_id, created_at, updated_at
 		FROM households
 		WHERE designation = ?`
 
@@ -101,7 +114,7 @@ func (r *HouseholdRepository) GetByDesignation(ctx context.Context, designation
 // Update modifies an existing household.
 func (r *HouseholdRepository) Update(ctx context.Context, tx *sql.Tx, household *models.Household) error {
 	if err := household.Validate(); err != nil {
-		return fmt.Errorf("validation failed: %w", err)
+		return fmt.Errorf("%w: %w", ErrValidation, err)
 	}
 
 	query := `
@@ -129,26 +142,71 @@ func (r *HouseholdRepository) Update(ctx context.Context, tx *sql.Tx, household
 		string(household.RationClass),
 		string(household.Status),
 		household.FormedDate.Format(time.DateOnly),
-		nullableTimePtr(household.DissolvedDate),
+		nullableTime(household.DissolvedDate),
 		household.UpdatedAt.Format(time.RFC3339),
 		household.ID,
 	)
 	if err != nil {
-		return fmt.Errorf("updating household: %w", err)
+		return fmt.Errorf("updating household: %w", constraintError(err))
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("household %w: %s", ErrNotFound, household.ID)
+	}
+
+	return nil
+}
+
+// Delete removes a household. Households that still have residents are
+// restricted and return ErrHouseholdHasMembers; dissolve them instead.
+// Pending ration reviews are deleted and assigned quarters are released.
+func (r *HouseholdRepository) Delete(ctx context.Context, tx *sql.Tx, id string) error {
+	query := `DELETE FROM households WHERE id = ?`
+
+	var execer interface {
+		ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	}
+	if tx != nil {
+		execer = tx
+	} else {
+		execer = r.db
+	}
+
+	result, err := execer.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("deleting household: %w", constraintError(err))
 	}
 
 	rows, _ := result.RowsAffected()
 	if rows == 0 {
-		return fmt.Errorf("household not found: %s", household.ID)
+		return fmt.Errorf("household %w: %s", ErrNotFound, id)
 	}
 
 	return nil
 }
 
-// List retrieves households with filtering and pagination.
+// householdSortColumns maps models.HouseholdSortColumns to SQL.
+var householdSortColumns = sortColumns{
+	"designation":    "h.designation",
+	"household_type": "h.household_type",
+	"member_count":   "member_count",
+	"ration_class":   "h.ration_class",
+	"status":         "h.status",
+	"formed_date":    "h.formed_date",
+	"dissolved_date": "h.dissolved_date",
+}
+
+// List retrieves households with filtering and pagination, by designation
+// unless page.Sort picks one of models.HouseholdSortColumns.
 func (r *HouseholdRepository) List(ctx context.Context, filter models.HouseholdFilter, page models.Pagination) (*models.HouseholdList, error) {
-	var conditions []string
-	var args []any
+	orderBy, err := householdSortColumns.orderBy(page.Sort, "h.designation", "h.designation")
+	if err != nil {
+		return nil, err
+	}
+
+	conditions := []string{vaultCondition}
+	args := []any{r.vault, r.vault}
 
 	if filter.Status != nil {
 		conditions = append(conditions, "status = ?")
@@ -174,10 +232,7 @@ func (r *HouseholdRepository) List(ctx context.Context, filter models.HouseholdF
 		args = append(args, "%"+filter.SearchTerm+"%")
 	}
 
-	whereClause := ""
-	if len(conditions) > 0 {
-		whereClause = "WHERE " + strings.Join(conditions, " AND ")
-	}
+	whereClause := "WHERE " + strings.Join(conditions, " AND ")
 
 	// Count total
 	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM households %s", whereClause)
@@ -189,31 +244,21 @@ func (r *HouseholdRepository) List(ctx context.Context, filter models.HouseholdF
 	// Get page with member counts
 	query := fmt.Sprintf(`
 		SELECT h.id, h.designation, h.household_type, h.head_of_household_id, h.quarters_id,
-			h.ration_class, h.status, h.formed_date, h.dissolved_date, h.created_at, h.updated_at,
+			h.ration_class, h.status, h.formed_date, h.dissolved_date, h.vault_id, h.created_at, h.updated_at,
 			(SELECT COUNT(*) FROM residents r WHERE r.household_id = h.id AND r.status = 'ACTIVE') as member_count
 		FROM households h
 		%s
-		ORDER BY h.designation
-		LIMIT ? OFFSET ?`, whereClause)
+		%s
+		LIMIT ? OFFSET ?`, whereClause, orderBy)
 
 	args = append(args, page.Limit(), page.Offset())
 	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("querying households: %w", err)
 	}
-	defer rows.Close()
-
-	var households []*models.Household
-	for rows.Next() {
-		household, err := r.scanHouseholdRowWithCount(rows)
-		if err != nil {
-			return nil, err
-		}
-		households = append(households, household)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("iterating households: %w", err)
+	households, err := collect(rows, r.scanHouseholdWithCount)
+	if err != nil {
+		return nil, err
 	}
 
 	return &models.HouseholdList{
@@ -257,10 +302,21 @@ func (r *HouseholdRepository) GetNextDesignation(ctx context.Context) (string, e
 	return fmt.Sprintf("H-%04d", num+1), nil
 }
 
+// GetMemberCount returns the number of active residents in a household.
+func (r *HouseholdRepository) GetMemberCount(ctx context.Context, householdID string) (int, error) {
+	query := `SELECT COUNT(*) FROM residents WHERE household_id = ? AND status = 'ACTIVE'`
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, householdID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("counting household members: %w", err)
+	}
+	return count, nil
+}
+
 // CountByStatus returns counts of households by status.
 func (r *HouseholdRepository) CountByStatus(ctx context.Context) (map[models.HouseholdStatus]int, error) {
-	query := `SELECT status, COUNT(*) FROM households GROUP BY status`
-	rows, err := r.db.QueryContext(ctx, query)
+	query := `SELECT status, COUNT(*) FROM households WHERE ` + vaultCondition + ` GROUP BY status`
+	rows, err := r.db.QueryContext(ctx, query, r.vault, r.vault)
 	if err != nil {
 		return nil, fmt.Errorf("counting by status: %w", err)
 	}
@@ -283,125 +339,44 @@ func (r *HouseholdRepository) CountByStatus(ctx context.Context) (map[models.Hou
 func (r *HouseholdRepository) GetByRationClass(ctx context.Context, rationClass models.RationClass) ([]*models.Household, error) {
 	query := `
 		SELECT id, designation, household_type, head_of_household_id, quarters_id,
-			ration_class, status, formed_date, dissolved_date, created_at, updated_at
+			ration_class, status, formed_date, dissolved_date, vault_id, created_at, updated_at
 		FROM households
-		WHERE ration_class = ? AND status = 'ACTIVE'
+		WHERE ration_class = ? AND status = 'ACTIVE' AND ` + vaultCondition + `
 		ORDER BY designation`
 
-	rows, err := r.db.QueryContext(ctx, query, string(rationClass))
+	rows, err := r.db.QueryContext(ctx, query, string(rationClass), r.vault, r.vault)
 	if err != nil {
 		return nil, fmt.Errorf("querying by ration class: %w", err)
 	}
-	defer rows.Close()
-
-	var households []*models.Household
-	for rows.Next() {
-		household, err := r.scanHouseholdRow(rows)
-		if err != nil {
-			return nil, err
-		}
-		households = append(households, household)
-	}
-
-	return households, rows.Err()
+	return collect(rows, r.scanHousehold)
 }
 
-// scanHousehold scans a single row into a Household struct.
-func (r *HouseholdRepository) scanHousehold(row *sql.Row) (*models.Household, error) {
+// scanHousehold scans a household from a single row or a rows iterator.
+func (r *HouseholdRepository) scanHousehold(row rowScanner) (*models.Household, error) {
 	var household models.Household
-	var formedStr, createdStr, updatedStr string
-	var dissolvedStr, headID, quartersID sql.NullString
-
-	err := row.Scan(
-		&household.ID,
-		&household.Designation,
-		&household.HouseholdType,
-		&headID,
-		&quartersID,
-		&household.RationClass,
-		&household.Status,
-		&formedStr,
-		&dissolvedStr,
-		&createdStr,
-		&updatedStr,
-	)
-	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("household not found")
-	}
-	if err != nil {
-		return nil, fmt.Errorf("scanning household: %w", err)
-	}
-
-	// Parse dates
-	household.FormedDate, _ = time.Parse(time.DateOnly, formedStr)
-	if dissolvedStr.Valid {
-		dissolved, _ := time.Parse(time.DateOnly, dissolvedStr.String)
-		household.DissolvedDate = &dissolved
-	}
-	household.CreatedAt, _ = time.Parse(time.RFC3339, createdStr)
-	household.UpdatedAt, _ = time.Parse(time.RFC3339, updatedStr)
-
-	// Set nullable fields
-	if headID.Valid {
-		household.HeadOfHouseholdID = &headID.String
-	}
-	if quartersID.Valid {
-		household.QuartersID = &quartersID.String
+	if err := r.scanHouseholdInto(row, &household); err != nil {
+		return nil, err
 	}
-
 	return &household, nil
 }
 
-// scanHouseholdRow scans a row from a rows iterator.
-func (r *HouseholdRepository) scanHouseholdRow(rows *sql.Rows) (*models.Household, error) {
+// scanHouseholdWithCount scans a household from a row that ends with its
+// member_count.
+func (r *HouseholdRepository) scanHouseholdWithCount(row rowScanner) (*models.Household, error) {
 	var household models.Household
-	var formedStr, createdStr, updatedStr string
-	var dissolvedStr, headID, quartersID sql.NullString
-
-	err := rows.Scan(
-		&household.ID,
-		&household.Designation,
-		&household.HouseholdType,
-		&headID,
-		&quartersID,
-		&household.RationClass,
-		&household.Status,
-		&formedStr,
-		&dissolvedStr,
-		&createdStr,
-		&updatedStr,
-	)
-	if err != nil {
-		return nil, fmt.Errorf("scanning household row: %w", err)
-	}
-
-	// Parse dates
-	household.FormedDate, _ = time.Parse(time.DateOnly, formedStr)
-	if dissolvedStr.Valid {
-		dissolved, _ := time.Parse(time.DateOnly, dissolvedStr.String)
-		household.DissolvedDate = &dissolved
-	}
-	household.CreatedAt, _ = time.Parse(time.RFC3339, createdStr)
-	household.UpdatedAt, _ = time.Parse(time.RFC3339, updatedStr)
-
-	// Set nullable fields
-	if headID.Valid {
-		household.HeadOfHouseholdID = &headID.String
-	}
-	if quartersID.Valid {
-		household.QuartersID = &quartersID.String
+	if err := r.scanHouseholdInto(row, &household, &household.MemberCount); err != nil {
+		return nil, err
 	}
-
 	return &household, nil
 }
 
-// scanHouseholdRowWithCount scans a row that includes member_count.
-func (r *HouseholdRepository) scanHouseholdRowWithCount(rows *sql.Rows) (*models.Household, error) {
-	var household models.Household
+// scanHouseholdInto scans the household columns of a row, followed by any
+// extra columns, into household.
+func (r *HouseholdRepository) scanHouseholdInto(row rowScanner, household *models.Household, extra ...any) error {
 	var formedStr, createdStr, updatedStr string
 	var dissolvedStr, headID, quartersID sql.NullString
 
-	err := rows.Scan(
+	dest := append([]any{
 		&household.ID,
 		&household.Designation,
 		&household.HouseholdType,
@@ -411,37 +386,23 @@ func (r *HouseholdRepository) scanHouseholdRowWithCount(rows *sql.Rows) (*models
 		&household.Status,
 		&formedStr,
 		&dissolvedStr,
+		&household.VaultID,
 		&createdStr,
 		&updatedStr,
-		&household.MemberCount,
-	)
-	if err != nil {
-		return nil, fmt.Errorf("scanning household row: %w", err)
-	}
-
-	// Parse dates
-	household.FormedDate, _ = time.Parse(time.DateOnly, formedStr)
-	if dissolvedStr.Valid {
-		dissolved, _ := time.Parse(time.DateOnly, dissolvedStr.String)
-		household.DissolvedDate = &dissolved
-	}
-	household.CreatedAt, _ = time.Parse(time.RFC3339, createdStr)
-	household.UpdatedAt, _ = time.Parse(time.RFC3339, updatedStr)
-
-	// Set nullable fields
-	if headID.Valid {
-		household.HeadOfHouseholdID = &headID.String
+	}, extra...)
+	err := row.Scan(dest...)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("household %w", ErrNotFound)
 	}
-	if quartersID.Valid {
-		household.QuartersID = &quartersID.String
+	if err != nil {
+		return fmt.Errorf("scanning household: %w", err)
 	}
 
-	return &household, nil
-}
-
-func nullableTimePtr(t *time.Time) sql.NullString {
-	if t == nil {
-		return sql.NullString{}
-	}
-	return sql.NullString{String: t.Format(time.DateOnly), Valid: true}
+	household.FormedDate = parseTime(time.DateOnly, formedStr)
+	household.DissolvedDate = timePtr(time.DateOnly, dissolvedStr)
+	household.CreatedAt = parseTime(time.RFC3339, createdStr)
+	household.UpdatedAt = parseTime(time.RFC3339, updatedStr)
+	household.HeadOfHouseholdID = stringPtr(headID)
+	household.QuartersID = stringPtr(quartersID)
+	return nil
 }
@@ -2,7 +2,7 @@ package repository
 
 import (
 	"context"
-	"database/sql"
+	"errors"
 	"testing"
 
 	"github.com/vtuos/vtuos/internal/models"
@@ -73,8 +73,8 @@ func TestHouseholdRepository_Create(t *testing.T) {
 		})
 
 		err := repo.Create(ctx, nil, household)
-		if err == nil {
-			t.Error("expected error for invalid household, got nil")
+		if !errors.Is(err, ErrValidation) {
+			t.Errorf("expected ErrValidation for invalid household, got %v", err)
 		}
 	})
 
@@ -90,8 +90,8 @@ func TestHouseholdRepository_Create(t *testing.T) {
 		})
 
 		err = repo.Create(ctx, nil, household2)
-		if err == nil {
-			t.Error("expected error for duplicate designation, got nil")
+		if !errors.Is(err, ErrDuplicate) {
+			t.Errorf("expected ErrDuplicate for duplicate designation, got %v", err)
 		}
 	})
 }
@@ -128,8 +128,8 @@ func TestHouseholdRepository_GetByID(t *testing.T) {
 
 	t.Run("Get non-existent household returns error", func(t *testing.T) {
 		_, err := repo.GetByID(ctx, "non-existent-id")
-		if err != sql.ErrNoRows {
-			t.Errorf("expected sql.ErrNoRows, got %v", err)
+		if !errors.Is(err, ErrNotFound) {
+			t.Errorf("expected ErrNotFound, got %v", err)
 		}
 	})
 }
@@ -190,8 +190,57 @@ func TestHouseholdRepository_Delete(t *testing.T) {
 
 		// Verify deletion
 		_, err = repo.GetByID(ctx, household.ID)
-		if err != sql.ErrNoRows {
-			t.Errorf("expected sql.ErrNoRows after delete, got %v", err)
+		if !errors.Is(err, ErrNotFound) {
+			t.Errorf("expected ErrNotFound after delete, got %v", err)
+		}
+	})
+
+	t.Run("Household with members is restricted", func(t *testing.T) {
+		residents := NewResidentRepository(db.DB)
+		household := testutil.FixtureHousehold()
+		if err := repo.Create(ctx, nil, household); err != nil {
+			t.Fatalf("failed to create household: %v", err)
+		}
+		member := testutil.FixtureResident(func(r *models.Resident) {
+			r.HouseholdID = &household.ID
+		})
+		if err := residents.Create(ctx, nil, member); err != nil {
+			t.Fatalf("failed to create member: %v", err)
+		}
+
+		err := repo.Delete(ctx, nil, household.ID)
+		if !errors.Is(err, ErrHouseholdHasMembers) {
+			t.Errorf("expected ErrHouseholdHasMembers, got %v", err)
+		}
+		if _, err := repo.GetByID(ctx, household.ID); err != nil {
+			t.Errorf("expected the household to remain, got %v", err)
+		}
+	})
+
+	t.Run("Ration reviews are deleted with the household", func(t *testing.T) {
+		reviews := NewRationReviewRepository(db.DB)
+		household := testutil.FixtureHousehold()
+		if err := repo.Create(ctx, nil, household); err != nil {
+			t.Fatalf("failed to create household: %v", err)
+		}
+		review := &models.RationClassReview{
+			ID:               "review-" + household.ID,
+			HouseholdID:      household.ID,
+			CurrentClass:     models.RationClassStandard,
+			RecommendedClass: models.RationClassEnhanced,
+			Reason:           "member is an infant",
+			Trigger:          models.RationReviewTriggerBirth,
+			Status:           models.RationReviewStatusPending,
+		}
+		if err := reviews.Create(ctx, nil, review); err != nil {
+			t.Fatalf("failed to create review: %v", err)
+		}
+
+		if err := repo.Delete(ctx, nil, household.ID); err != nil {
+			t.Fatalf("failed to delete household: %v", err)
+		}
+		if _, err := reviews.GetByID(ctx, review.ID); !errors.Is(err, ErrNotFound) {
+			t.Errorf("expected the review to be deleted, got %v", err)
 		}
 	})
 }
@@ -216,6 +265,7 @@ func TestHouseholdRepository_List(t *testing.T) {
 		}),
 		testutil.FixtureDissolvedHousehold(func(h *models.Household) {
 			h.Designation = "Family-Gamma"
+			h.RationClass = models.RationClassEnhanced
 		}),
 	}
 
@@ -226,7 +276,7 @@ func TestHouseholdRepository_List(t *testing.T) {
 	}
 
 	t.Run("List all households", func(t *testing.T) {
-		result, err := repo.List(ctx, models.HouseholdFilter{}, 1, 10)
+		result, err := repo.List(ctx, models.HouseholdFilter{}, models.Pagination{Page: 1, PageSize: 10})
 		if err != nil {
 			t.Fatalf("failed to list households: %v", err)
 		}
@@ -241,7 +291,7 @@ func TestHouseholdRepository_List(t *testing.T) {
 
 	t.Run("Filter by status", func(t *testing.T) {
 		status := models.HouseholdStatusActive
-		result, err := repo.List(ctx, models.HouseholdFilter{Status: &status}, 1, 10)
+		result, err := repo.List(ctx, models.HouseholdFilter{Status: &status}, models.Pagination{Page: 1, PageSize: 10})
 		if err != nil {
 			t.Fatalf("failed to list households: %v", err)
 		}
@@ -253,7 +303,7 @@ func TestHouseholdRepository_List(t *testing.T) {
 
 	t.Run("Filter by household type", func(t *testing.T) {
 		householdType := models.HouseholdTypeIndividual
-		result, err := repo.List(ctx, models.HouseholdFilter{HouseholdType: &householdType}, 1, 10)
+		result, err := repo.List(ctx, models.HouseholdFilter{HouseholdType: &householdType}, models.Pagination{Page: 1, PageSize: 10})
 		if err != nil {
 			t.Fatalf("failed to list households: %v", err)
 		}
@@ -265,7 +315,7 @@ func TestHouseholdRepository_List(t *testing.T) {
 
 	t.Run("Filter by ration class", func(t *testing.T) {
 		rationClass := models.RationClassStandard
-		result, err := repo.List(ctx, models.HouseholdFilter{RationClass: &rationClass}, 1, 10)
+		result, err := repo.List(ctx, models.HouseholdFilter{RationClass: &rationClass}, models.Pagination{Page: 1, PageSize: 10})
 		if err != nil {
 			t.Fatalf("failed to list households: %v", err)
 		}
@@ -276,7 +326,7 @@ func TestHouseholdRepository_List(t *testing.T) {
 	})
 
 	t.Run("Search by designation", func(t *testing.T) {
-		result, err := repo.List(ctx, models.HouseholdFilter{SearchTerm: "Alpha"}, 1, 10)
+		result, err := repo.List(ctx, models.HouseholdFilter{SearchTerm: "Alpha"}, models.Pagination{Page: 1, PageSize: 10})
 		if err != nil {
 			t.Fatalf("failed to list households: %v", err)
 		}
@@ -288,7 +338,7 @@ func TestHouseholdRepository_List(t *testing.T) {
 
 	t.Run("Pagination", func(t *testing.T) {
 		// Get first page (2 items)
-		result, err := repo.List(ctx, models.HouseholdFilter{}, 1, 2)
+		result, err := repo.List(ctx, models.HouseholdFilter{}, models.Pagination{Page: 1, PageSize: 2})
 		if err != nil {
 			t.Fatalf("failed to list households: %v", err)
 		}
@@ -0,0 +1,455 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// InspectionRepository handles inspection template, schedule, and finding data access.
+type InspectionRepository struct {
+	db *sql.DB
+}
+
+// NewInspectionRepository creates a new inspection repository.
+func NewInspectionRepository(db *sql.DB) *InspectionRepository {
+	return &InspectionRepository{db: db}
+}
+
+// ============================================================================
+// TEMPLATES
+// ============================================================================
+
+// CreateTemplate inserts a new inspection template along with its checklist items.
+func (r *InspectionRepository) CreateTemplate(ctx context.Context, tx *sql.Tx, tmpl *models.InspectionTemplate) error {
+	if err := tmpl.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	execer := r.getExecer(tx)
+
+	now := time.Now().UTC()
+	tmpl.CreatedAt = now
+	tmpl.UpdatedAt = now
+
+	query := `
+		INSERT INTO inspection_templates (
+			id, code, name, category, description, interval_days, is_active,
+			created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := execer.ExecContext(ctx, query,
+		tmpl.ID,
+		tmpl.Code,
+		tmpl.Name,
+		string(tmpl.Category),
+		nullableString(tmpl.Description),
+		tmpl.IntervalDays,
+		boolToInt(tmpl.IsActive),
+		tmpl.CreatedAt.Format(time.RFC3339),
+		tmpl.UpdatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("inserting inspection template: %w", constraintError(err))
+	}
+
+	itemQuery := `
+		INSERT INTO inspection_checklist_items (
+			id, template_id, sequence, description, is_critical
+		) VALUES (?, ?, ?, ?, ?)`
+
+	for i, item := range tmpl.Items {
+		item.TemplateID = tmpl.ID
+		if item.Sequence == 0 {
+			item.Sequence = i + 1
+		}
+		_, err := execer.ExecContext(ctx, itemQuery,
+			item.ID,
+			item.TemplateID,
+			item.Sequence,
+			item.Description,
+			boolToInt(item.IsCritical),
+		)
+		if err != nil {
+			return fmt.Errorf("inserting checklist item %d: %w", item.Sequence, constraintError(err))
+		}
+	}
+
+	return nil
+}
+
+// GetTemplate retrieves an inspection template with its checklist items.
+func (r *InspectionRepository) GetTemplate(ctx context.Context, id string) (*models.InspectionTemplate, error) {
+	query := `
+		SELECT id, code, name, category, description, interval_days, is_active,
+			created_at, updated_at
+		FROM inspection_templates
+		WHERE id = ?`
+
+	tmpl, err := r.scanTemplate(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl.Items, err = r.getChecklistItems(ctx, tmpl.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return tmpl, nil
+}
+
+// GetTemplateByCode retrieves an inspection template by code with its checklist items.
+func (r *InspectionRepository) GetTemplateByCode(ctx context.Context, code string) (*models.InspectionTemplate, error) {
+	query := `
+		SELECT id, code, name, category, description, interval_days, is_active,
+			created_at, updated_at
+		FROM inspection_templates
+		WHERE code = ?`
+
+	tmpl, err := r.scanTemplate(r.db.QueryRowContext(ctx, query, code))
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl.Items, err = r.getChecklistItems(ctx, tmpl.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return tmpl, nil
+}
+
+// ListTemplates retrieves inspection templates ordered by code. Checklist
+// items are not loaded.
+func (r *InspectionRepository) ListTemplates(ctx context.Context, activeOnly bool) ([]*models.InspectionTemplate, error) {
+	query := `
+		SELECT id, code, name, category, description, interval_days, is_active,
+			created_at, updated_at
+		FROM inspection_templates`
+	if activeOnly {
+		query += " WHERE is_active = 1"
+	}
+	query += " ORDER BY code"
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("querying inspection templates: %w", err)
+	}
+	return collect(rows, r.scanTemplate)
+}
+
+// getChecklistItems retrieves the checklist items of a template in sequence order.
+func (r *InspectionRepository) getChecklistItems(ctx context.Context, templateID string) ([]*models.InspectionChecklistItem, error) {
+	query := `
+		SELECT id, template_id, sequence, description, is_critical
+		FROM inspection_checklist_items
+		WHERE template_id = ?
+		ORDER BY sequence`
+
+	rows, err := r.db.QueryContext(ctx, query, templateID)
+	if err != nil {
+		return nil, fmt.Errorf("querying checklist items: %w", err)
+	}
+	return collect(rows, r.scanChecklistItem)
+}
+
+// ============================================================================
+// INSPECTIONS
+// ============================================================================
+
+// CreateInspection inserts a new inspection.
+func (r *InspectionRepository) CreateInspection(ctx context.Context, tx *sql.Tx, insp *models.Inspection) error {
+	if err := insp.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	query := `
+		INSERT INTO inspections (
+			id, template_id, location, scheduled_date, assigned_to, status,
+			completed_at, completed_by, passed, notes, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	now := time.Now().UTC()
+	insp.CreatedAt = now
+	insp.UpdatedAt = now
+
+	_, err := r.getExecer(tx).ExecContext(ctx, query,
+		insp.ID,
+		insp.TemplateID,
+		nullableString(insp.Location),
+		insp.ScheduledDate.Format(time.DateOnly),
+		insp.AssignedTo,
+		string(insp.Status),
+		nullableTimePtrRFC3339(insp.CompletedAt),
+		insp.CompletedBy,
+		nullableBool(insp.Passed),
+		nullableString(insp.Notes),
+		insp.CreatedAt.Format(time.RFC3339),
+		insp.UpdatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("inserting inspection: %w", constraintError(err))
+	}
+
+	return nil
+}
+
+// GetInspection retrieves an inspection by ID.
+func (r *InspectionRepository) GetInspection(ctx context.Context, id string) (*models.Inspection, error) {
+	query := `
+		SELECT id, template_id, location, scheduled_date, assigned_to, status,
+			completed_at, completed_by, passed, notes, created_at, updated_at
+		FROM inspections
+		WHERE id = ?`
+
+	return r.scanInspection(r.db.QueryRowContext(ctx, query, id))
+}
+
+// UpdateInspection updates an existing inspection.
+func (r *InspectionRepository) UpdateInspection(ctx context.Context, tx *sql.Tx, insp *models.Inspection) error {
+	if err := insp.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	query := `
+		UPDATE inspections SET
+			location = ?, scheduled_date = ?, assigned_to = ?, status = ?,
+			completed_at = ?, completed_by = ?, passed = ?, notes = ?, updated_at = ?
+		WHERE id = ?`
+
+	insp.UpdatedAt = time.Now().UTC()
+
+	result, err := r.getExecer(tx).ExecContext(ctx, query,
+		nullableString(insp.Location),
+		insp.ScheduledDate.Format(time.DateOnly),
+		insp.AssignedTo,
+		string(insp.Status),
+		nullableTimePtrRFC3339(insp.CompletedAt),
+		insp.CompletedBy,
+		nullableBool(insp.Passed),
+		nullableString(insp.Notes),
+		insp.UpdatedAt.Format(time.RFC3339),
+		insp.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("updating inspection: %w", constraintError(err))
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("inspection %w: %s", ErrNotFound, insp.ID)
+	}
+
+	return nil
+}
+
+// ListInspections retrieves inspections matching the filter, ordered by
+// scheduled date.
+func (r *InspectionRepository) ListInspections(ctx context.Context, filter models.InspectionFilter) ([]*models.Inspection, error) {
+	var conditions []string
+	var args []any
+
+	if filter.Status != nil {
+		conditions = append(conditions, "status = ?")
+		args = append(args, string(*filter.Status))
+	}
+	if filter.TemplateID != nil {
+		conditions = append(conditions, "template_id = ?")
+		args = append(args, *filter.TemplateID)
+	}
+	if filter.From != nil {
+		conditions = append(conditions, "scheduled_date >= ?")
+		args = append(args, filter.From.Format(time.DateOnly))
+	}
+	if filter.To != nil {
+		conditions = append(conditions, "scheduled_date <= ?")
+		args = append(args, filter.To.Format(time.DateOnly))
+	}
+
+	query := `
+		SELECT id, template_id, location, scheduled_date, assigned_to, status,
+			completed_at, completed_by, passed, notes, created_at, updated_at
+		FROM inspections`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY scheduled_date, created_at"
+
+	return r.queryInspections(ctx, query, args...)
+}
+
+// ListOverdue retrieves scheduled inspections dated before asOf, oldest first.
+func (r *InspectionRepository) ListOverdue(ctx context.Context, asOf time.Time) ([]*models.Inspection, error) {
+	query := `
+		SELECT id, template_id, location, scheduled_date, assigned_to, status,
+			completed_at, completed_by, passed, notes, created_at, updated_at
+		FROM inspections
+		WHERE status = 'SCHEDULED' AND scheduled_date < ?
+		ORDER BY scheduled_date, created_at`
+
+	return r.queryInspections(ctx, query, asOf.Format(time.DateOnly))
+}
+
+func (r *InspectionRepository) queryInspections(ctx context.Context, query string, args ...any) ([]*models.Inspection, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying inspections: %w", err)
+	}
+	return collect(rows, r.scanInspection)
+}
+
+// ============================================================================
+// FINDINGS
+// ============================================================================
+
+// CreateFinding inserts a new inspection finding.
+func (r *InspectionRepository) CreateFinding(ctx context.Context, tx *sql.Tx, f *models.InspectionFinding) error {
+	if err := f.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	query := `
+		INSERT INTO inspection_findings (
+			id, inspection_id, checklist_item_id, severity, description, action,
+			maintenance_record_id, incident_id, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	f.CreatedAt = time.Now().UTC()
+
+	_, err := r.getExecer(tx).ExecContext(ctx, query,
+		f.ID,
+		f.InspectionID,
+		f.ChecklistItemID,
+		string(f.Severity),
+		f.Description,
+		string(f.Action),
+		f.MaintenanceRecordID,
+		f.IncidentID,
+		f.CreatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("inserting inspection finding: %w", constraintError(err))
+	}
+
+	return nil
+}
+
+// ListFindings retrieves the findings recorded for an inspection.
+func (r *InspectionRepository) ListFindings(ctx context.Context, inspectionID string) ([]*models.InspectionFinding, error) {
+	query := `
+		SELECT id, inspection_id, checklist_item_id, severity, description, action,
+			maintenance_record_id, incident_id, created_at
+		FROM inspection_findings
+		WHERE inspection_id = ?
+		ORDER BY created_at`
+
+	rows, err := r.db.QueryContext(ctx, query, inspectionID)
+	if err != nil {
+		return nil, fmt.Errorf("querying inspection findings: %w", err)
+	}
+	return collect(rows, r.scanFinding)
+}
+
+// ============================================================================
+// HELPERS
+// ============================================================================
+
+func (r *InspectionRepository) getExecer(tx *sql.Tx) interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+} {
+	if tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+// scanTemplate scans a template, without its checklist, from a single row
+// or a rows iterator.
+func (r *InspectionRepository) scanTemplate(row rowScanner) (*models.InspectionTemplate, error) {
+	var tmpl models.InspectionTemplate
+	var desc sql.NullString
+	var isActive int
+	var createdStr, updatedStr string
+
+	err := row.Scan(
+		&tmpl.ID, &tmpl.Code, &tmpl.Name, &tmpl.Category, &desc,
+		&tmpl.IntervalDays, &isActive, &createdStr, &updatedStr,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("inspection template %w", ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scanning inspection template: %w", err)
+	}
+
+	tmpl.Description = desc.String
+	tmpl.IsActive = isActive == 1
+	tmpl.CreatedAt = parseTime(time.RFC3339, createdStr)
+	tmpl.UpdatedAt = parseTime(time.RFC3339, updatedStr)
+	return &tmpl, nil
+}
+
+// scanChecklistItem scans a checklist item from a rows iterator.
+func (r *InspectionRepository) scanChecklistItem(row rowScanner) (*models.InspectionChecklistItem, error) {
+	var item models.InspectionChecklistItem
+	var isCritical int
+	if err := row.Scan(&item.ID, &item.TemplateID, &item.Sequence, &item.Description, &isCritical); err != nil {
+		return nil, fmt.Errorf("scanning checklist item: %w", err)
+	}
+	item.IsCritical = isCritical == 1
+	return &item, nil
+}
+
+// scanInspection scans an inspection from a single row or a rows iterator.
+func (r *InspectionRepository) scanInspection(row rowScanner) (*models.Inspection, error) {
+	var insp models.Inspection
+	var scheduledStr, createdStr, updatedStr string
+	var location, assignedTo, completedAt, completedBy, notes sql.NullString
+	var passed sql.NullInt64
+
+	err := row.Scan(
+		&insp.ID, &insp.TemplateID, &location, &scheduledStr, &assignedTo, &insp.Status,
+		&completedAt, &completedBy, &passed, &notes, &createdStr, &updatedStr,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("inspection %w", ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scanning inspection: %w", err)
+	}
+
+	insp.Location = location.String
+	insp.Notes = notes.String
+	insp.AssignedTo = stringPtr(assignedTo)
+	insp.CompletedBy = stringPtr(completedBy)
+	insp.CompletedAt = timePtr(time.RFC3339, completedAt)
+	insp.Passed = boolPtr(passed)
+	insp.ScheduledDate = parseTime(time.DateOnly, scheduledStr)
+	insp.CreatedAt = parseTime(time.RFC3339, createdStr)
+	insp.UpdatedAt = parseTime(time.RFC3339, updatedStr)
+	return &insp, nil
+}
+
+// scanFinding scans an inspection finding from a rows iterator.
+func (r *InspectionRepository) scanFinding(row rowScanner) (*models.InspectionFinding, error) {
+	var f models.InspectionFinding
+	var itemID, maintID, incidentID sql.NullString
+	var createdStr string
+	err := row.Scan(
+		&f.ID, &f.InspectionID, &itemID, &f.Severity, &f.Description, &f.Action,
+		&maintID, &incidentID, &createdStr,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("scanning inspection finding: %w", err)
+	}
+	f.ChecklistItemID = stringPtr(itemID)
+	f.MaintenanceRecordID = stringPtr(maintID)
+	f.IncidentID = stringPtr(incidentID)
+	f.CreatedAt = parseTime(time.RFC3339, createdStr)
+	return &f, nil
+}
@@ -0,0 +1,280 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// IntakeRepository handles admission intake and screening data access.
+type IntakeRepository struct {
+	db    *sql.DB
+	vault int
+}
+
+// NewIntakeRepository creates a new intake repository.
+func NewIntakeRepository(db *sql.DB) *IntakeRepository {
+	return &IntakeRepository{db: db}
+}
+
+// ForVault returns a copy of the repository whose intake lists are limited
+// to residents of the vault.
+func (r *IntakeRepository) ForVault(vault int) *IntakeRepository {
+	scoped := *r
+	scoped.vault = vault
+	return &scoped
+}
+
+// ============================================================================
+// INTAKES
+// ============================================================================
+
+// Create inserts a new intake. A second intake of the same resident is
+// reported as ErrDuplicate.
+func (r *IntakeRepository) Create(ctx context.Context, tx *sql.Tx, i *models.Intake) error {
+	if err := i.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	now := time.Now().UTC()
+	i.CreatedAt = now
+	i.UpdatedAt = now
+
+	_, err := r.getExecer(tx).ExecContext(ctx, `
+		INSERT INTO intakes (
+			id, resident_id, admitted_at, quarantine_days, quarantine_end,
+			cleared_at, cleared_by, notes, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		i.ID,
+		i.ResidentID,
+		i.AdmittedAt.UTC().Format(time.RFC3339),
+		i.QuarantineDays,
+		i.QuarantineEnd.Format(time.DateOnly),
+		nullableTimePtrRFC3339(i.ClearedAt),
+		i.ClearedBy,
+		nullableString(i.Notes),
+		i.CreatedAt.Format(time.RFC3339),
+		i.UpdatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("inserting intake: %w", constraintError(err))
+	}
+	return nil
+}
+
+// GetByID retrieves an intake by ID.
+func (r *IntakeRepository) GetByID(ctx context.Context, id string) (*models.Intake, error) {
+	i, err := r.scan(r.db.QueryRowContext(ctx, intakeSelect+" WHERE i.id = ?", id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("intake %w: %s", ErrNotFound, id)
+	}
+	return i, err
+}
+
+// GetByResident retrieves a resident's intake.
+func (r *IntakeRepository) GetByResident(ctx context.Context, residentID string) (*models.Intake, error) {
+	i, err := r.scan(r.db.QueryRowContext(ctx, intakeSelect+" WHERE i.resident_id = ?", residentID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("intake %w: resident %s", ErrNotFound, residentID)
+	}
+	return i, err
+}
+
+// ListPending retrieves the intakes not yet cleared, earliest admitted
+// first.
+func (r *IntakeRepository) ListPending(ctx context.Context) ([]*models.Intake, error) {
+	query := intakeSelect + `
+		JOIN residents r ON r.id = i.resident_id
+		WHERE i.cleared_at IS NULL AND ` + vaultCondition + `
+		ORDER BY i.admitted_at, i.id`
+
+	rows, err := r.db.QueryContext(ctx, query, r.vault, r.vault)
+	if err != nil {
+		return nil, fmt.Errorf("querying intakes: %w", err)
+	}
+	return collect(rows, r.scan)
+}
+
+// Clear records a medical officer's clearance of an intake not yet
+// cleared.
+func (r *IntakeRepository) Clear(ctx context.Context, tx *sql.Tx, id, officerID string, at time.Time) error {
+	result, err := r.getExecer(tx).ExecContext(ctx, `
+		UPDATE intakes SET cleared_at = ?, cleared_by = ?, updated_at = ?
+		WHERE id = ? AND cleared_at IS NULL`,
+		at.UTC().Format(time.RFC3339),
+		officerID,
+		time.Now().UTC().Format(time.RFC3339),
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("updating intake: %w", constraintError(err))
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("pending intake %w: %s", ErrNotFound, id)
+	}
+	return nil
+}
+
+// ============================================================================
+// SCREENINGS
+// ============================================================================
+
+// CreateScreening inserts a new screening. A second screening of the same
+// type for an intake is reported as ErrDuplicate.
+func (r *IntakeRepository) CreateScreening(ctx context.Context, tx *sql.Tx, s *models.IntakeScreening) error {
+	if err := s.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	now := time.Now().UTC()
+	s.CreatedAt = now
+	s.UpdatedAt = now
+
+	_, err := r.getExecer(tx).ExecContext(ctx, `
+		INSERT INTO intake_screenings (
+			id, intake_id, screening, due_date, completed_at, completed_by,
+			passed, notes, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		s.ID,
+		s.IntakeID,
+		string(s.Screening),
+		s.DueDate.Format(time.DateOnly),
+		nullableTimePtrRFC3339(s.CompletedAt),
+		s.CompletedBy,
+		nullableBool(s.Passed),
+		nullableString(s.Notes),
+		s.CreatedAt.Format(time.RFC3339),
+		s.UpdatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("inserting intake screening: %w", constraintError(err))
+	}
+	return nil
+}
+
+// GetScreening retrieves a screening by ID.
+func (r *IntakeRepository) GetScreening(ctx context.Context, id string) (*models.IntakeScreening, error) {
+	s, err := r.scanScreening(r.db.QueryRowContext(ctx, screeningSelect+" WHERE id = ?", id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("intake screening %w: %s", ErrNotFound, id)
+	}
+	return s, err
+}
+
+// ListScreenings retrieves an intake's screenings, earliest due first.
+func (r *IntakeRepository) ListScreenings(ctx context.Context, intakeID string) ([]*models.IntakeScreening, error) {
+	rows, err := r.db.QueryContext(ctx, screeningSelect+" WHERE intake_id = ? ORDER BY due_date, created_at, id", intakeID)
+	if err != nil {
+		return nil, fmt.Errorf("querying intake screenings: %w", err)
+	}
+	return collect(rows, r.scanScreening)
+}
+
+// RecordResult records a screening's latest result: when it was done, by
+// whom, whether it passed and the examiner's notes.
+func (r *IntakeRepository) RecordResult(ctx context.Context, tx *sql.Tx, s *models.IntakeScreening) error {
+	if err := s.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	s.UpdatedAt = time.Now().UTC()
+	result, err := r.getExecer(tx).ExecContext(ctx, `
+		UPDATE intake_screenings
+		SET completed_at = ?, completed_by = ?, passed = ?, notes = ?, updated_at = ?
+		WHERE id = ?`,
+		nullableTimePtrRFC3339(s.CompletedAt),
+		s.CompletedBy,
+		nullableBool(s.Passed),
+		nullableString(s.Notes),
+		s.UpdatedAt.Format(time.RFC3339),
+		s.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("updating intake screening: %w", constraintError(err))
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("intake screening %w: %s", ErrNotFound, s.ID)
+	}
+	return nil
+}
+
+const intakeSelect = `
+	SELECT i.id, i.resident_id, i.admitted_at, i.quarantine_days, i.quarantine_end,
+		i.cleared_at, i.cleared_by, i.notes, i.created_at, i.updated_at
+	FROM intakes i`
+
+const screeningSelect = `
+	SELECT id, intake_id, screening, due_date, completed_at, completed_by,
+		passed, notes, created_at, updated_at
+	FROM intake_screenings`
+
+func (r *IntakeRepository) getExecer(tx *sql.Tx) interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+} {
+	if tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+// scan scans an intake from a single row or a rows iterator.
+func (r *IntakeRepository) scan(row rowScanner) (*models.Intake, error) {
+	var i models.Intake
+	var clearedAt, clearedBy, notes sql.NullString
+	var admittedStr, endStr, createdStr, updatedStr string
+
+	err := row.Scan(
+		&i.ID, &i.ResidentID, &admittedStr, &i.QuarantineDays, &endStr,
+		&clearedAt, &clearedBy, &notes, &createdStr, &updatedStr,
+	)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scanning intake: %w", err)
+	}
+
+	i.AdmittedAt = parseTime(time.RFC3339, admittedStr)
+	i.QuarantineEnd = parseTime(time.DateOnly, endStr)
+	i.ClearedAt = timePtr(time.RFC3339, clearedAt)
+	i.ClearedBy = stringPtr(clearedBy)
+	i.Notes = notes.String
+	i.CreatedAt = parseTime(time.RFC3339, createdStr)
+	i.UpdatedAt = parseTime(time.RFC3339, updatedStr)
+	return &i, nil
+}
+
+// scanScreening scans a screening from a single row or a rows iterator.
+func (r *IntakeRepository) scanScreening(row rowScanner) (*models.IntakeScreening, error) {
+	var s models.IntakeScreening
+	var completedAt, completedBy, notes sql.NullString
+	var passed sql.NullInt64
+	var dueStr, createdStr, updatedStr string
+
+	err := row.Scan(
+		&s.ID, &s.IntakeID, &s.Screening, &dueStr, &completedAt, &completedBy,
+		&passed, &notes, &createdStr, &updatedStr,
+	)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scanning intake screening: %w", err)
+	}
+
+	s.DueDate = parseTime(time.DateOnly, dueStr)
+	s.CompletedAt = timePtr(time.RFC3339, completedAt)
+	s.CompletedBy = stringPtr(completedBy)
+	s.Passed = boolPtr(passed)
+	s.Notes = notes.String
+	s.CreatedAt = parseTime(time.RFC3339, createdStr)
+	s.UpdatedAt = parseTime(time.RFC3339, updatedStr)
+	return &s, nil
+}
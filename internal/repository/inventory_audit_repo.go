@@ -0,0 +1,237 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// InventoryAuditRepository handles inventory audit session data access.
+type InventoryAuditRepository struct {
+	db *sql.DB
+}
+
+// NewInventoryAuditRepository creates a new inventory audit repository.
+func NewInventoryAuditRepository(db *sql.DB) *InventoryAuditRepository {
+	return &InventoryAuditRepository{db: db}
+}
+
+// Create inserts a new audit and its expected counts. Opening a second audit
+// of a location that has one open fails with ErrDuplicate.
+func (r *InventoryAuditRepository) Create(ctx context.Context, tx *sql.Tx, audit *models.InventoryAudit) error {
+	if err := audit.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	var execer interface {
+		ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	}
+	if tx != nil {
+		execer = tx
+	} else {
+		execer = r.db
+	}
+
+	now := time.Now().UTC()
+	audit.CreatedAt = now
+	audit.UpdatedAt = now
+
+	_, err := execer.ExecContext(ctx, `
+		INSERT INTO inventory_audits (
+			id, storage_location, status, opened_by, opened_at, closed_at, closed_by,
+			created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		audit.ID,
+		audit.StorageLocation,
+		string(audit.Status),
+		audit.OpenedBy,
+		audit.OpenedAt.UTC().Format(time.RFC3339),
+		nullableTimePtrRFC3339(audit.ClosedAt),
+		audit.ClosedBy,
+		audit.CreatedAt.Format(time.RFC3339),
+		audit.UpdatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("inserting inventory audit: %w", constraintError(err))
+	}
+
+	for _, c := range audit.Counts {
+		_, err := execer.ExecContext(ctx, `
+			INSERT INTO inventory_audit_counts (audit_id, stock_id, expected_quantity)
+			VALUES (?, ?, ?)`,
+			audit.ID, c.StockID, c.ExpectedQuantity,
+		)
+		if err != nil {
+			return fmt.Errorf("inserting inventory audit count: %w", constraintError(err))
+		}
+	}
+	return nil
+}
+
+// GetByID retrieves an audit and its counts by ID.
+func (r *InventoryAuditRepository) GetByID(ctx context.Context, id string) (*models.InventoryAudit, error) {
+	audit, err := r.scanAudit(r.db.QueryRowContext(ctx, inventoryAuditSelect+" WHERE id = ?", id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("inventory audit %w: %s", ErrNotFound, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.loadCounts(ctx, audit); err != nil {
+		return nil, err
+	}
+	return audit, nil
+}
+
+// List retrieves audits, most recently opened first, optionally limited to
+// one status. Counts are not loaded.
+func (r *InventoryAuditRepository) List(ctx context.Context, status *models.InventoryAuditStatus) ([]*models.InventoryAudit, error) {
+	query := inventoryAuditSelect
+	var args []any
+	if status != nil {
+		query += " WHERE status = ?"
+		args = append(args, string(*status))
+	}
+	query += " ORDER BY opened_at DESC, id"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying inventory audits: %w", err)
+	}
+	return collect(rows, r.scanAudit)
+}
+
+// RecordCount records the counted quantity of a lot in an audit, replacing
+// an earlier count.
+func (r *InventoryAuditRepository) RecordCount(ctx context.Context, tx *sql.Tx, auditID, stockID string, quantity float64, at time.Time) error {
+	var execer interface {
+		ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	}
+	if tx != nil {
+		execer = tx
+	} else {
+		execer = r.db
+	}
+
+	result, err := execer.ExecContext(ctx, `
+		UPDATE inventory_audit_counts SET counted_quantity = ?, counted_at = ?
+		WHERE audit_id = ? AND stock_id = ?`,
+		quantity, at.UTC().Format(time.RFC3339), auditID, stockID,
+	)
+	if err != nil {
+		return fmt.Errorf("recording count: %w", constraintError(err))
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("lot %w in audit %s: %s", ErrNotFound, auditID, stockID)
+	}
+	return nil
+}
+
+// UpdateStatus records an audit's status and who closed it when.
+func (r *InventoryAuditRepository) UpdateStatus(ctx context.Context, tx *sql.Tx, audit *models.InventoryAudit) error {
+	if !audit.Status.Valid() {
+		return fmt.Errorf("%w: invalid status: %s", ErrValidation, audit.Status)
+	}
+
+	var execer interface {
+		ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	}
+	if tx != nil {
+		execer = tx
+	} else {
+		execer = r.db
+	}
+
+	audit.UpdatedAt = time.Now().UTC()
+
+	result, err := execer.ExecContext(ctx, `
+		UPDATE inventory_audits SET status = ?, closed_at = ?, closed_by = ?, updated_at = ?
+		WHERE id = ?`,
+		string(audit.Status),
+		nullableTimePtrRFC3339(audit.ClosedAt),
+		audit.ClosedBy,
+		audit.UpdatedAt.Format(time.RFC3339),
+		audit.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("updating inventory audit: %w", constraintError(err))
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("inventory audit %w: %s", ErrNotFound, audit.ID)
+	}
+	return nil
+}
+
+const inventoryAuditSelect = `
+	SELECT id, storage_location, status, opened_by, opened_at, closed_at, closed_by,
+		created_at, updated_at
+	FROM inventory_audits`
+
+// loadCounts loads an audit's counts with their lot and item, in item code
+// and lot order.
+func (r *InventoryAuditRepository) loadCounts(ctx context.Context, audit *models.InventoryAudit) error {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT c.stock_id, c.expected_quantity, c.counted_quantity, c.counted_at,
+			i.item_code, i.name, i.unit_of_measure, s.lot_number
+		FROM inventory_audit_counts c
+		JOIN resource_stocks s ON c.stock_id = s.id
+		JOIN resource_items i ON s.item_id = i.id
+		WHERE c.audit_id = ?
+		ORDER BY i.item_code, s.lot_number, c.stock_id`, audit.ID)
+	if err != nil {
+		return fmt.Errorf("querying inventory audit counts: %w", err)
+	}
+	audit.Counts, err = collect(rows, r.scanCount)
+	return err
+}
+
+// scanCount scans an audit count from a rows iterator.
+func (r *InventoryAuditRepository) scanCount(row rowScanner) (models.InventoryAuditCount, error) {
+	var c models.InventoryAuditCount
+	var counted sql.NullFloat64
+	var countedAt, lot sql.NullString
+	if err := row.Scan(
+		&c.StockID, &c.ExpectedQuantity, &counted, &countedAt,
+		&c.ItemCode, &c.ItemName, &c.UnitOfMeasure, &lot,
+	); err != nil {
+		return c, fmt.Errorf("scanning inventory audit count: %w", err)
+	}
+	c.CountedQuantity = floatPtr(counted)
+	c.CountedAt = timePtr(time.RFC3339, countedAt)
+	c.LotNumber = stringPtr(lot)
+	return c, nil
+}
+
+// scanAudit scans an audit from a single row or a rows iterator.
+func (r *InventoryAuditRepository) scanAudit(row rowScanner) (*models.InventoryAudit, error) {
+	var audit models.InventoryAudit
+	var openedBy, closedAt, closedBy sql.NullString
+	var openedStr, createdStr, updatedStr string
+
+	err := row.Scan(
+		&audit.ID, &audit.StorageLocation, &audit.Status, &openedBy, &openedStr,
+		&closedAt, &closedBy, &createdStr, &updatedStr,
+	)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scanning inventory audit: %w", err)
+	}
+
+	audit.OpenedBy = stringPtr(openedBy)
+	audit.ClosedAt = timePtr(time.RFC3339, closedAt)
+	audit.ClosedBy = stringPtr(closedBy)
+	audit.OpenedAt = parseTime(time.RFC3339, openedStr)
+	audit.CreatedAt = parseTime(time.RFC3339, createdStr)
+	audit.UpdatedAt = parseTime(time.RFC3339, updatedStr)
+	return &audit, nil
+}
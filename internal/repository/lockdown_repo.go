@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// LockdownRepository handles vault alert state data access.
+type LockdownRepository struct {
+	db *sql.DB
+}
+
+// NewLockdownRepository creates a new lockdown repository.
+func NewLockdownRepository(db *sql.DB) *LockdownRepository {
+	return &LockdownRepository{db: db}
+}
+
+// Create inserts a new state change.
+func (r *LockdownRepository) Create(ctx context.Context, tx *sql.Tx, c *models.LockdownChange) error {
+	if err := c.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	c.CreatedAt = time.Now().UTC()
+
+	_, err := r.getExecer(tx).ExecContext(ctx, `
+		INSERT INTO lockdown_changes (
+			id, state, previous_state, authorized_by, reason, changed_at, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		c.ID,
+		string(c.State),
+		string(c.PreviousState),
+		c.AuthorizedBy,
+		nullableString(c.Reason),
+		c.ChangedAt.Format(time.RFC3339),
+		c.CreatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("inserting lockdown change: %w", constraintError(err))
+	}
+	return nil
+}
+
+// GetCurrent retrieves the most recent state change.
+func (r *LockdownRepository) GetCurrent(ctx context.Context) (*models.LockdownChange, error) {
+	c, err := r.scan(r.db.QueryRowContext(ctx, lockdownSelect+`
+		ORDER BY changed_at DESC, created_at DESC
+		LIMIT 1`))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("lockdown change %w", ErrNotFound)
+	}
+	return c, err
+}
+
+// ListRecent retrieves up to limit state changes, most recent first.
+func (r *LockdownRepository) ListRecent(ctx context.Context, limit int) ([]*models.LockdownChange, error) {
+	rows, err := r.db.QueryContext(ctx, lockdownSelect+`
+		ORDER BY changed_at DESC, created_at DESC
+		LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("querying lockdown changes: %w", err)
+	}
+	return collect(rows, r.scan)
+}
+
+const lockdownSelect = `
+	SELECT id, state, previous_state, authorized_by, reason, changed_at, created_at
+	FROM lockdown_changes`
+
+func (r *LockdownRepository) getExecer(tx *sql.Tx) interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+} {
+	if tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+// scan scans a state change from a single row or a rows iterator.
+func (r *LockdownRepository) scan(row rowScanner) (*models.LockdownChange, error) {
+	var c models.LockdownChange
+	var authorizedBy, reason sql.NullString
+	var changedStr, createdStr string
+
+	err := row.Scan(&c.ID, &c.State, &c.PreviousState, &authorizedBy, &reason, &changedStr, &createdStr)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scanning lockdown change: %w", err)
+	}
+
+	c.AuthorizedBy = stringPtr(authorizedBy)
+	c.Reason = reason.String
+	c.ChangedAt = parseTime(time.RFC3339, changedStr)
+	c.CreatedAt = parseTime(time.RFC3339, createdStr)
+	return &c, nil
+}
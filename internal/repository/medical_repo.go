@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// MedicalRepository handles medical condition data access.
+type MedicalRepository struct {
+	db *sql.DB
+}
+
+// NewMedicalRepository creates a new medical repository.
+func NewMedicalRepository(db *sql.DB) *MedicalRepository {
+	return &MedicalRepository{db: db}
+}
+
+// CreateCondition inserts a new medical condition.
+func (r *MedicalRepository) CreateCondition(ctx context.Context, tx *sql.Tx, cond *models.MedicalCondition) error {
+	if err := cond.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	query := `
+		INSERT INTO medical_conditions (
+			id, resident_id, condition_code, condition_name, onset_date, resolution_date,
+			severity, is_chronic, is_genetic, is_contagious, treatment_plan, notes,
+			created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	plan, err := sealedString("medical_conditions.treatment_plan", cond.TreatmentPlan)
+	if err != nil {
+		return err
+	}
+	notes, err := sealedString("medical_conditions.notes", cond.Notes)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	cond.CreatedAt = now
+	cond.UpdatedAt = now
+
+	_, err = r.getExecer(tx).ExecContext(ctx, query,
+		cond.ID,
+		cond.ResidentID,
+		cond.ConditionCode,
+		cond.ConditionName,
+		cond.OnsetDate.Format(time.DateOnly),
+		nullableTime(cond.ResolutionDate),
+		string(cond.Severity),
+		boolToInt(cond.IsChronic),
+		boolToInt(cond.IsGenetic),
+		boolToInt(cond.IsContagious),
+		plan,
+		notes,
+		cond.CreatedAt.Format(time.RFC3339),
+		cond.UpdatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("inserting medical condition: %w", constraintError(err))
+	}
+	return nil
+}
+
+// ListConditions retrieves a resident's conditions, open and resolved, in
+// order of onset.
+func (r *MedicalRepository) ListConditions(ctx context.Context, residentID string) ([]*models.MedicalCondition, error) {
+	rows, err := r.db.QueryContext(ctx, medicalConditionSelect+`
+		WHERE resident_id = ?
+		ORDER BY onset_date, id`, residentID)
+	if err != nil {
+		return nil, fmt.Errorf("querying medical conditions: %w", err)
+	}
+	return collect(rows, r.scanCondition)
+}
+
+// ListOpenConditions retrieves a resident's unresolved conditions, most
+// recent first.
+func (r *MedicalRepository) ListOpenConditions(ctx context.Context, residentID string) ([]*models.MedicalCondition, error) {
+	rows, err := r.db.QueryContext(ctx, medicalConditionSelect+`
+		WHERE resident_id = ? AND resolution_date IS NULL
+		ORDER BY onset_date DESC, id`, residentID)
+	if err != nil {
+		return nil, fmt.Errorf("querying medical conditions: %w", err)
+	}
+	return collect(rows, r.scanCondition)
+}
+
+// ResolveCondition records the date an open condition resolved.
+func (r *MedicalRepository) ResolveCondition(ctx context.Context, tx *sql.Tx, id string, date time.Time) error {
+	result, err := r.getExecer(tx).ExecContext(ctx, `
+		UPDATE medical_conditions SET resolution_date = ?, updated_at = ?
+		WHERE id = ? AND resolution_date IS NULL`,
+		date.Format(time.DateOnly),
+		time.Now().UTC().Format(time.RFC3339),
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("resolving medical condition: %w", constraintError(err))
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("open medical condition %w: %s", ErrNotFound, id)
+	}
+	return nil
+}
+
+const medicalConditionSelect = `
+	SELECT id, resident_id, condition_code, condition_name, onset_date, resolution_date,
+		severity, is_chronic, is_genetic, is_contagious, treatment_plan, notes,
+		created_at, updated_at
+	FROM medical_conditions`
+
+func (r *MedicalRepository) getExecer(tx *sql.Tx) interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+} {
+	if tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+// scanCondition scans a medical condition from a single row or a rows
+// iterator.
+func (r *MedicalRepository) scanCondition(row rowScanner) (*models.MedicalCondition, error) {
+	var cond models.MedicalCondition
+	var resolution, plan, notes sql.NullString
+	var onsetStr, createdStr, updatedStr string
+	var chronic, genetic, contagious int
+
+	err := row.Scan(
+		&cond.ID, &cond.ResidentID, &cond.ConditionCode, &cond.ConditionName, &onsetStr, &resolution,
+		&cond.Severity, &chronic, &genetic, &contagious, &plan, &notes,
+		&createdStr, &updatedStr,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("scanning medical condition: %w", err)
+	}
+
+	cond.OnsetDate = parseTime(time.DateOnly, onsetStr)
+	cond.ResolutionDate = timePtr(time.DateOnly, resolution)
+	cond.IsChronic = chronic == 1
+	cond.IsGenetic = genetic == 1
+	cond.IsContagious = contagious == 1
+	if cond.TreatmentPlan, err = openString("medical_conditions.treatment_plan", plan); err != nil {
+		return nil, err
+	}
+	if cond.Notes, err = openString("medical_conditions.notes", notes); err != nil {
+		return nil, err
+	}
+	cond.CreatedAt = parseTime(time.RFC3339, createdStr)
+	cond.UpdatedAt = parseTime(time.RFC3339, updatedStr)
+	return &cond, nil
+}
@@ -0,0 +1,300 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// MoraleRepository handles resident morale and happiness index data access.
+type MoraleRepository struct {
+	db    *sql.DB
+	vault int // Vault residents and snapshots are limited to, 0 for every vault
+}
+
+// NewMoraleRepository creates a new morale repository.
+func NewMoraleRepository(db *sql.DB) *MoraleRepository {
+	return &MoraleRepository{db: db}
+}
+
+// ForVault returns a copy of the repository whose residents and snapshots
+// are limited to the vault, and which records snapshots there.
+func (r *MoraleRepository) ForVault(vault int) *MoraleRepository {
+	scoped := *r
+	scoped.vault = vault
+	return &scoped
+}
+
+// ListFactors retrieves the conditions of every active resident's morale
+// other than incidents: their household's ration class, the sector,
+// occupancy and capacity of the quarters they are housed in, their own or
+// their household's, and their active work assignments other than
+// training. By registry number.
+func (r *MoraleRepository) ListFactors(ctx context.Context) ([]*models.MoraleFactors, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		WITH housed AS (
+			SELECT COALESCE(res.quarters_id, h.quarters_id) AS quarters_id
+			FROM residents res
+			LEFT JOIN households h ON h.id = res.household_id
+			WHERE res.status = 'ACTIVE'
+		),
+		occupancy AS (
+			SELECT quarters_id, COUNT(*) AS occupants FROM housed
+			WHERE quarters_id IS NOT NULL
+			GROUP BY quarters_id
+		),
+		workload AS (
+			SELECT resident_id, COUNT(*) AS assignments FROM work_assignments
+			WHERE status = 'ACTIVE' AND assignment_type != 'TRAINING'
+			GROUP BY resident_id
+		)
+		SELECT res.id, COALESCE(h.ration_class, ''), COALESCE(q.sector, ''),
+			COALESCE(o.occupants, 0), COALESCE(q.capacity, 0), COALESCE(w.assignments, 0)
+		FROM residents res
+		LEFT JOIN households h ON h.id = res.household_id
+		LEFT JOIN quarters q ON q.id = COALESCE(res.quarters_id, h.quarters_id)
+		LEFT JOIN occupancy o ON o.quarters_id = q.id
+		LEFT JOIN workload w ON w.resident_id = res.id
+		WHERE res.status = 'ACTIVE' AND (? = 0 OR res.vault_id = ?)
+		ORDER BY res.registry_number`,
+		r.vault, r.vault)
+	if err != nil {
+		return nil, fmt.Errorf("querying morale factors: %w", err)
+	}
+	return collect(rows, func(row rowScanner) (*models.MoraleFactors, error) {
+		var f models.MoraleFactors
+		err := row.Scan(&f.ResidentID, &f.RationClass, &f.Sector, &f.Occupants, &f.Capacity, &f.Assignments)
+		if err != nil {
+			return nil, fmt.Errorf("scanning morale factors: %w", err)
+		}
+		return &f, nil
+	})
+}
+
+// ListSectorIncidents retrieves the severities of the security incidents
+// that occurred in each sector since a time.
+func (r *MoraleRepository) ListSectorIncidents(ctx context.Context, since time.Time) (map[string][]models.Severity, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT location_sector, severity FROM security_incidents
+		WHERE location_sector IS NOT NULL AND occurred_at >= ?
+		ORDER BY occurred_at`,
+		since.UTC().Format(time.RFC3339))
+	if err != nil {
+		return nil, fmt.Errorf("querying sector incidents: %w", err)
+	}
+	defer rows.Close()
+
+	incidents := make(map[string][]models.Severity)
+	for rows.Next() {
+		var sector string
+		var severity models.Severity
+		if err := rows.Scan(&sector, &severity); err != nil {
+			return nil, fmt.Errorf("scanning sector incident: %w", err)
+		}
+		incidents[sector] = append(incidents[sector], severity)
+	}
+	return incidents, rows.Err()
+}
+
+// ListActivityHours retrieves the hours of classes and recreation each
+// resident attended in sessions since a time.
+func (r *MoraleRepository) ListActivityHours(ctx context.Context, since time.Time) (map[string]float64, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT resident_id, SUM(hours) FROM activity_attendance
+		WHERE present = 1 AND session_at >= ?
+		GROUP BY resident_id`,
+		since.UTC().Format(time.RFC3339))
+	if err != nil {
+		return nil, fmt.Errorf("querying activity hours: %w", err)
+	}
+	defer rows.Close()
+
+	hours := make(map[string]float64)
+	for rows.Next() {
+		var residentID string
+		var h float64
+		if err := rows.Scan(&residentID, &h); err != nil {
+			return nil, fmt.Errorf("scanning activity hours: %w", err)
+		}
+		hours[residentID] = h
+	}
+	return hours, rows.Err()
+}
+
+// SaveScores records residents' morale scores, replacing their previous
+// ones.
+func (r *MoraleRepository) SaveScores(ctx context.Context, tx *sql.Tx, scores []*models.ResidentMorale) error {
+	now := time.Now().UTC()
+	execer := r.getExecer(tx)
+	for _, m := range scores {
+		m.UpdatedAt = now
+		_, err := execer.ExecContext(ctx, `
+			INSERT INTO resident_morale (
+				resident_id, score, level, ration_effect, crowding_effect,
+				incident_effect, workload_effect, activity_effect, scored_at, updated_at
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT (resident_id) DO UPDATE SET
+				score = excluded.score,
+				level = excluded.level,
+				ration_effect = excluded.ration_effect,
+				crowding_effect = excluded.crowding_effect,
+				incident_effect = excluded.incident_effect,
+				workload_effect = excluded.workload_effect,
+				activity_effect = excluded.activity_effect,
+				scored_at = excluded.scored_at,
+				updated_at = excluded.updated_at`,
+			m.ResidentID,
+			m.Score,
+			string(m.Level),
+			m.RationEffect,
+			m.CrowdingEffect,
+			m.IncidentEffect,
+			m.WorkloadEffect,
+			m.ActivityEffect,
+			m.ScoredAt.UTC().Format(time.RFC3339),
+			m.UpdatedAt.Format(time.RFC3339),
+		)
+		if err != nil {
+			return fmt.Errorf("saving morale of resident %s: %w", m.ResidentID, constraintError(err))
+		}
+	}
+	return nil
+}
+
+// GetScore retrieves a resident's latest morale score.
+func (r *MoraleRepository) GetScore(ctx context.Context, residentID string) (*models.ResidentMorale, error) {
+	m, err := r.scanScore(r.db.QueryRowContext(ctx, `
+		SELECT resident_id, score, level, ration_effect, crowding_effect,
+			incident_effect, workload_effect, activity_effect, scored_at, updated_at
+		FROM resident_morale
+		WHERE resident_id = ?`, residentID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("morale score %w: %s", ErrNotFound, residentID)
+	}
+	return m, err
+}
+
+// ListLowest retrieves up to limit scores of active residents, lowest
+// first.
+func (r *MoraleRepository) ListLowest(ctx context.Context, limit int) ([]*models.ResidentMorale, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT m.resident_id, m.score, m.level, m.ration_effect, m.crowding_effect,
+			m.incident_effect, m.workload_effect, m.activity_effect, m.scored_at, m.updated_at
+		FROM resident_morale m
+		JOIN residents res ON res.id = m.resident_id
+		WHERE res.status = 'ACTIVE' AND (? = 0 OR res.vault_id = ?)
+		ORDER BY m.score, res.registry_number
+		LIMIT ?`,
+		r.vault, r.vault, limit)
+	if err != nil {
+		return nil, fmt.Errorf("querying morale scores: %w", err)
+	}
+	return collect(rows, r.scanScore)
+}
+
+// ListSectors retrieves the morale of the active residents housed in each
+// sector, lowest first.
+func (r *MoraleRepository) ListSectors(ctx context.Context) ([]*models.SectorMorale, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT q.sector, COUNT(*), CAST(ROUND(AVG(m.score)) AS INTEGER)
+		FROM resident_morale m
+		JOIN residents res ON res.id = m.resident_id
+		LEFT JOIN households h ON h.id = res.household_id
+		JOIN quarters q ON q.id = COALESCE(res.quarters_id, h.quarters_id)
+		WHERE res.status = 'ACTIVE' AND (? = 0 OR res.vault_id = ?)
+		GROUP BY q.sector
+		ORDER BY 3, q.sector`,
+		r.vault, r.vault)
+	if err != nil {
+		return nil, fmt.Errorf("querying sector morale: %w", err)
+	}
+	return collect(rows, func(row rowScanner) (*models.SectorMorale, error) {
+		var s models.SectorMorale
+		if err := row.Scan(&s.Sector, &s.Residents, &s.Index); err != nil {
+			return nil, fmt.Errorf("scanning sector morale: %w", err)
+		}
+		return &s, nil
+	})
+}
+
+// CreateSnapshot inserts a new happiness index snapshot.
+func (r *MoraleRepository) CreateSnapshot(ctx context.Context, tx *sql.Tx, s *models.MoraleSnapshot) error {
+	if s.ID == "" || s.TakenAt.IsZero() {
+		return fmt.Errorf("%w: id and taken_at are required", ErrValidation)
+	}
+
+	s.CreatedAt = time.Now().UTC()
+
+	_, err := r.getExecer(tx).ExecContext(ctx, `
+		INSERT INTO morale_snapshots (id, vault_id, taken_at, population, happiness_index, low_morale, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		s.ID,
+		r.vault,
+		s.TakenAt.UTC().Format(time.RFC3339),
+		s.Population,
+		s.Index,
+		s.Low,
+		s.CreatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("inserting morale snapshot: %w", constraintError(err))
+	}
+	return nil
+}
+
+// ListRecentSnapshots retrieves up to limit snapshots, most recent first.
+func (r *MoraleRepository) ListRecentSnapshots(ctx context.Context, limit int) ([]*models.MoraleSnapshot, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, taken_at, population, happiness_index, low_morale, created_at
+		FROM morale_snapshots
+		WHERE `+vaultCondition+`
+		ORDER BY taken_at DESC, created_at DESC
+		LIMIT ?`, r.vault, r.vault, limit)
+	if err != nil {
+		return nil, fmt.Errorf("querying morale snapshots: %w", err)
+	}
+	return collect(rows, func(row rowScanner) (*models.MoraleSnapshot, error) {
+		var s models.MoraleSnapshot
+		var takenStr, createdStr string
+		err := row.Scan(&s.ID, &takenStr, &s.Population, &s.Index, &s.Low, &createdStr)
+		if err != nil {
+			return nil, fmt.Errorf("scanning morale snapshot: %w", err)
+		}
+		s.TakenAt = parseTime(time.RFC3339, takenStr)
+		s.CreatedAt = parseTime(time.RFC3339, createdStr)
+		return &s, nil
+	})
+}
+
+func (r *MoraleRepository) getExecer(tx *sql.Tx) interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+} {
+	if tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+// scanScore scans a morale score from a single row or a rows iterator.
+func (r *MoraleRepository) scanScore(row rowScanner) (*models.ResidentMorale, error) {
+	var m models.ResidentMorale
+	var scoredStr, updatedStr string
+
+	err := row.Scan(
+		&m.ResidentID, &m.Score, &m.Level, &m.RationEffect, &m.CrowdingEffect,
+		&m.IncidentEffect, &m.WorkloadEffect, &m.ActivityEffect, &scoredStr, &updatedStr,
+	)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scanning morale score: %w", err)
+	}
+
+	m.ScoredAt = parseTime(time.RFC3339, scoredStr)
+	m.UpdatedAt = parseTime(time.RFC3339, updatedStr)
+	return &m, nil
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// ProductionRunRepository handles production run data access.
+type ProductionRunRepository struct {
+	db    *sql.DB
+	vault int // Vault runs are limited to, 0 for every vault
+}
+
+// NewProductionRunRepository creates a new production run repository.
+func NewProductionRunRepository(db *sql.DB) *ProductionRunRepository {
+	return &ProductionRunRepository{db: db}
+}
+
+// ForVault returns a copy of the repository whose lists are limited to runs
+// of the vault, and which queues runs there.
+func (r *ProductionRunRepository) ForVault(vault int) *ProductionRunRepository {
+	scoped := *r
+	scoped.vault = vault
+	return &scoped
+}
+
+// productionRunSelect selects runs, aliased p, with their item's code, name
+// and unit.
+const productionRunSelect = `
+	SELECT p.id, p.item_id, p.quantity, p.status, p.reason, p.queued_at, p.closed_at,
+		p.stock_id, p.vault_id, i.item_code, i.name, i.unit_of_measure
+	FROM production_runs p
+	JOIN resource_items i ON i.id = p.item_id`
+
+// Create queues a new production run in the repository's vault. A second
+// run queued for the same item fails with ErrDuplicate.
+func (r *ProductionRunRepository) Create(ctx context.Context, tx *sql.Tx, run *models.ProductionRun) error {
+	if r.vault != 0 {
+		run.VaultID = r.vault
+	}
+	if err := run.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	_, err := r.getExecer(tx).ExecContext(ctx, `
+		INSERT INTO production_runs (
+			id, item_id, quantity, status, reason, queued_at, vault_id
+		) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		run.ID,
+		run.ItemID,
+		run.Quantity,
+		string(run.Status),
+		run.Reason,
+		run.QueuedAt.UTC().Format(time.RFC3339),
+		run.VaultID,
+	)
+	if err != nil {
+		return fmt.Errorf("inserting production run: %w", constraintError(err))
+	}
+	return nil
+}
+
+// Close completes or cancels a queued run, recording the lot it produced
+// when completed.
+func (r *ProductionRunRepository) Close(ctx context.Context, tx *sql.Tx, run *models.ProductionRun) error {
+	if run.Status == models.ProductionRunQueued || run.ClosedAt == nil {
+		return fmt.Errorf("%w: closing a production run needs its outcome and time", ErrValidation)
+	}
+
+	result, err := r.getExecer(tx).ExecContext(ctx, `
+		UPDATE production_runs SET status = ?, closed_at = ?, stock_id = ?
+		WHERE id = ? AND status = 'QUEUED'`,
+		string(run.Status),
+		run.ClosedAt.UTC().Format(time.RFC3339),
+		run.StockID,
+		run.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("closing production run: %w", constraintError(err))
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("queued production run %w: %s", ErrNotFound, run.ID)
+	}
+	return nil
+}
+
+// GetQueued retrieves the run queued for an item in the repository's vault.
+func (r *ProductionRunRepository) GetQueued(ctx context.Context, itemID string) (*models.ProductionRun, error) {
+	row := r.db.QueryRowContext(ctx, productionRunSelect+`
+		WHERE p.item_id = ? AND p.status = 'QUEUED' AND p.vault_id = ?`, itemID, r.vault)
+	return r.scan(row)
+}
+
+// ListQueued retrieves the queued runs of the repository's vault, oldest
+// first.
+func (r *ProductionRunRepository) ListQueued(ctx context.Context) ([]*models.ProductionRun, error) {
+	rows, err := r.db.QueryContext(ctx, productionRunSelect+`
+		WHERE p.status = 'QUEUED' AND (? = 0 OR p.vault_id = ?)
+		ORDER BY p.queued_at, i.item_code`, r.vault, r.vault)
+	if err != nil {
+		return nil, fmt.Errorf("querying production runs: %w", err)
+	}
+	return collect(rows, r.scan)
+}
+
+func (r *ProductionRunRepository) getExecer(tx *sql.Tx) interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+} {
+	if tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+// scan scans a production run from a single row or a rows iterator.
+func (r *ProductionRunRepository) scan(row rowScanner) (*models.ProductionRun, error) {
+	var run models.ProductionRun
+	var item models.ResourceItem
+	var closedAt, stockID sql.NullString
+	var queuedStr string
+
+	err := row.Scan(&run.ID, &run.ItemID, &run.Quantity, &run.Status, &run.Reason, &queuedStr, &closedAt,
+		&stockID, &run.VaultID, &item.ItemCode, &item.Name, &item.UnitOfMeasure)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("production run %w", ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scanning production run: %w", err)
+	}
+
+	run.QueuedAt = parseTime(time.RFC3339, queuedStr)
+	run.ClosedAt = timePtr(time.RFC3339, closedAt)
+	run.StockID = stringPtr(stockID)
+	item.ID = run.ItemID
+	run.Item = &item
+	return &run, nil
+}
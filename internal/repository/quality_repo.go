@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// QualityTestRepository handles quality test data access.
+type QualityTestRepository struct {
+	db *sql.DB
+}
+
+// NewQualityTestRepository creates a new quality test repository.
+func NewQualityTestRepository(db *sql.DB) *QualityTestRepository {
+	return &QualityTestRepository{db: db}
+}
+
+// Create inserts a new quality test.
+func (r *QualityTestRepository) Create(ctx context.Context, tx *sql.Tx, q *models.QualityTest) error {
+	if err := q.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	q.CreatedAt = time.Now().UTC()
+
+	_, err := r.getExecer(tx).ExecContext(ctx, `
+		INSERT INTO quality_tests (
+			id, stock_id, tested_at, tested_by, contamination_level, passed, notes, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		q.ID,
+		q.StockID,
+		q.TestedAt.UTC().Format(time.RFC3339),
+		q.TestedBy,
+		q.ContaminationLevel,
+		boolToInt(q.Passed),
+		nullableString(q.Notes),
+		q.CreatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("inserting quality test: %w", constraintError(err))
+	}
+	return nil
+}
+
+// ListByStock retrieves the quality tests of a lot, most recent first.
+func (r *QualityTestRepository) ListByStock(ctx context.Context, stockID string) ([]*models.QualityTest, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, stock_id, tested_at, tested_by, contamination_level, passed, notes, created_at
+		FROM quality_tests
+		WHERE stock_id = ?
+		ORDER BY tested_at DESC, created_at DESC`, stockID)
+	if err != nil {
+		return nil, fmt.Errorf("querying quality tests: %w", err)
+	}
+	return collect(rows, r.scan)
+}
+
+func (r *QualityTestRepository) getExecer(tx *sql.Tx) interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+} {
+	if tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+// scan scans a quality test from a single row or a rows iterator.
+func (r *QualityTestRepository) scan(row rowScanner) (*models.QualityTest, error) {
+	var q models.QualityTest
+	var testedBy, notes sql.NullString
+	var testedStr, createdStr string
+	var passed int
+
+	err := row.Scan(&q.ID, &q.StockID, &testedStr, &testedBy, &q.ContaminationLevel, &passed, &notes, &createdStr)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scanning quality test: %w", err)
+	}
+
+	q.TestedAt = parseTime(time.RFC3339, testedStr)
+	q.TestedBy = stringPtr(testedBy)
+	q.Passed = passed == 1
+	q.Notes = notes.String
+	q.CreatedAt = parseTime(time.RFC3339, createdStr)
+	return &q, nil
+}
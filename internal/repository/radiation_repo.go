@@ -0,0 +1,225 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// RadiationRepository handles radiation exposure and decontamination data
+// access.
+type RadiationRepository struct {
+	db    *sql.DB
+	vault int // Vault dose lists are limited to, 0 for every vault
+}
+
+// NewRadiationRepository creates a new radiation repository.
+func NewRadiationRepository(db *sql.DB) *RadiationRepository {
+	return &RadiationRepository{db: db}
+}
+
+// ForVault returns a copy of the repository whose dose lists are limited to
+// residents of the vault.
+func (r *RadiationRepository) ForVault(vault int) *RadiationRepository {
+	scoped := *r
+	scoped.vault = vault
+	return &scoped
+}
+
+// CreateExposure inserts a new exposure event.
+func (r *RadiationRepository) CreateExposure(ctx context.Context, tx *sql.Tx, exp *models.RadiationExposure) error {
+	if err := exp.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	notes, err := sealedString("radiation_exposures.notes", exp.Notes)
+	if err != nil {
+		return err
+	}
+
+	exp.CreatedAt = time.Now().UTC()
+
+	_, err = r.getExecer(tx).ExecContext(ctx, `
+		INSERT INTO radiation_exposures (
+			id, resident_id, source, dose_msv, exposure_date, recorded_by, notes, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		exp.ID,
+		exp.ResidentID,
+		exp.Source,
+		exp.DoseMSv,
+		exp.ExposureDate.Format(time.RFC3339),
+		exp.RecordedBy,
+		notes,
+		exp.CreatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("inserting radiation exposure: %w", constraintError(err))
+	}
+	return nil
+}
+
+// CreateTreatment inserts a new decontamination treatment.
+func (r *RadiationRepository) CreateTreatment(ctx context.Context, tx *sql.Tx, t *models.DecontaminationTreatment) error {
+	if err := t.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	notes, err := sealedString("decontamination_treatments.notes", t.Notes)
+	if err != nil {
+		return err
+	}
+
+	t.CreatedAt = time.Now().UTC()
+
+	_, err = r.getExecer(tx).ExecContext(ctx, `
+		INSERT INTO decontamination_treatments (
+			id, resident_id, item_id, quantity, dose_reduced_msv, treatment_date,
+			provider_id, notes, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		t.ID,
+		t.ResidentID,
+		t.ItemID,
+		t.Quantity,
+		t.DoseReducedMSv,
+		t.TreatmentDate.Format(time.RFC3339),
+		t.ProviderID,
+		notes,
+		t.CreatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("inserting decontamination treatment: %w", constraintError(err))
+	}
+	return nil
+}
+
+// ListExposures retrieves a resident's exposure events, most recent first.
+func (r *RadiationRepository) ListExposures(ctx context.Context, residentID string) ([]*models.RadiationExposure, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, resident_id, source, dose_msv, exposure_date, recorded_by, notes, created_at
+		FROM radiation_exposures
+		WHERE resident_id = ?
+		ORDER BY exposure_date DESC, id`, residentID)
+	if err != nil {
+		return nil, fmt.Errorf("querying radiation exposures: %w", err)
+	}
+	return collect(rows, r.scanExposure)
+}
+
+// ListTreatments retrieves a resident's decontamination treatments, most
+// recent first.
+func (r *RadiationRepository) ListTreatments(ctx context.Context, residentID string) ([]*models.DecontaminationTreatment, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, resident_id, item_id, quantity, dose_reduced_msv, treatment_date,
+			provider_id, notes, created_at
+		FROM decontamination_treatments
+		WHERE resident_id = ?
+		ORDER BY treatment_date DESC, id`, residentID)
+	if err != nil {
+		return nil, fmt.Errorf("querying decontamination treatments: %w", err)
+	}
+	return collect(rows, r.scanTreatment)
+}
+
+// GetDose totals a resident's exposures and treatments. A resident with no
+// exposures has a zero dose.
+func (r *RadiationRepository) GetDose(ctx context.Context, residentID string) (*models.RadiationDose, error) {
+	dose, err := r.scanDose(r.db.QueryRowContext(ctx, radiationDoseSelect+" WHERE r.id = ?", residentID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("resident %w: %s", ErrNotFound, residentID)
+	}
+	return dose, err
+}
+
+// ListDoses retrieves the doses of active residents carrying at least
+// minMSv, highest first.
+func (r *RadiationRepository) ListDoses(ctx context.Context, minMSv float64) ([]*models.RadiationDose, error) {
+	rows, err := r.db.QueryContext(ctx, radiationDoseSelect+`
+		WHERE r.status = 'ACTIVE'
+			AND COALESCE(e.total, 0) - COALESCE(t.total, 0) >= ?
+			AND `+vaultCondition+`
+		ORDER BY COALESCE(e.total, 0) - COALESCE(t.total, 0) DESC, r.registry_number`, minMSv, r.vault, r.vault)
+	if err != nil {
+		return nil, fmt.Errorf("querying radiation doses: %w", err)
+	}
+	return collect(rows, r.scanDose)
+}
+
+const radiationDoseSelect = `
+	SELECT r.id, COALESCE(e.total, 0), COALESCE(t.total, 0), COALESCE(e.events, 0), e.last_exposure
+	FROM residents r
+	LEFT JOIN (
+		SELECT resident_id, SUM(dose_msv) AS total, COUNT(*) AS events, MAX(exposure_date) AS last_exposure
+		FROM radiation_exposures GROUP BY resident_id
+	) e ON e.resident_id = r.id
+	LEFT JOIN (
+		SELECT resident_id, SUM(dose_reduced_msv) AS total
+		FROM decontamination_treatments GROUP BY resident_id
+	) t ON t.resident_id = r.id`
+
+func (r *RadiationRepository) getExecer(tx *sql.Tx) interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+} {
+	if tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+// scanDose scans a dose from a single row or a rows iterator.
+func (r *RadiationRepository) scanDose(row rowScanner) (*models.RadiationDose, error) {
+	var dose models.RadiationDose
+	var last sql.NullString
+
+	err := row.Scan(&dose.ResidentID, &dose.ExposedMSv, &dose.PurgedMSv, &dose.Exposures, &last)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scanning radiation dose: %w", err)
+	}
+
+	dose.LastExposure = timePtr(time.RFC3339, last)
+	dose.Level = models.RadiationLevelFor(dose.CumulativeMSv())
+	return &dose, nil
+}
+
+// scanExposure scans an exposure event from a rows iterator.
+func (r *RadiationRepository) scanExposure(row rowScanner) (*models.RadiationExposure, error) {
+	var exp models.RadiationExposure
+	var recordedBy, notes sql.NullString
+	var dateStr, createdStr string
+	if err := row.Scan(&exp.ID, &exp.ResidentID, &exp.Source, &exp.DoseMSv, &dateStr,
+		&recordedBy, &notes, &createdStr); err != nil {
+		return nil, fmt.Errorf("scanning radiation exposure: %w", err)
+	}
+	exp.ExposureDate = parseTime(time.RFC3339, dateStr)
+	exp.RecordedBy = stringPtr(recordedBy)
+	var err error
+	if exp.Notes, err = openString("radiation_exposures.notes", notes); err != nil {
+		return nil, err
+	}
+	exp.CreatedAt = parseTime(time.RFC3339, createdStr)
+	return &exp, nil
+}
+
+// scanTreatment scans a decontamination treatment from a rows iterator.
+func (r *RadiationRepository) scanTreatment(row rowScanner) (*models.DecontaminationTreatment, error) {
+	var t models.DecontaminationTreatment
+	var providerID, notes sql.NullString
+	var dateStr, createdStr string
+	if err := row.Scan(&t.ID, &t.ResidentID, &t.ItemID, &t.Quantity, &t.DoseReducedMSv, &dateStr,
+		&providerID, &notes, &createdStr); err != nil {
+		return nil, fmt.Errorf("scanning decontamination treatment: %w", err)
+	}
+	t.TreatmentDate = parseTime(time.RFC3339, dateStr)
+	t.ProviderID = stringPtr(providerID)
+	var err error
+	if t.Notes, err = openString("decontamination_treatments.notes", notes); err != nil {
+		return nil, err
+	}
+	t.CreatedAt = parseTime(time.RFC3339, createdStr)
+	return &t, nil
+}
@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// RationDrawRepository handles ration draw data access and the consumption
+// reports built on it.
+type RationDrawRepository struct {
+	db    *sql.DB
+	vault int // Vault draws are limited to, 0 for every vault
+}
+
+// NewRationDrawRepository creates a new ration draw repository.
+func NewRationDrawRepository(db *sql.DB) *RationDrawRepository {
+	return &RationDrawRepository{db: db}
+}
+
+// ForVault returns a copy of the repository whose reports are limited to
+// draws of the vault, and which records draws there.
+func (r *RationDrawRepository) ForVault(vault int) *RationDrawRepository {
+	scoped := *r
+	scoped.vault = vault
+	return &scoped
+}
+
+// Create inserts a household's ration draw with its residents by department.
+func (r *RationDrawRepository) Create(ctx context.Context, tx *sql.Tx, d *models.RationDraw) error {
+	if r.vault != 0 {
+		d.VaultID = r.vault
+	}
+	if err := d.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	execer := r.getExecer(tx)
+	_, err := execer.ExecContext(ctx, `
+		INSERT INTO ration_draws (
+			id, day, household_id, ration_class, residents, calories_required,
+			calories_drawn, water_required_l, water_drawn_l,
+			protein_required_g, protein_drawn_g, carbs_required_g, carbs_drawn_g,
+			fat_required_g, fat_drawn_g, vitamins_required_pct, vitamins_drawn_pct, vault_id
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		d.ID,
+		d.Day.UTC().Format(time.DateOnly),
+		d.HouseholdID,
+		string(d.RationClass),
+		d.Residents,
+		d.CaloriesRequired,
+		d.CaloriesDrawn,
+		d.WaterRequiredL,
+		d.WaterDrawnL,
+		d.NutrientsRequired.ProteinG, d.NutrientsDrawn.ProteinG,
+		d.NutrientsRequired.CarbsG, d.NutrientsDrawn.CarbsG,
+		d.NutrientsRequired.FatG, d.NutrientsDrawn.FatG,
+		d.NutrientsRequired.VitaminsPct, d.NutrientsDrawn.VitaminsPct,
+		d.VaultID,
+	)
+	if err != nil {
+		return fmt.Errorf("inserting ration draw: %w", constraintError(err))
+	}
+
+	// In department order, so a draw is written the same way every time
+	departments := make([]models.Department, 0, len(d.Departments))
+	for dept := range d.Departments {
+		departments = append(departments, dept)
+	}
+	slices.Sort(departments)
+	for _, dept := range departments {
+		_, err := execer.ExecContext(ctx, `
+			INSERT INTO ration_draw_departments (draw_id, department, residents) VALUES (?, ?, ?)`,
+			d.ID, string(dept), d.Departments[dept])
+		if err != nil {
+			return fmt.Errorf("inserting ration draw department: %w", constraintError(err))
+		}
+	}
+	return nil
+}
+
+// DrawnOn reports, within tx if given, whether the vault has drawn rations
+// for day.
+func (r *RationDrawRepository) DrawnOn(ctx context.Context, tx *sql.Tx, day time.Time) (bool, error) {
+	var drawn bool
+	err := r.getQuerier(tx).QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM ration_draws WHERE vault_id = ? AND day = ?)`,
+		r.vault, day.UTC().Format(time.DateOnly)).Scan(&drawn)
+	if err != nil {
+		return false, fmt.Errorf("checking ration draws: %w", err)
+	}
+	return drawn, nil
+}
+
+// TopHouseholds returns the households that drew the most calories on the
+// days in [from, to), most first, at most limit of them.
+func (r *RationDrawRepository) TopHouseholds(ctx context.Context, from, to time.Time, limit int) ([]*models.HouseholdConsumption, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT h.id, h.designation, h.ration_class,
+			SUM(d.residents), SUM(d.calories_drawn), SUM(d.water_drawn_l)
+		FROM ration_draws d
+		JOIN households h ON h.id = d.household_id
+		WHERE d.day >= ? AND d.day < ? AND (? = 0 OR d.vault_id = ?)
+		GROUP BY h.id
+		ORDER BY SUM(d.calories_drawn) DESC, h.designation
+		LIMIT ?`,
+		from.UTC().Format(time.DateOnly), to.UTC().Format(time.DateOnly), r.vault, r.vault, limit)
+	if err != nil {
+		return nil, fmt.Errorf("querying household consumption: %w", err)
+	}
+	return collect(rows, func(row rowScanner) (*models.HouseholdConsumption, error) {
+		var c models.HouseholdConsumption
+		if err := row.Scan(&c.HouseholdID, &c.Designation, &c.RationClass,
+			&c.ResidentDays, &c.Calories, &c.WaterL); err != nil {
+			return nil, fmt.Errorf("scanning household consumption: %w", err)
+		}
+		return &c, nil
+	})
+}
+
+// ByDepartment returns the rations drawn on the days in [from, to) for the
+// residents of each department, in department order. Each draw is divided
+// between departments by the share of the household's residents working
+// there.
+func (r *RationDrawRepository) ByDepartment(ctx context.Context, from, to time.Time) ([]*models.DepartmentConsumption, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT dd.department, SUM(dd.residents),
+			SUM(d.calories_drawn * dd.residents / d.residents),
+			SUM(d.water_drawn_l * dd.residents / d.residents)
+		FROM ration_draw_departments dd
+		JOIN ration_draws d ON d.id = dd.draw_id
+		WHERE d.day >= ? AND d.day < ? AND (? = 0 OR d.vault_id = ?)
+		GROUP BY dd.department
+		ORDER BY dd.department`,
+		from.UTC().Format(time.DateOnly), to.UTC().Format(time.DateOnly), r.vault, r.vault)
+	if err != nil {
+		return nil, fmt.Errorf("querying department consumption: %w", err)
+	}
+	return collect(rows, func(row rowScanner) (*models.DepartmentConsumption, error) {
+		var c models.DepartmentConsumption
+		if err := row.Scan(&c.Department, &c.ResidentDays, &c.Calories, &c.WaterL); err != nil {
+			return nil, fmt.Errorf("scanning department consumption: %w", err)
+		}
+		return &c, nil
+	})
+}
+
+// ByClass returns the rations and nutrients drawn on the days in [from, to)
+// by households of each ration class, as it was on the day, in class order.
+func (r *RationDrawRepository) ByClass(ctx context.Context, from, to time.Time) ([]*models.ClassConsumption, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT ration_class, SUM(residents), SUM(calories_drawn), SUM(water_drawn_l),
+			SUM(protein_required_g), SUM(protein_drawn_g), SUM(carbs_required_g), SUM(carbs_drawn_g),
+			SUM(fat_required_g), SUM(fat_drawn_g), SUM(vitamins_required_pct), SUM(vitamins_drawn_pct)
+		FROM ration_draws
+		WHERE day >= ? AND day < ? AND (? = 0 OR vault_id = ?)
+		GROUP BY ration_class
+		ORDER BY ration_class`,
+		from.UTC().Format(time.DateOnly), to.UTC().Format(time.DateOnly), r.vault, r.vault)
+	if err != nil {
+		return nil, fmt.Errorf("querying ration class consumption: %w", err)
+	}
+	return collect(rows, func(row rowScanner) (*models.ClassConsumption, error) {
+		var c models.ClassConsumption
+		required, drawn := &c.NutrientsRequired, &c.NutrientsDrawn
+		if err := row.Scan(&c.RationClass, &c.ResidentDays, &c.Calories, &c.WaterL,
+			&required.ProteinG, &drawn.ProteinG, &required.CarbsG, &drawn.CarbsG,
+			&required.FatG, &drawn.FatG, &required.VitaminsPct, &drawn.VitaminsPct); err != nil {
+			return nil, fmt.Errorf("scanning ration class consumption: %w", err)
+		}
+		return &c, nil
+	})
+}
+
+func (r *RationDrawRepository) getExecer(tx *sql.Tx) interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+} {
+	if tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+func (r *RationDrawRepository) getQuerier(tx *sql.Tx) interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+} {
+	if tx != nil {
+		return tx
+	}
+	return r.db
+}
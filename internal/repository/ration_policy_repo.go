@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// RationPolicyRepository handles ration policy data access.
+type RationPolicyRepository struct {
+	db *sql.DB
+}
+
+// NewRationPolicyRepository creates a new ration policy repository.
+func NewRationPolicyRepository(db *sql.DB) *RationPolicyRepository {
+	return &RationPolicyRepository{db: db}
+}
+
+// Create inserts a new policy. A second policy of the same class from the
+// same moment is reported as ErrDuplicate.
+func (r *RationPolicyRepository) Create(ctx context.Context, tx *sql.Tx, p *models.RationPolicy) error {
+	if err := p.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	p.CreatedAt = time.Now().UTC()
+	nutrients := nullableNutrients(p.Nutrients)
+
+	_, err := r.getExecer(tx).ExecContext(ctx, `
+		INSERT INTO ration_policies (
+			id, ration_class, calorie_target, water_target_l,
+			protein_target_g, carbs_target_g, fat_target_g, vitamins_target_pct,
+			effective_from, set_by, reason, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		p.ID,
+		string(p.RationClass),
+		p.CalorieTarget,
+		p.WaterTargetL,
+		nutrients[0], nutrients[1], nutrients[2], nutrients[3],
+		p.EffectiveFrom.UTC().Format(time.RFC3339),
+		p.SetBy,
+		nullableString(p.Reason),
+		p.CreatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("inserting ration policy: %w", constraintError(err))
+	}
+	return nil
+}
+
+// List retrieves the policies of a class, or of every class if class is
+// empty, latest effective first.
+func (r *RationPolicyRepository) List(ctx context.Context, class models.RationClass) ([]*models.RationPolicy, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, ration_class, calorie_target, water_target_l,
+			protein_target_g, carbs_target_g, fat_target_g, vitamins_target_pct,
+			effective_from, set_by, reason, created_at
+		FROM ration_policies
+		WHERE ? = '' OR ration_class = ?
+		ORDER BY effective_from DESC, created_at DESC`, string(class), string(class))
+	if err != nil {
+		return nil, fmt.Errorf("querying ration policies: %w", err)
+	}
+	return collect(rows, r.scan)
+}
+
+// Active retrieves the policy of each class in force at at.
+func (r *RationPolicyRepository) Active(ctx context.Context, at time.Time) (models.RationTargets, error) {
+	policies, err := r.List(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	return models.ActiveRationPolicies(policies, at), nil
+}
+
+func (r *RationPolicyRepository) getExecer(tx *sql.Tx) interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+} {
+	if tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+// scan scans a policy from a single row or a rows iterator.
+func (r *RationPolicyRepository) scan(row rowScanner) (*models.RationPolicy, error) {
+	var p models.RationPolicy
+	var setBy, reason sql.NullString
+	var nutrients nutrientColumns
+	var effectiveStr, createdStr string
+
+	dest := append([]any{&p.ID, &p.RationClass, &p.CalorieTarget, &p.WaterTargetL}, nutrients.dest()...)
+	err := row.Scan(append(dest, &effectiveStr, &setBy, &reason, &createdStr)...)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scanning ration policy: %w", err)
+	}
+
+	p.Nutrients = nutrients.nutrients()
+	p.EffectiveFrom = parseTime(time.RFC3339, effectiveStr)
+	p.SetBy = stringPtr(setBy)
+	p.Reason = reason.String
+	p.CreatedAt = parseTime(time.RFC3339, createdStr)
+	return &p, nil
+}
@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// RationReviewRepository handles ration class review data access.
+type RationReviewRepository struct {
+	db *sql.DB
+}
+
+// NewRationReviewRepository creates a new ration review repository.
+func NewRationReviewRepository(db *sql.DB) *RationReviewRepository {
+	return &RationReviewRepository{db: db}
+}
+
+// Create inserts a new ration class review into the database.
+func (r *RationReviewRepository) Create(ctx context.Context, tx *sql.Tx, review *models.RationClassReview) error {
+	if err := review.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	query := `
+		INSERT INTO ration_class_reviews (
+			id, household_id, current_class, recommended_class, reason,
+			trigger_event, status, decided_by, decided_at, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	var execer interface {
+		ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	}
+	if tx != nil {
+		execer = tx
+	} else {
+		execer = r.db
+	}
+
+	now := time.Now().UTC()
+	review.CreatedAt = now
+	review.UpdatedAt = now
+
+	_, err := execer.ExecContext(ctx, query,
+		review.ID,
+		review.HouseholdID,
+		string(review.CurrentClass),
+		string(review.RecommendedClass),
+		review.Reason,
+		string(review.Trigger),
+		string(review.Status),
+		review.DecidedBy,
+		nullableTimePtrRFC3339(review.DecidedAt),
+		review.CreatedAt.Format(time.RFC3339),
+		review.UpdatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("inserting ration review: %w", constraintError(err))
+	}
+
+	return nil
+}
+
+// GetByID retrieves a ration class review by ID.
+func (r *RationReviewRepository) GetByID(ctx context.Context, id string) (*models.RationClassReview, error) {
+	query := `
+		SELECT id, household_id, current_class, recommended_class, reason,
+			trigger_event, status, decided_by, decided_at, created_at, updated_at
+		FROM ration_class_reviews
+		WHERE id = ?`
+
+	return r.scanReview(r.db.QueryRowContext(ctx, query, id))
+}
+
+// GetPendingByHousehold retrieves the pending review for a household, if any.
+// Returns nil without error when the household has no pending review.
+func (r *RationReviewRepository) GetPendingByHousehold(ctx context.Context, householdID string) (*models.RationClassReview, error) {
+	query := `
+		SELECT id FROM ration_class_reviews
+		WHERE household_id = ? AND status = 'PENDING'
+		ORDER BY created_at DESC
+		LIMIT 1`
+
+	var id string
+	err := r.db.QueryRowContext(ctx, query, householdID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("querying pending ration review: %w", err)
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+// UpdateStatus records a decision on a review.
+func (r *RationReviewRepository) UpdateStatus(ctx context.Context, tx *sql.Tx, review *models.RationClassReview) error {
+	if err := review.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	query := `
+		UPDATE ration_class_reviews SET
+			status = ?, decided_by = ?, decided_at = ?, updated_at = ?
+		WHERE id = ?`
+
+	var execer interface {
+		ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	}
+	if tx != nil {
+		execer = tx
+	} else {
+		execer = r.db
+	}
+
+	review.UpdatedAt = time.Now().UTC()
+
+	result, err := execer.ExecContext(ctx, query,
+		string(review.Status),
+		review.DecidedBy,
+		nullableTimePtrRFC3339(review.DecidedAt),
+		review.UpdatedAt.Format(time.RFC3339),
+		review.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("updating ration review: %w", constraintError(err))
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("ration review %w: %s", ErrNotFound, review.ID)
+	}
+
+	return nil
+}
+
+// ListPending retrieves all pending reviews, oldest first.
+func (r *RationReviewRepository) ListPending(ctx context.Context) ([]*models.RationClassReview, error) {
+	query := `
+		SELECT id, household_id, current_class, recommended_class, reason,
+			trigger_event, status, decided_by, decided_at, created_at, updated_at
+		FROM ration_class_reviews
+		WHERE status = 'PENDING'
+		ORDER BY created_at`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("querying pending ration reviews: %w", err)
+	}
+	return collect(rows, r.scanReview)
+}
+
+// scanReview scans a review from a single row or a rows iterator.
+func (r *RationReviewRepository) scanReview(row rowScanner) (*models.RationClassReview, error) {
+	var review models.RationClassReview
+	var createdStr, updatedStr string
+	var decidedBy, decidedAt sql.NullString
+
+	err := row.Scan(
+		&review.ID,
+		&review.HouseholdID,
+		&review.CurrentClass,
+		&review.RecommendedClass,
+		&review.Reason,
+		&review.Trigger,
+		&review.Status,
+		&decidedBy,
+		&decidedAt,
+		&createdStr,
+		&updatedStr,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("ration review %w", ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scanning ration review: %w", err)
+	}
+
+	review.DecidedBy = stringPtr(decidedBy)
+	review.DecidedAt = timePtr(time.RFC3339, decidedAt)
+	review.CreatedAt = parseTime(time.RFC3339, createdStr)
+	review.UpdatedAt = parseTime(time.RFC3339, updatedStr)
+	return &review, nil
+}
@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// RelationshipRepository handles resident relationship data access.
+type RelationshipRepository struct {
+	db *sql.DB
+}
+
+// NewRelationshipRepository creates a new relationship repository.
+func NewRelationshipRepository(db *sql.DB) *RelationshipRepository {
+	return &RelationshipRepository{db: db}
+}
+
+// Create inserts a new relationship. A second current next of kin for the
+// same resident is reported as ErrDuplicate.
+func (r *RelationshipRepository) Create(ctx context.Context, tx *sql.Tx, rel *models.Relationship) error {
+	if err := rel.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	query := `
+		INSERT INTO resident_relationships (
+			id, relationship_type, resident_id, related_id, start_date, end_date,
+			end_reason, notes, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	now := time.Now().UTC()
+	rel.CreatedAt = now
+	rel.UpdatedAt = now
+
+	_, err := r.getExecer(tx).ExecContext(ctx, query,
+		rel.ID,
+		string(rel.Type),
+		rel.ResidentID,
+		rel.RelatedID,
+		rel.StartDate.Format(time.DateOnly),
+		nullableTime(rel.EndDate),
+		nullableString(rel.EndReason),
+		nullableString(rel.Notes),
+		rel.CreatedAt.Format(time.RFC3339),
+		rel.UpdatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("inserting relationship: %w", constraintError(err))
+	}
+	return nil
+}
+
+// GetByID retrieves a relationship by ID.
+func (r *RelationshipRepository) GetByID(ctx context.Context, id string) (*models.Relationship, error) {
+	rel, err := r.scan(r.db.QueryRowContext(ctx, relationshipSelect+" WHERE id = ?", id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("relationship %w: %s", ErrNotFound, id)
+	}
+	return rel, err
+}
+
+// ListByResident retrieves every relationship a resident is a party to,
+// current ones first, then most recent first.
+func (r *RelationshipRepository) ListByResident(ctx context.Context, residentID string) ([]*models.Relationship, error) {
+	query := relationshipSelect + `
+		WHERE resident_id = ? OR related_id = ?
+		ORDER BY end_date IS NOT NULL, start_date DESC, id`
+
+	rows, err := r.db.QueryContext(ctx, query, residentID, residentID)
+	if err != nil {
+		return nil, fmt.Errorf("querying relationships: %w", err)
+	}
+	return collect(rows, r.scan)
+}
+
+// End ends a current relationship on the given date.
+func (r *RelationshipRepository) End(ctx context.Context, tx *sql.Tx, id string, date time.Time, reason string) error {
+	result, err := r.getExecer(tx).ExecContext(ctx, `
+		UPDATE resident_relationships SET end_date = ?, end_reason = ?, updated_at = ?
+		WHERE id = ? AND end_date IS NULL`,
+		date.Format(time.DateOnly),
+		nullableString(reason),
+		time.Now().UTC().Format(time.RFC3339),
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("ending relationship: %w", constraintError(err))
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("current relationship %w: %s", ErrNotFound, id)
+	}
+	return nil
+}
+
+// EndAllForResident ends every current relationship a resident is a party
+// to and returns how many it ended.
+func (r *RelationshipRepository) EndAllForResident(ctx context.Context, tx *sql.Tx, residentID string, date time.Time, reason string) (int, error) {
+	result, err := r.getExecer(tx).ExecContext(ctx, `
+		UPDATE resident_relationships SET end_date = ?, end_reason = ?, updated_at = ?
+		WHERE (resident_id = ? OR related_id = ?) AND end_date IS NULL`,
+		date.Format(time.DateOnly),
+		nullableString(reason),
+		time.Now().UTC().Format(time.RFC3339),
+		residentID, residentID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("ending relationships: %w", constraintError(err))
+	}
+
+	rows, _ := result.RowsAffected()
+	return int(rows), nil
+}
+
+// ResumeEnded makes current again every relationship of a resident that
+// ended on the given date for the given reason, undoing EndAllForResident,
+// and returns how many it resumed.
+func (r *RelationshipRepository) ResumeEnded(ctx context.Context, tx *sql.Tx, residentID string, date time.Time, reason string) (int, error) {
+	result, err := r.getExecer(tx).ExecContext(ctx, `
+		UPDATE resident_relationships SET end_date = NULL, end_reason = NULL, updated_at = ?
+		WHERE (resident_id = ? OR related_id = ?) AND end_date = ? AND end_reason = ?`,
+		time.Now().UTC().Format(time.RFC3339),
+		residentID, residentID,
+		date.Format(time.DateOnly),
+		reason,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("resuming relationships: %w", constraintError(err))
+	}
+
+	rows, _ := result.RowsAffected()
+	return int(rows), nil
+}
+
+const relationshipSelect = `
+	SELECT id, relationship_type, resident_id, related_id, start_date, end_date,
+		end_reason, notes, created_at, updated_at
+	FROM resident_relationships`
+
+func (r *RelationshipRepository) getExecer(tx *sql.Tx) interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+} {
+	if tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+// scan scans a relationship from a single row or a rows iterator.
+func (r *RelationshipRepository) scan(row rowScanner) (*models.Relationship, error) {
+	var rel models.Relationship
+	var endDate, endReason, notes sql.NullString
+	var startStr, createdStr, updatedStr string
+
+	err := row.Scan(
+		&rel.ID, &rel.Type, &rel.ResidentID, &rel.RelatedID, &startStr, &endDate,
+		&endReason, &notes, &createdStr, &updatedStr,
+	)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scanning relationship: %w", err)
+	}
+
+	rel.StartDate = parseTime(time.DateOnly, startStr)
+	rel.EndDate = timePtr(time.DateOnly, endDate)
+	rel.EndReason = endReason.String
+	rel.Notes = notes.String
+	rel.CreatedAt = parseTime(time.RFC3339, createdStr)
+	rel.UpdatedAt = parseTime(time.RFC3339, updatedStr)
+	return &rel, nil
+}
@@ -9,22 +9,34 @@ import (
 	"time"
 
 	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/util"
 )
 
 // ResidentRepository handles resident data access.
 type ResidentRepository struct {
-	db *sql.DB
+	db    *sql.DB
+	stmts *stmtCache
+	vault int // Vault lists are limited to, 0 for every vault
 }
 
 // NewResidentRepository creates a new resident repository.
 func NewResidentRepository(db *sql.DB) *ResidentRepository {
-	return &ResidentRepository{db: db}
+	return &ResidentRepository{db: db, stmts: newStmtCache(db)}
+}
+
+// ForVault returns a copy of the repository whose lists and counts are
+// limited to residents of the vault, and which creates residents there.
+// Lookups by ID or registry number still find residents of any vault.
+func (r *ResidentRepository) ForVault(vault int) *ResidentRepository {
+	scoped := *r
+	scoped.vault = vault
+	return &scoped
 }
 
 // Create inserts a new resident into the database.
 func (r *ResidentRepository) Create(ctx context.Context, tx *sql.Tx, resident *models.Resident) error {
 	if err := resident.Validate(); err != nil {
-		return fmt.Errorf("validation failed: %w", err)
+		return fmt.Errorf("%w: %w", ErrValidation, err)
 	}
 
 	query := `
@@ -32,9 +44,9 @@ func (r *ResidentRepository) Create(ctx context.Context, tx *sql.Tx, resident *m
 			id, registry_number, surname, given_names, date_of_birth, date_of_death,
 			sex, blood_type, entry_type, entry_date, status,
 			biological_parent_1_id, biological_parent_2_id,
-			household_id, quarters_id, primary_vocation_id, clearance_level,
+			household_id, quarters_id, primary_vocation_id, clearance_level, vault_id,
 			notes, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
 	var execer interface {
 		ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
@@ -45,11 +57,19 @@ func (r *ResidentRepository) Create(ctx context.Context, tx *sql.Tx, resident *m
 		execer = r.db
 	}
 
+	notes, err := sealedString("residents.notes", resident.Notes)
+	if err != nil {
+		return err
+	}
+
 	now := time.Now().UTC()
 	resident.CreatedAt = now
 	resident.UpdatedAt = now
+	if resident.VaultID == 0 {
+		resident.VaultID = r.vault
+	}
 
-	_, err := execer.ExecContext(ctx, query,
+	_, err = execer.ExecContext(ctx, query,
 		resident.ID,
 		resident.RegistryNumber,
 		resident.Surname,
@@ -67,12 +87,13 @@ func (r *ResidentRepository) Create(ctx context.Context, tx *sql.Tx, resident *m
 		resident.QuartersID,
 		resident.PrimaryVocationID,
 		resident.ClearanceLevel,
-		nullableString(resident.Notes),
+		resident.VaultID,
+		notes,
 		resident.CreatedAt.Format(time.RFC3339),
 		resident.UpdatedAt.Format(time.RFC3339),
 	)
 	if err != nil {
-		return fmt.Errorf("inserting resident: %w", err)
+		return fmt.Errorf("inserting resident: %w", constraintError(err))
 	}
 
 	return nil
@@ -84,32 +105,36 @@ func (r *ResidentRepository) GetByID(ctx context.Context, id string) (*models.Re
 		SELECT id, registry_number, surname, given_names, date_of_birth, date_of_death,
 			sex, blood_type, entry_type, entry_date, status,
 			biological_parent_1_id, biological_parent_2_id,
-			household_id, quarters_id, primary_vocation_id, clearance_level,
+			household_id, quarters_id, primary_vocation_id, clearance_level, vault_id,
 			notes, created_at, updated_at
 		FROM residents
 		WHERE id = ?`
 
-	return r.scanResident(r.db.QueryRowContext(ctx, query, id))
+	return r.scanResident(r.stmts.queryRow(ctx, query, id))
 }
 
-// GetByRegistryNumber retrieves a resident by registry number.
-func (r *ResidentRepository) GetByRegistryNumber(ctx context.Context, regNum string) (*models.Resident, error) {
+// GetByRegistryNumber retrieves a resident by registry number, within tx if
+// given.
+func (r *ResidentRepository) GetByRegistryNumber(ctx context.Context, tx *sql.Tx, regNum string) (*models.Resident, error) {
 	query := `
 		SELECT id, registry_number, surname, given_names, date_of_birth, date_of_death,
 			sex, blood_type, entry_type, entry_date, status,
 			biological_parent_1_id, biological_parent_2_id,
-			household_id, quarters_id, primary_vocation_id, clearance_level,
+			household_id, quarters_id, primary_vocation_id, clearance_level, vault_id,
 			notes, created_at, updated_at
 		FROM residents
 		WHERE registry_number = ?`
 
-	return r.scanResident(r.db.QueryRowContext(ctx, query, regNum))
+	if tx != nil {
+		return r.scanResident(tx.QueryRowContext(ctx, query, regNum))
+	}
+	return r.scanResident(r.stmts.queryRow(ctx, query, regNum))
 }
 
 // Update modifies an existing resident.
 func (r *ResidentRepository) Update(ctx context.Context, tx *sql.Tx, resident *models.Resident) error {
 	if err := resident.Validate(); err != nil {
-		return fmt.Errorf("validation failed: %w", err)
+		return fmt.Errorf("%w: %w", ErrValidation, err)
 	}
 
 	query := `
@@ -130,6 +155,11 @@ func (r *ResidentRepository) Update(ctx context.Context, tx *sql.Tx, resident *m
 		execer = r.db
 	}
 
+	notes, err := sealedString("residents.notes", resident.Notes)
+	if err != nil {
+		return err
+	}
+
 	resident.UpdatedAt = time.Now().UTC()
 
 	result, err := execer.ExecContext(ctx, query,
@@ -148,26 +178,82 @@ func (r *ResidentRepository) Update(ctx context.Context, tx *sql.Tx, resident *m
 		resident.QuartersID,
 		resident.PrimaryVocationID,
 		resident.ClearanceLevel,
-		nullableString(resident.Notes),
+		notes,
 		resident.UpdatedAt.Format(time.RFC3339),
 		resident.ID,
 	)
 	if err != nil {
-		return fmt.Errorf("updating resident: %w", err)
+		return fmt.Errorf("updating resident: %w", constraintError(err))
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("resident %w: %s", ErrNotFound, resident.ID)
+	}
+
+	return nil
+}
+
+// Delete removes a resident record. Residents recorded as a biological
+// parent are restricted and return ErrResidentIsParent; record a status
+// change instead. Head-of-household references are cleared.
+func (r *ResidentRepository) Delete(ctx context.Context, tx *sql.Tx, id string) error {
+	query := `DELETE FROM residents WHERE id = ?`
+
+	var execer interface {
+		ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	}
+	if tx != nil {
+		execer = tx
+	} else {
+		execer = r.db
+	}
+
+	result, err := execer.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("deleting resident: %w", constraintError(err))
 	}
 
 	rows, _ := result.RowsAffected()
 	if rows == 0 {
-		return fmt.Errorf("resident not found: %s", resident.ID)
+		return fmt.Errorf("resident %w: %s", ErrNotFound, id)
 	}
 
 	return nil
 }
 
-// List retrieves residents with filtering and pagination.
+// residentSortColumns maps models.ResidentSortColumns to SQL.
+var residentSortColumns = sortColumns{
+	"registry_number": "registry_number",
+	"surname":         "surname",
+	"given_names":     "given_names",
+	"age":             "-julianday(date_of_birth)",
+	"sex":             "sex",
+	"blood_type":      "COALESCE(blood_type, '')",
+	"status":          "status",
+	"entry_type":      "entry_type",
+	"clearance_level": "clearance_level",
+}
+
+// residentOrder is the keyset order of resident lists.
+var residentOrder = keysetOrder{
+	from:        "residents",
+	id:          "id",
+	defaultKeys: []string{"surname", "given_names", "registry_number"},
+	tiebreak:    "registry_number",
+}
+
+// List retrieves residents with filtering and pagination, by name unless
+// page.Sort picks one of models.ResidentSortColumns. It supports keyset
+// pagination through page.After.
 func (r *ResidentRepository) List(ctx context.Context, filter models.ResidentFilter, page models.Pagination) (*models.ResidentList, error) {
-	var conditions []string
-	var args []any
+	after, orderBy, err := residentSortColumns.keyset(page.Sort, residentOrder, page.After)
+	if err != nil {
+		return nil, err
+	}
+
+	conditions := []string{vaultCondition}
+	args := []any{r.vault, r.vault}
 
 	if filter.Status != nil {
 		conditions = append(conditions, "status = ?")
@@ -190,53 +276,100 @@ func (r *ResidentRepository) List(ctx context.Context, filter models.ResidentFil
 		args = append(args, string(*filter.EntryType))
 	}
 	if filter.SearchTerm != "" {
-		conditions = append(conditions, "(surname LIKE ? OR given_names LIKE ?)")
+		conditions = append(conditions, "(surname LIKE ? OR given_names LIKE ? OR registry_number LIKE ?)")
 		searchPattern := "%" + filter.SearchTerm + "%"
-		args = append(args, searchPattern, searchPattern)
+		args = append(args, searchPattern, searchPattern, searchPattern)
+	}
+	for _, tag := range filter.Tags {
+		conditions = append(conditions, "id IN (SELECT resident_id FROM resident_tags WHERE tag = ?)")
+		args = append(args, tag)
+	}
+	if filter.VocationCode != "" {
+		conditions = append(conditions, "primary_vocation_id IN (SELECT id FROM vocations WHERE code = ?)")
+		args = append(args, filter.VocationCode)
 	}
 
-	whereClause := ""
-	if len(conditions) > 0 {
-		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	// Ages as bounds on the date of birth: a resident is at least n on the
+	// nth anniversary of their birth, and at most n until the day before
+	// their (n+1)th
+	asOf := filter.AsOf
+	if asOf.IsZero() {
+		asOf = time.Now().UTC()
+	}
+	if filter.MinAge != nil {
+		conditions = append(conditions, "date_of_birth <= ?")
+		args = append(args, asOf.AddDate(-*filter.MinAge, 0, 0).Format(time.DateOnly))
+	}
+	if filter.MaxAge != nil {
+		conditions = append(conditions, "date_of_birth > ?")
+		args = append(args, asOf.AddDate(-*filter.MaxAge-1, 0, 0).Format(time.DateOnly))
+	}
+	if filter.MinClearance != nil {
+		conditions = append(conditions, "clearance_level >= ?")
+		args = append(args, *filter.MinClearance)
+	}
+	if filter.MaxClearance != nil {
+		conditions = append(conditions, "clearance_level <= ?")
+		args = append(args, *filter.MaxClearance)
 	}
+	if filter.HouseholdType != nil {
+		conditions = append(conditions, "household_id IN (SELECT id FROM households WHERE household_type = ?)")
+		args = append(args, string(*filter.HouseholdType))
+	}
+	if filter.Sector != "" {
+		// Housed in the resident's own quarters, or else their household's
+		conditions = append(conditions, `COALESCE(quarters_id,
+			(SELECT h.quarters_id FROM households h WHERE h.id = residents.household_id))
+			IN (SELECT id FROM quarters WHERE sector = ?)`)
+		args = append(args, filter.Sector)
+	}
+
+	whereClause := "WHERE " + strings.Join(conditions, " AND ")
 
 	// Count total
 	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM residents %s", whereClause)
 	var total int
-	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+	if err := r.stmts.queryRow(ctx, countQuery, args...).Scan(&total); err != nil {
 		return nil, fmt.Errorf("counting residents: %w", err)
 	}
 
-	// Get page
+	// Get page, continuing after the cursor row or skipping earlier pages,
+	// with one extra row to tell whether another page follows
+	limit := "LIMIT ? OFFSET ?"
+	if after != "" {
+		conditions = append(conditions, after)
+		args = append(args, page.After)
+		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+		limit = "LIMIT ?"
+	}
 	query := fmt.Sprintf(`
 		SELECT id, registry_number, surname, given_names, date_of_birth, date_of_death,
 			sex, blood_type, entry_type, entry_date, status,
 			biological_parent_1_id, biological_parent_2_id,
-			household_id, quarters_id, primary_vocation_id, clearance_level,
+			household_id, quarters_id, primary_vocation_id, clearance_level, vault_id,
 			notes, created_at, updated_at
 		FROM residents
 		%s
-		ORDER BY surname, given_names
-		LIMIT ? OFFSET ?`, whereClause)
+		%s
+		%s`, whereClause, orderBy, limit)
 
-	args = append(args, page.Limit(), page.Offset())
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	args = append(args, page.Limit()+1)
+	if after == "" {
+		args = append(args, page.Offset())
+	}
+	rows, err := r.stmts.query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("querying residents: %w", err)
 	}
-	defer rows.Close()
-
-	var residents []*models.Resident
-	for rows.Next() {
-		resident, err := r.scanResidentRow(rows)
-		if err != nil {
-			return nil, err
-		}
-		residents = append(residents, resident)
+	residents, err := collect(rows, r.scanResident)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("iterating residents: %w", err)
+	var next string
+	if len(residents) > page.Limit() {
+		residents = residents[:page.Limit()]
+		next = residents[len(residents)-1].ID
 	}
 
 	return &models.ResidentList{
@@ -245,39 +378,81 @@ func (r *ResidentRepository) List(ctx context.Context, filter models.ResidentFil
 		Page:       page.Page,
 		PageSize:   page.Limit(),
 		TotalPages: page.TotalPages(total),
+		NextCursor: next,
 	}, nil
 }
 
-// GetNextRegistryNumber generates the next available registry number.
-func (r *ResidentRepository) GetNextRegistryNumber(ctx context.Context, vaultNumber int) (string, error) {
-	query := `
-		SELECT registry_number FROM residents
-		ORDER BY registry_number DESC
-		LIMIT 1`
-
-	var lastNum string
-	err := r.db.QueryRowContext(ctx, query).Scan(&lastNum)
-	if err == sql.ErrNoRows {
-		return fmt.Sprintf("V%03d-00001", vaultNumber), nil
+// ReserveRegistryNumbers reserves the next n registry numbers of the
+// generator's vault, issued in year, moving the vault's sequence forward
+// in tx. The sequence is taken on first use from the highest among the
+// registry numbers in the generator's format, and never moves back, so a
+// number is not issued again once its resident is deleted. Call it before
+// any other statement of tx: its first statement is a write, which holds
+// the database's write lock until tx ends, so concurrent creations queue
+// rather than reading the same maximum.
+func (r *ResidentRepository) ReserveRegistryNumbers(ctx context.Context, tx *sql.Tx, gen *util.RegistryNumberGenerator, year, n int) ([]string, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("%w: at least one registry number must be reserved", ErrValidation)
+	}
+	vault := gen.VaultNumber()
+
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO registry_sequences (vault_id, last_seq) VALUES (?, 0)
+		ON CONFLICT (vault_id) DO NOTHING`, vault)
+	if err != nil {
+		return nil, fmt.Errorf("claiming registry sequence: %w", err)
+	}
+	if created, err := result.RowsAffected(); err != nil {
+		return nil, fmt.Errorf("claiming registry sequence: %w", err)
+	} else if created > 0 {
+		last, err := r.highestSequence(ctx, tx, gen)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE registry_sequences SET last_seq = ? WHERE vault_id = ?`, last, vault); err != nil {
+			return nil, fmt.Errorf("starting registry sequence: %w", err)
+		}
 	}
+
+	var last int
+	err = tx.QueryRowContext(ctx, `
+		UPDATE registry_sequences SET last_seq = last_seq + ?, updated_at = datetime('now')
+		WHERE vault_id = ?
+		RETURNING last_seq`, n, vault).Scan(&last)
 	if err != nil {
-		return "", fmt.Errorf("getting last registry number: %w", err)
+		return nil, fmt.Errorf("reserving registry numbers: %w", err)
 	}
 
-	// Parse the number portion and increment
-	var num int
-	_, err = fmt.Sscanf(lastNum, fmt.Sprintf("V%03d-%%05d", vaultNumber), &num)
+	numbers := make([]string, 0, n)
+	for seq := last - n + 1; seq <= last; seq++ {
+		numbers = append(numbers, gen.Number(seq, year))
+	}
+	return numbers, nil
+}
+
+// highestSequence returns the highest sequence number among the registry
+// numbers in the generator's format of its vault, 0 for none.
+func (r *ResidentRepository) highestSequence(ctx context.Context, tx *sql.Tx, gen *util.RegistryNumberGenerator) (int, error) {
+	rows, err := tx.QueryContext(ctx, `SELECT registry_number FROM residents`)
 	if err != nil {
-		// Fallback to sequential scan
-		countQuery := `SELECT COUNT(*) FROM residents`
-		var count int
-		if err := r.db.QueryRowContext(ctx, countQuery).Scan(&count); err != nil {
-			return "", fmt.Errorf("counting residents: %w", err)
-		}
-		return fmt.Sprintf("V%03d-%05d", vaultNumber, count+1), nil
+		return 0, fmt.Errorf("reading registry numbers: %w", err)
 	}
+	defer rows.Close()
 
-	return fmt.Sprintf("V%03d-%05d", vaultNumber, num+1), nil
+	highest := 0
+	for rows.Next() {
+		var regNum string
+		if err := rows.Scan(&regNum); err != nil {
+			return 0, fmt.Errorf("scanning registry number: %w", err)
+		}
+		if seq, ok := gen.Sequence(regNum); ok && seq > highest {
+			highest = seq
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("reading registry numbers: %w", err)
+	}
+	return highest, nil
 }
 
 // GetByHousehold retrieves all residents in a household.
@@ -286,28 +461,68 @@ func (r *ResidentRepository) GetByHousehold(ctx context.Context, householdID str
 		SELECT id, registry_number, surname, given_names, date_of_birth, date_of_death,
 			sex, blood_type, entry_type, entry_date, status,
 			biological_parent_1_id, biological_parent_2_id,
-			household_id, quarters_id, primary_vocation_id, clearance_level,
+			household_id, quarters_id, primary_vocation_id, clearance_level, vault_id,
 			notes, created_at, updated_at
 		FROM residents
 		WHERE household_id = ?
 		ORDER BY date_of_birth`
 
-	rows, err := r.db.QueryContext(ctx, query, householdID)
+	rows, err := r.stmts.query(ctx, query, householdID)
 	if err != nil {
 		return nil, fmt.Errorf("querying household members: %w", err)
 	}
+	return collect(rows, r.scanResident)
+}
+
+// GetMedicalNeeds returns the IDs of active household members with an
+// unresolved chronic condition or a severe/critical condition.
+func (r *ResidentRepository) GetMedicalNeeds(ctx context.Context, householdID string) (map[string]bool, error) {
+	query := `
+		SELECT DISTINCT r.id
+		FROM residents r
+		JOIN medical_conditions mc ON mc.resident_id = r.id
+		WHERE r.household_id = ? AND r.status = 'ACTIVE'
+			AND mc.resolution_date IS NULL
+			AND (mc.is_chronic = 1 OR mc.severity IN ('SEVERE', 'CRITICAL'))`
+
+	rows, err := r.db.QueryContext(ctx, query, householdID)
+	if err != nil {
+		return nil, fmt.Errorf("querying medical needs: %w", err)
+	}
 	defer rows.Close()
 
-	var residents []*models.Resident
+	needs := make(map[string]bool)
 	for rows.Next() {
-		resident, err := r.scanResidentRow(rows)
-		if err != nil {
-			return nil, err
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scanning medical need: %w", err)
 		}
-		residents = append(residents, resident)
+		needs[id] = true
 	}
 
-	return residents, rows.Err()
+	return needs, rows.Err()
+}
+
+// FindByIdentity retrieves the residents with a name and date of birth,
+// compared without regard to case, such as a resident already registered
+// under the name of one arriving from another vault.
+func (r *ResidentRepository) FindByIdentity(ctx context.Context, surname, givenNames string, dateOfBirth time.Time) ([]*models.Resident, error) {
+	query := `
+		SELECT id, registry_number, surname, given_names, date_of_birth, date_of_death,
+			sex, blood_type, entry_type, entry_date, status,
+			biological_parent_1_id, biological_parent_2_id,
+			household_id, quarters_id, primary_vocation_id, clearance_level, vault_id,
+			notes, created_at, updated_at
+		FROM residents
+		WHERE surname = ? COLLATE NOCASE AND given_names = ? COLLATE NOCASE
+			AND date(date_of_birth) = ? AND ` + vaultCondition + `
+		ORDER BY registry_number`
+
+	rows, err := r.db.QueryContext(ctx, query, surname, givenNames, dateOfBirth.Format(time.DateOnly), r.vault, r.vault)
+	if err != nil {
+		return nil, fmt.Errorf("querying residents by identity: %w", err)
+	}
+	return collect(rows, r.scanResident)
 }
 
 // GetChildren retrieves biological children of a resident.
@@ -316,7 +531,7 @@ func (r *ResidentRepository) GetChildren(ctx context.Context, parentID string) (
 		SELECT id, registry_number, surname, given_names, date_of_birth, date_of_death,
 			sex, blood_type, entry_type, entry_date, status,
 			biological_parent_1_id, biological_parent_2_id,
-			household_id, quarters_id, primary_vocation_id, clearance_level,
+			household_id, quarters_id, primary_vocation_id, clearance_level, vault_id,
 			notes, created_at, updated_at
 		FROM residents
 		WHERE biological_parent_1_id = ? OR biological_parent_2_id = ?
@@ -326,18 +541,57 @@ func (r *ResidentRepository) GetChildren(ctx context.Context, parentID string) (
 	if err != nil {
 		return nil, fmt.Errorf("querying children: %w", err)
 	}
-	defer rows.Close()
+	return collect(rows, r.scanResident)
+}
 
-	var children []*models.Resident
-	for rows.Next() {
-		child, err := r.scanResidentRow(rows)
-		if err != nil {
-			return nil, err
-		}
-		children = append(children, child)
-	}
+// ListEnteredBetween retrieves residents who entered the vault, by birth or
+// admission, on a date in [from, to).
+func (r *ResidentRepository) ListEnteredBetween(ctx context.Context, from, to time.Time) ([]*models.Resident, error) {
+	return r.listByDate(ctx, "entry_date", from, to)
+}
+
+// ListDiedBetween retrieves residents who died on a date in [from, to).
+func (r *ResidentRepository) ListDiedBetween(ctx context.Context, from, to time.Time) ([]*models.Resident, error) {
+	return r.listByDate(ctx, "date_of_death", from, to)
+}
 
-	return children, rows.Err()
+// CountPresentAt returns how many residents were in the vault at the end of
+// a day: entered on or before it and not dead by then. Exiled residents
+// are left out, as when they left is not recorded.
+func (r *ResidentRepository) CountPresentAt(ctx context.Context, day time.Time) (int, error) {
+	query := `
+		SELECT COUNT(*) FROM residents
+		WHERE date(entry_date) <= ?
+		  AND (date_of_death IS NULL OR date(date_of_death) > ?)
+		  AND status != 'EXILED'
+		  AND ` + vaultCondition
+
+	stamp := day.Format(time.DateOnly)
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, stamp, stamp, r.vault, r.vault).Scan(&count); err != nil {
+		return 0, fmt.Errorf("counting residents present: %w", err)
+	}
+	return count, nil
+}
+
+// listByDate retrieves residents whose date column falls in [from, to).
+// column must be a trusted column name.
+func (r *ResidentRepository) listByDate(ctx context.Context, column string, from, to time.Time) ([]*models.Resident, error) {
+	query := `
+		SELECT id, registry_number, surname, given_names, date_of_birth, date_of_death,
+			sex, blood_type, entry_type, entry_date, status,
+			biological_parent_1_id, biological_parent_2_id,
+			household_id, quarters_id, primary_vocation_id, clearance_level, vault_id,
+			notes, created_at, updated_at
+		FROM residents
+		WHERE date(` + column + `) >= ? AND date(` + column + `) < ? AND ` + vaultCondition + `
+		ORDER BY ` + column + `, registry_number`
+
+	rows, err := r.db.QueryContext(ctx, query, from.Format(time.DateOnly), to.Format(time.DateOnly), r.vault, r.vault)
+	if err != nil {
+		return nil, fmt.Errorf("querying residents by %s: %w", column, err)
+	}
+	return collect(rows, r.scanResident)
 }
 
 // GetParents retrieves biological parents of a resident.
@@ -367,8 +621,8 @@ func (r *ResidentRepository) GetParents(ctx context.Context, residentID string)
 
 // CountByStatus returns counts of residents by status.
 func (r *ResidentRepository) CountByStatus(ctx context.Context) (map[models.ResidentStatus]int, error) {
-	query := `SELECT status, COUNT(*) FROM residents GROUP BY status`
-	rows, err := r.db.QueryContext(ctx, query)
+	query := `SELECT status, COUNT(*) FROM residents WHERE ` + vaultCondition + ` GROUP BY status`
+	rows, err := r.db.QueryContext(ctx, query, r.vault, r.vault)
 	if err != nil {
 		return nil, fmt.Errorf("counting by status: %w", err)
 	}
@@ -387,8 +641,8 @@ func (r *ResidentRepository) CountByStatus(ctx context.Context) (map[models.Resi
 	return counts, rows.Err()
 }
 
-// scanResident scans a single row into a Resident struct.
-func (r *ResidentRepository) scanResident(row *sql.Row) (*models.Resident, error) {
+// scanResident scans a resident from a single row or a rows iterator.
+func (r *ResidentRepository) scanResident(row rowScanner) (*models.Resident, error) {
 	var resident models.Resident
 	var dobStr, entryDateStr, createdStr, updatedStr string
 	var dodStr, bloodType, notes sql.NullString
@@ -412,133 +666,33 @@ func (r *ResidentRepository) scanResident(row *sql.Row) (*models.Resident, error
 		&quartersID,
 		&vocationID,
 		&resident.ClearanceLevel,
+		&resident.VaultID,
 		&notes,
 		&createdStr,
 		&updatedStr,
 	)
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("resident not found")
+		return nil, fmt.Errorf("resident %w", ErrNotFound)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("scanning resident: %w", err)
 	}
 
-	// Parse dates
-	resident.DateOfBirth, _ = time.Parse(time.DateOnly, dobStr)
-	if dodStr.Valid {
-		dod, _ := time.Parse(time.DateOnly, dodStr.String)
-		resident.DateOfDeath = &dod
-	}
-	resident.EntryDate, _ = time.Parse(time.RFC3339, entryDateStr)
-	resident.CreatedAt, _ = time.Parse(time.RFC3339, createdStr)
-	resident.UpdatedAt, _ = time.Parse(time.RFC3339, updatedStr)
+	resident.DateOfBirth = parseTime(time.DateOnly, dobStr)
+	resident.DateOfDeath = timePtr(time.DateOnly, dodStr)
+	resident.EntryDate = parseTime(time.RFC3339, entryDateStr)
+	resident.CreatedAt = parseTime(time.RFC3339, createdStr)
+	resident.UpdatedAt = parseTime(time.RFC3339, updatedStr)
 
-	// Set nullable fields
-	if bloodType.Valid {
-		resident.BloodType = models.BloodType(bloodType.String)
-	}
-	if notes.Valid {
-		resident.Notes = notes.String
-	}
-	if parent1ID.Valid {
-		resident.BiologicalParent1ID = &parent1ID.String
-	}
-	if parent2ID.Valid {
-		resident.BiologicalParent2ID = &parent2ID.String
-	}
-	if householdID.Valid {
-		resident.HouseholdID = &householdID.String
-	}
-	if quartersID.Valid {
-		resident.QuartersID = &quartersID.String
-	}
-	if vocationID.Valid {
-		resident.PrimaryVocationID = &vocationID.String
-	}
-
-	return &resident, nil
-}
-
-// scanResidentRow scans a row from a rows iterator.
-func (r *ResidentRepository) scanResidentRow(rows *sql.Rows) (*models.Resident, error) {
-	var resident models.Resident
-	var dobStr, entryDateStr, createdStr, updatedStr string
-	var dodStr, bloodType, notes sql.NullString
-	var parent1ID, parent2ID, householdID, quartersID, vocationID sql.NullString
-
-	err := rows.Scan(
-		&resident.ID,
-		&resident.RegistryNumber,
-		&resident.Surname,
-		&resident.GivenNames,
-		&dobStr,
-		&dodStr,
-		&resident.Sex,
-		&bloodType,
-		&resident.EntryType,
-		&entryDateStr,
-		&resident.Status,
-		&parent1ID,
-		&parent2ID,
-		&householdID,
-		&quartersID,
-		&vocationID,
-		&resident.ClearanceLevel,
-		&notes,
-		&createdStr,
-		&updatedStr,
-	)
-	if err != nil {
-		return nil, fmt.Errorf("scanning resident row: %w", err)
-	}
-
-	// Parse dates
-	resident.DateOfBirth, _ = time.Parse(time.DateOnly, dobStr)
-	if dodStr.Valid {
-		dod, _ := time.Parse(time.DateOnly, dodStr.String)
-		resident.DateOfDeath = &dod
-	}
-	resident.EntryDate, _ = time.Parse(time.RFC3339, entryDateStr)
-	resident.CreatedAt, _ = time.Parse(time.RFC3339, createdStr)
-	resident.UpdatedAt, _ = time.Parse(time.RFC3339, updatedStr)
-
-	// Set nullable fields
-	if bloodType.Valid {
-		resident.BloodType = models.BloodType(bloodType.String)
-	}
-	if notes.Valid {
-		resident.Notes = notes.String
-	}
-	if parent1ID.Valid {
-		resident.BiologicalParent1ID = &parent1ID.String
-	}
-	if parent2ID.Valid {
-		resident.BiologicalParent2ID = &parent2ID.String
-	}
-	if householdID.Valid {
-		resident.HouseholdID = &householdID.String
-	}
-	if quartersID.Valid {
-		resident.QuartersID = &quartersID.String
-	}
-	if vocationID.Valid {
-		resident.PrimaryVocationID = &vocationID.String
+	resident.BloodType = models.BloodType(bloodType.String)
+	if resident.Notes, err = openString("residents.notes", notes); err != nil {
+		return nil, err
 	}
+	resident.BiologicalParent1ID = stringPtr(parent1ID)
+	resident.BiologicalParent2ID = stringPtr(parent2ID)
+	resident.HouseholdID = stringPtr(householdID)
+	resident.QuartersID = stringPtr(quartersID)
+	resident.PrimaryVocationID = stringPtr(vocationID)
 
 	return &resident, nil
 }
-
-// Helper functions for nullable values
-func nullableString(s string) sql.NullString {
-	if s == "" {
-		return sql.NullString{}
-	}
-	return sql.NullString{String: s, Valid: true}
-}
-
-func nullableTime(t *time.Time) sql.NullString {
-	if t == nil {
-		return sql.NullString{}
-	}
-	return sql.NullString{String: t.Format(time.DateOnly), Valid: true}
-}
@@ -3,12 +3,16 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"path/filepath"
+	"slices"
 	"testing"
 	"time"
 
 	"github.com/vtuos/vtuos/internal/models"
 	"github.com/vtuos/vtuos/internal/testutil"
+	"github.com/vtuos/vtuos/internal/util"
+	"github.com/vtuos/vtuos/internal/vaultcrypt"
 )
 
 func setupTestDB(t *testing.T) *testutil.TestDB {
@@ -87,8 +91,8 @@ func TestResidentRepository_Create(t *testing.T) {
 		})
 
 		err := repo.Create(ctx, nil, resident)
-		if err == nil {
-			t.Error("expected error for invalid resident, got nil")
+		if !errors.Is(err, ErrValidation) {
+			t.Errorf("expected ErrValidation for invalid resident, got %v", err)
 		}
 	})
 
@@ -104,8 +108,8 @@ func TestResidentRepository_Create(t *testing.T) {
 		})
 
 		err = repo.Create(ctx, nil, resident2)
-		if err == nil {
-			t.Error("expected error for duplicate registry number, got nil")
+		if !errors.Is(err, ErrDuplicate) {
+			t.Errorf("expected ErrDuplicate for duplicate registry number, got %v", err)
 		}
 	})
 }
@@ -139,8 +143,8 @@ func TestResidentRepository_GetByID(t *testing.T) {
 
 	t.Run("Get non-existent resident returns error", func(t *testing.T) {
 		_, err := repo.GetByID(ctx, "non-existent-id")
-		if err != sql.ErrNoRows {
-			t.Errorf("expected sql.ErrNoRows, got %v", err)
+		if !errors.Is(err, ErrNotFound) {
+			t.Errorf("expected ErrNotFound, got %v", err)
 		}
 	})
 }
@@ -159,7 +163,7 @@ func TestResidentRepository_GetByRegistryNumber(t *testing.T) {
 			t.Fatalf("failed to create resident: %v", err)
 		}
 
-		found, err := repo.GetByRegistryNumber(ctx, resident.RegistryNumber)
+		found, err := repo.GetByRegistryNumber(ctx, nil, resident.RegistryNumber)
 		if err != nil {
 			t.Fatalf("failed to get resident: %v", err)
 		}
@@ -267,8 +271,55 @@ func TestResidentRepository_Delete(t *testing.T) {
 
 		// Verify deletion
 		_, err = repo.GetByID(ctx, resident.ID)
-		if err != sql.ErrNoRows {
-			t.Errorf("expected sql.ErrNoRows after delete, got %v", err)
+		if !errors.Is(err, ErrNotFound) {
+			t.Errorf("expected ErrNotFound after delete, got %v", err)
+		}
+	})
+
+	t.Run("Recorded parent is restricted", func(t *testing.T) {
+		parent1 := testutil.FixtureResident()
+		parent2 := testutil.FixtureFemaleResident()
+		for _, parent := range []*models.Resident{parent1, parent2} {
+			if err := repo.Create(ctx, nil, parent); err != nil {
+				t.Fatalf("failed to create parent: %v", err)
+			}
+		}
+		child := testutil.FixtureVaultBornResident(parent1.ID, parent2.ID)
+		if err := repo.Create(ctx, nil, child); err != nil {
+			t.Fatalf("failed to create child: %v", err)
+		}
+
+		err := repo.Delete(ctx, nil, parent1.ID)
+		if !errors.Is(err, ErrResidentIsParent) {
+			t.Errorf("expected ErrResidentIsParent, got %v", err)
+		}
+		if _, err := repo.GetByID(ctx, parent1.ID); err != nil {
+			t.Errorf("expected the parent to remain, got %v", err)
+		}
+	})
+
+	t.Run("Head of household is cleared", func(t *testing.T) {
+		households := NewHouseholdRepository(db.DB)
+		head := testutil.FixtureResident()
+		if err := repo.Create(ctx, nil, head); err != nil {
+			t.Fatalf("failed to create resident: %v", err)
+		}
+		household := testutil.FixtureHousehold(func(h *models.Household) {
+			h.HeadOfHouseholdID = &head.ID
+		})
+		if err := households.Create(ctx, nil, household); err != nil {
+			t.Fatalf("failed to create household: %v", err)
+		}
+
+		if err := repo.Delete(ctx, nil, head.ID); err != nil {
+			t.Fatalf("failed to delete resident: %v", err)
+		}
+		found, err := households.GetByID(ctx, household.ID)
+		if err != nil {
+			t.Fatalf("failed to get household: %v", err)
+		}
+		if found.HeadOfHouseholdID != nil {
+			t.Errorf("expected no head of household, got %s", *found.HeadOfHouseholdID)
 		}
 	})
 }
@@ -293,6 +344,7 @@ func TestResidentRepository_List(t *testing.T) {
 		testutil.FixtureFemaleResident(func(r *models.Resident) {
 			r.Surname = "Gamma"
 			r.Status = models.ResidentStatusActive
+			r.ClearanceLevel = 7
 		}),
 		testutil.FixtureDeceasedResident(func(r *models.Resident) {
 			r.Surname = "Delta"
@@ -306,7 +358,7 @@ func TestResidentRepository_List(t *testing.T) {
 	}
 
 	t.Run("List all residents", func(t *testing.T) {
-		result, err := repo.List(ctx, models.ResidentFilter{}, 1, 10)
+		result, err := repo.List(ctx, models.ResidentFilter{}, models.Pagination{Page: 1, PageSize: 10})
 		if err != nil {
 			t.Fatalf("failed to list residents: %v", err)
 		}
@@ -321,7 +373,7 @@ func TestResidentRepository_List(t *testing.T) {
 
 	t.Run("Filter by status", func(t *testing.T) {
 		status := models.ResidentStatusActive
-		result, err := repo.List(ctx, models.ResidentFilter{Status: &status}, 1, 10)
+		result, err := repo.List(ctx, models.ResidentFilter{Status: &status}, models.Pagination{Page: 1, PageSize: 10})
 		if err != nil {
 			t.Fatalf("failed to list residents: %v", err)
 		}
@@ -333,7 +385,7 @@ func TestResidentRepository_List(t *testing.T) {
 
 	t.Run("Filter by sex", func(t *testing.T) {
 		sex := models.SexFemale
-		result, err := repo.List(ctx, models.ResidentFilter{Sex: &sex}, 1, 10)
+		result, err := repo.List(ctx, models.ResidentFilter{Sex: &sex}, models.Pagination{Page: 1, PageSize: 10})
 		if err != nil {
 			t.Fatalf("failed to list residents: %v", err)
 		}
@@ -344,7 +396,7 @@ func TestResidentRepository_List(t *testing.T) {
 	})
 
 	t.Run("Search by name", func(t *testing.T) {
-		result, err := repo.List(ctx, models.ResidentFilter{SearchTerm: "Alpha"}, 1, 10)
+		result, err := repo.List(ctx, models.ResidentFilter{SearchTerm: "Alpha"}, models.Pagination{Page: 1, PageSize: 10})
 		if err != nil {
 			t.Fatalf("failed to list residents: %v", err)
 		}
@@ -357,9 +409,23 @@ func TestResidentRepository_List(t *testing.T) {
 		}
 	})
 
+	t.Run("Search by registry number", func(t *testing.T) {
+		result, err := repo.List(ctx, models.ResidentFilter{SearchTerm: residents[2].RegistryNumber}, models.Pagination{Page: 1, PageSize: 10})
+		if err != nil {
+			t.Fatalf("failed to list residents: %v", err)
+		}
+
+		if result.Total != 1 {
+			t.Errorf("expected total 1 resident matching %s, got %d", residents[2].RegistryNumber, result.Total)
+		}
+		if len(result.Residents) > 0 && result.Residents[0].Surname != "Gamma" {
+			t.Errorf("expected surname 'Gamma', got %s", result.Residents[0].Surname)
+		}
+	})
+
 	t.Run("Pagination", func(t *testing.T) {
 		// Get first page (2 items)
-		result, err := repo.List(ctx, models.ResidentFilter{}, 1, 2)
+		result, err := repo.List(ctx, models.ResidentFilter{}, models.Pagination{Page: 1, PageSize: 2})
 		if err != nil {
 			t.Fatalf("failed to list residents: %v", err)
 		}
@@ -375,7 +441,7 @@ func TestResidentRepository_List(t *testing.T) {
 		}
 
 		// Get second page
-		result, err = repo.List(ctx, models.ResidentFilter{}, 2, 2)
+		result, err = repo.List(ctx, models.ResidentFilter{}, models.Pagination{Page: 2, PageSize: 2})
 		if err != nil {
 			t.Fatalf("failed to list residents: %v", err)
 		}
@@ -384,6 +450,162 @@ func TestResidentRepository_List(t *testing.T) {
 			t.Errorf("expected 2 residents on second page, got %d", len(result.Residents))
 		}
 	})
+
+	t.Run("Sort by surname descending", func(t *testing.T) {
+		page := models.Pagination{Page: 1, PageSize: 10, Sort: models.SortOption{Column: "surname", Direction: models.SortDesc}}
+		result, err := repo.List(ctx, models.ResidentFilter{}, page)
+		if err != nil {
+			t.Fatalf("failed to list residents: %v", err)
+		}
+
+		want := []string{"Gamma", "Delta", "Beta", "Alpha"}
+		if len(result.Residents) != len(want) {
+			t.Fatalf("expected %d residents, got %d", len(want), len(result.Residents))
+		}
+		for i, r := range result.Residents {
+			if r.Surname != want[i] {
+				t.Errorf("position %d: expected %s, got %s", i, want[i], r.Surname)
+			}
+		}
+	})
+
+	t.Run("Keyset pagination", func(t *testing.T) {
+		for _, sort := range []models.SortOption{{}, {Column: "surname", Direction: models.SortDesc}} {
+			offset, err := repo.List(ctx, models.ResidentFilter{}, models.Pagination{Page: 1, PageSize: 10, Sort: sort})
+			if err != nil {
+				t.Fatalf("failed to list residents: %v", err)
+			}
+
+			page := models.Pagination{Page: 1, PageSize: 3, Sort: sort}
+			var got []string
+			for {
+				result, err := repo.List(ctx, models.ResidentFilter{}, page)
+				if err != nil {
+					t.Fatalf("failed to list residents: %v", err)
+				}
+				for _, r := range result.Residents {
+					got = append(got, r.ID)
+				}
+				if result.NextCursor == "" {
+					break
+				}
+				page.After = result.NextCursor
+				page.Page++
+			}
+
+			if page.Page != 2 {
+				t.Errorf("%s: expected 2 pages, got %d", sort, page.Page)
+			}
+			if len(got) != len(offset.Residents) {
+				t.Fatalf("%s: expected %d residents, got %d", sort, len(offset.Residents), len(got))
+			}
+			for i, r := range offset.Residents {
+				if got[i] != r.ID {
+					t.Errorf("%s: position %d differs from offset order", sort, i)
+				}
+			}
+		}
+	})
+
+	t.Run("Filter by tags", func(t *testing.T) {
+		tags := NewTagRepository(db.DB)
+		if err := tags.Add(ctx, nil, residents[0].ID, []string{"council", "G.O.A.T. pending"}); err != nil {
+			t.Fatalf("failed to tag resident: %v", err)
+		}
+		if err := tags.Add(ctx, nil, residents[1].ID, []string{"Council"}); err != nil {
+			t.Fatalf("failed to tag resident: %v", err)
+		}
+
+		result, err := repo.List(ctx, models.ResidentFilter{Tags: []string{"COUNCIL"}}, models.Pagination{Page: 1, PageSize: 10})
+		if err != nil {
+			t.Fatalf("failed to list residents: %v", err)
+		}
+		if result.Total != 2 {
+			t.Errorf("expected 2 residents tagged council, got %d", result.Total)
+		}
+
+		result, err = repo.List(ctx, models.ResidentFilter{Tags: []string{"council", "g.o.a.t. pending"}}, models.Pagination{Page: 1, PageSize: 10})
+		if err != nil {
+			t.Fatalf("failed to list residents: %v", err)
+		}
+		if result.Total != 1 || result.Residents[0].ID != residents[0].ID {
+			t.Errorf("expected only Alpha to carry both tags, got %d residents", result.Total)
+		}
+	})
+
+	t.Run("Filter by age and clearance", func(t *testing.T) {
+		// Every fixture resident turns 30 on the same day
+		birthday := residents[0].DateOfBirth.AddDate(30, 0, 0)
+		count := func(filter models.ResidentFilter) int {
+			t.Helper()
+			result, err := repo.List(ctx, filter, models.Pagination{Page: 1, PageSize: 10})
+			if err != nil {
+				t.Fatalf("failed to list residents: %v", err)
+			}
+			return result.Total
+		}
+		age := func(n int) *int { return &n }
+
+		if got := count(models.ResidentFilter{MinAge: age(30), MaxAge: age(30), AsOf: birthday}); got != 4 {
+			t.Errorf("expected 4 residents aged 30 on their birthday, got %d", got)
+		}
+		if got := count(models.ResidentFilter{MinAge: age(30), AsOf: birthday.AddDate(0, 0, -1)}); got != 0 {
+			t.Errorf("expected no resident aged 30 the day before, got %d", got)
+		}
+		if got := count(models.ResidentFilter{MaxAge: age(29), AsOf: birthday.AddDate(0, 0, -1)}); got != 4 {
+			t.Errorf("expected 4 residents aged 29 the day before, got %d", got)
+		}
+		if got := count(models.ResidentFilter{MinClearance: age(5), MaxClearance: age(10)}); got != 1 {
+			t.Errorf("expected only Gamma to hold clearance 5 or above, got %d", got)
+		}
+	})
+
+	t.Run("Filter by household type and sector", func(t *testing.T) {
+		quarters := testutil.FixtureQuarters(func(q *models.Quarters) { q.Sector = "B" })
+		if _, err := db.ExecContext(ctx, `
+			INSERT INTO quarters (id, unit_code, sector, level, unit_type, capacity, square_meters)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			quarters.ID, quarters.UnitCode, quarters.Sector, quarters.Level, quarters.UnitType,
+			quarters.Capacity, quarters.SquareMeters); err != nil {
+			t.Fatalf("failed to create quarters: %v", err)
+		}
+		household := testutil.FixtureHousehold(func(h *models.Household) { h.QuartersID = &quarters.ID })
+		if err := NewHouseholdRepository(db.DB).Create(ctx, nil, household); err != nil {
+			t.Fatalf("failed to create household: %v", err)
+		}
+		residents[1].HouseholdID = &household.ID
+		if err := repo.Update(ctx, nil, residents[1]); err != nil {
+			t.Fatalf("failed to update resident: %v", err)
+		}
+
+		family := models.HouseholdTypeFamily
+		for _, filter := range []models.ResidentFilter{{HouseholdType: &family}, {Sector: "B"}} {
+			result, err := repo.List(ctx, filter, models.Pagination{Page: 1, PageSize: 10})
+			if err != nil {
+				t.Fatalf("failed to list residents: %v", err)
+			}
+			if result.Total != 1 || result.Residents[0].ID != residents[1].ID {
+				t.Errorf("expected only Beta for %+v, got %d residents", filter, result.Total)
+			}
+		}
+
+		communal := models.HouseholdTypeCommunal
+		result, err := repo.List(ctx, models.ResidentFilter{HouseholdType: &communal}, models.Pagination{Page: 1, PageSize: 10})
+		if err != nil {
+			t.Fatalf("failed to list residents: %v", err)
+		}
+		if result.Total != 0 {
+			t.Errorf("expected no resident in a communal household, got %d", result.Total)
+		}
+	})
+
+	t.Run("Sort by unknown column", func(t *testing.T) {
+		page := models.Pagination{Page: 1, PageSize: 10, Sort: models.SortOption{Column: "surname; DROP TABLE residents"}}
+		_, err := repo.List(ctx, models.ResidentFilter{}, page)
+		if !errors.Is(err, ErrValidation) {
+			t.Errorf("expected ErrValidation, got %v", err)
+		}
+	})
 }
 
 func TestResidentRepository_CountByStatus(t *testing.T) {
@@ -503,3 +725,146 @@ func TestResidentRepository_AgeCalculations(t *testing.T) {
 		t.Error("70 year old should not be working age")
 	}
 }
+
+func TestResidentRepository_ForVault(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close(t)
+
+	ctx := context.Background()
+	repo76 := NewResidentRepository(db.DB).ForVault(76)
+	repo81 := NewResidentRepository(db.DB).ForVault(81)
+
+	for _, r := range []*models.Resident{testutil.FixtureResident(), testutil.FixtureResident()} {
+		if err := repo76.Create(ctx, nil, r); err != nil {
+			t.Fatalf("failed to create resident: %v", err)
+		}
+	}
+	other := testutil.FixtureResident()
+	if err := repo81.Create(ctx, nil, other); err != nil {
+		t.Fatalf("failed to create resident: %v", err)
+	}
+	if other.VaultID != 81 {
+		t.Errorf("expected vault 81, got %d", other.VaultID)
+	}
+
+	counts, err := repo76.CountByStatus(ctx)
+	if err != nil {
+		t.Fatalf("failed to count by status: %v", err)
+	}
+	if counts[models.ResidentStatusActive] != 2 {
+		t.Errorf("expected 2 active residents in vault 76, got %d", counts[models.ResidentStatusActive])
+	}
+
+	all, err := NewResidentRepository(db.DB).CountByStatus(ctx)
+	if err != nil {
+		t.Fatalf("failed to count by status: %v", err)
+	}
+	if all[models.ResidentStatusActive] != 3 {
+		t.Errorf("expected 3 active residents across vaults, got %d", all[models.ResidentStatusActive])
+	}
+
+	claimed, err := ClaimUnassigned(ctx, db.DB, 76)
+	if err != nil {
+		t.Fatalf("failed to claim unassigned: %v", err)
+	}
+	if claimed != 0 {
+		t.Errorf("expected no unassigned rows, got %d", claimed)
+	}
+}
+
+func TestResidentRepository_ReserveRegistryNumbers(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close(t)
+
+	repo := NewResidentRepository(db.DB)
+	ctx := context.Background()
+	gen := util.NewRegistryNumberGenerator(76)
+
+	reserve := func(n int) []string {
+		t.Helper()
+		var numbers []string
+		err := WithTransaction(ctx, db.DB, func(tx *sql.Tx) error {
+			var err error
+			numbers, err = repo.ReserveRegistryNumbers(ctx, tx, gen, 2079, n)
+			return err
+		})
+		if err != nil {
+			t.Fatalf("failed to reserve registry numbers: %v", err)
+		}
+		return numbers
+	}
+
+	// The sequence starts after the highest number already issued
+	for _, regNum := range []string{"V076-00007", "V081-00099", "VT-076-legacy"} {
+		resident := testutil.FixtureResident()
+		resident.RegistryNumber = regNum
+		if err := repo.Create(ctx, nil, resident); err != nil {
+			t.Fatalf("failed to create resident: %v", err)
+		}
+	}
+	if got := reserve(1); !slices.Equal(got, []string{"V076-00008"}) {
+		t.Errorf("first reservation = %q, want [V076-00008]", got)
+	}
+
+	// Numbers of deleted residents are not issued again
+	resident := testutil.FixtureResident()
+	resident.RegistryNumber = "V076-00008"
+	if err := repo.Create(ctx, nil, resident); err != nil {
+		t.Fatalf("failed to create resident: %v", err)
+	}
+	if err := repo.Delete(ctx, nil, resident.ID); err != nil {
+		t.Fatalf("failed to delete resident: %v", err)
+	}
+	if got, want := reserve(3), []string{"V076-00009", "V076-00010", "V076-00011"}; !slices.Equal(got, want) {
+		t.Errorf("reservation after delete = %q, want %q", got, want)
+	}
+
+	err := WithTransaction(ctx, db.DB, func(tx *sql.Tx) error {
+		_, err := repo.ReserveRegistryNumbers(ctx, tx, gen, 2079, 0)
+		return err
+	})
+	if !errors.Is(err, ErrValidation) {
+		t.Errorf("expected ErrValidation reserving no numbers, got %v", err)
+	}
+}
+
+func TestResidentRepository_EncryptedNotes(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close(t)
+
+	key, _ := vaultcrypt.NewKey()
+	keyring := vaultcrypt.NewKeyring()
+	if err := keyring.Add("k1", key); err != nil {
+		t.Fatal(err)
+	}
+	vaultcrypt.Install(keyring)
+	defer vaultcrypt.Install(nil)
+
+	repo := NewResidentRepository(db.DB)
+	ctx := context.Background()
+	resident := testutil.FixtureResident()
+	resident.Notes = "Sleepwalks"
+	if err := repo.Create(ctx, nil, resident); err != nil {
+		t.Fatalf("failed to create resident: %v", err)
+	}
+
+	var stored string
+	if err := db.QueryRow(`SELECT notes FROM residents WHERE id = ?`, resident.ID).Scan(&stored); err != nil {
+		t.Fatal(err)
+	}
+	if !vaultcrypt.IsSealed(stored) {
+		t.Errorf("notes stored as %q, want encrypted", stored)
+	}
+	found, err := repo.GetByID(ctx, resident.ID)
+	if err != nil {
+		t.Fatalf("failed to get resident: %v", err)
+	}
+	if found.Notes != "Sleepwalks" {
+		t.Errorf("expected notes Sleepwalks, got %q", found.Notes)
+	}
+
+	vaultcrypt.Install(nil)
+	if _, err := repo.GetByID(ctx, resident.ID); !errors.Is(err, vaultcrypt.ErrNoKey) {
+		t.Errorf("expected ErrNoKey reading without the key, got %v", err)
+	}
+}
@@ -12,12 +12,23 @@ import (
 
 // ResourceRepository handles resource data access.
 type ResourceRepository struct {
-	db *sql.DB
+	db    *sql.DB
+	stmts *stmtCache
+	vault int // Vault stock lists are limited to, 0 for every vault
 }
 
 // NewResourceRepository creates a new resource repository.
 func NewResourceRepository(db *sql.DB) *ResourceRepository {
-	return &ResourceRepository{db: db}
+	return &ResourceRepository{db: db, stmts: newStmtCache(db)}
+}
+
+// ForVault returns a copy of the repository whose stock and transaction
+// lists and totals are limited to stock held by the vault, and which creates
+// stock there. The category and item catalogs are shared by every vault.
+func (r *ResourceRepository) ForVault(vault int) *ResourceRepository {
+	scoped := *r
+	scoped.vault = vault
+	return &scoped
 }
 
 // ============================================================================
@@ -46,7 +57,34 @@ func (r *ResourceRepository) CreateCategory(ctx context.Context, tx *sql.Tx, cat
 		cat.CreatedAt.Format(time.RFC3339),
 	)
 	if err != nil {
-		return fmt.Errorf("inserting category: %w", err)
+		return fmt.Errorf("inserting category: %w", constraintError(err))
+	}
+	return nil
+}
+
+// UpdateCategory updates a category's name, description, unit and flags.
+// The code is fixed once the category is created.
+func (r *ResourceRepository) UpdateCategory(ctx context.Context, tx *sql.Tx, cat *models.ResourceCategory) error {
+	query := `
+		UPDATE resource_categories SET
+			name = ?, description = ?, unit_of_measure = ?, is_consumable = ?, is_critical = ?
+		WHERE id = ?`
+
+	result, err := r.getExecer(tx).ExecContext(ctx, query,
+		cat.Name,
+		nullableString(cat.Description),
+		cat.UnitOfMeasure,
+		boolToInt(cat.IsConsumable),
+		boolToInt(cat.IsCritical),
+		cat.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("updating category: %w", constraintError(err))
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("category %w: %s", ErrNotFound, cat.ID)
 	}
 	return nil
 }
@@ -85,17 +123,7 @@ func (r *ResourceRepository) ListCategories(ctx context.Context) ([]*models.Reso
 	if err != nil {
 		return nil, fmt.Errorf("querying categories: %w", err)
 	}
-	defer rows.Close()
-
-	var categories []*models.ResourceCategory
-	for rows.Next() {
-		cat, err := r.scanCategoryRow(rows)
-		if err != nil {
-			return nil, err
-		}
-		categories = append(categories, cat)
-	}
-	return categories, rows.Err()
+	return collect(rows, r.scanCategory)
 }
 
 // ============================================================================
@@ -108,13 +136,16 @@ func (r *ResourceRepository) CreateItem(ctx context.Context, tx *sql.Tx, item *m
 		INSERT INTO resource_items (
 			id, category_id, item_code, name, description, unit_of_measure,
 			calories_per_unit, shelf_life_days, storage_requirements,
-			is_producible, production_rate_per_day, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+			is_producible, production_rate_per_day, min_stock, target_stock,
+			protein_g_per_unit, carbs_g_per_unit, fat_g_per_unit, vitamins_pct_per_unit,
+			created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
 	execer := r.getExecer(tx)
 	now := time.Now().UTC()
 	item.CreatedAt = now
 	item.UpdatedAt = now
+	nutrients := nullableNutrients(item.Nutrients)
 
 	_, err := execer.ExecContext(ctx, query,
 		item.ID,
@@ -128,11 +159,57 @@ func (r *ResourceRepository) CreateItem(ctx context.Context, tx *sql.Tx, item *m
 		nullableString(item.StorageRequirements),
 		boolToInt(item.IsProducible),
 		item.ProductionRatePerDay,
+		item.MinStock,
+		item.TargetStock,
+		nutrients[0], nutrients[1], nutrients[2], nutrients[3],
 		item.CreatedAt.Format(time.RFC3339),
 		item.UpdatedAt.Format(time.RFC3339),
 	)
 	if err != nil {
-		return fmt.Errorf("inserting item: %w", err)
+		return fmt.Errorf("inserting item: %w", constraintError(err))
+	}
+	return nil
+}
+
+// UpdateItem updates an item's category, description, unit, nutrition,
+// shelf life, production and stock thresholds. The item code is fixed once the item is
+// created.
+func (r *ResourceRepository) UpdateItem(ctx context.Context, tx *sql.Tx, item *models.ResourceItem) error {
+	query := `
+		UPDATE resource_items SET
+			category_id = ?, name = ?, description = ?, unit_of_measure = ?,
+			calories_per_unit = ?, shelf_life_days = ?, storage_requirements = ?,
+			is_producible = ?, production_rate_per_day = ?, min_stock = ?, target_stock = ?,
+			protein_g_per_unit = ?, carbs_g_per_unit = ?, fat_g_per_unit = ?, vitamins_pct_per_unit = ?,
+			updated_at = ?
+		WHERE id = ?`
+
+	item.UpdatedAt = time.Now().UTC()
+	nutrients := nullableNutrients(item.Nutrients)
+
+	result, err := r.getExecer(tx).ExecContext(ctx, query,
+		item.CategoryID,
+		item.Name,
+		nullableString(item.Description),
+		item.UnitOfMeasure,
+		item.CaloriesPerUnit,
+		item.ShelfLifeDays,
+		nullableString(item.StorageRequirements),
+		boolToInt(item.IsProducible),
+		item.ProductionRatePerDay,
+		item.MinStock,
+		item.TargetStock,
+		nutrients[0], nutrients[1], nutrients[2], nutrients[3],
+		item.UpdatedAt.Format(time.RFC3339),
+		item.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("updating item: %w", constraintError(err))
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("item %w: %s", ErrNotFound, item.ID)
 	}
 	return nil
 }
@@ -142,14 +219,16 @@ func (r *ResourceRepository) GetItem(ctx context.Context, id string) (*models.Re
 	query := `
 		SELECT i.id, i.category_id, i.item_code, i.name, i.description, i.unit_of_measure,
 			i.calories_per_unit, i.shelf_life_days, i.storage_requirements,
-			i.is_producible, i.production_rate_per_day, i.created_at, i.updated_at,
+			i.is_producible, i.production_rate_per_day, i.min_stock, i.target_stock,
+			i.protein_g_per_unit, i.carbs_g_per_unit, i.fat_g_per_unit, i.vitamins_pct_per_unit,
+			i.created_at, i.updated_at,
 			c.id, c.code, c.name, c.description, c.unit_of_measure,
 			c.is_consumable, c.is_critical, c.created_at
 		FROM resource_items i
 		LEFT JOIN resource_categories c ON i.category_id = c.id
 		WHERE i.id = ?`
 
-	return r.scanItemWithCategory(r.db.QueryRowContext(ctx, query, id))
+	return r.scanItemWithCategory(r.stmts.queryRow(ctx, query, id))
 }
 
 // GetItemByCode retrieves an item by code.
@@ -157,18 +236,34 @@ func (r *ResourceRepository) GetItemByCode(ctx context.Context, code string) (*m
 	query := `
 		SELECT i.id, i.category_id, i.item_code, i.name, i.description, i.unit_of_measure,
 			i.calories_per_unit, i.shelf_life_days, i.storage_requirements,
-			i.is_producible, i.production_rate_per_day, i.created_at, i.updated_at,
+			i.is_producible, i.production_rate_per_day, i.min_stock, i.target_stock,
+			i.protein_g_per_unit, i.carbs_g_per_unit, i.fat_g_per_unit, i.vitamins_pct_per_unit,
+			i.created_at, i.updated_at,
 			c.id, c.code, c.name, c.description, c.unit_of_measure,
 			c.is_consumable, c.is_critical, c.created_at
 		FROM resource_items i
 		LEFT JOIN resource_categories c ON i.category_id = c.id
 		WHERE i.item_code = ?`
 
-	return r.scanItemWithCategory(r.db.QueryRowContext(ctx, query, code))
+	return r.scanItemWithCategory(r.stmts.queryRow(ctx, query, code))
+}
+
+// itemSortColumns maps models.ItemSortColumns to SQL.
+var itemSortColumns = sortColumns{
+	"item_code":       "i.item_code",
+	"name":            "i.name",
+	"unit":            "i.unit_of_measure",
+	"shelf_life_days": "i.shelf_life_days",
 }
 
-// ListItems retrieves items with optional category filter.
+// ListItems retrieves items with optional category filter, by item code
+// unless page.Sort picks one of models.ItemSortColumns.
 func (r *ResourceRepository) ListItems(ctx context.Context, categoryID string, page models.Pagination) (*models.ItemList, error) {
+	orderBy, err := itemSortColumns.orderBy(page.Sort, "i.item_code", "i.item_code")
+	if err != nil {
+		return nil, err
+	}
+
 	var conditions []string
 	var args []any
 
@@ -193,26 +288,22 @@ func (r *ResourceRepository) ListItems(ctx context.Context, categoryID string, p
 	query := fmt.Sprintf(`
 		SELECT i.id, i.category_id, i.item_code, i.name, i.description, i.unit_of_measure,
 			i.calories_per_unit, i.shelf_life_days, i.storage_requirements,
-			i.is_producible, i.production_rate_per_day, i.created_at, i.updated_at
+			i.is_producible, i.production_rate_per_day, i.min_stock, i.target_stock,
+			i.protein_g_per_unit, i.carbs_g_per_unit, i.fat_g_per_unit, i.vitamins_pct_per_unit,
+			i.created_at, i.updated_at
 		FROM resource_items i
 		%s
-		ORDER BY i.item_code
-		LIMIT ? OFFSET ?`, whereClause)
+		%s
+		LIMIT ? OFFSET ?`, whereClause, orderBy)
 
 	args = append(args, page.Limit(), page.Offset())
 	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("querying items: %w", err)
 	}
-	defer rows.Close()
-
-	var items []*models.ResourceItem
-	for rows.Next() {
-		item, err := r.scanItemRow(rows)
-		if err != nil {
-			return nil, err
-		}
-		items = append(items, item)
+	items, err := collect(rows, r.scanItem)
+	if err != nil {
+		return nil, err
 	}
 
 	return &models.ItemList{
@@ -220,7 +311,25 @@ func (r *ResourceRepository) ListItems(ctx context.Context, categoryID string, p
 		Total:      total,
 		Page:       page.Page,
 		TotalPages: page.TotalPages(total),
-	}, rows.Err()
+	}, nil
+}
+
+// ListItemsWithMinimum retrieves the items that set a minimum stock, by
+// item code.
+func (r *ResourceRepository) ListItemsWithMinimum(ctx context.Context) ([]*models.ResourceItem, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT i.id, i.category_id, i.item_code, i.name, i.description, i.unit_of_measure,
+			i.calories_per_unit, i.shelf_life_days, i.storage_requirements,
+			i.is_producible, i.production_rate_per_day, i.min_stock, i.target_stock,
+			i.protein_g_per_unit, i.carbs_g_per_unit, i.fat_g_per_unit, i.vitamins_pct_per_unit,
+			i.created_at, i.updated_at
+		FROM resource_items i
+		WHERE i.min_stock IS NOT NULL
+		ORDER BY i.item_code`)
+	if err != nil {
+		return nil, fmt.Errorf("querying items: %w", err)
+	}
+	return collect(rows, r.scanItem)
 }
 
 // ============================================================================
@@ -233,13 +342,16 @@ func (r *ResourceRepository) CreateStock(ctx context.Context, tx *sql.Tx, stock
 		INSERT INTO resource_stocks (
 			id, item_id, lot_number, quantity, quantity_reserved,
 			storage_location, received_date, expiration_date, status,
-			last_audit_date, last_audit_by, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+			last_audit_date, last_audit_by, vault_id, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
 	execer := r.getExecer(tx)
 	now := time.Now().UTC()
 	stock.CreatedAt = now
 	stock.UpdatedAt = now
+	if stock.VaultID == 0 {
+		stock.VaultID = r.vault
+	}
 
 	_, err := execer.ExecContext(ctx, query,
 		stock.ID,
@@ -253,34 +365,59 @@ func (r *ResourceRepository) CreateStock(ctx context.Context, tx *sql.Tx, stock
 		string(stock.Status),
 		nullableTimePtrRFC3339(stock.LastAuditDate),
 		stock.LastAuditBy,
+		stock.VaultID,
 		stock.CreatedAt.Format(time.RFC3339),
 		stock.UpdatedAt.Format(time.RFC3339),
 	)
 	if err != nil {
-		return fmt.Errorf("inserting stock: %w", err)
+		return fmt.Errorf("inserting stock: %w", constraintError(err))
 	}
 	return nil
 }
 
-// GetStock retrieves a stock by ID.
-func (r *ResourceRepository) GetStock(ctx context.Context, id string) (*models.ResourceStock, error) {
+// GetStock retrieves a stock by ID, within tx if given.
+func (r *ResourceRepository) GetStock(ctx context.Context, tx *sql.Tx, id string) (*models.ResourceStock, error) {
 	query := `
 		SELECT s.id, s.item_id, s.lot_number, s.quantity, s.quantity_reserved,
 			s.storage_location, s.received_date, s.expiration_date, s.status,
-			s.last_audit_date, s.last_audit_by, s.created_at, s.updated_at,
+			s.last_audit_date, s.last_audit_by, s.vault_id, s.created_at, s.updated_at,
 			i.id, i.category_id, i.item_code, i.name, i.unit_of_measure
 		FROM resource_stocks s
 		LEFT JOIN resource_items i ON s.item_id = i.id
 		WHERE s.id = ?`
 
-	return r.scanStockWithItem(r.db.QueryRowContext(ctx, query, id))
+	if tx != nil {
+		return r.scanStockWithItem(tx.QueryRowContext(ctx, query, id))
+	}
+	return r.scanStockWithItem(r.stmts.queryRow(ctx, query, id))
+}
+
+// GetStocksByLot retrieves the stocks with a lot number, oldest first, within
+// tx if given. Lot numbers are not unique across items, so there may be more
+// than one.
+func (r *ResourceRepository) GetStocksByLot(ctx context.Context, tx *sql.Tx, lot string) ([]*models.ResourceStock, error) {
+	query := `
+		SELECT s.id, s.item_id, s.lot_number, s.quantity, s.quantity_reserved,
+			s.storage_location, s.received_date, s.expiration_date, s.status,
+			s.last_audit_date, s.last_audit_by, s.vault_id, s.created_at, s.updated_at,
+			i.id, i.category_id, i.item_code, i.name, i.unit_of_measure
+		FROM resource_stocks s
+		LEFT JOIN resource_items i ON s.item_id = i.id
+		WHERE s.lot_number = ? AND ` + vaultCondition + `
+		ORDER BY s.received_date ASC, s.id ASC`
+
+	rows, err := r.getQuerier(tx).QueryContext(ctx, query, lot, r.vault, r.vault)
+	if err != nil {
+		return nil, fmt.Errorf("querying stocks by lot: %w", err)
+	}
+	return collect(rows, r.scanStockWithItem)
 }
 
 // UpdateStock updates a stock record.
 func (r *ResourceRepository) UpdateStock(ctx context.Context, tx *sql.Tx, stock *models.ResourceStock) error {
 	query := `
 		UPDATE resource_stocks SET
-			quantity = ?, quantity_reserved = ?, status = ?,
+			quantity = ?, quantity_reserved = ?, storage_location = ?, status = ?,
 			last_audit_date = ?, last_audit_by = ?, updated_at = ?
 		WHERE id = ?`
 
@@ -290,6 +427,7 @@ func (r *ResourceRepository) UpdateStock(ctx context.Context, tx *sql.Tx, stock
 	result, err := execer.ExecContext(ctx, query,
 		stock.Quantity,
 		stock.QuantityReserved,
+		stock.StorageLocation,
 		string(stock.Status),
 		nullableTimePtrRFC3339(stock.LastAuditDate),
 		stock.LastAuditBy,
@@ -297,20 +435,105 @@ func (r *ResourceRepository) UpdateStock(ctx context.Context, tx *sql.Tx, stock
 		stock.ID,
 	)
 	if err != nil {
-		return fmt.Errorf("updating stock: %w", err)
+		return fmt.Errorf("updating stock: %w", constraintError(err))
 	}
 
 	rows, _ := result.RowsAffected()
 	if rows == 0 {
-		return fmt.Errorf("stock not found: %s", stock.ID)
+		return fmt.Errorf("stock %w: %s", ErrNotFound, stock.ID)
 	}
 	return nil
 }
 
-// ListStocks retrieves stocks with filtering and pagination.
-func (r *ResourceRepository) ListStocks(ctx context.Context, filter models.StockFilter, page models.Pagination) (*models.StockList, error) {
-	var conditions []string
-	var args []any
+// stockSortColumns maps models.StockSortColumns to SQL. Stock without an
+// expiration date sorts as never expiring.
+var stockSortColumns = sortColumns{
+	"item_code":        "i.item_code",
+	"name":             "i.name",
+	"category":         "(SELECT code FROM resource_categories WHERE id = i.category_id)",
+	"quantity":         "s.quantity",
+	"unit":             "i.unit_of_measure",
+	"status":           "s.status",
+	"expiration_date":  "COALESCE(s.expiration_date, '9999-12-31')",
+	"storage_location": "s.storage_location",
+	"received_date":    "s.received_date",
+}
+
+// ListStocks retrieves stocks with filtering and pagination, soonest-expiring
+// first unless page.Sort picks one of models.StockSortColumns, within tx if
+// given.
+func (r *ResourceRepository) ListStocks(ctx context.Context, tx *sql.Tx, filter models.StockFilter, page models.Pagination) (*models.StockList, error) {
+	orderBy, err := stockSortColumns.orderBy(page.Sort,
+		"s.expiration_date ASC NULLS LAST, s.received_date ASC", "s.received_date, s.id")
+	if err != nil {
+		return nil, err
+	}
+	whereClause, args := r.stockWhere(filter)
+
+	// Count total
+	countQuery := fmt.Sprintf(`
+		SELECT COUNT(*) FROM resource_stocks s
+		LEFT JOIN resource_items i ON s.item_id = i.id
+		%s`, whereClause)
+	querier := r.getQuerier(tx)
+	var total int
+	if err := querier.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, fmt.Errorf("counting stocks: %w", err)
+	}
+
+	// Get page
+	query := fmt.Sprintf("%s\n%s\n%s\nLIMIT ? OFFSET ?", stockSelect, whereClause, orderBy)
+	args = append(args, page.Limit(), page.Offset())
+	rows, err := querier.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying stocks: %w", err)
+	}
+	stocks, err := collect(rows, r.scanStockWithItem)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.StockList{
+		Stocks:     stocks,
+		Total:      total,
+		Page:       page.Page,
+		TotalPages: page.TotalPages(total),
+	}, nil
+}
+
+// ListAllStocks retrieves every stock matching filter in the order of sort,
+// soonest-expiring first when it is unset, within tx if given. Unlike
+// ListStocks it is not paged, so drawing from an item's lots sees all of
+// them.
+func (r *ResourceRepository) ListAllStocks(ctx context.Context, tx *sql.Tx, filter models.StockFilter, sort models.SortOption) ([]*models.ResourceStock, error) {
+	orderBy, err := stockSortColumns.orderBy(sort,
+		"s.expiration_date ASC NULLS LAST, s.received_date ASC", "s.received_date, s.id")
+	if err != nil {
+		return nil, err
+	}
+	whereClause, args := r.stockWhere(filter)
+
+	rows, err := r.getQuerier(tx).QueryContext(ctx, stockSelect+"\n"+whereClause+"\n"+orderBy, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying stocks: %w", err)
+	}
+	return collect(rows, r.scanStockWithItem)
+}
+
+// stockSelect selects stocks with a summary of their item, for
+// scanStockWithItem.
+const stockSelect = `
+		SELECT s.id, s.item_id, s.lot_number, s.quantity, s.quantity_reserved,
+			s.storage_location, s.received_date, s.expiration_date, s.status,
+			s.last_audit_date, s.last_audit_by, s.vault_id, s.created_at, s.updated_at,
+			i.id, i.category_id, i.item_code, i.name, i.unit_of_measure
+		FROM resource_stocks s
+		LEFT JOIN resource_items i ON s.item_id = i.id`
+
+// stockWhere builds the WHERE clause of a stock list and its arguments.
+func (r *ResourceRepository) stockWhere(filter models.StockFilter) (string, []any) {
+	conditions := []string{vaultCondition}
+	args := []any{r.vault, r.vault}
 
 	if filter.ItemID != "" {
 		conditions = append(conditions, "s.item_id = ?")
@@ -337,97 +560,65 @@ func (r *ResourceRepository) ListStocks(ctx context.Context, filter models.Stock
 		args = append(args, *filter.MinQuantity)
 	}
 
-	whereClause := ""
-	if len(conditions) > 0 {
-		whereClause = "WHERE " + strings.Join(conditions, " AND ")
-	}
-
-	// Count total
-	countQuery := fmt.Sprintf(`
-		SELECT COUNT(*) FROM resource_stocks s
-		LEFT JOIN resource_items i ON s.item_id = i.id
-		%s`, whereClause)
-	var total int
-	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
-		return nil, fmt.Errorf("counting stocks: %w", err)
-	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
 
-	// Get page
-	query := fmt.Sprintf(`
+// GetExpiringStocks retrieves stocks expiring within the given days.
+func (r *ResourceRepository) GetExpiringStocks(ctx context.Context, days int) ([]*models.ResourceStock, error) {
+	query := `
 		SELECT s.id, s.item_id, s.lot_number, s.quantity, s.quantity_reserved,
 			s.storage_location, s.received_date, s.expiration_date, s.status,
-			s.last_audit_date, s.last_audit_by, s.created_at, s.updated_at,
+			s.last_audit_date, s.last_audit_by, s.vault_id, s.created_at, s.updated_at,
 			i.id, i.category_id, i.item_code, i.name, i.unit_of_measure
 		FROM resource_stocks s
 		LEFT JOIN resource_items i ON s.item_id = i.id
-		%s
-		ORDER BY s.expiration_date ASC NULLS LAST, s.received_date ASC
-		LIMIT ? OFFSET ?`, whereClause)
+		WHERE s.expiration_date IS NOT NULL
+		  AND s.expiration_date <= date('now', '+' || ? || ' days')
+		  AND s.status = 'AVAILABLE'
+		  AND ` + vaultCondition + `
+		ORDER BY s.expiration_date ASC`
 
-	args = append(args, page.Limit(), page.Offset())
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := r.db.QueryContext(ctx, query, days, r.vault, r.vault)
 	if err != nil {
-		return nil, fmt.Errorf("querying stocks: %w", err)
-	}
-	defer rows.Close()
-
-	var stocks []*models.ResourceStock
-	for rows.Next() {
-		stock, err := r.scanStockWithItemRow(rows)
-		if err != nil {
-			return nil, err
-		}
-		stocks = append(stocks, stock)
+		return nil, fmt.Errorf("querying expiring stocks: %w", err)
 	}
-
-	return &models.StockList{
-		Stocks:     stocks,
-		Total:      total,
-		Page:       page.Page,
-		TotalPages: page.TotalPages(total),
-	}, rows.Err()
+	return collect(rows, r.scanStockWithItem)
 }
 
-// GetExpiringStocks retrieves stocks expiring within the given days.
-func (r *ResourceRepository) GetExpiringStocks(ctx context.Context, days int) ([]*models.ResourceStock, error) {
+// GetStocksExpiringBy retrieves available stocks whose expiration date is at
+// or before cutoff, soonest-expiring first. Unlike GetExpiringStocks it takes
+// the cutoff from the caller, so it works in vault time.
+func (r *ResourceRepository) GetStocksExpiringBy(ctx context.Context, cutoff time.Time) ([]*models.ResourceStock, error) {
 	query := `
 		SELECT s.id, s.item_id, s.lot_number, s.quantity, s.quantity_reserved,
 			s.storage_location, s.received_date, s.expiration_date, s.status,
-			s.last_audit_date, s.last_audit_by, s.created_at, s.updated_at,
+			s.last_audit_date, s.last_audit_by, s.vault_id, s.created_at, s.updated_at,
 			i.id, i.category_id, i.item_code, i.name, i.unit_of_measure
 		FROM resource_stocks s
 		LEFT JOIN resource_items i ON s.item_id = i.id
 		WHERE s.expiration_date IS NOT NULL
-		  AND s.expiration_date <= date('now', '+' || ? || ' days')
+		  AND s.expiration_date <= ?
 		  AND s.status = 'AVAILABLE'
-		ORDER BY s.expiration_date ASC`
+		  AND ` + vaultCondition + `
+		ORDER BY s.expiration_date ASC, s.id`
 
-	rows, err := r.db.QueryContext(ctx, query, days)
+	rows, err := r.db.QueryContext(ctx, query, cutoff.UTC().Format(time.RFC3339), r.vault, r.vault)
 	if err != nil {
 		return nil, fmt.Errorf("querying expiring stocks: %w", err)
 	}
-	defer rows.Close()
-
-	var stocks []*models.ResourceStock
-	for rows.Next() {
-		stock, err := r.scanStockWithItemRow(rows)
-		if err != nil {
-			return nil, err
-		}
-		stocks = append(stocks, stock)
-	}
-	return stocks, rows.Err()
+	return collect(rows, r.scanStockWithItem)
 }
 
-// GetTotalStockByItem returns total quantity for an item.
+// GetTotalStockByItem returns the quantity of an item available for use:
+// available stock less what active reservations hold.
 func (r *ResourceRepository) GetTotalStockByItem(ctx context.Context, itemID string) (float64, error) {
 	query := `
 		SELECT COALESCE(SUM(quantity - quantity_reserved), 0)
 		FROM resource_stocks
-		WHERE item_id = ? AND status = 'AVAILABLE'`
+		WHERE item_id = ? AND status = 'AVAILABLE' AND ` + vaultCondition
 
 	var total float64
-	err := r.db.QueryRowContext(ctx, query, itemID).Scan(&total)
+	err := r.stmts.queryRow(ctx, query, itemID, r.vault, r.vault).Scan(&total)
 	return total, err
 }
 
@@ -466,15 +657,39 @@ func (r *ResourceRepository) CreateTransaction(ctx context.Context, tx *sql.Tx,
 		txn.CreatedAt.Format(time.RFC3339),
 	)
 	if err != nil {
-		return fmt.Errorf("inserting transaction: %w", err)
+		return fmt.Errorf("inserting transaction: %w", constraintError(err))
 	}
 	return nil
 }
 
-// ListTransactions retrieves transactions with filtering and pagination.
+// transactionSortColumns maps models.TransactionSortColumns to SQL.
+var transactionSortColumns = sortColumns{
+	"timestamp":        "t.timestamp",
+	"item_code":        "COALESCE(i.item_code, '')",
+	"transaction_type": "t.transaction_type",
+	"quantity":         "t.quantity",
+}
+
+// transactionOrder is the keyset order of transaction lists.
+var transactionOrder = keysetOrder{
+	from:        "resource_transactions t LEFT JOIN resource_items i ON t.item_id = i.id",
+	id:          "t.id",
+	defaultKeys: []string{"t.timestamp", "t.id"},
+	defaultDesc: true,
+	tiebreak:    "t.id",
+}
+
+// ListTransactions retrieves transactions with filtering and pagination, most
+// recent first unless page.Sort picks one of models.TransactionSortColumns.
+// It supports keyset pagination through page.After.
 func (r *ResourceRepository) ListTransactions(ctx context.Context, filter models.TransactionFilter, page models.Pagination) (*models.TransactionList, error) {
-	var conditions []string
-	var args []any
+	after, orderBy, err := transactionSortColumns.keyset(page.Sort, transactionOrder, page.After)
+	if err != nil {
+		return nil, err
+	}
+
+	conditions := []string{vaultTransactionCondition}
+	args := []any{r.vault, r.vault}
 
 	if filter.ItemID != "" {
 		conditions = append(conditions, "t.item_id = ?")
@@ -505,19 +720,24 @@ func (r *ResourceRepository) ListTransactions(ctx context.Context, filter models
 		args = append(args, filter.RelatedEntityID)
 	}
 
-	whereClause := ""
-	if len(conditions) > 0 {
-		whereClause = "WHERE " + strings.Join(conditions, " AND ")
-	}
+	whereClause := "WHERE " + strings.Join(conditions, " AND ")
 
 	// Count total
 	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM resource_transactions t %s", whereClause)
 	var total int
-	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+	if err := r.stmts.queryRow(ctx, countQuery, args...).Scan(&total); err != nil {
 		return nil, fmt.Errorf("counting transactions: %w", err)
 	}
 
-	// Get page
+	// Get page, continuing after the cursor row or skipping earlier pages,
+	// with one extra row to tell whether another page follows
+	limit := "LIMIT ? OFFSET ?"
+	if after != "" {
+		conditions = append(conditions, after)
+		args = append(args, page.After)
+		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+		limit = "LIMIT ?"
+	}
 	query := fmt.Sprintf(`
 		SELECT t.id, t.stock_id, t.item_id, t.transaction_type, t.quantity,
 			t.balance_after, t.reason, t.authorized_by, t.related_entity_type,
@@ -526,23 +746,26 @@ func (r *ResourceRepository) ListTransactions(ctx context.Context, filter models
 		FROM resource_transactions t
 		LEFT JOIN resource_items i ON t.item_id = i.id
 		%s
-		ORDER BY t.timestamp DESC
-		LIMIT ? OFFSET ?`, whereClause)
+		%s
+		%s`, whereClause, orderBy, limit)
 
-	args = append(args, page.Limit(), page.Offset())
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	args = append(args, page.Limit()+1)
+	if after == "" {
+		args = append(args, page.Offset())
+	}
+	rows, err := r.stmts.query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("querying transactions: %w", err)
 	}
-	defer rows.Close()
+	transactions, err := collect(rows, r.scanTransaction)
+	if err != nil {
+		return nil, err
+	}
 
-	var transactions []*models.ResourceTransaction
-	for rows.Next() {
-		txn, err := r.scanTransactionRow(rows)
-		if err != nil {
-			return nil, err
-		}
-		transactions = append(transactions, txn)
+	var next string
+	if len(transactions) > page.Limit() {
+		transactions = transactions[:page.Limit()]
+		next = transactions[len(transactions)-1].ID
 	}
 
 	return &models.TransactionList{
@@ -550,20 +773,22 @@ func (r *ResourceRepository) ListTransactions(ctx context.Context, filter models
 		Total:        total,
 		Page:         page.Page,
 		TotalPages:   page.TotalPages(total),
-	}, rows.Err()
+		NextCursor:   next,
+	}, nil
 }
 
 // GetDailyConsumption calculates daily consumption for an item over a period.
 func (r *ResourceRepository) GetDailyConsumption(ctx context.Context, itemID string, days int) (float64, error) {
 	query := `
 		SELECT COALESCE(SUM(ABS(quantity)), 0)
-		FROM resource_transactions
+		FROM resource_transactions t
 		WHERE item_id = ?
 		  AND transaction_type = 'CONSUMPTION'
-		  AND timestamp >= date('now', '-' || ? || ' days')`
+		  AND timestamp >= date('now', '-' || ? || ' days')
+		  AND ` + vaultTransactionCondition
 
 	var totalConsumed float64
-	err := r.db.QueryRowContext(ctx, query, itemID, days).Scan(&totalConsumed)
+	err := r.db.QueryRowContext(ctx, query, itemID, days, r.vault, r.vault).Scan(&totalConsumed)
 	if err != nil {
 		return 0, err
 	}
@@ -574,6 +799,268 @@ func (r *ResourceRepository) GetDailyConsumption(ctx context.Context, itemID str
 	return 0, nil
 }
 
+// GetConsumptionByCategoryDay returns the quantity of each resource
+// category consumed on each day in [from, to), keyed by category code and
+// then by date (YYYY-MM-DD). Days without consumption are left out.
+func (r *ResourceRepository) GetConsumptionByCategoryDay(ctx context.Context, from, to time.Time) (map[string]map[string]float64, error) {
+	query := `
+		SELECT c.code, date(t.timestamp), SUM(ABS(t.quantity))
+		FROM resource_transactions t
+		JOIN resource_items i ON i.id = t.item_id
+		JOIN resource_categories c ON c.id = i.category_id
+		WHERE t.transaction_type = 'CONSUMPTION'
+		  AND t.timestamp >= ? AND t.timestamp < ?
+		  AND ` + vaultTransactionCondition + `
+		GROUP BY c.code, date(t.timestamp)`
+
+	rows, err := r.db.QueryContext(ctx, query,
+		from.UTC().Format(time.RFC3339), to.UTC().Format(time.RFC3339), r.vault, r.vault)
+	if err != nil {
+		return nil, fmt.Errorf("querying consumption by category: %w", err)
+	}
+	defer rows.Close()
+
+	consumed := make(map[string]map[string]float64)
+	for rows.Next() {
+		var code, day string
+		var quantity float64
+		if err := rows.Scan(&code, &day, &quantity); err != nil {
+			return nil, fmt.Errorf("scanning consumption: %w", err)
+		}
+		if consumed[code] == nil {
+			consumed[code] = make(map[string]float64)
+		}
+		consumed[code][day] = quantity
+	}
+	return consumed, rows.Err()
+}
+
+// GetAvailableStockByCategory returns the quantity of each resource
+// category available for use, keyed by category code: available stock less
+// what active reservations hold.
+func (r *ResourceRepository) GetAvailableStockByCategory(ctx context.Context) (map[string]float64, error) {
+	query := `
+		SELECT c.code, COALESCE(SUM(s.quantity - s.quantity_reserved), 0)
+		FROM resource_stocks s
+		JOIN resource_items i ON i.id = s.item_id
+		JOIN resource_categories c ON c.id = i.category_id
+		WHERE s.status = 'AVAILABLE' AND ` + vaultCondition + `
+		GROUP BY c.code`
+
+	rows, err := r.db.QueryContext(ctx, query, r.vault, r.vault)
+	if err != nil {
+		return nil, fmt.Errorf("querying stock by category: %w", err)
+	}
+	defer rows.Close()
+
+	stock := make(map[string]float64)
+	for rows.Next() {
+		var code string
+		var quantity float64
+		if err := rows.Scan(&code, &quantity); err != nil {
+			return nil, fmt.Errorf("scanning stock: %w", err)
+		}
+		stock[code] = quantity
+	}
+	return stock, rows.Err()
+}
+
+// ============================================================================
+// RESERVATIONS
+// ============================================================================
+
+// CreateReservation inserts a new stock reservation and its allocations.
+// Callers adjust each allocated stock's quantity_reserved in the same
+// transaction.
+func (r *ResourceRepository) CreateReservation(ctx context.Context, tx *sql.Tx, res *models.StockReservation) error {
+	if err := res.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	query := `
+		INSERT INTO stock_reservations (
+			id, item_id, quantity, purpose, related_entity_type, related_entity_id,
+			reserved_by, status, expires_at, closed_at, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	execer := r.getExecer(tx)
+	now := time.Now().UTC()
+	res.CreatedAt = now
+	res.UpdatedAt = now
+
+	_, err := execer.ExecContext(ctx, query,
+		res.ID,
+		res.ItemID,
+		res.Quantity,
+		res.Purpose,
+		res.RelatedEntityType,
+		res.RelatedEntityID,
+		res.ReservedBy,
+		string(res.Status),
+		res.ExpiresAt.UTC().Format(time.RFC3339),
+		nullableTimePtrRFC3339(res.ClosedAt),
+		res.CreatedAt.Format(time.RFC3339),
+		res.UpdatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("inserting reservation: %w", constraintError(err))
+	}
+
+	for _, a := range res.Allocations {
+		_, err := execer.ExecContext(ctx, `
+			INSERT INTO stock_reservation_allocations (reservation_id, stock_id, quantity)
+			VALUES (?, ?, ?)`,
+			res.ID, a.StockID, a.Quantity,
+		)
+		if err != nil {
+			return fmt.Errorf("inserting reservation allocation: %w", constraintError(err))
+		}
+	}
+	return nil
+}
+
+// GetReservation retrieves a reservation and its allocations by ID, within
+// tx if given.
+func (r *ResourceRepository) GetReservation(ctx context.Context, tx *sql.Tx, id string) (*models.StockReservation, error) {
+	query := reservationSelect + " WHERE id = ?"
+
+	res, err := r.scanReservation(r.getQuerier(tx).QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("reservation %w: %s", ErrNotFound, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.loadAllocations(ctx, tx, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// UpdateReservationStatus records the status an active reservation closed
+// with and its closing time. A reservation closed already fails with
+// ErrValidation, so it is committed or released once.
+func (r *ResourceRepository) UpdateReservationStatus(ctx context.Context, tx *sql.Tx, res *models.StockReservation) error {
+	if !res.Status.Valid() {
+		return fmt.Errorf("%w: invalid status: %s", ErrValidation, res.Status)
+	}
+
+	execer := r.getExecer(tx)
+	res.UpdatedAt = time.Now().UTC()
+
+	result, err := execer.ExecContext(ctx, `
+		UPDATE stock_reservations SET status = ?, closed_at = ?, updated_at = ?
+		WHERE id = ? AND status = 'ACTIVE'`,
+		string(res.Status),
+		nullableTimePtrRFC3339(res.ClosedAt),
+		res.UpdatedAt.Format(time.RFC3339),
+		res.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("updating reservation: %w", constraintError(err))
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows != 1 {
+		return fmt.Errorf("%w: reservation %s is not active", ErrValidation, res.ID)
+	}
+	return nil
+}
+
+// ListActiveReservations retrieves active reservations, soonest expiry
+// first, optionally limited to one item.
+func (r *ResourceRepository) ListActiveReservations(ctx context.Context, itemID string) ([]*models.StockReservation, error) {
+	query := reservationSelect + " WHERE status = 'ACTIVE'"
+	var args []any
+	if itemID != "" {
+		query += " AND item_id = ?"
+		args = append(args, itemID)
+	}
+	query += " ORDER BY expires_at"
+
+	return r.listReservations(ctx, query, args...)
+}
+
+// ListExpiredReservations retrieves active reservations that expired at or
+// before asOf.
+func (r *ResourceRepository) ListExpiredReservations(ctx context.Context, asOf time.Time) ([]*models.StockReservation, error) {
+	query := reservationSelect + " WHERE status = 'ACTIVE' AND expires_at <= ? ORDER BY expires_at"
+
+	return r.listReservations(ctx, query, asOf.UTC().Format(time.RFC3339))
+}
+
+const reservationSelect = `
+	SELECT id, item_id, quantity, purpose, related_entity_type, related_entity_id,
+		reserved_by, status, expires_at, closed_at, created_at, updated_at
+	FROM stock_reservations`
+
+func (r *ResourceRepository) listReservations(ctx context.Context, query string, args ...any) ([]*models.StockReservation, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying reservations: %w", err)
+	}
+	reservations, err := collect(rows, r.scanReservation)
+	if err != nil {
+		return nil, err
+	}
+
+	// Allocations are loaded once the reservation rows have released the
+	// connection.
+	for _, res := range reservations {
+		if err := r.loadAllocations(ctx, nil, res); err != nil {
+			return nil, err
+		}
+	}
+	return reservations, nil
+}
+
+func (r *ResourceRepository) loadAllocations(ctx context.Context, tx *sql.Tx, res *models.StockReservation) error {
+	rows, err := r.getQuerier(tx).QueryContext(ctx, `
+		SELECT stock_id, quantity FROM stock_reservation_allocations
+		WHERE reservation_id = ?
+		ORDER BY rowid`, res.ID)
+	if err != nil {
+		return fmt.Errorf("querying reservation allocations: %w", err)
+	}
+	res.Allocations, err = collect(rows, func(row rowScanner) (models.ReservationAllocation, error) {
+		var a models.ReservationAllocation
+		if err := row.Scan(&a.StockID, &a.Quantity); err != nil {
+			return a, fmt.Errorf("scanning reservation allocation: %w", err)
+		}
+		return a, nil
+	})
+	return err
+}
+
+// scanReservation scans a reservation, without its allocations, from a
+// single row or a rows iterator.
+func (r *ResourceRepository) scanReservation(row rowScanner) (*models.StockReservation, error) {
+	var res models.StockReservation
+	var relType, relID, reservedBy, closedStr sql.NullString
+	var expiresStr, createdStr, updatedStr string
+
+	err := row.Scan(
+		&res.ID, &res.ItemID, &res.Quantity, &res.Purpose, &relType, &relID,
+		&reservedBy, &res.Status, &expiresStr, &closedStr, &createdStr, &updatedStr,
+	)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scanning reservation: %w", err)
+	}
+
+	res.RelatedEntityType = stringPtr(relType)
+	res.RelatedEntityID = stringPtr(relID)
+	res.ReservedBy = stringPtr(reservedBy)
+	res.ExpiresAt = parseTime(time.RFC3339, expiresStr)
+	res.ClosedAt = timePtr(time.RFC3339, closedStr)
+	res.CreatedAt = parseTime(time.RFC3339, createdStr)
+	res.UpdatedAt = parseTime(time.RFC3339, updatedStr)
+	return &res, nil
+}
+
 // ============================================================================
 // HELPERS
 // ============================================================================
@@ -587,7 +1074,18 @@ func (r *ResourceRepository) getExecer(tx *sql.Tx) interface {
 	return r.db
 }
 
-func (r *ResourceRepository) scanCategory(row *sql.Row) (*models.ResourceCategory, error) {
+func (r *ResourceRepository) getQuerier(tx *sql.Tx) interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+} {
+	if tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+// scanCategory scans a category from a single row or a rows iterator.
+func (r *ResourceRepository) scanCategory(row rowScanner) (*models.ResourceCategory, error) {
 	var cat models.ResourceCategory
 	var desc sql.NullString
 	var createdStr string
@@ -598,142 +1096,93 @@ func (r *ResourceRepository) scanCategory(row *sql.Row) (*models.ResourceCategor
 		&isConsumable, &isCritical, &createdStr,
 	)
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("category not found")
+		return nil, fmt.Errorf("category %w", ErrNotFound)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("scanning category: %w", err)
 	}
 
-	if desc.Valid {
-		cat.Description = desc.String
-	}
+	cat.Description = desc.String
 	cat.IsConsumable = isConsumable == 1
 	cat.IsCritical = isCritical == 1
-	cat.CreatedAt, _ = time.Parse(time.RFC3339, createdStr)
-
+	cat.CreatedAt = parseTime(time.RFC3339, createdStr)
 	return &cat, nil
 }
 
-func (r *ResourceRepository) scanCategoryRow(rows *sql.Rows) (*models.ResourceCategory, error) {
-	var cat models.ResourceCategory
-	var desc sql.NullString
-	var createdStr string
-	var isConsumable, isCritical int
-
-	err := rows.Scan(
-		&cat.ID, &cat.Code, &cat.Name, &desc, &cat.UnitOfMeasure,
-		&isConsumable, &isCritical, &createdStr,
-	)
-	if err != nil {
-		return nil, fmt.Errorf("scanning category row: %w", err)
-	}
-
-	if desc.Valid {
-		cat.Description = desc.String
+// scanItem scans an item from a single row or a rows iterator.
+func (r *ResourceRepository) scanItem(row rowScanner) (*models.ResourceItem, error) {
+	var item models.ResourceItem
+	if err := r.scanItemInto(row, &item); err != nil {
+		return nil, err
 	}
-	cat.IsConsumable = isConsumable == 1
-	cat.IsCritical = isCritical == 1
-	cat.CreatedAt, _ = time.Parse(time.RFC3339, createdStr)
-
-	return &cat, nil
+	return &item, nil
 }
 
-func (r *ResourceRepository) scanItemWithCategory(row *sql.Row) (*models.ResourceItem, error) {
+// scanItemWithCategory scans an item from a row that ends with the columns
+// of its category.
+func (r *ResourceRepository) scanItemWithCategory(row rowScanner) (*models.ResourceItem, error) {
 	var item models.ResourceItem
 	var cat models.ResourceCategory
-	var itemDesc, storageReq sql.NullString
-	var calories, prodRate sql.NullFloat64
-	var shelfLife sql.NullInt64
-	var isProducible int
-	var createdStr, updatedStr string
 	var catDesc sql.NullString
 	var catCreatedStr string
 	var catConsumable, catCritical int
 
-	err := row.Scan(
-		&item.ID, &item.CategoryID, &item.ItemCode, &item.Name, &itemDesc, &item.UnitOfMeasure,
-		&calories, &shelfLife, &storageReq, &isProducible, &prodRate, &createdStr, &updatedStr,
+	err := r.scanItemInto(row, &item,
 		&cat.ID, &cat.Code, &cat.Name, &catDesc, &cat.UnitOfMeasure,
 		&catConsumable, &catCritical, &catCreatedStr,
 	)
-	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("item not found")
-	}
 	if err != nil {
-		return nil, fmt.Errorf("scanning item: %w", err)
-	}
-
-	if itemDesc.Valid {
-		item.Description = itemDesc.String
-	}
-	if calories.Valid {
-		item.CaloriesPerUnit = &calories.Float64
+		return nil, err
 	}
-	if shelfLife.Valid {
-		v := int(shelfLife.Int64)
-		item.ShelfLifeDays = &v
-	}
-	if storageReq.Valid {
-		item.StorageRequirements = storageReq.String
-	}
-	item.IsProducible = isProducible == 1
-	if prodRate.Valid {
-		item.ProductionRatePerDay = &prodRate.Float64
-	}
-	item.CreatedAt, _ = time.Parse(time.RFC3339, createdStr)
-	item.UpdatedAt, _ = time.Parse(time.RFC3339, updatedStr)
 
-	if catDesc.Valid {
-		cat.Description = catDesc.String
-	}
+	cat.Description = catDesc.String
 	cat.IsConsumable = catConsumable == 1
 	cat.IsCritical = catCritical == 1
-	cat.CreatedAt, _ = time.Parse(time.RFC3339, catCreatedStr)
+	cat.CreatedAt = parseTime(time.RFC3339, catCreatedStr)
 	item.Category = &cat
-
 	return &item, nil
 }
 
-func (r *ResourceRepository) scanItemRow(rows *sql.Rows) (*models.ResourceItem, error) {
-	var item models.ResourceItem
+// scanItemInto scans the item columns of a row, followed by any extra
+// columns, into item.
+func (r *ResourceRepository) scanItemInto(row rowScanner, item *models.ResourceItem, extra ...any) error {
 	var itemDesc, storageReq sql.NullString
-	var calories, prodRate sql.NullFloat64
+	var calories, prodRate, minStock, targetStock sql.NullFloat64
 	var shelfLife sql.NullInt64
 	var isProducible int
+	var nutrients nutrientColumns
 	var createdStr, updatedStr string
 
-	err := rows.Scan(
+	dest := append([]any{
 		&item.ID, &item.CategoryID, &item.ItemCode, &item.Name, &itemDesc, &item.UnitOfMeasure,
-		&calories, &shelfLife, &storageReq, &isProducible, &prodRate, &createdStr, &updatedStr,
-	)
+		&calories, &shelfLife, &storageReq, &isProducible, &prodRate, &minStock, &targetStock,
+	}, nutrients.dest()...)
+	dest = append(append(dest, &createdStr, &updatedStr), extra...)
+	err := row.Scan(dest...)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("item %w", ErrNotFound)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("scanning item row: %w", err)
+		return fmt.Errorf("scanning item: %w", err)
 	}
 
-	if itemDesc.Valid {
-		item.Description = itemDesc.String
-	}
-	if calories.Valid {
-		item.CaloriesPerUnit = &calories.Float64
-	}
-	if shelfLife.Valid {
-		v := int(shelfLife.Int64)
-		item.ShelfLifeDays = &v
-	}
-	if storageReq.Valid {
-		item.StorageRequirements = storageReq.String
-	}
+	item.Description = itemDesc.String
+	item.CaloriesPerUnit = floatPtr(calories)
+	item.ShelfLifeDays = intPtr(shelfLife)
+	item.StorageRequirements = storageReq.String
 	item.IsProducible = isProducible == 1
-	if prodRate.Valid {
-		item.ProductionRatePerDay = &prodRate.Float64
-	}
-	item.CreatedAt, _ = time.Parse(time.RFC3339, createdStr)
-	item.UpdatedAt, _ = time.Parse(time.RFC3339, updatedStr)
-
-	return &item, nil
+	item.ProductionRatePerDay = floatPtr(prodRate)
+	item.MinStock = floatPtr(minStock)
+	item.TargetStock = floatPtr(targetStock)
+	item.Nutrients = nutrients.nutrients()
+	item.CreatedAt = parseTime(time.RFC3339, createdStr)
+	item.UpdatedAt = parseTime(time.RFC3339, updatedStr)
+	return nil
 }
 
-func (r *ResourceRepository) scanStockWithItem(row *sql.Row) (*models.ResourceStock, error) {
+// scanStockWithItem scans a stock from a single row or a rows iterator
+// whose columns end with a summary of its item.
+func (r *ResourceRepository) scanStockWithItem(row rowScanner) (*models.ResourceStock, error) {
 	var stock models.ResourceStock
 	var item models.ResourceItem
 	var lotNum, expDate, auditDate, auditBy sql.NullString
@@ -742,109 +1191,52 @@ func (r *ResourceRepository) scanStockWithItem(row *sql.Row) (*models.ResourceSt
 	err := row.Scan(
 		&stock.ID, &stock.ItemID, &lotNum, &stock.Quantity, &stock.QuantityReserved,
 		&stock.StorageLocation, &receivedStr, &expDate, &stock.Status,
-		&auditDate, &auditBy, &createdStr, &updatedStr,
+		&auditDate, &auditBy, &stock.VaultID, &createdStr, &updatedStr,
 		&item.ID, &item.CategoryID, &item.ItemCode, &item.Name, &item.UnitOfMeasure,
 	)
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("stock not found")
+		return nil, fmt.Errorf("stock %w", ErrNotFound)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("scanning stock: %w", err)
 	}
 
-	if lotNum.Valid {
-		stock.LotNumber = &lotNum.String
-	}
-	stock.ReceivedDate, _ = time.Parse(time.RFC3339, receivedStr)
-	if expDate.Valid {
-		t, _ := time.Parse(time.RFC3339, expDate.String)
-		stock.ExpirationDate = &t
-	}
-	if auditDate.Valid {
-		t, _ := time.Parse(time.RFC3339, auditDate.String)
-		stock.LastAuditDate = &t
-	}
-	if auditBy.Valid {
-		stock.LastAuditBy = &auditBy.String
-	}
-	stock.CreatedAt, _ = time.Parse(time.RFC3339, createdStr)
-	stock.UpdatedAt, _ = time.Parse(time.RFC3339, updatedStr)
+	stock.LotNumber = stringPtr(lotNum)
+	stock.ReceivedDate = parseTime(time.RFC3339, receivedStr)
+	stock.ExpirationDate = timePtr(time.RFC3339, expDate)
+	stock.LastAuditDate = timePtr(time.RFC3339, auditDate)
+	stock.LastAuditBy = stringPtr(auditBy)
+	stock.CreatedAt = parseTime(time.RFC3339, createdStr)
+	stock.UpdatedAt = parseTime(time.RFC3339, updatedStr)
 	stock.Item = &item
-
 	return &stock, nil
 }
 
-func (r *ResourceRepository) scanStockWithItemRow(rows *sql.Rows) (*models.ResourceStock, error) {
-	var stock models.ResourceStock
-	var item models.ResourceItem
-	var lotNum, expDate, auditDate, auditBy sql.NullString
-	var receivedStr, createdStr, updatedStr string
-
-	err := rows.Scan(
-		&stock.ID, &stock.ItemID, &lotNum, &stock.Quantity, &stock.QuantityReserved,
-		&stock.StorageLocation, &receivedStr, &expDate, &stock.Status,
-		&auditDate, &auditBy, &createdStr, &updatedStr,
-		&item.ID, &item.CategoryID, &item.ItemCode, &item.Name, &item.UnitOfMeasure,
-	)
-	if err != nil {
-		return nil, fmt.Errorf("scanning stock row: %w", err)
-	}
-
-	if lotNum.Valid {
-		stock.LotNumber = &lotNum.String
-	}
-	stock.ReceivedDate, _ = time.Parse(time.RFC3339, receivedStr)
-	if expDate.Valid {
-		t, _ := time.Parse(time.RFC3339, expDate.String)
-		stock.ExpirationDate = &t
-	}
-	if auditDate.Valid {
-		t, _ := time.Parse(time.RFC3339, auditDate.String)
-		stock.LastAuditDate = &t
-	}
-	if auditBy.Valid {
-		stock.LastAuditBy = &auditBy.String
-	}
-	stock.CreatedAt, _ = time.Parse(time.RFC3339, createdStr)
-	stock.UpdatedAt, _ = time.Parse(time.RFC3339, updatedStr)
-	stock.Item = &item
-
-	return &stock, nil
-}
-
-func (r *ResourceRepository) scanTransactionRow(rows *sql.Rows) (*models.ResourceTransaction, error) {
+// scanTransaction scans a transaction from a rows iterator whose columns
+// end with its item's code and name.
+func (r *ResourceRepository) scanTransaction(row rowScanner) (*models.ResourceTransaction, error) {
 	var txn models.ResourceTransaction
 	var stockID, reason, authBy, relType, relID sql.NullString
 	var timestampStr, createdStr string
 	var itemCode, itemName sql.NullString
 
-	err := rows.Scan(
+	err := row.Scan(
 		&txn.ID, &stockID, &txn.ItemID, &txn.TransactionType, &txn.Quantity,
 		&txn.BalanceAfter, &reason, &authBy, &relType, &relID,
 		&timestampStr, &createdStr,
 		&itemCode, &itemName,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("scanning transaction row: %w", err)
+		return nil, fmt.Errorf("scanning transaction: %w", err)
 	}
 
-	if stockID.Valid {
-		txn.StockID = &stockID.String
-	}
-	if reason.Valid {
-		txn.Reason = reason.String
-	}
-	if authBy.Valid {
-		txn.AuthorizedBy = &authBy.String
-	}
-	if relType.Valid {
-		txn.RelatedEntityType = &relType.String
-	}
-	if relID.Valid {
-		txn.RelatedEntityID = &relID.String
-	}
-	txn.Timestamp, _ = time.Parse(time.RFC3339, timestampStr)
-	txn.CreatedAt, _ = time.Parse(time.RFC3339, createdStr)
+	txn.StockID = stringPtr(stockID)
+	txn.Reason = reason.String
+	txn.AuthorizedBy = stringPtr(authBy)
+	txn.RelatedEntityType = stringPtr(relType)
+	txn.RelatedEntityID = stringPtr(relID)
+	txn.Timestamp = parseTime(time.RFC3339, timestampStr)
+	txn.CreatedAt = parseTime(time.RFC3339, createdStr)
 
 	if itemCode.Valid && itemName.Valid {
 		txn.Item = &models.ResourceItem{
@@ -852,20 +1244,5 @@ func (r *ResourceRepository) scanTransactionRow(rows *sql.Rows) (*models.Resourc
 			Name:     itemName.String,
 		}
 	}
-
 	return &txn, nil
 }
-
-func boolToInt(b bool) int {
-	if b {
-		return 1
-	}
-	return 0
-}
-
-func nullableTimePtrRFC3339(t *time.Time) sql.NullString {
-	if t == nil {
-		return sql.NullString{}
-	}
-	return sql.NullString{String: t.Format(time.RFC3339), Valid: true}
-}
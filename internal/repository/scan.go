@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/vaultcrypt"
+)
+
+// rowScanner is a single row or a rows iterator, so one scan function serves
+// both lookups and lists.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// collect scans every row of rows with scan and closes rows.
+func collect[T any](rows *sql.Rows, scan func(rowScanner) (T, error)) ([]T, error) {
+	defer rows.Close()
+
+	var items []T
+	for rows.Next() {
+		item, err := scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating rows: %w", err)
+	}
+	return items, nil
+}
+
+// parseTime parses a stored timestamp or date. Columns are written by the
+// repositories in a known layout, so a malformed value reads as the zero
+// time rather than failing the whole row.
+func parseTime(layout, s string) time.Time {
+	t, _ := time.Parse(layout, s)
+	return t
+}
+
+// timePtr parses a nullable timestamp or date column, nil when NULL.
+func timePtr(layout string, s sql.NullString) *time.Time {
+	if !s.Valid {
+		return nil
+	}
+	t := parseTime(layout, s.String)
+	return &t
+}
+
+// stringPtr returns the value of a nullable text column, nil when NULL.
+func stringPtr(s sql.NullString) *string {
+	if !s.Valid {
+		return nil
+	}
+	return &s.String
+}
+
+// floatPtr returns the value of a nullable real column, nil when NULL.
+func floatPtr(f sql.NullFloat64) *float64 {
+	if !f.Valid {
+		return nil
+	}
+	return &f.Float64
+}
+
+// nutrientColumns holds optional nutrients as their protein, carbohydrate,
+// fat and vitamin columns.
+type nutrientColumns [4]sql.NullFloat64
+
+// nullableNutrients stores optional nutrients, all four columns NULL when n
+// is nil.
+func nullableNutrients(n *models.Nutrients) nutrientColumns {
+	if n == nil {
+		return nutrientColumns{}
+	}
+	return nutrientColumns{
+		{Float64: n.ProteinG, Valid: true},
+		{Float64: n.CarbsG, Valid: true},
+		{Float64: n.FatG, Valid: true},
+		{Float64: n.VitaminsPct, Valid: true},
+	}
+}
+
+// dest returns the scan destinations of the columns.
+func (c *nutrientColumns) dest() []any {
+	return []any{&c[0], &c[1], &c[2], &c[3]}
+}
+
+// nutrients returns the nutrients stored in the columns, nil when all are
+// NULL.
+func (c *nutrientColumns) nutrients() *models.Nutrients {
+	if !c[0].Valid && !c[1].Valid && !c[2].Valid && !c[3].Valid {
+		return nil
+	}
+	return &models.Nutrients{ProteinG: c[0].Float64, CarbsG: c[1].Float64, FatG: c[2].Float64, VitaminsPct: c[3].Float64}
+}
+
+// intPtr returns the value of a nullable integer column, nil when NULL.
+func intPtr(n sql.NullInt64) *int {
+	if !n.Valid {
+		return nil
+	}
+	v := int(n.Int64)
+	return &v
+}
+
+// boolPtr returns the value of a nullable 0/1 column, nil when NULL.
+func boolPtr(n sql.NullInt64) *bool {
+	if !n.Valid {
+		return nil
+	}
+	v := n.Int64 == 1
+	return &v
+}
+
+// nullableString stores an empty string as NULL.
+func nullableString(s string) sql.NullString {
+	if s == "" {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: s, Valid: true}
+}
+
+// sealedString stores the value of an encrypted column, "table.column",
+// encrypted when encryption at rest is enabled, or NULL when empty.
+func sealedString(column, s string) (sql.NullString, error) {
+	sealed, err := vaultcrypt.Seal(column, s)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("encrypting %s: %w", column, err)
+	}
+	return nullableString(sealed), nil
+}
+
+// openString reads the value of an encrypted column, decrypting it.
+func openString(column string, ns sql.NullString) (string, error) {
+	if !ns.Valid {
+		return "", nil
+	}
+	s, err := vaultcrypt.Open(column, ns.String)
+	if err != nil {
+		return "", fmt.Errorf("decrypting %s: %w", column, err)
+	}
+	return s, nil
+}
+
+// nullableTime stores a date, or NULL when t is nil.
+func nullableTime(t *time.Time) sql.NullString {
+	if t == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: t.Format(time.DateOnly), Valid: true}
+}
+
+// nullableTimePtrRFC3339 stores a timestamp, or NULL when t is nil.
+func nullableTimePtrRFC3339(t *time.Time) sql.NullString {
+	if t == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: t.Format(time.RFC3339), Valid: true}
+}
+
+// nullableBool stores a flag as 0/1, or NULL when b is nil.
+func nullableBool(b *bool) sql.NullInt64 {
+	if b == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: int64(boolToInt(*b)), Valid: true}
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,249 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/testutil"
+)
+
+func TestCollect(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close(t)
+
+	ctx := context.Background()
+	scanInt := func(row rowScanner) (int, error) {
+		var n int
+		err := row.Scan(&n)
+		return n, err
+	}
+
+	t.Run("Scans every row in order", func(t *testing.T) {
+		rows, err := db.QueryContext(ctx, "SELECT column1 FROM (VALUES (3), (1), (2))")
+		if err != nil {
+			t.Fatalf("query failed: %v", err)
+		}
+		got, err := collect(rows, scanInt)
+		if err != nil {
+			t.Fatalf("collect failed: %v", err)
+		}
+		if !reflect.DeepEqual(got, []int{3, 1, 2}) {
+			t.Errorf("expected [3 1 2], got %v", got)
+		}
+	})
+
+	t.Run("Returns nil for no rows", func(t *testing.T) {
+		rows, err := db.QueryContext(ctx, "SELECT 1 WHERE 0")
+		if err != nil {
+			t.Fatalf("query failed: %v", err)
+		}
+		got, err := collect(rows, scanInt)
+		if err != nil {
+			t.Fatalf("collect failed: %v", err)
+		}
+		if got != nil {
+			t.Errorf("expected nil, got %v", got)
+		}
+	})
+
+	t.Run("Stops at a scan error and closes the rows", func(t *testing.T) {
+		failure := errors.New("bad row")
+		rows, err := db.QueryContext(ctx, "SELECT column1 FROM (VALUES (1), (2))")
+		if err != nil {
+			t.Fatalf("query failed: %v", err)
+		}
+		_, err = collect(rows, func(row rowScanner) (int, error) {
+			return 0, failure
+		})
+		if !errors.Is(err, failure) {
+			t.Fatalf("expected scan error, got %v", err)
+		}
+		if rows.Next() {
+			t.Error("expected rows to be closed")
+		}
+	})
+}
+
+func TestNullableScanHelpers(t *testing.T) {
+	at := time.Date(2077, 10, 23, 9, 47, 0, 0, time.UTC)
+
+	if got := stringPtr(sql.NullString{}); got != nil {
+		t.Errorf("stringPtr(NULL) = %v, want nil", *got)
+	}
+	if got := stringPtr(sql.NullString{String: "x", Valid: true}); got == nil || *got != "x" {
+		t.Errorf("stringPtr(x) = %v, want x", got)
+	}
+
+	if got := timePtr(time.RFC3339, sql.NullString{}); got != nil {
+		t.Errorf("timePtr(NULL) = %v, want nil", *got)
+	}
+	got := timePtr(time.RFC3339, sql.NullString{String: at.Format(time.RFC3339), Valid: true})
+	if got == nil || !got.Equal(at) {
+		t.Errorf("timePtr = %v, want %v", got, at)
+	}
+	if got := parseTime(time.DateOnly, "not a date"); !got.IsZero() {
+		t.Errorf("parseTime(malformed) = %v, want zero time", got)
+	}
+
+	if got := floatPtr(sql.NullFloat64{Float64: 2.5, Valid: true}); got == nil || *got != 2.5 {
+		t.Errorf("floatPtr(2.5) = %v, want 2.5", got)
+	}
+	if got := intPtr(sql.NullInt64{Int64: 7, Valid: true}); got == nil || *got != 7 {
+		t.Errorf("intPtr(7) = %v, want 7", got)
+	}
+	if got := boolPtr(sql.NullInt64{Int64: 0, Valid: true}); got == nil || *got {
+		t.Errorf("boolPtr(0) = %v, want false", got)
+	}
+	if floatPtr(sql.NullFloat64{}) != nil || intPtr(sql.NullInt64{}) != nil || boolPtr(sql.NullInt64{}) != nil {
+		t.Error("expected nil for NULL columns")
+	}
+}
+
+// Lookups and lists share a scanner per entity; these check that a row reads
+// back the same either way, with nullable columns both set and NULL.
+
+func TestScan_ResidentLookupMatchesList(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close(t)
+
+	repo := NewResidentRepository(db.DB)
+	ctx := context.Background()
+
+	parent := testutil.FixtureResident()
+	deceased := testutil.FixtureDeceasedResident(func(r *models.Resident) {
+		r.BiologicalParent1ID = &parent.ID
+		r.BloodType = models.BloodTypeONeg
+		r.Notes = "Lost in the reactor breach"
+	})
+	for _, r := range []*models.Resident{parent, deceased} {
+		if err := repo.Create(ctx, nil, r); err != nil {
+			t.Fatalf("failed to create resident: %v", err)
+		}
+	}
+
+	list, err := repo.List(ctx, models.ResidentFilter{}, models.Pagination{Page: 1, PageSize: 10})
+	if err != nil {
+		t.Fatalf("failed to list residents: %v", err)
+	}
+	if len(list.Residents) != 2 {
+		t.Fatalf("expected 2 residents, got %d", len(list.Residents))
+	}
+	for _, listed := range list.Residents {
+		found, err := repo.GetByID(ctx, listed.ID)
+		if err != nil {
+			t.Fatalf("failed to get resident: %v", err)
+		}
+		if !reflect.DeepEqual(found, listed) {
+			t.Errorf("lookup and list differ:\n%+v\n%+v", found, listed)
+		}
+	}
+
+	found, err := repo.GetByID(ctx, deceased.ID)
+	if err != nil {
+		t.Fatalf("failed to get resident: %v", err)
+	}
+	if found.DateOfDeath == nil || found.BiologicalParent1ID == nil || *found.BiologicalParent1ID != parent.ID {
+		t.Errorf("expected date of death and parent to be set, got %+v", found)
+	}
+	if found.BiologicalParent2ID != nil || found.HouseholdID != nil {
+		t.Errorf("expected NULL columns to read as nil, got %+v", found)
+	}
+	if found.BloodType != models.BloodTypeONeg || found.Notes != deceased.Notes {
+		t.Errorf("expected blood type and notes to round-trip, got %+v", found)
+	}
+}
+
+func TestScan_HouseholdLookupMatchesList(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close(t)
+
+	repo := NewHouseholdRepository(db.DB)
+	ctx := context.Background()
+
+	for _, h := range []*models.Household{testutil.FixtureHousehold(), testutil.FixtureDissolvedHousehold()} {
+		if err := repo.Create(ctx, nil, h); err != nil {
+			t.Fatalf("failed to create household: %v", err)
+		}
+	}
+
+	list, err := repo.List(ctx, models.HouseholdFilter{}, models.Pagination{Page: 1, PageSize: 10})
+	if err != nil {
+		t.Fatalf("failed to list households: %v", err)
+	}
+	if len(list.Households) != 2 {
+		t.Fatalf("expected 2 households, got %d", len(list.Households))
+	}
+	for _, listed := range list.Households {
+		found, err := repo.GetByID(ctx, listed.ID)
+		if err != nil {
+			t.Fatalf("failed to get household: %v", err)
+		}
+		// Only lists count members
+		found.MemberCount = listed.MemberCount
+		if !reflect.DeepEqual(found, listed) {
+			t.Errorf("lookup and list differ:\n%+v\n%+v", found, listed)
+		}
+		if (listed.Status == models.HouseholdStatusDissolved) != (listed.DissolvedDate != nil) {
+			t.Errorf("expected dissolved date only on the dissolved household, got %+v", listed)
+		}
+	}
+}
+
+func TestScan_ItemLookupMatchesList(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close(t)
+
+	repo := NewResourceRepository(db.DB)
+	ctx := context.Background()
+
+	cat := testutil.FixtureResourceCategory()
+	if err := repo.CreateCategory(ctx, nil, cat); err != nil {
+		t.Fatalf("failed to create category: %v", err)
+	}
+	withNulls := testutil.FixtureResourceItem(cat.ID, func(i *models.ResourceItem) {
+		i.ItemCode = "TOOL-WRENCH-001"
+		i.CaloriesPerUnit = nil
+		i.Nutrients = nil
+		i.ShelfLifeDays = nil
+	})
+	for _, i := range []*models.ResourceItem{testutil.FixtureResourceItem(cat.ID), withNulls} {
+		if err := repo.CreateItem(ctx, nil, i); err != nil {
+			t.Fatalf("failed to create item: %v", err)
+		}
+	}
+
+	list, err := repo.ListItems(ctx, cat.ID, models.Pagination{Page: 1, PageSize: 10})
+	if err != nil {
+		t.Fatalf("failed to list items: %v", err)
+	}
+	if len(list.Items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(list.Items))
+	}
+	for _, listed := range list.Items {
+		found, err := repo.GetItem(ctx, listed.ID)
+		if err != nil {
+			t.Fatalf("failed to get item: %v", err)
+		}
+		if found.Category == nil || found.Category.ID != cat.ID {
+			t.Errorf("expected lookup to load the category, got %+v", found.Category)
+		}
+		// Only lookups load the category
+		found.Category = nil
+		if !reflect.DeepEqual(found, listed) {
+			t.Errorf("lookup and list differ:\n%+v\n%+v", found, listed)
+		}
+	}
+
+	found, err := repo.GetItem(ctx, withNulls.ID)
+	if err != nil {
+		t.Fatalf("failed to get item: %v", err)
+	}
+	if found.CaloriesPerUnit != nil || found.Nutrients != nil || found.ShelfLifeDays != nil {
+		t.Errorf("expected NULL columns to read as nil, got %+v", found)
+	}
+}
@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// SecurityRepository handles security incident data access.
+type SecurityRepository struct {
+	db *sql.DB
+}
+
+// NewSecurityRepository creates a new security repository.
+func NewSecurityRepository(db *sql.DB) *SecurityRepository {
+	return &SecurityRepository{db: db}
+}
+
+// CreateIncident inserts a new security incident.
+func (r *SecurityRepository) CreateIncident(ctx context.Context, tx *sql.Tx, inc *models.SecurityIncident) error {
+	if err := inc.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	query := `
+		INSERT INTO security_incidents (
+			id, incident_number, incident_type, severity, description,
+			location_sector, location_detail, reported_by, status,
+			occurred_at, reported_at, notes, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	var execer interface {
+		ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	}
+	if tx != nil {
+		execer = tx
+	} else {
+		execer = r.db
+	}
+
+	now := time.Now().UTC()
+	inc.CreatedAt = now
+	inc.UpdatedAt = now
+
+	_, err := execer.ExecContext(ctx, query,
+		inc.ID,
+		inc.IncidentNumber,
+		string(inc.IncidentType),
+		string(inc.Severity),
+		inc.Description,
+		nullableString(inc.LocationSector),
+		nullableString(inc.LocationDetail),
+		inc.ReportedBy,
+		string(inc.Status),
+		inc.OccurredAt.Format(time.RFC3339),
+		inc.ReportedAt.Format(time.RFC3339),
+		nullableString(inc.Notes),
+		inc.CreatedAt.Format(time.RFC3339),
+		inc.UpdatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("inserting security incident: %w", constraintError(err))
+	}
+
+	return nil
+}
+
+// GetNextIncidentNumber returns the next incident number for the given year,
+// in the form INC-YYYY-NNNN.
+func (r *SecurityRepository) GetNextIncidentNumber(ctx context.Context, year int) (string, error) {
+	prefix := fmt.Sprintf("INC-%04d-", year)
+
+	query := `
+		SELECT incident_number FROM security_incidents
+		WHERE incident_number LIKE ?
+		ORDER BY incident_number DESC
+		LIMIT 1`
+
+	var last string
+	err := r.db.QueryRowContext(ctx, query, prefix+"%").Scan(&last)
+	if err == sql.ErrNoRows {
+		return prefix + "0001", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("querying last incident number: %w", err)
+	}
+
+	var seq int
+	if _, err := fmt.Sscanf(last[len(prefix):], "%d", &seq); err != nil {
+		return "", fmt.Errorf("parsing incident number %s: %w", last, err)
+	}
+
+	return fmt.Sprintf("%s%04d", prefix, seq+1), nil
+}
+
+// GetIncidentByNumber retrieves an incident by its incident number.
+func (r *SecurityRepository) GetIncidentByNumber(ctx context.Context, number string) (*models.SecurityIncident, error) {
+	query := `
+		SELECT id, incident_number, incident_type, severity, description,
+			location_sector, location_detail, reported_by, status,
+			occurred_at, reported_at, notes, created_at, updated_at
+		FROM security_incidents
+		WHERE incident_number = ?`
+
+	inc, err := r.scanIncident(r.db.QueryRowContext(ctx, query, number))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("security incident %w: %s", ErrNotFound, number)
+	}
+	return inc, err
+}
+
+// ListOpenIncidents retrieves every incident not yet resolved or closed,
+// most severe first, then oldest first.
+func (r *SecurityRepository) ListOpenIncidents(ctx context.Context) ([]*models.SecurityIncident, error) {
+	query := `
+		SELECT id, incident_number, incident_type, severity, description,
+			location_sector, location_detail, reported_by, status,
+			occurred_at, reported_at, notes, created_at, updated_at
+		FROM security_incidents
+		WHERE status NOT IN ('RESOLVED', 'CLOSED')
+		ORDER BY CASE severity
+				WHEN 'CRITICAL' THEN 0 WHEN 'MAJOR' THEN 1 WHEN 'MODERATE' THEN 2 ELSE 3
+			END, occurred_at, incident_number`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("querying open incidents: %w", err)
+	}
+	return collect(rows, r.scanIncident)
+}
+
+// scanIncident scans an incident from a single row or a rows iterator.
+func (r *SecurityRepository) scanIncident(row rowScanner) (*models.SecurityIncident, error) {
+	var inc models.SecurityIncident
+	var sector, detail, reportedBy, notes sql.NullString
+	var occurredStr, reportedStr, createdStr, updatedStr string
+
+	err := row.Scan(
+		&inc.ID, &inc.IncidentNumber, &inc.IncidentType, &inc.Severity, &inc.Description,
+		&sector, &detail, &reportedBy, &inc.Status,
+		&occurredStr, &reportedStr, &notes, &createdStr, &updatedStr,
+	)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scanning security incident: %w", err)
+	}
+
+	inc.LocationSector = sector.String
+	inc.LocationDetail = detail.String
+	if reportedBy.Valid {
+		inc.ReportedBy = &reportedBy.String
+	}
+	inc.Notes = notes.String
+	inc.OccurredAt = parseTime(time.RFC3339, occurredStr)
+	inc.ReportedAt = parseTime(time.RFC3339, reportedStr)
+	inc.CreatedAt = parseTime(time.RFC3339, createdStr)
+	inc.UpdatedAt = parseTime(time.RFC3339, updatedStr)
+	return &inc, nil
+}
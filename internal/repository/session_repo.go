@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// SessionRepository handles terminal session data access.
+type SessionRepository struct {
+	db    *sql.DB
+	vault int // Vault lists are limited to, 0 for every vault
+}
+
+// NewSessionRepository creates a new session repository.
+func NewSessionRepository(db *sql.DB) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+// ForVault returns a copy of the repository whose lists are limited to
+// sessions of the vault, and which starts sessions there.
+func (r *SessionRepository) ForVault(vault int) *SessionRepository {
+	scoped := *r
+	scoped.vault = vault
+	return &scoped
+}
+
+// Create inserts a new session.
+func (r *SessionRepository) Create(ctx context.Context, s *models.TerminalSession) error {
+	if err := s.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	s.CreatedAt = time.Now().UTC()
+	if s.VaultID == 0 {
+		s.VaultID = r.vault
+	}
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO terminal_sessions (
+			id, operator_id, started_at, last_active_at, ended_at, modules,
+			mutations, vault_id, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		s.ID,
+		s.OperatorID,
+		s.StartedAt.UTC().Format(time.RFC3339),
+		s.LastActiveAt.UTC().Format(time.RFC3339),
+		nullableTimePtrRFC3339(s.EndedAt),
+		strings.Join(s.Modules, ","),
+		s.Mutations,
+		s.VaultID,
+		s.CreatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("inserting session: %w", constraintError(err))
+	}
+	return nil
+}
+
+// Update saves a session's modules, changes, last activity and end.
+func (r *SessionRepository) Update(ctx context.Context, s *models.TerminalSession) error {
+	if err := s.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE terminal_sessions
+		SET last_active_at = ?, ended_at = ?, modules = ?, mutations = ?
+		WHERE id = ?`,
+		s.LastActiveAt.UTC().Format(time.RFC3339),
+		nullableTimePtrRFC3339(s.EndedAt),
+		strings.Join(s.Modules, ","),
+		s.Mutations,
+		s.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("updating session: %w", constraintError(err))
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("session %w: %s", ErrNotFound, s.ID)
+	}
+	return nil
+}
+
+// ListActiveSince retrieves the sessions last active at or after since,
+// latest started first, with their operator's registry number and name.
+func (r *SessionRepository) ListActiveSince(ctx context.Context, since time.Time) ([]*models.TerminalSession, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT s.id, s.operator_id, COALESCE(o.registry_number, ''),
+			COALESCE(o.surname || ', ' || o.given_names, ''),
+			s.started_at, s.last_active_at, s.ended_at, s.modules, s.mutations,
+			s.vault_id, s.created_at
+		FROM terminal_sessions s
+		LEFT JOIN residents o ON o.id = s.operator_id
+		WHERE (? = 0 OR s.vault_id = ?) AND s.last_active_at >= ?
+		ORDER BY s.started_at DESC, s.id`,
+		r.vault, r.vault, since.UTC().Format(time.RFC3339))
+	if err != nil {
+		return nil, fmt.Errorf("querying sessions: %w", err)
+	}
+	return collect(rows, r.scanSession)
+}
+
+// scanSession scans a session from a rows iterator.
+func (r *SessionRepository) scanSession(row rowScanner) (*models.TerminalSession, error) {
+	var s models.TerminalSession
+	var operatorID, endedStr sql.NullString
+	var startedStr, activeStr, modules, createdStr string
+
+	err := row.Scan(
+		&s.ID, &operatorID, &s.OperatorRegistry, &s.OperatorName,
+		&startedStr, &activeStr, &endedStr, &modules, &s.Mutations,
+		&s.VaultID, &createdStr,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("scanning session: %w", err)
+	}
+
+	s.OperatorID = stringPtr(operatorID)
+	s.StartedAt = parseTime(time.RFC3339, startedStr)
+	s.LastActiveAt = parseTime(time.RFC3339, activeStr)
+	s.EndedAt = timePtr(time.RFC3339, endedStr)
+	if modules != "" {
+		s.Modules = strings.Split(modules, ",")
+	}
+	s.CreatedAt = parseTime(time.RFC3339, createdStr)
+	return &s, nil
+}
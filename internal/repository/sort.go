@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// sortColumns maps the sort keys a list accepts to SQL expressions. Only
+// these expressions are ever written into a query.
+type sortColumns map[string]string
+
+// orderBy builds the ORDER BY clause for a list query. An unset sort gives
+// defaultOrder; otherwise the sort column is followed by tiebreak so pages
+// stay stable. Sort keys not in columns are rejected with ErrValidation.
+func (c sortColumns) orderBy(sort models.SortOption, defaultOrder, tiebreak string) (string, error) {
+	if !sort.IsSet() {
+		return "ORDER BY " + defaultOrder, nil
+	}
+
+	expr, err := c.column(sort)
+	if err != nil {
+		return "", err
+	}
+	direction := models.SortAsc
+	if sort.Descending() {
+		direction = models.SortDesc
+	}
+	return fmt.Sprintf("ORDER BY %s %s, %s", expr, direction, tiebreak), nil
+}
+
+// column returns the SQL expression for the sort column.
+func (c sortColumns) column(sort models.SortOption) (string, error) {
+	expr, ok := c[sort.Column]
+	if !ok {
+		return "", fmt.Errorf("%w: cannot sort by %q", ErrValidation, sort.Column)
+	}
+	return expr, nil
+}
+
+// keysetOrder describes the order of a list that supports keyset pagination.
+// Every key runs in one direction and the last is unique, so the position
+// after a row can be compared as a row value.
+type keysetOrder struct {
+	from        string   // FROM clause the keys are evaluated against
+	id          string   // Row ID column within from
+	defaultKeys []string // Keys when no sort is set
+	defaultDesc bool
+	tiebreak    string // Unique key following a sort column
+}
+
+// keyset builds the ORDER BY clause for a keyset-paginated list and, when
+// after names a row ID, the condition selecting the rows that follow it. The
+// condition takes after as its only argument. A row that no longer exists
+// ends the list.
+func (c sortColumns) keyset(sort models.SortOption, order keysetOrder, after string) (cond, orderBy string, err error) {
+	keys, desc := order.defaultKeys, order.defaultDesc
+	if sort.IsSet() {
+		expr, err := c.column(sort)
+		if err != nil {
+			return "", "", err
+		}
+		keys, desc = []string{expr, order.tiebreak}, sort.Descending()
+	}
+
+	direction, cmp := models.SortAsc, ">"
+	if desc {
+		direction, cmp = models.SortDesc, "<"
+	}
+	terms := make([]string, len(keys))
+	for i, k := range keys {
+		terms[i] = fmt.Sprintf("%s %s", k, direction)
+	}
+	orderBy = "ORDER BY " + strings.Join(terms, ", ")
+
+	if after != "" {
+		list := strings.Join(keys, ", ")
+		cond = fmt.Sprintf("(%s) %s (SELECT %s FROM %s WHERE %s = ?)", list, cmp, list, order.from, order.id)
+	}
+	return cond, orderBy, nil
+}
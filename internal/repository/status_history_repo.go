@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// StatusHistoryRepository handles scheduled resident status data access.
+type StatusHistoryRepository struct {
+	db *sql.DB
+}
+
+// NewStatusHistoryRepository creates a new status history repository.
+func NewStatusHistoryRepository(db *sql.DB) *StatusHistoryRepository {
+	return &StatusHistoryRepository{db: db}
+}
+
+// Create inserts a new status period. A resident with an open period cannot
+// start another; that is reported as ErrDuplicate.
+func (r *StatusHistoryRepository) Create(ctx context.Context, tx *sql.Tx, t *models.StatusTransition) error {
+	if err := t.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	query := `
+		INSERT INTO status_history (
+			id, resident_id, status, reason, started_at, expected_end, auto_revert,
+			prompted_at, ended_at, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	execer := r.getExecer(tx)
+	now := time.Now().UTC()
+	t.CreatedAt = now
+	t.UpdatedAt = now
+
+	_, err := execer.ExecContext(ctx, query,
+		t.ID,
+		t.ResidentID,
+		string(t.Status),
+		nullableString(t.Reason),
+		t.StartedAt.UTC().Format(time.RFC3339),
+		t.ExpectedEnd.Format(time.DateOnly),
+		boolToInt(t.AutoRevert),
+		nullableTimePtrRFC3339(t.PromptedAt),
+		nullableTimePtrRFC3339(t.EndedAt),
+		t.CreatedAt.Format(time.RFC3339),
+		t.UpdatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("inserting status history: %w", constraintError(err))
+	}
+	return nil
+}
+
+// GetOpen retrieves a resident's open status period.
+func (r *StatusHistoryRepository) GetOpen(ctx context.Context, residentID string) (*models.StatusTransition, error) {
+	query := statusHistorySelect + " WHERE resident_id = ? AND ended_at IS NULL"
+
+	t, err := r.scan(r.db.QueryRowContext(ctx, query, residentID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("open status period %w: resident %s", ErrNotFound, residentID)
+	}
+	return t, err
+}
+
+// ListByResident retrieves a resident's status periods, most recent first.
+func (r *StatusHistoryRepository) ListByResident(ctx context.Context, residentID string) ([]*models.StatusTransition, error) {
+	query := statusHistorySelect + " WHERE resident_id = ? ORDER BY started_at DESC"
+	return r.list(ctx, query, residentID)
+}
+
+// ListDue retrieves open periods whose expected end date is before the date
+// of asOf, earliest first.
+func (r *StatusHistoryRepository) ListDue(ctx context.Context, asOf time.Time) ([]*models.StatusTransition, error) {
+	query := statusHistorySelect + " WHERE ended_at IS NULL AND expected_end < ? ORDER BY expected_end, started_at"
+	return r.list(ctx, query, asOf.Format(time.DateOnly))
+}
+
+// MarkPrompted records that the operator was asked to confirm the end of a
+// period.
+func (r *StatusHistoryRepository) MarkPrompted(ctx context.Context, tx *sql.Tx, id string, at time.Time) error {
+	return r.setTime(ctx, tx, id, "prompted_at", at)
+}
+
+// End closes a status period.
+func (r *StatusHistoryRepository) End(ctx context.Context, tx *sql.Tx, id string, at time.Time) error {
+	return r.setTime(ctx, tx, id, "ended_at", at)
+}
+
+// SetAutoRevert makes an open status period return its resident to ACTIVE
+// without asking the operator once it is due.
+func (r *StatusHistoryRepository) SetAutoRevert(ctx context.Context, tx *sql.Tx, id string) error {
+	result, err := r.getExecer(tx).ExecContext(ctx,
+		"UPDATE status_history SET auto_revert = 1, updated_at = ? WHERE id = ? AND ended_at IS NULL",
+		time.Now().UTC().Format(time.RFC3339),
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("updating status history: %w", constraintError(err))
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("open status period %w: %s", ErrNotFound, id)
+	}
+	return nil
+}
+
+func (r *StatusHistoryRepository) setTime(ctx context.Context, tx *sql.Tx, id, column string, at time.Time) error {
+	query := fmt.Sprintf("UPDATE status_history SET %s = ?, updated_at = ? WHERE id = ?", column)
+
+	result, err := r.getExecer(tx).ExecContext(ctx, query,
+		at.UTC().Format(time.RFC3339),
+		time.Now().UTC().Format(time.RFC3339),
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("updating status history: %w", constraintError(err))
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("status period %w: %s", ErrNotFound, id)
+	}
+	return nil
+}
+
+const statusHistorySelect = `
+	SELECT id, resident_id, status, reason, started_at, expected_end, auto_revert,
+		prompted_at, ended_at, created_at, updated_at
+	FROM status_history`
+
+func (r *StatusHistoryRepository) list(ctx context.Context, query string, args ...any) ([]*models.StatusTransition, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying status history: %w", err)
+	}
+	return collect(rows, r.scan)
+}
+
+func (r *StatusHistoryRepository) getExecer(tx *sql.Tx) interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+} {
+	if tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+// scan scans a status period from a single row or a rows iterator.
+func (r *StatusHistoryRepository) scan(row rowScanner) (*models.StatusTransition, error) {
+	var t models.StatusTransition
+	var reason, prompted, ended sql.NullString
+	var startedStr, endStr, createdStr, updatedStr string
+	var autoRevert int
+
+	err := row.Scan(
+		&t.ID, &t.ResidentID, &t.Status, &reason, &startedStr, &endStr, &autoRevert,
+		&prompted, &ended, &createdStr, &updatedStr,
+	)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scanning status history: %w", err)
+	}
+
+	t.Reason = reason.String
+	t.StartedAt = parseTime(time.RFC3339, startedStr)
+	t.ExpectedEnd = parseTime(time.DateOnly, endStr)
+	t.AutoRevert = autoRevert == 1
+	t.PromptedAt = timePtr(time.RFC3339, prompted)
+	t.EndedAt = timePtr(time.RFC3339, ended)
+	t.CreatedAt = parseTime(time.RFC3339, createdStr)
+	t.UpdatedAt = parseTime(time.RFC3339, updatedStr)
+	return &t, nil
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+)
+
+// maxCachedStmts bounds a statement cache. List queries are built from
+// filters and sort keys, so their text varies; past the bound, further
+// queries run unprepared.
+const maxCachedStmts = 128
+
+// stmtCache holds prepared statements keyed by query text, so repeated reads
+// skip SQLite's parse and plan step. Statements live as long as the database
+// handle.
+type stmtCache struct {
+	db    *sql.DB
+	mu    sync.Mutex
+	stmts map[string]*sql.Stmt
+}
+
+func newStmtCache(db *sql.DB) *stmtCache {
+	return &stmtCache{db: db, stmts: make(map[string]*sql.Stmt)}
+}
+
+// prepare returns the cached statement for query, preparing it on first use.
+// It returns nil when the cache is full.
+func (c *stmtCache) prepare(ctx context.Context, query string) (*sql.Stmt, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if stmt, ok := c.stmts[query]; ok {
+		return stmt, nil
+	}
+	if len(c.stmts) >= maxCachedStmts {
+		return nil, nil
+	}
+	stmt, err := c.db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	c.stmts[query] = stmt
+	return stmt, nil
+}
+
+// queryRow runs a single-row query through its prepared statement. A query
+// that fails to prepare runs directly, so the error surfaces from Scan.
+func (c *stmtCache) queryRow(ctx context.Context, query string, args ...any) *sql.Row {
+	stmt, err := c.prepare(ctx, query)
+	if err != nil || stmt == nil {
+		return c.db.QueryRowContext(ctx, query, args...)
+	}
+	return stmt.QueryRowContext(ctx, args...)
+}
+
+// query runs a query through its prepared statement.
+func (c *stmtCache) query(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	stmt, err := c.prepare(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if stmt == nil {
+		return c.db.QueryContext(ctx, query, args...)
+	}
+	return stmt.QueryContext(ctx, args...)
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// TagRepository handles resident tag data access.
+type TagRepository struct {
+	db    *sql.DB
+	vault int // Vault tag counts are limited to, 0 for every vault
+}
+
+// NewTagRepository creates a new tag repository.
+func NewTagRepository(db *sql.DB) *TagRepository {
+	return &TagRepository{db: db}
+}
+
+// ForVault returns a copy of the repository whose tag counts are limited to
+// residents of the vault.
+func (r *TagRepository) ForVault(vault int) *TagRepository {
+	scoped := *r
+	scoped.vault = vault
+	return &scoped
+}
+
+// Add tags a resident. Tags the resident already carries, in any case, are
+// left as they are.
+func (r *TagRepository) Add(ctx context.Context, tx *sql.Tx, residentID string, tags []string) error {
+	now := time.Now().UTC().Format(time.RFC3339)
+	for _, tag := range tags {
+		_, err := r.getExecer(tx).ExecContext(ctx, `
+			INSERT INTO resident_tags (resident_id, tag, created_at) VALUES (?, ?, ?)
+			ON CONFLICT (resident_id, tag) DO NOTHING`,
+			residentID, tag, now)
+		if err != nil {
+			return fmt.Errorf("inserting resident tag: %w", constraintError(err))
+		}
+	}
+	return nil
+}
+
+// Remove takes tags off a resident, regardless of case. Tags the resident
+// does not carry are ignored.
+func (r *TagRepository) Remove(ctx context.Context, tx *sql.Tx, residentID string, tags []string) error {
+	for _, tag := range tags {
+		_, err := r.getExecer(tx).ExecContext(ctx,
+			`DELETE FROM resident_tags WHERE resident_id = ? AND tag = ?`, residentID, tag)
+		if err != nil {
+			return fmt.Errorf("deleting resident tag: %w", err)
+		}
+	}
+	return nil
+}
+
+// ListByResident retrieves a resident's tags in alphabetical order.
+func (r *TagRepository) ListByResident(ctx context.Context, residentID string) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT tag FROM resident_tags WHERE resident_id = ? ORDER BY tag`, residentID)
+	if err != nil {
+		return nil, fmt.Errorf("querying resident tags: %w", err)
+	}
+	return collect(rows, func(row rowScanner) (string, error) {
+		var tag string
+		if err := row.Scan(&tag); err != nil {
+			return "", fmt.Errorf("scanning resident tag: %w", err)
+		}
+		return tag, nil
+	})
+}
+
+// ListCounts retrieves every tag in use with the number of residents
+// carrying it, most used first.
+func (r *TagRepository) ListCounts(ctx context.Context) ([]*models.TagCount, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT MIN(t.tag), COUNT(*)
+		FROM resident_tags t
+		JOIN residents r ON r.id = t.resident_id
+		WHERE (? = 0 OR r.vault_id = ?)
+		GROUP BY t.tag
+		ORDER BY COUNT(*) DESC, MIN(t.tag)`,
+		r.vault, r.vault)
+	if err != nil {
+		return nil, fmt.Errorf("querying tags: %w", err)
+	}
+	return collect(rows, func(row rowScanner) (*models.TagCount, error) {
+		var c models.TagCount
+		if err := row.Scan(&c.Tag, &c.Residents); err != nil {
+			return nil, fmt.Errorf("scanning tag: %w", err)
+		}
+		return &c, nil
+	})
+}
+
+func (r *TagRepository) getExecer(tx *sql.Tx) interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+} {
+	if tx != nil {
+		return tx
+	}
+	return r.db
+}
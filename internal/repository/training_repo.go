@@ -0,0 +1,281 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// TrainingRepository handles training program and apprenticeship data
+// access.
+type TrainingRepository struct {
+	db    *sql.DB
+	vault int
+}
+
+// NewTrainingRepository creates a new training repository.
+func NewTrainingRepository(db *sql.DB) *TrainingRepository {
+	return &TrainingRepository{db: db}
+}
+
+// ForVault returns a copy of the repository whose apprenticeship lists are
+// limited to apprentices of the vault.
+func (r *TrainingRepository) ForVault(vault int) *TrainingRepository {
+	scoped := *r
+	scoped.vault = vault
+	return &scoped
+}
+
+// ============================================================================
+// PROGRAMS
+// ============================================================================
+
+// SetProgram sets a vocation's training program. An earlier program of the
+// vocation keeps its ID and takes the new hours and description.
+func (r *TrainingRepository) SetProgram(ctx context.Context, tx *sql.Tx, p *models.TrainingProgram) error {
+	if err := p.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	now := time.Now().UTC()
+	p.CreatedAt = now
+	p.UpdatedAt = now
+
+	_, err := r.getExecer(tx).ExecContext(ctx, `
+		INSERT INTO training_programs (
+			id, vocation_id, required_hours, hours_per_day, description, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (vocation_id) DO UPDATE SET
+			required_hours = excluded.required_hours,
+			hours_per_day = excluded.hours_per_day,
+			description = excluded.description,
+			updated_at = excluded.updated_at`,
+		p.ID,
+		p.VocationID,
+		p.RequiredHours,
+		p.HoursPerDay,
+		nullableString(p.Description),
+		p.CreatedAt.Format(time.RFC3339),
+		p.UpdatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("setting training program: %w", constraintError(err))
+	}
+	return nil
+}
+
+// GetProgram retrieves a vocation's training program.
+func (r *TrainingRepository) GetProgram(ctx context.Context, vocationID string) (*models.TrainingProgram, error) {
+	p, err := r.scanProgram(r.db.QueryRowContext(ctx, programSelect+" WHERE vocation_id = ?", vocationID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("training program %w: vocation %s", ErrNotFound, vocationID)
+	}
+	return p, err
+}
+
+// ============================================================================
+// APPRENTICESHIPS
+// ============================================================================
+
+// Create inserts a new apprenticeship. A second active apprenticeship of
+// the same resident is reported as ErrDuplicate.
+func (r *TrainingRepository) Create(ctx context.Context, tx *sql.Tx, a *models.Apprenticeship) error {
+	if err := a.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	now := time.Now().UTC()
+	a.CreatedAt = now
+	a.UpdatedAt = now
+
+	_, err := r.getExecer(tx).ExecContext(ctx, `
+		INSERT INTO apprenticeships (
+			id, resident_id, vocation_id, mentor_id, status, enrolled_at,
+			required_hours, hours_per_day, hours_completed, signed_off_by,
+			ended_at, notes, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		a.ID,
+		a.ResidentID,
+		a.VocationID,
+		a.MentorID,
+		string(a.Status),
+		a.EnrolledAt.Format(time.RFC3339),
+		a.RequiredHours,
+		a.HoursPerDay,
+		a.HoursCompleted,
+		a.SignedOffBy,
+		nullableTimePtrRFC3339(a.EndedAt),
+		nullableString(a.Notes),
+		a.CreatedAt.Format(time.RFC3339),
+		a.UpdatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("inserting apprenticeship: %w", constraintError(err))
+	}
+	return nil
+}
+
+// GetByID retrieves an apprenticeship by ID.
+func (r *TrainingRepository) GetByID(ctx context.Context, id string) (*models.Apprenticeship, error) {
+	a, err := r.scan(r.db.QueryRowContext(ctx, apprenticeshipSelect+" WHERE a.id = ?", id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("apprenticeship %w: %s", ErrNotFound, id)
+	}
+	return a, err
+}
+
+// ListActive retrieves the active apprenticeships, earliest enrolled
+// first. With trainingOnly set, apprentices who are not ACTIVE residents,
+// e.g. on a surface mission or in quarantine, are left out.
+func (r *TrainingRepository) ListActive(ctx context.Context, trainingOnly bool) ([]*models.Apprenticeship, error) {
+	query := apprenticeshipSelect + `
+		JOIN residents r ON r.id = a.resident_id
+		WHERE a.status = 'ACTIVE' AND ` + vaultCondition
+	if trainingOnly {
+		query += " AND r.status = 'ACTIVE'"
+	}
+	query += " ORDER BY a.enrolled_at, a.id"
+
+	rows, err := r.db.QueryContext(ctx, query, r.vault, r.vault)
+	if err != nil {
+		return nil, fmt.Errorf("querying apprenticeships: %w", err)
+	}
+	return collect(rows, r.scan)
+}
+
+// UpdateHours records the hours an active apprentice has trained.
+func (r *TrainingRepository) UpdateHours(ctx context.Context, tx *sql.Tx, a *models.Apprenticeship) error {
+	a.UpdatedAt = time.Now().UTC()
+	_, err := r.getExecer(tx).ExecContext(ctx, `
+		UPDATE apprenticeships SET hours_completed = ?, updated_at = ?
+		WHERE id = ? AND status = 'ACTIVE'`,
+		a.HoursCompleted,
+		a.UpdatedAt.Format(time.RFC3339),
+		a.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("updating apprenticeship: %w", constraintError(err))
+	}
+	return nil
+}
+
+// SetMentor puts an active apprenticeship under a new mentor.
+func (r *TrainingRepository) SetMentor(ctx context.Context, tx *sql.Tx, id, mentorID string) error {
+	result, err := r.getExecer(tx).ExecContext(ctx, `
+		UPDATE apprenticeships SET mentor_id = ?, updated_at = ?
+		WHERE id = ? AND status = 'ACTIVE'`,
+		mentorID,
+		time.Now().UTC().Format(time.RFC3339),
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("updating apprenticeship: %w", constraintError(err))
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("active apprenticeship %w: %s", ErrNotFound, id)
+	}
+	return nil
+}
+
+// Close ends an active apprenticeship with its status, sign-off, end time
+// and notes.
+func (r *TrainingRepository) Close(ctx context.Context, tx *sql.Tx, a *models.Apprenticeship) error {
+	if err := a.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	a.UpdatedAt = time.Now().UTC()
+	result, err := r.getExecer(tx).ExecContext(ctx, `
+		UPDATE apprenticeships
+		SET status = ?, signed_off_by = ?, ended_at = ?, notes = ?, updated_at = ?
+		WHERE id = ? AND status = 'ACTIVE'`,
+		string(a.Status),
+		a.SignedOffBy,
+		nullableTimePtrRFC3339(a.EndedAt),
+		nullableString(a.Notes),
+		a.UpdatedAt.Format(time.RFC3339),
+		a.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("updating apprenticeship: %w", constraintError(err))
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("active apprenticeship %w: %s", ErrNotFound, a.ID)
+	}
+	return nil
+}
+
+const programSelect = `
+	SELECT id, vocation_id, required_hours, hours_per_day, description, created_at, updated_at
+	FROM training_programs`
+
+const apprenticeshipSelect = `
+	SELECT a.id, a.resident_id, a.vocation_id, a.mentor_id, a.status, a.enrolled_at,
+		a.required_hours, a.hours_per_day, a.hours_completed, a.signed_off_by,
+		a.ended_at, a.notes, a.created_at, a.updated_at
+	FROM apprenticeships a`
+
+func (r *TrainingRepository) getExecer(tx *sql.Tx) interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+} {
+	if tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+// scanProgram scans a training program from a single row or a rows
+// iterator.
+func (r *TrainingRepository) scanProgram(row rowScanner) (*models.TrainingProgram, error) {
+	var p models.TrainingProgram
+	var description sql.NullString
+	var createdStr, updatedStr string
+
+	err := row.Scan(&p.ID, &p.VocationID, &p.RequiredHours, &p.HoursPerDay, &description, &createdStr, &updatedStr)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scanning training program: %w", err)
+	}
+
+	p.Description = description.String
+	p.CreatedAt = parseTime(time.RFC3339, createdStr)
+	p.UpdatedAt = parseTime(time.RFC3339, updatedStr)
+	return &p, nil
+}
+
+// scan scans an apprenticeship from a single row or a rows iterator.
+func (r *TrainingRepository) scan(row rowScanner) (*models.Apprenticeship, error) {
+	var a models.Apprenticeship
+	var mentorID, signedOffBy, endedAt, notes sql.NullString
+	var enrolledStr, createdStr, updatedStr string
+
+	err := row.Scan(
+		&a.ID, &a.ResidentID, &a.VocationID, &mentorID, &a.Status, &enrolledStr,
+		&a.RequiredHours, &a.HoursPerDay, &a.HoursCompleted, &signedOffBy,
+		&endedAt, &notes, &createdStr, &updatedStr,
+	)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scanning apprenticeship: %w", err)
+	}
+
+	a.MentorID = stringPtr(mentorID)
+	a.EnrolledAt = parseTime(time.RFC3339, enrolledStr)
+	a.SignedOffBy = stringPtr(signedOffBy)
+	a.EndedAt = timePtr(time.RFC3339, endedAt)
+	a.Notes = notes.String
+	a.CreatedAt = parseTime(time.RFC3339, createdStr)
+	a.UpdatedAt = parseTime(time.RFC3339, updatedStr)
+	return &a, nil
+}
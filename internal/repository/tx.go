@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// WithTransaction runs fn as one unit of work. It opens a transaction, which
+// fn passes to every repository write, and commits when fn returns nil. An
+// error from fn, or a panic, rolls back every write made through tx.
+//
+// Repository reads use the connection pool rather than tx, and the database
+// allows a single connection, so do all reads before WithTransaction.
+func WithTransaction(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/vtuos/vtuos/internal/testutil"
+)
+
+func TestWithTransaction(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close(t)
+
+	repo := NewResidentRepository(db.DB)
+	ctx := context.Background()
+
+	t.Run("Commits on success", func(t *testing.T) {
+		resident := testutil.FixtureResident()
+
+		err := WithTransaction(ctx, db.DB, func(tx *sql.Tx) error {
+			return repo.Create(ctx, tx, resident)
+		})
+		if err != nil {
+			t.Fatalf("WithTransaction failed: %v", err)
+		}
+
+		if _, err := repo.GetByID(ctx, resident.ID); err != nil {
+			t.Errorf("expected committed resident, got %v", err)
+		}
+	})
+
+	t.Run("Rolls back every write on error", func(t *testing.T) {
+		first := testutil.FixtureResident()
+		second := testutil.FixtureResident()
+		failure := errors.New("step failed")
+
+		err := WithTransaction(ctx, db.DB, func(tx *sql.Tx) error {
+			if err := repo.Create(ctx, tx, first); err != nil {
+				return err
+			}
+			if err := repo.Create(ctx, tx, second); err != nil {
+				return err
+			}
+			return failure
+		})
+		if !errors.Is(err, failure) {
+			t.Fatalf("expected step error, got %v", err)
+		}
+
+		for _, r := range []string{first.ID, second.ID} {
+			if _, err := repo.GetByID(ctx, r); !errors.Is(err, ErrNotFound) {
+				t.Errorf("expected rolled back resident %s, got %v", r, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// vaultCondition is the filter limiting a query to one vault. It takes the
+// vault number twice, e.g. args = append(args, vault, vault); vault 0 leaves
+// the query across every vault.
+const vaultCondition = "(? = 0 OR vault_id = ?)"
+
+// vaultTransactionCondition limits resource transactions, aliased t, to
+// those against stock of one vault, taking the vault number twice like
+// vaultCondition.
+const vaultTransactionCondition = "(? = 0 OR t.stock_id IN (SELECT id FROM resource_stocks WHERE vault_id = ?))"
+
+// vaultSystemCondition limits rows keyed by system_id to those of facility
+// systems of one vault, taking the vault number twice like vaultCondition.
+const vaultSystemCondition = "(? = 0 OR system_id IN (SELECT id FROM facility_systems WHERE vault_id = ?))"
+
+// vaultAccessPointCondition limits access events, aliased e, to those at
+// access points of one vault, taking the vault number twice like
+// vaultCondition.
+const vaultAccessPointCondition = "(? = 0 OR e.access_point_id IN (SELECT id FROM access_points WHERE vault_id = ?))"
+
+// vaultScopedTables are the tables whose rows belong to one vault.
+var vaultScopedTables = []string{
+	"residents", "households", "resource_stocks", "facility_systems", "genetic_health_snapshots",
+	"access_points", "assets", "announcements", "environment_readings", "morale_snapshots", "venues",
+	"terminal_sessions", "alert_routes", "alerts", "census_presets", "dual_auth_policies",
+}
+
+// ClaimUnassigned assigns the rows of every vault-scoped table that belong
+// to no vault, e.g. rows written before multi-vault support, to the vault.
+// It returns the number of rows claimed.
+func ClaimUnassigned(ctx context.Context, db *sql.DB, vault int) (int64, error) {
+	if vault <= 0 {
+		return 0, fmt.Errorf("%w: vault number must be positive", ErrValidation)
+	}
+
+	var claimed int64
+	err := WithTransaction(ctx, db, func(tx *sql.Tx) error {
+		for _, table := range vaultScopedTables {
+			result, err := tx.ExecContext(ctx, `UPDATE `+table+` SET vault_id = ? WHERE vault_id = 0`, vault)
+			if err != nil {
+				return fmt.Errorf("claiming %s: %w", table, constraintError(err))
+			}
+			n, err := result.RowsAffected()
+			if err != nil {
+				return fmt.Errorf("claiming %s: %w", table, err)
+			}
+			claimed += n
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return claimed, nil
+}
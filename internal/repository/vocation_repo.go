@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// VocationRepository handles vocation data access.
+type VocationRepository struct {
+	db *sql.DB
+}
+
+// NewVocationRepository creates a new vocation repository.
+func NewVocationRepository(db *sql.DB) *VocationRepository {
+	return &VocationRepository{db: db}
+}
+
+// GetByID retrieves a vocation by ID.
+func (r *VocationRepository) GetByID(ctx context.Context, id string) (*models.Vocation, error) {
+	query := `
+		SELECT id, code, title, department, required_clearance, headcount_authorized,
+			headcount_minimum, shift_pattern, hazard_level, description, is_active,
+			created_at, updated_at
+		FROM vocations
+		WHERE id = ?`
+
+	return r.scanVocation(r.db.QueryRowContext(ctx, query, id))
+}
+
+// GetByCode retrieves a vocation by code.
+func (r *VocationRepository) GetByCode(ctx context.Context, code string) (*models.Vocation, error) {
+	query := `
+		SELECT id, code, title, department, required_clearance, headcount_authorized,
+			headcount_minimum, shift_pattern, hazard_level, description, is_active,
+			created_at, updated_at
+		FROM vocations
+		WHERE code = ?`
+
+	return r.scanVocation(r.db.QueryRowContext(ctx, query, code))
+}
+
+// List retrieves vocations ordered by department and code.
+func (r *VocationRepository) List(ctx context.Context, activeOnly bool) ([]*models.Vocation, error) {
+	query := `
+		SELECT id, code, title, department, required_clearance, headcount_authorized,
+			headcount_minimum, shift_pattern, hazard_level, description, is_active,
+			created_at, updated_at
+		FROM vocations`
+	if activeOnly {
+		query += " WHERE is_active = 1"
+	}
+	query += " ORDER BY department, code"
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("querying vocations: %w", err)
+	}
+	return collect(rows, r.scanVocation)
+}
+
+// scanVocation scans a vocation from a single row or a rows iterator.
+func (r *VocationRepository) scanVocation(row rowScanner) (*models.Vocation, error) {
+	var voc models.Vocation
+	var desc sql.NullString
+	var isActive int
+	var createdStr, updatedStr string
+
+	err := row.Scan(
+		&voc.ID, &voc.Code, &voc.Title, &voc.Department, &voc.RequiredClearance,
+		&voc.HeadcountAuthorized, &voc.HeadcountMinimum, &voc.ShiftPattern,
+		&voc.HazardLevel, &desc, &isActive, &createdStr, &updatedStr,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("vocation %w", ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scanning vocation: %w", err)
+	}
+
+	voc.Description = desc.String
+	voc.IsActive = isActive == 1
+	voc.CreatedAt = parseTime(time.RFC3339, createdStr)
+	voc.UpdatedAt = parseTime(time.RFC3339, updatedStr)
+	return &voc, nil
+}
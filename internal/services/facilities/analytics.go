@@ -0,0 +1,31 @@
+package facilities
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// MaintenanceAnalytics analyzes the work orders raised on each of the days
+// days up to and including the day of at: mean time to repair and
+// efficiency recovered per labor hour by category, and the systems ranked
+// by failures for a Pareto analysis.
+func (s *Service) MaintenanceAnalytics(ctx context.Context, at time.Time, days int) (*models.MaintenanceAnalytics, error) {
+	if days < 1 {
+		days = 1
+	}
+	to := at.UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+	from := to.AddDate(0, 0, -days)
+
+	systems, err := s.facilities.ListSystems(ctx, nil, nil, models.SortOption{})
+	if err != nil {
+		return nil, fmt.Errorf("listing systems: %w", err)
+	}
+	records, err := s.facilities.ListRaisedMaintenance(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return models.AnalyzeMaintenance(from, to, records, systems), nil
+}
@@ -0,0 +1,178 @@
+package facilities
+
+import (
+	"context"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/journal"
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// Journaled facility commands.
+const (
+	CommandSetSystemStatus     = "facilities.set_system_status"
+	CommandDeclareFlow         = "facilities.declare_flow"
+	CommandPlanMaintenance     = "facilities.plan_maintenance"
+	CommandDeclareConsumable   = "facilities.declare_consumable"
+	CommandCompleteMaintenance = "facilities.complete_maintenance"
+	CommandAddDependency       = "facilities.add_dependency"
+	CommandRemoveDependency    = "facilities.remove_dependency"
+	CommandSetTechnician       = "facilities.set_technician"
+	CommandAssignWorkOrder     = "facilities.assign_work_order"
+	CommandSetShedPriority     = "facilities.set_shed_priority"
+	CommandBalanceLoad         = "facilities.balance_load"
+	CommandRecordEnvironment   = "facilities.record_environment"
+	CommandAccrueRuntime       = "facilities.accrue_runtime"
+	CommandSetDutyCycle        = "facilities.set_duty_cycle"
+	CommandCommissionSystem    = "facilities.commission_system"
+	CommandDecommissionSystem  = "facilities.decommission_system"
+)
+
+// Arguments of journaled commands.
+type (
+	systemStatusArgs struct {
+		SystemCode string                `json:"system_code"`
+		Status     models.FacilityStatus `json:"status"`
+		Efficiency *float64              `json:"efficiency"`
+		At         time.Time             `json:"at"`
+		Reason     string                `json:"reason"`
+	}
+	declareFlowArgs struct {
+		SystemCode string               `json:"system_code"`
+		Grid       models.Grid          `json:"grid"`
+		Direction  models.FlowDirection `json:"direction"`
+		Rate       float64              `json:"rate"`
+	}
+	planMaintenanceArgs struct {
+		Now     time.Time `json:"now"`
+		Horizon time.Time `json:"horizon"`
+	}
+	declareConsumableArgs struct {
+		SystemCode   string  `json:"system_code"`
+		ItemCode     string  `json:"item_code"`
+		Quantity     float64 `json:"quantity"`
+		IntervalDays int     `json:"interval_days"`
+	}
+	completeMaintenanceArgs struct {
+		RecordID string                   `json:"record_id"`
+		Input    CompleteMaintenanceInput `json:"input"`
+	}
+	dependencyArgs struct {
+		SystemCode    string `json:"system_code"`
+		DependsOnCode string `json:"depends_on_code"`
+	}
+	technicianArgs struct {
+		RegistryNumber string                          `json:"registry_number"`
+		Shift          models.Shift                    `json:"shift"`
+		Skills         map[models.FacilityCategory]int `json:"skills"`
+	}
+	assignWorkOrderArgs struct {
+		RecordID       string `json:"record_id"`
+		RegistryNumber string `json:"registry_number"`
+	}
+	shedPriorityArgs struct {
+		SystemCode string `json:"system_code"`
+		Priority   int    `json:"priority"`
+	}
+	balanceLoadArgs struct {
+		At time.Time `json:"at"`
+	}
+	recordEnvironmentArgs struct {
+		Readings []*models.EnvironmentReading `json:"readings"`
+	}
+	accrueRuntimeArgs struct {
+		From time.Time `json:"from"`
+		To   time.Time `json:"to"`
+	}
+	dutyCycleArgs struct {
+		SystemCode    string    `json:"system_code"`
+		DutyCycle     float64   `json:"duty_cycle"`
+		IntervalHours float64   `json:"interval_hours"`
+		At            time.Time `json:"at"`
+	}
+	commissionArgs struct {
+		Input CommissionInput `json:"input"`
+	}
+	decommissionArgs struct {
+		SystemCode string            `json:"system_code"`
+		Input      DecommissionInput `json:"input"`
+	}
+)
+
+// SetJournal records the commands the service runs in j.
+func (s *Service) SetJournal(j *journal.Journal) {
+	s.journal = j
+}
+
+// begin starts journaling a command of the service.
+func (s *Service) begin(ctx context.Context, command string, args any) (context.Context, *journal.Command) {
+	return s.journal.Begin(ctx, s.vault, command, args)
+}
+
+// Commands returns the handlers that replay the service's journaled
+// commands.
+func (s *Service) Commands() journal.Handlers {
+	return journal.Handlers{
+		CommandSetSystemStatus: journal.Handle(func(ctx context.Context, args systemStatusArgs) error {
+			_, err := s.SetSystemStatus(ctx, args.SystemCode, args.Status, args.Efficiency, args.At, args.Reason)
+			return err
+		}),
+		CommandDeclareFlow: journal.Handle(func(ctx context.Context, args declareFlowArgs) error {
+			_, err := s.DeclareFlow(ctx, args.SystemCode, args.Grid, args.Direction, args.Rate)
+			return err
+		}),
+		CommandPlanMaintenance: journal.Handle(func(ctx context.Context, args planMaintenanceArgs) error {
+			_, err := s.PlanMaintenance(ctx, args.Now, args.Horizon)
+			return err
+		}),
+		CommandDeclareConsumable: journal.Handle(func(ctx context.Context, args declareConsumableArgs) error {
+			_, err := s.DeclareConsumable(ctx, args.SystemCode, args.ItemCode, args.Quantity, args.IntervalDays)
+			return err
+		}),
+		CommandCompleteMaintenance: journal.Handle(func(ctx context.Context, args completeMaintenanceArgs) error {
+			_, err := s.CompleteMaintenance(ctx, args.RecordID, args.Input)
+			return err
+		}),
+		CommandAddDependency: journal.Handle(func(ctx context.Context, args dependencyArgs) error {
+			_, err := s.AddDependency(ctx, args.SystemCode, args.DependsOnCode)
+			return err
+		}),
+		CommandRemoveDependency: journal.Handle(func(ctx context.Context, args dependencyArgs) error {
+			return s.RemoveDependency(ctx, args.SystemCode, args.DependsOnCode)
+		}),
+		CommandSetTechnician: journal.Handle(func(ctx context.Context, args technicianArgs) error {
+			_, err := s.SetTechnician(ctx, args.RegistryNumber, args.Shift, args.Skills)
+			return err
+		}),
+		CommandAssignWorkOrder: journal.Handle(func(ctx context.Context, args assignWorkOrderArgs) error {
+			return s.AssignWorkOrder(ctx, args.RecordID, args.RegistryNumber)
+		}),
+		CommandSetShedPriority: journal.Handle(func(ctx context.Context, args shedPriorityArgs) error {
+			return s.SetShedPriority(ctx, args.SystemCode, args.Priority)
+		}),
+		CommandBalanceLoad: journal.Handle(func(ctx context.Context, args balanceLoadArgs) error {
+			_, err := s.BalanceLoad(ctx, args.At)
+			return err
+		}),
+		CommandRecordEnvironment: journal.Handle(func(ctx context.Context, args recordEnvironmentArgs) error {
+			_, err := s.RecordEnvironment(ctx, args.Readings)
+			return err
+		}),
+		CommandAccrueRuntime: journal.Handle(func(ctx context.Context, args accrueRuntimeArgs) error {
+			_, err := s.AccrueRuntime(ctx, args.From, args.To)
+			return err
+		}),
+		CommandSetDutyCycle: journal.Handle(func(ctx context.Context, args dutyCycleArgs) error {
+			_, err := s.SetDutyCycle(ctx, args.SystemCode, args.DutyCycle, args.IntervalHours, args.At)
+			return err
+		}),
+		CommandCommissionSystem: journal.Handle(func(ctx context.Context, args commissionArgs) error {
+			_, err := s.CommissionSystem(ctx, args.Input)
+			return err
+		}),
+		CommandDecommissionSystem: journal.Handle(func(ctx context.Context, args decommissionArgs) error {
+			_, err := s.DecommissionSystem(ctx, args.SystemCode, args.Input)
+			return err
+		}),
+	}
+}
@@ -0,0 +1,292 @@
+package facilities
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/services/resources"
+	"github.com/vtuos/vtuos/internal/simulation"
+)
+
+// consumableReserveCycles is how many replacement cycles of each consumable
+// the vault should hold in stock before the consumable check warns.
+const consumableReserveCycles = 2
+
+// CompleteMaintenanceInput contains data for closing a work order.
+type CompleteMaintenanceInput struct {
+	Outcome     models.MaintenanceOutcome `json:"outcome"`
+	At          time.Time                 `json:"at"` // Zero uses the current time
+	CompletedBy *string                   `json:"completed_by"`
+	Notes       string                    `json:"notes"`      // Empty keeps the order's notes
+	Hours       *float64                  `json:"hours"`      // Labor hours the work took
+	Efficiency  *float64                  `json:"efficiency"` // Efficiency the completed work left the system at; nil leaves it
+}
+
+// MaintenanceCompletion is the result of closing a work order: the order,
+// the consumables replaced with it and the change restoring the system, if
+// any.
+type MaintenanceCompletion struct {
+	Record   *models.MaintenanceRecord
+	Replaced []*models.SystemConsumable
+	Restored *Failure
+}
+
+// DeclareConsumable declares that a system takes quantity of an item, e.g. a
+// filter cartridge, every intervalDays days. Declaring an item the system
+// already takes changes its quantity and interval and keeps when it was last
+// replaced.
+func (s *Service) DeclareConsumable(ctx context.Context, systemCode, itemCode string, quantity float64, intervalDays int) (_ *models.SystemConsumable, err error) {
+	ctx, cmd := s.begin(ctx, CommandDeclareConsumable, declareConsumableArgs{systemCode, itemCode, quantity, intervalDays})
+	defer func() { cmd.End(err) }()
+
+	sys, err := s.inServiceSystem(ctx, systemCode)
+	if err != nil {
+		return nil, err
+	}
+	item, err := s.resources.GetItemByCode(ctx, itemCode)
+	if err != nil {
+		return nil, fmt.Errorf("item %s: %w", itemCode, err)
+	}
+
+	existing, err := s.facilities.ListConsumables(ctx, sys.ID)
+	if err != nil {
+		return nil, fmt.Errorf("listing consumables: %w", err)
+	}
+
+	consumable := &models.SystemConsumable{
+		ID:           s.idGenerator.NewID(),
+		SystemID:     sys.ID,
+		ItemID:       item.ID,
+		Quantity:     quantity,
+		IntervalDays: intervalDays,
+		Item:         item,
+	}
+	for _, c := range existing {
+		if c.ItemID == item.ID {
+			consumable.ID = c.ID
+			consumable.LastReplaced = c.LastReplaced
+			consumable.NextDue = c.NextDue
+		}
+	}
+
+	if err := s.facilities.SetConsumable(ctx, nil, consumable); err != nil {
+		return nil, fmt.Errorf("declaring consumable: %w", err)
+	}
+	return consumable, nil
+}
+
+// ListConsumables retrieves the consumables of a system, or of every system
+// when systemCode is empty, soonest due first.
+func (s *Service) ListConsumables(ctx context.Context, systemCode string) ([]*models.SystemConsumable, error) {
+	var systemID string
+	if systemCode != "" {
+		sys, err := s.facilities.GetSystemByCode(ctx, systemCode)
+		if err != nil {
+			return nil, fmt.Errorf("system %s: %w", systemCode, err)
+		}
+		systemID = sys.ID
+	}
+	return s.facilities.ListConsumables(ctx, systemID)
+}
+
+// ListOpenMaintenance retrieves the open work orders, soonest scheduled
+// first.
+func (s *Service) ListOpenMaintenance(ctx context.Context) ([]*models.MaintenanceRecord, error) {
+	return s.facilities.ListOpenMaintenance(ctx)
+}
+
+// CompleteMaintenance closes an open work order. A completed order moves
+// the system's maintenance dates on by its maintenance interval, in days or
+// in runtime, restarts its wear and replaces every consumable that would
+// fall due before the next maintenance, drawing the replacement stock and
+// restarting the consumable's cycle. The order closes together with its
+// stock draws, or not at all when stock runs short. The order records the
+// labor hours given and the system's efficiency before and after the work;
+// completed work given an efficiency then sets the system to it, returning
+// a degraded or failed system to OPERATIONAL.
+func (s *Service) CompleteMaintenance(ctx context.Context, recordID string, input CompleteMaintenanceInput) (_ *MaintenanceCompletion, err error) {
+	ctx, cmd := s.begin(ctx, CommandCompleteMaintenance, completeMaintenanceArgs{recordID, input})
+	defer func() { cmd.End(err) }()
+
+	if !input.Outcome.Valid() {
+		return nil, fmt.Errorf("%w: invalid outcome: %s", repository.ErrValidation, input.Outcome)
+	}
+	if input.Hours != nil && (math.IsNaN(*input.Hours) || math.IsInf(*input.Hours, 0) || *input.Hours < 0) {
+		return nil, fmt.Errorf("%w: labor hours cannot be negative", repository.ErrValidation)
+	}
+	if input.Efficiency != nil {
+		if input.Outcome != models.MaintenanceOutcomeCompleted {
+			return nil, fmt.Errorf("%w: only completed work sets the system's efficiency", repository.ErrValidation)
+		}
+		if math.IsNaN(*input.Efficiency) || *input.Efficiency < 0 || *input.Efficiency > 100 {
+			return nil, fmt.Errorf("%w: efficiency must be 0-100", repository.ErrValidation)
+		}
+	}
+
+	rec, err := s.facilities.GetMaintenanceRecord(ctx, recordID)
+	if err != nil {
+		return nil, err
+	}
+	if rec.Outcome != "" {
+		return nil, fmt.Errorf("%w: work order %s already closed %s", repository.ErrValidation, recordID, rec.Outcome)
+	}
+	sys, err := s.facilities.GetSystem(ctx, rec.SystemID)
+	if err != nil {
+		return nil, fmt.Errorf("getting system: %w", err)
+	}
+
+	at := input.At
+	if at.IsZero() {
+		at = s.now()
+	}
+	rec.Outcome = input.Outcome
+	rec.CompletedAt = &at
+	if input.Notes != "" {
+		rec.Notes = input.Notes
+	}
+	before, after := sys.EfficiencyPercent, sys.EfficiencyPercent
+	if input.Efficiency != nil {
+		after = *input.Efficiency
+	}
+	rec.ActualHours = input.Hours
+	rec.EfficiencyBefore = &before
+	rec.EfficiencyAfter = &after
+	completion := &MaintenanceCompletion{Record: rec}
+
+	type draw struct {
+		consumable *models.SystemConsumable
+		input      resources.ConsumptionInput
+	}
+	var draws []draw
+	nextDue := at
+	if input.Outcome == models.MaintenanceOutcomeCompleted {
+		sys.RuntimeAtLastMaintenance = sys.TotalRuntimeHours
+		nextDue = sys.NextMaintenanceFrom(at)
+
+		consumables, err := s.facilities.ListConsumables(ctx, sys.ID)
+		if err != nil {
+			return nil, fmt.Errorf("listing consumables: %w", err)
+		}
+		for _, c := range consumables {
+			if !c.DueBy(nextDue) {
+				continue
+			}
+			consumption := resources.ConsumptionInput{
+				ItemID:            c.ItemID,
+				Quantity:          c.Quantity,
+				Reason:            fmt.Sprintf("Replacement on %s: %s", sys.SystemCode, rec.Description),
+				AuthorizedBy:      input.CompletedBy,
+				RelatedEntityType: "FACILITY",
+				RelatedEntityID:   sys.ID,
+			}
+			draws = append(draws, draw{c, consumption})
+		}
+	}
+
+	err = repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		if err := s.facilities.CloseMaintenanceRecord(ctx, tx, rec); err != nil {
+			return err
+		}
+		if input.Outcome != models.MaintenanceOutcomeCompleted {
+			return nil
+		}
+		if err := s.facilities.RecordSystemMaintenance(ctx, tx, sys.ID, at, nextDue, sys.RuntimeAtLastMaintenance); err != nil {
+			return err
+		}
+		for _, d := range draws {
+			if err := s.resources.ApplyConsumption(ctx, tx, d.input); err != nil {
+				return fmt.Errorf("drawing %s: %w", d.consumable.Item.ItemCode, err)
+			}
+			d.consumable.Replace(at)
+			if err := s.facilities.ReplaceConsumable(ctx, tx, d.consumable); err != nil {
+				return err
+			}
+			completion.Replaced = append(completion.Replaced, d.consumable)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Efficiency != nil {
+		status := sys.Status
+		if status == models.FacilityStatusDegraded || status == models.FacilityStatusFailed {
+			status = models.FacilityStatusOperational
+		}
+		completion.Restored, err = s.SetSystemStatus(ctx, sys.SystemCode, status, input.Efficiency, at, "work order "+rec.ID)
+		if err != nil {
+			return nil, fmt.Errorf("restoring %s: %w", sys.SystemCode, err)
+		}
+	}
+	return completion, nil
+}
+
+// CheckConsumables raises a warning for every consumable item whose
+// available stock would not last the next two replacement cycles of the
+// systems that take it, and a critical alert when it would not last the
+// next one.
+func (s *Service) CheckConsumables(ctx context.Context, at time.Time) ([]simulation.Event, error) {
+	consumables, err := s.facilities.ListConsumables(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("listing consumables: %w", err)
+	}
+
+	perCycle := make(map[string]float64)
+	items := make(map[string]*models.ResourceItem)
+	for _, c := range consumables {
+		perCycle[c.ItemID] += c.Quantity
+		items[c.ItemID] = c.Item
+	}
+
+	var warnings, critical []simulation.Event
+	for itemID, need := range perCycle {
+		available, err := s.resources.GetAvailableQuantity(ctx, itemID)
+		if err != nil {
+			return nil, fmt.Errorf("getting stock of %s: %w", items[itemID].ItemCode, err)
+		}
+		reserve := need * consumableReserveCycles
+		if available >= reserve-models.QuantityEpsilon {
+			continue
+		}
+
+		event := simulation.Event{
+			Time:   at,
+			Level:  simulation.EventWarning,
+			Source: "consumable check",
+			Message: fmt.Sprintf("Consumable stock below %d replacement cycles: %s, %.2f of %.2f %s",
+				consumableReserveCycles, items[itemID].ItemCode, available, reserve, items[itemID].UnitOfMeasure),
+		}
+		if available < need-models.QuantityEpsilon {
+			event.Level = simulation.EventCritical
+			event.Message = fmt.Sprintf("Consumable stock short of the next replacement: %s, %.2f of %.2f %s",
+				items[itemID].ItemCode, available, need, items[itemID].UnitOfMeasure)
+			critical = append(critical, event)
+			continue
+		}
+		warnings = append(warnings, event)
+	}
+
+	byMessage := func(events []simulation.Event) {
+		sort.Slice(events, func(i, j int) bool { return events[i].Message < events[j].Message })
+	}
+	byMessage(warnings)
+	byMessage(critical)
+	return append(warnings, critical...), nil
+}
+
+// ConsumableAlertJob is the scheduled job that checks replacement
+// consumable stock every vault day.
+func (s *Service) ConsumableAlertJob() simulation.Job {
+	return simulation.Job{
+		Name:     "Consumable stock alerts",
+		Interval: simulation.Daily,
+		Check:    s.CheckConsumables,
+	}
+}
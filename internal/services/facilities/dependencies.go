@@ -0,0 +1,200 @@
+package facilities
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+)
+
+// DependencyMap is the facility systems with what each depends on and the
+// root cause of those degraded by a system they depend on.
+type DependencyMap struct {
+	Systems  []*models.FacilitySystem // By system code
+	Graph    *models.DependencyGraph
+	Cascades map[string]*models.SystemCascade // By system ID
+
+	byID map[string]*models.FacilitySystem
+}
+
+// System returns the system with the given ID, or nil.
+func (m *DependencyMap) System(id string) *models.FacilitySystem {
+	return m.byID[id]
+}
+
+// AddDependency records that a system needs another, its parent, running to
+// run at full efficiency. A dependency that would make the parent depend on
+// the system, directly or through others, is rejected.
+func (s *Service) AddDependency(ctx context.Context, systemCode, dependsOnCode string) (_ *models.SystemDependency, err error) {
+	ctx, cmd := s.begin(ctx, CommandAddDependency, dependencyArgs{systemCode, dependsOnCode})
+	defer func() { cmd.End(err) }()
+
+	sys, err := s.inServiceSystem(ctx, systemCode)
+	if err != nil {
+		return nil, err
+	}
+	parent, err := s.inServiceSystem(ctx, dependsOnCode)
+	if err != nil {
+		return nil, err
+	}
+
+	deps, err := s.facilities.ListDependencies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing dependencies: %w", err)
+	}
+	if models.NewDependencyGraph(deps).DependsOn(parent.ID, sys.ID) {
+		return nil, fmt.Errorf("%w: %s already depends on %s", repository.ErrValidation, dependsOnCode, systemCode)
+	}
+
+	dep := &models.SystemDependency{SystemID: sys.ID, DependsOnID: parent.ID}
+	if err := s.facilities.AddDependency(ctx, nil, dep); err != nil {
+		return nil, fmt.Errorf("adding dependency: %w", err)
+	}
+	return dep, nil
+}
+
+// RemoveDependency removes a system's dependency on another. A degradation
+// the parent already caused stays until the parent is back in operation.
+func (s *Service) RemoveDependency(ctx context.Context, systemCode, dependsOnCode string) (err error) {
+	ctx, cmd := s.begin(ctx, CommandRemoveDependency, dependencyArgs{systemCode, dependsOnCode})
+	defer func() { cmd.End(err) }()
+
+	sys, err := s.facilities.GetSystemByCode(ctx, systemCode)
+	if err != nil {
+		return fmt.Errorf("system %s: %w", systemCode, err)
+	}
+	parent, err := s.facilities.GetSystemByCode(ctx, dependsOnCode)
+	if err != nil {
+		return fmt.Errorf("system %s: %w", dependsOnCode, err)
+	}
+	return s.facilities.RemoveDependency(ctx, nil, sys.ID, parent.ID)
+}
+
+// Dependencies maps the facility systems' dependencies and cascades.
+func (s *Service) Dependencies(ctx context.Context) (*DependencyMap, error) {
+	systems, err := s.facilities.ListSystems(ctx, nil, nil, models.SortOption{})
+	if err != nil {
+		return nil, fmt.Errorf("listing systems: %w", err)
+	}
+	deps, err := s.facilities.ListDependencies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing dependencies: %w", err)
+	}
+	cascades, err := s.facilities.ListCascades(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing cascades: %w", err)
+	}
+
+	m := &DependencyMap{
+		Systems:  systems,
+		Graph:    models.NewDependencyGraph(deps),
+		Cascades: make(map[string]*models.SystemCascade, len(cascades)),
+		byID:     make(map[string]*models.FacilitySystem, len(systems)),
+	}
+	for _, sys := range systems {
+		m.byID[sys.ID] = sys
+	}
+	for _, c := range cascades {
+		m.Cascades[c.SystemID] = c
+	}
+	return m, nil
+}
+
+// cascadeStep is a change to a system made by a change to one it depends
+// on, and the cascade to record or clear with it.
+type cascadeStep struct {
+	sys     *models.FacilitySystem
+	change  *Failure // Nil when only the cascade is cleared
+	cascade *models.SystemCascade
+	clear   bool
+}
+
+// planCascade returns the changes a status change to sys makes to the
+// systems that depend on it, nearest first. A root cause that degrades
+// caps every running dependent at its efficiency, one that stops running at
+// minDegradedEffPct, and the dependents degrade; the caps carry on through
+// their own dependents. A root cause back in operation restores the systems
+// it degraded that are still degraded to what they were before. A system
+// already degraded by another root cause keeps it.
+func (s *Service) planCascade(ctx context.Context, sys *models.FacilitySystem, change *Failure) ([]cascadeStep, error) {
+	m, err := s.Dependencies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var steps []cascadeStep
+	if own := m.Cascades[sys.ID]; own != nil && change.After == models.FacilityStatusOperational {
+		steps = append(steps, cascadeStep{sys: sys, cascade: own, clear: true})
+	}
+	downstream := m.Graph.Downstream(sys.ID)
+
+	if change.After == models.FacilityStatusOperational {
+		cause := fmt.Sprintf("restored with %s", sys.SystemCode)
+		for _, id := range downstream {
+			c, dep := m.Cascades[id], m.System(id)
+			if c == nil || c.RootID != sys.ID || dep == nil {
+				continue
+			}
+			step := cascadeStep{sys: dep, cascade: c, clear: true}
+			if dep.Status == models.FacilityStatusDegraded {
+				step.change = &Failure{
+					SystemCode: dep.SystemCode,
+					Name:       dep.Name,
+					Before:     dep.Status,
+					After:      c.StatusBefore,
+					Efficiency: c.EfficiencyBefore,
+					At:         change.At,
+					Cause:      cause,
+				}
+			}
+			steps = append(steps, step)
+		}
+		return steps, nil
+	}
+
+	limit := minDegradedEffPct
+	if change.After == models.FacilityStatusDegraded {
+		limit = change.Efficiency
+	}
+	caps := map[string]float64{sys.ID: limit}
+	cause := fmt.Sprintf("root cause: %s %s", sys.SystemCode, change.After)
+	for _, id := range downstream {
+		dep := m.System(id)
+		if dep == nil || !dep.IsRunning() {
+			continue
+		}
+		capped, limit := false, 100.0
+		for _, p := range m.Graph.Parents(id) {
+			if c, ok := caps[p]; ok {
+				capped, limit = true, min(limit, c)
+			}
+		}
+		efficiency := min(dep.EfficiencyPercent, limit)
+		if !capped || (dep.Status == models.FacilityStatusDegraded && efficiency >= dep.EfficiencyPercent) {
+			continue
+		}
+		caps[id] = efficiency
+
+		step := cascadeStep{sys: dep, change: &Failure{
+			SystemCode: dep.SystemCode,
+			Name:       dep.Name,
+			Before:     dep.Status,
+			After:      models.FacilityStatusDegraded,
+			Efficiency: efficiency,
+			At:         change.At,
+			Cause:      cause,
+		}}
+		if m.Cascades[id] == nil {
+			step.cascade = &models.SystemCascade{
+				SystemID:         id,
+				RootID:           sys.ID,
+				StatusBefore:     dep.Status,
+				EfficiencyBefore: dep.EfficiencyPercent,
+				At:               change.At,
+			}
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
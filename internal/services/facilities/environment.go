@@ -0,0 +1,227 @@
+package facilities
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/simulation"
+)
+
+// environmentRetentionDays is how long environment readings are kept.
+const environmentRetentionDays = 90
+
+// Sector environment model. Air is handled vault-wide, so oxygen, carbon
+// dioxide and temperature move from nominal with the share of HVAC capacity
+// lost, reaching nominal plus the full change with every HVAC system
+// stopped; radiation rises where running power systems have lost
+// efficiency and with it their shielding.
+const (
+	nominalOxygenPct    = 20.9
+	oxygenLossPct       = 6.0
+	nominalCO2PPM       = 650.0
+	co2RisePPM          = 6000.0
+	nominalTemperatureC = 21.0
+	temperatureRiseC    = 16.0
+	backgroundMSvPerH   = 0.0003
+	shieldingLossMSvPer = 0.2 // Leaked by a power system running at 0% efficiency
+)
+
+// EnvironmentChange is a reading that moved its sector's metric to another
+// level.
+type EnvironmentChange struct {
+	Reading  *models.EnvironmentReading
+	Previous models.EnvironmentLevel // Empty for a sector's first reading
+}
+
+// String describes the change, e.g. "Environment CORE CO2 3120 ppm
+// (WARNING)" or "Environment CORE CO2 800 ppm (NOMINAL), was WARNING".
+func (c *EnvironmentChange) String() string {
+	desc := "Environment " + c.Reading.String()
+	if c.Previous != "" && !c.Reading.Level.Breached() {
+		desc += ", was " + string(c.Previous)
+	}
+	return desc
+}
+
+// Environment retrieves the latest reading of each sector and metric.
+func (s *Service) Environment(ctx context.Context) ([]*models.EnvironmentReading, error) {
+	return s.environment.LatestReadings(ctx, s.now())
+}
+
+// RecordEnvironment records sensor readings, grading each against its
+// metric's safe range, and drops readings older than the retention period.
+// It returns the readings that moved their sector's metric to another
+// level, or breached it on a first reading.
+func (s *Service) RecordEnvironment(ctx context.Context, readings []*models.EnvironmentReading) (_ []*EnvironmentChange, err error) {
+	ctx, cmd := s.begin(ctx, CommandRecordEnvironment, recordEnvironmentArgs{readings})
+	defer func() { cmd.End(err) }()
+
+	if len(readings) == 0 {
+		return nil, nil
+	}
+	at := readings[0].ReadAt
+	latest, err := s.environment.LatestReadings(ctx, at)
+	if err != nil {
+		return nil, err
+	}
+	previous := make(map[string]models.EnvironmentLevel, len(latest))
+	for _, r := range latest {
+		previous[r.Sector+"/"+string(r.Metric)] = r.Level
+	}
+
+	var changes []*EnvironmentChange
+	err = repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		for _, r := range readings {
+			r.ID = s.idGenerator.NewID()
+			r.Level = r.Metric.Classify(r.Value)
+			if err := s.environment.CreateReading(ctx, tx, r); err != nil {
+				return err
+			}
+			before, seen := previous[r.Sector+"/"+string(r.Metric)]
+			if (seen && before != r.Level) || (!seen && r.Level.Breached()) {
+				changes = append(changes, &EnvironmentChange{Reading: r, Previous: before})
+			}
+		}
+		_, err := s.environment.DeleteReadingsBefore(ctx, tx, at.AddDate(0, 0, -environmentRetentionDays))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// sampleEnvironment reads every metric in every sector at vault time at,
+// with a little sensor noise.
+func (s *Service) sampleEnvironment(ctx context.Context, at time.Time, rng *rand.Rand) ([]*models.EnvironmentReading, error) {
+	sectors, err := s.environment.ListSectors(ctx)
+	if err != nil {
+		return nil, err
+	}
+	systems, err := s.facilities.ListSystems(ctx, nil, nil, models.SortOption{})
+	if err != nil {
+		return nil, fmt.Errorf("listing systems: %w", err)
+	}
+
+	// Share of HVAC capacity lost, stopped systems losing all of theirs and
+	// retired ones none, and the radiation leaked in each sector by degraded
+	// power systems.
+	var hvac, hvacLoss float64
+	leak := make(map[string]float64)
+	for _, sys := range systems {
+		if sys.IsRetired() {
+			continue
+		}
+		efficiency := 0.0
+		if sys.IsRunning() {
+			efficiency = sys.EfficiencyPercent
+		}
+		switch sys.Category {
+		case models.FacilityCategoryHVAC:
+			hvac++
+			hvacLoss += 1 - efficiency/100
+		case models.FacilityCategoryPower:
+			if sys.IsRunning() {
+				leak[sys.LocationSector] += (1 - efficiency/100) * shieldingLossMSvPer
+			}
+		}
+	}
+	if hvac > 0 {
+		hvacLoss /= hvac
+	}
+
+	noise := func(spread float64) float64 {
+		return rng.NormFloat64() * spread
+	}
+	var readings []*models.EnvironmentReading
+	for _, sector := range sectors {
+		values := map[models.EnvironmentMetric]float64{
+			models.MetricOxygen:      nominalOxygenPct - hvacLoss*oxygenLossPct + noise(0.1),
+			models.MetricCO2:         nominalCO2PPM + hvacLoss*co2RisePPM + noise(40),
+			models.MetricTemperature: nominalTemperatureC + hvacLoss*temperatureRiseC + noise(0.4),
+			models.MetricRadiation:   backgroundMSvPerH + leak[sector] + math.Abs(noise(0.00005)),
+		}
+		for _, metric := range models.EnvironmentMetrics {
+			readings = append(readings, &models.EnvironmentReading{
+				Sector: sector,
+				Metric: metric,
+				Value:  math.Max(values[metric], 0),
+				ReadAt: at,
+			})
+		}
+	}
+	return readings, nil
+}
+
+// EnvironmentMonitor is the simulation hook that samples the sensors of
+// every sector once every models.EnvironmentSampleInterval of vault time.
+type EnvironmentMonitor struct {
+	service *Service
+	rng     *rand.Rand
+	last    *time.Time
+}
+
+// EnvironmentMonitor creates the environment monitoring hook with a random
+// source for sensor noise seeded by seed.
+func (s *Service) EnvironmentMonitor(seed int64) *EnvironmentMonitor {
+	return &EnvironmentMonitor{service: s, rng: rand.New(rand.NewSource(seed))}
+}
+
+// Name implements simulation.Hook.
+func (m *EnvironmentMonitor) Name() string {
+	return "environment monitoring"
+}
+
+// Advance implements simulation.Hook. A reading that breaches its metric's
+// safe range raises a warning, or a critical event past the critical
+// bound, and one back in range an informational event. The readings go
+// through RecordEnvironment so that a replay need not roll the noise again.
+func (m *EnvironmentMonitor) Advance(ctx context.Context, from, to time.Time) ([]simulation.Event, error) {
+	if m.last == nil {
+		last, err := m.service.environment.LastReadAt(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if last == nil {
+			last = &time.Time{}
+		}
+		m.last = last
+	}
+	if to.Sub(*m.last) < models.EnvironmentSampleInterval {
+		return nil, nil
+	}
+
+	readings, err := m.service.sampleEnvironment(ctx, to, m.rng)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := m.service.RecordEnvironment(ctx, readings)
+	if err != nil {
+		return nil, err
+	}
+	m.last = &to
+
+	events := make([]simulation.Event, 0, len(changes))
+	for _, c := range changes {
+		level := simulation.EventInfo
+		switch c.Reading.Level {
+		case models.EnvironmentWarning:
+			level = simulation.EventWarning
+		case models.EnvironmentCritical:
+			level = simulation.EventCritical
+		}
+		events = append(events, simulation.Event{
+			Time:    to,
+			Level:   level,
+			Source:  m.Name(),
+			Message: c.String(),
+		})
+	}
+	return events, nil
+}
@@ -0,0 +1,39 @@
+package facilities
+
+import (
+	"context"
+
+	"github.com/vtuos/vtuos/internal/events"
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// SetEvents publishes the service's events, such as systems failed, and
+// those of the consumable stock it draws, on bus.
+func (s *Service) SetEvents(bus *events.Bus) {
+	s.events = bus
+	s.resources.SetEvents(bus)
+}
+
+// systemFailed publishes that a system failed, of its own accord or as one
+// it depends on failed, once the command running in ctx succeeds.
+func (s *Service) systemFailed(ctx context.Context, sys *models.FacilitySystem, change *Failure) {
+	data := map[string]any{
+		"system_code": sys.SystemCode,
+		"name":        sys.Name,
+		"category":    sys.Category,
+		"previous":    change.Before,
+	}
+	if change.Cause != "" {
+		data["cause"] = change.Cause
+	}
+	if change.WorkOrderID != "" {
+		data["work_order_id"] = change.WorkOrderID
+	}
+	s.events.Emit(ctx, events.Event{
+		Type:    events.SystemFailed,
+		Time:    change.At,
+		Vault:   sys.VaultID,
+		Subject: sys.ID,
+		Data:    data,
+	})
+}
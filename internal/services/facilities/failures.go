@@ -0,0 +1,236 @@
+package facilities
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/simulation"
+)
+
+// Failure severity.
+const (
+	failedShare       = 0.25 // Share of failures on an operational system that are outright
+	minDegradeLoss    = 15.0 // Efficiency points lost when a system degrades
+	maxDegradeLoss    = 35.0
+	minDegradedEffPct = 10.0
+)
+
+// Failure records a system the failure model took down, or a status change
+// set by a scenario script, with the changes it cascaded to the systems
+// that depend on it.
+type Failure struct {
+	SystemCode  string
+	Name        string
+	Before      models.FacilityStatus
+	After       models.FacilityStatus
+	Efficiency  float64
+	WorkOrderID string
+	At          time.Time
+	Cause       string     // Root cause of a cascaded change, e.g. "root cause: PWR-REACTOR-01 FAILED"
+	Cascaded    []*Failure // Changes to dependent systems, nearest first
+}
+
+// String describes the failure, e.g. "PWR-REACTOR-01 Fusion Reactor degraded
+// to 72% efficiency", followed by the root cause of a cascaded change.
+func (f Failure) String() string {
+	var desc string
+	switch f.After {
+	case models.FacilityStatusFailed:
+		desc = fmt.Sprintf("%s %s FAILED", f.SystemCode, f.Name)
+	case models.FacilityStatusDegraded:
+		desc = fmt.Sprintf("%s %s degraded to %.0f%% efficiency", f.SystemCode, f.Name, f.Efficiency)
+	default:
+		desc = fmt.Sprintf("%s %s %s at %.0f%% efficiency", f.SystemCode, f.Name, f.After, f.Efficiency)
+	}
+	if f.Cause != "" {
+		desc += " (" + f.Cause + ")"
+	}
+	return desc
+}
+
+// FailureModel is the simulation hook that rolls random facility failures
+// from each system's MTBF rating.
+type FailureModel struct {
+	service *Service
+	rng     *rand.Rand
+	rate    float64
+}
+
+// FailureModel creates the failure hook. rate scales failure probability,
+// e.g. by the configured event frequency.
+func (s *Service) FailureModel(rate float64, seed int64) *FailureModel {
+	return &FailureModel{
+		service: s,
+		rng:     rand.New(rand.NewSource(seed)),
+		rate:    rate,
+	}
+}
+
+// Name implements simulation.Hook.
+func (m *FailureModel) Name() string {
+	return "facility failures"
+}
+
+// Advance implements simulation.Hook. Each running system is rolled once for
+// the hours of the elapsed time it ran at its duty cycle; a shed system does
+// not run. An operational system that fails degrades or, less often, fails
+// outright; a degraded system that fails again fails outright. Every failure
+// raises a corrective work order, and degrades the running systems that
+// depend on the failed one.
+func (m *FailureModel) Advance(ctx context.Context, from, to time.Time) ([]simulation.Event, error) {
+	hours := to.Sub(from).Hours()
+
+	systems, err := m.service.facilities.ListSystems(ctx, nil, nil, models.SortOption{})
+	if err != nil {
+		return nil, fmt.Errorf("listing systems: %w", err)
+	}
+
+	var events []simulation.Event
+	for _, sys := range systems {
+		running := sys.RunningHours(hours)
+		if running <= 0 {
+			continue
+		}
+		if m.rng.Float64() >= math.Min(sys.FailureProbability(running)*m.rate, 1) {
+			continue
+		}
+
+		failure, err := m.service.failSystem(ctx, sys, to, m.rng)
+		if err != nil {
+			return events, fmt.Errorf("failing %s: %w", sys.SystemCode, err)
+		}
+
+		level := simulation.EventWarning
+		if failure.After == models.FacilityStatusFailed {
+			level = simulation.EventCritical
+		}
+		events = append(events, simulation.Event{
+			Time:    to,
+			Level:   level,
+			Source:  m.Name(),
+			Message: failure.String(),
+		})
+		for _, cascaded := range failure.Cascaded {
+			events = append(events, simulation.Event{
+				Time:    to,
+				Level:   simulation.EventWarning,
+				Source:  m.Name(),
+				Message: cascaded.String(),
+			})
+		}
+	}
+
+	return events, nil
+}
+
+// failSystem degrades or fails a system and raises its corrective work order.
+// The change goes through SetSystemStatus so that a replay need not roll the
+// failure again.
+func (s *Service) failSystem(ctx context.Context, sys *models.FacilitySystem, at time.Time, rng *rand.Rand) (*Failure, error) {
+	status, efficiency := models.FacilityStatusFailed, 0.0
+	if sys.Status == models.FacilityStatusOperational && rng.Float64() >= failedShare {
+		loss := minDegradeLoss + rng.Float64()*(maxDegradeLoss-minDegradeLoss)
+		status = models.FacilityStatusDegraded
+		efficiency = math.Max(minDegradedEffPct, math.Round(sys.EfficiencyPercent-loss))
+	}
+	return s.SetSystemStatus(ctx, sys.SystemCode, status, &efficiency, at, "the failure model")
+}
+
+// recordStatusChange applies a status change to a system, and the changes
+// it cascades to the systems that depend on it, and audits them. A system
+// that degraded or failed also gets a corrective work order with the given
+// notes, recorded on the change; the systems it degraded need no work of
+// their own. The cascaded changes are recorded on the change.
+func (s *Service) recordStatusChange(ctx context.Context, sys *models.FacilitySystem, change *Failure, notes string) error {
+	steps, err := s.planCascade(ctx, sys, change)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.applyStatusChange(ctx, tx, sys, change, notes); err != nil {
+		return err
+	}
+	for _, step := range steps {
+		if step.change != nil {
+			if err := s.applyStatusChange(ctx, tx, step.sys, step.change, ""); err != nil {
+				return err
+			}
+			change.Cascaded = append(change.Cascaded, step.change)
+		}
+		switch {
+		case step.clear:
+			err = s.facilities.DeleteCascade(ctx, tx, step.sys.ID)
+		case step.cascade != nil:
+			err = s.facilities.SetCascade(ctx, tx, step.cascade)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+	return nil
+}
+
+// applyStatusChange applies a status change to a system in tx and audits
+// it, raising the corrective work order of a system that degraded or
+// failed of its own accord. A system that failed is announced once the
+// command commits.
+func (s *Service) applyStatusChange(ctx context.Context, tx *sql.Tx, sys *models.FacilitySystem, change *Failure, notes string) error {
+	var order *models.MaintenanceRecord
+	if change.Cause == "" && (change.After == models.FacilityStatusDegraded || change.After == models.FacilityStatusFailed) {
+		order = &models.MaintenanceRecord{
+			ID:              s.idGenerator.NewID(),
+			SystemID:        sys.ID,
+			MaintenanceType: models.MaintenanceTypeCorrective,
+			Description:     fmt.Sprintf("Repair %s: %s", sys.Name, strings.ToLower(string(change.After))),
+			ScheduledDate:   &change.At,
+			RaisedAt:        &change.At,
+			Notes:           notes,
+		}
+		change.WorkOrderID = order.ID
+	}
+
+	before, after := sys.EfficiencyPercent, change.Efficiency
+	entry := &models.AuditEntry{
+		ID:         s.idGenerator.NewID(),
+		Timestamp:  change.At,
+		ActorType:  models.AuditActorSimulation,
+		Action:     models.AuditActionStatusChange,
+		EntityType: models.AuditEntityFacilitySystem,
+		EntityID:   sys.ID,
+	}
+	if err := entry.SetValues(
+		models.StatusValues{Status: string(sys.Status), Efficiency: &before},
+		models.StatusValues{Status: string(change.After), Efficiency: &after},
+	); err != nil {
+		return err
+	}
+
+	if err := s.facilities.UpdateSystemStatus(ctx, tx, sys.ID, change.After, change.Efficiency); err != nil {
+		return err
+	}
+	if change.After == models.FacilityStatusFailed && sys.Status != models.FacilityStatusFailed {
+		s.systemFailed(ctx, sys, change)
+	}
+	if order != nil {
+		if err := s.facilities.CreateMaintenanceRecord(ctx, tx, order); err != nil {
+			return err
+		}
+	}
+	return s.audit.Create(ctx, tx, entry)
+}
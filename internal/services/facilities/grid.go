@@ -0,0 +1,254 @@
+package facilities
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// TightMarginPercent is the spare supply, as a percentage of supply, below
+// which a grid is reported as tight.
+const TightMarginPercent = 10
+
+// GridState summarizes a grid's supply against its demand.
+type GridState string
+
+const (
+	GridStateNominal  GridState = "NOMINAL"
+	GridStateTight    GridState = "TIGHT"
+	GridStateShedding GridState = "SHEDDING" // Supply meets demand with loads shed
+	GridStateDeficit  GridState = "DEFICIT"
+	GridStateNoData   GridState = "NO DATA" // No system declares a flow on the grid
+)
+
+// GridBalance is the supply and demand position of one grid.
+type GridBalance struct {
+	Grid      models.Grid
+	Unit      string
+	Capacity  float64 // Nameplate output of every producer
+	Supply    float64 // Output of running producers at their efficiency
+	Demand    float64 // Draw of running consumers
+	Producers int     // Producers currently running
+	Stopped   int     // Producers not running
+	Shed      int     // Running consumers powered down by load shedding
+	ShedLoad  float64 // Full draw of the shed consumers
+	State     GridState
+}
+
+// Margin returns spare supply, negative when demand exceeds supply.
+func (b GridBalance) Margin() float64 {
+	return b.Supply - b.Demand
+}
+
+// MarginPercent returns the margin as a percentage of supply.
+func (b GridBalance) MarginPercent() float64 {
+	if b.Supply == 0 {
+		if b.Demand == 0 {
+			return 0
+		}
+		return -100
+	}
+	return b.Margin() / b.Supply * 100
+}
+
+// String describes the margin, e.g. "+340 kW (17%)".
+func (b GridBalance) String() string {
+	return fmt.Sprintf("%+.0f %s (%.0f%%)", b.Margin(), b.Unit, b.MarginPercent())
+}
+
+// GridStatus aggregates declared flows into the balance of each grid.
+func (s *Service) GridStatus(ctx context.Context) ([]GridBalance, error) {
+	systems, err := s.facilities.ListSystems(ctx, nil, nil, models.SortOption{})
+	if err != nil {
+		return nil, fmt.Errorf("listing systems: %w", err)
+	}
+	byID := make(map[string]*models.FacilitySystem, len(systems))
+	for _, sys := range systems {
+		byID[sys.ID] = sys
+	}
+
+	flows, err := s.facilities.ListGridFlows(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("listing grid flows: %w", err)
+	}
+
+	var balances []GridBalance
+	for _, grid := range []models.Grid{models.GridPower, models.GridWater} {
+		balances = append(balances, balanceGrid(grid, flows, byID))
+	}
+	return balances, nil
+}
+
+// balanceGrid totals the flows on one grid.
+func balanceGrid(grid models.Grid, flows []*models.GridFlow, systems map[string]*models.FacilitySystem) GridBalance {
+	b := GridBalance{Grid: grid, Unit: grid.Unit(), State: GridStateNoData}
+
+	declared := false
+	for _, flow := range flows {
+		sys, ok := systems[flow.SystemID]
+		if !ok || flow.Grid != grid {
+			continue
+		}
+		declared = true
+
+		switch flow.Direction {
+		case models.FlowOutput:
+			b.Capacity += flow.Rate
+			b.Supply += flow.EffectiveRate(sys)
+			if sys.IsRunning() {
+				b.Producers++
+			} else {
+				b.Stopped++
+			}
+		case models.FlowDraw:
+			b.Demand += flow.EffectiveRate(sys)
+			if sys.IsRunning() && sys.IsShed() {
+				b.Shed++
+				b.ShedLoad += flow.Rate
+			}
+		}
+	}
+	if !declared {
+		return b
+	}
+
+	switch margin := b.MarginPercent(); {
+	case b.Margin() < 0:
+		b.State = GridStateDeficit
+	case b.Shed > 0:
+		b.State = GridStateShedding
+	case margin < TightMarginPercent:
+		b.State = GridStateTight
+	default:
+		b.State = GridStateNominal
+	}
+	return b
+}
+
+// CategoryStatus summarizes the systems of one facility category.
+type CategoryStatus struct {
+	Category   models.FacilityCategory
+	Systems    int // Systems in service, leaving out retired ones
+	Running    int
+	Efficiency float64               // Mean efficiency of the systems in service, counting stopped systems as 0
+	Worst      models.FacilityStatus // Most severe status in the category
+}
+
+// CategoryStatus summarizes the systems in service of a facility category,
+// e.g. HVAC or SECURITY.
+func (s *Service) CategoryStatus(ctx context.Context, category models.FacilityCategory) (*CategoryStatus, error) {
+	systems, err := s.facilities.ListSystems(ctx, nil, &category, models.SortOption{})
+	if err != nil {
+		return nil, fmt.Errorf("listing systems: %w", err)
+	}
+
+	status := &CategoryStatus{Category: category}
+	var total float64
+	for _, sys := range systems {
+		if sys.IsRetired() {
+			continue
+		}
+		status.Systems++
+		if sys.IsRunning() {
+			status.Running++
+			total += sys.EfficiencyPercent
+		}
+		if status.Worst == "" || statusSeverity(sys.Status) > statusSeverity(status.Worst) {
+			status.Worst = sys.Status
+		}
+	}
+	if status.Systems > 0 {
+		status.Efficiency = math.Round(total/float64(status.Systems)*10) / 10
+	}
+
+	return status, nil
+}
+
+// EfficiencyHistory returns the mean efficiency of a category's systems, or
+// of every system if category is nil, at the end of each of the days days
+// up to and including the day of at, oldest first. Stopped systems count
+// as 0, as in CategoryStatus, and systems not yet commissioned or already
+// retired on a day are left out of its mean. The history is worked back from
+// the current efficiencies through the audited status changes since each
+// day. There is no history without systems.
+func (s *Service) EfficiencyHistory(ctx context.Context, category *models.FacilityCategory, at time.Time, days int) ([]float64, error) {
+	systems, err := s.facilities.ListSystems(ctx, nil, category, models.SortOption{})
+	if err != nil {
+		return nil, fmt.Errorf("listing systems: %w", err)
+	}
+	if len(systems) == 0 || days < 1 {
+		return nil, nil
+	}
+	history := make([]float64, days)
+
+	state := make(map[string]models.StatusValues, len(systems))
+	for _, sys := range systems {
+		efficiency := sys.EfficiencyPercent
+		state[sys.ID] = models.StatusValues{Status: string(sys.Status), Efficiency: &efficiency}
+	}
+	end := at.UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+	from := end.AddDate(0, 0, -days+1)
+	entries, err := s.audit.ListBetween(ctx, from, s.now().AddDate(0, 0, 1), models.AuditActionStatusChange)
+	if err != nil {
+		return nil, fmt.Errorf("listing status changes: %w", err)
+	}
+
+	// Undo the changes latest first, taking each day's mean once every
+	// change after its end is undone.
+	next := len(entries) - 1
+	for day := days - 1; day >= 0; day-- {
+		dayEnd := end.AddDate(0, 0, day-days+1)
+		for ; next >= 0 && !entries[next].Timestamp.Before(dayEnd); next-- {
+			entry := entries[next]
+			if _, ok := state[entry.EntityID]; !ok || entry.EntityType != models.AuditEntityFacilitySystem {
+				continue
+			}
+			var before, after models.StatusValues
+			if err := entry.DecodeValues(&before, &after); err != nil {
+				return nil, fmt.Errorf("audit entry %s: %w", entry.ID, err)
+			}
+			if before.Efficiency == nil {
+				before.Efficiency = state[entry.EntityID].Efficiency
+			}
+			state[entry.EntityID] = before
+		}
+
+		var total float64
+		inService := 0
+		for _, values := range state {
+			sys := models.FacilitySystem{Status: models.FacilityStatus(values.Status)}
+			if sys.Status == "" || sys.IsRetired() {
+				continue
+			}
+			inService++
+			if sys.IsRunning() {
+				total += *values.Efficiency
+			}
+		}
+		if inService > 0 {
+			history[day] = math.Round(total/float64(inService)*10) / 10
+		}
+	}
+	return history, nil
+}
+
+// statusSeverity orders facility statuses from healthy to destroyed.
+func statusSeverity(status models.FacilityStatus) int {
+	switch status {
+	case models.FacilityStatusOperational:
+		return 0
+	case models.FacilityStatusDegraded:
+		return 1
+	case models.FacilityStatusMaintenance:
+		return 2
+	case models.FacilityStatusOffline:
+		return 3
+	case models.FacilityStatusFailed:
+		return 4
+	default:
+		return 5
+	}
+}
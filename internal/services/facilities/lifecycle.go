@@ -0,0 +1,353 @@
+package facilities
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/services/resources"
+)
+
+// defaultMaintenanceIntervalDays is the maintenance interval of a system
+// commissioned without one, as the schema defaults it.
+const defaultMaintenanceIntervalDays = 90
+
+// CommissionInput contains data for commissioning a facility system.
+type CommissionInput struct {
+	SystemCode              string                  `json:"system_code"`
+	Name                    string                  `json:"name"`
+	Category                models.FacilityCategory `json:"category"`
+	LocationSector          string                  `json:"location_sector"`
+	LocationLevel           int                     `json:"location_level"`
+	MaintenanceIntervalDays int                     `json:"maintenance_interval_days"` // Zero uses 90
+	MTBFHours               *int                    `json:"mtbf_hours"`
+	Notes                   string                  `json:"notes"`
+	AuthorizedBy            *string                 `json:"authorized_by"`
+	At                      time.Time               `json:"at"` // Zero uses the current vault time
+}
+
+// Commissioning is the result of commissioning a system: the system and the
+// inspection work order raised to check its installation.
+type Commissioning struct {
+	System     *models.FacilitySystem
+	Inspection *models.MaintenanceRecord
+}
+
+// DecommissionInput contains data for decommissioning a facility system.
+type DecommissionInput struct {
+	Reason        string    `json:"reason"`
+	DisposeSpares bool      `json:"dispose_spares"` // Write off the stock of spares no system in service takes
+	AuthorizedBy  *string   `json:"authorized_by"`
+	At            time.Time `json:"at"` // Zero uses the current vault time
+}
+
+// RetiredSpare is a consumable item of a decommissioned system that no
+// system in service takes any more.
+type RetiredSpare struct {
+	Item      *models.ResourceItem
+	Available float64 // Units in stock less reservations when decommissioned
+	Disposed  float64 // Units written off
+}
+
+// Decommissioning is the result of decommissioning a system: the system,
+// the open work orders cancelled, the consumables it no longer takes and
+// the spares left without a system to take them.
+type Decommissioning struct {
+	System      *models.FacilitySystem
+	Cancelled   []*models.MaintenanceRecord
+	Consumables []*models.SystemConsumable
+	Spares      []*RetiredSpare
+}
+
+// CommissionSystem puts a new facility system into service, OPERATIONAL at
+// full efficiency, installed at vault time at and first due maintenance an
+// interval later. An INSPECTION work order raised with it checks the
+// installation, led by the best suggested technician or left unassigned
+// when no active technician is rated in the system's category.
+func (s *Service) CommissionSystem(ctx context.Context, input CommissionInput) (_ *Commissioning, err error) {
+	ctx, cmd := s.begin(ctx, CommandCommissionSystem, commissionArgs{input})
+	defer func() { cmd.End(err) }()
+
+	at := input.At
+	if at.IsZero() {
+		at = s.now()
+	}
+	interval := input.MaintenanceIntervalDays
+	if interval == 0 {
+		interval = defaultMaintenanceIntervalDays
+	}
+	sys := &models.FacilitySystem{
+		ID:                      s.idGenerator.NewID(),
+		SystemCode:              strings.TrimSpace(input.SystemCode),
+		Name:                    strings.TrimSpace(input.Name),
+		Category:                input.Category,
+		LocationSector:          strings.TrimSpace(input.LocationSector),
+		LocationLevel:           input.LocationLevel,
+		Status:                  models.FacilityStatusOperational,
+		EfficiencyPercent:       100,
+		InstallDate:             at,
+		MaintenanceIntervalDays: interval,
+		MTBFHours:               input.MTBFHours,
+		DutyCyclePercent:        100,
+		Notes:                   input.Notes,
+	}
+	if err := sys.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", repository.ErrValidation, err)
+	}
+	due := sys.NextMaintenanceFrom(at)
+	sys.NextMaintenanceDue = &due
+
+	inspection := &models.MaintenanceRecord{
+		ID:              s.idGenerator.NewID(),
+		SystemID:        sys.ID,
+		MaintenanceType: models.MaintenanceTypeInspection,
+		Description:     fmt.Sprintf("Commissioning inspection: %s", sys.Name),
+		ScheduledDate:   &at,
+		RaisedAt:        &at,
+		Notes:           "Raised by commissioning",
+	}
+	technicians, err := s.facilities.ListTechnicians(ctx)
+	if err != nil {
+		return nil, err
+	}
+	workload, err := s.facilities.OpenWorkload(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if suggested := suggestTechnicians(technicians, workload, sys.Category, workStart(inspection, at)); len(suggested) > 0 {
+		lead := suggested[0].Technician.ResidentID
+		inspection.LeadTechnicianID = &lead
+	}
+
+	efficiency := sys.EfficiencyPercent
+	entry := s.lifecycleEntry(sys, at, input.AuthorizedBy)
+	if err := entry.SetValues(
+		models.StatusValues{},
+		models.StatusValues{Status: string(sys.Status), Efficiency: &efficiency},
+	); err != nil {
+		return nil, err
+	}
+
+	err = repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		if err := s.facilities.CreateSystem(ctx, tx, sys); err != nil {
+			return err
+		}
+		if err := s.facilities.CreateMaintenanceRecord(ctx, tx, inspection); err != nil {
+			return fmt.Errorf("raising inspection: %w", err)
+		}
+		return s.audit.Create(ctx, tx, entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Commissioning{System: sys, Inspection: inspection}, nil
+}
+
+// DecommissionSystem takes a system out of service for good: it cancels the
+// system's open work orders, removes its consumables, grid flows,
+// dependencies and load-shedding place, and retires it, keeping it on
+// record. A system that systems in service still depend on cannot be
+// decommissioned. The consumable items no system in service takes any more
+// are returned as spares; with DisposeSpares their available stock is
+// written off, subject to any dual authorization policy on write-offs.
+func (s *Service) DecommissionSystem(ctx context.Context, systemCode string, input DecommissionInput) (_ *Decommissioning, err error) {
+	ctx, cmd := s.begin(ctx, CommandDecommissionSystem, decommissionArgs{systemCode, input})
+	defer func() { cmd.End(err) }()
+
+	reason := strings.TrimSpace(input.Reason)
+	if reason == "" {
+		return nil, fmt.Errorf("%w: a decommissioning reason is required", repository.ErrValidation)
+	}
+	at := input.At
+	if at.IsZero() {
+		at = s.now()
+	}
+	sys, err := s.inServiceSystem(ctx, systemCode)
+	if err != nil {
+		return nil, err
+	}
+	m, err := s.Dependencies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var dependents []string
+	for _, id := range m.Graph.Dependents(sys.ID) {
+		if d := m.System(id); d != nil && !d.IsRetired() {
+			dependents = append(dependents, d.SystemCode)
+		}
+	}
+	if len(dependents) > 0 {
+		return nil, fmt.Errorf("%w: %s still depend(s) on %s; remove the dependencies first",
+			repository.ErrValidation, strings.Join(dependents, ", "), sys.SystemCode)
+	}
+
+	result := &Decommissioning{System: sys}
+	open, err := s.facilities.ListOpenMaintenance(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, rec := range open {
+		if rec.SystemID != sys.ID {
+			continue
+		}
+		rec.Outcome = models.MaintenanceOutcomeCancelled
+		rec.CompletedAt = &at
+		rec.Notes = strings.TrimSpace(rec.Notes + "\nCancelled: system decommissioned: " + reason)
+		result.Cancelled = append(result.Cancelled, rec)
+	}
+	if result.Spares, err = s.retiredSpares(ctx, sys, m, result); err != nil {
+		return nil, err
+	}
+
+	before := sys.EfficiencyPercent
+	retired := 0.0
+	entry := s.lifecycleEntry(sys, at, input.AuthorizedBy)
+	if err := entry.SetValues(
+		models.StatusValues{Status: string(sys.Status), Efficiency: &before},
+		models.StatusValues{Status: string(models.FacilityStatusRetired), Efficiency: &retired},
+	); err != nil {
+		return nil, err
+	}
+	sys.DecommissionedAt = &at
+	sys.DecommissionReason = reason
+
+	err = repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		for _, rec := range result.Cancelled {
+			if err := s.facilities.CloseMaintenanceRecord(ctx, tx, rec); err != nil {
+				return err
+			}
+		}
+		if err := s.facilities.DeleteConsumables(ctx, tx, sys.ID); err != nil {
+			return err
+		}
+		if err := s.facilities.DeleteGridFlows(ctx, tx, sys.ID); err != nil {
+			return err
+		}
+		if err := s.facilities.DeleteDependencies(ctx, tx, sys.ID); err != nil {
+			return err
+		}
+		if err := s.facilities.DeleteCascade(ctx, tx, sys.ID); err != nil {
+			return err
+		}
+		if err := s.facilities.DeleteShedPriority(ctx, tx, sys.ID); err != nil {
+			return err
+		}
+		if err := s.facilities.RetireSystem(ctx, tx, sys); err != nil {
+			return err
+		}
+		return s.audit.Create(ctx, tx, entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+	sys.Status = models.FacilityStatusRetired
+	sys.EfficiencyPercent = 0
+	sys.CurrentOutput = nil
+	sys.NextMaintenanceDue = nil
+
+	if input.DisposeSpares {
+		for _, spare := range result.Spares {
+			if err := s.disposeSpare(ctx, sys, spare, input.AuthorizedBy); err != nil {
+				return result, fmt.Errorf("%s is retired, but disposing of %s: %w", sys.SystemCode, spare.Item.ItemCode, err)
+			}
+		}
+	}
+	return result, nil
+}
+
+// inServiceSystem retrieves a system by code, failing validation for one
+// that is retired and so can no longer be changed.
+func (s *Service) inServiceSystem(ctx context.Context, systemCode string) (*models.FacilitySystem, error) {
+	sys, err := s.facilities.GetSystemByCode(ctx, systemCode)
+	if err != nil {
+		return nil, fmt.Errorf("system %s: %w", systemCode, err)
+	}
+	if sys.IsRetired() {
+		return nil, fmt.Errorf("%w: %s was decommissioned", repository.ErrValidation, sys.SystemCode)
+	}
+	return sys, nil
+}
+
+// retiredSpares records the consumables of a system being decommissioned
+// on result and returns their items that no other system in service takes,
+// with the stock available of each.
+func (s *Service) retiredSpares(ctx context.Context, sys *models.FacilitySystem, m *DependencyMap, result *Decommissioning) ([]*RetiredSpare, error) {
+	consumables, err := s.facilities.ListConsumables(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("listing consumables: %w", err)
+	}
+	taken := make(map[string]bool)
+	for _, c := range consumables {
+		if c.SystemID == sys.ID {
+			result.Consumables = append(result.Consumables, c)
+		} else if other := m.System(c.SystemID); other != nil && !other.IsRetired() {
+			taken[c.ItemID] = true
+		}
+	}
+
+	var spares []*RetiredSpare
+	for _, c := range result.Consumables {
+		if taken[c.ItemID] {
+			continue
+		}
+		taken[c.ItemID] = true
+		available, err := s.resources.GetAvailableQuantity(ctx, c.ItemID)
+		if err != nil {
+			return nil, fmt.Errorf("stock of %s: %w", c.Item.ItemCode, err)
+		}
+		spares = append(spares, &RetiredSpare{Item: c.Item, Available: available})
+	}
+	return spares, nil
+}
+
+// disposeSpare writes off the unreserved units of every available lot of a
+// retired system's spare.
+func (s *Service) disposeSpare(ctx context.Context, sys *models.FacilitySystem, spare *RetiredSpare, authorizedBy *string) error {
+	status := models.StockStatusAvailable
+	stocks, err := s.resources.ListStocks(ctx, models.StockFilter{ItemID: spare.Item.ID, Status: &status},
+		models.Pagination{Page: 1, PageSize: 1000})
+	if err != nil {
+		return fmt.Errorf("listing stocks: %w", err)
+	}
+	for _, stock := range stocks.Stocks {
+		quantity := stock.AvailableQuantity()
+		if quantity <= models.QuantityEpsilon {
+			continue
+		}
+		err := s.resources.AdjustStock(ctx, stock.ID, resources.StockAdjustment{
+			QuantityChange:    -quantity,
+			Type:              models.TransactionTypeAdjustment,
+			Reason:            fmt.Sprintf("Disposed of with decommissioned %s: %s", sys.SystemCode, sys.DecommissionReason),
+			AuthorizedBy:      authorizedBy,
+			RelatedEntityType: "FACILITY",
+			RelatedEntityID:   sys.ID,
+		})
+		if err != nil {
+			return err
+		}
+		spare.Disposed += quantity
+	}
+	return nil
+}
+
+// lifecycleEntry starts the audit entry of a system's commissioning or
+// decommissioning, by the given resident or the system.
+func (s *Service) lifecycleEntry(sys *models.FacilitySystem, at time.Time, authorizedBy *string) *models.AuditEntry {
+	entry := &models.AuditEntry{
+		ID:         s.idGenerator.NewID(),
+		Timestamp:  at,
+		ActorType:  models.AuditActorSystem,
+		Action:     models.AuditActionStatusChange,
+		EntityType: models.AuditEntityFacilitySystem,
+		EntityID:   sys.ID,
+	}
+	if authorizedBy != nil {
+		entry.ActorType = models.AuditActorUser
+		entry.ActorID = authorizedBy
+	}
+	return entry
+}
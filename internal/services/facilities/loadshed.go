@@ -0,0 +1,194 @@
+package facilities
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/simulation"
+)
+
+// LoadChange is a system load shedding powered down or up again.
+type LoadChange struct {
+	SystemCode string
+	Name       string
+	Priority   int
+	Draw       float64 // Power drawn at full output, kW
+	Shed       bool    // False when restored
+	At         time.Time
+}
+
+// String describes the change, e.g. "SEC-MONITOR-01 Surveillance Network
+// shed (40 kW, priority 1)".
+func (c LoadChange) String() string {
+	action := "restored"
+	if c.Shed {
+		action = "shed"
+	}
+	if c.Priority == 0 {
+		return fmt.Sprintf("%s %s %s (%.0f kW)", c.SystemCode, c.Name, action, c.Draw)
+	}
+	return fmt.Sprintf("%s %s %s (%.0f kW, priority %d)", c.SystemCode, c.Name, action, c.Draw, c.Priority)
+}
+
+// SetShedPriority places a system in the load-shedding order, 1 shedding
+// first, or with priority 0 takes it out, so it is never shed. Life support
+// systems cannot be given a priority.
+func (s *Service) SetShedPriority(ctx context.Context, systemCode string, priority int) (err error) {
+	ctx, cmd := s.begin(ctx, CommandSetShedPriority, shedPriorityArgs{systemCode, priority})
+	defer func() { cmd.End(err) }()
+
+	sys, err := s.inServiceSystem(ctx, systemCode)
+	if err != nil {
+		return err
+	}
+	if priority == 0 {
+		return s.facilities.DeleteShedPriority(ctx, nil, sys.ID)
+	}
+	if sys.Category.LifeSupport() {
+		return fmt.Errorf("%w: %s is life support (%s) and is never shed", repository.ErrValidation, systemCode, sys.Category)
+	}
+	return s.facilities.SetShedPriority(ctx, nil, &models.ShedPriority{SystemID: sys.ID, Priority: priority})
+}
+
+// ShedLoads returns every system that draws power, with its place in the
+// load-shedding order, first shed first; systems never shed come last.
+func (s *Service) ShedLoads(ctx context.Context) ([]*models.ShedLoad, error) {
+	systems, err := s.facilities.ListSystems(ctx, nil, nil, models.SortOption{})
+	if err != nil {
+		return nil, fmt.Errorf("listing systems: %w", err)
+	}
+	byID := make(map[string]*models.FacilitySystem, len(systems))
+	for _, sys := range systems {
+		byID[sys.ID] = sys
+	}
+
+	grid := models.GridPower
+	flows, err := s.facilities.ListGridFlows(ctx, &grid)
+	if err != nil {
+		return nil, fmt.Errorf("listing grid flows: %w", err)
+	}
+	priorities, err := s.facilities.ListShedPriorities(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing shed priorities: %w", err)
+	}
+	priority := make(map[string]int, len(priorities))
+	for _, p := range priorities {
+		priority[p.SystemID] = p.Priority
+	}
+
+	var loads []*models.ShedLoad
+	for _, flow := range flows {
+		sys, ok := byID[flow.SystemID]
+		if !ok || flow.Direction != models.FlowDraw {
+			continue
+		}
+		loads = append(loads, &models.ShedLoad{System: sys, Priority: priority[sys.ID], Draw: flow.Rate})
+	}
+	models.SortShedLoads(loads)
+	return loads, nil
+}
+
+// BalanceLoad sheds power loads at vault time at while the power grid's
+// supply falls below its demand, lowest priority first, and restores them,
+// in the reverse order, once there is supply to spare for them. A shed
+// system keeps its status but neither draws nor supplies. A grid without
+// declared flows is left alone.
+func (s *Service) BalanceLoad(ctx context.Context, at time.Time) (_ []*LoadChange, err error) {
+	ctx, cmd := s.begin(ctx, CommandBalanceLoad, balanceLoadArgs{at})
+	defer func() { cmd.End(err) }()
+
+	balances, err := s.GridStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var power GridBalance
+	for _, b := range balances {
+		if b.Grid == models.GridPower {
+			power = b
+		}
+	}
+	if power.State == GridStateNoData {
+		return nil, nil
+	}
+
+	loads, err := s.ShedLoads(ctx)
+	if err != nil {
+		return nil, err
+	}
+	shed, restore := models.PlanLoadShed(power.Margin(), loads)
+	if len(shed) == 0 && len(restore) == 0 {
+		return nil, nil
+	}
+
+	var changes []*LoadChange
+	err = repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		off := 0.0
+		for _, l := range append(shed, restore...) {
+			change := &LoadChange{
+				SystemCode: l.System.SystemCode,
+				Name:       l.System.Name,
+				Priority:   l.Priority,
+				Draw:       l.Draw,
+				Shed:       !l.System.IsShed(),
+				At:         at,
+			}
+			output := &off
+			if !change.Shed {
+				output = nil
+			}
+			if err := s.facilities.SetCurrentOutput(ctx, tx, l.System.ID, output); err != nil {
+				return err
+			}
+			changes = append(changes, change)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// LoadShedder is the simulation hook that keeps the power grid's demand
+// within its supply by shedding and restoring loads.
+type LoadShedder struct {
+	service *Service
+}
+
+// LoadShedder creates the load-shedding hook.
+func (s *Service) LoadShedder() *LoadShedder {
+	return &LoadShedder{service: s}
+}
+
+// Name implements simulation.Hook.
+func (h *LoadShedder) Name() string {
+	return "load shedding"
+}
+
+// Advance implements simulation.Hook. Each shed load raises a critical
+// event and each restored one an informational event.
+func (h *LoadShedder) Advance(ctx context.Context, from, to time.Time) ([]simulation.Event, error) {
+	changes, err := h.service.BalanceLoad(ctx, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []simulation.Event
+	for _, c := range changes {
+		level := simulation.EventInfo
+		if c.Shed {
+			level = simulation.EventCritical
+		}
+		events = append(events, simulation.Event{
+			Time:    to,
+			Level:   level,
+			Source:  h.Name(),
+			Message: "Power load " + c.String(),
+		})
+	}
+	return events, nil
+}
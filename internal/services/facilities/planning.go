@@ -0,0 +1,91 @@
+package facilities
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/simulation"
+)
+
+// PlanMaintenance raises a preventive work order for every system whose
+// maintenance falls due before horizon, scheduled on its due date or at now
+// when already overdue. Systems with an open preventive order and destroyed
+// or retired systems are skipped. Each order is led by the best suggested technician,
+// counting the orders raised before it, or left unassigned when no active
+// technician is rated in the system's category. It returns the orders
+// raised.
+func (s *Service) PlanMaintenance(ctx context.Context, now, horizon time.Time) (_ []*models.MaintenanceRecord, err error) {
+	ctx, cmd := s.begin(ctx, CommandPlanMaintenance, planMaintenanceArgs{now, horizon})
+	defer func() { cmd.End(err) }()
+
+	systems, err := s.facilities.ListSystems(ctx, nil, nil, models.SortOption{})
+	if err != nil {
+		return nil, fmt.Errorf("listing systems: %w", err)
+	}
+	technicians, err := s.facilities.ListTechnicians(ctx)
+	if err != nil {
+		return nil, err
+	}
+	workload, err := s.facilities.OpenWorkload(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var orders []*models.MaintenanceRecord
+	for _, sys := range systems {
+		due := sys.NextMaintenanceDue
+		if due == nil || !due.Before(horizon) || sys.Status == models.FacilityStatusDestroyed || sys.IsRetired() {
+			continue
+		}
+		open, err := s.facilities.HasOpenMaintenance(ctx, sys.ID, models.MaintenanceTypePreventive)
+		if err != nil {
+			return orders, err
+		}
+		if open {
+			continue
+		}
+
+		scheduled := *due
+		if scheduled.Before(now) {
+			scheduled = now
+		}
+		order := &models.MaintenanceRecord{
+			ID:              s.idGenerator.NewID(),
+			SystemID:        sys.ID,
+			MaintenanceType: models.MaintenanceTypePreventive,
+			Description:     fmt.Sprintf("Scheduled maintenance: %s", sys.Name),
+			ScheduledDate:   &scheduled,
+			RaisedAt:        &now,
+			Notes:           fmt.Sprintf("Raised by maintenance planning: due %s", due.Format(time.DateOnly)),
+		}
+		if suggested := suggestTechnicians(technicians, workload, sys.Category, workStart(order, now)); len(suggested) > 0 {
+			lead := suggested[0].Technician.ResidentID
+			order.LeadTechnicianID = &lead
+			workload[lead]++
+		}
+		if err := s.facilities.CreateMaintenanceRecord(ctx, nil, order); err != nil {
+			return orders, fmt.Errorf("raising work order for %s: %w", sys.SystemCode, err)
+		}
+		orders = append(orders, order)
+	}
+
+	return orders, nil
+}
+
+// MaintenancePlanningJob is the scheduled job that plans the coming month's
+// preventive maintenance on the 1st of every month.
+func (s *Service) MaintenancePlanningJob() simulation.Job {
+	return simulation.Job{
+		Name:     "Monthly maintenance planning",
+		Interval: simulation.Monthly,
+		Run: func(ctx context.Context, at time.Time) (string, error) {
+			orders, err := s.PlanMaintenance(ctx, at, at.AddDate(0, 1, 0))
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("%d preventive work order(s) raised", len(orders)), nil
+		},
+	}
+}
@@ -0,0 +1,127 @@
+package facilities
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/simulation"
+)
+
+// AccrueRuntime adds the running hours of the vault time between from and
+// to, at each system's duty cycle, to the runtime of every system online
+// and not shed, and moves the next maintenance of systems maintained by
+// runtime to when it is now projected. It returns the systems that reached
+// their runtime interval.
+func (s *Service) AccrueRuntime(ctx context.Context, from, to time.Time) (_ []*models.FacilitySystem, err error) {
+	ctx, cmd := s.begin(ctx, CommandAccrueRuntime, accrueRuntimeArgs{from, to})
+	defer func() { cmd.End(err) }()
+
+	hours := to.Sub(from).Hours()
+	if hours <= 0 {
+		return nil, nil
+	}
+
+	var due []*models.FacilitySystem
+	err = repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		systems, err := s.facilities.ListSystems(ctx, tx, nil, models.SortOption{})
+		if err != nil {
+			return fmt.Errorf("listing systems: %w", err)
+		}
+		for _, sys := range systems {
+			if sys.RunningHours(hours) <= 0 {
+				continue
+			}
+			if sys.Run(hours, to) {
+				due = append(due, sys)
+			}
+			if err := s.facilities.UpdateSystemRuntime(ctx, tx, sys); err != nil {
+				return fmt.Errorf("updating %s: %w", sys.SystemCode, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return due, nil
+}
+
+// SetDutyCycle sets the percentage of its online time a system runs and the
+// runtime hours between its maintenance, 0 to maintain it by its calendar
+// interval. A runtime interval moves the next maintenance to when it is
+// projected as of at; going back to the calendar interval counts it from
+// the last maintenance, if any.
+func (s *Service) SetDutyCycle(ctx context.Context, systemCode string, dutyCycle, intervalHours float64, at time.Time) (_ *models.FacilitySystem, err error) {
+	ctx, cmd := s.begin(ctx, CommandSetDutyCycle, dutyCycleArgs{systemCode, dutyCycle, intervalHours, at})
+	defer func() { cmd.End(err) }()
+
+	if math.IsNaN(dutyCycle) || dutyCycle <= 0 || dutyCycle > 100 {
+		return nil, fmt.Errorf("%w: duty cycle must be above 0 and at most 100 percent", repository.ErrValidation)
+	}
+	if math.IsNaN(intervalHours) || math.IsInf(intervalHours, 0) || intervalHours < 0 {
+		return nil, fmt.Errorf("%w: runtime interval cannot be negative", repository.ErrValidation)
+	}
+	sys, err := s.inServiceSystem(ctx, systemCode)
+	if err != nil {
+		return nil, err
+	}
+
+	sys.DutyCyclePercent = dutyCycle
+	switch {
+	case intervalHours > 0:
+		sys.MaintenanceIntervalHours = &intervalHours
+		due := sys.NextMaintenanceFrom(at)
+		sys.NextMaintenanceDue = &due
+	case sys.MaintenanceIntervalHours != nil:
+		sys.MaintenanceIntervalHours = nil
+		if sys.LastMaintenanceDate != nil {
+			due := sys.NextMaintenanceFrom(*sys.LastMaintenanceDate)
+			sys.NextMaintenanceDue = &due
+		}
+	}
+	if err := s.facilities.UpdateSystemRuntime(ctx, nil, sys); err != nil {
+		return nil, err
+	}
+	return sys, nil
+}
+
+// RuntimeAccrual is the simulation hook that accrues facility runtime as
+// vault time passes.
+type RuntimeAccrual struct {
+	service *Service
+}
+
+// RuntimeAccrual creates the runtime accrual hook.
+func (s *Service) RuntimeAccrual() *RuntimeAccrual {
+	return &RuntimeAccrual{service: s}
+}
+
+// Name implements simulation.Hook.
+func (h *RuntimeAccrual) Name() string {
+	return "facility runtime"
+}
+
+// Advance implements simulation.Hook. Each system that reached its runtime
+// maintenance interval raises a warning event.
+func (h *RuntimeAccrual) Advance(ctx context.Context, from, to time.Time) ([]simulation.Event, error) {
+	due, err := h.service.AccrueRuntime(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []simulation.Event
+	for _, sys := range due {
+		events = append(events, simulation.Event{
+			Time:    to,
+			Level:   simulation.EventWarning,
+			Source:  h.Name(),
+			Message: fmt.Sprintf("%s %s reached its %.0f-hour maintenance interval", sys.SystemCode, sys.Name, *sys.MaintenanceIntervalHours),
+		})
+	}
+	return events, nil
+}
@@ -0,0 +1,82 @@
+package facilities
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/simulation"
+)
+
+// ScriptActionSystemStatus is the scenario script action that sets a
+// system's status.
+const ScriptActionSystemStatus = "system_status"
+
+// SetSystemStatus sets a system's status at vault time at, e.g. for a
+// scripted drill. Efficiency defaults to 0 for a FAILED, OFFLINE or
+// DESTROYED system, 100 for an OPERATIONAL one and is otherwise kept. A
+// system set DEGRADED or FAILED gets a corrective work order, as a random
+// failure would. The change cascades to the systems that depend on the
+// system, degrading them, or restoring those it degraded once it is set
+// OPERATIONAL again. Systems are retired by DecommissionSystem only, and a
+// retired system's status cannot be set.
+func (s *Service) SetSystemStatus(ctx context.Context, systemCode string, status models.FacilityStatus, efficiency *float64, at time.Time, reason string) (_ *Failure, err error) {
+	ctx, cmd := s.begin(ctx, CommandSetSystemStatus, systemStatusArgs{systemCode, status, efficiency, at, reason})
+	defer func() { cmd.End(err) }()
+
+	if !status.Valid() {
+		return nil, fmt.Errorf("%w: invalid system status %q", repository.ErrValidation, status)
+	}
+	if status == models.FacilityStatusRetired {
+		return nil, fmt.Errorf("%w: decommission %s to retire it", repository.ErrValidation, systemCode)
+	}
+	if efficiency != nil && (*efficiency < 0 || *efficiency > 100) {
+		return nil, fmt.Errorf("%w: efficiency must be 0-100", repository.ErrValidation)
+	}
+	sys, err := s.inServiceSystem(ctx, systemCode)
+	if err != nil {
+		return nil, err
+	}
+
+	change := &Failure{
+		SystemCode: sys.SystemCode,
+		Name:       sys.Name,
+		Before:     sys.Status,
+		After:      status,
+		Efficiency: sys.EfficiencyPercent,
+		At:         at,
+	}
+	switch {
+	case efficiency != nil:
+		change.Efficiency = *efficiency
+	case status == models.FacilityStatusFailed, status == models.FacilityStatusOffline, status == models.FacilityStatusDestroyed:
+		change.Efficiency = 0
+	case status == models.FacilityStatusOperational:
+		change.Efficiency = 100
+	}
+
+	notes := fmt.Sprintf("Raised by %s: was %s at %.0f%% efficiency", reason, sys.Status, sys.EfficiencyPercent)
+	if err := s.recordStatusChange(ctx, sys, change, notes); err != nil {
+		return nil, err
+	}
+	return change, nil
+}
+
+// ScriptedStatus handles the system_status action of scenario scripts.
+func (s *Service) ScriptedStatus() simulation.ScriptHandler {
+	return func(ctx context.Context, event simulation.ScriptEvent, at time.Time) (string, error) {
+		status := models.FacilityStatus(strings.ToUpper(event.Status))
+		change, err := s.SetSystemStatus(ctx, event.System, status, event.Efficiency, at, "scenario script")
+		if err != nil {
+			return "", err
+		}
+		messages := []string{change.String()}
+		for _, cascaded := range change.Cascaded {
+			messages = append(messages, cascaded.String())
+		}
+		return strings.Join(messages, "; "), nil
+	}
+}
@@ -0,0 +1,96 @@
+// Package facilities provides facility system and utility grid services for VT-UOS.
+package facilities
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/events"
+	"github.com/vtuos/vtuos/internal/journal"
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/services/resources"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// Service provides facility operations.
+type Service struct {
+	db          *sql.DB
+	facilities  *repository.FacilityRepository
+	residents   *repository.ResidentRepository
+	audit       *repository.AuditRepository
+	environment *repository.EnvironmentRepository
+	resources   *resources.Service
+	idGenerator *util.IDGenerator
+	journal     *journal.Journal
+	events      *events.Bus
+	vault       int
+	now         func() time.Time
+}
+
+// NewService creates a new facilities service.
+func NewService(db *sql.DB) *Service {
+	return &Service{
+		db:          db,
+		facilities:  repository.NewFacilityRepository(db),
+		residents:   repository.NewResidentRepository(db),
+		audit:       repository.NewAuditRepository(db),
+		environment: repository.NewEnvironmentRepository(db),
+		resources:   resources.NewService(db),
+		idGenerator: util.NewIDGenerator(),
+		now:         func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// SetVault limits the service to facility systems, technicians, sector
+// environment readings and the consumable stock it draws, of the vault with
+// the given number. A new service covers every vault.
+func (s *Service) SetVault(vault int) {
+	s.vault = vault
+	s.facilities = s.facilities.ForVault(vault)
+	s.environment = s.environment.ForVault(vault)
+	s.residents = s.residents.ForVault(vault)
+	s.resources.SetVault(vault)
+}
+
+// SetClock makes the service date completed work orders and the consumable
+// stock they draw with vault time.
+func (s *Service) SetClock(clock *util.VaultClock) {
+	s.now = clock.Now
+	s.resources.SetClock(clock)
+}
+
+// ListSystems retrieves facility systems, optionally limited to one category
+// and sorted by one of models.FacilitySystemSortColumns.
+func (s *Service) ListSystems(ctx context.Context, category *models.FacilityCategory, sort models.SortOption) ([]*models.FacilitySystem, error) {
+	return s.facilities.ListSystems(ctx, nil, category, sort)
+}
+
+// DeclareFlow sets what a system supplies to or draws from a grid. Rate is
+// in the grid's unit (kW or liters/day); a rate of zero keeps the system on
+// the grid without contributing to it.
+func (s *Service) DeclareFlow(ctx context.Context, systemCode string, grid models.Grid, direction models.FlowDirection, rate float64) (_ *models.GridFlow, err error) {
+	ctx, cmd := s.begin(ctx, CommandDeclareFlow, declareFlowArgs{systemCode, grid, direction, rate})
+	defer func() { cmd.End(err) }()
+
+	sys, err := s.inServiceSystem(ctx, systemCode)
+	if err != nil {
+		return nil, err
+	}
+
+	flow := &models.GridFlow{
+		ID:        s.idGenerator.NewID(),
+		SystemID:  sys.ID,
+		Grid:      grid,
+		Direction: direction,
+		Rate:      rate,
+	}
+
+	if err := s.facilities.SetGridFlow(ctx, nil, flow); err != nil {
+		return nil, fmt.Errorf("declaring flow: %w", err)
+	}
+
+	return flow, nil
+}
@@ -0,0 +1,213 @@
+package facilities
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+)
+
+// Technician suggestion weights. A skill point outweighs two open work
+// orders, and being on shift when the work starts is worth one skill point.
+const (
+	workloadPenalty = 0.5
+	onShiftBonus    = 1.0
+)
+
+// TechnicianSuggestion is a technician suggested to lead a work order.
+type TechnicianSuggestion struct {
+	Technician *models.Technician
+	Rating     int  // In the system's category
+	Workload   int  // Open work orders they already lead
+	OnShift    bool // On shift when the work starts
+	Score      float64
+}
+
+// WorkOrder is an open work order with its system, its lead technician and
+// the technicians suggested to lead it.
+type WorkOrder struct {
+	Record      *models.MaintenanceRecord
+	System      *models.FacilitySystem
+	Lead        *models.Resident // Nil when unassigned
+	Suggestions []*TechnicianSuggestion
+}
+
+// SetTechnician records the shift a resident works and their skill ratings
+// by facility category, replacing any recorded before.
+func (s *Service) SetTechnician(ctx context.Context, registryNumber string, shift models.Shift, skills map[models.FacilityCategory]int) (_ *models.Technician, err error) {
+	ctx, cmd := s.begin(ctx, CommandSetTechnician, technicianArgs{registryNumber, shift, skills})
+	defer func() { cmd.End(err) }()
+
+	resident, err := s.activeResident(ctx, registryNumber)
+	if err != nil {
+		return nil, err
+	}
+	t := &models.Technician{ResidentID: resident.ID, Shift: shift, Skills: skills, Resident: resident}
+	if t.Skills == nil {
+		t.Skills = make(map[models.FacilityCategory]int)
+	}
+
+	err = repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		return s.facilities.SetTechnician(ctx, tx, t)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// ListTechnicians retrieves the technicians by registry number.
+func (s *Service) ListTechnicians(ctx context.Context) ([]*models.Technician, error) {
+	return s.facilities.ListTechnicians(ctx)
+}
+
+// SuggestTechnicians ranks the active technicians rated in the category of
+// a work order's system to lead it, best first.
+func (s *Service) SuggestTechnicians(ctx context.Context, recordID string) ([]*TechnicianSuggestion, error) {
+	rec, err := s.facilities.GetMaintenanceRecord(ctx, recordID)
+	if err != nil {
+		return nil, err
+	}
+	sys, err := s.facilities.GetSystem(ctx, rec.SystemID)
+	if err != nil {
+		return nil, fmt.Errorf("getting system: %w", err)
+	}
+	technicians, err := s.facilities.ListTechnicians(ctx)
+	if err != nil {
+		return nil, err
+	}
+	workload, err := s.facilities.OpenWorkload(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return suggestTechnicians(technicians, workload, sys.Category, workStart(rec, s.now())), nil
+}
+
+// WorkOrders retrieves the open work orders, soonest scheduled first, with
+// their lead technicians and suggestions.
+func (s *Service) WorkOrders(ctx context.Context) ([]*WorkOrder, error) {
+	records, err := s.facilities.ListOpenMaintenance(ctx)
+	if err != nil {
+		return nil, err
+	}
+	systems, err := s.facilities.ListSystems(ctx, nil, nil, models.SortOption{})
+	if err != nil {
+		return nil, fmt.Errorf("listing systems: %w", err)
+	}
+	technicians, err := s.facilities.ListTechnicians(ctx)
+	if err != nil {
+		return nil, err
+	}
+	workload, err := s.facilities.OpenWorkload(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*models.FacilitySystem, len(systems))
+	for _, sys := range systems {
+		byID[sys.ID] = sys
+	}
+	leads := make(map[string]*models.Resident)
+	now := s.now()
+	orders := make([]*WorkOrder, 0, len(records))
+	for _, rec := range records {
+		sys := byID[rec.SystemID]
+		if sys == nil {
+			continue
+		}
+		order := &WorkOrder{
+			Record:      rec,
+			System:      sys,
+			Suggestions: suggestTechnicians(technicians, workload, sys.Category, workStart(rec, now)),
+		}
+		if id := rec.LeadTechnicianID; id != nil {
+			if leads[*id] == nil {
+				if leads[*id], err = s.residents.GetByID(ctx, *id); err != nil {
+					return nil, fmt.Errorf("getting lead technician: %w", err)
+				}
+			}
+			order.Lead = leads[*id]
+		}
+		orders = append(orders, order)
+	}
+	return orders, nil
+}
+
+// AssignWorkOrder makes the active resident with the registry number the
+// lead technician of an open work order, or with "" leaves it unassigned.
+// Any active resident can lead, rated in the system's category or not.
+func (s *Service) AssignWorkOrder(ctx context.Context, recordID, registryNumber string) (err error) {
+	ctx, cmd := s.begin(ctx, CommandAssignWorkOrder, assignWorkOrderArgs{recordID, registryNumber})
+	defer func() { cmd.End(err) }()
+
+	var lead *string
+	if registryNumber != "" {
+		resident, err := s.activeResident(ctx, registryNumber)
+		if err != nil {
+			return err
+		}
+		lead = &resident.ID
+	}
+	return s.facilities.SetLeadTechnician(ctx, nil, recordID, lead)
+}
+
+// activeResident retrieves the resident with a registry number, who must
+// be active.
+func (s *Service) activeResident(ctx context.Context, registryNumber string) (*models.Resident, error) {
+	resident, err := s.residents.GetByRegistryNumber(ctx, nil, registryNumber)
+	if err != nil {
+		return nil, fmt.Errorf("resident %s: %w", registryNumber, err)
+	}
+	if resident.Status != models.ResidentStatusActive {
+		return nil, fmt.Errorf("%w: resident %s is %s", repository.ErrValidation, resident.RegistryNumber, resident.Status)
+	}
+	return resident, nil
+}
+
+// workStart returns when work on an order starts: at now when it is due
+// today or overdue, otherwise at the start of the day shift on its
+// scheduled date.
+func workStart(rec *models.MaintenanceRecord, now time.Time) time.Time {
+	today := now.UTC().Truncate(24 * time.Hour)
+	if rec.ScheduledDate == nil || !rec.ScheduledDate.After(today) {
+		return now
+	}
+	day := rec.ScheduledDate.UTC().Truncate(24 * time.Hour)
+	return day.Add(time.Duration(models.ShiftAlpha.StartHour()) * time.Hour)
+}
+
+// suggestTechnicians ranks the active technicians rated in a category for
+// work starting at start, by score, then rating, then registry number.
+func suggestTechnicians(technicians []*models.Technician, workload map[string]int, category models.FacilityCategory, start time.Time) []*TechnicianSuggestion {
+	shift := models.ShiftAt(start)
+	var suggestions []*TechnicianSuggestion
+	for _, t := range technicians {
+		rating := t.Skill(category)
+		if rating == 0 || (t.Resident != nil && t.Resident.Status != models.ResidentStatusActive) {
+			continue
+		}
+		sg := &TechnicianSuggestion{
+			Technician: t,
+			Rating:     rating,
+			Workload:   workload[t.ResidentID],
+			OnShift:    t.Shift == shift,
+		}
+		sg.Score = float64(sg.Rating) - workloadPenalty*float64(sg.Workload)
+		if sg.OnShift {
+			sg.Score += onShiftBonus
+		}
+		suggestions = append(suggestions, sg)
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		a, b := suggestions[i], suggestions[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return a.Rating > b.Rating
+	})
+	return suggestions
+}
@@ -0,0 +1,331 @@
+package governance
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/simulation"
+)
+
+// AlertEscalationSource is the source of the alerts announcing an
+// escalation. They are not recorded themselves.
+const AlertEscalationSource = "alert escalation"
+
+// AlertRouteInput contains data for adding an alert routing rule.
+type AlertRouteInput struct {
+	Source             string // As the alerts name it, e.g. "shortage check"; empty or "*" for any
+	MinSeverity        models.AlertSeverity
+	Department         models.Department // Either a department
+	OperatorID         string            // or the resident ID of an operator
+	EscalateTo         models.Department // Empty for ADMINISTRATION
+	EscalateAfterHours int               // 0 for the configured default
+}
+
+// AlertActionInput contains data for acknowledging or resolving an alert.
+type AlertActionInput struct {
+	AlertID    string
+	OperatorID string // Resident ID of the operator
+	Notes      string
+}
+
+// escalationArgs are the arguments of a journaled EscalateAlerts.
+type escalationArgs struct {
+	Now   time.Time     `json:"now"`
+	After time.Duration `json:"after"`
+}
+
+// AddAlertRoute adds a rule routing alerts of a source, from a severity up,
+// to a department or an active operator.
+func (s *Service) AddAlertRoute(ctx context.Context, input AlertRouteInput) (_ *models.AlertRoute, err error) {
+	ctx, cmd := s.begin(ctx, CommandAddAlertRoute, input)
+	defer func() { cmd.End(err) }()
+
+	route := &models.AlertRoute{
+		ID:                 s.idGenerator.NewID(),
+		Source:             strings.TrimSpace(input.Source),
+		MinSeverity:        input.MinSeverity,
+		Department:         input.Department,
+		EscalateTo:         input.EscalateTo,
+		EscalateAfterHours: input.EscalateAfterHours,
+	}
+	if route.Source == "" {
+		route.Source = models.AlertRouteAnySource
+	}
+	if route.MinSeverity == "" {
+		route.MinSeverity = models.AlertSeverityWarning
+	}
+	if input.OperatorID != "" {
+		operator, err := s.activeOperator(ctx, input.OperatorID)
+		if err != nil {
+			return nil, err
+		}
+		route.OperatorID = &operator.ID
+		route.OperatorRegistry = operator.RegistryNumber
+	}
+
+	if err := s.alerts.CreateRoute(ctx, route); err != nil {
+		return nil, err
+	}
+	return route, nil
+}
+
+// RemoveAlertRoute removes a routing rule. The alerts it routed stay with
+// whoever they were routed to.
+func (s *Service) RemoveAlertRoute(ctx context.Context, id string) (err error) {
+	ctx, cmd := s.begin(ctx, CommandRemoveAlertRoute, id)
+	defer func() { cmd.End(err) }()
+
+	return s.alerts.DeleteRoute(ctx, id)
+}
+
+// ListAlertRoutes retrieves the vault's routing rules, earliest added first.
+func (s *Service) ListAlertRoutes(ctx context.Context) ([]*models.AlertRoute, error) {
+	return s.alerts.ListRoutes(ctx)
+}
+
+// RaiseAlerts records the warnings and critical events of a simulation
+// tick as alerts, each routed by the most specific matching rule, or to
+// ADMINISTRATION if none matches. Informational events and escalation
+// notices are not recorded. It returns the alerts recorded.
+func (s *Service) RaiseAlerts(ctx context.Context, events []simulation.Event) (_ []*models.Alert, err error) {
+	var raised []*models.Alert
+	for _, e := range events {
+		severity, ok := alertSeverity(e.Level)
+		if !ok || e.Source == AlertEscalationSource {
+			continue
+		}
+		at := e.Time
+		if at.IsZero() {
+			at = s.now()
+		}
+		raised = append(raised, &models.Alert{
+			ID:       s.idGenerator.NewID(),
+			Source:   e.Source,
+			Severity: severity,
+			Message:  e.Message,
+			RaisedAt: at.Truncate(time.Second),
+			Status:   models.AlertOpen,
+		})
+	}
+	if len(raised) == 0 {
+		return nil, nil
+	}
+
+	ctx, cmd := s.begin(ctx, CommandRaiseAlerts, events)
+	defer func() { cmd.End(err) }()
+
+	routes, err := s.alerts.ListRoutes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range raised {
+		a.Route(models.SelectAlertRoute(routes, a.Source, a.Severity))
+	}
+	err = repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		for _, a := range raised {
+			if err := s.alerts.Create(ctx, tx, a); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return raised, nil
+}
+
+// alertSeverity returns the severity an event is recorded at, false for
+// informational events, which are not.
+func alertSeverity(level simulation.EventLevel) (models.AlertSeverity, bool) {
+	switch level {
+	case simulation.EventCritical:
+		return models.AlertSeverityCritical, true
+	case simulation.EventWarning:
+		return models.AlertSeverityWarning, true
+	default:
+		return "", false
+	}
+}
+
+// AcknowledgeAlert records that an operator has taken an open alert on,
+// with their notes.
+func (s *Service) AcknowledgeAlert(ctx context.Context, input AlertActionInput) (_ *models.Alert, err error) {
+	ctx, cmd := s.begin(ctx, CommandAcknowledgeAlert, input)
+	defer func() { cmd.End(err) }()
+
+	return s.updateAlert(ctx, input, (*models.Alert).Acknowledge)
+}
+
+// ResolveAlert records that an operator has dealt with an alert, with
+// their notes. An alert still open is acknowledged at the same time.
+func (s *Service) ResolveAlert(ctx context.Context, input AlertActionInput) (_ *models.Alert, err error) {
+	ctx, cmd := s.begin(ctx, CommandResolveAlert, input)
+	defer func() { cmd.End(err) }()
+
+	return s.updateAlert(ctx, input, (*models.Alert).Resolve)
+}
+
+// updateAlert acknowledges or resolves an alert for an active operator.
+func (s *Service) updateAlert(ctx context.Context, input AlertActionInput,
+	apply func(a *models.Alert, by string, at time.Time, notes string) error) (*models.Alert, error) {
+	alert, err := s.alerts.GetByID(ctx, input.AlertID)
+	if err != nil {
+		return nil, err
+	}
+	operator, err := s.activeOperator(ctx, input.OperatorID)
+	if err != nil {
+		return nil, err
+	}
+	if err := apply(alert, operator.ID, s.now().Truncate(time.Second), input.Notes); err != nil {
+		return nil, fmt.Errorf("%w: %w", repository.ErrValidation, err)
+	}
+	if err := s.alerts.Update(ctx, nil, alert); err != nil {
+		return nil, err
+	}
+	return s.alerts.GetByID(ctx, alert.ID)
+}
+
+// activeOperator returns the active resident acting as an operator.
+func (s *Service) activeOperator(ctx context.Context, id string) (*models.Resident, error) {
+	operator, err := s.residents.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("getting operator: %w", err)
+	}
+	if operator.Status != models.ResidentStatusActive {
+		return nil, fmt.Errorf("%w: operator %s is %s", repository.ErrValidation, operator.RegistryNumber, operator.Status)
+	}
+	return operator, nil
+}
+
+// ListAlerts retrieves the vault's alerts not yet resolved, or every alert,
+// open ones first, then latest raised first.
+func (s *Service) ListAlerts(ctx context.Context, includeResolved bool) ([]*models.Alert, error) {
+	return s.alerts.List(ctx, includeResolved)
+}
+
+// AlertsAddressedTo retrieves the alerts not yet resolved that are routed
+// to an operator, or to the department of their primary vocation.
+func (s *Service) AlertsAddressedTo(ctx context.Context, operatorID string) ([]*models.Alert, error) {
+	operator, err := s.residents.GetByID(ctx, operatorID)
+	if err != nil {
+		return nil, fmt.Errorf("getting operator: %w", err)
+	}
+	var department models.Department
+	if operator.PrimaryVocationID != nil {
+		vocation, err := s.vocations.GetByID(ctx, *operator.PrimaryVocationID)
+		if err != nil {
+			return nil, fmt.Errorf("getting vocation: %w", err)
+		}
+		department = vocation.Department
+	}
+
+	alerts, err := s.alerts.List(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	var addressed []*models.Alert
+	for _, a := range alerts {
+		if a.AddressedTo(operator.ID, department) {
+			addressed = append(addressed, a)
+		}
+	}
+	return addressed, nil
+}
+
+// EscalateAlerts escalates the alerts left unacknowledged by now for longer
+// than their rule allows, or than after for alerts whose rule sets no limit,
+// to the rule's escalation department. An alert is escalated once; after 0
+// leaves alerts without a limit of their own alone. It returns the alerts
+// escalated.
+func (s *Service) EscalateAlerts(ctx context.Context, now time.Time, after time.Duration) (_ []*models.Alert, err error) {
+	ctx, cmd := s.begin(ctx, CommandEscalateAlerts, escalationArgs{now, after})
+	defer func() { cmd.End(err) }()
+
+	pending, err := s.alerts.ListUnescalated(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	if len(pending) == 0 {
+		return nil, nil
+	}
+	routes, err := s.alerts.ListRoutes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*models.AlertRoute, len(routes))
+	for _, r := range routes {
+		byID[r.ID] = r
+	}
+
+	var escalated []*models.Alert
+	for _, a := range pending {
+		limit, to := after, models.DepartmentAdministration
+		if a.RouteID != nil {
+			if route := byID[*a.RouteID]; route != nil {
+				if route.EscalateAfterHours > 0 {
+					limit = time.Duration(route.EscalateAfterHours) * time.Hour
+				}
+				to = route.EscalationDepartment()
+			}
+		}
+		if !a.EscalationDue(now, limit) {
+			continue
+		}
+		a.Escalate(to, now.Truncate(time.Second))
+		escalated = append(escalated, a)
+	}
+
+	err = repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		for _, a := range escalated {
+			if err := s.alerts.Update(ctx, tx, a); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return escalated, nil
+}
+
+// AlertEscalation is the simulation hook that escalates alerts left
+// unacknowledged too long, raising a critical alert for each.
+type AlertEscalation struct {
+	service *Service
+	after   time.Duration
+}
+
+// AlertEscalation creates the alert escalation hook, escalating alerts
+// whose rule sets no limit after the given vault time.
+func (s *Service) AlertEscalation(after time.Duration) *AlertEscalation {
+	return &AlertEscalation{service: s, after: after}
+}
+
+// Name implements simulation.Hook.
+func (h *AlertEscalation) Name() string {
+	return AlertEscalationSource
+}
+
+// Advance implements simulation.Hook.
+func (h *AlertEscalation) Advance(ctx context.Context, from, to time.Time) ([]simulation.Event, error) {
+	escalated, err := h.service.EscalateAlerts(ctx, to, h.after)
+
+	events := make([]simulation.Event, 0, len(escalated))
+	for _, a := range escalated {
+		events = append(events, simulation.Event{
+			Time:   to,
+			Level:  simulation.EventCritical,
+			Source: h.Name(),
+			Message: fmt.Sprintf("Unacknowledged %s alert escalated to %s: %s",
+				strings.ToLower(string(a.Severity)), a.Department, a.Message),
+		})
+	}
+	return events, err
+}
@@ -0,0 +1,208 @@
+package governance
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/simulation"
+)
+
+// MaintenanceNoticeDays is how many days ahead of scheduled maintenance
+// residents are notified of the downtime.
+const MaintenanceNoticeDays = 3
+
+// AnnouncementInput contains data for posting an overseer broadcast or a
+// department notice.
+type AnnouncementInput struct {
+	Kind        models.AnnouncementKind // BROADCAST or NOTICE
+	Title       string
+	Body        string
+	Audience    models.AnnouncementAudience
+	Department  models.Department // For a DEPARTMENT audience
+	HouseholdID string            // For a HOUSEHOLD audience
+	IssuedBy    string            // Resident ID of the issuer; empty if not recorded
+	ExpiresAt   *time.Time        // Nil to post until withdrawn
+}
+
+// AcknowledgeInput contains data for acknowledging an announcement.
+type AcknowledgeInput struct {
+	AnnouncementID string
+	ResidentID     string
+}
+
+// PostAnnouncement posts an overseer broadcast or department notice to its
+// audience from now. System notices are raised by the simulation only.
+func (s *Service) PostAnnouncement(ctx context.Context, input AnnouncementInput) (_ *models.Announcement, err error) {
+	ctx, cmd := s.begin(ctx, CommandPostAnnouncement, input)
+	defer func() { cmd.End(err) }()
+
+	if input.Kind == models.AnnouncementSystem {
+		return nil, fmt.Errorf("%w: system notices are raised by the simulation", repository.ErrValidation)
+	}
+	announcement := &models.Announcement{
+		ID:         s.idGenerator.NewID(),
+		Kind:       input.Kind,
+		Title:      strings.TrimSpace(input.Title),
+		Body:       strings.TrimSpace(input.Body),
+		Audience:   input.Audience,
+		Department: input.Department,
+		IssuedAt:   s.now().Truncate(time.Second),
+		ExpiresAt:  input.ExpiresAt,
+	}
+
+	if input.HouseholdID != "" {
+		household, err := s.households.GetByID(ctx, input.HouseholdID)
+		if err != nil {
+			return nil, fmt.Errorf("getting household: %w", err)
+		}
+		announcement.HouseholdID = &household.ID
+		announcement.HouseholdDesignation = household.Designation
+	}
+	if input.IssuedBy != "" {
+		issuer, err := s.residents.GetByID(ctx, input.IssuedBy)
+		if err != nil {
+			return nil, fmt.Errorf("getting issuer: %w", err)
+		}
+		if issuer.Status != models.ResidentStatusActive {
+			return nil, fmt.Errorf("%w: %s is %s", repository.ErrValidation, issuer.RegistryNumber, issuer.Status)
+		}
+		announcement.IssuedBy = &issuer.ID
+		announcement.IssuerRegistry = issuer.RegistryNumber
+	}
+
+	if err := s.announcements.Create(ctx, nil, announcement); err != nil {
+		return nil, err
+	}
+	return announcement, nil
+}
+
+// AcknowledgeAnnouncement records that a resident has read an announcement
+// posted to them. Acknowledging it again changes nothing.
+func (s *Service) AcknowledgeAnnouncement(ctx context.Context, input AcknowledgeInput) (_ *models.Announcement, err error) {
+	ctx, cmd := s.begin(ctx, CommandAcknowledgeAnnouncement, input)
+	defer func() { cmd.End(err) }()
+
+	announcement, err := s.announcements.GetByID(ctx, input.AnnouncementID)
+	if err != nil {
+		return nil, err
+	}
+	resident, err := s.residents.GetByID(ctx, input.ResidentID)
+	if err != nil {
+		return nil, fmt.Errorf("getting resident: %w", err)
+	}
+
+	var household string
+	if resident.HouseholdID != nil {
+		household = *resident.HouseholdID
+	}
+	var department models.Department
+	if resident.PrimaryVocationID != nil {
+		vocation, err := s.vocations.GetByID(ctx, *resident.PrimaryVocationID)
+		if err != nil {
+			return nil, fmt.Errorf("getting vocation: %w", err)
+		}
+		department = vocation.Department
+	}
+	if resident.VaultID != announcement.VaultID || !announcement.Addresses(household, department) {
+		return nil, fmt.Errorf("%w: %q is not addressed to %s", repository.ErrValidation,
+			announcement.Title, resident.RegistryNumber)
+	}
+
+	now := s.now()
+	if !announcement.ActiveAt(now) {
+		return nil, fmt.Errorf("%w: %q is no longer posted", repository.ErrValidation, announcement.Title)
+	}
+	if err := s.announcements.Acknowledge(ctx, nil, announcement.ID, resident.ID, now); err != nil {
+		return nil, err
+	}
+	return s.announcements.GetByID(ctx, announcement.ID)
+}
+
+// ListAnnouncements retrieves the vault's announcements posted now, or
+// every announcement including expired ones, latest issued first, with how
+// many of the residents addressed have acknowledged each.
+func (s *Service) ListAnnouncements(ctx context.Context, includeExpired bool) ([]*models.Announcement, error) {
+	if includeExpired {
+		return s.announcements.List(ctx, nil)
+	}
+	now := s.now()
+	return s.announcements.List(ctx, &now)
+}
+
+// Inbox retrieves the announcements posted now to a resident, those still
+// to acknowledge first.
+func (s *Service) Inbox(ctx context.Context, residentID string) ([]*models.Announcement, error) {
+	return s.announcements.Inbox(ctx, residentID, s.now())
+}
+
+// PostMaintenanceNotices posts a system notice to the whole vault for every
+// open work order scheduled from now until horizon, so residents know which
+// system goes down for maintenance and when. Each work order is announced
+// once. It returns the notices posted.
+func (s *Service) PostMaintenanceNotices(ctx context.Context, now, horizon time.Time) (_ []*models.Announcement, err error) {
+	ctx, cmd := s.begin(ctx, CommandPostMaintenanceNotices, maintenanceNoticeArgs{now, horizon})
+	defer func() { cmd.End(err) }()
+
+	orders, err := s.facilities.ListOpenMaintenance(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing work orders: %w", err)
+	}
+
+	var notices []*models.Announcement
+	for _, order := range orders {
+		scheduled := order.ScheduledDate
+		if scheduled == nil || scheduled.Before(now) || !scheduled.Before(horizon) {
+			continue
+		}
+		source := "maintenance:" + order.ID
+		posted, err := s.announcements.HasSource(ctx, source)
+		if err != nil {
+			return notices, err
+		}
+		if posted {
+			continue
+		}
+		system, err := s.facilities.GetSystem(ctx, order.SystemID)
+		if err != nil {
+			return notices, fmt.Errorf("getting system of work order %s: %w", order.ID, err)
+		}
+
+		day := scheduled.Format(time.DateOnly)
+		expires := scheduled.AddDate(0, 0, 1)
+		notice := &models.Announcement{
+			ID:        s.idGenerator.NewID(),
+			Kind:      models.AnnouncementSystem,
+			Title:     fmt.Sprintf("Maintenance downtime %s: %s", day, system.Name),
+			Body:      fmt.Sprintf("%s (%s) is out of service for %s maintenance on %s.", system.Name, system.SystemCode, strings.ToLower(string(order.MaintenanceType)), day),
+			Audience:  models.AudienceAll,
+			IssuedAt:  now.Truncate(time.Second),
+			ExpiresAt: &expires,
+			Source:    source,
+		}
+		if err := s.announcements.Create(ctx, nil, notice); err != nil {
+			return notices, fmt.Errorf("posting notice for %s: %w", system.SystemCode, err)
+		}
+		notices = append(notices, notice)
+	}
+	return notices, nil
+}
+
+// MaintenanceNoticeJob is the scheduled job that notifies residents of
+// maintenance downtime MaintenanceNoticeDays ahead, every vault day.
+func (s *Service) MaintenanceNoticeJob() simulation.Job {
+	return simulation.Job{
+		Name:     "Maintenance downtime notices",
+		Interval: simulation.Daily,
+		Run: func(ctx context.Context, at time.Time) (string, error) {
+			notices, err := s.PostMaintenanceNotices(ctx, at, at.AddDate(0, 0, MaintenanceNoticeDays))
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("%d maintenance notice(s) posted", len(notices)), nil
+		},
+	}
+}
@@ -0,0 +1,168 @@
+package governance
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/services/population"
+)
+
+// CapacityYear is one projected vault-year measured against the vault's
+// designed capacity and food production.
+type CapacityYear struct {
+	population.ForecastPoint
+	CapacityPct    float64 // Population as a percentage of designed capacity
+	CaloriesNeeded float64 // Daily calories at the current ration mix
+	FoodCoverage   float64 // Daily food production as a share of CaloriesNeeded
+}
+
+// OverCapacity returns true if the population exceeds designed capacity.
+func (y CapacityYear) OverCapacity() bool {
+	return y.CapacityPct > 100
+}
+
+// FoodShortfall returns true if food production falls short of rations.
+func (y CapacityYear) FoodShortfall() bool {
+	return y.CaloriesNeeded > 0 && y.FoodCoverage < 1
+}
+
+// CapacityForecast is the population forecast compared against the vault's
+// carrying capacity: its designed capacity and the calories it produces at
+// the current ration class policies.
+type CapacityForecast struct {
+	Forecast            *population.PopulationForecast
+	DesignedCapacity    int
+	CaloriesPerResident float64 // Daily calorie target averaged over household members
+	ProductionCalories  float64 // Daily calories from producible food
+	Years               []CapacityYear
+	OverCapacityYear    int // First year over designed capacity, 0 if none
+	ShortfallYear       int // First year food production falls short, 0 if none
+}
+
+// CapacityForecast projects the population over the given number of
+// vault-years and measures each year against designed capacity and food
+// production. Rations are costed at the current mix of ration classes,
+// weighted by household size.
+func (s *Service) CapacityForecast(ctx context.Context, asOf time.Time, years, designedCapacity int) (*CapacityForecast, error) {
+	if years < 1 {
+		years = DefaultPlanningYears
+	}
+
+	forecast, err := s.population.ForecastPopulation(ctx, asOf, years)
+	if err != nil {
+		return nil, fmt.Errorf("forecasting population: %w", err)
+	}
+
+	perResident, err := s.averageCalorieTarget(ctx, asOf)
+	if err != nil {
+		return nil, fmt.Errorf("costing rations: %w", err)
+	}
+
+	production, err := s.foodProduction(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("totalling food production: %w", err)
+	}
+
+	result := &CapacityForecast{
+		Forecast:            forecast,
+		DesignedCapacity:    designedCapacity,
+		CaloriesPerResident: perResident,
+		ProductionCalories:  production,
+	}
+	for _, point := range forecast.Points {
+		year := CapacityYear{
+			ForecastPoint:  point,
+			CaloriesNeeded: float64(point.Population) * perResident,
+		}
+		if designedCapacity > 0 {
+			year.CapacityPct = float64(point.Population) / float64(designedCapacity) * 100
+		}
+		if year.CaloriesNeeded > 0 {
+			year.FoodCoverage = production / year.CaloriesNeeded
+		}
+		if year.OverCapacity() && result.OverCapacityYear == 0 {
+			result.OverCapacityYear = point.Year
+		}
+		if year.FoodShortfall() && result.ShortfallYear == 0 {
+			result.ShortfallYear = point.Year
+		}
+		result.Years = append(result.Years, year)
+	}
+
+	return result, nil
+}
+
+// averageCalorieTarget returns the daily calorie target of the average
+// member of an active household under the ration policies in force at at.
+// With no household members it is the STANDARD target.
+func (s *Service) averageCalorieTarget(ctx context.Context, at time.Time) (float64, error) {
+	plan, err := s.rationPlan(ctx, at)
+	if err != nil {
+		return 0, err
+	}
+	if plan.Members == 0 {
+		return float64(plan.Targets.Calories(models.RationClassStandard)), nil
+	}
+	return plan.Calories / float64(plan.Members), nil
+}
+
+// rationTotals is the daily ration of the members of the active households.
+type rationTotals struct {
+	Members  int
+	Calories float64
+	WaterL   float64
+	Targets  models.RationTargets
+}
+
+// rationPlan totals the daily ration of every active household member at
+// the household's current ration class, under the ration policies in force
+// at at.
+func (s *Service) rationPlan(ctx context.Context, at time.Time) (*rationTotals, error) {
+	targets, err := s.rationPolicies.Active(ctx, at)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &rationTotals{Targets: targets}
+	for _, class := range models.RationClasses {
+		households, err := s.households.GetByRationClass(ctx, class)
+		if err != nil {
+			return nil, err
+		}
+		for _, h := range households {
+			count, err := s.households.GetMemberCount(ctx, h.ID)
+			if err != nil {
+				return nil, err
+			}
+			plan.Members += count
+			plan.Calories += float64(count * targets.Calories(class))
+			plan.WaterL += float64(count) * targets.WaterL(class)
+		}
+	}
+	return plan, nil
+}
+
+// foodProduction returns the daily calories of every producible item at its
+// production rate.
+func (s *Service) foodProduction(ctx context.Context) (float64, error) {
+	var total float64
+	page := models.Pagination{Page: 1, PageSize: 100}
+	for {
+		result, err := s.resources.ListItems(ctx, "", page)
+		if err != nil {
+			return 0, err
+		}
+		for _, item := range result.Items {
+			if item.IsProducible && item.ProductionRatePerDay != nil && item.CaloriesPerUnit != nil {
+				total += *item.ProductionRatePerDay * *item.CaloriesPerUnit
+			}
+		}
+		if page.Page >= result.TotalPages {
+			break
+		}
+		page.Page++
+	}
+	return total, nil
+}
@@ -0,0 +1,99 @@
+package governance
+
+import (
+	"context"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/journal"
+	"github.com/vtuos/vtuos/internal/simulation"
+)
+
+// Journaled governance commands.
+const (
+	CommandSetLockdownState = "governance.set_lockdown_state"
+	CommandSetRationPolicy  = "governance.set_ration_policy"
+
+	CommandPostAnnouncement        = "governance.post_announcement"
+	CommandAcknowledgeAnnouncement = "governance.acknowledge_announcement"
+	CommandPostMaintenanceNotices  = "governance.post_maintenance_notices"
+
+	CommandAddAlertRoute    = "governance.add_alert_route"
+	CommandRemoveAlertRoute = "governance.remove_alert_route"
+	CommandRaiseAlerts      = "governance.raise_alerts"
+	CommandAcknowledgeAlert = "governance.acknowledge_alert"
+	CommandResolveAlert     = "governance.resolve_alert"
+	CommandEscalateAlerts   = "governance.escalate_alerts"
+
+	CommandSetDualAuthPolicy    = "governance.set_dual_auth_policy"
+	CommandRemoveDualAuthPolicy = "governance.remove_dual_auth_policy"
+)
+
+// maintenanceNoticeArgs are the arguments of a journaled
+// PostMaintenanceNotices.
+type maintenanceNoticeArgs struct {
+	Now     time.Time `json:"now"`
+	Horizon time.Time `json:"horizon"`
+}
+
+// SetJournal records the commands the service runs in j.
+func (s *Service) SetJournal(j *journal.Journal) {
+	s.journal = j
+}
+
+// begin starts journaling a command of the service.
+func (s *Service) begin(ctx context.Context, command string, args any) (context.Context, *journal.Command) {
+	return s.journal.Begin(ctx, s.vaultNumber, command, args)
+}
+
+// Commands returns the handlers that replay the service's journaled
+// commands.
+func (s *Service) Commands() journal.Handlers {
+	return journal.Handlers{
+		CommandSetLockdownState: journal.Handle(func(ctx context.Context, input LockdownInput) error {
+			_, err := s.SetLockdownState(ctx, input)
+			return err
+		}),
+		CommandSetRationPolicy: journal.Handle(func(ctx context.Context, input RationPolicyInput) error {
+			_, err := s.SetRationPolicy(ctx, input)
+			return err
+		}),
+		CommandPostAnnouncement: journal.Handle(func(ctx context.Context, input AnnouncementInput) error {
+			_, err := s.PostAnnouncement(ctx, input)
+			return err
+		}),
+		CommandAcknowledgeAnnouncement: journal.Handle(func(ctx context.Context, input AcknowledgeInput) error {
+			_, err := s.AcknowledgeAnnouncement(ctx, input)
+			return err
+		}),
+		CommandPostMaintenanceNotices: journal.Handle(func(ctx context.Context, args maintenanceNoticeArgs) error {
+			_, err := s.PostMaintenanceNotices(ctx, args.Now, args.Horizon)
+			return err
+		}),
+		CommandAddAlertRoute: journal.Handle(func(ctx context.Context, input AlertRouteInput) error {
+			_, err := s.AddAlertRoute(ctx, input)
+			return err
+		}),
+		CommandRemoveAlertRoute: journal.Handle(s.RemoveAlertRoute),
+		CommandRaiseAlerts: journal.Handle(func(ctx context.Context, events []simulation.Event) error {
+			_, err := s.RaiseAlerts(ctx, events)
+			return err
+		}),
+		CommandAcknowledgeAlert: journal.Handle(func(ctx context.Context, input AlertActionInput) error {
+			_, err := s.AcknowledgeAlert(ctx, input)
+			return err
+		}),
+		CommandResolveAlert: journal.Handle(func(ctx context.Context, input AlertActionInput) error {
+			_, err := s.ResolveAlert(ctx, input)
+			return err
+		}),
+		CommandEscalateAlerts: journal.Handle(func(ctx context.Context, args escalationArgs) error {
+			_, err := s.EscalateAlerts(ctx, args.Now, args.After)
+			return err
+		}),
+		CommandSetDualAuthPolicy: journal.Handle(func(ctx context.Context, input DualAuthPolicyInput) error {
+			_, err := s.SetDualAuthPolicy(ctx, input)
+			return err
+		}),
+		CommandRemoveDualAuthPolicy: journal.Handle(s.RemoveDualAuthPolicy),
+	}
+}
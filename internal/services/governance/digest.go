@@ -0,0 +1,193 @@
+package governance
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// DefaultDigestStockThreshold is the smallest stock movement, in the item's
+// unit, that the daily digest lists.
+const DefaultDigestStockThreshold = 50.0
+
+// Digest summarizes everything that changed in the vault on one vault day.
+type Digest struct {
+	Day            time.Time // Midnight at the start of the vault day
+	Births         []*models.Resident
+	Admissions     []*models.Resident
+	Deaths         []*models.Resident
+	StockMovements []*models.ResourceTransaction // Largest movement first
+	StatusChanges  []StatusChange
+	Maintenance    []CompletedWork
+}
+
+// StatusChange is an audited status change of a resident or facility system.
+type StatusChange struct {
+	Time       time.Time
+	Actor      models.AuditActorType
+	EntityType string
+	Label      string // e.g. "PWR-REACTOR-01 Fusion Reactor"
+	From       string
+	To         string
+}
+
+// CompletedWork is a work order closed during the day.
+type CompletedWork struct {
+	Record *models.MaintenanceRecord
+	System string
+}
+
+// Changes returns the number of entries in the digest.
+func (d *Digest) Changes() int {
+	return len(d.Births) + len(d.Admissions) + len(d.Deaths) +
+		len(d.StockMovements) + len(d.StatusChanges) + len(d.Maintenance)
+}
+
+// DailyDigest builds the digest of the vault day containing day. Vital
+// events come from resident records, stock movements from the transaction
+// ledger, status changes from the audit log and work from closed work orders.
+// Only stock movements of at least stockThreshold units are listed.
+func (s *Service) DailyDigest(ctx context.Context, day time.Time, stockThreshold float64) (*Digest, error) {
+	from := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	to := from.AddDate(0, 0, 1)
+	digest := &Digest{Day: from}
+
+	entered, err := s.residents.ListEnteredBetween(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("listing new residents: %w", err)
+	}
+	for _, r := range entered {
+		switch r.EntryType {
+		case models.EntryTypeVaultBorn:
+			digest.Births = append(digest.Births, r)
+		case models.EntryTypeAdmitted:
+			digest.Admissions = append(digest.Admissions, r)
+		}
+	}
+
+	digest.Deaths, err = s.residents.ListDiedBetween(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("listing deaths: %w", err)
+	}
+
+	digest.StockMovements, err = s.stockMovements(ctx, from, to, stockThreshold)
+	if err != nil {
+		return nil, err
+	}
+
+	digest.StatusChanges, err = s.statusChanges(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := s.facilities.ListCompletedMaintenance(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("listing completed maintenance: %w", err)
+	}
+	for _, rec := range records {
+		digest.Maintenance = append(digest.Maintenance, CompletedWork{
+			Record: rec,
+			System: s.systemLabel(ctx, rec.SystemID),
+		})
+	}
+
+	return digest, nil
+}
+
+// stockMovements lists the day's transactions of at least threshold units.
+func (s *Service) stockMovements(ctx context.Context, from, to time.Time, threshold float64) ([]*models.ResourceTransaction, error) {
+	transactions, err := s.transactionsBetween(ctx, from, to, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var movements []*models.ResourceTransaction
+	for _, txn := range transactions {
+		if math.Abs(txn.Quantity) >= threshold {
+			movements = append(movements, txn)
+		}
+	}
+
+	sort.SliceStable(movements, func(i, j int) bool {
+		return math.Abs(movements[i].Quantity) > math.Abs(movements[j].Quantity)
+	})
+	return movements, nil
+}
+
+// transactionsBetween lists every transaction from from up to to, of the
+// given type or, when txnType is nil, of any type.
+func (s *Service) transactionsBetween(ctx context.Context, from, to time.Time, txnType *models.TransactionType) ([]*models.ResourceTransaction, error) {
+	end := to.Add(-time.Second)
+	filter := models.TransactionFilter{TransactionType: txnType, StartDate: &from, EndDate: &end}
+	page := models.Pagination{Page: 1, PageSize: 100}
+
+	var transactions []*models.ResourceTransaction
+	for {
+		result, err := s.resources.ListTransactions(ctx, filter, page)
+		if err != nil {
+			return nil, fmt.Errorf("listing transactions: %w", err)
+		}
+		transactions = append(transactions, result.Transactions...)
+		if result.NextCursor == "" {
+			break
+		}
+		page.Page++
+		page.After = result.NextCursor
+	}
+	return transactions, nil
+}
+
+// statusChanges lists the day's audited status changes.
+func (s *Service) statusChanges(ctx context.Context, from, to time.Time) ([]StatusChange, error) {
+	entries, err := s.audit.ListBetween(ctx, from, to, models.AuditActionStatusChange)
+	if err != nil {
+		return nil, fmt.Errorf("listing audit log: %w", err)
+	}
+
+	var changes []StatusChange
+	for _, entry := range entries {
+		var before, after models.StatusValues
+		if err := entry.DecodeValues(&before, &after); err != nil {
+			return nil, fmt.Errorf("audit entry %s: %w", entry.ID, err)
+		}
+
+		change := StatusChange{
+			Time:       entry.Timestamp,
+			Actor:      entry.ActorType,
+			EntityType: entry.EntityType,
+			Label:      entry.EntityID,
+			From:       before.Status,
+			To:         after.Status,
+		}
+		switch entry.EntityType {
+		case models.AuditEntityFacilitySystem:
+			change.Label = s.systemLabel(ctx, entry.EntityID)
+			if after.Efficiency != nil && after.Status == string(models.FacilityStatusDegraded) {
+				change.To = fmt.Sprintf("%s %.0f%%", after.Status, *after.Efficiency)
+			}
+		case models.AuditEntityVault:
+			change.Label = "Vault " + entry.EntityID + " alert state"
+		case models.AuditEntityResident:
+			if r, err := s.residents.GetByID(ctx, entry.EntityID); err == nil {
+				change.Label = fmt.Sprintf("%s %s", r.RegistryNumber, r.FullName())
+			}
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, nil
+}
+
+// systemLabel returns "CODE Name" for a facility system, or its ID if it no
+// longer exists.
+func (s *Service) systemLabel(ctx context.Context, id string) string {
+	sys, err := s.facilities.GetSystem(ctx, id)
+	if err != nil {
+		return id
+	}
+	return fmt.Sprintf("%s %s", sys.SystemCode, sys.Name)
+}
@@ -0,0 +1,57 @@
+package governance
+
+import (
+	"context"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// DualAuthPolicyInput contains data for holding an operation for two-person
+// authorization.
+type DualAuthPolicyInput struct {
+	Operation    models.DualAuthOperation
+	MinClearance int     // Clearance both operators must hold; defaults to 1
+	Threshold    float64 // Units from which a stock write-off is held; 0 for every write-off
+}
+
+// SetDualAuthPolicy holds an operation for a second operator's
+// confirmation, or changes the clearance and threshold of the policy that
+// already does.
+func (s *Service) SetDualAuthPolicy(ctx context.Context, input DualAuthPolicyInput) (_ *models.DualAuthPolicy, err error) {
+	ctx, cmd := s.begin(ctx, CommandSetDualAuthPolicy, input)
+	defer func() { cmd.End(err) }()
+
+	policy := &models.DualAuthPolicy{
+		Operation:    input.Operation,
+		MinClearance: input.MinClearance,
+		Threshold:    input.Threshold,
+	}
+	if policy.MinClearance == 0 {
+		policy.MinClearance = 1
+	}
+	if err := s.dualAuth.SetPolicy(ctx, policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// RemoveDualAuthPolicy releases an operation from two-person
+// authorization; one operator may run it again.
+func (s *Service) RemoveDualAuthPolicy(ctx context.Context, op models.DualAuthOperation) (err error) {
+	ctx, cmd := s.begin(ctx, CommandRemoveDualAuthPolicy, op)
+	defer func() { cmd.End(err) }()
+
+	return s.dualAuth.DeletePolicy(ctx, op)
+}
+
+// ListDualAuthPolicies retrieves the vault's two-person authorization
+// policies.
+func (s *Service) ListDualAuthPolicies(ctx context.Context) ([]*models.DualAuthPolicy, error) {
+	return s.dualAuth.ListPolicies(ctx)
+}
+
+// SetOperatorPIN sets the PIN an operator confirms held operations with.
+// It is not journaled, so that PINs stay out of the journal.
+func (s *Service) SetOperatorPIN(ctx context.Context, registryNumber, pin string) error {
+	return s.authorizer.SetPIN(ctx, registryNumber, pin)
+}
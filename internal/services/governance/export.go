@@ -0,0 +1,454 @@
+package governance
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// HQ export datasets.
+const (
+	DatasetDemographics = "demographics"
+	DatasetConsumption  = "consumption"
+	DatasetMaintenance  = "maintenance"
+)
+
+// HQDatasets lists every HQ export dataset, in export order.
+var HQDatasets = []string{DatasetDemographics, DatasetConsumption, DatasetMaintenance}
+
+// ExportPolicy controls how an HQ export is anonymized. No export contains
+// names, registry numbers, birth dates or free-text notes.
+type ExportPolicy struct {
+	// Pseudonymize exports one row per record, with residents and households
+	// replaced by pseudonyms; otherwise only counts and totals are exported.
+	Pseudonymize bool
+	// PseudonymKey keys the pseudonyms, so the same resident keeps the same
+	// pseudonym across exports made with the same key.
+	PseudonymKey []byte
+	// MinGroupSize suppresses demographic groups with fewer residents.
+	MinGroupSize int
+	// AgeBandYears is the width of the age bands that replace ages.
+	AgeBandYears int
+	// DatePrecision truncates dates to "day", "month" or "year".
+	DatePrecision string
+}
+
+// Dataset is one table of an HQ export.
+type Dataset struct {
+	Name    string
+	Columns []string
+	Rows    [][]string
+}
+
+// HQExport builds the named datasets under policy. Ages are taken as of
+// asOf, and residents who entered the vault after it are left out.
+func (s *Service) HQExport(ctx context.Context, asOf time.Time, policy ExportPolicy, datasets []string) ([]*Dataset, error) {
+	if policy.MinGroupSize < 1 {
+		policy.MinGroupSize = 1
+	}
+	if policy.AgeBandYears < 1 {
+		policy.AgeBandYears = 10
+	}
+	if policy.Pseudonymize && len(policy.PseudonymKey) == 0 {
+		return nil, fmt.Errorf("pseudonymized export requires a pseudonym key")
+	}
+
+	var out []*Dataset
+	for _, name := range datasets {
+		var ds *Dataset
+		var err error
+		switch name {
+		case DatasetDemographics:
+			ds, err = s.exportDemographics(ctx, asOf, policy)
+		case DatasetConsumption:
+			ds, err = s.exportConsumption(ctx, policy)
+		case DatasetMaintenance:
+			ds, err = s.exportMaintenance(ctx, policy)
+		default:
+			return nil, fmt.Errorf("unknown dataset: %s", name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("exporting %s: %w", name, err)
+		}
+		out = append(out, ds)
+	}
+	return out, nil
+}
+
+// demographicGroup is the set of quasi-identifiers a demographic row is
+// counted or suppressed by.
+type demographicGroup struct {
+	status  models.ResidentStatus
+	sex     models.Sex
+	ageBand string
+}
+
+// exportDemographics exports residents by status, sex and age band. Groups
+// smaller than the policy's minimum are suppressed: aggregate counts are
+// reported as "<N", and pseudonymized rows lose their age band and entry
+// period.
+func (s *Service) exportDemographics(ctx context.Context, asOf time.Time, policy ExportPolicy) (*Dataset, error) {
+	residents, err := s.allResidents(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make(map[demographicGroup]int)
+	var included []*models.Resident
+	var bands []string
+	for _, r := range residents {
+		if r.EntryDate.After(asOf) || r.DateOfBirth.After(asOf) {
+			continue
+		}
+		at := asOf
+		if r.DateOfDeath != nil && r.DateOfDeath.Before(asOf) {
+			at = *r.DateOfDeath
+		}
+		band := ageBand(r.Age(at), policy.AgeBandYears)
+		groups[demographicGroup{r.Status, r.Sex, band}]++
+		included = append(included, r)
+		bands = append(bands, band)
+	}
+
+	if !policy.Pseudonymize {
+		keys := make([]demographicGroup, 0, len(groups))
+		for g := range groups {
+			keys = append(keys, g)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			a, b := keys[i], keys[j]
+			if a.status != b.status {
+				return a.status < b.status
+			}
+			if a.sex != b.sex {
+				return a.sex < b.sex
+			}
+			return bandStart(a.ageBand) < bandStart(b.ageBand)
+		})
+
+		ds := &Dataset{
+			Name:    DatasetDemographics,
+			Columns: []string{"status", "sex", "age_band", "residents"},
+		}
+		for _, g := range keys {
+			count := strconv.Itoa(groups[g])
+			if groups[g] < policy.MinGroupSize {
+				count = fmt.Sprintf("<%d", policy.MinGroupSize)
+			}
+			ds.Rows = append(ds.Rows, []string{string(g.status), string(g.sex), g.ageBand, count})
+		}
+		return ds, nil
+	}
+
+	ds := &Dataset{
+		Name:    DatasetDemographics,
+		Columns: []string{"resident", "household", "status", "sex", "age_band", "entry_type", "entry_period"},
+	}
+	for i, r := range included {
+		band := bands[i]
+		entry := truncateDate(r.EntryDate, policy.DatePrecision)
+		if groups[demographicGroup{r.Status, r.Sex, band}] < policy.MinGroupSize {
+			band, entry = "*", "*"
+		}
+		household := ""
+		if r.HouseholdID != nil {
+			household = pseudonym(policy.PseudonymKey, "H", *r.HouseholdID)
+		}
+		ds.Rows = append(ds.Rows, []string{
+			pseudonym(policy.PseudonymKey, "R", r.ID),
+			household,
+			string(r.Status),
+			string(r.Sex),
+			band,
+			string(r.EntryType),
+			entry,
+		})
+	}
+	sort.Slice(ds.Rows, func(i, j int) bool { return ds.Rows[i][0] < ds.Rows[j][0] })
+	return ds, nil
+}
+
+// consumptionKey groups transactions in an aggregate consumption export.
+type consumptionKey struct {
+	period   string
+	category string
+	item     string
+	unit     string
+	txnType  models.TransactionType
+}
+
+// consumptionTotal is the aggregate of one consumptionKey.
+type consumptionTotal struct {
+	transactions int
+	quantity     float64
+}
+
+// exportConsumption exports the resource ledger. Reasons are left out, as
+// they are free text.
+func (s *Service) exportConsumption(ctx context.Context, policy ExportPolicy) (*Dataset, error) {
+	items := make(map[string]*models.ResourceItem)
+	itemOf := func(id string) *models.ResourceItem {
+		if item, ok := items[id]; ok {
+			return item
+		}
+		item, err := s.resources.GetItem(ctx, id)
+		if err != nil {
+			item = &models.ResourceItem{ID: id, ItemCode: id}
+		}
+		items[id] = item
+		return item
+	}
+
+	var ds *Dataset
+	totals := make(map[consumptionKey]*consumptionTotal)
+	if policy.Pseudonymize {
+		ds = &Dataset{
+			Name: DatasetConsumption,
+			Columns: []string{
+				"period", "category", "item", "unit", "type", "quantity",
+				"related_type", "related", "authorized_by",
+			},
+		}
+	} else {
+		ds = &Dataset{
+			Name:    DatasetConsumption,
+			Columns: []string{"period", "category", "item", "unit", "type", "transactions", "quantity"},
+		}
+	}
+
+	page := models.Pagination{Page: 1, PageSize: 100}
+	for {
+		result, err := s.resources.ListTransactions(ctx, models.TransactionFilter{}, page)
+		if err != nil {
+			return nil, fmt.Errorf("listing transactions: %w", err)
+		}
+		for _, txn := range result.Transactions {
+			item := itemOf(txn.ItemID)
+			category := ""
+			if item.Category != nil {
+				category = item.Category.Code
+			}
+			period := truncateDate(txn.Timestamp, policy.DatePrecision)
+
+			if !policy.Pseudonymize {
+				key := consumptionKey{period, category, item.ItemCode, item.UnitOfMeasure, txn.TransactionType}
+				total, ok := totals[key]
+				if !ok {
+					total = &consumptionTotal{}
+					totals[key] = total
+				}
+				total.transactions++
+				total.quantity += txn.Quantity
+				continue
+			}
+
+			var relatedType, related, authorizedBy string
+			if txn.RelatedEntityType != nil && txn.RelatedEntityID != nil {
+				relatedType = *txn.RelatedEntityType
+				related = relatedPseudonym(policy.PseudonymKey, relatedType, *txn.RelatedEntityID)
+			}
+			if txn.AuthorizedBy != nil {
+				authorizedBy = pseudonym(policy.PseudonymKey, "R", *txn.AuthorizedBy)
+			}
+			ds.Rows = append(ds.Rows, []string{
+				period, category, item.ItemCode, item.UnitOfMeasure, string(txn.TransactionType),
+				formatQuantity(txn.Quantity), relatedType, related, authorizedBy,
+			})
+		}
+		if result.NextCursor == "" {
+			break
+		}
+		page.Page++
+		page.After = result.NextCursor
+	}
+
+	if policy.Pseudonymize {
+		sortRows(ds.Rows)
+		return ds, nil
+	}
+	for key, total := range totals {
+		ds.Rows = append(ds.Rows, []string{
+			key.period, key.category, key.item, key.unit, string(key.txnType),
+			strconv.Itoa(total.transactions), formatQuantity(total.quantity),
+		})
+	}
+	sortRows(ds.Rows)
+	return ds, nil
+}
+
+// maintenanceKey groups work orders in an aggregate maintenance export.
+type maintenanceKey struct {
+	period   string
+	category models.FacilityCategory
+	mtype    models.MaintenanceType
+	outcome  models.MaintenanceOutcome
+}
+
+// exportMaintenance exports closed work orders. Descriptions and notes are
+// left out, as they are free text.
+func (s *Service) exportMaintenance(ctx context.Context, policy ExportPolicy) (*Dataset, error) {
+	systems, err := s.facilities.ListSystems(ctx, nil, nil, models.SortOption{})
+	if err != nil {
+		return nil, fmt.Errorf("listing facility systems: %w", err)
+	}
+	systemByID := make(map[string]*models.FacilitySystem, len(systems))
+	for _, sys := range systems {
+		systemByID[sys.ID] = sys
+	}
+
+	records, err := s.facilities.ListCompletedMaintenance(ctx, time.Time{}, time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		return nil, fmt.Errorf("listing completed maintenance: %w", err)
+	}
+
+	if policy.Pseudonymize {
+		ds := &Dataset{
+			Name:    DatasetMaintenance,
+			Columns: []string{"period", "category", "system", "type", "outcome", "lead_technician"},
+		}
+		for _, rec := range records {
+			var category models.FacilityCategory
+			code := rec.SystemID
+			if sys, ok := systemByID[rec.SystemID]; ok {
+				category, code = sys.Category, sys.SystemCode
+			}
+			lead := ""
+			if rec.LeadTechnicianID != nil {
+				lead = pseudonym(policy.PseudonymKey, "R", *rec.LeadTechnicianID)
+			}
+			ds.Rows = append(ds.Rows, []string{
+				truncateDate(*rec.CompletedAt, policy.DatePrecision),
+				string(category), code, string(rec.MaintenanceType), string(rec.Outcome), lead,
+			})
+		}
+		sortRows(ds.Rows)
+		return ds, nil
+	}
+
+	counts := make(map[maintenanceKey]int)
+	for _, rec := range records {
+		key := maintenanceKey{
+			period:  truncateDate(*rec.CompletedAt, policy.DatePrecision),
+			mtype:   rec.MaintenanceType,
+			outcome: rec.Outcome,
+		}
+		if sys, ok := systemByID[rec.SystemID]; ok {
+			key.category = sys.Category
+		}
+		counts[key]++
+	}
+
+	ds := &Dataset{
+		Name:    DatasetMaintenance,
+		Columns: []string{"period", "category", "type", "outcome", "work_orders"},
+	}
+	for key, n := range counts {
+		ds.Rows = append(ds.Rows, []string{
+			key.period, string(key.category), string(key.mtype), string(key.outcome), strconv.Itoa(n),
+		})
+	}
+	sortRows(ds.Rows)
+	return ds, nil
+}
+
+// allResidents lists every resident, living or not.
+func (s *Service) allResidents(ctx context.Context) ([]*models.Resident, error) {
+	page := models.Pagination{Page: 1, PageSize: 100}
+	var residents []*models.Resident
+	for {
+		result, err := s.residents.List(ctx, models.ResidentFilter{}, page)
+		if err != nil {
+			return nil, fmt.Errorf("listing residents: %w", err)
+		}
+		residents = append(residents, result.Residents...)
+		if result.NextCursor == "" {
+			return residents, nil
+		}
+		page.Page++
+		page.After = result.NextCursor
+	}
+}
+
+// pseudonym derives a stable pseudonym for id, e.g. "R-3f9a0c12d4e6". The
+// kind keeps resident and household pseudonyms apart.
+func pseudonym(key []byte, kind, id string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(kind + ":" + id))
+	return kind + "-" + hex.EncodeToString(mac.Sum(nil)[:6])
+}
+
+// relatedPseudonym pseudonymizes the entity a transaction relates to when
+// it is a resident or household; other entities are not personal.
+func relatedPseudonym(key []byte, entityType, id string) string {
+	switch entityType {
+	case "RESIDENT":
+		return pseudonym(key, "R", id)
+	case "HOUSEHOLD":
+		return pseudonym(key, "H", id)
+	default:
+		return id
+	}
+}
+
+// ageBand returns the band containing age, e.g. "20-29" for width 10.
+func ageBand(age, width int) string {
+	if age < 0 {
+		age = 0
+	}
+	start := age / width * width
+	if width == 1 {
+		return strconv.Itoa(start)
+	}
+	return fmt.Sprintf("%d-%d", start, start+width-1)
+}
+
+// bandStart returns the first age of a band produced by ageBand.
+func bandStart(band string) int {
+	n := 0
+	for _, c := range band {
+		if c < '0' || c > '9' {
+			break
+		}
+		n = n*10 + int(c-'0')
+	}
+	return n
+}
+
+// truncateDate formats t to the given precision: "day", "month" or "year".
+func truncateDate(t time.Time, precision string) string {
+	switch precision {
+	case "day":
+		return t.Format("2006-01-02")
+	case "year":
+		return t.Format("2006")
+	default:
+		return t.Format("2006-01")
+	}
+}
+
+// formatQuantity formats a quantity to at most three decimals, without
+// trailing zeros.
+func formatQuantity(q float64) string {
+	return strconv.FormatFloat(math.Round(q*1000)/1000, 'f', -1, 64)
+}
+
+// sortRows sorts rows by their columns, left to right.
+func sortRows(rows [][]string) {
+	sort.Slice(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		for k := range a {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return false
+	})
+}
@@ -0,0 +1,140 @@
+package governance
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+)
+
+// LockdownInput contains data for changing the vault's alert state.
+type LockdownInput struct {
+	State        models.LockdownState
+	AuthorizedBy string // Resident ID of the authorizing operator
+	Reason       string
+}
+
+// GetLockdownState retrieves the change that set the vault's current alert
+// state, with its authorizer. A vault whose state never changed gets a
+// NORMAL change with no ID.
+func (s *Service) GetLockdownState(ctx context.Context) (*models.LockdownChange, error) {
+	current, err := s.lockdowns.GetCurrent(ctx)
+	if errors.Is(err, repository.ErrNotFound) {
+		return &models.LockdownChange{State: models.LockdownNormal, PreviousState: models.LockdownNormal}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.joinAuthorizer(ctx, current)
+	return current, nil
+}
+
+// SetLockdownState moves the vault to a new alert state. The authorizing
+// operator must be an active resident holding the transition's clearance.
+// The change is logged, and audited as a status change of the vault.
+func (s *Service) SetLockdownState(ctx context.Context, input LockdownInput) (_ *models.LockdownChange, err error) {
+	ctx, cmd := s.begin(ctx, CommandSetLockdownState, input)
+	defer func() { cmd.End(err) }()
+
+	current, err := s.GetLockdownState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !current.State.CanTransitionTo(input.State) {
+		return nil, fmt.Errorf("%w: vault cannot move from %s to %s", repository.ErrValidation, current.State, input.State)
+	}
+
+	operator, err := s.residents.GetByID(ctx, input.AuthorizedBy)
+	if err != nil {
+		return nil, fmt.Errorf("authorizing operator: %w", err)
+	}
+	if operator.Status != models.ResidentStatusActive {
+		return nil, fmt.Errorf("%w: operator %s is %s", repository.ErrValidation, operator.RegistryNumber, operator.Status)
+	}
+	if required := models.TransitionClearance(current.State, input.State); operator.ClearanceLevel < required {
+		return nil, fmt.Errorf("%w: %s to %s needs clearance %d, operator %s holds %d", repository.ErrValidation,
+			current.State, input.State, required, operator.RegistryNumber, operator.ClearanceLevel)
+	}
+
+	change := &models.LockdownChange{
+		ID:            s.idGenerator.NewID(),
+		State:         input.State,
+		PreviousState: current.State,
+		AuthorizedBy:  &operator.ID,
+		Reason:        input.Reason,
+		ChangedAt:     s.now(),
+		Authorizer:    operator,
+	}
+	entry := &models.AuditEntry{
+		ID:         s.idGenerator.NewID(),
+		Timestamp:  change.ChangedAt,
+		ActorType:  models.AuditActorUser,
+		ActorID:    &operator.ID,
+		Action:     models.AuditActionStatusChange,
+		EntityType: models.AuditEntityVault,
+		EntityID:   strconv.Itoa(s.vaultNumber),
+	}
+	if err := entry.SetValues(
+		models.StatusValues{Status: string(current.State)},
+		models.StatusValues{Status: string(change.State)},
+	); err != nil {
+		return nil, err
+	}
+
+	err = repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		if err := s.lockdowns.Create(ctx, tx, change); err != nil {
+			return err
+		}
+		if err := s.audit.Create(ctx, tx, entry); err != nil {
+			return fmt.Errorf("auditing vault state change: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Warn("vault alert state changed", "from", change.PreviousState, "to", change.State,
+		"authorized_by", operator.RegistryNumber, "reason", change.Reason)
+	return change, nil
+}
+
+// ListLockdownChanges retrieves up to limit state changes with their
+// authorizers, most recent first.
+func (s *Service) ListLockdownChanges(ctx context.Context, limit int) ([]*models.LockdownChange, error) {
+	changes, err := s.lockdowns.ListRecent(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range changes {
+		s.joinAuthorizer(ctx, c)
+	}
+	return changes, nil
+}
+
+// NonEssentialPaused reports whether the current alert state pauses
+// non-essential scheduled jobs. It is the scheduler's pause check, so a
+// failed read keeps jobs running rather than stalling the vault.
+func (s *Service) NonEssentialPaused(ctx context.Context) bool {
+	current, err := s.GetLockdownState(ctx)
+	if err != nil {
+		slog.Warn("reading vault alert state", "error", err)
+		return false
+	}
+	return current.State.PausesNonEssential()
+}
+
+// joinAuthorizer fills in the change's authorizer, if their record remains.
+func (s *Service) joinAuthorizer(ctx context.Context, c *models.LockdownChange) {
+	if c.AuthorizedBy == nil {
+		return
+	}
+	if operator, err := s.residents.GetByID(ctx, *c.AuthorizedBy); err == nil {
+		c.Authorizer = operator
+	}
+}
@@ -0,0 +1,86 @@
+package governance
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+)
+
+// RationPolicyInput contains data for setting the daily targets of a ration
+// class.
+type RationPolicyInput struct {
+	RationClass   models.RationClass
+	CalorieTarget int
+	WaterTargetL  float64
+	Nutrients     *models.Nutrients // Per resident; nil keeps the class's built-in targets
+	EffectiveFrom time.Time         // Defaults to now
+	SetBy         string            // Resident ID of the overseer setting it; empty if not recorded
+	Reason        string
+}
+
+// SetRationPolicy sets new daily targets for a ration class from the
+// effective date. Policies of a class take effect in order, so the date must
+// be later than that of the class's latest policy; a date still to come
+// schedules the change. Rationing and allocation read the policy in force.
+func (s *Service) SetRationPolicy(ctx context.Context, input RationPolicyInput) (_ *models.RationPolicy, err error) {
+	ctx, cmd := s.begin(ctx, CommandSetRationPolicy, input)
+	defer func() { cmd.End(err) }()
+
+	policy := &models.RationPolicy{
+		ID:            s.idGenerator.NewID(),
+		RationClass:   input.RationClass,
+		CalorieTarget: input.CalorieTarget,
+		WaterTargetL:  input.WaterTargetL,
+		Nutrients:     input.Nutrients,
+		EffectiveFrom: input.EffectiveFrom,
+		Reason:        input.Reason,
+	}
+	if policy.EffectiveFrom.IsZero() {
+		policy.EffectiveFrom = s.now()
+	}
+	policy.EffectiveFrom = policy.EffectiveFrom.UTC().Truncate(time.Second)
+	if err := policy.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", repository.ErrValidation, err)
+	}
+
+	history, err := s.rationPolicies.List(ctx, policy.RationClass)
+	if err != nil {
+		return nil, err
+	}
+	if len(history) > 0 && !policy.EffectiveFrom.After(history[0].EffectiveFrom) {
+		return nil, fmt.Errorf("%w: %s already has a policy effective %s", repository.ErrValidation,
+			policy.RationClass, history[0].EffectiveFrom.Format(time.RFC3339))
+	}
+
+	if input.SetBy != "" {
+		overseer, err := s.residents.GetByID(ctx, input.SetBy)
+		if err != nil {
+			return nil, fmt.Errorf("setting overseer: %w", err)
+		}
+		if overseer.Status != models.ResidentStatusActive {
+			return nil, fmt.Errorf("%w: %s is %s", repository.ErrValidation, overseer.RegistryNumber, overseer.Status)
+		}
+		policy.SetBy = &overseer.ID
+	}
+
+	if err := s.rationPolicies.Create(ctx, nil, policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// ListRationPolicies retrieves the policies of a ration class, or of every
+// class if class is empty, latest effective first: scheduled changes, then
+// the policy in force, then its history.
+func (s *Service) ListRationPolicies(ctx context.Context, class models.RationClass) ([]*models.RationPolicy, error) {
+	return s.rationPolicies.List(ctx, class)
+}
+
+// ActiveRationPolicies retrieves the policy of each ration class in force
+// now.
+func (s *Service) ActiveRationPolicies(ctx context.Context) (models.RationTargets, error) {
+	return s.rationPolicies.Active(ctx, s.now())
+}
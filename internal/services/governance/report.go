@@ -0,0 +1,271 @@
+package governance
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/services/resources"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// reportWidth is the width of the printed daily report, in characters.
+const reportWidth = 72
+
+// DailyReport is the printable operations report of one vault day: who
+// came and went, what the vault consumed against its ration plan, what
+// stock is about to expire, how its facilities changed and which incidents
+// remain open.
+type DailyReport struct {
+	Vault       int
+	Day         time.Time // Midnight at the start of the vault day
+	GeneratedAt time.Time
+	Population  int // Active residents when the report was generated
+
+	Births     []*models.Resident
+	Admissions []*models.Resident
+	Deaths     []*models.Resident
+
+	CaloriesPlanned float64 // Daily ration of the current households
+	CaloriesDrawn   float64
+	WaterPlannedL   float64
+	WaterDrawnL     float64
+	Consumption     []ItemConsumption // Largest first
+
+	WarningDays     int
+	Expiring        []*models.ResourceStock // Soonest-expiring first
+	FacilityChanges []StatusChange
+	OpenIncidents   []*models.SecurityIncident
+}
+
+// ItemConsumption is the quantity of one item consumed during the day.
+type ItemConsumption struct {
+	Code     string
+	Name     string
+	Unit     string
+	Quantity float64
+}
+
+// DailyReport builds the operations report of the vault day containing day.
+// Population changes and facility status changes come from the daily digest,
+// consumption from the day's CONSUMPTION transactions, measured against the
+// rations of the current households, and expiring stock is the available
+// stock expiring within warningDays of the end of the day.
+func (s *Service) DailyReport(ctx context.Context, day time.Time, warningDays int) (*DailyReport, error) {
+	if warningDays < 1 {
+		warningDays = resources.DefaultExpirationWarningDays
+	}
+	digest, err := s.DailyDigest(ctx, day, math.Inf(1))
+	if err != nil {
+		return nil, err
+	}
+	to := digest.Day.AddDate(0, 0, 1)
+
+	report := &DailyReport{
+		Vault:       s.vaultNumber,
+		Day:         digest.Day,
+		GeneratedAt: s.now(),
+		Births:      digest.Births,
+		Admissions:  digest.Admissions,
+		Deaths:      digest.Deaths,
+		WarningDays: warningDays,
+	}
+	for _, c := range digest.StatusChanges {
+		if c.EntityType == models.AuditEntityFacilitySystem {
+			report.FacilityChanges = append(report.FacilityChanges, c)
+		}
+	}
+
+	stats, err := s.population.GetPopulationStats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("counting population: %w", err)
+	}
+	report.Population = stats.TotalActive
+
+	if err := s.reportConsumption(ctx, report, digest.Day, to); err != nil {
+		return nil, err
+	}
+
+	report.Expiring, err = s.resources.GetStocksExpiringBy(ctx, to.AddDate(0, 0, warningDays))
+	if err != nil {
+		return nil, fmt.Errorf("getting expiring stocks: %w", err)
+	}
+
+	report.OpenIncidents, err = s.incidents.ListOpenIncidents(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing open incidents: %w", err)
+	}
+
+	return report, nil
+}
+
+// reportConsumption totals the day's consumption by item, and the calories
+// of food and litres of ration water drawn against the ration plan under the
+// day's ration policies.
+func (s *Service) reportConsumption(ctx context.Context, report *DailyReport, from, to time.Time) error {
+	plan, err := s.rationPlan(ctx, from)
+	if err != nil {
+		return fmt.Errorf("costing rations: %w", err)
+	}
+	report.CaloriesPlanned = plan.Calories
+	report.WaterPlannedL = plan.WaterL
+
+	consumption := models.TransactionTypeConsumption
+	transactions, err := s.transactionsBetween(ctx, from, to, &consumption)
+	if err != nil {
+		return err
+	}
+
+	items := make(map[string]*models.ResourceItem)
+	byItem := make(map[string]*ItemConsumption)
+	for _, txn := range transactions {
+		item, ok := items[txn.ItemID]
+		if !ok {
+			item, err = s.resources.GetItem(ctx, txn.ItemID)
+			if err != nil {
+				return fmt.Errorf("getting item %s: %w", txn.ItemID, err)
+			}
+			items[txn.ItemID] = item
+		}
+
+		qty := math.Abs(txn.Quantity)
+		switch {
+		case item.ItemCode == resources.RationWaterItemCode:
+			report.WaterDrawnL += qty
+		case item.CaloriesPerUnit != nil:
+			report.CaloriesDrawn += qty * *item.CaloriesPerUnit
+		}
+
+		c, ok := byItem[item.ID]
+		if !ok {
+			c = &ItemConsumption{Code: item.ItemCode, Name: item.Name, Unit: item.UnitOfMeasure}
+			byItem[item.ID] = c
+		}
+		c.Quantity += qty
+	}
+
+	for _, c := range byItem {
+		report.Consumption = append(report.Consumption, *c)
+	}
+	sort.Slice(report.Consumption, func(i, j int) bool {
+		if report.Consumption[i].Quantity != report.Consumption[j].Quantity {
+			return report.Consumption[i].Quantity > report.Consumption[j].Quantity
+		}
+		return report.Consumption[i].Code < report.Consumption[j].Code
+	})
+	return nil
+}
+
+// FileName returns the name the report is filed under, e.g.
+// "vault-076-daily-report-2077-10-23.txt".
+func (r *DailyReport) FileName() string {
+	return fmt.Sprintf("vault-%03d-daily-report-%s.txt", r.Vault, r.Day.Format(time.DateOnly))
+}
+
+// Text renders the report as plain text for printing.
+func (r *DailyReport) Text() string {
+	var b strings.Builder
+	rule := strings.Repeat("=", reportWidth)
+
+	b.WriteString(rule + "\n")
+	b.WriteString(fmt.Sprintf("VAULT %03d DAILY VAULT REPORT\n", r.Vault))
+	b.WriteString(fmt.Sprintf("Vault day %-30s Generated %s\n",
+		util.FormatDate(r.Day)+" "+r.Day.Format("Mon"), util.FormatDateTime(r.GeneratedAt)))
+	b.WriteString(rule + "\n")
+
+	writeReportSection(&b, "POPULATION")
+	b.WriteString(fmt.Sprintf("  Active residents %d   Births %d   Admissions %d   Deaths %d\n",
+		r.Population, len(r.Births), len(r.Admissions), len(r.Deaths)))
+	for _, group := range []struct {
+		label     string
+		residents []*models.Resident
+	}{{"Birth", r.Births}, {"Admitted", r.Admissions}, {"Died", r.Deaths}} {
+		for _, res := range group.residents {
+			b.WriteString(fmt.Sprintf("  %-9s %s %s\n", group.label, res.RegistryNumber, res.FullName()))
+		}
+	}
+
+	writeReportSection(&b, "RATIONS: CONSUMPTION VS PLAN")
+	b.WriteString(fmt.Sprintf("  Calories %14.0f kcal of %14.0f planned  %s\n",
+		r.CaloriesDrawn, r.CaloriesPlanned, percentOf(r.CaloriesDrawn, r.CaloriesPlanned)))
+	b.WriteString(fmt.Sprintf("  Water    %14.1f L    of %14.1f planned  %s\n",
+		r.WaterDrawnL, r.WaterPlannedL, percentOf(r.WaterDrawnL, r.WaterPlannedL)))
+	if len(r.Consumption) > 0 {
+		b.WriteString("\n")
+	}
+	for _, c := range r.Consumption {
+		b.WriteString(fmt.Sprintf("  %-18s %-30s %12.2f %s\n", c.Code, clip(c.Name, 30), c.Quantity, c.Unit))
+	}
+
+	writeReportSection(&b, fmt.Sprintf("EXPIRING STOCK (WITHIN %d DAYS)", r.WarningDays))
+	if len(r.Expiring) == 0 {
+		b.WriteString("  None\n")
+	}
+	end := r.Day.AddDate(0, 0, 1)
+	for _, stock := range r.Expiring {
+		code, unit := stock.ItemID, ""
+		if stock.Item != nil {
+			code, unit = stock.Item.ItemCode, stock.Item.UnitOfMeasure
+		}
+		lot := "-"
+		if stock.LotNumber != nil {
+			lot = *stock.LotNumber
+		}
+		when := fmt.Sprintf("%d day(s)", stock.DaysUntilExpiration(end))
+		if stock.IsExpired(end) {
+			when = "EXPIRED"
+		}
+		b.WriteString(fmt.Sprintf("  %-18s %-14s %10.2f %-6s %s  %s\n",
+			code, lot, stock.Quantity, unit, util.FormatDate(*stock.ExpirationDate), when))
+	}
+
+	writeReportSection(&b, "FACILITY STATUS CHANGES")
+	if len(r.FacilityChanges) == 0 {
+		b.WriteString("  None\n")
+	}
+	for _, c := range r.FacilityChanges {
+		b.WriteString(fmt.Sprintf("  %s  %s: %s -> %s\n",
+			util.FormatTime(c.Time), c.Label, c.From, c.To))
+	}
+
+	writeReportSection(&b, "OPEN INCIDENTS")
+	if len(r.OpenIncidents) == 0 {
+		b.WriteString("  None\n")
+	}
+	for _, inc := range r.OpenIncidents {
+		b.WriteString(fmt.Sprintf("  %-13s %-8s %-19s %-14s %s\n",
+			inc.IncidentNumber, inc.Severity, inc.IncidentType, inc.Status, util.FormatDay(inc.OccurredAt)))
+		b.WriteString("    " + clip(inc.Description, reportWidth-4) + "\n")
+	}
+
+	b.WriteString("\n" + rule + "\n")
+	b.WriteString("END OF REPORT\n")
+	return b.String()
+}
+
+// writeReportSection writes a section heading of the report.
+func writeReportSection(b *strings.Builder, title string) {
+	b.WriteString("\n" + title + "\n")
+	b.WriteString(strings.Repeat("-", len(title)) + "\n")
+}
+
+// percentOf formats part as a percentage of whole, e.g. "(98.5%)".
+func percentOf(part, whole float64) string {
+	if whole == 0 {
+		return "(-)"
+	}
+	return fmt.Sprintf("(%.1f%%)", part/whole*100)
+}
+
+// clip shortens s to at most n characters.
+func clip(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-3]) + "..."
+}
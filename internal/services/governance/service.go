@@ -0,0 +1,119 @@
+// Package governance provides overseer planning services for VT-UOS.
+package governance
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/dualauth"
+	"github.com/vtuos/vtuos/internal/journal"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/services/population"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// DefaultPlanningYears is the default horizon of the planning report.
+const DefaultPlanningYears = 20
+
+// Service provides governance planning operations.
+type Service struct {
+	db             *sql.DB
+	vaultNumber    int
+	population     *population.Service
+	residents      *repository.ResidentRepository
+	households     *repository.HouseholdRepository
+	resources      *repository.ResourceRepository
+	facilities     *repository.FacilityRepository
+	audit          *repository.AuditRepository
+	incidents      *repository.SecurityRepository
+	lockdowns      *repository.LockdownRepository
+	rationPolicies *repository.RationPolicyRepository
+	announcements  *repository.AnnouncementRepository
+	vocations      *repository.VocationRepository
+	sessions       *repository.SessionRepository
+	alerts         *repository.AlertRepository
+	dualAuth       *repository.DualAuthRepository
+	authorizer     *dualauth.Authorizer
+	idGenerator    *util.IDGenerator
+	journal        *journal.Journal
+	now            func() time.Time
+}
+
+// NewService creates a new governance service.
+func NewService(db *sql.DB, vaultNumber int) *Service {
+	return &Service{
+		db:             db,
+		vaultNumber:    vaultNumber,
+		population:     population.NewService(db, vaultNumber),
+		residents:      repository.NewResidentRepository(db).ForVault(vaultNumber),
+		households:     repository.NewHouseholdRepository(db).ForVault(vaultNumber),
+		resources:      repository.NewResourceRepository(db).ForVault(vaultNumber),
+		facilities:     repository.NewFacilityRepository(db).ForVault(vaultNumber),
+		audit:          repository.NewAuditRepository(db),
+		incidents:      repository.NewSecurityRepository(db),
+		lockdowns:      repository.NewLockdownRepository(db),
+		rationPolicies: repository.NewRationPolicyRepository(db),
+		announcements:  repository.NewAnnouncementRepository(db).ForVault(vaultNumber),
+		vocations:      repository.NewVocationRepository(db),
+		sessions:       repository.NewSessionRepository(db).ForVault(vaultNumber),
+		alerts:         repository.NewAlertRepository(db).ForVault(vaultNumber),
+		dualAuth:       repository.NewDualAuthRepository(db).ForVault(vaultNumber),
+		authorizer:     dualauth.New(db).ForVault(vaultNumber),
+		idGenerator:    util.NewIDGenerator(),
+		now:            func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// SetClock makes the service record vault state changes in vault time.
+func (s *Service) SetClock(clock *util.VaultClock) {
+	s.now = clock.Now
+}
+
+// PlanningReport is the overseer's long-range planning summary.
+type PlanningReport struct {
+	AsOf       time.Time
+	Years      int
+	Population *population.PopulationProjection
+	Workforce  *population.WorkforceStats
+	Staffing   *population.StaffingForecast
+}
+
+// PlanningReport builds the planning report for the given horizon.
+func (s *Service) PlanningReport(ctx context.Context, asOf time.Time, years int) (*PlanningReport, error) {
+	if years < 1 {
+		years = DefaultPlanningYears
+	}
+
+	projection, err := s.population.ProjectPopulation(ctx, asOf, years)
+	if err != nil {
+		return nil, fmt.Errorf("projecting population: %w", err)
+	}
+
+	workforce, err := s.population.GetWorkforceStats(ctx, asOf)
+	if err != nil {
+		return nil, fmt.Errorf("getting workforce stats: %w", err)
+	}
+
+	staffing, err := s.population.ForecastStaffing(ctx, asOf, years)
+	if err != nil {
+		return nil, fmt.Errorf("forecasting staffing: %w", err)
+	}
+
+	return &PlanningReport{
+		AsOf:       asOf,
+		Years:      years,
+		Population: projection,
+		Workforce:  workforce,
+		Staffing:   staffing,
+	}, nil
+}
+
+// FinalPopulation returns the projected population at the end of the horizon.
+func (r *PlanningReport) FinalPopulation() int {
+	if len(r.Population.Projections) == 0 {
+		return r.Population.CurrentPopulation
+	}
+	return r.Population.Projections[len(r.Population.Projections)-1].Population
+}
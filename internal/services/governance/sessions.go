@@ -0,0 +1,97 @@
+package governance
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+)
+
+// Terminal sessions are bookkeeping of who used a terminal rather than
+// changes to the vault, so they are not journaled: replaying a journal
+// would record sessions that never took place. They are stamped in wall
+// time, as they record people at terminals and the vault clock may be
+// paused, scaled or started over.
+
+// SessionReport is the activity of a vault's terminals over a span of days.
+type SessionReport struct {
+	Since     time.Time
+	Days      int
+	Operators []*models.OperatorActivity
+	Sessions  []*models.TerminalSession // Latest started first
+}
+
+// StartSession starts a terminal session of the operator, or of no one if
+// operatorID is empty.
+func (s *Service) StartSession(ctx context.Context, operatorID string) (*models.TerminalSession, error) {
+	now := sessionNow()
+	session := &models.TerminalSession{
+		ID:           s.idGenerator.NewID(),
+		StartedAt:    now,
+		LastActiveAt: now,
+	}
+	if operatorID != "" {
+		operator, err := s.residents.GetByID(ctx, operatorID)
+		if err != nil {
+			return nil, fmt.Errorf("getting operator: %w", err)
+		}
+		session.OperatorID = &operator.ID
+		session.OperatorRegistry = operator.RegistryNumber
+		session.OperatorName = operator.FullName()
+	}
+	if err := s.sessions.Create(ctx, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// SaveSession saves the modules opened and changes made in a session so far.
+func (s *Service) SaveSession(ctx context.Context, session *models.TerminalSession) error {
+	session.LastActiveAt = activeAt(session)
+	return s.sessions.Update(ctx, session)
+}
+
+// EndSession saves a session and marks it ended.
+func (s *Service) EndSession(ctx context.Context, session *models.TerminalSession) error {
+	now := activeAt(session)
+	session.LastActiveAt = now
+	session.EndedAt = &now
+	return s.sessions.Update(ctx, session)
+}
+
+// sessionNow returns the wall time sessions are stamped with.
+func sessionNow() time.Time {
+	return time.Now().UTC().Truncate(time.Second)
+}
+
+// activeAt returns the time a session is saved at: now, but never before it
+// started, should the system clock have been set back.
+func activeAt(session *models.TerminalSession) time.Time {
+	now := sessionNow()
+	if now.Before(session.StartedAt) {
+		return session.StartedAt
+	}
+	return now
+}
+
+// SessionActivity summarizes the vault's terminal sessions active over the
+// last days, by operator. open is the ID of the session in progress on the
+// asking terminal, if any, which is not counted as unclosed.
+func (s *Service) SessionActivity(ctx context.Context, days int, open string) (*SessionReport, error) {
+	if days < 1 {
+		return nil, fmt.Errorf("%w: days must be positive", repository.ErrValidation)
+	}
+	since := sessionNow().AddDate(0, 0, -days)
+	sessions, err := s.sessions.ListActiveSince(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+	return &SessionReport{
+		Since:     since,
+		Days:      days,
+		Operators: models.SummarizeSessions(sessions, open),
+		Sessions:  sessions,
+	}, nil
+}
@@ -0,0 +1,68 @@
+package inspections
+
+import (
+	"context"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/journal"
+)
+
+// Journaled inspection commands.
+const (
+	CommandCreateTemplate          = "inspections.create_template"
+	CommandInstallDefaultTemplates = "inspections.install_default_templates"
+	CommandScheduleInspection      = "inspections.schedule_inspection"
+	CommandCancelInspection        = "inspections.cancel_inspection"
+	CommandCompleteInspection      = "inspections.complete_inspection"
+)
+
+// Arguments of journaled commands that take more than an input.
+type (
+	idArgs struct {
+		ID string `json:"id"`
+	}
+	installTemplatesArgs struct {
+		FirstDate time.Time `json:"first_date"`
+	}
+	completeArgs struct {
+		ID    string        `json:"id"`
+		Input CompleteInput `json:"input"`
+	}
+)
+
+// SetJournal records the commands the service runs in j.
+func (s *Service) SetJournal(j *journal.Journal) {
+	s.journal = j
+}
+
+// begin starts journaling a command of the service, which covers every
+// vault.
+func (s *Service) begin(ctx context.Context, command string, args any) (context.Context, *journal.Command) {
+	return s.journal.Begin(ctx, 0, command, args)
+}
+
+// Commands returns the handlers that replay the service's journaled
+// commands.
+func (s *Service) Commands() journal.Handlers {
+	return journal.Handlers{
+		CommandCreateTemplate: journal.Handle(func(ctx context.Context, input CreateTemplateInput) error {
+			_, err := s.CreateTemplate(ctx, input)
+			return err
+		}),
+		CommandInstallDefaultTemplates: journal.Handle(func(ctx context.Context, args installTemplatesArgs) error {
+			_, err := s.InstallDefaultTemplates(ctx, args.FirstDate)
+			return err
+		}),
+		CommandScheduleInspection: journal.Handle(func(ctx context.Context, input ScheduleInput) error {
+			_, err := s.ScheduleInspection(ctx, input)
+			return err
+		}),
+		CommandCancelInspection: journal.Handle(func(ctx context.Context, args idArgs) error {
+			return s.CancelInspection(ctx, args.ID)
+		}),
+		CommandCompleteInspection: journal.Handle(func(ctx context.Context, args completeArgs) error {
+			_, err := s.CompleteInspection(ctx, args.ID, args.Input)
+			return err
+		}),
+	}
+}
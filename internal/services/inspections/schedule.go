@@ -0,0 +1,338 @@
+package inspections
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+)
+
+// ScheduleInspection puts an inspection on the vault calendar.
+func (s *Service) ScheduleInspection(ctx context.Context, input ScheduleInput) (_ *models.Inspection, err error) {
+	ctx, cmd := s.begin(ctx, CommandScheduleInspection, input)
+	defer func() { cmd.End(err) }()
+
+	tmpl, err := s.inspections.GetTemplate(ctx, input.TemplateID)
+	if err != nil {
+		return nil, fmt.Errorf("getting template: %w", err)
+	}
+	if !tmpl.IsActive {
+		return nil, fmt.Errorf("%w: template %s is inactive", repository.ErrValidation, tmpl.Code)
+	}
+
+	insp := &models.Inspection{
+		ID:            s.idGenerator.NewID(),
+		TemplateID:    tmpl.ID,
+		Location:      input.Location,
+		ScheduledDate: input.ScheduledDate,
+		AssignedTo:    input.AssignedTo,
+		Status:        models.InspectionStatusScheduled,
+	}
+
+	if err := s.inspections.CreateInspection(ctx, nil, insp); err != nil {
+		return nil, fmt.Errorf("scheduling inspection: %w", err)
+	}
+
+	insp.Template = tmpl
+	return insp, nil
+}
+
+// GetInspection retrieves an inspection with its template and findings.
+func (s *Service) GetInspection(ctx context.Context, id string) (*models.Inspection, error) {
+	insp, err := s.inspections.GetInspection(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	insp.Template, err = s.inspections.GetTemplate(ctx, insp.TemplateID)
+	if err != nil {
+		return nil, fmt.Errorf("getting template: %w", err)
+	}
+
+	insp.Findings, err = s.inspections.ListFindings(ctx, insp.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return insp, nil
+}
+
+// CancelInspection cancels a scheduled inspection.
+func (s *Service) CancelInspection(ctx context.Context, id string) (err error) {
+	ctx, cmd := s.begin(ctx, CommandCancelInspection, idArgs{id})
+	defer func() { cmd.End(err) }()
+
+	insp, err := s.inspections.GetInspection(ctx, id)
+	if err != nil {
+		return err
+	}
+	if insp.Status != models.InspectionStatusScheduled {
+		return fmt.Errorf("%w: inspection is %s, not scheduled", repository.ErrValidation, insp.Status)
+	}
+
+	insp.Status = models.InspectionStatusCancelled
+	if err := s.inspections.UpdateInspection(ctx, nil, insp); err != nil {
+		return fmt.Errorf("cancelling inspection: %w", err)
+	}
+
+	return nil
+}
+
+// ListUpcoming retrieves scheduled inspections due within the given number of
+// days, including any that are already overdue. Templates are attached.
+func (s *Service) ListUpcoming(ctx context.Context, withinDays int) ([]*models.Inspection, error) {
+	status := models.InspectionStatusScheduled
+	to := s.now().AddDate(0, 0, withinDays)
+	upcoming, err := s.inspections.ListInspections(ctx, models.InspectionFilter{
+		Status: &status,
+		To:     &to,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.attachTemplates(ctx, upcoming); err != nil {
+		return nil, err
+	}
+	return upcoming, nil
+}
+
+// OverdueReport lists scheduled inspections past their date, most overdue first.
+func (s *Service) OverdueReport(ctx context.Context) ([]OverdueInspection, error) {
+	now := s.now()
+
+	overdue, err := s.inspections.ListOverdue(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attachTemplates(ctx, overdue); err != nil {
+		return nil, err
+	}
+
+	report := make([]OverdueInspection, 0, len(overdue))
+	for _, insp := range overdue {
+		entry := OverdueInspection{
+			Inspection:  insp,
+			DaysOverdue: insp.DaysOverdue(now),
+		}
+		if insp.Template != nil {
+			entry.TemplateCode = insp.Template.Code
+			entry.TemplateName = insp.Template.Name
+		}
+		report = append(report, entry)
+	}
+
+	return report, nil
+}
+
+// attachTemplates sets Template on each inspection. Checklist items are not loaded.
+func (s *Service) attachTemplates(ctx context.Context, list []*models.Inspection) error {
+	if len(list) == 0 {
+		return nil
+	}
+
+	templates, err := s.inspections.ListTemplates(ctx, false)
+	if err != nil {
+		return err
+	}
+	byID := make(map[string]*models.InspectionTemplate, len(templates))
+	for _, t := range templates {
+		byID[t.ID] = t
+	}
+
+	for _, insp := range list {
+		insp.Template = byID[insp.TemplateID]
+	}
+	return nil
+}
+
+// CompleteInspection records the outcome of a scheduled inspection. Each
+// finding is stored and, depending on its action, raises a corrective work
+// order or a security incident in the same transaction. The inspection fails
+// if any finding is MAJOR or CRITICAL or is against a critical checklist
+// item. When the template is active, the next inspection is scheduled one
+// interval after completion.
+func (s *Service) CompleteInspection(ctx context.Context, id string, input CompleteInput) (_ *models.Inspection, err error) {
+	ctx, cmd := s.begin(ctx, CommandCompleteInspection, completeArgs{id, input})
+	defer func() { cmd.End(err) }()
+
+	insp, err := s.inspections.GetInspection(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if insp.Status != models.InspectionStatusScheduled {
+		return nil, fmt.Errorf("%w: inspection is %s, not scheduled", repository.ErrValidation, insp.Status)
+	}
+
+	tmpl, err := s.inspections.GetTemplate(ctx, insp.TemplateID)
+	if err != nil {
+		return nil, fmt.Errorf("getting template: %w", err)
+	}
+	critical := make(map[string]bool, len(tmpl.Items))
+	for _, item := range tmpl.Items {
+		critical[item.ID] = item.IsCritical
+	}
+
+	now := s.now()
+
+	// Resolve everything that needs a read before the transaction takes the
+	// connection: work order systems and incident numbers.
+	findings := make([]*models.InspectionFinding, 0, len(input.Findings))
+	workOrders := make(map[int]*models.MaintenanceRecord)
+	incidents := make(map[int]*models.SecurityIncident)
+	var nextIncident string
+	passed := true
+
+	for i, f := range input.Findings {
+		action := f.Action
+		if action == "" {
+			action = models.FindingActionNone
+		}
+		finding := &models.InspectionFinding{
+			ID:              s.idGenerator.NewID(),
+			InspectionID:    insp.ID,
+			ChecklistItemID: f.ChecklistItemID,
+			Severity:        f.Severity,
+			Description:     f.Description,
+			Action:          action,
+		}
+		if err := finding.Validate(); err != nil {
+			return nil, fmt.Errorf("finding %d: %w", i+1, err)
+		}
+		if f.ChecklistItemID != nil {
+			isCritical, ok := critical[*f.ChecklistItemID]
+			if !ok {
+				return nil, fmt.Errorf("%w: finding %d: checklist item not on template %s", repository.ErrValidation, i+1, tmpl.Code)
+			}
+			if isCritical {
+				passed = false
+			}
+		}
+		if f.Severity == models.SeverityMajor || f.Severity == models.SeverityCritical {
+			passed = false
+		}
+
+		summary := fmt.Sprintf("%s: %s", tmpl.Name, f.Description)
+
+		switch action {
+		case models.FindingActionWorkOrder:
+			if f.SystemCode == "" {
+				return nil, fmt.Errorf("%w: finding %d: work order requires a system code", repository.ErrValidation, i+1)
+			}
+			sys, err := s.facilities.GetSystemByCode(ctx, f.SystemCode)
+			if err != nil {
+				return nil, fmt.Errorf("finding %d: %w", i+1, err)
+			}
+			workOrders[i] = &models.MaintenanceRecord{
+				ID:              s.idGenerator.NewID(),
+				SystemID:        sys.ID,
+				MaintenanceType: models.MaintenanceTypeCorrective,
+				Description:     summary,
+				ScheduledDate:   &now,
+				RaisedAt:        &now,
+			}
+			finding.MaintenanceRecordID = &workOrders[i].ID
+
+		case models.FindingActionIncident:
+			if nextIncident == "" {
+				nextIncident, err = s.security.GetNextIncidentNumber(ctx, now.Year())
+				if err != nil {
+					return nil, fmt.Errorf("generating incident number: %w", err)
+				}
+			} else {
+				nextIncident, err = incrementIncidentNumber(nextIncident)
+				if err != nil {
+					return nil, err
+				}
+			}
+			incidents[i] = &models.SecurityIncident{
+				ID:             s.idGenerator.NewID(),
+				IncidentNumber: nextIncident,
+				IncidentType:   models.IncidentTypeOther,
+				Severity:       f.Severity,
+				Description:    summary,
+				LocationDetail: insp.Location,
+				ReportedBy:     input.CompletedBy,
+				Status:         models.IncidentStatusOpen,
+				OccurredAt:     now,
+				ReportedAt:     now,
+			}
+			finding.IncidentID = &incidents[i].ID
+		}
+
+		findings = append(findings, finding)
+	}
+
+	var next *models.Inspection
+	if tmpl.IsActive {
+		next = &models.Inspection{
+			ID:            s.idGenerator.NewID(),
+			TemplateID:    tmpl.ID,
+			Location:      insp.Location,
+			ScheduledDate: now.AddDate(0, 0, tmpl.IntervalDays),
+			AssignedTo:    insp.AssignedTo,
+			Status:        models.InspectionStatusScheduled,
+		}
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for i, finding := range findings {
+		if wo := workOrders[i]; wo != nil {
+			if err := s.facilities.CreateMaintenanceRecord(ctx, tx, wo); err != nil {
+				return nil, fmt.Errorf("finding %d: creating work order: %w", i+1, err)
+			}
+		}
+		if inc := incidents[i]; inc != nil {
+			if err := s.security.CreateIncident(ctx, tx, inc); err != nil {
+				return nil, fmt.Errorf("finding %d: creating incident: %w", i+1, err)
+			}
+		}
+		if err := s.inspections.CreateFinding(ctx, tx, finding); err != nil {
+			return nil, fmt.Errorf("finding %d: %w", i+1, err)
+		}
+	}
+
+	insp.Status = models.InspectionStatusCompleted
+	insp.CompletedAt = &now
+	insp.CompletedBy = input.CompletedBy
+	insp.Passed = &passed
+	insp.Notes = input.Notes
+	if err := s.inspections.UpdateInspection(ctx, tx, insp); err != nil {
+		return nil, fmt.Errorf("completing inspection: %w", err)
+	}
+
+	if next != nil {
+		if err := s.inspections.CreateInspection(ctx, tx, next); err != nil {
+			return nil, fmt.Errorf("scheduling next inspection: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("committing transaction: %w", err)
+	}
+
+	insp.Template = tmpl
+	insp.Findings = findings
+	return insp, nil
+}
+
+// incrementIncidentNumber returns the incident number after num.
+func incrementIncidentNumber(num string) (string, error) {
+	i := strings.LastIndex(num, "-")
+	if i < 0 {
+		return "", fmt.Errorf("invalid incident number: %s", num)
+	}
+	seq, err := strconv.Atoi(num[i+1:])
+	if err != nil {
+		return "", fmt.Errorf("invalid incident number: %s", num)
+	}
+	return fmt.Sprintf("%s%04d", num[:i+1], seq+1), nil
+}
@@ -0,0 +1,148 @@
+// Package inspections provides vault inspection scheduling and recording for VT-UOS.
+package inspections
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/journal"
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// Service provides inspection operations.
+type Service struct {
+	db          *sql.DB
+	inspections *repository.InspectionRepository
+	facilities  *repository.FacilityRepository
+	security    *repository.SecurityRepository
+	idGenerator *util.IDGenerator
+	journal     *journal.Journal
+	now         func() time.Time
+}
+
+// NewService creates a new inspection service.
+func NewService(db *sql.DB) *Service {
+	return &Service{
+		db:          db,
+		inspections: repository.NewInspectionRepository(db),
+		facilities:  repository.NewFacilityRepository(db),
+		security:    repository.NewSecurityRepository(db),
+		idGenerator: util.NewIDGenerator(),
+		now:         func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// SetClock makes the service schedule against the vault calendar.
+func (s *Service) SetClock(clock *util.VaultClock) {
+	s.now = clock.Now
+}
+
+// ============================================================================
+// TEMPLATES
+// ============================================================================
+
+// CreateTemplate creates a new inspection template with its checklist.
+func (s *Service) CreateTemplate(ctx context.Context, input CreateTemplateInput) (_ *models.InspectionTemplate, err error) {
+	ctx, cmd := s.begin(ctx, CommandCreateTemplate, input)
+	defer func() { cmd.End(err) }()
+
+	tmpl := s.newTemplate(input)
+
+	if err := s.inspections.CreateTemplate(ctx, nil, tmpl); err != nil {
+		return nil, fmt.Errorf("creating template: %w", err)
+	}
+
+	return tmpl, nil
+}
+
+// GetTemplate retrieves a template and its checklist by ID.
+func (s *Service) GetTemplate(ctx context.Context, id string) (*models.InspectionTemplate, error) {
+	return s.inspections.GetTemplate(ctx, id)
+}
+
+// GetTemplateByCode retrieves a template and its checklist by code.
+func (s *Service) GetTemplateByCode(ctx context.Context, code string) (*models.InspectionTemplate, error) {
+	return s.inspections.GetTemplateByCode(ctx, code)
+}
+
+// ListTemplates retrieves all active templates.
+func (s *Service) ListTemplates(ctx context.Context) ([]*models.InspectionTemplate, error) {
+	return s.inspections.ListTemplates(ctx, true)
+}
+
+// InstallDefaultTemplates creates any of the standard templates that do not
+// exist yet and schedules their first inspection on firstDate. It returns the
+// number of templates created.
+func (s *Service) InstallDefaultTemplates(ctx context.Context, firstDate time.Time) (_ int, err error) {
+	ctx, cmd := s.begin(ctx, CommandInstallDefaultTemplates, installTemplatesArgs{firstDate})
+	defer func() { cmd.End(err) }()
+
+	var missing []*models.InspectionTemplate
+	for _, input := range DefaultTemplates() {
+		_, err := s.inspections.GetTemplateByCode(ctx, input.Code)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, repository.ErrNotFound) {
+			return 0, fmt.Errorf("checking template %s: %w", input.Code, err)
+		}
+		missing = append(missing, s.newTemplate(input))
+	}
+	if len(missing) == 0 {
+		return 0, nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, tmpl := range missing {
+		if err := s.inspections.CreateTemplate(ctx, tx, tmpl); err != nil {
+			return 0, fmt.Errorf("creating template %s: %w", tmpl.Code, err)
+		}
+		insp := &models.Inspection{
+			ID:            s.idGenerator.NewID(),
+			TemplateID:    tmpl.ID,
+			ScheduledDate: firstDate,
+			Status:        models.InspectionStatusScheduled,
+		}
+		if err := s.inspections.CreateInspection(ctx, tx, insp); err != nil {
+			return 0, fmt.Errorf("scheduling %s: %w", tmpl.Code, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("committing transaction: %w", err)
+	}
+
+	return len(missing), nil
+}
+
+func (s *Service) newTemplate(input CreateTemplateInput) *models.InspectionTemplate {
+	tmpl := &models.InspectionTemplate{
+		ID:           s.idGenerator.NewID(),
+		Code:         input.Code,
+		Name:         input.Name,
+		Category:     input.Category,
+		Description:  input.Description,
+		IntervalDays: input.IntervalDays,
+		IsActive:     true,
+	}
+	for i, item := range input.Items {
+		tmpl.Items = append(tmpl.Items, &models.InspectionChecklistItem{
+			ID:          s.idGenerator.NewID(),
+			TemplateID:  tmpl.ID,
+			Sequence:    i + 1,
+			Description: item.Description,
+			IsCritical:  item.IsCritical,
+		})
+	}
+	return tmpl
+}
@@ -0,0 +1,107 @@
+package inspections
+
+import (
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// CreateTemplateInput contains data for creating an inspection template.
+type CreateTemplateInput struct {
+	Code         string
+	Name         string
+	Category     models.InspectionCategory
+	Description  string
+	IntervalDays int
+	Items        []ChecklistItemInput
+}
+
+// ChecklistItemInput describes one checklist item on a new template.
+type ChecklistItemInput struct {
+	Description string
+	IsCritical  bool // a finding against this item fails the inspection
+}
+
+// ScheduleInput contains data for scheduling an inspection.
+type ScheduleInput struct {
+	TemplateID    string
+	Location      string
+	ScheduledDate time.Time
+	AssignedTo    *string
+}
+
+// CompleteInput records the outcome of an inspection.
+type CompleteInput struct {
+	CompletedBy *string
+	Notes       string
+	Findings    []FindingInput
+}
+
+// FindingInput describes a deficiency found during an inspection.
+//
+// Action WORK_ORDER raises a corrective maintenance record and requires
+// SystemCode. Action INCIDENT raises a security incident of type OTHER.
+type FindingInput struct {
+	ChecklistItemID *string
+	Severity        models.Severity
+	Description     string
+	Action          models.FindingAction
+	SystemCode      string
+}
+
+// OverdueInspection is a line in the overdue inspection report.
+type OverdueInspection struct {
+	Inspection   *models.Inspection
+	TemplateCode string
+	TemplateName string
+	DaysOverdue  int
+}
+
+// DefaultTemplates returns the standard vault inspection templates.
+func DefaultTemplates() []CreateTemplateInput {
+	return []CreateTemplateInput{
+		{
+			Code:         "SAFETY-WALK",
+			Name:         "Safety Walk",
+			Category:     models.InspectionCategorySafetyWalk,
+			Description:  "Walk-through of common areas and corridors for hazards",
+			IntervalDays: 7,
+			Items: []ChecklistItemInput{
+				{Description: "Emergency exits and bulkhead doors unobstructed", IsCritical: true},
+				{Description: "Fire suppression equipment charged and tagged", IsCritical: true},
+				{Description: "Emergency lighting functional"},
+				{Description: "No exposed wiring or leaking conduits", IsCritical: true},
+				{Description: "Walkways dry and free of debris"},
+				{Description: "Radiation signage and dosimeter stations in place"},
+			},
+		},
+		{
+			Code:         "FOOD-STORAGE",
+			Name:         "Food Storage Audit",
+			Category:     models.InspectionCategoryFoodStorage,
+			Description:  "Audit of food stores for spoilage, contamination, and rotation",
+			IntervalDays: 14,
+			Items: []ChecklistItemInput{
+				{Description: "Cold storage within temperature range", IsCritical: true},
+				{Description: "No signs of pests or contamination", IsCritical: true},
+				{Description: "Stock rotated first-expiring-first-out"},
+				{Description: "Expired stock segregated and logged"},
+				{Description: "Containers sealed and labelled"},
+			},
+		},
+		{
+			Code:         "QUARTERS",
+			Name:         "Quarters Inspection",
+			Category:     models.InspectionCategoryQuarters,
+			Description:  "Inspection of residential quarters for habitability",
+			IntervalDays: 30,
+			Items: []ChecklistItemInput{
+				{Description: "Air vents clear and circulating", IsCritical: true},
+				{Description: "Water fixtures free of leaks"},
+				{Description: "Occupancy matches assignment"},
+				{Description: "No contraband or unauthorized modifications"},
+				{Description: "Sanitation acceptable"},
+			},
+		},
+	}
+}
@@ -0,0 +1,57 @@
+package medical
+
+import (
+	"context"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/events"
+	"github.com/vtuos/vtuos/internal/journal"
+)
+
+// Journaled medical commands.
+const (
+	CommandRecordExposure   = "medical.record_exposure"
+	CommandDecontaminate    = "medical.decontaminate"
+	CommandApplyEnvironment = "medical.apply_environment"
+)
+
+// applyEnvironmentArgs are the arguments of a journaled ApplyEnvironment.
+type applyEnvironmentArgs struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// SetJournal records the commands the service runs in j.
+func (s *Service) SetJournal(j *journal.Journal) {
+	s.journal = j
+}
+
+// SetEvents publishes the events of the stock the service draws, such as
+// lots depleted, on bus.
+func (s *Service) SetEvents(bus *events.Bus) {
+	s.resources.SetEvents(bus)
+}
+
+// begin starts journaling a command of the service.
+func (s *Service) begin(ctx context.Context, command string, args any) (context.Context, *journal.Command) {
+	return s.journal.Begin(ctx, s.vault, command, args)
+}
+
+// Commands returns the handlers that replay the service's journaled
+// commands.
+func (s *Service) Commands() journal.Handlers {
+	return journal.Handlers{
+		CommandRecordExposure: journal.Handle(func(ctx context.Context, input ExposureInput) error {
+			_, _, err := s.RecordExposure(ctx, input)
+			return err
+		}),
+		CommandDecontaminate: journal.Handle(func(ctx context.Context, input TreatmentInput) error {
+			_, _, err := s.Decontaminate(ctx, input)
+			return err
+		}),
+		CommandApplyEnvironment: journal.Handle(func(ctx context.Context, args applyEnvironmentArgs) error {
+			_, err := s.ApplyEnvironment(ctx, args.From, args.To)
+			return err
+		}),
+	}
+}
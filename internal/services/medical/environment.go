@@ -0,0 +1,239 @@
+package medical
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/simulation"
+)
+
+// EnvironmentEffect is what a sector's readings did to the health of the
+// residents housed there.
+type EnvironmentEffect struct {
+	Sector   string
+	Metric   models.EnvironmentMetric
+	Level    models.EnvironmentLevel
+	Flagged  int     // Residents given a condition, or one at a new severity
+	Resolved int     // Residents whose condition was resolved
+	DoseMSv  float64 // Radiation dose each resident received
+	Exposed  int     // Residents who received it
+}
+
+// String describes the effect, e.g. "Sector A CO2 WARNING: 42 resident(s)
+// flagged ENV-HYPERCAPNIA (MODERATE)".
+func (e *EnvironmentEffect) String() string {
+	if e.Metric == models.MetricRadiation {
+		return fmt.Sprintf("Sector %s RADIATION %s: %d resident(s) received %.3f mSv",
+			e.Sector, e.Level, e.Exposed, e.DoseMSv)
+	}
+	if e.Flagged == 0 {
+		return fmt.Sprintf("Sector %s %s %s: %s resolved for %d resident(s)",
+			e.Sector, e.Metric, e.Level, e.Metric.ConditionCode(), e.Resolved)
+	}
+	return fmt.Sprintf("Sector %s %s %s: %d resident(s) flagged %s (%s)",
+		e.Sector, e.Metric, e.Level, e.Flagged, e.Metric.ConditionCode(), e.Level.ConditionSeverity())
+}
+
+// ApplyEnvironment applies the sector readings taken after from and up to
+// to to the health of the active residents housed in each sector. The
+// latest oxygen, carbon dioxide and temperature readings of a sector that
+// is, or was at from, out of its safe range bring each resident's
+// condition for the metric in line: a MODERATE condition for a WARNING, a
+// SEVERE one for a CRITICAL reading, resolved once back in range. Each
+// radiation reading out of range delivers a sample interval's dose, which
+// is recorded as a radiation exposure.
+func (s *Service) ApplyEnvironment(ctx context.Context, from, to time.Time) (_ []*EnvironmentEffect, err error) {
+	ctx, cmd := s.begin(ctx, CommandApplyEnvironment, applyEnvironmentArgs{from, to})
+	defer func() { cmd.End(err) }()
+
+	readings, err := s.environment.ListReadings(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if len(readings) == 0 {
+		return nil, nil
+	}
+	before, err := s.environment.LatestReadings(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	previous := make(map[string]models.EnvironmentLevel, len(before))
+	for _, r := range before {
+		previous[r.Sector+"/"+string(r.Metric)] = r.Level
+	}
+
+	// Latest reading of each sector and metric, and the radiation dose of
+	// each sector, in reading order.
+	var order []string
+	latest := make(map[string]*models.EnvironmentReading)
+	doses := make(map[string]*EnvironmentEffect)
+	for _, r := range readings {
+		key := r.Sector + "/" + string(r.Metric)
+		if r.Metric == models.MetricRadiation {
+			if !r.Level.Breached() {
+				continue
+			}
+			effect := doses[key]
+			if effect == nil {
+				effect = &EnvironmentEffect{Sector: r.Sector, Metric: r.Metric}
+				doses[key] = effect
+				order = append(order, key)
+			}
+			effect.Level = r.Level
+			effect.DoseMSv += r.Value * models.EnvironmentSampleInterval.Hours()
+			continue
+		}
+		if latest[key] == nil {
+			order = append(order, key)
+		}
+		latest[key] = r
+	}
+
+	var effects []*EnvironmentEffect
+	for _, key := range order {
+		if r := latest[key]; r != nil {
+			if !r.Level.Breached() && !previous[key].Breached() {
+				continue
+			}
+			effect, err := s.applyCondition(ctx, r, to)
+			if err != nil {
+				return effects, err
+			}
+			if effect.Flagged > 0 || effect.Resolved > 0 {
+				effects = append(effects, effect)
+			}
+			continue
+		}
+
+		effect := doses[key]
+		residents, err := s.environment.ListSectorResidents(ctx, effect.Sector)
+		if err != nil {
+			return effects, err
+		}
+		for _, id := range residents {
+			_, _, err := s.RecordExposure(ctx, ExposureInput{
+				ResidentID: id,
+				Source:     fmt.Sprintf("Sector %s ambient radiation", effect.Sector),
+				DoseMSv:    effect.DoseMSv,
+				Date:       to,
+			})
+			if err != nil {
+				return effects, fmt.Errorf("exposing resident %s: %w", id, err)
+			}
+			effect.Exposed++
+		}
+		if effect.Exposed > 0 {
+			effects = append(effects, effect)
+		}
+	}
+	return effects, nil
+}
+
+// applyCondition brings the condition of a reading's metric of every active
+// resident housed in its sector in line with the reading's level.
+func (s *Service) applyCondition(ctx context.Context, r *models.EnvironmentReading, at time.Time) (*EnvironmentEffect, error) {
+	effect := &EnvironmentEffect{Sector: r.Sector, Metric: r.Metric, Level: r.Level}
+	residents, err := s.environment.ListSectorResidents(ctx, r.Sector)
+	if err != nil {
+		return nil, err
+	}
+
+	code, severity := r.Metric.ConditionCode(), r.Level.ConditionSeverity()
+	open := make(map[string]*models.MedicalCondition)
+	for _, id := range residents {
+		conds, err := s.conditions.ListOpenConditions(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range conds {
+			if c.ConditionCode == code {
+				open[id] = c
+			}
+		}
+	}
+
+	err = repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		for _, id := range residents {
+			if open := open[id]; open != nil {
+				if open.Severity == severity {
+					continue
+				}
+				if err := s.conditions.ResolveCondition(ctx, tx, open.ID, at); err != nil {
+					return err
+				}
+				if severity == "" {
+					effect.Resolved++
+				}
+			}
+			if severity == "" {
+				continue
+			}
+
+			flag := &models.MedicalCondition{
+				ID:            s.idGenerator.NewID(),
+				ResidentID:    id,
+				ConditionCode: code,
+				ConditionName: fmt.Sprintf("Environmental exposure: %s %s", r.Metric, r.Level),
+				OnsetDate:     at,
+				Severity:      severity,
+				TreatmentPlan: "Relocate from sector until readings are back in range",
+				Notes:         "Sector " + r.String(),
+			}
+			if err := s.conditions.CreateCondition(ctx, tx, flag); err != nil {
+				return err
+			}
+			effect.Flagged++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return effect, nil
+}
+
+// EnvironmentHealth is the simulation hook that applies the sector
+// environment readings taken since its last advance to residents' health.
+// Register it after the facilities environment monitor so that it sees
+// each advance's readings.
+type EnvironmentHealth struct {
+	service *Service
+}
+
+// EnvironmentHealth creates the environment health hook.
+func (s *Service) EnvironmentHealth() *EnvironmentHealth {
+	return &EnvironmentHealth{service: s}
+}
+
+// Name implements simulation.Hook.
+func (h *EnvironmentHealth) Name() string {
+	return "environmental health"
+}
+
+// Advance implements simulation.Hook. Residents flagged or exposed raise a
+// warning, conditions resolved an informational event.
+func (h *EnvironmentHealth) Advance(ctx context.Context, from, to time.Time) ([]simulation.Event, error) {
+	effects, err := h.service.ApplyEnvironment(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]simulation.Event, 0, len(effects))
+	for _, e := range effects {
+		level := simulation.EventWarning
+		if e.Flagged == 0 && e.Exposed == 0 {
+			level = simulation.EventInfo
+		}
+		events = append(events, simulation.Event{
+			Time:    to,
+			Level:   level,
+			Source:  h.Name(),
+			Message: e.String(),
+		})
+	}
+	return events, nil
+}
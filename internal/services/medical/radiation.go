@@ -0,0 +1,312 @@
+package medical
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/services/resources"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// RadAwayItemCode is the resource item decontamination draws by default.
+const RadAwayItemCode = "MED-RADY-001"
+
+// DecontaminationMSvPerUnit is the dose one unit of a decontamination item
+// purges.
+const DecontaminationMSvPerUnit = 100.0
+
+// ExposureInput contains data for recording a radiation exposure.
+type ExposureInput struct {
+	ResidentID string
+	Source     string
+	DoseMSv    float64
+	Date       time.Time // Defaults to now
+	RecordedBy *string
+	Notes      string
+}
+
+// TreatmentInput contains data for recording a decontamination treatment.
+type TreatmentInput struct {
+	ResidentID string
+	ItemCode   string  // Defaults to RadAwayItemCode
+	Quantity   float64 // Units administered, defaults to 1
+	Date       time.Time
+	ProviderID *string
+	Notes      string
+}
+
+// DoseChange is a resident's dose after an exposure or treatment, with the
+// health flag it leaves open.
+type DoseChange struct {
+	Dose     *models.RadiationDose
+	Previous models.RadiationLevel
+	Flag     *models.MedicalCondition // Open RAD-EXPOSURE condition, nil if none
+}
+
+// Escalated returns true if the change raised the resident's level.
+func (c *DoseChange) Escalated() bool {
+	return c.Dose.Level.Rank() > c.Previous.Rank()
+}
+
+// RecordExposure records a dose a living resident received. Crossing a
+// threshold opens a RAD-EXPOSURE condition at the new level's severity,
+// resolving the condition of the previous level.
+func (s *Service) RecordExposure(ctx context.Context, input ExposureInput) (_ *models.RadiationExposure, _ *DoseChange, err error) {
+	ctx, cmd := s.begin(ctx, CommandRecordExposure, input)
+	defer func() { cmd.End(err) }()
+
+	exp := &models.RadiationExposure{
+		ID:           s.idGenerator.NewID(),
+		ResidentID:   input.ResidentID,
+		Source:       input.Source,
+		DoseMSv:      input.DoseMSv,
+		ExposureDate: input.Date,
+		RecordedBy:   input.RecordedBy,
+		Notes:        input.Notes,
+	}
+	if exp.ExposureDate.IsZero() {
+		exp.ExposureDate = s.now()
+	}
+	if err := exp.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", repository.ErrValidation, err)
+	}
+
+	resident, err := s.livingResident(ctx, exp.ResidentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	dose, flag, err := s.currentDose(ctx, resident)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	change := &DoseChange{Dose: dose, Previous: dose.Level}
+	dose.ExposedMSv += exp.DoseMSv
+	dose.Exposures++
+	if dose.LastExposure == nil || exp.ExposureDate.After(*dose.LastExposure) {
+		dose.LastExposure = &exp.ExposureDate
+	}
+	dose.Level = models.RadiationLevelFor(dose.CumulativeMSv())
+
+	err = repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		if err := s.radiation.CreateExposure(ctx, tx, exp); err != nil {
+			return err
+		}
+		change.Flag, err = s.updateFlag(ctx, tx, dose, flag, exp.ExposureDate)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return exp, change, nil
+}
+
+// Decontaminate records a decontamination treatment of a living resident
+// carrying a dose. The treatment consumes its units of the item from stock,
+// soonest-expiring first, and purges DecontaminationMSvPerUnit per unit, up
+// to the dose carried. Falling below a threshold resolves the RAD-EXPOSURE
+// condition, opening one at the lower level's severity if still flagged.
+func (s *Service) Decontaminate(ctx context.Context, input TreatmentInput) (_ *models.DecontaminationTreatment, _ *DoseChange, err error) {
+	ctx, cmd := s.begin(ctx, CommandDecontaminate, input)
+	defer func() { cmd.End(err) }()
+
+	if input.ItemCode == "" {
+		input.ItemCode = RadAwayItemCode
+	}
+	if input.Quantity == 0 {
+		input.Quantity = 1
+	}
+	if input.Date.IsZero() {
+		input.Date = s.now()
+	}
+
+	resident, err := s.livingResident(ctx, input.ResidentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	item, err := s.resources.GetItemByCode(ctx, input.ItemCode)
+	if err != nil {
+		return nil, nil, fmt.Errorf("treatment item %s: %w", input.ItemCode, err)
+	}
+	dose, flag, err := s.currentDose(ctx, resident)
+	if err != nil {
+		return nil, nil, err
+	}
+	if dose.CumulativeMSv() <= 0 {
+		return nil, nil, fmt.Errorf("%w: resident %s carries no radiation dose", repository.ErrValidation, resident.RegistryNumber)
+	}
+
+	treatment := &models.DecontaminationTreatment{
+		ID:             s.idGenerator.NewID(),
+		ResidentID:     resident.ID,
+		ItemID:         item.ID,
+		Quantity:       input.Quantity,
+		DoseReducedMSv: min(input.Quantity*DecontaminationMSvPerUnit, dose.CumulativeMSv()),
+		TreatmentDate:  input.Date,
+		ProviderID:     input.ProviderID,
+		Notes:          input.Notes,
+	}
+	if err := treatment.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", repository.ErrValidation, err)
+	}
+
+	consumption := resources.ConsumptionInput{
+		ItemID:            item.ID,
+		Quantity:          treatment.Quantity,
+		Reason:            "Decontamination of " + resident.RegistryNumber,
+		AuthorizedBy:      input.ProviderID,
+		RelatedEntityType: "RESIDENT",
+		RelatedEntityID:   resident.ID,
+		Policy:            models.ConsumptionPolicyFEFO,
+	}
+
+	change := &DoseChange{Dose: dose, Previous: dose.Level}
+	dose.PurgedMSv += treatment.DoseReducedMSv
+	dose.Level = models.RadiationLevelFor(dose.CumulativeMSv())
+
+	err = repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		if err := s.resources.ApplyConsumption(ctx, tx, consumption); err != nil {
+			return fmt.Errorf("drawing %s: %w", item.ItemCode, err)
+		}
+		if err := s.radiation.CreateTreatment(ctx, tx, treatment); err != nil {
+			return err
+		}
+		change.Flag, err = s.updateFlag(ctx, tx, dose, flag, treatment.TreatmentDate)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return treatment, change, nil
+}
+
+// GetDose retrieves a resident's cumulative dose.
+func (s *Service) GetDose(ctx context.Context, residentID string) (*models.RadiationDose, error) {
+	resident, err := s.residents.GetByID(ctx, residentID)
+	if err != nil {
+		return nil, err
+	}
+	dose, err := s.radiation.GetDose(ctx, residentID)
+	if err != nil {
+		return nil, err
+	}
+	dose.Resident = resident
+	return dose, nil
+}
+
+// ListFlagged retrieves the doses of active residents at or above
+// RadiationFlagMSv, highest first.
+func (s *Service) ListFlagged(ctx context.Context) ([]*models.RadiationDose, error) {
+	doses, err := s.radiation.ListDoses(ctx, models.RadiationFlagMSv)
+	if err != nil {
+		return nil, err
+	}
+	for _, dose := range doses {
+		if dose.Resident, err = s.residents.GetByID(ctx, dose.ResidentID); err != nil {
+			return nil, fmt.Errorf("getting resident: %w", err)
+		}
+	}
+	return doses, nil
+}
+
+// ListExposures retrieves a resident's exposure events, most recent first,
+// without their notes for an operator lacking the PII clearance.
+func (s *Service) ListExposures(ctx context.Context, residentID string) ([]*models.RadiationExposure, error) {
+	exposures, err := s.radiation.ListExposures(ctx, residentID)
+	if err != nil {
+		return nil, err
+	}
+	if !util.Cleared(ctx, s.piiClearance) {
+		for _, e := range exposures {
+			e.Redact()
+		}
+	}
+	return exposures, nil
+}
+
+// ListTreatments retrieves a resident's decontamination treatments, most
+// recent first, without their notes for an operator lacking the PII
+// clearance.
+func (s *Service) ListTreatments(ctx context.Context, residentID string) ([]*models.DecontaminationTreatment, error) {
+	treatments, err := s.radiation.ListTreatments(ctx, residentID)
+	if err != nil {
+		return nil, err
+	}
+	if !util.Cleared(ctx, s.piiClearance) {
+		for _, t := range treatments {
+			t.Redact()
+		}
+	}
+	return treatments, nil
+}
+
+// livingResident retrieves a resident who can be exposed or treated.
+func (s *Service) livingResident(ctx context.Context, residentID string) (*models.Resident, error) {
+	resident, err := s.residents.GetByID(ctx, residentID)
+	if err != nil {
+		return nil, err
+	}
+	if !resident.IsAlive() {
+		return nil, fmt.Errorf("%w: resident %s is deceased", repository.ErrValidation, resident.RegistryNumber)
+	}
+	return resident, nil
+}
+
+// currentDose retrieves a resident's dose and open RAD-EXPOSURE condition,
+// if any.
+func (s *Service) currentDose(ctx context.Context, resident *models.Resident) (*models.RadiationDose, *models.MedicalCondition, error) {
+	dose, err := s.radiation.GetDose(ctx, resident.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	dose.Resident = resident
+
+	conds, err := s.conditions.ListOpenConditions(ctx, resident.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, c := range conds {
+		if c.ConditionCode == models.RadiationConditionCode {
+			return dose, c, nil
+		}
+	}
+	return dose, nil, nil
+}
+
+// updateFlag brings the open RAD-EXPOSURE condition in line with the dose's
+// level within tx: it is kept while the severity holds, and otherwise
+// resolved and replaced by one at the new severity while still flagged. It
+// returns the condition left open.
+func (s *Service) updateFlag(ctx context.Context, tx *sql.Tx, dose *models.RadiationDose, open *models.MedicalCondition, at time.Time) (*models.MedicalCondition, error) {
+	severity := dose.Level.ConditionSeverity()
+	if open != nil {
+		if open.Severity == severity {
+			return open, nil
+		}
+		if err := s.conditions.ResolveCondition(ctx, tx, open.ID, at); err != nil {
+			return nil, err
+		}
+	}
+	if !dose.Level.Flagged() {
+		return nil, nil
+	}
+
+	flag := &models.MedicalCondition{
+		ID:            s.idGenerator.NewID(),
+		ResidentID:    dose.ResidentID,
+		ConditionCode: models.RadiationConditionCode,
+		ConditionName: fmt.Sprintf("Radiation exposure (%s)", dose.Level),
+		OnsetDate:     at,
+		Severity:      severity,
+		TreatmentPlan: "Decontamination",
+		Notes:         fmt.Sprintf("Cumulative dose %.0f mSv", dose.CumulativeMSv()),
+	}
+	if err := s.conditions.CreateCondition(ctx, tx, flag); err != nil {
+		return nil, err
+	}
+	return flag, nil
+}
@@ -0,0 +1,66 @@
+// Package medical provides medical services for VT-UOS.
+package medical
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/journal"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/services/resources"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// Service provides medical operations.
+type Service struct {
+	db           *sql.DB
+	radiation    *repository.RadiationRepository
+	conditions   *repository.MedicalRepository
+	environment  *repository.EnvironmentRepository
+	residents    *repository.ResidentRepository
+	resources    *resources.Service
+	idGenerator  *util.IDGenerator
+	journal      *journal.Journal
+	vault        int
+	piiClearance int // Clearance needed to read medical notes, 0 for none
+	now          func() time.Time
+}
+
+// NewService creates a new medical service.
+func NewService(db *sql.DB) *Service {
+	return &Service{
+		db:          db,
+		radiation:   repository.NewRadiationRepository(db),
+		conditions:  repository.NewMedicalRepository(db),
+		environment: repository.NewEnvironmentRepository(db),
+		residents:   repository.NewResidentRepository(db),
+		resources:   resources.NewService(db),
+		idGenerator: util.NewIDGenerator(),
+		now:         func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// SetClock makes the service date records and the treatment stock it draws
+// with vault time.
+func (s *Service) SetClock(clock *util.VaultClock) {
+	s.now = clock.Now
+	s.resources.SetClock(clock)
+}
+
+// SetVault limits the service's dose lists, the sector environment readings
+// it applies and the treatment stock it draws to the vault with the given
+// number. A new service covers every vault.
+func (s *Service) SetVault(vault int) {
+	s.vault = vault
+	s.radiation = s.radiation.ForVault(vault)
+	s.environment = s.environment.ForVault(vault)
+	s.residents = s.residents.ForVault(vault)
+	s.resources.SetVault(vault)
+}
+
+// SetPIIClearance sets the clearance an operator needs to read the medical
+// officer's notes on exposures and treatments. Below it the service returns
+// them as models.Redacted. 0, the default, shows them to everyone.
+func (s *Service) SetPIIClearance(clearance int) {
+	s.piiClearance = clearance
+}
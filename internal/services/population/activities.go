@@ -0,0 +1,460 @@
+package population
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/simulation"
+)
+
+// VenueInput contains data for opening a venue.
+type VenueInput struct {
+	Code     string
+	Name     string
+	Type     models.VenueType
+	Sector   string
+	Capacity int
+}
+
+// ActivityInput contains data for scheduling an activity.
+type ActivityInput struct {
+	Code          string
+	Name          string
+	Type          models.ActivityType
+	VenueCode     string
+	VocationCode  string // SKILLS classes only, optional
+	InstructorID  string // Optional
+	Weekdays      models.Weekdays
+	StartHour     int
+	DurationHours float64
+	MinAge        *int // Defaults to models.SchoolMinAge for SCHOOL classes and models.AptitudeTestAge for SKILLS classes
+	MaxAge        *int // Defaults to models.SchoolMaxAge for SCHOOL classes
+}
+
+// AttendanceInput contains the attendance of a session of an activity.
+// Every resident listed must be enrolled.
+type AttendanceInput struct {
+	ActivityID string
+	SessionAt  time.Time
+	Present    []string // Resident IDs
+	Absent     []string
+}
+
+// AddVenue opens a venue classes and recreation can be scheduled in.
+func (s *Service) AddVenue(ctx context.Context, input VenueInput) (_ *models.Venue, err error) {
+	ctx, cmd := s.begin(ctx, CommandAddVenue, input)
+	defer func() { cmd.End(err) }()
+
+	venue := &models.Venue{
+		ID:        s.idGenerator.NewID(),
+		Code:      strings.ToUpper(strings.TrimSpace(input.Code)),
+		Name:      strings.TrimSpace(input.Name),
+		VenueType: input.Type,
+		Sector:    strings.ToUpper(strings.TrimSpace(input.Sector)),
+		Capacity:  input.Capacity,
+		IsActive:  true,
+	}
+	if err := s.activities.CreateVenue(ctx, nil, venue); err != nil {
+		return nil, err
+	}
+	return venue, nil
+}
+
+// ScheduleActivity schedules a weekly class or recreation in a venue.
+// SCHOOL classes are for minors up to the aptitude assessment and SKILLS
+// classes for residents who have sat it, unless the input sets other ages;
+// a SKILLS class for a vocation counts as training toward apprenticeships
+// in it. The instructor, if any, must be an ACTIVE resident.
+func (s *Service) ScheduleActivity(ctx context.Context, input ActivityInput) (_ *models.Activity, err error) {
+	ctx, cmd := s.begin(ctx, CommandScheduleActivity, input)
+	defer func() { cmd.End(err) }()
+
+	venue, err := s.activities.GetVenueByCode(ctx, input.VenueCode)
+	if err != nil {
+		return nil, err
+	}
+	if !venue.IsActive {
+		return nil, fmt.Errorf("%w: venue %s is closed", repository.ErrValidation, venue.Code)
+	}
+
+	code := strings.ToUpper(strings.TrimSpace(input.Code))
+	if _, err := s.activities.GetActivityByCode(ctx, code); err == nil {
+		return nil, fmt.Errorf("%w: activity %s is already scheduled", repository.ErrDuplicate, code)
+	}
+
+	activity := &models.Activity{
+		ID:            s.idGenerator.NewID(),
+		VenueID:       venue.ID,
+		Code:          code,
+		Name:          strings.TrimSpace(input.Name),
+		ActivityType:  input.Type,
+		Weekdays:      input.Weekdays,
+		StartHour:     input.StartHour,
+		DurationHours: input.DurationHours,
+		MinAge:        input.MinAge,
+		MaxAge:        input.MaxAge,
+		IsActive:      true,
+	}
+	switch input.Type {
+	case models.ActivityTypeSchool:
+		if activity.MinAge == nil {
+			activity.MinAge = ptr(models.SchoolMinAge)
+		}
+		if activity.MaxAge == nil {
+			activity.MaxAge = ptr(models.SchoolMaxAge)
+		}
+	case models.ActivityTypeSkills:
+		if activity.MinAge == nil {
+			activity.MinAge = ptr(models.AptitudeTestAge)
+		}
+	}
+
+	if input.VocationCode != "" {
+		vocation, err := s.vocationByCode(ctx, input.VocationCode)
+		if err != nil {
+			return nil, err
+		}
+		activity.VocationID = &vocation.ID
+	}
+	if input.InstructorID != "" {
+		instructor, err := s.residents.GetByID(ctx, input.InstructorID)
+		if err != nil {
+			return nil, fmt.Errorf("instructor: %w", err)
+		}
+		if instructor.Status != models.ResidentStatusActive {
+			return nil, fmt.Errorf("%w: instructor %s is %s", repository.ErrValidation, instructor.RegistryNumber, instructor.Status)
+		}
+		activity.InstructorID = &instructor.ID
+	}
+
+	if err := s.activities.CreateActivity(ctx, nil, activity); err != nil {
+		return nil, err
+	}
+	return activity, nil
+}
+
+// CancelActivity takes an activity off the schedule. Its enrollments and
+// attendance are kept.
+func (s *Service) CancelActivity(ctx context.Context, activityID string) (err error) {
+	ctx, cmd := s.begin(ctx, CommandCancelActivity, idArgs{activityID})
+	defer func() { cmd.End(err) }()
+
+	return s.activities.SetActive(ctx, nil, activityID, false)
+}
+
+// GetActivityByCode retrieves an activity of the vault by its code.
+func (s *Service) GetActivityByCode(ctx context.Context, code string) (*models.Activity, error) {
+	return s.activities.GetActivityByCode(ctx, strings.ToUpper(code))
+}
+
+// ListVenues retrieves the vault's venues, by code.
+func (s *Service) ListVenues(ctx context.Context) ([]*models.Venue, error) {
+	return s.activities.ListVenues(ctx)
+}
+
+// ListActivities retrieves the vault's activities, by start hour. With
+// activeOnly set, cancelled activities and those in closed venues are left
+// out.
+func (s *Service) ListActivities(ctx context.Context, activeOnly bool) ([]*models.Activity, error) {
+	return s.activities.ListActivities(ctx, activeOnly)
+}
+
+// EnrollInActivity enrolls an ACTIVE resident of the ages the activity
+// admits, while its venue has room for another.
+func (s *Service) EnrollInActivity(ctx context.Context, activityID, residentID string) (err error) {
+	ctx, cmd := s.begin(ctx, CommandEnrollInActivity, activityResidentArgs{activityID, residentID})
+	defer func() { cmd.End(err) }()
+
+	activity, venue, err := s.scheduledActivity(ctx, activityID)
+	if err != nil {
+		return err
+	}
+	resident, err := s.residents.GetByID(ctx, residentID)
+	if err != nil {
+		return err
+	}
+	if resident.Status != models.ResidentStatusActive {
+		return fmt.Errorf("%w: resident %s is %s", repository.ErrValidation, resident.RegistryNumber, resident.Status)
+	}
+	now := s.now()
+	if age := resident.Age(now); !activity.AdmitsAge(age) {
+		return fmt.Errorf("%w: resident %s is %d, outside the ages %s admits",
+			repository.ErrValidation, resident.RegistryNumber, age, activity.Code)
+	}
+	enrolled, err := s.activities.CountEnrolled(ctx, activity.ID)
+	if err != nil {
+		return err
+	}
+	if enrolled >= venue.Capacity {
+		return fmt.Errorf("%w: %s is full at the %d places of %s",
+			repository.ErrValidation, activity.Code, venue.Capacity, venue.Code)
+	}
+	return s.activities.Enroll(ctx, nil, activity.ID, resident.ID, now)
+}
+
+// EnrollEligible enrolls every ACTIVE resident of the ages the activity
+// admits who is not already enrolled, by registry number, until its venue
+// is full, e.g. every school-age child in a school class. It returns the
+// number enrolled.
+func (s *Service) EnrollEligible(ctx context.Context, activityID string) (_ int, err error) {
+	ctx, cmd := s.begin(ctx, CommandEnrollEligible, idArgs{activityID})
+	defer func() { cmd.End(err) }()
+
+	activity, venue, err := s.scheduledActivity(ctx, activityID)
+	if err != nil {
+		return 0, err
+	}
+	enrollees, err := s.activities.ListEnrollees(ctx, activity.ID)
+	if err != nil {
+		return 0, err
+	}
+	enrolled := make(map[string]bool, len(enrollees))
+	for _, e := range enrollees {
+		enrolled[e.ResidentID] = true
+	}
+	places, err := s.activities.CountEnrolled(ctx, activity.ID)
+	if err != nil {
+		return 0, err
+	}
+	places = venue.Capacity - places
+
+	residents, err := s.listActive(ctx)
+	if err != nil {
+		return 0, err
+	}
+	now := s.now()
+	var added int
+	err = repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		for _, r := range residents {
+			if added >= places {
+				break
+			}
+			if enrolled[r.ID] || !activity.AdmitsAge(r.Age(now)) {
+				continue
+			}
+			if err := s.activities.Enroll(ctx, tx, activity.ID, r.ID, now); err != nil {
+				return err
+			}
+			added++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return added, nil
+}
+
+// ListEnrollees retrieves the ACTIVE residents enrolled in an activity, by
+// registry number.
+func (s *Service) ListEnrollees(ctx context.Context, activityID string) ([]*models.Enrollee, error) {
+	return s.activities.ListEnrollees(ctx, activityID)
+}
+
+// LeaveActivity removes a resident from an activity.
+func (s *Service) LeaveActivity(ctx context.Context, activityID, residentID string) (err error) {
+	ctx, cmd := s.begin(ctx, CommandLeaveActivity, activityResidentArgs{activityID, residentID})
+	defer func() { cmd.End(err) }()
+
+	return s.activities.Unenroll(ctx, nil, activityID, residentID)
+}
+
+// RecordAttendance records who attended a session of an activity, the
+// session's full length for those present. Attending a SKILLS class for a
+// vocation credits the hours to the attendee's apprenticeship in it, if
+// they are training in it. It returns the apprenticeships whose hours that
+// completed.
+func (s *Service) RecordAttendance(ctx context.Context, input AttendanceInput) (_ []*models.Apprenticeship, err error) {
+	ctx, cmd := s.begin(ctx, CommandRecordAttendance, input)
+	defer func() { cmd.End(err) }()
+
+	activity, err := s.activities.GetActivity(ctx, input.ActivityID)
+	if err != nil {
+		return nil, err
+	}
+	if input.SessionAt.IsZero() {
+		return nil, fmt.Errorf("%w: session time is required", repository.ErrValidation)
+	}
+	enrollees, err := s.activities.ListEnrollees(ctx, activity.ID)
+	if err != nil {
+		return nil, err
+	}
+	enrolled := make(map[string]bool, len(enrollees))
+	for _, e := range enrollees {
+		enrolled[e.ResidentID] = true
+	}
+	for _, id := range append(append([]string{}, input.Present...), input.Absent...) {
+		if !enrolled[id] {
+			return nil, fmt.Errorf("%w: resident %s is not an active enrollee of %s", repository.ErrValidation, id, activity.Code)
+		}
+	}
+
+	var training []*models.Apprenticeship
+	if activity.VocationID != nil && len(input.Present) > 0 {
+		present := make(map[string]bool, len(input.Present))
+		for _, id := range input.Present {
+			present[id] = true
+		}
+		active, err := s.training.ListActive(ctx, true)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range active {
+			if a.VocationID == *activity.VocationID && present[a.ResidentID] {
+				training = append(training, a)
+			}
+		}
+	}
+
+	var completed []*models.Apprenticeship
+	err = repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		record := func(residentID string, present bool) error {
+			attendance := &models.Attendance{
+				ID:         s.idGenerator.NewID(),
+				ActivityID: activity.ID,
+				ResidentID: residentID,
+				SessionAt:  input.SessionAt,
+				Present:    present,
+			}
+			if present {
+				attendance.Hours = activity.DurationHours
+			}
+			return s.activities.CreateAttendance(ctx, tx, attendance)
+		}
+		for _, id := range input.Present {
+			if err := record(id, true); err != nil {
+				return err
+			}
+		}
+		for _, id := range input.Absent {
+			if err := record(id, false); err != nil {
+				return err
+			}
+		}
+		for _, a := range training {
+			if a.HoursComplete() {
+				continue
+			}
+			if a.Credit(activity.DurationHours) {
+				completed = append(completed, a)
+			}
+			if err := s.training.UpdateHours(ctx, tx, a); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return completed, nil
+}
+
+// ActivityAttendance sums the attendance of each of the vault's activities
+// over the sessions of the last days days.
+func (s *Service) ActivityAttendance(ctx context.Context, days int) ([]*models.ActivityAttendance, error) {
+	return s.activities.ListAttendance(ctx, s.now().AddDate(0, 0, -days))
+}
+
+// scheduledActivity retrieves an activity still on the schedule with its
+// venue, which must be open.
+func (s *Service) scheduledActivity(ctx context.Context, activityID string) (*models.Activity, *models.Venue, error) {
+	activity, err := s.activities.GetActivity(ctx, activityID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !activity.IsActive {
+		return nil, nil, fmt.Errorf("%w: activity %s is cancelled", repository.ErrValidation, activity.Code)
+	}
+	venue, err := s.activities.GetVenue(ctx, activity.VenueID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !venue.IsActive {
+		return nil, nil, fmt.Errorf("%w: venue %s is closed", repository.ErrValidation, venue.Code)
+	}
+	return activity, venue, nil
+}
+
+// ActivitySessions is the simulation hook that holds the sessions of the
+// scheduled activities as vault time reaches them and records who attended.
+type ActivitySessions struct {
+	service *Service
+	rng     *rand.Rand
+}
+
+// ActivitySessions creates the activity session hook.
+func (s *Service) ActivitySessions(seed int64) *ActivitySessions {
+	return &ActivitySessions{service: s, rng: rand.New(rand.NewSource(seed))}
+}
+
+// Name implements simulation.Hook.
+func (h *ActivitySessions) Name() string {
+	return "recreation and education"
+}
+
+// Advance implements simulation.Hook. Each enrollee of a session attends
+// with models.AttendanceChance, and the attendance goes through
+// RecordAttendance so that a replay need not roll it again. Sessions less
+// than half attended raise a warning, and apprentices whose classes
+// complete their hours are reported as awaiting their mentor's sign-off.
+func (h *ActivitySessions) Advance(ctx context.Context, from, to time.Time) ([]simulation.Event, error) {
+	activities, err := h.service.activities.ListActivities(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []simulation.Event
+	for _, activity := range activities {
+		sessions := activity.SessionsBetween(from, to)
+		if len(sessions) == 0 {
+			continue
+		}
+		enrollees, err := h.service.activities.ListEnrollees(ctx, activity.ID)
+		if err != nil {
+			return events, err
+		}
+		if len(enrollees) == 0 {
+			continue
+		}
+
+		for _, at := range sessions {
+			input := AttendanceInput{ActivityID: activity.ID, SessionAt: at}
+			for _, e := range enrollees {
+				if h.rng.Float64() < models.AttendanceChance(activity.ActivityType, e.Morale) {
+					input.Present = append(input.Present, e.ResidentID)
+				} else {
+					input.Absent = append(input.Absent, e.ResidentID)
+				}
+			}
+			completed, err := h.service.RecordAttendance(ctx, input)
+			if err != nil {
+				return events, fmt.Errorf("recording attendance of %s: %w", activity.Code, err)
+			}
+
+			if 2*len(input.Present) < len(enrollees) {
+				events = append(events, simulation.Event{
+					Time:   at,
+					Level:  simulation.EventWarning,
+					Source: h.Name(),
+					Message: fmt.Sprintf("Low attendance at %s %s: %d of %d enrolled",
+						activity.Code, activity.Name, len(input.Present), len(enrollees)),
+				})
+			}
+			for _, a := range completed {
+				event, err := h.service.trainingCompleteEvent(ctx, a, at, h.Name())
+				if err != nil {
+					return events, err
+				}
+				events = append(events, event)
+			}
+		}
+	}
+	return events, nil
+}
@@ -0,0 +1,90 @@
+package population
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/database/seed"
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/simulation"
+)
+
+// ScriptActionAdmit is the scenario script action that admits surface
+// survivors.
+const ScriptActionAdmit = "admit"
+
+// Ages of admitted surface survivors, inclusive.
+const (
+	survivorMinAge = 16
+	survivorMaxAge = 60
+)
+
+// AdmitSurvivors admits count surface survivors at vault time at, with
+// names, sexes, ages and blood types drawn from rng. They arrive ACTIVE and
+// without a household; note is recorded on each. Residents admitted before
+// a failure are kept and returned with the error.
+func (s *Service) AdmitSurvivors(ctx context.Context, count int, at time.Time, note string, rng *rand.Rand) ([]*models.Resident, error) {
+	if count < 1 {
+		return nil, fmt.Errorf("%w: count must be at least 1", repository.ErrValidation)
+	}
+
+	admitted := make([]*models.Resident, 0, count)
+	for i := 0; i < count; i++ {
+		sex, names := models.SexMale, seed.MaleGivenNames
+		if rng.Intn(2) == 0 {
+			sex, names = models.SexFemale, seed.FemaleGivenNames
+		}
+		age := survivorMinAge + rng.Intn(survivorMaxAge-survivorMinAge+1)
+
+		resident, err := s.CreateResident(ctx, CreateResidentInput{
+			Surname:     seed.Surnames[rng.Intn(len(seed.Surnames))],
+			GivenNames:  names[rng.Intn(len(names))],
+			DateOfBirth: at.AddDate(-age, 0, -rng.Intn(365)),
+			Sex:         sex,
+			BloodType:   randomBloodType(rng),
+			EntryType:   models.EntryTypeAdmitted,
+			EntryDate:   at,
+			Notes:       note,
+		})
+		if err != nil {
+			return admitted, fmt.Errorf("admitting survivor %d of %d: %w", i+1, count, err)
+		}
+		admitted = append(admitted, resident)
+	}
+	return admitted, nil
+}
+
+// ScriptedAdmission handles the admit action of scenario scripts.
+func (s *Service) ScriptedAdmission() simulation.ScriptHandler {
+	return func(ctx context.Context, event simulation.ScriptEvent, at time.Time) (string, error) {
+		note := "Admitted from the surface by scenario script"
+		if event.Message != "" {
+			note += ": " + event.Message
+		}
+		admitted, err := s.AdmitSurvivors(ctx, event.Count, at, note, rand.New(rand.NewSource(at.UnixNano())))
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d survivor(s) admitted, %s to %s",
+			len(admitted), admitted[0].RegistryNumber, admitted[len(admitted)-1].RegistryNumber), nil
+	}
+}
+
+// randomBloodType draws a blood type by its frequency in the seed data.
+func randomBloodType(rng *rand.Rand) models.BloodType {
+	total := 0
+	for _, bt := range seed.BloodTypes {
+		total += bt.Weight
+	}
+	r := rng.Intn(total)
+	for _, bt := range seed.BloodTypes {
+		r -= bt.Weight
+		if r < 0 {
+			return models.BloodType(bt.Type)
+		}
+	}
+	return models.BloodTypeOPos
+}
@@ -0,0 +1,263 @@
+package population
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+)
+
+// VocationMatch is an open vocation a resident qualifies for, with the
+// resident's aptitude for its department.
+type VocationMatch struct {
+	Vocation  *models.Vocation
+	Score     int // Aptitude score for the vocation's department
+	Assigned  int // Active residents holding the vocation
+	Openings  int // Authorized headcount not yet filled
+	Shortfall int // Residents needed to reach the minimum headcount
+}
+
+// AptitudeCandidate is a resident of testing age without a vocation, with
+// their latest assessment, if any.
+type AptitudeCandidate struct {
+	Resident    *models.Resident
+	Assessment  *models.AptitudeAssessment // Nil if not yet assessed
+	Recommended *models.Vocation           // Nil if not assessed or nothing was open
+}
+
+// Due returns true if the candidate has not been assessed.
+func (c AptitudeCandidate) Due() bool {
+	return c.Assessment == nil
+}
+
+// AptitudeInput contains data for recording an aptitude assessment.
+type AptitudeInput struct {
+	ResidentID     string
+	Scores         models.AptitudeScores
+	AdministeredBy *string
+	AssessedAt     time.Time // Defaults to now
+	Notes          string
+}
+
+// ListAptitudeCandidates retrieves active residents aged AptitudeTestAge or
+// over who hold no vocation, those not yet assessed first, then youngest
+// first.
+func (s *Service) ListAptitudeCandidates(ctx context.Context) ([]AptitudeCandidate, error) {
+	residents, err := s.listActiveResidents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	latest, err := s.aptitude.ListLatest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	vocations, err := s.vocationRef.All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing vocations: %w", err)
+	}
+	byID := make(map[string]*models.Vocation, len(vocations))
+	for _, v := range vocations {
+		byID[v.ID] = v
+	}
+
+	now := s.now()
+	var candidates []AptitudeCandidate
+	for _, r := range residents {
+		if r.PrimaryVocationID != nil || r.Age(now) < models.AptitudeTestAge {
+			continue
+		}
+		s.redactResidents(ctx, r)
+		c := AptitudeCandidate{Resident: r, Assessment: latest[r.ID]}
+		if c.Assessment != nil && c.Assessment.RecommendedVocationID != nil {
+			c.Recommended = byID[*c.Assessment.RecommendedVocationID]
+		}
+		candidates = append(candidates, c)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Due() != candidates[j].Due() {
+			return candidates[i].Due()
+		}
+		return candidates[i].Resident.DateOfBirth.After(candidates[j].Resident.DateOfBirth)
+	})
+	return candidates, nil
+}
+
+// MatchVocations ranks the open vocations a resident qualifies for by their
+// aptitude scores: a vocation is open while its assigned headcount is below
+// the authorized headcount, and the resident qualifies if their clearance
+// meets the vocation's required clearance. Ties in score go to the vocation
+// furthest below its minimum headcount, then the one with most openings.
+func (s *Service) MatchVocations(ctx context.Context, resident *models.Resident, scores models.AptitudeScores) ([]VocationMatch, error) {
+	vocations, err := s.activeVocations(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing vocations: %w", err)
+	}
+	residents, err := s.listActiveResidents(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	assigned := make(map[string]int)
+	for _, r := range residents {
+		if r.PrimaryVocationID != nil && r.ID != resident.ID {
+			assigned[*r.PrimaryVocationID]++
+		}
+	}
+
+	var matches []VocationMatch
+	for _, v := range vocations {
+		if v.RequiredClearance > resident.ClearanceLevel {
+			continue
+		}
+		count := assigned[v.ID]
+		if count >= v.HeadcountAuthorized {
+			continue
+		}
+		matches = append(matches, VocationMatch{
+			Vocation:  v,
+			Score:     scores[v.Department],
+			Assigned:  count,
+			Openings:  v.HeadcountAuthorized - count,
+			Shortfall: max(v.HeadcountMinimum-count, 0),
+		})
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Shortfall != b.Shortfall {
+			return a.Shortfall > b.Shortfall
+		}
+		if a.Openings != b.Openings {
+			return a.Openings > b.Openings
+		}
+		return a.Vocation.Code < b.Vocation.Code
+	})
+	return matches, nil
+}
+
+// RecordAptitude records a living resident's aptitude assessment with the
+// best matching open vocation as its recommendation. The resident must be
+// at least AptitudeTestAge. The returned matches are every open vocation
+// the resident qualifies for, best first.
+func (s *Service) RecordAptitude(ctx context.Context, input AptitudeInput) (_ *models.AptitudeAssessment, _ []VocationMatch, err error) {
+	ctx, cmd := s.begin(ctx, CommandRecordAptitude, input)
+	defer func() { cmd.End(err) }()
+
+	if err := input.Scores.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", repository.ErrValidation, err)
+	}
+
+	resident, err := s.residents.GetByID(ctx, input.ResidentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !resident.IsAlive() {
+		return nil, nil, fmt.Errorf("%w: resident %s is deceased", repository.ErrValidation, resident.RegistryNumber)
+	}
+
+	assessedAt := input.AssessedAt
+	if assessedAt.IsZero() {
+		assessedAt = s.now()
+	}
+	if age := resident.Age(assessedAt); age < models.AptitudeTestAge {
+		return nil, nil, fmt.Errorf("%w: resident %s is %d, the aptitude test is sat at %d",
+			repository.ErrValidation, resident.RegistryNumber, age, models.AptitudeTestAge)
+	}
+
+	matches, err := s.MatchVocations(ctx, resident, input.Scores)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	assessment := &models.AptitudeAssessment{
+		ID:             s.idGenerator.NewID(),
+		ResidentID:     resident.ID,
+		AssessedAt:     assessedAt,
+		AdministeredBy: input.AdministeredBy,
+		Scores:         input.Scores,
+		Notes:          input.Notes,
+	}
+	if len(matches) > 0 {
+		assessment.RecommendedVocationID = &matches[0].Vocation.ID
+	}
+
+	if err := s.aptitude.Create(ctx, nil, assessment); err != nil {
+		return nil, nil, fmt.Errorf("creating aptitude assessment: %w", err)
+	}
+	return assessment, matches, nil
+}
+
+// GetLatestAptitude retrieves a resident's most recent assessment.
+func (s *Service) GetLatestAptitude(ctx context.Context, residentID string) (*models.AptitudeAssessment, error) {
+	return s.aptitude.GetLatest(ctx, residentID)
+}
+
+// ApplyAptitudeRecommendation assigns an assessment's recommended vocation
+// as the resident's primary vocation. Clearance and openings are checked
+// again, since headcount may have changed since the assessment.
+func (s *Service) ApplyAptitudeRecommendation(ctx context.Context, assessmentID string) (_ *models.Resident, _ *models.Vocation, err error) {
+	ctx, cmd := s.begin(ctx, CommandApplyAptitude, idArgs{assessmentID})
+	defer func() { cmd.End(err) }()
+
+	assessment, err := s.aptitude.GetByID(ctx, assessmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if assessment.IsApplied() {
+		return nil, nil, fmt.Errorf("%w: assessment has already been applied", repository.ErrValidation)
+	}
+	if assessment.RecommendedVocationID == nil {
+		return nil, nil, fmt.Errorf("%w: assessment has no recommended vocation", repository.ErrValidation)
+	}
+
+	resident, err := s.residents.GetByID(ctx, assessment.ResidentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resident.Status != models.ResidentStatusActive {
+		return nil, nil, fmt.Errorf("%w: resident %s is %s", repository.ErrValidation, resident.RegistryNumber, resident.Status)
+	}
+	latest, err := s.aptitude.GetLatest(ctx, resident.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if latest.ID != assessment.ID {
+		return nil, nil, fmt.Errorf("%w: resident %s has a more recent assessment", repository.ErrValidation, resident.RegistryNumber)
+	}
+
+	matches, err := s.MatchVocations(ctx, resident, assessment.Scores)
+	if err != nil {
+		return nil, nil, err
+	}
+	var vocation *models.Vocation
+	for _, m := range matches {
+		if m.Vocation.ID == *assessment.RecommendedVocationID {
+			vocation = m.Vocation
+			break
+		}
+	}
+	if vocation == nil {
+		return nil, nil, fmt.Errorf("%w: recommended vocation is no longer open to %s; reassess the resident",
+			repository.ErrValidation, resident.RegistryNumber)
+	}
+
+	resident.PrimaryVocationID = &vocation.ID
+	err = repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		if err := s.residents.Update(ctx, tx, resident); err != nil {
+			return err
+		}
+		return s.aptitude.MarkApplied(ctx, tx, assessment.ID, s.now())
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return resident, vocation, nil
+}
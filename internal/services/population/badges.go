@@ -0,0 +1,51 @@
+package population
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// IDBadge builds the ID badge of a resident.
+func (s *Service) IDBadge(ctx context.Context, resident *models.Resident) (*models.IDBadge, error) {
+	badge := &models.IDBadge{
+		VaultNumber:    s.vaultNumber,
+		RegistryNumber: resident.RegistryNumber,
+		Name:           resident.FullName(),
+		Clearance:      resident.ClearanceLevel,
+		Status:         resident.Status,
+	}
+
+	if resident.PrimaryVocationID != nil {
+		id := *resident.PrimaryVocationID
+		vocation, ok, err := s.vocationRef.Find(ctx, func(v *models.Vocation) bool { return v.ID == id })
+		if err != nil {
+			return nil, fmt.Errorf("loading vocations: %w", err)
+		}
+		if ok {
+			badge.Vocation = vocation.Title
+		}
+	}
+
+	return badge, nil
+}
+
+// ActiveIDBadges builds the ID badges of every active resident, in census
+// order, for a bulk print run.
+func (s *Service) ActiveIDBadges(ctx context.Context) ([]*models.IDBadge, error) {
+	residents, err := s.listActiveResidents(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing active residents: %w", err)
+	}
+
+	badges := make([]*models.IDBadge, 0, len(residents))
+	for _, r := range residents {
+		badge, err := s.IDBadge(ctx, r)
+		if err != nil {
+			return nil, err
+		}
+		badges = append(badges, badge)
+	}
+	return badges, nil
+}
@@ -0,0 +1,105 @@
+package population
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+)
+
+// AssignToHouseholdBatch moves living residents into an active household in
+// one transaction: all of them move, or none do when any is missing or
+// deceased. The households gaining and losing members are queued for ration
+// class review afterwards.
+func (s *Service) AssignToHouseholdBatch(ctx context.Context, residentIDs []string, householdID string) (err error) {
+	ctx, cmd := s.begin(ctx, CommandAssignHouseholdBatch, assignHouseholdBatchArgs{residentIDs, householdID})
+	defer func() { cmd.End(err) }()
+
+	if len(residentIDs) == 0 {
+		return fmt.Errorf("%w: no residents selected", repository.ErrValidation)
+	}
+	if _, err := s.activeHousehold(ctx, householdID); err != nil {
+		return err
+	}
+
+	residents := make([]*models.Resident, 0, len(residentIDs))
+	var previous []string
+	for _, id := range residentIDs {
+		resident, err := s.residents.GetByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("resident %s: %w", id, err)
+		}
+		if !resident.IsAlive() {
+			return fmt.Errorf("%w: resident %s is deceased", repository.ErrValidation, resident.RegistryNumber)
+		}
+		if from := resident.HouseholdID; from != nil && *from != householdID && !slices.Contains(previous, *from) {
+			previous = append(previous, *from)
+		}
+		residents = append(residents, resident)
+	}
+
+	err = repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		return s.moveMembers(ctx, tx, residents, &householdID)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.queueRationReview(ctx, householdID, models.RationReviewTriggerTransfer)
+	for _, id := range previous {
+		s.queueRationReview(ctx, id, models.RationReviewTriggerTransfer)
+	}
+	return nil
+}
+
+// SetRationClassBatch sets the ration class of active households in one
+// transaction, overriding the recommended class. A pending review of any of
+// them is superseded, as the operator has decided the class directly.
+func (s *Service) SetRationClassBatch(ctx context.Context, householdIDs []string, class models.RationClass) (err error) {
+	ctx, cmd := s.begin(ctx, CommandSetRationClassBatch, rationClassBatchArgs{householdIDs, class})
+	defer func() { cmd.End(err) }()
+
+	if len(householdIDs) == 0 {
+		return fmt.Errorf("%w: no households selected", repository.ErrValidation)
+	}
+	if !class.Valid() {
+		return fmt.Errorf("%w: invalid ration class %q", repository.ErrValidation, class)
+	}
+
+	households := make([]*models.Household, 0, len(householdIDs))
+	reviews := make([]*models.RationClassReview, 0, len(householdIDs))
+	for _, id := range householdIDs {
+		household, err := s.activeHousehold(ctx, id)
+		if err != nil {
+			return err
+		}
+		households = append(households, household)
+
+		pending, err := s.reviews.GetPendingByHousehold(ctx, id)
+		if err != nil {
+			return err
+		}
+		if pending != nil {
+			reviews = append(reviews, pending)
+		}
+	}
+
+	return repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		for _, household := range households {
+			household.RationClass = class
+			if err := s.households.Update(ctx, tx, household); err != nil {
+				return fmt.Errorf("updating household %s: %w", household.Designation, err)
+			}
+		}
+		for _, review := range reviews {
+			review.Status = models.RationReviewStatusSuperseded
+			if err := s.reviews.UpdateStatus(ctx, tx, review); err != nil {
+				return fmt.Errorf("superseding review: %w", err)
+			}
+		}
+		return nil
+	})
+}
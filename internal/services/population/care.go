@@ -0,0 +1,198 @@
+package population
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+)
+
+// PendingCare is a pending care assignment with its dependent.
+type PendingCare struct {
+	*models.CareAssignment
+	Dependent *models.Resident
+}
+
+// ListPendingCare retrieves the dependents awaiting a guardian, longest
+// waiting first.
+func (s *Service) ListPendingCare(ctx context.Context) ([]PendingCare, error) {
+	cares, err := s.care.ListPending(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := make([]PendingCare, 0, len(cares))
+	for _, care := range cares {
+		dependent, err := s.residents.GetByID(ctx, care.DependentID)
+		if err != nil {
+			return nil, fmt.Errorf("getting dependent: %w", err)
+		}
+		s.redactResidents(ctx, dependent)
+		pending = append(pending, PendingCare{CareAssignment: care, Dependent: dependent})
+	}
+	return pending, nil
+}
+
+// CountPendingCare returns the number of dependents awaiting a guardian.
+func (s *Service) CountPendingCare(ctx context.Context) (int, error) {
+	return s.care.CountPending(ctx)
+}
+
+// AssignCare resolves a pending care assignment: the guardian becomes the
+// dependent's legal guardian and the dependent moves into the guardian's
+// household.
+func (s *Service) AssignCare(ctx context.Context, careID, guardianID string) (_ *models.CareAssignment, err error) {
+	ctx, cmd := s.begin(ctx, CommandAssignCare, assignCareArgs{careID, guardianID})
+	defer func() { cmd.End(err) }()
+
+	care, err := s.care.GetByID(ctx, careID)
+	if err != nil {
+		return nil, err
+	}
+	if !care.IsPending() {
+		return nil, fmt.Errorf("%w: care assignment is %s", repository.ErrValidation, care.Status)
+	}
+
+	guardianship, _, err := s.newRelationship(ctx, RelationshipInput{
+		Type:       models.RelationshipGuardianship,
+		ResidentID: guardianID,
+		RelatedID:  care.DependentID,
+		Notes:      "Care assignment: " + care.Reason,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	guardian, err := s.residents.GetByID(ctx, guardianID)
+	if err != nil {
+		return nil, err
+	}
+	if guardian.HouseholdID == nil {
+		return nil, fmt.Errorf("%w: guardian %s has no household", repository.ErrValidation, guardian.RegistryNumber)
+	}
+	dependent, err := s.residents.GetByID(ctx, care.DependentID)
+	if err != nil {
+		return nil, err
+	}
+
+	previousID := dependent.HouseholdID
+	dependent.HouseholdID = guardian.HouseholdID
+	resolvedAt := s.now()
+	care.Status = models.CareAssignmentStatusAssigned
+	care.GuardianID = &guardian.ID
+	care.HouseholdID = guardian.HouseholdID
+	care.ResolvedAt = &resolvedAt
+
+	err = repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		if err := s.relationships.Create(ctx, tx, guardianship); err != nil {
+			return err
+		}
+		if err := s.residents.Update(ctx, tx, dependent); err != nil {
+			return err
+		}
+		return s.care.Resolve(ctx, tx, care)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.queueRationReview(ctx, *guardian.HouseholdID, models.RationReviewTriggerTransfer)
+	if previousID != nil && *previousID != *guardian.HouseholdID {
+		s.queueRationReview(ctx, *previousID, models.RationReviewTriggerTransfer)
+	}
+	return care, nil
+}
+
+// CancelCare closes a pending care assignment without assigning a guardian,
+// e.g. when a relative has been recorded as guardian directly.
+func (s *Service) CancelCare(ctx context.Context, careID string) (err error) {
+	ctx, cmd := s.begin(ctx, CommandCancelCare, idArgs{careID})
+	defer func() { cmd.End(err) }()
+
+	care, err := s.care.GetByID(ctx, careID)
+	if err != nil {
+		return err
+	}
+	resolvedAt := s.now()
+	care.Status = models.CareAssignmentStatusCancelled
+	care.ResolvedAt = &resolvedAt
+	return s.care.Resolve(ctx, nil, care)
+}
+
+// dependentsOf returns a resident's children and current wards, the minors
+// who may be left without a carer when the resident dies.
+func (s *Service) dependentsOf(ctx context.Context, residentID string) ([]*models.Resident, error) {
+	dependents, err := s.residents.GetChildren(ctx, residentID)
+	if err != nil {
+		return nil, err
+	}
+	rels, err := s.relationships.ListByResident(ctx, residentID)
+	if err != nil {
+		return nil, err
+	}
+	for _, rel := range rels {
+		if !rel.IsActive() || rel.Type != models.RelationshipGuardianship || rel.ResidentID != residentID {
+			continue
+		}
+		ward, err := s.residents.GetByID(ctx, rel.RelatedID)
+		if err != nil {
+			return nil, err
+		}
+		dependents = append(dependents, ward)
+	}
+	return dependents, nil
+}
+
+// carersOf returns a dependent's biological parents and current guardians.
+func (s *Service) carersOf(ctx context.Context, dependentID string) ([]*models.Resident, error) {
+	carers, err := s.residents.GetParents(ctx, dependentID)
+	if err != nil {
+		return nil, err
+	}
+	rels, err := s.relationships.ListByResident(ctx, dependentID)
+	if err != nil {
+		return nil, err
+	}
+	for _, rel := range rels {
+		if !rel.IsActive() || rel.Type != models.RelationshipGuardianship || rel.RelatedID != dependentID {
+			continue
+		}
+		guardian, err := s.residents.GetByID(ctx, rel.ResidentID)
+		if err != nil {
+			return nil, err
+		}
+		carers = append(carers, guardian)
+	}
+	return carers, nil
+}
+
+// queueCareAssignments opens a pending care assignment for each dependent
+// left with no living parent or guardian. Like ration reviews, failures are
+// ignored: the death has already been recorded, and a guardian can still be
+// recorded directly.
+func (s *Service) queueCareAssignments(ctx context.Context, dependents []*models.Resident, reason string) {
+	now := s.now()
+	seen := make(map[string]bool, len(dependents))
+	for _, dependent := range dependents {
+		if seen[dependent.ID] {
+			continue
+		}
+		seen[dependent.ID] = true
+
+		carers, err := s.carersOf(ctx, dependent.ID)
+		if err != nil || !models.NeedsGuardian(dependent, carers, now) {
+			continue
+		}
+		// A dependent already awaiting a guardian keeps the earlier
+		// assignment; the duplicate is rejected by the repository.
+		_ = s.care.Create(ctx, nil, &models.CareAssignment{
+			ID:          s.idGenerator.NewID(),
+			DependentID: dependent.ID,
+			Status:      models.CareAssignmentStatusPending,
+			Reason:      reason,
+			OpenedAt:    now,
+		})
+	}
+}
@@ -0,0 +1,378 @@
+package population
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/journal"
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// Journaled population commands.
+const (
+	CommandCreateResident        = "population.create_resident"
+	CommandUpdateResident        = "population.update_resident"
+	CommandDeleteResident        = "population.delete_resident"
+	CommandImportResidents       = "population.import_residents"
+	CommandRegisterBirth         = "population.register_birth"
+	CommandRegisterDeath         = "population.register_death"
+	CommandRevokeDeath           = "population.revoke_death"
+	CommandCreateHousehold       = "population.create_household"
+	CommandDeleteHousehold       = "population.delete_household"
+	CommandAssignToHousehold     = "population.assign_to_household"
+	CommandAssignVocation        = "population.assign_vocation"
+	CommandDissolveHousehold     = "population.dissolve_household"
+	CommandMergeHouseholds       = "population.merge_households"
+	CommandSplitHousehold        = "population.split_household"
+	CommandReviewRationClass     = "population.review_ration_class"
+	CommandApproveRationReview   = "population.approve_ration_review"
+	CommandRejectRationReview    = "population.reject_ration_review"
+	CommandRecordRelationship    = "population.record_relationship"
+	CommandEndRelationship       = "population.end_relationship"
+	CommandAssignCare            = "population.assign_care"
+	CommandCancelCare            = "population.cancel_care"
+	CommandRecordAptitude        = "population.record_aptitude"
+	CommandApplyAptitude         = "population.apply_aptitude"
+	CommandScheduleStatus        = "population.schedule_status"
+	CommandEndStatus             = "population.end_status"
+	CommandProcessDueTransitions = "population.process_due_transitions"
+	CommandAssignHouseholdBatch  = "population.assign_household_batch"
+	CommandSetRationClassBatch   = "population.set_ration_class_batch"
+	CommandSetTrainingProgram    = "population.set_training_program"
+	CommandEnrollApprentice      = "population.enroll_apprentice"
+	CommandChangeMentor          = "population.change_mentor"
+	CommandSignOffApprentice     = "population.sign_off_apprentice"
+	CommandWithdrawApprentice    = "population.withdraw_apprentice"
+	CommandAccrueTraining        = "population.accrue_training"
+	CommandRecordGeneticHealth   = "population.record_genetic_health"
+	CommandAdmitIntake           = "population.admit_intake"
+	CommandRecordScreening       = "population.record_screening"
+	CommandClearIntake           = "population.clear_intake"
+	CommandScoreMorale           = "population.score_morale"
+	CommandAddVenue              = "population.add_venue"
+	CommandScheduleActivity      = "population.schedule_activity"
+	CommandCancelActivity        = "population.cancel_activity"
+	CommandEnrollInActivity      = "population.enroll_in_activity"
+	CommandEnrollEligible        = "population.enroll_eligible"
+	CommandLeaveActivity         = "population.leave_activity"
+	CommandRecordAttendance      = "population.record_attendance"
+	CommandTagResidents          = "population.tag_residents"
+	CommandSaveCensusPreset      = "population.save_census_preset"
+	CommandDeleteCensusPreset    = "population.delete_census_preset"
+	CommandImportTransfer        = "population.import_transfer"
+)
+
+// Arguments of journaled commands that take more than an input.
+type (
+	idArgs struct {
+		ID string `json:"id"`
+	}
+	updateResidentArgs struct {
+		ID    string              `json:"id"`
+		Input UpdateResidentInput `json:"input"`
+	}
+	importArgs struct {
+		CSV string `json:"csv"`
+	}
+	registerDeathArgs struct {
+		ResidentID string            `json:"resident_id"`
+		Input      DeathRegistration `json:"input"`
+	}
+	revokeDeathArgs struct {
+		ResidentID string          `json:"resident_id"`
+		Input      DeathRevocation `json:"input"`
+	}
+	assignHouseholdArgs struct {
+		ResidentID  string `json:"resident_id"`
+		HouseholdID string `json:"household_id"`
+	}
+	assignVocationArgs struct {
+		ResidentID   string `json:"resident_id"`
+		VocationCode string `json:"vocation_code"`
+	}
+	dissolveArgs struct {
+		ID           string `json:"id"`
+		ReassignToID string `json:"reassign_to_id"`
+	}
+	mergeArgs struct {
+		SourceID string `json:"source_id"`
+		TargetID string `json:"target_id"`
+	}
+	splitArgs struct {
+		HouseholdID string              `json:"household_id"`
+		Input       SplitHouseholdInput `json:"input"`
+	}
+	rationReviewArgs struct {
+		HouseholdID string                     `json:"household_id"`
+		Trigger     models.RationReviewTrigger `json:"trigger"`
+	}
+	decideReviewArgs struct {
+		ReviewID  string  `json:"review_id"`
+		DecidedBy *string `json:"decided_by"`
+	}
+	endRelationshipArgs struct {
+		ID     string `json:"id"`
+		Reason string `json:"reason"`
+	}
+	assignCareArgs struct {
+		CareID     string `json:"care_id"`
+		GuardianID string `json:"guardian_id"`
+	}
+	scheduleStatusArgs struct {
+		ResidentID string              `json:"resident_id"`
+		Input      ScheduleStatusInput `json:"input"`
+	}
+	residentArgs struct {
+		ResidentID string `json:"resident_id"`
+	}
+	dueTransitionsArgs struct {
+		Now time.Time `json:"now"`
+	}
+	assignHouseholdBatchArgs struct {
+		ResidentIDs []string `json:"resident_ids"`
+		HouseholdID string   `json:"household_id"`
+	}
+	rationClassBatchArgs struct {
+		HouseholdIDs []string           `json:"household_ids"`
+		Class        models.RationClass `json:"class"`
+	}
+	trainingProgramArgs struct {
+		VocationCode  string  `json:"vocation_code"`
+		RequiredHours float64 `json:"required_hours"`
+		HoursPerDay   float64 `json:"hours_per_day"`
+	}
+	mentorArgs struct {
+		ApprenticeshipID string `json:"apprenticeship_id"`
+		MentorID         string `json:"mentor_id"`
+	}
+	withdrawArgs struct {
+		ApprenticeshipID string `json:"apprenticeship_id"`
+		Reason           string `json:"reason"`
+	}
+	accrueTrainingArgs struct {
+		From time.Time `json:"from"`
+		To   time.Time `json:"to"`
+	}
+	geneticHealthArgs struct {
+		At time.Time `json:"at"`
+	}
+	clearIntakeArgs struct {
+		ResidentID string `json:"resident_id"`
+		OfficerID  string `json:"officer_id"`
+	}
+	scoreMoraleArgs struct {
+		At time.Time `json:"at"`
+	}
+	activityResidentArgs struct {
+		ActivityID string `json:"activity_id"`
+		ResidentID string `json:"resident_id"`
+	}
+	tagResidentsArgs struct {
+		ResidentIDs []string `json:"resident_ids"`
+		Add         []string `json:"add"`
+		Remove      []string `json:"remove"`
+	}
+	censusPresetArgs struct {
+		Name   string                `json:"name"`
+		Filter models.ResidentFilter `json:"filter"`
+		Sort   models.SortOption     `json:"sort"`
+	}
+)
+
+// SetJournal records the commands the service runs in j.
+func (s *Service) SetJournal(j *journal.Journal) {
+	s.journal = j
+}
+
+// begin starts journaling a command of the service.
+func (s *Service) begin(ctx context.Context, command string, args any) (context.Context, *journal.Command) {
+	return s.journal.Begin(ctx, s.vaultNumber, command, args)
+}
+
+// Commands returns the handlers that replay the service's journaled
+// commands.
+func (s *Service) Commands() journal.Handlers {
+	return journal.Handlers{
+		CommandCreateResident: journal.Handle(func(ctx context.Context, input CreateResidentInput) error {
+			_, err := s.CreateResident(ctx, input)
+			return err
+		}),
+		CommandUpdateResident: journal.Handle(func(ctx context.Context, args updateResidentArgs) error {
+			_, err := s.UpdateResident(ctx, args.ID, args.Input)
+			return err
+		}),
+		CommandDeleteResident: journal.Handle(func(ctx context.Context, args idArgs) error {
+			return s.DeleteResident(ctx, args.ID)
+		}),
+		CommandImportResidents: journal.Handle(func(ctx context.Context, args importArgs) error {
+			_, err := s.ImportResidents(ctx, strings.NewReader(args.CSV))
+			return err
+		}),
+		CommandRegisterBirth: journal.Handle(func(ctx context.Context, input BirthRegistration) error {
+			_, err := s.RegisterBirth(ctx, input)
+			return err
+		}),
+		CommandRegisterDeath: journal.Handle(func(ctx context.Context, args registerDeathArgs) error {
+			return s.RegisterDeath(ctx, args.ResidentID, args.Input)
+		}),
+		CommandRevokeDeath: journal.Handle(func(ctx context.Context, args revokeDeathArgs) error {
+			return s.RevokeDeath(ctx, args.ResidentID, args.Input)
+		}),
+		CommandCreateHousehold: journal.Handle(func(ctx context.Context, input CreateHouseholdInput) error {
+			_, err := s.CreateHousehold(ctx, input)
+			return err
+		}),
+		CommandDeleteHousehold: journal.Handle(func(ctx context.Context, args idArgs) error {
+			return s.DeleteHousehold(ctx, args.ID)
+		}),
+		CommandAssignToHousehold: journal.Handle(func(ctx context.Context, args assignHouseholdArgs) error {
+			return s.AssignToHousehold(ctx, args.ResidentID, args.HouseholdID)
+		}),
+		CommandAssignVocation: journal.Handle(func(ctx context.Context, args assignVocationArgs) error {
+			return s.AssignVocation(ctx, args.ResidentID, args.VocationCode)
+		}),
+		CommandDissolveHousehold: journal.Handle(func(ctx context.Context, args dissolveArgs) error {
+			return s.DissolveHousehold(ctx, args.ID, args.ReassignToID)
+		}),
+		CommandMergeHouseholds: journal.Handle(func(ctx context.Context, args mergeArgs) error {
+			_, err := s.MergeHouseholds(ctx, args.SourceID, args.TargetID)
+			return err
+		}),
+		CommandSplitHousehold: journal.Handle(func(ctx context.Context, args splitArgs) error {
+			_, err := s.SplitHousehold(ctx, args.HouseholdID, args.Input)
+			return err
+		}),
+		CommandReviewRationClass: journal.Handle(func(ctx context.Context, args rationReviewArgs) error {
+			_, err := s.ReviewHouseholdRationClass(ctx, args.HouseholdID, args.Trigger)
+			return err
+		}),
+		CommandApproveRationReview: journal.Handle(func(ctx context.Context, args decideReviewArgs) error {
+			return s.ApproveRationReview(ctx, args.ReviewID, args.DecidedBy)
+		}),
+		CommandRejectRationReview: journal.Handle(func(ctx context.Context, args decideReviewArgs) error {
+			return s.RejectRationReview(ctx, args.ReviewID, args.DecidedBy)
+		}),
+		CommandRecordRelationship: journal.Handle(func(ctx context.Context, input RelationshipInput) error {
+			_, err := s.RecordRelationship(ctx, input)
+			return err
+		}),
+		CommandEndRelationship: journal.Handle(func(ctx context.Context, args endRelationshipArgs) error {
+			return s.EndRelationship(ctx, args.ID, args.Reason)
+		}),
+		CommandAssignCare: journal.Handle(func(ctx context.Context, args assignCareArgs) error {
+			_, err := s.AssignCare(ctx, args.CareID, args.GuardianID)
+			return err
+		}),
+		CommandCancelCare: journal.Handle(func(ctx context.Context, args idArgs) error {
+			return s.CancelCare(ctx, args.ID)
+		}),
+		CommandRecordAptitude: journal.Handle(func(ctx context.Context, input AptitudeInput) error {
+			_, _, err := s.RecordAptitude(ctx, input)
+			return err
+		}),
+		CommandApplyAptitude: journal.Handle(func(ctx context.Context, args idArgs) error {
+			_, _, err := s.ApplyAptitudeRecommendation(ctx, args.ID)
+			return err
+		}),
+		CommandScheduleStatus: journal.Handle(func(ctx context.Context, args scheduleStatusArgs) error {
+			_, err := s.ScheduleStatus(ctx, args.ResidentID, args.Input)
+			return err
+		}),
+		CommandEndStatus: journal.Handle(func(ctx context.Context, args residentArgs) error {
+			return s.EndStatus(ctx, args.ResidentID)
+		}),
+		CommandProcessDueTransitions: journal.Handle(func(ctx context.Context, args dueTransitionsArgs) error {
+			_, err := s.ProcessDueTransitions(ctx, args.Now)
+			return err
+		}),
+		CommandAssignHouseholdBatch: journal.Handle(func(ctx context.Context, args assignHouseholdBatchArgs) error {
+			return s.AssignToHouseholdBatch(ctx, args.ResidentIDs, args.HouseholdID)
+		}),
+		CommandSetRationClassBatch: journal.Handle(func(ctx context.Context, args rationClassBatchArgs) error {
+			return s.SetRationClassBatch(ctx, args.HouseholdIDs, args.Class)
+		}),
+		CommandSetTrainingProgram: journal.Handle(func(ctx context.Context, args trainingProgramArgs) error {
+			_, err := s.SetTrainingProgram(ctx, args.VocationCode, args.RequiredHours, args.HoursPerDay)
+			return err
+		}),
+		CommandEnrollApprentice: journal.Handle(func(ctx context.Context, input EnrollInput) error {
+			_, err := s.Enroll(ctx, input)
+			return err
+		}),
+		CommandChangeMentor: journal.Handle(func(ctx context.Context, args mentorArgs) error {
+			return s.ChangeMentor(ctx, args.ApprenticeshipID, args.MentorID)
+		}),
+		CommandSignOffApprentice: journal.Handle(func(ctx context.Context, args mentorArgs) error {
+			_, err := s.SignOff(ctx, args.ApprenticeshipID, args.MentorID)
+			return err
+		}),
+		CommandWithdrawApprentice: journal.Handle(func(ctx context.Context, args withdrawArgs) error {
+			_, err := s.Withdraw(ctx, args.ApprenticeshipID, args.Reason)
+			return err
+		}),
+		CommandAccrueTraining: journal.Handle(func(ctx context.Context, args accrueTrainingArgs) error {
+			_, err := s.AccrueTraining(ctx, args.From, args.To)
+			return err
+		}),
+		CommandRecordGeneticHealth: journal.Handle(func(ctx context.Context, args geneticHealthArgs) error {
+			_, err := s.RecordGeneticHealth(ctx, args.At)
+			return err
+		}),
+		CommandImportTransfer: journal.Handle(func(ctx context.Context, bundle TransferBundle) error {
+			_, err := s.ImportTransfer(ctx, &bundle)
+			return err
+		}),
+		CommandAdmitIntake: journal.Handle(func(ctx context.Context, input IntakeInput) error {
+			_, err := s.AdmitIntake(ctx, input)
+			return err
+		}),
+		CommandRecordScreening: journal.Handle(func(ctx context.Context, input ScreeningInput) error {
+			_, err := s.RecordScreening(ctx, input)
+			return err
+		}),
+		CommandClearIntake: journal.Handle(func(ctx context.Context, args clearIntakeArgs) error {
+			_, err := s.ClearIntake(ctx, args.ResidentID, args.OfficerID)
+			return err
+		}),
+		CommandScoreMorale: journal.Handle(func(ctx context.Context, args scoreMoraleArgs) error {
+			_, err := s.ScoreMorale(ctx, args.At)
+			return err
+		}),
+		CommandAddVenue: journal.Handle(func(ctx context.Context, input VenueInput) error {
+			_, err := s.AddVenue(ctx, input)
+			return err
+		}),
+		CommandScheduleActivity: journal.Handle(func(ctx context.Context, input ActivityInput) error {
+			_, err := s.ScheduleActivity(ctx, input)
+			return err
+		}),
+		CommandCancelActivity: journal.Handle(func(ctx context.Context, args idArgs) error {
+			return s.CancelActivity(ctx, args.ID)
+		}),
+		CommandEnrollInActivity: journal.Handle(func(ctx context.Context, args activityResidentArgs) error {
+			return s.EnrollInActivity(ctx, args.ActivityID, args.ResidentID)
+		}),
+		CommandEnrollEligible: journal.Handle(func(ctx context.Context, args idArgs) error {
+			_, err := s.EnrollEligible(ctx, args.ID)
+			return err
+		}),
+		CommandLeaveActivity: journal.Handle(func(ctx context.Context, args activityResidentArgs) error {
+			return s.LeaveActivity(ctx, args.ActivityID, args.ResidentID)
+		}),
+		CommandRecordAttendance: journal.Handle(func(ctx context.Context, input AttendanceInput) error {
+			_, err := s.RecordAttendance(ctx, input)
+			return err
+		}),
+		CommandTagResidents: journal.Handle(func(ctx context.Context, args tagResidentsArgs) error {
+			return s.TagResidents(ctx, args.ResidentIDs, args.Add, args.Remove)
+		}),
+		CommandSaveCensusPreset: journal.Handle(func(ctx context.Context, args censusPresetArgs) error {
+			_, err := s.SaveCensusPreset(ctx, args.Name, args.Filter, args.Sort)
+			return err
+		}),
+		CommandDeleteCensusPreset: journal.Handle(func(ctx context.Context, args idArgs) error {
+			return s.DeleteCensusPreset(ctx, args.ID)
+		}),
+	}
+}
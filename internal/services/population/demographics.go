@@ -230,6 +230,21 @@ func (s *Service) ProjectPopulation(ctx context.Context, asOf time.Time, years i
 	return projection, nil
 }
 
+// PopulationTrend returns the vault's headcount a month apart over months
+// months, oldest first, the last at asOf: the residents who had entered and
+// not died by then, including those away on missions or in quarantine.
+func (s *Service) PopulationTrend(ctx context.Context, asOf time.Time, months int) ([]int, error) {
+	trend := make([]int, months)
+	for i := range trend {
+		count, err := s.residents.CountPresentAt(ctx, asOf.AddDate(0, i-months+1, 0))
+		if err != nil {
+			return nil, err
+		}
+		trend[i] = count
+	}
+	return trend, nil
+}
+
 // assessViability evaluates the long-term viability of the population.
 func assessViability(current int, projection *PopulationProjection, age *AgeDistribution, sex *SexDistribution) ViabilityAssessment {
 	assessment := ViabilityAssessment{
@@ -0,0 +1,136 @@
+package population
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/simulation"
+)
+
+// DefaultGeneticHealthThreshold is the genetic health score below which the
+// genetic health check alerts when the configuration does not say.
+const DefaultGeneticHealthThreshold = 60
+
+// GeneticTrendSnapshots is how many recorded snapshots the genetic health
+// report's trend covers.
+const GeneticTrendSnapshots = 12
+
+// GeneticHealthReport is the current genetic health of the vault with the
+// recorded snapshots before it.
+type GeneticHealthReport struct {
+	Current *models.GeneticHealth
+	History []*models.GeneticHealth // Most recent first
+	Trend   int                     // Score change from the oldest snapshot to the current assessment
+}
+
+// AssessGeneticHealth measures the genetic diversity of the vault's active
+// residents as of now, without recording it.
+func (s *Service) AssessGeneticHealth(ctx context.Context) (*models.GeneticHealth, error) {
+	residents, err := s.listPedigree(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing residents: %w", err)
+	}
+	return models.AssessGeneticHealth(residents, s.now()), nil
+}
+
+// GetGeneticHealthReport assesses the vault's genetic health and compares
+// it with the last GeneticTrendSnapshots recorded snapshots.
+func (s *Service) GetGeneticHealthReport(ctx context.Context) (*GeneticHealthReport, error) {
+	current, err := s.AssessGeneticHealth(ctx)
+	if err != nil {
+		return nil, err
+	}
+	history, err := s.genetics.ListRecent(ctx, GeneticTrendSnapshots)
+	if err != nil {
+		return nil, err
+	}
+	return &GeneticHealthReport{
+		Current: current,
+		History: history,
+		Trend:   models.GeneticTrend(append([]*models.GeneticHealth{current}, history...)),
+	}, nil
+}
+
+// RecordGeneticHealth assesses the vault's genetic health as of at and
+// records the snapshot.
+func (s *Service) RecordGeneticHealth(ctx context.Context, at time.Time) (_ *models.GeneticHealth, err error) {
+	ctx, cmd := s.begin(ctx, CommandRecordGeneticHealth, geneticHealthArgs{at})
+	defer func() { cmd.End(err) }()
+
+	residents, err := s.listPedigree(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing residents: %w", err)
+	}
+	health := models.AssessGeneticHealth(residents, at)
+	health.ID = s.idGenerator.NewID()
+	if err := s.genetics.Create(ctx, nil, health); err != nil {
+		return nil, err
+	}
+	return health, nil
+}
+
+// CheckGeneticHealth records a genetic health snapshot as of at and raises
+// a warning if its score is below threshold, critical below half of it.
+func (s *Service) CheckGeneticHealth(ctx context.Context, at time.Time, threshold int) ([]simulation.Event, error) {
+	health, err := s.RecordGeneticHealth(ctx, at)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := fmt.Sprintf("score %d, %d of %d founders represented, mean kinship %.4f, %d of %d breeding pairs viable",
+		health.Score, health.FoundersRepresented, health.Founders, health.MeanKinship, health.ViablePairs, health.BreedingPairs)
+	event := simulation.Event{
+		Time:    at,
+		Level:   simulation.EventInfo,
+		Source:  "genetic health",
+		Message: "Genetic health " + summary,
+	}
+	switch {
+	case health.Population == 0:
+		return nil, nil
+	case health.Score < threshold/2:
+		event.Level = simulation.EventCritical
+		event.Message = fmt.Sprintf("Genetic diversity critically low (below %d): %s", threshold/2, summary)
+	case health.Score < threshold:
+		event.Level = simulation.EventWarning
+		event.Message = fmt.Sprintf("Genetic diversity below %d: %s", threshold, summary)
+	}
+	return []simulation.Event{event}, nil
+}
+
+// GeneticHealthJob is the scheduled job that records a genetic health
+// snapshot on the 1st of every month and alerts when the score is below
+// threshold; 0 or less takes DefaultGeneticHealthThreshold.
+func (s *Service) GeneticHealthJob(threshold int) simulation.Job {
+	if threshold <= 0 {
+		threshold = DefaultGeneticHealthThreshold
+	}
+	return simulation.Job{
+		Name:     "Genetic health",
+		Interval: simulation.Monthly,
+		Check: func(ctx context.Context, at time.Time) ([]simulation.Event, error) {
+			return s.CheckGeneticHealth(ctx, at, threshold)
+		},
+	}
+}
+
+// listPedigree retrieves every resident of the vault, living or not, whose
+// parentage the genetic health assessment traces.
+func (s *Service) listPedigree(ctx context.Context) ([]*models.Resident, error) {
+	var residents []*models.Resident
+	page := models.Pagination{Page: 1, PageSize: 100}
+	for {
+		result, err := s.residents.List(ctx, models.ResidentFilter{}, page)
+		if err != nil {
+			return nil, err
+		}
+		residents = append(residents, result.Residents...)
+		if page.Page >= result.TotalPages {
+			break
+		}
+		page.Page++
+	}
+	return residents, nil
+}
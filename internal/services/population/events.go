@@ -0,0 +1,34 @@
+package population
+
+import (
+	"context"
+
+	"github.com/vtuos/vtuos/internal/events"
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// SetEvents publishes the service's events, such as residents created, on
+// bus.
+func (s *Service) SetEvents(bus *events.Bus) {
+	s.events = bus
+}
+
+// residentCreated publishes a resident's creation once the command running
+// in ctx succeeds.
+func (s *Service) residentCreated(ctx context.Context, r *models.Resident) {
+	data := map[string]any{
+		"registry_number": r.RegistryNumber,
+		"entry_type":      r.EntryType,
+		"status":          r.Status,
+	}
+	if r.HouseholdID != nil {
+		data["household_id"] = *r.HouseholdID
+	}
+	s.events.Emit(ctx, events.Event{
+		Type:    events.ResidentCreated,
+		Time:    s.now(),
+		Vault:   r.VaultID,
+		Subject: r.ID,
+		Data:    data,
+	})
+}
@@ -0,0 +1,250 @@
+package population
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// ForecastLookbackYears is how many vault-years of births and deaths the
+// forecast derives its rates from.
+const ForecastLookbackYears = 5
+
+// Fertile ages, inclusive, for the birth rate.
+const (
+	fertileAgeMin = 15
+	fertileAgeMax = 44
+)
+
+// maxForecastAge is the oldest age a cohort is tracked at; older residents
+// are counted at this age.
+const maxForecastAge = 110
+
+// mortalityBands are the age bands the forecast tracks death rates in, with
+// the default annual rate used when the vault has no death history.
+var mortalityBands = []struct {
+	MaxAge      int
+	DefaultRate float64
+}{
+	{2, 0.01},
+	{12, 0.001},
+	{17, 0.001},
+	{25, 0.002},
+	{45, 0.003},
+	{65, 0.01},
+	{maxForecastAge, 0.05},
+}
+
+// defaultFertilityRate is the default annual births per woman of fertile
+// age, roughly 2.1 children over 26 years.
+const defaultFertilityRate = 0.08
+
+// VitalRates are the annual rates a forecast applies.
+type VitalRates struct {
+	FertilityRate float64   // Births per woman aged 15-44 per year
+	MortalityRate []float64 // Deaths per resident per year, by mortality band
+	Births        int       // Births in the lookback window
+	Deaths        int       // Deaths in the lookback window
+	Historical    bool      // Derived from vault records rather than defaults
+}
+
+// mortality returns the annual death rate at an age.
+func (v VitalRates) mortality(age int) float64 {
+	return v.MortalityRate[mortalityBand(age)]
+}
+
+// mortalityBand returns the index of the mortality band an age falls in.
+func mortalityBand(age int) int {
+	for i, band := range mortalityBands {
+		if age <= band.MaxAge {
+			return i
+		}
+	}
+	return len(mortalityBands) - 1
+}
+
+// ForecastPoint is the projected population at the end of a vault-year.
+type ForecastPoint struct {
+	Year       int
+	Population int
+	Births     int
+	Deaths     int
+	Children   int // 0-17
+	WorkingAge int // 18-65
+	Seniors    int // 66+
+}
+
+// PopulationForecast is a cohort projection of the active population.
+type PopulationForecast struct {
+	AsOf    time.Time
+	Current int
+	Rates   VitalRates
+	Points  []ForecastPoint
+}
+
+// Final returns the projected population at the end of the horizon.
+func (f *PopulationForecast) Final() int {
+	if len(f.Points) == 0 {
+		return f.Current
+	}
+	return f.Points[len(f.Points)-1].Population
+}
+
+// ForecastPopulation projects the active population over the given number
+// of vault-years. Residents are aged one year at a time by sex and single
+// year of age; deaths follow age-banded rates and births the fertility of
+// women aged 15-44. Rates come from the births and deaths of the last
+// ForecastLookbackYears; with no births or deaths on record, and for age
+// bands without a recorded death, default rates are used.
+func (s *Service) ForecastPopulation(ctx context.Context, asOf time.Time, years int) (*PopulationForecast, error) {
+	residents, err := s.listActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rates, err := s.vitalRates(ctx, asOf, residents)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cohorts by single year of age, as expected counts
+	var female, male [maxForecastAge + 1]float64
+	for _, r := range residents {
+		age := min(r.Age(asOf), maxForecastAge)
+		if r.Sex == models.SexFemale {
+			female[age]++
+		} else {
+			male[age]++
+		}
+	}
+
+	forecast := &PopulationForecast{AsOf: asOf, Current: len(residents), Rates: rates}
+	for y := 1; y <= years; y++ {
+		var births, deaths float64
+		for age := fertileAgeMin; age <= fertileAgeMax; age++ {
+			births += female[age] * rates.FertilityRate
+		}
+
+		// Age every cohort a year, the oldest staying at maxForecastAge
+		for age := maxForecastAge; age >= 0; age-- {
+			rate := rates.mortality(age)
+			deaths += (female[age] + male[age]) * rate
+			survivingF, survivingM := female[age]*(1-rate), male[age]*(1-rate)
+			female[age], male[age] = 0, 0
+			next := min(age+1, maxForecastAge)
+			female[next] += survivingF
+			male[next] += survivingM
+		}
+		female[0], male[0] = births/2, births/2
+
+		point := ForecastPoint{
+			Year:   asOf.Year() + y,
+			Births: int(math.Round(births)),
+			Deaths: int(math.Round(deaths)),
+		}
+		var children, working, seniors float64
+		for age := 0; age <= maxForecastAge; age++ {
+			n := female[age] + male[age]
+			switch {
+			case age <= 17:
+				children += n
+			case age <= 65:
+				working += n
+			default:
+				seniors += n
+			}
+		}
+		point.Children = int(math.Round(children))
+		point.WorkingAge = int(math.Round(working))
+		point.Seniors = int(math.Round(seniors))
+		point.Population = int(math.Round(children + working + seniors))
+		forecast.Points = append(forecast.Points, point)
+	}
+
+	return forecast, nil
+}
+
+// vitalRates derives annual fertility and mortality from the births and
+// deaths of the lookback window, measured against the residents alive at
+// its end plus those who died during it.
+func (s *Service) vitalRates(ctx context.Context, asOf time.Time, active []*models.Resident) (VitalRates, error) {
+	from := asOf.AddDate(-ForecastLookbackYears, 0, 0)
+	entered, err := s.residents.ListEnteredBetween(ctx, from, asOf)
+	if err != nil {
+		return VitalRates{}, err
+	}
+	died, err := s.residents.ListDiedBetween(ctx, from, asOf)
+	if err != nil {
+		return VitalRates{}, err
+	}
+
+	rates := VitalRates{MortalityRate: make([]float64, len(mortalityBands))}
+	for _, r := range entered {
+		if r.EntryType == models.EntryTypeVaultBorn {
+			rates.Births++
+		}
+	}
+	rates.Deaths = len(died)
+
+	if rates.Births == 0 && rates.Deaths == 0 {
+		rates.FertilityRate = defaultFertilityRate
+		for i, band := range mortalityBands {
+			rates.MortalityRate[i] = band.DefaultRate
+		}
+		return rates, nil
+	}
+	rates.Historical = true
+
+	var women int
+	exposure := make([]int, len(mortalityBands))
+	deaths := make([]int, len(mortalityBands))
+	for _, r := range active {
+		age := r.Age(asOf)
+		exposure[mortalityBand(age)]++
+		if r.Sex == models.SexFemale && age >= fertileAgeMin && age <= fertileAgeMax {
+			women++
+		}
+	}
+	for _, r := range died {
+		i := mortalityBand(r.Age(*r.DateOfDeath))
+		exposure[i]++
+		deaths[i]++
+	}
+
+	if women > 0 {
+		rates.FertilityRate = float64(rates.Births) / float64(women) / ForecastLookbackYears
+	}
+	for i, band := range mortalityBands {
+		// A few years of a small vault leave bands without a death; those
+		// keep the default rate rather than projecting no deaths at all
+		rates.MortalityRate[i] = band.DefaultRate
+		if deaths[i] > 0 {
+			rates.MortalityRate[i] = float64(deaths[i]) / float64(exposure[i]) / ForecastLookbackYears
+		}
+	}
+	return rates, nil
+}
+
+// listActive retrieves every active resident.
+func (s *Service) listActive(ctx context.Context) ([]*models.Resident, error) {
+	filter := models.ResidentFilter{
+		Status: ptr(models.ResidentStatusActive),
+	}
+
+	var residents []*models.Resident
+	page := models.Pagination{Page: 1, PageSize: 100}
+	for {
+		result, err := s.residents.List(ctx, filter, page)
+		if err != nil {
+			return nil, err
+		}
+		residents = append(residents, result.Residents...)
+		if page.Page >= result.TotalPages {
+			break
+		}
+		page.Page++
+	}
+	return residents, nil
+}
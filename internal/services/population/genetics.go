@@ -83,6 +83,11 @@ func (s *Service) CalculateCOI(ctx context.Context, parent1ID, parent2ID string)
 		pathCoef := pow(0.5, gens.gen1+gens.gen2+1) * (1 + ancestorCOI)
 		coi += pathCoef
 	}
+	// Ancestor lookups fall back to no inbreeding, which a cancelled
+	// context must not pass off as the result
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
 
 	return coi, nil
 }
@@ -197,6 +202,7 @@ func (s *Service) GetAncestry(ctx context.Context, residentID string, generation
 		return nil, err
 	}
 
+	s.redactTree(ctx, tree)
 	return tree, nil
 }
 
@@ -267,6 +273,7 @@ func (s *Service) GetDescendants(ctx context.Context, residentID string, generat
 		return nil, err
 	}
 
+	s.redactTree(ctx, tree)
 	return tree, nil
 }
 
@@ -318,6 +325,7 @@ func (s *Service) FindCommonAncestors(ctx context.Context, resident1ID, resident
 		}
 	}
 
+	s.redactResidents(ctx, common...)
 	return common, nil
 }
 
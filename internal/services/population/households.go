@@ -0,0 +1,366 @@
+package population
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+)
+
+// SplitHouseholdInput contains data for splitting members off into a new
+// household.
+type SplitHouseholdInput struct {
+	MemberIDs         []string
+	HeadOfHouseholdID *string              // Defaults to the eldest member
+	HouseholdType     models.HouseholdType // Defaults to INDIVIDUAL or FAMILY by size
+	SpouseIDs         []string             // Two of the members to record as married
+}
+
+// DissolveHousehold closes an active household. Its living members move to
+// the household with ID reassignToID, or are left without a household when
+// it is empty. The designation is retained on the dissolved record. A
+// dual authorization policy may require a second operator to confirm it.
+func (s *Service) DissolveHousehold(ctx context.Context, id, reassignToID string) (err error) {
+	ctx, cmd := s.begin(ctx, CommandDissolveHousehold, dissolveArgs{id, reassignToID})
+	defer func() { cmd.End(err) }()
+
+	household, err := s.activeHousehold(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	var target *models.Household
+	if reassignToID != "" {
+		if reassignToID == id {
+			return fmt.Errorf("%w: cannot reassign members to the household being dissolved", repository.ErrValidation)
+		}
+		if target, err = s.activeHousehold(ctx, reassignToID); err != nil {
+			return err
+		}
+	}
+
+	members, err := s.livingMembers(ctx, id)
+	if err != nil {
+		return err
+	}
+	var joined []*models.Resident
+	if target != nil {
+		staying, err := s.livingMembers(ctx, target.ID)
+		if err != nil {
+			return err
+		}
+		joined = append(staying, members...)
+	}
+	auth, err := s.authorizer.Authorize(ctx, nil, models.DualAuthDissolveHousehold, 0)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var targetID *string
+	if target != nil {
+		targetID = &target.ID
+	}
+	if err := s.moveMembers(ctx, tx, members, targetID); err != nil {
+		return err
+	}
+	if target != nil && len(members) > 0 {
+		target.HeadOfHouseholdID = resolveHead([]*string{target.HeadOfHouseholdID, household.HeadOfHouseholdID}, joined)
+		if err := s.households.Update(ctx, tx, target); err != nil {
+			return fmt.Errorf("updating household %s: %w", target.Designation, err)
+		}
+	}
+	if err := s.closeHousehold(ctx, tx, household, models.HouseholdStatusDissolved); err != nil {
+		return err
+	}
+	if err := s.authorizer.Record(ctx, tx, auth, models.AuditEntityHousehold, household.ID, s.now()); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+
+	if target != nil {
+		s.queueRationReview(ctx, target.ID, models.RationReviewTriggerTransfer)
+	}
+	return nil
+}
+
+// MergeHouseholds moves the living members of the source household into the
+// target and marks the source MERGED. The target keeps its designation and
+// its head of household while they are alive; otherwise the source head, then
+// the eldest member, takes over. An INDIVIDUAL target becomes a FAMILY.
+func (s *Service) MergeHouseholds(ctx context.Context, sourceID, targetID string) (_ *models.Household, err error) {
+	ctx, cmd := s.begin(ctx, CommandMergeHouseholds, mergeArgs{sourceID, targetID})
+	defer func() { cmd.End(err) }()
+
+	if sourceID == targetID {
+		return nil, fmt.Errorf("%w: cannot merge a household into itself", repository.ErrValidation)
+	}
+	source, err := s.activeHousehold(ctx, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.activeHousehold(ctx, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	moving, err := s.livingMembers(ctx, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	staying, err := s.livingMembers(ctx, targetID)
+	if err != nil {
+		return nil, err
+	}
+	members := append(staying, moving...)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.moveMembers(ctx, tx, moving, &target.ID); err != nil {
+		return nil, err
+	}
+
+	target.HeadOfHouseholdID = resolveHead([]*string{target.HeadOfHouseholdID, source.HeadOfHouseholdID}, members)
+	if target.HouseholdType == models.HouseholdTypeIndividual && len(members) > 1 {
+		target.HouseholdType = models.HouseholdTypeFamily
+	}
+	if err := s.households.Update(ctx, tx, target); err != nil {
+		return nil, fmt.Errorf("updating household %s: %w", target.Designation, err)
+	}
+	if err := s.closeHousehold(ctx, tx, source, models.HouseholdStatusMerged); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("committing transaction: %w", err)
+	}
+
+	s.queueRationReview(ctx, target.ID, models.RationReviewTriggerTransfer)
+	return target, nil
+}
+
+// SplitHousehold moves some living members of a household into a new one
+// with the next designation and the same ration class. At least one member
+// must stay behind; if the head leaves, the household gets a new head. A
+// couple leaving to start their own household can be recorded as married
+// with input.SpouseIDs.
+func (s *Service) SplitHousehold(ctx context.Context, householdID string, input SplitHouseholdInput) (_ *models.Household, err error) {
+	ctx, cmd := s.begin(ctx, CommandSplitHousehold, splitArgs{householdID, input})
+	defer func() { cmd.End(err) }()
+
+	source, err := s.activeHousehold(ctx, householdID)
+	if err != nil {
+		return nil, err
+	}
+	if len(input.MemberIDs) == 0 {
+		return nil, fmt.Errorf("%w: no members to split off", repository.ErrValidation)
+	}
+
+	members, err := s.livingMembers(ctx, householdID)
+	if err != nil {
+		return nil, err
+	}
+
+	leaving := make(map[string]bool, len(input.MemberIDs))
+	for _, id := range input.MemberIDs {
+		leaving[id] = true
+	}
+	var moving, staying []*models.Resident
+	for _, m := range members {
+		if leaving[m.ID] {
+			moving = append(moving, m)
+			delete(leaving, m.ID)
+		} else {
+			staying = append(staying, m)
+		}
+	}
+	if len(leaving) > 0 {
+		return nil, fmt.Errorf("%w: %d resident(s) are not living members of household %s",
+			repository.ErrValidation, len(leaving), source.Designation)
+	}
+	if len(staying) == 0 {
+		return nil, fmt.Errorf("%w: at least one member must stay in household %s",
+			repository.ErrValidation, source.Designation)
+	}
+
+	for _, id := range input.SpouseIDs {
+		if !slices.Contains(input.MemberIDs, id) {
+			return nil, fmt.Errorf("%w: spouses must be among the members splitting off", repository.ErrValidation)
+		}
+	}
+	marriage, err := s.newMarriage(ctx, input.SpouseIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	householdType := input.HouseholdType
+	if householdType == "" {
+		householdType = models.HouseholdTypeIndividual
+		if len(moving) > 1 {
+			householdType = models.HouseholdTypeFamily
+		}
+	}
+
+	designation, err := s.households.GetNextDesignation(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("generating designation: %w", err)
+	}
+	now := s.now()
+	split := &models.Household{
+		ID:                s.idGenerator.NewID(),
+		Designation:       designation,
+		HouseholdType:     householdType,
+		HeadOfHouseholdID: resolveHead([]*string{input.HeadOfHouseholdID}, moving),
+		RationClass:       source.RationClass,
+		Status:            models.HouseholdStatusActive,
+		FormedDate:        time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC),
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.households.Create(ctx, tx, split); err != nil {
+		return nil, fmt.Errorf("creating household: %w", err)
+	}
+	if err := s.moveMembers(ctx, tx, moving, &split.ID); err != nil {
+		return nil, err
+	}
+	if marriage != nil {
+		if err := s.relationships.Create(ctx, tx, marriage); err != nil {
+			return nil, err
+		}
+	}
+
+	source.HeadOfHouseholdID = resolveHead([]*string{source.HeadOfHouseholdID}, staying)
+	if err := s.households.Update(ctx, tx, source); err != nil {
+		return nil, fmt.Errorf("updating household %s: %w", source.Designation, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("committing transaction: %w", err)
+	}
+
+	s.queueRationReview(ctx, source.ID, models.RationReviewTriggerTransfer)
+	s.queueRationReview(ctx, split.ID, models.RationReviewTriggerTransfer)
+	return split, nil
+}
+
+// SplitHouseholdByRegistry splits the residents with the given registry
+// numbers off into a new household.
+func (s *Service) SplitHouseholdByRegistry(ctx context.Context, householdID string, registryNumbers []string) (*models.Household, error) {
+	input := SplitHouseholdInput{MemberIDs: make([]string, 0, len(registryNumbers))}
+	for _, regNum := range registryNumbers {
+		resident, err := s.residents.GetByRegistryNumber(ctx, nil, regNum)
+		if err != nil {
+			return nil, fmt.Errorf("resident %s: %w", regNum, err)
+		}
+		input.MemberIDs = append(input.MemberIDs, resident.ID)
+	}
+	return s.SplitHousehold(ctx, householdID, input)
+}
+
+// GetHouseholdByDesignation retrieves a household by designation.
+func (s *Service) GetHouseholdByDesignation(ctx context.Context, designation string) (*models.Household, error) {
+	household, err := s.households.GetByDesignation(ctx, designation)
+	if err != nil {
+		return nil, fmt.Errorf("household %s: %w", designation, err)
+	}
+	return household, nil
+}
+
+// activeHousehold retrieves a household that can still change membership.
+func (s *Service) activeHousehold(ctx context.Context, id string) (*models.Household, error) {
+	household, err := s.households.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !household.IsActive() {
+		return nil, fmt.Errorf("%w: household %s is %s", repository.ErrValidation, household.Designation, household.Status)
+	}
+	return household, nil
+}
+
+// livingMembers retrieves the members of a household who are alive, eldest
+// first. Deceased residents keep their household as a historical record.
+func (s *Service) livingMembers(ctx context.Context, householdID string) ([]*models.Resident, error) {
+	members, err := s.residents.GetByHousehold(ctx, householdID)
+	if err != nil {
+		return nil, fmt.Errorf("getting household members: %w", err)
+	}
+	living := members[:0]
+	for _, m := range members {
+		if m.IsAlive() {
+			living = append(living, m)
+		}
+	}
+	return living, nil
+}
+
+// moveMembers sets the household of each resident within tx.
+func (s *Service) moveMembers(ctx context.Context, tx *sql.Tx, members []*models.Resident, householdID *string) error {
+	for _, m := range members {
+		m.HouseholdID = householdID
+		if err := s.residents.Update(ctx, tx, m); err != nil {
+			return fmt.Errorf("moving resident %s: %w", m.RegistryNumber, err)
+		}
+	}
+	return nil
+}
+
+// closeHousehold ends a household with the given status as of today.
+func (s *Service) closeHousehold(ctx context.Context, tx *sql.Tx, household *models.Household, status models.HouseholdStatus) error {
+	now := s.now()
+	closed := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	household.Status = status
+	household.DissolvedDate = &closed
+	household.HeadOfHouseholdID = nil
+	if err := s.households.Update(ctx, tx, household); err != nil {
+		return fmt.Errorf("closing household %s: %w", household.Designation, err)
+	}
+	return nil
+}
+
+// resolveHead picks a head of household from living members: the first
+// preferred ID that is a member, otherwise the eldest member.
+func resolveHead(preferred []*string, members []*models.Resident) *string {
+	for _, id := range preferred {
+		if id == nil {
+			continue
+		}
+		for _, m := range members {
+			if m.ID == *id {
+				return &m.ID
+			}
+		}
+	}
+
+	var eldest *models.Resident
+	for _, m := range members {
+		if eldest == nil || m.DateOfBirth.Before(eldest.DateOfBirth) {
+			eldest = m
+		}
+	}
+	if eldest == nil {
+		return nil
+	}
+	return &eldest.ID
+}
@@ -0,0 +1,281 @@
+package population
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// Resident import CSV columns. The header row is required; column order is free.
+const (
+	ImportColSurname        = "surname"
+	ImportColGivenNames     = "given_names"
+	ImportColDateOfBirth    = "date_of_birth"
+	ImportColSex            = "sex"
+	ImportColBloodType      = "blood_type"
+	ImportColEntryType      = "entry_type"
+	ImportColEntryDate      = "entry_date"
+	ImportColHousehold      = "household"
+	ImportColClearanceLevel = "clearance_level"
+	ImportColNotes          = "notes"
+)
+
+var requiredImportColumns = []string{
+	ImportColSurname, ImportColGivenNames, ImportColDateOfBirth, ImportColSex,
+}
+
+// ImportRowError describes why a single CSV row was rejected.
+type ImportRowError struct {
+	Line int // 1-based line number in the file, header is line 1
+	Err  error
+}
+
+func (e ImportRowError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// ImportResult summarizes a resident import.
+type ImportResult struct {
+	RowsRead          int
+	Imported          int
+	HouseholdsCreated int
+	Errors            []ImportRowError
+}
+
+// HasErrors returns true if any row was rejected.
+func (r *ImportResult) HasErrors() bool {
+	return len(r.Errors) > 0
+}
+
+// importRow is a parsed CSV row awaiting insertion.
+type importRow struct {
+	line      int
+	resident  *models.Resident
+	household string
+}
+
+// ImportResidents reads a resident manifest in CSV form and creates a resident
+// for every row. Rows are validated with models.Resident.Validate before
+// anything is written; if any row is rejected, nothing is imported and the
+// per-row errors are returned in the result. Households are resolved by
+// designation and created when they do not yet exist.
+//
+// Defaults: entry_type ORIGINAL, entry_date the vault's current date,
+// clearance_level 1.
+func (s *Service) ImportResidents(ctx context.Context, r io.Reader) (_ *ImportResult, err error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading import file: %w", err)
+	}
+	ctx, cmd := s.begin(ctx, CommandImportResidents, importArgs{string(data)})
+	defer func() { cmd.End(err) }()
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("import file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range requiredImportColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("missing required column: %s", name)
+		}
+	}
+
+	result := &ImportResult{}
+	var rows []importRow
+	line := 1
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			result.Errors = append(result.Errors, ImportRowError{Line: line, Err: err})
+			continue
+		}
+		result.RowsRead++
+
+		row, err := s.parseImportRow(columns, record)
+		if err != nil {
+			result.Errors = append(result.Errors, ImportRowError{Line: line, Err: err})
+			continue
+		}
+		row.line = line
+		rows = append(rows, row)
+	}
+
+	if result.HasErrors() || len(rows) == 0 {
+		return result, nil
+	}
+
+	if err := s.insertImportRows(ctx, rows, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// parseImportRow converts a CSV record into a validated resident.
+func (s *Service) parseImportRow(columns map[string]int, record []string) (importRow, error) {
+	field := func(name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var errs []error
+
+	dob, err := util.ParseDate(field(ImportColDateOfBirth))
+	if err != nil {
+		errs = append(errs, fmt.Errorf("invalid date_of_birth: %q", field(ImportColDateOfBirth)))
+	}
+
+	entryType := models.EntryTypeOriginal
+	if v := field(ImportColEntryType); v != "" {
+		entryType = models.EntryType(strings.ToUpper(v))
+	}
+
+	entryDate := s.now()
+	if v := field(ImportColEntryDate); v != "" {
+		entryDate, err = util.ParseDate(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid entry_date: %q", v))
+		}
+	}
+
+	clearance := 1
+	if v := field(ImportColClearanceLevel); v != "" {
+		clearance, err = strconv.Atoi(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid clearance_level: %q", v))
+		}
+	}
+
+	if len(errs) > 0 {
+		return importRow{}, errors.Join(errs...)
+	}
+
+	resident := &models.Resident{
+		// Placeholders so Validate can run before numbers are assigned
+		ID:             "pending",
+		RegistryNumber: "pending",
+		Surname:        field(ImportColSurname),
+		GivenNames:     field(ImportColGivenNames),
+		DateOfBirth:    dob,
+		Sex:            models.Sex(strings.ToUpper(field(ImportColSex))),
+		BloodType:      models.BloodType(strings.ToUpper(field(ImportColBloodType))),
+		EntryType:      entryType,
+		EntryDate:      entryDate,
+		Status:         models.ResidentStatusActive,
+		ClearanceLevel: clearance,
+		Notes:          field(ImportColNotes),
+	}
+	if err := resident.Validate(); err != nil {
+		return importRow{}, err
+	}
+
+	return importRow{resident: resident, household: field(ImportColHousehold)}, nil
+}
+
+// insertImportRows writes all parsed rows in a single transaction.
+func (s *Service) insertImportRows(ctx context.Context, rows []importRow, result *ImportResult) error {
+	// Resolve existing households before the transaction takes the connection
+	households := make(map[string]*models.Household)
+	memberCounts := make(map[string]int)
+	for _, row := range rows {
+		if row.household == "" {
+			continue
+		}
+		memberCounts[row.household]++
+		if _, ok := households[row.household]; ok {
+			continue
+		}
+		h, err := s.households.GetByDesignation(ctx, row.household)
+		switch {
+		case err == nil:
+			households[row.household] = h
+		case errors.Is(err, repository.ErrNotFound):
+			households[row.household] = nil // Created with its first member
+		default:
+			return fmt.Errorf("line %d: looking up household %s: %w", row.line, row.household, err)
+		}
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	numbers, err := s.reserveRegistryNumbers(ctx, tx, len(rows))
+	if err != nil {
+		return err
+	}
+
+	for i, row := range rows {
+		resident := row.resident
+		resident.ID = s.idGenerator.NewID()
+		resident.RegistryNumber = numbers[i]
+
+		if row.household != "" {
+			household := households[row.household]
+			if household == nil {
+				householdType := models.HouseholdTypeIndividual
+				if memberCounts[row.household] > 1 {
+					householdType = models.HouseholdTypeFamily
+				}
+				household = &models.Household{
+					ID:            s.idGenerator.NewID(),
+					Designation:   row.household,
+					HouseholdType: householdType,
+					RationClass:   models.RationClassStandard,
+					Status:        models.HouseholdStatusActive,
+					FormedDate:    resident.EntryDate,
+				}
+				if err := s.households.Create(ctx, tx, household); err != nil {
+					return fmt.Errorf("line %d: creating household %s: %w", row.line, row.household, err)
+				}
+				households[row.household] = household
+				result.HouseholdsCreated++
+			}
+			resident.HouseholdID = &household.ID
+		}
+
+		if err := s.residents.Create(ctx, tx, resident); err != nil {
+			return fmt.Errorf("line %d: creating resident: %w", row.line, err)
+		}
+		s.residentCreated(ctx, resident)
+		result.Imported++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+
+	return nil
+}
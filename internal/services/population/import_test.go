@@ -0,0 +1,96 @@
+package population
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/testutil"
+)
+
+func setupImportService(t *testing.T) (*Service, *testutil.TestDB) {
+	t.Helper()
+	db := testutil.NewTestDBWithFile(t)
+	t.Cleanup(func() { db.Close(t) })
+	db.RunMigrations(t, filepath.Join("..", "..", "database", "migrations"))
+	return NewService(db.DB, 76), db
+}
+
+const importHeader = "surname,given_names,date_of_birth,sex,blood_type,household\n"
+
+func TestImportResidents_CreatesHousehold(t *testing.T) {
+	svc, db := setupImportService(t)
+	ctx := context.Background()
+
+	csv := importHeader +
+		"Hawthorne,Edith,2050-03-14,F,O+,H-0100\n" +
+		"Hawthorne,Amos,2049-07-02,M,A+,H-0100\n"
+	result, err := svc.ImportResidents(ctx, strings.NewReader(csv))
+	if err != nil {
+		t.Fatalf("ImportResidents() error = %v", err)
+	}
+	if result.HasErrors() || result.Imported != 2 || result.HouseholdsCreated != 1 {
+		t.Fatalf("result = %+v, want 2 imported into 1 new household", result)
+	}
+
+	household, err := repository.NewHouseholdRepository(db.DB).GetByDesignation(ctx, "H-0100")
+	if err != nil {
+		t.Fatalf("GetByDesignation() error = %v", err)
+	}
+	if household.HouseholdType != models.HouseholdTypeFamily {
+		t.Errorf("household type = %s, want %s", household.HouseholdType, models.HouseholdTypeFamily)
+	}
+	if members, err := repository.NewHouseholdRepository(db.DB).GetMemberCount(ctx, household.ID); err != nil || members != 2 {
+		t.Errorf("GetMemberCount() = %d, %v, want 2 members", members, err)
+	}
+}
+
+func TestImportResidents_ReusesHousehold(t *testing.T) {
+	svc, db := setupImportService(t)
+	ctx := context.Background()
+
+	existing := testutil.FixtureHousehold(func(h *models.Household) { h.Designation = "H-0200" })
+	if err := repository.NewHouseholdRepository(db.DB).Create(ctx, nil, existing); err != nil {
+		t.Fatalf("creating household: %v", err)
+	}
+
+	csv := importHeader + "Hawthorne,Edith,2050-03-14,F,O+,H-0200\n"
+	result, err := svc.ImportResidents(ctx, strings.NewReader(csv))
+	if err != nil {
+		t.Fatalf("ImportResidents() error = %v", err)
+	}
+	if result.Imported != 1 || result.HouseholdsCreated != 0 {
+		t.Fatalf("result = %+v, want 1 imported into the existing household", result)
+	}
+
+	residents, err := repository.NewResidentRepository(db.DB).FindByIdentity(ctx, "Hawthorne", "Edith", time.Date(2050, 3, 14, 0, 0, 0, 0, time.UTC))
+	if err != nil || len(residents) != 1 {
+		t.Fatalf("FindByIdentity() = %v, %v, want the imported resident", residents, err)
+	}
+	if got := residents[0].HouseholdID; got == nil || *got != existing.ID {
+		t.Errorf("household = %v, want %s", got, existing.ID)
+	}
+}
+
+func TestImportResidents_HouseholdLookupError(t *testing.T) {
+	svc, db := setupImportService(t)
+	ctx := context.Background()
+
+	// Any failure but not-found must stop the import rather than create
+	db.ExecSQL(t, `ALTER TABLE households RENAME TO households_moved`)
+
+	csv := importHeader + "Hawthorne,Edith,2050-03-14,F,O+,H-0300\n"
+	result, err := svc.ImportResidents(ctx, strings.NewReader(csv))
+	if err == nil {
+		t.Fatalf("ImportResidents() = %+v, want the lookup error", result)
+	}
+	if errors.Is(err, repository.ErrNotFound) || !strings.Contains(err.Error(), "looking up household H-0300") {
+		t.Errorf("ImportResidents() error = %v, want the lookup error rather than an attempt to create", err)
+	}
+	db.AssertRowCount(t, "residents", 0)
+}
@@ -0,0 +1,319 @@
+package population
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// IntakeInput contains data for admitting an outsider through intake.
+type IntakeInput struct {
+	Surname        string
+	GivenNames     string
+	DateOfBirth    time.Time
+	Sex            models.Sex
+	BloodType      models.BloodType
+	QuarantineDays int // 0 for models.MinIntakeQuarantineDays
+	Notes          string
+}
+
+// ScreeningInput contains a medical officer's result of an intake
+// screening.
+type ScreeningInput struct {
+	ScreeningID string
+	OfficerID   string
+	Passed      bool
+	Notes       string
+}
+
+// AdmitIntake admits an outsider, a visitor or a surface survivor, as an
+// ADMITTED resident in QUARANTINE. The quarantine runs at least
+// models.MinIntakeQuarantineDays from today and does not end on its own;
+// every screening of models.IntakeScreenings is scheduled within it.
+func (s *Service) AdmitIntake(ctx context.Context, input IntakeInput) (_ *models.Intake, err error) {
+	ctx, cmd := s.begin(ctx, CommandAdmitIntake, input)
+	defer func() { cmd.End(err) }()
+
+	if input.QuarantineDays == 0 {
+		input.QuarantineDays = models.MinIntakeQuarantineDays
+	}
+	if input.QuarantineDays < models.MinIntakeQuarantineDays {
+		return nil, fmt.Errorf("%w: quarantine must be at least %d days", repository.ErrValidation, models.MinIntakeQuarantineDays)
+	}
+
+	now := s.now()
+	resident := &models.Resident{
+		ID:             s.idGenerator.NewID(),
+		Surname:        input.Surname,
+		GivenNames:     input.GivenNames,
+		DateOfBirth:    input.DateOfBirth,
+		Sex:            input.Sex,
+		BloodType:      input.BloodType,
+		EntryType:      models.EntryTypeAdmitted,
+		EntryDate:      now,
+		Status:         models.ResidentStatusQuarantine,
+		ClearanceLevel: 1,
+		Notes:          input.Notes,
+	}
+	intake, transition := s.newIntake(resident, input.QuarantineDays, input.Notes, now)
+
+	err = repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		numbers, err := s.reserveRegistryNumbers(ctx, tx, 1)
+		if err != nil {
+			return err
+		}
+		resident.RegistryNumber = numbers[0]
+		if err := s.residents.Create(ctx, tx, resident); err != nil {
+			return fmt.Errorf("creating resident: %w", err)
+		}
+		return s.createIntake(ctx, tx, intake, transition)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.residentCreated(ctx, resident)
+	return intake, nil
+}
+
+// newIntake builds the intake of a resident admitted at now: a quarantine
+// of days days from today, recorded as an open QUARANTINE status period,
+// with every screening of models.IntakeScreenings scheduled within it.
+func (s *Service) newIntake(resident *models.Resident, days int, notes string, now time.Time) (*models.Intake, *models.StatusTransition) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	end := today.AddDate(0, 0, days-1)
+
+	transition := &models.StatusTransition{
+		ID:          s.idGenerator.NewID(),
+		ResidentID:  resident.ID,
+		Status:      models.ResidentStatusQuarantine,
+		Reason:      "Intake quarantine",
+		StartedAt:   now,
+		ExpectedEnd: end,
+	}
+	intake := &models.Intake{
+		ID:             s.idGenerator.NewID(),
+		ResidentID:     resident.ID,
+		AdmittedAt:     now,
+		QuarantineDays: days,
+		QuarantineEnd:  end,
+		Notes:          notes,
+		Resident:       resident,
+	}
+	for _, screening := range models.IntakeScreenings {
+		intake.Screenings = append(intake.Screenings, &models.IntakeScreening{
+			ID:        s.idGenerator.NewID(),
+			IntakeID:  intake.ID,
+			Screening: screening,
+			DueDate:   screening.DueDate(now, end),
+		})
+	}
+	return intake, transition
+}
+
+// createIntake records an intake built by newIntake, with its quarantine
+// period and screenings, in tx. The resident must already be created.
+func (s *Service) createIntake(ctx context.Context, tx *sql.Tx, intake *models.Intake, transition *models.StatusTransition) error {
+	if err := s.history.Create(ctx, tx, transition); err != nil {
+		return fmt.Errorf("recording status history: %w", err)
+	}
+	if err := s.intakes.Create(ctx, tx, intake); err != nil {
+		return err
+	}
+	for _, screening := range intake.Screenings {
+		if err := s.intakes.CreateScreening(ctx, tx, screening); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RecordScreening records a medical officer's result of an intake
+// screening. A failed screening can be retested until the intake is
+// cleared; the latest result stands.
+func (s *Service) RecordScreening(ctx context.Context, input ScreeningInput) (_ *models.IntakeScreening, err error) {
+	ctx, cmd := s.begin(ctx, CommandRecordScreening, input)
+	defer func() { cmd.End(err) }()
+
+	screening, err := s.intakes.GetScreening(ctx, input.ScreeningID)
+	if err != nil {
+		return nil, err
+	}
+	intake, err := s.intakes.GetByID(ctx, screening.IntakeID)
+	if err != nil {
+		return nil, err
+	}
+	if intake.IsCleared() {
+		return nil, fmt.Errorf("%w: intake was cleared %s", repository.ErrValidation, util.FormatDay(*intake.ClearedAt))
+	}
+	officer, err := s.medicalOfficer(ctx, input.OfficerID)
+	if err != nil {
+		return nil, err
+	}
+	if officer.ID == intake.ResidentID {
+		return nil, fmt.Errorf("%w: a resident cannot screen themselves", repository.ErrValidation)
+	}
+
+	now := s.now()
+	screening.CompletedAt = &now
+	screening.CompletedBy = &officer.ID
+	screening.Passed = &input.Passed
+	screening.Notes = input.Notes
+	if err := s.intakes.RecordResult(ctx, nil, screening); err != nil {
+		return nil, err
+	}
+	return screening, nil
+}
+
+// ClearIntake records a medical officer's clearance of a quarantined
+// intake whose screenings have all passed. A resident who has served the
+// quarantine is promoted to ACTIVE at once; otherwise the quarantine ends on
+// its own at the end of its last day.
+func (s *Service) ClearIntake(ctx context.Context, residentID, officerID string) (_ *models.Intake, err error) {
+	ctx, cmd := s.begin(ctx, CommandClearIntake, clearIntakeArgs{residentID, officerID})
+	defer func() { cmd.End(err) }()
+
+	intake, err := s.loadIntake(ctx, residentID)
+	if err != nil {
+		return nil, err
+	}
+	resident := intake.Resident
+	if intake.IsCleared() {
+		return nil, fmt.Errorf("%w: intake of %s was cleared %s", repository.ErrValidation,
+			resident.RegistryNumber, util.FormatDay(*intake.ClearedAt))
+	}
+	if resident.Status != models.ResidentStatusQuarantine {
+		return nil, fmt.Errorf("%w: resident %s is %s", repository.ErrValidation, resident.RegistryNumber, resident.Status)
+	}
+	if pending := intake.PendingScreenings(); len(pending) > 0 {
+		names := make([]string, len(pending))
+		for i, p := range pending {
+			names[i] = string(p.Screening)
+		}
+		return nil, fmt.Errorf("%w: screenings not passed: %s", repository.ErrValidation, strings.Join(names, ", "))
+	}
+	officer, err := s.medicalOfficer(ctx, officerID)
+	if err != nil {
+		return nil, err
+	}
+	if officer.ID == resident.ID {
+		return nil, fmt.Errorf("%w: a resident cannot clear themselves", repository.ErrValidation)
+	}
+
+	open, err := s.history.GetOpen(ctx, residentID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+	now := s.now()
+	served := intake.QuarantineServed(now)
+	if open == nil && !served {
+		return nil, fmt.Errorf("%w: resident %s has no open quarantine period", repository.ErrValidation, resident.RegistryNumber)
+	}
+
+	err = repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		if err := s.intakes.Clear(ctx, tx, intake.ID, officer.ID, now); err != nil {
+			return err
+		}
+		if open != nil {
+			return s.history.SetAutoRevert(ctx, tx, open.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	intake.ClearedAt = &now
+	intake.ClearedBy = &officer.ID
+
+	if served {
+		if err := s.revertStatus(ctx, resident, open, models.AuditActorUser); err != nil {
+			return nil, err
+		}
+	}
+	return intake, nil
+}
+
+// GetIntake retrieves a resident's intake with the resident and the
+// screenings.
+func (s *Service) GetIntake(ctx context.Context, residentID string) (*models.Intake, error) {
+	return s.loadIntake(ctx, residentID)
+}
+
+// ListPendingIntakes retrieves the vault's intakes not yet cleared,
+// earliest admitted first, with their residents and screenings.
+func (s *Service) ListPendingIntakes(ctx context.Context) ([]*models.Intake, error) {
+	intakes, err := s.intakes.ListPending(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, i := range intakes {
+		if err := s.fillIntake(ctx, i); err != nil {
+			return nil, err
+		}
+	}
+	return intakes, nil
+}
+
+// loadIntake retrieves a resident's intake with the resident and the
+// screenings.
+func (s *Service) loadIntake(ctx context.Context, residentID string) (*models.Intake, error) {
+	intake, err := s.intakes.GetByResident(ctx, residentID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.fillIntake(ctx, intake); err != nil {
+		return nil, err
+	}
+	return intake, nil
+}
+
+// fillIntake loads an intake's resident and screenings.
+func (s *Service) fillIntake(ctx context.Context, intake *models.Intake) error {
+	var err error
+	if intake.Resident, err = s.residents.GetByID(ctx, intake.ResidentID); err != nil {
+		return err
+	}
+	intake.Screenings, err = s.intakes.ListScreenings(ctx, intake.ID)
+	return err
+}
+
+// awaitingClearance reports whether a resident has an intake not yet
+// cleared by a medical officer.
+func (s *Service) awaitingClearance(ctx context.Context, residentID string) (bool, error) {
+	intake, err := s.intakes.GetByResident(ctx, residentID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return !intake.IsCleared(), nil
+}
+
+// medicalOfficer retrieves a resident who can screen and clear intakes: an
+// ACTIVE resident whose primary vocation is in the medical department.
+func (s *Service) medicalOfficer(ctx context.Context, id string) (*models.Resident, error) {
+	officer, err := s.residents.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("medical officer: %w", err)
+	}
+	if officer.Status != models.ResidentStatusActive {
+		return nil, fmt.Errorf("%w: medical officer %s is %s", repository.ErrValidation, officer.RegistryNumber, officer.Status)
+	}
+	if officer.PrimaryVocationID != nil {
+		vocation, ok, err := s.vocationRef.Find(ctx, func(v *models.Vocation) bool { return v.ID == *officer.PrimaryVocationID })
+		if err != nil {
+			return nil, fmt.Errorf("loading vocations: %w", err)
+		}
+		if ok && vocation.Department == models.DepartmentMedical {
+			return officer, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s is not medical staff", repository.ErrValidation, officer.RegistryNumber)
+}
@@ -0,0 +1,124 @@
+package population
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/simulation"
+)
+
+// ScoreMorale scores the morale of every active resident as of at from
+// their household's ration class, crowding in their quarters, the security
+// incidents in their sector over the last models.MoraleIncidentDays, their
+// workload and the classes and recreation they attended over the last
+// models.MoraleActivityDays, replacing their previous scores, and records
+// the vault happiness index they give.
+func (s *Service) ScoreMorale(ctx context.Context, at time.Time) (_ *models.MoraleSnapshot, err error) {
+	ctx, cmd := s.begin(ctx, CommandScoreMorale, scoreMoraleArgs{at})
+	defer func() { cmd.End(err) }()
+
+	factors, err := s.morale.ListFactors(ctx)
+	if err != nil {
+		return nil, err
+	}
+	incidents, err := s.morale.ListSectorIncidents(ctx, at.AddDate(0, 0, -models.MoraleIncidentDays))
+	if err != nil {
+		return nil, err
+	}
+
+	activity, err := s.morale.ListActivityHours(ctx, at.AddDate(0, 0, -models.MoraleActivityDays))
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make([]*models.ResidentMorale, 0, len(factors))
+	for _, f := range factors {
+		f.Incidents = incidents[f.Sector]
+		f.ActivityHours = activity[f.ResidentID]
+		scores = append(scores, models.ScoreMorale(*f, at))
+	}
+	snapshot := models.SummarizeMorale(scores, at)
+	snapshot.ID = s.idGenerator.NewID()
+
+	err = repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		if err := s.morale.SaveScores(ctx, tx, scores); err != nil {
+			return err
+		}
+		return s.morale.CreateSnapshot(ctx, tx, snapshot)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// MoraleHistory retrieves the happiness index snapshots of up to the last
+// days days, oldest first.
+func (s *Service) MoraleHistory(ctx context.Context, days int) ([]*models.MoraleSnapshot, error) {
+	history, err := s.morale.ListRecentSnapshots(ctx, days)
+	if err != nil {
+		return nil, err
+	}
+	slices.Reverse(history)
+	return history, nil
+}
+
+// GetResidentMorale retrieves a resident's latest morale score.
+func (s *Service) GetResidentMorale(ctx context.Context, residentID string) (*models.ResidentMorale, error) {
+	return s.morale.GetScore(ctx, residentID)
+}
+
+// LowestMorale retrieves up to limit scores of active residents, lowest
+// first.
+func (s *Service) LowestMorale(ctx context.Context, limit int) ([]*models.ResidentMorale, error) {
+	return s.morale.ListLowest(ctx, limit)
+}
+
+// SectorMorale retrieves the morale of the residents housed in each
+// sector, lowest first.
+func (s *Service) SectorMorale(ctx context.Context) ([]*models.SectorMorale, error) {
+	return s.morale.ListSectors(ctx)
+}
+
+// CheckMorale scores morale as of at and raises a warning if the happiness
+// index is LOW, critical if it is CRITICAL; a steadier vault raises
+// nothing.
+func (s *Service) CheckMorale(ctx context.Context, at time.Time) ([]simulation.Event, error) {
+	snapshot, err := s.ScoreMorale(ctx, at)
+	if err != nil {
+		return nil, err
+	}
+	if snapshot.Population == 0 {
+		return nil, nil
+	}
+
+	summary := fmt.Sprintf("happiness index %d, %d of %d residents at low morale",
+		snapshot.Index, snapshot.Low, snapshot.Population)
+	event := simulation.Event{Time: at, Source: "morale"}
+	switch snapshot.Level() {
+	case models.MoraleCritical:
+		event.Level = simulation.EventCritical
+		event.Message = "Vault morale critical: " + summary
+	case models.MoraleLow:
+		event.Level = simulation.EventWarning
+		event.Message = "Vault morale low: " + summary
+	default:
+		return nil, nil
+	}
+	return []simulation.Event{event}, nil
+}
+
+// MoraleJob is the scheduled job that scores morale at every vault
+// midnight and alerts when the happiness index is low.
+func (s *Service) MoraleJob() simulation.Job {
+	return simulation.Job{
+		Name:     "Morale",
+		Interval: simulation.Daily,
+		Check:    s.CheckMorale,
+	}
+}
@@ -0,0 +1,54 @@
+package population
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// SetPIIClearance sets the clearance an operator needs to see residents'
+// blood types, notes and status reasons, and to change them. Below it the
+// service returns them as models.Redacted. 0, the default, shows them to
+// everyone.
+func (s *Service) SetPIIClearance(clearance int) {
+	s.piiClearance = clearance
+}
+
+// redactResidents hides the personal details of residents the operator of
+// ctx lacks the clearance to see. Nil residents are skipped.
+func (s *Service) redactResidents(ctx context.Context, residents ...*models.Resident) {
+	if util.Cleared(ctx, s.piiClearance) {
+		return
+	}
+	for _, r := range residents {
+		if r != nil {
+			r.Redact()
+		}
+	}
+}
+
+// updatePII returns the value a personal detail, current until now, takes
+// in an update: models.Redacted leaves it unchanged, and only an operator
+// holding the PII clearance may change it.
+func (s *Service) updatePII(ctx context.Context, field, current, value string) (string, error) {
+	if value == models.Redacted || value == current {
+		return current, nil
+	}
+	if !util.Cleared(ctx, s.piiClearance) {
+		return "", fmt.Errorf("%w: changing a resident's %s needs clearance %d", repository.ErrValidation, field, s.piiClearance)
+	}
+	return value, nil
+}
+
+// redactTree hides the personal details of the residents of a family tree,
+// as redactResidents does.
+func (s *Service) redactTree(ctx context.Context, tree *FamilyTree) {
+	for _, nodes := range []map[string]*FamilyTreeNode{tree.Ancestors, tree.Descendants} {
+		for _, node := range nodes {
+			s.redactResidents(ctx, node.Resident)
+		}
+	}
+}
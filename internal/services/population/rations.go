@@ -0,0 +1,169 @@
+package population
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+)
+
+// ReviewHouseholdRationClass recomputes the recommended ration class for a
+// household and queues a suggested change for approval when it differs from
+// the current class. Returns nil when no change is needed.
+//
+// A household has at most one pending review: an existing pending review with
+// the same recommendation is returned as-is, and one with a different
+// recommendation is superseded by the new review.
+func (s *Service) ReviewHouseholdRationClass(ctx context.Context, householdID string, trigger models.RationReviewTrigger) (_ *models.RationClassReview, err error) {
+	ctx, cmd := s.begin(ctx, CommandReviewRationClass, rationReviewArgs{householdID, trigger})
+	defer func() { cmd.End(err) }()
+
+	household, err := s.households.GetByID(ctx, householdID)
+	if err != nil {
+		return nil, err
+	}
+	if !household.IsActive() {
+		return nil, nil
+	}
+
+	members, err := s.residents.GetByHousehold(ctx, householdID)
+	if err != nil {
+		return nil, fmt.Errorf("getting household members: %w", err)
+	}
+
+	medicalNeeds, err := s.residents.GetMedicalNeeds(ctx, householdID)
+	if err != nil {
+		return nil, fmt.Errorf("getting medical needs: %w", err)
+	}
+
+	recommended, reason := models.RecommendRationClass(household.RationClass, members, medicalNeeds, s.now())
+
+	pending, err := s.reviews.GetPendingByHousehold(ctx, householdID)
+	if err != nil {
+		return nil, err
+	}
+	if pending != nil && pending.RecommendedClass == recommended {
+		return pending, nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if pending != nil {
+		pending.Status = models.RationReviewStatusSuperseded
+		if err := s.reviews.UpdateStatus(ctx, tx, pending); err != nil {
+			return nil, fmt.Errorf("superseding review: %w", err)
+		}
+	}
+
+	var review *models.RationClassReview
+	if recommended != household.RationClass {
+		review = &models.RationClassReview{
+			ID:               s.idGenerator.NewID(),
+			HouseholdID:      householdID,
+			CurrentClass:     household.RationClass,
+			RecommendedClass: recommended,
+			Reason:           reason,
+			Trigger:          trigger,
+			Status:           models.RationReviewStatusPending,
+		}
+		if err := s.reviews.Create(ctx, tx, review); err != nil {
+			return nil, fmt.Errorf("creating review: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("committing transaction: %w", err)
+	}
+
+	return review, nil
+}
+
+// ListPendingRationReviews retrieves all ration class reviews awaiting approval.
+func (s *Service) ListPendingRationReviews(ctx context.Context) ([]*models.RationClassReview, error) {
+	return s.reviews.ListPending(ctx)
+}
+
+// ApproveRationReview applies a pending review's recommended class to its household.
+func (s *Service) ApproveRationReview(ctx context.Context, reviewID string, decidedBy *string) (err error) {
+	ctx, cmd := s.begin(ctx, CommandApproveRationReview, decideReviewArgs{reviewID, decidedBy})
+	defer func() { cmd.End(err) }()
+
+	review, err := s.pendingReview(ctx, reviewID)
+	if err != nil {
+		return err
+	}
+
+	household, err := s.households.GetByID(ctx, review.HouseholdID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	household.RationClass = review.RecommendedClass
+	if err := s.households.Update(ctx, tx, household); err != nil {
+		return fmt.Errorf("updating household: %w", err)
+	}
+
+	s.decideReview(review, models.RationReviewStatusApproved, decidedBy)
+	if err := s.reviews.UpdateStatus(ctx, tx, review); err != nil {
+		return fmt.Errorf("recording approval: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+// RejectRationReview declines a pending review, leaving the household unchanged.
+func (s *Service) RejectRationReview(ctx context.Context, reviewID string, decidedBy *string) (err error) {
+	ctx, cmd := s.begin(ctx, CommandRejectRationReview, decideReviewArgs{reviewID, decidedBy})
+	defer func() { cmd.End(err) }()
+
+	review, err := s.pendingReview(ctx, reviewID)
+	if err != nil {
+		return err
+	}
+
+	s.decideReview(review, models.RationReviewStatusRejected, decidedBy)
+	return s.reviews.UpdateStatus(ctx, nil, review)
+}
+
+func (s *Service) pendingReview(ctx context.Context, reviewID string) (*models.RationClassReview, error) {
+	review, err := s.reviews.GetByID(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if !review.IsPending() {
+		return nil, fmt.Errorf("%w: review is already %s", repository.ErrValidation, review.Status)
+	}
+	return review, nil
+}
+
+func (s *Service) decideReview(review *models.RationClassReview, status models.RationReviewStatus, decidedBy *string) {
+	decidedAt := s.now()
+	review.Status = status
+	review.DecidedBy = decidedBy
+	review.DecidedAt = &decidedAt
+}
+
+// queueRationReview runs a ration class review after a composition change.
+// Failures are ignored: the change itself has already been recorded, and the
+// household can still be reviewed manually.
+func (s *Service) queueRationReview(ctx context.Context, householdID string, trigger models.RationReviewTrigger) {
+	if householdID == "" {
+		return
+	}
+	_, _ = s.ReviewHouseholdRationClass(ctx, householdID, trigger)
+}
@@ -0,0 +1,194 @@
+package population
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+)
+
+// RelationshipInput contains data for recording a relationship.
+type RelationshipInput struct {
+	Type       models.RelationshipType
+	ResidentID string    // A partner, the guardian, or the resident designating next of kin
+	RelatedID  string    // The other partner, the ward, or the next of kin
+	StartDate  time.Time // Defaults to today
+	Notes      string
+}
+
+// ResidentRelationship is a relationship as seen from one resident.
+type ResidentRelationship struct {
+	*models.Relationship
+	Role  string           // What Other is to the resident, e.g. "Spouse"
+	Other *models.Resident // The other party
+}
+
+// RecordRelationship records a marriage, partnership, guardianship or
+// next-of-kin designation. Unions need two living adults who are not in one
+// already; a guardian must be a living adult and the ward a living minor.
+// Designating a new next of kin ends the previous designation.
+func (s *Service) RecordRelationship(ctx context.Context, input RelationshipInput) (_ *models.Relationship, err error) {
+	ctx, cmd := s.begin(ctx, CommandRecordRelationship, input)
+	defer func() { cmd.End(err) }()
+
+	rel, replaced, err := s.newRelationship(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	err = repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		if replaced != nil {
+			if err := s.relationships.End(ctx, tx, replaced.ID, rel.StartDate, "Next of kin replaced"); err != nil {
+				return err
+			}
+		}
+		return s.relationships.Create(ctx, tx, rel)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rel, nil
+}
+
+// EndRelationship ends a current relationship as of today, e.g. a divorce
+// or a ward coming of age.
+func (s *Service) EndRelationship(ctx context.Context, id, reason string) (err error) {
+	ctx, cmd := s.begin(ctx, CommandEndRelationship, endRelationshipArgs{id, reason})
+	defer func() { cmd.End(err) }()
+
+	return s.relationships.End(ctx, nil, id, s.today(), reason)
+}
+
+// GetRelationships retrieves every relationship of a resident with the other
+// party, current ones first.
+func (s *Service) GetRelationships(ctx context.Context, residentID string) ([]ResidentRelationship, error) {
+	rels, err := s.relationships.ListByResident(ctx, residentID)
+	if err != nil {
+		return nil, err
+	}
+
+	views := make([]ResidentRelationship, 0, len(rels))
+	for _, rel := range rels {
+		other, err := s.residents.GetByID(ctx, rel.OtherParty(residentID))
+		if err != nil {
+			return nil, fmt.Errorf("getting related resident: %w", err)
+		}
+		s.redactResidents(ctx, other)
+		views = append(views, ResidentRelationship{
+			Relationship: rel,
+			Role:         rel.RoleOfOther(residentID),
+			Other:        other,
+		})
+	}
+	return views, nil
+}
+
+// newRelationship validates input against both residents and their current
+// relationships. For a next-of-kin designation it also returns the current
+// designation the new one replaces, if any.
+func (s *Service) newRelationship(ctx context.Context, input RelationshipInput) (*models.Relationship, *models.Relationship, error) {
+	rel := &models.Relationship{
+		ID:         s.idGenerator.NewID(),
+		Type:       input.Type,
+		ResidentID: input.ResidentID,
+		RelatedID:  input.RelatedID,
+		StartDate:  input.StartDate,
+		Notes:      input.Notes,
+	}
+	if rel.StartDate.IsZero() {
+		rel.StartDate = s.today()
+	}
+	if err := rel.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", repository.ErrValidation, err)
+	}
+
+	resident, err := s.residents.GetByID(ctx, rel.ResidentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	related, err := s.residents.GetByID(ctx, rel.RelatedID)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, r := range []*models.Resident{resident, related} {
+		if !r.IsAlive() {
+			return nil, nil, fmt.Errorf("%w: resident %s is deceased", repository.ErrValidation, r.RegistryNumber)
+		}
+	}
+
+	now := s.now()
+	switch {
+	case rel.Type.IsUnion():
+		for _, r := range []*models.Resident{resident, related} {
+			if !r.IsAdult(now) {
+				return nil, nil, fmt.Errorf("%w: resident %s is not an adult", repository.ErrValidation, r.RegistryNumber)
+			}
+			if union, err := s.currentUnion(ctx, r.ID); err != nil {
+				return nil, nil, err
+			} else if union != nil {
+				return nil, nil, fmt.Errorf("%w: resident %s is already in a %s",
+					repository.ErrValidation, r.RegistryNumber, union.Type)
+			}
+		}
+	case rel.Type == models.RelationshipGuardianship:
+		if !resident.IsAdult(now) {
+			return nil, nil, fmt.Errorf("%w: guardian %s is not an adult", repository.ErrValidation, resident.RegistryNumber)
+		}
+		if related.IsAdult(now) {
+			return nil, nil, fmt.Errorf("%w: ward %s is an adult", repository.ErrValidation, related.RegistryNumber)
+		}
+	case rel.Type == models.RelationshipNextOfKin:
+		current, err := s.relationships.ListByResident(ctx, rel.ResidentID)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, c := range current {
+			if c.IsActive() && c.Type == models.RelationshipNextOfKin && c.ResidentID == rel.ResidentID {
+				return rel, c, nil
+			}
+		}
+	}
+
+	return rel, nil, nil
+}
+
+// currentUnion returns a resident's current marriage or partnership, or nil.
+func (s *Service) currentUnion(ctx context.Context, residentID string) (*models.Relationship, error) {
+	rels, err := s.relationships.ListByResident(ctx, residentID)
+	if err != nil {
+		return nil, err
+	}
+	for _, rel := range rels {
+		if rel.IsActive() && rel.Type.IsUnion() {
+			return rel, nil
+		}
+	}
+	return nil, nil
+}
+
+// newMarriage validates a marriage of the two residents recorded as a
+// household forms. It returns nil when no spouses are given.
+func (s *Service) newMarriage(ctx context.Context, spouseIDs []string) (*models.Relationship, error) {
+	switch len(spouseIDs) {
+	case 0:
+		return nil, nil
+	case 2:
+		rel, _, err := s.newRelationship(ctx, RelationshipInput{
+			Type:       models.RelationshipMarriage,
+			ResidentID: spouseIDs[0],
+			RelatedID:  spouseIDs[1],
+		})
+		return rel, err
+	default:
+		return nil, fmt.Errorf("%w: a marriage needs two spouses, got %d", repository.ErrValidation, len(spouseIDs))
+	}
+}
+
+// today returns the current date at midnight UTC.
+func (s *Service) today() time.Time {
+	now := s.now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
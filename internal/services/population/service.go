@@ -4,9 +4,14 @@ package population
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/vtuos/vtuos/internal/dualauth"
+	"github.com/vtuos/vtuos/internal/events"
+	"github.com/vtuos/vtuos/internal/journal"
 	"github.com/vtuos/vtuos/internal/models"
 	"github.com/vtuos/vtuos/internal/repository"
 	"github.com/vtuos/vtuos/internal/util"
@@ -14,26 +19,112 @@ import (
 
 // Service provides population management operations.
 type Service struct {
-	db          *sql.DB
-	vaultNumber int
-	residents   *repository.ResidentRepository
-	households  *repository.HouseholdRepository
-	idGenerator *util.IDGenerator
-	regNumGen   *util.RegistryNumberGenerator
+	db            *sql.DB
+	vaultNumber   int
+	residents     *repository.ResidentRepository
+	households    *repository.HouseholdRepository
+	reviews       *repository.RationReviewRepository
+	vocations     *repository.VocationRepository
+	vocationRef   *util.RefCache[*models.Vocation]
+	audit         *repository.AuditRepository
+	history       *repository.StatusHistoryRepository
+	relationships *repository.RelationshipRepository
+	care          *repository.CareAssignmentRepository
+	aptitude      *repository.AptitudeRepository
+	training      *repository.TrainingRepository
+	genetics      *repository.GeneticsRepository
+	intakes       *repository.IntakeRepository
+	morale        *repository.MoraleRepository
+	activities    *repository.ActivityRepository
+	tags          *repository.TagRepository
+	presets       *repository.CensusPresetRepository
+	medical       *repository.MedicalRepository
+	radiation     *repository.RadiationRepository
+	authorizer    *dualauth.Authorizer
+	idGenerator   *util.IDGenerator
+	regNumGen     *util.RegistryNumberGenerator
+	journal       *journal.Journal
+	events        *events.Bus
+	piiClearance  int // Clearance needed to see personal details, 0 for none
+	now           func() time.Time
 }
 
 // NewService creates a new population service.
 func NewService(db *sql.DB, vaultNumber int) *Service {
+	vocations := repository.NewVocationRepository(db)
 	return &Service{
 		db:          db,
 		vaultNumber: vaultNumber,
-		residents:   repository.NewResidentRepository(db),
-		households:  repository.NewHouseholdRepository(db),
-		idGenerator: util.NewIDGenerator(),
-		regNumGen:   util.NewRegistryNumberGenerator(vaultNumber),
+		residents:   repository.NewResidentRepository(db).ForVault(vaultNumber),
+		households:  repository.NewHouseholdRepository(db).ForVault(vaultNumber),
+		reviews:     repository.NewRationReviewRepository(db),
+		vocations:   vocations,
+		vocationRef: util.NewRefCache(func(ctx context.Context) ([]*models.Vocation, error) {
+			return vocations.List(ctx, false)
+		}),
+		audit:         repository.NewAuditRepository(db),
+		history:       repository.NewStatusHistoryRepository(db),
+		relationships: repository.NewRelationshipRepository(db),
+		care:          repository.NewCareAssignmentRepository(db),
+		aptitude:      repository.NewAptitudeRepository(db),
+		training:      repository.NewTrainingRepository(db).ForVault(vaultNumber),
+		genetics:      repository.NewGeneticsRepository(db).ForVault(vaultNumber),
+		intakes:       repository.NewIntakeRepository(db).ForVault(vaultNumber),
+		morale:        repository.NewMoraleRepository(db).ForVault(vaultNumber),
+		activities:    repository.NewActivityRepository(db).ForVault(vaultNumber),
+		tags:          repository.NewTagRepository(db).ForVault(vaultNumber),
+		presets:       repository.NewCensusPresetRepository(db).ForVault(vaultNumber),
+		medical:       repository.NewMedicalRepository(db),
+		radiation:     repository.NewRadiationRepository(db).ForVault(vaultNumber),
+		authorizer:    dualauth.New(db).ForVault(vaultNumber),
+		idGenerator:   util.NewIDGenerator(),
+		regNumGen:     util.NewRegistryNumberGenerator(vaultNumber),
+		now:           func() time.Time { return time.Now().UTC() },
 	}
 }
 
+// SetClock makes the service use vault time for age-dependent rules.
+func (s *Service) SetClock(clock *util.VaultClock) {
+	s.now = clock.Now
+}
+
+// SetRegistryFormat sets the format of the registry numbers issued to new
+// residents, util.DefaultRegistryFormat until set.
+func (s *Service) SetRegistryFormat(format *util.RegistryFormat) {
+	s.regNumGen.SetFormat(format)
+}
+
+// reserveRegistryNumbers reserves n registry numbers issued in the current
+// vault year. Call it first in tx.
+func (s *Service) reserveRegistryNumbers(ctx context.Context, tx *sql.Tx, n int) ([]string, error) {
+	numbers, err := s.residents.ReserveRegistryNumbers(ctx, tx, s.regNumGen, s.now().Year(), n)
+	if err != nil {
+		return nil, fmt.Errorf("generating registry number: %w", err)
+	}
+	return numbers, nil
+}
+
+// InvalidateCache drops cached reference data. Call it after writing
+// vocations other than through this service, e.g. after a restore.
+func (s *Service) InvalidateCache() {
+	s.vocationRef.Invalidate()
+}
+
+// activeVocations returns the active vocations from the vocation cache.
+func (s *Service) activeVocations(ctx context.Context) ([]*models.Vocation, error) {
+	all, err := s.vocationRef.All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var active []*models.Vocation
+	for _, v := range all {
+		if v.IsActive {
+			active = append(active, v)
+		}
+	}
+	return active, nil
+}
+
 // CreateResidentInput contains data for creating a new resident.
 type CreateResidentInput struct {
 	Surname             string
@@ -51,13 +142,12 @@ type CreateResidentInput struct {
 }
 
 // CreateResident creates a new resident in the vault.
-func (s *Service) CreateResident(ctx context.Context, input CreateResidentInput) (*models.Resident, error) {
+func (s *Service) CreateResident(ctx context.Context, input CreateResidentInput) (_ *models.Resident, err error) {
+	ctx, cmd := s.begin(ctx, CommandCreateResident, input)
+	defer func() { cmd.End(err) }()
+
 	// Generate IDs
 	id := s.idGenerator.NewID()
-	regNum, err := s.residents.GetNextRegistryNumber(ctx, s.vaultNumber)
-	if err != nil {
-		return nil, fmt.Errorf("generating registry number: %w", err)
-	}
 
 	// Set defaults
 	clearance := input.ClearanceLevel
@@ -67,7 +157,6 @@ func (s *Service) CreateResident(ctx context.Context, input CreateResidentInput)
 
 	resident := &models.Resident{
 		ID:                  id,
-		RegistryNumber:      regNum,
 		Surname:             input.Surname,
 		GivenNames:          input.GivenNames,
 		DateOfBirth:         input.DateOfBirth,
@@ -83,21 +172,43 @@ func (s *Service) CreateResident(ctx context.Context, input CreateResidentInput)
 		Notes:               input.Notes,
 	}
 
-	if err := s.residents.Create(ctx, nil, resident); err != nil {
-		return nil, fmt.Errorf("creating resident: %w", err)
+	err = repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		numbers, err := s.reserveRegistryNumbers(ctx, tx, 1)
+		if err != nil {
+			return err
+		}
+		resident.RegistryNumber = numbers[0]
+		if err := s.residents.Create(ctx, tx, resident); err != nil {
+			return fmt.Errorf("creating resident: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	s.residentCreated(ctx, resident)
 
 	return resident, nil
 }
 
 // GetResident retrieves a resident by ID.
 func (s *Service) GetResident(ctx context.Context, id string) (*models.Resident, error) {
-	return s.residents.GetByID(ctx, id)
+	resident, err := s.residents.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.redactResidents(ctx, resident)
+	return resident, nil
 }
 
 // GetResidentByRegistryNumber retrieves a resident by registry number.
 func (s *Service) GetResidentByRegistryNumber(ctx context.Context, regNum string) (*models.Resident, error) {
-	return s.residents.GetByRegistryNumber(ctx, regNum)
+	resident, err := s.residents.GetByRegistryNumber(ctx, nil, regNum)
+	if err != nil {
+		return nil, err
+	}
+	s.redactResidents(ctx, resident)
+	return resident, nil
 }
 
 // UpdateResidentInput contains data for updating a resident.
@@ -115,11 +226,15 @@ type UpdateResidentInput struct {
 }
 
 // UpdateResident updates an existing resident.
-func (s *Service) UpdateResident(ctx context.Context, id string, input UpdateResidentInput) (*models.Resident, error) {
+func (s *Service) UpdateResident(ctx context.Context, id string, input UpdateResidentInput) (_ *models.Resident, err error) {
+	ctx, cmd := s.begin(ctx, CommandUpdateResident, updateResidentArgs{id, input})
+	defer func() { cmd.End(err) }()
+
 	resident, err := s.residents.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	previousStatus := resident.Status
 
 	// Apply updates
 	if input.Surname != nil {
@@ -129,7 +244,11 @@ func (s *Service) UpdateResident(ctx context.Context, id string, input UpdateRes
 		resident.GivenNames = *input.GivenNames
 	}
 	if input.BloodType != nil {
-		resident.BloodType = *input.BloodType
+		bloodType, err := s.updatePII(ctx, "blood type", string(resident.BloodType), string(*input.BloodType))
+		if err != nil {
+			return nil, err
+		}
+		resident.BloodType = models.BloodType(bloodType)
 	}
 	if input.Status != nil {
 		resident.Status = *input.Status
@@ -150,19 +269,130 @@ func (s *Service) UpdateResident(ctx context.Context, id string, input UpdateRes
 		resident.ClearanceLevel = *input.ClearanceLevel
 	}
 	if input.Notes != nil {
-		resident.Notes = *input.Notes
+		if resident.Notes, err = s.updatePII(ctx, "notes", resident.Notes, *input.Notes); err != nil {
+			return nil, err
+		}
 	}
 
-	if err := s.residents.Update(ctx, nil, resident); err != nil {
+	// Exile may need a second operator's confirmation.
+	var auth *dualauth.Authorization
+	if resident.Status == models.ResidentStatusExiled && previousStatus != models.ResidentStatusExiled {
+		if auth, err = s.authorizer.Authorize(ctx, nil, models.DualAuthExile, 0); err != nil {
+			return nil, err
+		}
+	}
+
+	// Leaving a scheduled status by hand ends its period.
+	var open *models.StatusTransition
+	if resident.Status != previousStatus && previousStatus.IsTemporary() {
+		open, err = s.history.GetOpen(ctx, id)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return nil, err
+		}
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.residents.Update(ctx, tx, resident); err != nil {
 		return nil, fmt.Errorf("updating resident: %w", err)
 	}
+	if open != nil {
+		if err := s.history.End(ctx, tx, open.ID, s.now()); err != nil {
+			return nil, err
+		}
+	}
 
+	if resident.Status != previousStatus {
+		if err := s.auditStatusChange(ctx, tx, resident.ID, previousStatus, resident.Status, models.AuditActorUser); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.authorizer.Record(ctx, tx, auth, models.AuditEntityResident, resident.ID, s.now()); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("committing transaction: %w", err)
+	}
+
+	s.redactResidents(ctx, resident)
 	return resident, nil
 }
 
-// ListResidents retrieves residents with filtering and pagination.
+// auditStatusChange records a resident status change in the audit log.
+func (s *Service) auditStatusChange(ctx context.Context, tx *sql.Tx, residentID string, from, to models.ResidentStatus, actor models.AuditActorType) error {
+	entry := &models.AuditEntry{
+		ID:         s.idGenerator.NewID(),
+		Timestamp:  s.now(),
+		ActorType:  actor,
+		Action:     models.AuditActionStatusChange,
+		EntityType: models.AuditEntityResident,
+		EntityID:   residentID,
+	}
+	if err := entry.SetValues(
+		models.StatusValues{Status: string(from)},
+		models.StatusValues{Status: string(to)},
+	); err != nil {
+		return err
+	}
+	if err := s.audit.Create(ctx, tx, entry); err != nil {
+		return fmt.Errorf("auditing status change: %w", err)
+	}
+	return nil
+}
+
+// DeleteResident removes a resident recorded in error. Residents recorded as a
+// biological parent cannot be deleted; record a status change instead.
+func (s *Service) DeleteResident(ctx context.Context, id string) (err error) {
+	ctx, cmd := s.begin(ctx, CommandDeleteResident, idArgs{id})
+	defer func() { cmd.End(err) }()
+
+	resident, err := s.residents.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.residents.Delete(ctx, nil, id); err != nil {
+		if errors.Is(err, repository.ErrResidentIsParent) {
+			return fmt.Errorf("resident %s cannot be deleted, they have recorded children: %w",
+				resident.RegistryNumber, repository.ErrResidentIsParent)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// ListResidents retrieves residents with filtering and pagination. Ages
+// are reckoned at the vault date unless the filter gives another, and a
+// vocation filter must name a known vocation.
 func (s *Service) ListResidents(ctx context.Context, filter models.ResidentFilter, page models.Pagination) (*models.ResidentList, error) {
-	return s.residents.List(ctx, filter, page)
+	if err := filter.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", repository.ErrValidation, err)
+	}
+	if filter.AsOf.IsZero() {
+		filter.AsOf = s.now()
+	}
+	filter.Sector = strings.ToUpper(strings.TrimSpace(filter.Sector))
+	if filter.VocationCode = strings.ToUpper(strings.TrimSpace(filter.VocationCode)); filter.VocationCode != "" {
+		_, ok, err := s.vocationRef.Find(ctx, func(v *models.Vocation) bool { return v.Code == filter.VocationCode })
+		if err != nil {
+			return nil, fmt.Errorf("loading vocations: %w", err)
+		}
+		if !ok {
+			return nil, fmt.Errorf("vocation %s: vocation %w", filter.VocationCode, repository.ErrNotFound)
+		}
+	}
+	list, err := s.residents.List(ctx, filter, page)
+	if err != nil {
+		return nil, err
+	}
+	s.redactResidents(ctx, list.Residents...)
+	return list, nil
 }
 
 // BirthRegistration contains data for registering a birth.
@@ -179,14 +409,17 @@ type BirthRegistration struct {
 }
 
 // RegisterBirth registers a new vault-born resident.
-func (s *Service) RegisterBirth(ctx context.Context, input BirthRegistration) (*models.Resident, error) {
+func (s *Service) RegisterBirth(ctx context.Context, input BirthRegistration) (_ *models.Resident, err error) {
+	ctx, cmd := s.begin(ctx, CommandRegisterBirth, input)
+	defer func() { cmd.End(err) }()
+
 	// Validate parents exist and are alive
 	parent1, err := s.residents.GetByID(ctx, input.Parent1ID)
 	if err != nil {
 		return nil, fmt.Errorf("parent 1 not found: %w", err)
 	}
 	if !parent1.IsAlive() {
-		return nil, fmt.Errorf("parent 1 is deceased")
+		return nil, fmt.Errorf("%w: parent 1 is deceased", repository.ErrValidation)
 	}
 
 	parent2, err := s.residents.GetByID(ctx, input.Parent2ID)
@@ -194,7 +427,7 @@ func (s *Service) RegisterBirth(ctx context.Context, input BirthRegistration) (*
 		return nil, fmt.Errorf("parent 2 not found: %w", err)
 	}
 	if !parent2.IsAlive() {
-		return nil, fmt.Errorf("parent 2 is deceased")
+		return nil, fmt.Errorf("%w: parent 2 is deceased", repository.ErrValidation)
 	}
 
 	// Calculate and warn on COI
@@ -205,23 +438,11 @@ func (s *Service) RegisterBirth(ctx context.Context, input BirthRegistration) (*
 		_ = fmt.Sprintf("WARNING: High coefficient of inbreeding: %.4f", coi)
 	}
 
-	// Start transaction
-	tx, err := s.db.BeginTx(ctx, nil)
-	if err != nil {
-		return nil, fmt.Errorf("starting transaction: %w", err)
-	}
-	defer tx.Rollback()
-
 	// Generate IDs
 	id := s.idGenerator.NewID()
-	regNum, err := s.residents.GetNextRegistryNumber(ctx, s.vaultNumber)
-	if err != nil {
-		return nil, fmt.Errorf("generating registry number: %w", err)
-	}
 
 	resident := &models.Resident{
 		ID:                  id,
-		RegistryNumber:      regNum,
 		Surname:             input.Surname,
 		GivenNames:          input.GivenNames,
 		DateOfBirth:         input.DateOfBirth,
@@ -237,13 +458,23 @@ func (s *Service) RegisterBirth(ctx context.Context, input BirthRegistration) (*
 		Notes:               input.Notes,
 	}
 
-	if err := s.residents.Create(ctx, tx, resident); err != nil {
-		return nil, fmt.Errorf("creating resident: %w", err)
+	err = repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		numbers, err := s.reserveRegistryNumbers(ctx, tx, 1)
+		if err != nil {
+			return err
+		}
+		resident.RegistryNumber = numbers[0]
+		if err := s.residents.Create(ctx, tx, resident); err != nil {
+			return fmt.Errorf("creating resident: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	s.residentCreated(ctx, resident)
 
-	if err := tx.Commit(); err != nil {
-		return nil, fmt.Errorf("committing transaction: %w", err)
-	}
+	s.queueRationReview(ctx, input.HouseholdID, models.RationReviewTriggerBirth)
 
 	return resident, nil
 }
@@ -254,15 +485,41 @@ type DeathRegistration struct {
 	Cause       string // Stored in notes
 }
 
-// RegisterDeath records the death of a resident.
-func (s *Service) RegisterDeath(ctx context.Context, residentID string, input DeathRegistration) error {
+// causeOfDeathPrefix starts the line of a resident's notes giving the cause
+// of their death.
+const causeOfDeathPrefix = "Cause of death: "
+
+// withoutCauseOfDeath returns notes without the cause of death registered
+// last.
+func withoutCauseOfDeath(notes string) string {
+	i := strings.LastIndex(notes, causeOfDeathPrefix)
+	if i < 0 || (i > 0 && notes[i-1] != '\n') || strings.Contains(notes[i:], "\n") {
+		return notes
+	}
+	return strings.TrimSuffix(notes[:i], "\n")
+}
+
+// DeathRevocation contains the record of a resident as it was before a death
+// registered in error.
+type DeathRevocation struct {
+	Status models.ResidentStatus // e.g. ACTIVE
+	Notes  string                // Without the cause of death
+}
+
+// RegisterDeath records the death of a resident and ends every relationship
+// they are a party to. Minor children and wards left with no living parent
+// or guardian are queued for a care assignment.
+func (s *Service) RegisterDeath(ctx context.Context, residentID string, input DeathRegistration) (err error) {
+	ctx, cmd := s.begin(ctx, CommandRegisterDeath, registerDeathArgs{residentID, input})
+	defer func() { cmd.End(err) }()
+
 	resident, err := s.residents.GetByID(ctx, residentID)
 	if err != nil {
 		return err
 	}
 
 	if !resident.IsAlive() {
-		return fmt.Errorf("resident is already deceased")
+		return fmt.Errorf("%w: resident is already deceased", repository.ErrValidation)
 	}
 
 	resident.Status = models.ResidentStatusDeceased
@@ -271,10 +528,102 @@ func (s *Service) RegisterDeath(ctx context.Context, residentID string, input De
 		if resident.Notes != "" {
 			resident.Notes += "\n"
 		}
-		resident.Notes += fmt.Sprintf("Cause of death: %s", input.Cause)
+		resident.Notes += causeOfDeathPrefix + input.Cause
+	}
+
+	// Found before the death ends the guardianships that make them wards
+	dependents, err := s.dependentsOf(ctx, residentID)
+	if err != nil {
+		return fmt.Errorf("finding dependents: %w", err)
+	}
+
+	reason := "Death of " + resident.RegistryNumber
+	err = repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		if err := s.residents.Update(ctx, tx, resident); err != nil {
+			return err
+		}
+		_, err := s.relationships.EndAllForResident(ctx, tx, residentID, input.DateOfDeath, reason)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if resident.HouseholdID != nil {
+		s.queueRationReview(ctx, *resident.HouseholdID, models.RationReviewTriggerDeath)
+	}
+	s.queueCareAssignments(ctx, dependents, reason)
+
+	return nil
+}
+
+// RevokeDeath reverses a death registered in error. The resident returns to
+// the status and notes in input, the relationships the death ended resume,
+// and the care assignments it opened are cancelled while still pending.
+func (s *Service) RevokeDeath(ctx context.Context, residentID string, input DeathRevocation) (err error) {
+	ctx, cmd := s.begin(ctx, CommandRevokeDeath, revokeDeathArgs{residentID, input})
+	defer func() { cmd.End(err) }()
+
+	if !input.Status.Valid() || !input.Status.IsAlive() {
+		return fmt.Errorf("%w: cannot restore a resident to %s", repository.ErrValidation, input.Status)
+	}
+
+	resident, err := s.residents.GetByID(ctx, residentID)
+	if err != nil {
+		return err
+	}
+	if resident.IsAlive() || resident.DateOfDeath == nil {
+		return fmt.Errorf("%w: resident %s is not deceased", repository.ErrValidation, resident.RegistryNumber)
+	}
+
+	reason := "Death of " + resident.RegistryNumber
+	pending, err := s.care.ListPending(ctx)
+	if err != nil {
+		return fmt.Errorf("listing care assignments: %w", err)
+	}
+
+	// Notes redacted for the operator lose the cause of death the death
+	// added to them.
+	notes := withoutCauseOfDeath(resident.Notes)
+	if input.Notes != models.Redacted {
+		if notes, err = s.updatePII(ctx, "notes", resident.Notes, input.Notes); err != nil {
+			return err
+		}
+	}
+
+	diedOn := *resident.DateOfDeath
+	resident.Status = input.Status
+	resident.DateOfDeath = nil
+	resident.Notes = notes
+
+	err = repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		if err := s.residents.Update(ctx, tx, resident); err != nil {
+			return err
+		}
+		if _, err := s.relationships.ResumeEnded(ctx, tx, residentID, diedOn, reason); err != nil {
+			return err
+		}
+		resolvedAt := s.now()
+		for _, care := range pending {
+			if care.Reason != reason {
+				continue
+			}
+			care.Status = models.CareAssignmentStatusCancelled
+			care.ResolvedAt = &resolvedAt
+			if err := s.care.Resolve(ctx, tx, care); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	return s.residents.Update(ctx, nil, resident)
+	if resident.HouseholdID != nil {
+		s.queueRationReview(ctx, *resident.HouseholdID, models.RationReviewTriggerManual)
+	}
+	return nil
 }
 
 // CreateHouseholdInput contains data for creating a household.
@@ -284,10 +633,20 @@ type CreateHouseholdInput struct {
 	QuartersID        *string
 	RationClass       models.RationClass
 	FormedDate        time.Time
+	SpouseIDs         []string // Two residents to record as married as the household forms
 }
 
-// CreateHousehold creates a new household.
-func (s *Service) CreateHousehold(ctx context.Context, input CreateHouseholdInput) (*models.Household, error) {
+// CreateHousehold creates a new household, recording the marriage of
+// input.SpouseIDs with it when given.
+func (s *Service) CreateHousehold(ctx context.Context, input CreateHouseholdInput) (_ *models.Household, err error) {
+	ctx, cmd := s.begin(ctx, CommandCreateHousehold, input)
+	defer func() { cmd.End(err) }()
+
+	marriage, err := s.newMarriage(ctx, input.SpouseIDs)
+	if err != nil {
+		return nil, err
+	}
+
 	id := s.idGenerator.NewID()
 	designation, err := s.households.GetNextDesignation(ctx)
 	if err != nil {
@@ -305,8 +664,17 @@ func (s *Service) CreateHousehold(ctx context.Context, input CreateHouseholdInpu
 		FormedDate:        input.FormedDate,
 	}
 
-	if err := s.households.Create(ctx, nil, household); err != nil {
-		return nil, fmt.Errorf("creating household: %w", err)
+	err = repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		if err := s.households.Create(ctx, tx, household); err != nil {
+			return fmt.Errorf("creating household: %w", err)
+		}
+		if marriage != nil {
+			return s.relationships.Create(ctx, tx, marriage)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return household, nil
@@ -322,13 +690,43 @@ func (s *Service) ListHouseholds(ctx context.Context, filter models.HouseholdFil
 	return s.households.List(ctx, filter, page)
 }
 
+// DeleteHousehold removes a household recorded in error. Households that have
+// ever had residents cannot be deleted and must be dissolved instead.
+func (s *Service) DeleteHousehold(ctx context.Context, id string) (err error) {
+	ctx, cmd := s.begin(ctx, CommandDeleteHousehold, idArgs{id})
+	defer func() { cmd.End(err) }()
+
+	household, err := s.households.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.households.Delete(ctx, nil, id); err != nil {
+		if errors.Is(err, repository.ErrHouseholdHasMembers) {
+			return fmt.Errorf("household %s cannot be deleted, dissolve it instead: %w",
+				household.Designation, repository.ErrHouseholdHasMembers)
+		}
+		return err
+	}
+
+	return nil
+}
+
 // GetHouseholdMembers retrieves all members of a household.
 func (s *Service) GetHouseholdMembers(ctx context.Context, householdID string) ([]*models.Resident, error) {
-	return s.residents.GetByHousehold(ctx, householdID)
+	members, err := s.residents.GetByHousehold(ctx, householdID)
+	if err != nil {
+		return nil, err
+	}
+	s.redactResidents(ctx, members...)
+	return members, nil
 }
 
 // AssignToHousehold assigns a resident to a household.
-func (s *Service) AssignToHousehold(ctx context.Context, residentID, householdID string) error {
+func (s *Service) AssignToHousehold(ctx context.Context, residentID, householdID string) (err error) {
+	ctx, cmd := s.begin(ctx, CommandAssignToHousehold, assignHouseholdArgs{residentID, householdID})
+	defer func() { cmd.End(err) }()
+
 	resident, err := s.residents.GetByID(ctx, residentID)
 	if err != nil {
 		return err
@@ -340,18 +738,85 @@ func (s *Service) AssignToHousehold(ctx context.Context, residentID, householdID
 		return fmt.Errorf("household not found: %w", err)
 	}
 
+	previousID := resident.HouseholdID
 	resident.HouseholdID = &householdID
-	return s.residents.Update(ctx, nil, resident)
+	if err := s.residents.Update(ctx, nil, resident); err != nil {
+		return err
+	}
+
+	s.queueRationReview(ctx, householdID, models.RationReviewTriggerTransfer)
+	if previousID != nil && *previousID != householdID {
+		s.queueRationReview(ctx, *previousID, models.RationReviewTriggerTransfer)
+	}
+
+	return nil
+}
+
+// AssignToHouseholdByDesignation assigns a resident to the household with the
+// given designation.
+func (s *Service) AssignToHouseholdByDesignation(ctx context.Context, residentID, designation string) error {
+	household, err := s.households.GetByDesignation(ctx, designation)
+	if err != nil {
+		return fmt.Errorf("household %s: %w", designation, err)
+	}
+	return s.AssignToHousehold(ctx, residentID, household.ID)
+}
+
+// AssignVocation sets a resident's primary vocation by vocation code. The
+// vocation must be active and the resident must hold its required clearance.
+func (s *Service) AssignVocation(ctx context.Context, residentID, vocationCode string) (err error) {
+	ctx, cmd := s.begin(ctx, CommandAssignVocation, assignVocationArgs{residentID, vocationCode})
+	defer func() { cmd.End(err) }()
+
+	resident, err := s.residents.GetByID(ctx, residentID)
+	if err != nil {
+		return err
+	}
+	if !resident.IsAlive() {
+		return fmt.Errorf("%w: resident %s is deceased", repository.ErrValidation, resident.RegistryNumber)
+	}
+
+	vocation, ok, err := s.vocationRef.Find(ctx, func(v *models.Vocation) bool { return v.Code == vocationCode })
+	if err != nil {
+		return fmt.Errorf("loading vocations: %w", err)
+	}
+	if !ok {
+		return fmt.Errorf("vocation %s: vocation %w", vocationCode, repository.ErrNotFound)
+	}
+	if !vocation.IsActive {
+		return fmt.Errorf("%w: vocation %s is inactive", repository.ErrValidation, vocation.Code)
+	}
+	if resident.ClearanceLevel < vocation.RequiredClearance {
+		return fmt.Errorf("%w: vocation %s requires clearance %d, resident has %d",
+			repository.ErrValidation, vocation.Code, vocation.RequiredClearance, resident.ClearanceLevel)
+	}
+
+	resident.PrimaryVocationID = &vocation.ID
+	if err := s.residents.Update(ctx, nil, resident); err != nil {
+		return fmt.Errorf("updating resident: %w", err)
+	}
+
+	return nil
 }
 
 // GetChildren retrieves biological children of a resident.
 func (s *Service) GetChildren(ctx context.Context, residentID string) ([]*models.Resident, error) {
-	return s.residents.GetChildren(ctx, residentID)
+	children, err := s.residents.GetChildren(ctx, residentID)
+	if err != nil {
+		return nil, err
+	}
+	s.redactResidents(ctx, children...)
+	return children, nil
 }
 
 // GetParents retrieves biological parents of a resident.
 func (s *Service) GetParents(ctx context.Context, residentID string) ([]*models.Resident, error) {
-	return s.residents.GetParents(ctx, residentID)
+	parents, err := s.residents.GetParents(ctx, residentID)
+	if err != nil {
+		return nil, err
+	}
+	s.redactResidents(ctx, parents...)
+	return parents, nil
 }
 
 // GetPopulationStats returns current population statistics.
@@ -0,0 +1,261 @@
+package population
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// Staffing forecast ages, in line with GetWorkforceStats.
+const (
+	traineeAge    = 16 // Youngest age a resident can start vocational training
+	retirementAge = 66 // Age at which a resident leaves the workforce
+)
+
+// trainingYears is how long it takes to train a replacement in each department.
+var trainingYears = map[models.Department]int{
+	models.DepartmentEngineering:    3,
+	models.DepartmentMedical:        4,
+	models.DepartmentSecurity:       1,
+	models.DepartmentFoodProduction: 1,
+	models.DepartmentAdministration: 2,
+	models.DepartmentEducation:      2,
+	models.DepartmentSanitation:     1,
+	models.DepartmentResearch:       4,
+}
+
+// TrainingYears returns the training lead time for a department.
+func TrainingYears(dept models.Department) int {
+	if years, ok := trainingYears[dept]; ok {
+		return years
+	}
+	return 2
+}
+
+// StaffingForecast projects vocation headcount against requirements.
+type StaffingForecast struct {
+	AsOf            time.Time
+	Years           int
+	Vocations       []VocationForecast
+	Gaps            []StaffingGap            // Ordered by how soon the shortfall begins
+	Recommendations []TrainingRecommendation // Ordered by training start year
+}
+
+// VocationForecast contains the projected staffing of one vocation.
+type VocationForecast struct {
+	Vocation *models.Vocation
+	Current  int
+	Points   []StaffingPoint // Year 0 is the current year
+}
+
+// StaffingPoint is the staffing position of a vocation in a given year.
+type StaffingPoint struct {
+	Year      int
+	Required  int
+	Available int
+	Shortfall int
+}
+
+// StaffingGap is a vocation that falls below its required headcount.
+type StaffingGap struct {
+	VocationCode string
+	Title        string
+	Department   models.Department
+	Year         int // Calendar year the shortfall begins
+	YearsOut     int // Years from the forecast date, 0 if already short
+	Shortfall    int // Largest shortfall within the forecast window
+}
+
+// String describes the gap, e.g. "in 12 years (2089) short 4 Power Plant Operator".
+func (g StaffingGap) String() string {
+	when := "now"
+	if g.YearsOut > 0 {
+		when = fmt.Sprintf("in %d years (%d)", g.YearsOut, g.Year)
+	}
+	return fmt.Sprintf("%s short %d %s", when, g.Shortfall, g.Title)
+}
+
+// TrainingRecommendation suggests when to start training replacements.
+type TrainingRecommendation struct {
+	VocationCode  string
+	Title         string
+	Trainees      int
+	StartYear     int
+	TrainingYears int
+	Candidates    int  // Unassigned residents of training age at the start year
+	Late          bool // Training should already have started
+}
+
+// ForecastStaffing combines the population projection with vocation headcount
+// minimums to find future staffing gaps. Required headcount scales with the
+// projected population. Assigned residents leave the workforce at retirement
+// age and are not replaced unless a training pipeline is started.
+func (s *Service) ForecastStaffing(ctx context.Context, asOf time.Time, years int) (*StaffingForecast, error) {
+	if years < 1 {
+		return nil, fmt.Errorf("forecast years must be at least 1")
+	}
+
+	projection, err := s.ProjectPopulation(ctx, asOf, years)
+	if err != nil {
+		return nil, fmt.Errorf("projecting population: %w", err)
+	}
+
+	residents, err := s.listActiveResidents(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	vocations, err := s.activeVocations(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing vocations: %w", err)
+	}
+
+	assigned := make(map[string][]*models.Resident)
+	var unassigned []*models.Resident
+	for _, r := range residents {
+		if r.PrimaryVocationID == nil {
+			unassigned = append(unassigned, r)
+			continue
+		}
+		assigned[*r.PrimaryVocationID] = append(assigned[*r.PrimaryVocationID], r)
+	}
+
+	forecast := &StaffingForecast{AsOf: asOf, Years: years}
+
+	for _, voc := range vocations {
+		vf := VocationForecast{Vocation: voc, Current: len(assigned[voc.ID])}
+		var gap *StaffingGap
+
+		for y := 0; y <= years; y++ {
+			population := projection.CurrentPopulation
+			if y > 0 {
+				population = projection.Projections[y-1].Population
+			}
+
+			point := StaffingPoint{
+				Year:      asOf.Year() + y,
+				Required:  requiredHeadcount(voc.HeadcountMinimum, population, projection.CurrentPopulation),
+				Available: countBelowAge(assigned[voc.ID], asOf.AddDate(y, 0, 0), retirementAge),
+			}
+			if point.Available < point.Required {
+				point.Shortfall = point.Required - point.Available
+			}
+			vf.Points = append(vf.Points, point)
+
+			if point.Shortfall == 0 {
+				continue
+			}
+			if gap == nil {
+				gap = &StaffingGap{
+					VocationCode: voc.Code,
+					Title:        voc.Title,
+					Department:   voc.Department,
+					Year:         point.Year,
+					YearsOut:     y,
+				}
+			}
+			if point.Shortfall > gap.Shortfall {
+				gap.Shortfall = point.Shortfall
+			}
+		}
+
+		forecast.Vocations = append(forecast.Vocations, vf)
+		if gap != nil {
+			forecast.Gaps = append(forecast.Gaps, *gap)
+			forecast.Recommendations = append(forecast.Recommendations,
+				recommendTraining(*gap, asOf, unassigned))
+		}
+	}
+
+	sort.SliceStable(forecast.Gaps, func(i, j int) bool {
+		if forecast.Gaps[i].YearsOut != forecast.Gaps[j].YearsOut {
+			return forecast.Gaps[i].YearsOut < forecast.Gaps[j].YearsOut
+		}
+		return forecast.Gaps[i].Shortfall > forecast.Gaps[j].Shortfall
+	})
+	sort.SliceStable(forecast.Recommendations, func(i, j int) bool {
+		return forecast.Recommendations[i].StartYear < forecast.Recommendations[j].StartYear
+	})
+
+	return forecast, nil
+}
+
+// recommendTraining works back from a gap by the department's training time.
+func recommendTraining(gap StaffingGap, asOf time.Time, unassigned []*models.Resident) TrainingRecommendation {
+	lead := TrainingYears(gap.Department)
+	rec := TrainingRecommendation{
+		VocationCode:  gap.VocationCode,
+		Title:         gap.Title,
+		Trainees:      gap.Shortfall,
+		StartYear:     gap.Year - lead,
+		TrainingYears: lead,
+	}
+	if rec.StartYear < asOf.Year() {
+		rec.StartYear = asOf.Year()
+		rec.Late = true
+	}
+
+	start := asOf.AddDate(rec.StartYear-asOf.Year(), 0, 0)
+	for _, r := range unassigned {
+		age := r.Age(start)
+		if age >= traineeAge && age+lead < retirementAge {
+			rec.Candidates++
+		}
+	}
+
+	return rec
+}
+
+// requiredHeadcount scales a vocation minimum by projected population.
+func requiredHeadcount(minimum, population, current int) int {
+	if minimum <= 0 {
+		return 0
+	}
+	if current <= 0 || population <= 0 {
+		return minimum
+	}
+	required := int(math.Ceil(float64(minimum) * float64(population) / float64(current)))
+	if required < 1 {
+		required = 1
+	}
+	return required
+}
+
+// countBelowAge counts residents younger than age on the given date.
+func countBelowAge(residents []*models.Resident, on time.Time, age int) int {
+	count := 0
+	for _, r := range residents {
+		if r.Age(on) < age {
+			count++
+		}
+	}
+	return count
+}
+
+// listActiveResidents retrieves every active resident.
+func (s *Service) listActiveResidents(ctx context.Context) ([]*models.Resident, error) {
+	filter := models.ResidentFilter{
+		Status: ptr(models.ResidentStatusActive),
+	}
+
+	var allResidents []*models.Resident
+	page := models.Pagination{Page: 1, PageSize: 100}
+
+	for {
+		result, err := s.residents.List(ctx, filter, page)
+		if err != nil {
+			return nil, err
+		}
+		allResidents = append(allResidents, result.Residents...)
+		if page.Page >= result.TotalPages {
+			break
+		}
+		page.Page++
+	}
+
+	return allResidents, nil
+}
@@ -0,0 +1,123 @@
+package population
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+)
+
+// TagResidents adds tags to residents and takes others off, in one
+// transaction: every resident is tagged, or none is when any is missing.
+// Tags are free-form, e.g. "council" or "G.O.A.T. pending", and matched
+// regardless of case.
+func (s *Service) TagResidents(ctx context.Context, residentIDs []string, add, remove []string) (err error) {
+	ctx, cmd := s.begin(ctx, CommandTagResidents, tagResidentsArgs{residentIDs, add, remove})
+	defer func() { cmd.End(err) }()
+
+	if len(residentIDs) == 0 {
+		return fmt.Errorf("%w: no residents selected", repository.ErrValidation)
+	}
+	if add, err = normalizeTags(add); err != nil {
+		return err
+	}
+	if remove, err = normalizeTags(remove); err != nil {
+		return err
+	}
+	if len(add) == 0 && len(remove) == 0 {
+		return fmt.Errorf("%w: no tags given", repository.ErrValidation)
+	}
+	for _, tag := range add {
+		if models.HasTag(remove, tag) {
+			return fmt.Errorf("%w: tag %q both added and removed", repository.ErrValidation, tag)
+		}
+	}
+	for _, id := range residentIDs {
+		if _, err := s.residents.GetByID(ctx, id); err != nil {
+			return fmt.Errorf("resident %s: %w", id, err)
+		}
+	}
+
+	return repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		for _, id := range residentIDs {
+			if err := s.tags.Remove(ctx, tx, id, remove); err != nil {
+				return err
+			}
+			if err := s.tags.Add(ctx, tx, id, add); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// normalizeTags normalizes each of the tags, dropping repeats.
+func normalizeTags(tags []string) ([]string, error) {
+	var normalized []string
+	for _, tag := range tags {
+		tag, err := models.NormalizeTag(tag)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", repository.ErrValidation, err)
+		}
+		if !models.HasTag(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized, nil
+}
+
+// GetResidentTags retrieves a resident's tags in alphabetical order.
+func (s *Service) GetResidentTags(ctx context.Context, residentID string) ([]string, error) {
+	return s.tags.ListByResident(ctx, residentID)
+}
+
+// ListTags retrieves the tags carried by the vault's residents with how
+// many carry each, most used first.
+func (s *Service) ListTags(ctx context.Context) ([]*models.TagCount, error) {
+	return s.tags.ListCounts(ctx)
+}
+
+// SaveCensusPreset saves a census filter and sort under a name, replacing
+// the preset of that name if there is one.
+func (s *Service) SaveCensusPreset(ctx context.Context, name string, filter models.ResidentFilter, sort models.SortOption) (_ *models.CensusPreset, err error) {
+	ctx, cmd := s.begin(ctx, CommandSaveCensusPreset, censusPresetArgs{name, filter, sort})
+	defer func() { cmd.End(err) }()
+
+	if filter.Tags, err = normalizeTags(filter.Tags); err != nil {
+		return nil, err
+	}
+	filter.VocationCode = strings.ToUpper(strings.TrimSpace(filter.VocationCode))
+	filter.Sector = strings.ToUpper(strings.TrimSpace(filter.Sector))
+	preset := &models.CensusPreset{
+		ID:     s.idGenerator.NewID(),
+		Name:   strings.Join(strings.Fields(name), " "),
+		Filter: filter,
+		Sort:   sort,
+	}
+	if err := s.presets.Save(ctx, preset); err != nil {
+		return nil, err
+	}
+	return preset, nil
+}
+
+// DeleteCensusPreset removes a saved census preset.
+func (s *Service) DeleteCensusPreset(ctx context.Context, id string) (err error) {
+	ctx, cmd := s.begin(ctx, CommandDeleteCensusPreset, idArgs{id})
+	defer func() { cmd.End(err) }()
+
+	return s.presets.Delete(ctx, id)
+}
+
+// GetCensusPreset retrieves the vault's census preset of a name,
+// regardless of case.
+func (s *Service) GetCensusPreset(ctx context.Context, name string) (*models.CensusPreset, error) {
+	return s.presets.GetByName(ctx, strings.Join(strings.Fields(name), " "))
+}
+
+// ListCensusPresets retrieves the vault's census presets by name.
+func (s *Service) ListCensusPresets(ctx context.Context) ([]*models.CensusPreset, error) {
+	return s.presets.List(ctx)
+}
@@ -0,0 +1,402 @@
+package population
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/simulation"
+)
+
+// Apprentice is an active apprenticeship with its apprentice, vocation and
+// mentor.
+type Apprentice struct {
+	Apprenticeship *models.Apprenticeship
+	Resident       *models.Resident
+	Vocation       *models.Vocation
+	Mentor         *models.Resident // Nil if the mentor's record was deleted
+}
+
+// EnrollInput contains data for enrolling an apprentice.
+type EnrollInput struct {
+	ResidentID   string
+	VocationCode string
+	MentorID     string
+	EnrolledAt   time.Time // Defaults to now
+	Notes        string
+}
+
+// SetTrainingProgram sets the training hours a vocation requires and the
+// hours a vault day of training counts for. Apprentices already enrolled
+// keep the hours they enrolled under.
+func (s *Service) SetTrainingProgram(ctx context.Context, vocationCode string, requiredHours, hoursPerDay float64) (_ *models.TrainingProgram, err error) {
+	ctx, cmd := s.begin(ctx, CommandSetTrainingProgram, trainingProgramArgs{vocationCode, requiredHours, hoursPerDay})
+	defer func() { cmd.End(err) }()
+
+	vocation, err := s.vocationByCode(ctx, vocationCode)
+	if err != nil {
+		return nil, err
+	}
+
+	program := &models.TrainingProgram{
+		ID:            s.idGenerator.NewID(),
+		VocationID:    vocation.ID,
+		RequiredHours: requiredHours,
+		HoursPerDay:   hoursPerDay,
+	}
+	if existing, err := s.training.GetProgram(ctx, vocation.ID); err == nil {
+		program.ID = existing.ID
+		program.Description = existing.Description
+	}
+	if err := s.training.SetProgram(ctx, nil, program); err != nil {
+		return nil, err
+	}
+	return program, nil
+}
+
+// GetTrainingProgram retrieves the training program of a vocation.
+func (s *Service) GetTrainingProgram(ctx context.Context, vocationID string) (*models.TrainingProgram, error) {
+	return s.training.GetProgram(ctx, vocationID)
+}
+
+// Enroll enrolls a living, active resident of at least AptitudeTestAge as an
+// apprentice in a vocation, under a mentor who holds it. The resident must
+// meet the vocation's clearance, not already hold it and not already be
+// training.
+func (s *Service) Enroll(ctx context.Context, input EnrollInput) (_ *models.Apprenticeship, err error) {
+	ctx, cmd := s.begin(ctx, CommandEnrollApprentice, input)
+	defer func() { cmd.End(err) }()
+
+	resident, err := s.residents.GetByID(ctx, input.ResidentID)
+	if err != nil {
+		return nil, err
+	}
+	if resident.Status != models.ResidentStatusActive {
+		return nil, fmt.Errorf("%w: resident %s is %s", repository.ErrValidation, resident.RegistryNumber, resident.Status)
+	}
+
+	enrolledAt := input.EnrolledAt
+	if enrolledAt.IsZero() {
+		enrolledAt = s.now()
+	}
+	if age := resident.Age(enrolledAt); age < models.AptitudeTestAge {
+		return nil, fmt.Errorf("%w: resident %s is %d, apprentices enroll from %d",
+			repository.ErrValidation, resident.RegistryNumber, age, models.AptitudeTestAge)
+	}
+
+	vocation, err := s.vocationByCode(ctx, input.VocationCode)
+	if err != nil {
+		return nil, err
+	}
+	if resident.PrimaryVocationID != nil && *resident.PrimaryVocationID == vocation.ID {
+		return nil, fmt.Errorf("%w: resident %s already holds %s", repository.ErrValidation, resident.RegistryNumber, vocation.Code)
+	}
+	if resident.ClearanceLevel < vocation.RequiredClearance {
+		return nil, fmt.Errorf("%w: vocation %s requires clearance %d, resident has %d",
+			repository.ErrValidation, vocation.Code, vocation.RequiredClearance, resident.ClearanceLevel)
+	}
+
+	mentor, err := s.residents.GetByID(ctx, input.MentorID)
+	if err != nil {
+		return nil, fmt.Errorf("mentor: %w", err)
+	}
+	if err := checkMentor(mentor, vocation); err != nil {
+		return nil, err
+	}
+
+	program, err := s.training.GetProgram(ctx, vocation.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	apprenticeship := &models.Apprenticeship{
+		ID:            s.idGenerator.NewID(),
+		ResidentID:    resident.ID,
+		VocationID:    vocation.ID,
+		MentorID:      &mentor.ID,
+		Status:        models.ApprenticeshipStatusActive,
+		EnrolledAt:    enrolledAt,
+		RequiredHours: program.RequiredHours,
+		HoursPerDay:   program.HoursPerDay,
+		Notes:         input.Notes,
+	}
+	if err := s.training.Create(ctx, nil, apprenticeship); err != nil {
+		return nil, fmt.Errorf("enrolling %s: %w", resident.RegistryNumber, err)
+	}
+	return apprenticeship, nil
+}
+
+// ChangeMentor puts an apprentice under a new mentor who holds the
+// vocation, e.g. when their mentor dies or changes vocation.
+func (s *Service) ChangeMentor(ctx context.Context, apprenticeshipID, mentorID string) (err error) {
+	ctx, cmd := s.begin(ctx, CommandChangeMentor, mentorArgs{apprenticeshipID, mentorID})
+	defer func() { cmd.End(err) }()
+
+	apprenticeship, err := s.activeApprenticeship(ctx, apprenticeshipID)
+	if err != nil {
+		return err
+	}
+	vocation, err := s.vocations.GetByID(ctx, apprenticeship.VocationID)
+	if err != nil {
+		return err
+	}
+	mentor, err := s.residents.GetByID(ctx, mentorID)
+	if err != nil {
+		return fmt.Errorf("mentor: %w", err)
+	}
+	if mentor.ID == apprenticeship.ResidentID {
+		return fmt.Errorf("%w: an apprentice cannot mentor themselves", repository.ErrValidation)
+	}
+	if err := checkMentor(mentor, vocation); err != nil {
+		return err
+	}
+	return s.training.SetMentor(ctx, nil, apprenticeship.ID, mentor.ID)
+}
+
+// SignOff records the mentor's sign-off of an apprentice who has trained
+// the required hours, graduating them: the vocation becomes their primary
+// vocation. The mentor must still hold the vocation.
+func (s *Service) SignOff(ctx context.Context, apprenticeshipID, mentorID string) (_ *models.Apprenticeship, err error) {
+	ctx, cmd := s.begin(ctx, CommandSignOffApprentice, mentorArgs{apprenticeshipID, mentorID})
+	defer func() { cmd.End(err) }()
+
+	apprenticeship, err := s.activeApprenticeship(ctx, apprenticeshipID)
+	if err != nil {
+		return nil, err
+	}
+	if apprenticeship.MentorID == nil || *apprenticeship.MentorID != mentorID {
+		return nil, fmt.Errorf("%w: only the apprentice's mentor can sign off", repository.ErrValidation)
+	}
+	if !apprenticeship.HoursComplete() {
+		return nil, fmt.Errorf("%w: %.0f of %.0f training hours completed", repository.ErrValidation,
+			apprenticeship.HoursCompleted, apprenticeship.RequiredHours)
+	}
+
+	resident, err := s.residents.GetByID(ctx, apprenticeship.ResidentID)
+	if err != nil {
+		return nil, err
+	}
+	if resident.Status != models.ResidentStatusActive {
+		return nil, fmt.Errorf("%w: resident %s is %s", repository.ErrValidation, resident.RegistryNumber, resident.Status)
+	}
+	vocation, err := s.vocations.GetByID(ctx, apprenticeship.VocationID)
+	if err != nil {
+		return nil, err
+	}
+	if !vocation.IsActive {
+		return nil, fmt.Errorf("%w: vocation %s is inactive", repository.ErrValidation, vocation.Code)
+	}
+	mentor, err := s.residents.GetByID(ctx, mentorID)
+	if err != nil {
+		return nil, fmt.Errorf("mentor: %w", err)
+	}
+	if err := checkMentor(mentor, vocation); err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	apprenticeship.Status = models.ApprenticeshipStatusGraduated
+	apprenticeship.SignedOffBy = &mentor.ID
+	apprenticeship.EndedAt = &now
+	resident.PrimaryVocationID = &vocation.ID
+
+	err = repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		if err := s.training.Close(ctx, tx, apprenticeship); err != nil {
+			return err
+		}
+		return s.residents.Update(ctx, tx, resident)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return apprenticeship, nil
+}
+
+// Withdraw ends an apprenticeship before graduation.
+func (s *Service) Withdraw(ctx context.Context, apprenticeshipID, reason string) (_ *models.Apprenticeship, err error) {
+	ctx, cmd := s.begin(ctx, CommandWithdrawApprentice, withdrawArgs{apprenticeshipID, reason})
+	defer func() { cmd.End(err) }()
+
+	apprenticeship, err := s.activeApprenticeship(ctx, apprenticeshipID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	apprenticeship.Status = models.ApprenticeshipStatusWithdrawn
+	apprenticeship.EndedAt = &now
+	if reason != "" {
+		apprenticeship.Notes = reason
+	}
+	if err := s.training.Close(ctx, nil, apprenticeship); err != nil {
+		return nil, err
+	}
+	return apprenticeship, nil
+}
+
+// ListApprentices retrieves the vault's active apprenticeships with their
+// apprentices, vocations and mentors, earliest enrolled first.
+func (s *Service) ListApprentices(ctx context.Context) ([]Apprentice, error) {
+	apprenticeships, err := s.training.ListActive(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	apprentices := make([]Apprentice, 0, len(apprenticeships))
+	for _, a := range apprenticeships {
+		resident, err := s.residents.GetByID(ctx, a.ResidentID)
+		if err != nil {
+			return nil, err
+		}
+		vocation, ok, err := s.vocationRef.Find(ctx, func(v *models.Vocation) bool { return v.ID == a.VocationID })
+		if err != nil {
+			return nil, fmt.Errorf("loading vocations: %w", err)
+		}
+		if !ok {
+			return nil, fmt.Errorf("vocation %w: %s", repository.ErrNotFound, a.VocationID)
+		}
+		apprentice := Apprentice{Apprenticeship: a, Resident: resident, Vocation: vocation}
+		if a.MentorID != nil {
+			if apprentice.Mentor, err = s.residents.GetByID(ctx, *a.MentorID); err != nil {
+				return nil, err
+			}
+		}
+		s.redactResidents(ctx, apprentice.Resident, apprentice.Mentor)
+		apprentices = append(apprentices, apprentice)
+	}
+	return apprentices, nil
+}
+
+// AccrueTraining adds the training of the vault time between from and to to
+// every apprentice who is an ACTIVE resident. It returns the apprenticeships
+// whose hours that completed.
+func (s *Service) AccrueTraining(ctx context.Context, from, to time.Time) (_ []*models.Apprenticeship, err error) {
+	ctx, cmd := s.begin(ctx, CommandAccrueTraining, accrueTrainingArgs{from, to})
+	defer func() { cmd.End(err) }()
+
+	elapsed := to.Sub(from)
+	if elapsed <= 0 {
+		return nil, nil
+	}
+	apprenticeships, err := s.training.ListActive(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var completed []*models.Apprenticeship
+	err = repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		for _, a := range apprenticeships {
+			if a.HoursComplete() {
+				continue
+			}
+			if a.Train(elapsed) {
+				completed = append(completed, a)
+			}
+			if err := s.training.UpdateHours(ctx, tx, a); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return completed, nil
+}
+
+// activeApprenticeship retrieves an apprenticeship that is still training.
+func (s *Service) activeApprenticeship(ctx context.Context, id string) (*models.Apprenticeship, error) {
+	apprenticeship, err := s.training.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !apprenticeship.IsActive() {
+		return nil, fmt.Errorf("%w: apprenticeship has already ended %s", repository.ErrValidation, apprenticeship.Status)
+	}
+	return apprenticeship, nil
+}
+
+// vocationByCode retrieves an active vocation from the vocation cache.
+func (s *Service) vocationByCode(ctx context.Context, code string) (*models.Vocation, error) {
+	vocation, ok, err := s.vocationRef.Find(ctx, func(v *models.Vocation) bool { return v.Code == code })
+	if err != nil {
+		return nil, fmt.Errorf("loading vocations: %w", err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("vocation %s: vocation %w", code, repository.ErrNotFound)
+	}
+	if !vocation.IsActive {
+		return nil, fmt.Errorf("%w: vocation %s is inactive", repository.ErrValidation, vocation.Code)
+	}
+	return vocation, nil
+}
+
+// checkMentor checks that a resident can mentor apprentices of a vocation:
+// they are an ACTIVE resident holding it.
+func checkMentor(mentor *models.Resident, vocation *models.Vocation) error {
+	if mentor.Status != models.ResidentStatusActive {
+		return fmt.Errorf("%w: mentor %s is %s", repository.ErrValidation, mentor.RegistryNumber, mentor.Status)
+	}
+	if mentor.PrimaryVocationID == nil || *mentor.PrimaryVocationID != vocation.ID {
+		return fmt.Errorf("%w: mentor %s does not hold %s", repository.ErrValidation, mentor.RegistryNumber, vocation.Code)
+	}
+	return nil
+}
+
+// TrainingAccrual is the simulation hook that advances apprentices' training
+// hours with vault time.
+type TrainingAccrual struct {
+	service *Service
+}
+
+// TrainingAccrual creates the training hook.
+func (s *Service) TrainingAccrual() *TrainingAccrual {
+	return &TrainingAccrual{service: s}
+}
+
+// Name implements simulation.Hook.
+func (h *TrainingAccrual) Name() string {
+	return "vocational training"
+}
+
+// Advance implements simulation.Hook. Apprentices who complete their hours
+// are reported as awaiting their mentor's sign-off.
+func (h *TrainingAccrual) Advance(ctx context.Context, from, to time.Time) ([]simulation.Event, error) {
+	completed, err := h.service.AccrueTraining(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]simulation.Event, 0, len(completed))
+	for _, a := range completed {
+		event, err := h.service.trainingCompleteEvent(ctx, a, to, h.Name())
+		if err != nil {
+			return events, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// trainingCompleteEvent reports an apprentice who has completed their hours
+// as awaiting their mentor's sign-off.
+func (s *Service) trainingCompleteEvent(ctx context.Context, a *models.Apprenticeship, at time.Time, source string) (simulation.Event, error) {
+	resident, err := s.residents.GetByID(ctx, a.ResidentID)
+	if err != nil {
+		return simulation.Event{}, err
+	}
+	vocation, err := s.vocations.GetByID(ctx, a.VocationID)
+	if err != nil {
+		return simulation.Event{}, err
+	}
+	return simulation.Event{
+		Time:   at,
+		Level:  simulation.EventInfo,
+		Source: source,
+		Message: fmt.Sprintf("%s %s completed %.0f training hours as %s; awaiting mentor sign-off",
+			resident.RegistryNumber, resident.FullName(), a.RequiredHours, vocation.Title),
+	}, nil
+}
@@ -0,0 +1,465 @@
+package population
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// TransferFormat identifies the format of resident transfer bundles.
+const TransferFormat = "vtuos-transfer/1"
+
+// ErrTransferSignature is returned for a transfer bundle that was not
+// signed with the transfer key, or was changed after it was.
+var ErrTransferSignature = errors.New("transfer bundle signature does not match the transfer key")
+
+// TransferOptions selects what a transfer bundle carries beside the
+// resident.
+type TransferOptions struct {
+	Household bool // Every living member of the resident's household, as a household
+	Medical   bool // Medical conditions and radiation exposures
+}
+
+// TransferBundle carries residents from one vault to another. Residents
+// are identified by their registry numbers in the source vault; nothing
+// else of the source vault's records, such as IDs, vocations or quarters,
+// travels with them.
+type TransferBundle struct {
+	SourceVault int                `json:"source_vault"`
+	ExportedAt  time.Time          `json:"exported_at"`
+	Residents   []TransferResident `json:"residents"`
+	Household   *TransferHousehold `json:"household,omitempty"`
+}
+
+// TransferResident is a resident in a transfer bundle.
+type TransferResident struct {
+	RegistryNumber string              `json:"registry_number"` // In the source vault
+	Surname        string              `json:"surname"`
+	GivenNames     string              `json:"given_names"`
+	DateOfBirth    time.Time           `json:"date_of_birth"`
+	Sex            models.Sex          `json:"sex"`
+	BloodType      models.BloodType    `json:"blood_type,omitempty"`
+	Notes          string              `json:"notes,omitempty"`
+	Conditions     []TransferCondition `json:"conditions,omitempty"`
+	Exposures      []TransferExposure  `json:"exposures,omitempty"`
+}
+
+// TransferHousehold is the household the residents of a bundle form.
+type TransferHousehold struct {
+	Designation   string               `json:"designation"` // In the source vault
+	HouseholdType models.HouseholdType `json:"household_type"`
+	RationClass   models.RationClass   `json:"ration_class"`
+	Head          string               `json:"head,omitempty"` // Registry number of the head
+}
+
+// TransferCondition is a medical condition of a transferred resident.
+type TransferCondition struct {
+	Code           string                   `json:"code"`
+	Name           string                   `json:"name"`
+	OnsetDate      time.Time                `json:"onset_date"`
+	ResolutionDate *time.Time               `json:"resolution_date,omitempty"`
+	Severity       models.ConditionSeverity `json:"severity"`
+	IsChronic      bool                     `json:"is_chronic,omitempty"`
+	IsGenetic      bool                     `json:"is_genetic,omitempty"`
+	IsContagious   bool                     `json:"is_contagious,omitempty"`
+	TreatmentPlan  string                   `json:"treatment_plan,omitempty"`
+	Notes          string                   `json:"notes,omitempty"`
+}
+
+// TransferExposure is a radiation exposure of a transferred resident.
+type TransferExposure struct {
+	Source       string    `json:"source"`
+	DoseMSv      float64   `json:"dose_msv"`
+	ExposureDate time.Time `json:"exposure_date"`
+	Notes        string    `json:"notes,omitempty"`
+}
+
+// TransferResult summarizes an admitted transfer bundle.
+type TransferResult struct {
+	Admitted   []*models.Resident
+	Intakes    []*models.Intake  // Intake quarantine of each resident admitted
+	From       []string          // Source registry number of each resident admitted
+	Duplicates []string          // Source registry numbers of residents already registered here
+	Household  *models.Household // Household formed by the admitted residents, nil if none
+}
+
+// signedTransfer is a transfer bundle as written to a file.
+type signedTransfer struct {
+	Format    string          `json:"format"`
+	Bundle    json.RawMessage `json:"bundle"`
+	Signature string          `json:"signature"` // Hex HMAC-SHA256 of the compact bundle
+}
+
+// ExportTransfer bundles a resident for transfer to another vault,
+// optionally with their household and medical history. It needs the
+// clearance to see personal details, as the bundle carries them all.
+func (s *Service) ExportTransfer(ctx context.Context, registryNumber string, opts TransferOptions) (*TransferBundle, error) {
+	if !util.Cleared(ctx, s.piiClearance) {
+		return nil, fmt.Errorf("%w: transferring a resident needs clearance %d", repository.ErrValidation, s.piiClearance)
+	}
+	resident, err := s.residents.GetByRegistryNumber(ctx, nil, registryNumber)
+	if err != nil {
+		return nil, err
+	}
+	if !resident.IsAlive() {
+		return nil, fmt.Errorf("%w: resident %s is not alive", repository.ErrValidation, registryNumber)
+	}
+
+	bundle := &TransferBundle{SourceVault: resident.VaultID, ExportedAt: s.now()}
+	residents := []*models.Resident{resident}
+	if opts.Household {
+		if resident.HouseholdID == nil {
+			return nil, fmt.Errorf("%w: resident %s has no household", repository.ErrValidation, registryNumber)
+		}
+		household, err := s.households.GetByID(ctx, *resident.HouseholdID)
+		if err != nil {
+			return nil, err
+		}
+		if residents, err = s.livingMembers(ctx, household.ID); err != nil {
+			return nil, err
+		}
+		bundle.Household = &TransferHousehold{
+			Designation:   household.Designation,
+			HouseholdType: household.HouseholdType,
+			RationClass:   household.RationClass,
+		}
+		for _, m := range residents {
+			if household.HeadOfHouseholdID != nil && m.ID == *household.HeadOfHouseholdID {
+				bundle.Household.Head = m.RegistryNumber
+			}
+		}
+	}
+
+	for _, r := range residents {
+		tr := TransferResident{
+			RegistryNumber: r.RegistryNumber,
+			Surname:        r.Surname,
+			GivenNames:     r.GivenNames,
+			DateOfBirth:    r.DateOfBirth,
+			Sex:            r.Sex,
+			BloodType:      r.BloodType,
+			Notes:          r.Notes,
+		}
+		if opts.Medical {
+			if err := s.exportMedical(ctx, r.ID, &tr); err != nil {
+				return nil, fmt.Errorf("resident %s: %w", r.RegistryNumber, err)
+			}
+		}
+		bundle.Residents = append(bundle.Residents, tr)
+	}
+	return bundle, nil
+}
+
+// exportMedical adds a resident's conditions and exposures to a bundle.
+func (s *Service) exportMedical(ctx context.Context, residentID string, tr *TransferResident) error {
+	conditions, err := s.medical.ListConditions(ctx, residentID)
+	if err != nil {
+		return err
+	}
+	for _, c := range conditions {
+		tr.Conditions = append(tr.Conditions, TransferCondition{
+			Code:           c.ConditionCode,
+			Name:           c.ConditionName,
+			OnsetDate:      c.OnsetDate,
+			ResolutionDate: c.ResolutionDate,
+			Severity:       c.Severity,
+			IsChronic:      c.IsChronic,
+			IsGenetic:      c.IsGenetic,
+			IsContagious:   c.IsContagious,
+			TreatmentPlan:  c.TreatmentPlan,
+			Notes:          c.Notes,
+		})
+	}
+	exposures, err := s.radiation.ListExposures(ctx, residentID)
+	if err != nil {
+		return err
+	}
+	// Listed most recent first; the bundle keeps them in order of date
+	for i := len(exposures) - 1; i >= 0; i-- {
+		e := exposures[i]
+		tr.Exposures = append(tr.Exposures, TransferExposure{
+			Source:       e.Source,
+			DoseMSv:      e.DoseMSv,
+			ExposureDate: e.ExposureDate,
+			Notes:        e.Notes,
+		})
+	}
+	return nil
+}
+
+// SealTransfer signs a transfer bundle with the transfer key and returns
+// it as written to a file.
+func SealTransfer(bundle *TransferBundle, key string) ([]byte, error) {
+	if key == "" {
+		return nil, errors.New("no transfer key configured; set [transfer] key")
+	}
+	data, err := json.Marshal(bundle)
+	if err != nil {
+		return nil, fmt.Errorf("encoding transfer bundle: %w", err)
+	}
+	out, err := json.MarshalIndent(signedTransfer{
+		Format:    TransferFormat,
+		Bundle:    data,
+		Signature: transferSignature(data, key),
+	}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding transfer bundle: %w", err)
+	}
+	return append(out, '\n'), nil
+}
+
+// OpenTransfer checks the signature of a transfer bundle written by
+// SealTransfer against the transfer key and returns the bundle.
+func OpenTransfer(data []byte, key string) (*TransferBundle, error) {
+	if key == "" {
+		return nil, errors.New("no transfer key configured; set [transfer] key")
+	}
+	var signed signedTransfer
+	if err := json.Unmarshal(data, &signed); err != nil {
+		return nil, fmt.Errorf("reading transfer bundle: %w", err)
+	}
+	if signed.Format != TransferFormat {
+		return nil, fmt.Errorf("unsupported transfer bundle format %q (want %s)", signed.Format, TransferFormat)
+	}
+
+	// The bundle is signed compact, so reindenting the file does no harm
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, signed.Bundle); err != nil {
+		return nil, fmt.Errorf("reading transfer bundle: %w", err)
+	}
+	if !hmac.Equal([]byte(transferSignature(compact.Bytes(), key)), []byte(signed.Signature)) {
+		return nil, ErrTransferSignature
+	}
+
+	var bundle TransferBundle
+	if err := json.Unmarshal(compact.Bytes(), &bundle); err != nil {
+		return nil, fmt.Errorf("reading transfer bundle: %w", err)
+	}
+	return &bundle, nil
+}
+
+// transferSignature returns the hex HMAC-SHA256 of data under key.
+func transferSignature(data []byte, key string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write(data)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// transferRow is a resident of a bundle awaiting admission.
+type transferRow struct {
+	source     TransferResident
+	resident   *models.Resident
+	conditions []*models.MedicalCondition
+	exposures  []*models.RadiationExposure
+	intake     *models.Intake
+	transition *models.StatusTransition
+}
+
+// ImportTransfer admits the residents of a transfer bundle opened with
+// OpenTransfer as ADMITTED residents with new registry numbers, at
+// clearance 1, with their medical history. Like any admission, each enters
+// an intake quarantine of models.MinIntakeQuarantineDays and stays in it
+// until cleared with ClearIntake. A resident whose name and
+// date of birth match one already registered is skipped as a duplicate.
+// The others, if the bundle carries a household, form a new household
+// under a designation of this vault. Every resident is checked before any
+// is admitted, and all are admitted in one transaction.
+func (s *Service) ImportTransfer(ctx context.Context, bundle *TransferBundle) (_ *TransferResult, err error) {
+	ctx, cmd := s.begin(ctx, CommandImportTransfer, bundle)
+	defer func() { cmd.End(err) }()
+
+	if len(bundle.Residents) == 0 {
+		return nil, fmt.Errorf("%w: transfer bundle has no residents", repository.ErrValidation)
+	}
+	if bundle.SourceVault == s.vaultNumber {
+		return nil, fmt.Errorf("%w: transfer bundle is from this vault", repository.ErrValidation)
+	}
+
+	result := &TransferResult{}
+	now := s.now()
+	seen := make(map[string]bool)
+	var rows []*transferRow
+	var errs []error
+	for _, tr := range bundle.Residents {
+		if seen[tr.RegistryNumber] {
+			errs = append(errs, fmt.Errorf("resident %s: listed twice", tr.RegistryNumber))
+			continue
+		}
+		seen[tr.RegistryNumber] = true
+
+		matches, err := s.residents.FindByIdentity(ctx, tr.Surname, tr.GivenNames, tr.DateOfBirth)
+		if err != nil {
+			return nil, err
+		}
+		if len(matches) > 0 {
+			result.Duplicates = append(result.Duplicates, tr.RegistryNumber)
+			continue
+		}
+
+		row, err := s.transferRow(bundle.SourceVault, tr, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("resident %s: %w", tr.RegistryNumber, err))
+			continue
+		}
+		rows = append(rows, row)
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("%w: %w", repository.ErrValidation, errors.Join(errs...))
+	}
+	if len(rows) == 0 {
+		return result, nil
+	}
+
+	var household *models.Household
+	if bundle.Household != nil {
+		designation, err := s.households.GetNextDesignation(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("generating designation: %w", err)
+		}
+		household = &models.Household{
+			ID:            s.idGenerator.NewID(),
+			Designation:   designation,
+			HouseholdType: bundle.Household.HouseholdType,
+			RationClass:   bundle.Household.RationClass,
+			Status:        models.HouseholdStatusActive,
+			FormedDate:    now,
+		}
+		if len(rows) == 1 {
+			household.HouseholdType = models.HouseholdTypeIndividual
+		}
+	}
+
+	err = repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		numbers, err := s.reserveRegistryNumbers(ctx, tx, len(rows))
+		if err != nil {
+			return err
+		}
+		if household != nil {
+			if err := s.households.Create(ctx, tx, household); err != nil {
+				return fmt.Errorf("creating household: %w", err)
+			}
+		}
+		for i, row := range rows {
+			row.resident.RegistryNumber = numbers[i]
+			if household != nil {
+				row.resident.HouseholdID = &household.ID
+				if row.source.RegistryNumber == bundle.Household.Head {
+					household.HeadOfHouseholdID = &row.resident.ID
+				}
+			}
+			if err := s.residents.Create(ctx, tx, row.resident); err != nil {
+				return fmt.Errorf("admitting %s: %w", row.source.RegistryNumber, err)
+			}
+			if err := s.createIntake(ctx, tx, row.intake, row.transition); err != nil {
+				return fmt.Errorf("admitting %s: %w", row.source.RegistryNumber, err)
+			}
+			for _, c := range row.conditions {
+				if err := s.medical.CreateCondition(ctx, tx, c); err != nil {
+					return fmt.Errorf("admitting %s: %w", row.source.RegistryNumber, err)
+				}
+			}
+			for _, e := range row.exposures {
+				if err := s.radiation.CreateExposure(ctx, tx, e); err != nil {
+					return fmt.Errorf("admitting %s: %w", row.source.RegistryNumber, err)
+				}
+			}
+		}
+		if household != nil && household.HeadOfHouseholdID != nil {
+			if err := s.households.Update(ctx, tx, household); err != nil {
+				return fmt.Errorf("setting head of household: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		s.residentCreated(ctx, row.resident)
+		result.Admitted = append(result.Admitted, row.resident)
+		result.Intakes = append(result.Intakes, row.intake)
+		result.From = append(result.From, row.source.RegistryNumber)
+	}
+	result.Household = household
+	return result, nil
+}
+
+// transferRow checks a resident of a bundle from vault source and builds
+// the records that admit them at vault time now.
+func (s *Service) transferRow(source int, tr TransferResident, now time.Time) (*transferRow, error) {
+	note := fmt.Sprintf("Transferred from Vault %03d as %s", source, tr.RegistryNumber)
+	if tr.Notes != "" {
+		note += "\n" + tr.Notes
+	}
+	row := &transferRow{
+		source: tr,
+		resident: &models.Resident{
+			ID:             s.idGenerator.NewID(),
+			RegistryNumber: "pending", // Reserved on admission
+			Surname:        strings.TrimSpace(tr.Surname),
+			GivenNames:     strings.TrimSpace(tr.GivenNames),
+			DateOfBirth:    tr.DateOfBirth,
+			Sex:            tr.Sex,
+			BloodType:      tr.BloodType,
+			EntryType:      models.EntryTypeAdmitted,
+			EntryDate:      now,
+			Status:         models.ResidentStatusQuarantine,
+			ClearanceLevel: 1,
+			Notes:          note,
+		},
+	}
+	if err := row.resident.Validate(); err != nil {
+		return nil, err
+	}
+	row.intake, row.transition = s.newIntake(row.resident, models.MinIntakeQuarantineDays,
+		fmt.Sprintf("Transferred from Vault %03d", source), now)
+
+	for _, c := range tr.Conditions {
+		cond := &models.MedicalCondition{
+			ID:             s.idGenerator.NewID(),
+			ResidentID:     row.resident.ID,
+			ConditionCode:  c.Code,
+			ConditionName:  c.Name,
+			OnsetDate:      c.OnsetDate,
+			ResolutionDate: c.ResolutionDate,
+			Severity:       c.Severity,
+			IsChronic:      c.IsChronic,
+			IsGenetic:      c.IsGenetic,
+			IsContagious:   c.IsContagious,
+			TreatmentPlan:  c.TreatmentPlan,
+			Notes:          c.Notes,
+		}
+		if err := cond.Validate(); err != nil {
+			return nil, fmt.Errorf("condition %s: %w", c.Code, err)
+		}
+		row.conditions = append(row.conditions, cond)
+	}
+	for _, e := range tr.Exposures {
+		exp := &models.RadiationExposure{
+			ID:           s.idGenerator.NewID(),
+			ResidentID:   row.resident.ID,
+			Source:       e.Source,
+			DoseMSv:      e.DoseMSv,
+			ExposureDate: e.ExposureDate,
+			Notes:        e.Notes,
+		}
+		if err := exp.Validate(); err != nil {
+			return nil, fmt.Errorf("exposure of %s: %w", util.FormatDate(e.ExposureDate), err)
+		}
+		row.exposures = append(row.exposures, exp)
+	}
+	return row, nil
+}
@@ -0,0 +1,77 @@
+package population
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+func testTransferBundle() *TransferBundle {
+	return &TransferBundle{
+		SourceVault: 101,
+		ExportedAt:  time.Date(2077, 10, 23, 12, 0, 0, 0, time.UTC),
+		Residents: []TransferResident{{
+			RegistryNumber: "V101-00042",
+			Surname:        "Hawthorne",
+			GivenNames:     "Edith",
+			DateOfBirth:    time.Date(2050, 3, 14, 0, 0, 0, 0, time.UTC),
+			Sex:            models.SexFemale,
+			BloodType:      models.BloodTypeOPos,
+		}},
+	}
+}
+
+func TestOpenTransfer(t *testing.T) {
+	data, err := SealTransfer(testTransferBundle(), "vault-key")
+	if err != nil {
+		t.Fatalf("SealTransfer() error = %v", err)
+	}
+
+	bundle, err := OpenTransfer(data, "vault-key")
+	if err != nil {
+		t.Fatalf("OpenTransfer() error = %v", err)
+	}
+	if bundle.SourceVault != 101 || len(bundle.Residents) != 1 || bundle.Residents[0].Surname != "Hawthorne" {
+		t.Errorf("OpenTransfer() = %+v, want the sealed bundle", bundle)
+	}
+}
+
+func TestOpenTransfer_WrongKey(t *testing.T) {
+	data, err := SealTransfer(testTransferBundle(), "vault-key")
+	if err != nil {
+		t.Fatalf("SealTransfer() error = %v", err)
+	}
+
+	if _, err := OpenTransfer(data, "other-key"); !errors.Is(err, ErrTransferSignature) {
+		t.Errorf("OpenTransfer() with another key error = %v, want ErrTransferSignature", err)
+	}
+}
+
+func TestOpenTransfer_Tampered(t *testing.T) {
+	data, err := SealTransfer(testTransferBundle(), "vault-key")
+	if err != nil {
+		t.Fatalf("SealTransfer() error = %v", err)
+	}
+	if !bytes.Contains(data, []byte("Hawthorne")) {
+		t.Fatalf("sealed bundle does not carry the surname: %s", data)
+	}
+	tampered := bytes.Replace(data, []byte("Hawthorne"), []byte("Hawthorns"), 1)
+
+	if _, err := OpenTransfer(tampered, "vault-key"); !errors.Is(err, ErrTransferSignature) {
+		t.Errorf("OpenTransfer() of a changed bundle error = %v, want ErrTransferSignature", err)
+	}
+}
+
+func TestOpenTransfer_NoKey(t *testing.T) {
+	data, err := SealTransfer(testTransferBundle(), "vault-key")
+	if err != nil {
+		t.Fatalf("SealTransfer() error = %v", err)
+	}
+
+	if _, err := OpenTransfer(data, ""); err == nil {
+		t.Error("OpenTransfer() without a key succeeded, want an error")
+	}
+}
@@ -0,0 +1,282 @@
+package population
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/simulation"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// ScheduleStatusInput contains data for putting a resident into a temporary
+// status.
+type ScheduleStatusInput struct {
+	Status      models.ResidentStatus // QUARANTINE or SURFACE_MISSION
+	ExpectedEnd time.Time             // Last day in the status
+	Reason      string
+	AutoRevert  bool // Return to ACTIVE without asking the operator
+}
+
+// DueTransition is a scheduled status period that has run past its expected
+// end.
+type DueTransition struct {
+	Transition *models.StatusTransition
+	Resident   *models.Resident
+	Reverted   bool // Returned to ACTIVE; otherwise the operator must confirm
+	// AwaitingClearance is set for an intake quarantine that a medical
+	// officer has not cleared; it cannot end until they do.
+	AwaitingClearance bool
+}
+
+// String describes the outcome, e.g. "V076-00012 Turner, Lori returned to
+// ACTIVE: quarantine ended 2077-11-02".
+func (d DueTransition) String() string {
+	name := fmt.Sprintf("%s %s", d.Resident.RegistryNumber, d.Resident.FullName())
+	status := strings.ToLower(strings.ReplaceAll(string(d.Transition.Status), "_", " "))
+	end := util.FormatDate(d.Transition.ExpectedEnd)
+	if d.Reverted {
+		return fmt.Sprintf("%s returned to ACTIVE: %s ended %s", name, status, end)
+	}
+	if d.AwaitingClearance {
+		return fmt.Sprintf("%s intake %s was due to end %s: awaiting medical clearance", name, status, end)
+	}
+	return fmt.Sprintf("%s %s was due to end %s: confirm return in census (r)", name, status, end)
+}
+
+// ScheduleStatus puts an active resident into quarantine or on a surface
+// mission until the end of input.ExpectedEnd.
+func (s *Service) ScheduleStatus(ctx context.Context, residentID string, input ScheduleStatusInput) (_ *models.StatusTransition, err error) {
+	ctx, cmd := s.begin(ctx, CommandScheduleStatus, scheduleStatusArgs{residentID, input})
+	defer func() { cmd.End(err) }()
+
+	resident, err := s.residents.GetByID(ctx, residentID)
+	if err != nil {
+		return nil, err
+	}
+	if resident.Status != models.ResidentStatusActive {
+		return nil, fmt.Errorf("%w: resident is %s", repository.ErrValidation, resident.Status)
+	}
+
+	now := s.now()
+	transition := &models.StatusTransition{
+		ID:          s.idGenerator.NewID(),
+		ResidentID:  residentID,
+		Status:      input.Status,
+		Reason:      input.Reason,
+		StartedAt:   now,
+		ExpectedEnd: input.ExpectedEnd,
+		AutoRevert:  input.AutoRevert,
+	}
+	if err := transition.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", repository.ErrValidation, err)
+	}
+
+	// A period left open by a status change made elsewhere is closed first.
+	stale, err := s.history.GetOpen(ctx, residentID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if stale != nil {
+		if err := s.history.End(ctx, tx, stale.ID, now); err != nil {
+			return nil, err
+		}
+	}
+
+	resident.Status = input.Status
+	if err := s.residents.Update(ctx, tx, resident); err != nil {
+		return nil, fmt.Errorf("updating resident: %w", err)
+	}
+	if err := s.history.Create(ctx, tx, transition); err != nil {
+		return nil, fmt.Errorf("recording status history: %w", err)
+	}
+	if err := s.auditStatusChange(ctx, tx, residentID, models.ResidentStatusActive, input.Status, models.AuditActorUser); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("committing transaction: %w", err)
+	}
+
+	return transition, nil
+}
+
+// EndStatus returns a resident in a temporary status to ACTIVE, e.g. when a
+// surface mission comes home early or the operator confirms a return. An
+// intake quarantine ends only through ClearIntake.
+func (s *Service) EndStatus(ctx context.Context, residentID string) (err error) {
+	ctx, cmd := s.begin(ctx, CommandEndStatus, residentArgs{residentID})
+	defer func() { cmd.End(err) }()
+
+	resident, err := s.residents.GetByID(ctx, residentID)
+	if err != nil {
+		return err
+	}
+	if !resident.Status.IsTemporary() {
+		return fmt.Errorf("%w: resident is %s", repository.ErrValidation, resident.Status)
+	}
+	if resident.Status == models.ResidentStatusQuarantine {
+		awaiting, err := s.awaitingClearance(ctx, residentID)
+		if err != nil {
+			return err
+		}
+		if awaiting {
+			return fmt.Errorf("%w: %s awaits intake clearance by a medical officer", repository.ErrValidation, resident.RegistryNumber)
+		}
+	}
+
+	open, err := s.history.GetOpen(ctx, residentID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+
+	return s.revertStatus(ctx, resident, open, models.AuditActorUser)
+}
+
+// GetStatusHistory retrieves a resident's scheduled status periods, most
+// recent first, without their reasons for an operator lacking the PII
+// clearance.
+func (s *Service) GetStatusHistory(ctx context.Context, residentID string) ([]*models.StatusTransition, error) {
+	history, err := s.history.ListByResident(ctx, residentID)
+	if err != nil {
+		return nil, err
+	}
+	if !util.Cleared(ctx, s.piiClearance) {
+		for _, t := range history {
+			t.Redact()
+		}
+	}
+	return history, nil
+}
+
+// ProcessDueTransitions handles the scheduled status periods that ended
+// before now. Periods with auto-revert return the resident to ACTIVE; the
+// others are returned once for the operator to confirm, or for a medical
+// officer to clear if they are an intake quarantine. Periods whose
+// resident has since changed status, e.g. died in quarantine, are closed.
+func (s *Service) ProcessDueTransitions(ctx context.Context, now time.Time) (_ []DueTransition, err error) {
+	ctx, cmd := s.begin(ctx, CommandProcessDueTransitions, dueTransitionsArgs{now})
+	defer func() { cmd.End(err) }()
+
+	due, err := s.history.ListDue(ctx, now)
+	if err != nil {
+		return nil, fmt.Errorf("listing due transitions: %w", err)
+	}
+
+	var processed []DueTransition
+	for _, t := range due {
+		if !t.IsDue(now) {
+			continue
+		}
+
+		resident, err := s.residents.GetByID(ctx, t.ResidentID)
+		if err != nil {
+			return processed, err
+		}
+
+		if resident.Status != t.Status {
+			if err := s.history.End(ctx, nil, t.ID, now); err != nil {
+				return processed, err
+			}
+			continue
+		}
+		if !t.AutoRevert && t.PromptedAt != nil {
+			continue
+		}
+
+		due := DueTransition{Transition: t, Resident: resident, Reverted: t.AutoRevert}
+		if t.AutoRevert {
+			err = s.revertStatus(ctx, resident, t, models.AuditActorSimulation)
+		} else {
+			err = s.history.MarkPrompted(ctx, nil, t.ID, now)
+			if err == nil && t.Status == models.ResidentStatusQuarantine {
+				due.AwaitingClearance, err = s.awaitingClearance(ctx, resident.ID)
+			}
+		}
+		if err != nil {
+			return processed, fmt.Errorf("resident %s: %w", resident.RegistryNumber, err)
+		}
+		processed = append(processed, due)
+	}
+
+	return processed, nil
+}
+
+// revertStatus returns a resident to ACTIVE and closes their open period, if
+// any.
+func (s *Service) revertStatus(ctx context.Context, resident *models.Resident, open *models.StatusTransition, actor models.AuditActorType) error {
+	previous := resident.Status
+	now := s.now()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	resident.Status = models.ResidentStatusActive
+	if err := s.residents.Update(ctx, tx, resident); err != nil {
+		return fmt.Errorf("updating resident: %w", err)
+	}
+	if open != nil {
+		if err := s.history.End(ctx, tx, open.ID, now); err != nil {
+			return err
+		}
+	}
+	if err := s.auditStatusChange(ctx, tx, resident.ID, previous, resident.Status, actor); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+// TransitionScheduler is the simulation hook that ends scheduled status
+// periods as the vault calendar passes them.
+type TransitionScheduler struct {
+	service *Service
+}
+
+// TransitionScheduler creates the status transition hook.
+func (s *Service) TransitionScheduler() *TransitionScheduler {
+	return &TransitionScheduler{service: s}
+}
+
+// Name implements simulation.Hook.
+func (h *TransitionScheduler) Name() string {
+	return "status transitions"
+}
+
+// Advance implements simulation.Hook.
+func (h *TransitionScheduler) Advance(ctx context.Context, from, to time.Time) ([]simulation.Event, error) {
+	processed, err := h.service.ProcessDueTransitions(ctx, to)
+
+	events := make([]simulation.Event, 0, len(processed))
+	for _, d := range processed {
+		level := simulation.EventInfo
+		if !d.Reverted {
+			level = simulation.EventWarning
+		}
+		events = append(events, simulation.Event{
+			Time:    to,
+			Level:   level,
+			Source:  h.Name(),
+			Message: d.String(),
+		})
+	}
+	return events, err
+}
@@ -0,0 +1,329 @@
+package resources
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// RegisterAsset registers an individually tracked asset in the vault's
+// registry. Serial numbers are unique across vaults.
+func (s *Service) RegisterAsset(ctx context.Context, input AssetInput) (_ *models.Asset, err error) {
+	ctx, cmd := s.begin(ctx, CommandRegisterAsset, input)
+	defer func() { cmd.End(err) }()
+
+	asset := &models.Asset{
+		ID:           s.idGenerator.NewID(),
+		SerialNumber: strings.ToUpper(strings.TrimSpace(input.SerialNumber)),
+		Name:         strings.TrimSpace(input.Name),
+		Category:     input.Category,
+		Condition:    input.Condition,
+		Location:     input.Location,
+		AcquiredAt:   input.AcquiredAt,
+		Notes:        input.Notes,
+	}
+	if asset.Condition == "" {
+		asset.Condition = models.AssetConditionGood
+	}
+	if err := s.assets.Create(ctx, nil, asset); err != nil {
+		return nil, err
+	}
+	return asset, nil
+}
+
+// CheckOutAsset issues an asset into an active resident's custody: a
+// check-out due back by a date, or without one a standing custodianship
+// assignment. The asset must be in store, in service and not broken.
+// Weapons are issued through the armory.
+func (s *Service) CheckOutAsset(ctx context.Context, input CheckOutInput) (_ *models.AssetCustody, err error) {
+	ctx, cmd := s.begin(ctx, CommandCheckOutAsset, input)
+	defer func() { cmd.End(err) }()
+
+	asset, err := s.PlanCheckOut(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	if asset.Category == models.AssetCategoryWeapon {
+		return nil, fmt.Errorf("%w: weapon %s is issued through the armory", repository.ErrValidation, asset.SerialNumber)
+	}
+	if err := s.ApplyCheckOut(ctx, nil, asset); err != nil {
+		return nil, err
+	}
+	return asset.Custody, nil
+}
+
+// PlanCheckOut checks that an asset can be issued as input asks and returns
+// it with the custody CheckOutAsset would open, for ApplyCheckOut within
+// another service's transaction.
+func (s *Service) PlanCheckOut(ctx context.Context, input CheckOutInput) (*models.Asset, error) {
+	asset, err := s.loadAsset(ctx, input.AssetID)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case asset.IsRetired():
+		return nil, fmt.Errorf("%w: asset %s is retired", repository.ErrValidation, asset.SerialNumber)
+	case asset.Custody != nil:
+		return nil, fmt.Errorf("%w: asset %s is in the custody of %s", repository.ErrValidation,
+			asset.SerialNumber, asset.Custody.Resident.RegistryNumber)
+	case !asset.Condition.Serviceable():
+		return nil, fmt.Errorf("%w: asset %s is %s", repository.ErrValidation, asset.SerialNumber, asset.Condition)
+	}
+
+	resident, err := s.residents.GetByID(ctx, input.ResidentID)
+	if err != nil {
+		return nil, fmt.Errorf("getting resident: %w", err)
+	}
+	if resident.Status != models.ResidentStatusActive {
+		return nil, fmt.Errorf("%w: resident %s is %s", repository.ErrValidation, resident.RegistryNumber, resident.Status)
+	}
+
+	asset.Custody = &models.AssetCustody{
+		ID:           s.idGenerator.NewID(),
+		AssetID:      asset.ID,
+		ResidentID:   resident.ID,
+		IssuedAt:     s.now().UTC().Truncate(time.Second),
+		IssuedBy:     input.IssuedBy,
+		DueAt:        input.DueAt,
+		ConditionOut: asset.Condition,
+		Notes:        input.Notes,
+		Resident:     resident,
+	}
+	return asset, nil
+}
+
+// ApplyCheckOut opens the custody of an asset PlanCheckOut returned, within
+// tx.
+func (s *Service) ApplyCheckOut(ctx context.Context, tx *sql.Tx, asset *models.Asset) error {
+	return s.assets.CreateCustody(ctx, tx, asset.Custody)
+}
+
+// CheckInAsset returns an asset from custody in the condition found. A
+// condition different from the one it went out in is recorded as the
+// asset's condition, with a report, in the same transaction. Weapons are
+// returned to the armory.
+func (s *Service) CheckInAsset(ctx context.Context, input CheckInInput) (_ *models.AssetCustody, err error) {
+	ctx, cmd := s.begin(ctx, CommandCheckInAsset, input)
+	defer func() { cmd.End(err) }()
+
+	asset, err := s.PlanCheckIn(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	if asset.Category == models.AssetCategoryWeapon {
+		return nil, fmt.Errorf("%w: weapon %s is returned to the armory", repository.ErrValidation, asset.SerialNumber)
+	}
+	err = repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		return s.ApplyCheckIn(ctx, tx, asset, input)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return asset.Custody, nil
+}
+
+// PlanCheckIn checks that an asset can be returned as input asks and returns
+// it with its custody closed as CheckInAsset would close it, for
+// ApplyCheckIn within another service's transaction.
+func (s *Service) PlanCheckIn(ctx context.Context, input CheckInInput) (*models.Asset, error) {
+	if !input.Condition.Valid() {
+		return nil, fmt.Errorf("%w: invalid condition %q", repository.ErrValidation, input.Condition)
+	}
+	asset, err := s.loadAsset(ctx, input.AssetID)
+	if err != nil {
+		return nil, err
+	}
+	custody := asset.Custody
+	if custody == nil {
+		return nil, fmt.Errorf("%w: asset %s is not in custody", repository.ErrValidation, asset.SerialNumber)
+	}
+
+	now := s.now().UTC().Truncate(time.Second)
+	custody.ReturnedAt = &now
+	custody.ReceivedBy = input.ReceivedBy
+	custody.ConditionIn = input.Condition
+	if input.Notes != "" {
+		custody.Notes = strings.TrimSpace(custody.Notes + " " + input.Notes)
+	}
+	return asset, nil
+}
+
+// ApplyCheckIn closes the custody of an asset PlanCheckIn returned and
+// records a change of its condition, within tx.
+func (s *Service) ApplyCheckIn(ctx context.Context, tx *sql.Tx, asset *models.Asset, input CheckInInput) error {
+	custody := asset.Custody
+	if err := s.assets.CloseCustody(ctx, tx, custody); err != nil {
+		return err
+	}
+	if input.Condition == asset.Condition {
+		return nil
+	}
+	_, err := s.setAssetCondition(ctx, tx, asset, input.Condition, input.ReceivedBy, &custody.ID, input.Notes, *custody.ReturnedAt)
+	return err
+}
+
+// RecordAssetCondition records an asset's condition found at an
+// inspection, whether in store or in custody. The report is kept even when
+// the condition is unchanged.
+func (s *Service) RecordAssetCondition(ctx context.Context, input AssetConditionInput) (_ *models.AssetConditionReport, err error) {
+	ctx, cmd := s.begin(ctx, CommandRecordAssetCondition, input)
+	defer func() { cmd.End(err) }()
+
+	if !input.Condition.Valid() {
+		return nil, fmt.Errorf("%w: invalid condition %q", repository.ErrValidation, input.Condition)
+	}
+	asset, err := s.assets.GetByID(ctx, input.AssetID)
+	if err != nil {
+		return nil, err
+	}
+	if asset.IsRetired() {
+		return nil, fmt.Errorf("%w: asset %s is retired", repository.ErrValidation, asset.SerialNumber)
+	}
+
+	var report *models.AssetConditionReport
+	err = repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		var err error
+		report, err = s.setAssetCondition(ctx, tx, asset, input.Condition, input.RecordedBy, nil, input.Notes,
+			s.now().UTC().Truncate(time.Second))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// RetireAsset writes an asset off the registry, e.g. when it is lost or
+// beyond repair. It must be checked in first; its history is kept.
+func (s *Service) RetireAsset(ctx context.Context, assetID, reason string) (err error) {
+	ctx, cmd := s.begin(ctx, CommandRetireAsset, retireAssetArgs{assetID, reason})
+	defer func() { cmd.End(err) }()
+
+	asset, err := s.loadAsset(ctx, assetID)
+	if err != nil {
+		return err
+	}
+	if asset.IsRetired() {
+		return fmt.Errorf("%w: asset %s was retired %s", repository.ErrValidation,
+			asset.SerialNumber, util.FormatDay(*asset.RetiredAt))
+	}
+	if asset.Custody != nil {
+		return fmt.Errorf("%w: asset %s is in the custody of %s; check it in first", repository.ErrValidation,
+			asset.SerialNumber, asset.Custody.Resident.RegistryNumber)
+	}
+
+	now := s.now().UTC().Truncate(time.Second)
+	asset.RetiredAt = &now
+	if reason != "" {
+		asset.Notes = strings.TrimSpace(asset.Notes + " Retired: " + reason)
+	}
+	return s.assets.Update(ctx, nil, asset)
+}
+
+// GetAsset retrieves an asset with its open custody.
+func (s *Service) GetAsset(ctx context.Context, id string) (*models.Asset, error) {
+	return s.loadAsset(ctx, id)
+}
+
+// GetAssetBySerial retrieves an asset by serial number, in any case, with
+// its open custody.
+func (s *Service) GetAssetBySerial(ctx context.Context, serial string) (*models.Asset, error) {
+	asset, err := s.assets.GetBySerial(ctx, strings.ToUpper(strings.TrimSpace(serial)))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.fillCustody(ctx, asset); err != nil {
+		return nil, err
+	}
+	return asset, nil
+}
+
+// ListAssets retrieves the vault's assets matching the filter, ordered by
+// category and serial number, with their open custody.
+func (s *Service) ListAssets(ctx context.Context, filter models.AssetFilter) ([]*models.Asset, error) {
+	assets, err := s.assets.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	open, err := s.assets.ListOpenCustody(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byAsset := make(map[string]*models.AssetCustody, len(open))
+	for _, c := range open {
+		byAsset[c.AssetID] = c
+	}
+	for _, a := range assets {
+		a.Custody = byAsset[a.ID]
+	}
+	return assets, nil
+}
+
+// ListAssetCustody retrieves an asset's custody history, most recent
+// first.
+func (s *Service) ListAssetCustody(ctx context.Context, assetID string) ([]*models.AssetCustody, error) {
+	return s.assets.ListCustody(ctx, assetID)
+}
+
+// ListAssetConditions retrieves an asset's condition reports, most recent
+// first.
+func (s *Service) ListAssetConditions(ctx context.Context, assetID string) ([]*models.AssetConditionReport, error) {
+	return s.assets.ListConditionReports(ctx, assetID)
+}
+
+// loadAsset retrieves an asset with its open custody.
+func (s *Service) loadAsset(ctx context.Context, id string) (*models.Asset, error) {
+	asset, err := s.assets.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.fillCustody(ctx, asset); err != nil {
+		return nil, err
+	}
+	return asset, nil
+}
+
+// fillCustody loads an asset's open custody, leaving it nil when the asset
+// is in store.
+func (s *Service) fillCustody(ctx context.Context, asset *models.Asset) error {
+	custody, err := s.assets.GetOpenCustody(ctx, asset.ID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	asset.Custody = custody
+	return nil
+}
+
+// setAssetCondition records an asset's condition with a report of the
+// change from its previous one.
+func (s *Service) setAssetCondition(ctx context.Context, tx *sql.Tx, asset *models.Asset, condition models.AssetCondition,
+	recordedBy, custodyID *string, notes string, at time.Time) (*models.AssetConditionReport, error) {
+	report := &models.AssetConditionReport{
+		ID:         s.idGenerator.NewID(),
+		AssetID:    asset.ID,
+		Condition:  condition,
+		Previous:   asset.Condition,
+		RecordedAt: at,
+		RecordedBy: recordedBy,
+		CustodyID:  custodyID,
+		Notes:      notes,
+	}
+	if err := s.assets.CreateConditionReport(ctx, tx, report); err != nil {
+		return nil, err
+	}
+	asset.Condition = condition
+	if err := s.assets.Update(ctx, tx, asset); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
@@ -0,0 +1,175 @@
+package resources
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+)
+
+// auditedStatuses are the lot statuses an inventory audit counts. Depleted
+// and expired lots are off the books.
+var auditedStatuses = map[models.StockStatus]bool{
+	models.StockStatusAvailable:  true,
+	models.StockStatusReserved:   true,
+	models.StockStatusQuarantine: true,
+	models.StockStatusDamaged:    true,
+}
+
+// OpenAudit opens an audit session for a storage location, freezing the book
+// quantity of every lot stored there. A location can have one open audit.
+func (s *Service) OpenAudit(ctx context.Context, location string, openedBy *string) (_ *models.InventoryAudit, err error) {
+	ctx, cmd := s.begin(ctx, CommandOpenAudit, openAuditArgs{location, openedBy})
+	defer func() { cmd.End(err) }()
+
+	if location == "" {
+		return nil, fmt.Errorf("%w: storage location is required", repository.ErrValidation)
+	}
+
+	stocks, err := s.resources.ListAllStocks(ctx, nil, models.StockFilter{StorageLocation: location}, models.SortOption{})
+	if err != nil {
+		return nil, fmt.Errorf("listing stocks: %w", err)
+	}
+
+	audit := &models.InventoryAudit{
+		ID:              s.idGenerator.NewID(),
+		StorageLocation: location,
+		Status:          models.InventoryAuditStatusOpen,
+		OpenedBy:        openedBy,
+		OpenedAt:        s.now(),
+	}
+	for _, stock := range stocks {
+		if !auditedStatuses[stock.Status] {
+			continue
+		}
+		audit.Counts = append(audit.Counts, models.InventoryAuditCount{
+			StockID:          stock.ID,
+			ExpectedQuantity: stock.Quantity,
+		})
+	}
+	if len(audit.Counts) == 0 {
+		return nil, fmt.Errorf("%w: no stock stored at %s", repository.ErrValidation, location)
+	}
+
+	if err := s.audits.Create(ctx, nil, audit); err != nil {
+		return nil, fmt.Errorf("opening audit: %w", err)
+	}
+	return s.audits.GetByID(ctx, audit.ID)
+}
+
+// GetAudit retrieves an audit with its counts.
+func (s *Service) GetAudit(ctx context.Context, id string) (*models.InventoryAudit, error) {
+	return s.audits.GetByID(ctx, id)
+}
+
+// ListAudits retrieves audits, most recent first, optionally with one status.
+func (s *Service) ListAudits(ctx context.Context, status *models.InventoryAuditStatus) ([]*models.InventoryAudit, error) {
+	return s.audits.List(ctx, status)
+}
+
+// RecordAuditCount records the counted quantity of a lot, given by stock ID
+// or lot number, in an open audit. Recounting replaces the earlier count.
+func (s *Service) RecordAuditCount(ctx context.Context, auditID, lot string, counted float64) (_ *models.InventoryAuditCount, err error) {
+	ctx, cmd := s.begin(ctx, CommandRecordAuditCount, auditCountArgs{auditID, lot, counted})
+	defer func() { cmd.End(err) }()
+
+	if counted < 0 {
+		return nil, fmt.Errorf("%w: counted quantity cannot be negative", repository.ErrValidation)
+	}
+	audit, err := s.openAudit(ctx, auditID)
+	if err != nil {
+		return nil, err
+	}
+	count := audit.Count(lot)
+	if count == nil {
+		return nil, fmt.Errorf("lot %w in audit of %s: %s", repository.ErrNotFound, audit.StorageLocation, lot)
+	}
+
+	now := s.now()
+	if err := s.audits.RecordCount(ctx, nil, audit.ID, count.StockID, counted, now); err != nil {
+		return nil, err
+	}
+	count.CountedQuantity = &counted
+	count.CountedAt = &now
+	return count, nil
+}
+
+// CloseAudit closes an audit, setting every counted lot to its count with an
+// AUDIT_CORRECTION transaction for each difference. The correction is taken
+// against the lot's current quantity, so stock moved during the audit is not
+// double-counted. Uncounted lots are left as they are. Every correction
+// commits together with the closing, or none do, e.g. when a count falls
+// below a lot's reserved quantity. Counts written down are authorized as a
+// stock write-off, like any bulk adjustment.
+func (s *Service) CloseAudit(ctx context.Context, auditID string, closedBy *string) (_ *models.InventoryAudit, err error) {
+	ctx, cmd := s.begin(ctx, CommandCloseAudit, closeAuditArgs{auditID, closedBy})
+	defer func() { cmd.End(err) }()
+
+	audit, err := s.openAudit(ctx, auditID)
+	if err != nil {
+		return nil, err
+	}
+
+	reason := fmt.Sprintf("Inventory audit of %s", audit.StorageLocation)
+	var adjustments []BulkAdjustment
+	for i := range audit.Counts {
+		c := &audit.Counts[i]
+		if !c.IsCounted() {
+			continue
+		}
+		adjustments = append(adjustments, BulkAdjustment{Lot: c.StockID, Counted: c.CountedQuantity, Reason: reason})
+	}
+	now := s.now()
+	audit.Status = models.InventoryAuditStatusClosed
+	audit.ClosedAt = &now
+	audit.ClosedBy = closedBy
+
+	err = repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		plan, err := s.planBulk(ctx, tx, adjustments, closedBy)
+		if err != nil {
+			return err
+		}
+		if len(plan.result.Errors) > 0 {
+			e := plan.result.Errors[0]
+			return fmt.Errorf("lot %s: %w", e.Lot, e.Err)
+		}
+		if err := s.applyBulk(ctx, tx, plan, closedBy); err != nil {
+			return err
+		}
+		return s.audits.UpdateStatus(ctx, tx, audit)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return audit, nil
+}
+
+// CancelAudit abandons an open audit without changing any stock.
+func (s *Service) CancelAudit(ctx context.Context, auditID string) (err error) {
+	ctx, cmd := s.begin(ctx, CommandCancelAudit, idArgs{auditID})
+	defer func() { cmd.End(err) }()
+
+	audit, err := s.openAudit(ctx, auditID)
+	if err != nil {
+		return err
+	}
+	now := s.now()
+	audit.Status = models.InventoryAuditStatusCancelled
+	audit.ClosedAt = &now
+	return s.audits.UpdateStatus(ctx, nil, audit)
+}
+
+// openAudit retrieves an audit that is still open.
+func (s *Service) openAudit(ctx context.Context, id string) (*models.InventoryAudit, error) {
+	audit, err := s.audits.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !audit.IsOpen() {
+		return nil, fmt.Errorf("%w: audit %s is %s", repository.ErrValidation, id, audit.Status)
+	}
+	return audit, nil
+}
@@ -0,0 +1,128 @@
+package resources
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+)
+
+// settableStatuses are the lot statuses an operator may set, and set from.
+// Expired and depleted lots are marked by the expiry sweep and consumption,
+// and stay off the books. A lot is marked damaged one at a time, from its
+// detail view.
+var settableStatuses = map[models.StockStatus]bool{
+	models.StockStatusAvailable:  true,
+	models.StockStatusReserved:   true,
+	models.StockStatusQuarantine: true,
+}
+
+// MoveStockBatch moves lots to a storage location in one transaction,
+// recording a transfer for each lot not already there.
+func (s *Service) MoveStockBatch(ctx context.Context, stockIDs []string, location string, authorizedBy *string) (err error) {
+	ctx, cmd := s.begin(ctx, CommandMoveStockBatch, moveStockBatchArgs{stockIDs, location, authorizedBy})
+	defer func() { cmd.End(err) }()
+
+	if location == "" {
+		return fmt.Errorf("%w: storage location is required", repository.ErrValidation)
+	}
+	return repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		stocks, err := s.batchStocks(ctx, tx, stockIDs)
+		if err != nil {
+			return err
+		}
+		for _, stock := range stocks {
+			if err := s.moveStock(ctx, tx, stock, location, authorizedBy); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SetStockStatusBatch sets the status of lots in one transaction, e.g. to
+// quarantine a suspect delivery or release it again. Each change is recorded
+// as a zero-quantity adjustment.
+func (s *Service) SetStockStatusBatch(ctx context.Context, stockIDs []string, status models.StockStatus, authorizedBy *string) (err error) {
+	ctx, cmd := s.begin(ctx, CommandSetStockStatusBatch, stockStatusBatchArgs{stockIDs, status, authorizedBy})
+	defer func() { cmd.End(err) }()
+
+	if !settableStatuses[status] {
+		return fmt.Errorf("%w: stock status %q cannot be set directly", repository.ErrValidation, status)
+	}
+	return repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		stocks, err := s.batchStocks(ctx, tx, stockIDs)
+		if err != nil {
+			return err
+		}
+		for _, stock := range stocks {
+			if !settableStatuses[stock.Status] {
+				return fmt.Errorf("%w: lot %s is %s", repository.ErrValidation, lotName(stock), stock.Status)
+			}
+		}
+		for _, stock := range stocks {
+			if stock.Status == status {
+				continue
+			}
+			if err := s.setStockStatus(ctx, tx, stock, status, "", authorizedBy); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// setStockStatus sets the status of a loaded lot within tx, recording the
+// change as a zero-quantity adjustment with the note, if any, in its reason.
+func (s *Service) setStockStatus(ctx context.Context, tx *sql.Tx, stock *models.ResourceStock, status models.StockStatus, note string, authorizedBy *string) error {
+	reason := fmt.Sprintf("Status %s to %s", stock.Status, status)
+	if note != "" {
+		reason += ": " + note
+	}
+	stock.Status = status
+	txn := &models.ResourceTransaction{
+		ID:              s.idGenerator.NewID(),
+		StockID:         &stock.ID,
+		ItemID:          stock.ItemID,
+		TransactionType: models.TransactionTypeAdjustment,
+		Quantity:        0,
+		BalanceAfter:    stock.Quantity,
+		Reason:          reason,
+		AuthorizedBy:    authorizedBy,
+		Timestamp:       s.now(),
+	}
+	if err := s.resources.UpdateStock(ctx, tx, stock); err != nil {
+		return fmt.Errorf("updating stock: %w", err)
+	}
+	if err := s.resources.CreateTransaction(ctx, tx, txn); err != nil {
+		return fmt.Errorf("recording transaction: %w", err)
+	}
+	return nil
+}
+
+// batchStocks retrieves the lots of a batch operation within tx, failing on
+// the first missing one so the batch runs on all of them or none.
+func (s *Service) batchStocks(ctx context.Context, tx *sql.Tx, stockIDs []string) ([]*models.ResourceStock, error) {
+	if len(stockIDs) == 0 {
+		return nil, fmt.Errorf("%w: no stock selected", repository.ErrValidation)
+	}
+	stocks := make([]*models.ResourceStock, 0, len(stockIDs))
+	for _, id := range stockIDs {
+		stock, err := s.resources.GetStock(ctx, tx, id)
+		if err != nil {
+			return nil, fmt.Errorf("getting stock %s: %w", id, err)
+		}
+		stocks = append(stocks, stock)
+	}
+	return stocks, nil
+}
+
+// lotName returns the lot number of a stock, or its ID when it has none.
+func lotName(stock *models.ResourceStock) string {
+	if stock.LotNumber != nil {
+		return *stock.LotNumber
+	}
+	return stock.ID
+}
@@ -0,0 +1,353 @@
+package resources
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/vtuos/vtuos/internal/dualauth"
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+)
+
+// MaxBulkAdjustments is the most lines one bulk adjustment takes, keeping
+// its transaction and the journal entry recording it bounded. A larger
+// count is split into several.
+const MaxBulkAdjustments = 500
+
+// maxReasonLength is the longest reason a bulk adjustment line may give.
+const maxReasonLength = 200
+
+// bulkTypes are the transaction types a bulk adjustment records.
+// Consumption, production and transfers have operations of their own.
+var bulkTypes = map[models.TransactionType]bool{
+	models.TransactionTypeAdjustment:      true,
+	models.TransactionTypeSpoilage:        true,
+	models.TransactionTypeAuditCorrection: true,
+}
+
+// Count import CSV columns. The header row is required; column order is
+// free.
+const (
+	CountColLot     = "lot"     // Stock ID or lot number
+	CountColCounted = "counted" // Quantity found
+	CountColReason  = "reason"  // Optional
+)
+
+// bulkLine is a validated line of a bulk adjustment.
+type bulkLine struct {
+	stock      *models.ResourceStock
+	counted    bool
+	adjustment StockAdjustment
+}
+
+// bulkPlan is a bulk adjustment checked against the lots' quantities,
+// ready to apply.
+type bulkPlan struct {
+	lines  []bulkLine
+	result BulkAdjustResult
+}
+
+// BulkAdjustStocks validates a batch of adjustments and applies the valid
+// lines in one transaction. A line naming no known lot, without a reason,
+// or taking its lot below zero or into its reserved units is reported in
+// the result's errors and not applied. Lines on the same lot apply in
+// order. A count stamps its lot's audit date even when it changes nothing.
+// The units written off by the batch are authorized as one stock write-off.
+func (s *Service) BulkAdjustStocks(ctx context.Context, input BulkAdjustInput) (_ *BulkAdjustResult, err error) {
+	ctx, cmd := s.begin(ctx, CommandBulkAdjustStocks, input)
+	defer func() { cmd.End(err) }()
+
+	if len(input.Adjustments) == 0 {
+		return nil, fmt.Errorf("%w: no adjustments given", repository.ErrValidation)
+	}
+	if len(input.Adjustments) > MaxBulkAdjustments {
+		return nil, fmt.Errorf("%w: %d adjustments given, at most %d at once",
+			repository.ErrValidation, len(input.Adjustments), MaxBulkAdjustments)
+	}
+
+	var plan *bulkPlan
+	err = repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		var err error
+		if plan, err = s.planBulk(ctx, tx, input.Adjustments, input.AuthorizedBy); err != nil {
+			return err
+		}
+		return s.applyBulk(ctx, tx, plan, input.AuthorizedBy)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &plan.result, nil
+}
+
+// ImportCounts reads physical counts in CSV form and sets each lot to its
+// count with BulkAdjustStocks. Rows that cannot be read are reported with
+// the rejected lines, by their line in the file, and the rest applied.
+func (s *Service) ImportCounts(ctx context.Context, r io.Reader, authorizedBy *string) (*BulkAdjustResult, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading count file: %w", err)
+	}
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("count file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{CountColLot, CountColCounted} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("missing required column: %s", name)
+		}
+	}
+
+	var rowErrors []BulkAdjustError
+	var adjustments []BulkAdjustment
+	var lines []int // File line of each adjustment
+	line := 1
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			rowErrors = append(rowErrors, BulkAdjustError{Line: line, Err: err})
+			continue
+		}
+		field := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+		lot := field(CountColLot)
+		if lot == "" && field(CountColCounted) == "" {
+			continue
+		}
+		counted, err := strconv.ParseFloat(field(CountColCounted), 64)
+		if err != nil || math.IsNaN(counted) || math.IsInf(counted, 0) {
+			rowErrors = append(rowErrors, BulkAdjustError{Line: line, Lot: lot,
+				Err: fmt.Errorf("invalid counted: %q", field(CountColCounted))})
+			continue
+		}
+		reason := field(CountColReason)
+		if reason == "" {
+			reason = "Physical count"
+		}
+		adjustments = append(adjustments, BulkAdjustment{Lot: lot, Counted: &counted, Reason: reason})
+		lines = append(lines, line)
+	}
+
+	result := &BulkAdjustResult{}
+	if len(adjustments) > 0 {
+		result, err = s.BulkAdjustStocks(ctx, BulkAdjustInput{Adjustments: adjustments, AuthorizedBy: authorizedBy})
+		if err != nil {
+			return nil, err
+		}
+		for i := range result.Errors {
+			result.Errors[i].Line = lines[result.Errors[i].Line-1]
+		}
+	}
+	result.Errors = append(result.Errors, rowErrors...)
+	sort.Slice(result.Errors, func(i, j int) bool { return result.Errors[i].Line < result.Errors[j].Line })
+	return result, nil
+}
+
+// planBulk checks adjustments against the quantities of their lots as read
+// within tx, in order, sorting them into lines to apply and rejected lines.
+// The plan must be applied in the same transaction.
+func (s *Service) planBulk(ctx context.Context, tx *sql.Tx, adjustments []BulkAdjustment, authorizedBy *string) (*bulkPlan, error) {
+	plan := &bulkPlan{}
+	stocks := make(map[string]*models.ResourceStock) // By ID and by lot as given
+	balance := make(map[string]float64)              // Quantity of each lot so far, by ID
+
+	for i, a := range adjustments {
+		reject := func(lot string, err error) {
+			plan.result.Errors = append(plan.result.Errors, BulkAdjustError{Line: i + 1, Lot: lot, Err: err})
+		}
+
+		stock, ok := stocks[a.Lot]
+		if !ok {
+			var err error
+			stock, err = s.findLot(ctx, tx, a.Lot)
+			if errors.Is(err, repository.ErrNotFound) || errors.Is(err, repository.ErrValidation) {
+				reject(a.Lot, err)
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			if known, ok := stocks[stock.ID]; ok {
+				stock = known
+			} else {
+				stocks[stock.ID] = stock
+				balance[stock.ID] = stock.Quantity
+			}
+			stocks[a.Lot] = stock
+		}
+		lot := lotName(stock)
+
+		reason := strings.TrimSpace(a.Reason)
+		if reason == "" {
+			reject(lot, fmt.Errorf("%w: a reason is required", repository.ErrValidation))
+			continue
+		}
+		if len(reason) > maxReasonLength {
+			reject(lot, fmt.Errorf("%w: reason is longer than %d characters", repository.ErrValidation, maxReasonLength))
+			continue
+		}
+
+		change := a.QuantityChange
+		txnType := a.Type
+		if a.Counted != nil {
+			if a.QuantityChange != 0 {
+				reject(lot, fmt.Errorf("%w: give a change or a count, not both", repository.ErrValidation))
+				continue
+			}
+			if *a.Counted < 0 {
+				reject(lot, fmt.Errorf("%w: counted quantity cannot be negative", repository.ErrValidation))
+				continue
+			}
+			change = *a.Counted - balance[stock.ID]
+			if txnType == "" {
+				txnType = models.TransactionTypeAuditCorrection
+			}
+		} else if txnType == "" {
+			txnType = models.TransactionTypeAdjustment
+		}
+		if !bulkTypes[txnType] {
+			reject(lot, fmt.Errorf("%w: %s cannot be recorded as an adjustment", repository.ErrValidation, txnType))
+			continue
+		}
+
+		after := balance[stock.ID] + change
+		if after < -models.QuantityEpsilon {
+			reject(lot, fmt.Errorf("%w: adjustment would leave %.2f units", repository.ErrValidation, after))
+			continue
+		}
+		if change < 0 && after < stock.QuantityReserved-models.QuantityEpsilon {
+			reject(lot, fmt.Errorf("%w: would leave %.2f, below its %.2f reserved units",
+				repository.ErrValidation, after, stock.QuantityReserved))
+			continue
+		}
+
+		if math.Abs(change) <= models.QuantityEpsilon {
+			plan.result.Skipped++
+			if a.Counted == nil {
+				continue
+			}
+			change = 0
+		} else {
+			plan.result.Applied++
+			balance[stock.ID] = after
+		}
+		plan.lines = append(plan.lines, bulkLine{
+			stock:   stock,
+			counted: a.Counted != nil,
+			adjustment: StockAdjustment{
+				QuantityChange: change,
+				Type:           txnType,
+				Reason:         reason,
+				AuthorizedBy:   authorizedBy,
+			},
+		})
+	}
+	return plan, nil
+}
+
+// applyBulk applies the lines of a plan within the transaction it was
+// planned in, authorizing the units they write off.
+func (s *Service) applyBulk(ctx context.Context, tx *sql.Tx, plan *bulkPlan, authorizedBy *string) error {
+	writtenOff := make(map[string]float64)
+	var total float64
+	var order []*models.ResourceStock
+	for _, l := range plan.lines {
+		if l.adjustment.QuantityChange >= 0 {
+			continue
+		}
+		if _, ok := writtenOff[l.stock.ID]; !ok {
+			order = append(order, l.stock)
+		}
+		writtenOff[l.stock.ID] -= l.adjustment.QuantityChange
+		total -= l.adjustment.QuantityChange
+	}
+	var auth *dualauth.Authorization
+	if total > 0 {
+		var err error
+		if auth, err = s.authorizer.Authorize(ctx, tx, models.DualAuthStockWriteOff, total); err != nil {
+			return err
+		}
+	}
+
+	now := s.now()
+	for _, l := range plan.lines {
+		if l.counted {
+			l.stock.LastAuditDate = &now
+			l.stock.LastAuditBy = authorizedBy
+		}
+		if l.adjustment.QuantityChange == 0 {
+			if err := s.resources.UpdateStock(ctx, tx, l.stock); err != nil {
+				return fmt.Errorf("updating stock %s: %w", lotName(l.stock), err)
+			}
+			continue
+		}
+		if err := s.adjustStock(ctx, tx, l.stock, l.adjustment); err != nil {
+			return fmt.Errorf("lot %s: %w", lotName(l.stock), err)
+		}
+	}
+	if auth != nil {
+		for _, stock := range order {
+			recorded := *auth
+			recorded.Amount = writtenOff[stock.ID]
+			if err := s.authorizer.Record(ctx, tx, &recorded, models.AuditEntityStock, stock.ID, now); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// findLot retrieves a stock within tx by ID or, failing that, by lot
+// number. A lot number shared by several stocks must be given by stock ID
+// instead.
+func (s *Service) findLot(ctx context.Context, tx *sql.Tx, lot string) (*models.ResourceStock, error) {
+	lot = strings.TrimSpace(lot)
+	if lot == "" {
+		return nil, fmt.Errorf("%w: lot is required", repository.ErrValidation)
+	}
+	stock, err := s.resources.GetStock(ctx, tx, lot)
+	if !errors.Is(err, repository.ErrNotFound) {
+		return stock, err
+	}
+	stocks, err := s.resources.GetStocksByLot(ctx, tx, lot)
+	if err != nil {
+		return nil, err
+	}
+	switch len(stocks) {
+	case 0:
+		return nil, fmt.Errorf("lot %w: %s", repository.ErrNotFound, lot)
+	case 1:
+		return stocks[0], nil
+	default:
+		return nil, fmt.Errorf("%w: %d stocks have lot number %s; give the stock ID",
+			repository.ErrValidation, len(stocks), lot)
+	}
+}
@@ -0,0 +1,105 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/vtuos/vtuos/internal/repository"
+)
+
+func TestBulkAdjustStocks_Lines(t *testing.T) {
+	svc, db := setupService(t)
+	ctx := context.Background()
+	stock := createStock(t, svc, 10)
+	two := 2.0
+
+	result, err := svc.BulkAdjustStocks(ctx, BulkAdjustInput{Adjustments: []BulkAdjustment{
+		{Lot: "LOT-MISSING", QuantityChange: -1, Reason: "Test"},
+		{Lot: stock.ID, QuantityChange: -4, Reason: "Test"},
+		{Lot: stock.ID, QuantityChange: -3, Reason: "Test"}, // Applies after the line above
+		{Lot: stock.ID, QuantityChange: -5, Reason: "Test"}, // Would leave -2
+		{Lot: stock.ID, Counted: &two, Reason: "Test"},
+	}})
+	if err != nil {
+		t.Fatalf("BulkAdjustStocks() error = %v", err)
+	}
+	if result.Applied != 3 || result.Skipped != 0 {
+		t.Errorf("applied %d and skipped %d, want 3 applied", result.Applied, result.Skipped)
+	}
+	if len(result.Errors) != 2 {
+		t.Fatalf("errors = %v, want the unknown lot and the negative result", result.Errors)
+	}
+	for i, want := range []struct {
+		line int
+		err  error
+	}{{1, repository.ErrNotFound}, {4, repository.ErrValidation}} {
+		if e := result.Errors[i]; e.Line != want.line || !errors.Is(e.Err, want.err) {
+			t.Errorf("error %d = %v, want line %d with %v", i, e, want.line, want.err)
+		}
+	}
+
+	got, err := svc.GetStock(ctx, stock.ID)
+	if err != nil {
+		t.Fatalf("GetStock() error = %v", err)
+	}
+	if got.Quantity != 2 {
+		t.Errorf("quantity = %.0f, want 2", got.Quantity)
+	}
+	if got.LastAuditDate == nil {
+		t.Error("counted lot has no audit date")
+	}
+	if n := countRows(t, db, `SELECT COUNT(*) FROM resource_transactions`); n != 3 {
+		t.Errorf("transactions = %d, want one for each applied line", n)
+	}
+}
+
+func TestBulkAdjustStocks_Concurrent(t *testing.T) {
+	svc, _ := setupService(t)
+	ctx := context.Background()
+	stock := createStock(t, svc, 100)
+
+	// Each batch takes 1 unit at a time; only 100 lines can apply in all
+	const batches, lines = 20, 10
+	var wg sync.WaitGroup
+	results := make(chan *BulkAdjustResult, batches)
+	errs := make(chan error, batches)
+	for range batches {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			adjustments := make([]BulkAdjustment, lines)
+			for i := range adjustments {
+				adjustments[i] = BulkAdjustment{Lot: stock.ID, QuantityChange: -1, Reason: "Concurrent count"}
+			}
+			result, err := svc.BulkAdjustStocks(ctx, BulkAdjustInput{Adjustments: adjustments})
+			if err != nil {
+				errs <- err
+				return
+			}
+			results <- result
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	close(results)
+	for err := range errs {
+		t.Fatalf("BulkAdjustStocks() error = %v", err)
+	}
+
+	var applied int
+	for result := range results {
+		applied += result.Applied
+	}
+	if applied != 100 {
+		t.Errorf("lines applied = %d, want 100", applied)
+	}
+	got, err := svc.GetStock(ctx, stock.ID)
+	if err != nil {
+		t.Fatalf("GetStock() error = %v", err)
+	}
+	if got.Quantity != 0 {
+		t.Errorf("quantity = %.0f, want 0", got.Quantity)
+	}
+}
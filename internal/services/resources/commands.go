@@ -0,0 +1,262 @@
+package resources
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/journal"
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// Journaled resource commands.
+const (
+	CommandCreateCategory       = "resources.create_category"
+	CommandUpdateCategory       = "resources.update_category"
+	CommandCreateItem           = "resources.create_item"
+	CommandUpdateItem           = "resources.update_item"
+	CommandCreateStock          = "resources.create_stock"
+	CommandAdjustStock          = "resources.adjust_stock"
+	CommandMoveStock            = "resources.move_stock"
+	CommandRecordConsumption    = "resources.record_consumption"
+	CommandRecordProduction     = "resources.record_production"
+	CommandProcessExpiredItems  = "resources.process_expired_items"
+	CommandInventoryAudit       = "resources.inventory_audit"
+	CommandDeductDailyRations   = "resources.deduct_daily_rations"
+	CommandReserveStock         = "resources.reserve_stock"
+	CommandReleaseReservation   = "resources.release_reservation"
+	CommandCommitReservation    = "resources.commit_reservation"
+	CommandExpireReservations   = "resources.expire_reservations"
+	CommandOpenAudit            = "resources.open_audit"
+	CommandRecordAuditCount     = "resources.record_audit_count"
+	CommandCloseAudit           = "resources.close_audit"
+	CommandCancelAudit          = "resources.cancel_audit"
+	CommandMoveStockBatch       = "resources.move_stock_batch"
+	CommandSetStockStatusBatch  = "resources.set_stock_status_batch"
+	CommandCheckShortages       = "resources.check_shortages"
+	CommandSplitStock           = "resources.split_stock"
+	CommandSetStockStatus       = "resources.set_stock_status"
+	CommandRecordQualityTest    = "resources.record_quality_test"
+	CommandRegisterAsset        = "resources.register_asset"
+	CommandCheckOutAsset        = "resources.check_out_asset"
+	CommandCheckInAsset         = "resources.check_in_asset"
+	CommandRecordAssetCondition = "resources.record_asset_condition"
+	CommandRetireAsset          = "resources.retire_asset"
+	CommandLoseStock            = "resources.lose_stock"
+	CommandBulkAdjustStocks     = "resources.bulk_adjust_stocks"
+	CommandImportDelivery       = "resources.import_delivery"
+)
+
+// Arguments of journaled commands that take more than an input.
+type (
+	idArgs struct {
+		ID string `json:"id"`
+	}
+	updateCategoryArgs struct {
+		ID    string              `json:"id"`
+		Input UpdateCategoryInput `json:"input"`
+	}
+	updateItemArgs struct {
+		ID    string          `json:"id"`
+		Input UpdateItemInput `json:"input"`
+	}
+	adjustStockArgs struct {
+		StockID    string          `json:"stock_id"`
+		Adjustment StockAdjustment `json:"adjustment"`
+	}
+	moveStockArgs struct {
+		StockID      string  `json:"stock_id"`
+		Location     string  `json:"location"`
+		AuthorizedBy *string `json:"authorized_by"`
+	}
+	timeArgs struct {
+		At time.Time `json:"at"`
+	}
+	inventoryAuditArgs struct {
+		StockID   string  `json:"stock_id"`
+		ActualQty float64 `json:"actual_qty"`
+		AuditorID string  `json:"auditor_id"`
+	}
+	commitReservationArgs struct {
+		ID           string  `json:"id"`
+		AuthorizedBy *string `json:"authorized_by"`
+	}
+	openAuditArgs struct {
+		Location string  `json:"location"`
+		OpenedBy *string `json:"opened_by"`
+	}
+	auditCountArgs struct {
+		AuditID string  `json:"audit_id"`
+		Lot     string  `json:"lot"`
+		Counted float64 `json:"counted"`
+	}
+	closeAuditArgs struct {
+		AuditID  string  `json:"audit_id"`
+		ClosedBy *string `json:"closed_by"`
+	}
+	moveStockBatchArgs struct {
+		StockIDs     []string `json:"stock_ids"`
+		Location     string   `json:"location"`
+		AuthorizedBy *string  `json:"authorized_by"`
+	}
+	stockStatusBatchArgs struct {
+		StockIDs     []string           `json:"stock_ids"`
+		Status       models.StockStatus `json:"status"`
+		AuthorizedBy *string            `json:"authorized_by"`
+	}
+	retireAssetArgs struct {
+		AssetID string `json:"asset_id"`
+		Reason  string `json:"reason"`
+	}
+	deliveryArgs struct {
+		CSV     string          `json:"csv"`
+		Options DeliveryOptions `json:"options"`
+	}
+	loseStockArgs struct {
+		ItemCode string  `json:"item_code"`
+		Percent  float64 `json:"percent"`
+		Reason   string  `json:"reason"`
+	}
+)
+
+// SetJournal records the commands the service runs in j.
+func (s *Service) SetJournal(j *journal.Journal) {
+	s.journal = j
+}
+
+// begin starts journaling a command of the service.
+func (s *Service) begin(ctx context.Context, command string, args any) (context.Context, *journal.Command) {
+	return s.journal.Begin(ctx, s.vault, command, args)
+}
+
+// Commands returns the handlers that replay the service's journaled
+// commands.
+func (s *Service) Commands() journal.Handlers {
+	return journal.Handlers{
+		CommandCreateCategory: journal.Handle(func(ctx context.Context, input CreateCategoryInput) error {
+			_, err := s.CreateCategory(ctx, input)
+			return err
+		}),
+		CommandUpdateCategory: journal.Handle(func(ctx context.Context, args updateCategoryArgs) error {
+			_, err := s.UpdateCategory(ctx, args.ID, args.Input)
+			return err
+		}),
+		CommandCreateItem: journal.Handle(func(ctx context.Context, input CreateItemInput) error {
+			_, err := s.CreateItem(ctx, input)
+			return err
+		}),
+		CommandUpdateItem: journal.Handle(func(ctx context.Context, args updateItemArgs) error {
+			_, err := s.UpdateItem(ctx, args.ID, args.Input)
+			return err
+		}),
+		CommandCreateStock: journal.Handle(func(ctx context.Context, input CreateStockInput) error {
+			_, err := s.CreateStock(ctx, input)
+			return err
+		}),
+		CommandAdjustStock: journal.Handle(func(ctx context.Context, args adjustStockArgs) error {
+			return s.AdjustStock(ctx, args.StockID, args.Adjustment)
+		}),
+		CommandMoveStock: journal.Handle(func(ctx context.Context, args moveStockArgs) error {
+			return s.MoveStock(ctx, args.StockID, args.Location, args.AuthorizedBy)
+		}),
+		CommandRecordConsumption: journal.Handle(func(ctx context.Context, input ConsumptionInput) error {
+			return s.RecordConsumption(ctx, input)
+		}),
+		CommandRecordProduction: journal.Handle(func(ctx context.Context, input ProductionInput) error {
+			_, err := s.RecordProduction(ctx, input)
+			return err
+		}),
+		CommandCheckShortages: journal.Handle(func(ctx context.Context, args timeArgs) error {
+			_, err := s.CheckShortages(ctx, args.At)
+			return err
+		}),
+		CommandProcessExpiredItems: journal.Handle(func(ctx context.Context, args timeArgs) error {
+			_, err := s.ProcessExpiredItems(ctx, args.At)
+			return err
+		}),
+		CommandInventoryAudit: journal.Handle(func(ctx context.Context, args inventoryAuditArgs) error {
+			return s.PerformInventoryAudit(ctx, args.StockID, args.ActualQty, args.AuditorID)
+		}),
+		CommandDeductDailyRations: journal.Handle(func(ctx context.Context, args timeArgs) error {
+			_, err := s.DeductDailyRations(ctx, args.At)
+			return err
+		}),
+		CommandReserveStock: journal.Handle(func(ctx context.Context, input ReservationInput) error {
+			_, err := s.ReserveStock(ctx, input)
+			return err
+		}),
+		CommandReleaseReservation: journal.Handle(func(ctx context.Context, args idArgs) error {
+			return s.ReleaseReservation(ctx, args.ID)
+		}),
+		CommandCommitReservation: journal.Handle(func(ctx context.Context, args commitReservationArgs) error {
+			return s.CommitReservation(ctx, args.ID, args.AuthorizedBy)
+		}),
+		CommandExpireReservations: journal.Handle(func(ctx context.Context, args timeArgs) error {
+			_, err := s.ExpireReservations(ctx, args.At)
+			return err
+		}),
+		CommandOpenAudit: journal.Handle(func(ctx context.Context, args openAuditArgs) error {
+			_, err := s.OpenAudit(ctx, args.Location, args.OpenedBy)
+			return err
+		}),
+		CommandRecordAuditCount: journal.Handle(func(ctx context.Context, args auditCountArgs) error {
+			_, err := s.RecordAuditCount(ctx, args.AuditID, args.Lot, args.Counted)
+			return err
+		}),
+		CommandCloseAudit: journal.Handle(func(ctx context.Context, args closeAuditArgs) error {
+			_, err := s.CloseAudit(ctx, args.AuditID, args.ClosedBy)
+			return err
+		}),
+		CommandCancelAudit: journal.Handle(func(ctx context.Context, args idArgs) error {
+			return s.CancelAudit(ctx, args.ID)
+		}),
+		CommandMoveStockBatch: journal.Handle(func(ctx context.Context, args moveStockBatchArgs) error {
+			return s.MoveStockBatch(ctx, args.StockIDs, args.Location, args.AuthorizedBy)
+		}),
+		CommandSetStockStatusBatch: journal.Handle(func(ctx context.Context, args stockStatusBatchArgs) error {
+			return s.SetStockStatusBatch(ctx, args.StockIDs, args.Status, args.AuthorizedBy)
+		}),
+		CommandSplitStock: journal.Handle(func(ctx context.Context, input SplitStockInput) error {
+			_, err := s.SplitStock(ctx, input)
+			return err
+		}),
+		CommandSetStockStatus: journal.Handle(func(ctx context.Context, input StockStatusInput) error {
+			return s.SetStockStatus(ctx, input)
+		}),
+		CommandRecordQualityTest: journal.Handle(func(ctx context.Context, input QualityTestInput) error {
+			_, err := s.RecordQualityTest(ctx, input)
+			return err
+		}),
+		CommandRegisterAsset: journal.Handle(func(ctx context.Context, input AssetInput) error {
+			_, err := s.RegisterAsset(ctx, input)
+			return err
+		}),
+		CommandCheckOutAsset: journal.Handle(func(ctx context.Context, input CheckOutInput) error {
+			_, err := s.CheckOutAsset(ctx, input)
+			return err
+		}),
+		CommandCheckInAsset: journal.Handle(func(ctx context.Context, input CheckInInput) error {
+			_, err := s.CheckInAsset(ctx, input)
+			return err
+		}),
+		CommandRecordAssetCondition: journal.Handle(func(ctx context.Context, input AssetConditionInput) error {
+			_, err := s.RecordAssetCondition(ctx, input)
+			return err
+		}),
+		CommandRetireAsset: journal.Handle(func(ctx context.Context, args retireAssetArgs) error {
+			return s.RetireAsset(ctx, args.AssetID, args.Reason)
+		}),
+		CommandLoseStock: journal.Handle(func(ctx context.Context, args loseStockArgs) error {
+			_, err := s.LoseStock(ctx, args.ItemCode, args.Percent, args.Reason)
+			return err
+		}),
+		CommandBulkAdjustStocks: journal.Handle(func(ctx context.Context, input BulkAdjustInput) error {
+			_, err := s.BulkAdjustStocks(ctx, input)
+			return err
+		}),
+		CommandImportDelivery: journal.Handle(func(ctx context.Context, args deliveryArgs) error {
+			_, err := s.ImportDelivery(ctx, strings.NewReader(args.CSV), args.Options)
+			return err
+		}),
+	}
+}
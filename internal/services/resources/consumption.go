@@ -0,0 +1,71 @@
+package resources
+
+import (
+	"context"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// ConsumptionTopHouseholds is how many households the consumption report
+// ranks.
+const ConsumptionTopHouseholds = 10
+
+// ConsumptionReport attributes the rations drawn over a period to the
+// households, departments and ration classes they fed.
+type ConsumptionReport struct {
+	From, To    time.Time                       // Days in [From, To)
+	Households  []*models.HouseholdConsumption  // Top consumers, most calories first
+	Departments []*models.DepartmentConsumption // In department order, "" last
+	Classes     []*models.ClassConsumption      // Against the targets in force at the end
+}
+
+// ConsumptionReport reports the rations drawn on each of the days days up
+// to and including the day of at.
+func (s *Service) ConsumptionReport(ctx context.Context, at time.Time, days int) (*ConsumptionReport, error) {
+	if days < 1 {
+		days = 1
+	}
+	to := at.UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+	report := &ConsumptionReport{From: to.AddDate(0, 0, -days), To: to}
+
+	var err error
+	report.Households, err = s.draws.TopHouseholds(ctx, report.From, report.To, ConsumptionTopHouseholds)
+	if err != nil {
+		return nil, err
+	}
+	report.Departments, err = s.draws.ByDepartment(ctx, report.From, report.To)
+	if err != nil {
+		return nil, err
+	}
+	// Residents without a vocation after the departments
+	if len(report.Departments) > 0 && report.Departments[0].Department == "" {
+		report.Departments = append(report.Departments[1:], report.Departments[0])
+	}
+
+	report.Classes, err = s.draws.ByClass(ctx, report.From, report.To)
+	if err != nil {
+		return nil, err
+	}
+	targets, err := s.policies.Active(ctx, at)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range report.Classes {
+		c.CalorieTarget = targets.Calories(c.RationClass)
+		c.WaterTargetL = targets.WaterL(c.RationClass)
+	}
+	return report, nil
+}
+
+// NutritionReport reports the nutrients drawn by households of each ration
+// class on each of the days days up to and including the day of at, against
+// what their draws called for, in class order. Classes whose draws called
+// for no nutrients, made before nutrients were tracked, have no deficits.
+func (s *Service) NutritionReport(ctx context.Context, at time.Time, days int) ([]*models.ClassConsumption, error) {
+	if days < 1 {
+		days = 1
+	}
+	to := at.UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+	return s.draws.ByClass(ctx, to.AddDate(0, 0, -days), to)
+}
@@ -0,0 +1,325 @@
+package resources
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// Delivery import CSV columns. The header row is required; column order is
+// free.
+const (
+	DeliveryColItemCode   = "item_code"
+	DeliveryColQuantity   = "quantity"
+	DeliveryColLot        = "lot"             // Optional; lines without one are received as unnumbered lots
+	DeliveryColExpiration = "expiration_date" // Optional, YYYY-MM-DD
+	DeliveryColLocation   = "location"        // Optional; defaults to the import's storage location
+	DeliveryColName       = "name"            // Optional; names an item created for an unknown code
+)
+
+// deliveryReceipt is a delivery line to take into stock.
+type deliveryReceipt struct {
+	stock    *models.ResourceStock
+	quantity float64
+	create   bool // Receive as a new lot rather than extend one
+}
+
+// ImportDelivery receives a supply delivery given in CSV form. A line with
+// a lot number of the item on file extends that lot; any other line is
+// received as a new lot, expiring after the item's shelf life when the
+// line gives no date. Unknown item codes are added to the catalog only
+// with CreateItems. Lines with an unknown code, a lot number on file for
+// another item or off the books, or an expiration date other than the
+// lot's are not received and are listed in the reconciliation report for
+// taking up with the supplier. Everything received commits together.
+func (s *Service) ImportDelivery(ctx context.Context, r io.Reader, opts DeliveryOptions) (_ *models.DeliveryReconciliation, err error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading delivery file: %w", err)
+	}
+	ctx, cmd := s.begin(ctx, CommandImportDelivery, deliveryArgs{string(data), opts})
+	defer func() { cmd.End(err) }()
+
+	var category *models.ResourceCategory
+	if opts.CreateItems {
+		if opts.CategoryCode == "" {
+			return nil, fmt.Errorf("%w: a category is required to create items", repository.ErrValidation)
+		}
+		category, err = s.GetCategoryByCode(ctx, opts.CategoryCode)
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, fmt.Errorf("%w: category %s does not exist", repository.ErrValidation, opts.CategoryCode)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	received := opts.ReceivedDate
+	if received.IsZero() {
+		received = s.now()
+	}
+	reason := "Delivery"
+	if opts.Reference != "" {
+		reason += " " + opts.Reference
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("delivery file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{DeliveryColItemCode, DeliveryColQuantity} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("missing required column: %s", name)
+		}
+	}
+
+	rec := &models.DeliveryReconciliation{
+		Reference:       opts.Reference,
+		StorageLocation: opts.StorageLocation,
+		ReceivedAt:      received,
+	}
+	items := make(map[string]*models.ResourceItem) // By code, nil when unknown
+	lots := make(map[string]*models.ResourceStock) // By item ID and lot number, as matched or received so far
+	var newItems []*models.ResourceItem
+	var receipts []deliveryReceipt
+
+	line := 1
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		dl := models.DeliveryLine{Line: line}
+		reject := func(outcome models.DeliveryOutcome, format string, args ...any) {
+			dl.Outcome = outcome
+			dl.Detail = fmt.Sprintf(format, args...)
+			rec.Lines = append(rec.Lines, dl)
+		}
+		if err != nil {
+			reject(models.DeliveryInvalid, "%v", err)
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		field := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		dl.ItemCode = strings.ToUpper(field(DeliveryColItemCode))
+		dl.LotNumber = field(DeliveryColLot)
+		if dl.ItemCode == "" && field(DeliveryColQuantity) == "" {
+			continue
+		}
+		quantity, err := strconv.ParseFloat(field(DeliveryColQuantity), 64)
+		if err != nil || math.IsNaN(quantity) || math.IsInf(quantity, 0) || quantity <= 0 {
+			reject(models.DeliveryInvalid, "quantity must be a positive number, not %q", field(DeliveryColQuantity))
+			continue
+		}
+		dl.Quantity = quantity
+		if v := field(DeliveryColExpiration); v != "" {
+			expires, err := util.ParseDate(v)
+			if err != nil {
+				reject(models.DeliveryInvalid, "invalid expiration_date: %q", v)
+				continue
+			}
+			dl.ExpirationDate = &expires
+		}
+		location := field(DeliveryColLocation)
+		if location == "" {
+			location = opts.StorageLocation
+		}
+		if location == "" {
+			reject(models.DeliveryInvalid, "no storage location: give a location column or a default location")
+			continue
+		}
+
+		item, known := items[dl.ItemCode]
+		if !known {
+			item, err = s.resources.GetItemByCode(ctx, dl.ItemCode)
+			if errors.Is(err, repository.ErrNotFound) {
+				item = nil
+				if opts.CreateItems {
+					name := field(DeliveryColName)
+					if name == "" {
+						name = dl.ItemCode
+					}
+					item = &models.ResourceItem{
+						ID:            s.idGenerator.NewID(),
+						CategoryID:    category.ID,
+						ItemCode:      dl.ItemCode,
+						Name:          name,
+						UnitOfMeasure: category.UnitOfMeasure,
+					}
+					if err := item.Validate(); err != nil {
+						reject(models.DeliveryInvalid, "%v", err)
+						continue
+					}
+					newItems = append(newItems, item)
+					dl.ItemCreated = true
+				}
+			} else if err != nil {
+				return nil, fmt.Errorf("getting item %s: %w", dl.ItemCode, err)
+			}
+			items[dl.ItemCode] = item
+		}
+		if item == nil {
+			reject(models.DeliveryUnknownCode, "item code %s is not in the catalog", dl.ItemCode)
+			continue
+		}
+
+		var stock *models.ResourceStock
+		if dl.LotNumber != "" {
+			key := item.ID + "/" + dl.LotNumber
+			stock = lots[key]
+			if stock == nil && !dl.ItemCreated {
+				matched, mismatch, err := s.matchLot(ctx, item, dl.LotNumber)
+				if err != nil {
+					return nil, err
+				}
+				if mismatch != "" {
+					reject(models.DeliveryLotMismatch, "%s", mismatch)
+					continue
+				}
+				stock = matched
+			}
+			if stock != nil {
+				if dl.ExpirationDate != nil && !sameDay(*dl.ExpirationDate, stock.ExpirationDate) {
+					on := "no expiration date"
+					if stock.ExpirationDate != nil {
+						on = "expiration date " + util.FormatDay(*stock.ExpirationDate)
+					}
+					reject(models.DeliveryExpirationMismatch, "lot %s is on file with %s", dl.LotNumber, on)
+					continue
+				}
+				lots[key] = stock
+				receipts = append(receipts, deliveryReceipt{stock: stock, quantity: quantity})
+				dl.Outcome = models.DeliveryExtended
+				dl.StockID = stock.ID
+				rec.Lines = append(rec.Lines, dl)
+				continue
+			}
+		}
+
+		stock = &models.ResourceStock{
+			ID:              s.idGenerator.NewID(),
+			ItemID:          item.ID,
+			StorageLocation: location,
+			ReceivedDate:    received,
+			ExpirationDate:  dl.ExpirationDate,
+			Status:          models.StockStatusAvailable,
+		}
+		if dl.LotNumber != "" {
+			lot := dl.LotNumber
+			stock.LotNumber = &lot
+			lots[item.ID+"/"+lot] = stock
+		}
+		if stock.ExpirationDate == nil && item.ShelfLifeDays != nil {
+			expires := received.AddDate(0, 0, *item.ShelfLifeDays)
+			stock.ExpirationDate = &expires
+		}
+		receipts = append(receipts, deliveryReceipt{stock: stock, quantity: quantity, create: true})
+		dl.Outcome = models.DeliveryStocked
+		dl.StockID = stock.ID
+		rec.Lines = append(rec.Lines, dl)
+	}
+
+	if len(receipts) == 0 {
+		return rec, nil
+	}
+	err = repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		for _, item := range newItems {
+			if err := s.resources.CreateItem(ctx, tx, item); err != nil {
+				return fmt.Errorf("creating item %s: %w", item.ItemCode, err)
+			}
+		}
+		for _, r := range receipts {
+			if r.create {
+				r.stock.Quantity = r.quantity
+				if err := s.createStock(ctx, tx, r.stock, reason, opts.AuthorizedBy); err != nil {
+					return err
+				}
+				continue
+			}
+			// The lot as it stands, not as the manifest was matched
+			stock, err := s.resources.GetStock(ctx, tx, r.stock.ID)
+			if err != nil {
+				return fmt.Errorf("getting lot %s: %w", lotName(r.stock), err)
+			}
+			err = s.adjustStock(ctx, tx, stock, StockAdjustment{
+				QuantityChange: r.quantity,
+				Type:           models.TransactionTypeProduction,
+				Reason:         reason,
+				AuthorizedBy:   opts.AuthorizedBy,
+			})
+			if err != nil {
+				return fmt.Errorf("extending lot %s: %w", lotName(r.stock), err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+// matchLot finds the lot of an item with a lot number, nil when the item
+// has none. A lot number on file only for another item, or a lot off the
+// books, is a mismatch, described in the string returned.
+func (s *Service) matchLot(ctx context.Context, item *models.ResourceItem, lot string) (*models.ResourceStock, string, error) {
+	stocks, err := s.resources.GetStocksByLot(ctx, nil, lot)
+	if err != nil {
+		return nil, "", err
+	}
+	var other, closed *models.ResourceStock
+	for _, stock := range stocks {
+		switch {
+		case stock.ItemID != item.ID:
+			other = stock
+		case settableStatuses[stock.Status]:
+			return stock, "", nil
+		default:
+			closed = stock
+		}
+	}
+	if closed != nil {
+		return nil, fmt.Sprintf("lot %s of %s is %s", lot, item.ItemCode, closed.Status), nil
+	}
+	if other != nil {
+		return nil, fmt.Sprintf("lot %s is on file for %s", lot, other.Item.ItemCode), nil
+	}
+	return nil, "", nil
+}
+
+// sameDay reports whether an expiration date falls on the day of a lot's,
+// if it has one.
+func sameDay(a time.Time, b *time.Time) bool {
+	return b != nil && a.Format(util.DateFormat) == b.Format(util.DateFormat)
+}
@@ -0,0 +1,36 @@
+package resources
+
+import (
+	"context"
+
+	"github.com/vtuos/vtuos/internal/events"
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// SetEvents publishes the service's events, such as stock depleted, on bus.
+func (s *Service) SetEvents(bus *events.Bus) {
+	s.events = bus
+}
+
+// stockDepleted publishes that a lot ran out once the command running in
+// ctx succeeds.
+func (s *Service) stockDepleted(ctx context.Context, stock *models.ResourceStock) {
+	data := map[string]any{
+		"item_id":          stock.ItemID,
+		"storage_location": stock.StorageLocation,
+	}
+	if stock.LotNumber != nil {
+		data["lot_number"] = *stock.LotNumber
+	}
+	if stock.Item != nil {
+		data["item_code"] = stock.Item.ItemCode
+		data["item_name"] = stock.Item.Name
+	}
+	s.events.Emit(ctx, events.Event{
+		Type:    events.StockDepleted,
+		Time:    s.now(),
+		Vault:   stock.VaultID,
+		Subject: stock.ID,
+		Data:    data,
+	})
+}
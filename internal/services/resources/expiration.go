@@ -0,0 +1,75 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/simulation"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// DefaultExpirationWarningDays is how far ahead the expiration check warns
+// when the configuration does not say.
+const DefaultExpirationWarningDays = 14
+
+// CheckExpirations raises a warning for every available lot expiring within
+// warningDays of at, and a critical alert for every lot already expired but
+// still available, i.e. not yet written off by the expiration sweep. Warnings
+// come first so the critical alerts are the most recent.
+func (s *Service) CheckExpirations(ctx context.Context, at time.Time, warningDays int) ([]simulation.Event, error) {
+	stocks, err := s.resources.GetStocksExpiringBy(ctx, at.AddDate(0, 0, warningDays))
+	if err != nil {
+		return nil, fmt.Errorf("getting expiring stocks: %w", err)
+	}
+
+	var warnings, critical []simulation.Event
+	for _, stock := range stocks {
+		if stock.ExpirationDate == nil {
+			continue
+		}
+		if stock.IsExpired(at) {
+			critical = append(critical, simulation.Event{
+				Time:    at,
+				Level:   simulation.EventCritical,
+				Source:  "expiration check",
+				Message: fmt.Sprintf("Expired stock still available: %s", expiringLotLabel(stock)),
+			})
+			continue
+		}
+		warnings = append(warnings, simulation.Event{
+			Time:    at,
+			Level:   simulation.EventWarning,
+			Source:  "expiration check",
+			Message: fmt.Sprintf("Stock expires in %d day(s): %s", stock.DaysUntilExpiration(at), expiringLotLabel(stock)),
+		})
+	}
+	return append(warnings, critical...), nil
+}
+
+// expiringLotLabel describes a lot for an expiration alert, e.g.
+// "FOOD-PROTEIN-001 lot LOT-2077-114, 40.00 kg on 2077-11-02".
+func expiringLotLabel(stock *models.ResourceStock) string {
+	code, unit := stock.ItemID, ""
+	if stock.Item != nil {
+		code, unit = stock.Item.ItemCode, " "+stock.Item.UnitOfMeasure
+	}
+	label := code
+	if stock.LotNumber != nil {
+		label += " lot " + *stock.LotNumber
+	}
+	return fmt.Sprintf("%s, %.2f%s on %s", label, stock.Quantity, unit, util.FormatDate(*stock.ExpirationDate))
+}
+
+// ExpirationAlertJob is the scheduled job that checks for expiring and
+// expired stock every vault day.
+func (s *Service) ExpirationAlertJob(warningDays int) simulation.Job {
+	return simulation.Job{
+		Name:     "Expiration alerts",
+		Interval: simulation.Daily,
+		Check: func(ctx context.Context, at time.Time) ([]simulation.Event, error) {
+			return s.CheckExpirations(ctx, at, warningDays)
+		},
+	}
+}
@@ -0,0 +1,155 @@
+package resources
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/vtuos/vtuos/internal/dualauth"
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+)
+
+// lotStatuses are the statuses a single lot may be set to, and set from:
+// the settable statuses, and damaged.
+var lotStatuses = map[models.StockStatus]bool{
+	models.StockStatusAvailable:  true,
+	models.StockStatusReserved:   true,
+	models.StockStatusQuarantine: true,
+	models.StockStatusDamaged:    true,
+}
+
+// SplitStock splits part of a lot off into a new lot, e.g. to store some of
+// it elsewhere or set it apart as damaged. The new lot keeps the item, dates
+// and status of the source. Both lots get a TRANSFER transaction for the
+// quantity moved, in one transaction; reserved units stay with the source.
+func (s *Service) SplitStock(ctx context.Context, input SplitStockInput) (_ *models.ResourceStock, err error) {
+	ctx, cmd := s.begin(ctx, CommandSplitStock, input)
+	defer func() { cmd.End(err) }()
+
+	if input.LotNumber == "" {
+		return nil, fmt.Errorf("%w: lot number of the new lot is required", repository.ErrValidation)
+	}
+	if input.Quantity <= 0 {
+		return nil, fmt.Errorf("%w: split quantity must be positive", repository.ErrValidation)
+	}
+
+	var lot *models.ResourceStock
+	err = repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		source, err := s.resources.GetStock(ctx, tx, input.StockID)
+		if err != nil {
+			return fmt.Errorf("getting stock: %w", err)
+		}
+		if !lotStatuses[source.Status] {
+			return fmt.Errorf("%w: lot %s is %s", repository.ErrValidation, lotName(source), source.Status)
+		}
+		if source.LotNumber != nil && *source.LotNumber == input.LotNumber {
+			return fmt.Errorf("%w: the new lot needs a lot number of its own", repository.ErrValidation)
+		}
+		if input.Quantity >= source.Quantity {
+			return fmt.Errorf("%w: split of %.2f leaves nothing in lot %s of %.2f",
+				repository.ErrValidation, input.Quantity, lotName(source), source.Quantity)
+		}
+		if input.Quantity > source.AvailableQuantity()+models.QuantityEpsilon {
+			return fmt.Errorf("%w: split would cut into %.2f reserved units", repository.ErrValidation, source.QuantityReserved)
+		}
+
+		location := input.StorageLocation
+		if location == "" {
+			location = source.StorageLocation
+		}
+		lot = &models.ResourceStock{
+			ID:              s.idGenerator.NewID(),
+			ItemID:          source.ItemID,
+			LotNumber:       &input.LotNumber,
+			Quantity:        input.Quantity,
+			StorageLocation: location,
+			ReceivedDate:    source.ReceivedDate,
+			ExpirationDate:  source.ExpirationDate,
+			Status:          source.Status,
+			VaultID:         source.VaultID,
+		}
+		source.Quantity -= input.Quantity
+
+		now := s.now()
+		out := &models.ResourceTransaction{
+			ID:              s.idGenerator.NewID(),
+			StockID:         &source.ID,
+			ItemID:          source.ItemID,
+			TransactionType: models.TransactionTypeTransfer,
+			Quantity:        -input.Quantity,
+			BalanceAfter:    source.Quantity,
+			Reason:          "Split into lot " + input.LotNumber,
+			AuthorizedBy:    input.AuthorizedBy,
+			Timestamp:       now,
+		}
+		in := &models.ResourceTransaction{
+			ID:              s.idGenerator.NewID(),
+			StockID:         &lot.ID,
+			ItemID:          lot.ItemID,
+			TransactionType: models.TransactionTypeTransfer,
+			Quantity:        input.Quantity,
+			BalanceAfter:    input.Quantity,
+			Reason:          "Split from lot " + lotName(source),
+			AuthorizedBy:    input.AuthorizedBy,
+			Timestamp:       now,
+		}
+
+		if err := s.resources.UpdateStock(ctx, tx, source); err != nil {
+			return fmt.Errorf("updating stock: %w", err)
+		}
+		if err := s.resources.CreateStock(ctx, tx, lot); err != nil {
+			return fmt.Errorf("creating stock: %w", err)
+		}
+		for _, txn := range []*models.ResourceTransaction{out, in} {
+			if err := s.resources.CreateTransaction(ctx, tx, txn); err != nil {
+				return fmt.Errorf("recording transaction: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return lot, nil
+}
+
+// SetStockStatus sets the status of one lot, e.g. to mark it damaged with
+// what was found, recording the change as a zero-quantity adjustment. A lot
+// with units reserved cannot be marked damaged, and marking one damaged
+// writes off its units, which a dual authorization policy may require a
+// second operator to confirm.
+func (s *Service) SetStockStatus(ctx context.Context, input StockStatusInput) (err error) {
+	ctx, cmd := s.begin(ctx, CommandSetStockStatus, input)
+	defer func() { cmd.End(err) }()
+
+	if !lotStatuses[input.Status] {
+		return fmt.Errorf("%w: stock status %q cannot be set directly", repository.ErrValidation, input.Status)
+	}
+	return repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		stock, err := s.resources.GetStock(ctx, tx, input.StockID)
+		if err != nil {
+			return fmt.Errorf("getting stock: %w", err)
+		}
+		if !lotStatuses[stock.Status] {
+			return fmt.Errorf("%w: lot %s is %s", repository.ErrValidation, lotName(stock), stock.Status)
+		}
+		if stock.Status == input.Status {
+			return nil
+		}
+		if input.Status == models.StockStatusDamaged && stock.QuantityReserved > models.QuantityEpsilon {
+			return fmt.Errorf("%w: lot %s has %.2f units reserved", repository.ErrValidation, lotName(stock), stock.QuantityReserved)
+		}
+		var auth *dualauth.Authorization
+		if input.Status == models.StockStatusDamaged {
+			if auth, err = s.authorizer.Authorize(ctx, tx, models.DualAuthStockWriteOff, stock.Quantity); err != nil {
+				return err
+			}
+		}
+
+		if err := s.setStockStatus(ctx, tx, stock, input.Status, input.Note, input.AuthorizedBy); err != nil {
+			return err
+		}
+		return s.authorizer.Record(ctx, tx, auth, models.AuditEntityStock, stock.ID, s.now())
+	})
+}
@@ -0,0 +1,98 @@
+package resources
+
+import "github.com/vtuos/vtuos/internal/models"
+
+// menuSteps is how many portions the daily menu is planned in: each adds
+// about 1/menuSteps of the day's calories from a single food.
+const menuSteps = 40
+
+// menuFood is a food the daily menu can draw on and the lots it is drawn
+// from.
+type menuFood struct {
+	itemID    string
+	calories  float64          // Per unit
+	nutrients models.Nutrients // Per unit, zero if not recorded
+	available float64          // Units left to plan
+	lots      []*models.ResourceStock
+	next      int // Lot drawn from next
+}
+
+// menuFoods returns the food items with calories that have stock among the
+// lots, soonest-expiring lot first, in the order of their soonest lot.
+func menuFoods(items []*models.ResourceItem, stocks []*models.ResourceStock) []*menuFood {
+	byID := make(map[string]*models.ResourceItem, len(items))
+	for _, item := range items {
+		if item.CaloriesPerUnit != nil && *item.CaloriesPerUnit > 0 {
+			byID[item.ID] = item
+		}
+	}
+
+	var foods []*menuFood
+	index := make(map[string]*menuFood)
+	for _, stock := range stocks {
+		item, ok := byID[stock.ItemID]
+		if !ok || stock.AvailableQuantity() <= models.QuantityEpsilon {
+			continue
+		}
+		food, ok := index[item.ID]
+		if !ok {
+			food = &menuFood{itemID: item.ID, calories: *item.CaloriesPerUnit}
+			if item.Nutrients != nil {
+				food.nutrients = *item.Nutrients
+			}
+			index[item.ID] = food
+			foods = append(foods, food)
+		}
+		food.available += stock.AvailableQuantity()
+		food.lots = append(food.lots, stock)
+	}
+	return foods
+}
+
+// planMenu plans the day's menu: up to calories kcal from the foods, in
+// portions of about 1/menuSteps of it, each from the food whose portion
+// goes furthest per kcal towards the nutrients still short of need. Foods
+// are listed soonest-expiring first, so ties, and every portion once the
+// nutrients are met, go to the food closest to spoiling. It returns the
+// units of each food, by index.
+func planMenu(foods []*menuFood, calories float64, need models.Nutrients) []float64 {
+	units := make([]float64, len(foods))
+	left := make([]float64, len(foods))
+	for i, food := range foods {
+		left[i] = food.available
+	}
+	step := calories / menuSteps
+	var planned models.Nutrients
+
+	for remaining := calories; remaining > models.QuantityEpsilon; {
+		best, bestScore, bestKcal := -1, -1.0, 0.0
+		for i, food := range foods {
+			kcal := min(step, remaining, left[i]*food.calories)
+			if kcal <= models.QuantityEpsilon {
+				continue
+			}
+			portion := food.nutrients.Scale(kcal / food.calories)
+			var gain float64
+			for _, n := range models.TrackedNutrients {
+				if want := need.Get(n); want > 0 {
+					short := max(want-planned.Get(n), 0)
+					gain += min(portion.Get(n), short) / want
+				}
+			}
+			if score := gain / kcal; score > bestScore+1e-12 {
+				best, bestScore, bestKcal = i, score, kcal
+			}
+		}
+		if best < 0 {
+			break // Every food is planned out
+		}
+
+		food := foods[best]
+		qty := min(bestKcal/food.calories, left[best])
+		units[best] += qty
+		left[best] -= qty
+		planned = planned.Add(food.nutrients.Scale(qty))
+		remaining -= bestKcal
+	}
+	return units
+}
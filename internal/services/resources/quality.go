@@ -0,0 +1,80 @@
+package resources
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+)
+
+// RecordQualityTest records a water or food quality test of a consumable
+// lot. A failed test quarantines the lot in the same transaction, taking it
+// out of consumption and rations until an operator releases it; the caller
+// raises the alert.
+func (s *Service) RecordQualityTest(ctx context.Context, input QualityTestInput) (_ *models.QualityTest, err error) {
+	ctx, cmd := s.begin(ctx, CommandRecordQualityTest, input)
+	defer func() { cmd.End(err) }()
+
+	stock, err := s.resources.GetStock(ctx, nil, input.StockID)
+	if err != nil {
+		return nil, fmt.Errorf("getting stock: %w", err)
+	}
+	if !settableStatuses[stock.Status] {
+		return nil, fmt.Errorf("%w: lot %s is %s", repository.ErrValidation, lotName(stock), stock.Status)
+	}
+	if stock.Item == nil {
+		return nil, fmt.Errorf("%w: lot %s has no item", repository.ErrValidation, lotName(stock))
+	}
+	category, err := s.GetCategory(ctx, stock.Item.CategoryID)
+	if err != nil {
+		return nil, fmt.Errorf("getting category: %w", err)
+	}
+	if !category.IsConsumable {
+		return nil, fmt.Errorf("%w: %s is not a consumable", repository.ErrValidation, stock.Item.ItemCode)
+	}
+
+	test := &models.QualityTest{
+		ID:                 s.idGenerator.NewID(),
+		StockID:            stock.ID,
+		TestedAt:           input.TestedAt,
+		TestedBy:           input.TestedBy,
+		ContaminationLevel: input.ContaminationLevel,
+		Passed:             input.Passed,
+		Notes:              input.Notes,
+	}
+	if test.TestedAt.IsZero() {
+		test.TestedAt = s.now()
+	}
+	test.TestedAt = test.TestedAt.UTC().Truncate(time.Second)
+
+	err = repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		if err := s.quality.Create(ctx, tx, test); err != nil {
+			return err
+		}
+		if test.Passed {
+			return nil
+		}
+		// Quarantine the lot as it stands, not as it was when the test began
+		stock, err := s.resources.GetStock(ctx, tx, stock.ID)
+		if err != nil {
+			return fmt.Errorf("getting stock: %w", err)
+		}
+		if stock.Status == models.StockStatusQuarantine {
+			return nil
+		}
+		return s.setStockStatus(ctx, tx, stock, models.StockStatusQuarantine,
+			fmt.Sprintf("failed quality test, contamination %.2f ppm", test.ContaminationLevel), input.TestedBy)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return test, nil
+}
+
+// ListQualityTests retrieves the quality tests of a lot, most recent first.
+func (s *Service) ListQualityTests(ctx context.Context, stockID string) ([]*models.QualityTest, error) {
+	return s.quality.ListByStock(ctx, stockID)
+}
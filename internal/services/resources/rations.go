@@ -0,0 +1,293 @@
+package resources
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/simulation"
+)
+
+// Daily ration sources.
+const (
+	RationFoodCategoryCode = "FOOD"
+	RationWaterItemCode    = "WATER-PURIF-001"
+)
+
+// RationDeduction is the outcome of drawing one day's rations from stock.
+type RationDeduction struct {
+	Day               time.Time
+	CaloriesRequired  float64
+	CaloriesDrawn     float64
+	WaterRequiredL    float64
+	WaterDrawnL       float64
+	NutrientsRequired models.Nutrients
+	NutrientsDrawn    models.Nutrients
+}
+
+// Short returns true if stock could not cover the day's rations.
+func (d *RationDeduction) Short() bool {
+	return d.CaloriesRequired-d.CaloriesDrawn > models.QuantityEpsilon ||
+		d.WaterRequiredL-d.WaterDrawnL > models.QuantityEpsilon
+}
+
+// Deficits returns the nutrients the day's menu drew short of the day's
+// targets.
+func (d *RationDeduction) Deficits() []models.Nutrient {
+	return d.NutrientsDrawn.Deficits(d.NutrientsRequired)
+}
+
+// String summarizes the deduction, e.g. "1240000 kcal, 3100.0 L drawn".
+func (d *RationDeduction) String() string {
+	s := fmt.Sprintf("%.0f kcal, %.1f L drawn", d.CaloriesDrawn, d.WaterDrawnL)
+	if d.Short() {
+		s += fmt.Sprintf(" (SHORT %.0f kcal, %.1f L)",
+			d.CaloriesRequired-d.CaloriesDrawn, d.WaterRequiredL-d.WaterDrawnL)
+	}
+	if deficits := d.Deficits(); len(deficits) > 0 {
+		names := make([]string, len(deficits))
+		for i, n := range deficits {
+			names[i] = string(n)
+		}
+		s += " (LOW " + strings.Join(names, ", ") + ")"
+	}
+	return s
+}
+
+// DeductDailyRations draws one day's household rations from stock under the
+// ration policies in force on the day: calories from a menu of the food in
+// stock planned against the day's nutrient targets (see planMenu), and water
+// from purified water, each soonest-expiring lot first. Each household's
+// rations are drawn in turn, its calorie share of every food on the menu,
+// naming the household on every CONSUMPTION transaction, and recorded as a
+// ration draw with the nutrients it drew and its members by department.
+// When stock cannot cover the day every household gets the same share of its
+// rations, and the rest is reported as a shortfall rather than failing, so
+// the vault eats what it has. Every draw commits together. A day the vault
+// has already drawn is not drawn again: the error wraps
+// repository.ErrDuplicate.
+func (s *Service) DeductDailyRations(ctx context.Context, day time.Time) (_ *RationDeduction, err error) {
+	ctx, cmd := s.begin(ctx, CommandDeductDailyRations, timeArgs{day})
+	defer func() { cmd.End(err) }()
+
+	reqs, err := s.dailyRequirements(ctx, day)
+	if err != nil {
+		return nil, err
+	}
+	deduction := &RationDeduction{
+		Day:               day,
+		CaloriesRequired:  reqs.TotalCalories,
+		WaterRequiredL:    reqs.TotalWaterL,
+		NutrientsRequired: reqs.TotalNutrients,
+	}
+
+	food, err := s.GetCategoryByCode(ctx, RationFoodCategoryCode)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.resources.ListItems(ctx, food.ID, models.Pagination{Page: 1, PageSize: 500})
+	if err != nil {
+		return nil, fmt.Errorf("listing food items: %w", err)
+	}
+	water, err := s.resources.GetItemByCode(ctx, RationWaterItemCode)
+	if err != nil {
+		return nil, fmt.Errorf("getting ration water item: %w", err)
+	}
+
+	households := make([]string, 0, len(reqs.ByHousehold))
+	for id, req := range reqs.ByHousehold {
+		if req.MemberCount > 0 {
+			households = append(households, id)
+		}
+	}
+	slices.Sort(households)
+
+	reason := "Daily rations " + day.Format(time.DateOnly)
+	err = repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		drawn, err := s.draws.DrawnOn(ctx, tx, day)
+		if err != nil {
+			return err
+		}
+		if drawn {
+			return fmt.Errorf("%w: rations for %s already drawn", repository.ErrDuplicate, day.Format(time.DateOnly))
+		}
+
+		// The menu is planned from stock as it stands within the transaction
+		foodStocks, err := s.availableStocks(ctx, tx, models.StockFilter{CategoryID: food.ID})
+		if err != nil {
+			return err
+		}
+		foods := menuFoods(items.Items, foodStocks)
+		menu := planMenu(foods, deduction.CaloriesRequired, deduction.NutrientsRequired)
+		waterStocks, err := s.availableStocks(ctx, tx, models.StockFilter{ItemID: water.ID})
+		if err != nil {
+			return err
+		}
+
+		// The share of its water every household gets; the menu holds the
+		// share of food
+		units := func(*models.ResourceStock) float64 { return 1 }
+		waterShare := rationShare(waterStocks, units, deduction.WaterRequiredL)
+
+		var nextWater int
+		for _, id := range households {
+			req := reqs.ByHousehold[id]
+			draw := &models.RationDraw{
+				ID:                s.idGenerator.NewID(),
+				Day:               day,
+				HouseholdID:       &id,
+				RationClass:       req.RationClass,
+				Residents:         req.MemberCount,
+				CaloriesRequired:  req.CaloriesDay,
+				WaterRequiredL:    req.WaterLDay,
+				NutrientsRequired: req.Nutrients,
+				Departments:       req.Departments,
+			}
+			share := req.CaloriesDay / deduction.CaloriesRequired
+			for i, food := range foods {
+				if menu[i] <= models.QuantityEpsilon {
+					continue
+				}
+				qty, err := s.drawRations(ctx, tx, food.lots, &food.next, units, menu[i]*share, reason, id)
+				if err != nil {
+					return err
+				}
+				draw.CaloriesDrawn += qty * food.calories
+				draw.NutrientsDrawn = draw.NutrientsDrawn.Add(food.nutrients.Scale(qty))
+			}
+			draw.CaloriesDrawn = min(draw.CaloriesDrawn, draw.CaloriesRequired)
+			var err error
+			draw.WaterDrawnL, err = s.drawRations(ctx, tx, waterStocks, &nextWater, units,
+				req.WaterLDay*waterShare, reason, id)
+			if err != nil {
+				return err
+			}
+			if err := s.draws.Create(ctx, tx, draw); err != nil {
+				return fmt.Errorf("recording rations of household %s: %w", id, err)
+			}
+			deduction.CaloriesDrawn += draw.CaloriesDrawn
+			deduction.WaterDrawnL += draw.WaterDrawnL
+			deduction.NutrientsDrawn = deduction.NutrientsDrawn.Add(draw.NutrientsDrawn)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return deduction, nil
+}
+
+// availableStocks lists the available lots matching filter within tx,
+// soonest-expiring first.
+func (s *Service) availableStocks(ctx context.Context, tx *sql.Tx, filter models.StockFilter) ([]*models.ResourceStock, error) {
+	filter.Status = ptr(models.StockStatusAvailable)
+	stocks, err := s.resources.ListAllStocks(ctx, tx, filter, models.SortOption{})
+	if err != nil {
+		return nil, fmt.Errorf("listing stocks: %w", err)
+	}
+	return stocks, nil
+}
+
+// rationShare returns the fraction of required, in the measure value gives
+// a unit of each lot, that the lots can cover: 1 when they hold enough.
+func rationShare(stocks []*models.ResourceStock, value func(*models.ResourceStock) float64, required float64) float64 {
+	var available float64
+	for _, stock := range stocks {
+		available += stock.AvailableQuantity() * value(stock)
+	}
+	if required <= 0 || available >= required {
+		return 1
+	}
+	return available / required
+}
+
+// drawRations draws want of a household's rations, in the measure value
+// gives a unit of each lot, from the lots starting at *next within tx,
+// moving *next past the lots it empties. It returns how much it drew, less
+// than want only when the lots run out.
+func (s *Service) drawRations(ctx context.Context, tx *sql.Tx, stocks []*models.ResourceStock, next *int,
+	value func(*models.ResourceStock) float64, want float64, reason, householdID string) (float64, error) {
+	var drawn float64
+	for *next < len(stocks) && want-drawn > models.QuantityEpsilon {
+		stock := stocks[*next]
+		perUnit := value(stock)
+		if perUnit == 0 || stock.AvailableQuantity() <= models.QuantityEpsilon {
+			*next++
+			continue
+		}
+		qty, err := s.drawRation(ctx, tx, stock, (want-drawn)/perUnit, reason, householdID)
+		if err != nil {
+			return drawn, err
+		}
+		if qty == 0 {
+			break
+		}
+		drawn += qty * perUnit
+	}
+	return min(drawn, want), nil
+}
+
+// drawRation consumes up to want units of a lot's unreserved quantity for a
+// household within tx and returns how much it drew.
+func (s *Service) drawRation(ctx context.Context, tx *sql.Tx, stock *models.ResourceStock, want float64, reason, householdID string) (float64, error) {
+	qty := math.Min(want, stock.AvailableQuantity())
+	if qty <= models.QuantityEpsilon {
+		return 0, nil
+	}
+	adjustment := StockAdjustment{
+		QuantityChange:    -qty,
+		Type:              models.TransactionTypeConsumption,
+		Reason:            reason,
+		RelatedEntityType: "HOUSEHOLD",
+		RelatedEntityID:   householdID,
+	}
+	if err := s.adjustStock(ctx, tx, stock, adjustment); err != nil {
+		return 0, fmt.Errorf("drawing rations from stock %s: %w", stock.ID, err)
+	}
+	return qty, nil
+}
+
+// RationJob is the scheduled job that deducts rations at the start of every
+// vault day. The scheduler starts again from the start date at every launch,
+// so the job passes over days already drawn.
+func (s *Service) RationJob() simulation.Job {
+	return simulation.Job{
+		Name:      "Daily ration deduction",
+		Interval:  simulation.Daily,
+		Essential: true, // Residents eat through a lockdown
+		Run: func(ctx context.Context, at time.Time) (string, error) {
+			deduction, err := s.DeductDailyRations(ctx, at)
+			if errors.Is(err, repository.ErrDuplicate) {
+				return "rations for " + at.Format(time.DateOnly) + " already drawn", nil
+			}
+			if err != nil {
+				return "", err
+			}
+			return deduction.String(), nil
+		},
+	}
+}
+
+// ExpirationSweepJob is the scheduled job that marks expired lots as spoiled
+// once a week.
+func (s *Service) ExpirationSweepJob() simulation.Job {
+	return simulation.Job{
+		Name:     "Inventory expiration sweep",
+		Interval: simulation.Weekly,
+		Run: func(ctx context.Context, at time.Time) (string, error) {
+			count, err := s.ProcessExpiredItems(ctx, at)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("%d expired lot(s) written off", count), nil
+		},
+	}
+}
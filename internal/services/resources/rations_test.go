@@ -0,0 +1,101 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/database"
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/testutil"
+)
+
+// setupRations stocks a food and purified water, and houses two residents in
+// one household.
+func setupRations(t *testing.T) (*Service, *database.DB) {
+	t.Helper()
+	svc, db := setupService(t)
+	ctx := context.Background()
+	resources := repository.NewResourceRepository(db.DB)
+
+	food := testutil.FixtureResourceCategory()
+	water := testutil.FixtureResourceCategory(func(c *models.ResourceCategory) {
+		c.Code, c.Name, c.UnitOfMeasure = "WATER", "Water", "L"
+	})
+	ration := testutil.FixtureResourceItem(food.ID)
+	purified := testutil.FixtureResourceItem(water.ID, func(i *models.ResourceItem) {
+		i.ItemCode, i.Name, i.UnitOfMeasure = RationWaterItemCode, "Purified Water", "L"
+		i.CaloriesPerUnit, i.Nutrients = nil, nil
+	})
+	for _, c := range []*models.ResourceCategory{food, water} {
+		if err := resources.CreateCategory(ctx, nil, c); err != nil {
+			t.Fatalf("creating category: %v", err)
+		}
+	}
+	for _, i := range []*models.ResourceItem{ration, purified} {
+		if err := resources.CreateItem(ctx, nil, i); err != nil {
+			t.Fatalf("creating item: %v", err)
+		}
+	}
+	for _, s := range []*models.ResourceStock{
+		testutil.FixtureResourceStock(ration.ID, func(s *models.ResourceStock) { s.Quantity = 1000 }),
+		testutil.FixtureResourceStock(purified.ID, func(s *models.ResourceStock) { s.Quantity = 1000 }),
+	} {
+		if err := resources.CreateStock(ctx, nil, s); err != nil {
+			t.Fatalf("creating stock: %v", err)
+		}
+	}
+
+	household := testutil.FixtureHousehold()
+	if err := repository.NewHouseholdRepository(db.DB).Create(ctx, nil, household); err != nil {
+		t.Fatalf("creating household: %v", err)
+	}
+	for range 2 {
+		resident := testutil.FixtureResident(func(r *models.Resident) { r.HouseholdID = &household.ID })
+		if err := repository.NewResidentRepository(db.DB).Create(ctx, nil, resident); err != nil {
+			t.Fatalf("creating resident: %v", err)
+		}
+	}
+	return svc, db
+}
+
+func TestDeductDailyRations_OncePerDay(t *testing.T) {
+	svc, db := setupRations(t)
+	ctx := context.Background()
+	day := time.Date(2077, 10, 23, 0, 0, 0, 0, time.UTC)
+
+	first, err := svc.DeductDailyRations(ctx, day)
+	if err != nil {
+		t.Fatalf("DeductDailyRations() error = %v", err)
+	}
+	if first.Short() || first.CaloriesDrawn == 0 {
+		t.Fatalf("deduction = %s, want the day's rations drawn in full", first)
+	}
+
+	// A restarted scheduler, or a manual trigger, runs the day again
+	if _, err := svc.DeductDailyRations(ctx, day); !errors.Is(err, repository.ErrDuplicate) {
+		t.Errorf("second DeductDailyRations() error = %v, want ErrDuplicate", err)
+	}
+	msg, err := svc.RationJob().Run(ctx, day)
+	if err != nil || msg != "rations for 2077-10-23 already drawn" {
+		t.Errorf("RationJob().Run() = %q, %v, want the day passed over", msg, err)
+	}
+
+	if n := countRows(t, db, `SELECT COUNT(*) FROM ration_draws`); n != 1 {
+		t.Errorf("ration draws = %d, want 1", n)
+	}
+	consumptions := countRows(t, db, `SELECT COUNT(*) FROM resource_transactions WHERE transaction_type = 'CONSUMPTION'`)
+	if consumptions != 2 {
+		t.Errorf("CONSUMPTION transactions = %d, want one each of food and water", consumptions)
+	}
+
+	// The next day draws again
+	if _, err := svc.DeductDailyRations(ctx, day.AddDate(0, 0, 1)); err != nil {
+		t.Fatalf("next day DeductDailyRations() error = %v", err)
+	}
+	if n := countRows(t, db, `SELECT COUNT(*) FROM ration_draws`); n != 2 {
+		t.Errorf("ration draws = %d, want 2", n)
+	}
+}
@@ -0,0 +1,300 @@
+package resources
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/simulation"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// DefaultReservationHold is how long a reservation holds stock when the
+// caller gives no expiry.
+const DefaultReservationHold = 72 * time.Hour
+
+// ReserveStock holds item quantity for planned consumption. The quantity is
+// taken from available lots soonest-expiring first, like consumption, and
+// stays out of available totals until the reservation is committed,
+// released or expires.
+func (s *Service) ReserveStock(ctx context.Context, input ReservationInput) (_ *models.StockReservation, err error) {
+	ctx, cmd := s.begin(ctx, CommandReserveStock, input)
+	defer func() { cmd.End(err) }()
+
+	now := s.now()
+	res := &models.StockReservation{
+		ID:         s.idGenerator.NewID(),
+		ItemID:     input.ItemID,
+		Quantity:   input.Quantity,
+		Purpose:    input.Purpose,
+		ReservedBy: input.ReservedBy,
+		Status:     models.ReservationStatusActive,
+		ExpiresAt:  input.ExpiresAt,
+	}
+	if res.ExpiresAt.IsZero() {
+		res.ExpiresAt = now.Add(DefaultReservationHold)
+	}
+	if !res.ExpiresAt.After(now) {
+		return nil, fmt.Errorf("%w: reservation would already be expired", repository.ErrValidation)
+	}
+	if input.RelatedEntityType != "" {
+		res.RelatedEntityType = &input.RelatedEntityType
+		res.RelatedEntityID = &input.RelatedEntityID
+	}
+	if err := res.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", repository.ErrValidation, err)
+	}
+
+	filter := models.StockFilter{
+		ItemID: input.ItemID,
+		Status: ptr(models.StockStatusAvailable),
+	}
+	err = repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		stocks, err := s.resources.ListAllStocks(ctx, tx, filter, models.SortOption{})
+		if err != nil {
+			return fmt.Errorf("listing stocks: %w", err)
+		}
+
+		remaining := input.Quantity
+		for _, stock := range stocks {
+			if remaining <= models.QuantityEpsilon {
+				break
+			}
+			available := stock.AvailableQuantity()
+			if available <= models.QuantityEpsilon {
+				continue
+			}
+
+			hold := math.Min(remaining, available)
+			stock.QuantityReserved += hold
+			if err := s.resources.UpdateStock(ctx, tx, stock); err != nil {
+				return fmt.Errorf("holding stock %s: %w", stock.ID, err)
+			}
+			res.Allocations = append(res.Allocations, models.ReservationAllocation{StockID: stock.ID, Quantity: hold})
+			remaining -= hold
+		}
+		if remaining > models.QuantityEpsilon {
+			return fmt.Errorf("%w: insufficient stock: %.2f of %.2f units available",
+				repository.ErrValidation, input.Quantity-remaining, input.Quantity)
+		}
+
+		if err := s.resources.CreateReservation(ctx, tx, res); err != nil {
+			return fmt.Errorf("creating reservation: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// GetReservation retrieves a reservation by ID.
+func (s *Service) GetReservation(ctx context.Context, id string) (*models.StockReservation, error) {
+	return s.resources.GetReservation(ctx, nil, id)
+}
+
+// ListReservations retrieves active reservations, optionally for one item.
+func (s *Service) ListReservations(ctx context.Context, itemID string) ([]*models.StockReservation, error) {
+	return s.resources.ListActiveReservations(ctx, itemID)
+}
+
+// ReleaseReservation cancels a reservation and returns its stock to use.
+func (s *Service) ReleaseReservation(ctx context.Context, id string) (err error) {
+	ctx, cmd := s.begin(ctx, CommandReleaseReservation, idArgs{id})
+	defer func() { cmd.End(err) }()
+
+	return repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		res, err := s.activeReservation(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		return s.closeReservation(ctx, tx, res, models.ReservationStatusReleased)
+	})
+}
+
+// CommitReservation consumes a reservation's stock, recording a CONSUMPTION
+// transaction for each lot it was held in. An expired reservation cannot be
+// committed.
+func (s *Service) CommitReservation(ctx context.Context, id string, authorizedBy *string) (err error) {
+	ctx, cmd := s.begin(ctx, CommandCommitReservation, commitReservationArgs{id, authorizedBy})
+	defer func() { cmd.End(err) }()
+
+	return repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		res, err := s.activeReservation(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		now := s.now()
+		if res.IsExpired(now) {
+			return fmt.Errorf("%w: reservation expired at %s", repository.ErrValidation, util.FormatDateTime(res.ExpiresAt))
+		}
+		return s.commitReservation(ctx, tx, res, authorizedBy, now)
+	})
+}
+
+// commitReservation consumes an active reservation's stock within tx.
+func (s *Service) commitReservation(ctx context.Context, tx *sql.Tx, res *models.StockReservation, authorizedBy *string, now time.Time) error {
+	stocks, err := s.allocatedStocks(ctx, tx, res)
+	if err != nil {
+		return err
+	}
+
+	for i, a := range res.Allocations {
+		stock := stocks[i]
+		stock.Quantity = math.Max(0, stock.Quantity-a.Quantity)
+		stock.QuantityReserved = math.Max(0, stock.QuantityReserved-a.Quantity)
+		if stock.Quantity <= models.QuantityEpsilon {
+			stock.Quantity = 0
+			if stock.Status != models.StockStatusDepleted {
+				stock.Status = models.StockStatusDepleted
+				s.stockDepleted(ctx, stock)
+			}
+		}
+		if err := s.resources.UpdateStock(ctx, tx, stock); err != nil {
+			return fmt.Errorf("consuming stock %s: %w", stock.ID, err)
+		}
+
+		txn := &models.ResourceTransaction{
+			ID:                s.idGenerator.NewID(),
+			StockID:           &stock.ID,
+			ItemID:            res.ItemID,
+			TransactionType:   models.TransactionTypeConsumption,
+			Quantity:          -a.Quantity,
+			BalanceAfter:      stock.Quantity,
+			Reason:            res.Purpose,
+			AuthorizedBy:      authorizedBy,
+			RelatedEntityType: res.RelatedEntityType,
+			RelatedEntityID:   res.RelatedEntityID,
+			Timestamp:         now,
+		}
+		if err := s.resources.CreateTransaction(ctx, tx, txn); err != nil {
+			return fmt.Errorf("recording transaction: %w", err)
+		}
+	}
+
+	res.Status = models.ReservationStatusCommitted
+	res.ClosedAt = &now
+	return s.resources.UpdateReservationStatus(ctx, tx, res)
+}
+
+// ExpireReservations releases active reservations that expired by now and
+// returns them.
+func (s *Service) ExpireReservations(ctx context.Context, now time.Time) (_ []*models.StockReservation, err error) {
+	ctx, cmd := s.begin(ctx, CommandExpireReservations, timeArgs{now})
+	defer func() { cmd.End(err) }()
+
+	expired, err := s.resources.ListExpiredReservations(ctx, now)
+	if err != nil {
+		return nil, fmt.Errorf("listing expired reservations: %w", err)
+	}
+
+	var closed []*models.StockReservation
+	for _, listed := range expired {
+		var res *models.StockReservation
+		err := repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+			current, err := s.resources.GetReservation(ctx, tx, listed.ID)
+			if err != nil {
+				return err
+			}
+			// One committed or released since it was listed holds nothing
+			if !current.IsActive() {
+				return nil
+			}
+			res = current
+			return s.closeReservation(ctx, tx, res, models.ReservationStatusExpired)
+		})
+		if err != nil {
+			return closed, fmt.Errorf("expiring reservation %s: %w", listed.ID, err)
+		}
+		if res != nil {
+			closed = append(closed, res)
+		}
+	}
+	return closed, nil
+}
+
+// activeReservation retrieves a reservation that still holds stock, within
+// tx.
+func (s *Service) activeReservation(ctx context.Context, tx *sql.Tx, id string) (*models.StockReservation, error) {
+	res, err := s.resources.GetReservation(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !res.IsActive() {
+		return nil, fmt.Errorf("%w: reservation is %s", repository.ErrValidation, res.Status)
+	}
+	return res, nil
+}
+
+// allocatedStocks retrieves the stock lots of a reservation's allocations
+// within tx, in allocation order.
+func (s *Service) allocatedStocks(ctx context.Context, tx *sql.Tx, res *models.StockReservation) ([]*models.ResourceStock, error) {
+	stocks := make([]*models.ResourceStock, len(res.Allocations))
+	for i, a := range res.Allocations {
+		stock, err := s.resources.GetStock(ctx, tx, a.StockID)
+		if err != nil {
+			return nil, fmt.Errorf("getting stock %s: %w", a.StockID, err)
+		}
+		stocks[i] = stock
+	}
+	return stocks, nil
+}
+
+// closeReservation ends an active reservation within tx without consuming
+// it, returning the held quantity to its lots.
+func (s *Service) closeReservation(ctx context.Context, tx *sql.Tx, res *models.StockReservation, status models.ReservationStatus) error {
+	stocks, err := s.allocatedStocks(ctx, tx, res)
+	if err != nil {
+		return err
+	}
+
+	for i, a := range res.Allocations {
+		stock := stocks[i]
+		stock.QuantityReserved = math.Max(0, stock.QuantityReserved-a.Quantity)
+		if err := s.resources.UpdateStock(ctx, tx, stock); err != nil {
+			return fmt.Errorf("releasing stock %s: %w", stock.ID, err)
+		}
+	}
+
+	now := s.now()
+	res.Status = status
+	res.ClosedAt = &now
+	return s.resources.UpdateReservationStatus(ctx, tx, res)
+}
+
+// ReservationExpiry is the simulation hook that releases reservations as
+// they expire.
+type ReservationExpiry struct {
+	service *Service
+}
+
+// ReservationExpiry creates the reservation expiry hook.
+func (s *Service) ReservationExpiry() *ReservationExpiry {
+	return &ReservationExpiry{service: s}
+}
+
+// Name implements simulation.Hook.
+func (h *ReservationExpiry) Name() string {
+	return "reservation expiry"
+}
+
+// Advance implements simulation.Hook.
+func (h *ReservationExpiry) Advance(ctx context.Context, from, to time.Time) ([]simulation.Event, error) {
+	expired, err := h.service.ExpireReservations(ctx, to)
+
+	events := make([]simulation.Event, 0, len(expired))
+	for _, res := range expired {
+		events = append(events, simulation.Event{
+			Time:    to,
+			Level:   simulation.EventInfo,
+			Source:  h.Name(),
+			Message: fmt.Sprintf("Reservation expired: %.2f units held for %s released", res.Quantity, res.Purpose),
+		})
+	}
+	return events, err
+}
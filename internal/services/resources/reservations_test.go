@@ -0,0 +1,104 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/testutil"
+)
+
+func TestCommitReservation_Once(t *testing.T) {
+	svc, _ := setupService(t)
+	ctx := context.Background()
+	stock := createStock(t, svc, 100)
+
+	res, err := svc.ReserveStock(ctx, ReservationInput{ItemID: stock.ItemID, Quantity: 30, Purpose: "Test"})
+	if err != nil {
+		t.Fatalf("ReserveStock() error = %v", err)
+	}
+	if err := svc.CommitReservation(ctx, res.ID, nil); err != nil {
+		t.Fatalf("CommitReservation() error = %v", err)
+	}
+
+	// A closed reservation holds nothing to consume or return
+	if err := svc.CommitReservation(ctx, res.ID, nil); !errors.Is(err, repository.ErrValidation) {
+		t.Errorf("second CommitReservation() error = %v, want ErrValidation", err)
+	}
+	if err := svc.ReleaseReservation(ctx, res.ID); !errors.Is(err, repository.ErrValidation) {
+		t.Errorf("ReleaseReservation() of a committed reservation error = %v, want ErrValidation", err)
+	}
+
+	got, err := svc.GetStock(ctx, stock.ID)
+	if err != nil {
+		t.Fatalf("GetStock() error = %v", err)
+	}
+	if got.Quantity != 70 || got.QuantityReserved != 0 {
+		t.Errorf("stock = %.0f with %.0f reserved, want 70 with none", got.Quantity, got.QuantityReserved)
+	}
+}
+
+func TestReserveStock_Concurrent(t *testing.T) {
+	svc, _ := setupService(t)
+	ctx := context.Background()
+	stock := createStock(t, svc, 50)
+
+	// Twenty reservations of 5 contend for 50 units: ten fit
+	const reservers = 20
+	var wg sync.WaitGroup
+	results := make(chan error, reservers)
+	for range reservers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := svc.ReserveStock(ctx, ReservationInput{ItemID: stock.ItemID, Quantity: 5, Purpose: "Test"})
+			results <- err
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	var reserved int
+	for err := range results {
+		switch {
+		case err == nil:
+			reserved++
+		case !errors.Is(err, repository.ErrValidation):
+			t.Fatalf("ReserveStock() error = %v", err)
+		}
+	}
+	if reserved != 10 {
+		t.Errorf("reservations made = %d, want 10", reserved)
+	}
+	got, err := svc.GetStock(ctx, stock.ID)
+	if err != nil {
+		t.Fatalf("GetStock() error = %v", err)
+	}
+	if got.QuantityReserved != 50 {
+		t.Errorf("quantity reserved = %.0f, want 50", got.QuantityReserved)
+	}
+}
+
+func TestReserveStock_ManyLots(t *testing.T) {
+	svc, _ := setupService(t)
+	ctx := context.Background()
+	first := createStock(t, svc, 1)
+	for range 119 {
+		lot := testutil.FixtureResourceStock(first.ItemID, func(s *models.ResourceStock) { s.Quantity = 1 })
+		if err := svc.resources.CreateStock(ctx, nil, lot); err != nil {
+			t.Fatalf("creating stock: %v", err)
+		}
+	}
+
+	// More lots than a page holds
+	res, err := svc.ReserveStock(ctx, ReservationInput{ItemID: first.ItemID, Quantity: 110, Purpose: "Test"})
+	if err != nil {
+		t.Fatalf("ReserveStock() error = %v", err)
+	}
+	if len(res.Allocations) != 110 {
+		t.Errorf("allocations = %d, want 110", len(res.Allocations))
+	}
+}
@@ -0,0 +1,69 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// CategoryRunway is how long the available stock of a consumable resource
+// category lasts at its recent rate of consumption, with the consumption
+// history the rate is taken from.
+type CategoryRunway struct {
+	Code             string // Category code, e.g. "FOOD"
+	Name             string
+	Unit             string
+	Stock            float64   // Available for use
+	DailyConsumption float64   // Mean over History
+	DaysRemaining    int       // -1 when nothing is consumed
+	History          []float64 // Quantity consumed each day, oldest first
+}
+
+// CategoryRunways returns the runway of each consumable resource category,
+// in category code order, from its consumption on each of the days days up
+// to and including the day of at.
+func (s *Service) CategoryRunways(ctx context.Context, at time.Time, days int) ([]*CategoryRunway, error) {
+	if days < 1 {
+		days = 1
+	}
+	categories, err := s.categories.All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing categories: %w", err)
+	}
+	stock, err := s.resources.GetAvailableStockByCategory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	to := at.UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+	from := to.AddDate(0, 0, -days)
+	consumed, err := s.resources.GetConsumptionByCategoryDay(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var runways []*CategoryRunway
+	for _, cat := range categories {
+		if !cat.IsConsumable {
+			continue
+		}
+		runway := &CategoryRunway{
+			Code:          cat.Code,
+			Name:          cat.Name,
+			Unit:          cat.UnitOfMeasure,
+			Stock:         stock[cat.Code],
+			DaysRemaining: -1,
+			History:       make([]float64, days),
+		}
+		var total float64
+		for i := range runway.History {
+			runway.History[i] = consumed[cat.Code][from.AddDate(0, 0, i).Format(time.DateOnly)]
+			total += runway.History[i]
+		}
+		runway.DailyConsumption = total / float64(days)
+		if runway.DailyConsumption > 0 {
+			runway.DaysRemaining = int(runway.Stock / runway.DailyConsumption)
+		}
+		runways = append(runways, runway)
+	}
+	return runways, nil
+}
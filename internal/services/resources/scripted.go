@@ -0,0 +1,75 @@
+package resources
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/simulation"
+)
+
+// ScriptActionStockLoss is the scenario script action that destroys part of
+// an item's stock, e.g. a contaminated water tank or a blighted harvest.
+const ScriptActionStockLoss = "stock_loss"
+
+// LoseStock destroys percent of the unreserved quantity of every available
+// lot of an item, recording each loss as an ADJUSTMENT with reason. It
+// returns the quantity lost. Lost stock is not spoilage: no operator let it
+// expire.
+func (s *Service) LoseStock(ctx context.Context, itemCode string, percent float64, reason string) (_ float64, err error) {
+	ctx, cmd := s.begin(ctx, CommandLoseStock, loseStockArgs{itemCode, percent, reason})
+	defer func() { cmd.End(err) }()
+
+	if percent <= 0 || percent > 100 {
+		return 0, fmt.Errorf("%w: percent lost must be above 0 and at most 100", repository.ErrValidation)
+	}
+	item, err := s.resources.GetItemByCode(ctx, itemCode)
+	if err != nil {
+		return 0, fmt.Errorf("item %s: %w", itemCode, err)
+	}
+
+	var lost float64
+	err = repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		stocks, err := s.availableStocks(ctx, tx, models.StockFilter{ItemID: item.ID})
+		if err != nil {
+			return err
+		}
+		for _, stock := range stocks {
+			loss := stock.AvailableQuantity() * percent / 100
+			if loss <= models.QuantityEpsilon {
+				continue
+			}
+			if err := s.adjustStock(ctx, tx, stock, StockAdjustment{
+				QuantityChange: -loss,
+				Type:           models.TransactionTypeAdjustment,
+				Reason:         "Lost: " + reason,
+			}); err != nil {
+				return fmt.Errorf("stock %s: %w", stock.ID, err)
+			}
+			lost += loss
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return lost, nil
+}
+
+// ScriptedLoss handles the stock_loss action of scenario scripts.
+func (s *Service) ScriptedLoss() simulation.ScriptHandler {
+	return func(ctx context.Context, event simulation.ScriptEvent, _ time.Time) (string, error) {
+		reason := "scenario script"
+		if event.Message != "" {
+			reason = event.Message
+		}
+		lost, err := s.LoseStock(ctx, event.Item, event.Percent, reason)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%.2f of %s lost", lost, event.Item), nil
+	}
+}
@@ -4,9 +4,13 @@ package resources
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/vtuos/vtuos/internal/dualauth"
+	"github.com/vtuos/vtuos/internal/events"
+	"github.com/vtuos/vtuos/internal/journal"
 	"github.com/vtuos/vtuos/internal/models"
 	"github.com/vtuos/vtuos/internal/repository"
 	"github.com/vtuos/vtuos/internal/util"
@@ -16,28 +20,88 @@ import (
 type Service struct {
 	db          *sql.DB
 	resources   *repository.ResourceRepository
+	audits      *repository.InventoryAuditRepository
+	quality     *repository.QualityTestRepository
+	production  *repository.ProductionRunRepository
+	draws       *repository.RationDrawRepository
+	assets      *repository.AssetRepository
+	policies    *repository.RationPolicyRepository
 	households  *repository.HouseholdRepository
 	residents   *repository.ResidentRepository
+	vocations   *repository.VocationRepository
+	categories  *util.RefCache[*models.ResourceCategory]
+	authorizer  *dualauth.Authorizer
 	idGenerator *util.IDGenerator
+	journal     *journal.Journal
+	events      *events.Bus
+	vault       int
+	now         func() time.Time
+	policy      models.ConsumptionPolicy
 }
 
 // NewService creates a new resource service.
 func NewService(db *sql.DB) *Service {
+	resources := repository.NewResourceRepository(db)
 	return &Service{
 		db:          db,
-		resources:   repository.NewResourceRepository(db),
+		resources:   resources,
+		audits:      repository.NewInventoryAuditRepository(db),
+		quality:     repository.NewQualityTestRepository(db),
+		production:  repository.NewProductionRunRepository(db),
+		draws:       repository.NewRationDrawRepository(db),
+		assets:      repository.NewAssetRepository(db),
+		policies:    repository.NewRationPolicyRepository(db),
 		households:  repository.NewHouseholdRepository(db),
 		residents:   repository.NewResidentRepository(db),
+		vocations:   repository.NewVocationRepository(db),
+		categories:  util.NewRefCache(resources.ListCategories),
+		authorizer:  dualauth.New(db),
 		idGenerator: util.NewIDGenerator(),
+		now:         func() time.Time { return time.Now().UTC() },
+		policy:      models.ConsumptionPolicyFEFO,
 	}
 }
 
+// SetClock makes the service timestamp transactions with vault time.
+func (s *Service) SetClock(clock *util.VaultClock) {
+	s.now = clock.Now
+}
+
+// SetVault limits the service's stock, consumption, rations, production
+// runs and assets to the vault with the given number. A new service covers
+// every vault.
+func (s *Service) SetVault(vault int) {
+	s.vault = vault
+	s.resources = s.resources.ForVault(vault)
+	s.assets = s.assets.ForVault(vault)
+	s.production = s.production.ForVault(vault)
+	s.draws = s.draws.ForVault(vault)
+	s.households = s.households.ForVault(vault)
+	s.residents = s.residents.ForVault(vault)
+	s.authorizer = s.authorizer.ForVault(vault)
+}
+
+// SetConsumptionPolicy sets the order consumption draws lots in when the
+// input does not choose one. The default is FEFO.
+func (s *Service) SetConsumptionPolicy(policy models.ConsumptionPolicy) {
+	s.policy = policy
+}
+
+// InvalidateCache drops cached reference data. Call it after writing
+// categories other than through this service, e.g. after a restore.
+func (s *Service) InvalidateCache() {
+	s.categories.Invalidate()
+}
+
 // ============================================================================
 // CATEGORIES
 // ============================================================================
 
 // CreateCategory creates a new resource category.
-func (s *Service) CreateCategory(ctx context.Context, input CreateCategoryInput) (*models.ResourceCategory, error) {
+func (s *Service) CreateCategory(ctx context.Context, input CreateCategoryInput) (_ *models.ResourceCategory, err error) {
+	ctx, cmd := s.begin(ctx, CommandCreateCategory, input)
+	defer func() { cmd.End(err) }()
+
 	cat := &models.ResourceCategory{
 		ID:            s.idGenerator.NewID(),
 		Code:          input.Code,
@@ -47,27 +111,75 @@ func (s *Service) CreateCategory(ctx context.Context, input CreateCategoryInput)
 		IsConsumable:  input.IsConsumable,
 		IsCritical:    input.IsCritical,
 	}
+	if err := cat.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", repository.ErrValidation, err)
+	}
 
 	if err := s.resources.CreateCategory(ctx, nil, cat); err != nil {
 		return nil, fmt.Errorf("creating category: %w", err)
 	}
+	s.categories.Invalidate()
+
+	return cat, nil
+}
+
+// UpdateCategory edits a category's name, description, unit and flags.
+func (s *Service) UpdateCategory(ctx context.Context, id string, input UpdateCategoryInput) (_ *models.ResourceCategory, err error) {
+	ctx, cmd := s.begin(ctx, CommandUpdateCategory, updateCategoryArgs{id, input})
+	defer func() { cmd.End(err) }()
+
+	cat, err := s.resources.GetCategory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	cat.Name = input.Name
+	cat.Description = input.Description
+	cat.UnitOfMeasure = input.UnitOfMeasure
+	cat.IsConsumable = input.IsConsumable
+	cat.IsCritical = input.IsCritical
+	if err := cat.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", repository.ErrValidation, err)
+	}
+
+	if err := s.resources.UpdateCategory(ctx, nil, cat); err != nil {
+		return nil, fmt.Errorf("updating category: %w", err)
+	}
+	s.categories.Invalidate()
 
 	return cat, nil
 }
 
-// GetCategory retrieves a category by ID.
+// GetCategory retrieves a category by ID from the category cache.
 func (s *Service) GetCategory(ctx context.Context, id string) (*models.ResourceCategory, error) {
-	return s.resources.GetCategory(ctx, id)
+	cat, ok, err := s.categories.Find(ctx, func(c *models.ResourceCategory) bool { return c.ID == id })
+	if err != nil {
+		return nil, fmt.Errorf("loading categories: %w", err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("category %w: %s", repository.ErrNotFound, id)
+	}
+	return cat, nil
 }
 
-// GetCategoryByCode retrieves a category by code.
+// GetCategoryByCode retrieves a category by code from the category cache.
 func (s *Service) GetCategoryByCode(ctx context.Context, code string) (*models.ResourceCategory, error) {
-	return s.resources.GetCategoryByCode(ctx, code)
+	cat, ok, err := s.categories.Find(ctx, func(c *models.ResourceCategory) bool { return c.Code == code })
+	if err != nil {
+		return nil, fmt.Errorf("loading categories: %w", err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("category %w: %s", repository.ErrNotFound, code)
+	}
+	return cat, nil
 }
 
-// ListCategories retrieves all resource categories.
+// ListCategories retrieves all resource categories from the category cache.
 func (s *Service) ListCategories(ctx context.Context) ([]*models.ResourceCategory, error) {
-	return s.resources.ListCategories(ctx)
+	cats, err := s.categories.All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("loading categories: %w", err)
+	}
+	return append([]*models.ResourceCategory(nil), cats...), nil
 }
 
 // ============================================================================
@@ -75,7 +187,10 @@ func (s *Service) ListCategories(ctx context.Context) ([]*models.ResourceCategor
 // ============================================================================
 
 // CreateItem creates a new resource item.
-func (s *Service) CreateItem(ctx context.Context, input CreateItemInput) (*models.ResourceItem, error) {
+func (s *Service) CreateItem(ctx context.Context, input CreateItemInput) (_ *models.ResourceItem, err error) {
+	ctx, cmd := s.begin(ctx, CommandCreateItem, input)
+	defer func() { cmd.End(err) }()
+
 	item := &models.ResourceItem{
 		ID:                   s.idGenerator.NewID(),
 		CategoryID:           input.CategoryID,
@@ -84,10 +199,16 @@ func (s *Service) CreateItem(ctx context.Context, input CreateItemInput) (*model
 		Description:          input.Description,
 		UnitOfMeasure:        input.UnitOfMeasure,
 		CaloriesPerUnit:      input.CaloriesPerUnit,
+		Nutrients:            input.Nutrients,
 		ShelfLifeDays:        input.ShelfLifeDays,
 		StorageRequirements:  input.StorageRequirements,
 		IsProducible:         input.IsProducible,
 		ProductionRatePerDay: input.ProductionRatePerDay,
+		MinStock:             input.MinStock,
+		TargetStock:          input.TargetStock,
+	}
+	if err := s.validItem(ctx, item); err != nil {
+		return nil, err
 	}
 
 	if err := s.resources.CreateItem(ctx, nil, item); err != nil {
@@ -97,6 +218,54 @@ func (s *Service) CreateItem(ctx context.Context, input CreateItemInput) (*model
 	return item, nil
 }
 
+// UpdateItem edits an item's category, description, unit, nutrition, shelf
+// life, production and stock thresholds.
+func (s *Service) UpdateItem(ctx context.Context, id string, input UpdateItemInput) (_ *models.ResourceItem, err error) {
+	ctx, cmd := s.begin(ctx, CommandUpdateItem, updateItemArgs{id, input})
+	defer func() { cmd.End(err) }()
+
+	item, err := s.resources.GetItem(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	item.CategoryID = input.CategoryID
+	item.Name = input.Name
+	item.Description = input.Description
+	item.UnitOfMeasure = input.UnitOfMeasure
+	item.CaloriesPerUnit = input.CaloriesPerUnit
+	item.Nutrients = input.Nutrients
+	item.ShelfLifeDays = input.ShelfLifeDays
+	item.StorageRequirements = input.StorageRequirements
+	item.IsProducible = input.IsProducible
+	item.ProductionRatePerDay = input.ProductionRatePerDay
+	item.MinStock = input.MinStock
+	item.TargetStock = input.TargetStock
+	if err := s.validItem(ctx, item); err != nil {
+		return nil, err
+	}
+
+	if err := s.resources.UpdateItem(ctx, nil, item); err != nil {
+		return nil, fmt.Errorf("updating item: %w", err)
+	}
+	item.Category, _ = s.GetCategory(ctx, item.CategoryID)
+
+	return item, nil
+}
+
+// validItem validates an item and checks that its category exists.
+func (s *Service) validItem(ctx context.Context, item *models.ResourceItem) error {
+	if err := item.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", repository.ErrValidation, err)
+	}
+	if _, err := s.GetCategory(ctx, item.CategoryID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return fmt.Errorf("%w: category %s does not exist", repository.ErrValidation, item.CategoryID)
+		}
+		return err
+	}
+	return nil
+}
+
 // GetItem retrieves an item by ID.
 func (s *Service) GetItem(ctx context.Context, id string) (*models.ResourceItem, error) {
 	return s.resources.GetItem(ctx, id)
@@ -117,7 +286,10 @@ func (s *Service) ListItems(ctx context.Context, categoryID string, page models.
 // ============================================================================
 
 // CreateStock creates a new stock record.
-func (s *Service) CreateStock(ctx context.Context, input CreateStockInput) (*models.ResourceStock, error) {
+func (s *Service) CreateStock(ctx context.Context, input CreateStockInput) (_ *models.ResourceStock, err error) {
+	ctx, cmd := s.begin(ctx, CommandCreateStock, input)
+	defer func() { cmd.End(err) }()
+
 	stock := &models.ResourceStock{
 		ID:              s.idGenerator.NewID(),
 		ItemID:          input.ItemID,
@@ -129,93 +301,198 @@ func (s *Service) CreateStock(ctx context.Context, input CreateStockInput) (*mod
 		Status:          models.StockStatusAvailable,
 	}
 
-	if err := s.resources.CreateStock(ctx, nil, stock); err != nil {
-		return nil, fmt.Errorf("creating stock: %w", err)
+	err = repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		return s.createStock(ctx, tx, stock, "Initial stock receipt", nil)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// Record the receipt transaction
+	return stock, nil
+}
+
+// createStock inserts a new lot within tx with the PRODUCTION transaction
+// receiving its quantity.
+func (s *Service) createStock(ctx context.Context, tx *sql.Tx, stock *models.ResourceStock, reason string, authorizedBy *string) error {
 	txn := &models.ResourceTransaction{
 		ID:              s.idGenerator.NewID(),
 		StockID:         &stock.ID,
-		ItemID:          input.ItemID,
+		ItemID:          stock.ItemID,
 		TransactionType: models.TransactionTypeProduction,
-		Quantity:        input.Quantity,
-		BalanceAfter:    input.Quantity,
-		Reason:          "Initial stock receipt",
+		Quantity:        stock.Quantity,
+		BalanceAfter:    stock.Quantity,
+		Reason:          reason,
+		AuthorizedBy:    authorizedBy,
+		Timestamp:       s.now(),
 	}
-	if err := s.resources.CreateTransaction(ctx, nil, txn); err != nil {
-		return nil, fmt.Errorf("recording receipt transaction: %w", err)
+	if err := s.resources.CreateStock(ctx, tx, stock); err != nil {
+		return fmt.Errorf("creating stock: %w", err)
 	}
-
-	return stock, nil
+	if err := s.resources.CreateTransaction(ctx, tx, txn); err != nil {
+		return fmt.Errorf("recording receipt transaction: %w", err)
+	}
+	return nil
 }
 
 // GetStock retrieves a stock by ID.
 func (s *Service) GetStock(ctx context.Context, id string) (*models.ResourceStock, error) {
-	return s.resources.GetStock(ctx, id)
+	return s.resources.GetStock(ctx, nil, id)
 }
 
 // ListStocks retrieves stocks with filtering and pagination.
 func (s *Service) ListStocks(ctx context.Context, filter models.StockFilter, page models.Pagination) (*models.StockList, error) {
-	return s.resources.ListStocks(ctx, filter, page)
+	return s.resources.ListStocks(ctx, nil, filter, page)
 }
 
-// AdjustStock adjusts the quantity of a stock.
-func (s *Service) AdjustStock(ctx context.Context, stockID string, adjustment StockAdjustment) error {
-	stock, err := s.resources.GetStock(ctx, stockID)
-	if err != nil {
-		return fmt.Errorf("getting stock: %w", err)
+// AdjustStock adjusts the quantity of a stock. Taking units off other than
+// by consumption is a write-off, which a dual authorization policy may
+// require a second operator to confirm.
+func (s *Service) AdjustStock(ctx context.Context, stockID string, adjustment StockAdjustment) (err error) {
+	ctx, cmd := s.begin(ctx, CommandAdjustStock, adjustStockArgs{stockID, adjustment})
+	defer func() { cmd.End(err) }()
+
+	var auth *dualauth.Authorization
+	if adjustment.QuantityChange < 0 && adjustment.Type != models.TransactionTypeConsumption {
+		if auth, err = s.authorizer.Authorize(ctx, nil, models.DualAuthStockWriteOff, -adjustment.QuantityChange); err != nil {
+			return err
+		}
 	}
 
+	return repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		stock, err := s.resources.GetStock(ctx, tx, stockID)
+		if err != nil {
+			return fmt.Errorf("getting stock: %w", err)
+		}
+		if err := s.adjustStock(ctx, tx, stock, adjustment); err != nil {
+			return err
+		}
+		return s.authorizer.Record(ctx, tx, auth, models.AuditEntityStock, stock.ID, s.now())
+	})
+}
+
+// adjustStock applies an adjustment to a stock loaded within tx and records
+// its transaction.
+func (s *Service) adjustStock(ctx context.Context, tx *sql.Tx, stock *models.ResourceStock, adjustment StockAdjustment) error {
 	newQty := stock.Quantity + adjustment.QuantityChange
 	if newQty < 0 {
-		return fmt.Errorf("adjustment would result in negative quantity")
+		return fmt.Errorf("%w: adjustment would result in negative quantity", repository.ErrValidation)
+	}
+	if adjustment.QuantityChange < 0 && newQty < stock.QuantityReserved-models.QuantityEpsilon {
+		return fmt.Errorf("%w: adjustment would cut into %.2f reserved units", repository.ErrValidation, stock.QuantityReserved)
 	}
 
 	stock.Quantity = newQty
-	if newQty == 0 {
+	if newQty == 0 && stock.Status != models.StockStatusDepleted {
 		stock.Status = models.StockStatusDepleted
+		s.stockDepleted(ctx, stock)
 	}
 
-	if err := s.resources.UpdateStock(ctx, nil, stock); err != nil {
+	if err := s.resources.UpdateStock(ctx, tx, stock); err != nil {
 		return fmt.Errorf("updating stock: %w", err)
 	}
 
 	// Record the transaction
 	txn := &models.ResourceTransaction{
 		ID:              s.idGenerator.NewID(),
-		StockID:         &stockID,
+		StockID:         &stock.ID,
 		ItemID:          stock.ItemID,
 		TransactionType: adjustment.Type,
 		Quantity:        adjustment.QuantityChange,
 		BalanceAfter:    newQty,
 		Reason:          adjustment.Reason,
 		AuthorizedBy:    adjustment.AuthorizedBy,
+		Timestamp:       s.now(),
 	}
-	if err := s.resources.CreateTransaction(ctx, nil, txn); err != nil {
+	if adjustment.RelatedEntityType != "" {
+		txn.RelatedEntityType = &adjustment.RelatedEntityType
+		txn.RelatedEntityID = &adjustment.RelatedEntityID
+	}
+	if err := s.resources.CreateTransaction(ctx, tx, txn); err != nil {
 		return fmt.Errorf("recording transaction: %w", err)
 	}
 
 	return nil
 }
 
-// RecordConsumption records resource consumption.
-func (s *Service) RecordConsumption(ctx context.Context, input ConsumptionInput) error {
-	// Find available stock (FIFO - oldest first by expiration/received date)
-	filter := models.StockFilter{
-		ItemID: input.ItemID,
-		Status: ptr(models.StockStatusAvailable),
+// MoveStock moves a stock to a new storage location and records a TRANSFER
+// transaction for the move.
+func (s *Service) MoveStock(ctx context.Context, stockID, location string, authorizedBy *string) (err error) {
+	ctx, cmd := s.begin(ctx, CommandMoveStock, moveStockArgs{stockID, location, authorizedBy})
+	defer func() { cmd.End(err) }()
+
+	if location == "" {
+		return fmt.Errorf("%w: storage location is required", repository.ErrValidation)
 	}
-	stocks, err := s.resources.ListStocks(ctx, filter, models.Pagination{Page: 1, PageSize: 100})
+
+	return repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		stock, err := s.resources.GetStock(ctx, tx, stockID)
+		if err != nil {
+			return fmt.Errorf("getting stock: %w", err)
+		}
+		return s.moveStock(ctx, tx, stock, location, authorizedBy)
+	})
+}
+
+// moveStock moves a lot to location within tx, recording the transfer. A
+// lot already there is left alone.
+func (s *Service) moveStock(ctx context.Context, tx *sql.Tx, stock *models.ResourceStock, location string, authorizedBy *string) error {
+	if stock.StorageLocation == location {
+		return nil
+	}
+
+	previous := stock.StorageLocation
+	stock.StorageLocation = location
+	txn := &models.ResourceTransaction{
+		ID:              s.idGenerator.NewID(),
+		StockID:         &stock.ID,
+		ItemID:          stock.ItemID,
+		TransactionType: models.TransactionTypeTransfer,
+		Quantity:        0,
+		BalanceAfter:    stock.Quantity,
+		Reason:          fmt.Sprintf("Moved from %s to %s", previous, location),
+		AuthorizedBy:    authorizedBy,
+		Timestamp:       s.now(),
+	}
+
+	if err := s.resources.UpdateStock(ctx, tx, stock); err != nil {
+		return fmt.Errorf("updating stock: %w", err)
+	}
+	if err := s.resources.CreateTransaction(ctx, tx, txn); err != nil {
+		return fmt.Errorf("recording transaction: %w", err)
+	}
+	return nil
+}
+
+// RecordConsumption records resource consumption, drawing lots in the order
+// of the input's consumption policy, or the service's when it has none. The
+// draws from every lot commit together, or not at all when stock runs short.
+func (s *Service) RecordConsumption(ctx context.Context, input ConsumptionInput) (err error) {
+	ctx, cmd := s.begin(ctx, CommandRecordConsumption, input)
+	defer func() { cmd.End(err) }()
+
+	return repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		return s.ApplyConsumption(ctx, tx, input)
+	})
+}
+
+// ApplyConsumption draws input's quantity within tx, from the available lots
+// in the order of its consumption policy, or the service's when it has none.
+// Other services call it within their own transactions. It fails with
+// ErrValidation when the lots run short.
+func (s *Service) ApplyConsumption(ctx context.Context, tx *sql.Tx, input ConsumptionInput) error {
+	stocks, err := s.planConsumption(ctx, tx, input)
 	if err != nil {
-		return fmt.Errorf("listing stocks: %w", err)
+		return err
 	}
 
 	remaining := input.Quantity
-	for _, stock := range stocks.Stocks {
+	for _, stock := range stocks {
 		if remaining <= 0 {
 			break
 		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 
 		available := stock.AvailableQuantity()
 		if available <= 0 {
@@ -228,12 +505,14 @@ func (s *Service) RecordConsumption(ctx context.Context, input ConsumptionInput)
 		}
 
 		adjustment := StockAdjustment{
-			QuantityChange: -consume,
-			Type:           models.TransactionTypeConsumption,
-			Reason:         input.Reason,
-			AuthorizedBy:   input.AuthorizedBy,
+			QuantityChange:    -consume,
+			Type:              models.TransactionTypeConsumption,
+			Reason:            input.Reason,
+			AuthorizedBy:      input.AuthorizedBy,
+			RelatedEntityType: input.RelatedEntityType,
+			RelatedEntityID:   input.RelatedEntityID,
 		}
-		if err := s.AdjustStock(ctx, stock.ID, adjustment); err != nil {
+		if err := s.adjustStock(ctx, tx, stock, adjustment); err != nil {
 			return fmt.Errorf("consuming from stock %s: %w", stock.ID, err)
 		}
 
@@ -241,29 +520,55 @@ func (s *Service) RecordConsumption(ctx context.Context, input ConsumptionInput)
 	}
 
 	if remaining > 0 {
-		return fmt.Errorf("insufficient stock: %.2f units remaining", remaining)
+		return fmt.Errorf("%w: insufficient stock: %.2f units remaining", repository.ErrValidation, remaining)
 	}
-
 	return nil
 }
 
-// RecordProduction records resource production.
-func (s *Service) RecordProduction(ctx context.Context, input ProductionInput) (*models.ResourceStock, error) {
+// planConsumption lists, within tx, the available lots consumption of input
+// draws from, in the order of its policy.
+func (s *Service) planConsumption(ctx context.Context, tx *sql.Tx, input ConsumptionInput) ([]*models.ResourceStock, error) {
+	policy := input.Policy
+	if policy == "" {
+		policy = s.policy
+	}
+	if !policy.Valid() {
+		return nil, fmt.Errorf("%w: invalid consumption policy: %s", repository.ErrValidation, policy)
+	}
+
+	filter := models.StockFilter{
+		ItemID: input.ItemID,
+		Status: ptr(models.StockStatusAvailable),
+	}
+	stocks, err := s.resources.ListAllStocks(ctx, tx, filter, policy.StockOrder())
+	if err != nil {
+		return nil, fmt.Errorf("listing stocks: %w", err)
+	}
+	return stocks, nil
+}
+
+// RecordProduction records resource production, completing the production
+// run queued for the item, if any, with the lot produced.
+func (s *Service) RecordProduction(ctx context.Context, input ProductionInput) (_ *models.ResourceStock, err error) {
+	ctx, cmd := s.begin(ctx, CommandRecordProduction, input)
+	defer func() { cmd.End(err) }()
+
+	run, err := s.production.GetQueued(ctx, input.ItemID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("getting production run: %w", err)
+	}
+
 	stock := &models.ResourceStock{
 		ID:              s.idGenerator.NewID(),
 		ItemID:          input.ItemID,
 		LotNumber:       input.LotNumber,
 		Quantity:        input.Quantity,
 		StorageLocation: input.StorageLocation,
-		ReceivedDate:    time.Now(),
+		ReceivedDate:    s.now(),
 		ExpirationDate:  input.ExpirationDate,
 		Status:          models.StockStatusAvailable,
 	}
 
-	if err := s.resources.CreateStock(ctx, nil, stock); err != nil {
-		return nil, fmt.Errorf("creating stock: %w", err)
-	}
-
 	txn := &models.ResourceTransaction{
 		ID:              s.idGenerator.NewID(),
 		StockID:         &stock.ID,
@@ -273,9 +578,29 @@ func (s *Service) RecordProduction(ctx context.Context, input ProductionInput) (
 		BalanceAfter:    input.Quantity,
 		Reason:          input.Reason,
 		AuthorizedBy:    input.AuthorizedBy,
+		Timestamp:       s.now(),
 	}
-	if err := s.resources.CreateTransaction(ctx, nil, txn); err != nil {
-		return nil, fmt.Errorf("recording production transaction: %w", err)
+
+	err = repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		if err := s.resources.CreateStock(ctx, tx, stock); err != nil {
+			return fmt.Errorf("creating stock: %w", err)
+		}
+		if err := s.resources.CreateTransaction(ctx, tx, txn); err != nil {
+			return fmt.Errorf("recording production transaction: %w", err)
+		}
+		if run != nil {
+			closed := s.now()
+			run.Status = models.ProductionRunCompleted
+			run.ClosedAt = &closed
+			run.StockID = &stock.ID
+			if err := s.production.Close(ctx, tx, run); err != nil {
+				return fmt.Errorf("completing production run: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return stock, nil
@@ -296,7 +621,10 @@ func (s *Service) GetExpiringItems(ctx context.Context, withinDays int) ([]*mode
 }
 
 // ProcessExpiredItems marks expired items and creates spoilage transactions.
-func (s *Service) ProcessExpiredItems(ctx context.Context, now time.Time) (int, error) {
+func (s *Service) ProcessExpiredItems(ctx context.Context, now time.Time) (_ int, err error) {
+	ctx, cmd := s.begin(ctx, CommandProcessExpiredItems, timeArgs{now})
+	defer func() { cmd.End(err) }()
+
 	// Get items expiring today or earlier
 	stocks, err := s.resources.GetExpiringStocks(ctx, 0)
 	if err != nil {
@@ -305,24 +633,42 @@ func (s *Service) ProcessExpiredItems(ctx context.Context, now time.Time) (int,
 
 	count := 0
 	for _, stock := range stocks {
+		// A failed lot is skipped, but a cancelled sweep stops
+		if err := ctx.Err(); err != nil {
+			return count, err
+		}
 		if stock.ExpirationDate != nil && now.After(*stock.ExpirationDate) {
-			// Mark as expired
-			stock.Status = models.StockStatusExpired
-			if err := s.resources.UpdateStock(ctx, nil, stock); err != nil {
+			// Mark as expired and record the spoilage together, as the lot
+			// stands when the write-off takes its turn
+			expired := false
+			err := repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+				stock, err := s.resources.GetStock(ctx, tx, stock.ID)
+				if err != nil {
+					return err
+				}
+				if stock.Status != models.StockStatusAvailable {
+					return nil
+				}
+				stock.Status = models.StockStatusExpired
+				txn := &models.ResourceTransaction{
+					ID:              s.idGenerator.NewID(),
+					StockID:         &stock.ID,
+					ItemID:          stock.ItemID,
+					TransactionType: models.TransactionTypeSpoilage,
+					Quantity:        -stock.Quantity,
+					BalanceAfter:    0,
+					Reason:          "Expired",
+					Timestamp:       now,
+				}
+				if err := s.resources.UpdateStock(ctx, tx, stock); err != nil {
+					return err
+				}
+				expired = true
+				return s.resources.CreateTransaction(ctx, tx, txn)
+			})
+			if err != nil || !expired {
 				continue
 			}
-
-			// Record spoilage transaction
-			txn := &models.ResourceTransaction{
-				ID:              s.idGenerator.NewID(),
-				StockID:         &stock.ID,
-				ItemID:          stock.ItemID,
-				TransactionType: models.TransactionTypeSpoilage,
-				Quantity:        -stock.Quantity,
-				BalanceAfter:    0,
-				Reason:          "Expired",
-			}
-			s.resources.CreateTransaction(ctx, nil, txn)
 			count++
 		}
 	}
@@ -330,6 +676,12 @@ func (s *Service) ProcessExpiredItems(ctx context.Context, now time.Time) (int,
 	return count, nil
 }
 
+// GetAvailableQuantity returns the quantity of an item available for use:
+// available stock less what active reservations hold.
+func (s *Service) GetAvailableQuantity(ctx context.Context, itemID string) (float64, error) {
+	return s.resources.GetTotalStockByItem(ctx, itemID)
+}
+
 // GetResourceRunway calculates how long resources will last.
 func (s *Service) GetResourceRunway(ctx context.Context, itemID string) (*models.RunwayProjection, error) {
 	// Get total available stock
@@ -383,7 +735,8 @@ func (s *Service) GetResourceRunway(ctx context.Context, itemID string) (*models
 // RATIONING
 // ============================================================================
 
-// CalculateHouseholdAllocation calculates resource allocation for a household.
+// CalculateHouseholdAllocation calculates resource allocation for a household
+// under the ration policy of its class in force now.
 func (s *Service) CalculateHouseholdAllocation(ctx context.Context, householdID string) (*models.RationAllocation, error) {
 	household, err := s.households.GetByID(ctx, householdID)
 	if err != nil {
@@ -396,9 +749,14 @@ func (s *Service) CalculateHouseholdAllocation(ctx context.Context, householdID
 		return nil, fmt.Errorf("getting members: %w", err)
 	}
 
+	targets, err := s.policies.Active(ctx, s.now())
+	if err != nil {
+		return nil, err
+	}
+
 	// Calculate totals based on ration class and member count
-	baseCalories := float64(household.RationClass.CalorieTarget())
-	baseWater := household.RationClass.WaterTarget()
+	baseCalories := float64(targets.Calories(household.RationClass))
+	baseWater := targets.WaterL(household.RationClass)
 
 	allocation := &models.RationAllocation{
 		HouseholdID:   householdID,
@@ -410,8 +768,29 @@ func (s *Service) CalculateHouseholdAllocation(ctx context.Context, householdID
 	return allocation, nil
 }
 
-// GetVaultDailyRequirements calculates total daily resource requirements.
+// GetVaultDailyRequirements calculates total daily resource requirements
+// under the ration policies in force now.
 func (s *Service) GetVaultDailyRequirements(ctx context.Context) (*models.DailyRequirements, error) {
+	return s.dailyRequirements(ctx, s.now())
+}
+
+// dailyRequirements calculates total daily resource requirements under the
+// ration policies in force at at, with each household's members by
+// department.
+func (s *Service) dailyRequirements(ctx context.Context, at time.Time) (*models.DailyRequirements, error) {
+	targets, err := s.policies.Active(ctx, at)
+	if err != nil {
+		return nil, err
+	}
+	vocations, err := s.vocations.List(ctx, false)
+	if err != nil {
+		return nil, fmt.Errorf("listing vocations: %w", err)
+	}
+	departments := make(map[string]models.Department, len(vocations))
+	for _, v := range vocations {
+		departments[v.ID] = v.Department
+	}
+
 	// Get all active households
 	filter := models.HouseholdFilter{
 		Status: ptr(models.HouseholdStatusActive),
@@ -428,15 +807,30 @@ func (s *Service) GetVaultDailyRequirements(ctx context.Context) (*models.DailyR
 	for _, h := range households.Households {
 		members, err := s.residents.GetByHousehold(ctx, h.ID)
 		if err != nil {
+			// A cancelled lookup would otherwise read as an empty household
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
 			continue
 		}
 		memberCount := len(members)
 
-		caloriesDay := float64(h.RationClass.CalorieTarget() * memberCount)
-		waterDay := h.RationClass.WaterTarget() * float64(memberCount)
+		caloriesDay := float64(targets.Calories(h.RationClass) * memberCount)
+		waterDay := targets.WaterL(h.RationClass) * float64(memberCount)
+		nutrients := targets.Nutrients(h.RationClass).Scale(float64(memberCount))
 
 		reqs.TotalCalories += caloriesDay
 		reqs.TotalWaterL += waterDay
+		reqs.TotalNutrients = reqs.TotalNutrients.Add(nutrients)
+
+		byDepartment := make(map[models.Department]int)
+		for _, m := range members {
+			var dept models.Department
+			if m.PrimaryVocationID != nil {
+				dept = departments[*m.PrimaryVocationID]
+			}
+			byDepartment[dept]++
+		}
 
 		reqs.ByHousehold[h.ID] = models.HouseholdRequirement{
 			HouseholdID: h.ID,
@@ -444,6 +838,8 @@ func (s *Service) GetVaultDailyRequirements(ctx context.Context) (*models.DailyR
 			MemberCount: memberCount,
 			CaloriesDay: caloriesDay,
 			WaterLDay:   waterDay,
+			Nutrients:   nutrients,
+			Departments: byDepartment,
 		}
 	}
 
@@ -455,50 +851,50 @@ func (s *Service) GetVaultDailyRequirements(ctx context.Context) (*models.DailyR
 // ============================================================================
 
 // PerformInventoryAudit records an inventory audit adjustment.
-func (s *Service) PerformInventoryAudit(ctx context.Context, stockID string, actualQty float64, auditorID string) error {
-	stock, err := s.resources.GetStock(ctx, stockID)
-	if err != nil {
-		return fmt.Errorf("getting stock: %w", err)
-	}
+func (s *Service) PerformInventoryAudit(ctx context.Context, stockID string, actualQty float64, auditorID string) (err error) {
+	ctx, cmd := s.begin(ctx, CommandInventoryAudit, inventoryAuditArgs{stockID, actualQty, auditorID})
+	defer func() { cmd.End(err) }()
+
+	return repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		stock, err := s.resources.GetStock(ctx, tx, stockID)
+		if err != nil {
+			return fmt.Errorf("getting stock: %w", err)
+		}
 
-	difference := actualQty - stock.Quantity
-	if difference == 0 {
-		// No adjustment needed, just update audit date
-		now := time.Now()
+		now := s.now()
+		difference := actualQty - stock.Quantity
+		stock.Quantity = actualQty
 		stock.LastAuditDate = &now
 		stock.LastAuditBy = &auditorID
-		return s.resources.UpdateStock(ctx, nil, stock)
-	}
-
-	// Record the adjustment
-	now := time.Now()
-	stock.Quantity = actualQty
-	stock.LastAuditDate = &now
-	stock.LastAuditBy = &auditorID
-
-	if actualQty == 0 {
-		stock.Status = models.StockStatusDepleted
-	}
-
-	if err := s.resources.UpdateStock(ctx, nil, stock); err != nil {
-		return fmt.Errorf("updating stock: %w", err)
-	}
-
-	txn := &models.ResourceTransaction{
-		ID:              s.idGenerator.NewID(),
-		StockID:         &stockID,
-		ItemID:          stock.ItemID,
-		TransactionType: models.TransactionTypeAuditCorrection,
-		Quantity:        difference,
-		BalanceAfter:    actualQty,
-		Reason:          "Inventory audit correction",
-		AuthorizedBy:    &auditorID,
-	}
-	if err := s.resources.CreateTransaction(ctx, nil, txn); err != nil {
-		return fmt.Errorf("recording audit transaction: %w", err)
-	}
+		if difference == 0 {
+			// No adjustment needed, just update audit date
+			return s.resources.UpdateStock(ctx, tx, stock)
+		}
 
-	return nil
+		// Record the adjustment
+		if actualQty == 0 && stock.Status != models.StockStatusDepleted {
+			stock.Status = models.StockStatusDepleted
+			s.stockDepleted(ctx, stock)
+		}
+		txn := &models.ResourceTransaction{
+			ID:              s.idGenerator.NewID(),
+			StockID:         &stockID,
+			ItemID:          stock.ItemID,
+			TransactionType: models.TransactionTypeAuditCorrection,
+			Quantity:        difference,
+			BalanceAfter:    actualQty,
+			Reason:          "Inventory audit correction",
+			AuthorizedBy:    &auditorID,
+			Timestamp:       now,
+		}
+		if err := s.resources.UpdateStock(ctx, tx, stock); err != nil {
+			return fmt.Errorf("updating stock: %w", err)
+		}
+		if err := s.resources.CreateTransaction(ctx, tx, txn); err != nil {
+			return fmt.Errorf("recording audit transaction: %w", err)
+		}
+		return nil
+	})
 }
 
 // Helper function
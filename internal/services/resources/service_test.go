@@ -0,0 +1,144 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/vtuos/vtuos/internal/config"
+	"github.com/vtuos/vtuos/internal/database"
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/testutil"
+)
+
+// setupService opens a migrated vault database with its write queue, so
+// concurrent commands take turns as they do in the application.
+func setupService(t *testing.T) (*Service, *database.DB) {
+	t.Helper()
+	cfg := config.Default().Database
+	db, err := database.Open(filepath.Join(t.TempDir(), "vault.db"), &cfg, "")
+	if err != nil {
+		t.Fatalf("opening database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	m, err := database.NewMigrator(db)
+	if err != nil {
+		t.Fatalf("creating migrator: %v", err)
+	}
+	if _, err := m.MigrateUp(context.Background()); err != nil {
+		t.Fatalf("migrating: %v", err)
+	}
+	return NewService(db.DB), db
+}
+
+// countRows returns the result of a COUNT query.
+func countRows(t *testing.T, db *database.DB, query string) int {
+	t.Helper()
+	var n int
+	if err := db.QueryRow(query).Scan(&n); err != nil {
+		t.Fatalf("counting rows: %v", err)
+	}
+	return n
+}
+
+// createStock stores a lot of quantity units of a new item.
+func createStock(t *testing.T, svc *Service, quantity float64) *models.ResourceStock {
+	t.Helper()
+	ctx := context.Background()
+	category := testutil.FixtureResourceCategory()
+	item := testutil.FixtureResourceItem(category.ID)
+	stock := testutil.FixtureResourceStock(item.ID, func(s *models.ResourceStock) { s.Quantity = quantity })
+	if err := svc.resources.CreateCategory(ctx, nil, category); err != nil {
+		t.Fatalf("creating category: %v", err)
+	}
+	if err := svc.resources.CreateItem(ctx, nil, item); err != nil {
+		t.Fatalf("creating item: %v", err)
+	}
+	if err := svc.resources.CreateStock(ctx, nil, stock); err != nil {
+		t.Fatalf("creating stock: %v", err)
+	}
+	return stock
+}
+
+func TestAdjustStock_Concurrent(t *testing.T) {
+	svc, _ := setupService(t)
+	ctx := context.Background()
+	const stocked, adjusters, adjustments = 500, 8, 25
+	stock := createStock(t, svc, stocked)
+
+	// Every adjustment counts, however they interleave
+	var wg sync.WaitGroup
+	errs := make(chan error, adjusters)
+	for range adjusters {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range adjustments {
+				err := svc.AdjustStock(ctx, stock.ID, StockAdjustment{
+					QuantityChange: -1,
+					Type:           models.TransactionTypeConsumption,
+					Reason:         "Concurrent draw",
+				})
+				if err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("AdjustStock() error = %v", err)
+	}
+
+	got, err := svc.GetStock(ctx, stock.ID)
+	if err != nil {
+		t.Fatalf("GetStock() error = %v", err)
+	}
+	if want := float64(stocked - adjusters*adjustments); got.Quantity != want {
+		t.Errorf("quantity = %.0f, want %.0f", got.Quantity, want)
+	}
+}
+
+func TestRecordConsumption_DrawsLotsInOrder(t *testing.T) {
+	svc, _ := setupService(t)
+	ctx := context.Background()
+	first := createStock(t, svc, 5)
+	second := testutil.FixtureResourceStock(first.ItemID, func(s *models.ResourceStock) {
+		s.Quantity = 10
+		s.ExpirationDate = nil // Never expires, so drawn last
+	})
+	if err := svc.resources.CreateStock(ctx, nil, second); err != nil {
+		t.Fatalf("creating stock: %v", err)
+	}
+
+	err := svc.RecordConsumption(ctx, ConsumptionInput{ItemID: first.ItemID, Quantity: 8, Reason: "Test"})
+	if err != nil {
+		t.Fatalf("RecordConsumption() error = %v", err)
+	}
+	for _, want := range []struct {
+		id       string
+		quantity float64
+	}{{first.ID, 0}, {second.ID, 7}} {
+		got, err := svc.GetStock(ctx, want.id)
+		if err != nil {
+			t.Fatalf("GetStock() error = %v", err)
+		}
+		if got.Quantity != want.quantity {
+			t.Errorf("stock %s quantity = %.0f, want %.0f", want.id, got.Quantity, want.quantity)
+		}
+	}
+
+	// Short stock draws nothing
+	err = svc.RecordConsumption(ctx, ConsumptionInput{ItemID: first.ItemID, Quantity: 8, Reason: "Test"})
+	if !errors.Is(err, repository.ErrValidation) {
+		t.Fatalf("RecordConsumption() of more than in stock error = %v, want ErrValidation", err)
+	}
+	if got, _ := svc.GetStock(ctx, second.ID); got.Quantity != 7 {
+		t.Errorf("quantity after a short draw = %.0f, want 7", got.Quantity)
+	}
+}
@@ -0,0 +1,153 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/simulation"
+)
+
+// Shortages returns the items whose available stock is below their
+// minimum, by item code, each with the production run queued for it.
+func (s *Service) Shortages(ctx context.Context) ([]*models.Shortage, error) {
+	items, err := s.resources.ListItemsWithMinimum(ctx)
+	if err != nil {
+		return nil, err
+	}
+	runs, err := s.queuedRuns(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var shortages []*models.Shortage
+	for _, item := range items {
+		available, err := s.resources.GetTotalStockByItem(ctx, item.ID)
+		if err != nil {
+			return nil, fmt.Errorf("getting stock of %s: %w", item.ItemCode, err)
+		}
+		if shortfall := item.Shortfall(available); shortfall > 0 {
+			shortages = append(shortages, &models.Shortage{
+				Item:      item,
+				Available: available,
+				Shortfall: shortfall,
+				Run:       runs[item.ID],
+			})
+		}
+	}
+	return shortages, nil
+}
+
+// ProductionRuns returns the production runs queued, oldest first.
+func (s *Service) ProductionRuns(ctx context.Context) ([]*models.ProductionRun, error) {
+	return s.production.ListQueued(ctx)
+}
+
+// queuedRuns returns the production runs queued by item ID.
+func (s *Service) queuedRuns(ctx context.Context) (map[string]*models.ProductionRun, error) {
+	runs, err := s.production.ListQueued(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byItem := make(map[string]*models.ProductionRun, len(runs))
+	for _, run := range runs {
+		byItem[run.ItemID] = run
+	}
+	return byItem, nil
+}
+
+// CheckShortages compares available stock with each item's minimum. A
+// producible item below it gets a production run queued for its shortfall,
+// unless one already is; any other item raises a procurement alert every
+// check until restocked. A queued run whose item is back at its minimum,
+// or no longer has one, is cancelled. An item out of stock is critical.
+func (s *Service) CheckShortages(ctx context.Context, at time.Time) (_ []simulation.Event, err error) {
+	ctx, cmd := s.begin(ctx, CommandCheckShortages, timeArgs{at})
+	defer func() { cmd.End(err) }()
+
+	shortages, err := s.Shortages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	queued, err := s.production.ListQueued(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []simulation.Event
+	short := make(map[string]bool, len(shortages))
+	for _, shortage := range shortages {
+		item := shortage.Item
+		short[item.ID] = true
+		level := simulation.EventWarning
+		if shortage.Critical() {
+			level = simulation.EventCritical
+		}
+		below := fmt.Sprintf("%.2f of %.2f %s", shortage.Available, *item.MinStock, item.UnitOfMeasure)
+
+		if !item.IsProducible {
+			events = append(events, simulation.Event{
+				Time:   at,
+				Level:  level,
+				Source: "shortage check",
+				Message: fmt.Sprintf("Procurement needed: %s below minimum, %s; order %.2f %s",
+					item.ItemCode, below, shortage.Shortfall, item.UnitOfMeasure),
+			})
+			continue
+		}
+		if shortage.Run != nil {
+			continue
+		}
+		run := &models.ProductionRun{
+			ID:       s.idGenerator.NewID(),
+			ItemID:   item.ID,
+			Quantity: shortage.Shortfall,
+			Status:   models.ProductionRunQueued,
+			Reason:   "Below minimum: " + below,
+			QueuedAt: at,
+		}
+		if err := s.production.Create(ctx, nil, run); err != nil {
+			return events, fmt.Errorf("queuing production of %s: %w", item.ItemCode, err)
+		}
+		if level == simulation.EventWarning {
+			level = simulation.EventInfo
+		}
+		events = append(events, simulation.Event{
+			Time:   at,
+			Level:  level,
+			Source: "shortage check",
+			Message: fmt.Sprintf("Production run queued: %s below minimum, %s; produce %.2f %s",
+				item.ItemCode, below, run.Quantity, item.UnitOfMeasure),
+		})
+	}
+
+	// Runs of items no longer short are cancelled
+	for _, run := range queued {
+		if short[run.ItemID] {
+			continue
+		}
+		run.Status = models.ProductionRunCancelled
+		run.ClosedAt = &at
+		if err := s.production.Close(ctx, nil, run); err != nil {
+			return events, fmt.Errorf("cancelling production of %s: %w", run.Item.ItemCode, err)
+		}
+		events = append(events, simulation.Event{
+			Time:    at,
+			Level:   simulation.EventInfo,
+			Source:  "shortage check",
+			Message: fmt.Sprintf("Production run cancelled: %s no longer below minimum", run.Item.ItemCode),
+		})
+	}
+	return events, nil
+}
+
+// ShortageCheckJob is the scheduled job that checks stock against item
+// minimums every vault day.
+func (s *Service) ShortageCheckJob() simulation.Job {
+	return simulation.Job{
+		Name:     "Shortage check",
+		Interval: simulation.Daily,
+		Check:    s.CheckShortages,
+	}
+}
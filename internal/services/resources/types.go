@@ -1,6 +1,7 @@
 package resources
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/vtuos/vtuos/internal/models"
@@ -16,6 +17,16 @@ type CreateCategoryInput struct {
 	IsCritical    bool
 }
 
+// UpdateCategoryInput contains data for editing a resource category. The
+// code cannot be changed.
+type UpdateCategoryInput struct {
+	Name          string
+	Description   string
+	UnitOfMeasure string
+	IsConsumable  bool
+	IsCritical    bool
+}
+
 // CreateItemInput contains data for creating a resource item.
 type CreateItemInput struct {
 	CategoryID           string
@@ -24,10 +35,30 @@ type CreateItemInput struct {
 	Description          string
 	UnitOfMeasure        string
 	CaloriesPerUnit      *float64
+	Nutrients            *models.Nutrients
+	ShelfLifeDays        *int
+	StorageRequirements  string
+	IsProducible         bool
+	ProductionRatePerDay *float64
+	MinStock             *float64
+	TargetStock          *float64
+}
+
+// UpdateItemInput contains data for editing a resource item. The item code
+// cannot be changed.
+type UpdateItemInput struct {
+	CategoryID           string
+	Name                 string
+	Description          string
+	UnitOfMeasure        string
+	CaloriesPerUnit      *float64
+	Nutrients            *models.Nutrients
 	ShelfLifeDays        *int
 	StorageRequirements  string
 	IsProducible         bool
 	ProductionRatePerDay *float64
+	MinStock             *float64
+	TargetStock          *float64
 }
 
 // CreateStockInput contains data for creating a stock record.
@@ -40,12 +71,76 @@ type CreateStockInput struct {
 	ExpirationDate  *time.Time
 }
 
+// DeliveryOptions contains settings for importing a supply delivery.
+type DeliveryOptions struct {
+	Reference       string    // Manifest or delivery number, recorded in each receipt's reason
+	StorageLocation string    // Of lines without a location of their own
+	ReceivedDate    time.Time // Defaults to now
+	CreateItems     bool      // Add unknown item codes to the catalog instead of reporting them
+	CategoryCode    string    // Of the items created; required with CreateItems
+	AuthorizedBy    *string
+}
+
 // StockAdjustment contains data for adjusting stock quantity.
 type StockAdjustment struct {
+	QuantityChange    float64
+	Type              models.TransactionType
+	Reason            string
+	AuthorizedBy      *string
+	RelatedEntityType string // RESIDENT, HOUSEHOLD, FACILITY
+	RelatedEntityID   string
+}
+
+// SplitStockInput contains data for splitting part of a lot off into a new
+// lot.
+type SplitStockInput struct {
+	StockID         string
+	Quantity        float64 // Moved to the new lot
+	LotNumber       string  // Of the new lot
+	StorageLocation string  // Of the new lot, the source lot's when empty
+	AuthorizedBy    *string
+}
+
+// StockStatusInput contains data for setting the status of one lot.
+type StockStatusInput struct {
+	StockID      string
+	Status       models.StockStatus
+	Note         string // Recorded in the transaction's reason, e.g. the damage found
+	AuthorizedBy *string
+}
+
+// BulkAdjustment is one line of a bulk stock adjustment. It changes a lot
+// by QuantityChange or, with Counted set, sets it to a physical count.
+type BulkAdjustment struct {
+	Lot            string // Stock ID or lot number
 	QuantityChange float64
-	Type           models.TransactionType
+	Counted        *float64               // Physical count, taken against the lot's current quantity
+	Type           models.TransactionType // ADJUSTMENT, SPOILAGE or AUDIT_CORRECTION; defaults by line
 	Reason         string
-	AuthorizedBy   *string
+}
+
+// BulkAdjustInput contains data for adjusting many lots at once.
+type BulkAdjustInput struct {
+	Adjustments  []BulkAdjustment // At most MaxBulkAdjustments
+	AuthorizedBy *string
+}
+
+// BulkAdjustResult summarizes a bulk stock adjustment.
+type BulkAdjustResult struct {
+	Applied int               // Lines that changed their lot
+	Skipped int               // Lines that left their lot as it was
+	Errors  []BulkAdjustError // Lines rejected and not applied
+}
+
+// BulkAdjustError is a line of a bulk adjustment that was rejected.
+type BulkAdjustError struct {
+	Line int    // Position in the adjustments, from 1
+	Lot  string // Lot number, or the lot as given when it was not found
+	Err  error
+}
+
+func (e BulkAdjustError) Error() string {
+	return fmt.Sprintf("line %d, lot %s: %v", e.Line, e.Lot, e.Err)
 }
 
 // ConsumptionInput contains data for recording consumption.
@@ -56,6 +151,7 @@ type ConsumptionInput struct {
 	AuthorizedBy      *string
 	RelatedEntityType string // RESIDENT, HOUSEHOLD, FACILITY
 	RelatedEntityID   string
+	Policy            models.ConsumptionPolicy // Empty uses the service's policy
 }
 
 // ProductionInput contains data for recording production.
@@ -68,3 +164,62 @@ type ProductionInput struct {
 	Reason          string
 	AuthorizedBy    *string
 }
+
+// QualityTestInput contains data for recording a quality test of a lot.
+type QualityTestInput struct {
+	StockID            string
+	TestedAt           time.Time // Defaults to now
+	TestedBy           *string
+	ContaminationLevel float64 // ppm
+	Passed             bool
+	Notes              string
+}
+
+// AssetInput contains data for registering an asset.
+type AssetInput struct {
+	SerialNumber string
+	Name         string
+	Category     models.AssetCategory
+	Condition    models.AssetCondition // Defaults to GOOD
+	Location     string
+	AcquiredAt   *time.Time
+	Notes        string
+}
+
+// CheckOutInput contains data for issuing an asset into a resident's
+// custody.
+type CheckOutInput struct {
+	AssetID    string
+	ResidentID string
+	DueAt      *time.Time // Nil for a standing custodianship assignment
+	IssuedBy   *string
+	Notes      string
+}
+
+// CheckInInput contains data for returning an asset from custody.
+type CheckInInput struct {
+	AssetID    string
+	Condition  models.AssetCondition // As found on return
+	ReceivedBy *string
+	Notes      string
+}
+
+// AssetConditionInput contains data for recording an asset's condition
+// found at an inspection.
+type AssetConditionInput struct {
+	AssetID    string
+	Condition  models.AssetCondition
+	RecordedBy *string
+	Notes      string
+}
+
+// ReservationInput contains data for reserving stock.
+type ReservationInput struct {
+	ItemID            string
+	Quantity          float64
+	Purpose           string
+	ReservedBy        *string
+	RelatedEntityType string // RESIDENT, HOUSEHOLD, FACILITY
+	RelatedEntityID   string
+	ExpiresAt         time.Time // Zero holds the stock for DefaultReservationHold
+}
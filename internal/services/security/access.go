@@ -0,0 +1,247 @@
+package security
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+)
+
+// AccessPointInput contains data for registering a door or airlock.
+type AccessPointInput struct {
+	Code         string
+	Name         string
+	Kind         models.AccessPointKind
+	Sector       string
+	Level        int
+	MinClearance int
+}
+
+// GrantInput contains data for granting a resident access to a point.
+type GrantInput struct {
+	ResidentID    string
+	AccessPointID string
+	GrantedBy     *string
+	GrantedAt     time.Time  // Defaults to now
+	ExpiresAt     *time.Time // Nil for a grant until revoked
+	Reason        string
+}
+
+// AccessAttempt is a credential presented at an access point.
+type AccessAttempt struct {
+	AccessPointID string
+	ResidentID    string    // Empty for a credential that matches no resident
+	At            time.Time // Defaults to now
+	Simulated     bool
+}
+
+// CreateAccessPoint registers a door or airlock, active from creation.
+func (s *Service) CreateAccessPoint(ctx context.Context, input AccessPointInput) (_ *models.AccessPoint, err error) {
+	ctx, cmd := s.begin(ctx, CommandCreateAccessPoint, input)
+	defer func() { cmd.End(err) }()
+
+	point := &models.AccessPoint{
+		ID:             s.idGenerator.NewID(),
+		Code:           strings.ToUpper(strings.TrimSpace(input.Code)),
+		Name:           input.Name,
+		Kind:           input.Kind,
+		LocationSector: input.Sector,
+		LocationLevel:  input.Level,
+		MinClearance:   input.MinClearance,
+		IsActive:       true,
+	}
+	if err := s.access.CreatePoint(ctx, nil, point); err != nil {
+		return nil, err
+	}
+	return point, nil
+}
+
+// SetAccessPointActive enables or disables an access point. A disabled
+// point admits no one, whatever their clearance or grants.
+func (s *Service) SetAccessPointActive(ctx context.Context, id string, active bool) (_ *models.AccessPoint, err error) {
+	ctx, cmd := s.begin(ctx, CommandSetAccessPointActive, pointActiveArgs{PointID: id, Active: active})
+	defer func() { cmd.End(err) }()
+
+	point, err := s.access.GetPoint(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	point.IsActive = active
+	if err := s.access.UpdatePoint(ctx, nil, point); err != nil {
+		return nil, err
+	}
+	return point, nil
+}
+
+// SetMinClearance sets the clearance level an access point admits without
+// a grant.
+func (s *Service) SetMinClearance(ctx context.Context, id string, clearance int) (_ *models.AccessPoint, err error) {
+	ctx, cmd := s.begin(ctx, CommandSetMinClearance, minClearanceArgs{PointID: id, Clearance: clearance})
+	defer func() { cmd.End(err) }()
+
+	point, err := s.access.GetPoint(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	point.MinClearance = clearance
+	if err := s.access.UpdatePoint(ctx, nil, point); err != nil {
+		return nil, err
+	}
+	return point, nil
+}
+
+// GetAccessPointByCode retrieves an access point by its code, in any case.
+func (s *Service) GetAccessPointByCode(ctx context.Context, code string) (*models.AccessPoint, error) {
+	return s.access.GetPointByCode(ctx, strings.ToUpper(strings.TrimSpace(code)))
+}
+
+// ListAccessPoints retrieves the vault's access points, ordered by code.
+func (s *Service) ListAccessPoints(ctx context.Context) ([]*models.AccessPoint, error) {
+	return s.access.ListPoints(ctx)
+}
+
+// GrantAccess lets an active resident through an access point whatever
+// their clearance. A resident holds at most one grant in force per point.
+func (s *Service) GrantAccess(ctx context.Context, input GrantInput) (_ *models.AccessGrant, err error) {
+	ctx, cmd := s.begin(ctx, CommandGrantAccess, input)
+	defer func() { cmd.End(err) }()
+
+	if input.GrantedAt.IsZero() {
+		input.GrantedAt = s.now()
+	}
+	resident, err := s.residents.GetByID(ctx, input.ResidentID)
+	if err != nil {
+		return nil, fmt.Errorf("getting resident: %w", err)
+	}
+	if resident.Status != models.ResidentStatusActive {
+		return nil, fmt.Errorf("%w: resident %s is %s", repository.ErrValidation, resident.RegistryNumber, resident.Status)
+	}
+	point, err := s.access.GetPoint(ctx, input.AccessPointID)
+	if err != nil {
+		return nil, fmt.Errorf("getting access point: %w", err)
+	}
+
+	grants, err := s.access.ListGrantsByResident(ctx, resident.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range grants {
+		if g.AccessPointID == point.ID && g.ActiveAt(input.GrantedAt) {
+			return nil, fmt.Errorf("%w: %s already holds a grant for %s", repository.ErrValidation, resident.RegistryNumber, point.Code)
+		}
+	}
+
+	grant := &models.AccessGrant{
+		ID:            s.idGenerator.NewID(),
+		ResidentID:    resident.ID,
+		AccessPointID: point.ID,
+		GrantedBy:     input.GrantedBy,
+		GrantedAt:     input.GrantedAt.UTC().Truncate(time.Second),
+		ExpiresAt:     input.ExpiresAt,
+		Reason:        input.Reason,
+		Resident:      resident,
+		AccessPoint:   point,
+	}
+	if err := s.access.CreateGrant(ctx, nil, grant); err != nil {
+		return nil, err
+	}
+	return grant, nil
+}
+
+// RevokeGrant revokes an access grant from now.
+func (s *Service) RevokeGrant(ctx context.Context, id string) (err error) {
+	ctx, cmd := s.begin(ctx, CommandRevokeGrant, idArgs{ID: id})
+	defer func() { cmd.End(err) }()
+
+	return s.access.RevokeGrant(ctx, nil, id, s.now())
+}
+
+// ListGrants retrieves a resident's grants, newest first, with their access
+// points.
+func (s *Service) ListGrants(ctx context.Context, residentID string) ([]*models.AccessGrant, error) {
+	grants, err := s.access.ListGrantsByResident(ctx, residentID)
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range grants {
+		if g.AccessPoint, err = s.access.GetPoint(ctx, g.AccessPointID); err != nil {
+			return nil, err
+		}
+	}
+	return grants, nil
+}
+
+// ListPointGrants retrieves the grants for an access point, newest first,
+// with their residents.
+func (s *Service) ListPointGrants(ctx context.Context, pointID string) ([]*models.AccessGrant, error) {
+	grants, err := s.access.ListGrantsByPoint(ctx, pointID)
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range grants {
+		if g.Resident, err = s.residents.GetByID(ctx, g.ResidentID); err != nil {
+			return nil, err
+		}
+	}
+	return grants, nil
+}
+
+// RequestAccess decides an attempt to pass an access point and logs it,
+// granted or denied. A resident ID that matches no resident is logged as
+// an unknown credential.
+func (s *Service) RequestAccess(ctx context.Context, attempt AccessAttempt) (_ *models.AccessEvent, err error) {
+	ctx, cmd := s.begin(ctx, CommandRequestAccess, attempt)
+	defer func() { cmd.End(err) }()
+
+	if attempt.At.IsZero() {
+		attempt.At = s.now()
+	}
+	point, err := s.access.GetPoint(ctx, attempt.AccessPointID)
+	if err != nil {
+		return nil, fmt.Errorf("getting access point: %w", err)
+	}
+
+	var resident *models.Resident
+	var grants []*models.AccessGrant
+	if attempt.ResidentID != "" {
+		resident, err = s.residents.GetByID(ctx, attempt.ResidentID)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return nil, fmt.Errorf("getting resident: %w", err)
+		}
+		if resident != nil {
+			if grants, err = s.access.ListGrantsByResident(ctx, resident.ID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	granted, reason := models.DecideAccess(point, resident, grants, attempt.At)
+	event := &models.AccessEvent{
+		ID:            s.idGenerator.NewID(),
+		AccessPointID: point.ID,
+		OccurredAt:    attempt.At.UTC().Truncate(time.Second),
+		Granted:       granted,
+		Reason:        reason,
+		Simulated:     attempt.Simulated,
+		PointCode:     point.Code,
+	}
+	if resident != nil {
+		event.ResidentID = &resident.ID
+		event.RegistryNumber = resident.RegistryNumber
+	}
+
+	if err := s.access.CreateEvent(ctx, nil, event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// ListAccessEvents retrieves up to limit of the vault's access events
+// matching the filter, most recent first.
+func (s *Service) ListAccessEvents(ctx context.Context, filter models.AccessEventFilter, limit int) ([]*models.AccessEvent, error) {
+	return s.access.ListEvents(ctx, filter, limit)
+}
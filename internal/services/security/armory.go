@@ -0,0 +1,309 @@
+package security
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/services/resources"
+)
+
+// WeaponInput contains data for bringing a WEAPON asset under armory
+// control.
+type WeaponInput struct {
+	AssetID            string
+	AuthorizationLevel int
+	AmmoItemID         *string // Nil for a weapon that fires no stocked ammunition
+}
+
+// IssueWeaponInput contains data for issuing a weapon to a resident.
+type IssueWeaponInput struct {
+	AssetID        string
+	ResidentID     string
+	ApprovedBy     string
+	IncidentNumber string // Empty to issue for the drill in progress
+	RoundsIssued   int
+	DueAt          *time.Time // Nil to issue until returned
+	Notes          string
+}
+
+// ReturnWeaponInput contains data for returning an issued weapon.
+type ReturnWeaponInput struct {
+	AssetID        string
+	Condition      models.AssetCondition
+	RoundsExpended int
+	ReceivedBy     *string
+	Notes          string
+}
+
+// SetWeapon brings a WEAPON asset under armory control, or changes the
+// authorization level and ammunition of one already there.
+func (s *Service) SetWeapon(ctx context.Context, input WeaponInput) (_ *models.ArmoryWeapon, err error) {
+	ctx, cmd := s.begin(ctx, CommandSetWeapon, input)
+	defer func() { cmd.End(err) }()
+
+	asset, err := s.resources.GetAsset(ctx, input.AssetID)
+	if err != nil {
+		return nil, err
+	}
+	if asset.Category != models.AssetCategoryWeapon {
+		return nil, fmt.Errorf("%w: asset %s is %s, not a weapon", repository.ErrValidation, asset.SerialNumber, asset.Category)
+	}
+	if asset.IsRetired() {
+		return nil, fmt.Errorf("%w: weapon %s is retired", repository.ErrValidation, asset.SerialNumber)
+	}
+
+	weapon := &models.ArmoryWeapon{
+		AssetID:            asset.ID,
+		AuthorizationLevel: input.AuthorizationLevel,
+		AmmoItemID:         input.AmmoItemID,
+		Asset:              asset,
+	}
+	if input.AmmoItemID != nil {
+		if weapon.AmmoItem, err = s.resources.GetItem(ctx, *input.AmmoItemID); err != nil {
+			return nil, fmt.Errorf("getting ammunition item: %w", err)
+		}
+	}
+	if err := s.armory.SetWeapon(ctx, nil, weapon); err != nil {
+		return nil, err
+	}
+	return weapon, nil
+}
+
+// IssueWeapon issues an armory weapon to a resident cleared to carry it, on
+// the approval of an officer, for an open security incident or, without
+// one, the drill in progress. Rounds issued must be in stock; they are
+// drawn only as they are expended.
+func (s *Service) IssueWeapon(ctx context.Context, input IssueWeaponInput) (_ *models.ArmoryIssue, err error) {
+	ctx, cmd := s.begin(ctx, CommandIssueWeapon, input)
+	defer func() { cmd.End(err) }()
+
+	asset, err := s.resources.PlanCheckOut(ctx, resources.CheckOutInput{
+		AssetID:    input.AssetID,
+		ResidentID: input.ResidentID,
+		IssuedBy:   &input.ApprovedBy,
+		DueAt:      input.DueAt,
+		Notes:      input.Notes,
+	})
+	if err != nil {
+		return nil, err
+	}
+	weapon, err := s.weapon(ctx, asset)
+	if err != nil {
+		return nil, err
+	}
+	approver, err := s.residents.GetByID(ctx, input.ApprovedBy)
+	if err != nil {
+		return nil, fmt.Errorf("getting approving officer: %w", err)
+	}
+	if err := models.CheckWeaponIssue(weapon, asset.Custody.Resident, approver); err != nil {
+		return nil, fmt.Errorf("%w: %w", repository.ErrValidation, err)
+	}
+
+	issue := &models.ArmoryIssue{
+		CustodyID:        asset.Custody.ID,
+		ApprovedBy:       &approver.ID,
+		RoundsIssued:     input.RoundsIssued,
+		ApproverRegistry: approver.RegistryNumber,
+		Asset:            asset,
+		Custody:          asset.Custody,
+	}
+	if input.IncidentNumber != "" {
+		incident, err := s.incidents.GetIncidentByNumber(ctx, strings.ToUpper(strings.TrimSpace(input.IncidentNumber)))
+		if err != nil {
+			return nil, err
+		}
+		if !incident.IsOpen() {
+			return nil, fmt.Errorf("%w: incident %s is %s", repository.ErrValidation, incident.IncidentNumber, incident.Status)
+		}
+		issue.IncidentID = &incident.ID
+		issue.IncidentNumber = incident.IncidentNumber
+	} else {
+		current, err := s.lockdowns.GetCurrent(ctx)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return nil, fmt.Errorf("getting vault state: %w", err)
+		}
+		if current == nil || current.State != models.LockdownDrill {
+			return nil, fmt.Errorf("%w: a weapon is issued for an open security incident or during a drill", repository.ErrValidation)
+		}
+		issue.Drill = true
+	}
+
+	if input.RoundsIssued > 0 {
+		if weapon.AmmoItemID == nil {
+			return nil, fmt.Errorf("%w: weapon %s has no ammunition item", repository.ErrValidation, asset.SerialNumber)
+		}
+		available, err := s.resources.GetAvailableQuantity(ctx, *weapon.AmmoItemID)
+		if err != nil {
+			return nil, fmt.Errorf("getting ammunition stock: %w", err)
+		}
+		if available < float64(input.RoundsIssued) {
+			return nil, fmt.Errorf("%w: %d rounds asked, %.0f in stock", repository.ErrValidation, input.RoundsIssued, available)
+		}
+	}
+
+	err = repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		if err := s.resources.ApplyCheckOut(ctx, tx, asset); err != nil {
+			return err
+		}
+		return s.armory.CreateIssue(ctx, tx, issue)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return issue, nil
+}
+
+// ReturnWeapon returns an issued weapon in the condition found, drawing the
+// rounds expended, at most those issued, from the ammunition stock. The
+// custody closes together with its stock draw, or not at all when stock
+// runs short.
+func (s *Service) ReturnWeapon(ctx context.Context, input ReturnWeaponInput) (_ *models.ArmoryIssue, err error) {
+	ctx, cmd := s.begin(ctx, CommandReturnWeapon, input)
+	defer func() { cmd.End(err) }()
+
+	checkIn := resources.CheckInInput{
+		AssetID:    input.AssetID,
+		Condition:  input.Condition,
+		ReceivedBy: input.ReceivedBy,
+		Notes:      input.Notes,
+	}
+	asset, err := s.resources.PlanCheckIn(ctx, checkIn)
+	if err != nil {
+		return nil, err
+	}
+	weapon, err := s.weapon(ctx, asset)
+	if err != nil {
+		return nil, err
+	}
+	issue, err := s.armory.GetIssue(ctx, asset.Custody.ID)
+	if err != nil {
+		return nil, err
+	}
+	if input.RoundsExpended < 0 || input.RoundsExpended > issue.RoundsIssued {
+		return nil, fmt.Errorf("%w: rounds expended must be between 0 and the %d issued", repository.ErrValidation, issue.RoundsIssued)
+	}
+	issue.RoundsExpended = &input.RoundsExpended
+	issue.Asset = asset
+	issue.Custody = asset.Custody
+
+	var draw *resources.ConsumptionInput
+	if input.RoundsExpended > 0 {
+		draw = &resources.ConsumptionInput{
+			ItemID:            *weapon.AmmoItemID,
+			Quantity:          float64(input.RoundsExpended),
+			Reason:            fmt.Sprintf("Rounds expended with %s (%s)", asset.SerialNumber, issue.Purpose()),
+			AuthorizedBy:      input.ReceivedBy,
+			RelatedEntityType: "RESIDENT",
+			RelatedEntityID:   asset.Custody.ResidentID,
+		}
+	}
+
+	err = repository.WithTransaction(ctx, s.db, func(tx *sql.Tx) error {
+		if err := s.resources.ApplyCheckIn(ctx, tx, asset, checkIn); err != nil {
+			return err
+		}
+		if err := s.armory.ReturnIssue(ctx, tx, issue); err != nil {
+			return err
+		}
+		if draw == nil {
+			return nil
+		}
+		if err := s.resources.ApplyConsumption(ctx, tx, *draw); err != nil {
+			return fmt.Errorf("drawing ammunition: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return issue, nil
+}
+
+// ListWeapons retrieves the vault's armory weapons, ordered by serial
+// number, with their assets, open custody and ammunition.
+func (s *Service) ListWeapons(ctx context.Context) ([]*models.ArmoryWeapon, error) {
+	weapons, err := s.armory.ListWeapons(ctx)
+	if err != nil {
+		return nil, err
+	}
+	assets, err := s.weaponAssets(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	items := make(map[string]*models.ResourceItem)
+	for _, w := range weapons {
+		w.Asset = assets[w.AssetID]
+		if w.AmmoItemID == nil {
+			continue
+		}
+		if items[*w.AmmoItemID] == nil {
+			if items[*w.AmmoItemID], err = s.resources.GetItem(ctx, *w.AmmoItemID); err != nil {
+				return nil, fmt.Errorf("getting ammunition item: %w", err)
+			}
+		}
+		w.AmmoItem = items[*w.AmmoItemID]
+	}
+	return weapons, nil
+}
+
+// ListIssuedWeapons retrieves the issues of the vault's weapons still in
+// custody, earliest issued first, with the weapons and who holds them.
+func (s *Service) ListIssuedWeapons(ctx context.Context) ([]*models.ArmoryIssue, error) {
+	issues, err := s.armory.ListOpenIssues(ctx)
+	if err != nil {
+		return nil, err
+	}
+	assets, err := s.weaponAssets(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	byCustody := make(map[string]*models.Asset, len(assets))
+	for _, a := range assets {
+		if a.Custody != nil {
+			byCustody[a.Custody.ID] = a
+		}
+	}
+	for _, i := range issues {
+		if i.Asset = byCustody[i.CustodyID]; i.Asset != nil {
+			i.Custody = i.Asset.Custody
+		}
+	}
+	return issues, nil
+}
+
+// weapon retrieves the armory record of an asset.
+func (s *Service) weapon(ctx context.Context, asset *models.Asset) (*models.ArmoryWeapon, error) {
+	weapon, err := s.armory.GetWeapon(ctx, asset.ID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("%w: asset %s is not an armory weapon; set its authorization level first",
+			repository.ErrValidation, asset.SerialNumber)
+	}
+	if err != nil {
+		return nil, err
+	}
+	weapon.Asset = asset
+	return weapon, nil
+}
+
+// weaponAssets retrieves the vault's WEAPON assets, with their open
+// custody, by ID.
+func (s *Service) weaponAssets(ctx context.Context, includeRetired bool) (map[string]*models.Asset, error) {
+	assets, err := s.resources.ListAssets(ctx, models.AssetFilter{
+		Category:       models.AssetCategoryWeapon,
+		IncludeRetired: includeRetired,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing weapons: %w", err)
+	}
+	byID := make(map[string]*models.Asset, len(assets))
+	for _, a := range assets {
+		byID[a.ID] = a
+	}
+	return byID, nil
+}
@@ -0,0 +1,99 @@
+package security
+
+import (
+	"context"
+
+	"github.com/vtuos/vtuos/internal/events"
+	"github.com/vtuos/vtuos/internal/journal"
+)
+
+// Journaled security commands.
+const (
+	CommandCreateAccessPoint    = "security.create_access_point"
+	CommandSetAccessPointActive = "security.set_access_point_active"
+	CommandSetMinClearance      = "security.set_min_clearance"
+	CommandGrantAccess          = "security.grant_access"
+	CommandRevokeGrant          = "security.revoke_grant"
+	CommandRequestAccess        = "security.request_access"
+	CommandSetWeapon            = "security.set_weapon"
+	CommandIssueWeapon          = "security.issue_weapon"
+	CommandReturnWeapon         = "security.return_weapon"
+	CommandReportIncident       = "security.report_incident"
+)
+
+// Arguments of journaled commands.
+type (
+	idArgs struct {
+		ID string `json:"id"`
+	}
+	pointActiveArgs struct {
+		PointID string `json:"point_id"`
+		Active  bool   `json:"active"`
+	}
+	minClearanceArgs struct {
+		PointID   string `json:"point_id"`
+		Clearance int    `json:"clearance"`
+	}
+)
+
+// SetJournal records the commands the service runs in j.
+func (s *Service) SetJournal(j *journal.Journal) {
+	s.journal = j
+}
+
+// SetEvents publishes the events of the stock the service draws, such as
+// lots depleted, on bus.
+func (s *Service) SetEvents(bus *events.Bus) {
+	s.resources.SetEvents(bus)
+}
+
+// begin starts journaling a command of the service.
+func (s *Service) begin(ctx context.Context, command string, args any) (context.Context, *journal.Command) {
+	return s.journal.Begin(ctx, s.vault, command, args)
+}
+
+// Commands returns the handlers that replay the service's journaled
+// commands.
+func (s *Service) Commands() journal.Handlers {
+	return journal.Handlers{
+		CommandCreateAccessPoint: journal.Handle(func(ctx context.Context, input AccessPointInput) error {
+			_, err := s.CreateAccessPoint(ctx, input)
+			return err
+		}),
+		CommandSetAccessPointActive: journal.Handle(func(ctx context.Context, args pointActiveArgs) error {
+			_, err := s.SetAccessPointActive(ctx, args.PointID, args.Active)
+			return err
+		}),
+		CommandSetMinClearance: journal.Handle(func(ctx context.Context, args minClearanceArgs) error {
+			_, err := s.SetMinClearance(ctx, args.PointID, args.Clearance)
+			return err
+		}),
+		CommandGrantAccess: journal.Handle(func(ctx context.Context, input GrantInput) error {
+			_, err := s.GrantAccess(ctx, input)
+			return err
+		}),
+		CommandRevokeGrant: journal.Handle(func(ctx context.Context, args idArgs) error {
+			return s.RevokeGrant(ctx, args.ID)
+		}),
+		CommandRequestAccess: journal.Handle(func(ctx context.Context, attempt AccessAttempt) error {
+			_, err := s.RequestAccess(ctx, attempt)
+			return err
+		}),
+		CommandSetWeapon: journal.Handle(func(ctx context.Context, input WeaponInput) error {
+			_, err := s.SetWeapon(ctx, input)
+			return err
+		}),
+		CommandIssueWeapon: journal.Handle(func(ctx context.Context, input IssueWeaponInput) error {
+			_, err := s.IssueWeapon(ctx, input)
+			return err
+		}),
+		CommandReturnWeapon: journal.Handle(func(ctx context.Context, input ReturnWeaponInput) error {
+			_, err := s.ReturnWeapon(ctx, input)
+			return err
+		}),
+		CommandReportIncident: journal.Handle(func(ctx context.Context, input IncidentInput) error {
+			_, err := s.ReportIncident(ctx, input)
+			return err
+		}),
+	}
+}
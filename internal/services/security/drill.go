@@ -0,0 +1,124 @@
+package security
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/simulation"
+)
+
+// Drill attempt generation.
+const (
+	drillAttemptsPerHour  = 6    // Mean simulated attempts an hour of drill
+	maxDrillAttempts      = 60   // Attempts generated in one advance at most
+	unknownCredentialOdds = 0.05 // Share of attempts with an unknown credential
+)
+
+// DrillAccess is the simulation hook that exercises the access points while
+// the vault is in a DRILL: residents try doors and airlocks at random, and
+// each attempt is decided and logged as a simulated event.
+type DrillAccess struct {
+	service *Service
+	rng     *rand.Rand
+}
+
+// DrillAccess creates the drill hook with a random source seeded by seed.
+func (s *Service) DrillAccess(seed int64) *DrillAccess {
+	return &DrillAccess{service: s, rng: rand.New(rand.NewSource(seed))}
+}
+
+// Name implements simulation.Hook.
+func (d *DrillAccess) Name() string {
+	return "access drill"
+}
+
+// Advance implements simulation.Hook. Outside a DRILL it does nothing.
+// Each attempt goes through RequestAccess so that a replay need not roll
+// it again; the advance is summarized in one event, a warning if any
+// attempt was denied.
+func (d *DrillAccess) Advance(ctx context.Context, from, to time.Time) ([]simulation.Event, error) {
+	current, err := d.service.lockdowns.GetCurrent(ctx)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting vault state: %w", err)
+	}
+	if current.State != models.LockdownDrill {
+		return nil, nil
+	}
+
+	span := to.Sub(from)
+	attempts := min(int(span.Hours()*drillAttemptsPerHour+d.rng.Float64()), maxDrillAttempts)
+	if attempts == 0 {
+		return nil, nil
+	}
+	points, err := d.service.ListAccessPoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+	residents, err := d.service.activeResidents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(points) == 0 || len(residents) == 0 {
+		return nil, nil
+	}
+
+	denied := 0
+	for i := 0; i < attempts; i++ {
+		attempt := AccessAttempt{
+			AccessPointID: points[d.rng.Intn(len(points))].ID,
+			At:            from.Add(time.Duration(d.rng.Int63n(int64(span)))),
+			Simulated:     true,
+		}
+		if d.rng.Float64() >= unknownCredentialOdds {
+			attempt.ResidentID = residents[d.rng.Intn(len(residents))].ID
+		}
+		event, err := d.service.RequestAccess(ctx, attempt)
+		if err != nil {
+			return nil, fmt.Errorf("simulating access attempt: %w", err)
+		}
+		if !event.Granted {
+			denied++
+		}
+	}
+
+	level := simulation.EventInfo
+	if denied > 0 {
+		level = simulation.EventWarning
+	}
+	return []simulation.Event{{
+		Time:    to,
+		Level:   level,
+		Source:  d.Name(),
+		Message: fmt.Sprintf("Drill: %d simulated access attempts, %d denied", attempts, denied),
+	}}, nil
+}
+
+// activeResidents lists the vault's active residents.
+func (s *Service) activeResidents(ctx context.Context) ([]*models.Resident, error) {
+	status := models.ResidentStatusActive
+	filter := models.ResidentFilter{Status: &status}
+	page := models.Pagination{Page: 1, PageSize: 100}
+
+	var residents []*models.Resident
+	for {
+		result, err := s.residents.List(ctx, filter, page)
+		if err != nil {
+			return nil, fmt.Errorf("listing residents: %w", err)
+		}
+		residents = append(residents, result.Residents...)
+		if result.NextCursor == "" {
+			break
+		}
+		page.Page++
+		page.After = result.NextCursor
+	}
+	return residents, nil
+}
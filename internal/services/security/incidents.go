@@ -0,0 +1,201 @@
+package security
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/simulation"
+)
+
+// Incident generation.
+const (
+	incidentsPerResidentHour = 0.00002 // At a content vault: about one every two days per 1,000 residents
+	maxIncidentsPerAdvance   = 10
+)
+
+// IncidentInput contains data for reporting a security incident.
+type IncidentInput struct {
+	Type        models.IncidentType
+	Severity    models.Severity
+	Description string
+	Sector      string
+	OccurredAt  time.Time // Default now
+	ReportedBy  *string
+	Notes       string
+}
+
+// ReportIncident records an OPEN security incident, numbered for the year
+// it occurred.
+func (s *Service) ReportIncident(ctx context.Context, input IncidentInput) (_ *models.SecurityIncident, err error) {
+	ctx, cmd := s.begin(ctx, CommandReportIncident, input)
+	defer func() { cmd.End(err) }()
+
+	now := s.now()
+	occurred := input.OccurredAt
+	if occurred.IsZero() {
+		occurred = now
+	}
+	number, err := s.incidents.GetNextIncidentNumber(ctx, occurred.Year())
+	if err != nil {
+		return nil, err
+	}
+
+	incident := &models.SecurityIncident{
+		ID:             s.idGenerator.NewID(),
+		IncidentNumber: number,
+		IncidentType:   input.Type,
+		Severity:       input.Severity,
+		Description:    strings.TrimSpace(input.Description),
+		LocationSector: input.Sector,
+		ReportedBy:     input.ReportedBy,
+		Status:         models.IncidentStatusOpen,
+		OccurredAt:     occurred,
+		ReportedAt:     occurred,
+		Notes:          input.Notes,
+	}
+	if err := s.incidents.CreateIncident(ctx, nil, incident); err != nil {
+		return nil, err
+	}
+	return incident, nil
+}
+
+// incidentKinds are the incidents low morale breaks out in, with the share
+// of each and how they are described.
+var incidentKinds = []struct {
+	Type        models.IncidentType
+	Share       float64
+	Description string
+}{
+	{models.IncidentTypeAltercation, 0.35, "Altercation between residents"},
+	{models.IncidentTypeInsubordination, 0.25, "Resident refused a work order"},
+	{models.IncidentTypeTheft, 0.2, "Rations reported stolen"},
+	{models.IncidentTypeVandalism, 0.15, "Vault property damaged"},
+	{models.IncidentTypeAssault, 0.05, "Resident assaulted"},
+}
+
+// incidentSeverities are the shares of severities of random incidents.
+var incidentSeverities = []struct {
+	Severity models.Severity
+	Share    float64
+}{
+	{models.SeverityMinor, 0.6},
+	{models.SeverityModerate, 0.3},
+	{models.SeverityMajor, 0.1},
+}
+
+// IncidentModel is the simulation hook that rolls random security
+// incidents among the residents, more often the lower the vault's
+// happiness index.
+type IncidentModel struct {
+	service *Service
+	rng     *rand.Rand
+	rate    float64
+}
+
+// IncidentModel creates the incident hook. rate scales incident
+// probability, e.g. by the configured event frequency.
+func (s *Service) IncidentModel(rate float64, seed int64) *IncidentModel {
+	return &IncidentModel{
+		service: s,
+		rng:     rand.New(rand.NewSource(seed)),
+		rate:    rate,
+	}
+}
+
+// Name implements simulation.Hook.
+func (m *IncidentModel) Name() string {
+	return "security incidents"
+}
+
+// Advance implements simulation.Hook. The expected number of incidents
+// grows with the active residents and the elapsed hours, scaled by
+// models.IncidentFactor of the latest happiness index. Each breaks out in
+// a sector chosen by how many residents it houses and how low their
+// morale is, and goes through ReportIncident so that a replay need not
+// roll it again.
+func (m *IncidentModel) Advance(ctx context.Context, from, to time.Time) ([]simulation.Event, error) {
+	index := models.MoraleBaseline
+	snapshots, err := m.service.morale.ListRecentSnapshots(ctx, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(snapshots) > 0 {
+		index = snapshots[0].Index
+	}
+	sectors, err := m.service.morale.ListSectors(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var residents int
+	for _, sector := range sectors {
+		residents += sector.Residents
+	}
+	if residents == 0 {
+		return nil, nil
+	}
+
+	span := to.Sub(from)
+	expected := float64(residents) * span.Hours() * incidentsPerResidentHour * models.IncidentFactor(index) * m.rate
+	count := min(int(expected+m.rng.Float64()), maxIncidentsPerAdvance)
+
+	events := make([]simulation.Event, 0, count)
+	for i := 0; i < count; i++ {
+		sector := m.pickSector(sectors)
+		kind := incidentKinds[m.pick(len(incidentKinds), func(i int) float64 { return incidentKinds[i].Share })]
+		severity := incidentSeverities[m.pick(len(incidentSeverities), func(i int) float64 { return incidentSeverities[i].Share })].Severity
+
+		incident, err := m.service.ReportIncident(ctx, IncidentInput{
+			Type:        kind.Type,
+			Severity:    severity,
+			Description: kind.Description,
+			Sector:      sector.Sector,
+			OccurredAt:  from.Add(time.Duration(m.rng.Int63n(int64(span)))),
+			Notes:       fmt.Sprintf("Reported by the incident model at vault happiness index %d", index),
+		})
+		if err != nil {
+			return events, fmt.Errorf("reporting incident: %w", err)
+		}
+
+		level := simulation.EventWarning
+		if severity == models.SeverityMajor {
+			level = simulation.EventCritical
+		}
+		events = append(events, simulation.Event{
+			Time:   to,
+			Level:  level,
+			Source: m.Name(),
+			Message: fmt.Sprintf("%s %s (%s) in sector %s: %s",
+				incident.IncidentNumber, incident.IncidentType, incident.Severity, sector.Sector, incident.Description),
+		})
+	}
+	return events, nil
+}
+
+// pickSector chooses a sector weighted by its residents and by how far
+// their morale falls short of full.
+func (m *IncidentModel) pickSector(sectors []*models.SectorMorale) *models.SectorMorale {
+	return sectors[m.pick(len(sectors), func(i int) float64 {
+		return float64(sectors[i].Residents) * math.Max(float64(100-sectors[i].Index), 1)
+	})]
+}
+
+// pick chooses one of n options at random in proportion to its weight.
+func (m *IncidentModel) pick(n int, weight func(i int) float64) int {
+	var total float64
+	for i := 0; i < n; i++ {
+		total += weight(i)
+	}
+	roll := m.rng.Float64() * total
+	for i := 0; i < n; i++ {
+		roll -= weight(i)
+		if roll < 0 {
+			return i
+		}
+	}
+	return n - 1
+}
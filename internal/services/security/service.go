@@ -0,0 +1,64 @@
+// Package security provides access control and armory services for VT-UOS.
+package security
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/journal"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/services/resources"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// Service provides access control and armory operations.
+type Service struct {
+	db          *sql.DB
+	access      *repository.AccessRepository
+	armory      *repository.ArmoryRepository
+	incidents   *repository.SecurityRepository
+	residents   *repository.ResidentRepository
+	lockdowns   *repository.LockdownRepository
+	morale      *repository.MoraleRepository
+	resources   *resources.Service
+	idGenerator *util.IDGenerator
+	journal     *journal.Journal
+	vault       int
+	now         func() time.Time
+}
+
+// NewService creates a new security service.
+func NewService(db *sql.DB) *Service {
+	return &Service{
+		db:          db,
+		access:      repository.NewAccessRepository(db),
+		armory:      repository.NewArmoryRepository(db),
+		incidents:   repository.NewSecurityRepository(db),
+		residents:   repository.NewResidentRepository(db),
+		lockdowns:   repository.NewLockdownRepository(db),
+		morale:      repository.NewMoraleRepository(db),
+		resources:   resources.NewService(db),
+		idGenerator: util.NewIDGenerator(),
+		now:         func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// SetClock makes the service date grants, access events and weapon
+// custody with vault time.
+func (s *Service) SetClock(clock *util.VaultClock) {
+	s.now = clock.Now
+	s.resources.SetClock(clock)
+}
+
+// SetVault limits the service's access point, event and weapon lists, the
+// ammunition stock it draws and the morale its incident model reads to the
+// vault with the given number, and creates points there. A new service
+// covers every vault.
+func (s *Service) SetVault(vault int) {
+	s.vault = vault
+	s.access = s.access.ForVault(vault)
+	s.armory = s.armory.ForVault(vault)
+	s.residents = s.residents.ForVault(vault)
+	s.morale = s.morale.ForVault(vault)
+	s.resources.SetVault(vault)
+}
@@ -0,0 +1,133 @@
+// Package simulation advances vault state as vault time passes.
+package simulation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// TickInterval is the vault time processed by one simulation tick.
+const TickInterval = time.Hour
+
+// EventLevel indicates the severity of a simulation event.
+type EventLevel int
+
+const (
+	EventInfo EventLevel = iota
+	EventWarning
+	EventCritical
+)
+
+// Event is something a hook reports while processing vault time.
+type Event struct {
+	Time    time.Time
+	Level   EventLevel
+	Source  string
+	Message string
+	Vault   int // Vault the event concerns, 0 for the whole database
+}
+
+// Hook processes the vault time between two ticks. Services register hooks
+// with the engine rather than the engine depending on services.
+type Hook interface {
+	Name() string
+	Advance(ctx context.Context, from, to time.Time) ([]Event, error)
+}
+
+// vaultHook is a hook of one vault's records.
+type vaultHook struct {
+	Hook
+	vault int
+}
+
+// InVault marks the events of a hook of one vault's records, such as a
+// vault's failure model, as concerning that vault.
+func InVault(vault int, hook Hook) Hook {
+	return vaultHook{Hook: hook, vault: vault}
+}
+
+// Advance implements Hook, marking the events of the vault.
+func (h vaultHook) Advance(ctx context.Context, from, to time.Time) ([]Event, error) {
+	events, err := h.Hook.Advance(ctx, from, to)
+	for i := range events {
+		if events[i].Vault == 0 {
+			events[i].Vault = h.vault
+		}
+	}
+	return events, err
+}
+
+// Engine runs registered hooks over whole ticks of vault time.
+type Engine struct {
+	clock *util.VaultClock
+	hooks []Hook
+
+	mu   sync.Mutex
+	last time.Time // Vault time processed up to
+}
+
+// NewEngine creates an engine that starts processing from the current vault time.
+func NewEngine(clock *util.VaultClock) *Engine {
+	return &Engine{
+		clock: clock,
+		last:  clock.Now(),
+	}
+}
+
+// Register adds a hook. Hooks run in registration order.
+func (e *Engine) Register(hook Hook) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.hooks = append(e.hooks, hook)
+}
+
+// Due returns true if at least one tick of vault time is waiting to be processed.
+func (e *Engine) Due() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return len(e.hooks) > 0 && e.clock.Now().Sub(e.last) >= TickInterval
+}
+
+// Tick runs every hook over the whole ticks elapsed since the last run.
+// Leftover time shorter than a tick carries over to the next run. A failing
+// hook does not stop the others; their errors are joined. Cancelling ctx
+// stops the hooks not yet run.
+func (e *Engine) Tick(ctx context.Context) ([]Event, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	ticks := e.clock.Now().Sub(e.last) / TickInterval
+	if ticks < 1 {
+		return nil, nil
+	}
+	from := e.last
+	to := from.Add(ticks * TickInterval)
+
+	var events []Event
+	var errs []error
+	for _, hook := range e.hooks {
+		// Once cancelled, the remaining hooks skip the interval as a
+		// failing hook would
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		hookEvents, err := hook.Advance(ctx, from, to)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", hook.Name(), err))
+			continue
+		}
+		events = append(events, hookEvents...)
+	}
+	e.last = to
+
+	if len(errs) > 0 {
+		return events, errors.Join(errs...)
+	}
+	return events, nil
+}
@@ -0,0 +1,243 @@
+package simulation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Interval is how often a scheduled job recurs, in vault time.
+type Interval int
+
+const (
+	Daily     Interval = iota // At vault midnight
+	Weekly                    // At midnight starting Monday
+	Monthly                   // At midnight on the 1st
+	Quarterly                 // At midnight on the 1st of January, April, July and October
+)
+
+// String returns the interval name.
+func (i Interval) String() string {
+	switch i {
+	case Daily:
+		return "Daily"
+	case Weekly:
+		return "Weekly"
+	case Monthly:
+		return "Monthly"
+	case Quarterly:
+		return "Quarterly"
+	default:
+		return "Unknown"
+	}
+}
+
+// Next returns the first occurrence of the interval strictly after t, in t's
+// location.
+func (i Interval) Next(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch i {
+	case Weekly:
+		days := (8 - int(day.Weekday())) % 7 // Days until Monday
+		if days == 0 {
+			days = 7
+		}
+		return day.AddDate(0, 0, days)
+	case Monthly:
+		return time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+	case Quarterly:
+		quarterStart := time.Month((int(t.Month())-1)/3*3 + 1)
+		return time.Date(t.Year(), quarterStart+3, 1, 0, 0, 0, 0, t.Location())
+	default:
+		return day.AddDate(0, 0, 1)
+	}
+}
+
+// maxCatchUpRuns caps how many missed occurrences of one job a single
+// Advance runs, so a long jump in vault time cannot stall the simulation.
+// Later occurrences are skipped.
+const maxCatchUpRuns = 31
+
+// Job is a recurring vault task. Run performs the task for the occurrence at
+// the given vault time and summarizes what it did. A job that raises alerts
+// sets Check instead, which returns its events at their own levels.
+// Essential jobs keep running while the scheduler is paused. The events of
+// a job of one vault's records are marked with its Vault.
+type Job struct {
+	Name      string
+	Interval  Interval
+	Essential bool
+	Vault     int
+	Run       func(ctx context.Context, at time.Time) (string, error)
+	Check     func(ctx context.Context, at time.Time) ([]Event, error)
+}
+
+// JobStatus is the run history of a scheduled job.
+type JobStatus struct {
+	Name       string
+	Interval   Interval
+	LastRun    time.Time // Zero if the job has not run
+	NextRun    time.Time
+	LastResult string
+	LastErr    error
+	Runs       int
+	Paused     bool // Held by the pause check at the last Advance
+}
+
+// Scheduler runs jobs at fixed points of vault time. It is a Hook, so the
+// engine drives it as vault time passes.
+type Scheduler struct {
+	mu     sync.Mutex
+	start  time.Time
+	jobs   []*scheduledJob
+	paused func(ctx context.Context) bool
+}
+
+// scheduledJob is a registered job and its run history.
+type scheduledJob struct {
+	job    Job
+	status JobStatus
+}
+
+// NewScheduler creates a scheduler whose jobs first run at their next
+// occurrence after start.
+func NewScheduler(start time.Time) *Scheduler {
+	return &Scheduler{start: start}
+}
+
+// Add registers a job. Job names should be unique; Trigger runs the first
+// job registered under a name.
+func (s *Scheduler) Add(job Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs = append(s.jobs, &scheduledJob{
+		job: job,
+		status: JobStatus{
+			Name:     job.Name,
+			Interval: job.Interval,
+			NextRun:  job.Interval.Next(s.start),
+		},
+	})
+}
+
+// SetPause sets the check that pauses non-essential jobs, e.g. during a
+// vault lockdown. Advance asks it once; while it returns true, non-essential
+// jobs hold their next run and catch up once it returns false.
+func (s *Scheduler) SetPause(paused func(ctx context.Context) bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.paused = paused
+}
+
+// Jobs returns the status of every job, in registration order.
+func (s *Scheduler) Jobs() []JobStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	statuses := make([]JobStatus, len(s.jobs))
+	for i, j := range s.jobs {
+		statuses[i] = j.status
+	}
+	return statuses
+}
+
+// Name implements Hook.
+func (s *Scheduler) Name() string {
+	return "scheduler"
+}
+
+// Advance implements Hook. Every occurrence due by to runs in order, up to
+// maxCatchUpRuns per job, except those of non-essential jobs while paused. A
+// failing run does not stop other jobs or later occurrences; the errors are
+// joined. A cancelled ctx stops the runs, and those left are caught up on the
+// next advance.
+func (s *Scheduler) Advance(ctx context.Context, from, to time.Time) ([]Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	paused := s.paused != nil && s.paused(ctx)
+	var events []Event
+	var errs []error
+	for _, j := range s.jobs {
+		j.status.Paused = paused && !j.job.Essential
+		if j.status.Paused {
+			continue
+		}
+		for runs := 0; !j.status.NextRun.After(to); runs++ {
+			// Runs left undone by a cancellation are caught up next time
+			if ctx.Err() != nil {
+				break
+			}
+			if runs == maxCatchUpRuns {
+				j.status.NextRun = j.job.Interval.Next(to)
+				break
+			}
+			at := j.status.NextRun
+			jobEvents, err := j.runAt(ctx, at)
+			if err != nil {
+				errs = append(errs, err)
+			}
+			events = append(events, jobEvents...)
+			j.status.NextRun = j.job.Interval.Next(at)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if len(errs) > 0 {
+		return events, errors.Join(errs...)
+	}
+	return events, nil
+}
+
+// Trigger runs the named job now, at vault time at, without moving its next
+// scheduled run.
+func (s *Scheduler) Trigger(ctx context.Context, name string, at time.Time) ([]Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, j := range s.jobs {
+		if j.job.Name == name {
+			return j.runAt(ctx, at)
+		}
+	}
+	return nil, fmt.Errorf("no scheduled job named %q", name)
+}
+
+// runAt runs one occurrence of the job and records it in its status. A Run
+// job reports its summary as one informational event; a Check job reports
+// its own events, and those raised before an error are kept.
+func (j *scheduledJob) runAt(ctx context.Context, at time.Time) ([]Event, error) {
+	var events []Event
+	var result string
+	var err error
+	if j.job.Check != nil {
+		events, err = j.job.Check(ctx, at)
+		result = fmt.Sprintf("%d alert(s)", len(events))
+	} else {
+		result, err = j.job.Run(ctx, at)
+		if err == nil {
+			events = []Event{{
+				Time:    at,
+				Level:   EventInfo,
+				Source:  j.job.Name,
+				Message: fmt.Sprintf("%s: %s", j.job.Name, result),
+			}}
+		}
+	}
+	for i := range events {
+		if events[i].Vault == 0 {
+			events[i].Vault = j.job.Vault
+		}
+	}
+	j.status.LastRun = at
+	j.status.LastResult = result
+	j.status.LastErr = err
+	j.status.Runs++
+	if err != nil {
+		return events, fmt.Errorf("%s: %w", j.job.Name, err)
+	}
+	return events, nil
+}
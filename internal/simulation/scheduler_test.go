@@ -0,0 +1,194 @@
+package simulation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestInterval_Next(t *testing.T) {
+	at := func(year int, month time.Month, day, hour int) time.Time {
+		return time.Date(year, month, day, hour, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		interval Interval
+		from     time.Time
+		want     time.Time
+	}{
+		{"Daily mid-day", Daily, at(2077, 10, 23, 14), at(2077, 10, 24, 0)},
+		{"Daily at midnight", Daily, at(2077, 10, 23, 0), at(2077, 10, 24, 0)},
+		{"Weekly from Saturday", Weekly, at(2077, 10, 23, 9), at(2077, 10, 25, 0)},
+		{"Weekly from Monday", Weekly, at(2077, 10, 25, 0), at(2077, 11, 1, 0)},
+		{"Monthly across year", Monthly, at(2077, 12, 15, 8), at(2078, 1, 1, 0)},
+		{"Quarterly mid-quarter", Quarterly, at(2077, 8, 20, 8), at(2077, 10, 1, 0)},
+		{"Quarterly on quarter start", Quarterly, at(2077, 10, 1, 0), at(2078, 1, 1, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.interval.Next(tt.from); !got.Equal(tt.want) {
+				t.Errorf("%s.Next(%v) = %v, want %v", tt.interval, tt.from, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduler_Advance(t *testing.T) {
+	start := time.Date(2077, 10, 23, 9, 0, 0, 0, time.UTC)
+	s := NewScheduler(start)
+
+	var runs []time.Time
+	s.Add(Job{
+		Name:     "daily",
+		Interval: Daily,
+		Run: func(ctx context.Context, at time.Time) (string, error) {
+			runs = append(runs, at)
+			return "ok", nil
+		},
+	})
+	s.Add(Job{
+		Name:     "broken",
+		Interval: Daily,
+		Run: func(ctx context.Context, at time.Time) (string, error) {
+			return "", errors.New("no stock")
+		},
+	})
+
+	events, err := s.Advance(context.Background(), start, start.Add(50*time.Hour))
+	if err == nil {
+		t.Error("Advance() error = nil, want the failing job's error")
+	}
+	if len(runs) != 2 || len(events) != 2 {
+		t.Fatalf("daily ran %d times with %d events, want 2 and 2", len(runs), len(events))
+	}
+
+	jobs := s.Jobs()
+	if want := time.Date(2077, 10, 26, 0, 0, 0, 0, time.UTC); !jobs[0].NextRun.Equal(want) {
+		t.Errorf("NextRun = %v, want %v", jobs[0].NextRun, want)
+	}
+	if jobs[1].LastErr == nil || jobs[1].Runs != 2 {
+		t.Errorf("broken job status = %+v, want 2 failed runs", jobs[1])
+	}
+
+	// A manual run does not move the schedule
+	if _, err := s.Trigger(context.Background(), "daily", start.Add(51*time.Hour)); err != nil {
+		t.Fatalf("Trigger() error = %v", err)
+	}
+	if got := s.Jobs()[0]; got.Runs != 3 || !got.NextRun.Equal(jobs[0].NextRun) {
+		t.Errorf("after Trigger status = %+v", got)
+	}
+	if _, err := s.Trigger(context.Background(), "missing", start); err == nil {
+		t.Error("Trigger() of unknown job error = nil")
+	}
+}
+
+func TestScheduler_AdvanceCancelled(t *testing.T) {
+	start := time.Date(2077, 10, 23, 9, 0, 0, 0, time.UTC)
+	s := NewScheduler(start)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var runs []time.Time
+	s.Add(Job{
+		Name:     "daily",
+		Interval: Daily,
+		Run: func(ctx context.Context, at time.Time) (string, error) {
+			runs = append(runs, at)
+			cancel()
+			return "ok", nil
+		},
+	})
+
+	to := start.Add(74 * time.Hour)
+	if _, err := s.Advance(ctx, start, to); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Advance() error = %v, want context.Canceled", err)
+	}
+	if len(runs) != 1 {
+		t.Fatalf("daily ran %d times before the cancellation, want 1", len(runs))
+	}
+
+	// The runs left undone are caught up on the next advance
+	if _, err := s.Advance(context.Background(), to, to); err != nil {
+		t.Fatalf("Advance() error = %v", err)
+	}
+	if len(runs) != 3 {
+		t.Errorf("daily ran %d times after catching up, want 3", len(runs))
+	}
+}
+
+func TestScheduler_CheckJob(t *testing.T) {
+	start := time.Date(2077, 10, 23, 9, 0, 0, 0, time.UTC)
+	s := NewScheduler(start)
+	s.Add(Job{
+		Name:     "expiry",
+		Interval: Daily,
+		Vault:    76,
+		Check: func(ctx context.Context, at time.Time) ([]Event, error) {
+			return []Event{
+				{Time: at, Level: EventWarning, Source: "expiry", Message: "lot expiring"},
+				{Time: at, Level: EventCritical, Source: "expiry", Message: "lot expired"},
+			}, nil
+		},
+	})
+
+	events, err := s.Advance(context.Background(), start, start.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("Advance() error = %v", err)
+	}
+	if len(events) != 2 || events[0].Level != EventWarning || events[1].Level != EventCritical {
+		t.Errorf("events = %+v, want the check's warning and critical", events)
+	}
+	for _, e := range events {
+		if e.Vault != 76 {
+			t.Errorf("event %q of vault %d, want the job's vault 76", e.Message, e.Vault)
+		}
+	}
+	if got := s.Jobs()[0].LastResult; got != "2 alert(s)" {
+		t.Errorf("LastResult = %q, want %q", got, "2 alert(s)")
+	}
+}
+
+func TestScheduler_Pause(t *testing.T) {
+	start := time.Date(2077, 10, 23, 9, 0, 0, 0, time.UTC)
+	s := NewScheduler(start)
+
+	runs := map[string]int{}
+	for _, essential := range []bool{true, false} {
+		name := "routine"
+		if essential {
+			name = "rations"
+		}
+		s.Add(Job{
+			Name:      name,
+			Interval:  Daily,
+			Essential: essential,
+			Run: func(ctx context.Context, at time.Time) (string, error) {
+				runs[name]++
+				return "ok", nil
+			},
+		})
+	}
+
+	locked := true
+	s.SetPause(func(ctx context.Context) bool { return locked })
+	if _, err := s.Advance(context.Background(), start, start.Add(48*time.Hour)); err != nil {
+		t.Fatalf("Advance() error = %v", err)
+	}
+	if runs["rations"] != 2 || runs["routine"] != 0 {
+		t.Errorf("while paused runs = %v, want rations 2 and routine 0", runs)
+	}
+	if jobs := s.Jobs(); jobs[0].Paused || !jobs[1].Paused {
+		t.Errorf("Paused = %v, %v, want only the routine job paused", jobs[0].Paused, jobs[1].Paused)
+	}
+
+	// Held occurrences catch up once the pause lifts
+	locked = false
+	if _, err := s.Advance(context.Background(), start.Add(48*time.Hour), start.Add(49*time.Hour)); err != nil {
+		t.Fatalf("Advance() error = %v", err)
+	}
+	if runs["routine"] != 2 || s.Jobs()[1].Paused {
+		t.Errorf("after resume routine ran %d times, paused %v; want 2, false", runs["routine"], s.Jobs()[1].Paused)
+	}
+}
@@ -0,0 +1,200 @@
+package simulation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// ScriptActionAlert raises the event's message as an alert. It needs no
+// handler; every other action is handled by a service.
+const ScriptActionAlert = "alert"
+
+// Script is a scenario of events fired at fixed points of vault time, e.g.
+// for drills and training.
+type Script struct {
+	Name   string        `toml:"name" json:"name"`
+	Events []ScriptEvent `toml:"event" json:"events"`
+}
+
+// ScriptEvent is one scripted event. Day 1 is the simulation start date;
+// the event fires at Hour on that vault day. Action names what happens and
+// the remaining fields are its parameters.
+type ScriptEvent struct {
+	Day        int      `toml:"day" json:"day"`
+	Hour       int      `toml:"hour" json:"hour"`
+	Action     string   `toml:"action" json:"action"`
+	Level      string   `toml:"level" json:"level"` // info, warning (default) or critical
+	Message    string   `toml:"message" json:"message"`
+	System     string   `toml:"system" json:"system"`         // System code, for system_status
+	Status     string   `toml:"status" json:"status"`         // New status, for system_status
+	Efficiency *float64 `toml:"efficiency" json:"efficiency"` // Efficiency percent, for system_status
+	Count      int      `toml:"count" json:"count"`           // Number of residents, for admit
+	Item       string   `toml:"item" json:"item"`             // Item code, for stock_loss
+	Percent    float64  `toml:"percent" json:"percent"`       // Percent of the item's stock lost, for stock_loss
+}
+
+// At returns the vault time the event fires at for a simulation that starts
+// at start.
+func (e ScriptEvent) At(start time.Time) time.Time {
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+	return day.AddDate(0, 0, e.Day-1).Add(time.Duration(e.Hour) * time.Hour)
+}
+
+// EventLevel returns the level the event is reported at.
+func (e ScriptEvent) EventLevel() EventLevel {
+	switch strings.ToLower(e.Level) {
+	case "info":
+		return EventInfo
+	case "critical":
+		return EventCritical
+	default:
+		return EventWarning
+	}
+}
+
+// Validate checks the event's timing, level and action parameters that do
+// not depend on vault data.
+func (e ScriptEvent) Validate() error {
+	if e.Day < 1 {
+		return fmt.Errorf("day must be at least 1, got %d", e.Day)
+	}
+	if e.Hour < 0 || e.Hour > 23 {
+		return fmt.Errorf("hour must be 0-23, got %d", e.Hour)
+	}
+	switch strings.ToLower(e.Level) {
+	case "", "info", "warning", "critical":
+	default:
+		return fmt.Errorf("invalid level %q: use info, warning or critical", e.Level)
+	}
+	if e.Action == "" {
+		return fmt.Errorf("action is required")
+	}
+	if e.Action == ScriptActionAlert && e.Message == "" {
+		return fmt.Errorf("an alert needs a message")
+	}
+	return nil
+}
+
+// LoadScript reads a scenario script from a TOML file, or a JSON file when
+// its extension is .json, and validates its events.
+func LoadScript(path string) (*Script, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading script: %w", err)
+	}
+
+	var script Script
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(data, &script)
+	} else {
+		err = toml.Unmarshal(data, &script)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing script %s: %w", path, err)
+	}
+
+	if script.Name == "" {
+		script.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	for i, event := range script.Events {
+		if err := event.Validate(); err != nil {
+			return nil, fmt.Errorf("script %s, event %d: %w", path, i+1, err)
+		}
+	}
+	return &script, nil
+}
+
+// ScriptHandler performs a scripted action at vault time at and summarizes
+// what it did.
+type ScriptHandler func(ctx context.Context, event ScriptEvent, at time.Time) (string, error)
+
+// ScriptRunner fires a script's events as vault time passes. It is a Hook.
+// Events due before the engine's first tick, e.g. on a restart partway
+// through a scenario, are skipped rather than replayed.
+type ScriptRunner struct {
+	script   *Script
+	start    time.Time
+	handlers map[string]ScriptHandler
+	events   []ScriptEvent // Sorted by firing time
+	next     int           // Index of the first event not yet fired or skipped
+}
+
+// NewScriptRunner creates a runner for a script whose day 1 is the day of
+// start. Every action in the script other than alert needs a handler.
+func NewScriptRunner(script *Script, start time.Time, handlers map[string]ScriptHandler) (*ScriptRunner, error) {
+	for i, event := range script.Events {
+		if _, ok := handlers[event.Action]; !ok && event.Action != ScriptActionAlert {
+			return nil, fmt.Errorf("script %s, event %d: unknown action %q", script.Name, i+1, event.Action)
+		}
+	}
+
+	events := make([]ScriptEvent, len(script.Events))
+	copy(events, script.Events)
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].At(start).Before(events[j].At(start))
+	})
+
+	return &ScriptRunner{script: script, start: start, handlers: handlers, events: events}, nil
+}
+
+// Name implements Hook.
+func (r *ScriptRunner) Name() string {
+	return "script " + r.script.Name
+}
+
+// Pending returns the events not yet fired, in firing order.
+func (r *ScriptRunner) Pending() []ScriptEvent {
+	return r.events[r.next:]
+}
+
+// Advance implements Hook. Each event due in (from, to] fires once, in
+// order. A failed action is reported as a critical event rather than an
+// error, so the events fired with it still reach the operator.
+func (r *ScriptRunner) Advance(ctx context.Context, from, to time.Time) ([]Event, error) {
+	var events []Event
+	for ; r.next < len(r.events); r.next++ {
+		event := r.events[r.next]
+		at := event.At(r.start)
+		if at.After(to) {
+			break
+		}
+		if !at.After(from) {
+			continue
+		}
+		events = append(events, r.fire(ctx, event, at))
+	}
+	return events, nil
+}
+
+// fire performs one event and logs the outcome.
+func (r *ScriptRunner) fire(ctx context.Context, event ScriptEvent, at time.Time) Event {
+	result := Event{Time: at, Level: event.EventLevel(), Source: r.Name(), Message: event.Message}
+
+	if handler, ok := r.handlers[event.Action]; ok {
+		summary, err := handler(ctx, event, at)
+		if err != nil {
+			slog.Warn("scripted event failed", "script", r.script.Name, "day", event.Day, "action", event.Action, "error", err)
+			result.Level = EventCritical
+			result.Message = fmt.Sprintf("Scripted %s failed: %v", event.Action, err)
+			return result
+		}
+		switch {
+		case result.Message == "":
+			result.Message = summary
+		case summary != "":
+			result.Message += ": " + summary
+		}
+	}
+
+	slog.Info("scripted event", "script", r.script.Name, "day", event.Day, "action", event.Action, "message", result.Message)
+	return result
+}
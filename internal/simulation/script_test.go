@@ -0,0 +1,117 @@
+package simulation
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadScript(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	script, err := LoadScript(write("drill.toml", `
+name = "Water chip drill"
+
+[[event]]
+day = 120
+hour = 8
+action = "system_status"
+system = "WATER-PURIF-01"
+status = "FAILED"
+level = "critical"
+message = "Water chip failure"
+
+[[event]]
+day = 200
+action = "admit"
+count = 15
+`))
+	if err != nil {
+		t.Fatalf("LoadScript() error = %v", err)
+	}
+	if script.Name != "Water chip drill" || len(script.Events) != 2 {
+		t.Fatalf("LoadScript() = %q with %d events, want Water chip drill with 2", script.Name, len(script.Events))
+	}
+	if ev := script.Events[0]; ev.System != "WATER-PURIF-01" || ev.EventLevel() != EventCritical {
+		t.Errorf("first event = %+v", ev)
+	}
+
+	json, err := LoadScript(write("census.json", `{"events": [{"day": 3, "action": "alert", "message": "Census drill"}]}`))
+	if err != nil {
+		t.Fatalf("LoadScript(json) error = %v", err)
+	}
+	if json.Name != "census" || json.Events[0].EventLevel() != EventWarning {
+		t.Errorf("LoadScript(json) = %+v", json)
+	}
+
+	if _, err := LoadScript(write("bad.toml", "[[event]]\nday = 0\naction = \"alert\"\nmessage = \"x\"\n")); err == nil {
+		t.Error("LoadScript() accepted day 0")
+	}
+}
+
+func TestScriptRunner_Advance(t *testing.T) {
+	start := time.Date(2077, 10, 23, 9, 0, 0, 0, time.UTC)
+	script := &Script{Name: "drill", Events: []ScriptEvent{
+		{Day: 3, Action: "admit", Count: 2, Message: "Survivors at the door"},
+		{Day: 1, Hour: 8, Action: ScriptActionAlert, Message: "Before the vault opened"},
+		{Day: 2, Hour: 6, Action: ScriptActionAlert, Message: "Drill begins", Level: "info"},
+		{Day: 3, Hour: 12, Action: "fail", Message: "Reactor scram"},
+	}}
+
+	var admitted int
+	handlers := map[string]ScriptHandler{
+		"admit": func(ctx context.Context, event ScriptEvent, at time.Time) (string, error) {
+			admitted += event.Count
+			return "2 admitted", nil
+		},
+		"fail": func(ctx context.Context, event ScriptEvent, at time.Time) (string, error) {
+			return "", errors.New("no such system")
+		},
+	}
+
+	if _, err := NewScriptRunner(script, start, map[string]ScriptHandler{}); err == nil {
+		t.Fatal("NewScriptRunner() accepted actions without handlers")
+	}
+	runner, err := NewScriptRunner(script, start, handlers)
+	if err != nil {
+		t.Fatalf("NewScriptRunner() error = %v", err)
+	}
+
+	events, err := runner.Advance(context.Background(), start, start.Add(72*time.Hour))
+	if err != nil {
+		t.Fatalf("Advance() error = %v", err)
+	}
+
+	want := []struct {
+		level   EventLevel
+		message string
+	}{
+		{EventInfo, "Drill begins"},
+		{EventWarning, "Survivors at the door: 2 admitted"},
+		{EventCritical, "Scripted fail failed: no such system"},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("Advance() returned %d events, want %d: %+v", len(events), len(want), events)
+	}
+	for i, w := range want {
+		if events[i].Level != w.level || events[i].Message != w.message {
+			t.Errorf("event %d = %v %q, want %v %q", i, events[i].Level, events[i].Message, w.level, w.message)
+		}
+	}
+	if admitted != 2 {
+		t.Errorf("admitted = %d, want 2", admitted)
+	}
+	if pending := runner.Pending(); len(pending) != 0 {
+		t.Errorf("Pending() = %d events after the last, want 0", len(pending))
+	}
+}
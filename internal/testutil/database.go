@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	_ "modernc.org/sqlite" // SQLite driver
@@ -86,7 +87,9 @@ func (tdb *TestDB) RunMigrations(t *testing.T, migrationsDir string) {
 			t.Fatalf("failed to read migration %s: %v", file.Name(), err)
 		}
 
-		if _, err := tx.ExecContext(ctx, string(sqlBytes)); err != nil {
+		// Only the up section applies; the down section follows its marker
+		upSQL, _, _ := strings.Cut(string(sqlBytes), "-- +migrate Down")
+		if _, err := tx.ExecContext(ctx, upSQL); err != nil {
 			t.Fatalf("failed to execute migration %s: %v", file.Name(), err)
 		}
 	}
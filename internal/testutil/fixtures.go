@@ -175,6 +175,7 @@ func FixtureResourceItem(categoryID string, overrides ...func(*models.ResourceIt
 		Description:     "High-protein meal ration",
 		UnitOfMeasure:   "unit",
 		CaloriesPerUnit: &calories,
+		Nutrients:       &models.Nutrients{ProteinG: 20, CarbsG: 25, FatG: 6, VitaminsPct: 15},
 		ShelfLifeDays:   &shelfLife,
 		IsProducible:    false,
 		CreatedAt:       now,
@@ -0,0 +1,221 @@
+package training
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// Points each part of the score is worth; they add up to 100.
+const (
+	survivalPoints   = 50.0
+	wastePoints      = 20.0
+	resolutionPoints = 30.0
+
+	// resolvedPoints of the resolution points are earned by resolving the
+	// crisis at all, the rest by how little of the time limit it took.
+	resolvedPoints = 10.0
+)
+
+// debriefWidth is the width of the printed debrief.
+const debriefWidth = 72
+
+// Debrief is the outcome of an exercise.
+type Debrief struct {
+	Scenario   string
+	Crisis     time.Time // When the crisis began
+	Deadline   time.Time // When the time limit ran out
+	End        time.Time // When the crisis was resolved, or the deadline
+	Resolved   bool
+	Objectives []ObjectiveOutcome
+
+	Population int                // Residents alive when the exercise started
+	Deaths     []*models.Resident // Residents who died during it
+	Lots       int                // Lots available when the exercise started
+	Spoiled    int                // Lots that spoiled during it
+
+	Actions []Action
+	Score   Score
+}
+
+// ObjectiveOutcome is how an objective of an exercise went.
+type ObjectiveOutcome struct {
+	Objective string
+	MetAt     *time.Time // When it was first met, nil if never
+}
+
+// Score is an exercise's score by part.
+type Score struct {
+	Survival   float64 // Of 50: the share of the population that survived
+	Waste      float64 // Of 20: lots spoiled against the waste tolerance
+	Resolution float64 // Of 30: 10 for resolving the crisis, 20 for speed
+}
+
+// Total returns the score out of 100.
+func (s Score) Total() int {
+	return int(math.Round(s.Survival + s.Waste + s.Resolution))
+}
+
+// Rating returns how a score reads in a debrief.
+func (s Score) Rating() string {
+	switch total := s.Total(); {
+	case total >= 90:
+		return "EXEMPLARY"
+	case total >= 75:
+		return "PROFICIENT"
+	case total >= 50:
+		return "ADEQUATE"
+	default:
+		return "RETRAINING REQUIRED"
+	}
+}
+
+// score scores the outcome of an exercise.
+func score(d *Debrief, objectives Objectives) Score {
+	var s Score
+	s.Survival = survivalPoints
+	if d.Population > 0 {
+		s.Survival = survivalPoints * (1 - math.Min(1, float64(len(d.Deaths))/float64(d.Population)))
+	}
+	s.Waste = wastePoints * (1 - math.Min(1, d.WastePercent()/objectives.WasteTolerance))
+	if d.Resolved {
+		taken := d.End.Sub(d.Crisis).Hours() / float64(objectives.TimeLimitHours)
+		s.Resolution = resolvedPoints + (resolutionPoints-resolvedPoints)*(1-math.Min(1, taken))
+	}
+	return s
+}
+
+// WastePercent returns the share of lots that spoiled, in percent.
+func (d *Debrief) WastePercent() float64 {
+	if d.Lots == 0 {
+		return 0
+	}
+	return float64(d.Spoiled) / float64(d.Lots) * 100
+}
+
+// Failed returns the number of actions that failed.
+func (d *Debrief) Failed() int {
+	n := 0
+	for _, a := range d.Actions {
+		if a.Error != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// Summary returns the outcome in a line, for an alert.
+func (d *Debrief) Summary() string {
+	outcome := "not resolved within " + formatHours(d.Deadline.Sub(d.Crisis))
+	if d.Resolved {
+		outcome = "resolved in " + formatHours(d.End.Sub(d.Crisis))
+	}
+	return fmt.Sprintf("Training exercise %s %s: score %d/100, %s", d.Scenario, outcome, d.Score.Total(), d.Score.Rating())
+}
+
+// FileName returns the name the debrief is written under.
+func (d *Debrief) FileName() string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + 'a' - 'A'
+		default:
+			return '-'
+		}
+	}, d.Scenario)
+	return fmt.Sprintf("training-debrief-%s-%s.txt", name, d.End.Format("20060102-1504"))
+}
+
+// Write writes the debrief to dir and returns the path of the file.
+func (d *Debrief) Write(dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return "", fmt.Errorf("creating debrief directory: %w", err)
+	}
+	path := filepath.Join(dir, d.FileName())
+	if err := os.WriteFile(path, []byte(d.Text()), 0640); err != nil {
+		return "", fmt.Errorf("writing debrief: %w", err)
+	}
+	return path, nil
+}
+
+// Text renders the debrief as plain text for printing.
+func (d *Debrief) Text() string {
+	var b strings.Builder
+	rule := strings.Repeat("=", debriefWidth)
+
+	b.WriteString(rule + "\n")
+	b.WriteString("TRAINING EXERCISE DEBRIEF\n")
+	b.WriteString(fmt.Sprintf("Scenario %s\n", d.Scenario))
+	b.WriteString(rule + "\n")
+
+	writeSection(&b, "OUTCOME")
+	b.WriteString(fmt.Sprintf("  Crisis began     %s\n", util.FormatDateTime(d.Crisis)))
+	b.WriteString(fmt.Sprintf("  Time limit       %s (%s)\n", util.FormatDateTime(d.Deadline), formatHours(d.Deadline.Sub(d.Crisis))))
+	if d.Resolved {
+		b.WriteString(fmt.Sprintf("  Resolved         %s, after %s\n", util.FormatDateTime(d.End), formatHours(d.End.Sub(d.Crisis))))
+	} else {
+		b.WriteString("  Resolved         NO\n")
+	}
+
+	writeSection(&b, "OBJECTIVES")
+	for _, o := range d.Objectives {
+		status := "NOT MET"
+		if o.MetAt != nil {
+			status = "met after " + formatHours(o.MetAt.Sub(d.Crisis))
+		}
+		b.WriteString(fmt.Sprintf("  %-48s %s\n", o.Objective, status))
+	}
+
+	writeSection(&b, "SCORE")
+	b.WriteString(fmt.Sprintf("  Population survival  %5.1f / %.0f   %d of %d residents died\n",
+		d.Score.Survival, survivalPoints, len(d.Deaths), d.Population))
+	b.WriteString(fmt.Sprintf("  Resource waste       %5.1f / %.0f   %d of %d lots spoiled (%.1f%%)\n",
+		d.Score.Waste, wastePoints, d.Spoiled, d.Lots, d.WastePercent()))
+	b.WriteString(fmt.Sprintf("  Time to resolution   %5.1f / %.0f\n", d.Score.Resolution, resolutionPoints))
+	b.WriteString(fmt.Sprintf("  TOTAL                %5d / 100  %s\n", d.Score.Total(), d.Score.Rating()))
+	for _, res := range d.Deaths {
+		b.WriteString(fmt.Sprintf("  Died  %s %s\n", res.RegistryNumber, res.FullName()))
+	}
+
+	writeSection(&b, "OPERATOR ACTIONS")
+	if len(d.Actions) == 0 {
+		b.WriteString("  None\n")
+	} else {
+		b.WriteString(fmt.Sprintf("  %d action(s), %d failed; first response after %s\n",
+			len(d.Actions), d.Failed(), formatHours(d.Actions[0].At.Sub(d.Crisis))))
+	}
+	for _, a := range d.Actions {
+		line := fmt.Sprintf("  +%-8s %s", formatHours(a.At.Sub(d.Crisis)), a.Command)
+		if a.Error != "" {
+			line += "  FAILED: " + a.Error
+		}
+		b.WriteString(line + "\n")
+	}
+
+	b.WriteString(rule + "\n")
+	return b.String()
+}
+
+// writeSection writes a debrief section heading.
+func writeSection(b *strings.Builder, title string) {
+	b.WriteString("\n" + title + "\n")
+	b.WriteString(strings.Repeat("-", len(title)) + "\n")
+}
+
+// formatHours formats a span of vault time in hours and minutes, e.g.
+// "14h05m".
+func formatHours(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	d = d.Round(time.Minute)
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
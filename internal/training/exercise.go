@@ -0,0 +1,271 @@
+package training
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/services/resources"
+	"github.com/vtuos/vtuos/internal/simulation"
+)
+
+// Action is a command the operator ran during an exercise.
+type Action struct {
+	At      time.Time
+	Command string // e.g. "facilities.complete_work_order"
+	Error   string // Error the command returned, empty if it succeeded
+}
+
+// Exercise runs a scenario as a training exercise. It is a Hook, registered
+// after the runner of the scenario's script: each tick from the crisis on
+// it checks the objectives, and it ends once all are met at once or the
+// time limit passes, scoring the outcome and writing the debrief.
+type Exercise struct {
+	scenario  *Scenario
+	crisis    time.Time // Vault time of the first scripted event
+	deadline  time.Time
+	now       func() time.Time
+	debriefTo string // Directory the debrief is written to
+
+	residents  *repository.ResidentRepository
+	facilities *repository.FacilityRepository
+	resources  *resources.Service
+
+	// Residents alive and lots available when the exercise started
+	population int
+	lots       int
+
+	mu      sync.Mutex
+	actions []Action
+	met     map[string]time.Time // When each objective was first met
+	debrief *Debrief             // Set once the exercise ends
+}
+
+// Start starts an exercise of a scenario whose script day 1 is the day of
+// start, recording the vault's population and stock to score it against.
+// now is the vault clock actions are stamped with; the debrief is written
+// to dir.
+func Start(ctx context.Context, db *sql.DB, scenario *Scenario, start time.Time, now func() time.Time, dir string) (*Exercise, error) {
+	e := &Exercise{
+		scenario:   scenario,
+		now:        now,
+		debriefTo:  dir,
+		residents:  repository.NewResidentRepository(db),
+		facilities: repository.NewFacilityRepository(db),
+		resources:  resources.NewService(db),
+		met:        make(map[string]time.Time),
+	}
+	for i, event := range scenario.Script.Events {
+		if at := event.At(start); i == 0 || at.Before(e.crisis) {
+			e.crisis = at
+		}
+	}
+	e.deadline = e.crisis.Add(time.Duration(scenario.Objectives.TimeLimitHours) * time.Hour)
+
+	counts, err := e.residents.CountByStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for status, n := range counts {
+		if status.IsAlive() {
+			e.population += n
+		}
+	}
+	stocks, err := e.resources.ListStocks(ctx, models.StockFilter{Status: ptr(models.StockStatusAvailable)}, models.Pagination{Page: 1, PageSize: 1})
+	if err != nil {
+		return nil, fmt.Errorf("counting lots: %w", err)
+	}
+	e.lots = stocks.Total
+
+	for _, code := range scenario.Objectives.Systems {
+		if _, err := e.facilities.GetSystemByCode(ctx, code); err != nil {
+			return nil, fmt.Errorf("objective system %s: %w", code, err)
+		}
+	}
+	for _, stock := range scenario.Objectives.Stock {
+		if _, err := e.resources.GetItemByCode(ctx, stock.Item); err != nil {
+			return nil, fmt.Errorf("objective item %s: %w", stock.Item, err)
+		}
+	}
+	return e, nil
+}
+
+// Name implements Hook.
+func (e *Exercise) Name() string {
+	return "training " + e.scenario.Script.Name
+}
+
+// Crisis returns the vault time the crisis begins and the time limit to
+// resolve it by.
+func (e *Exercise) Crisis() (begins, deadline time.Time) {
+	return e.crisis, e.deadline
+}
+
+// Record records a command the operator ran, once the crisis has begun and
+// until the exercise ends. It may be called from any goroutine.
+func (e *Exercise) Record(command string, err error) {
+	at := e.now()
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.debrief != nil || at.Before(e.crisis) {
+		return
+	}
+	action := Action{At: at, Command: command}
+	if err != nil {
+		action.Error = err.Error()
+	}
+	e.actions = append(e.actions, action)
+}
+
+// Debrief returns the debrief of the exercise, nil until it ends.
+func (e *Exercise) Debrief() *Debrief {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.debrief
+}
+
+// Advance implements Hook. It checks the objectives at the end of the
+// interval, once the crisis has begun, and ends the exercise when they are
+// all met or the time limit has passed.
+func (e *Exercise) Advance(ctx context.Context, _, to time.Time) ([]simulation.Event, error) {
+	if e.Debrief() != nil || to.Before(e.crisis) {
+		return nil, nil
+	}
+
+	resolved, err := e.check(ctx, to)
+	if err != nil {
+		return nil, err
+	}
+	if !resolved && to.Before(e.deadline) {
+		return nil, nil
+	}
+	end := to
+	if !resolved {
+		end = e.deadline
+	}
+
+	debrief, err := e.finish(ctx, end, resolved)
+	if err != nil {
+		return nil, err
+	}
+	event := simulation.Event{Time: to, Level: simulation.EventInfo, Source: e.Name(), Message: debrief.Summary()}
+	if path, err := debrief.Write(e.debriefTo); err != nil {
+		slog.Error("writing training debrief", "scenario", e.scenario.Script.Name, "error", err)
+		event.Message += "; debrief not written: " + err.Error()
+	} else {
+		event.Message += "; debrief written to " + path
+	}
+	slog.Info("training exercise ended", "scenario", e.scenario.Script.Name, "resolved", resolved, "score", debrief.Score.Total())
+	return []simulation.Event{event}, nil
+}
+
+// check records the objectives met at vault time at and reports whether
+// all are.
+func (e *Exercise) check(ctx context.Context, at time.Time) (bool, error) {
+	all := true
+	for _, code := range e.scenario.Objectives.Systems {
+		sys, err := e.facilities.GetSystemByCode(ctx, code)
+		if err != nil {
+			return false, fmt.Errorf("objective system %s: %w", code, err)
+		}
+		all = e.mark(systemObjective(code), sys.Status == models.FacilityStatusOperational, at) && all
+	}
+	for _, stock := range e.scenario.Objectives.Stock {
+		item, err := e.resources.GetItemByCode(ctx, stock.Item)
+		if err != nil {
+			return false, fmt.Errorf("objective item %s: %w", stock.Item, err)
+		}
+		runway, err := e.resources.GetResourceRunway(ctx, item.ID)
+		if err != nil {
+			return false, fmt.Errorf("objective item %s: %w", stock.Item, err)
+		}
+		lasts := runway.DaysRemaining < 0 || runway.DaysRemaining >= stock.Days
+		all = e.mark(stockObjective(stock), lasts, at) && all
+	}
+	return all, nil
+}
+
+// mark records when an objective was first met and returns whether it is
+// met now.
+func (e *Exercise) mark(objective string, met bool, at time.Time) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if _, ok := e.met[objective]; met && !ok {
+		e.met[objective] = at
+	}
+	return met
+}
+
+// finish ends the exercise at vault time end and scores it.
+func (e *Exercise) finish(ctx context.Context, end time.Time, resolved bool) (*Debrief, error) {
+	from := dayStart(e.crisis)
+	died, err := e.residents.ListDiedBetween(ctx, from, dayStart(end).AddDate(0, 0, 1))
+	if err != nil {
+		return nil, fmt.Errorf("listing deaths: %w", err)
+	}
+	spoiled, err := e.resources.GetTransactionHistory(ctx, models.TransactionFilter{
+		TransactionType: ptr(models.TransactionTypeSpoilage),
+		StartDate:       &e.crisis,
+		EndDate:         &end,
+	}, models.Pagination{Page: 1, PageSize: 1})
+	if err != nil {
+		return nil, fmt.Errorf("counting spoiled lots: %w", err)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	d := &Debrief{
+		Scenario:   e.scenario.Script.Name,
+		Crisis:     e.crisis,
+		Deadline:   e.deadline,
+		End:        end,
+		Resolved:   resolved,
+		Population: e.population,
+		Deaths:     died,
+		Lots:       e.lots,
+		Spoiled:    spoiled.Total,
+		Actions:    append([]Action(nil), e.actions...),
+	}
+	for _, code := range e.scenario.Objectives.Systems {
+		d.Objectives = append(d.Objectives, e.outcome(systemObjective(code)))
+	}
+	for _, stock := range e.scenario.Objectives.Stock {
+		d.Objectives = append(d.Objectives, e.outcome(stockObjective(stock)))
+	}
+	d.Score = score(d, e.scenario.Objectives)
+	e.debrief = d
+	return d, nil
+}
+
+// outcome returns how an objective went. Call with e.mu held.
+func (e *Exercise) outcome(objective string) ObjectiveOutcome {
+	o := ObjectiveOutcome{Objective: objective}
+	if at, ok := e.met[objective]; ok {
+		o.MetAt = &at
+	}
+	return o
+}
+
+// systemObjective describes the objective of restoring a system.
+func systemObjective(code string) string {
+	return fmt.Sprintf("Restore %s to OPERATIONAL", code)
+}
+
+// stockObjective describes the objective of restoring an item's runway.
+func stockObjective(stock StockObjective) string {
+	return fmt.Sprintf("Restore %s to %d days of stock", stock.Item, stock.Days)
+}
+
+// dayStart returns midnight of t's day.
+func dayStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
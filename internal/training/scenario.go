@@ -0,0 +1,126 @@
+// Package training runs scripted crisis scenarios as exercises for
+// operators: it tracks what the operator does while the crisis unfolds,
+// decides when the crisis is resolved and scores the outcome in a debrief.
+package training
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/vtuos/vtuos/internal/simulation"
+)
+
+// DefaultWasteTolerance is the share of lots, in percent, an exercise may
+// let spoil before its waste score reaches nothing.
+const DefaultWasteTolerance = 5.0
+
+// Scenario is a crisis to train on: a scenario script whose events cause
+// it, and the objectives that resolve it.
+type Scenario struct {
+	Script     *simulation.Script
+	Objectives Objectives
+}
+
+// Objectives decide when a scenario's crisis is resolved and how long the
+// operator has.
+type Objectives struct {
+	// TimeLimitHours is the vault time from the scenario's first event the
+	// operator has to resolve the crisis.
+	TimeLimitHours int `toml:"time_limit_hours" json:"time_limit_hours"`
+
+	// Systems must be OPERATIONAL again, e.g. a failed reactor.
+	Systems []string `toml:"systems" json:"systems"`
+
+	// Stock lists items whose runway must be restored, e.g. water lost to
+	// contamination.
+	Stock []StockObjective `toml:"stock" json:"stock"`
+
+	// WasteTolerance is the share of lots, in percent, that may spoil
+	// before the waste score reaches nothing; DefaultWasteTolerance if 0.
+	WasteTolerance float64 `toml:"waste_tolerance_percent" json:"waste_tolerance_percent"`
+}
+
+// StockObjective requires an item's stock to last at least Days at its
+// current rate of consumption.
+type StockObjective struct {
+	Item string `toml:"item" json:"item"`
+	Days int    `toml:"days" json:"days"`
+}
+
+// Count returns the number of objectives.
+func (o Objectives) Count() int {
+	return len(o.Systems) + len(o.Stock)
+}
+
+// Validate checks that the objectives can be met and timed.
+func (o Objectives) Validate() error {
+	var errs []error
+	if o.TimeLimitHours < 1 {
+		errs = append(errs, fmt.Errorf("time_limit_hours must be at least 1, got %d", o.TimeLimitHours))
+	}
+	if o.Count() == 0 {
+		errs = append(errs, errors.New("at least one system or stock objective is required"))
+	}
+	for i, code := range o.Systems {
+		if code == "" {
+			errs = append(errs, fmt.Errorf("systems[%d]: empty system code", i))
+		}
+	}
+	for i, stock := range o.Stock {
+		if stock.Item == "" {
+			errs = append(errs, fmt.Errorf("stock[%d]: item is required", i))
+		}
+		if stock.Days < 1 {
+			errs = append(errs, fmt.Errorf("stock[%d]: days must be at least 1, got %d", i, stock.Days))
+		}
+	}
+	if o.WasteTolerance < 0 || o.WasteTolerance > 100 {
+		errs = append(errs, fmt.Errorf("waste_tolerance_percent must be 0-100, got %g", o.WasteTolerance))
+	}
+	return errors.Join(errs...)
+}
+
+// LoadScenario reads a training scenario: a scenario script, TOML or JSON
+// as LoadScript reads it, with an objectives table.
+func LoadScenario(path string) (*Scenario, error) {
+	script, err := simulation.LoadScript(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(script.Events) == 0 {
+		return nil, fmt.Errorf("scenario %s has no events to cause a crisis", path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading scenario: %w", err)
+	}
+	var file struct {
+		Objectives *Objectives `toml:"objectives" json:"objectives"`
+	}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(data, &file)
+	} else {
+		err = toml.Unmarshal(data, &file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing scenario %s: %w", path, err)
+	}
+	if file.Objectives == nil {
+		return nil, fmt.Errorf("scenario %s has no objectives; a plain script runs with -script", path)
+	}
+	if err := file.Objectives.Validate(); err != nil {
+		return nil, fmt.Errorf("scenario %s, objectives: %w", path, err)
+	}
+	if file.Objectives.WasteTolerance == 0 {
+		file.Objectives.WasteTolerance = DefaultWasteTolerance
+	}
+
+	return &Scenario{Script: script, Objectives: *file.Objectives}, nil
+}
@@ -0,0 +1,105 @@
+package training
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+func TestLoadScenario(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	scenario, err := LoadScenario(write("reactor.toml", `
+name = "Reactor failure"
+
+[[event]]
+day = 1
+hour = 8
+action = "system_status"
+system = "PWR-REACTOR-01"
+status = "failed"
+
+[[event]]
+day = 1
+hour = 9
+action = "stock_loss"
+item = "WATER-PURIF-001"
+percent = 40
+
+[objectives]
+time_limit_hours = 48
+systems = ["PWR-REACTOR-01"]
+
+[[objectives.stock]]
+item = "WATER-PURIF-001"
+days = 30
+`))
+	if err != nil {
+		t.Fatalf("LoadScenario() error = %v", err)
+	}
+	if got := scenario.Script.Events[1]; got.Item != "WATER-PURIF-001" || got.Percent != 40 {
+		t.Errorf("stock_loss event = %+v", got)
+	}
+	if o := scenario.Objectives; o.Count() != 2 || o.TimeLimitHours != 48 || o.WasteTolerance != DefaultWasteTolerance {
+		t.Errorf("objectives = %+v", o)
+	}
+
+	json, err := LoadScenario(write("drill.json", `{"events": [{"day": 2, "action": "alert", "message": "x"}],
+		"objectives": {"time_limit_hours": 6, "systems": ["HVAC-AIR-01"], "waste_tolerance_percent": 10}}`))
+	if err != nil {
+		t.Fatalf("LoadScenario(json) error = %v", err)
+	}
+	if json.Objectives.WasteTolerance != 10 {
+		t.Errorf("LoadScenario(json) waste tolerance = %g, want 10", json.Objectives.WasteTolerance)
+	}
+
+	if _, err := LoadScenario(write("plain.toml", "[[event]]\nday = 1\naction = \"alert\"\nmessage = \"x\"\n")); err == nil {
+		t.Error("LoadScenario() accepted a script without objectives")
+	}
+	_, err = LoadScenario(write("bad.toml", "[[event]]\nday = 1\naction = \"alert\"\nmessage = \"x\"\n[objectives]\ntime_limit_hours = 0\n"))
+	if err == nil || !strings.Contains(err.Error(), "time_limit_hours") {
+		t.Errorf("LoadScenario() error = %v, want time_limit_hours rejected", err)
+	}
+}
+
+func TestScore(t *testing.T) {
+	crisis := time.Date(2077, 10, 23, 8, 0, 0, 0, time.UTC)
+	objectives := Objectives{TimeLimitHours: 48, Systems: []string{"PWR-REACTOR-01"}, WasteTolerance: 5}
+
+	tests := []struct {
+		name  string
+		d     Debrief
+		total int
+	}{
+		{"resolved at once without loss", Debrief{Resolved: true, End: crisis, Population: 100, Lots: 50}, 100},
+		{"resolved at the time limit", Debrief{Resolved: true, End: crisis.Add(48 * time.Hour), Population: 100, Lots: 50}, 80},
+		{"resolved halfway", Debrief{Resolved: true, End: crisis.Add(24 * time.Hour), Population: 100, Lots: 50}, 90},
+		{"unresolved", Debrief{End: crisis.Add(48 * time.Hour), Population: 100, Lots: 50}, 70},
+		{"deaths and waste", Debrief{
+			End:        crisis.Add(48 * time.Hour),
+			Population: 100,
+			Deaths:     make([]*models.Resident, 10),
+			Lots:       100,
+			Spoiled:    10, // Twice the tolerance
+		}, 45},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.d.Crisis = crisis
+			if got := score(&tt.d, objectives).Total(); got != tt.total {
+				t.Errorf("score() = %d, want %d", got, tt.total)
+			}
+		})
+	}
+}
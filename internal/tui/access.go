@@ -0,0 +1,272 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/services/security"
+	"github.com/vtuos/vtuos/internal/tui/components"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// accessLogRows is how many access events the security module lists.
+const accessLogRows = 10
+
+// accessActions are the actions available on the security module's access
+// points.
+var accessActions = components.NewActionBar(
+	components.Action{Key: "g", Label: "Grant"},
+	components.Action{Key: "a", Label: "Log attempt"},
+	components.Action{Key: "x", Label: "Enable/disable"},
+)
+
+// accessMsg carries the vault's access points and the access log under the
+// screen's filters.
+type accessMsg struct {
+	points []*models.AccessPoint
+	events []*models.AccessEvent
+	err    error
+}
+
+// loadAccess loads the access points and the filtered access log.
+func (a *App) loadAccess() tea.Cmd {
+	filter := models.AccessEventFilter{Granted: a.accessResult}
+	if point := a.selectedAccessPoint(); a.accessByPoint && point != nil {
+		filter.AccessPointID = point.ID
+	}
+	return func() tea.Msg {
+		ctx, cancel := a.operation()
+		defer cancel()
+		points, err := a.securitySvc.ListAccessPoints(ctx)
+		if err != nil {
+			return accessMsg{err: err}
+		}
+		events, err := a.securitySvc.ListAccessEvents(ctx, filter, accessLogRows)
+		return accessMsg{points: points, events: events, err: err}
+	}
+}
+
+// selectedAccessPoint returns the access point selected on the security
+// screen, nil if there are none.
+func (a *App) selectedAccessPoint() *models.AccessPoint {
+	if a.accessPointIndex < len(a.accessPoints) {
+		return a.accessPoints[a.accessPointIndex]
+	}
+	return nil
+}
+
+// handleAccessKeys handles the security module's access control keys: point
+// selection, log filters and the grant, attempt and enable actions.
+func (a *App) handleAccessKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	point := a.selectedAccessPoint()
+	switch msg.String() {
+	case "up", "k":
+		if a.accessPointIndex > 0 {
+			a.accessPointIndex--
+			if a.accessByPoint {
+				return a, a.loadAccess()
+			}
+		}
+	case "down", "j":
+		if a.accessPointIndex < len(a.accessPoints)-1 {
+			a.accessPointIndex++
+			if a.accessByPoint {
+				return a, a.loadAccess()
+			}
+		}
+	case "f":
+		// All events, then granted only, then denied only
+		switch {
+		case a.accessResult == nil:
+			granted := true
+			a.accessResult = &granted
+		case *a.accessResult:
+			denied := false
+			a.accessResult = &denied
+		default:
+			a.accessResult = nil
+		}
+		return a, a.loadAccess()
+	case "p":
+		a.accessByPoint = !a.accessByPoint
+		return a, a.loadAccess()
+	case "g", "a", "x":
+		if point == nil || a.denyReadOnly() {
+			return a, nil
+		}
+		action := &quickAction{targetID: point.ID, targetName: point.Code}
+		switch msg.String() {
+		case "g":
+			action.kind = quickActionGrantAccess
+			action.prompt = "Grant " + point.Code + " to registry number [days] [reason]: "
+		case "a":
+			action.kind = quickActionAccessAttempt
+			action.prompt = "Credential presented at " + point.Code + ", registry number: "
+		case "x":
+			action.kind = quickActionToggleAccessPoint
+			action.confirm = true
+			if point.IsActive {
+				action.prompt = "Disable " + point.Code + "? It will admit no one (y/n)"
+			} else {
+				action.prompt = "Enable " + point.Code + "? (y/n)"
+			}
+		}
+		a.quickAction = action
+	}
+	return a, nil
+}
+
+// runAccessAction grants access to the selected point, logs a credential
+// presented there, or enables or disables it.
+func (a *App) runAccessAction(ctx context.Context, action *quickAction, input string) quickActionDoneMsg {
+	if action.kind == quickActionToggleAccessPoint {
+		point := a.selectedAccessPoint()
+		if point == nil || point.ID != action.targetID {
+			return quickActionDoneMsg{module: ModuleSecurity, err: fmt.Errorf("access point %w: %s", repository.ErrNotFound, action.targetName)}
+		}
+		updated, err := a.securitySvc.SetAccessPointActive(ctx, point.ID, !point.IsActive)
+		if err != nil {
+			return quickActionDoneMsg{module: ModuleSecurity, err: err}
+		}
+		if updated.IsActive {
+			return quickActionDoneMsg{module: ModuleSecurity, success: updated.Code + " enabled"}
+		}
+		return quickActionDoneMsg{module: ModuleSecurity, alert: AlertWarning, success: updated.Code + " disabled"}
+	}
+
+	fields := strings.Fields(input)
+	regNum := strings.ToUpper(fields[0])
+	resident, err := a.populationSvc.GetResidentByRegistryNumber(ctx, regNum)
+	if err != nil && !(action.kind == quickActionAccessAttempt && errors.Is(err, repository.ErrNotFound)) {
+		return quickActionDoneMsg{module: ModuleSecurity, err: fmt.Errorf("resident %s: %w", regNum, err)}
+	}
+
+	if action.kind == quickActionAccessAttempt {
+		attempt := security.AccessAttempt{AccessPointID: action.targetID}
+		if resident != nil {
+			attempt.ResidentID = resident.ID
+		}
+		event, err := a.securitySvc.RequestAccess(ctx, attempt)
+		if err != nil {
+			return quickActionDoneMsg{module: ModuleSecurity, err: err}
+		}
+		message := fmt.Sprintf("%s at %s: %s (%s)", regNum, action.targetName, event.Outcome(), event.Reason)
+		if !event.Granted {
+			return quickActionDoneMsg{module: ModuleSecurity, alert: AlertWarning, success: message}
+		}
+		return quickActionDoneMsg{module: ModuleSecurity, success: message}
+	}
+
+	grant := security.GrantInput{ResidentID: resident.ID, AccessPointID: action.targetID}
+	rest := fields[1:]
+	if len(rest) > 0 {
+		if days, err := strconv.Atoi(rest[0]); err == nil {
+			if days < 1 {
+				return quickActionDoneMsg{module: ModuleSecurity, err: fmt.Errorf("%w: days must be at least 1", repository.ErrValidation)}
+			}
+			expires := a.clock.Now().UTC().Truncate(time.Second).AddDate(0, 0, days)
+			grant.ExpiresAt = &expires
+			rest = rest[1:]
+		}
+	}
+	grant.Reason = strings.Join(rest, " ")
+	if a.operator != nil {
+		grant.GrantedBy = &a.operator.ID
+	}
+	created, err := a.securitySvc.GrantAccess(ctx, grant)
+	if err != nil {
+		return quickActionDoneMsg{module: ModuleSecurity, err: err}
+	}
+	until := "until revoked"
+	if created.ExpiresAt != nil {
+		until = "until " + util.FormatDateTime(*created.ExpiresAt)
+	}
+	return quickActionDoneMsg{module: ModuleSecurity, success: fmt.Sprintf("%s granted %s %s",
+		resident.RegistryNumber, action.targetName, until)}
+}
+
+// renderAccess renders the access control sections of the security module:
+// the vault's doors and airlocks and the access log under its filters.
+func (a *App) renderAccess() string {
+	var b strings.Builder
+	b.WriteString(a.theme.Subtitle.Render("ACCESS POINTS"))
+	b.WriteString("\n")
+
+	if len(a.accessPoints) == 0 {
+		b.WriteString(a.theme.Muted.Render("  No access points registered; add them with vtuos access add-point"))
+		b.WriteString("\n")
+	}
+	narrow := GetBreakpoint(a.width) == BreakpointNarrow
+	for i, p := range a.accessPoints {
+		status := "ACTIVE"
+		if !p.IsActive {
+			status = "DISABLED"
+		}
+		line := fmt.Sprintf("%-16s %-24s %-7s CLR:%-2d %s", Truncate(p.Code, 16), Truncate(p.Name, 24), p.Kind, p.MinClearance, status)
+		if narrow {
+			line = fmt.Sprintf("%-16s CLR:%-2d %s", Truncate(p.Code, 16), p.MinClearance, status)
+		}
+		line = Truncate(line, a.width-4)
+
+		switch {
+		case i == a.accessPointIndex:
+			b.WriteString(a.theme.Selected.Render("> " + line))
+		case !p.IsActive:
+			b.WriteString("  " + a.theme.Warning.Render(line))
+		default:
+			b.WriteString("  " + a.theme.Base.Render(line))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("  ")
+	b.WriteString(a.renderActionBar(accessActions))
+	b.WriteString("\n\n")
+
+	filter := "all"
+	if a.accessResult != nil {
+		filter = "granted"
+		if !*a.accessResult {
+			filter = "denied"
+		}
+	}
+	if point := a.selectedAccessPoint(); a.accessByPoint && point != nil {
+		filter += " at " + point.Code
+	}
+	b.WriteString(a.theme.Subtitle.Render("ACCESS LOG"))
+	b.WriteString(a.theme.Muted.Render("  " + filter))
+	b.WriteString("\n")
+
+	if len(a.accessEvents) == 0 {
+		b.WriteString(a.theme.Muted.Render("  No access events"))
+		b.WriteString("\n")
+	}
+	for _, e := range a.accessEvents {
+		who := e.RegistryNumber
+		if who == "" {
+			who = "unknown"
+		}
+		drill := ""
+		if e.Simulated {
+			drill = "DRILL"
+		}
+		line := Truncate(fmt.Sprintf("%s  %-16s %-11s %-7s %-18s %s", util.FormatDateTime(e.OccurredAt),
+			Truncate(e.PointCode, 16), who, e.Outcome(), e.Reason, drill), a.width-4)
+		if e.Granted {
+			b.WriteString("  " + a.theme.Base.Render(line))
+		} else {
+			b.WriteString("  " + a.theme.Warning.Render(line))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(a.theme.Muted.Render("  " + a.theme.Locale.T("↑/↓ select point  f granted/denied  p this point only  r reload")))
+	b.WriteString("\n\n")
+	return b.String()
+}
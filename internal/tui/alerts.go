@@ -0,0 +1,339 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/services/governance"
+	"github.com/vtuos/vtuos/internal/tui/components"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// alertQueueRows is how many alerts the alert queue lists.
+const alertQueueRows = 12
+
+// alertQueueActions are the actions available on the alert queue.
+var alertQueueActions = components.NewActionBar(
+	components.Action{Key: "a", Label: "Acknowledge"},
+	components.Action{Key: "v", Label: "Resolve"},
+	components.Action{Key: "m", Label: "Mine/all"},
+	components.Action{Key: "n", Label: "Add rule"},
+	components.Action{Key: "x", Label: "Remove rule"},
+	components.Action{Key: "s", Label: "Sign on"},
+)
+
+// alertQueueMsg carries the alerts not yet resolved, those addressed to the
+// signed on operator if only theirs are shown, and the routing rules.
+type alertQueueMsg struct {
+	alerts []*models.Alert
+	routes []*models.AlertRoute
+	err    error
+}
+
+// openAlertQueue switches to the alert queue.
+func (a *App) openAlertQueue() tea.Cmd {
+	a.currentModule = ModuleAlerts
+	return a.loadAlertQueue()
+}
+
+// loadAlertQueue loads the vault's alerts not yet resolved, or only those
+// addressed to the signed on operator, and its routing rules.
+func (a *App) loadAlertQueue() tea.Cmd {
+	operator := a.operator
+	mine := a.alertQueueMine
+	return func() tea.Msg {
+		ctx, cancel := a.operation()
+		defer cancel()
+		var alerts []*models.Alert
+		var err error
+		if mine && operator != nil {
+			alerts, err = a.governanceSvc.AlertsAddressedTo(ctx, operator.ID)
+		} else {
+			alerts, err = a.governanceSvc.ListAlerts(ctx, false)
+		}
+		if err != nil {
+			return alertQueueMsg{err: err}
+		}
+		routes, err := a.governanceSvc.ListAlertRoutes(ctx)
+		return alertQueueMsg{alerts: alerts, routes: routes, err: err}
+	}
+}
+
+// handleAlertQueue stores the loaded alerts, keeping the selection in range.
+func (a *App) handleAlertQueue(msg alertQueueMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		a.AddError("Failed to load alerts", msg.err)
+		return a, nil
+	}
+	a.alertQueue = msg.alerts
+	a.alertRoutes = msg.routes
+	a.alertQueueIndex = max(min(a.alertQueueIndex, len(a.alertQueue)-1), 0)
+	return a, nil
+}
+
+// handleAlertQueueKeys handles key presses on the alert queue: selecting an
+// alert, acknowledging or resolving it for the signed on operator, showing
+// only the operator's alerts, and adding or removing routing rules.
+func (a *App) handleAlertQueueKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		if a.alertQueueIndex > 0 {
+			a.alertQueueIndex--
+		}
+		return a, nil
+	case "down", "j":
+		if a.alertQueueIndex < len(a.alertQueue)-1 {
+			a.alertQueueIndex++
+		}
+		return a, nil
+	case "r":
+		return a, a.loadAlertQueue()
+	case "s":
+		a.quickAction = &quickAction{
+			kind:     quickActionSignOn,
+			targetID: string(ModuleAlerts),
+			prompt:   "Operator registry number: ",
+		}
+		return a, nil
+	case "m":
+		if a.operator == nil {
+			a.AddAlert(AlertWarning, "Sign on (s) to show your alerts")
+			return a, nil
+		}
+		a.alertQueueMine = !a.alertQueueMine
+		return a, a.loadAlertQueue()
+	}
+	if a.denyReadOnly() {
+		return a, nil
+	}
+
+	switch msg.String() {
+	case "a", "v":
+		if a.operator == nil {
+			a.AddAlert(AlertWarning, "Sign on (s) to acknowledge or resolve alerts")
+			return a, nil
+		}
+		if len(a.alertQueue) == 0 {
+			a.AddAlert(AlertInfo, "No alerts to deal with")
+			return a, nil
+		}
+		alert := a.alertQueue[a.alertQueueIndex]
+		kind, verb := quickActionAcknowledgeAlert, "Acknowledge"
+		if msg.String() == "v" {
+			kind, verb = quickActionResolveAlert, "Resolve"
+		}
+		a.quickAction = &quickAction{
+			kind:       kind,
+			prompt:     fmt.Sprintf("%s %q: notes, or - for none: ", verb, Truncate(alert.Message, 40)),
+			targetID:   alert.ID,
+			targetName: alert.Message,
+		}
+	case "n":
+		a.quickAction = &quickAction{
+			kind:   quickActionAddAlertRoute,
+			prompt: "SOURCE or * | WARNING or CRITICAL | DEPARTMENT or REGISTRY [| ESCALATE_TO [HOURS]]: ",
+		}
+	case "x":
+		if len(a.alertRoutes) == 0 {
+			a.AddAlert(AlertInfo, "No routing rules to remove")
+			return a, nil
+		}
+		a.quickAction = &quickAction{
+			kind:   quickActionRemoveAlertRoute,
+			prompt: fmt.Sprintf("Remove routing rule # (1-%d): ", len(a.alertRoutes)),
+		}
+	}
+	return a, nil
+}
+
+// runAlertQueueAction acknowledges or resolves an alert for the operator,
+// or adds or removes a routing rule.
+func (a *App) runAlertQueueAction(ctx context.Context, action *quickAction, input string) quickActionDoneMsg {
+	switch action.kind {
+	case quickActionAcknowledgeAlert, quickActionResolveAlert:
+		if a.operator == nil {
+			return quickActionDoneMsg{module: ModuleAlerts, err: fmt.Errorf("%w: no operator signed on", repository.ErrValidation)}
+		}
+		if input == "-" {
+			input = ""
+		}
+		update := governance.AlertActionInput{AlertID: action.targetID, OperatorID: a.operator.ID, Notes: input}
+		if action.kind == quickActionResolveAlert {
+			_, err := a.governanceSvc.ResolveAlert(ctx, update)
+			return quickActionDoneMsg{module: ModuleAlerts, success: "Resolved: " + action.targetName, err: err}
+		}
+		_, err := a.governanceSvc.AcknowledgeAlert(ctx, update)
+		return quickActionDoneMsg{module: ModuleAlerts, success: "Acknowledged: " + action.targetName, err: err}
+
+	case quickActionRemoveAlertRoute:
+		n, err := strconv.Atoi(input)
+		if err != nil || n < 1 || n > len(a.alertRoutes) {
+			return quickActionDoneMsg{module: ModuleAlerts, err: fmt.Errorf("%w: no routing rule %q", repository.ErrValidation, input)}
+		}
+		route := a.alertRoutes[n-1]
+		err = a.governanceSvc.RemoveAlertRoute(ctx, route.ID)
+		return quickActionDoneMsg{module: ModuleAlerts, success: fmt.Sprintf("Removed rule routing %s to %s", route.Source, route.Recipient()), err: err}
+	}
+
+	route, err := a.parseAlertRoute(ctx, input)
+	if err != nil {
+		return quickActionDoneMsg{module: ModuleAlerts, err: err}
+	}
+	added, err := a.governanceSvc.AddAlertRoute(ctx, route)
+	if err != nil {
+		return quickActionDoneMsg{module: ModuleAlerts, err: err}
+	}
+	return quickActionDoneMsg{module: ModuleAlerts, success: fmt.Sprintf("Alerts of %s from %s up routed to %s",
+		added.Source, added.MinSeverity, added.Recipient())}
+}
+
+// parseAlertRoute reads a routing rule prompt, e.g.
+// "shortage check | warning | food_production | administration 2".
+func (a *App) parseAlertRoute(ctx context.Context, input string) (governance.AlertRouteInput, error) {
+	var route governance.AlertRouteInput
+	parts := strings.Split(input, "|")
+	if len(parts) < 3 || len(parts) > 4 {
+		return route, fmt.Errorf("%w: expected a source, a severity and a department or operator", repository.ErrValidation)
+	}
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+
+	route.Source = parts[0]
+	severity, err := models.ParseAlertSeverity(parts[1])
+	if err != nil {
+		return route, fmt.Errorf("%w: %w", repository.ErrValidation, err)
+	}
+	route.MinSeverity = severity
+	if department, err := models.ParseDepartment(parts[2]); err == nil {
+		route.Department = department
+	} else {
+		registry := strings.ToUpper(parts[2])
+		operator, err := a.populationSvc.GetResidentByRegistryNumber(ctx, registry)
+		if err != nil {
+			return route, fmt.Errorf("%q is neither a department nor an operator: %w", parts[2], err)
+		}
+		route.OperatorID = operator.ID
+	}
+
+	if len(parts) == 4 {
+		fields := strings.Fields(parts[3])
+		if len(fields) == 0 || len(fields) > 2 {
+			return route, fmt.Errorf("%w: expected an escalation department and optionally hours", repository.ErrValidation)
+		}
+		if route.EscalateTo, err = models.ParseDepartment(fields[0]); err != nil {
+			return route, fmt.Errorf("%w: %w", repository.ErrValidation, err)
+		}
+		if len(fields) == 2 {
+			hours, err := strconv.Atoi(fields[1])
+			if err != nil || hours < 1 {
+				return route, fmt.Errorf("%w: invalid escalation hours %q", repository.ErrValidation, fields[1])
+			}
+			route.EscalateAfterHours = hours
+		}
+	}
+	return route, nil
+}
+
+// renderAlertQueue renders the alert queue: the alerts not yet resolved,
+// open ones first, with who they are routed to and whether they were
+// escalated, then the routing rules, numbered for removal.
+func (a *App) renderAlertQueue() string {
+	var b strings.Builder
+	b.WriteString(a.theme.Title.Render("═══ ALERT QUEUE ═══"))
+	b.WriteString("\n\n")
+
+	open := 0
+	for _, alert := range a.alertQueue {
+		if alert.Status == models.AlertOpen {
+			open++
+		}
+	}
+	scope := "every department"
+	if a.alertQueueMine && a.operator != nil {
+		scope = "addressed to " + a.operator.RegistryNumber
+	}
+	b.WriteString(a.theme.Muted.Render(fmt.Sprintf("  %d unresolved, %d unacknowledged, %s; escalated after %d vault hours unless a rule says otherwise",
+		len(a.alertQueue), open, scope, a.config.Alerts.EscalateAfterHours)))
+	b.WriteString("\n\n")
+
+	width := a.width - 4
+	header := fmt.Sprintf("  %-16s %-8s %-22s %-15s %-12s %s", "RAISED", "SEVERITY", "SOURCE", "ROUTED TO", "STATUS", "MESSAGE")
+	b.WriteString(a.theme.TableHeader.Render(Truncate(header, a.width-2)))
+	b.WriteString("\n")
+	if len(a.alertQueue) == 0 {
+		b.WriteString(a.theme.Muted.Render("  No unresolved alerts"))
+		b.WriteString("\n")
+	}
+	start := max(0, min(a.alertQueueIndex-alertQueueRows/2, len(a.alertQueue)-alertQueueRows))
+	for i := start; i < len(a.alertQueue) && i < start+alertQueueRows; i++ {
+		alert := a.alertQueue[i]
+		status := string(alert.Status)
+		if alert.EscalatedAt != nil {
+			status += "↑"
+		}
+		recipient := alert.Recipient()
+		if recipient == "" {
+			recipient = "(deleted)"
+		}
+		line := fmt.Sprintf("%-16s %-8s %-22s %-15s %-12s %s", util.FormatDateTime(alert.RaisedAt), alert.Severity,
+			Truncate(alert.Source, 22), recipient, status, alert.Message)
+		line = Truncate(line, width)
+		switch {
+		case i == a.alertQueueIndex:
+			b.WriteString(a.theme.Selected.Render("> " + line))
+		case alert.Status == models.AlertOpen && alert.Severity == models.AlertSeverityCritical:
+			b.WriteString("  " + a.theme.Error.Render(line))
+		case alert.Status == models.AlertOpen:
+			b.WriteString("  " + a.theme.Warning.Render(line))
+		default:
+			b.WriteString("  " + a.theme.Base.Render(line))
+		}
+		b.WriteString("\n")
+	}
+	if len(a.alertQueue) > 0 {
+		alert := a.alertQueue[a.alertQueueIndex]
+		if alert.AcknowledgedAt != nil {
+			note := fmt.Sprintf("  Acknowledged %s by %s", util.FormatDateTime(*alert.AcknowledgedAt), alert.AcknowledgedRegistry)
+			if alert.AcknowledgmentNotes != "" {
+				note += ": " + alert.AcknowledgmentNotes
+			}
+			b.WriteString(a.theme.Muted.Render(Truncate(note, width)))
+			b.WriteString("\n")
+		}
+		if alert.EscalatedAt != nil {
+			b.WriteString(a.theme.Muted.Render(fmt.Sprintf("  Escalated %s", util.FormatDateTime(*alert.EscalatedAt))))
+			b.WriteString("\n")
+		}
+	}
+	b.WriteString("\n")
+
+	b.WriteString(a.theme.Subtitle.Render("ROUTING RULES"))
+	b.WriteString("\n")
+	if len(a.alertRoutes) == 0 {
+		b.WriteString(a.theme.Muted.Render("  No rules: every alert goes to ADMINISTRATION"))
+		b.WriteString("\n")
+	}
+	for i, route := range a.alertRoutes {
+		escalation := "default"
+		if route.EscalateAfterHours > 0 {
+			escalation = fmt.Sprintf("%dh", route.EscalateAfterHours)
+		}
+		line := fmt.Sprintf("%2d. %-22s %-8s -> %-15s escalate to %s after %s", i+1, Truncate(route.Source, 22),
+			route.MinSeverity, route.Recipient(), route.EscalationDepartment(), escalation)
+		b.WriteString("  " + a.theme.Base.Render(Truncate(line, width)))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n  ")
+	b.WriteString(a.renderActionBar(alertQueueActions))
+	b.WriteString("\n")
+	b.WriteString(a.theme.Muted.Render("  " + a.theme.Locale.T("↑/↓ select  r reload  Esc back")))
+	return b.String()
+}
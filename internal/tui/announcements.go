@@ -0,0 +1,241 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/services/governance"
+	"github.com/vtuos/vtuos/internal/tui/components"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// announcementRows is how many announcements the governance screen's inbox
+// lists.
+const announcementRows = 6
+
+// announcementActions are the actions available on the governance screen's
+// inbox.
+var announcementActions = components.NewActionBar(
+	components.Action{Key: "b", Label: "Broadcast"},
+	components.Action{Key: "n", Label: "Dept notice"},
+	components.Action{Key: "a", Label: "Acknowledge"},
+	components.Action{Key: "s", Label: "Sign on"},
+)
+
+// announcementsMsg carries the announcements posted now: the operator's
+// inbox with a signed on operator, every one posted otherwise.
+type announcementsMsg struct {
+	announcements []*models.Announcement
+	inbox         bool
+	err           error
+}
+
+// loadAnnouncements loads the signed on operator's inbox, or without an
+// operator every announcement posted now.
+func (a *App) loadAnnouncements() tea.Cmd {
+	operator := a.operator
+	return func() tea.Msg {
+		ctx, cancel := a.operation()
+		defer cancel()
+		if operator != nil {
+			inbox, err := a.governanceSvc.Inbox(ctx, operator.ID)
+			return announcementsMsg{announcements: inbox, inbox: true, err: err}
+		}
+		posted, err := a.governanceSvc.ListAnnouncements(ctx, false)
+		return announcementsMsg{announcements: posted, err: err}
+	}
+}
+
+// handleAnnouncementKeys opens the posting prompts of the inbox,
+// acknowledges the operator's earliest unacknowledged announcement, or
+// signs an operator on to read their inbox.
+func (a *App) handleAnnouncementKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.String() == "s" {
+		a.quickAction = &quickAction{
+			kind:     quickActionSignOn,
+			targetID: string(ModuleGovernance),
+			prompt:   "Operator registry number: ",
+		}
+		return a, nil
+	}
+	if a.denyReadOnly() {
+		return a, nil
+	}
+	switch msg.String() {
+	case "b", "n":
+		kind := models.AnnouncementBroadcast
+		if msg.String() == "n" {
+			kind = models.AnnouncementNotice
+		}
+		a.quickAction = &quickAction{
+			kind:       quickActionPostAnnouncement,
+			prompt:     fmt.Sprintf("%s to ALL | DEPARTMENT NAME | HOUSEHOLD H-NNNN, then TITLE [| body]: ", kind),
+			targetName: string(kind),
+		}
+	case "a":
+		if a.operator == nil {
+			a.AddAlert(AlertWarning, "Sign on (s) to acknowledge announcements")
+			return a, nil
+		}
+		pending := a.pendingAnnouncement()
+		if pending == nil {
+			a.AddAlert(AlertInfo, "Inbox is clear")
+			return a, nil
+		}
+		a.quickAction = &quickAction{
+			kind:       quickActionAcknowledge,
+			prompt:     fmt.Sprintf("Acknowledge %q? (y/n) ", pending.Title),
+			targetID:   pending.ID,
+			targetName: pending.Title,
+			confirm:    true,
+		}
+	}
+	return a, nil
+}
+
+// pendingAnnouncement returns the earliest issued announcement of the
+// operator's inbox still to acknowledge, nil if none.
+func (a *App) pendingAnnouncement() *models.Announcement {
+	if !a.announcementInbox {
+		return nil
+	}
+	var pending *models.Announcement
+	for _, ann := range a.announcements {
+		if !ann.Acknowledged && (pending == nil || ann.IssuedAt.Before(pending.IssuedAt)) {
+			pending = ann
+		}
+	}
+	return pending
+}
+
+// runAnnouncementAction posts a broadcast or department notice, or
+// acknowledges an announcement for the operator.
+func (a *App) runAnnouncementAction(ctx context.Context, action *quickAction, input string) quickActionDoneMsg {
+	if action.kind == quickActionAcknowledge {
+		if a.operator == nil {
+			return quickActionDoneMsg{module: ModuleGovernance, err: fmt.Errorf("%w: no operator signed on", repository.ErrValidation)}
+		}
+		ann, err := a.governanceSvc.AcknowledgeAnnouncement(ctx, governance.AcknowledgeInput{
+			AnnouncementID: action.targetID,
+			ResidentID:     a.operator.ID,
+		})
+		if err != nil {
+			return quickActionDoneMsg{module: ModuleGovernance, err: err}
+		}
+		return quickActionDoneMsg{module: ModuleGovernance, success: fmt.Sprintf("Acknowledged %q, %d of %d acknowledged",
+			ann.Title, ann.Acknowledgments, ann.Recipients)}
+	}
+
+	post, err := a.parseAnnouncement(ctx, input)
+	if err != nil {
+		return quickActionDoneMsg{module: ModuleGovernance, err: err}
+	}
+	post.Kind = models.AnnouncementKind(action.targetName)
+	if a.operator != nil {
+		post.IssuedBy = a.operator.ID
+	}
+	ann, err := a.governanceSvc.PostAnnouncement(ctx, post)
+	if err != nil {
+		return quickActionDoneMsg{module: ModuleGovernance, err: err}
+	}
+	return quickActionDoneMsg{module: ModuleGovernance, success: fmt.Sprintf("%s posted to %s: %s",
+		ann.Kind, ann.AudienceLabel(), ann.Title)}
+}
+
+// parseAnnouncement reads the audience, title and body of an announcement
+// prompt, e.g. "department medical Checkups moved | Sector B clinic".
+func (a *App) parseAnnouncement(ctx context.Context, input string) (governance.AnnouncementInput, error) {
+	var post governance.AnnouncementInput
+	fields := strings.Fields(input)
+	if len(fields) < 2 {
+		return post, fmt.Errorf("%w: expected an audience and a title", repository.ErrValidation)
+	}
+	audience, err := models.ParseAnnouncementAudience(fields[0])
+	if err != nil {
+		return post, fmt.Errorf("%w: %w", repository.ErrValidation, err)
+	}
+	post.Audience = audience
+	rest := fields[1:]
+	switch audience {
+	case models.AudienceDepartment:
+		if post.Department, err = models.ParseDepartment(rest[0]); err != nil {
+			return post, fmt.Errorf("%w: %w", repository.ErrValidation, err)
+		}
+		rest = rest[1:]
+	case models.AudienceHousehold:
+		designation := strings.ToUpper(rest[0])
+		household, err := a.populationSvc.GetHouseholdByDesignation(ctx, designation)
+		if err != nil {
+			return post, fmt.Errorf("household %s: %w", designation, err)
+		}
+		post.HouseholdID = household.ID
+		rest = rest[1:]
+	}
+
+	title, body, _ := strings.Cut(strings.Join(rest, " "), "|")
+	post.Title = strings.TrimSpace(title)
+	post.Body = strings.TrimSpace(body)
+	return post, nil
+}
+
+// renderAnnouncements renders the governance screen's inbox: the signed on
+// operator's announcements, those to acknowledge marked NEW, or without an
+// operator every announcement posted, each with its acknowledgments.
+func (a *App) renderAnnouncements() string {
+	var b strings.Builder
+	if a.announcementInbox && a.operator != nil {
+		b.WriteString(a.theme.Subtitle.Render("INBOX"))
+		unread := 0
+		for _, ann := range a.announcements {
+			if !ann.Acknowledged {
+				unread++
+			}
+		}
+		b.WriteString(a.theme.Muted.Render(fmt.Sprintf("  %s, %d to acknowledge", a.operator.RegistryNumber, unread)))
+	} else {
+		b.WriteString(a.theme.Subtitle.Render("ANNOUNCEMENTS"))
+		b.WriteString(a.theme.Muted.Render(fmt.Sprintf("  %d posted", len(a.announcements))))
+	}
+	b.WriteString("\n")
+
+	if len(a.announcements) == 0 {
+		b.WriteString(a.theme.Muted.Render("  No announcements posted"))
+		b.WriteString("\n")
+	}
+	narrow := GetBreakpoint(a.width) == BreakpointNarrow
+	for i, ann := range a.announcements {
+		if i == announcementRows {
+			b.WriteString(a.theme.Muted.Render(fmt.Sprintf("  ... %d more, see `vtuos announce list`", len(a.announcements)-i)))
+			b.WriteString("\n")
+			break
+		}
+		mark := "   "
+		if a.announcementInbox && !ann.Acknowledged {
+			mark = "NEW"
+		}
+		line := fmt.Sprintf("%s %-10s %-9s %-15s %-40s ack %d/%d", mark, util.FormatDay(ann.IssuedAt),
+			ann.Kind, Truncate(ann.AudienceLabel(), 15), Truncate(ann.Title, 40), ann.Acknowledgments, ann.Recipients)
+		if narrow {
+			line = fmt.Sprintf("%s %-9s %s", mark, ann.Kind, ann.Title)
+		}
+		line = Truncate(line, a.width-4)
+		switch {
+		case a.announcementInbox && !ann.Acknowledged:
+			b.WriteString("  " + a.theme.Warning.Render(line))
+		case ann.Kind == models.AnnouncementSystem:
+			b.WriteString("  " + a.theme.Muted.Render(line))
+		default:
+			b.WriteString("  " + a.theme.Base.Render(line))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("  ")
+	b.WriteString(a.renderActionBar(announcementActions))
+	b.WriteString("\n")
+	return b.String()
+}
@@ -2,17 +2,33 @@ package tui
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"os"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/vtuos/vtuos/internal/config"
 	"github.com/vtuos/vtuos/internal/database"
+	"github.com/vtuos/vtuos/internal/dualauth"
+	"github.com/vtuos/vtuos/internal/events"
+	"github.com/vtuos/vtuos/internal/journal"
 	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/services/facilities"
+	"github.com/vtuos/vtuos/internal/services/governance"
+	"github.com/vtuos/vtuos/internal/services/inspections"
+	"github.com/vtuos/vtuos/internal/services/medical"
 	"github.com/vtuos/vtuos/internal/services/population"
 	"github.com/vtuos/vtuos/internal/services/resources"
+	"github.com/vtuos/vtuos/internal/services/security"
+	"github.com/vtuos/vtuos/internal/simulation"
+	"github.com/vtuos/vtuos/internal/training"
+	facviews "github.com/vtuos/vtuos/internal/tui/views/facilities"
 	popviews "github.com/vtuos/vtuos/internal/tui/views/population"
 	resviews "github.com/vtuos/vtuos/internal/tui/views/resources"
 	"github.com/vtuos/vtuos/internal/util"
@@ -41,23 +57,122 @@ const (
 	ModuleGovernance Module = "governance"
 	ModuleSettings   Module = "settings"
 	ModuleHelp       Module = "help"
+	ModuleDigest     Module = "digest"
+	ModuleTasks      Module = "tasks"
+	ModuleCare       Module = "care"
+	ModuleAptitude   Module = "aptitude"
+	ModuleVaults     Module = "vaults"
+
+	ModuleDiagnostics Module = "diagnostics"
+	ModuleConsumption Module = "consumption"
+
+	ModuleDependencies Module = "dependencies"
+	ModuleWorkOrders   Module = "workorders"
+	ModuleMaintenance  Module = "maintenance"
+
+	ModuleSessions Module = "sessions"
+	ModuleAlerts   Module = "alerts"
 )
 
 // App is the main Bubble Tea application model.
 type App struct {
 	// Dependencies
 	db     *database.DB
-	config *config.Config
-	clock  *util.VaultClock
+	config     *config.Config
+	configPath string
+	clock      *util.VaultClock
+	readOnly   bool // Kiosk terminal; every change is disabled
+
+	// Operations started from the terminal run under ctx, which shutdown
+	// cancels; inFlight counts those still running
+	ctx      context.Context
+	inFlight atomic.Int32
 
 	// Services
 	populationSvc *population.Service
 	resourceSvc   *resources.Service
+	inspectionSvc *inspections.Service
+	facilitySvc   *facilities.Service
+	governanceSvc *governance.Service
+	medicalSvc    *medical.Service
+	securitySvc   *security.Service
+
+	// Managed vaults; the services above are those of the vault the
+	// terminal is administering
+	vaults           []*vaultServices
+	vaultIndex       int   // Vault being administered
+	vaultCursor      int   // Selected row of the vault switcher
+	vaultPopulations []int // Active population of each vault
+
+	// Simulation
+	engine     *simulation.Engine
+	scheduler  *simulation.Scheduler
+	simulating bool // A simulation tick is in flight
+	taskIndex  int  // Selected row of the scheduled tasks screen
+
+	// Training exercise in progress, nil outside training mode; it records
+	// the commands run from the terminal
+	exercise *training.Exercise
+
+	// Diagnostics screen: the last system check, whether the query timings
+	// show, and the first statement they list
+	diagnostics       *systemDiagnostics
+	showQueryTimings  bool
+	diagnosticsScroll int
+
+	// Consumption analytics screen: the report shown and the index of its
+	// period in consumptionPeriods
+	consumption       *resources.ConsumptionReport
+	consumptionPeriod int
+
+	// Terminal session in progress, with the modules opened, the changes
+	// made and when it was last saved; and the session activity screen's
+	// report and the index of its period in sessionPeriods
+	session          *models.TerminalSession
+	sessionModules   []string
+	sessionMutations atomic.Int32
+	sessionSavedAt   time.Time
+	sessionReport    *governance.SessionReport
+	sessionPeriod    int
+
+	// Alert queue: the alerts not yet resolved, the vault's routing rules,
+	// the index of the selected alert and whether only those addressed to
+	// the signed on operator are shown
+	alertQueue      []*models.Alert
+	alertRoutes     []*models.AlertRoute
+	alertQueueIndex int
+	alertQueueMine  bool
+
+	// Dependency view of the facilities module: the systems' dependencies
+	// and the index of the selected system
+	dependencies    *facilities.DependencyMap
+	dependencyIndex int
+
+	// Work order screen of the facilities module: the open work orders, the
+	// index of the selected one and its form while it is open
+	workOrders     []*facilities.WorkOrder
+	workOrderIndex int
+	workOrderForm  *facviews.WorkOrderForm
+
+	// Maintenance analytics screen of the facilities module: the analytics
+	// shown and the index of their period in maintenancePeriods
+	maintenance       *models.MaintenanceAnalytics
+	maintenancePeriod int
+
+	// Census preset menu: whether it is open, the vault's presets and the
+	// index of the selected one
+	showPresets bool
+	presets     []*models.CensusPreset
+	presetIndex int
+
+	// Census filter panel, while it is open
+	censusFilter *popviews.CensusFilterForm
 
 	// Views
-	censusView    *popviews.CensusView
-	residentForm  *popviews.ResidentForm
-	inventoryView *resviews.InventoryView
+	censusView     *popviews.CensusView
+	householdsView *popviews.HouseholdsView
+	residentForm   *popviews.ResidentForm
+	inventoryView  *resviews.InventoryView
 
 	// UI state
 	theme       *Theme
@@ -67,22 +182,147 @@ type App struct {
 	ready       bool
 	quitting    bool
 	showConfirm bool
+	spinning    bool    // Loading spinners are animating
+	splitRatio  float64 // Share of a split screen's width the list takes
+
+	// Settings screen: the settings last written to config, the section
+	// with the focus, and the dashboard panel and the setting selected
+	savedSettings      settingsValues
+	settingsFocus      settingsSection
+	settingsPanelIndex int
+	settingsFieldIndex int
+
+	// Suspend state (Ctrl+Z)
+	program        *tea.Program
+	suspended      bool
+	clockWasPaused bool // clock state to restore on resume
 
 	// Current view
 	currentModule  Module
 	previousModule Module
 	showDetail     bool // Show detail view instead of list
+	showHouseholds bool // Households tab of the population module
+	showIntakes    bool // Intake tab of the population module
+	showAssets     bool // Asset tab of the resources module
 	showForm       bool // Show add/edit form
 	searchMode     bool // Search input mode
 	searchInput    string
+	quickAction    *quickAction    // Active list row action
+	undo           *undoHistory    // Recent changes that can be undone
+	badge          *models.IDBadge // ID badge shown in the resident detail view
+	palette        *commandPalette // Open command palette
+	openResident   string          // Registry number to open once the census loads
 
 	// Alerts
 	alerts     []Alert
 	alertIndex int
 	alertTick  int
+	announcer  announcer // What screen reader mode last announced
 
 	// Population count (updated periodically)
 	population int
+
+	// Ration class reviews awaiting approval
+	pendingReviews int
+
+	// Dependents awaiting a guardian, and the selected row of the care queue
+	pendingCare int
+	careQueue   []population.PendingCare
+	careIndex   int
+
+	// Residents of testing age without a vocation, and the selected row of
+	// the aptitude screen
+	aptitudeCandidates []population.AptitudeCandidate
+	aptitudeIndex      int
+
+	// Residents in vocational training, and the selected row of the labor
+	// screen
+	apprentices     []population.Apprentice
+	apprenticeIndex int
+
+	// Ration policies of every class, latest effective first, and the
+	// selected class of the governance screen
+	rationPolicies   []*models.RationPolicy
+	rationClassIndex int
+
+	// Vault alert state with its recent changes, and the operator signed on
+	// to this terminal to use modules the state restricts
+	lockdown        *models.LockdownChange
+	lockdownHistory []*models.LockdownChange
+	operator        *models.Resident
+
+	// Access points with the selected one, and the access log with its
+	// filters, for the security screen
+	accessPoints     []*models.AccessPoint
+	accessPointIndex int
+	accessEvents     []*models.AccessEvent
+	accessResult     *bool // Granted or denied events only, nil for both
+	accessByPoint    bool  // Log limited to the selected point
+
+	// Weapons issued from the armory and still held, for the security screen
+	armoryIssues []*models.ArmoryIssue
+
+	// Announcements for the governance screen: the signed on operator's
+	// inbox, or every announcement posted when no operator is signed on
+	announcements     []*models.Announcement
+	announcementInbox bool
+
+	// Intakes awaiting medical clearance with the selected one, and the
+	// intake wizard while it is open
+	intakes      []*models.Intake
+	intakeIndex  int
+	intakeWizard *popviews.IntakeWizard
+
+	// Registered assets under the category filter with the selected one and
+	// its custody history, for the resources module's asset tab
+	assets        []*models.Asset
+	assetIndex    int
+	assetHistory  []*models.AssetCustody
+	assetCategory models.AssetCategory // Empty for every category
+
+	// The item master's category or item form while it is open
+	catalogForm catalogEditor
+
+	// Inspections due soon and overdue
+	upcomingInspections []*models.Inspection
+	overdueInspections  int
+
+	// Utility grid balances and system status for the dashboard
+	grid          []facilities.GridBalance
+	systemsStatus []*facilities.CategoryStatus
+
+	// Resource runways, headcount trend, efficiency history and happiness
+	// index charted on the dashboard, with the items short of their
+	// minimum stock
+	runways         []*resources.CategoryRunway
+	shortages       []*models.Shortage
+	populationTrend []int
+	efficiencyTrend []float64
+	moraleTrend     []*models.MoraleSnapshot
+
+	// Long-range planning report for the governance screen
+	planningReport *governance.PlanningReport
+
+	// Population forecast against carrying capacity for the governance screen
+	capacityForecast *governance.CapacityForecast
+
+	// Residents flagged for radiation exposure for the medical screen
+	radiationFlags []*models.RadiationDose
+
+	// Genetic diversity and its trend for the medical screen
+	geneticHealth *population.GeneticHealthReport
+
+	// Nutrients drawn by each ration class for the medical screen
+	nutrition []*models.ClassConsumption
+
+	// Daily digest and the vault day it covers
+	digest    *governance.Digest
+	digestDay time.Time
+
+	// Daily vault report of the digest's day, shown in place of the digest
+	report       *governance.DailyReport
+	showReport   bool
+	reportScroll int
 }
 
 // Alert represents a system alert.
@@ -104,35 +344,126 @@ const (
 // tickMsg is sent periodically to update the UI.
 type tickMsg time.Time
 
-// New creates a new App instance.
-func New(db *database.DB, cfg *config.Config, clock *util.VaultClock) *App {
-	// Create population service
-	popSvc := population.NewService(db.DB, cfg.Vault.Number)
-
-	// Create resource service
-	resSvc := resources.NewService(db.DB)
+// New creates a new App instance. cfgPath is where settings changes are saved.
+func New(db *database.DB, cfg *config.Config, cfgPath string, clock *util.VaultClock) *App {
+	// Create the services of each managed vault; the terminal opens on the
+	// primary vault
+	var vaults []*vaultServices
+	for _, vault := range cfg.ManagedVaults() {
+		vaults = append(vaults, newVaultServices(db, cfg, clock, vault))
+	}
+	primary := vaults[0]
+	popSvc := primary.population
+	resSvc := primary.resources
 
 	// Create census view
 	censusView := popviews.NewCensusView(popSvc)
 	censusView.SetVaultTime(clock.Now())
 
+	// Create inspection service
+	inspSvc := inspections.NewService(db.DB)
+	inspSvc.SetClock(clock)
+
 	// Create inventory view
 	inventoryView := resviews.NewInventoryView(resSvc)
 	inventoryView.SetVaultTime(clock.Now())
 
-	return &App{
-		db:            db,
-		config:        cfg,
-		clock:         clock,
-		populationSvc: popSvc,
-		resourceSvc:   resSvc,
-		censusView:    censusView,
-		inventoryView: inventoryView,
-		theme:         NewTheme(cfg.Display.ColorScheme),
-		keys:          DefaultKeyMap(),
-		currentModule: ModuleDashboard,
-		alerts:        []Alert{},
+	// Register simulation hooks. Reservation expiry and status transitions
+	// are bookkeeping and run even without random events; they, like the
+	// census snapshot and the scenario script, cover the whole database and
+	// run once, while each vault has its own jobs and failures. Every hook
+	// writes, so a read-only terminal has none.
+	facSvc := primary.facilities
+	govSvc := primary.governance
+	engine := simulation.NewEngine(clock)
+	scheduler := simulation.NewScheduler(clock.Now())
+	scheduler.SetPause(govSvc.NonEssentialPaused)
+	for _, v := range vaults {
+		v.schedule(scheduler, cfg, len(vaults))
+	}
+	scheduler.Add(censusSnapshotJob(db, cfg, popSvc))
+	scheduler.Add(databaseMaintenanceJob(db))
+	readOnly := cfg.Database.ReadOnly
+	startupAlerts := []Alert{}
+	var exercise *training.Exercise
+	if !readOnly {
+		engine.Register(resSvc.ReservationExpiry())
+		engine.Register(popSvc.TransitionScheduler())
+		engine.Register(scheduler)
+		for _, v := range vaults {
+			n := v.vault.Number
+			engine.Register(simulation.InVault(n, v.population.TrainingAccrual()))
+			engine.Register(simulation.InVault(n, v.population.ActivitySessions(time.Now().UnixNano())))
+			engine.Register(simulation.InVault(n, v.security.DrillAccess(time.Now().UnixNano())))
+			engine.Register(simulation.InVault(n, v.facilities.LoadShedder()))
+			engine.Register(simulation.InVault(n, v.facilities.RuntimeAccrual()))
+			engine.Register(simulation.InVault(n, v.facilities.EnvironmentMonitor(time.Now().UnixNano())))
+			engine.Register(simulation.InVault(n, v.medical.EnvironmentHealth()))
+			engine.Register(simulation.InVault(n, v.governance.AlertEscalation(cfg.Alerts.EscalateAfter())))
+		}
+		if cfg.Simulation.AutoEvents {
+			for _, v := range vaults {
+				n := v.vault.Number
+				engine.Register(simulation.InVault(n, v.facilities.FailureModel(cfg.Simulation.EventFrequency.Multiplier(), time.Now().UnixNano())))
+				engine.Register(simulation.InVault(n, v.security.IncidentModel(cfg.Simulation.EventFrequency.Multiplier(), time.Now().UnixNano())))
+			}
+		}
+		if cfg.Simulation.Script != "" {
+			if runner, ex, err := scenarioScript(cfg, db, clock, facSvc, popSvc, resSvc); err != nil {
+				startupAlerts = append(startupAlerts, Alert{Level: AlertCritical, Message: "Scenario script not loaded: " + err.Error(), Time: time.Now()})
+			} else {
+				engine.Register(runner)
+				if ex != nil {
+					engine.Register(ex)
+					exercise = ex
+					begins, deadline := ex.Crisis()
+					startupAlerts = append(startupAlerts, Alert{Level: AlertWarning, Message: fmt.Sprintf("Training exercise %s: crisis at %s, to be resolved by %s", ex.Name(), util.FormatDateTime(begins), util.FormatDateTime(deadline)), Time: time.Now()})
+				}
+			}
+		}
 	}
+	censusView.SetReadOnly(readOnly)
+	setScreenReader(cfg.Display.ScreenReader)
+
+	app := &App{
+		db:             db,
+		ctx:            context.Background(),
+		config:         cfg,
+		configPath:     cfgPath,
+		clock:          clock,
+		readOnly:       readOnly,
+		populationSvc:  popSvc,
+		resourceSvc:    resSvc,
+		inspectionSvc:  inspSvc,
+		facilitySvc:    facSvc,
+		governanceSvc:  govSvc,
+		medicalSvc:     primary.medical,
+		securitySvc:    primary.security,
+		vaults:         vaults,
+		engine:         engine,
+		scheduler:      scheduler,
+		exercise:       exercise,
+		censusView:     censusView,
+		householdsView: popviews.NewHouseholdsView(popSvc),
+		inventoryView:  inventoryView,
+		theme:          newDisplayTheme(&cfg.Display),
+		savedSettings:  currentSettings(cfg),
+		keys:           DefaultKeyMap(),
+		currentModule:  ModuleDashboard,
+		splitRatio:     defaultSplitRatio,
+		undo:           newUndoHistory(),
+		alerts:         startupAlerts,
+	}
+	app.setViewsPlain()
+	return app
+}
+
+// setViewsPlain shows the list views in screen reader mode if the theme is
+// plain.
+func (a *App) setViewsPlain() {
+	a.censusView.SetPlain(a.theme.Plain)
+	a.householdsView.SetPlain(a.theme.Plain)
+	a.inventoryView.SetPlain(a.theme.Plain)
 }
 
 // Init implements tea.Model.
@@ -141,6 +472,11 @@ func (a *App) Init() tea.Cmd {
 		tea.EnterAltScreen,
 		tickCmd(),
 		a.loadPopulation(),
+		a.loadInspections(),
+		a.loadSystems(),
+		a.loadTrends(),
+		a.loadLockdown(),
+		a.startSession(""),
 	)
 }
 
@@ -154,36 +490,132 @@ func tickCmd() tea.Cmd {
 // loadPopulation loads the population count from the database.
 func (a *App) loadPopulation() tea.Cmd {
 	return func() tea.Msg {
+		ctx, cancel := a.operation()
+		defer cancel()
+
 		var count int
-		err := a.db.QueryRow(
-			"SELECT COUNT(*) FROM residents WHERE status = 'ACTIVE'",
+		err := a.db.QueryRowContext(ctx,
+			"SELECT COUNT(*) FROM residents WHERE status = 'ACTIVE' AND vault_id = ?",
+			a.currentVault().Number,
 		).Scan(&count)
 		if err != nil {
 			// Table might not exist yet
 			return populationMsg{count: 0}
 		}
-		return populationMsg{count: count}
+		reviews, err := a.populationSvc.ListPendingRationReviews(ctx)
+		if err != nil {
+			return populationMsg{count: count}
+		}
+		pendingCare, err := a.populationSvc.CountPendingCare(ctx)
+		if err != nil {
+			return populationMsg{count: count, pendingReviews: len(reviews)}
+		}
+		return populationMsg{count: count, pendingReviews: len(reviews), pendingCare: pendingCare}
 	}
 }
 
 type populationMsg struct {
-	count int
+	count          int
+	pendingReviews int
+	pendingCare    int
+}
+
+// inspectionWindowDays is how far ahead the facilities screen lists inspections.
+const inspectionWindowDays = 14
+
+// loadInspections loads upcoming inspections and the overdue count.
+func (a *App) loadInspections() tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := a.operation()
+		defer cancel()
+		upcoming, err := a.inspectionSvc.ListUpcoming(ctx, inspectionWindowDays)
+		if err != nil {
+			return inspectionsMsg{err: err}
+		}
+		overdue, err := a.inspectionSvc.OverdueReport(ctx)
+		if err != nil {
+			return inspectionsMsg{err: err}
+		}
+		return inspectionsMsg{upcoming: upcoming, overdue: len(overdue)}
+	}
 }
 
-type censusLoadedMsg struct {
-	err error
+type inspectionsMsg struct {
+	upcoming []*models.Inspection
+	overdue  int
+	err      error
+}
+
+// dashboardCategories are the non-grid categories on the dashboard systems panel.
+var dashboardCategories = []models.FacilityCategory{
+	models.FacilityCategoryHVAC,
+	models.FacilityCategorySecurity,
+}
+
+// loadSystems loads grid balances and category status for the dashboard.
+func (a *App) loadSystems() tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := a.operation()
+		defer cancel()
+		grid, err := a.facilitySvc.GridStatus(ctx)
+		if err != nil {
+			return systemsMsg{err: err}
+		}
+		var categories []*facilities.CategoryStatus
+		for _, category := range dashboardCategories {
+			status, err := a.facilitySvc.CategoryStatus(ctx, category)
+			if err != nil {
+				return systemsMsg{err: err}
+			}
+			categories = append(categories, status)
+		}
+		return systemsMsg{grid: grid, categories: categories}
+	}
 }
 
-type inventoryLoadedMsg struct {
-	err error
+type systemsMsg struct {
+	grid       []facilities.GridBalance
+	categories []*facilities.CategoryStatus
+	err        error
+}
+
+// loadPlanningReport builds the governance planning report.
+func (a *App) loadPlanningReport() tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := a.reportOperation()
+		defer cancel()
+		report, err := a.governanceSvc.PlanningReport(ctx,
+			a.clock.Now(), governance.DefaultPlanningYears)
+		return planningReportMsg{report: report, err: err}
+	}
+}
+
+type planningReportMsg struct {
+	report *governance.PlanningReport
+	err    error
 }
 
 // Update implements tea.Model.
 func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	model, cmd := a.update(msg)
+	if a.theme.Plain {
+		a.announce()
+	}
+	return model, cmd
+}
+
+// update handles a message.
+func (a *App) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		return a.handleKeyPress(msg)
 
+	case suspendMsg:
+		return a.handleSuspend()
+
+	case resumeMsg:
+		return a.handleResume(msg)
+
 	case tea.WindowSizeMsg:
 		a.width = msg.Width
 		a.height = msg.Height
@@ -196,27 +628,333 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Update vault time in views
 		a.censusView.SetVaultTime(a.clock.Now())
 		a.inventoryView.SetVaultTime(a.clock.Now())
-		// Rotate alerts every 3 ticks
+		// Rotate alerts every 3 ticks, unless a screen reader would
+		// read each one out
 		a.alertTick++
-		if a.alertTick >= 3 && len(a.alerts) > 1 {
+		if a.alertTick >= 3 && len(a.alerts) > 1 && !a.theme.Plain {
 			a.alertTick = 0
 			a.alertIndex = (a.alertIndex + 1) % len(a.alerts)
 		}
-		return a, tickCmd()
+		if !a.simulating && a.engine.Due() {
+			a.simulating = true
+			return a, tea.Batch(tickCmd(), a.sessionSaveDue(), a.runSimulation())
+		}
+		return a, tea.Batch(tickCmd(), a.sessionSaveDue())
+
+	case simulationMsg:
+		return a.handleSimulation(msg)
+
+	case censusLoadedMsg, householdsLoadedMsg, inventoryLoadedMsg, loadDueMsg, spinnerMsg:
+		return a.handleLoad(msg)
 
 	case populationMsg:
 		a.population = msg.count
+		if msg.pendingReviews > 0 && msg.pendingReviews != a.pendingReviews {
+			a.AddAlert(AlertInfo, fmt.Sprintf("%d ration class review(s) awaiting approval", msg.pendingReviews))
+		}
+		a.pendingReviews = msg.pendingReviews
+		if msg.pendingCare > 0 && msg.pendingCare != a.pendingCare {
+			a.AddAlert(AlertWarning, fmt.Sprintf("%d dependent(s) awaiting a guardian", msg.pendingCare))
+		}
+		a.pendingCare = msg.pendingCare
+		return a, nil
+
+	case careMsg:
+		if msg.err != nil {
+			a.AddError("Failed to load care assignments", msg.err)
+			return a, nil
+		}
+		a.careQueue = msg.pending
+		if a.careIndex >= len(a.careQueue) {
+			a.careIndex = max(len(a.careQueue)-1, 0)
+		}
+		return a, nil
+
+	case lockdownMsg:
+		return a.handleLockdown(msg)
+
+	case operatorMsg:
+		return a.handleOperator(msg)
+
+	case diagnosticsMsg:
+		if msg.err != nil {
+			a.AddError("System check failed", msg.err)
+			return a, nil
+		}
+		a.diagnostics = msg.diagnostics
+		return a, nil
+
+	case aptitudeMsg:
+		if msg.err != nil {
+			a.AddError("Failed to load aptitude candidates", msg.err)
+			return a, nil
+		}
+		a.aptitudeCandidates = msg.candidates
+		if a.aptitudeIndex >= len(a.aptitudeCandidates) {
+			a.aptitudeIndex = max(len(a.aptitudeCandidates)-1, 0)
+		}
+		return a, nil
+
+	case trainingMsg:
+		if msg.err != nil {
+			a.AddError("Failed to load apprentices", msg.err)
+			return a, nil
+		}
+		a.apprentices = msg.apprentices
+		if a.apprenticeIndex >= len(a.apprentices) {
+			a.apprenticeIndex = max(len(a.apprentices)-1, 0)
+		}
+		return a, nil
+
+	case intakeMsg:
+		if msg.err != nil {
+			a.AddError("Failed to load intakes", msg.err)
+			return a, nil
+		}
+		a.intakes = msg.intakes
+		if a.intakeIndex >= len(a.intakes) {
+			a.intakeIndex = max(len(a.intakes)-1, 0)
+		}
+		return a, nil
+
+	case intakeAdmittedMsg:
+		return a.handleIntakeAdmitted(msg)
+
+	case catalogItemMsg:
+		return a.handleCatalogItem(msg)
+
+	case catalogSavedMsg:
+		return a.handleCatalogSaved(msg)
+
+	case assetsMsg:
+		if msg.err != nil {
+			a.AddError("Failed to load assets", msg.err)
+			return a, nil
+		}
+		a.assets = msg.assets
+		a.assetHistory = msg.history
+		if a.assetIndex >= len(a.assets) {
+			a.assetIndex = max(len(a.assets)-1, 0)
+		}
 		return a, nil
 
-	case censusLoadedMsg:
+	case assetHistoryMsg:
 		if msg.err != nil {
-			a.AddAlert(AlertWarning, "Failed to load census: "+msg.err.Error())
+			a.AddError("Failed to load custody history", msg.err)
+			return a, nil
+		}
+		if asset := a.selectedAsset(); asset != nil && asset.ID == msg.assetID {
+			a.assetHistory = msg.history
+		}
+		return a, nil
+
+	case accessMsg:
+		if msg.err != nil {
+			a.AddError("Failed to load access control", msg.err)
+			return a, nil
+		}
+		a.accessPoints = msg.points
+		a.accessEvents = msg.events
+		if a.accessPointIndex >= len(a.accessPoints) {
+			a.accessPointIndex = max(len(a.accessPoints)-1, 0)
 		}
 		return a, nil
 
-	case inventoryLoadedMsg:
+	case armoryMsg:
 		if msg.err != nil {
-			a.AddAlert(AlertWarning, "Failed to load inventory: "+msg.err.Error())
+			a.AddError("Failed to load the armory", msg.err)
+			return a, nil
+		}
+		a.armoryIssues = msg.issues
+		return a, nil
+
+	case announcementsMsg:
+		if msg.err != nil {
+			a.AddError("Failed to load announcements", msg.err)
+			return a, nil
+		}
+		a.announcements, a.announcementInbox = msg.announcements, msg.inbox
+		return a, nil
+
+	case rationPoliciesMsg:
+		if msg.err != nil {
+			a.AddError("Failed to load ration policies", msg.err)
+			return a, nil
+		}
+		a.rationPolicies = msg.policies
+		return a, nil
+
+	case vaultsMsg:
+		if msg.err != nil {
+			a.AddError("Failed to load managed vaults", msg.err)
+			return a, nil
+		}
+		a.vaultPopulations = msg.populations
+		return a, nil
+
+	case inspectionsMsg:
+		if msg.err != nil {
+			return a, nil
+		}
+		a.upcomingInspections = msg.upcoming
+		if msg.overdue > 0 && msg.overdue != a.overdueInspections {
+			a.AddAlert(AlertWarning, fmt.Sprintf("%d inspection(s) overdue", msg.overdue))
+		}
+		a.overdueInspections = msg.overdue
+		return a, nil
+
+	case systemsMsg:
+		if msg.err != nil {
+			a.AddError("Failed to load facility systems", msg.err)
+			return a, nil
+		}
+		for _, b := range msg.grid {
+			switch previous := a.gridState(b.Grid); {
+			case b.State == facilities.GridStateDeficit && previous != facilities.GridStateDeficit:
+				a.AddAlert(AlertCritical, fmt.Sprintf("%s grid deficit: %s", b.Grid, b))
+			case b.State == facilities.GridStateShedding && previous != facilities.GridStateShedding:
+				a.AddAlert(AlertWarning, fmt.Sprintf("%s grid shedding %d load(s), %.0f %s", b.Grid, b.Shed, b.ShedLoad, b.Unit))
+			}
+		}
+		a.grid = msg.grid
+		a.systemsStatus = msg.categories
+		return a, nil
+
+	case trendsMsg:
+		return a.handleTrends(msg)
+
+	case consumptionMsg:
+		return a.handleConsumption(msg)
+
+	case sessionMsg:
+		return a.handleSession(msg)
+
+	case sessionReportMsg:
+		return a.handleSessionReport(msg)
+
+	case alertQueueMsg:
+		return a.handleAlertQueue(msg)
+
+	case dependenciesMsg:
+		return a.handleDependencies(msg)
+
+	case workOrdersMsg:
+		return a.handleWorkOrders(msg)
+
+	case workOrderSavedMsg:
+		return a.handleWorkOrderSaved(msg)
+
+	case maintenanceAnalyticsMsg:
+		return a.handleMaintenanceAnalytics(msg)
+
+	case planningReportMsg:
+		if msg.err != nil {
+			a.AddError("Failed to build planning report", msg.err)
+			return a, nil
+		}
+		a.planningReport = msg.report
+		return a, nil
+
+	case radiationMsg:
+		if msg.err != nil {
+			a.AddError("Failed to load radiation exposure", msg.err)
+			return a, nil
+		}
+		a.radiationFlags = msg.doses
+		return a, nil
+
+	case geneticHealthMsg:
+		if msg.err != nil {
+			a.AddError("Failed to assess genetic health", msg.err)
+			return a, nil
+		}
+		a.geneticHealth = msg.report
+		return a, nil
+
+	case nutritionMsg:
+		if msg.err != nil {
+			a.AddError("Failed to load nutrition", msg.err)
+			return a, nil
+		}
+		a.nutrition = msg.classes
+		return a, nil
+
+	case capacityForecastMsg:
+		if msg.err != nil {
+			a.AddError("Failed to forecast population", msg.err)
+			return a, nil
+		}
+		a.capacityForecast = msg.forecast
+		return a, nil
+
+	case badgeMsg:
+		if msg.err != nil {
+			a.AddError("Failed to build ID badge", msg.err)
+			return a, nil
+		}
+		a.badge = msg.badge
+		return a, nil
+
+	case qualityTestsMsg:
+		if msg.err != nil {
+			a.AddError("Failed to load quality tests", msg.err)
+			return a, nil
+		}
+		// The selection may have moved on while the split screen loaded them
+		if stock := a.inventoryView.SelectedStock(); stock != nil && stock.ID == msg.stockID {
+			a.inventoryView.SetQualityTests(msg.stockID, msg.tests)
+		}
+		return a, nil
+
+	case censusPresetsMsg:
+		return a.handleCensusPresets(msg)
+
+	case censusTagFilterMsg:
+		return a.handleCensusTagFilter(msg)
+
+	case relationshipsMsg:
+		if msg.err != nil {
+			a.AddError("Failed to load relationships", msg.err)
+			return a, nil
+		}
+		// The selection may have moved on while the split screen loaded them
+		if resident := a.censusView.SelectedResident(); resident != nil && resident.ID == msg.residentID {
+			a.censusView.SetRelationships(msg.residentID, msg.relationships, msg.tags)
+		}
+		return a, nil
+
+	case badgePrintedMsg:
+		if msg.err != nil {
+			a.AddError("Failed to print ID badge", msg.err)
+		} else {
+			a.AddAlert(AlertInfo, "ID badge written to "+msg.path)
+		}
+		return a, nil
+
+	case taskRunMsg:
+		return a.handleTaskRun(msg)
+
+	case digestMsg:
+		if msg.err != nil {
+			a.AddError("Failed to build daily digest", msg.err)
+			return a, nil
+		}
+		a.digest = msg.digest
+		return a, nil
+
+	case dailyReportMsg:
+		if msg.err != nil {
+			a.AddError("Failed to build daily report", msg.err)
+			return a, nil
+		}
+		a.report = msg.report
+		return a, nil
+
+	case reportWrittenMsg:
+		if msg.err != nil {
+			a.AddError("Failed to write daily report", msg.err)
+		} else {
+			a.AddAlert(AlertInfo, "Daily report written to "+msg.path)
 		}
 		return a, nil
 
@@ -224,18 +962,91 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.showForm = false
 		a.residentForm = nil
 		if msg.err != nil {
-			a.AddAlert(AlertWarning, "Failed to save resident: "+msg.err.Error())
+			a.AddError("Failed to save resident", msg.err)
 		} else {
 			a.AddAlert(AlertInfo, "Resident saved successfully")
+			if msg.undo != nil {
+				a.undo.record(msg.undo)
+			}
 		}
 		return a, tea.Batch(a.loadCensus(), a.loadPopulation())
 
+	case settingMsg:
+		if msg.err != nil {
+			a.AddError("Invalid setting", msg.err)
+		} else {
+			a.applySettings(msg.values)
+		}
+		return a, nil
+
+	case settingsSavedMsg:
+		if msg.err != nil {
+			a.AddError("Failed to save settings", msg.err)
+		} else {
+			a.savedSettings = msg.values
+			a.AddAlert(AlertInfo, "Settings saved")
+		}
+		return a, nil
+
+	case undoDoneMsg:
+		return a.handleUndoDone(msg)
+
+	case quickActionDoneMsg:
+		if errors.Is(msg.err, dualauth.ErrRequired) && msg.action != nil && msg.action.countersign == nil {
+			a.requestCountersign(msg.action, msg.err)
+			return a, nil
+		}
+		if msg.err != nil {
+			a.AddError("Action failed", msg.err)
+		} else {
+			a.AddAlert(msg.alert, msg.success)
+			if msg.undo != nil {
+				a.undo.record(msg.undo)
+			}
+		}
+		if msg.module == ModuleResources {
+			if a.showAssets {
+				return a, a.loadAssets()
+			}
+			if stock := a.inventoryView.SelectedStock(); stock != nil && a.inventoryView.HasQualityTests(stock.ID) {
+				return a, tea.Batch(a.loadInventory(), a.loadQualityTests(stock.ID))
+			}
+			return a, a.loadInventory()
+		}
+		if msg.module == ModuleCare {
+			return a, tea.Batch(a.loadCare(), a.loadCensus(), a.loadHouseholds(), a.loadPopulation())
+		}
+		if msg.module == ModuleAptitude {
+			return a, tea.Batch(a.loadAptitude(), a.loadCensus())
+		}
+		if msg.module == ModuleLabor {
+			return a, tea.Batch(a.loadTraining(), a.loadCensus())
+		}
+		if msg.module == ModuleGovernance {
+			return a, tea.Batch(a.loadRationPolicies(), a.loadCapacityForecast(), a.loadAnnouncements())
+		}
+		if msg.module == ModuleSecurity {
+			return a, tea.Batch(a.loadAccess(), a.loadArmory())
+		}
+		if msg.module == ModuleAlerts {
+			return a, a.loadAlertQueue()
+		}
+		cmds := []tea.Cmd{a.loadCensus(), a.loadHouseholds(), a.loadIntakes(), a.loadPopulation()}
+		if a.showPresets {
+			cmds = append(cmds, a.loadCensusPresets())
+		}
+		if resident := a.censusView.SelectedResident(); resident != nil && a.censusView.HasRelationships(resident.ID) {
+			cmds = append(cmds, a.loadRelationships(resident))
+		}
+		return a, tea.Batch(cmds...)
+
 	case deathRegisteredMsg:
 		a.showDetail = false
 		if msg.err != nil {
-			a.AddAlert(AlertWarning, "Failed to register death: "+msg.err.Error())
+			a.AddError("Failed to register death", msg.err)
 		} else {
 			a.AddAlert(AlertInfo, "Death registered")
+			a.undo.record(msg.undo)
 		}
 		return a, tea.Batch(a.loadCensus(), a.loadPopulation())
 	}
@@ -246,15 +1057,21 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 // updateViewDimensions recalculates visible rows for all views based on terminal height.
 func (a *App) updateViewDimensions() {
 	contentH := ContentHeight(a.height, chromeLines)
-	// Census table: subtract 4 lines for title, search info, separator, help line
-	censusRows := contentH - 6
+	if a.splitPane() {
+		contentH -= 2 // Pane borders
+	}
+	// Census and household tables: subtract lines for tab strip, title, search
+	// info, separator, help line, action bar
+	censusRows := contentH - 8
 	if censusRows < 5 {
 		censusRows = 5
 	}
 	a.censusView.SetVisibleRows(censusRows)
+	a.householdsView.SetVisibleRows(censusRows)
 
-	// Inventory table: subtract 4 lines for title, filter info, separator, help line
-	invRows := contentH - 6
+	// Inventory table: subtract lines for tab strip, title, filter info,
+	// separator, help line, action bar
+	invRows := contentH - 8
 	if invRows < 5 {
 		invRows = 5
 	}
@@ -263,6 +1080,11 @@ func (a *App) updateViewDimensions() {
 
 // handleKeyPress processes key press events.
 func (a *App) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// Suspend works from any mode, like a shell job
+	if msg.String() == "ctrl+z" {
+		return a.handleSuspend()
+	}
+
 	// Handle quit confirmation first (modal takes priority)
 	if a.showConfirm {
 		switch msg.String() {
@@ -280,12 +1102,38 @@ func (a *App) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	if a.currentModule == ModulePopulation && a.showForm {
 		return a.handleFormKeys(msg)
 	}
+	if a.currentModule == ModulePopulation && a.intakeWizard != nil {
+		return a.handleIntakeWizardKeys(msg)
+	}
+	if a.currentModule == ModulePopulation && a.censusFilter != nil {
+		return a.handleCensusFilterKeys(msg)
+	}
+	if a.currentModule == ModuleResources && a.catalogForm != nil {
+		return a.handleCatalogFormKeys(msg)
+	}
+	if a.currentModule == ModuleWorkOrders && a.workOrderForm != nil {
+		return a.handleWorkOrderFormKeys(msg)
+	}
 
 	// Handle search mode BEFORE global keys - search needs text input
 	if a.currentModule == ModulePopulation && a.searchMode {
 		return a.handleSearchKeys(msg)
 	}
 
+	// Handle quick action prompts BEFORE global keys - prompts need text input
+	if a.quickAction != nil {
+		return a.handleQuickActionKeys(msg)
+	}
+
+	// Handle the command palette BEFORE global keys - it needs text input
+	if a.palette != nil {
+		return a.handlePaletteKeys(msg)
+	}
+	if msg.String() == ":" {
+		a.palette = &commandPalette{}
+		return a, nil
+	}
+
 	// Global key bindings (only when not in input mode)
 	if a.keys.IsQuit(msg) {
 		a.showConfirm = true
@@ -295,51 +1143,175 @@ func (a *App) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	// Function key navigation (always available)
 	if a.keys.IsFunctionKey(msg) {
 		module := a.keys.GetFunctionKeyModule(msg)
+		if a.currentModule == ModuleSettings && module != "settings" {
+			a.revertSettings()
+		}
 		switch module {
 		case "quit":
 			a.showConfirm = true
 		case "help":
 			a.previousModule = a.currentModule
 			a.currentModule = ModuleHelp
-		case "dashboard":
-			a.currentModule = ModuleDashboard
-			a.showDetail = false
-		case "population":
-			a.currentModule = ModulePopulation
-			a.showDetail = false
-			return a, a.loadCensus()
-		case "resources":
-			a.currentModule = ModuleResources
-			a.showDetail = false
-			return a, a.loadInventory()
-		case "facilities":
-			a.currentModule = ModuleFacilities
-		case "labor":
-			a.currentModule = ModuleLabor
-		case "medical":
-			a.currentModule = ModuleMedical
-		case "security":
-			a.currentModule = ModuleSecurity
-		case "governance":
-			a.currentModule = ModuleGovernance
+		default:
+			return a, a.gotoModule(Module(module))
 		}
 		return a, nil
 	}
 
+	// Undo and redo (only when not in input mode)
+	if a.keys.Undo.Matches(msg) {
+		return a, a.undoLast()
+	}
+	if a.keys.Redo.Matches(msg) {
+		return a, a.redoLast()
+	}
+
 	// Back navigation (only when not in input mode)
 	if a.keys.Back.Matches(msg) {
+		if a.showingBadge() {
+			a.badge = nil
+			return a, nil
+		}
 		if a.showDetail {
 			a.showDetail = false
 			return a, nil
 		}
+		if a.currentModule == ModulePopulation && a.showPresets {
+			a.showPresets = false
+			return a, nil
+		}
+		if a.currentModule == ModuleDigest && a.showReport {
+			a.showReport = false
+			return a, nil
+		}
+		if a.currentModule == ModuleDependencies || a.currentModule == ModuleWorkOrders || a.currentModule == ModuleMaintenance {
+			a.currentModule = ModuleFacilities
+			return a, nil
+		}
 		if a.currentModule == ModuleHelp && a.previousModule != "" {
 			a.currentModule = a.previousModule
 			a.previousModule = ""
 		}
+		if a.currentModule == ModuleDigest || a.currentModule == ModuleTasks || a.currentModule == ModuleCare ||
+			a.currentModule == ModuleAptitude || a.currentModule == ModuleVaults || a.currentModule == ModuleDiagnostics ||
+			a.currentModule == ModuleConsumption || a.currentModule == ModuleSessions || a.currentModule == ModuleAlerts {
+			a.currentModule = ModuleDashboard
+		}
+		if a.currentModule == ModuleSettings {
+			a.revertSettings()
+		}
 		return a, nil
 	}
 
 	// Module-specific key handling
+	if a.currentModule == ModuleDashboard && msg.String() == "d" {
+		return a, a.gotoModule(ModuleDigest)
+	}
+
+	if a.currentModule == ModuleDashboard && msg.String() == "t" {
+		return a, a.gotoModule(ModuleTasks)
+	}
+
+	if a.currentModule == ModuleDashboard && msg.String() == "c" {
+		return a, a.gotoModule(ModuleCare)
+	}
+
+	if a.currentModule == ModuleDashboard && msg.String() == "g" {
+		return a, a.gotoModule(ModuleAptitude)
+	}
+
+	if a.currentModule == ModuleDashboard && msg.String() == "v" {
+		return a, a.gotoModule(ModuleVaults)
+	}
+
+	if a.currentModule == ModuleDashboard && msg.String() == "x" {
+		return a, a.gotoModule(ModuleDiagnostics)
+	}
+
+	if a.currentModule == ModuleDashboard && msg.String() == "u" {
+		return a, a.gotoModule(ModuleConsumption)
+	}
+
+	if a.currentModule == ModuleDashboard && msg.String() == "o" {
+		return a, a.gotoModule(ModuleSessions)
+	}
+
+	if a.currentModule == ModuleDashboard && msg.String() == "a" {
+		return a, a.gotoModule(ModuleAlerts)
+	}
+
+	if a.currentModule == ModuleFacilities && msg.String() == "d" {
+		return a, a.gotoModule(ModuleDependencies)
+	}
+
+	if a.currentModule == ModuleFacilities && msg.String() == "w" {
+		return a, a.gotoModule(ModuleWorkOrders)
+	}
+
+	if a.currentModule == ModuleFacilities && msg.String() == "m" {
+		return a, a.gotoModule(ModuleMaintenance)
+	}
+
+	if a.currentModule == ModuleSecurity {
+		return a.handleSecurityKeys(msg)
+	}
+
+	if a.currentModule == ModuleTasks {
+		return a.handleTaskKeys(msg)
+	}
+
+	if a.currentModule == ModuleCare {
+		return a.handleCareKeys(msg)
+	}
+
+	if a.currentModule == ModuleAptitude {
+		return a.handleAptitudeKeys(msg)
+	}
+
+	if a.currentModule == ModuleVaults {
+		return a.handleVaultKeys(msg)
+	}
+
+	if a.currentModule == ModuleDiagnostics {
+		return a.handleDiagnosticsKeys(msg)
+	}
+
+	if a.currentModule == ModuleConsumption {
+		return a.handleConsumptionKeys(msg)
+	}
+
+	if a.currentModule == ModuleSessions {
+		return a.handleSessionKeys(msg)
+	}
+
+	if a.currentModule == ModuleAlerts {
+		return a.handleAlertQueueKeys(msg)
+	}
+
+	if a.currentModule == ModuleDependencies {
+		return a.handleDependencyKeys(msg)
+	}
+
+	if a.currentModule == ModuleWorkOrders {
+		return a.handleWorkOrderKeys(msg)
+	}
+
+	if a.currentModule == ModuleMaintenance {
+		return a.handleMaintenanceKeys(msg)
+	}
+
+	if a.currentModule == ModuleLabor {
+		return a.handleLaborKeys(msg)
+	}
+
+	if a.currentModule == ModuleGovernance {
+		return a.handleGovernanceKeys(msg)
+	}
+
+	if a.currentModule == ModuleDigest {
+		return a.handleDigestKeys(msg)
+	}
+
 	if a.currentModule == ModulePopulation {
 		return a.handlePopulationKeys(msg)
 	}
@@ -348,21 +1320,42 @@ func (a *App) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return a.handleResourceKeys(msg)
 	}
 
+	if a.currentModule == ModuleSettings {
+		return a.handleSettingsKeys(msg)
+	}
+
 	return a, nil
 }
 
 // handlePopulationKeys handles key presses in the population module.
 // Note: form and search modes are handled in handleKeyPress before this is called
 func (a *App) handlePopulationKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if a.showPresets {
+		return a.handleCensusPresetKeys(msg)
+	}
+	if a.splitView() && a.handleSplitKeys(msg, a.censusView.SelectedResident() != nil) {
+		return a, a.followSelection()
+	}
 	if a.showDetail {
 		// In detail view
 		switch msg.String() {
 		case "esc":
 			a.showDetail = false
+		case "i":
+			// Toggle the ID badge
+			if a.showingBadge() {
+				a.badge = nil
+			} else if resident := a.censusView.SelectedResident(); resident != nil {
+				return a, a.loadBadge(resident)
+			}
+		case "p":
+			if a.showingBadge() {
+				return a, a.printBadge()
+			}
 		case "e":
 			// Edit resident
 			resident := a.censusView.SelectedResident()
-			if resident != nil {
+			if resident != nil && !a.denyReadOnly() {
 				a.residentForm = popviews.NewResidentForm(popviews.FormModeEdit)
 				a.residentForm.SetResident(resident)
 				a.showForm = true
@@ -371,37 +1364,104 @@ func (a *App) handlePopulationKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		case "d":
 			// Register death - show confirmation
 			resident := a.censusView.SelectedResident()
-			if resident != nil && resident.IsAlive() {
+			if resident != nil && resident.IsAlive() && !a.denyReadOnly() {
 				return a, a.registerDeath(resident)
 			}
 		}
 		return a, nil
 	}
 
+	// A split census moves focus with Tab, so Shift+Tab switches tabs too
+	if a.keys.Tab.Matches(msg) {
+		return a, a.switchPopulationTab(1)
+	}
+	if a.keys.ShiftTab.Matches(msg) {
+		return a, a.switchPopulationTab(-1)
+	}
+	if a.showIntakes {
+		return a.handleIntakeKeys(msg)
+	}
+	if a.showHouseholds {
+		return a.handleHouseholdKeys(msg)
+	}
+
 	// In list view
 	switch msg.String() {
 	case "up", "k":
 		a.censusView.MoveUp()
+		return a, a.followSelection()
 	case "down", "j":
 		a.censusView.MoveDown()
+		return a, a.followSelection()
 	case "enter":
-		if a.censusView.SelectedResident() != nil {
+		if resident := a.censusView.SelectedResident(); resident != nil {
 			a.showDetail = true
+			a.badge = nil
+			return a, a.loadRelationships(resident)
 		}
 	case "pgup":
 		a.censusView.PrevPage()
-		return a, a.loadCensus()
+		return a, a.pageCensus()
 	case "pgdown":
 		a.censusView.NextPage()
-		return a, a.loadCensus()
+		return a, a.pageCensus()
+	case " ":
+		a.censusView.ToggleMark()
 	case "a":
-		// Add new resident
-		a.residentForm = popviews.NewResidentForm(popviews.FormModeAdd)
-		a.showForm = true
+		a.censusView.MarkAll()
+	case "A":
+		a.addResident("")
 	case "/", "s":
 		// Enter search mode
 		a.searchMode = true
 		a.searchInput = ""
+	case "o":
+		a.censusView.CycleSort()
+		return a, a.loadCensus()
+	case "O":
+		a.censusView.ReverseSort()
+		return a, a.loadCensus()
+	case "f":
+		a.openCensusFilter()
+	case "g":
+		a.startTagFilter()
+	case "p":
+		return a, a.openCensusPresets()
+	case "d", "h", "v", "q", "u", "r", "c", "t":
+		if !a.denyReadOnly() {
+			a.startCensusAction(msg.String())
+		}
+	}
+
+	return a, nil
+}
+
+// handleHouseholdKeys handles key presses in the households tab.
+func (a *App) handleHouseholdKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		a.householdsView.MoveUp()
+	case "down", "j":
+		a.householdsView.MoveDown()
+	case "pgup":
+		a.householdsView.PrevPage()
+		return a, a.pageHouseholds()
+	case "pgdown":
+		a.householdsView.NextPage()
+		return a, a.pageHouseholds()
+	case "f":
+		a.householdsView.ToggleClosed()
+		return a, a.loadHouseholds()
+	case "o":
+		a.householdsView.CycleSort()
+		return a, a.loadHouseholds()
+	case "O":
+		a.householdsView.ReverseSort()
+		return a, a.loadHouseholds()
+	case "x", "m", "p":
+		if !a.denyReadOnly() {
+			a.startHouseholdAction(msg.String())
+		}
 	}
 
 	return a, nil
@@ -453,11 +1513,13 @@ func (a *App) handleSearchKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 type residentSavedMsg struct {
-	err error
+	undo *undoStep // Set for an edit
+	err  error
 }
 
 type deathRegisteredMsg struct {
-	err error
+	undo *undoStep
+	err  error
 }
 
 // saveResident saves the resident from the form.
@@ -468,7 +1530,8 @@ func (a *App) saveResident() tea.Cmd {
 			return residentSavedMsg{err: err}
 		}
 
-		ctx := context.Background()
+		ctx, cancel := a.operation()
+		defer cancel()
 		if resident.ID == "" {
 			// New resident - use CreateResidentInput
 			input := population.CreateResidentInput{
@@ -492,7 +1555,12 @@ func (a *App) saveResident() tea.Cmd {
 				ClearanceLevel: &resident.ClearanceLevel,
 				Notes:          &resident.Notes,
 			}
+			before, err := a.populationSvc.GetResident(ctx, resident.ID)
+			if err != nil {
+				return residentSavedMsg{err: err}
+			}
 			_, err = a.populationSvc.UpdateResident(ctx, resident.ID, input)
+			return residentSavedMsg{undo: a.editStep(before, input), err: err}
 		}
 
 		return residentSavedMsg{err: err}
@@ -502,51 +1570,79 @@ func (a *App) saveResident() tea.Cmd {
 // registerDeath registers a death for the resident.
 func (a *App) registerDeath(resident *models.Resident) tea.Cmd {
 	return func() tea.Msg {
-		ctx := context.Background()
+		ctx, cancel := a.operation()
+		defer cancel()
 		input := population.DeathRegistration{
 			DateOfDeath: a.clock.Now(),
 			Cause:       "Cause pending investigation",
 		}
 		err := a.populationSvc.RegisterDeath(ctx, resident.ID, input)
-		return deathRegisteredMsg{err: err}
+		return deathRegisteredMsg{undo: a.deathStep(resident, input), err: err}
 	}
 }
 
-// loadCensus loads the census data.
-func (a *App) loadCensus() tea.Cmd {
-	return func() tea.Msg {
-		err := a.censusView.Load(context.Background())
-		return censusLoadedMsg{err: err}
+// addResident opens the form for a new resident, with the entry type
+// preselected when given.
+func (a *App) addResident(entry models.EntryType) {
+	if a.denyReadOnly() {
+		return
+	}
+	a.residentForm = popviews.NewResidentForm(popviews.FormModeAdd)
+	if entry != "" {
+		a.residentForm.SetEntryType(entry)
 	}
+	a.showForm = true
 }
 
 // handleResourceKeys handles key presses in the resources module.
 func (a *App) handleResourceKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if a.splitView() && a.handleSplitKeys(msg, a.inventoryView.SelectedStock() != nil) {
+		return a, a.followSelection()
+	}
 	if a.showDetail {
 		// In detail view
 		switch msg.String() {
 		case "esc":
 			a.showDetail = false
+		case "t":
+			a.startQualityTest()
+		case "x", "p", "d", "q":
+			a.startStockDetailAction(msg.String())
 		}
 		return a, nil
 	}
 
+	// A split inventory moves focus with Tab, so Shift+Tab switches tabs too
+	if a.keys.Tab.Matches(msg) || a.keys.ShiftTab.Matches(msg) {
+		a.showAssets = !a.showAssets
+		if a.showAssets {
+			return a, a.loadAssets()
+		}
+		return a, a.loadInventory()
+	}
+	if a.showAssets {
+		return a.handleAssetKeys(msg)
+	}
+
 	// In list view
 	switch msg.String() {
 	case "up", "k":
 		a.inventoryView.MoveUp()
+		return a, a.followSelection()
 	case "down", "j":
 		a.inventoryView.MoveDown()
+		return a, a.followSelection()
 	case "enter":
-		if a.inventoryView.SelectedStock() != nil {
+		if stock := a.inventoryView.SelectedStock(); stock != nil {
 			a.showDetail = true
+			return a, a.loadQualityTests(stock.ID)
 		}
 	case "pgup":
 		a.inventoryView.PrevPage()
-		return a, a.loadInventory()
+		return a, a.pageInventory()
 	case "pgdown":
 		a.inventoryView.NextPage()
-		return a, a.loadInventory()
+		return a, a.pageInventory()
 	case "c":
 		// Cycle through category filter
 		categories := a.inventoryView.GetCategories()
@@ -572,19 +1668,27 @@ func (a *App) handleResourceKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			a.inventoryView.SetCategoryFilter(nextCat)
 			return a, a.loadInventory()
 		}
+	case "o":
+		a.inventoryView.CycleSort()
+		return a, a.loadInventory()
+	case "O":
+		a.inventoryView.ReverseSort()
+		return a, a.loadInventory()
+	case " ":
+		a.inventoryView.ToggleMark()
+	case "a":
+		a.inventoryView.MarkAll()
+	case "x", "m", "s":
+		if !a.denyReadOnly() {
+			a.startInventoryAction(msg.String())
+		}
+	case "n", "N", "e", "E":
+		return a, a.openCatalogForm(msg.String())
 	}
 
 	return a, nil
 }
 
-// loadInventory loads the inventory data.
-func (a *App) loadInventory() tea.Cmd {
-	return func() tea.Msg {
-		err := a.inventoryView.Load(context.Background())
-		return inventoryLoadedMsg{err: err}
-	}
-}
-
 // View implements tea.Model.
 func (a *App) View() string {
 	if !a.ready {
@@ -609,6 +1713,8 @@ func (a *App) View() string {
 	contentHeight := ContentHeight(a.height, chromeLines)
 	if a.showConfirm {
 		b.WriteString(a.renderConfirmDialog(contentHeight))
+	} else if a.palette != nil {
+		b.WriteString(a.renderPalette(contentHeight))
 	} else {
 		b.WriteString(a.renderContent(contentHeight))
 	}
@@ -617,9 +1723,87 @@ func (a *App) View() string {
 	b.WriteString("\n")
 	b.WriteString(a.renderFooter())
 
+	if a.theme.Plain {
+		return plainScreen(b.String())
+	}
 	return b.String()
 }
 
+// openModule switches to a module, recording the visit in the terminal
+// session, and loads its data.
+func (a *App) openModule(m Module) tea.Cmd {
+	return tea.Batch(a.visitModule(m), a.loadModule(m))
+}
+
+// loadModule switches to a module and loads its data.
+func (a *App) loadModule(m Module) tea.Cmd {
+	switch m {
+	case ModuleDashboard:
+		a.currentModule = ModuleDashboard
+		a.showDetail = false
+		return tea.Batch(a.loadSystems(), a.loadTrends())
+	case ModulePopulation:
+		a.currentModule = ModulePopulation
+		a.showDetail = false
+		if a.showIntakes {
+			return a.loadIntakes()
+		}
+		if a.showHouseholds {
+			return a.loadHouseholds()
+		}
+		return a.loadCensus()
+	case ModuleResources:
+		a.currentModule = ModuleResources
+		a.showDetail = false
+		if a.showAssets {
+			return a.loadAssets()
+		}
+		return a.loadInventory()
+	case ModuleFacilities:
+		a.currentModule = ModuleFacilities
+		return a.loadInspections()
+	case ModuleMedical:
+		a.currentModule = ModuleMedical
+		return tea.Batch(a.loadRadiation(), a.loadGeneticHealth(), a.loadNutrition())
+	case ModuleSecurity:
+		a.currentModule = ModuleSecurity
+		return tea.Batch(a.loadLockdown(), a.loadAccess(), a.loadArmory())
+	case ModuleGovernance:
+		a.currentModule = ModuleGovernance
+		return tea.Batch(a.loadPlanningReport(), a.loadCapacityForecast(), a.loadRationPolicies(), a.loadAnnouncements())
+	case ModuleDigest:
+		return a.openDigest()
+	case ModuleTasks:
+		a.openTasks()
+	case ModuleCare:
+		return a.openCare()
+	case ModuleAptitude:
+		return a.openAptitude()
+	case ModuleVaults:
+		return a.openVaults()
+	case ModuleDiagnostics:
+		return a.openDiagnostics()
+	case ModuleConsumption:
+		return a.openConsumption()
+	case ModuleSessions:
+		return a.openSessions()
+	case ModuleAlerts:
+		return a.openAlertQueue()
+	case ModuleDependencies:
+		return a.openDependencies()
+	case ModuleWorkOrders:
+		return a.openWorkOrders()
+	case ModuleMaintenance:
+		return a.openMaintenanceAnalytics()
+	case ModuleLabor:
+		a.currentModule = ModuleLabor
+		return a.loadTraining()
+	case ModuleSettings:
+		a.currentModule = m
+	}
+	return nil
+}
+
 // renderHeader renders the top header bar, responsive to terminal width.
 func (a *App) renderHeader() string {
 	w := a.width
@@ -628,12 +1812,12 @@ func (a *App) renderHeader() string {
 	}
 
 	// Left side: title
-	title := "VAULT-TEC UNIFIED OPERATING SYSTEM"
+	title := a.theme.Locale.T("VAULT-TEC UNIFIED OPERATING SYSTEM")
 	versionStr := fmt.Sprintf("v%s", Version)
 
 	// Right side: vault info
-	vaultInfo := fmt.Sprintf("%s │ POP: %d",
-		a.config.Vault.Designation,
+	vaultInfo := a.theme.Locale.T("%s │ POP: %d",
+		a.currentVault().Designation,
 		a.population,
 	)
 
@@ -642,12 +1826,18 @@ func (a *App) renderHeader() string {
 	case BreakpointNarrow:
 		// Compact: just vault designation and population
 		title = "VT-UOS"
-		vaultInfo = fmt.Sprintf("POP:%d", a.population)
+		vaultInfo = a.theme.Locale.T("POP:%d", a.population)
 	case BreakpointMedium:
 		title = "VT-UOS " + versionStr
 	default:
 		title = title + " " + versionStr
 	}
+	if a.readOnly {
+		vaultInfo = a.theme.Locale.T("READ-ONLY") + " │ " + vaultInfo
+	}
+	if name, _ := a.config.Profile(); name != "" {
+		vaultInfo = strings.ToUpper(name) + " │ " + vaultInfo
+	}
 
 	titleRendered := a.theme.Header.Render(title)
 	infoRendered := a.theme.Header.Render(vaultInfo)
@@ -677,23 +1867,26 @@ func (a *App) renderAlertBar() string {
 	bp := GetBreakpoint(w)
 	switch bp {
 	case BreakpointNarrow:
-		timeStr = vaultTime.Format(a.config.Display.TimeFormat)
+		timeStr = util.FormatTime(vaultTime)
 	default:
-		timeStr = vaultTime.Format(a.config.Display.DateFormat + " " + a.config.Display.TimeFormat)
+		timeStr = util.FormatDateTime(vaultTime)
 	}
 
 	// Show current time and any active alerts
 	var alertText string
-	if len(a.alerts) > 0 {
+	if a.theme.Plain && a.announcer.text != "" {
+		// Screen reader mode shows the latest announcement instead
+		alertText = a.announcer.text
+	} else if len(a.alerts) > 0 {
 		idx := a.alertIndex % len(a.alerts)
 		alert := a.alerts[idx]
 		switch alert.Level {
 		case AlertCritical:
-			alertText = a.theme.AlertCrit.Render("CRITICAL: " + alert.Message)
+			alertText = a.theme.AlertCrit.Render(alertMessage(alert, a.theme.Locale))
 		case AlertWarning:
-			alertText = a.theme.AlertWarn.Render("WARNING: " + alert.Message)
+			alertText = a.theme.AlertWarn.Render(alertMessage(alert, a.theme.Locale))
 		default:
-			alertText = a.theme.Alert.Render("INFO: " + alert.Message)
+			alertText = a.theme.Alert.Render(alertMessage(alert, a.theme.Locale))
 		}
 		// Truncate alert to fit
 		maxAlertWidth := w - lipgloss.Width(timeStr) - 5
@@ -701,12 +1894,15 @@ func (a *App) renderAlertBar() string {
 			alertText = Truncate(alertText, maxAlertWidth)
 		}
 	} else {
-		alertText = a.theme.Muted.Render("All systems operational")
+		alertText = a.theme.Muted.Render(a.theme.Locale.T("All systems operational"))
 	}
 
 	timeDisplay := a.theme.Value.Render(timeStr)
 	divider := a.theme.StatusDivider.Render()
 
+	if banner := a.renderLockdownBanner(); banner != "" {
+		return timeDisplay + divider + banner + divider + alertText
+	}
 	return timeDisplay + divider + alertText
 }
 
@@ -744,6 +1940,35 @@ func (a *App) getModuleContent() string {
 		return a.renderGovernance()
 	case ModuleHelp:
 		return a.renderHelp()
+	case ModuleSettings:
+		return a.renderSettings()
+	case ModuleDigest:
+		if a.showReport {
+			return a.renderReport()
+		}
+		return a.renderDigest()
+	case ModuleTasks:
+		return a.renderTasks()
+	case ModuleCare:
+		return a.renderCare()
+	case ModuleAptitude:
+		return a.renderAptitude()
+	case ModuleVaults:
+		return a.renderVaults()
+	case ModuleDiagnostics:
+		return a.renderDiagnostics()
+	case ModuleConsumption:
+		return a.renderConsumption()
+	case ModuleSessions:
+		return a.renderSessions()
+	case ModuleAlerts:
+		return a.renderAlertQueue()
+	case ModuleDependencies:
+		return a.renderDependencies()
+	case ModuleWorkOrders:
+		return a.renderWorkOrders()
+	case ModuleMaintenance:
+		return a.renderMaintenanceAnalytics()
 	default:
 		return a.renderPlaceholder(string(a.currentModule))
 	}
@@ -755,11 +1980,42 @@ func (a *App) renderPopulation() string {
 	if a.showForm && a.residentForm != nil {
 		return a.residentForm.RenderResponsive(a.width)
 	}
+	if a.intakeWizard != nil {
+		return a.intakeWizard.RenderResponsive(a.width)
+	}
+	if a.censusFilter != nil {
+		return a.censusFilter.RenderResponsive(a.width)
+	}
+	if a.showIntakes {
+		return a.renderPopulationTabs() + a.renderIntakes()
+	}
+
+	if a.showHouseholds {
+		return a.renderPopulationTabs() +
+			a.householdsView.Render(a.width, a.height-chromeLines) +
+			"\n" + a.renderActionBar(householdActions)
+	}
+	if a.showPresets {
+		return a.renderPopulationTabs() + a.renderCensusPresets(a.width)
+	}
+
+	split := a.splitView()
+	a.censusView.SetSplit(split)
+	listWidth, detailWidth := a.width, a.width
+	if split {
+		listWidth, detailWidth = a.splitWidths()
+		listWidth, detailWidth = paneWidth(listWidth), paneWidth(detailWidth)
+	}
 
 	// Show detail if active
-	if a.showDetail {
-		resident := a.censusView.SelectedResident()
-		return a.censusView.RenderDetail(resident, a.width)
+	var detail string
+	if a.showingBadge() {
+		detail = a.renderBadge()
+	} else if a.showDetail || split {
+		detail = a.censusView.RenderDetail(a.censusView.SelectedResident(), detailWidth)
+	}
+	if a.showDetail && !split {
+		return detail
 	}
 
 	// Show search bar if in search mode
@@ -770,21 +2026,105 @@ func (a *App) renderPopulation() string {
 			a.theme.Accent.Render("_") + "\n\n"
 	}
 
-	return searchBar + a.censusView.Render(a.width, a.height-chromeLines)
+	list := a.renderPopulationTabs() + searchBar +
+		a.censusView.Render(listWidth, a.height-chromeLines) +
+		"\n" + a.renderActionBarIn(a.censusBar(), listWidth)
+	if split {
+		return a.renderSplit(list, detail)
+	}
+	return list
+}
+
+// renderPopulationTabs renders the census, households and intake tab
+// strip.
+func (a *App) renderPopulationTabs() string {
+	tabs := []string{"Census", "Households", "Intake"}
+	current := a.populationTab()
+	for i, tab := range tabs {
+		if i == current {
+			tabs[i] = a.theme.Accent.Render("[" + tab + "]")
+		} else {
+			tabs[i] = a.theme.Label.Render(" " + tab + " ")
+		}
+	}
+	key := "  (Tab)"
+	if a.splitView() {
+		key = "  (Shift+Tab)"
+	}
+	return strings.Join(tabs, " ") + a.theme.Label.Render(key) + "\n"
+}
+
+// populationTab returns the population module's open tab: 0 for the
+// census, 1 for households and 2 for intake.
+func (a *App) populationTab() int {
+	switch {
+	case a.showHouseholds:
+		return 1
+	case a.showIntakes:
+		return 2
+	}
+	return 0
+}
+
+// switchPopulationTab moves step tabs along the population module's census,
+// households and intake tabs, wrapping around, and loads the new tab.
+func (a *App) switchPopulationTab(step int) tea.Cmd {
+	next := (a.populationTab() + step + 3) % 3
+	a.showHouseholds, a.showIntakes = next == 1, next == 2
+	switch next {
+	case 1:
+		return a.loadHouseholds()
+	case 2:
+		return a.loadIntakes()
+	}
+	return a.loadCensus()
 }
 
 // renderResources renders the resources module.
 func (a *App) renderResources() string {
+	if a.catalogForm != nil {
+		return a.catalogForm.RenderResponsive(a.width)
+	}
+	if a.showAssets {
+		return a.renderResourceTabs() + a.renderAssets()
+	}
+	split := a.splitView()
+	a.inventoryView.SetSplit(split)
+
 	// Show detail if active
-	if a.showDetail {
+	if a.showDetail && !split {
 		stock := a.inventoryView.SelectedStock()
 		return a.inventoryView.RenderDetail(stock, a.width)
 	}
+	if !split {
+		return a.renderResourceTabs() + a.inventoryView.Render(a.width, a.height-chromeLines) +
+			"\n" + a.renderActionBar(a.inventoryBar())
+	}
+
+	listWidth, detailWidth := a.splitWidths()
+	listWidth, detailWidth = paneWidth(listWidth), paneWidth(detailWidth)
+	list := a.renderResourceTabs() + a.inventoryView.Render(listWidth, a.height-chromeLines) +
+		"\n" + a.renderActionBarIn(a.inventoryBar(), listWidth)
+	return a.renderSplit(list, a.inventoryView.RenderDetail(a.inventoryView.SelectedStock(), detailWidth))
+}
 
-	return a.inventoryView.Render(a.width, a.height-chromeLines)
+// renderResourceTabs renders the stock and asset tab strip of the resources
+// module.
+func (a *App) renderResourceTabs() string {
+	stock, assets := a.theme.Accent.Render("[Stock]"), a.theme.Label.Render(" Assets ")
+	if a.showAssets {
+		stock, assets = a.theme.Label.Render(" Stock "), a.theme.Accent.Render("[Assets]")
+	}
+	key := "  (Tab)"
+	if a.splitView() {
+		key = "  (Shift+Tab)"
+	}
+	return stock + " " + assets + a.theme.Label.Render(key) + "\n"
 }
 
-// renderDashboard renders the main dashboard view with responsive panels.
+// renderDashboard renders the dashboard's configured panels in order: two
+// to a row, charts spanning the width on rows of their own, or all stacked
+// on narrow screens.
 func (a *App) renderDashboard() string {
 	w := a.width
 	if w < 40 {
@@ -799,29 +2139,30 @@ func (a *App) renderDashboard() string {
 
 	bp := GetBreakpoint(w)
 
-	// Build panels
-	popPanel := a.renderPopulationPanel(w, bp)
-	sysPanel := a.renderSystemsPanel(w, bp)
-	resPanel := a.renderResourcesPanel(w, bp)
-	simPanel := a.renderSimulationPanel(w, bp)
-
-	switch bp {
-	case BreakpointNarrow:
-		// Stack all panels vertically
-		b.WriteString(popPanel)
-		b.WriteString("\n")
-		b.WriteString(sysPanel)
-		b.WriteString("\n")
-		b.WriteString(resPanel)
-		b.WriteString("\n")
-		b.WriteString(simPanel)
-	default:
-		// Side-by-side: Population + Systems, then Resources + Simulation
-		halfWidth := w / 2
-		b.WriteString(renderSideBySide(popPanel, sysPanel, halfWidth, w))
-		b.WriteString("\n")
-		b.WriteString(renderSideBySide(resPanel, simPanel, halfWidth, w))
+	var rows []string
+	var pending string // Panel awaiting a partner on its row
+	for _, panel := range a.config.Display.Panels() {
+		content := a.renderDashboardPanel(panel, w, bp)
+		switch {
+		case bp == BreakpointNarrow:
+			rows = append(rows, content)
+		case dashboardFullWidth(panel):
+			if pending != "" {
+				rows = append(rows, pending)
+				pending = ""
+			}
+			rows = append(rows, content)
+		case pending == "":
+			pending = content
+		default:
+			rows = append(rows, renderSideBySide(pending, content, w/2, w))
+			pending = ""
+		}
 	}
+	if pending != "" {
+		rows = append(rows, pending)
+	}
+	b.WriteString(strings.Join(rows, "\n"))
 
 	return b.String()
 }
@@ -832,7 +2173,7 @@ func (a *App) renderPopulationPanel(totalWidth int, bp LayoutBreakpoint) string
 	b.WriteString(a.theme.Subtitle.Render("POPULATION"))
 	b.WriteString("\n")
 
-	capacity := a.config.Vault.DesignedCapacity
+	capacity := a.currentVault().DesignedCapacity
 	ratio := float64(a.population) / float64(capacity)
 
 	b.WriteString(fmt.Sprintf("  Active:   %s\n", a.theme.Value.Render(fmt.Sprintf("%d", a.population))))
@@ -854,68 +2195,114 @@ func (a *App) renderPopulationPanel(totalWidth int, bp LayoutBreakpoint) string
 	pctStr := fmt.Sprintf(" %.0f%%", ratio*100)
 	b.WriteString(a.theme.Muted.Render(pctStr))
 	b.WriteString("\n")
+	b.WriteString(a.renderPopulationTrend(totalWidth, bp))
+	b.WriteString(a.renderMorale(totalWidth, bp))
 
 	return b.String()
 }
 
 // renderSystemsPanel renders critical systems status for the dashboard.
+// Power and water show the grid margin; other categories show their most
+// severe system status.
 func (a *App) renderSystemsPanel(totalWidth int, bp LayoutBreakpoint) string {
 	var b strings.Builder
 	b.WriteString(a.theme.Subtitle.Render("CRITICAL SYSTEMS"))
 	b.WriteString("\n")
 
-	systems := []struct {
-		name   string
-		status string
-		pct    float64
-	}{
-		{"Power", "OPERATIONAL", 0.98},
-		{"Water", "OPERATIONAL", 0.95},
-		{"HVAC", "OPERATIONAL", 0.92},
-		{"Security", "OPERATIONAL", 1.0},
-	}
-
 	barWidth := 16
 	if bp == BreakpointNarrow {
 		barWidth = 10
 	}
 
-	for _, sys := range systems {
+	for _, grid := range a.grid {
+		line := fmt.Sprintf("  %-10s", systemLabel(string(grid.Grid)))
+		b.WriteString(a.theme.Base.Render(line))
+		b.WriteString(a.theme.ProgressBar(grid.Supply, grid.Capacity, barWidth))
+		b.WriteString(" ")
+
 		statusStyle := a.theme.Success
-		if sys.pct < 0.8 {
+		switch grid.State {
+		case facilities.GridStateTight, facilities.GridStateShedding:
 			statusStyle = a.theme.Warning
-		}
-		if sys.pct < 0.5 {
+		case facilities.GridStateDeficit:
 			statusStyle = a.theme.Error
+		case facilities.GridStateNoData:
+			statusStyle = a.theme.Muted
 		}
+		if grid.State != facilities.GridStateNoData {
+			b.WriteString(a.theme.Value.Render(fmt.Sprintf("%+.0f %s", grid.Margin(), grid.Unit)))
+			b.WriteString(" ")
+		}
+		b.WriteString(statusStyle.Render(string(grid.State)))
+		if grid.Shed > 0 {
+			b.WriteString(a.theme.Muted.Render(fmt.Sprintf(" %d shed", grid.Shed)))
+		}
+		b.WriteString("\n")
+	}
 
-		line := fmt.Sprintf("  %-10s", sys.name)
+	for _, cat := range a.systemsStatus {
+		line := fmt.Sprintf("  %-10s", systemLabel(string(cat.Category)))
 		b.WriteString(a.theme.Base.Render(line))
-		b.WriteString(a.theme.ProgressBar(sys.pct, 1.0, barWidth))
+		b.WriteString(a.theme.ProgressBar(cat.Efficiency, 100, barWidth))
 		b.WriteString(" ")
-		b.WriteString(statusStyle.Render(sys.status))
+
+		if cat.Systems == 0 {
+			b.WriteString(a.theme.Muted.Render("NO DATA"))
+			b.WriteString("\n")
+			continue
+		}
+
+		statusStyle := a.theme.Success
+		switch cat.Worst {
+		case models.FacilityStatusOperational:
+		case models.FacilityStatusDegraded, models.FacilityStatusMaintenance:
+			statusStyle = a.theme.Warning
+		default:
+			statusStyle = a.theme.Error
+		}
+		b.WriteString(statusStyle.Render(string(cat.Worst)))
 		b.WriteString("\n")
 	}
 
 	return b.String()
 }
 
-// renderResourcesPanel renders resource status for the dashboard.
+// systemLabel returns the dashboard label for a grid or facility category.
+func systemLabel(code string) string {
+	switch code {
+	case "POWER":
+		return "Power"
+	case "WATER":
+		return "Water"
+	case "SECURITY":
+		return "Security"
+	default:
+		return code
+	}
+}
+
+// gridState returns the state of the last loaded balance of a grid, or ""
+// before one is loaded.
+func (a *App) gridState(grid models.Grid) facilities.GridState {
+	for _, b := range a.grid {
+		if b.Grid == grid {
+			return b.State
+		}
+	}
+	return ""
+}
+
+// renderResourcesPanel renders the runway of each consumable resource
+// category for the dashboard.
 func (a *App) renderResourcesPanel(totalWidth int, bp LayoutBreakpoint) string {
 	var b strings.Builder
-	b.WriteString(a.theme.Subtitle.Render("RESOURCE STATUS"))
+	b.WriteString(a.theme.Subtitle.Render("RESOURCE RUNWAY"))
 	b.WriteString("\n")
 
-	// Placeholder resource data (would come from service in production)
-	resourceStats := []struct {
-		name    string
-		pct     float64
-		runway  int
-	}{
-		{"Food", 0.72, 180},
-		{"Water", 0.85, 240},
-		{"Medical", 0.60, 120},
-		{"Power", 0.90, 365},
+	if len(a.runways) == 0 {
+		b.WriteString(a.theme.Muted.Render("  No consumable resources"))
+		b.WriteString("\n")
+		return b.String()
 	}
 
 	barWidth := 16
@@ -923,13 +2310,8 @@ func (a *App) renderResourcesPanel(totalWidth int, bp LayoutBreakpoint) string {
 		barWidth = 10
 	}
 
-	for _, res := range resourceStats {
-		line := fmt.Sprintf("  %-10s", res.name)
-		b.WriteString(a.theme.Base.Render(line))
-		b.WriteString(a.theme.ProgressBar(res.pct, 1.0, barWidth))
-		runway := fmt.Sprintf(" %dd", res.runway)
-		b.WriteString(a.theme.Muted.Render(runway))
-		b.WriteString("\n")
+	for _, runway := range a.runways {
+		b.WriteString(a.renderRunway(runway, barWidth, dashboardPanelWidth(totalWidth, bp)))
 	}
 
 	return b.String()
@@ -965,8 +2347,16 @@ func (a *App) renderSimulationPanel(totalWidth int, bp LayoutBreakpoint) string
 
 	b.WriteString(fmt.Sprintf("  Status:     %s\n", statusStyle.Render(status)))
 	b.WriteString(fmt.Sprintf("  Time Scale: %s\n", a.theme.Value.Render(fmt.Sprintf("%.0fx", a.clock.TimeScale()))))
-	b.WriteString(fmt.Sprintf("  Vault Time: %s\n", a.theme.Value.Render(vaultTime.Format("2006-01-02 15:04"))))
+	b.WriteString(fmt.Sprintf("  Vault Time: %s\n", a.theme.Value.Render(util.FormatDateTime(vaultTime))))
 	b.WriteString(fmt.Sprintf("  Elapsed:    %s\n", a.theme.Value.Render(fmt.Sprintf("%d years, %d days", years, days))))
+	if a.db != nil {
+		queue := a.db.WriteQueueStats()
+		style := a.theme.Value
+		if queue.Depth > 0 {
+			style = a.theme.Warning
+		}
+		b.WriteString(fmt.Sprintf("  DB Writes:  %s\n", style.Render(fmt.Sprintf("%d queued, peak %d", queue.Depth, queue.MaxDepth))))
+	}
 
 	return b.String()
 }
@@ -1091,8 +2481,58 @@ func (a *App) renderFacilities() string {
 		b.WriteString("\n")
 	}
 
+	b.WriteString("\n")
+	b.WriteString(a.renderInspections())
+
 	b.WriteString("\n")
 	b.WriteString(a.theme.Muted.Render("  Facility management module — monitoring mode"))
+	b.WriteString("\n")
+	b.WriteString(a.theme.Muted.Render("  " + a.theme.Locale.T("d system dependencies  w work orders  m maintenance analytics")))
+
+	return b.String()
+}
+
+// renderInspections renders the inspection schedule for the facilities module.
+func (a *App) renderInspections() string {
+	var b strings.Builder
+	b.WriteString(a.theme.Subtitle.Render("INSPECTIONS"))
+	if a.overdueInspections > 0 {
+		b.WriteString(" ")
+		b.WriteString(a.theme.Error.Render(fmt.Sprintf("(%d overdue)", a.overdueInspections)))
+	}
+	b.WriteString("\n")
+
+	if len(a.upcomingInspections) == 0 {
+		b.WriteString(a.theme.Muted.Render(fmt.Sprintf("  No inspections due in the next %d days", inspectionWindowDays)))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	now := a.clock.Now()
+	nameWidth := 22
+	if GetBreakpoint(a.width) == BreakpointNarrow {
+		nameWidth = 15
+	}
+
+	for _, insp := range a.upcomingInspections {
+		name := insp.TemplateID
+		if insp.Template != nil {
+			name = insp.Template.Name
+		}
+		if insp.Location != "" {
+			name += " " + insp.Location
+		}
+
+		status := a.theme.Success.Render("DUE " + util.RelativeTimeString(insp.ScheduledDate, now))
+		if insp.IsOverdue(now) {
+			status = a.theme.Error.Render(fmt.Sprintf("OVERDUE %dd", insp.DaysOverdue(now)))
+		}
+
+		line := fmt.Sprintf("  %-*s %s ", nameWidth, Truncate(name, nameWidth), util.FormatDate(insp.ScheduledDate))
+		b.WriteString(a.theme.Base.Render(line))
+		b.WriteString(status)
+		b.WriteString("\n")
+	}
 
 	return b.String()
 }
@@ -1171,7 +2611,7 @@ func (a *App) renderLabor() string {
 	}
 
 	b.WriteString("\n")
-	b.WriteString(a.theme.Muted.Render("  Labor allocation module — monitoring mode"))
+	b.WriteString(a.renderTraining(barWidth))
 
 	return b.String()
 }
@@ -1217,6 +2657,15 @@ func (a *App) renderMedical() string {
 		b.WriteString("\n")
 	}
 
+	b.WriteString("\n")
+	b.WriteString(a.renderRadiationExposure(barWidth))
+
+	b.WriteString("\n")
+	b.WriteString(a.renderGeneticHealth(barWidth))
+
+	b.WriteString("\n")
+	b.WriteString(a.renderNutrition())
+
 	b.WriteString("\n")
 	b.WriteString(a.theme.Subtitle.Render("RECENT ENCOUNTERS"))
 	b.WriteString("\n")
@@ -1228,66 +2677,19 @@ func (a *App) renderMedical() string {
 	return b.String()
 }
 
-// renderSecurity renders the security module placeholder with structure.
+// renderSecurity renders the security module: the vault state, access
+// control and the incident log.
 func (a *App) renderSecurity() string {
 	var b strings.Builder
 	b.WriteString(a.theme.Title.Render("═══ SECURITY ═══"))
 	b.WriteString("\n\n")
-
-	b.WriteString(a.theme.Subtitle.Render("SECURITY ZONES"))
-	b.WriteString("\n")
-
-	zones := []struct {
-		code      string
-		name      string
-		clearance int
-		status    string
-	}{
-		{"ZONE-A", "Command Center", 8, "SECURE"},
-		{"ZONE-B", "Residential", 1, "SECURE"},
-		{"ZONE-C", "Engineering", 4, "SECURE"},
-		{"ZONE-D", "Armory", 7, "LOCKED"},
-		{"ZONE-E", "Vault Door", 10, "SEALED"},
-		{"ZONE-F", "Reactor", 6, "RESTRICTED"},
-	}
-
-	bp := GetBreakpoint(a.width)
-	for _, zone := range zones {
-		statusStyle := a.theme.Success
-		switch zone.status {
-		case "LOCKED":
-			statusStyle = a.theme.Warning
-		case "SEALED":
-			statusStyle = a.theme.Accent
-		case "RESTRICTED":
-			statusStyle = a.theme.Warning
-		case "BREACH":
-			statusStyle = a.theme.Error
-		}
-
-		if bp == BreakpointNarrow {
-			// Compact: code + status only
-			b.WriteString(fmt.Sprintf("  %-8s CLR:%d %s\n",
-				zone.code,
-				zone.clearance,
-				statusStyle.Render(zone.status)))
-		} else {
-			b.WriteString(fmt.Sprintf("  %-8s %-18s CLR:%d  %s\n",
-				zone.code,
-				zone.name,
-				zone.clearance,
-				statusStyle.Render(zone.status)))
-		}
-	}
-
-	b.WriteString("\n")
+	b.WriteString(a.renderLockdown())
+	b.WriteString(a.renderAccess())
+	b.WriteString(a.renderArmory())
 	b.WriteString(a.theme.Subtitle.Render("INCIDENT LOG"))
 	b.WriteString("\n")
 	b.WriteString(a.theme.Base.Render("  No active security incidents.\n"))
 
-	b.WriteString("\n")
-	b.WriteString(a.theme.Muted.Render("  Security module — monitoring mode"))
-
 	return b.String()
 }
 
@@ -1335,13 +2737,77 @@ func (a *App) renderGovernance() string {
 		}
 	}
 
+	b.WriteString("\n")
+	b.WriteString(a.renderAnnouncements())
+
+	b.WriteString("\n")
+	b.WriteString(a.renderRationPolicies())
+
+	b.WriteString("\n")
+	b.WriteString(a.renderCapacityForecast())
+
+	b.WriteString("\n")
+	b.WriteString(a.renderStaffingForecast())
+
 	b.WriteString("\n")
 	b.WriteString(a.theme.Subtitle.Render("AUDIT LOG"))
 	b.WriteString("\n")
 	b.WriteString(a.theme.Base.Render("  System initialized. Awaiting overseer input.\n"))
 
+	return b.String()
+}
+
+// staffingForecastRows is how many staffing gaps the governance screen lists.
+const staffingForecastRows = 5
+
+// renderStaffingForecast renders the staffing section of the planning report.
+func (a *App) renderStaffingForecast() string {
+	var b strings.Builder
+	b.WriteString(a.theme.Subtitle.Render(fmt.Sprintf("STAFFING FORECAST (%d YR)", governance.DefaultPlanningYears)))
 	b.WriteString("\n")
-	b.WriteString(a.theme.Muted.Render("  Governance module — monitoring mode"))
+
+	report := a.planningReport
+	if report == nil {
+		b.WriteString(a.theme.Muted.Render("  Forecast loading..."))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	b.WriteString(fmt.Sprintf("  Population %s → %s\n",
+		a.theme.Value.Render(fmt.Sprintf("%d", report.Population.CurrentPopulation)),
+		a.theme.Value.Render(fmt.Sprintf("%d", report.FinalPopulation()))))
+
+	gaps := report.Staffing.Gaps
+	if len(gaps) == 0 {
+		b.WriteString(a.theme.Success.Render("  All vocations staffed through the horizon"))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	for i, gap := range gaps {
+		if i == staffingForecastRows {
+			b.WriteString(a.theme.Muted.Render(fmt.Sprintf("  ... %d more gap(s), see `vtuos report planning`", len(gaps)-i)))
+			b.WriteString("\n")
+			break
+		}
+		style := a.theme.Warning
+		if gap.YearsOut == 0 {
+			style = a.theme.Error
+		}
+		b.WriteString("  " + style.Render(Truncate(gap.String(), a.width-4)))
+		b.WriteString("\n")
+	}
+
+	late := 0
+	for _, rec := range report.Staffing.Recommendations {
+		if rec.Late {
+			late++
+		}
+	}
+	if late > 0 {
+		b.WriteString(a.theme.Muted.Render(fmt.Sprintf("  %d training pipeline(s) should start now", late)))
+		b.WriteString("\n")
+	}
 
 	return b.String()
 }
@@ -1353,10 +2819,10 @@ func (a *App) renderHelp() string {
 
 	var b strings.Builder
 
-	b.WriteString(a.theme.Title.Render("═══ HELP ═══"))
+	b.WriteString(a.theme.Title.Render("═══ " + a.theme.Locale.T("HELP") + " ═══"))
 	b.WriteString("\n\n")
 
-	b.WriteString(a.theme.Subtitle.Render("NAVIGATION"))
+	b.WriteString(a.theme.Subtitle.Render(a.theme.Locale.T("NAVIGATION")))
 	b.WriteString("\n\n")
 
 	navItems := [][2]string{
@@ -1370,30 +2836,31 @@ func (a *App) renderHelp() string {
 		{"F8", "Security"},
 		{"F9", "Governance"},
 		{"F10", "Quit"},
+		{"F11", "Settings"},
 	}
 
 	// On wider terminals, render in two columns
 	if bp == BreakpointWide && len(navItems) > 5 {
 		half := (len(navItems) + 1) / 2
 		for i := 0; i < half; i++ {
-			left := fmt.Sprintf("    %-8s  %-24s", navItems[i][0], navItems[i][1])
+			left := fmt.Sprintf("    %-8s  %s", navItems[i][0], PadRight(a.theme.Locale.T(navItems[i][1]), 24))
 			b.WriteString(a.theme.Primary.Render(left))
 			if i+half < len(navItems) {
-				right := fmt.Sprintf("    %-8s  %s", navItems[i+half][0], navItems[i+half][1])
+				right := fmt.Sprintf("    %-8s  %s", navItems[i+half][0], a.theme.Locale.T(navItems[i+half][1]))
 				b.WriteString(a.theme.Primary.Render(right))
 			}
 			b.WriteString("\n")
 		}
 	} else {
 		for _, item := range navItems {
-			line := fmt.Sprintf("    %-8s  %s", item[0], item[1])
+			line := fmt.Sprintf("    %-8s  %s", item[0], a.theme.Locale.T(item[1]))
 			b.WriteString(a.theme.Primary.Render(line))
 			b.WriteString("\n")
 		}
 	}
 
 	b.WriteString("\n")
-	b.WriteString(a.theme.Subtitle.Render("CONTROLS"))
+	b.WriteString(a.theme.Subtitle.Render(a.theme.Locale.T("CONTROLS")))
 	b.WriteString("\n\n")
 
 	ctrlItems := [][2]string{
@@ -1403,33 +2870,71 @@ func (a *App) renderHelp() string {
 		{"/", "Search in lists"},
 		{"Tab", "Next field in forms"},
 		{"PgUp/Dn", "Page navigation"},
-		{"a", "Add new record"},
+		{"A", "Add new resident"},
+		{"Space/a", "Mark row / all rows on page"},
 		{"e", "Edit selected"},
 		{"d", "Delete / Death record"},
 		{"c", "Cycle category filter"},
+		{"Ctrl+Z", "Suspend to shell"},
+		{"Ctrl+U/Y", "Undo / redo recent change"},
+		{":", "Command palette"},
+		{"h", "Reassign household"},
+		{"v", "Assign vocation"},
+		{"x", "Adjust stock quantity"},
+		{"m", "Move stock location"},
+		{"s", "Set stock status"},
+		{"c", "Set household ration class"},
+		{"q/u", "Quarantine / surface mission"},
+		{"r", "Return resident to active"},
+		{"f", "Filter panel (census)"},
+		{"t/g/p", "Tag / filter by tags / presets (census)"},
+		{"Tab", "Census / households / intake (population)"},
+		{"n/s/c", "New intake / screen / clear (intake)"},
+		{"Tab", "Stock / assets (resources)"},
+		{"n/N", "New item / category (resources)"},
+		{"e/E", "Edit item / category (resources)"},
+		{"n/o/i/c/x", "Register / check out / check in / condition / retire (assets)"},
+		{"o/O", "Cycle sort column / reverse sort"},
+		{"x/m/p", "Dissolve / merge / split household"},
+		{"d", "Daily digest (dashboard)"},
+		{"t", "Scheduled tasks (dashboard)"},
+		{"c", "Care assignments (dashboard)"},
+		{"g", "Aptitude assessments (dashboard)"},
+		{"v", "Switch managed vault (dashboard)"},
+		{"x", "Diagnostics (dashboard)"},
+		{"u", "Consumption analytics (dashboard)"},
+		{"o", "Operator session activity (dashboard)"},
+		{"a", "Alert queue and routing rules (dashboard)"},
+		{"d", "System dependencies (facilities)"},
+		{"w", "Work orders and lead technicians (facilities)"},
+		{"m", "Maintenance analytics and failure Pareto (facilities)"},
+		{"l", "Change vault state (security)"},
+		{"i/t", "Issue / turn in weapon (security)"},
+		{"b/n/a", "Broadcast / notice / acknowledge (governance)"},
+		{"i/p", "ID badge / print badge (resident)"},
 	}
 
 	if bp == BreakpointWide && len(ctrlItems) > 5 {
 		half := (len(ctrlItems) + 1) / 2
 		for i := 0; i < half; i++ {
-			left := fmt.Sprintf("    %-10s  %-22s", ctrlItems[i][0], ctrlItems[i][1])
+			left := fmt.Sprintf("    %-10s  %s", ctrlItems[i][0], PadRight(a.theme.Locale.T(ctrlItems[i][1]), 22))
 			b.WriteString(a.theme.Primary.Render(left))
 			if i+half < len(ctrlItems) {
-				right := fmt.Sprintf("    %-10s  %s", ctrlItems[i+half][0], ctrlItems[i+half][1])
+				right := fmt.Sprintf("    %-10s  %s", ctrlItems[i+half][0], a.theme.Locale.T(ctrlItems[i+half][1]))
 				b.WriteString(a.theme.Primary.Render(right))
 			}
 			b.WriteString("\n")
 		}
 	} else {
 		for _, item := range ctrlItems {
-			line := fmt.Sprintf("    %-10s  %s", item[0], item[1])
+			line := fmt.Sprintf("    %-10s  %s", item[0], a.theme.Locale.T(item[1]))
 			b.WriteString(a.theme.Primary.Render(line))
 			b.WriteString("\n")
 		}
 	}
 
 	b.WriteString("\n")
-	b.WriteString(a.theme.Muted.Render("Press Esc to return"))
+	b.WriteString(a.theme.Muted.Render(a.theme.Locale.T("Press Esc to return")))
 
 	return b.String()
 }
@@ -1442,10 +2947,10 @@ func (a *App) renderPlaceholder(name string) string {
 	b.WriteString(a.theme.Title.Render(title))
 	b.WriteString("\n\n")
 
-	b.WriteString(a.theme.Muted.Render("This module is not yet implemented."))
+	b.WriteString(a.theme.Muted.Render(a.theme.Locale.T("This module is not yet implemented.")))
 	b.WriteString("\n\n")
 
-	b.WriteString(a.theme.Label.Render("Press F2 to return to Dashboard"))
+	b.WriteString(a.theme.Label.Render(a.theme.Locale.T("Press F2 to return to Dashboard")))
 
 	return b.String()
 }
@@ -1453,9 +2958,9 @@ func (a *App) renderPlaceholder(name string) string {
 // renderConfirmDialog renders the quit confirmation dialog.
 func (a *App) renderConfirmDialog(height int) string {
 	dialog := a.theme.Box.Render(
-		a.theme.Title.Render("CONFIRM EXIT") + "\n\n" +
-			a.theme.Base.Render("Are you sure you want to exit?") + "\n\n" +
-			a.theme.Label.Render("[Y]es  [N]o"),
+		a.theme.Title.Render(a.theme.Locale.T("CONFIRM EXIT")) + "\n\n" +
+			a.theme.Base.Render(a.theme.Locale.T("Are you sure you want to exit?")) + "\n\n" +
+			a.theme.Label.Render(a.theme.Locale.T("[Y]es  [N]o")),
 	)
 
 	// Center the dialog
@@ -1472,8 +2977,11 @@ func (a *App) renderFooter() string {
 	// Draw separator
 	separator := a.theme.DrawHorizontalLine(a.width)
 
-	// Help text adapts to width
-	help := a.keys.StatusBarHelpResponsive(a.width)
+	// Help text adapts to width; a sign-on prompt takes its place
+	help := a.keys.StatusBarHelpResponsive(a.width, a.theme.Locale)
+	if a.quickAction != nil && a.quickAction.kind == quickActionSignOn {
+		help = a.quickAction.prompt + a.quickAction.input + "_"
+	}
 
 	return separator + "\n" + a.theme.Footer.Render(help)
 }
@@ -1495,17 +3003,82 @@ func (a *App) AddAlert(level AlertLevel, message string) {
 	a.alertIndex = 0
 }
 
+// AddError adds an alert for a failed operation. Errors caused by the
+// request itself (missing records, duplicates, failed validation or restrict
+// policies) are warnings; anything else points at the system and is critical.
+func (a *App) AddError(action string, err error) {
+	a.AddAlert(errorAlertLevel(err), action+": "+errorMessage(err))
+}
+
+// errorAlertLevel maps a service error to an alert level. An operation
+// cancelled by shutdown is only noted; one that timed out is a warning.
+func errorAlertLevel(err error) AlertLevel {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return AlertInfo
+	case errors.Is(err, context.DeadlineExceeded):
+		return AlertWarning
+	case errors.Is(err, repository.ErrNotFound),
+		errors.Is(err, repository.ErrDuplicate),
+		errors.Is(err, repository.ErrValidation),
+		errors.Is(err, repository.ErrReadOnly),
+		errors.Is(err, repository.ErrHouseholdHasMembers),
+		errors.Is(err, repository.ErrResidentIsParent),
+		errors.Is(err, repository.ErrVocationHasAssignments),
+		errors.Is(err, repository.ErrQuartersOccupied):
+		return AlertWarning
+	default:
+		return AlertCritical
+	}
+}
+
+// errorMessage describes a service error for an alert, replacing the
+// driver's wording for cancelled and timed-out operations.
+func errorMessage(err error) string {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return "cancelled, terminal shutting down"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timed out"
+	default:
+		return err.Error()
+	}
+}
+
 // ClearAlerts removes all alerts.
 func (a *App) ClearAlerts() {
 	a.alerts = []Alert{}
 	a.alertIndex = 0
 }
 
-// Run starts the TUI application.
-func Run(ctx context.Context, db *database.DB, cfg *config.Config, clock *util.VaultClock) error {
-	app := New(db, cfg, clock)
+// SetJournal records the commands the terminal's services run in j.
+func (a *App) SetJournal(j *journal.Journal) {
+	for _, v := range a.vaults {
+		v.setJournal(j)
+	}
+	a.inspectionSvc.SetJournal(j)
+}
+
+// SetEvents publishes the events of the terminal's services on bus.
+func (a *App) SetEvents(bus *events.Bus) {
+	for _, v := range a.vaults {
+		v.setEvents(bus)
+	}
+}
+
+// Run starts the TUI application, journaling its commands in j and
+// publishing its events on bus, each if not nil.
+func Run(ctx context.Context, db *database.DB, cfg *config.Config, cfgPath string, clock *util.VaultClock, j *journal.Journal, bus *events.Bus) error {
+	app := New(db, cfg, cfgPath, clock)
+	app.SetJournal(j)
+	app.SetEvents(bus)
+	app.ctx = ctx
 
 	p := tea.NewProgram(app, tea.WithAltScreen())
+	app.program = p
+
+	stopSuspendWatch := watchSuspendSignal(p)
+	defer stopSuspendWatch()
 
 	// Handle context cancellation
 	go func() {
@@ -1514,5 +3087,10 @@ func Run(ctx context.Context, db *database.DB, cfg *config.Config, clock *util.V
 	}()
 
 	_, err := p.Run()
+	app.endSession()
+	if n := app.inFlight.Load(); n > 0 && ctx.Err() != nil {
+		slog.Warn("operations cancelled by shutdown", "count", n)
+		fmt.Fprintf(os.Stderr, "Shutdown cancelled %d operation(s) in progress; their uncommitted changes were rolled back.\n", n)
+	}
 	return err
 }
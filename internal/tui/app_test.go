@@ -0,0 +1,61 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/vtuos/vtuos/internal/config"
+	"github.com/vtuos/vtuos/internal/repository"
+)
+
+func TestErrorAlertLevel(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected AlertLevel
+	}{
+		{"Not found", fmt.Errorf("resident %w: abc", repository.ErrNotFound), AlertWarning},
+		{"Duplicate", fmt.Errorf("inserting household: %w", repository.ErrDuplicate), AlertWarning},
+		{"Validation", fmt.Errorf("%w: storage location is required", repository.ErrValidation), AlertWarning},
+		{"Read-only", fmt.Errorf("creating resident: %w", repository.ErrReadOnly), AlertWarning},
+		{"Restrict policy", fmt.Errorf("deleting household: %w", repository.ErrHouseholdHasMembers), AlertWarning},
+		{"Wrapped twice", fmt.Errorf("household H-0001: %w", fmt.Errorf("household %w", repository.ErrNotFound)), AlertWarning},
+		{"Database failure", errors.New("database is locked"), AlertCritical},
+		{"Cancelled by shutdown", fmt.Errorf("listing residents: %w", context.Canceled), AlertInfo},
+		{"Timed out", fmt.Errorf("building digest: %w", context.DeadlineExceeded), AlertWarning},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errorAlertLevel(tt.err); got != tt.expected {
+				t.Errorf("errorAlertLevel(%v) = %v, want %v", tt.err, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestOperation_CountsInFlight(t *testing.T) {
+	ctx, shutdown := context.WithCancel(context.Background())
+	a := &App{ctx: ctx, config: config.Default()}
+
+	opCtx, cancel := a.operation()
+	if n := a.inFlight.Load(); n != 1 {
+		t.Fatalf("inFlight = %d, want 1", n)
+	}
+
+	shutdown()
+	if !errors.Is(opCtx.Err(), context.Canceled) {
+		t.Errorf("operation context error = %v, want context.Canceled", opCtx.Err())
+	}
+	if got := errorMessage(fmt.Errorf("loading census: %w", opCtx.Err())); got != "cancelled, terminal shutting down" {
+		t.Errorf("errorMessage() = %q", got)
+	}
+
+	cancel()
+	cancel()
+	if n := a.inFlight.Load(); n != 0 {
+		t.Errorf("inFlight after cancel = %d, want 0", n)
+	}
+}
@@ -0,0 +1,167 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/services/population"
+	"github.com/vtuos/vtuos/internal/tui/components"
+)
+
+// aptitudeActions are the actions available on aptitude candidate rows.
+var aptitudeActions = components.NewActionBar(
+	components.Action{Key: "s", Label: "Record scores"},
+	components.Action{Key: "a", Label: "Apply recommendation"},
+)
+
+// aptitudeMsg carries the loaded aptitude candidates.
+type aptitudeMsg struct {
+	candidates []population.AptitudeCandidate
+	err        error
+}
+
+// openAptitude switches to the aptitude assessment screen.
+func (a *App) openAptitude() tea.Cmd {
+	a.currentModule = ModuleAptitude
+	return a.loadAptitude()
+}
+
+// loadAptitude loads the residents of testing age without a vocation.
+func (a *App) loadAptitude() tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := a.operation()
+		defer cancel()
+		candidates, err := a.populationSvc.ListAptitudeCandidates(ctx)
+		return aptitudeMsg{candidates: candidates, err: err}
+	}
+}
+
+// handleAptitudeKeys handles key presses on the aptitude assessment screen.
+func (a *App) handleAptitudeKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch key := msg.String(); key {
+	case "up", "k":
+		if a.aptitudeIndex > 0 {
+			a.aptitudeIndex--
+		}
+	case "down", "j":
+		if a.aptitudeIndex < len(a.aptitudeCandidates)-1 {
+			a.aptitudeIndex++
+		}
+	case "enter", "s", "a":
+		if a.aptitudeIndex >= len(a.aptitudeCandidates) || a.denyReadOnly() {
+			return a, nil
+		}
+		c := a.aptitudeCandidates[a.aptitudeIndex]
+		action := &quickAction{targetID: c.Resident.ID, targetName: c.Resident.FullName()}
+		if key == "a" {
+			if c.Recommended == nil || c.Assessment.IsApplied() {
+				a.AddAlert(AlertWarning, "No recommendation to apply for "+action.targetName)
+				return a, nil
+			}
+			action.kind = quickActionApplyAptitude
+			action.targetID = c.Assessment.ID
+			action.prompt = "Assign " + action.targetName + " as " + c.Recommended.Title + "? (y/n)"
+			action.confirm = true
+		} else {
+			action.kind = quickActionAptitude
+			action.prompt = "Scores ENG MED SEC FOOD ADM EDU SAN RES (0-100): "
+		}
+		a.quickAction = action
+	case "r":
+		return a, a.loadAptitude()
+	}
+	return a, nil
+}
+
+// runAptitudeAction records an assessment for, or applies the recommended
+// vocation of, the selected candidate.
+func (a *App) runAptitudeAction(ctx context.Context, action *quickAction, input string) quickActionDoneMsg {
+	if action.kind == quickActionApplyAptitude {
+		_, vocation, err := a.populationSvc.ApplyAptitudeRecommendation(ctx, action.targetID)
+		if err != nil {
+			return quickActionDoneMsg{module: ModuleAptitude, err: err}
+		}
+		return quickActionDoneMsg{module: ModuleAptitude, success: action.targetName + " assigned as " + vocation.Title}
+	}
+
+	scores, err := models.ParseAptitudeScores(input)
+	if err != nil {
+		return quickActionDoneMsg{module: ModuleAptitude, err: fmt.Errorf("%w: %w", repository.ErrValidation, err)}
+	}
+	_, matches, err := a.populationSvc.RecordAptitude(ctx, population.AptitudeInput{
+		ResidentID: action.targetID,
+		Scores:     scores,
+	})
+	if err != nil {
+		return quickActionDoneMsg{module: ModuleAptitude, err: err}
+	}
+	if len(matches) == 0 {
+		return quickActionDoneMsg{module: ModuleAptitude, success: "Assessment recorded for " + action.targetName + "; no open vocation matches"}
+	}
+	return quickActionDoneMsg{module: ModuleAptitude, success: fmt.Sprintf("Assessment recorded for %s: recommended %s (%d)",
+		action.targetName, matches[0].Vocation.Title, matches[0].Score)}
+}
+
+// renderAptitude renders the aptitude assessment screen: each resident of
+// testing age without a vocation, with their top scores and the vocation
+// their latest assessment recommends.
+func (a *App) renderAptitude() string {
+	var b strings.Builder
+	b.WriteString(a.theme.Title.Render("═══ APTITUDE ASSESSMENT ═══"))
+	b.WriteString("\n\n")
+
+	if len(a.aptitudeCandidates) == 0 {
+		b.WriteString(a.theme.Muted.Render(fmt.Sprintf("  No residents aged %d or over awaiting a vocation", models.AptitudeTestAge)))
+		return b.String()
+	}
+
+	header := fmt.Sprintf("  %-12s %-28s %-4s %-26s %s", "REGISTRY", "RESIDENT", "AGE", "TOP APTITUDES", "RECOMMENDATION")
+	b.WriteString(a.theme.TableHeader.Render(Truncate(header, a.width-2)))
+	b.WriteString("\n")
+
+	now := a.clock.Now()
+	for i, c := range a.aptitudeCandidates {
+		top, recommendation := "-", "DUE"
+		if c.Assessment != nil {
+			ranked := c.Assessment.Scores.Ranked()
+			parts := make([]string, 0, 2)
+			for _, dept := range ranked[:2] {
+				parts = append(parts, fmt.Sprintf("%s %d", dept, c.Assessment.Scores[dept]))
+			}
+			top = strings.Join(parts, ", ")
+			switch {
+			case c.Recommended == nil:
+				recommendation = "No open vocation"
+			case c.Assessment.IsApplied():
+				recommendation = c.Recommended.Title + " (applied)"
+			default:
+				recommendation = c.Recommended.Title
+			}
+		}
+		line := fmt.Sprintf("%-12s %-28s %-4d %-26s %s",
+			c.Resident.RegistryNumber, Truncate(c.Resident.FullName(), 28),
+			c.Resident.Age(now), Truncate(top, 26), recommendation)
+		line = Truncate(line, a.width-4)
+
+		switch {
+		case i == a.aptitudeIndex:
+			b.WriteString(a.theme.Selected.Render("> " + line))
+		case c.Due():
+			b.WriteString("  " + a.theme.Warning.Render(line))
+		default:
+			b.WriteString("  " + a.theme.Base.Render(line))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n  ")
+	b.WriteString(a.renderActionBar(aptitudeActions))
+	b.WriteString("\n")
+	b.WriteString(a.theme.Muted.Render("  " + a.theme.Locale.T("↑/↓ select  r reload  Esc back")))
+	return b.String()
+}
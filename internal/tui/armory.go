@@ -0,0 +1,167 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/services/security"
+	"github.com/vtuos/vtuos/internal/tui/components"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// armoryActions are the actions available on the security module's armory.
+var armoryActions = components.NewActionBar(
+	components.Action{Key: "i", Label: "Issue weapon"},
+	components.Action{Key: "t", Label: "Turn in weapon"},
+)
+
+// armoryMsg carries the weapons issued from the armory and still held.
+type armoryMsg struct {
+	issues []*models.ArmoryIssue
+	err    error
+}
+
+// loadArmory loads the weapons currently issued.
+func (a *App) loadArmory() tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := a.operation()
+		defer cancel()
+		issues, err := a.securitySvc.ListIssuedWeapons(ctx)
+		return armoryMsg{issues: issues, err: err}
+	}
+}
+
+// handleArmoryKeys opens the issue and turn-in prompts of the armory.
+func (a *App) handleArmoryKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if a.denyReadOnly() {
+		return a, nil
+	}
+	switch msg.String() {
+	case "i":
+		a.quickAction = &quickAction{
+			kind:   quickActionIssueWeapon,
+			prompt: "Issue weapon: SERIAL REGISTRY APPROVER [INC-YYYY-NNNN, none in a drill] [rounds]: ",
+		}
+	case "t":
+		a.quickAction = &quickAction{
+			kind:   quickActionReturnWeapon,
+			prompt: "Turn in weapon: SERIAL CONDITION ROUNDS-EXPENDED [notes]: ",
+		}
+	}
+	return a, nil
+}
+
+// runArmoryAction issues a weapon or takes one back into the armory.
+func (a *App) runArmoryAction(ctx context.Context, action *quickAction, input string) quickActionDoneMsg {
+	fields := strings.Fields(input)
+	if len(fields) < 3 {
+		if action.kind == quickActionIssueWeapon {
+			return quickActionDoneMsg{module: ModuleSecurity, err: fmt.Errorf("%w: expected serial number, registry number and approving officer", repository.ErrValidation)}
+		}
+		return quickActionDoneMsg{module: ModuleSecurity, err: fmt.Errorf("%w: expected serial number, condition and rounds expended", repository.ErrValidation)}
+	}
+	asset, err := a.resourceSvc.GetAssetBySerial(ctx, fields[0])
+	if err != nil {
+		return quickActionDoneMsg{module: ModuleSecurity, err: err}
+	}
+
+	if action.kind == quickActionReturnWeapon {
+		condition, err := models.ParseAssetCondition(fields[1])
+		if err != nil {
+			return quickActionDoneMsg{module: ModuleSecurity, err: fmt.Errorf("%w: %w", repository.ErrValidation, err)}
+		}
+		rounds, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return quickActionDoneMsg{module: ModuleSecurity, err: fmt.Errorf("%w: rounds expended must be a number", repository.ErrValidation)}
+		}
+		input := security.ReturnWeaponInput{
+			AssetID:        asset.ID,
+			Condition:      condition,
+			RoundsExpended: rounds,
+			Notes:          strings.Join(fields[3:], " "),
+		}
+		if a.operator != nil {
+			input.ReceivedBy = &a.operator.ID
+		}
+		issue, err := a.securitySvc.ReturnWeapon(ctx, input)
+		if err != nil {
+			return quickActionDoneMsg{module: ModuleSecurity, err: err}
+		}
+		done := quickActionDoneMsg{module: ModuleSecurity, success: fmt.Sprintf("%s turned in by %s, %s, %d of %d rounds expended",
+			asset.SerialNumber, issue.Custody.Resident.RegistryNumber, condition, rounds, issue.RoundsIssued)}
+		if !condition.Serviceable() {
+			done.alert = AlertWarning
+		}
+		return done
+	}
+
+	resident, err := a.populationSvc.GetResidentByRegistryNumber(ctx, strings.ToUpper(fields[1]))
+	if err != nil {
+		return quickActionDoneMsg{module: ModuleSecurity, err: fmt.Errorf("resident %s: %w", strings.ToUpper(fields[1]), err)}
+	}
+	approver, err := a.populationSvc.GetResidentByRegistryNumber(ctx, strings.ToUpper(fields[2]))
+	if err != nil {
+		return quickActionDoneMsg{module: ModuleSecurity, err: fmt.Errorf("approving officer %s: %w", strings.ToUpper(fields[2]), err)}
+	}
+	issue := security.IssueWeaponInput{AssetID: asset.ID, ResidentID: resident.ID, ApprovedBy: approver.ID}
+	for _, field := range fields[3:] {
+		if rounds, err := strconv.Atoi(field); err == nil {
+			issue.RoundsIssued = rounds
+		} else {
+			issue.IncidentNumber = field
+		}
+	}
+	issued, err := a.securitySvc.IssueWeapon(ctx, issue)
+	if err != nil {
+		return quickActionDoneMsg{module: ModuleSecurity, err: err}
+	}
+	return quickActionDoneMsg{module: ModuleSecurity, success: fmt.Sprintf("%s issued to %s %s with %d rounds for %s",
+		asset.SerialNumber, resident.RegistryNumber, resident.FullName(), issued.RoundsIssued, issued.Purpose())}
+}
+
+// renderArmory renders the armory section of the security module: who
+// currently holds which weapon, on whose approval and for what.
+func (a *App) renderArmory() string {
+	var b strings.Builder
+	b.WriteString(a.theme.Subtitle.Render("ARMORY"))
+	b.WriteString(a.theme.Muted.Render(fmt.Sprintf("  %d weapons issued", len(a.armoryIssues))))
+	b.WriteString("\n")
+
+	if len(a.armoryIssues) == 0 {
+		b.WriteString(a.theme.Muted.Render("  All weapons in the armory"))
+		b.WriteString("\n")
+	}
+	narrow := GetBreakpoint(a.width) == BreakpointNarrow
+	now := a.clock.Now()
+	for _, i := range a.armoryIssues {
+		if i.Asset == nil || i.Custody == nil {
+			continue
+		}
+		holder := i.Custody.Resident
+		line := fmt.Sprintf("%-14s %-20s %-11s %-18s %-13s by %-11s %3d rds  %s",
+			Truncate(i.Asset.SerialNumber, 14), Truncate(i.Asset.Name, 20), holder.RegistryNumber,
+			Truncate(holder.FullName(), 18), i.Purpose(), i.ApproverRegistry, i.RoundsIssued,
+			util.FormatDay(i.Custody.IssuedAt))
+		if narrow {
+			line = fmt.Sprintf("%-14s %-11s %-13s %3d rds", Truncate(i.Asset.SerialNumber, 14),
+				holder.RegistryNumber, i.Purpose(), i.RoundsIssued)
+		}
+		line = Truncate(line, a.width-4)
+		if i.Custody.IsOverdue(now) {
+			b.WriteString("  " + a.theme.Warning.Render(line+" OVERDUE"))
+		} else {
+			b.WriteString("  " + a.theme.Base.Render(line))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("  ")
+	b.WriteString(a.renderActionBar(armoryActions))
+	b.WriteString("\n\n")
+	return b.String()
+}
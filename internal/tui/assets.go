@@ -0,0 +1,363 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/services/resources"
+	"github.com/vtuos/vtuos/internal/tui/components"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// assetHistoryRows is how many custody periods of the selected asset the
+// asset tab lists.
+const assetHistoryRows = 8
+
+// assetActions are the actions available on the resources module's asset
+// tab.
+var assetActions = components.NewActionBar(
+	components.Action{Key: "n", Label: "Register"},
+	components.Action{Key: "o", Label: "Check out"},
+	components.Action{Key: "i", Label: "Check in"},
+	components.Action{Key: "c", Label: "Condition"},
+	components.Action{Key: "x", Label: "Retire"},
+)
+
+// assetsMsg carries the vault's assets under the category filter and the
+// custody history of the selected one.
+type assetsMsg struct {
+	assets  []*models.Asset
+	history []*models.AssetCustody
+	err     error
+}
+
+// assetHistoryMsg carries the custody history of an asset.
+type assetHistoryMsg struct {
+	assetID string
+	history []*models.AssetCustody
+	err     error
+}
+
+// loadAssets loads the assets under the category filter and the custody
+// history of the one selected.
+func (a *App) loadAssets() tea.Cmd {
+	filter := models.AssetFilter{Category: a.assetCategory}
+	index := a.assetIndex
+	return func() tea.Msg {
+		ctx, cancel := a.operation()
+		defer cancel()
+		assets, err := a.resourceSvc.ListAssets(ctx, filter)
+		if err != nil || len(assets) == 0 {
+			return assetsMsg{assets: assets, err: err}
+		}
+		selected := assets[min(index, len(assets)-1)]
+		history, err := a.resourceSvc.ListAssetCustody(ctx, selected.ID)
+		return assetsMsg{assets: assets, history: history, err: err}
+	}
+}
+
+// loadAssetHistory loads the custody history of the selected asset.
+func (a *App) loadAssetHistory() tea.Cmd {
+	asset := a.selectedAsset()
+	if asset == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		ctx, cancel := a.operation()
+		defer cancel()
+		history, err := a.resourceSvc.ListAssetCustody(ctx, asset.ID)
+		return assetHistoryMsg{assetID: asset.ID, history: history, err: err}
+	}
+}
+
+// selectedAsset returns the asset selected on the asset tab, nil if there
+// are none.
+func (a *App) selectedAsset() *models.Asset {
+	if a.assetIndex < len(a.assets) {
+		return a.assets[a.assetIndex]
+	}
+	return nil
+}
+
+// handleAssetKeys handles key presses in the asset tab: selection, the
+// category filter and the registry actions.
+func (a *App) handleAssetKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	asset := a.selectedAsset()
+	switch key := msg.String(); key {
+	case "up", "k":
+		if a.assetIndex > 0 {
+			a.assetIndex--
+			return a, a.loadAssetHistory()
+		}
+	case "down", "j":
+		if a.assetIndex < len(a.assets)-1 {
+			a.assetIndex++
+			return a, a.loadAssetHistory()
+		}
+	case "f":
+		// Every category, then each in turn
+		next := models.AssetCategories[0]
+		for i, category := range models.AssetCategories {
+			if category == a.assetCategory {
+				next = ""
+				if i+1 < len(models.AssetCategories) {
+					next = models.AssetCategories[i+1]
+				}
+			}
+		}
+		a.assetCategory = next
+		a.assetIndex = 0
+		return a, a.loadAssets()
+	case "n":
+		if a.denyReadOnly() {
+			return a, nil
+		}
+		a.quickAction = &quickAction{
+			kind:   quickActionRegisterAsset,
+			prompt: "Register asset: SERIAL CATEGORY NAME: ",
+		}
+	case "o", "i", "c", "x":
+		if asset == nil || a.denyReadOnly() {
+			return a, nil
+		}
+		action := &quickAction{targetID: asset.ID, targetName: asset.SerialNumber}
+		switch key {
+		case "o":
+			if asset.Custody != nil {
+				a.AddAlert(AlertWarning, fmt.Sprintf("%s is in the custody of %s", asset.SerialNumber,
+					asset.Custody.Resident.RegistryNumber))
+				return a, nil
+			}
+			action.kind = quickActionCheckOutAsset
+			action.prompt = "Issue " + asset.SerialNumber + " to registry number [days] [notes] (no days: assignment): "
+		case "i":
+			if asset.Custody == nil {
+				a.AddAlert(AlertInfo, asset.SerialNumber+" is in store")
+				return a, nil
+			}
+			action.kind = quickActionCheckInAsset
+			action.prompt = "Return of " + asset.SerialNumber + " from " + asset.Custody.Resident.RegistryNumber +
+				", condition [notes]: "
+		case "c":
+			action.kind = quickActionAssetCondition
+			action.prompt = "Condition of " + asset.SerialNumber + " found: CONDITION [notes]: "
+		case "x":
+			action.kind = quickActionRetireAsset
+			action.prompt = "Retire " + asset.SerialNumber + ", reason: "
+		}
+		a.quickAction = action
+	case "r":
+		return a, a.loadAssets()
+	}
+	return a, nil
+}
+
+// runAssetAction registers an asset, or checks out, checks in, records the
+// condition of or retires the selected one.
+func (a *App) runAssetAction(ctx context.Context, action *quickAction, input string) quickActionDoneMsg {
+	fields := strings.Fields(input)
+	var operator *string
+	if a.operator != nil {
+		operator = &a.operator.ID
+	}
+
+	switch action.kind {
+	case quickActionRegisterAsset:
+		if len(fields) < 3 {
+			return quickActionDoneMsg{module: ModuleResources, err: fmt.Errorf("%w: expected serial number, category and name", repository.ErrValidation)}
+		}
+		category, err := models.ParseAssetCategory(fields[1])
+		if err != nil {
+			return quickActionDoneMsg{module: ModuleResources, err: fmt.Errorf("%w: %w", repository.ErrValidation, err)}
+		}
+		asset, err := a.resourceSvc.RegisterAsset(ctx, resources.AssetInput{
+			SerialNumber: fields[0],
+			Name:         strings.Join(fields[2:], " "),
+			Category:     category,
+		})
+		if err != nil {
+			return quickActionDoneMsg{module: ModuleResources, err: err}
+		}
+		return quickActionDoneMsg{module: ModuleResources, success: fmt.Sprintf("Registered %s %s (%s)",
+			asset.Category, asset.SerialNumber, asset.Name)}
+
+	case quickActionCheckOutAsset:
+		regNum := strings.ToUpper(fields[0])
+		resident, err := a.populationSvc.GetResidentByRegistryNumber(ctx, regNum)
+		if err != nil {
+			return quickActionDoneMsg{module: ModuleResources, err: fmt.Errorf("resident %s: %w", regNum, err)}
+		}
+		input := resources.CheckOutInput{AssetID: action.targetID, ResidentID: resident.ID, IssuedBy: operator}
+		rest := fields[1:]
+		if len(rest) > 0 {
+			if days, err := strconv.Atoi(rest[0]); err == nil {
+				if days < 1 {
+					return quickActionDoneMsg{module: ModuleResources, err: fmt.Errorf("%w: days must be at least 1", repository.ErrValidation)}
+				}
+				due := a.clock.Now().UTC().Truncate(time.Second).AddDate(0, 0, days)
+				input.DueAt = &due
+				rest = rest[1:]
+			}
+		}
+		input.Notes = strings.Join(rest, " ")
+		custody, err := a.resourceSvc.CheckOutAsset(ctx, input)
+		if err != nil {
+			return quickActionDoneMsg{module: ModuleResources, err: err}
+		}
+		if custody.IsAssignment() {
+			return quickActionDoneMsg{module: ModuleResources, success: fmt.Sprintf("%s assigned to %s %s",
+				action.targetName, resident.RegistryNumber, resident.FullName())}
+		}
+		return quickActionDoneMsg{module: ModuleResources, success: fmt.Sprintf("%s checked out to %s %s, due %s",
+			action.targetName, resident.RegistryNumber, resident.FullName(), util.FormatDay(*custody.DueAt))}
+
+	case quickActionCheckInAsset, quickActionAssetCondition:
+		condition, err := models.ParseAssetCondition(fields[0])
+		if err != nil {
+			return quickActionDoneMsg{module: ModuleResources, err: fmt.Errorf("%w: %w", repository.ErrValidation, err)}
+		}
+		notes := strings.Join(fields[1:], " ")
+		if action.kind == quickActionAssetCondition {
+			report, err := a.resourceSvc.RecordAssetCondition(ctx, resources.AssetConditionInput{
+				AssetID:    action.targetID,
+				Condition:  condition,
+				RecordedBy: operator,
+				Notes:      notes,
+			})
+			if err != nil {
+				return quickActionDoneMsg{module: ModuleResources, err: err}
+			}
+			return assetConditionDone(action.targetName, report.Previous, report.Condition)
+		}
+		custody, err := a.resourceSvc.CheckInAsset(ctx, resources.CheckInInput{
+			AssetID:    action.targetID,
+			Condition:  condition,
+			ReceivedBy: operator,
+			Notes:      notes,
+		})
+		if err != nil {
+			return quickActionDoneMsg{module: ModuleResources, err: err}
+		}
+		done := assetConditionDone(action.targetName, custody.ConditionOut, custody.ConditionIn)
+		done.success = fmt.Sprintf("%s returned by %s; %s", action.targetName, custody.Resident.RegistryNumber, done.success)
+		if custody.DueAt != nil && custody.ReturnedAt.After(*custody.DueAt) {
+			done.alert = AlertWarning
+			done.success += " (returned late)"
+		}
+		return done
+
+	case quickActionRetireAsset:
+		if err := a.resourceSvc.RetireAsset(ctx, action.targetID, input); err != nil {
+			return quickActionDoneMsg{module: ModuleResources, err: err}
+		}
+		return quickActionDoneMsg{module: ModuleResources, success: action.targetName + " retired"}
+	}
+	return quickActionDoneMsg{module: ModuleResources}
+}
+
+// assetConditionDone reports an asset's condition as found, warning when
+// it has worsened to unserviceable.
+func assetConditionDone(serial string, previous, condition models.AssetCondition) quickActionDoneMsg {
+	if condition == previous {
+		return quickActionDoneMsg{module: ModuleResources, success: fmt.Sprintf("%s %s", serial, condition)}
+	}
+	done := quickActionDoneMsg{module: ModuleResources, success: fmt.Sprintf("%s %s → %s", serial, previous, condition)}
+	if !condition.Serviceable() {
+		done.alert = AlertWarning
+		done.success += "; withdrawn from issue until repaired"
+	}
+	return done
+}
+
+// renderAssets renders the asset tab: the registry under its category
+// filter, who holds what, and the custody history of the selected asset.
+func (a *App) renderAssets() string {
+	var b strings.Builder
+	title := "ASSET REGISTRY"
+	if a.assetCategory != "" {
+		title += " — " + string(a.assetCategory)
+	}
+	b.WriteString(a.theme.Subtitle.Render(title))
+	b.WriteString("\n")
+
+	now := a.clock.Now()
+	if len(a.assets) == 0 {
+		b.WriteString(a.theme.Muted.Render("  No assets registered; register one with n"))
+		b.WriteString("\n")
+	}
+	narrow := GetBreakpoint(a.width) == BreakpointNarrow
+	for i, asset := range a.assets {
+		holder := "in store"
+		if asset.Location != "" {
+			holder = "at " + asset.Location
+		}
+		if c := asset.Custody; c != nil {
+			holder = c.Resident.RegistryNumber + " " + c.Resident.FullName()
+			switch {
+			case c.IsOverdue(now):
+				holder += "  OVERDUE " + util.FormatDay(*c.DueAt)
+			case c.DueAt != nil:
+				holder += "  due " + util.FormatDay(*c.DueAt)
+			default:
+				holder += "  assigned"
+			}
+		}
+		line := fmt.Sprintf("%-14s %-22s %-11s %-9s %s", asset.SerialNumber, Truncate(asset.Name, 22),
+			asset.Category, asset.Condition, holder)
+		if narrow {
+			line = fmt.Sprintf("%-14s %-9s %s", asset.SerialNumber, asset.Condition, holder)
+		}
+		line = Truncate(line, a.width-4)
+
+		switch {
+		case i == a.assetIndex:
+			b.WriteString(a.theme.Selected.Render("> " + line))
+		case asset.Custody != nil && asset.Custody.IsOverdue(now), !asset.Condition.Serviceable():
+			b.WriteString("  " + a.theme.Warning.Render(line))
+		default:
+			b.WriteString("  " + a.theme.Base.Render(line))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("  ")
+	b.WriteString(a.renderActionBar(assetActions))
+	b.WriteString("\n\n")
+
+	if asset := a.selectedAsset(); asset != nil {
+		b.WriteString(a.theme.Subtitle.Render("CUSTODY HISTORY"))
+		b.WriteString(a.theme.Muted.Render("  " + asset.SerialNumber + " " + asset.Name))
+		b.WriteString("\n")
+		if len(a.assetHistory) == 0 {
+			b.WriteString(a.theme.Muted.Render("  Never issued"))
+			b.WriteString("\n")
+		}
+		for i, c := range a.assetHistory {
+			if i == assetHistoryRows {
+				b.WriteString(a.theme.Muted.Render(fmt.Sprintf("  ... %d earlier", len(a.assetHistory)-i)))
+				b.WriteString("\n")
+				break
+			}
+			returned := "held"
+			if c.ReturnedAt != nil {
+				returned = fmt.Sprintf("returned %s %s", util.FormatDay(*c.ReturnedAt), c.ConditionIn)
+			}
+			line := fmt.Sprintf("%s  %-11s %-9s %s", util.FormatDay(c.IssuedAt), c.Resident.RegistryNumber,
+				c.ConditionOut, returned)
+			if c.Notes != "" {
+				line += "  " + c.Notes
+			}
+			b.WriteString("  " + a.theme.Base.Render(Truncate(line, a.width-4)))
+			b.WriteString("\n")
+		}
+	}
+	b.WriteString(a.theme.Muted.Render("  " + a.theme.Locale.T("↑/↓ select  f filter by category  r reload")))
+	b.WriteString("\n")
+	return b.String()
+}
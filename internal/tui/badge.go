@@ -0,0 +1,85 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/vtuos/vtuos/internal/config"
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// badgeMsg carries the loaded ID badge of the resident in the detail view.
+type badgeMsg struct {
+	badge *models.IDBadge
+	err   error
+}
+
+// badgePrintedMsg reports where an ID badge was written for the printer.
+type badgePrintedMsg struct {
+	path string
+	err  error
+}
+
+// loadBadge builds the ID badge of a resident.
+func (a *App) loadBadge(resident *models.Resident) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := a.operation()
+		defer cancel()
+		badge, err := a.populationSvc.IDBadge(ctx, resident)
+		return badgeMsg{badge: badge, err: err}
+	}
+}
+
+// printBadge writes the shown ID badge to <registry number>.txt in the
+// badge directory, for the badge printer. It only writes a file, so it is
+// allowed on read-only terminals.
+func (a *App) printBadge() tea.Cmd {
+	badge := a.badge
+	return func() tea.Msg {
+		dir, err := config.BadgeDir(a.config)
+		if err != nil {
+			return badgePrintedMsg{err: err}
+		}
+		if err := os.MkdirAll(dir, 0750); err != nil {
+			return badgePrintedMsg{err: fmt.Errorf("creating badge directory: %w", err)}
+		}
+		path := filepath.Join(dir, badge.RegistryNumber+".txt")
+		if err := os.WriteFile(path, []byte(badge.Text()), 0640); err != nil {
+			return badgePrintedMsg{err: fmt.Errorf("writing badge: %w", err)}
+		}
+		return badgePrintedMsg{path: path}
+	}
+}
+
+// showingBadge reports whether the detail view shows the selected
+// resident's ID badge.
+func (a *App) showingBadge() bool {
+	if !a.showDetail || a.badge == nil {
+		return false
+	}
+	resident := a.censusView.SelectedResident()
+	return resident != nil && resident.RegistryNumber == a.badge.RegistryNumber
+}
+
+// renderBadge renders the ID badge of the resident in the detail view.
+func (a *App) renderBadge() string {
+	var b strings.Builder
+	b.WriteString(a.theme.Title.Render("═══ RESIDENT ID BADGE ═══"))
+	b.WriteString("\n\n")
+
+	for _, line := range a.badge.Lines() {
+		style := a.theme.Primary
+		if strings.HasPrefix(line, "+") {
+			style = a.theme.Accent
+		}
+		b.WriteString("  " + style.Render(line) + "\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(a.theme.Muted.Render("  Esc/i:Details  p:Print to file"))
+	return b.String()
+}
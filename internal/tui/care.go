@@ -0,0 +1,126 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/vtuos/vtuos/internal/services/population"
+	"github.com/vtuos/vtuos/internal/tui/components"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// careActions are the actions available on care queue rows.
+var careActions = components.NewActionBar(
+	components.Action{Key: "a", Label: "Assign guardian"},
+	components.Action{Key: "x", Label: "Cancel"},
+)
+
+// careMsg carries the loaded care assignment queue.
+type careMsg struct {
+	pending []population.PendingCare
+	err     error
+}
+
+// openCare switches to the care assignment queue.
+func (a *App) openCare() tea.Cmd {
+	a.currentModule = ModuleCare
+	return a.loadCare()
+}
+
+// loadCare loads the dependents awaiting a guardian.
+func (a *App) loadCare() tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := a.operation()
+		defer cancel()
+		pending, err := a.populationSvc.ListPendingCare(ctx)
+		return careMsg{pending: pending, err: err}
+	}
+}
+
+// handleCareKeys handles key presses in the care assignment queue.
+func (a *App) handleCareKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch key := msg.String(); key {
+	case "up", "k":
+		if a.careIndex > 0 {
+			a.careIndex--
+		}
+	case "down", "j":
+		if a.careIndex < len(a.careQueue)-1 {
+			a.careIndex++
+		}
+	case "enter", "a", "x":
+		if a.careIndex >= len(a.careQueue) || a.denyReadOnly() {
+			return a, nil
+		}
+		care := a.careQueue[a.careIndex]
+		action := &quickAction{targetID: care.ID, targetName: care.Dependent.FullName()}
+		if key == "x" {
+			action.kind = quickActionCancelCare
+			action.prompt = "Cancel care assignment for " + action.targetName + "? (y/n)"
+			action.confirm = true
+		} else {
+			action.kind = quickActionCare
+			action.prompt = "Guardian registry number for " + action.targetName + ": "
+		}
+		a.quickAction = action
+	case "r":
+		return a, a.loadCare()
+	}
+	return a, nil
+}
+
+// runCareAction assigns a guardian to, or cancels, a pending care assignment.
+func (a *App) runCareAction(ctx context.Context, action *quickAction, input string) quickActionDoneMsg {
+	if action.kind == quickActionCancelCare {
+		err := a.populationSvc.CancelCare(ctx, action.targetID)
+		return quickActionDoneMsg{module: ModuleCare, success: "Care assignment for " + action.targetName + " cancelled", err: err}
+	}
+
+	guardian, err := a.populationSvc.GetResidentByRegistryNumber(ctx, strings.ToUpper(input))
+	if err != nil {
+		return quickActionDoneMsg{module: ModuleCare, err: fmt.Errorf("guardian %s: %w", strings.ToUpper(input), err)}
+	}
+	_, err = a.populationSvc.AssignCare(ctx, action.targetID, guardian.ID)
+	return quickActionDoneMsg{module: ModuleCare, success: guardian.FullName() + " is now guardian of " + action.targetName, err: err}
+}
+
+// renderCare renders the care assignment queue: each dependent awaiting a
+// guardian with their age and why they were queued.
+func (a *App) renderCare() string {
+	var b strings.Builder
+	b.WriteString(a.theme.Title.Render("═══ CARE ASSIGNMENTS ═══"))
+	b.WriteString("\n\n")
+
+	if len(a.careQueue) == 0 {
+		b.WriteString(a.theme.Muted.Render("  No dependents awaiting a guardian"))
+		return b.String()
+	}
+
+	header := fmt.Sprintf("  %-12s %-28s %-4s %-11s %s", "REGISTRY", "DEPENDENT", "AGE", "OPENED", "REASON")
+	b.WriteString(a.theme.TableHeader.Render(Truncate(header, a.width-2)))
+	b.WriteString("\n")
+
+	now := a.clock.Now()
+	for i, care := range a.careQueue {
+		line := fmt.Sprintf("%-12s %-28s %-4d %-11s %s",
+			care.Dependent.RegistryNumber, Truncate(care.Dependent.FullName(), 28),
+			care.Dependent.Age(now), util.FormatDay(care.OpenedAt), care.Reason)
+		line = Truncate(line, a.width-4)
+
+		if i == a.careIndex {
+			b.WriteString(a.theme.Selected.Render("> " + line))
+		} else {
+			b.WriteString("  " + a.theme.Base.Render(line))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n  ")
+	b.WriteString(a.renderActionBar(careActions))
+	b.WriteString("\n")
+	b.WriteString(a.theme.Muted.Render("  " + a.theme.Locale.T("↑/↓ select  r reload  Esc back")))
+	return b.String()
+}
@@ -0,0 +1,195 @@
+package tui
+
+import (
+	"errors"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/services/resources"
+	resviews "github.com/vtuos/vtuos/internal/tui/views/resources"
+)
+
+// catalogEditor is a form of the item master: a category or item form.
+type catalogEditor interface {
+	HandleKey(key string)
+	IsSubmitted() bool
+	IsCancelled() bool
+	SetError(err string)
+	SetCodeError(err string)
+	RenderResponsive(width int) string
+}
+
+// catalogItemMsg carries an item fetched to be edited.
+type catalogItemMsg struct {
+	item *models.ResourceItem
+	err  error
+}
+
+// catalogSavedMsg is sent when a category or item form has been saved.
+type catalogSavedMsg struct {
+	saved string // Code of the category or item saved
+	err   error
+}
+
+// openCatalogForm opens the item master form for key: n a new item, N a new
+// category, e the item of the selected stock and E its category.
+func (a *App) openCatalogForm(key string) tea.Cmd {
+	if a.denyReadOnly() {
+		return nil
+	}
+	categories := a.inventoryView.GetCategories()
+	if key == "N" {
+		a.catalogForm = resviews.NewCategoryForm(nil)
+		return nil
+	}
+	if len(categories) == 0 {
+		a.AddAlert(AlertInfo, "No resource categories yet; add one with N")
+		return nil
+	}
+	if key == "n" {
+		a.catalogForm = resviews.NewItemForm(categories, nil)
+		return nil
+	}
+
+	stock := a.inventoryView.SelectedStock()
+	if stock == nil || stock.Item == nil {
+		return nil
+	}
+	if key == "E" {
+		for _, cat := range categories {
+			if cat.ID == stock.Item.CategoryID {
+				a.catalogForm = resviews.NewCategoryForm(cat)
+			}
+		}
+		return nil
+	}
+	itemID := stock.Item.ID
+	return func() tea.Msg {
+		ctx, cancel := a.operation()
+		defer cancel()
+		item, err := a.resourceSvc.GetItem(ctx, itemID)
+		return catalogItemMsg{item: item, err: err}
+	}
+}
+
+// handleCatalogItem opens the form editing a fetched item.
+func (a *App) handleCatalogItem(msg catalogItemMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		a.AddError("Failed to load item", msg.err)
+		return a, nil
+	}
+	a.catalogForm = resviews.NewItemForm(a.inventoryView.GetCategories(), msg.item)
+	return a, nil
+}
+
+// handleCatalogFormKeys handles key presses while a category or item form
+// is open, saving it once submitted.
+func (a *App) handleCatalogFormKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	a.catalogForm.HandleKey(msg.String())
+	if a.catalogForm.IsCancelled() {
+		a.catalogForm = nil
+		return a, nil
+	}
+	if a.catalogForm.IsSubmitted() {
+		return a, a.saveCatalog(a.catalogForm)
+	}
+	return a, nil
+}
+
+// saveCatalog creates or updates the category or item in form.
+func (a *App) saveCatalog(form catalogEditor) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := a.operation()
+		defer cancel()
+		switch form := form.(type) {
+		case *resviews.CategoryForm:
+			cat := form.GetData()
+			var err error
+			if cat.ID == "" {
+				_, err = a.resourceSvc.CreateCategory(ctx, resources.CreateCategoryInput{
+					Code:          cat.Code,
+					Name:          cat.Name,
+					Description:   cat.Description,
+					UnitOfMeasure: cat.UnitOfMeasure,
+					IsConsumable:  cat.IsConsumable,
+					IsCritical:    cat.IsCritical,
+				})
+			} else {
+				_, err = a.resourceSvc.UpdateCategory(ctx, cat.ID, resources.UpdateCategoryInput{
+					Name:          cat.Name,
+					Description:   cat.Description,
+					UnitOfMeasure: cat.UnitOfMeasure,
+					IsConsumable:  cat.IsConsumable,
+					IsCritical:    cat.IsCritical,
+				})
+			}
+			return catalogSavedMsg{saved: "Category " + cat.Code, err: err}
+
+		case *resviews.ItemForm:
+			item := form.GetData()
+			var err error
+			if item.ID == "" {
+				_, err = a.resourceSvc.CreateItem(ctx, resources.CreateItemInput{
+					CategoryID:           item.CategoryID,
+					ItemCode:             item.ItemCode,
+					Name:                 item.Name,
+					Description:          item.Description,
+					UnitOfMeasure:        item.UnitOfMeasure,
+					CaloriesPerUnit:      item.CaloriesPerUnit,
+					Nutrients:            item.Nutrients,
+					ShelfLifeDays:        item.ShelfLifeDays,
+					StorageRequirements:  item.StorageRequirements,
+					IsProducible:         item.IsProducible,
+					ProductionRatePerDay: item.ProductionRatePerDay,
+					MinStock:             item.MinStock,
+					TargetStock:          item.TargetStock,
+				})
+			} else {
+				_, err = a.resourceSvc.UpdateItem(ctx, item.ID, resources.UpdateItemInput{
+					CategoryID:           item.CategoryID,
+					Name:                 item.Name,
+					Description:          item.Description,
+					UnitOfMeasure:        item.UnitOfMeasure,
+					CaloriesPerUnit:      item.CaloriesPerUnit,
+					Nutrients:            item.Nutrients,
+					ShelfLifeDays:        item.ShelfLifeDays,
+					StorageRequirements:  item.StorageRequirements,
+					IsProducible:         item.IsProducible,
+					ProductionRatePerDay: item.ProductionRatePerDay,
+					MinStock:             item.MinStock,
+					TargetStock:          item.TargetStock,
+				})
+			}
+			return catalogSavedMsg{saved: "Item " + item.ItemCode, err: err}
+		}
+		return nil
+	}
+}
+
+// handleCatalogSaved closes the form once saved. An error stays on the form
+// to be corrected: a code in use against the code field, anything else below
+// the fields.
+func (a *App) handleCatalogSaved(msg catalogSavedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		if a.catalogForm == nil {
+			a.AddError("Failed to save "+msg.saved, msg.err)
+			return a, nil
+		}
+		switch {
+		case errors.Is(msg.err, repository.ErrDuplicate):
+			a.catalogForm.SetCodeError("Already in use")
+		case errors.Is(msg.err, repository.ErrValidation):
+			a.catalogForm.SetError(errorMessage(msg.err))
+		default:
+			a.catalogForm.SetError(errorMessage(msg.err))
+			a.AddError("Failed to save "+msg.saved, msg.err)
+		}
+		return a, nil
+	}
+	a.catalogForm = nil
+	a.AddAlert(AlertInfo, msg.saved+" saved")
+	a.inventoryView.ReloadCategories()
+	return a, a.loadInventory()
+}
@@ -0,0 +1,30 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	popviews "github.com/vtuos/vtuos/internal/tui/views/population"
+)
+
+// openCensusFilter opens the census filter panel on the current filter.
+func (a *App) openCensusFilter() {
+	filter, _ := a.censusView.Filter()
+	a.censusFilter = popviews.NewCensusFilterForm(filter)
+}
+
+// handleCensusFilterKeys handles key presses while the census filter panel
+// is open, reloading the census with the filter once applied. A vocation
+// code the vault does not know shows as the census's load error.
+func (a *App) handleCensusFilterKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	a.censusFilter.HandleKey(msg.String())
+	if a.censusFilter.IsCancelled() {
+		a.censusFilter = nil
+		return a, nil
+	}
+	if !a.censusFilter.IsSubmitted() {
+		return a, nil
+	}
+	a.censusView.SetFilter(a.censusFilter.GetData())
+	a.censusFilter = nil
+	return a, a.loadCensus()
+}
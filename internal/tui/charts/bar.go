@@ -0,0 +1,43 @@
+package charts
+
+import (
+	"math"
+	"strings"
+)
+
+// barEighths are the partly filled cells of a bar, one eighth full to seven
+// eighths full.
+var barEighths = []rune("▏▎▍▌▋▊▉")
+
+// Bar draws a horizontal bar width cells wide, filled to the nearest eighth
+// of a cell in proportion to value of max, the rest of its track shaded.
+// Values outside 0..max are drawn empty or full.
+func Bar(value, max float64, width int) string {
+	fill, track, _ := bar(value, max, width)
+	return fill + track
+}
+
+// Bar draws a styled bar, its fill colored by how full it is.
+func (s Styles) Bar(value, max float64, width int) string {
+	fill, track, ratio := bar(value, max, width)
+	return s.Level(ratio).Render(fill) + s.Axis.Render(track)
+}
+
+// bar returns the filled and shaded parts of a bar and its fill ratio.
+func bar(value, max float64, width int) (fill, track string, ratio float64) {
+	if width <= 0 {
+		return "", "", 0
+	}
+	if max > 0 {
+		ratio = clamp(value / max)
+	}
+
+	eighths := int(math.Round(ratio * float64(width*8)))
+	full, part := eighths/8, eighths%8
+	fill = strings.Repeat("█", full)
+	if part > 0 {
+		fill += string(barEighths[part-1])
+		full++
+	}
+	return fill, strings.Repeat("░", width-full), ratio
+}
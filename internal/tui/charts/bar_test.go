@@ -0,0 +1,38 @@
+package charts
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestBar(t *testing.T) {
+	tests := []struct {
+		name  string
+		value float64
+		max   float64
+		width int
+		want  string
+	}{
+		{"Half", 5, 10, 4, "██░░"},
+		{"Full", 10, 10, 4, "████"},
+		{"Empty", 0, 10, 4, "░░░░"},
+		{"Eighth of a cell", 1, 32, 4, "▏░░░"},
+		{"Partial cell", 5, 8, 2, "█▎"},
+		{"Over max clamped", 20, 10, 3, "███"},
+		{"Negative clamped", -1, 10, 3, "░░░"},
+		{"No max", 5, 0, 3, "░░░"},
+		{"No room", 5, 10, 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Bar(tt.value, tt.max, tt.width)
+			if got != tt.want {
+				t.Errorf("Bar(%v, %v, %d) = %q, want %q", tt.value, tt.max, tt.width, got, tt.want)
+			}
+			if n := utf8.RuneCountInString(got); n != tt.width {
+				t.Errorf("Bar(%v, %v, %d) is %d cells wide", tt.value, tt.max, tt.width, n)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+// Package charts draws small text charts for the terminal: sparklines,
+// horizontal bars, line charts and Pareto charts, each fitted to the width
+// it is given and colored by Styles the caller takes from its theme.
+package charts
+
+import (
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Fill ratios below which a bar turns from good to warning, and from
+// warning to critical, as the theme's progress bars do.
+const (
+	WarningRatio  = 0.6
+	CriticalRatio = 0.3
+)
+
+// Styles color the marks of a chart. The zero value draws plain charts.
+type Styles struct {
+	Line     lipgloss.Style // Sparklines and line chart points
+	Good     lipgloss.Style // Bars filled above WarningRatio
+	Warning  lipgloss.Style // Bars filled above CriticalRatio
+	Critical lipgloss.Style // Bars filled to CriticalRatio or less
+	Axis     lipgloss.Style // Bar tracks, chart axes and their labels
+}
+
+// Level returns the style of a bar filled to ratio.
+func (s Styles) Level(ratio float64) lipgloss.Style {
+	switch {
+	case ratio > WarningRatio:
+		return s.Good
+	case ratio > CriticalRatio:
+		return s.Warning
+	default:
+		return s.Critical
+	}
+}
+
+// Resample fits values to at most n cells. With more values than cells,
+// each cell is the mean of its share of consecutive values; fewer values
+// are returned as they are.
+func Resample(values []float64, n int) []float64 {
+	if n <= 0 {
+		return nil
+	}
+	if len(values) <= n {
+		return values
+	}
+	cells := make([]float64, n)
+	for i := range cells {
+		from, to := i*len(values)/n, (i+1)*len(values)/n
+		var sum float64
+		for _, v := range values[from:to] {
+			sum += v
+		}
+		cells[i] = sum / float64(to-from)
+	}
+	return cells
+}
+
+// bounds returns the lowest and highest of values, which must not be empty.
+func bounds(values []float64) (lo, hi float64) {
+	lo, hi = values[0], values[0]
+	for _, v := range values[1:] {
+		lo, hi = min(lo, v), max(hi, v)
+	}
+	return lo, hi
+}
+
+// clamp limits ratio to 0..1.
+func clamp(ratio float64) float64 {
+	return max(0, min(ratio, 1))
+}
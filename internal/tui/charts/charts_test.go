@@ -0,0 +1,57 @@
+package charts
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestResample(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		n      int
+		want   []float64
+	}{
+		{"Fewer values kept", []float64{1, 2}, 5, []float64{1, 2}},
+		{"Exact fit kept", []float64{1, 2, 3}, 3, []float64{1, 2, 3}},
+		{"Pairs averaged", []float64{1, 3, 5, 7}, 2, []float64{2, 6}},
+		{"Uneven shares", []float64{3, 6, 9}, 2, []float64{3, 7.5}},
+		{"No cells", []float64{1, 2}, 0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Resample(tt.values, tt.n); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Resample(%v, %d) = %v, want %v", tt.values, tt.n, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStyles_Level(t *testing.T) {
+	s := Styles{
+		Good:     lipgloss.NewStyle().SetString("good"),
+		Warning:  lipgloss.NewStyle().SetString("warning"),
+		Critical: lipgloss.NewStyle().SetString("critical"),
+	}
+
+	tests := []struct {
+		ratio float64
+		want  string
+	}{
+		{1, "good"},
+		{0.61, "good"},
+		{0.6, "warning"},
+		{0.31, "warning"},
+		{0.3, "critical"},
+		{0, "critical"},
+	}
+
+	for _, tt := range tests {
+		if got := s.Level(tt.ratio).String(); got != tt.want {
+			t.Errorf("Level(%v) = %s, want %s", tt.ratio, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package charts
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// LineChart draws values as a line chart height rows tall and at most width
+// cells wide, top row first. A left axis labels the highest value at the
+// top and the lowest at the bottom; each value is a point at its height,
+// joined to the one before by a vertical stroke. It draws nothing without
+// values or room for at least one point.
+func LineChart(values []float64, width, height int) []string {
+	axis, plot := lineChart(values, width, height)
+	if plot == nil {
+		return nil
+	}
+	rows := make([]string, len(plot))
+	for i := range plot {
+		rows[i] = axis[i] + plot[i]
+	}
+	return rows
+}
+
+// LineChart draws a styled line chart.
+func (s Styles) LineChart(values []float64, width, height int) []string {
+	axis, plot := lineChart(values, width, height)
+	if plot == nil {
+		return nil
+	}
+	rows := make([]string, len(plot))
+	for i := range plot {
+		rows[i] = s.Axis.Render(axis[i]) + s.Line.Render(plot[i])
+	}
+	return rows
+}
+
+// lineChart returns the axis and the plot of each row of a line chart.
+func lineChart(values []float64, width, height int) (axis, plot []string) {
+	if len(values) == 0 || height <= 0 {
+		return nil, nil
+	}
+	lo, hi := bounds(values)
+	top, bottom := axisLabel(hi), axisLabel(lo)
+	gutter := max(len(top), len(bottom)) + 1
+	points := Resample(values, width-gutter)
+	if len(points) == 0 {
+		return nil, nil
+	}
+
+	grid := make([][]rune, height)
+	for i := range grid {
+		grid[i] = []rune(strings.Repeat(" ", len(points)))
+	}
+	prev := -1
+	for col, v := range points {
+		level := 0
+		if hi > lo {
+			level = int(math.Round((v - lo) / (hi - lo) * float64(height-1)))
+		}
+		if prev >= 0 {
+			for l := min(prev, level) + 1; l < max(prev, level); l++ {
+				grid[height-1-l][col] = '│'
+			}
+		}
+		grid[height-1-level][col] = '•'
+		prev = level
+	}
+
+	axis = make([]string, height)
+	plot = make([]string, height)
+	for i := range grid {
+		label, tick := "", "│"
+		switch i {
+		case 0:
+			label, tick = top, "┤"
+		case height - 1:
+			label, tick = bottom, "┤"
+		}
+		axis[i] = strings.Repeat(" ", gutter-1-len(label)) + label + tick
+		plot[i] = string(grid[i])
+	}
+	return axis, plot
+}
+
+// axisLabel formats an axis value: whole numbers plainly, others to one
+// decimal place.
+func axisLabel(v float64) string {
+	if v == math.Trunc(v) {
+		return strconv.FormatFloat(v, 'f', 0, 64)
+	}
+	return strconv.FormatFloat(v, 'f', 1, 64)
+}
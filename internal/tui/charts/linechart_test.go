@@ -0,0 +1,61 @@
+package charts
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestLineChart(t *testing.T) {
+	got := LineChart([]float64{0, 50, 100, 100}, 10, 3)
+	want := []string{
+		"100┤  ••",
+		"   │ •  ",
+		"  0┤•   ",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LineChart =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestLineChart_JoinsPoints(t *testing.T) {
+	got := LineChart([]float64{0, 3, 0}, 10, 4)
+	want := []string{
+		"3┤ • ",
+		" │ ││",
+		" │ ││",
+		"0┤• •",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LineChart =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestLineChart_FitsWidth(t *testing.T) {
+	values := make([]float64, 60)
+	for i := range values {
+		values[i] = float64(i) / 4
+	}
+	rows := LineChart(values, 30, 5)
+	if len(rows) != 5 {
+		t.Fatalf("Expected 5 rows, got %d", len(rows))
+	}
+	for _, row := range rows {
+		if n := utf8.RuneCountInString(row); n != 30 {
+			t.Errorf("Row %q is %d cells wide, want 30", row, n)
+		}
+	}
+	if !strings.HasPrefix(rows[0], "14.8┤") {
+		t.Errorf("Expected top label 14.8, got %q", rows[0])
+	}
+}
+
+func TestLineChart_Empty(t *testing.T) {
+	if rows := LineChart(nil, 30, 5); rows != nil {
+		t.Errorf("Expected no rows without values, got %q", rows)
+	}
+	if rows := LineChart([]float64{1, 2}, 2, 5); rows != nil {
+		t.Errorf("Expected no rows without room, got %q", rows)
+	}
+}
@@ -0,0 +1,66 @@
+package charts
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Pareto draws a Pareto chart of values, which should be sorted largest
+// first: one row per value, its bar width cells wide scaled to the largest
+// value, followed by the running total as a percentage of the sum. Rows
+// from cut on, the trivial many, are drawn with shaded bars. It draws
+// nothing without values or room for a bar.
+func Pareto(values []float64, width, cut int) []string {
+	rows := make([]string, 0, len(values))
+	for _, r := range pareto(values, width, cut) {
+		rows = append(rows, r.fill+r.track+r.total)
+	}
+	return rows
+}
+
+// Pareto draws a styled Pareto chart, the vital few in the line style.
+func (s Styles) Pareto(values []float64, width, cut int) []string {
+	rows := make([]string, 0, len(values))
+	for i, r := range pareto(values, width, cut) {
+		fill := s.Line
+		if i >= cut {
+			fill = s.Axis
+		}
+		rows = append(rows, fill.Render(r.fill)+s.Axis.Render(r.track+r.total))
+	}
+	return rows
+}
+
+// paretoRow is a row of a Pareto chart: its bar and running total.
+type paretoRow struct {
+	fill, track, total string
+}
+
+// pareto returns the rows of a Pareto chart, shading the bars of the rows
+// from cut on.
+func pareto(values []float64, width, cut int) []paretoRow {
+	if len(values) == 0 || width <= 0 {
+		return nil
+	}
+	_, hi := bounds(values)
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+
+	rows := make([]paretoRow, len(values))
+	var running float64
+	for i, v := range values {
+		fill, track, _ := bar(v, hi, width)
+		if i >= cut {
+			fill = strings.Repeat("▒", len([]rune(fill)))
+		}
+		running += v
+		share := 0.0
+		if sum > 0 {
+			share = running / sum * 100
+		}
+		rows[i] = paretoRow{fill: fill, track: track, total: fmt.Sprintf(" %3.0f%%", share)}
+	}
+	return rows
+}
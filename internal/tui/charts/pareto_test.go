@@ -0,0 +1,25 @@
+package charts
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPareto(t *testing.T) {
+	got := Pareto([]float64{6, 3, 1}, 4, 2)
+	want := []string{
+		"████  60%",
+		"██░░  90%",
+		"▒░░░ 100%",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Pareto() = %q, want %q", got, want)
+	}
+
+	if rows := Pareto(nil, 4, 0); len(rows) != 0 {
+		t.Errorf("Pareto(nil) = %q, want no rows", rows)
+	}
+	if rows := Pareto([]float64{1}, 0, 1); len(rows) != 0 {
+		t.Errorf("Pareto() without room = %q, want no rows", rows)
+	}
+}
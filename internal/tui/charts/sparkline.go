@@ -0,0 +1,39 @@
+package charts
+
+import (
+	"math"
+	"strings"
+)
+
+// sparkTicks are the heights of a sparkline cell, lowest first.
+var sparkTicks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline draws values as a one-row chart at most width cells wide, each
+// cell as high as its value between the lowest and the highest. A series
+// that never changes is drawn flat: along the bottom if it is all zero,
+// halfway up otherwise.
+func Sparkline(values []float64, width int) string {
+	cells := Resample(values, width)
+	if len(cells) == 0 {
+		return ""
+	}
+
+	lo, hi := bounds(cells)
+	var b strings.Builder
+	for _, v := range cells {
+		tick := len(sparkTicks) / 2
+		switch {
+		case hi > lo:
+			tick = int(math.Round((v - lo) / (hi - lo) * float64(len(sparkTicks)-1)))
+		case hi == 0:
+			tick = 0
+		}
+		b.WriteRune(sparkTicks[tick])
+	}
+	return b.String()
+}
+
+// Sparkline draws a styled sparkline.
+func (s Styles) Sparkline(values []float64, width int) string {
+	return s.Line.Render(Sparkline(values, width))
+}
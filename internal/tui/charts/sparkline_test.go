@@ -0,0 +1,43 @@
+package charts
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		width  int
+		want   string
+	}{
+		{"Rising", []float64{0, 1, 2, 3, 4, 5, 6, 7}, 20, "▁▂▃▄▅▆▇█"},
+		{"Falling", []float64{7, 0}, 20, "█▁"},
+		{"Averaged to width", []float64{0, 0, 7, 7}, 2, "▁█"},
+		{"Flat zero along the bottom", []float64{0, 0, 0}, 20, "▁▁▁"},
+		{"Flat halfway up", []float64{5, 5}, 20, "▅▅"},
+		{"Empty", nil, 20, ""},
+		{"No room", []float64{1, 2}, 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sparkline(tt.values, tt.width); got != tt.want {
+				t.Errorf("Sparkline(%v, %d) = %q, want %q", tt.values, tt.width, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSparkline_FitsWidth(t *testing.T) {
+	values := make([]float64, 90)
+	for i := range values {
+		values[i] = float64(i % 7)
+	}
+	for _, width := range []int{1, 7, 30, 89} {
+		if got := utf8.RuneCountInString(Sparkline(values, width)); got != width {
+			t.Errorf("Sparkline of 90 values in %d cells is %d cells wide", width, got)
+		}
+	}
+}
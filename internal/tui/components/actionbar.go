@@ -0,0 +1,88 @@
+package components
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/vtuos/vtuos/internal/i18n"
+)
+
+// Action is a single-key operation on the selected list row.
+type Action struct {
+	Key   string
+	Label string
+}
+
+// ActionBar shows the quick actions available for the selected row.
+type ActionBar struct {
+	actions []Action
+
+	// Styles
+	keyStyle   lipgloss.Style
+	labelStyle lipgloss.Style
+
+	locale i18n.Locale // Of the labels
+}
+
+// NewActionBar creates an action bar with the given actions.
+func NewActionBar(actions ...Action) *ActionBar {
+	return &ActionBar{
+		actions:    actions,
+		keyStyle:   lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#66FF66")),
+		labelStyle: lipgloss.NewStyle().Foreground(lipgloss.Color("#00AA00")),
+	}
+}
+
+// SetStyles sets the key and label styles.
+func (b *ActionBar) SetStyles(key, label lipgloss.Style) {
+	b.keyStyle = key
+	b.labelStyle = label
+}
+
+// SetLocale sets the language the labels are shown in.
+func (b *ActionBar) SetLocale(l i18n.Locale) {
+	b.locale = l
+}
+
+// Actions returns the actions in display order.
+func (b *ActionBar) Actions() []Action {
+	return b.actions
+}
+
+// Has returns true if key is one of the bar's actions.
+func (b *ActionBar) Has(key string) bool {
+	for _, a := range b.actions {
+		if a.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Render renders the bar within width, its labels translated. Labels are
+// dropped when the full form does not fit, leaving just the keys.
+func (b *ActionBar) Render(width int) string {
+	if len(b.actions) == 0 {
+		return ""
+	}
+
+	full := make([]string, len(b.actions))
+	plainLen := 0
+	for i, a := range b.actions {
+		label := b.locale.T(a.Label)
+		full[i] = b.keyStyle.Render("["+a.Key+"]") + b.labelStyle.Render(label)
+		plainLen += len(a.Key) + 2 + lipgloss.Width(label)
+	}
+	plainLen += 2 * (len(b.actions) - 1)
+
+	if width <= 0 || plainLen <= width {
+		return strings.Join(full, "  ")
+	}
+
+	keys := make([]string, len(b.actions))
+	for i, a := range b.actions {
+		keys[i] = b.keyStyle.Render("[" + a.Key + "]")
+	}
+	return strings.Join(keys, " ")
+}
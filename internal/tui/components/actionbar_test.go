@@ -0,0 +1,55 @@
+package components
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestActionBar_Has(t *testing.T) {
+	bar := NewActionBar(Action{Key: "d", Label: "Death"}, Action{Key: "h", Label: "Household"})
+
+	if !bar.Has("d") {
+		t.Error("Expected bar to have action d")
+	}
+	if bar.Has("x") {
+		t.Error("Expected bar not to have action x")
+	}
+}
+
+func TestActionBar_Render(t *testing.T) {
+	bar := NewActionBar(
+		Action{Key: "d", Label: "Death"},
+		Action{Key: "h", Label: "Household"},
+		Action{Key: "v", Label: "Vocation"},
+	)
+	bar.SetStyles(lipgloss.NewStyle(), lipgloss.NewStyle())
+
+	tests := []struct {
+		name  string
+		width int
+		want  string
+	}{
+		{"Wide shows labels", 80, "[d]Death  [h]Household  [v]Vocation"},
+		{"Exact fit shows labels", 35, "[d]Death  [h]Household  [v]Vocation"},
+		{"Narrow shows keys only", 20, "[d] [h] [v]"},
+		{"Zero width shows labels", 0, "[d]Death  [h]Household  [v]Vocation"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := bar.Render(tt.width)
+			if got != tt.want {
+				t.Errorf("Render(%d) = %q, want %q", tt.width, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestActionBar_RenderEmpty(t *testing.T) {
+	bar := NewActionBar()
+	if got := bar.Render(80); strings.TrimSpace(got) != "" {
+		t.Errorf("Expected empty render, got %q", got)
+	}
+}
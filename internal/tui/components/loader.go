@@ -0,0 +1,103 @@
+package components
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// LoadDebounce is how long a view waits for rapid page changes to settle
+// before loading the page reached.
+const LoadDebounce = 150 * time.Millisecond
+
+// SpinnerInterval is how often a loading spinner advances.
+const SpinnerInterval = 100 * time.Millisecond
+
+// spinnerFrames are the frames of the loading spinner.
+var spinnerFrames = []string{"|", "/", "-", "\\"}
+
+// loadSeq numbers loads across all loaders, so a result arriving after its
+// view was replaced never matches the new view's loads.
+var loadSeq atomic.Uint64
+
+// Loader tracks the background loads of a view. Every load requested is
+// numbered and supersedes those before it: a superseded load is cancelled
+// if running, and its result is dropped when it arrives, so the view only
+// ever shows the latest query.
+type Loader struct {
+	seq     uint64
+	loading bool
+	cancel  context.CancelFunc
+	frame   int
+	plain   bool // Screen reader mode: the spinner stands still
+}
+
+// Request marks a new load as wanted, cancelling any load in flight, and
+// returns its number.
+func (l *Loader) Request() uint64 {
+	if l.cancel != nil {
+		l.cancel()
+		l.cancel = nil
+	}
+	l.seq = loadSeq.Add(1)
+	l.loading = true
+	return l.seq
+}
+
+// Current reports whether seq is the latest load requested.
+func (l *Loader) Current(seq uint64) bool {
+	return seq == l.seq
+}
+
+// Begin records the cancel func of load seq as it starts running. It
+// reports false, cancelling the load, when seq has been superseded.
+func (l *Loader) Begin(seq uint64, cancel context.CancelFunc) bool {
+	if !l.Current(seq) {
+		cancel()
+		return false
+	}
+	l.cancel = cancel
+	return true
+}
+
+// Done reports whether the result of load seq is the latest and should be
+// shown, and if so ends the loading state.
+func (l *Loader) Done(seq uint64) bool {
+	if !l.Current(seq) {
+		return false
+	}
+	l.loading = false
+	l.cancel = nil
+	return true
+}
+
+// Stop cancels any load in flight and drops its result, for a view being
+// discarded.
+func (l *Loader) Stop() {
+	l.Request()
+	l.loading = false
+}
+
+// Loading reports whether the latest load has yet to finish.
+func (l *Loader) Loading() bool {
+	return l.loading
+}
+
+// SetPlain turns screen reader mode on or off for the spinner.
+func (l *Loader) SetPlain(on bool) {
+	l.plain = on
+}
+
+// Tick advances the spinner.
+func (l *Loader) Tick() {
+	l.frame = (l.frame + 1) % len(spinnerFrames)
+}
+
+// Spinner returns the current spinner frame, which stands still in screen
+// reader mode.
+func (l *Loader) Spinner() string {
+	if l.plain {
+		return spinnerFrames[2]
+	}
+	return spinnerFrames[l.frame]
+}
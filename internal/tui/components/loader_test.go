@@ -0,0 +1,77 @@
+package components
+
+import "testing"
+
+func TestLoader_LatestLoadWins(t *testing.T) {
+	var l Loader
+	if l.Loading() {
+		t.Fatal("Expected a new loader not to be loading")
+	}
+
+	cancelled := false
+	first := l.Request()
+	if !l.Begin(first, func() { cancelled = true }) {
+		t.Fatal("Expected the latest load to begin")
+	}
+
+	second := l.Request()
+	if !cancelled {
+		t.Error("Expected a superseded load to be cancelled")
+	}
+	if l.Done(first) {
+		t.Error("Expected a superseded result to be dropped")
+	}
+	if !l.Loading() {
+		t.Error("Expected the loader to keep loading until the latest result")
+	}
+
+	if !l.Done(second) {
+		t.Error("Expected the latest result to be shown")
+	}
+	if l.Loading() {
+		t.Error("Expected loading to end with the latest result")
+	}
+}
+
+func TestLoader_BeginSuperseded(t *testing.T) {
+	var l Loader
+	stale := l.Request()
+	l.Request()
+
+	cancelled := false
+	if l.Begin(stale, func() { cancelled = true }) {
+		t.Error("Expected a superseded load not to begin")
+	}
+	if !cancelled {
+		t.Error("Expected a superseded load to be cancelled as it begins")
+	}
+}
+
+func TestLoader_NumbersAcrossLoaders(t *testing.T) {
+	var old, replacement Loader
+	seq := old.Request()
+	old.Stop()
+	if old.Loading() {
+		t.Error("Expected a stopped loader not to be loading")
+	}
+
+	replacement.Request()
+	if replacement.Current(seq) {
+		t.Error("Expected a replaced view's load not to match the new view's")
+	}
+}
+
+func TestLoader_Spinner(t *testing.T) {
+	var l Loader
+	seen := map[string]bool{}
+	for range spinnerFrames {
+		seen[l.Spinner()] = true
+		l.Tick()
+	}
+	if len(seen) != len(spinnerFrames) {
+		t.Errorf("Expected %d distinct frames, got %d", len(spinnerFrames), len(seen))
+	}
+	if l.Spinner() != spinnerFrames[0] {
+		t.Errorf("Expected the spinner to wrap around, got %q", l.Spinner())
+	}
+}
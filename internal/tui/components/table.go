@@ -29,6 +29,8 @@ type Table struct {
 	offset      int
 	visibleRows int
 	focused     bool
+	marked      map[int]bool // Rows marked for a batch action
+	plain       bool         // Screen reader mode: the selected row is marked with a >
 
 	// Styles
 	headerStyle   lipgloss.Style
@@ -61,9 +63,10 @@ func NewTable(columns []Column) *Table {
 	}
 }
 
-// SetRows sets the table data.
+// SetRows sets the table data, clearing any marks.
 func (t *Table) SetRows(rows [][]string) {
 	t.rows = rows
+	t.marked = nil
 }
 
 // SetPagination sets pagination info.
@@ -78,6 +81,12 @@ func (t *Table) SetVisibleRows(n int) {
 	t.visibleRows = n
 }
 
+// SetPlain turns screen reader mode on or off, in which the selected row
+// is told by a > rather than only by color.
+func (t *Table) SetPlain(on bool) {
+	t.plain = on
+}
+
 // SetStyles sets the table styles.
 func (t *Table) SetStyles(header, row, rowAlt, selected, border lipgloss.Style) {
 	t.headerStyle = header
@@ -105,6 +114,49 @@ func (t *Table) SelectedRow() []string {
 	return nil
 }
 
+// ToggleMark marks the selected row for a batch action, or unmarks it.
+func (t *Table) ToggleMark() {
+	if t.selected < 0 || t.selected >= len(t.rows) {
+		return
+	}
+	if t.marked[t.selected] {
+		delete(t.marked, t.selected)
+		return
+	}
+	if t.marked == nil {
+		t.marked = make(map[int]bool)
+	}
+	t.marked[t.selected] = true
+}
+
+// MarkAll marks every row, or unmarks them all when every row is marked.
+func (t *Table) MarkAll() {
+	if len(t.marked) == len(t.rows) {
+		t.marked = nil
+		return
+	}
+	t.marked = make(map[int]bool, len(t.rows))
+	for i := range t.rows {
+		t.marked[i] = true
+	}
+}
+
+// ClearMarks unmarks every row.
+func (t *Table) ClearMarks() {
+	t.marked = nil
+}
+
+// Marked returns the indexes of the marked rows in order.
+func (t *Table) Marked() []int {
+	var marked []int
+	for i := range t.rows {
+		if t.marked[i] {
+			marked = append(marked, i)
+		}
+	}
+	return marked
+}
+
 // MoveUp moves the selection up.
 func (t *Table) MoveUp() {
 	if t.selected > 0 {
@@ -149,6 +201,20 @@ func (t *Table) PageDown() {
 	}
 }
 
+// Select moves the selection to row i, scrolling it into view.
+func (t *Table) Select(i int) {
+	if i < 0 || i >= len(t.rows) {
+		return
+	}
+	t.selected = i
+	if t.selected < t.offset {
+		t.offset = t.selected
+	}
+	if t.selected >= t.offset+t.visibleRows {
+		t.offset = t.selected - t.visibleRows + 1
+	}
+}
+
 // GoToTop goes to the first row.
 func (t *Table) GoToTop() {
 	t.selected = 0
@@ -305,7 +371,17 @@ func (t *Table) RenderResponsive(width int) string {
 			style = t.rowStyle
 		}
 
-		b.WriteString(t.renderRowResponsive(t.rows[i], style, isSelected, colWidths))
+		row := t.renderRowResponsive(t.rows[i], style, isSelected, colWidths)
+		switch {
+		case isSelected && t.plain:
+			// Without color the selected row is told by a >, which takes
+			// the row's leading padding
+			row = style.Render(">") + row[1:]
+		case t.marked[i]:
+			// The mark takes the row's leading padding
+			row = style.Render("*") + row[1:]
+		}
+		b.WriteString(row)
 		b.WriteString("\n")
 	}
 
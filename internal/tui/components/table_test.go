@@ -320,3 +320,52 @@ func TestTable_SetPagination(t *testing.T) {
 		t.Error("Expected 'Page 3/10' in output")
 	}
 }
+
+func TestTable_Marks(t *testing.T) {
+	table := NewTable([]Column{{Title: "ID", Width: 5}})
+	table.SetRows([][]string{{"1"}, {"2"}, {"3"}})
+
+	table.ToggleMark()
+	table.MoveDown()
+	table.MoveDown()
+	table.ToggleMark()
+	if got := table.Marked(); len(got) != 2 || got[0] != 0 || got[1] != 2 {
+		t.Errorf("Expected rows 0 and 2 marked, got %v", got)
+	}
+	if !strings.Contains(table.Render(), "*1") {
+		t.Error("Expected a marked row to show its mark")
+	}
+
+	table.ToggleMark()
+	if got := table.Marked(); len(got) != 1 {
+		t.Errorf("Expected toggling to unmark the row, got %v", got)
+	}
+
+	table.MarkAll()
+	if got := table.Marked(); len(got) != 3 {
+		t.Errorf("Expected every row marked, got %v", got)
+	}
+	table.MarkAll()
+	if got := table.Marked(); len(got) != 0 {
+		t.Errorf("Expected marking all again to unmark every row, got %v", got)
+	}
+
+	table.MarkAll()
+	table.SetRows([][]string{{"4"}})
+	if got := table.Marked(); len(got) != 0 {
+		t.Errorf("Expected new rows to clear the marks, got %v", got)
+	}
+}
+
+func TestTable_PlainMarksSelectedRow(t *testing.T) {
+	table := NewTable([]Column{{Title: "Name", Width: 10}})
+	table.SetRows([][]string{{"Alice"}, {"Bob"}})
+	table.Focus(true)
+	table.MoveDown()
+
+	table.SetPlain(true)
+	lines := strings.Split(table.RenderResponsive(40), "\n")
+	if !strings.HasPrefix(lines[3], ">") || strings.HasPrefix(lines[2], ">") {
+		t.Errorf("rows = %q, want Bob marked selected", lines[2:4])
+	}
+}
@@ -0,0 +1,160 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/services/resources"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// consumptionPeriods are the periods, in days, the consumption analytics
+// screen cycles through with [ and ]. The first is shown on opening.
+var consumptionPeriods = []int{30, 7, 90}
+
+// consumptionLowCoverage is the share of its ration targets below which a
+// ration class shows as an error rather than a warning.
+const consumptionLowCoverage = 0.75
+
+// consumptionMsg carries the consumption report for the analytics screen.
+type consumptionMsg struct {
+	report *resources.ConsumptionReport
+	err    error
+}
+
+// openConsumption switches to the consumption analytics screen.
+func (a *App) openConsumption() tea.Cmd {
+	a.currentModule = ModuleConsumption
+	return a.loadConsumption()
+}
+
+// loadConsumption loads the consumption report of the selected period up to
+// today.
+func (a *App) loadConsumption() tea.Cmd {
+	days := consumptionPeriods[a.consumptionPeriod]
+	return func() tea.Msg {
+		ctx, cancel := a.reportOperation()
+		defer cancel()
+		report, err := a.resourceSvc.ConsumptionReport(ctx, a.clock.Now(), days)
+		return consumptionMsg{report: report, err: err}
+	}
+}
+
+// handleConsumption stores the loaded consumption report.
+func (a *App) handleConsumption(msg consumptionMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		a.AddError("Failed to load consumption", msg.err)
+		return a, nil
+	}
+	a.consumption = msg.report
+	return a, nil
+}
+
+// handleConsumptionKeys handles key presses on the consumption analytics
+// screen.
+func (a *App) handleConsumptionKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "]", "right":
+		a.consumptionPeriod = (a.consumptionPeriod + 1) % len(consumptionPeriods)
+		return a, a.loadConsumption()
+	case "[", "left":
+		a.consumptionPeriod = (a.consumptionPeriod + len(consumptionPeriods) - 1) % len(consumptionPeriods)
+		return a, a.loadConsumption()
+	case "r":
+		return a, a.loadConsumption()
+	}
+	return a, nil
+}
+
+// renderConsumption renders the consumption analytics screen: the
+// households drawing the most rations, per-capita consumption of each ration
+// class against its targets, and consumption by department.
+func (a *App) renderConsumption() string {
+	var b strings.Builder
+	b.WriteString(a.theme.Title.Render("═══ CONSUMPTION ANALYTICS ═══"))
+	b.WriteString("\n\n")
+
+	report := a.consumption
+	if report == nil {
+		b.WriteString(a.theme.Muted.Render("  Consumption loading..."))
+		return b.String()
+	}
+	b.WriteString(a.theme.Muted.Render(fmt.Sprintf("  Rations drawn %s to %s (%d days)",
+		util.FormatDay(report.From), util.FormatDay(report.To.AddDate(0, 0, -1)),
+		consumptionPeriods[a.consumptionPeriod])))
+	b.WriteString("\n\n")
+
+	if len(report.Classes) == 0 {
+		b.WriteString(a.theme.Muted.Render("  No rations drawn in the period"))
+		b.WriteString("\n\n")
+		b.WriteString(a.theme.Muted.Render("  " + a.theme.Locale.T("[/] period  r reload  Esc back")))
+		return b.String()
+	}
+	width := a.width - 4
+
+	b.WriteString(a.theme.Subtitle.Render("TOP CONSUMERS"))
+	b.WriteString("\n")
+	header := fmt.Sprintf("  %-12s %-16s %8s %12s %9s %9s %7s", "HOUSEHOLD", "CLASS", "RES-DAYS", "KCAL", "WATER L", "KCAL/CAP", "L/CAP")
+	b.WriteString(a.theme.TableHeader.Render(Truncate(header, a.width-2)))
+	b.WriteString("\n")
+	for _, h := range report.Households {
+		line := fmt.Sprintf("%-12s %-16s %8d %12.0f %9.1f %9.0f %7.2f",
+			Truncate(h.Designation, 12), h.RationClass, h.ResidentDays, h.Calories, h.WaterL,
+			h.CaloriesPerCapita(), h.WaterLPerCapita())
+		b.WriteString("  " + a.theme.Base.Render(Truncate(line, width)))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	b.WriteString(a.theme.Subtitle.Render("PER CAPITA BY RATION CLASS"))
+	b.WriteString("\n")
+	header = fmt.Sprintf("  %-16s %8s %9s %7s %6s %7s %7s %6s", "CLASS", "RES-DAYS", "KCAL/DAY", "TARGET", "COVER", "L/DAY", "TARGET", "COVER")
+	b.WriteString(a.theme.TableHeader.Render(Truncate(header, a.width-2)))
+	b.WriteString("\n")
+	for _, c := range report.Classes {
+		line := fmt.Sprintf("%-16s %8d %9.0f %7d %5.0f%% %7.2f %7.2f %5.0f%%",
+			c.RationClass, c.ResidentDays, c.CaloriesPerCapita(), c.CalorieTarget, c.CalorieCoverage()*100,
+			c.WaterLPerCapita(), c.WaterTargetL, c.WaterCoverage()*100)
+		cover := min(c.CalorieCoverage(), c.WaterCoverage())
+		switch {
+		case cover < consumptionLowCoverage:
+			b.WriteString("  " + a.theme.Error.Render(Truncate(line, width)))
+		case cover < 1-models.QuantityEpsilon:
+			b.WriteString("  " + a.theme.Warning.Render(Truncate(line, width)))
+		default:
+			b.WriteString("  " + a.theme.Base.Render(Truncate(line, width)))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	b.WriteString(a.theme.Subtitle.Render("BY DEPARTMENT"))
+	b.WriteString("\n")
+	header = fmt.Sprintf("  %-16s %8s %12s %9s %6s", "DEPARTMENT", "RES-DAYS", "KCAL", "WATER L", "SHARE")
+	b.WriteString(a.theme.TableHeader.Render(Truncate(header, a.width-2)))
+	b.WriteString("\n")
+	var total float64
+	for _, d := range report.Departments {
+		total += d.Calories
+	}
+	for _, d := range report.Departments {
+		name := string(d.Department)
+		if name == "" {
+			name = "(no vocation)"
+		}
+		share := 0.0
+		if total > 0 {
+			share = d.Calories / total * 100
+		}
+		line := fmt.Sprintf("%-16s %8d %12.0f %9.1f %5.1f%%", name, d.ResidentDays, d.Calories, d.WaterL, share)
+		b.WriteString("  " + a.theme.Base.Render(Truncate(line, width)))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(a.theme.Muted.Render("  " + a.theme.Locale.T("[/] period  r reload  Esc back")))
+	return b.String()
+}
@@ -0,0 +1,54 @@
+package tui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// requestCountersign asks for a second operator to confirm an action held
+// for two-person authorization: their registry number, then their PIN.
+// The action runs again once they give it. Only a signed-on operator may
+// request a confirmation, as the audit log records who asked.
+func (a *App) requestCountersign(action *quickAction, err error) {
+	if a.operator == nil {
+		a.AddError("Sign on to have this action confirmed", err)
+		return
+	}
+	a.AddAlert(AlertWarning, errorMessage(err))
+	a.quickAction = &quickAction{
+		kind:   quickActionCountersign,
+		prompt: "Second operator's registry number: ",
+		held:   action,
+	}
+}
+
+// countersign takes the confirming operator's registry number, then their
+// PIN, and runs the held action with the countersign.
+func (a *App) countersign(action *quickAction) tea.Cmd {
+	if !action.secret {
+		regNum := strings.ToUpper(strings.TrimSpace(action.input))
+		a.quickAction = &quickAction{
+			kind:       quickActionCountersign,
+			prompt:     "PIN of " + regNum + ": ",
+			targetName: regNum,
+			secret:     true,
+			held:       action.held,
+		}
+		return nil
+	}
+	if a.operator == nil {
+		a.AddAlert(AlertWarning, "Operator signed off; action not confirmed")
+		return nil
+	}
+
+	retry := *action.held
+	retry.countersign = &util.Countersign{
+		RequestedBy: a.operator.RegistryNumber,
+		By:          action.targetName,
+		PIN:         action.input,
+	}
+	return a.runQuickAction(&retry)
+}
@@ -0,0 +1,129 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vtuos/vtuos/internal/config"
+)
+
+// dashboardAlertRows is how many of the latest alerts the dashboard's
+// alerts panel lists.
+const dashboardAlertRows = 5
+
+// dashboardPanelNames are the names the settings screen shows for the
+// dashboard panels.
+var dashboardPanelNames = map[config.DashboardPanel]string{
+	config.DashboardPanelPopulation: "Population",
+	config.DashboardPanelFacilities: "Critical systems",
+	config.DashboardPanelResources:  "Resource runway",
+	config.DashboardPanelSimulation: "Simulation",
+	config.DashboardPanelEfficiency: "System efficiency",
+	config.DashboardPanelAlerts:     "Alerts",
+	config.DashboardPanelShortages:  "Shortages",
+}
+
+// dashboardFullWidth reports whether a dashboard panel spans the screen on
+// a row of its own rather than sharing one.
+func dashboardFullWidth(panel config.DashboardPanel) bool {
+	return panel == config.DashboardPanelEfficiency
+}
+
+// renderDashboardPanel renders one panel of the dashboard.
+func (a *App) renderDashboardPanel(panel config.DashboardPanel, totalWidth int, bp LayoutBreakpoint) string {
+	switch panel {
+	case config.DashboardPanelPopulation:
+		return a.renderPopulationPanel(totalWidth, bp)
+	case config.DashboardPanelFacilities:
+		return a.renderSystemsPanel(totalWidth, bp)
+	case config.DashboardPanelResources:
+		return a.renderResourcesPanel(totalWidth, bp)
+	case config.DashboardPanelSimulation:
+		return a.renderSimulationPanel(totalWidth, bp)
+	case config.DashboardPanelEfficiency:
+		return a.renderEfficiencyPanel(totalWidth)
+	case config.DashboardPanelAlerts:
+		return a.renderAlertsPanel(totalWidth, bp)
+	case config.DashboardPanelShortages:
+		return a.renderShortagesPanel(totalWidth, bp)
+	default:
+		return ""
+	}
+}
+
+// renderAlertsPanel renders the latest alerts for the dashboard, most
+// recent first.
+func (a *App) renderAlertsPanel(totalWidth int, bp LayoutBreakpoint) string {
+	var b strings.Builder
+	b.WriteString(a.theme.Subtitle.Render("ALERTS"))
+	b.WriteString("\n")
+
+	if len(a.alerts) == 0 {
+		b.WriteString(a.theme.Muted.Render("  All systems operational"))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	width := dashboardPanelWidth(totalWidth, bp) - 2
+	for i, alert := range a.alerts {
+		if i == dashboardAlertRows {
+			b.WriteString(a.theme.Muted.Render(fmt.Sprintf("  ... %d more", len(a.alerts)-i)))
+			b.WriteString("\n")
+			break
+		}
+		message := alert.Message
+		if a.theme.Plain {
+			// Spell out the level the line's color shows
+			message = alertMessage(alert, a.theme.Locale)
+		}
+		line := Truncate(alert.Time.Format("15:04")+" "+message, width)
+		switch alert.Level {
+		case AlertCritical:
+			b.WriteString("  " + a.theme.Error.Render(line))
+		case AlertWarning:
+			b.WriteString("  " + a.theme.Warning.Render(line))
+		default:
+			b.WriteString("  " + a.theme.Base.Render(line))
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// renderShortagesPanel renders the items below their minimum stock for the
+// dashboard, each with the production run queued for it or the quantity to
+// procure. Items out of stock show as critical.
+func (a *App) renderShortagesPanel(totalWidth int, bp LayoutBreakpoint) string {
+	var b strings.Builder
+	b.WriteString(a.theme.Subtitle.Render("SHORTAGES"))
+	b.WriteString("\n")
+
+	if len(a.shortages) == 0 {
+		b.WriteString(a.theme.Muted.Render("  No items below minimum stock"))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	width := dashboardPanelWidth(totalWidth, bp) - 2
+	for _, shortage := range a.shortages {
+		item := shortage.Item
+		action := fmt.Sprintf("procure %.2f", shortage.Shortfall)
+		if shortage.Run != nil {
+			action = fmt.Sprintf("run queued: %.2f", shortage.Run.Quantity)
+		}
+		line := fmt.Sprintf("%-10s %.2f/%.2f %s  %s", item.ItemCode, shortage.Available, *item.MinStock,
+			item.UnitOfMeasure, action)
+		if shortage.Critical() {
+			if a.theme.Plain {
+				line = "OUT " + line
+			}
+			b.WriteString("  " + a.theme.Error.Render(Truncate(line, width)))
+		} else {
+			b.WriteString("  " + a.theme.Warning.Render(Truncate(line, width)))
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
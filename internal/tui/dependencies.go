@@ -0,0 +1,156 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/services/facilities"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// dependenciesMsg carries the facility systems' dependencies for the
+// dependency view.
+type dependenciesMsg struct {
+	dependencies *facilities.DependencyMap
+	err          error
+}
+
+// openDependencies switches to the dependency view of the facilities module.
+func (a *App) openDependencies() tea.Cmd {
+	a.currentModule = ModuleDependencies
+	return a.loadDependencies()
+}
+
+// loadDependencies loads the systems with their dependencies and cascades.
+func (a *App) loadDependencies() tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := a.reportOperation()
+		defer cancel()
+		m, err := a.facilitySvc.Dependencies(ctx)
+		return dependenciesMsg{dependencies: m, err: err}
+	}
+}
+
+// handleDependencies stores the loaded dependencies, keeping the selection
+// in range.
+func (a *App) handleDependencies(msg dependenciesMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		a.AddError("Failed to load dependencies", msg.err)
+		return a, nil
+	}
+	a.dependencies = msg.dependencies
+	a.dependencyIndex = max(min(a.dependencyIndex, len(a.dependencies.Systems)-1), 0)
+	return a, nil
+}
+
+// handleDependencyKeys handles key presses on the dependency view.
+func (a *App) handleDependencyKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		if a.dependencyIndex > 0 {
+			a.dependencyIndex--
+		}
+	case "down", "j":
+		if a.dependencies != nil && a.dependencyIndex < len(a.dependencies.Systems)-1 {
+			a.dependencyIndex++
+		}
+	case "r":
+		return a, a.loadDependencies()
+	}
+	return a, nil
+}
+
+// systemStatusStyle returns the style a system's status is shown in.
+func (a *App) systemStatusStyle(status models.FacilityStatus) lipgloss.Style {
+	switch status {
+	case models.FacilityStatusOperational:
+		return a.theme.Success
+	case models.FacilityStatusDegraded, models.FacilityStatusMaintenance:
+		return a.theme.Warning
+	case models.FacilityStatusRetired:
+		return a.theme.Muted
+	default:
+		return a.theme.Error
+	}
+}
+
+// renderDependencies renders the dependency view: every system with its
+// status and, for a degraded one, the root cause, then what the selected
+// system depends on and what depends on it.
+func (a *App) renderDependencies() string {
+	var b strings.Builder
+	b.WriteString(a.theme.Title.Render("═══ SYSTEM DEPENDENCIES ═══"))
+	b.WriteString("\n\n")
+
+	m := a.dependencies
+	if m == nil {
+		b.WriteString(a.theme.Muted.Render("  Dependencies loading..."))
+		return b.String()
+	}
+	if len(m.Systems) == 0 {
+		b.WriteString(a.theme.Muted.Render("  No facility systems"))
+		return b.String()
+	}
+	width := a.width - 4
+
+	header := fmt.Sprintf("  %-16s %-26s %-11s %5s  %s", "SYSTEM", "NAME", "STATUS", "EFF", "ROOT CAUSE")
+	b.WriteString(a.theme.TableHeader.Render(Truncate(header, a.width-2)))
+	b.WriteString("\n")
+	for i, sys := range m.Systems {
+		cause := ""
+		if c := m.Cascades[sys.ID]; c != nil {
+			cause = m.System(c.RootID).SystemCode
+		}
+		line := fmt.Sprintf("%-16s %-26s %-11s %4.0f%%  %s",
+			sys.SystemCode, Truncate(sys.Name, 26), sys.Status, sys.EfficiencyPercent, cause)
+		line = Truncate(line, width)
+		if i == a.dependencyIndex {
+			b.WriteString(a.theme.Selected.Render("> " + line))
+		} else {
+			b.WriteString("  " + a.systemStatusStyle(sys.Status).Render(line))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	sys := m.Systems[a.dependencyIndex]
+	b.WriteString(a.theme.Subtitle.Render(sys.SystemCode + " " + sys.Name))
+	b.WriteString("\n")
+	if c := m.Cascades[sys.ID]; c != nil {
+		root := m.System(c.RootID)
+		line := fmt.Sprintf("  Degraded by %s %s (%s) since %s; restores to %s at %.0f%%",
+			root.SystemCode, root.Name, root.Status, util.FormatDate(c.At), c.StatusBefore, c.EfficiencyBefore)
+		b.WriteString(a.theme.Warning.Render(Truncate(line, width)))
+		b.WriteString("\n")
+	}
+	b.WriteString(a.renderDependencyList("Depends on", m, m.Graph.Parents(sys.ID)))
+	b.WriteString(a.renderDependencyList("Depended on by", m, m.Graph.Dependents(sys.ID)))
+
+	b.WriteString("\n")
+	b.WriteString(a.theme.Muted.Render("  " + a.theme.Locale.T("↑/↓ select  r reload  Esc back")))
+	return b.String()
+}
+
+// renderDependencyList renders a labelled list of systems with their
+// status, or "none".
+func (a *App) renderDependencyList(label string, m *facilities.DependencyMap, ids []string) string {
+	var b strings.Builder
+	b.WriteString(a.theme.Muted.Render(fmt.Sprintf("  %-15s", label+":")))
+	if len(ids) == 0 {
+		b.WriteString(a.theme.Muted.Render("none"))
+	}
+	for i, id := range ids {
+		sys := m.System(id)
+		if i > 0 {
+			b.WriteString(a.theme.Muted.Render(", "))
+		}
+		b.WriteString(a.theme.Base.Render(sys.SystemCode + " "))
+		b.WriteString(a.systemStatusStyle(sys.Status).Render(string(sys.Status)))
+	}
+	b.WriteString("\n")
+	return b.String()
+}
@@ -0,0 +1,335 @@
+package tui
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/vtuos/vtuos/internal/database"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// systemDiagnostics is the health of the database file, its schema and
+// backups, and of the process, as last checked.
+type systemDiagnostics struct {
+	database   *database.DatabaseDiagnostics
+	migrations []database.Migration
+	backup     string    // Newest backup, empty if none
+	backupAt   time.Time // When it was written
+	memory     runtime.MemStats
+	goroutines int
+	checkedAt  time.Time
+}
+
+// diagnosticsMsg carries a fresh system check.
+type diagnosticsMsg struct {
+	diagnostics *systemDiagnostics
+	err         error
+}
+
+// openDiagnostics switches to the diagnostics screen and checks the
+// system.
+func (a *App) openDiagnostics() tea.Cmd {
+	a.currentModule = ModuleDiagnostics
+	a.diagnosticsScroll = 0
+	return a.loadDiagnostics()
+}
+
+// loadDiagnostics checks the database file, running SQLite's quick check,
+// the migrations applied, the newest backup and the process's memory.
+func (a *App) loadDiagnostics() tea.Cmd {
+	db := a.db
+	return func() tea.Msg {
+		ctx, cancel := a.operation()
+		defer cancel()
+		if db == nil {
+			return diagnosticsMsg{err: fmt.Errorf("no database open")}
+		}
+
+		diag := &systemDiagnostics{checkedAt: time.Now()}
+		var err error
+		if diag.database, err = database.DiagnoseDatabase(db.Path()); err != nil {
+			return diagnosticsMsg{err: err}
+		}
+		migrator, err := database.NewMigrator(db)
+		if err != nil {
+			return diagnosticsMsg{err: err}
+		}
+		if diag.migrations, err = migrator.Status(ctx); err != nil {
+			return diagnosticsMsg{err: err}
+		}
+		if diag.backup, diag.backupAt, err = db.LatestBackup(); err != nil {
+			return diagnosticsMsg{err: err}
+		}
+		runtime.ReadMemStats(&diag.memory)
+		diag.goroutines = runtime.NumGoroutine()
+		return diagnosticsMsg{diagnostics: diag}
+	}
+}
+
+// handleDiagnosticsKeys handles key presses in the diagnostics screen: Tab
+// switches between the system check and the query timings.
+func (a *App) handleDiagnosticsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "tab":
+		a.showQueryTimings = !a.showQueryTimings
+		a.diagnosticsScroll = 0
+	case "up", "k":
+		if a.showQueryTimings && a.diagnosticsScroll > 0 {
+			a.diagnosticsScroll--
+		}
+	case "down", "j":
+		if a.showQueryTimings && a.db != nil && a.diagnosticsScroll < len(a.db.QueryStats())-1 {
+			a.diagnosticsScroll++
+		}
+	case "r":
+		if !a.showQueryTimings {
+			return a, a.loadDiagnostics()
+		}
+		if a.db != nil {
+			a.db.ResetQueryStats()
+			a.diagnosticsScroll = 0
+			a.AddAlert(AlertInfo, "Query counters reset")
+		}
+	}
+	return a, nil
+}
+
+// renderDiagnostics renders the diagnostics screen: the system check, or
+// the write queue and query timings.
+func (a *App) renderDiagnostics() string {
+	var b strings.Builder
+	b.WriteString(a.theme.Title.Render("═══ " + a.theme.Locale.T("SYSTEM DIAGNOSTICS") + " ═══"))
+	b.WriteString("\n\n")
+
+	system, queries := a.theme.Locale.T("System"), a.theme.Locale.T("Queries")
+	if a.showQueryTimings {
+		system, queries = a.theme.Label.Render(" "+system+" "), a.theme.Accent.Render("["+queries+"]")
+	} else {
+		system, queries = a.theme.Accent.Render("["+system+"]"), a.theme.Label.Render(" "+queries+" ")
+	}
+	b.WriteString(system + " " + queries + a.theme.Label.Render("  (Tab)") + "\n\n")
+
+	if a.db == nil {
+		b.WriteString(a.theme.Muted.Render("  No database open"))
+		return b.String()
+	}
+	if a.showQueryTimings {
+		b.WriteString(a.renderQueryTimings())
+		b.WriteString("\n")
+		b.WriteString(a.theme.Muted.Render("  " + a.theme.Locale.T("Tab system  ↑/↓ scroll  r reset counters  Esc back")))
+		return b.String()
+	}
+	b.WriteString(a.renderSystemCheck())
+	b.WriteString("\n")
+	b.WriteString(a.theme.Muted.Render("  " + a.theme.Locale.T("Tab queries  r check again  Esc back")))
+	return b.String()
+}
+
+// renderSystemCheck renders the database file, its pages and quick check,
+// the migrations applied, backup freshness and the process's memory.
+func (a *App) renderSystemCheck() string {
+	var b strings.Builder
+	diag := a.diagnostics
+	if diag == nil {
+		b.WriteString(a.theme.Muted.Render("  Checking..."))
+		b.WriteString("\n")
+		return b.String()
+	}
+	row := func(label, value string, warn bool) {
+		style := a.theme.Value
+		if warn {
+			style = a.theme.Warning
+		}
+		b.WriteString(fmt.Sprintf("  %-14s %s\n", label+":", style.Render(Truncate(value, a.width-20))))
+	}
+
+	db := diag.database
+	b.WriteString(a.theme.Subtitle.Render("DATABASE"))
+	b.WriteString("\n")
+	row("File", db.Path, !db.Exists)
+	if !db.Exists {
+		row("Status", "not found on disk", true)
+	} else {
+		row("Size", fmt.Sprintf("%s, modified %s", formatSize(db.SizeBytes), util.FormatDateTime(db.ModTime)), false)
+		wal := "none"
+		if db.WALExists {
+			wal = formatSize(db.WALSizeBytes)
+		}
+		row("Journal", fmt.Sprintf("%s, WAL %s", db.JournalMode, wal), db.JournalMode != "wal")
+		free := 0.0
+		if db.PageCount > 0 {
+			free = float64(db.FreelistCount) / float64(db.PageCount) * 100
+		}
+		row("Pages", fmt.Sprintf("%d of %s, %d free (%.1f%%)", db.PageCount, formatSize(int64(db.PageSize)),
+			db.FreelistCount, free), free > 25)
+		if db.OpenError != "" {
+			row("Quick check", "could not open: "+db.OpenError, true)
+		} else {
+			row("Quick check", db.QuickCheck, db.QuickCheck != "ok")
+		}
+		row("SQLite", db.SQLiteVersion, false)
+	}
+	b.WriteString("\n")
+
+	b.WriteString(a.theme.Subtitle.Render("SCHEMA"))
+	b.WriteString("\n")
+	var applied, pending, drifted, version int
+	for _, mig := range diag.migrations {
+		switch {
+		case mig.Drifted():
+			drifted++
+			applied++
+		case mig.Applied:
+			applied++
+		default:
+			pending++
+		}
+		if mig.Applied {
+			version = mig.Version
+		}
+	}
+	row("Version", fmt.Sprintf("%d, %d of %d migrations applied", version, applied, len(diag.migrations)), false)
+	row("Pending", fmt.Sprintf("%d", pending), pending > 0)
+	if drifted > 0 {
+		row("Drifted", fmt.Sprintf("%d, see `vtuos migrate status`", drifted), true)
+	}
+	b.WriteString("\n")
+
+	b.WriteString(a.theme.Subtitle.Render("BACKUPS"))
+	b.WriteString("\n")
+	interval := time.Duration(a.config.Database.BackupIntervalHours) * time.Hour
+	switch {
+	case diag.backup == "":
+		row("Latest", "none taken", !a.readOnly)
+	default:
+		age := diag.checkedAt.Sub(diag.backupAt)
+		row("Latest", fmt.Sprintf("%s, %s ago", util.FormatDateTime(diag.backupAt), formatAge(age)),
+			interval > 0 && age > 2*interval)
+	}
+	if interval > 0 && !a.readOnly {
+		row("Interval", fmt.Sprintf("every %dh", a.config.Database.BackupIntervalHours), false)
+	} else {
+		row("Interval", "scheduled backups off", false)
+	}
+	b.WriteString("\n")
+
+	b.WriteString(a.theme.Subtitle.Render("PROCESS"))
+	b.WriteString("\n")
+	mem := diag.memory
+	row("Heap", fmt.Sprintf("%s in use, %s from the system", formatSize(int64(mem.HeapAlloc)), formatSize(int64(mem.Sys))), false)
+	row("GC", fmt.Sprintf("%d cycles, %d goroutines", mem.NumGC, diag.goroutines), false)
+	row("Checked", util.FormatDateTime(diag.checkedAt), false)
+	return b.String()
+}
+
+// renderQueryTimings renders the write queue, and the statements run on
+// the database since it opened or the counters were reset, most total time
+// first, with their slow runs highlighted.
+func (a *App) renderQueryTimings() string {
+	var b strings.Builder
+	queue := a.db.WriteQueueStats()
+	b.WriteString(a.theme.Subtitle.Render("WRITE QUEUE"))
+	b.WriteString("\n")
+	style := a.theme.Value
+	if queue.Depth > 0 {
+		style = a.theme.Warning
+	}
+	var meanWait time.Duration
+	if queue.Writes > 0 {
+		meanWait = queue.TotalWait / time.Duration(queue.Writes)
+	}
+	b.WriteString(fmt.Sprintf("  Queued:  %s\n", style.Render(fmt.Sprintf("%d now, peak %d", queue.Depth, queue.MaxDepth))))
+	b.WriteString(fmt.Sprintf("  Writes:  %s\n", a.theme.Value.Render(fmt.Sprintf("%d, mean wait %s, longest %s",
+		queue.Writes, formatLatency(meanWait), formatLatency(queue.MaxWait)))))
+	b.WriteString("\n")
+
+	stats := a.db.QueryStats()
+	var calls, slow int64
+	for _, s := range stats {
+		calls += s.Calls
+		slow += s.Slow
+	}
+	b.WriteString(a.theme.Subtitle.Render("QUERIES"))
+	threshold := a.theme.Locale.T("slow query log off")
+	if ms := a.config.Database.SlowQueryMS; ms > 0 {
+		threshold = a.theme.Locale.T("slow from %dms", ms)
+	}
+	summary := []string{
+		a.theme.Locale.N(len(stats), "%d statement", "%d statements"),
+		a.theme.Locale.N(int(calls), "%d run", "%d runs"),
+		a.theme.Locale.T("%d slow", slow),
+		threshold,
+	}
+	b.WriteString(a.theme.Muted.Render("  " + strings.Join(summary, ", ")))
+	b.WriteString("\n")
+
+	if len(stats) == 0 {
+		b.WriteString(a.theme.Muted.Render("  No queries run"))
+		b.WriteString("\n")
+		return b.String()
+	}
+	header := fmt.Sprintf("  %8s %6s %9s %9s %9s  %s", "CALLS", "SLOW", "TOTAL", "MEAN", "MAX", "QUERY")
+	b.WriteString(a.theme.TableHeader.Render(Truncate(header, a.width-2)))
+	b.WriteString("\n")
+
+	rows := ContentHeight(a.height, chromeLines) - 12
+	if rows < 1 {
+		rows = 1
+	}
+	start := min(a.diagnosticsScroll, len(stats)-1)
+	for i := start; i < len(stats) && i < start+rows; i++ {
+		s := stats[i]
+		line := fmt.Sprintf("%8d %6d %9s %9s %9s  %s", s.Calls, s.Slow, formatLatency(s.Total),
+			formatLatency(s.Mean()), formatLatency(s.Max), s.Query)
+		line = Truncate(line, a.width-4)
+		if s.Slow > 0 {
+			b.WriteString("  " + a.theme.Warning.Render(line))
+		} else {
+			b.WriteString("  " + a.theme.Base.Render(line))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// formatLatency formats a duration to a precision that suits its size.
+func formatLatency(d time.Duration) string {
+	switch {
+	case d >= time.Second:
+		return d.Round(time.Millisecond).String()
+	case d >= time.Millisecond:
+		return d.Round(10 * time.Microsecond).String()
+	default:
+		return d.Round(time.Microsecond).String()
+	}
+}
+
+// formatSize formats a byte count, e.g. "2.4 MB".
+func formatSize(n int64) string {
+	switch {
+	case n >= 1<<30:
+		return fmt.Sprintf("%.1f GB", float64(n)/(1<<30))
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
+
+// formatAge formats how long ago something happened in its largest unit.
+func formatAge(d time.Duration) string {
+	switch {
+	case d >= 48*time.Hour:
+		return fmt.Sprintf("%d days", int(d/(24*time.Hour)))
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh", int(d/time.Hour))
+	default:
+		return fmt.Sprintf("%dm", int(d/time.Minute))
+	}
+}
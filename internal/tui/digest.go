@@ -0,0 +1,175 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/vtuos/vtuos/internal/services/governance"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// digestMsg carries a loaded daily digest.
+type digestMsg struct {
+	digest *governance.Digest
+	err    error
+}
+
+// openDigest switches to the digest screen for the current vault day.
+func (a *App) openDigest() tea.Cmd {
+	a.currentModule = ModuleDigest
+	a.digestDay = a.clock.Now()
+	a.showReport = false
+	return a.loadDigest()
+}
+
+// loadDigest builds the digest of the selected vault day.
+func (a *App) loadDigest() tea.Cmd {
+	day := a.digestDay
+	return func() tea.Msg {
+		ctx, cancel := a.reportOperation()
+		defer cancel()
+		digest, err := a.governanceSvc.DailyDigest(ctx, day, governance.DefaultDigestStockThreshold)
+		return digestMsg{digest: digest, err: err}
+	}
+}
+
+// handleDigestKeys handles key presses in the digest screen.
+func (a *App) handleDigestKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "left", "h", "[":
+		a.digestDay = a.digestDay.AddDate(0, 0, -1)
+	case "right", "l", "]":
+		next := a.digestDay.AddDate(0, 0, 1)
+		if next.After(a.clock.Now()) {
+			return a, nil
+		}
+		a.digestDay = next
+	case "t":
+		a.digestDay = a.clock.Now()
+	case "r": // Reload the same day
+	case "p":
+		a.showReport = !a.showReport
+		a.reportScroll = 0
+		if a.showReport {
+			return a, a.loadDailyReport()
+		}
+		return a, nil
+	case "w":
+		if !a.showReport || a.report == nil {
+			return a, nil
+		}
+		return a, a.writeReport()
+	case "up", "k":
+		if a.showReport && a.reportScroll > 0 {
+			a.reportScroll--
+		}
+		return a, nil
+	case "down", "j":
+		if a.showReport && a.report != nil && a.reportScroll < strings.Count(a.report.Text(), "\n")-1 {
+			a.reportScroll++
+		}
+		return a, nil
+	default:
+		return a, nil
+	}
+	if a.showReport {
+		a.reportScroll = 0
+		return a, tea.Batch(a.loadDigest(), a.loadDailyReport())
+	}
+	return a, a.loadDigest()
+}
+
+// renderDigest renders the what-changed-today digest.
+func (a *App) renderDigest() string {
+	var b strings.Builder
+	b.WriteString(a.theme.Title.Render("═══ DAILY DIGEST ═══"))
+	b.WriteString("\n\n")
+
+	digest := a.digest
+	if digest == nil {
+		b.WriteString(a.theme.Muted.Render("  Digest loading..."))
+		return b.String()
+	}
+
+	b.WriteString(fmt.Sprintf("  %s %s  %s\n\n",
+		a.theme.Label.Render("Vault day:"),
+		a.theme.Value.Render(util.FormatDate(digest.Day)+" "+digest.Day.Format("Mon")),
+		a.theme.Muted.Render(fmt.Sprintf("%d change(s)", digest.Changes()))))
+
+	if digest.Changes() == 0 {
+		b.WriteString(a.theme.Muted.Render("  No changes recorded"))
+		b.WriteString("\n")
+	}
+
+	var lines []string
+	for _, r := range digest.Births {
+		lines = append(lines, fmt.Sprintf("%s %s", r.RegistryNumber, r.FullName()))
+	}
+	a.writeDigestSection(&b, "BIRTHS", lines, a.theme.Success)
+
+	lines = nil
+	for _, r := range digest.Admissions {
+		lines = append(lines, fmt.Sprintf("%s %s", r.RegistryNumber, r.FullName()))
+	}
+	a.writeDigestSection(&b, "ADMISSIONS", lines, a.theme.Base)
+
+	lines = nil
+	for _, r := range digest.Deaths {
+		lines = append(lines, fmt.Sprintf("%s %s", r.RegistryNumber, r.FullName()))
+	}
+	a.writeDigestSection(&b, "DEATHS", lines, a.theme.Warning)
+
+	lines = nil
+	for _, txn := range digest.StockMovements {
+		item := txn.ItemID
+		if txn.Item != nil {
+			item = txn.Item.Name
+		}
+		lines = append(lines, fmt.Sprintf("%+10.1f  %-12s %s", txn.Quantity, txn.TransactionType, item))
+	}
+	a.writeDigestSection(&b, fmt.Sprintf("STOCK MOVEMENTS (≥ %.0f)", governance.DefaultDigestStockThreshold), lines, a.theme.Base)
+
+	lines = nil
+	for _, c := range digest.StatusChanges {
+		lines = append(lines, fmt.Sprintf("%s  %s: %s → %s",
+			util.FormatTime(c.Time), c.Label, c.From, c.To))
+	}
+	a.writeDigestSection(&b, "STATUS CHANGES", lines, a.theme.Base)
+
+	lines = nil
+	for _, w := range digest.Maintenance {
+		lines = append(lines, fmt.Sprintf("%-10s %s — %s", w.Record.Outcome, w.System, w.Record.Description))
+	}
+	a.writeDigestSection(&b, "COMPLETED MAINTENANCE", lines, a.theme.Base)
+
+	b.WriteString("\n")
+	b.WriteString(a.theme.Muted.Render("  " + a.theme.Locale.T("←/→ previous/next day  t today  r refresh  p printable report  Esc back")))
+
+	return b.String()
+}
+
+// digestSectionRows is how many entries each digest section lists.
+const digestSectionRows = 8
+
+// writeDigestSection writes one titled section of the digest, skipping empty
+// sections.
+func (a *App) writeDigestSection(b *strings.Builder, title string, lines []string, style lipgloss.Style) {
+	if len(lines) == 0 {
+		return
+	}
+	b.WriteString(a.theme.Subtitle.Render(fmt.Sprintf("%s (%d)", title, len(lines))))
+	b.WriteString("\n")
+	for i, line := range lines {
+		if i == digestSectionRows {
+			b.WriteString(a.theme.Muted.Render(fmt.Sprintf("  ... %d more", len(lines)-i)))
+			b.WriteString("\n")
+			break
+		}
+		b.WriteString("  " + style.Render(Truncate(line, a.width-4)))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+}
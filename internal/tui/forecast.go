@@ -0,0 +1,139 @@
+package tui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/vtuos/vtuos/internal/services/governance"
+	"github.com/vtuos/vtuos/internal/services/population"
+)
+
+// forecastChartRows is how many years the population forecast chart shows.
+const forecastChartRows = 10
+
+// capacityForecastMsg carries the population forecast for the governance
+// screen.
+type capacityForecastMsg struct {
+	forecast *governance.CapacityForecast
+	err      error
+}
+
+// loadCapacityForecast projects the population against the vault's
+// designed capacity and food production.
+func (a *App) loadCapacityForecast() tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := a.reportOperation()
+		defer cancel()
+		forecast, err := a.governanceSvc.CapacityForecast(ctx,
+			a.clock.Now(), governance.DefaultPlanningYears, a.currentVault().DesignedCapacity)
+		return capacityForecastMsg{forecast: forecast, err: err}
+	}
+}
+
+// renderCapacityForecast renders the population forecast as a bar chart
+// against designed capacity, flagging years over capacity or short of food.
+func (a *App) renderCapacityForecast() string {
+	var b strings.Builder
+	b.WriteString(a.theme.Subtitle.Render(fmt.Sprintf("POPULATION FORECAST (%d YR)", governance.DefaultPlanningYears)))
+	b.WriteString("\n")
+
+	forecast := a.capacityForecast
+	if forecast == nil {
+		b.WriteString(a.theme.Muted.Render("  Forecast loading..."))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	rates := "default rates"
+	if forecast.Forecast.Rates.Historical {
+		rates = fmt.Sprintf("rates from %d birth(s), %d death(s) in %d yr",
+			forecast.Forecast.Rates.Births, forecast.Forecast.Rates.Deaths, population.ForecastLookbackYears)
+	}
+	b.WriteString(a.theme.Muted.Render(fmt.Sprintf("  Now %d of %d capacity, %s",
+		forecast.Forecast.Current, forecast.DesignedCapacity, rates)))
+	b.WriteString("\n")
+
+	// Year, bar, then population, capacity share and food coverage
+	barWidth := max(a.width-34, 10)
+	bars := forecastChart(forecast.Years, forecast.DesignedCapacity, barWidth, forecastChartRows)
+	for i, year := range sampleYears(forecast.Years, forecastChartRows) {
+		line := fmt.Sprintf("  %d %s %5d %4.0f%%", year.Year, bars[i], year.Population, year.CapacityPct)
+		style := a.theme.Base
+		if year.OverCapacity() {
+			style = a.theme.Warning
+		}
+		if year.FoodShortfall() {
+			style = a.theme.Error
+			line += fmt.Sprintf(" food %.0f%%", year.FoodCoverage*100)
+		}
+		b.WriteString(style.Render(line))
+		b.WriteString("\n")
+	}
+
+	if forecast.OverCapacityYear != 0 {
+		b.WriteString(a.theme.Warning.Render(fmt.Sprintf("  Exceeds designed capacity in %d", forecast.OverCapacityYear)))
+		b.WriteString("\n")
+	}
+	if forecast.ShortfallYear != 0 {
+		b.WriteString(a.theme.Error.Render(fmt.Sprintf("  Food production falls short of rations in %d", forecast.ShortfallYear)))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// sampleYears picks up to rows evenly spaced years, always ending with the
+// last year of the forecast.
+func sampleYears(years []governance.CapacityYear, rows int) []governance.CapacityYear {
+	if len(years) <= rows {
+		return years
+	}
+	step := int(math.Ceil(float64(len(years)) / float64(rows)))
+	var sampled []governance.CapacityYear
+	for i := len(years) - 1; i >= 0 && len(sampled) < rows; i -= step {
+		sampled = append(sampled, years[i])
+	}
+	for i, j := 0, len(sampled)-1; i < j; i, j = i+1, j-1 {
+		sampled[i], sampled[j] = sampled[j], sampled[i]
+	}
+	return sampled
+}
+
+// forecastChart renders one bar per sampled year, scaled so the larger of
+// designed capacity and the peak population fills the width. A │ marks
+// designed capacity on every bar.
+func forecastChart(years []governance.CapacityYear, capacity, width, rows int) []string {
+	sampled := sampleYears(years, rows)
+	scale := float64(capacity)
+	for _, y := range sampled {
+		scale = max(scale, float64(y.Population))
+	}
+	if scale <= 0 {
+		scale = 1
+	}
+
+	capCol := -1
+	if capacity > 0 {
+		capCol = min(int(math.Round(float64(capacity)/scale*float64(width))), width-1)
+	}
+
+	bars := make([]string, len(sampled))
+	for i, y := range sampled {
+		filled := int(math.Round(float64(y.Population) / scale * float64(width)))
+		var bar strings.Builder
+		for col := 0; col < width; col++ {
+			switch {
+			case col == capCol:
+				bar.WriteString("│")
+			case col < filled:
+				bar.WriteString("█")
+			default:
+				bar.WriteString("░")
+			}
+		}
+		bars[i] = bar.String()
+	}
+	return bars
+}
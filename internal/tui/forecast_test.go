@@ -0,0 +1,50 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/vtuos/vtuos/internal/services/governance"
+	"github.com/vtuos/vtuos/internal/services/population"
+)
+
+func capacityYears(pops ...int) []governance.CapacityYear {
+	years := make([]governance.CapacityYear, len(pops))
+	for i, pop := range pops {
+		years[i].ForecastPoint = population.ForecastPoint{Year: 2078 + i, Population: pop}
+	}
+	return years
+}
+
+func TestSampleYears(t *testing.T) {
+	years := capacityYears(1, 2, 3, 4, 5, 6, 7)
+
+	sampled := sampleYears(years, 3)
+	want := []int{2078, 2081, 2084}
+	if len(sampled) != len(want) {
+		t.Fatalf("sampleYears() returned %d years, want %d", len(sampled), len(want))
+	}
+	for i, y := range sampled {
+		if y.Year != want[i] {
+			t.Errorf("sampled[%d].Year = %d, want %d", i, y.Year, want[i])
+		}
+	}
+
+	if got := sampleYears(years[:2], 3); len(got) != 2 {
+		t.Errorf("sampleYears() of a short forecast returned %d years, want 2", len(got))
+	}
+}
+
+func TestForecastChart(t *testing.T) {
+	bars := forecastChart(capacityYears(50, 100, 200), 100, 10, 10)
+
+	want := []string{
+		"███░░│░░░░",
+		"█████│░░░░",
+		"█████│████",
+	}
+	for i, bar := range bars {
+		if bar != want[i] {
+			t.Errorf("bar %d = %q, want %q", i, bar, want[i])
+		}
+	}
+}
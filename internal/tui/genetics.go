@@ -0,0 +1,85 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/vtuos/vtuos/internal/services/population"
+)
+
+// geneticHealthMsg carries the vault's genetic health report.
+type geneticHealthMsg struct {
+	report *population.GeneticHealthReport
+	err    error
+}
+
+// loadGeneticHealth assesses the vault's genetic health against its
+// recorded snapshots.
+func (a *App) loadGeneticHealth() tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := a.operation()
+		defer cancel()
+		report, err := a.populationSvc.GetGeneticHealthReport(ctx)
+		return geneticHealthMsg{report: report, err: err}
+	}
+}
+
+// renderGeneticHealth renders the genetic health score as a bar against the
+// alert threshold, what it is made of, and the scores of the recorded
+// snapshots oldest first with the trend to the current one.
+func (a *App) renderGeneticHealth(barWidth int) string {
+	var b strings.Builder
+	b.WriteString(a.theme.Subtitle.Render("GENETIC HEALTH"))
+	b.WriteString("\n")
+
+	report := a.geneticHealth
+	if report == nil || report.Current.Population == 0 {
+		b.WriteString(a.theme.Base.Render("  No active residents assessed.\n"))
+		return b.String()
+	}
+
+	threshold := a.config.Simulation.GeneticHealthThreshold
+	if threshold <= 0 {
+		threshold = population.DefaultGeneticHealthThreshold
+	}
+	g := report.Current
+	style := a.theme.Success
+	switch {
+	case g.Score < threshold/2:
+		style = a.theme.Error
+	case g.Score < threshold:
+		style = a.theme.Warning
+	}
+
+	b.WriteString(fmt.Sprintf("  %-16s", "Score"))
+	b.WriteString(a.theme.ProgressBar(float64(g.Score), 100, barWidth))
+	b.WriteString(style.Render(fmt.Sprintf(" %d/100", g.Score)))
+	b.WriteString(a.theme.Muted.Render(fmt.Sprintf("  alert below %d", threshold)))
+	b.WriteString("\n")
+	b.WriteString(fmt.Sprintf("  %-16s%s\n", "Founders", a.theme.Value.Render(fmt.Sprintf(
+		"%d of %d represented, %.1f effective", g.FoundersRepresented, g.Founders, g.EffectiveFounders))))
+	b.WriteString(fmt.Sprintf("  %-16s%s\n", "Mean kinship", a.theme.Value.Render(fmt.Sprintf(
+		"%.4f across %d breeding-age pairs", g.MeanKinship, g.BreedingPairs))))
+	b.WriteString(fmt.Sprintf("  %-16s%s\n", "Viable pairs", a.theme.Value.Render(fmt.Sprintf(
+		"%d (%.0f%%)", g.ViablePairs, g.ViablePairShare()*100))))
+
+	if len(report.History) == 0 {
+		b.WriteString(a.theme.Muted.Render("  No snapshots recorded yet; one is taken on the 1st of each month.\n"))
+		return b.String()
+	}
+	scores := make([]string, 0, len(report.History)+1)
+	for i := len(report.History) - 1; i >= 0; i-- {
+		scores = append(scores, fmt.Sprintf("%d", report.History[i].Score))
+	}
+	scores = append(scores, fmt.Sprintf("%d", g.Score))
+
+	trend := a.theme.Value.Render(fmt.Sprintf("%+d", report.Trend))
+	if report.Trend < 0 {
+		trend = a.theme.Warning.Render(fmt.Sprintf("%+d", report.Trend))
+	}
+	b.WriteString(fmt.Sprintf("  %-16s%s %s\n", "Trend", trend,
+		a.theme.Muted.Render(Truncate(strings.Join(scores, " → "), a.width-30))))
+	return b.String()
+}
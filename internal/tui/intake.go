@@ -0,0 +1,285 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/services/population"
+	"github.com/vtuos/vtuos/internal/tui/components"
+	popviews "github.com/vtuos/vtuos/internal/tui/views/population"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// intakeActions are the actions available on the population module's
+// intake tab.
+var intakeActions = components.NewActionBar(
+	components.Action{Key: "n", Label: "New intake"},
+	components.Action{Key: "s", Label: "Screen"},
+	components.Action{Key: "c", Label: "Clear"},
+)
+
+// intakeMsg carries the intakes awaiting medical clearance.
+type intakeMsg struct {
+	intakes []*models.Intake
+	err     error
+}
+
+// intakeAdmittedMsg is sent when the intake wizard's admission completes.
+type intakeAdmittedMsg struct {
+	intake *models.Intake
+	err    error
+}
+
+// loadIntakes loads the intakes awaiting medical clearance.
+func (a *App) loadIntakes() tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := a.operation()
+		defer cancel()
+		intakes, err := a.populationSvc.ListPendingIntakes(ctx)
+		return intakeMsg{intakes: intakes, err: err}
+	}
+}
+
+// openIntakeWizard opens the wizard that admits an outsider.
+func (a *App) openIntakeWizard() {
+	if a.denyReadOnly() {
+		return
+	}
+	a.intakeWizard = popviews.NewIntakeWizard(a.clock.Now())
+}
+
+// handleIntakeWizardKeys handles key presses while the intake wizard is
+// open, admitting once its review is confirmed.
+func (a *App) handleIntakeWizardKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	a.intakeWizard.HandleKey(msg.String())
+	if a.intakeWizard.IsCancelled() {
+		a.intakeWizard = nil
+		return a, nil
+	}
+	if a.intakeWizard.IsSubmitted() {
+		return a, a.admitIntake(a.intakeWizard.GetData())
+	}
+	return a, nil
+}
+
+// admitIntake admits the outsider entered in the intake wizard.
+func (a *App) admitIntake(data popviews.IntakeData) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := a.operation()
+		defer cancel()
+		intake, err := a.populationSvc.AdmitIntake(ctx, population.IntakeInput{
+			Surname:        data.Surname,
+			GivenNames:     data.GivenNames,
+			DateOfBirth:    data.DateOfBirth,
+			Sex:            data.Sex,
+			BloodType:      data.BloodType,
+			QuarantineDays: data.QuarantineDays,
+			Notes:          data.Notes,
+		})
+		return intakeAdmittedMsg{intake: intake, err: err}
+	}
+}
+
+// handleIntakeAdmitted closes the intake wizard once its admission is
+// made, or shows the error on its review.
+func (a *App) handleIntakeAdmitted(msg intakeAdmittedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		if a.intakeWizard != nil {
+			a.intakeWizard.SetError(msg.err.Error())
+		}
+		a.AddError("Admission failed", msg.err)
+		return a, nil
+	}
+	a.intakeWizard = nil
+	resident := msg.intake.Resident
+	a.AddAlert(AlertInfo, fmt.Sprintf("%s %s admitted to quarantine until %s", resident.RegistryNumber,
+		resident.FullName(), util.FormatDate(msg.intake.QuarantineEnd)))
+	return a, tea.Batch(a.loadIntakes(), a.loadCensus(), a.loadPopulation())
+}
+
+// selectedIntake returns the intake selected on the intake tab, nil if there
+// are none.
+func (a *App) selectedIntake() *models.Intake {
+	if a.intakeIndex < len(a.intakes) {
+		return a.intakes[a.intakeIndex]
+	}
+	return nil
+}
+
+// handleIntakeKeys handles key presses in the intake tab: selection, the
+// intake wizard, and screening and clearing the selected intake.
+func (a *App) handleIntakeKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	intake := a.selectedIntake()
+	switch key := msg.String(); key {
+	case "up", "k":
+		if a.intakeIndex > 0 {
+			a.intakeIndex--
+		}
+	case "down", "j":
+		if a.intakeIndex < len(a.intakes)-1 {
+			a.intakeIndex++
+		}
+	case "n":
+		a.openIntakeWizard()
+	case "s", "c":
+		if intake == nil || a.denyReadOnly() {
+			return a, nil
+		}
+		name := intake.Resident.FullName()
+		if key == "c" {
+			if pending := intake.PendingScreenings(); len(pending) > 0 {
+				a.AddAlert(AlertWarning, fmt.Sprintf("%s has %d screening(s) not passed", name, len(pending)))
+				return a, nil
+			}
+			a.quickAction = &quickAction{
+				kind:       quickActionClearIntake,
+				targetID:   intake.ResidentID,
+				targetName: name,
+				prompt:     "Clear " + name + " from intake, medical officer registry number: ",
+			}
+			return a, nil
+		}
+		pending := intake.PendingScreenings()
+		if len(pending) == 0 {
+			a.AddAlert(AlertInfo, name+" has passed every screening; clear them (c)")
+			return a, nil
+		}
+		a.quickAction = &quickAction{
+			kind:       quickActionScreening,
+			targetID:   pending[0].ID,
+			targetName: name,
+			prompt:     fmt.Sprintf("%s screening of %s: OFFICER-REG pass|fail [notes]: ", pending[0].Screening, name),
+		}
+	case "r":
+		return a, a.loadIntakes()
+	}
+	return a, nil
+}
+
+// runIntakeAction records the result of a screening of the selected intake
+// or clears it.
+func (a *App) runIntakeAction(ctx context.Context, action *quickAction, input string) quickActionDoneMsg {
+	fields := strings.Fields(input)
+	regNum := strings.ToUpper(fields[0])
+	officer, err := a.populationSvc.GetResidentByRegistryNumber(ctx, regNum)
+	if err != nil {
+		return quickActionDoneMsg{module: ModulePopulation, err: fmt.Errorf("medical officer %s: %w", regNum, err)}
+	}
+
+	if action.kind == quickActionClearIntake {
+		intake, err := a.populationSvc.ClearIntake(ctx, action.targetID, officer.ID)
+		if err != nil {
+			return quickActionDoneMsg{module: ModulePopulation, err: err}
+		}
+		if intake.Resident.Status == models.ResidentStatusActive {
+			return quickActionDoneMsg{module: ModulePopulation, success: action.targetName + " cleared and promoted to ACTIVE"}
+		}
+		return quickActionDoneMsg{module: ModulePopulation, success: fmt.Sprintf("%s cleared; quarantine ends %s",
+			action.targetName, util.FormatDate(intake.QuarantineEnd))}
+	}
+
+	if len(fields) < 2 {
+		return quickActionDoneMsg{module: ModulePopulation, err: fmt.Errorf("%w: expected officer and pass or fail", repository.ErrValidation)}
+	}
+	var passed bool
+	switch strings.ToLower(fields[1]) {
+	case "pass", "passed", "p":
+		passed = true
+	case "fail", "failed", "f":
+	default:
+		return quickActionDoneMsg{module: ModulePopulation, err: fmt.Errorf("%w: result must be pass or fail, not %q", repository.ErrValidation, fields[1])}
+	}
+	screening, err := a.populationSvc.RecordScreening(ctx, population.ScreeningInput{
+		ScreeningID: action.targetID,
+		OfficerID:   officer.ID,
+		Passed:      passed,
+		Notes:       strings.Join(fields[2:], " "),
+	})
+	if err != nil {
+		return quickActionDoneMsg{module: ModulePopulation, err: err}
+	}
+	message := fmt.Sprintf("%s %s screening %s", action.targetName, screening.Screening, screening.Result())
+	if !passed {
+		return quickActionDoneMsg{module: ModulePopulation, alert: AlertWarning, success: message + "; retest before clearance"}
+	}
+	return quickActionDoneMsg{module: ModulePopulation, success: message}
+}
+
+// renderIntakes renders the intake tab: the intakes awaiting clearance and
+// the screenings of the selected one.
+func (a *App) renderIntakes() string {
+	var b strings.Builder
+	b.WriteString(a.theme.Subtitle.Render("AWAITING MEDICAL CLEARANCE"))
+	b.WriteString("\n")
+
+	now := a.clock.Now()
+	if len(a.intakes) == 0 {
+		b.WriteString(a.theme.Muted.Render("  No intakes in quarantine; admit an outsider with n"))
+		b.WriteString("\n")
+	}
+	narrow := GetBreakpoint(a.width) == BreakpointNarrow
+	for i, intake := range a.intakes {
+		passed := len(intake.Screenings) - len(intake.PendingScreenings())
+		state := fmt.Sprintf("%d/%d passed", passed, len(intake.Screenings))
+		if passed == len(intake.Screenings) {
+			state = "READY TO CLEAR"
+		}
+		line := fmt.Sprintf("%-11s %-24s admitted %s  until %s  %s", intake.Resident.RegistryNumber,
+			Truncate(intake.Resident.FullName(), 24), util.FormatDay(intake.AdmittedAt),
+			util.FormatDate(intake.QuarantineEnd), state)
+		if narrow {
+			line = fmt.Sprintf("%-11s %-16s %s", intake.Resident.RegistryNumber, Truncate(intake.Resident.FullName(), 16), state)
+		}
+		line = Truncate(line, a.width-4)
+
+		overdue := false
+		for _, s := range intake.Screenings {
+			overdue = overdue || s.IsOverdue(now)
+		}
+		switch {
+		case i == a.intakeIndex:
+			b.WriteString(a.theme.Selected.Render("> " + line))
+		case overdue:
+			b.WriteString("  " + a.theme.Warning.Render(line))
+		default:
+			b.WriteString("  " + a.theme.Base.Render(line))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("  ")
+	b.WriteString(a.renderActionBar(intakeActions))
+	b.WriteString("\n\n")
+
+	if intake := a.selectedIntake(); intake != nil {
+		b.WriteString(a.theme.Subtitle.Render("SCREENINGS"))
+		b.WriteString(a.theme.Muted.Render("  " + intake.Resident.FullName()))
+		b.WriteString("\n")
+		for _, s := range intake.Screenings {
+			line := fmt.Sprintf("%-20s due %s  %-7s", s.Screening, util.FormatDate(s.DueDate), s.Result())
+			if s.IsOverdue(now) {
+				line += "  OVERDUE"
+			}
+			if s.Notes != "" {
+				line += "  " + s.Notes
+			}
+			line = Truncate(line, a.width-4)
+			switch {
+			case s.IsPassed():
+				b.WriteString("  " + a.theme.Base.Render(line))
+			case s.Passed != nil || s.IsOverdue(now):
+				b.WriteString("  " + a.theme.Warning.Render(line))
+			default:
+				b.WriteString("  " + a.theme.Muted.Render(line))
+			}
+			b.WriteString("\n")
+		}
+	}
+	b.WriteString(a.theme.Muted.Render("  " + a.theme.Locale.T("↑/↓ select  s records the next screening due  r reload")))
+	b.WriteString("\n")
+	return b.String()
+}
@@ -1,7 +1,11 @@
 package tui
 
 import (
+	"strings"
+
 	"github.com/charmbracelet/bubbletea"
+
+	"github.com/vtuos/vtuos/internal/i18n"
 )
 
 // KeyMap defines all key bindings for the application.
@@ -34,6 +38,7 @@ type KeyMap struct {
 	F8  Key
 	F9  Key
 	F10 Key
+	F11 Key
 
 	// Form navigation
 	Tab      Key
@@ -44,6 +49,8 @@ type KeyMap struct {
 	// Editing
 	Delete    Key
 	Backspace Key
+	Undo      Key
+	Redo      Key
 }
 
 // Key represents a key binding.
@@ -78,7 +85,7 @@ func DefaultKeyMap() KeyMap {
 			Enabled: true,
 		},
 		PageUp: Key{
-			Keys:    []string{"pgup", "ctrl+u"},
+			Keys:    []string{"pgup"},
 			Help:    "page up",
 			Enabled: true,
 		},
@@ -176,6 +183,11 @@ func DefaultKeyMap() KeyMap {
 			Help:    "Quit",
 			Enabled: true,
 		},
+		F11: Key{
+			Keys:    []string{"f11"},
+			Help:    "Settings",
+			Enabled: true,
+		},
 
 		// Form navigation
 		Tab: Key{
@@ -210,6 +222,17 @@ func DefaultKeyMap() KeyMap {
 			Help:    "backspace",
 			Enabled: true,
 		},
+		// Ctrl+Z suspends to the shell, so undo takes Ctrl+U
+		Undo: Key{
+			Keys:    []string{"ctrl+u"},
+			Help:    "undo",
+			Enabled: true,
+		},
+		Redo: Key{
+			Keys:    []string{"ctrl+y"},
+			Help:    "redo",
+			Enabled: true,
+		},
 	}
 }
 
@@ -252,7 +275,7 @@ func (km KeyMap) IsNavigation(msg tea.KeyMsg) bool {
 // IsFunctionKey checks if the key message is a function key.
 func (km KeyMap) IsFunctionKey(msg tea.KeyMsg) bool {
 	return MatchesAny(msg, km.F1, km.F2, km.F3, km.F4, km.F5,
-		km.F6, km.F7, km.F8, km.F9, km.F10)
+		km.F6, km.F7, km.F8, km.F9, km.F10, km.F11)
 }
 
 // GetFunctionKeyModule returns the module name for a function key.
@@ -278,24 +301,55 @@ func (km KeyMap) GetFunctionKeyModule(msg tea.KeyMsg) string {
 		return "governance"
 	case km.F10.Matches(msg):
 		return "quit"
+	case km.F11.Matches(msg):
+		return "settings"
 	default:
 		return ""
 	}
 }
 
+// statusBarLabels are the labels of the function keys the status bar
+// lists, full and for narrow terminals.
+var statusBarLabels = map[string][2]string{
+	"F1":  {"Help", "?"},
+	"F2":  {"Dashboard", "Dash"},
+	"F3":  {"Population", "Pop"},
+	"F4":  {"Resources", "Res"},
+	"F5":  {"Facilities", "Fac"},
+	"F6":  {"Labor", "Lab"},
+	"F7":  {"Medical", "Med"},
+	"F8":  {"Security", "Sec"},
+	"F9":  {"Governance", "Gov"},
+	"F10": {"Quit", "Quit"},
+}
+
+// statusBar lists function keys with their translated labels, short ones
+// if short is set.
+func statusBar(locale i18n.Locale, short bool, keys ...string) string {
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		label := statusBarLabels[key][0]
+		if short {
+			label = statusBarLabels[key][1]
+		}
+		parts[i] = "[" + key + "]" + locale.T(label)
+	}
+	return strings.Join(parts, " ")
+}
+
 // StatusBarHelp returns the help text for the status bar.
-func (km KeyMap) StatusBarHelp() string {
-	return "[F1]Help [F2]Dashboard [F3]Population [F4]Resources [F5]Facilities [F10]Quit"
+func (km KeyMap) StatusBarHelp(locale i18n.Locale) string {
+	return statusBar(locale, false, "F1", "F2", "F3", "F4", "F5", "F10")
 }
 
 // StatusBarHelpResponsive returns help text adapted to the terminal width.
-func (km KeyMap) StatusBarHelpResponsive(width int) string {
+func (km KeyMap) StatusBarHelpResponsive(width int, locale i18n.Locale) string {
 	switch {
 	case width < 60:
-		return "[F1]? [F2]Dash [F3]Pop [F4]Res [F10]Quit"
+		return statusBar(locale, true, "F1", "F2", "F3", "F4", "F10")
 	case width < 100:
-		return "[F1]Help [F2]Dashboard [F3]Population [F4]Resources [F10]Quit"
+		return statusBar(locale, false, "F1", "F2", "F3", "F4", "F10")
 	default:
-		return "[F1]Help [F2]Dashboard [F3]Population [F4]Resources [F5]Facilities [F6]Labor [F7]Medical [F8]Security [F9]Governance [F10]Quit"
+		return statusBar(locale, false, "F1", "F2", "F3", "F4", "F5", "F6", "F7", "F8", "F9", "F10")
 	}
 }
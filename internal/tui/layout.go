@@ -1,9 +1,11 @@
 package tui
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+
 )
 
 // LayoutBreakpoint defines terminal width thresholds for responsive layout.
@@ -16,6 +18,9 @@ const (
 	BreakpointMedium LayoutBreakpoint = 100
 	// BreakpointWide is for terminals over 100 columns.
 	BreakpointWide LayoutBreakpoint = 140
+	// BreakpointSplit is the width above which list modules show the
+	// selected record beside the list.
+	BreakpointSplit LayoutBreakpoint = 160
 )
 
 // GetBreakpoint returns the current layout breakpoint for the given width.
@@ -207,6 +212,19 @@ func SideBySide(left, right string, totalWidth, gap int) string {
 	return left + "\n\n" + right
 }
 
+// SplitWidths divides totalWidth between a left and right pane, giving the
+// left pane ratio of it but never less than minWidth to either pane.
+func SplitWidths(totalWidth int, ratio float64, minWidth int) (left, right int) {
+	left = int(float64(totalWidth) * ratio)
+	if left < minWidth {
+		left = minWidth
+	}
+	if totalWidth-left < minWidth {
+		left = totalWidth - minWidth
+	}
+	return left, totalWidth - left
+}
+
 // ProgressBar renders a text-based progress bar.
 func (t *Theme) ProgressBar(value, max float64, width int) string {
 	if max <= 0 {
@@ -220,6 +238,18 @@ func (t *Theme) ProgressBar(value, max float64, width int) string {
 		ratio = 0
 	}
 
+	// A plain theme says in words what the bar's color would
+	if t.Plain {
+		level := t.Locale.T("OK")
+		switch {
+		case ratio <= 0.3:
+			level = t.Locale.T("CRITICAL")
+		case ratio <= 0.6:
+			level = t.Locale.T("LOW")
+		}
+		return PadRight(fmt.Sprintf("%3.0f%% %s", ratio*100, level), width)
+	}
+
 	barWidth := width - 2 // for [ and ]
 	if barWidth < 4 {
 		barWidth = 4
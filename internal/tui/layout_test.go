@@ -228,6 +228,29 @@ func TestContentHeight(t *testing.T) {
 	}
 }
 
+func TestSplitWidths(t *testing.T) {
+	tests := []struct {
+		total     int
+		ratio     float64
+		minWidth  int
+		wantLeft  int
+		wantRight int
+	}{
+		{200, 0.5, 60, 100, 100}, // even split
+		{200, 0.6, 60, 120, 80},  // list wider
+		{200, 0.2, 60, 60, 140},  // left clamped to min
+		{200, 0.9, 60, 140, 60},  // right clamped to min
+	}
+
+	for _, tt := range tests {
+		left, right := SplitWidths(tt.total, tt.ratio, tt.minWidth)
+		if left != tt.wantLeft || right != tt.wantRight {
+			t.Errorf("SplitWidths(%d, %.1f, %d) = %d, %d, want %d, %d",
+				tt.total, tt.ratio, tt.minWidth, left, right, tt.wantLeft, tt.wantRight)
+		}
+	}
+}
+
 func TestSideBySide_Horizontal(t *testing.T) {
 	left := "AAA"
 	right := "BBB"
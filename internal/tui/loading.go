@@ -0,0 +1,213 @@
+package tui
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/tui/components"
+	resviews "github.com/vtuos/vtuos/internal/tui/views/resources"
+)
+
+// listView identifies a list view loaded in the background.
+type listView int
+
+const (
+	listCensus listView = iota
+	listHouseholds
+	listInventory
+)
+
+// censusLoadedMsg carries a census page fetched by load seq.
+type censusLoadedMsg struct {
+	seq    uint64
+	result *models.ResidentList
+	err    error
+}
+
+// householdsLoadedMsg carries a households page fetched by load seq.
+type householdsLoadedMsg struct {
+	seq    uint64
+	result *models.HouseholdList
+	err    error
+}
+
+// inventoryLoadedMsg carries an inventory page fetched by load seq.
+type inventoryLoadedMsg struct {
+	seq    uint64
+	result *resviews.InventoryPage
+	err    error
+}
+
+// loadDueMsg is sent once paging has settled, to start load seq of a view.
+type loadDueMsg struct {
+	view listView
+	seq  uint64
+}
+
+// spinnerMsg advances the loading spinners.
+type spinnerMsg struct{}
+
+// loadCensus loads the census page now, superseding any load in flight.
+func (a *App) loadCensus() tea.Cmd {
+	return a.startLoad(listCensus, a.censusView.Loader().Request())
+}
+
+// loadHouseholds loads the households page now, superseding any load in
+// flight.
+func (a *App) loadHouseholds() tea.Cmd {
+	return a.startLoad(listHouseholds, a.householdsView.Loader().Request())
+}
+
+// loadInventory loads the inventory page now, superseding any load in
+// flight.
+func (a *App) loadInventory() tea.Cmd {
+	return a.startLoad(listInventory, a.inventoryView.Loader().Request())
+}
+
+// pageCensus loads the census page reached once paging settles.
+func (a *App) pageCensus() tea.Cmd {
+	return a.debounceLoad(listCensus, a.censusView.Loader())
+}
+
+// pageHouseholds loads the households page reached once paging settles.
+func (a *App) pageHouseholds() tea.Cmd {
+	return a.debounceLoad(listHouseholds, a.householdsView.Loader())
+}
+
+// pageInventory loads the inventory page reached once paging settles.
+func (a *App) pageInventory() tea.Cmd {
+	return a.debounceLoad(listInventory, a.inventoryView.Loader())
+}
+
+// debounceLoad requests a load of view and starts it after
+// components.LoadDebounce, unless another request supersedes it first, so
+// holding Page Down queries only the page it stops on.
+func (a *App) debounceLoad(view listView, loader *components.Loader) tea.Cmd {
+	seq := loader.Request()
+	return tea.Batch(a.spin(), tea.Tick(components.LoadDebounce, func(time.Time) tea.Msg {
+		return loadDueMsg{view: view, seq: seq}
+	}))
+}
+
+// startLoad runs load seq of view in the background. The query is taken
+// from the view as it is now; the view is only updated when the result
+// arrives, and only if no later load superseded it.
+func (a *App) startLoad(view listView, seq uint64) tea.Cmd {
+	ctx, cancel := a.operation()
+	var run func(context.Context) tea.Msg
+	var loader *components.Loader
+	switch view {
+	case listCensus:
+		loader = a.censusView.Loader()
+		fetch := a.censusView.Fetch()
+		run = func(ctx context.Context) tea.Msg {
+			result, err := fetch(ctx)
+			return censusLoadedMsg{seq: seq, result: result, err: err}
+		}
+	case listHouseholds:
+		loader = a.householdsView.Loader()
+		fetch := a.householdsView.Fetch()
+		run = func(ctx context.Context) tea.Msg {
+			result, err := fetch(ctx)
+			return householdsLoadedMsg{seq: seq, result: result, err: err}
+		}
+	default:
+		loader = a.inventoryView.Loader()
+		fetch := a.inventoryView.Fetch()
+		run = func(ctx context.Context) tea.Msg {
+			result, err := fetch(ctx)
+			return inventoryLoadedMsg{seq: seq, result: result, err: err}
+		}
+	}
+	if !loader.Begin(seq, cancel) {
+		return nil
+	}
+
+	return tea.Batch(a.spin(), func() tea.Msg {
+		defer cancel()
+		return run(ctx)
+	})
+}
+
+// handleLoad handles the messages of background loads.
+func (a *App) handleLoad(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case loadDueMsg:
+		if a.loader(msg.view).Current(msg.seq) {
+			return a, a.startLoad(msg.view, msg.seq)
+		}
+
+	case censusLoadedMsg:
+		if a.censusView.Loader().Done(msg.seq) {
+			a.censusView.SetResult(msg.result, msg.err)
+			if msg.err != nil {
+				a.AddError("Failed to load census", msg.err)
+			}
+			if a.openResident != "" {
+				return a, a.openPendingResident(msg.err)
+			}
+			return a, a.followSelection()
+		}
+
+	case householdsLoadedMsg:
+		if a.householdsView.Loader().Done(msg.seq) {
+			a.householdsView.SetResult(msg.result, msg.err)
+			if msg.err != nil {
+				a.AddError("Failed to load households", msg.err)
+			}
+		}
+
+	case inventoryLoadedMsg:
+		if a.inventoryView.Loader().Done(msg.seq) {
+			a.inventoryView.SetResult(msg.result, msg.err)
+			if msg.err != nil {
+				a.AddError("Failed to load inventory", msg.err)
+			}
+			return a, a.followSelection()
+		}
+
+	case spinnerMsg:
+		a.spinning = false
+		for _, view := range []listView{listCensus, listHouseholds, listInventory} {
+			if loader := a.loader(view); loader.Loading() {
+				loader.Tick()
+				a.spinning = true
+			}
+		}
+		if a.spinning {
+			return a, spinnerTick()
+		}
+	}
+	return a, nil
+}
+
+// loader returns the loader of view.
+func (a *App) loader(view listView) *components.Loader {
+	switch view {
+	case listCensus:
+		return a.censusView.Loader()
+	case listHouseholds:
+		return a.householdsView.Loader()
+	default:
+		return a.inventoryView.Loader()
+	}
+}
+
+// spin starts the loading spinners unless they are already running.
+func (a *App) spin() tea.Cmd {
+	if a.spinning {
+		return nil
+	}
+	a.spinning = true
+	return spinnerTick()
+}
+
+// spinnerTick schedules the next spinner frame.
+func spinnerTick() tea.Cmd {
+	return tea.Tick(components.SpinnerInterval, func(time.Time) tea.Msg {
+		return spinnerMsg{}
+	})
+}
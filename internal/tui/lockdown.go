@@ -0,0 +1,292 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/services/governance"
+	"github.com/vtuos/vtuos/internal/tui/components"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// lockdownHistoryRows is how many state changes the security module lists.
+const lockdownHistoryRows = 5
+
+// restrictedModules are the modules that need the state's module clearance
+// while the vault is not NORMAL. Dashboard, facilities, medical, security
+// and help stay open to every terminal.
+var restrictedModules = map[Module]bool{
+	ModulePopulation:  true,
+	ModuleResources:   true,
+	ModuleLabor:       true,
+	ModuleGovernance:  true,
+	ModuleSettings:    true,
+	ModuleDigest:      true,
+	ModuleTasks:       true,
+	ModuleCare:        true,
+	ModuleAptitude:    true,
+	ModuleConsumption: true,
+	ModuleSessions:    true,
+	ModuleAlerts:      true,
+}
+
+// securityActions are the actions available in the security module.
+var securityActions = components.NewActionBar(
+	components.Action{Key: "l", Label: "Change vault state"},
+)
+
+// lockdownMsg carries the vault's alert state and recent changes.
+type lockdownMsg struct {
+	current *models.LockdownChange
+	history []*models.LockdownChange
+	err     error
+}
+
+// operatorMsg carries the outcome of an operator sign-on or an authorized
+// state change. On success the operator is signed on to the terminal and
+// module, if set, is opened.
+type operatorMsg struct {
+	operator *models.Resident
+	module   Module
+	success  string
+	err      error
+}
+
+// loadLockdown loads the vault's alert state.
+func (a *App) loadLockdown() tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := a.operation()
+		defer cancel()
+		current, err := a.governanceSvc.GetLockdownState(ctx)
+		if err != nil {
+			return lockdownMsg{err: err}
+		}
+		history, err := a.governanceSvc.ListLockdownChanges(ctx, lockdownHistoryRows)
+		return lockdownMsg{current: current, history: history, err: err}
+	}
+}
+
+// handleLockdown stores a loaded alert state, alerting when another terminal
+// changed it, and leaves a module this terminal may no longer use.
+func (a *App) handleLockdown(msg lockdownMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		a.AddError("Failed to load vault state", msg.err)
+		return a, nil
+	}
+	if a.lockdown != nil && a.lockdown.ID != msg.current.ID {
+		a.AddAlert(lockdownAlertLevel(msg.current.State), "Vault state is now "+string(msg.current.State))
+	}
+	a.lockdown = msg.current
+	a.lockdownHistory = msg.history
+	if a.moduleLocked(a.currentModule) {
+		a.currentModule = ModuleDashboard
+		a.showDetail = false
+	}
+	return a, nil
+}
+
+// lockdownState returns the vault's alert state, NORMAL until loaded.
+func (a *App) lockdownState() models.LockdownState {
+	if a.lockdown == nil {
+		return models.LockdownNormal
+	}
+	return a.lockdown.State
+}
+
+// moduleLocked reports whether the vault state keeps this terminal out of
+// the module: it is restricted and the signed-on operator, if any, lacks
+// the state's module clearance.
+func (a *App) moduleLocked(m Module) bool {
+	required := a.lockdownState().ModuleClearance()
+	if required == 0 || !restrictedModules[m] {
+		return false
+	}
+	return a.operator == nil || a.operator.ClearanceLevel < required
+}
+
+// gotoModule opens a module, first asking for an operator with the module
+// clearance if the vault state restricts it.
+func (a *App) gotoModule(m Module) tea.Cmd {
+	if a.moduleLocked(m) {
+		state := a.lockdownState()
+		a.quickAction = &quickAction{
+			kind:     quickActionSignOn,
+			targetID: string(m),
+			prompt:   fmt.Sprintf("%s: %s needs clearance %d. Operator registry number: ", state, m, state.ModuleClearance()),
+		}
+		return nil
+	}
+	return a.openModule(m)
+}
+
+// handleSecurityKeys handles key presses in the security module, passing
+// those of access control on.
+func (a *App) handleSecurityKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "l":
+		if a.denyReadOnly() {
+			return a, nil
+		}
+		a.quickAction = &quickAction{
+			kind:   quickActionLockdown,
+			prompt: "New state, authorizing registry number and reason (e.g. LOCKDOWN V076-00001 Breach): ",
+		}
+	case "i", "t":
+		return a.handleArmoryKeys(msg)
+	case "r":
+		return a, tea.Batch(a.loadLockdown(), a.loadAccess(), a.loadArmory())
+	default:
+		return a.handleAccessKeys(msg)
+	}
+	return a, nil
+}
+
+// runOperatorAction signs an operator on to open a restricted module, or
+// changes the vault state on an operator's authority.
+func (a *App) runOperatorAction(ctx context.Context, action *quickAction, input string) operatorMsg {
+	fields := strings.Fields(input)
+	var state models.LockdownState
+	var err error
+	if action.kind == quickActionLockdown {
+		if len(fields) < 2 {
+			return operatorMsg{err: fmt.Errorf("%w: enter a state and a registry number", repository.ErrValidation)}
+		}
+		if state, err = models.ParseLockdownState(fields[0]); err != nil {
+			return operatorMsg{err: fmt.Errorf("%w: %w", repository.ErrValidation, err)}
+		}
+		fields = fields[1:]
+	}
+	if len(fields) == 0 {
+		return operatorMsg{err: fmt.Errorf("%w: enter a registry number", repository.ErrValidation)}
+	}
+
+	regNum := strings.ToUpper(fields[0])
+	operator, err := a.populationSvc.GetResidentByRegistryNumber(ctx, regNum)
+	if err != nil {
+		return operatorMsg{err: fmt.Errorf("operator %s: %w", regNum, err)}
+	}
+	if operator.Status != models.ResidentStatusActive {
+		return operatorMsg{err: fmt.Errorf("%w: operator %s is %s", repository.ErrValidation, regNum, operator.Status)}
+	}
+
+	if action.kind == quickActionSignOn {
+		module := Module(action.targetID)
+		if required := a.lockdownState().ModuleClearance(); operator.ClearanceLevel < required {
+			return operatorMsg{err: fmt.Errorf("%w: %s holds clearance %d, %s needs %d",
+				repository.ErrValidation, regNum, operator.ClearanceLevel, module, required)}
+		}
+		return operatorMsg{operator: operator, module: module, success: "Signed on as " + operator.FullName()}
+	}
+
+	change, err := a.governanceSvc.SetLockdownState(ctx, governance.LockdownInput{
+		State:        state,
+		AuthorizedBy: operator.ID,
+		Reason:       strings.Join(fields[1:], " "),
+	})
+	if err != nil {
+		return operatorMsg{err: err}
+	}
+	return operatorMsg{operator: operator, success: fmt.Sprintf("Vault state %s authorized by %s", change.State, operator.FullName())}
+}
+
+// handleOperator signs the operator on and opens the module they asked for.
+// A new operator starts a new terminal session, and the census is reloaded
+// with the personal details their clearance shows.
+func (a *App) handleOperator(msg operatorMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		a.AddError("Authorization failed", msg.err)
+		return a, nil
+	}
+	var session tea.Cmd
+	changed := msg.operator.ID != a.operatorID()
+	a.operator = msg.operator
+	if changed {
+		session = tea.Batch(a.startSession(msg.operator.ID), a.loadCensus())
+	}
+	a.AddAlert(AlertInfo, msg.success)
+	if msg.module != "" {
+		return a, tea.Batch(session, a.openModule(msg.module))
+	}
+	return a, tea.Batch(session, a.loadLockdown())
+}
+
+// lockdownAlertLevel maps a vault state to the level it is announced at.
+func lockdownAlertLevel(state models.LockdownState) AlertLevel {
+	switch state {
+	case models.LockdownLockdown, models.LockdownEmergency:
+		return AlertCritical
+	case models.LockdownDrill:
+		return AlertWarning
+	default:
+		return AlertInfo
+	}
+}
+
+// renderLockdownBanner renders the persistent vault state banner shown
+// before the alerts, empty while the vault is NORMAL.
+func (a *App) renderLockdownBanner() string {
+	state := a.lockdownState()
+	if state == models.LockdownNormal {
+		return ""
+	}
+	banner := "■ " + string(state) + " ■"
+	if state == models.LockdownDrill {
+		return a.theme.AlertWarn.Render(banner)
+	}
+	return a.theme.AlertCrit.Render(banner)
+}
+
+// renderLockdown renders the vault state section of the security module:
+// the current state, what it restricts, who authorized it and recent
+// changes.
+func (a *App) renderLockdown() string {
+	var b strings.Builder
+	b.WriteString(a.theme.Subtitle.Render("VAULT STATE"))
+	b.WriteString("\n")
+
+	state := a.lockdownState()
+	style := a.theme.Success
+	switch lockdownAlertLevel(state) {
+	case AlertCritical:
+		style = a.theme.Error
+	case AlertWarning:
+		style = a.theme.Warning
+	}
+	b.WriteString("  " + style.Render(string(state)))
+	if a.lockdown != nil && !a.lockdown.ChangedAt.IsZero() {
+		b.WriteString(a.theme.Muted.Render("  since " + util.FormatDateTime(a.lockdown.ChangedAt)))
+	}
+	if required := state.ModuleClearance(); required > 0 {
+		b.WriteString(a.theme.Muted.Render(fmt.Sprintf("  terminals need clearance %d", required)))
+	}
+	if state.PausesNonEssential() {
+		b.WriteString(a.theme.Muted.Render("  non-essential tasks paused"))
+	}
+	b.WriteString("\n")
+	if a.operator != nil {
+		b.WriteString(a.theme.Muted.Render(fmt.Sprintf("  Operator: %s %s (clearance %d)",
+			a.operator.RegistryNumber, a.operator.FullName(), a.operator.ClearanceLevel)))
+		b.WriteString("\n")
+	}
+
+	for _, c := range a.lockdownHistory {
+		by := "unknown"
+		if c.Authorizer != nil {
+			by = c.Authorizer.RegistryNumber
+		}
+		line := fmt.Sprintf("%s  %-9s → %-9s by %-11s %s", util.FormatDateTime(c.ChangedAt),
+			c.PreviousState, c.State, by, c.Reason)
+		b.WriteString("  " + a.theme.Base.Render(Truncate(line, a.width-4)))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("  ")
+	b.WriteString(a.renderActionBar(securityActions))
+	b.WriteString("\n\n")
+	return b.String()
+}
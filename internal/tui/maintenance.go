@@ -0,0 +1,157 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// maintenancePeriods are the periods, in days, the maintenance analytics
+// screen cycles through with [ and ]. The first is shown on opening.
+var maintenancePeriods = []int{90, 30, 365}
+
+// maintenanceParetoRows is the most systems the Pareto chart shows.
+const maintenanceParetoRows = 12
+
+// maintenanceAnalyticsMsg carries the maintenance analytics for the
+// analytics screen of the facilities module.
+type maintenanceAnalyticsMsg struct {
+	analytics *models.MaintenanceAnalytics
+	err       error
+}
+
+// openMaintenanceAnalytics switches to the maintenance analytics screen.
+func (a *App) openMaintenanceAnalytics() tea.Cmd {
+	a.currentModule = ModuleMaintenance
+	return a.loadMaintenanceAnalytics()
+}
+
+// loadMaintenanceAnalytics loads the analytics of the work orders raised
+// in the selected period up to today.
+func (a *App) loadMaintenanceAnalytics() tea.Cmd {
+	days := maintenancePeriods[a.maintenancePeriod]
+	return func() tea.Msg {
+		ctx, cancel := a.reportOperation()
+		defer cancel()
+		analytics, err := a.facilitySvc.MaintenanceAnalytics(ctx, a.clock.Now(), days)
+		return maintenanceAnalyticsMsg{analytics: analytics, err: err}
+	}
+}
+
+// handleMaintenanceAnalytics stores the loaded maintenance analytics.
+func (a *App) handleMaintenanceAnalytics(msg maintenanceAnalyticsMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		a.AddError("Failed to load maintenance analytics", msg.err)
+		return a, nil
+	}
+	a.maintenance = msg.analytics
+	return a, nil
+}
+
+// handleMaintenanceKeys handles key presses on the maintenance analytics
+// screen.
+func (a *App) handleMaintenanceKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "]", "right":
+		a.maintenancePeriod = (a.maintenancePeriod + 1) % len(maintenancePeriods)
+		return a, a.loadMaintenanceAnalytics()
+	case "[", "left":
+		a.maintenancePeriod = (a.maintenancePeriod + len(maintenancePeriods) - 1) % len(maintenancePeriods)
+		return a, a.loadMaintenanceAnalytics()
+	case "r":
+		return a, a.loadMaintenanceAnalytics()
+	}
+	return a, nil
+}
+
+// renderMaintenanceAnalytics renders the maintenance analytics screen: mean
+// time to repair and efficiency recovered per labor hour by category, and a
+// Pareto chart of the systems that failed most, the vital few first, to
+// stock spare parts for.
+func (a *App) renderMaintenanceAnalytics() string {
+	var b strings.Builder
+	b.WriteString(a.theme.Title.Render("═══ MAINTENANCE ANALYTICS ═══"))
+	b.WriteString("\n\n")
+
+	m := a.maintenance
+	if m == nil {
+		b.WriteString(a.theme.Muted.Render("  Maintenance analytics loading..."))
+		return b.String()
+	}
+	b.WriteString(a.theme.Muted.Render(fmt.Sprintf("  Work orders raised %s to %s (%d days)",
+		util.FormatDay(m.From), util.FormatDay(m.To.AddDate(0, 0, -1)),
+		maintenancePeriods[a.maintenancePeriod])))
+	b.WriteString("\n\n")
+
+	if len(m.Categories) == 0 {
+		b.WriteString(a.theme.Muted.Render("  No work orders raised in the period"))
+		b.WriteString("\n\n")
+		b.WriteString(a.theme.Muted.Render("  " + a.theme.Locale.T("[/] period  r reload  Esc back")))
+		return b.String()
+	}
+	width := a.width - 4
+
+	b.WriteString(a.theme.Subtitle.Render("BY CATEGORY"))
+	b.WriteString("\n")
+	header := fmt.Sprintf("  %-16s %7s %8s %9s %9s %10s", "CATEGORY", "ORDERS", "REPAIRS", "MTTR", "LABOR H", "EFF/HOUR")
+	b.WriteString(a.theme.TableHeader.Render(Truncate(header, a.width-2)))
+	b.WriteString("\n")
+	for _, c := range m.Categories {
+		mttr, yield := "-", "-"
+		if c.TimedRepairs > 0 {
+			mttr = fmt.Sprintf("%.1fh", c.MTTR().Hours())
+		}
+		if c.LaborHours > 0 {
+			yield = fmt.Sprintf("%+.1f", c.RecoveredPerHour())
+		}
+		line := fmt.Sprintf("%-16s %7d %8d %9s %9.1f %10s", c.Category, c.Orders, c.Repairs, mttr, c.LaborHours, yield)
+		b.WriteString("  " + a.theme.Base.Render(Truncate(line, width)))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	b.WriteString(a.theme.Subtitle.Render("MOST FAILURE-PRONE SYSTEMS"))
+	b.WriteString("\n")
+	if len(m.Systems) == 0 {
+		b.WriteString(a.theme.Muted.Render("  No repairs raised in the period"))
+		b.WriteString("\n")
+	} else {
+		systems := m.Systems[:min(len(m.Systems), maintenanceParetoRows)]
+		values := make([]float64, len(systems))
+		for i, s := range systems {
+			values[i] = float64(s.Failures)
+		}
+		vital := len(m.VitalFew())
+		const labelWidth = 18 + 1 + 16 + 1 + 4
+		rows := a.theme.ChartStyles().Pareto(values, max(width-labelWidth-6, 10), vital)
+		for i, s := range systems {
+			label := fmt.Sprintf("%-18s %-16s %4d ", Truncate(s.System.SystemCode, 18), s.System.Category, s.Failures)
+			b.WriteString("  " + a.theme.Base.Render(label) + rows[i])
+			b.WriteString("\n")
+		}
+		if len(m.Systems) > len(systems) {
+			b.WriteString(a.theme.Muted.Render(fmt.Sprintf("  ... %d more system(s)", len(m.Systems)-len(systems))))
+			b.WriteString("\n")
+		}
+		b.WriteString(a.theme.Muted.Render(fmt.Sprintf("  %d of %d failures come from %d system(s): stock their spare parts first",
+			vitalFailures(m), m.Failures, vital)))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(a.theme.Muted.Render("  " + a.theme.Locale.T("[/] period  r reload  Esc back")))
+	return b.String()
+}
+
+// vitalFailures counts the failures of the vital few systems.
+func vitalFailures(m *models.MaintenanceAnalytics) int {
+	n := 0
+	for _, s := range m.VitalFew() {
+		n += s.Failures
+	}
+	return n
+}
@@ -0,0 +1,82 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// nutritionDays is the period, in days, the medical screen reports the
+// nutrients drawn over.
+const nutritionDays = 7
+
+// nutritionMsg carries the nutrients drawn by each ration class.
+type nutritionMsg struct {
+	classes []*models.ClassConsumption
+	err     error
+}
+
+// loadNutrition loads the nutrients each ration class drew over the last
+// nutritionDays days.
+func (a *App) loadNutrition() tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := a.reportOperation()
+		defer cancel()
+		classes, err := a.resourceSvc.NutritionReport(ctx, a.clock.Now(), nutritionDays)
+		return nutritionMsg{classes: classes, err: err}
+	}
+}
+
+// renderNutrition renders the nutrients each ration class drew as a share
+// of what its rations called for, flagging the nutrients in deficit.
+func (a *App) renderNutrition() string {
+	var b strings.Builder
+	b.WriteString(a.theme.Subtitle.Render(fmt.Sprintf("NUTRITION (LAST %d DAYS)", nutritionDays)))
+	b.WriteString("\n")
+
+	var tracked []*models.ClassConsumption
+	for _, c := range a.nutrition {
+		if c.NutrientsRequired != (models.Nutrients{}) {
+			tracked = append(tracked, c)
+		}
+	}
+	if len(tracked) == 0 {
+		b.WriteString(a.theme.Base.Render("  No nutrients drawn in the period.\n"))
+		return b.String()
+	}
+
+	header := fmt.Sprintf("  %-16s", "CLASS")
+	for _, n := range models.TrackedNutrients {
+		header += fmt.Sprintf(" %9s", n)
+	}
+	b.WriteString(a.theme.TableHeader.Render(Truncate(header, a.width-2)))
+	b.WriteString("\n")
+	for _, c := range tracked {
+		b.WriteString(a.theme.Base.Render(fmt.Sprintf("  %-16s", c.RationClass)))
+		for _, n := range models.TrackedNutrients {
+			cover := c.NutrientsDrawn.Coverage(c.NutrientsRequired, n)
+			style := a.theme.Success
+			switch {
+			case cover < consumptionLowCoverage:
+				style = a.theme.Error
+			case cover < models.NutrientDeficitShare:
+				style = a.theme.Warning
+			}
+			b.WriteString(style.Render(fmt.Sprintf(" %9s", fmt.Sprintf("%.0f%%", cover*100))))
+		}
+		if deficits := c.NutrientDeficits(); len(deficits) > 0 {
+			names := make([]string, len(deficits))
+			for i, n := range deficits {
+				names[i] = string(n)
+			}
+			b.WriteString(a.theme.Error.Render("  DEFICIT " + strings.Join(names, ", ")))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(a.theme.Muted.Render(fmt.Sprintf("  Drawn as a share of the ration targets; below %.0f%% is a deficit\n",
+		models.NutrientDeficitShare*100)))
+	return b.String()
+}
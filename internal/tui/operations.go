@@ -0,0 +1,42 @@
+package tui
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/journal"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// operation returns the context of a lookup, list or command started from
+// the terminal, cancelled on shutdown or once the configured operation
+// timeout passes. Changes made under it count in the terminal session. Call
+// cancel when the operation returns.
+func (a *App) operation() (context.Context, context.CancelFunc) {
+	ctx, cancel := a.startOperation(a.config.Timeouts.Operation())
+	return journal.Observe(ctx, a.observeCommand), cancel
+}
+
+// reportOperation is operation for reports, forecasts, simulation ticks and
+// scheduled job runs, which are bound by the longer report timeout.
+func (a *App) reportOperation() (context.Context, context.CancelFunc) {
+	return a.startOperation(a.config.Timeouts.Report())
+}
+
+// startOperation derives an operation's context from the terminal's, run
+// for the signed-on operator's clearance, and counts it in flight until
+// cancelled.
+func (a *App) startOperation(timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx := a.ctx
+	if a.operator != nil {
+		ctx = util.WithOperatorClearance(ctx, a.operator.ClearanceLevel)
+	}
+	ctx, cancel := util.WithTimeout(ctx, timeout)
+	a.inFlight.Add(1)
+	var once sync.Once
+	return ctx, func() {
+		once.Do(func() { a.inFlight.Add(-1) })
+		cancel()
+	}
+}
@@ -0,0 +1,483 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// paletteCommand is a command run from the command palette. A command that
+// takes an argument reads it from the text typed after its name.
+type paletteCommand struct {
+	name string
+	help string
+	arg  string // Placeholder of the argument, empty when there is none
+	run  func(a *App, arg string) tea.Cmd
+}
+
+// paletteMatch is a command matching the palette input, with its argument.
+type paletteMatch struct {
+	command *paletteCommand
+	arg     string
+}
+
+// commandPalette is the state of the open command palette.
+type commandPalette struct {
+	input    string
+	selected int
+}
+
+// paletteCommands returns the commands of the palette. Each runs the code
+// path of the key or menu doing the same.
+func paletteCommands() []paletteCommand {
+	commands := []paletteCommand{}
+	for _, m := range []Module{ModuleDashboard, ModulePopulation, ModuleResources, ModuleFacilities,
+		ModuleLabor, ModuleMedical, ModuleSecurity, ModuleGovernance, ModuleSettings} {
+		commands = append(commands, paletteCommand{
+			name: "go to " + string(m),
+			help: "Open the " + string(m) + " module",
+			run:  func(a *App, _ string) tea.Cmd { return a.paletteGoto(m) },
+		})
+	}
+	return append(commands,
+		paletteCommand{name: "go to resident", arg: "registry #", help: "Open a resident's record",
+			run: func(a *App, arg string) tea.Cmd { return a.gotoResident(arg) }},
+		paletteCommand{name: "search residents", arg: "name", help: "Search the census",
+			run: func(a *App, arg string) tea.Cmd { return a.searchResidents(arg) }},
+		paletteCommand{name: "daily digest", help: "Open the daily digest",
+			run: func(a *App, _ string) tea.Cmd { return a.paletteGoto(ModuleDigest) }},
+		paletteCommand{name: "daily report", help: "Show the printable daily vault report",
+			run: func(a *App, _ string) tea.Cmd { return a.openDailyReport() }},
+		paletteCommand{name: "scheduled tasks", help: "Open the scheduled tasks",
+			run: func(a *App, _ string) tea.Cmd { return a.paletteGoto(ModuleTasks) }},
+		paletteCommand{name: "care queue", help: "Open the care assignments",
+			run: func(a *App, _ string) tea.Cmd { return a.paletteGoto(ModuleCare) }},
+		paletteCommand{name: "aptitude assessments", help: "Open the aptitude assessments",
+			run: func(a *App, _ string) tea.Cmd { return a.paletteGoto(ModuleAptitude) }},
+		paletteCommand{name: "switch vault", help: "Change the managed vault",
+			run: func(a *App, _ string) tea.Cmd { return a.paletteGoto(ModuleVaults) }},
+		paletteCommand{name: "diagnostics", help: "Show query timings and the database write queue",
+			run: func(a *App, _ string) tea.Cmd { return a.paletteGoto(ModuleDiagnostics) }},
+		paletteCommand{name: "consumption analytics", help: "Show rations drawn by household, class and department",
+			run: func(a *App, _ string) tea.Cmd { return a.paletteGoto(ModuleConsumption) }},
+		paletteCommand{name: "session activity", help: "Show who used the terminals and what they changed",
+			run: func(a *App, _ string) tea.Cmd { return a.paletteGoto(ModuleSessions) }},
+		paletteCommand{name: "alert queue", help: "Acknowledge, resolve and route warning and critical alerts",
+			run: func(a *App, _ string) tea.Cmd { return a.paletteGoto(ModuleAlerts) }},
+		paletteCommand{name: "system dependencies", help: "Show what each facility system depends on and root causes",
+			run: func(a *App, _ string) tea.Cmd { return a.paletteGoto(ModuleDependencies) }},
+		paletteCommand{name: "work orders", help: "Assign lead technicians to open work orders",
+			run: func(a *App, _ string) tea.Cmd { return a.paletteGoto(ModuleWorkOrders) }},
+		paletteCommand{name: "maintenance analytics", help: "Repair times, efficiency recovered per hour and the most failure-prone systems",
+			run: func(a *App, _ string) tea.Cmd { return a.paletteGoto(ModuleMaintenance) }},
+		paletteCommand{name: "add resident", help: "Register a new resident",
+			run: func(a *App, _ string) tea.Cmd { return a.paletteAddResident("") }},
+		paletteCommand{name: "register birth", help: "Register a vault-born resident",
+			run: func(a *App, _ string) tea.Cmd { return a.paletteAddResident(models.EntryTypeVaultBorn) }},
+		paletteCommand{name: "admit outsider", help: "Admit a visitor or survivor through intake quarantine",
+			run: func(a *App, _ string) tea.Cmd { return a.paletteIntake() }},
+		paletteCommand{name: "register death", help: "Register the selected resident's death",
+			run: func(a *App, _ string) tea.Cmd { return a.paletteCensusAction("d") }},
+		paletteCommand{name: "reassign household", help: "Move the selected resident to a household",
+			run: func(a *App, _ string) tea.Cmd { return a.paletteCensusAction("h") }},
+		paletteCommand{name: "assign vocation", help: "Assign the selected resident a vocation",
+			run: func(a *App, _ string) tea.Cmd { return a.paletteCensusAction("v") }},
+		paletteCommand{name: "quarantine resident", help: "Quarantine the selected resident",
+			run: func(a *App, _ string) tea.Cmd { return a.paletteCensusAction("q") }},
+		paletteCommand{name: "surface mission", help: "Send the selected resident to the surface",
+			run: func(a *App, _ string) tea.Cmd { return a.paletteCensusAction("u") }},
+		paletteCommand{name: "return resident", help: "Return the selected resident to ACTIVE",
+			run: func(a *App, _ string) tea.Cmd { return a.paletteCensusAction("r") }},
+		paletteCommand{name: "set ration class", help: "Set the selected household's ration class",
+			run: func(a *App, _ string) tea.Cmd { return a.paletteCensusAction("c") }},
+		paletteCommand{name: "tag residents", help: "Tag the selected or marked residents",
+			run: func(a *App, _ string) tea.Cmd { return a.paletteCensusAction("t") }},
+		paletteCommand{name: "filter census", help: "Filter the census by age, clearance, vocation, household type or sector",
+			run: func(a *App, _ string) tea.Cmd { return a.paletteCensusFilter() }},
+		paletteCommand{name: "filter by tags", arg: "tags", help: "Limit the census to residents carrying the tags",
+			run: func(a *App, arg string) tea.Cmd { return a.filterByTags(arg) }},
+		paletteCommand{name: "census presets", help: "Apply, save or delete saved census filters",
+			run: func(a *App, _ string) tea.Cmd { return a.paletteCensusPresets() }},
+		paletteCommand{name: "adjust stock", help: "Adjust the selected lot's quantity",
+			run: func(a *App, _ string) tea.Cmd { return a.paletteInventoryAction("x") }},
+		paletteCommand{name: "move stock", help: "Move the selected lot",
+			run: func(a *App, _ string) tea.Cmd { return a.paletteInventoryAction("m") }},
+		paletteCommand{name: "set stock status", help: "Set the selected lot's status",
+			run: func(a *App, _ string) tea.Cmd { return a.paletteInventoryAction("s") }},
+		paletteCommand{name: "asset registry", help: "Open the tools, weapons and Pip-Boys tracked by serial number",
+			run: func(a *App, _ string) tea.Cmd { return a.paletteAssets() }},
+		paletteCommand{name: "pause simulation", help: "Stop the vault clock",
+			run: func(a *App, _ string) tea.Cmd { return a.setSimulationPaused(true) }},
+		paletteCommand{name: "resume simulation", help: "Restart the vault clock",
+			run: func(a *App, _ string) tea.Cmd { return a.setSimulationPaused(false) }},
+		paletteCommand{name: "undo", help: "Undo the last change",
+			run: func(a *App, _ string) tea.Cmd { return a.undoLast() }},
+		paletteCommand{name: "redo", help: "Redo the last change undone",
+			run: func(a *App, _ string) tea.Cmd { return a.redoLast() }},
+		paletteCommand{name: "help", help: "Show the key bindings",
+			run: func(a *App, _ string) tea.Cmd {
+				a.previousModule = a.currentModule
+				a.currentModule = ModuleHelp
+				return nil
+			}},
+		paletteCommand{name: "quit", help: "Quit, with confirmation",
+			run: func(a *App, _ string) tea.Cmd {
+				a.showConfirm = true
+				return nil
+			}},
+	)
+}
+
+// matchCommands returns the commands matching input, best first. Input
+// naming a command that takes an argument, followed by the argument,
+// matches that command alone.
+func matchCommands(commands []paletteCommand, input string) []paletteMatch {
+	query := strings.ToLower(strings.TrimLeft(input, " "))
+	for i := range commands {
+		c := &commands[i]
+		if c.arg != "" && strings.HasPrefix(query, c.name+" ") {
+			return []paletteMatch{{command: c, arg: strings.TrimSpace(input[len(input)-len(query)+len(c.name):])}}
+		}
+	}
+
+	type scored struct {
+		match paletteMatch
+		score int
+	}
+	var found []scored
+	for i := range commands {
+		if score, ok := fuzzyScore(query, commands[i].name); ok {
+			found = append(found, scored{paletteMatch{command: &commands[i]}, score})
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool { return found[i].score > found[j].score })
+
+	matches := make([]paletteMatch, len(found))
+	for i, f := range found {
+		matches[i] = f.match
+	}
+	return matches
+}
+
+// fuzzyScore reports whether the letters of query appear in name in order,
+// spaces aside, and scores the match: a letter starting a word or following
+// the letter matched before it scores higher, so "adj st" ranks "adjust
+// stock" above "assign vocation".
+func fuzzyScore(query, name string) (int, bool) {
+	score, from, last := 0, 0, -1
+	for _, r := range query {
+		if r == ' ' {
+			continue
+		}
+		i := strings.IndexRune(name[from:], r)
+		if i < 0 {
+			return 0, false
+		}
+		i += from
+		score++
+		if i == last+1 {
+			score += 2
+		}
+		if i == 0 || name[i-1] == ' ' {
+			score += 3
+		}
+		last, from = i, i+utf8.RuneLen(r)
+	}
+	return score, true
+}
+
+// handlePaletteKeys handles key presses while the command palette is open.
+func (a *App) handlePaletteKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p := a.palette
+	matches := matchCommands(paletteCommands(), p.input)
+
+	switch key := msg.String(); key {
+	case "esc":
+		a.palette = nil
+	case "up", "ctrl+p":
+		if p.selected > 0 {
+			p.selected--
+		}
+	case "down", "ctrl+n":
+		if p.selected < len(matches)-1 {
+			p.selected++
+		}
+	case "tab":
+		if p.selected < len(matches) {
+			p.complete(matches[p.selected])
+		}
+	case "enter":
+		if p.selected >= len(matches) {
+			return a, nil
+		}
+		match := matches[p.selected]
+		if match.command.arg != "" && match.arg == "" {
+			// Ask for the argument before running
+			p.complete(match)
+			return a, nil
+		}
+		a.palette = nil
+		return a, match.command.run(a, match.arg)
+	case "backspace":
+		if len(p.input) > 0 {
+			p.input = p.input[:len(p.input)-1]
+			p.selected = 0
+		}
+	default:
+		if len(key) == 1 {
+			p.input += key
+			p.selected = 0
+		}
+	}
+
+	return a, nil
+}
+
+// complete fills in the name of the matched command, ready for its
+// argument.
+func (p *commandPalette) complete(match paletteMatch) {
+	p.input = match.command.name
+	if match.command.arg != "" {
+		p.input += " " + match.arg
+	}
+	p.selected = 0
+}
+
+// paletteGoto opens a module for a palette command, as its function key
+// does.
+func (a *App) paletteGoto(m Module) tea.Cmd {
+	if a.currentModule == ModuleSettings && m != ModuleSettings {
+		a.revertSettings()
+	}
+	return a.gotoModule(m)
+}
+
+// paletteOpen opens module m for a palette command acting on its list. It
+// reports false when the module is locked, leaving the operator to sign on.
+func (a *App) paletteOpen(m Module) (tea.Cmd, bool) {
+	if a.moduleLocked(m) {
+		return a.gotoModule(m), false
+	}
+	a.showDetail = false
+	if a.currentModule == m {
+		return nil, true
+	}
+	return a.paletteGoto(m), true
+}
+
+// gotoResident opens the census record of the resident with the given
+// registry number once the census, searched for it, loads.
+func (a *App) gotoResident(registryNumber string) tea.Cmd {
+	cmd, ok := a.paletteOpen(ModulePopulation)
+	if !ok {
+		return cmd
+	}
+	a.showHouseholds, a.showIntakes = false, false
+	a.openResident = strings.ToUpper(registryNumber)
+	a.censusView.SetSearch(a.openResident)
+	return a.loadCensus()
+}
+
+// openPendingResident shows the record of the resident asked for by
+// gotoResident, once the census page searched for it is loaded or failed
+// to load with err.
+func (a *App) openPendingResident(err error) tea.Cmd {
+	registryNumber := a.openResident
+	a.openResident = ""
+	if err != nil {
+		return nil
+	}
+	if !a.censusView.SelectRegistry(registryNumber) {
+		a.AddAlert(AlertWarning, "Resident "+registryNumber+" not found")
+		return nil
+	}
+	resident := a.censusView.SelectedResident()
+	a.showDetail = true
+	a.badge = nil
+	return a.loadRelationships(resident)
+}
+
+// searchResidents searches the census, as the search prompt does.
+func (a *App) searchResidents(term string) tea.Cmd {
+	cmd, ok := a.paletteOpen(ModulePopulation)
+	if !ok {
+		return cmd
+	}
+	a.showHouseholds, a.showIntakes = false, false
+	a.censusView.SetSearch(term)
+	return a.loadCensus()
+}
+
+// filterByTags limits the census to residents carrying every one of the
+// comma-separated tags, as the tag filter prompt does.
+func (a *App) filterByTags(arg string) tea.Cmd {
+	cmd, ok := a.paletteOpen(ModulePopulation)
+	if !ok {
+		return cmd
+	}
+	a.showHouseholds, a.showIntakes = false, false
+	tags, err := models.ParseTags(arg)
+	if err != nil {
+		a.AddError("Invalid tags", err)
+		return cmd
+	}
+	a.censusView.SetTagFilter(tags)
+	return a.loadCensus()
+}
+
+// paletteCensusFilter opens the census filter panel.
+func (a *App) paletteCensusFilter() tea.Cmd {
+	cmd, ok := a.paletteOpen(ModulePopulation)
+	if ok {
+		a.showHouseholds, a.showIntakes = false, false
+		a.openCensusFilter()
+	}
+	return cmd
+}
+
+// paletteCensusPresets opens the census preset menu.
+func (a *App) paletteCensusPresets() tea.Cmd {
+	cmd, ok := a.paletteOpen(ModulePopulation)
+	if !ok {
+		return cmd
+	}
+	a.showHouseholds, a.showIntakes = false, false
+	return tea.Batch(cmd, a.openCensusPresets())
+}
+
+// paletteAddResident opens the new resident form in the census.
+func (a *App) paletteAddResident(entry models.EntryType) tea.Cmd {
+	if a.denyReadOnly() {
+		return nil
+	}
+	cmd, ok := a.paletteOpen(ModulePopulation)
+	if ok {
+		a.addResident(entry)
+	}
+	return cmd
+}
+
+// paletteIntake opens the intake tab with the intake wizard.
+func (a *App) paletteIntake() tea.Cmd {
+	if a.denyReadOnly() {
+		return nil
+	}
+	cmd, ok := a.paletteOpen(ModulePopulation)
+	if !ok {
+		return cmd
+	}
+	a.showHouseholds, a.showIntakes = false, true
+	a.openIntakeWizard()
+	return tea.Batch(cmd, a.loadIntakes())
+}
+
+// paletteCensusAction starts a census quick action on the selected or
+// marked residents.
+func (a *App) paletteCensusAction(key string) tea.Cmd {
+	if a.denyReadOnly() {
+		return nil
+	}
+	cmd, ok := a.paletteOpen(ModulePopulation)
+	if !ok {
+		return cmd
+	}
+	a.showHouseholds, a.showIntakes = false, false
+	if a.censusView.SelectedResident() == nil {
+		a.AddAlert(AlertWarning, "No resident selected")
+		return cmd
+	}
+	a.startCensusAction(key)
+	return cmd
+}
+
+// paletteInventoryAction starts an inventory quick action on the selected
+// or marked stock.
+func (a *App) paletteInventoryAction(key string) tea.Cmd {
+	if a.denyReadOnly() {
+		return nil
+	}
+	cmd, ok := a.paletteOpen(ModuleResources)
+	if !ok {
+		return cmd
+	}
+	a.showAssets = false
+	if a.inventoryView.SelectedStock() == nil {
+		a.AddAlert(AlertWarning, "No stock selected")
+		return cmd
+	}
+	a.startInventoryAction(key)
+	return cmd
+}
+
+// paletteAssets opens the resources module's asset tab.
+func (a *App) paletteAssets() tea.Cmd {
+	cmd, ok := a.paletteOpen(ModuleResources)
+	if !ok {
+		return cmd
+	}
+	a.showAssets = true
+	return tea.Batch(cmd, a.loadAssets())
+}
+
+// setSimulationPaused stops or restarts the vault clock, and with it the
+// simulation.
+func (a *App) setSimulationPaused(paused bool) tea.Cmd {
+	if paused {
+		a.clock.Pause()
+		a.AddAlert(AlertInfo, "Simulation paused")
+	} else {
+		a.clock.Resume()
+		a.AddAlert(AlertInfo, "Simulation resumed")
+	}
+	return nil
+}
+
+// renderPalette renders the open command palette: the input, then the
+// matching commands with the selected one highlighted.
+func (a *App) renderPalette(height int) string {
+	p := a.palette
+	var b strings.Builder
+	b.WriteString(a.theme.Title.Render("═══ COMMAND PALETTE ═══"))
+	b.WriteString("\n\n")
+	b.WriteString(a.theme.Label.Render(":") + a.theme.Accent.Render(p.input) + a.theme.Accent.Render("_"))
+	b.WriteString("\n\n")
+
+	matches := matchCommands(paletteCommands(), p.input)
+	if len(matches) == 0 {
+		b.WriteString(a.theme.Muted.Render("  No matching command"))
+		b.WriteString("\n")
+	}
+
+	// Keep the selected command in view
+	rows := height - 6
+	if rows < 1 {
+		rows = 1
+	}
+	start := 0
+	if p.selected >= rows {
+		start = p.selected - rows + 1
+	}
+	for i := start; i < len(matches) && i < start+rows; i++ {
+		c := matches[i].command
+		name := c.name
+		if c.arg != "" {
+			name += " <" + c.arg + ">"
+		}
+		line := fmt.Sprintf("  %-32s  ", name)
+		if i == p.selected {
+			b.WriteString(a.theme.Accent.Render("> "+line[2:]) + a.theme.Label.Render(c.help))
+		} else {
+			b.WriteString(a.theme.Primary.Render(line) + a.theme.Muted.Render(c.help))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(a.theme.Muted.Render("Type to filter  Up/Down:Select  Tab:Complete  Enter:Run  Esc:Close"))
+	return b.String()
+}
@@ -0,0 +1,57 @@
+package tui
+
+import "testing"
+
+func TestFuzzyScore(t *testing.T) {
+	tests := []struct {
+		query, name string
+		match       bool
+	}{
+		{"", "adjust stock", true},
+		{"adj st", "adjust stock", true},
+		{"adjst", "adjust stock", true},
+		{"rb", "register birth", true},
+		{"stock adjust", "adjust stock", false},
+		{"xyz", "adjust stock", false},
+	}
+
+	for _, tt := range tests {
+		if _, ok := fuzzyScore(tt.query, tt.name); ok != tt.match {
+			t.Errorf("fuzzyScore(%q, %q) matched = %v, want %v", tt.query, tt.name, ok, tt.match)
+		}
+	}
+
+	prefix, _ := fuzzyScore("adj", "adjust stock")
+	scattered, _ := fuzzyScore("adj", "assign vocation and jobs")
+	if prefix <= scattered {
+		t.Errorf("Expected a prefix match to score above a scattered one, got %d and %d", prefix, scattered)
+	}
+}
+
+func TestMatchCommands(t *testing.T) {
+	commands := paletteCommands()
+
+	matches := matchCommands(commands, "reg bir")
+	if len(matches) == 0 || matches[0].command.name != "register birth" {
+		t.Fatalf("Expected register birth first, got %v", matches)
+	}
+
+	matches = matchCommands(commands, "pause")
+	if len(matches) == 0 || matches[0].command.name != "pause simulation" {
+		t.Errorf("Expected pause simulation first, got %v", matches)
+	}
+
+	if matches := matchCommands(commands, ""); len(matches) != len(commands) {
+		t.Errorf("Expected every command for empty input, got %d of %d", len(matches), len(commands))
+	}
+}
+
+func TestMatchCommands_Argument(t *testing.T) {
+	matches := matchCommands(paletteCommands(), "Go to resident V076-00123")
+	if len(matches) != 1 {
+		t.Fatalf("Expected the one command taking the argument, got %d", len(matches))
+	}
+	if matches[0].command.name != "go to resident" || matches[0].arg != "V076-00123" {
+		t.Errorf("Expected go to resident V076-00123, got %s %q", matches[0].command.name, matches[0].arg)
+	}
+}
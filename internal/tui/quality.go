@@ -0,0 +1,92 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/services/resources"
+)
+
+// qualityTestsMsg carries the quality tests of the lot in the detail view.
+type qualityTestsMsg struct {
+	stockID string
+	tests   []*models.QualityTest
+	err     error
+}
+
+// loadQualityTests loads the quality tests of a lot for the detail view.
+func (a *App) loadQualityTests(stockID string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := a.operation()
+		defer cancel()
+		tests, err := a.resourceSvc.ListQualityTests(ctx, stockID)
+		return qualityTestsMsg{stockID: stockID, tests: tests, err: err}
+	}
+}
+
+// startQualityTest prompts for the result of a quality test of the selected
+// lot.
+func (a *App) startQualityTest() {
+	stock := a.inventoryView.SelectedStock()
+	if stock == nil || a.denyReadOnly() {
+		return
+	}
+	name := "stock"
+	if stock.Item != nil {
+		name = stock.Item.Name
+	}
+	a.quickAction = &quickAction{
+		kind:       quickActionQualityTest,
+		prompt:     "Test of " + name + ": PPM PASS|FAIL [notes]: ",
+		targetID:   stock.ID,
+		targetName: name,
+	}
+}
+
+// runQualityTestAction records a quality test from the contamination level,
+// the result and optional notes. A failure quarantines the lot and raises a
+// critical alert.
+func (a *App) runQualityTestAction(ctx context.Context, action *quickAction, input string) quickActionDoneMsg {
+	fields := strings.Fields(input)
+	if len(fields) < 2 {
+		return quickActionDoneMsg{module: ModuleResources, err: fmt.Errorf("%w: expected contamination level and PASS or FAIL", repository.ErrValidation)}
+	}
+	level, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return quickActionDoneMsg{module: ModuleResources, err: fmt.Errorf("%w: invalid contamination level %q", repository.ErrValidation, fields[0])}
+	}
+	var passed bool
+	switch strings.ToUpper(fields[1]) {
+	case "PASS", "P":
+		passed = true
+	case "FAIL", "F":
+	default:
+		return quickActionDoneMsg{module: ModuleResources, err: fmt.Errorf("%w: result must be PASS or FAIL, not %q", repository.ErrValidation, fields[1])}
+	}
+
+	testInput := resources.QualityTestInput{
+		StockID:            action.targetID,
+		ContaminationLevel: level,
+		Passed:             passed,
+		Notes:              strings.Join(fields[2:], " "),
+	}
+	if a.operator != nil {
+		testInput.TestedBy = &a.operator.ID
+	}
+	test, err := a.resourceSvc.RecordQualityTest(ctx, testInput)
+	if err != nil {
+		return quickActionDoneMsg{module: ModuleResources, err: err}
+	}
+	if !test.Passed {
+		return quickActionDoneMsg{module: ModuleResources, alert: AlertCritical, success: fmt.Sprintf(
+			"%s failed quality test at %.2f ppm: lot quarantined", action.targetName, test.ContaminationLevel)}
+	}
+	return quickActionDoneMsg{module: ModuleResources, success: fmt.Sprintf(
+		"%s passed quality test at %.2f ppm", action.targetName, test.ContaminationLevel)}
+}
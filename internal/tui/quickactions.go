@@ -0,0 +1,619 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/services/population"
+	"github.com/vtuos/vtuos/internal/services/resources"
+	"github.com/vtuos/vtuos/internal/tui/components"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// quickActionKind identifies a per-row action started from a list view.
+type quickActionKind int
+
+const (
+	quickActionDeath quickActionKind = iota
+	quickActionHousehold
+	quickActionVocation
+	quickActionAdjust
+	quickActionMove
+	quickActionQuarantine
+	quickActionMission
+	quickActionReturn
+	quickActionDissolve
+	quickActionMerge
+	quickActionSplit
+	quickActionCare
+	quickActionCancelCare
+	quickActionAptitude
+	quickActionApplyAptitude
+	quickActionSignOn
+	quickActionLockdown
+	quickActionBatchHousehold
+	quickActionRationClass
+	quickActionBatchMove
+	quickActionStockStatus
+	quickActionEnroll
+	quickActionMentor
+	quickActionSignOff
+	quickActionWithdraw
+	quickActionRationPolicy
+	quickActionQualityTest
+	quickActionGrantAccess
+	quickActionAccessAttempt
+	quickActionToggleAccessPoint
+	quickActionScreening
+	quickActionClearIntake
+	quickActionRegisterAsset
+	quickActionCheckOutAsset
+	quickActionCheckInAsset
+	quickActionAssetCondition
+	quickActionRetireAsset
+	quickActionIssueWeapon
+	quickActionReturnWeapon
+	quickActionPostAnnouncement
+	quickActionAcknowledge
+	quickActionSetting
+	quickActionAdjustLot
+	quickActionSplitLot
+	quickActionDamageLot
+	quickActionQuarantineLot
+	quickActionAcknowledgeAlert
+	quickActionResolveAlert
+	quickActionAddAlertRoute
+	quickActionRemoveAlertRoute
+	quickActionTag
+	quickActionTagFilter
+	quickActionSavePreset
+	quickActionDeletePreset
+	quickActionCountersign
+)
+
+// quickAction holds the state of an in-progress row action. Confirm actions
+// take y/n, the others collect a single line of input.
+type quickAction struct {
+	kind        quickActionKind
+	prompt      string
+	input       string
+	targetID    string
+	targetIDs   []string             // Rows a batch action applies to
+	targetName  string               // Shown in the result, e.g. a household designation
+	preset      *models.CensusPreset // Census filter and sort a preset saves
+	confirm     bool
+	secret      bool              // Input is masked, e.g. a PIN
+	held        *quickAction      // Action a countersign prompt confirms
+	countersign *util.Countersign // Second operator's confirmation the action runs with
+}
+
+// quickActionDoneMsg is sent when a quick action completes.
+type quickActionDoneMsg struct {
+	module  Module
+	success string
+	alert   AlertLevel   // Level of the success alert, AlertInfo unless set
+	undo    *undoStep    // Set for an undoable action
+	action  *quickAction // Action that ran, to run again with a countersign
+	err     error
+}
+
+// censusActions are the quick actions available on census list rows.
+var censusActions = components.NewActionBar(
+	components.Action{Key: "d", Label: "Death"},
+	components.Action{Key: "h", Label: "Household"},
+	components.Action{Key: "v", Label: "Vocation"},
+	components.Action{Key: "q", Label: "Quarantine"},
+	components.Action{Key: "u", Label: "Surface"},
+	components.Action{Key: "r", Label: "Return"},
+	components.Action{Key: "c", Label: "Rations"},
+	components.Action{Key: "t", Label: "Tag"},
+)
+
+// censusBatchActions are the quick actions available on marked census rows.
+var censusBatchActions = components.NewActionBar(
+	components.Action{Key: "h", Label: "Household"},
+	components.Action{Key: "c", Label: "Rations"},
+	components.Action{Key: "t", Label: "Tag"},
+)
+
+// householdActions are the quick actions available on household list rows.
+var householdActions = components.NewActionBar(
+	components.Action{Key: "x", Label: "Dissolve"},
+	components.Action{Key: "m", Label: "Merge"},
+	components.Action{Key: "p", Label: "Split"},
+)
+
+// inventoryActions are the quick actions available on inventory list rows.
+var inventoryActions = components.NewActionBar(
+	components.Action{Key: "x", Label: "Adjust"},
+	components.Action{Key: "m", Label: "Move"},
+	components.Action{Key: "s", Label: "Status"},
+	components.Action{Key: "n", Label: "New item"},
+	components.Action{Key: "e", Label: "Edit item"},
+)
+
+// inventoryBatchActions are the quick actions available on marked inventory
+// rows.
+var inventoryBatchActions = components.NewActionBar(
+	components.Action{Key: "m", Label: "Move"},
+	components.Action{Key: "s", Label: "Status"},
+)
+
+// censusBar returns the census actions, or those for marked residents when
+// any are marked.
+func (a *App) censusBar() *components.ActionBar {
+	if len(a.censusView.MarkedResidents()) > 0 {
+		return censusBatchActions
+	}
+	return censusActions
+}
+
+// inventoryBar returns the inventory actions, or those for marked stock when
+// any is marked.
+func (a *App) inventoryBar() *components.ActionBar {
+	if len(a.inventoryView.MarkedStocks()) > 0 {
+		return inventoryBatchActions
+	}
+	return inventoryActions
+}
+
+// startCensusAction begins a quick action on the selected resident, or on
+// the marked residents when any are marked.
+func (a *App) startCensusAction(key string) {
+	if marked := a.censusView.MarkedResidents(); len(marked) > 0 {
+		a.startCensusBatchAction(key, marked)
+		return
+	}
+	resident := a.censusView.SelectedResident()
+	if resident == nil {
+		return
+	}
+	if key == "t" {
+		a.startTagAction([]*models.Resident{resident})
+		return
+	}
+	if !resident.IsAlive() {
+		a.AddAlert(AlertWarning, resident.FullName()+" is deceased")
+		return
+	}
+
+	action := &quickAction{targetID: resident.ID}
+	switch key {
+	case "d":
+		action.kind = quickActionDeath
+		action.prompt = "Register death of " + resident.FullName() + "? (y/n)"
+		action.confirm = true
+	case "h":
+		action.kind = quickActionHousehold
+		action.prompt = "Household designation for " + resident.FullName() + ": "
+	case "v":
+		action.kind = quickActionVocation
+		action.prompt = "Vocation code for " + resident.FullName() + ": "
+	case "q":
+		action.kind = quickActionQuarantine
+		action.prompt = "Quarantine " + resident.FullName() + " for days (or until YYYY-MM-DD): "
+	case "u":
+		action.kind = quickActionMission
+		action.prompt = "Surface mission for " + resident.FullName() + ", days (or until YYYY-MM-DD): "
+	case "r":
+		if !resident.Status.IsTemporary() {
+			a.AddAlert(AlertWarning, resident.FullName()+" is "+string(resident.Status))
+			return
+		}
+		action.kind = quickActionReturn
+		action.prompt = "Return " + resident.FullName() + " to ACTIVE? (y/n)"
+		action.confirm = true
+	case "c":
+		if resident.HouseholdID == nil {
+			a.AddAlert(AlertWarning, resident.FullName()+" has no household")
+			return
+		}
+		action.kind = quickActionRationClass
+		action.targetIDs = []string{*resident.HouseholdID}
+		action.prompt = "Ration class for " + resident.FullName() + "'s household: "
+	default:
+		return
+	}
+	a.quickAction = action
+}
+
+// startCensusBatchAction begins a quick action on the marked residents.
+// Only household, ration class and tag changes apply to several at once.
+func (a *App) startCensusBatchAction(key string, marked []*models.Resident) {
+	action := &quickAction{}
+	switch key {
+	case "t":
+		a.startTagAction(marked)
+		return
+	case "h":
+		for _, r := range marked {
+			action.targetIDs = append(action.targetIDs, r.ID)
+		}
+		action.kind = quickActionBatchHousehold
+		action.prompt = fmt.Sprintf("Household designation for %d resident(s): ", len(marked))
+	case "c":
+		for _, r := range marked {
+			if r.HouseholdID != nil && !slices.Contains(action.targetIDs, *r.HouseholdID) {
+				action.targetIDs = append(action.targetIDs, *r.HouseholdID)
+			}
+		}
+		if len(action.targetIDs) == 0 {
+			a.AddAlert(AlertWarning, "No marked resident has a household")
+			return
+		}
+		action.kind = quickActionRationClass
+		action.prompt = fmt.Sprintf("Ration class for %d household(s): ", len(action.targetIDs))
+	default:
+		a.AddAlert(AlertWarning, "Unmark residents to act on one at a time")
+		return
+	}
+	a.quickAction = action
+}
+
+// startHouseholdAction begins a quick action on the selected household.
+func (a *App) startHouseholdAction(key string) {
+	household := a.householdsView.SelectedHousehold()
+	if household == nil {
+		return
+	}
+	if !household.IsActive() {
+		a.AddAlert(AlertWarning, "Household "+household.Designation+" is "+string(household.Status))
+		return
+	}
+
+	action := &quickAction{targetID: household.ID, targetName: household.Designation}
+	switch key {
+	case "x":
+		action.kind = quickActionDissolve
+		action.prompt = "Dissolve " + household.Designation + ", move members to (designation, - for none): "
+	case "m":
+		action.kind = quickActionMerge
+		action.prompt = "Merge " + household.Designation + " into household: "
+	case "p":
+		action.kind = quickActionSplit
+		action.prompt = "Split from " + household.Designation + ", registry numbers: "
+	default:
+		return
+	}
+	a.quickAction = action
+}
+
+// startInventoryAction begins a quick action on the selected stock, or on
+// the marked stock when any is marked.
+func (a *App) startInventoryAction(key string) {
+	if marked := a.inventoryView.MarkedStocks(); len(marked) > 0 {
+		a.startInventoryBatchAction(key, marked)
+		return
+	}
+	stock := a.inventoryView.SelectedStock()
+	if stock == nil {
+		return
+	}
+
+	name := "stock"
+	if stock.Item != nil {
+		name = stock.Item.Name
+	}
+
+	action := &quickAction{targetID: stock.ID, targetName: name}
+	switch key {
+	case "x":
+		action.kind = quickActionAdjust
+		action.prompt = "Adjust " + name + " by (+/-): "
+	case "m":
+		action.kind = quickActionMove
+		action.prompt = "Move " + name + " from " + stock.StorageLocation + " to: "
+	case "s":
+		action.kind = quickActionStockStatus
+		action.targetIDs = []string{stock.ID}
+		action.prompt = "Status for " + name + ", now " + string(stock.Status) + ": "
+	default:
+		return
+	}
+	a.quickAction = action
+}
+
+// startInventoryBatchAction begins a quick action on the marked stock. Only
+// moves and status changes apply to several lots at once.
+func (a *App) startInventoryBatchAction(key string, marked []*models.ResourceStock) {
+	action := &quickAction{}
+	for _, stock := range marked {
+		action.targetIDs = append(action.targetIDs, stock.ID)
+	}
+	switch key {
+	case "m":
+		action.kind = quickActionBatchMove
+		action.prompt = fmt.Sprintf("Move %d lot(s) to: ", len(marked))
+	case "s":
+		action.kind = quickActionStockStatus
+		action.prompt = fmt.Sprintf("Status for %d lot(s): ", len(marked))
+	default:
+		a.AddAlert(AlertWarning, "Unmark lots to act on one at a time")
+		return
+	}
+	a.quickAction = action
+}
+
+// handleQuickActionKeys handles key presses while a quick action is active.
+func (a *App) handleQuickActionKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	action := a.quickAction
+
+	if action.confirm {
+		switch key {
+		case "y", "Y", "enter":
+			a.quickAction = nil
+			return a, a.runQuickAction(action)
+		case "n", "N", "esc":
+			a.quickAction = nil
+		}
+		return a, nil
+	}
+
+	switch key {
+	case "esc":
+		a.quickAction = nil
+	case "enter":
+		a.quickAction = nil
+		if strings.TrimSpace(action.input) == "" {
+			return a, nil
+		}
+		if action.kind == quickActionCountersign {
+			return a, a.countersign(action)
+		}
+		return a, a.runQuickAction(action)
+	case "backspace":
+		if len(action.input) > 0 {
+			action.input = action.input[:len(action.input)-1]
+		}
+	default:
+		if len(key) == 1 {
+			action.input += key
+		}
+	}
+
+	return a, nil
+}
+
+// runQuickAction performs the action against the services, noting the
+// action on its result so that one held for a second operator can be run
+// again once they confirm it.
+func (a *App) runQuickAction(action *quickAction) tea.Cmd {
+	run := a.quickActionCmd(action)
+	return func() tea.Msg {
+		msg := run()
+		if done, ok := msg.(quickActionDoneMsg); ok {
+			done.action = action
+			return done
+		}
+		return msg
+	}
+}
+
+// quickActionCmd is the command running an action for runQuickAction.
+func (a *App) quickActionCmd(action *quickAction) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := a.operation()
+		defer cancel()
+		if action.countersign != nil {
+			ctx = util.WithCountersign(ctx, *action.countersign)
+		}
+		input := strings.TrimSpace(action.input)
+
+		switch action.kind {
+		case quickActionDeath:
+			resident, err := a.populationSvc.GetResident(ctx, action.targetID)
+			if err != nil {
+				return quickActionDoneMsg{module: ModulePopulation, err: err}
+			}
+			registration := population.DeathRegistration{
+				DateOfDeath: a.clock.Now(),
+				Cause:       "Cause pending investigation",
+			}
+			err = a.populationSvc.RegisterDeath(ctx, action.targetID, registration)
+			return quickActionDoneMsg{module: ModulePopulation, success: "Death registered", undo: a.deathStep(resident, registration), err: err}
+
+		case quickActionHousehold:
+			err := a.populationSvc.AssignToHouseholdByDesignation(ctx, action.targetID, strings.ToUpper(input))
+			return quickActionDoneMsg{module: ModulePopulation, success: "Assigned to household " + strings.ToUpper(input), err: err}
+
+		case quickActionVocation:
+			err := a.populationSvc.AssignVocation(ctx, action.targetID, strings.ToUpper(input))
+			return quickActionDoneMsg{module: ModulePopulation, success: "Assigned vocation " + strings.ToUpper(input), err: err}
+
+		case quickActionQuarantine, quickActionMission:
+			end, err := parseExpectedEnd(input, a.clock.Now())
+			if err != nil {
+				return quickActionDoneMsg{module: ModulePopulation, err: err}
+			}
+			status := models.ResidentStatusQuarantine
+			if action.kind == quickActionMission {
+				status = models.ResidentStatusSurfaceMission
+			}
+			// Quarantine ends on its own; a surface mission may not come home
+			// on time, so its return is confirmed by the operator.
+			_, err = a.populationSvc.ScheduleStatus(ctx, action.targetID, population.ScheduleStatusInput{
+				Status:      status,
+				ExpectedEnd: end,
+				Reason:      "Scheduled from census",
+				AutoRevert:  status == models.ResidentStatusQuarantine,
+			})
+			return quickActionDoneMsg{module: ModulePopulation, success: fmt.Sprintf("%s until %s", status, util.FormatDate(end)), err: err}
+
+		case quickActionReturn:
+			err := a.populationSvc.EndStatus(ctx, action.targetID)
+			return quickActionDoneMsg{module: ModulePopulation, success: "Returned to ACTIVE", err: err}
+
+		case quickActionDissolve:
+			var targetID string
+			if input != "-" {
+				target, err := a.populationSvc.GetHouseholdByDesignation(ctx, strings.ToUpper(input))
+				if err != nil {
+					return quickActionDoneMsg{module: ModulePopulation, err: err}
+				}
+				targetID = target.ID
+			}
+			err := a.populationSvc.DissolveHousehold(ctx, action.targetID, targetID)
+			return quickActionDoneMsg{module: ModulePopulation, success: "Household " + action.targetName + " dissolved", err: err}
+
+		case quickActionMerge:
+			target, err := a.populationSvc.GetHouseholdByDesignation(ctx, strings.ToUpper(input))
+			if err != nil {
+				return quickActionDoneMsg{module: ModulePopulation, err: err}
+			}
+			_, err = a.populationSvc.MergeHouseholds(ctx, action.targetID, target.ID)
+			return quickActionDoneMsg{module: ModulePopulation, success: action.targetName + " merged into " + target.Designation, err: err}
+
+		case quickActionSplit:
+			registryNumbers := strings.FieldsFunc(strings.ToUpper(input), func(r rune) bool {
+				return r == ',' || r == ' '
+			})
+			split, err := a.populationSvc.SplitHouseholdByRegistry(ctx, action.targetID, registryNumbers)
+			if err != nil {
+				return quickActionDoneMsg{module: ModulePopulation, err: err}
+			}
+			return quickActionDoneMsg{module: ModulePopulation, success: fmt.Sprintf("Split %d member(s) from %s into %s", len(registryNumbers), action.targetName, split.Designation)}
+
+		case quickActionAdjust:
+			change, err := strconv.ParseFloat(input, 64)
+			if err != nil {
+				return quickActionDoneMsg{module: ModuleResources, err: fmt.Errorf("%w: invalid quantity %q", repository.ErrValidation, input)}
+			}
+			adjustment := resources.StockAdjustment{
+				QuantityChange: change,
+				Type:           models.TransactionTypeAdjustment,
+				Reason:         "Quick adjustment",
+			}
+			err = a.resourceSvc.AdjustStock(ctx, action.targetID, adjustment)
+			return quickActionDoneMsg{module: ModuleResources, success: fmt.Sprintf("Stock adjusted by %+g", change),
+				undo: a.adjustStep(action.targetID, action.targetName, adjustment), err: err}
+
+		case quickActionMove:
+			err := a.resourceSvc.MoveStock(ctx, action.targetID, input, nil)
+			return quickActionDoneMsg{module: ModuleResources, success: "Stock moved to " + input, err: err}
+
+		case quickActionBatchHousehold:
+			household, err := a.populationSvc.GetHouseholdByDesignation(ctx, strings.ToUpper(input))
+			if err != nil {
+				return quickActionDoneMsg{module: ModulePopulation, err: err}
+			}
+			err = a.populationSvc.AssignToHouseholdBatch(ctx, action.targetIDs, household.ID)
+			return quickActionDoneMsg{module: ModulePopulation, success: fmt.Sprintf("Assigned %d resident(s) to household %s", len(action.targetIDs), household.Designation), err: err}
+
+		case quickActionRationClass:
+			class := models.RationClass(strings.ToUpper(input))
+			err := a.populationSvc.SetRationClassBatch(ctx, action.targetIDs, class)
+			return quickActionDoneMsg{module: ModulePopulation, success: fmt.Sprintf("Ration class %s set for %d household(s)", class, len(action.targetIDs)), err: err}
+
+		case quickActionBatchMove:
+			err := a.resourceSvc.MoveStockBatch(ctx, action.targetIDs, input, nil)
+			return quickActionDoneMsg{module: ModuleResources, success: fmt.Sprintf("%d lot(s) moved to %s", len(action.targetIDs), input), err: err}
+
+		case quickActionStockStatus:
+			status := models.StockStatus(strings.ToUpper(input))
+			err := a.resourceSvc.SetStockStatusBatch(ctx, action.targetIDs, status, nil)
+			return quickActionDoneMsg{module: ModuleResources, success: fmt.Sprintf("Status %s set for %d lot(s)", status, len(action.targetIDs)), err: err}
+
+		case quickActionCare, quickActionCancelCare:
+			return a.runCareAction(ctx, action, input)
+
+		case quickActionAptitude, quickActionApplyAptitude:
+			return a.runAptitudeAction(ctx, action, input)
+
+		case quickActionSignOn, quickActionLockdown:
+			return a.runOperatorAction(ctx, action, input)
+
+		case quickActionEnroll, quickActionMentor, quickActionSignOff, quickActionWithdraw:
+			return a.runTrainingAction(ctx, action, input)
+
+		case quickActionRationPolicy:
+			return a.runRationPolicyAction(ctx, action, input)
+
+		case quickActionQualityTest:
+			return a.runQualityTestAction(ctx, action, input)
+
+		case quickActionAdjustLot, quickActionSplitLot, quickActionDamageLot, quickActionQuarantineLot:
+			return a.runStockDetailAction(ctx, action, input)
+
+		case quickActionGrantAccess, quickActionAccessAttempt, quickActionToggleAccessPoint:
+			return a.runAccessAction(ctx, action, input)
+
+		case quickActionScreening, quickActionClearIntake:
+			return a.runIntakeAction(ctx, action, input)
+
+		case quickActionRegisterAsset, quickActionCheckOutAsset, quickActionCheckInAsset,
+			quickActionAssetCondition, quickActionRetireAsset:
+			return a.runAssetAction(ctx, action, input)
+
+		case quickActionIssueWeapon, quickActionReturnWeapon:
+			return a.runArmoryAction(ctx, action, input)
+
+		case quickActionPostAnnouncement, quickActionAcknowledge:
+			return a.runAnnouncementAction(ctx, action, input)
+
+		case quickActionAcknowledgeAlert, quickActionResolveAlert, quickActionAddAlertRoute, quickActionRemoveAlertRoute:
+			return a.runAlertQueueAction(ctx, action, input)
+
+		case quickActionTag, quickActionTagFilter, quickActionSavePreset, quickActionDeletePreset:
+			return a.runCensusTagAction(ctx, action, input)
+
+		case quickActionSetting:
+			return a.runSettingAction(action, input)
+		}
+
+		return nil
+	}
+}
+
+// parseExpectedEnd reads the last day of a scheduled status from a number of
+// days, counting today, or a YYYY-MM-DD date.
+func parseExpectedEnd(input string, now time.Time) (time.Time, error) {
+	if days, err := strconv.Atoi(input); err == nil {
+		if days < 1 {
+			return time.Time{}, fmt.Errorf("%w: days must be at least 1", repository.ErrValidation)
+		}
+		return time.Date(now.Year(), now.Month(), now.Day()+days-1, 0, 0, 0, 0, time.UTC), nil
+	}
+	end, err := time.Parse(time.DateOnly, input)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: invalid days or date %q", repository.ErrValidation, input)
+	}
+	return end, nil
+}
+
+// renderActionBar renders the active quick action prompt, or the available
+// actions for the list when none is active. On a read-only terminal the
+// actions are grayed out.
+func (a *App) renderActionBar(bar *components.ActionBar) string {
+	return a.renderActionBarIn(bar, a.width)
+}
+
+// renderActionBarIn is renderActionBar for a pane of the given width.
+func (a *App) renderActionBarIn(bar *components.ActionBar, width int) string {
+	if a.quickAction != nil {
+		line := a.theme.Label.Render(a.quickAction.prompt)
+		if !a.quickAction.confirm {
+			input := a.quickAction.input
+			if a.quickAction.secret {
+				input = strings.Repeat("*", len(input))
+			}
+			line += a.theme.Accent.Render(input) + a.theme.Accent.Render("_")
+		}
+		return line
+	}
+	bar.SetLocale(a.theme.Locale)
+	if a.readOnly {
+		// Actions stay listed but grayed out, as none can run
+		bar.SetStyles(a.theme.Muted, a.theme.Muted)
+		return bar.Render(width)
+	}
+	bar.SetStyles(a.theme.Accent, a.theme.Label)
+	return bar.Render(width)
+}
@@ -0,0 +1,63 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/vtuos/vtuos/internal/models"
+)
+
+// radiationListRows is how many flagged residents the medical screen lists.
+const radiationListRows = 8
+
+// radiationBarMaxMSv is the dose a full exposure bar stands for, the
+// CRITICAL threshold.
+const radiationBarMaxMSv = 4000
+
+// radiationMsg carries the residents flagged for radiation exposure.
+type radiationMsg struct {
+	doses []*models.RadiationDose
+	err   error
+}
+
+// loadRadiation lists the residents whose cumulative dose is flagged.
+func (a *App) loadRadiation() tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := a.operation()
+		defer cancel()
+		doses, err := a.medicalSvc.ListFlagged(ctx)
+		return radiationMsg{doses: doses, err: err}
+	}
+}
+
+// renderRadiationExposure renders the flagged residents, highest dose first,
+// with each dose as a bar up to the CRITICAL threshold.
+func (a *App) renderRadiationExposure(barWidth int) string {
+	var b strings.Builder
+	b.WriteString(a.theme.Subtitle.Render("RESIDENT EXPOSURE"))
+	b.WriteString("\n")
+
+	if len(a.radiationFlags) == 0 {
+		b.WriteString(a.theme.Base.Render(fmt.Sprintf("  No residents above %d mSv.\n", models.RadiationFlagMSv)))
+		return b.String()
+	}
+
+	for i, d := range a.radiationFlags {
+		if i == radiationListRows {
+			b.WriteString(a.theme.Muted.Render(fmt.Sprintf("  ... and %d more\n", len(a.radiationFlags)-i)))
+			break
+		}
+		style := a.theme.Warning
+		if d.Level.Rank() >= models.RadiationLevelSevere.Rank() {
+			style = a.theme.Error
+		}
+		b.WriteString(fmt.Sprintf("  %-12s ", d.Resident.RegistryNumber))
+		b.WriteString(a.theme.ProgressBar(d.CumulativeMSv(), radiationBarMaxMSv, barWidth))
+		b.WriteString(a.theme.Value.Render(fmt.Sprintf(" %7.0f mSv ", d.CumulativeMSv())))
+		b.WriteString(style.Render(string(d.Level)))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
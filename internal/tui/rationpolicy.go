@@ -0,0 +1,207 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/services/governance"
+	"github.com/vtuos/vtuos/internal/tui/components"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// rationPolicyHistoryRows is how many policies of the selected class the
+// governance screen lists.
+const rationPolicyHistoryRows = 5
+
+// rationPolicyActions are the actions available on the governance screen's
+// ration classes.
+var rationPolicyActions = components.NewActionBar(
+	components.Action{Key: "e", Label: "Set policy"},
+)
+
+// rationPoliciesMsg carries every ration policy, latest effective first.
+type rationPoliciesMsg struct {
+	policies []*models.RationPolicy
+	err      error
+}
+
+// loadRationPolicies loads the ration policies of every class.
+func (a *App) loadRationPolicies() tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := a.operation()
+		defer cancel()
+		policies, err := a.governanceSvc.ListRationPolicies(ctx, "")
+		return rationPoliciesMsg{policies: policies, err: err}
+	}
+}
+
+// handleGovernanceKeys handles key presses on the governance screen, whose
+// ration class list takes the policy editor, passing those of the inbox on.
+func (a *App) handleGovernanceKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		if a.rationClassIndex > 0 {
+			a.rationClassIndex--
+		}
+	case "down", "j":
+		if a.rationClassIndex < len(models.RationClasses)-1 {
+			a.rationClassIndex++
+		}
+	case "e", "enter":
+		if a.denyReadOnly() {
+			return a, nil
+		}
+		class := models.RationClasses[a.rationClassIndex]
+		a.quickAction = &quickAction{
+			kind:       quickActionRationPolicy,
+			prompt:     fmt.Sprintf("New %s policy: KCAL WATER-L [P/C/F/V] [%s] [reason]: ", class, util.DateFormat),
+			targetName: string(class),
+		}
+	case "b", "n", "a", "s":
+		return a.handleAnnouncementKeys(msg)
+	case "r":
+		return a, tea.Batch(a.loadRationPolicies(), a.loadAnnouncements())
+	}
+	return a, nil
+}
+
+// runRationPolicyAction sets the selected class's policy from the calorie
+// and water targets, optional nutrient targets as protein, carbohydrate and
+// fat grams and vitamin percent, e.g. 60/275/65/100, an optional effective
+// date and a reason.
+func (a *App) runRationPolicyAction(ctx context.Context, action *quickAction, input string) quickActionDoneMsg {
+	fields := strings.Fields(input)
+	if len(fields) < 2 {
+		return quickActionDoneMsg{module: ModuleGovernance, err: fmt.Errorf("%w: expected calories and liters of water", repository.ErrValidation)}
+	}
+	calories, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return quickActionDoneMsg{module: ModuleGovernance, err: fmt.Errorf("%w: invalid calories %q", repository.ErrValidation, fields[0])}
+	}
+	water, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil {
+		return quickActionDoneMsg{module: ModuleGovernance, err: fmt.Errorf("%w: invalid liters %q", repository.ErrValidation, fields[1])}
+	}
+
+	policy := governance.RationPolicyInput{
+		RationClass:   models.RationClass(action.targetName),
+		CalorieTarget: calories,
+		WaterTargetL:  water,
+	}
+	rest := fields[2:]
+	if len(rest) > 0 && strings.Contains(rest[0], "/") {
+		nutrients, err := models.ParseNutrients(rest[0])
+		if err != nil {
+			return quickActionDoneMsg{module: ModuleGovernance, err: fmt.Errorf("%w: %w", repository.ErrValidation, err)}
+		}
+		policy.Nutrients = &nutrients
+		rest = rest[1:]
+	}
+	if len(rest) > 0 {
+		if date, err := util.ParseDate(rest[0]); err == nil {
+			policy.EffectiveFrom = date
+			rest = rest[1:]
+		}
+	}
+	policy.Reason = strings.Join(rest, " ")
+	if a.operator != nil {
+		policy.SetBy = a.operator.ID
+	}
+
+	set, err := a.governanceSvc.SetRationPolicy(ctx, policy)
+	if err != nil {
+		return quickActionDoneMsg{module: ModuleGovernance, err: err}
+	}
+	return quickActionDoneMsg{module: ModuleGovernance, success: fmt.Sprintf("%s rations set to %d kcal, %.1f L from %s",
+		set.RationClass, set.CalorieTarget, set.WaterTargetL, util.FormatDate(set.EffectiveFrom))}
+}
+
+// renderRationPolicies renders the policy of each ration class in force,
+// with any scheduled change, and the recent policies of the selected class.
+func (a *App) renderRationPolicies() string {
+	var b strings.Builder
+	b.WriteString(a.theme.Subtitle.Render("RATION POLICY"))
+	b.WriteString("\n")
+
+	now := a.clock.Now()
+	active := models.ActiveRationPolicies(a.rationPolicies, now)
+	for i, class := range models.RationClasses {
+		since := "built-in"
+		if p, ok := active[class]; ok {
+			since = "since " + util.FormatDate(p.EffectiveFrom)
+			if p.EffectiveFrom.Year() <= 1 {
+				since = "initial"
+			}
+		}
+		line := fmt.Sprintf("%-16s %5d kcal %5.1f L  %-15s %-18s", class,
+			active.Calories(class), active.WaterL(class), active.Nutrients(class), since)
+		if next := nextRationPolicy(a.rationPolicies, class, now); next != nil {
+			line += fmt.Sprintf(" → %d kcal, %.1f L on %s", next.CalorieTarget, next.WaterTargetL, util.FormatDate(next.EffectiveFrom))
+		}
+		line = Truncate(line, a.width-4)
+
+		if i == a.rationClassIndex {
+			b.WriteString(a.theme.Selected.Render("> " + line))
+		} else {
+			b.WriteString("  " + a.theme.Base.Render(line))
+		}
+		b.WriteString("\n")
+	}
+
+	class := models.RationClasses[a.rationClassIndex]
+	b.WriteString(a.theme.Muted.Render(fmt.Sprintf("  %s history\n", class)))
+	shown := 0
+	for _, p := range a.rationPolicies {
+		if p.RationClass != class {
+			continue
+		}
+		if shown == rationPolicyHistoryRows {
+			break
+		}
+		shown++
+		state := ""
+		switch {
+		case p.EffectiveFrom.After(now):
+			state = "SCHEDULED"
+		case active[class] == p:
+			state = "IN FORCE"
+		}
+		date := util.FormatDate(p.EffectiveFrom)
+		if p.EffectiveFrom.Year() <= 1 {
+			date = "initial"
+		}
+		b.WriteString(a.theme.Base.Render(Truncate(fmt.Sprintf("    %-10s %5d kcal %5.1f L  %-9s %s",
+			date, p.CalorieTarget, p.WaterTargetL, state, p.Reason), a.width-2)))
+		b.WriteString("\n")
+	}
+	if shown == 0 {
+		b.WriteString(a.theme.Muted.Render("    No policies; built-in targets apply\n"))
+	}
+
+	b.WriteString("\n  ")
+	b.WriteString(a.renderActionBar(rationPolicyActions))
+	b.WriteString("\n")
+	b.WriteString(a.theme.Muted.Render("  " + a.theme.Locale.T("↑/↓ select class  r reload")))
+	b.WriteString("\n")
+	return b.String()
+}
+
+// nextRationPolicy returns the earliest scheduled policy of a class after
+// now, nil if none.
+func nextRationPolicy(policies []*models.RationPolicy, class models.RationClass, now time.Time) *models.RationPolicy {
+	var next *models.RationPolicy
+	for _, p := range policies {
+		if p.RationClass == class && p.EffectiveFrom.After(now) &&
+			(next == nil || p.EffectiveFrom.Before(next.EffectiveFrom)) {
+			next = p
+		}
+	}
+	return next
+}
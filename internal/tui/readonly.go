@@ -0,0 +1,15 @@
+package tui
+
+// readOnlyMessage is shown when a change is attempted on a read-only terminal.
+const readOnlyMessage = "Read-only terminal: changes are disabled"
+
+// denyReadOnly reports whether the terminal is read-only, alerting the
+// operator that the change they asked for is disabled. Mutating keys check
+// it before opening a form or starting an action.
+func (a *App) denyReadOnly() bool {
+	if !a.readOnly {
+		return false
+	}
+	a.AddAlert(AlertInfo, readOnlyMessage)
+	return true
+}
@@ -0,0 +1,32 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/services/population"
+)
+
+// relationshipsMsg carries the relationships and tags of the resident in
+// the detail view.
+type relationshipsMsg struct {
+	residentID    string
+	relationships []population.ResidentRelationship
+	tags          []string
+	err           error
+}
+
+// loadRelationships loads the relationships and tags of a resident for the
+// detail view.
+func (a *App) loadRelationships(resident *models.Resident) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := a.operation()
+		defer cancel()
+		rels, err := a.populationSvc.GetRelationships(ctx, resident.ID)
+		if err != nil {
+			return relationshipsMsg{residentID: resident.ID, err: err}
+		}
+		tags, err := a.populationSvc.GetResidentTags(ctx, resident.ID)
+		return relationshipsMsg{residentID: resident.ID, relationships: rels, tags: tags, err: err}
+	}
+}
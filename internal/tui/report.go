@@ -0,0 +1,136 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/vtuos/vtuos/internal/config"
+	"github.com/vtuos/vtuos/internal/services/governance"
+	"github.com/vtuos/vtuos/internal/simulation"
+)
+
+// dailyReportMsg carries a built daily vault report.
+type dailyReportMsg struct {
+	report *governance.DailyReport
+	err    error
+}
+
+// reportWrittenMsg is sent when the shown report has been written to file.
+type reportWrittenMsg struct {
+	path string
+	err  error
+}
+
+// dailyReportJob is the scheduled job that writes the daily vault report of
+// the vault day just ended to the report directory, at vault midnight.
+func dailyReportJob(cfg *config.Config, govSvc *governance.Service) simulation.Job {
+	return simulation.Job{
+		Name:     "Daily vault report",
+		Interval: simulation.Daily,
+		Run: func(ctx context.Context, at time.Time) (string, error) {
+			report, err := govSvc.DailyReport(ctx, at.AddDate(0, 0, -1), cfg.Simulation.Consumption.ExpirationWarningDays)
+			if err != nil {
+				return "", err
+			}
+			path, err := writeDailyReport(cfg, report)
+			if err != nil {
+				return "", err
+			}
+			return "report written to " + path, nil
+		},
+	}
+}
+
+// writeDailyReport writes report to the report directory and returns the
+// path of the file.
+func writeDailyReport(cfg *config.Config, report *governance.DailyReport) (string, error) {
+	dir, err := config.ReportDir(cfg)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return "", fmt.Errorf("creating report directory: %w", err)
+	}
+	path := filepath.Join(dir, report.FileName())
+	if err := os.WriteFile(path, []byte(report.Text()), 0640); err != nil {
+		return "", fmt.Errorf("writing report: %w", err)
+	}
+	return path, nil
+}
+
+// loadDailyReport builds the daily vault report of the digest's vault day.
+func (a *App) loadDailyReport() tea.Cmd {
+	day := a.digestDay
+	return func() tea.Msg {
+		ctx, cancel := a.reportOperation()
+		defer cancel()
+		report, err := a.governanceSvc.DailyReport(ctx, day, a.config.Simulation.Consumption.ExpirationWarningDays)
+		return dailyReportMsg{report: report, err: err}
+	}
+}
+
+// writeReport writes the shown daily report to the report directory. It
+// only writes a file, so it is allowed on read-only terminals.
+func (a *App) writeReport() tea.Cmd {
+	report := a.report
+	return func() tea.Msg {
+		path, err := writeDailyReport(a.config, report)
+		return reportWrittenMsg{path: path, err: err}
+	}
+}
+
+// openDailyReport opens the daily vault report of the current vault day.
+func (a *App) openDailyReport() tea.Cmd {
+	cmd, ok := a.paletteOpen(ModuleDigest)
+	if !ok {
+		return cmd
+	}
+	a.showReport = true
+	a.reportScroll = 0
+	return tea.Batch(cmd, a.loadDailyReport())
+}
+
+// renderReport renders the daily vault report as it prints, scrolled to
+// fit the content area.
+func (a *App) renderReport() string {
+	var b strings.Builder
+	b.WriteString(a.theme.Title.Render("═══ DAILY VAULT REPORT ═══"))
+	b.WriteString("\n\n")
+
+	if a.report == nil || !a.report.Day.Equal(dayStart(a.digestDay)) {
+		b.WriteString(a.theme.Muted.Render("  Report loading..."))
+		return b.String()
+	}
+
+	lines := strings.Split(strings.TrimRight(a.report.Text(), "\n"), "\n")
+	rows := ContentHeight(a.height, chromeLines) - 4
+	if rows < 1 {
+		rows = 1
+	}
+	start := a.reportScroll
+	if last := len(lines) - rows; start > last {
+		start = last
+	}
+	if start < 0 {
+		start = 0
+	}
+	for i := start; i < len(lines) && i < start+rows; i++ {
+		b.WriteString("  " + a.theme.Base.Render(Truncate(lines[i], a.width-4)))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(a.theme.Muted.Render("  " + a.theme.Locale.T("↑/↓ scroll  ←/→ previous/next day  w write to file  p digest  Esc back")))
+	return b.String()
+}
+
+// dayStart returns midnight at the start of t's day.
+func dayStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,225 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/vtuos/vtuos/internal/config"
+	"github.com/vtuos/vtuos/internal/database"
+	"github.com/vtuos/vtuos/internal/services/facilities"
+	"github.com/vtuos/vtuos/internal/services/population"
+	"github.com/vtuos/vtuos/internal/services/resources"
+	"github.com/vtuos/vtuos/internal/simulation"
+	"github.com/vtuos/vtuos/internal/training"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// censusSnapshotJob is the scheduled job that takes a database snapshot at
+// the start of every vault quarter, recording the census with it.
+func censusSnapshotJob(db *database.DB, cfg *config.Config, popSvc *population.Service) simulation.Job {
+	return simulation.Job{
+		Name:     "Quarterly census snapshot",
+		Interval: simulation.Quarterly,
+		Run: func(ctx context.Context, at time.Time) (string, error) {
+			dir, err := config.SnapshotDir(cfg)
+			if err != nil {
+				return "", err
+			}
+			stats, err := popSvc.GetPopulationStats(ctx)
+			if err != nil {
+				return "", fmt.Errorf("counting population: %w", err)
+			}
+			snap, err := db.CreateSnapshot(ctx, dir, database.SnapshotInfo{
+				Name:       at.Format("census-20060102-1504"),
+				Note:       "Quarterly census snapshot",
+				VaultTime:  at,
+				Population: stats.TotalActive,
+			})
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("snapshot %s, %d active residents", snap.Name, snap.Population), nil
+		},
+	}
+}
+
+// databaseMaintenanceJob is the scheduled job that optimizes the database
+// at vault midnight every week, when the vault is quietest. It only
+// releases free pages a database allows incremental vacuum of; converting
+// an older database is left to `vtuos db optimize --convert`.
+func databaseMaintenanceJob(db *database.DB) simulation.Job {
+	return simulation.Job{
+		Name:     "Database maintenance",
+		Interval: simulation.Weekly,
+		Run: func(ctx context.Context, at time.Time) (string, error) {
+			result, err := db.Optimize(ctx, false)
+			if err != nil {
+				return "", err
+			}
+			return result.String(), nil
+		},
+	}
+}
+
+// taskRunMsg carries the outcome of a manually triggered scheduled task.
+type taskRunMsg struct {
+	events []simulation.Event
+	err    error
+}
+
+// openTasks switches to the scheduled tasks screen.
+func (a *App) openTasks() {
+	a.currentModule = ModuleTasks
+	if jobs := a.scheduler.Jobs(); a.taskIndex >= len(jobs) {
+		a.taskIndex = 0
+	}
+}
+
+// runTask triggers the selected scheduled task at the current vault time.
+func (a *App) runTask(name string) tea.Cmd {
+	at := a.clock.Now()
+	return func() tea.Msg {
+		ctx, cancel := a.reportOperation()
+		defer cancel()
+		events, err := a.scheduler.Trigger(ctx, name, at)
+		return taskRunMsg{events: events, err: errors.Join(err, a.recordAlerts(ctx, events))}
+	}
+}
+
+// handleTaskRun alerts with the outcome of a triggered task and refreshes
+// the dashboard data it may have changed.
+func (a *App) handleTaskRun(msg taskRunMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		a.AddError("Scheduled task failed", msg.err)
+		return a, nil
+	}
+	for _, event := range msg.events {
+		a.AddAlert(simulationAlertLevel(event.Level), event.Message)
+	}
+	return a, tea.Batch(a.loadPopulation(), a.loadSystems(), a.loadTrends())
+}
+
+// handleTaskKeys handles key presses in the scheduled tasks screen.
+func (a *App) handleTaskKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	jobs := a.scheduler.Jobs()
+	switch msg.String() {
+	case "up", "k":
+		if a.taskIndex > 0 {
+			a.taskIndex--
+		}
+	case "down", "j":
+		if a.taskIndex < len(jobs)-1 {
+			a.taskIndex++
+		}
+	case "enter", "r":
+		if a.taskIndex >= len(jobs) || a.denyReadOnly() {
+			return a, nil
+		}
+		name := jobs[a.taskIndex].Name
+		a.AddAlert(AlertInfo, "Running "+name)
+		return a, a.runTask(name)
+	}
+	return a, nil
+}
+
+// renderTasks renders the scheduled tasks screen: each job's interval, last
+// and next run in vault time, and its last result.
+func (a *App) renderTasks() string {
+	var b strings.Builder
+	b.WriteString(a.theme.Title.Render("═══ SCHEDULED TASKS ═══"))
+	b.WriteString("\n\n")
+
+	jobs := a.scheduler.Jobs()
+	if len(jobs) == 0 {
+		b.WriteString(a.theme.Muted.Render("  No scheduled tasks"))
+		return b.String()
+	}
+
+	header := fmt.Sprintf("  %-28s %-10s %-17s %-17s %s", "TASK", "INTERVAL", "LAST RUN", "NEXT RUN", "RESULT")
+	b.WriteString(a.theme.TableHeader.Render(Truncate(header, a.width-2)))
+	b.WriteString("\n")
+
+	for i, job := range jobs {
+		last := "never"
+		if !job.LastRun.IsZero() {
+			last = util.FormatDateTime(job.LastRun)
+		}
+		result := job.LastResult
+		style := a.theme.Base
+		if job.LastErr != nil {
+			result = "FAILED: " + job.LastErr.Error()
+			style = a.theme.Warning
+		}
+		if job.Paused {
+			result = "PAUSED (" + string(a.lockdownState()) + ")"
+			style = a.theme.Muted
+		}
+		line := fmt.Sprintf("%-28s %-10s %-17s %-17s %s",
+			job.Name, job.Interval, last, util.FormatDateTime(job.NextRun), result)
+		line = Truncate(line, a.width-4)
+
+		if i == a.taskIndex {
+			b.WriteString(a.theme.Selected.Render("> " + line))
+		} else {
+			b.WriteString("  " + style.Render(line))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	if a.readOnly {
+		b.WriteString(a.theme.Muted.Render("  " + a.theme.Locale.T("↑/↓ select  Esc back  (read-only: tasks do not run)")))
+	} else {
+		b.WriteString(a.theme.Muted.Render("  " + a.theme.Locale.T("↑/↓ select  Enter/r run now  Esc back")))
+	}
+	return b.String()
+}
+
+// scenarioScript loads the configured scenario script and returns the hook
+// that fires its events. Day 1 of the script is the simulation start date,
+// or the current vault date if none is set. In training mode the script is
+// a training scenario, and the exercise that scores it is returned too, to
+// be registered after the runner.
+func scenarioScript(cfg *config.Config, db *database.DB, clock *util.VaultClock, facSvc *facilities.Service, popSvc *population.Service, resSvc *resources.Service) (*simulation.ScriptRunner, *training.Exercise, error) {
+	var scenario *training.Scenario
+	var script *simulation.Script
+	var err error
+	if cfg.Simulation.Training {
+		scenario, err = training.LoadScenario(cfg.Simulation.Script)
+		if scenario != nil {
+			script = scenario.Script
+		}
+	} else {
+		script, err = simulation.LoadScript(cfg.Simulation.Script)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	start, err := cfg.Simulation.StartDateTime()
+	if err != nil {
+		start = clock.Now()
+	}
+	runner, err := simulation.NewScriptRunner(script, start, map[string]simulation.ScriptHandler{
+		facilities.ScriptActionSystemStatus: facSvc.ScriptedStatus(),
+		population.ScriptActionAdmit:        popSvc.ScriptedAdmission(),
+		resources.ScriptActionStockLoss:     resSvc.ScriptedLoss(),
+	})
+	if err != nil || scenario == nil {
+		return runner, nil, err
+	}
+
+	dir, err := config.ReportDir(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	exercise, err := training.Start(context.Background(), db.DB, scenario, start, clock.Now, dir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("starting training exercise: %w", err)
+	}
+	return runner, exercise, nil
+}
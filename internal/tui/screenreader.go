@@ -0,0 +1,147 @@
+package tui
+
+import (
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+
+	"github.com/vtuos/vtuos/internal/i18n"
+)
+
+// moduleTitles are the names screen reader mode announces modules by.
+var moduleTitles = map[Module]string{
+	ModuleDashboard:    "Dashboard",
+	ModulePopulation:   "Population Registry",
+	ModuleResources:    "Resource Management",
+	ModuleFacilities:   "Facility Operations",
+	ModuleLabor:        "Labor Allocation",
+	ModuleMedical:      "Medical Records",
+	ModuleSecurity:     "Security",
+	ModuleGovernance:   "Governance",
+	ModuleSettings:     "Settings",
+	ModuleHelp:         "Help",
+	ModuleDigest:       "Daily digest",
+	ModuleTasks:        "Scheduled tasks",
+	ModuleCare:         "Care assignments",
+	ModuleAptitude:     "Aptitude assessments",
+	ModuleVaults:       "Managed vaults",
+	ModuleDiagnostics:  "Diagnostics",
+	ModuleConsumption:  "Consumption analytics",
+	ModuleDependencies: "System dependencies",
+	ModuleWorkOrders:   "Work orders",
+	ModuleMaintenance:  "Maintenance analytics",
+	ModuleSessions:     "Session activity",
+	ModuleAlerts:       "Alert queue",
+}
+
+// terminalProfile is the color profile of the terminal, restored when
+// screen reader mode is turned off. It is nil until the mode is first on.
+var terminalProfile *termenv.Profile
+
+// setScreenReader turns screen reader mode on or off. In the mode nothing
+// is colored; the app has components mark in text what color showed.
+func setScreenReader(on bool) {
+	if on {
+		if terminalProfile == nil {
+			profile := lipgloss.ColorProfile()
+			terminalProfile = &profile
+		}
+		lipgloss.SetColorProfile(termenv.Ascii)
+	} else if terminalProfile != nil {
+		lipgloss.SetColorProfile(*terminalProfile)
+	}
+}
+
+// plainTitle is drawn around screen titles; a screen reader reads it as
+// noise.
+var plainTitle = strings.NewReplacer("═══ ", "", " ═══", "")
+
+// plainRune returns what a screen reader is shown for a rune: box drawing
+// becomes space, keeping columns where they are, and block elements become
+// ASCII.
+func plainRune(r rune) rune {
+	switch {
+	case r >= '─' && r <= '╿', r == '■':
+		return ' '
+	case r == '░':
+		return '.'
+	case r >= '▀' && r <= '▟':
+		return '#'
+	case r == '▸':
+		return '>'
+	}
+	return r
+}
+
+// plainScreen rewrites a rendered screen for a screen reader, without box
+// drawing or trailing space.
+func plainScreen(screen string) string {
+	lines := strings.Split(plainTitle.Replace(screen), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(strings.Map(plainRune, line), " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// alertMessage returns an alert as the alert bar shows it, its level
+// spelled out.
+func alertMessage(alert Alert, locale i18n.Locale) string {
+	switch alert.Level {
+	case AlertCritical:
+		return locale.T("CRITICAL: %s", alert.Message)
+	case AlertWarning:
+		return locale.T("WARNING: %s", alert.Message)
+	default:
+		return locale.T("INFO: %s", alert.Message)
+	}
+}
+
+// announcer tracks what screen reader mode last announced, so that every
+// change of screen, prompt or alert is announced once, in the order it
+// happened.
+type announcer struct {
+	text    string       // Latest announcement
+	module  Module       // Module last announced
+	palette bool         // Whether the palette was open
+	confirm bool         // Whether the exit dialog was open
+	prompt  *quickAction // Prompt last announced
+	alert   time.Time    // Time of the latest alert announced
+}
+
+// announce updates the announcement with what changed on the screen since
+// the last update. Closing a prompt or dialog announces the screen again.
+func (a *App) announce() {
+	s := &a.announcer
+	screen := a.theme.Locale.T("Screen: %s", a.theme.Locale.T(moduleTitles[a.currentModule]))
+	if a.currentModule != s.module {
+		s.module = a.currentModule
+		s.text = screen
+	}
+	if open := a.palette != nil; open != s.palette {
+		s.palette = open
+		s.text = screen
+		if open {
+			s.text = a.theme.Locale.T("Command palette")
+		}
+	}
+	if a.showConfirm != s.confirm {
+		s.confirm = a.showConfirm
+		s.text = screen
+		if a.showConfirm {
+			s.text = a.theme.Locale.T("Are you sure you want to exit?")
+		}
+	}
+	if a.quickAction != s.prompt {
+		s.prompt = a.quickAction
+		s.text = screen
+		if a.quickAction != nil {
+			s.text = strings.TrimSpace(a.quickAction.prompt)
+		}
+	}
+	if len(a.alerts) > 0 && !a.alerts[0].Time.Equal(s.alert) {
+		s.alert = a.alerts[0].Time
+		s.text = alertMessage(a.alerts[0], a.theme.Locale)
+	}
+}
@@ -0,0 +1,69 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vtuos/vtuos/internal/config"
+)
+
+func TestPlainScreen(t *testing.T) {
+	screen := "═══ SETTINGS ═══\n╭────╮\n│ Power [██░░] │\n▸ GENERAL  \n■ LOCKDOWN ■"
+	want := "SETTINGS\n\n  Power [##..]\n> GENERAL\n  LOCKDOWN"
+	if got := plainScreen(screen); got != want {
+		t.Errorf("plainScreen = %q, want %q", got, want)
+	}
+}
+
+func TestPlainProgressBar(t *testing.T) {
+	theme := newDisplayTheme(&config.DisplayConfig{ScreenReader: true})
+	tests := []struct {
+		value float64
+		want  string
+	}{
+		{0.9, " 90% OK"},
+		{0.5, " 50% LOW"},
+		{0.1, " 10% CRITICAL"},
+	}
+	for _, tt := range tests {
+		if got := strings.TrimRight(theme.ProgressBar(tt.value, 1, 16), " "); got != tt.want {
+			t.Errorf("ProgressBar(%v) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestAnnounce(t *testing.T) {
+	a := &App{currentModule: ModuleDashboard, theme: NewTheme("green")}
+	a.announce()
+	if a.announcer.text != "Screen: Dashboard" {
+		t.Errorf("first announcement = %q", a.announcer.text)
+	}
+
+	a.currentModule = ModuleSettings
+	a.announce()
+	if a.announcer.text != "Screen: Settings" {
+		t.Errorf("after changing module = %q", a.announcer.text)
+	}
+
+	a.quickAction = &quickAction{prompt: "Time scale: "}
+	a.announce()
+	if a.announcer.text != "Time scale:" {
+		t.Errorf("with a prompt open = %q", a.announcer.text)
+	}
+	a.quickAction = nil
+	a.announce()
+	if a.announcer.text != "Screen: Settings" {
+		t.Errorf("after the prompt closed = %q", a.announcer.text)
+	}
+
+	a.alerts = []Alert{{Level: AlertCritical, Message: "Reactor offline", Time: time.Now()}}
+	a.announce()
+	if a.announcer.text != "CRITICAL: Reactor offline" {
+		t.Errorf("after an alert = %q", a.announcer.text)
+	}
+	a.announce()
+	if a.announcer.text != "CRITICAL: Reactor offline" {
+		t.Errorf("nothing changed, but announcement = %q", a.announcer.text)
+	}
+}
@@ -0,0 +1,302 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/services/governance"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// The terminal logs a session from opening, and a new one on every change
+// of operator or vault, with the modules opened and the changes made. A
+// failure to log a session is logged rather than alerted, as the terminal
+// works on without it. Kiosk terminals log nothing.
+
+// sessionSaveInterval is how often the session in progress is saved, so
+// that a crash loses little of it.
+const sessionSaveInterval = time.Minute
+
+// sessionPeriods are the spans, in days, the session activity screen cycles
+// through with [ and ]. The first is shown on opening.
+var sessionPeriods = []int{models.DefaultSessionDays, 1, 30}
+
+// sessionRows is how many recent sessions the session activity screen
+// lists.
+const sessionRows = 10
+
+// sessionMsg carries a session just started.
+type sessionMsg struct {
+	session *models.TerminalSession
+	err     error
+}
+
+// sessionReportMsg carries the session activity for its screen.
+type sessionReportMsg struct {
+	report *governance.SessionReport
+	err    error
+}
+
+// observeCommand counts a change made from the terminal in its session and
+// records it in the training exercise, if one is running.
+func (a *App) observeCommand(command string, err error) {
+	if err == nil {
+		a.sessionMutations.Add(1)
+	}
+	if a.exercise != nil {
+		a.exercise.Record(command, err)
+	}
+}
+
+// startSession ends the session in progress, if any, and starts one of the
+// operator, or of no one if operatorID is empty, in the vault administered.
+func (a *App) startSession(operatorID string) tea.Cmd {
+	if a.readOnly {
+		return nil
+	}
+	previous := a.sessionSnapshot()
+	a.session = nil
+	a.sessionModules = nil
+	a.sessionMutations.Store(0)
+	a.sessionSavedAt = time.Now()
+
+	gov := a.governanceSvc
+	return func() tea.Msg {
+		ctx, cancel := a.reportOperation()
+		defer cancel()
+		if previous != nil {
+			if err := gov.EndSession(ctx, previous); err != nil {
+				slog.Warn("ending terminal session", "session", previous.ID, "error", err)
+			}
+		}
+		session, err := gov.StartSession(ctx, operatorID)
+		return sessionMsg{session: session, err: err}
+	}
+}
+
+// handleSession stores the session just started.
+func (a *App) handleSession(msg sessionMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		slog.Warn("starting terminal session", "error", msg.err)
+		return a, nil
+	}
+	a.session = msg.session
+	return a, nil
+}
+
+// sessionSnapshot copies the session in progress with the modules opened
+// and changes made so far, for saving away from the update loop. It is nil
+// when no session is in progress.
+func (a *App) sessionSnapshot() *models.TerminalSession {
+	if a.session == nil {
+		return nil
+	}
+	snapshot := *a.session
+	snapshot.Modules = slices.Clone(a.sessionModules)
+	snapshot.Mutations = int(a.sessionMutations.Load())
+	return &snapshot
+}
+
+// visitModule records that a module was opened in the session, saving the
+// session the first time.
+func (a *App) visitModule(m Module) tea.Cmd {
+	if a.readOnly || slices.Contains(a.sessionModules, string(m)) {
+		return nil
+	}
+	a.sessionModules = append(a.sessionModules, string(m))
+	return a.saveSession()
+}
+
+// saveSession saves the session in progress.
+func (a *App) saveSession() tea.Cmd {
+	snapshot := a.sessionSnapshot()
+	if snapshot == nil {
+		return nil
+	}
+	a.sessionSavedAt = time.Now()
+	gov := a.governanceSvc
+	return func() tea.Msg {
+		ctx, cancel := a.reportOperation()
+		defer cancel()
+		if err := gov.SaveSession(ctx, snapshot); err != nil {
+			slog.Warn("saving terminal session", "session", snapshot.ID, "error", err)
+		}
+		return nil
+	}
+}
+
+// sessionSaveDue returns a command saving the session in progress if it was
+// last saved sessionSaveInterval ago, or nil.
+func (a *App) sessionSaveDue() tea.Cmd {
+	if time.Since(a.sessionSavedAt) < sessionSaveInterval {
+		return nil
+	}
+	return a.saveSession()
+}
+
+// endSession ends the session in progress as the terminal closes. It runs
+// after the program has stopped, under its own timeout, as the terminal's
+// context may already be cancelled.
+func (a *App) endSession() {
+	snapshot := a.sessionSnapshot()
+	if snapshot == nil {
+		return
+	}
+	ctx, cancel := util.WithTimeout(context.Background(), a.config.Timeouts.Operation())
+	defer cancel()
+	if err := a.governanceSvc.EndSession(ctx, snapshot); err != nil {
+		slog.Warn("ending terminal session", "session", snapshot.ID, "error", err)
+	}
+	a.session = nil
+}
+
+// operatorID returns the ID of the operator signed on, or "" for none.
+func (a *App) operatorID() string {
+	if a.operator == nil {
+		return ""
+	}
+	return a.operator.ID
+}
+
+// openSessions switches to the session activity screen.
+func (a *App) openSessions() tea.Cmd {
+	a.currentModule = ModuleSessions
+	return tea.Batch(a.saveSession(), a.loadSessionReport())
+}
+
+// loadSessionReport loads the session activity of the selected period.
+func (a *App) loadSessionReport() tea.Cmd {
+	days := sessionPeriods[a.sessionPeriod]
+	open := ""
+	if a.session != nil {
+		open = a.session.ID
+	}
+	return func() tea.Msg {
+		ctx, cancel := a.reportOperation()
+		defer cancel()
+		report, err := a.governanceSvc.SessionActivity(ctx, days, open)
+		return sessionReportMsg{report: report, err: err}
+	}
+}
+
+// handleSessionReport stores the loaded session activity.
+func (a *App) handleSessionReport(msg sessionReportMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		a.AddError("Failed to load sessions", msg.err)
+		return a, nil
+	}
+	a.sessionReport = msg.report
+	return a, nil
+}
+
+// handleSessionKeys handles key presses on the session activity screen.
+func (a *App) handleSessionKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "]", "right":
+		a.sessionPeriod = (a.sessionPeriod + 1) % len(sessionPeriods)
+		return a, a.loadSessionReport()
+	case "[", "left":
+		a.sessionPeriod = (a.sessionPeriod + len(sessionPeriods) - 1) % len(sessionPeriods)
+		return a, a.loadSessionReport()
+	case "r":
+		return a, tea.Batch(a.saveSession(), a.loadSessionReport())
+	}
+	return a, nil
+}
+
+// renderSessions renders the session activity screen: each operator's
+// sessions, time signed on, changes made and modules used over the period,
+// then the latest sessions.
+func (a *App) renderSessions() string {
+	var b strings.Builder
+	b.WriteString(a.theme.Title.Render("═══ SESSION ACTIVITY ═══"))
+	b.WriteString("\n\n")
+
+	report := a.sessionReport
+	if report == nil {
+		b.WriteString(a.theme.Muted.Render("  Sessions loading..."))
+		return b.String()
+	}
+	b.WriteString(a.theme.Muted.Render(fmt.Sprintf("  Terminal sessions active since %s (%d days)",
+		util.FormatDateTime(report.Since), report.Days)))
+	b.WriteString("\n\n")
+	width := a.width - 4
+
+	b.WriteString(a.theme.Subtitle.Render("BY OPERATOR"))
+	b.WriteString("\n")
+	header := fmt.Sprintf("  %-12s %-22s %8s %9s %7s %-19s %s", "REGISTRY", "NAME", "SESSIONS", "DURATION", "CHANGES", "LAST ACTIVE", "MODULES")
+	b.WriteString(a.theme.TableHeader.Render(Truncate(header, a.width-2)))
+	b.WriteString("\n")
+	if len(report.Operators) == 0 {
+		b.WriteString(a.theme.Muted.Render("  No sessions in the period"))
+		b.WriteString("\n")
+	}
+	for _, op := range report.Operators {
+		registry, name := op.Registry, op.Name
+		if op.OperatorID == nil {
+			registry, name = "-", "(no operator)"
+		} else if registry == "" {
+			registry = "(deleted)"
+		}
+		line := fmt.Sprintf("%-12s %-22s %8d %9s %7d %-19s %s",
+			registry, Truncate(name, 22), op.Sessions, formatSessionDuration(op.Duration), op.Mutations,
+			util.FormatDateTime(op.LastActive), strings.Join(op.Modules, ","))
+		if op.Unclosed > 0 {
+			b.WriteString("  " + a.theme.Warning.Render(Truncate(line, width)))
+		} else {
+			b.WriteString("  " + a.theme.Base.Render(Truncate(line, width)))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	b.WriteString(a.theme.Subtitle.Render("RECENT SESSIONS"))
+	b.WriteString("\n")
+	header = fmt.Sprintf("  %-19s %-19s %-12s %9s %7s %s", "STARTED", "ENDED", "OPERATOR", "DURATION", "CHANGES", "MODULES")
+	b.WriteString(a.theme.TableHeader.Render(Truncate(header, a.width-2)))
+	b.WriteString("\n")
+	for i, s := range report.Sessions {
+		if i == sessionRows {
+			b.WriteString(a.theme.Muted.Render(fmt.Sprintf("  ... and %d more", len(report.Sessions)-sessionRows)))
+			b.WriteString("\n")
+			break
+		}
+		ended := "never closed"
+		switch {
+		case s.EndedAt != nil:
+			ended = util.FormatDateTime(*s.EndedAt)
+		case a.session != nil && s.ID == a.session.ID:
+			ended = "in progress"
+		}
+		operator := s.OperatorRegistry
+		if s.OperatorID == nil {
+			operator = "-"
+		}
+		line := fmt.Sprintf("%-19s %-19s %-12s %9s %7d %s",
+			util.FormatDateTime(s.StartedAt), ended, operator, formatSessionDuration(s.Duration()),
+			s.Mutations, strings.Join(s.Modules, ","))
+		if ended == "never closed" {
+			b.WriteString("  " + a.theme.Warning.Render(Truncate(line, width)))
+		} else {
+			b.WriteString("  " + a.theme.Base.Render(Truncate(line, width)))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(a.theme.Muted.Render("  " + a.theme.Locale.T("[/] period  r reload  Esc back")))
+	return b.String()
+}
+
+// formatSessionDuration formats a session's length in hours and minutes.
+func formatSessionDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
@@ -0,0 +1,537 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/vtuos/vtuos/internal/config"
+	"github.com/vtuos/vtuos/internal/i18n"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// colorSchemeNames maps color schemes to their display names.
+var colorSchemeNames = map[config.ColorScheme]string{
+	config.ColorSchemeGreenPhosphor: "Green Phosphor",
+	config.ColorSchemeAmber:         "Amber Phosphor",
+	config.ColorSchemeBlue:          "Blue Phosphor",
+	config.ColorSchemeWhite:         "White Phosphor",
+}
+
+// settingsSection is a section of the settings screen; Tab moves the focus
+// to the next.
+type settingsSection int
+
+const (
+	settingsSchemes settingsSection = iota
+	settingsPanels
+	settingsGeneral
+	settingsSections // Number of sections
+)
+
+// settingsValues are the settings the settings screen edits. Changes apply
+// at once and are written to the config file when saved.
+type settingsValues struct {
+	scheme       config.ColorScheme
+	panels       []config.DashboardPanel
+	designation  string // Of the primary vault
+	timeScale    float64
+	dateFormat   string
+	timeFormat   string
+	timeZone     string
+	calendar     config.CalendarMode
+	backupHours  int
+	locale       i18n.Locale
+	screenReader bool
+}
+
+// currentSettings returns the settings of a configuration.
+func currentSettings(cfg *config.Config) settingsValues {
+	return settingsValues{
+		scheme:       cfg.Display.ColorScheme,
+		panels:       slices.Clone(cfg.Display.Panels()),
+		designation:  cfg.Vault.Designation,
+		timeScale:    cfg.Simulation.TimeScale,
+		dateFormat:   cfg.Display.DateFormat,
+		timeFormat:   cfg.Display.TimeFormat,
+		timeZone:     cfg.Display.TimeZone,
+		calendar:     cfg.Display.Calendar,
+		backupHours:  cfg.Database.BackupIntervalHours,
+		locale:       cfg.Display.Language(),
+		screenReader: cfg.Display.ScreenReader,
+	}
+}
+
+// apply writes the settings to a configuration.
+func (v settingsValues) apply(cfg *config.Config) {
+	cfg.Display.ColorScheme = v.scheme
+	cfg.Display.DashboardPanels = slices.Clone(v.panels)
+	cfg.Vault.Designation = v.designation
+	cfg.Simulation.TimeScale = v.timeScale
+	cfg.Display.DateFormat = v.dateFormat
+	cfg.Display.TimeFormat = v.timeFormat
+	cfg.Display.TimeZone = v.timeZone
+	cfg.Display.Calendar = v.calendar
+	cfg.Database.BackupIntervalHours = v.backupHours
+	cfg.Display.Locale = v.locale
+	cfg.Display.ScreenReader = v.screenReader
+}
+
+// equal reports whether two sets of settings are the same.
+func (v settingsValues) equal(o settingsValues) bool {
+	return v.scheme == o.scheme && slices.Equal(v.panels, o.panels) &&
+		v.designation == o.designation && v.timeScale == o.timeScale &&
+		v.dateFormat == o.dateFormat && v.timeFormat == o.timeFormat &&
+		v.timeZone == o.timeZone && v.calendar == o.calendar &&
+		v.backupHours == o.backupHours && v.locale == o.locale &&
+		v.screenReader == o.screenReader
+}
+
+// settingField is one of the general settings, edited as text.
+type settingField struct {
+	label string
+	value func(v settingsValues) string
+	set   func(v *settingsValues, input string) error
+}
+
+// settingFields are the general settings in screen order.
+var settingFields = []settingField{
+	{
+		label: "Vault designation",
+		value: func(v settingsValues) string { return v.designation },
+		set: func(v *settingsValues, input string) error {
+			v.designation = input
+			return nil
+		},
+	},
+	{
+		label: "Time scale",
+		value: func(v settingsValues) string { return strconv.FormatFloat(v.timeScale, 'f', -1, 64) },
+		set: func(v *settingsValues, input string) error {
+			scale, err := strconv.ParseFloat(strings.TrimSuffix(input, "x"), 64)
+			if err != nil {
+				return fmt.Errorf("time scale %q is not a number", input)
+			}
+			v.timeScale = scale
+			return nil
+		},
+	},
+	{
+		label: "Date format",
+		value: func(v settingsValues) string { return layoutSetting(v.dateFormat) },
+		set: func(v *settingsValues, input string) error {
+			v.dateFormat = parseLayoutSetting(input)
+			return nil
+		},
+	},
+	{
+		label: "Time format",
+		value: func(v settingsValues) string { return layoutSetting(v.timeFormat) },
+		set: func(v *settingsValues, input string) error {
+			v.timeFormat = parseLayoutSetting(input)
+			return nil
+		},
+	},
+	{
+		label: "Time zone",
+		value: func(v settingsValues) string { return v.timeZone },
+		set: func(v *settingsValues, input string) error {
+			v.timeZone = input
+			return nil
+		},
+	},
+	{
+		label: "Calendar",
+		value: func(v settingsValues) string { return string(v.calendar) },
+		set: func(v *settingsValues, input string) error {
+			v.calendar = config.CalendarMode(strings.ToLower(input))
+			return nil
+		},
+	},
+	{
+		label: "Backup interval (hours)",
+		value: func(v settingsValues) string { return strconv.Itoa(v.backupHours) },
+		set: func(v *settingsValues, input string) error {
+			hours, err := strconv.Atoi(input)
+			if err != nil {
+				return fmt.Errorf("backup interval %q is not a whole number of hours", input)
+			}
+			v.backupHours = hours
+			return nil
+		},
+	},
+	{
+		label: "Language",
+		value: func(v settingsValues) string { return string(v.locale) },
+		set: func(v *settingsValues, input string) error {
+			v.locale = i18n.Locale(strings.ToLower(input))
+			return nil
+		},
+	},
+	{
+		label: "Screen reader",
+		value: func(v settingsValues) string { return onOff(v.screenReader) },
+		set: func(v *settingsValues, input string) error {
+			switch strings.ToLower(input) {
+			case "on", "yes", "true":
+				v.screenReader = true
+			case "off", "no", "false":
+				v.screenReader = false
+			default:
+				return fmt.Errorf("screen reader %q is neither on nor off", input)
+			}
+			return nil
+		},
+	},
+}
+
+// onOff shows a setting that is on or off.
+func onOff(on bool) string {
+	if on {
+		return "on"
+	}
+	return "off"
+}
+
+// layoutSetting shows a date or time format setting, "default" when the
+// locale's layout is used.
+func layoutSetting(layout string) string {
+	if layout == "" {
+		return "default"
+	}
+	return layout
+}
+
+// parseLayoutSetting reads a date or time format setting, "default" for
+// the locale's layout.
+func parseLayoutSetting(input string) string {
+	if strings.EqualFold(input, "default") {
+		return ""
+	}
+	return input
+}
+
+// settingMsg carries the settings with one general setting changed, or why
+// the change was rejected.
+type settingMsg struct {
+	values settingsValues
+	err    error
+}
+
+type settingsSavedMsg struct {
+	values settingsValues
+	err    error
+}
+
+// applySettings makes settings current. The theme, screen reader mode, the
+// dashboard layout, the primary vault's designation, date and time formats,
+// the clock's time scale and the backup schedule all follow at once, but
+// nothing is written to the config file until saveSettings runs.
+func (a *App) applySettings(v settingsValues) {
+	selected := a.selectedPanel()
+	old := currentSettings(a.config)
+	v.apply(a.config)
+	util.SetCalendar(a.config.Calendar())
+
+	if v.screenReader != old.screenReader {
+		setScreenReader(v.screenReader)
+	}
+	if v.scheme != old.scheme || v.screenReader != old.screenReader || v.locale != old.locale {
+		a.theme = newDisplayTheme(&a.config.Display)
+		a.setViewsPlain()
+	}
+	if len(a.vaults) > 0 {
+		a.vaults[0].vault.Designation = v.designation
+	}
+	if v.timeScale != old.timeScale && a.clock != nil {
+		a.clock.SetTimeScale(v.timeScale)
+	}
+	if v.backupHours != old.backupHours && a.db != nil {
+		a.db.SetBackupInterval(v.backupHours)
+	}
+	a.settingsPanelIndex = max(0, slices.Index(panelRows(v.panels), selected))
+}
+
+// changeSettings applies the current settings changed by change.
+func (a *App) changeSettings(change func(v *settingsValues)) {
+	v := currentSettings(a.config)
+	change(&v)
+	a.applySettings(v)
+}
+
+// selectedPanel returns the dashboard panel selected on the settings screen.
+func (a *App) selectedPanel() config.DashboardPanel {
+	rows := panelRows(a.config.Display.Panels())
+	return rows[min(a.settingsPanelIndex, len(rows)-1)]
+}
+
+// handleSettingsKeys handles key presses in the settings module.
+func (a *App) handleSettingsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "tab":
+		a.settingsFocus = (a.settingsFocus + 1) % settingsSections
+		return a, nil
+	case "shift+tab":
+		a.settingsFocus = (a.settingsFocus + settingsSections - 1) % settingsSections
+		return a, nil
+	case "enter", "s":
+		if a.denyReadOnly() {
+			return a, nil
+		}
+		return a, a.saveSettings()
+	}
+
+	switch a.settingsFocus {
+	case settingsSchemes:
+		switch msg.String() {
+		case "right", "down", "l", "j", "t":
+			a.changeSettings(func(v *settingsValues) { v.scheme = v.scheme.Next() })
+		case "left", "up", "h", "k":
+			a.changeSettings(func(v *settingsValues) { v.scheme = v.scheme.Prev() })
+		}
+	case settingsPanels:
+		panel := a.selectedPanel()
+		switch msg.String() {
+		case "down", "j":
+			a.settingsPanelIndex = min(a.settingsPanelIndex+1, len(config.DashboardPanels)-1)
+		case "up", "k":
+			a.settingsPanelIndex = max(a.settingsPanelIndex-1, 0)
+		case " ", "x":
+			a.changeSettings(func(v *settingsValues) { v.panels = togglePanel(v.panels, panel) })
+		case "[":
+			a.changeSettings(func(v *settingsValues) { v.panels = movePanel(v.panels, panel, -1) })
+		case "]":
+			a.changeSettings(func(v *settingsValues) { v.panels = movePanel(v.panels, panel, 1) })
+		}
+	case settingsGeneral:
+		switch msg.String() {
+		case "down", "j":
+			a.settingsFieldIndex = min(a.settingsFieldIndex+1, len(settingFields)-1)
+		case "up", "k":
+			a.settingsFieldIndex = max(a.settingsFieldIndex-1, 0)
+		case "e":
+			field := settingFields[a.settingsFieldIndex]
+			a.quickAction = &quickAction{
+				kind:     quickActionSetting,
+				prompt:   a.theme.Locale.T(field.label) + ": ",
+				input:    field.value(currentSettings(a.config)),
+				targetID: strconv.Itoa(a.settingsFieldIndex),
+			}
+		}
+	}
+	return a, nil
+}
+
+// runSettingAction checks a general setting entered on the settings screen
+// against the rest of the configuration.
+func (a *App) runSettingAction(action *quickAction, input string) settingMsg {
+	i, _ := strconv.Atoi(action.targetID)
+	v := currentSettings(a.config)
+	if err := settingFields[i].set(&v, input); err != nil {
+		return settingMsg{err: fmt.Errorf("%w: %w", repository.ErrValidation, err)}
+	}
+	cfg := *a.config
+	v.apply(&cfg)
+	if err := cfg.Validate(); err != nil {
+		return settingMsg{err: fmt.Errorf("%w: %w", repository.ErrValidation, err)}
+	}
+	return settingMsg{values: v}
+}
+
+// panelRows lists the dashboard panels as the settings screen does: those
+// shown, in order, then those hidden.
+func panelRows(shown []config.DashboardPanel) []config.DashboardPanel {
+	rows := slices.Clone(shown)
+	for _, panel := range config.DashboardPanels {
+		if !slices.Contains(shown, panel) {
+			rows = append(rows, panel)
+		}
+	}
+	return rows
+}
+
+// togglePanel returns the dashboard layout with a hidden panel shown last,
+// or a shown panel hidden. The last panel shown stays.
+func togglePanel(shown []config.DashboardPanel, panel config.DashboardPanel) []config.DashboardPanel {
+	i := slices.Index(shown, panel)
+	switch {
+	case i < 0:
+		return append(slices.Clone(shown), panel)
+	case len(shown) == 1:
+		return shown
+	default:
+		return slices.Delete(slices.Clone(shown), i, i+1)
+	}
+}
+
+// movePanel returns the dashboard layout with a shown panel moved delta
+// places, no further than either end. Hidden panels do not move.
+func movePanel(shown []config.DashboardPanel, panel config.DashboardPanel, delta int) []config.DashboardPanel {
+	i := slices.Index(shown, panel)
+	if i < 0 {
+		return shown
+	}
+	j := max(0, min(i+delta, len(shown)-1))
+	moved := slices.Delete(slices.Clone(shown), i, i+1)
+	return slices.Insert(moved, j, panel)
+}
+
+// settingsChanged reports whether the settings differ from those last
+// saved.
+func (a *App) settingsChanged() bool {
+	return !currentSettings(a.config).equal(a.savedSettings)
+}
+
+// revertSettings restores the settings last saved, discarding unsaved
+// changes.
+func (a *App) revertSettings() {
+	if a.settingsChanged() {
+		a.applySettings(a.savedSettings)
+	}
+}
+
+// saveSettings persists the current settings to the config file.
+func (a *App) saveSettings() tea.Cmd {
+	values := currentSettings(a.config)
+	return func() tea.Msg {
+		if a.configPath == "" {
+			return settingsSavedMsg{values: values, err: fmt.Errorf("no config file path")}
+		}
+		return settingsSavedMsg{values: values, err: config.Save(a.config, a.configPath)}
+	}
+}
+
+// renderSettings renders the settings module.
+func (a *App) renderSettings() string {
+	var b strings.Builder
+	b.WriteString(a.theme.Title.Render("═══ " + a.theme.Locale.T("SETTINGS") + " ═══"))
+	b.WriteString("\n\n")
+
+	b.WriteString(a.settingsHeading(a.theme.Locale.T("COLOR SCHEME"), settingsSchemes))
+	b.WriteString("\n\n")
+
+	current := a.config.Display.ColorScheme
+	for _, scheme := range config.ColorSchemes {
+		marker := "  "
+		style := a.theme.Muted
+		if scheme == current {
+			marker = "> "
+			style = a.theme.Primary
+		}
+		line := "  " + marker + PadRight(a.theme.Locale.T(colorSchemeNames[scheme]), 16)
+		if scheme == a.savedSettings.scheme {
+			line += " " + a.theme.Locale.T("(saved)")
+		}
+		b.WriteString(style.Render(line))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(a.settingsHeading(a.theme.Locale.T("DASHBOARD PANELS"), settingsPanels))
+	b.WriteString("\n\n")
+
+	shown := a.config.Display.Panels()
+	for i, panel := range panelRows(shown) {
+		selected := a.settingsFocus == settingsPanels && i == a.settingsPanelIndex
+		marker := "  "
+		if selected {
+			marker = "> "
+		}
+		check, style := "[ ]", a.theme.Muted
+		if slices.Contains(shown, panel) {
+			check, style = "[x]", a.theme.Base
+		}
+		if selected {
+			style = a.theme.Primary
+		}
+		b.WriteString(style.Render(fmt.Sprintf("  %s%s %s", marker, check, a.theme.Locale.T(dashboardPanelNames[panel]))))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(a.settingsHeading(a.theme.Locale.T("GENERAL"), settingsGeneral))
+	b.WriteString("\n\n")
+
+	values := currentSettings(a.config)
+	now := a.clock.Now()
+	for i, field := range settingFields {
+		selected := a.settingsFocus == settingsGeneral && i == a.settingsFieldIndex
+		marker := "  "
+		style := a.theme.Base
+		if selected {
+			marker = "> "
+			style = a.theme.Primary
+		}
+		value := field.value(values)
+		b.WriteString(style.Render("  " + marker + PadRight(a.theme.Locale.T(field.label), 24)))
+		b.WriteString(a.theme.Value.Render(value))
+		switch i {
+		case 1:
+			b.WriteString(a.theme.Muted.Render(a.theme.Locale.T("x vault time")))
+		case 2:
+			b.WriteString(a.theme.Muted.Render("  " + a.theme.Locale.T("e.g. %s", now.Format(a.config.Display.DateLayout()))))
+		case 3, 4:
+			b.WriteString(a.theme.Muted.Render("  " + a.theme.Locale.T("e.g. %s", util.FormatTime(now))))
+		case 5:
+			b.WriteString(a.theme.Muted.Render("  " + a.theme.Locale.T("today %s", util.FormatDay(now))))
+		case 6:
+			if values.backupHours == 0 {
+				b.WriteString(a.theme.Muted.Render("  " + a.theme.Locale.T("(no automatic backups)")))
+			}
+		case 7:
+			b.WriteString(a.theme.Muted.Render("  " + values.locale.Name()))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(a.theme.Subtitle.Render(a.theme.Locale.T("PREVIEW")))
+	b.WriteString("\n\n")
+
+	barWidth := 20
+	if GetBreakpoint(a.width) == BreakpointNarrow {
+		barWidth = 10
+	}
+	b.WriteString("  " + a.theme.Label.Render(a.theme.Locale.T("Label")+": ") + a.theme.Value.Render(a.theme.Locale.T("Value")) + "  ")
+	b.WriteString(a.theme.Accent.Render(a.theme.Locale.T("Accent")) + "\n")
+	b.WriteString("  " + a.theme.Success.Render(a.theme.Locale.T("NOMINAL")) + "  ")
+	b.WriteString(a.theme.Warning.Render(a.theme.Locale.T("DEGRADED")) + "  ")
+	b.WriteString(a.theme.Error.Render(a.theme.Locale.T("CRITICAL")) + "\n")
+	b.WriteString("  " + a.theme.ProgressBar(0.65, 1.0, barWidth) + "\n")
+
+	b.WriteString("\n")
+	var keys string
+	switch a.settingsFocus {
+	case settingsSchemes:
+		keys = "  " + a.theme.Locale.T("←/→ cycle schemes  Tab next section")
+	case settingsPanels:
+		keys = "  " + a.theme.Locale.T("↑/↓ select  Space show/hide  [/] move  Tab next section")
+	case settingsGeneral:
+		keys = "  " + a.theme.Locale.T("↑/↓ select  e edit  Tab next section")
+	}
+	switch {
+	case a.quickAction != nil:
+		b.WriteString("  " + a.renderActionBar(nil))
+	case a.readOnly:
+		b.WriteString(a.theme.Muted.Render(keys + "  " + a.theme.Locale.T("(read-only: changes are not saved)")))
+	case a.settingsChanged():
+		b.WriteString(a.theme.Warning.Render("  " + a.theme.Locale.T("Unsaved change — Enter to save, Esc to revert")))
+	default:
+		b.WriteString(a.theme.Muted.Render(keys + "  " + a.theme.Locale.T("Enter save")))
+	}
+
+	return b.String()
+}
+
+// settingsHeading renders the heading of a settings section, marked while
+// the section has the focus.
+func (a *App) settingsHeading(title string, section settingsSection) string {
+	if a.settingsFocus == section {
+		return a.theme.Accent.Render("▸ " + title)
+	}
+	return a.theme.Subtitle.Render("  " + title)
+}
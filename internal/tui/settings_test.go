@@ -0,0 +1,139 @@
+package tui
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/vtuos/vtuos/internal/config"
+	"github.com/vtuos/vtuos/internal/i18n"
+	"github.com/vtuos/vtuos/internal/repository"
+)
+
+const (
+	pop = config.DashboardPanelPopulation
+	fac = config.DashboardPanelFacilities
+	res = config.DashboardPanelResources
+	sim = config.DashboardPanelSimulation
+	eff = config.DashboardPanelEfficiency
+	alr = config.DashboardPanelAlerts
+	sho = config.DashboardPanelShortages
+)
+
+func TestPanelRows(t *testing.T) {
+	got := panelRows([]config.DashboardPanel{res, pop})
+	want := []config.DashboardPanel{res, pop, fac, sim, eff, alr, sho}
+	if !slices.Equal(got, want) {
+		t.Errorf("panelRows = %v, want %v", got, want)
+	}
+}
+
+func TestTogglePanel(t *testing.T) {
+	tests := []struct {
+		name  string
+		shown []config.DashboardPanel
+		panel config.DashboardPanel
+		want  []config.DashboardPanel
+	}{
+		{"Hidden shown last", []config.DashboardPanel{pop, res}, alr, []config.DashboardPanel{pop, res, alr}},
+		{"Shown hidden", []config.DashboardPanel{pop, res, sim}, res, []config.DashboardPanel{pop, sim}},
+		{"Last stays", []config.DashboardPanel{pop}, pop, []config.DashboardPanel{pop}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shown := slices.Clone(tt.shown)
+			if got := togglePanel(shown, tt.panel); !slices.Equal(got, tt.want) {
+				t.Errorf("togglePanel(%v, %s) = %v, want %v", tt.shown, tt.panel, got, tt.want)
+			}
+			if !slices.Equal(shown, tt.shown) {
+				t.Errorf("togglePanel changed its input to %v", shown)
+			}
+		})
+	}
+}
+
+func TestMovePanel(t *testing.T) {
+	shown := []config.DashboardPanel{pop, fac, res}
+
+	tests := []struct {
+		name  string
+		panel config.DashboardPanel
+		delta int
+		want  []config.DashboardPanel
+	}{
+		{"Up", res, -1, []config.DashboardPanel{pop, res, fac}},
+		{"Down", pop, 1, []config.DashboardPanel{fac, pop, res}},
+		{"Stops at the top", pop, -1, []config.DashboardPanel{pop, fac, res}},
+		{"Stops at the bottom", res, 1, []config.DashboardPanel{pop, fac, res}},
+		{"Hidden stays", alr, -1, []config.DashboardPanel{pop, fac, res}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := movePanel(shown, tt.panel, tt.delta); !slices.Equal(got, tt.want) {
+				t.Errorf("movePanel(%s, %d) = %v, want %v", tt.panel, tt.delta, got, tt.want)
+			}
+		})
+	}
+	if !slices.Equal(shown, []config.DashboardPanel{pop, fac, res}) {
+		t.Errorf("movePanel changed its input to %v", shown)
+	}
+}
+
+func TestRunSettingAction(t *testing.T) {
+	field := func(label string) string {
+		for i, f := range settingFields {
+			if f.label == label {
+				return strconv.Itoa(i)
+			}
+		}
+		t.Fatalf("no setting %q", label)
+		return ""
+	}
+	tests := []struct {
+		label   string
+		input   string
+		wantErr bool
+		check   func(v settingsValues) bool
+	}{
+		{"Time scale", "120x", false, func(v settingsValues) bool { return v.timeScale == 120 }},
+		{"Time scale", "fast", true, nil},
+		{"Time scale", "-1", true, nil},
+		{"Date format", "02 Jan 2006", false, func(v settingsValues) bool { return v.dateFormat == "02 Jan 2006" }},
+		{"Date format", "yyyy-mm-dd", true, nil},
+		{"Backup interval (hours)", "0", false, func(v settingsValues) bool { return v.backupHours == 0 }},
+		{"Backup interval (hours)", "1.5", true, nil},
+		{"Time zone", "America/Denver", false, func(v settingsValues) bool { return v.timeZone == "America/Denver" }},
+		{"Time zone", "Mars/Olympus", true, nil},
+		{"Calendar", "VAULT", false, func(v settingsValues) bool { return v.calendar == config.CalendarVault }},
+		{"Calendar", "lunar", true, nil},
+		{"Vault designation", "Vault 111", false, func(v settingsValues) bool { return v.designation == "Vault 111" }},
+		{"Language", "es", false, func(v settingsValues) bool { return v.locale == i18n.Spanish }},
+		{"Language", "fr", true, nil},
+		{"Screen reader", "on", false, func(v settingsValues) bool { return v.screenReader }},
+		{"Screen reader", "maybe", true, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.label+"/"+tt.input, func(t *testing.T) {
+			a := &App{config: config.Default()}
+			msg := a.runSettingAction(&quickAction{targetID: field(tt.label)}, tt.input)
+			if tt.wantErr {
+				if !errors.Is(msg.err, repository.ErrValidation) {
+					t.Errorf("err = %v, want a validation error", msg.err)
+				}
+				return
+			}
+			if msg.err != nil {
+				t.Fatalf("unexpected error: %v", msg.err)
+			}
+			if !tt.check(msg.values) {
+				t.Errorf("values = %+v", msg.values)
+			}
+			if !msg.values.equal(msg.values) || msg.values.equal(currentSettings(a.config)) {
+				t.Errorf("setting %s did not change the settings", tt.label)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package tui
+
+import (
+	"context"
+	"errors"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/vtuos/vtuos/internal/simulation"
+)
+
+// simulationMsg carries the events of a simulation tick.
+type simulationMsg struct {
+	events []simulation.Event
+	err    error
+}
+
+// runSimulation processes the vault time elapsed since the last tick.
+func (a *App) runSimulation() tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := a.reportOperation()
+		defer cancel()
+		events, err := a.engine.Tick(ctx)
+		return simulationMsg{events: events, err: errors.Join(err, a.recordAlerts(ctx, events))}
+	}
+}
+
+// recordAlerts records the warnings and critical events of a tick in the
+// alert queue of the vault each concerns; events of the whole database are
+// recorded in the primary vault's. A kiosk terminal records none.
+func (a *App) recordAlerts(ctx context.Context, events []simulation.Event) error {
+	if a.readOnly {
+		return nil
+	}
+	byVault := make(map[int][]simulation.Event)
+	for _, e := range events {
+		byVault[e.Vault] = append(byVault[e.Vault], e)
+	}
+	var errs []error
+	for _, v := range a.vaults {
+		vaultEvents := byVault[v.vault.Number]
+		if v == a.vaults[0] {
+			vaultEvents = append(byVault[0], vaultEvents...)
+		}
+		if _, err := v.governance.RaiseAlerts(ctx, vaultEvents); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// handleSimulation raises alerts for simulation events and refreshes the
+// dashboard systems panel when anything happened.
+func (a *App) handleSimulation(msg simulationMsg) (tea.Model, tea.Cmd) {
+	a.simulating = false
+	if msg.err != nil {
+		a.AddError("Simulation tick failed", msg.err)
+	}
+	for _, event := range msg.events {
+		a.AddAlert(simulationAlertLevel(event.Level), event.Message)
+	}
+	if len(msg.events) == 0 {
+		return a, nil
+	}
+	return a, tea.Batch(a.loadSystems(), a.loadTrends(), a.loadPopulation(), a.loadLockdown())
+}
+
+// simulationAlertLevel maps a simulation event level to an alert level.
+func simulationAlertLevel(level simulation.EventLevel) AlertLevel {
+	switch level {
+	case simulation.EventCritical:
+		return AlertCritical
+	case simulation.EventWarning:
+		return AlertWarning
+	default:
+		return AlertInfo
+	}
+}
@@ -0,0 +1,132 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Split-pane sizing: the share of the width the list takes, how far one
+// resize step moves it, and the narrowest either pane may get.
+const (
+	defaultSplitRatio = 0.55
+	splitRatioStep    = 0.05
+	minSplitRatio     = 0.3
+	maxSplitRatio     = 0.7
+	minPaneWidth      = 50
+)
+
+// splitPane reports whether the terminal is wide enough to show the
+// selected record beside the list in the census and inventory.
+func (a *App) splitPane() bool {
+	return a.width > int(BreakpointSplit)
+}
+
+// splitView reports whether the current screen is split: the census or the
+// inventory list on a wide terminal, outside forms.
+func (a *App) splitView() bool {
+	if !a.splitPane() || a.showForm {
+		return false
+	}
+	switch a.currentModule {
+	case ModulePopulation:
+		return !a.showHouseholds && !a.showIntakes
+	case ModuleResources:
+		return !a.showAssets
+	}
+	return false
+}
+
+// handleSplitKeys handles the keys of a split screen that are not those of
+// either pane: Tab moves focus between the list and the detail pane, which
+// showDetail marks as focused, and < and > resize the panes. It reports
+// whether it handled msg.
+func (a *App) handleSplitKeys(msg tea.KeyMsg, hasSelection bool) bool {
+	switch {
+	case a.keys.Tab.Matches(msg):
+		if a.showDetail || hasSelection {
+			a.showDetail = !a.showDetail
+		}
+		return true
+	case msg.String() == "<":
+		a.resizeSplit(-splitRatioStep)
+		return true
+	case msg.String() == ">":
+		a.resizeSplit(splitRatioStep)
+		return true
+	}
+	return false
+}
+
+// resizeSplit widens the list pane by delta of the terminal width, within
+// bounds.
+func (a *App) resizeSplit(delta float64) {
+	ratio := a.splitRatio + delta
+	if ratio < minSplitRatio {
+		ratio = minSplitRatio
+	}
+	if ratio > maxSplitRatio {
+		ratio = maxSplitRatio
+	}
+	a.splitRatio = ratio
+}
+
+// followSelection loads what the detail pane shows of the resident selected
+// in the census list, or the lot selected in the inventory, as the selection
+// moves.
+func (a *App) followSelection() tea.Cmd {
+	if !a.splitView() {
+		return nil
+	}
+	if a.currentModule == ModuleResources {
+		stock := a.inventoryView.SelectedStock()
+		if stock == nil || a.inventoryView.HasQualityTests(stock.ID) {
+			return nil
+		}
+		return a.loadQualityTests(stock.ID)
+	}
+	if a.currentModule != ModulePopulation {
+		return nil
+	}
+	resident := a.censusView.SelectedResident()
+	if resident == nil || a.censusView.HasRelationships(resident.ID) {
+		return nil
+	}
+	return a.loadRelationships(resident)
+}
+
+// splitWidths returns the widths of the list and detail panes, borders
+// included.
+func (a *App) splitWidths() (list, detail int) {
+	return SplitWidths(a.width, a.splitRatio, minPaneWidth)
+}
+
+// renderSplit renders list and detail in bordered panes side by side, the
+// focused one highlighted.
+func (a *App) renderSplit(list, detail string) string {
+	listWidth, detailWidth := a.splitWidths()
+	height := ContentHeight(a.height, chromeLines) - 2 // -2 for border lines
+
+	pane := func(content string, width int, focused bool) string {
+		color := a.theme.MutedColor
+		if focused {
+			color = a.theme.AccentColor
+		}
+		return lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(color).
+			Width(width - 2). // -2 for border chars
+			Height(height).
+			MaxHeight(height + 2).
+			Render(content)
+	}
+
+	return lipgloss.JoinHorizontal(lipgloss.Top,
+		pane(list, listWidth, !a.showDetail),
+		pane(detail, detailWidth, a.showDetail))
+}
+
+// paneWidth returns the width available to the content of the pane of the
+// given width.
+func paneWidth(width int) int {
+	return width - 2
+}
@@ -0,0 +1,149 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/services/resources"
+)
+
+// startStockDetailAction prompts for an adjustment, split or status change
+// of the lot in the detail view. Each names the resident authorizing it by
+// registry number.
+func (a *App) startStockDetailAction(key string) {
+	stock := a.inventoryView.SelectedStock()
+	if stock == nil || a.denyReadOnly() {
+		return
+	}
+	name := "stock"
+	if stock.Item != nil {
+		name = stock.Item.Name
+	}
+
+	action := &quickAction{targetID: stock.ID, targetName: name}
+	switch key {
+	case "x":
+		action.kind = quickActionAdjustLot
+		action.prompt = "Adjust " + name + ": +/-QTY AUTHORIZER REASON: "
+	case "p":
+		action.kind = quickActionSplitLot
+		action.prompt = fmt.Sprintf("Split %s (%.2f): QTY NEW-LOT AUTHORIZER [LOCATION]: ", name, stock.AvailableQuantity())
+	case "d":
+		if stock.Status == models.StockStatusDamaged {
+			a.AddAlert(AlertInfo, name+" is already DAMAGED")
+			return
+		}
+		action.kind = quickActionDamageLot
+		action.prompt = "Mark " + name + " DAMAGED: AUTHORIZER DAMAGE FOUND: "
+	case "q":
+		if stock.Status == models.StockStatusQuarantine {
+			a.AddAlert(AlertInfo, name+" is already in QUARANTINE")
+			return
+		}
+		action.kind = quickActionQuarantineLot
+		action.prompt = "Quarantine " + name + ": AUTHORIZER REASON: "
+	default:
+		return
+	}
+	a.quickAction = action
+}
+
+// runStockDetailAction adjusts, splits, or marks damaged or quarantined the
+// lot in the detail view.
+func (a *App) runStockDetailAction(ctx context.Context, action *quickAction, input string) quickActionDoneMsg {
+	fields := strings.Fields(input)
+	need, expected := 2, "authorizer and reason"
+	switch action.kind {
+	case quickActionAdjustLot:
+		need, expected = 3, "quantity, authorizer and reason"
+	case quickActionSplitLot:
+		need, expected = 3, "quantity, new lot number and authorizer"
+	}
+	if len(fields) < need {
+		return quickActionDoneMsg{module: ModuleResources, err: fmt.Errorf("%w: expected %s", repository.ErrValidation, expected)}
+	}
+
+	switch action.kind {
+	case quickActionAdjustLot:
+		change, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil || change == 0 {
+			return quickActionDoneMsg{module: ModuleResources, err: fmt.Errorf("%w: invalid quantity %q", repository.ErrValidation, fields[0])}
+		}
+		authorizer, err := a.stockAuthorizer(ctx, fields[1])
+		if err != nil {
+			return quickActionDoneMsg{module: ModuleResources, err: err}
+		}
+		adjustment := resources.StockAdjustment{
+			QuantityChange: change,
+			Type:           models.TransactionTypeAdjustment,
+			Reason:         strings.Join(fields[2:], " "),
+			AuthorizedBy:   &authorizer.ID,
+		}
+		err = a.resourceSvc.AdjustStock(ctx, action.targetID, adjustment)
+		return quickActionDoneMsg{module: ModuleResources, success: fmt.Sprintf("%s adjusted by %+g, authorized by %s",
+			action.targetName, change, authorizer.FullName()), undo: a.adjustStep(action.targetID, action.targetName, adjustment), err: err}
+
+	case quickActionSplitLot:
+		quantity, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return quickActionDoneMsg{module: ModuleResources, err: fmt.Errorf("%w: invalid quantity %q", repository.ErrValidation, fields[0])}
+		}
+		authorizer, err := a.stockAuthorizer(ctx, fields[2])
+		if err != nil {
+			return quickActionDoneMsg{module: ModuleResources, err: err}
+		}
+		split := resources.SplitStockInput{
+			StockID:      action.targetID,
+			Quantity:     quantity,
+			LotNumber:    strings.ToUpper(fields[1]),
+			AuthorizedBy: &authorizer.ID,
+		}
+		if len(fields) > 3 {
+			split.StorageLocation = strings.ToUpper(fields[3])
+		}
+		lot, err := a.resourceSvc.SplitStock(ctx, split)
+		if err != nil {
+			return quickActionDoneMsg{module: ModuleResources, err: err}
+		}
+		return quickActionDoneMsg{module: ModuleResources, success: fmt.Sprintf("Split %.2f of %s into lot %s at %s",
+			lot.Quantity, action.targetName, *lot.LotNumber, lot.StorageLocation)}
+
+	default:
+		authorizer, err := a.stockAuthorizer(ctx, fields[0])
+		if err != nil {
+			return quickActionDoneMsg{module: ModuleResources, err: err}
+		}
+		status := models.StockStatusDamaged
+		alert := AlertWarning
+		if action.kind == quickActionQuarantineLot {
+			status = models.StockStatusQuarantine
+			alert = AlertInfo
+		}
+		err = a.resourceSvc.SetStockStatus(ctx, resources.StockStatusInput{
+			StockID:      action.targetID,
+			Status:       status,
+			Note:         strings.Join(fields[1:], " "),
+			AuthorizedBy: &authorizer.ID,
+		})
+		return quickActionDoneMsg{module: ModuleResources, alert: alert, success: fmt.Sprintf("%s marked %s, authorized by %s",
+			action.targetName, status, authorizer.FullName()), err: err}
+	}
+}
+
+// stockAuthorizer looks up the active resident authorizing a stock change by
+// registry number.
+func (a *App) stockAuthorizer(ctx context.Context, regNum string) (*models.Resident, error) {
+	regNum = strings.ToUpper(regNum)
+	resident, err := a.populationSvc.GetResidentByRegistryNumber(ctx, regNum)
+	if err != nil {
+		return nil, fmt.Errorf("authorizer %s: %w", regNum, err)
+	}
+	if resident.Status != models.ResidentStatusActive {
+		return nil, fmt.Errorf("%w: authorizer %s is %s", repository.ErrValidation, regNum, resident.Status)
+	}
+	return resident, nil
+}
@@ -4,6 +4,8 @@ package tui
 import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/vtuos/vtuos/internal/config"
+	"github.com/vtuos/vtuos/internal/i18n"
+	"github.com/vtuos/vtuos/internal/tui/charts"
 )
 
 // Theme contains all style definitions for the TUI.
@@ -70,6 +72,13 @@ type Theme struct {
 	StatusKey     lipgloss.Style
 	StatusValue   lipgloss.Style
 	StatusDivider lipgloss.Style
+
+	// Plain is set in screen reader mode: indicators are spelled out in
+	// words rather than drawn or told apart by color.
+	Plain bool
+
+	// Locale is the language text is shown in.
+	Locale i18n.Locale
 }
 
 // NewTheme creates a new theme based on the color scheme configuration.
@@ -77,6 +86,8 @@ func NewTheme(scheme config.ColorScheme) *Theme {
 	switch scheme {
 	case config.ColorSchemeAmber:
 		return newAmberTheme()
+	case config.ColorSchemeBlue:
+		return newBluePhosphorTheme()
 	case config.ColorSchemeWhite:
 		return newWhiteTheme()
 	default:
@@ -84,6 +95,14 @@ func NewTheme(scheme config.ColorScheme) *Theme {
 	}
 }
 
+// newDisplayTheme creates the theme of a display configuration.
+func newDisplayTheme(display *config.DisplayConfig) *Theme {
+	t := NewTheme(display.ColorScheme)
+	t.Plain = display.ScreenReader
+	t.Locale = display.Language()
+	return t
+}
+
 // newGreenPhosphorTheme creates the classic green phosphor terminal theme.
 func newGreenPhosphorTheme() *Theme {
 	primary := lipgloss.Color("#00FF00")
@@ -114,6 +133,21 @@ func newAmberTheme() *Theme {
 	return buildTheme(primary, secondary, accent, background, foreground, muted, errorColor, warningColor, successColor)
 }
 
+// newBluePhosphorTheme creates a blue/cyan phosphor terminal theme.
+func newBluePhosphorTheme() *Theme {
+	primary := lipgloss.Color("#33CCFF")
+	secondary := lipgloss.Color("#1F7FAA")
+	accent := lipgloss.Color("#99E6FF")
+	background := lipgloss.Color("#000000")
+	foreground := lipgloss.Color("#33CCFF")
+	muted := lipgloss.Color("#0F4055")
+	errorColor := lipgloss.Color("#FF4444")
+	warningColor := lipgloss.Color("#FFAA00")
+	successColor := lipgloss.Color("#33FF99")
+
+	return buildTheme(primary, secondary, accent, background, foreground, muted, errorColor, warningColor, successColor)
+}
+
 // newWhiteTheme creates a white/monochrome terminal theme.
 func newWhiteTheme() *Theme {
 	primary := lipgloss.Color("#FFFFFF")
@@ -338,3 +372,14 @@ func (t *Theme) DrawDoubleLine(width int) string {
 	}
 	return t.Primary.Render(line)
 }
+
+// ChartStyles returns the styles charts are drawn in with the theme.
+func (t *Theme) ChartStyles() charts.Styles {
+	return charts.Styles{
+		Line:     t.Value,
+		Good:     t.Success,
+		Warning:  t.Warning,
+		Critical: t.Error,
+		Axis:     t.Muted,
+	}
+}
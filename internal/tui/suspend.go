@@ -0,0 +1,50 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// suspendMsg asks the app to suspend the process, from Ctrl+Z or SIGTSTP.
+type suspendMsg struct{}
+
+// resumeMsg is sent once the process has been continued and the terminal
+// taken back.
+type resumeMsg struct {
+	err error
+}
+
+// handleSuspend pauses the vault clock and hands the terminal back to the
+// shell before stopping the process. The clock stays paused while stopped so
+// no vault time passes unseen.
+func (a *App) handleSuspend() (tea.Model, tea.Cmd) {
+	if a.suspended || a.program == nil || !canSuspend {
+		return a, nil
+	}
+	a.suspended = true
+	a.clockWasPaused = a.clock.IsPaused()
+	a.clock.Pause()
+
+	p := a.program
+	return a, func() tea.Msg {
+		if err := p.ReleaseTerminal(); err != nil {
+			return resumeMsg{err: err}
+		}
+		suspendProcess()
+		return resumeMsg{err: p.RestoreTerminal()}
+	}
+}
+
+// handleResume restarts the vault clock after a suspend. RestoreTerminal
+// has already queued a repaint and resize check.
+func (a *App) handleResume(msg resumeMsg) (tea.Model, tea.Cmd) {
+	a.suspended = false
+	if !a.clockWasPaused {
+		a.clock.Resume()
+	}
+	if msg.err != nil {
+		a.AddError("Failed to restore terminal", msg.err)
+	}
+	a.censusView.SetVaultTime(a.clock.Now())
+	a.inventoryView.SetVaultTime(a.clock.Now())
+	return a, tea.ClearScreen
+}
@@ -0,0 +1,53 @@
+//go:build !windows
+
+package tui
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// canSuspend reports whether the platform supports job control.
+const canSuspend = true
+
+// watchSuspendSignal routes SIGTSTP from outside the terminal (kill -TSTP)
+// through the app so it suspends as cleanly as Ctrl+Z. Ctrl+Z itself arrives
+// as a key press because the terminal is in raw mode.
+func watchSuspendSignal(p *tea.Program) (stop func()) {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGTSTP)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-sigs:
+				p.Send(suspendMsg{})
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(sigs)
+		close(done)
+	}
+}
+
+// suspendProcess stops the process and blocks until it is continued.
+// SIGSTOP is used because SIGTSTP is caught by watchSuspendSignal; the shell
+// sees a stopped job either way.
+func suspendProcess() {
+	cont := make(chan os.Signal, 1)
+	signal.Notify(cont, syscall.SIGCONT)
+	defer signal.Stop(cont)
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGSTOP); err != nil {
+		return
+	}
+	<-cont
+}
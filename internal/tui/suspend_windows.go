@@ -0,0 +1,18 @@
+//go:build windows
+
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// canSuspend reports whether the platform supports job control.
+const canSuspend = false
+
+// watchSuspendSignal is a no-op; Windows has no SIGTSTP.
+func watchSuspendSignal(p *tea.Program) (stop func()) {
+	return func() {}
+}
+
+// suspendProcess is a no-op; Windows has no job control.
+func suspendProcess() {}
@@ -0,0 +1,206 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/tui/components"
+)
+
+// censusPresetActions are the actions available in the census preset menu.
+var censusPresetActions = components.NewActionBar(
+	components.Action{Key: "n", Label: "Save current"},
+	components.Action{Key: "x", Label: "Delete"},
+)
+
+// censusPresetsMsg carries the vault's census presets.
+type censusPresetsMsg struct {
+	presets []*models.CensusPreset
+	err     error
+}
+
+// censusTagFilterMsg carries the tags entered at the tag filter prompt,
+// none to lift the filter.
+type censusTagFilterMsg struct {
+	tags []string
+	err  error
+}
+
+// startTagAction prompts for the tags to add to residents and take off
+// them. Unlike the other census actions it applies to the deceased too.
+func (a *App) startTagAction(residents []*models.Resident) {
+	action := &quickAction{kind: quickActionTag}
+	for _, r := range residents {
+		action.targetIDs = append(action.targetIDs, r.ID)
+	}
+	action.targetName = fmt.Sprintf("%d resident(s)", len(residents))
+	if len(residents) == 1 {
+		action.targetName = residents[0].FullName()
+	}
+	action.prompt = "Tags for " + action.targetName + ", comma-separated, -tag to remove: "
+	a.quickAction = action
+}
+
+// startTagFilter prompts for the tags to limit the census to.
+func (a *App) startTagFilter() {
+	a.quickAction = &quickAction{
+		kind:   quickActionTagFilter,
+		prompt: "Residents tagged, comma-separated (- for any): ",
+	}
+}
+
+// openCensusPresets opens the census preset menu.
+func (a *App) openCensusPresets() tea.Cmd {
+	a.showPresets = true
+	return a.loadCensusPresets()
+}
+
+// loadCensusPresets loads the vault's census presets.
+func (a *App) loadCensusPresets() tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := a.operation()
+		defer cancel()
+		presets, err := a.populationSvc.ListCensusPresets(ctx)
+		return censusPresetsMsg{presets: presets, err: err}
+	}
+}
+
+// handleCensusPresets stores the loaded presets, keeping the selection in
+// range.
+func (a *App) handleCensusPresets(msg censusPresetsMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		a.AddError("Failed to load census presets", msg.err)
+		return a, nil
+	}
+	a.presets = msg.presets
+	a.presetIndex = max(min(a.presetIndex, len(a.presets)-1), 0)
+	return a, nil
+}
+
+// handleCensusTagFilter limits the census to the tags entered.
+func (a *App) handleCensusTagFilter(msg censusTagFilterMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		a.AddError("Invalid tags", msg.err)
+		return a, nil
+	}
+	a.censusView.SetTagFilter(msg.tags)
+	return a, a.loadCensus()
+}
+
+// handleCensusPresetKeys handles key presses in the census preset menu.
+// Esc, handled with the other back keys, closes it.
+func (a *App) handleCensusPresetKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		if a.presetIndex > 0 {
+			a.presetIndex--
+		}
+	case "down", "j":
+		if a.presetIndex < len(a.presets)-1 {
+			a.presetIndex++
+		}
+	case "enter":
+		if len(a.presets) == 0 {
+			return a, nil
+		}
+		preset := a.presets[a.presetIndex]
+		a.censusView.ApplyPreset(preset)
+		a.showPresets = false
+		a.AddAlert(AlertInfo, "Census preset "+preset.Name+" applied")
+		return a, a.loadCensus()
+	case "n":
+		if a.denyReadOnly() {
+			return a, nil
+		}
+		filter, sort := a.censusView.Filter()
+		a.quickAction = &quickAction{
+			kind:   quickActionSavePreset,
+			preset: &models.CensusPreset{Filter: filter, Sort: sort},
+			prompt: "Save the census filter and sort as: ",
+		}
+	case "x":
+		if len(a.presets) == 0 || a.denyReadOnly() {
+			return a, nil
+		}
+		preset := a.presets[a.presetIndex]
+		a.quickAction = &quickAction{
+			kind:       quickActionDeletePreset,
+			targetID:   preset.ID,
+			targetName: preset.Name,
+			prompt:     "Delete census preset " + preset.Name + "? (y/n)",
+			confirm:    true,
+		}
+	}
+	return a, nil
+}
+
+// runCensusTagAction tags residents, limits the census to tags, or saves
+// or deletes a census preset.
+func (a *App) runCensusTagAction(ctx context.Context, action *quickAction, input string) tea.Msg {
+	switch action.kind {
+	case quickActionTag:
+		add, remove, err := models.ParseTagEdits(input)
+		if err != nil {
+			return quickActionDoneMsg{module: ModulePopulation, err: fmt.Errorf("%w: %w", repository.ErrValidation, err)}
+		}
+		err = a.populationSvc.TagResidents(ctx, action.targetIDs, add, remove)
+		return quickActionDoneMsg{module: ModulePopulation, success: "Tags updated for " + action.targetName, err: err}
+
+	case quickActionTagFilter:
+		if input == "-" {
+			return censusTagFilterMsg{}
+		}
+		tags, err := models.ParseTags(input)
+		return censusTagFilterMsg{tags: tags, err: err}
+
+	case quickActionSavePreset:
+		preset, err := a.populationSvc.SaveCensusPreset(ctx, input, action.preset.Filter, action.preset.Sort)
+		if err != nil {
+			return quickActionDoneMsg{module: ModulePopulation, err: err}
+		}
+		return quickActionDoneMsg{module: ModulePopulation, success: "Census preset " + preset.Name + " saved"}
+
+	case quickActionDeletePreset:
+		err := a.populationSvc.DeleteCensusPreset(ctx, action.targetID)
+		return quickActionDoneMsg{module: ModulePopulation, success: "Census preset " + action.targetName + " deleted", err: err}
+	}
+	return nil
+}
+
+// renderCensusPresets renders the census preset menu: the current filter,
+// then the saved presets with what each selects.
+func (a *App) renderCensusPresets(width int) string {
+	var b strings.Builder
+	b.WriteString(a.theme.Title.Render("═══ CENSUS PRESETS ═══"))
+	b.WriteString("\n\n")
+
+	filter, sort := a.censusView.Filter()
+	current := &models.CensusPreset{Filter: filter, Sort: sort}
+	b.WriteString(a.theme.Muted.Render(Truncate("  Current: "+current.Describe(), width-2)))
+	b.WriteString("\n\n")
+
+	if len(a.presets) == 0 {
+		b.WriteString(a.theme.Muted.Render("  No saved presets"))
+		b.WriteString("\n")
+	}
+	for i, preset := range a.presets {
+		line := Truncate(fmt.Sprintf("%-20s %s", Truncate(preset.Name, 20), preset.Describe()), width-4)
+		if i == a.presetIndex {
+			b.WriteString(a.theme.Selected.Render("> " + line))
+		} else {
+			b.WriteString("  " + a.theme.Base.Render(line))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n  ")
+	b.WriteString(a.renderActionBarIn(censusPresetActions, width))
+	b.WriteString("\n")
+	b.WriteString(a.theme.Muted.Render("  " + a.theme.Locale.T("↑/↓ select  Enter apply  Esc close")))
+	return b.String()
+}
@@ -0,0 +1,183 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/vtuos/vtuos/internal/repository"
+	"github.com/vtuos/vtuos/internal/services/population"
+	"github.com/vtuos/vtuos/internal/tui/components"
+)
+
+// trainingActions are the actions available on the labor screen's
+// apprentice rows.
+var trainingActions = components.NewActionBar(
+	components.Action{Key: "e", Label: "Enroll"},
+	components.Action{Key: "m", Label: "Mentor"},
+	components.Action{Key: "s", Label: "Sign off"},
+	components.Action{Key: "w", Label: "Withdraw"},
+)
+
+// trainingMsg carries the vault's active apprentices.
+type trainingMsg struct {
+	apprentices []population.Apprentice
+	err         error
+}
+
+// loadTraining loads the active apprentices.
+func (a *App) loadTraining() tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := a.operation()
+		defer cancel()
+		apprentices, err := a.populationSvc.ListApprentices(ctx)
+		return trainingMsg{apprentices: apprentices, err: err}
+	}
+}
+
+// handleLaborKeys handles key presses on the labor screen, whose apprentice
+// list takes the training actions.
+func (a *App) handleLaborKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch key := msg.String(); key {
+	case "up", "k":
+		if a.apprenticeIndex > 0 {
+			a.apprenticeIndex--
+		}
+	case "down", "j":
+		if a.apprenticeIndex < len(a.apprentices)-1 {
+			a.apprenticeIndex++
+		}
+	case "e":
+		if a.denyReadOnly() {
+			return a, nil
+		}
+		a.quickAction = &quickAction{
+			kind:   quickActionEnroll,
+			prompt: "Enroll APPRENTICE-REG VOCATION-CODE MENTOR-REG: ",
+		}
+	case "m", "s", "w":
+		if a.apprenticeIndex >= len(a.apprentices) || a.denyReadOnly() {
+			return a, nil
+		}
+		ap := a.apprentices[a.apprenticeIndex]
+		action := &quickAction{targetID: ap.Apprenticeship.ID, targetName: ap.Resident.FullName()}
+		switch key {
+		case "m":
+			action.kind = quickActionMentor
+			action.prompt = "New mentor registry number for " + action.targetName + ": "
+		case "s":
+			if !ap.Apprenticeship.HoursComplete() {
+				a.AddAlert(AlertWarning, fmt.Sprintf("%s has trained %.0f of %.0f hours",
+					action.targetName, ap.Apprenticeship.HoursCompleted, ap.Apprenticeship.RequiredHours))
+				return a, nil
+			}
+			if ap.Mentor == nil {
+				a.AddAlert(AlertWarning, action.targetName+" has no mentor to sign off; assign one first")
+				return a, nil
+			}
+			action.kind = quickActionSignOff
+			action.input = ap.Mentor.ID
+			action.prompt = fmt.Sprintf("Sign off %s as %s, signed by %s? (y/n)",
+				action.targetName, ap.Vocation.Title, ap.Mentor.FullName())
+			action.confirm = true
+		case "w":
+			action.kind = quickActionWithdraw
+			action.prompt = "Reason for withdrawing " + action.targetName + ": "
+		}
+		a.quickAction = action
+	case "r":
+		return a, a.loadTraining()
+	}
+	return a, nil
+}
+
+// runTrainingAction enrolls an apprentice, or changes the mentor of, signs
+// off or withdraws the selected one.
+func (a *App) runTrainingAction(ctx context.Context, action *quickAction, input string) quickActionDoneMsg {
+	switch action.kind {
+	case quickActionEnroll:
+		fields := strings.Fields(strings.ToUpper(input))
+		if len(fields) != 3 {
+			return quickActionDoneMsg{module: ModuleLabor, err: fmt.Errorf("%w: expected apprentice, vocation code and mentor", repository.ErrValidation)}
+		}
+		resident, err := a.populationSvc.GetResidentByRegistryNumber(ctx, fields[0])
+		if err != nil {
+			return quickActionDoneMsg{module: ModuleLabor, err: fmt.Errorf("apprentice %s: %w", fields[0], err)}
+		}
+		mentor, err := a.populationSvc.GetResidentByRegistryNumber(ctx, fields[2])
+		if err != nil {
+			return quickActionDoneMsg{module: ModuleLabor, err: fmt.Errorf("mentor %s: %w", fields[2], err)}
+		}
+		apprenticeship, err := a.populationSvc.Enroll(ctx, population.EnrollInput{
+			ResidentID:   resident.ID,
+			VocationCode: fields[1],
+			MentorID:     mentor.ID,
+		})
+		if err != nil {
+			return quickActionDoneMsg{module: ModuleLabor, err: err}
+		}
+		return quickActionDoneMsg{module: ModuleLabor, success: fmt.Sprintf("%s enrolled as %s apprentice under %s: %.0f hours",
+			resident.FullName(), fields[1], mentor.FullName(), apprenticeship.RequiredHours)}
+
+	case quickActionMentor:
+		mentor, err := a.populationSvc.GetResidentByRegistryNumber(ctx, strings.ToUpper(input))
+		if err != nil {
+			return quickActionDoneMsg{module: ModuleLabor, err: fmt.Errorf("mentor %s: %w", strings.ToUpper(input), err)}
+		}
+		err = a.populationSvc.ChangeMentor(ctx, action.targetID, mentor.ID)
+		return quickActionDoneMsg{module: ModuleLabor, success: mentor.FullName() + " now mentors " + action.targetName, err: err}
+
+	case quickActionSignOff:
+		_, err := a.populationSvc.SignOff(ctx, action.targetID, input)
+		return quickActionDoneMsg{module: ModuleLabor, success: action.targetName + " graduated", err: err}
+
+	default:
+		_, err := a.populationSvc.Withdraw(ctx, action.targetID, input)
+		return quickActionDoneMsg{module: ModuleLabor, success: action.targetName + " withdrawn from training", err: err}
+	}
+}
+
+// renderTraining renders the active apprentices with their training
+// progress, those ready for sign-off highlighted.
+func (a *App) renderTraining(barWidth int) string {
+	var b strings.Builder
+	b.WriteString(a.theme.Subtitle.Render("APPRENTICESHIPS"))
+	b.WriteString("\n")
+
+	if len(a.apprentices) == 0 {
+		b.WriteString(a.theme.Base.Render("  No residents in training.\n"))
+	}
+
+	for i, ap := range a.apprentices {
+		mentor := "-"
+		if ap.Mentor != nil {
+			mentor = ap.Mentor.FullName()
+		}
+		line := fmt.Sprintf("%-12s %-22s %-24s ", ap.Resident.RegistryNumber,
+			Truncate(ap.Resident.FullName(), 22), Truncate(ap.Vocation.Title, 24))
+		hours := fmt.Sprintf(" %5.0f/%-5.0f h  %s", ap.Apprenticeship.HoursCompleted,
+			ap.Apprenticeship.RequiredHours, Truncate(mentor, 22))
+
+		switch {
+		case i == a.apprenticeIndex:
+			b.WriteString(a.theme.Selected.Render("> " + line))
+		default:
+			b.WriteString("  " + a.theme.Base.Render(line))
+		}
+		b.WriteString(a.theme.ProgressBar(ap.Apprenticeship.Progress(), 1.0, barWidth))
+		if ap.Apprenticeship.HoursComplete() {
+			b.WriteString(a.theme.Success.Render(hours + "  READY"))
+		} else {
+			b.WriteString(a.theme.Value.Render(hours))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n  ")
+	b.WriteString(a.renderActionBar(trainingActions))
+	b.WriteString("\n")
+	b.WriteString(a.theme.Muted.Render("  " + a.theme.Locale.T("↑/↓ select  r reload")))
+	return b.String()
+}
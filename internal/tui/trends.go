@@ -0,0 +1,188 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/services/resources"
+)
+
+// Spans of the dashboard charts.
+const (
+	runwayHistoryDays     = 30  // Consumption history behind each runway
+	runwayHorizonDays     = 365 // Runway that fills a runway bar
+	populationTrendMonths = 12
+	efficiencyTrendDays   = 30
+	moraleTrendDays       = 30
+	efficiencyChartRows   = 5
+)
+
+// trendsMsg carries the series charted on the dashboard and the stock
+// shortages it lists.
+type trendsMsg struct {
+	runways    []*resources.CategoryRunway
+	shortages  []*models.Shortage
+	population []int
+	efficiency []float64
+	morale     []*models.MoraleSnapshot
+	err        error
+}
+
+// loadTrends loads the resource runways and shortages, the headcount trend,
+// the efficiency history of every facility system and the happiness index
+// history for the dashboard.
+func (a *App) loadTrends() tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := a.operation()
+		defer cancel()
+		now := a.clock.Now()
+		runways, err := a.resourceSvc.CategoryRunways(ctx, now, runwayHistoryDays)
+		if err != nil {
+			return trendsMsg{err: err}
+		}
+		shortages, err := a.resourceSvc.Shortages(ctx)
+		if err != nil {
+			return trendsMsg{err: err}
+		}
+		population, err := a.populationSvc.PopulationTrend(ctx, now, populationTrendMonths)
+		if err != nil {
+			return trendsMsg{err: err}
+		}
+		efficiency, err := a.facilitySvc.EfficiencyHistory(ctx, nil, now, efficiencyTrendDays)
+		if err != nil {
+			return trendsMsg{err: err}
+		}
+		morale, err := a.populationSvc.MoraleHistory(ctx, moraleTrendDays)
+		if err != nil {
+			return trendsMsg{err: err}
+		}
+		return trendsMsg{runways: runways, shortages: shortages, population: population, efficiency: efficiency, morale: morale}
+	}
+}
+
+// handleTrends stores the dashboard's series.
+func (a *App) handleTrends(msg trendsMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		a.AddError("Failed to load dashboard trends", msg.err)
+		return a, nil
+	}
+	a.runways = msg.runways
+	a.shortages = msg.shortages
+	a.populationTrend = msg.population
+	a.efficiencyTrend = msg.efficiency
+	a.moraleTrend = msg.morale
+	return a, nil
+}
+
+// dashboardPanelWidth returns the width a dashboard panel has: the whole
+// screen when panels stack, otherwise half of it less the gutter.
+func dashboardPanelWidth(totalWidth int, bp LayoutBreakpoint) int {
+	if bp == BreakpointNarrow {
+		return totalWidth - 2
+	}
+	return totalWidth/2 - 2
+}
+
+// renderPopulationTrend renders the headcount sparkline of the population
+// panel and the change over it.
+func (a *App) renderPopulationTrend(totalWidth int, bp LayoutBreakpoint) string {
+	if len(a.populationTrend) == 0 {
+		return ""
+	}
+	values := make([]float64, len(a.populationTrend))
+	for i, count := range a.populationTrend {
+		values[i] = float64(count)
+	}
+	change := a.populationTrend[len(a.populationTrend)-1] - a.populationTrend[0]
+	note := fmt.Sprintf(" %+d in %d mo", change, len(a.populationTrend))
+
+	width := dashboardPanelWidth(totalWidth, bp) - len("  Trend:    ") - len(note)
+	if width < 4 {
+		return ""
+	}
+	return "  Trend:    " + a.theme.ChartStyles().Sparkline(values, width) + a.theme.Muted.Render(note) + "\n"
+}
+
+// renderMorale renders the population panel's vault happiness index, its
+// level and a sparkline of its daily history with the change over it.
+func (a *App) renderMorale(totalWidth int, bp LayoutBreakpoint) string {
+	if len(a.moraleTrend) == 0 {
+		return ""
+	}
+	current := a.moraleTrend[len(a.moraleTrend)-1]
+	style := a.theme.Success
+	switch current.Level() {
+	case models.MoraleLow:
+		style = a.theme.Warning
+	case models.MoraleCritical:
+		style = a.theme.Error
+	}
+	line := "  Morale:   " + a.theme.Value.Render(fmt.Sprintf("%d", current.Index)) + " " + style.Render(string(current.Level()))
+
+	values := make([]float64, len(a.moraleTrend))
+	for i, s := range a.moraleTrend {
+		values[i] = float64(s.Index)
+	}
+	note := fmt.Sprintf(" %+d in %dd", current.Index-a.moraleTrend[0].Index, len(a.moraleTrend))
+	width := dashboardPanelWidth(totalWidth, bp) - len("  Morale:   ") - 5 - len(current.Level()) - len(note)
+	if len(values) > 1 && width >= 4 {
+		line += " " + a.theme.ChartStyles().Sparkline(values, width) + a.theme.Muted.Render(note)
+	}
+	return line + "\n"
+}
+
+// renderRunway renders one resource category's row of the resources panel:
+// its runway as a bar against a year, the days left, and a sparkline of
+// its daily consumption.
+func (a *App) renderRunway(runway *resources.CategoryRunway, barWidth, panelWidth int) string {
+	styles := a.theme.ChartStyles()
+	days, ratio := "--", 1.0
+	if runway.DaysRemaining >= 0 {
+		days = fmt.Sprintf("%dd", runway.DaysRemaining)
+		if runway.DaysRemaining > 999 {
+			days = ">999d"
+		}
+		ratio = float64(runway.DaysRemaining) / runwayHorizonDays
+	}
+
+	var b strings.Builder
+	b.WriteString(a.theme.Base.Render(fmt.Sprintf("  %-10s", Truncate(runway.Name, 10))))
+	if a.theme.Plain {
+		b.WriteString(a.theme.ProgressBar(ratio, 1, barWidth))
+	} else {
+		b.WriteString(styles.Bar(ratio, 1, barWidth))
+	}
+	b.WriteString(styles.Level(min(ratio, 1)).Render(fmt.Sprintf(" %5s", days)))
+	if width := panelWidth - 12 - barWidth - 7; width >= 4 {
+		b.WriteString(" ")
+		b.WriteString(styles.Sparkline(runway.History, width))
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// renderEfficiencyPanel renders the dashboard's line chart of the mean
+// efficiency of every facility system over the last days.
+func (a *App) renderEfficiencyPanel(totalWidth int) string {
+	var b strings.Builder
+	b.WriteString(a.theme.Subtitle.Render("SYSTEM EFFICIENCY"))
+	b.WriteString(a.theme.Muted.Render(fmt.Sprintf("  last %d days", efficiencyTrendDays)))
+	if n := len(a.efficiencyTrend); n > 0 {
+		b.WriteString(a.theme.Muted.Render(", now "))
+		b.WriteString(a.theme.Value.Render(fmt.Sprintf("%.1f%%", a.efficiencyTrend[n-1])))
+	}
+	b.WriteString("\n")
+
+	rows := a.theme.ChartStyles().LineChart(a.efficiencyTrend, totalWidth-4, efficiencyChartRows)
+	if len(rows) == 0 {
+		b.WriteString(a.theme.Muted.Render("  No facility systems"))
+		b.WriteString("\n")
+	}
+	for _, row := range rows {
+		b.WriteString("  " + row + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,242 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/services/population"
+	"github.com/vtuos/vtuos/internal/services/resources"
+)
+
+// Undo covers the changes made from this terminal that have a clean
+// inverse: resident edits, stock adjustments and, for a short grace period,
+// death registrations. Other actions, such as household moves, vocation
+// assignments, status changes and batch actions, cannot be undone, and the
+// history is dropped on switching vaults.
+const (
+	// undoDepth is how many recent changes can be undone.
+	undoDepth = 20
+
+	// deathUndoGrace is how long a death registration stays undoable, to
+	// correct a mistaken registration before others act on it.
+	deathUndoGrace = 10 * time.Minute
+)
+
+// errUndoExpired is returned when the change to undo is past its grace
+// period.
+var errUndoExpired = errors.New("grace period has passed")
+
+// undoStep is a change made from the terminal, with the operations that
+// revert and reapply it.
+type undoStep struct {
+	label  string // e.g. "adjustment of Water, Purified by +5"
+	module Module // Reloaded after the step is undone or redone
+	undo   func(ctx context.Context) error
+	redo   func(ctx context.Context) error
+	grace  time.Duration // How long the step stays undoable; 0 for no limit
+	at     time.Time     // When the change was last made
+}
+
+// undoHistory holds the changes that can be undone, most recent last, and
+// those undone that can be redone. One step runs at a time.
+type undoHistory struct {
+	done    []*undoStep
+	undone  []*undoStep
+	running bool
+	now     func() time.Time
+}
+
+// newUndoHistory creates an empty history.
+func newUndoHistory() *undoHistory {
+	return &undoHistory{now: time.Now}
+}
+
+// record adds a change just made. A new change drops the changes that could
+// be redone, and the oldest change beyond undoDepth.
+func (h *undoHistory) record(step *undoStep) {
+	step.at = h.now()
+	h.done = append(h.done, step)
+	if len(h.done) > undoDepth {
+		h.done = h.done[len(h.done)-undoDepth:]
+	}
+	h.undone = nil
+}
+
+// popUndo takes the most recent change to undo, or nil when there is none.
+// A change past its grace period is dropped with errUndoExpired.
+func (h *undoHistory) popUndo() (*undoStep, error) {
+	if h.running || len(h.done) == 0 {
+		return nil, nil
+	}
+	step := h.done[len(h.done)-1]
+	h.done = h.done[:len(h.done)-1]
+	if step.grace > 0 && h.now().Sub(step.at) > step.grace {
+		return step, errUndoExpired
+	}
+	h.running = true
+	return step, nil
+}
+
+// popRedo takes the most recently undone change, or nil when there is none.
+func (h *undoHistory) popRedo() *undoStep {
+	if h.running || len(h.undone) == 0 {
+		return nil
+	}
+	step := h.undone[len(h.undone)-1]
+	h.undone = h.undone[:len(h.undone)-1]
+	h.running = true
+	return step
+}
+
+// finish records the outcome of a step taken by popUndo or popRedo. A step
+// that succeeded can be taken back the other way; one that failed is
+// dropped, as the records may no longer match it.
+func (h *undoHistory) finish(step *undoStep, redo bool, err error) {
+	h.running = false
+	if err != nil {
+		return
+	}
+	if redo {
+		step.at = h.now()
+		h.done = append(h.done, step)
+		return
+	}
+	h.undone = append(h.undone, step)
+}
+
+// clear drops the whole history.
+func (h *undoHistory) clear() {
+	h.done, h.undone = nil, nil
+}
+
+// undoDoneMsg is sent when undoing or redoing a step completes.
+type undoDoneMsg struct {
+	step *undoStep
+	redo bool
+	err  error
+}
+
+// undoLast reverts the most recent undoable change.
+func (a *App) undoLast() tea.Cmd {
+	if a.denyReadOnly() {
+		return nil
+	}
+	step, err := a.undo.popUndo()
+	if err != nil {
+		a.AddAlert(AlertWarning, fmt.Sprintf("Cannot undo %s: %v", step.label, err))
+		return nil
+	}
+	if step == nil {
+		a.AddAlert(AlertInfo, "Nothing to undo")
+		return nil
+	}
+	return a.runUndoStep(step, false)
+}
+
+// redoLast reapplies the most recently undone change.
+func (a *App) redoLast() tea.Cmd {
+	if a.denyReadOnly() {
+		return nil
+	}
+	step := a.undo.popRedo()
+	if step == nil {
+		a.AddAlert(AlertInfo, "Nothing to redo")
+		return nil
+	}
+	return a.runUndoStep(step, true)
+}
+
+// runUndoStep undoes or redoes step against the services.
+func (a *App) runUndoStep(step *undoStep, redo bool) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := a.operation()
+		defer cancel()
+		run := step.undo
+		if redo {
+			run = step.redo
+		}
+		return undoDoneMsg{step: step, redo: redo, err: run(ctx)}
+	}
+}
+
+// handleUndoDone reports an undo or redo and reloads what it changed.
+func (a *App) handleUndoDone(msg undoDoneMsg) (tea.Model, tea.Cmd) {
+	a.undo.finish(msg.step, msg.redo, msg.err)
+	verb := "Undo"
+	if msg.redo {
+		verb = "Redo"
+	}
+	if msg.err != nil {
+		a.AddError(verb+" of "+msg.step.label+" failed", msg.err)
+	} else {
+		a.AddAlert(AlertInfo, verb+" of "+msg.step.label+" done")
+	}
+	if msg.step.module == ModuleResources {
+		return a, a.loadInventory()
+	}
+	return a, tea.Batch(a.loadCensus(), a.loadHouseholds(), a.loadPopulation())
+}
+
+// editStep returns the undo step of an edit of resident before, made with
+// input.
+func (a *App) editStep(before *models.Resident, input population.UpdateResidentInput) *undoStep {
+	revert := population.UpdateResidentInput{
+		Surname:        &before.Surname,
+		GivenNames:     &before.GivenNames,
+		BloodType:      &before.BloodType,
+		ClearanceLevel: &before.ClearanceLevel,
+		Notes:          &before.Notes,
+	}
+	return &undoStep{
+		label:  "edit of " + before.FullName(),
+		module: ModulePopulation,
+		undo: func(ctx context.Context) error {
+			_, err := a.populationSvc.UpdateResident(ctx, before.ID, revert)
+			return err
+		},
+		redo: func(ctx context.Context) error {
+			_, err := a.populationSvc.UpdateResident(ctx, before.ID, input)
+			return err
+		},
+	}
+}
+
+// deathStep returns the undo step of the death of resident, registered with
+// input.
+func (a *App) deathStep(resident *models.Resident, input population.DeathRegistration) *undoStep {
+	revocation := population.DeathRevocation{Status: resident.Status, Notes: resident.Notes}
+	return &undoStep{
+		label:  "death of " + resident.FullName(),
+		module: ModulePopulation,
+		grace:  deathUndoGrace,
+		undo: func(ctx context.Context) error {
+			return a.populationSvc.RevokeDeath(ctx, resident.ID, revocation)
+		},
+		redo: func(ctx context.Context) error {
+			return a.populationSvc.RegisterDeath(ctx, resident.ID, input)
+		},
+	}
+}
+
+// adjustStep returns the undo step of adjustment of the stock with the
+// given ID and name.
+func (a *App) adjustStep(stockID, name string, adjustment resources.StockAdjustment) *undoStep {
+	revert := adjustment
+	revert.QuantityChange = -adjustment.QuantityChange
+	revert.Reason = "Undo: " + adjustment.Reason
+	return &undoStep{
+		label:  fmt.Sprintf("adjustment of %s by %+g", name, adjustment.QuantityChange),
+		module: ModuleResources,
+		undo: func(ctx context.Context) error {
+			return a.resourceSvc.AdjustStock(ctx, stockID, revert)
+		},
+		redo: func(ctx context.Context) error {
+			return a.resourceSvc.AdjustStock(ctx, stockID, adjustment)
+		},
+	}
+}
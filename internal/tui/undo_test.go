@@ -0,0 +1,83 @@
+package tui
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestUndoHistory_UndoRedo(t *testing.T) {
+	h := newUndoHistory()
+	first, second := &undoStep{label: "first"}, &undoStep{label: "second"}
+	h.record(first)
+	h.record(second)
+
+	step, err := h.popUndo()
+	if err != nil || step != second {
+		t.Fatalf("Expected the latest change to undo first, got %v, %v", step, err)
+	}
+	if next, _ := h.popUndo(); next != nil {
+		t.Error("Expected no second undo while one is running")
+	}
+	h.finish(step, false, nil)
+
+	if redo := h.popRedo(); redo != second {
+		t.Fatalf("Expected the undone change to redo, got %v", redo)
+	}
+	h.finish(second, true, nil)
+	if step, _ := h.popUndo(); step != second {
+		t.Errorf("Expected a redone change to be undoable again, got %v", step)
+	}
+}
+
+func TestUndoHistory_NewChangeDropsRedo(t *testing.T) {
+	h := newUndoHistory()
+	h.record(&undoStep{label: "first"})
+	step, _ := h.popUndo()
+	h.finish(step, false, nil)
+
+	h.record(&undoStep{label: "second"})
+	if redo := h.popRedo(); redo != nil {
+		t.Errorf("Expected a new change to drop the redo history, got %v", redo)
+	}
+}
+
+func TestUndoHistory_FailedStepDropped(t *testing.T) {
+	h := newUndoHistory()
+	h.record(&undoStep{label: "first"})
+	step, _ := h.popUndo()
+	h.finish(step, false, errors.New("stock short"))
+
+	if redo := h.popRedo(); redo != nil {
+		t.Error("Expected a failed undo not to be redoable")
+	}
+	if step, _ := h.popUndo(); step != nil {
+		t.Error("Expected a failed undo not to be undoable again")
+	}
+}
+
+func TestUndoHistory_Depth(t *testing.T) {
+	h := newUndoHistory()
+	for i := 0; i < undoDepth+5; i++ {
+		h.record(&undoStep{})
+	}
+	if len(h.done) != undoDepth {
+		t.Errorf("Expected %d undoable changes, got %d", undoDepth, len(h.done))
+	}
+}
+
+func TestUndoHistory_GracePeriod(t *testing.T) {
+	now := time.Date(2077, 10, 23, 9, 0, 0, 0, time.UTC)
+	h := newUndoHistory()
+	h.now = func() time.Time { return now }
+	h.record(&undoStep{label: "death", grace: deathUndoGrace})
+
+	now = now.Add(deathUndoGrace + time.Second)
+	step, err := h.popUndo()
+	if !errors.Is(err, errUndoExpired) || step == nil {
+		t.Fatalf("Expected the change to have expired, got %v, %v", step, err)
+	}
+	if step, _ := h.popUndo(); step != nil {
+		t.Error("Expected an expired change to be dropped")
+	}
+}
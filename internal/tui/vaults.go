@@ -0,0 +1,291 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/vtuos/vtuos/internal/config"
+	"github.com/vtuos/vtuos/internal/database"
+	"github.com/vtuos/vtuos/internal/events"
+	"github.com/vtuos/vtuos/internal/journal"
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/services/facilities"
+	"github.com/vtuos/vtuos/internal/services/governance"
+	"github.com/vtuos/vtuos/internal/services/medical"
+	"github.com/vtuos/vtuos/internal/services/population"
+	"github.com/vtuos/vtuos/internal/services/resources"
+	"github.com/vtuos/vtuos/internal/services/security"
+	"github.com/vtuos/vtuos/internal/simulation"
+	popviews "github.com/vtuos/vtuos/internal/tui/views/population"
+	resviews "github.com/vtuos/vtuos/internal/tui/views/resources"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// vaultServices are the services of one managed vault, limited to its
+// residents, households, stock and facility systems.
+type vaultServices struct {
+	vault      config.ManagedVaultConfig
+	population *population.Service
+	resources  *resources.Service
+	facilities *facilities.Service
+	governance *governance.Service
+	medical    *medical.Service
+	security   *security.Service
+}
+
+// newVaultServices creates the services of a managed vault.
+func newVaultServices(db *database.DB, cfg *config.Config, clock *util.VaultClock, vault config.ManagedVaultConfig) *vaultServices {
+	popSvc := population.NewService(db.DB, vault.Number)
+	popSvc.SetClock(clock)
+	popSvc.SetRegistryFormat(cfg.RegistryFormat(vault.Number))
+	popSvc.SetPIIClearance(cfg.Privacy.PIIClearance)
+
+	resSvc := resources.NewService(db.DB)
+	resSvc.SetVault(vault.Number)
+	resSvc.SetClock(clock)
+	if cfg.Simulation.Consumption.Policy == config.ConsumptionPolicyFIFO {
+		resSvc.SetConsumptionPolicy(models.ConsumptionPolicyFIFO)
+	}
+
+	facSvc := facilities.NewService(db.DB)
+	facSvc.SetVault(vault.Number)
+	facSvc.SetClock(clock)
+
+	govSvc := governance.NewService(db.DB, vault.Number)
+	govSvc.SetClock(clock)
+
+	medSvc := medical.NewService(db.DB)
+	medSvc.SetVault(vault.Number)
+	medSvc.SetClock(clock)
+	medSvc.SetPIIClearance(cfg.Privacy.PIIClearance)
+
+	secSvc := security.NewService(db.DB)
+	secSvc.SetVault(vault.Number)
+	secSvc.SetClock(clock)
+
+	return &vaultServices{
+		vault:      vault,
+		population: popSvc,
+		resources:  resSvc,
+		facilities: facSvc,
+		governance: govSvc,
+		medical:    medSvc,
+		security:   secSvc,
+	}
+}
+
+// setJournal records the commands the vault's services run in j.
+func (v *vaultServices) setJournal(j *journal.Journal) {
+	v.population.SetJournal(j)
+	v.resources.SetJournal(j)
+	v.facilities.SetJournal(j)
+	v.governance.SetJournal(j)
+	v.medical.SetJournal(j)
+	v.security.SetJournal(j)
+}
+
+// setEvents publishes the events of the vault's services on bus.
+func (v *vaultServices) setEvents(bus *events.Bus) {
+	v.population.SetEvents(bus)
+	v.resources.SetEvents(bus)
+	v.facilities.SetEvents(bus)
+	v.medical.SetEvents(bus)
+	v.security.SetEvents(bus)
+}
+
+// schedule adds the vault's own scheduled jobs: rations, expiration,
+// shortages, maintenance planning, consumable stock, maintenance notices,
+// genetic health and the daily report. With several vaults managed, each job is named after its vault so
+// the tasks screen shows and runs them separately.
+func (v *vaultServices) schedule(scheduler *simulation.Scheduler, cfg *config.Config, managed int) {
+	jobs := []simulation.Job{
+		v.resources.RationJob(),
+		v.resources.ExpirationSweepJob(),
+		v.resources.ExpirationAlertJob(cfg.Simulation.Consumption.ExpirationWarningDays),
+		v.resources.ShortageCheckJob(),
+		v.facilities.MaintenancePlanningJob(),
+		v.facilities.ConsumableAlertJob(),
+		v.governance.MaintenanceNoticeJob(),
+		v.population.GeneticHealthJob(cfg.Simulation.GeneticHealthThreshold),
+		v.population.MoraleJob(),
+		dailyReportJob(cfg, v.governance),
+	}
+	for _, job := range jobs {
+		if managed > 1 {
+			job.Name = v.vault.Designation + ": " + job.Name
+		}
+		job.Vault = v.vault.Number
+		scheduler.Add(job)
+	}
+}
+
+// vaultsMsg carries the active population of each managed vault.
+type vaultsMsg struct {
+	populations []int
+	err         error
+}
+
+// currentVault returns the vault the terminal is administering.
+func (a *App) currentVault() config.ManagedVaultConfig {
+	return a.vaults[a.vaultIndex].vault
+}
+
+// openVaults switches to the vault switcher.
+func (a *App) openVaults() tea.Cmd {
+	a.currentModule = ModuleVaults
+	a.vaultCursor = a.vaultIndex
+	return a.loadVaults()
+}
+
+// loadVaults loads the active population of each managed vault.
+func (a *App) loadVaults() tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := a.operation()
+		defer cancel()
+
+		populations := make([]int, len(a.vaults))
+		for i, v := range a.vaults {
+			stats, err := v.population.GetPopulationStats(ctx)
+			if err != nil {
+				return vaultsMsg{err: err}
+			}
+			populations[i] = stats.TotalActive
+		}
+		return vaultsMsg{populations: populations}
+	}
+}
+
+// handleVaultKeys handles key presses on the vault switcher.
+func (a *App) handleVaultKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		if a.vaultCursor > 0 {
+			a.vaultCursor--
+		}
+	case "down", "j":
+		if a.vaultCursor < len(a.vaults)-1 {
+			a.vaultCursor++
+		}
+	case "enter":
+		return a, a.switchVault(a.vaultCursor)
+	case "r":
+		return a, a.loadVaults()
+	}
+	return a, nil
+}
+
+// switchVault makes the terminal administer the i-th managed vault: every
+// module shows and changes that vault's records, and the dashboard reopens
+// on it. The alert state and scheduled tasks cover every vault, so they are
+// unchanged.
+func (a *App) switchVault(i int) tea.Cmd {
+	if i == a.vaultIndex {
+		return a.gotoModule(ModuleDashboard)
+	}
+
+	v := a.vaults[i]
+	a.vaultIndex = i
+	a.populationSvc = v.population
+	a.resourceSvc = v.resources
+	a.facilitySvc = v.facilities
+	a.governanceSvc = v.governance
+	a.medicalSvc = v.medical
+	a.securitySvc = v.security
+
+	// Views hold the services they list through, so they start over
+	a.censusView.Loader().Stop()
+	a.householdsView.Loader().Stop()
+	a.inventoryView.Loader().Stop()
+	now := a.clock.Now()
+	a.censusView = popviews.NewCensusView(v.population)
+	a.censusView.SetVaultTime(now)
+	a.censusView.SetReadOnly(a.readOnly)
+	a.householdsView = popviews.NewHouseholdsView(v.population)
+	a.inventoryView = resviews.NewInventoryView(v.resources)
+	a.inventoryView.SetVaultTime(now)
+	a.setViewsPlain()
+	a.updateViewDimensions()
+	a.residentForm = nil
+	a.showForm = false
+	a.showDetail = false
+	a.showHouseholds = false
+	a.showIntakes = false
+	a.intakeWizard = nil
+	a.censusFilter = nil
+	a.catalogForm = nil
+	a.showAssets = false
+	a.assetCategory = ""
+	a.searchMode = false
+	a.searchInput = ""
+	a.badge = nil
+	a.undo.clear()
+
+	// Drop what was loaded for the previous vault
+	a.population = 0
+	a.pendingReviews = 0
+	a.pendingCare = 0
+	a.careQueue, a.careIndex = nil, 0
+	a.aptitudeCandidates, a.aptitudeIndex = nil, 0
+	a.grid, a.systemsStatus = nil, nil
+	a.runways, a.shortages, a.populationTrend, a.efficiencyTrend, a.moraleTrend = nil, nil, nil, nil, nil
+	a.planningReport, a.capacityForecast = nil, nil
+	a.radiationFlags, a.nutrition = nil, nil
+	a.accessPoints, a.accessEvents, a.accessPointIndex = nil, nil, 0
+	a.assets, a.assetHistory, a.assetIndex = nil, nil, 0
+	a.armoryIssues = nil
+	a.announcements, a.announcementInbox = nil, false
+	a.digest, a.digestDay = nil, time.Time{}
+	a.consumption = nil
+	a.dependencies, a.dependencyIndex = nil, 0
+	a.workOrders, a.workOrderIndex, a.workOrderForm = nil, 0, nil
+	a.showPresets, a.presets, a.presetIndex = false, nil, 0
+	a.sessionReport = nil
+
+	a.AddAlert(AlertInfo, "Now administering "+v.vault.Designation)
+	return tea.Batch(a.startSession(a.operatorID()), a.gotoModule(ModuleDashboard), a.loadPopulation())
+}
+
+// renderVaults renders the vault switcher: each vault administered from
+// this installation with its active population against capacity.
+func (a *App) renderVaults() string {
+	var b strings.Builder
+	b.WriteString(a.theme.Title.Render("═══ MANAGED VAULTS ═══"))
+	b.WriteString("\n\n")
+
+	header := fmt.Sprintf("  %-20s %-6s %-10s %-9s %s", "DESIGNATION", "NUMBER", "POPULATION", "CAPACITY", "")
+	b.WriteString(a.theme.TableHeader.Render(Truncate(header, a.width-2)))
+	b.WriteString("\n")
+
+	for i, v := range a.vaults {
+		population := "-"
+		if i < len(a.vaultPopulations) {
+			population = fmt.Sprintf("%d", a.vaultPopulations[i])
+		}
+		marker := ""
+		if i == a.vaultIndex {
+			marker = "CURRENT"
+		}
+		line := fmt.Sprintf("%-20s %-6d %-10s %-9d %s",
+			Truncate(v.vault.Designation, 20), v.vault.Number, population, v.vault.DesignedCapacity, marker)
+		line = Truncate(line, a.width-4)
+
+		if i == a.vaultCursor {
+			b.WriteString(a.theme.Selected.Render("> " + line))
+		} else {
+			b.WriteString("  " + a.theme.Base.Render(line))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(a.vaults) == 1 {
+		b.WriteString("\n")
+		b.WriteString(a.theme.Muted.Render("  Add [[vaults]] to the configuration to administer more vaults from this terminal"))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(a.theme.Muted.Render("  " + a.theme.Locale.T("↑/↓ select  Enter administer  r reload  Esc back")))
+	return b.String()
+}
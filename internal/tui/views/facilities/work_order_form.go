@@ -0,0 +1,177 @@
+// Package facilities provides TUI views for facility management.
+package facilities
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/vtuos/vtuos/internal/services/facilities"
+	"github.com/vtuos/vtuos/internal/tui/components"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// WorkOrderForm is a form for choosing the lead technician of an open work
+// order from the technicians suggested for it, or overriding the
+// suggestions with any resident's registry number.
+type WorkOrderForm struct {
+	order *facilities.WorkOrder
+
+	lead     *components.Select // Unassigned, then the suggestions best first
+	override *components.Input
+
+	fields     []components.FormField
+	focusIndex int
+	submitted  bool
+	cancelled  bool
+	err        string
+}
+
+// NewWorkOrderForm creates a form assigning order. The lead starts at the
+// order's current lead when suggested, in the override when not, and at the
+// best suggestion for an unassigned order.
+func NewWorkOrderForm(order *facilities.WorkOrder) *WorkOrderForm {
+	options := []string{"Unassigned"}
+	selected := 0
+	for i, sg := range order.Suggestions {
+		shift := "off shift"
+		if sg.OnShift {
+			shift = "on shift"
+		}
+		options = append(options, fmt.Sprintf("%s  skill %d  %d open  %s",
+			sg.Technician.Resident.RegistryNumber, sg.Rating, sg.Workload, shift))
+		if order.Lead != nil && sg.Technician.ResidentID == order.Lead.ID {
+			selected = i + 1
+		}
+	}
+
+	f := &WorkOrderForm{
+		order:    order,
+		lead:     components.NewSelect("Lead", options),
+		override: components.NewInput("Override").SetWidth(16).SetMaxLength(20).SetPlaceholder("registry #"),
+	}
+	switch {
+	case order.Lead != nil && selected == 0:
+		f.override.SetValue(order.Lead.RegistryNumber)
+	case order.Lead == nil && len(order.Suggestions) > 0:
+		selected = 1
+	}
+	f.lead.SetSelected(selected)
+	f.fields = []components.FormField{f.lead, f.override}
+	f.fields[0].Focus(true)
+	return f
+}
+
+// HandleKey handles key input.
+func (f *WorkOrderForm) HandleKey(key string) {
+	switch key {
+	case "tab", "down":
+		f.focus(f.focusIndex + 1)
+	case "shift+tab", "up":
+		f.focus(f.focusIndex - 1)
+	case "ctrl+s":
+		f.submit()
+	case "esc":
+		f.cancelled = true
+	case "enter":
+		if f.focusIndex == len(f.fields)-1 {
+			f.submit()
+		} else {
+			f.focus(f.focusIndex + 1)
+		}
+	default:
+		f.fields[f.focusIndex].HandleKey(key)
+	}
+}
+
+// focus moves focus to field i, wrapping around.
+func (f *WorkOrderForm) focus(i int) {
+	f.fields[f.focusIndex].Focus(false)
+	f.focusIndex = (i + len(f.fields)) % len(f.fields)
+	f.fields[f.focusIndex].Focus(true)
+}
+
+func (f *WorkOrderForm) submit() {
+	f.err = ""
+	f.submitted = true
+}
+
+// IsSubmitted returns true if the form was submitted.
+func (f *WorkOrderForm) IsSubmitted() bool {
+	return f.submitted
+}
+
+// IsCancelled returns true if the form was cancelled.
+func (f *WorkOrderForm) IsCancelled() bool {
+	return f.cancelled
+}
+
+// SetError shows an error from saving, keeping the form open.
+func (f *WorkOrderForm) SetError(err string) {
+	f.err = err
+	f.submitted = false
+}
+
+// RecordID returns the ID of the work order assigned.
+func (f *WorkOrderForm) RecordID() string {
+	return f.order.Record.ID
+}
+
+// GetData returns the registry number of the lead chosen: the override when
+// one is entered, otherwise the selected suggestion, or "" for unassigned.
+func (f *WorkOrderForm) GetData() string {
+	if reg := strings.ToUpper(strings.TrimSpace(f.override.Value())); reg != "" {
+		return reg
+	}
+	if idx := f.lead.SelectedIndex(); idx > 0 && idx <= len(f.order.Suggestions) {
+		return f.order.Suggestions[idx-1].Technician.Resident.RegistryNumber
+	}
+	return ""
+}
+
+// RenderResponsive renders the form adapted to the given terminal width.
+func (f *WorkOrderForm) RenderResponsive(width int) string {
+	titleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#66FF66")).Bold(true)
+	mutedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#00AA00"))
+	errStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FF4444"))
+
+	labelWidth := 16
+	if width > 0 && width < 60 {
+		labelWidth = 10
+	}
+
+	rec, sys := f.order.Record, f.order.System
+	scheduled := "unscheduled"
+	if rec.ScheduledDate != nil {
+		scheduled = util.FormatDate(*rec.ScheduledDate)
+	}
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("═══ WORK ORDER " + rec.ID + " ═══"))
+	b.WriteString("\n\n")
+	b.WriteString(mutedStyle.Render(fmt.Sprintf("%s %s (%s), %s %s", sys.SystemCode, sys.Name, sys.Category,
+		strings.ToLower(string(rec.MaintenanceType)), scheduled)))
+	b.WriteString("\n")
+	b.WriteString(mutedStyle.Render(rec.Description))
+	b.WriteString("\n\n")
+	if len(f.order.Suggestions) == 0 {
+		b.WriteString(mutedStyle.Render(fmt.Sprintf("No active technician is rated in %s", sys.Category)))
+		b.WriteString("\n")
+	}
+	for _, field := range f.fields {
+		b.WriteString(field.RenderWithLabelWidth(labelWidth))
+		b.WriteString("\n")
+	}
+	if f.err != "" {
+		b.WriteString("\n")
+		b.WriteString(errStyle.Render("Error: " + f.err))
+	}
+
+	b.WriteString("\n\n")
+	if width > 0 && width < 60 {
+		b.WriteString(mutedStyle.Render("Tab:Next  Ctrl+S:Save  Esc:Cancel"))
+	} else {
+		b.WriteString(mutedStyle.Render("Tab/Down:Next  Shift+Tab/Up:Prev  ←/→:Choose  Ctrl+S:Save  Esc:Cancel"))
+	}
+	return b.String()
+}
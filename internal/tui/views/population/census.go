@@ -11,6 +11,7 @@ import (
 	"github.com/vtuos/vtuos/internal/models"
 	"github.com/vtuos/vtuos/internal/services/population"
 	"github.com/vtuos/vtuos/internal/tui/components"
+	"github.com/vtuos/vtuos/internal/util"
 )
 
 // CensusView displays the resident census list.
@@ -19,11 +20,21 @@ type CensusView struct {
 	table     *components.Table
 	residents []*models.Resident
 	page      models.Pagination
+	cursors   []string // Keyset cursors of the pages before this one
+	next      string   // Keyset cursor of the next page
 	filter    models.ResidentFilter
-	loading   bool
+	loader    components.Loader
 	err       error
 	search    string
 	vaultTime time.Time
+	readOnly  bool
+	split     bool // Shown beside the detail pane
+
+	// Relationships and tags of the resident relationsOf, shown in the
+	// detail view
+	relationships []population.ResidentRelationship
+	tags          []string
+	relationsOf   string
 }
 
 // NewCensusView creates a new census view.
@@ -53,20 +64,36 @@ func NewCensusView(service *population.Service) *CensusView {
 	}
 }
 
-// Load fetches residents from the database.
-func (v *CensusView) Load(ctx context.Context) error {
-	v.loading = true
-	v.err = nil
+// Loader returns the loader tracking the view's background loads.
+func (v *CensusView) Loader() *components.Loader {
+	return &v.loader
+}
+
+// SetPlain turns screen reader mode on or off for the view's table and
+// loading spinner.
+func (v *CensusView) SetPlain(on bool) {
+	v.table.SetPlain(on)
+	v.loader.SetPlain(on)
+}
+
+// Fetch returns a query for the current page that reads no view state, so
+// it can run in the background while the view changes.
+func (v *CensusView) Fetch() func(context.Context) (*models.ResidentList, error) {
+	filter, page := v.filter, v.page
+	return func(ctx context.Context) (*models.ResidentList, error) {
+		return v.service.ListResidents(ctx, filter, page)
+	}
+}
 
-	result, err := v.service.ListResidents(ctx, v.filter, v.page)
+// SetResult shows a fetched page, or the error fetching it.
+func (v *CensusView) SetResult(result *models.ResidentList, err error) {
+	v.err = err
 	if err != nil {
-		v.loading = false
-		v.err = err
-		return err
+		return
 	}
 
 	v.residents = result.Residents
-	v.loading = false
+	v.next = result.NextCursor
 
 	// Convert to table rows
 	rows := make([][]string, len(v.residents))
@@ -91,8 +118,6 @@ func (v *CensusView) Load(ctx context.Context) error {
 
 	v.table.SetRows(rows)
 	v.table.SetPagination(result.Page, result.TotalPages, result.Total)
-
-	return nil
 }
 
 // SetVaultTime sets the current vault time for age calculation.
@@ -100,17 +125,92 @@ func (v *CensusView) SetVaultTime(t time.Time) {
 	v.vaultTime = t
 }
 
+// SetRelationships sets the relationships and tags shown in the detail view
+// of the resident with the given ID.
+func (v *CensusView) SetRelationships(residentID string, rels []population.ResidentRelationship, tags []string) {
+	v.relationsOf = residentID
+	v.relationships = rels
+	v.tags = tags
+}
+
+// HasRelationships reports whether the relationships and tags of the
+// resident with the given ID are loaded.
+func (v *CensusView) HasRelationships(residentID string) bool {
+	return v.relationsOf == residentID
+}
+
+// SetSplit sets whether the list is shown beside the detail pane, where Tab
+// moves focus between them.
+func (v *CensusView) SetSplit(split bool) {
+	v.split = split
+}
+
+// SetReadOnly hides the add, edit and death record hints on a read-only
+// terminal.
+func (v *CensusView) SetReadOnly(readOnly bool) {
+	v.readOnly = readOnly
+}
+
+// editHint returns hint unless the view is read-only.
+func (v *CensusView) editHint(hint string) string {
+	if v.readOnly {
+		return ""
+	}
+	return hint
+}
+
 // SetSearch sets the search filter.
 func (v *CensusView) SetSearch(term string) {
 	v.search = term
 	v.filter.SearchTerm = term
-	v.page.Page = 1
+	v.firstPage()
 }
 
 // SetStatusFilter sets the status filter.
 func (v *CensusView) SetStatusFilter(status *models.ResidentStatus) {
 	v.filter.Status = status
-	v.page.Page = 1
+	v.firstPage()
+}
+
+// SetTagFilter limits the census to residents carrying every one of the
+// tags, or lifts the limit when there are none.
+func (v *CensusView) SetTagFilter(tags []string) {
+	v.filter.Tags = tags
+	v.firstPage()
+}
+
+// SetFilter replaces the census filter, as the filter panel edits it.
+func (v *CensusView) SetFilter(filter models.ResidentFilter) {
+	v.filter = filter
+	v.firstPage()
+}
+
+// Filter returns the census filter and sort, as a preset saves them.
+func (v *CensusView) Filter() (models.ResidentFilter, models.SortOption) {
+	return v.filter, v.page.Sort
+}
+
+// ApplyPreset replaces the census filter and sort with a preset's.
+func (v *CensusView) ApplyPreset(preset *models.CensusPreset) {
+	v.filter = preset.Filter
+	v.search = preset.Filter.SearchTerm
+	v.page.Sort = preset.Sort
+	v.firstPage()
+}
+
+// CycleSort moves to the next sort column, returning to the default order
+// after the last.
+func (v *CensusView) CycleSort() {
+	v.page.Sort = v.page.Sort.Next(models.ResidentSortColumns)
+	v.firstPage()
+}
+
+// ReverseSort flips the direction of the current sort column.
+func (v *CensusView) ReverseSort() {
+	if v.page.Sort.IsSet() {
+		v.page.Sort = v.page.Sort.Reversed()
+		v.firstPage()
+	}
 }
 
 // SetVisibleRows sets the number of visible table rows.
@@ -118,16 +218,37 @@ func (v *CensusView) SetVisibleRows(n int) {
 	v.table.SetVisibleRows(n)
 }
 
-// NextPage moves to the next page.
+// NextPage moves to the next page, continuing after the last resident
+// shown rather than counting rows from the start. The next page is only
+// known once the current one loads, so presses while it loads are ignored.
 func (v *CensusView) NextPage() {
+	if v.next == "" {
+		return
+	}
+	v.cursors = append(v.cursors, v.next)
+	v.page.After = v.next
 	v.page.Page++
+	v.next = ""
 }
 
 // PrevPage moves to the previous page.
 func (v *CensusView) PrevPage() {
-	if v.page.Page > 1 {
-		v.page.Page--
+	if v.page.Page <= 1 || len(v.cursors) == 0 {
+		return
+	}
+	v.cursors = v.cursors[:len(v.cursors)-1]
+	v.page.After = ""
+	if n := len(v.cursors); n > 0 {
+		v.page.After = v.cursors[n-1]
 	}
+	v.page.Page--
+}
+
+// firstPage returns to the first page after the filter or sort changes.
+func (v *CensusView) firstPage() {
+	v.page.Page = 1
+	v.page.After = ""
+	v.cursors = nil
 }
 
 // MoveUp moves the selection up.
@@ -140,6 +261,18 @@ func (v *CensusView) MoveDown() {
 	v.table.MoveDown()
 }
 
+// SelectRegistry moves the selection to the resident with the given
+// registry number, reporting false when they are not on the page.
+func (v *CensusView) SelectRegistry(registryNumber string) bool {
+	for i, r := range v.residents {
+		if r.RegistryNumber == registryNumber {
+			v.table.Select(i)
+			return true
+		}
+	}
+	return false
+}
+
 // SelectedResident returns the currently selected resident.
 func (v *CensusView) SelectedResident() *models.Resident {
 	idx := v.table.Selected()
@@ -149,6 +282,33 @@ func (v *CensusView) SelectedResident() *models.Resident {
 	return nil
 }
 
+// ToggleMark marks the selected resident for a batch action, or unmarks
+// them.
+func (v *CensusView) ToggleMark() {
+	v.table.ToggleMark()
+}
+
+// MarkAll marks every resident on the page, or unmarks them all.
+func (v *CensusView) MarkAll() {
+	v.table.MarkAll()
+}
+
+// ClearMarks unmarks every resident.
+func (v *CensusView) ClearMarks() {
+	v.table.ClearMarks()
+}
+
+// MarkedResidents returns the residents marked for a batch action.
+func (v *CensusView) MarkedResidents() []*models.Resident {
+	var marked []*models.Resident
+	for _, i := range v.table.Marked() {
+		if i < len(v.residents) {
+			marked = append(marked, v.residents[i])
+		}
+	}
+	return marked
+}
+
 // Render renders the census view, responsive to the given terminal dimensions.
 func (v *CensusView) Render(width, height int) string {
 	titleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#66FF66")).Bold(true)
@@ -176,7 +336,40 @@ func (v *CensusView) Render(width, height int) string {
 		b.WriteString("\n")
 	}
 
-	if v.search != "" || v.filter.Status != nil {
+	if len(v.filter.Tags) > 0 {
+		b.WriteString(labelStyle.Render("Tags: "))
+		b.WriteString(valueStyle.Render(strings.Join(v.filter.Tags, ", ")))
+		b.WriteString("\n")
+	}
+
+	// The filter panel's fields
+	panel := models.ResidentFilter{
+		MinAge: v.filter.MinAge, MaxAge: v.filter.MaxAge,
+		MinClearance: v.filter.MinClearance, MaxClearance: v.filter.MaxClearance,
+		VocationCode: v.filter.VocationCode, HouseholdType: v.filter.HouseholdType,
+		Sector: v.filter.Sector,
+	}
+	filtered := panel.Describe()
+	if filtered != "" {
+		b.WriteString(labelStyle.Render("Filter: "))
+		b.WriteString(valueStyle.Render(filtered))
+		b.WriteString("\n")
+	}
+
+	if v.page.Sort.IsSet() {
+		b.WriteString(labelStyle.Render("Sort: "))
+		b.WriteString(valueStyle.Render(v.page.Sort.String()))
+		b.WriteString("\n")
+	}
+
+	marked := len(v.table.Marked())
+	if marked > 0 {
+		b.WriteString(labelStyle.Render("Marked: "))
+		b.WriteString(valueStyle.Render(fmt.Sprintf("%d", marked)))
+		b.WriteString("\n")
+	}
+
+	if v.search != "" || v.filter.Status != nil || len(v.filter.Tags) > 0 || filtered != "" || v.page.Sort.IsSet() || marked > 0 {
 		b.WriteString("\n")
 	}
 
@@ -186,24 +379,28 @@ func (v *CensusView) Render(width, height int) string {
 		b.WriteString("\n\n")
 	}
 
-	// Loading indicator
-	if v.loading {
-		b.WriteString(labelStyle.Render("Loading..."))
+	// Loading indicator; the previous page stays up until the next arrives
+	if v.loader.Loading() {
+		b.WriteString(labelStyle.Render(v.loader.Spinner() + " Loading..."))
 		b.WriteString("\n")
-	} else if v.table.Empty() {
-		b.WriteString(labelStyle.Render("No residents found."))
-		b.WriteString("\n")
-	} else {
+	}
+	switch {
+	case !v.table.Empty():
 		// Render table with responsive width
 		b.WriteString(v.table.RenderResponsive(width))
+	case !v.loader.Loading():
+		b.WriteString(labelStyle.Render("No residents found."))
+		b.WriteString("\n")
 	}
 
 	// Help - adapt to width
 	b.WriteString("\n")
 	if width < 60 {
-		b.WriteString(helpStyle.Render("↑↓:Nav  Enter:View  s:Search  a:Add"))
+		b.WriteString(helpStyle.Render("↑↓:Nav  Enter:View  s:Search" + v.editHint("  A:Add")))
+	} else if v.split {
+		b.WriteString(helpStyle.Render("Up/Down:Select  Tab:Detail  Space/a:Mark  s:Search" + v.editHint("  A:Add") + "  o/O:Sort  f:Filter  g:Tags  p:Presets  PgUp/Dn:Page  </>:Resize  Shift+Tab:Households"))
 	} else {
-		b.WriteString(helpStyle.Render("Up/Down:Select  Enter:Details  s:Search  a:Add  PgUp/Dn:Page"))
+		b.WriteString(helpStyle.Render("Up/Down:Select  Enter:Details  Space/a:Mark  s:Search" + v.editHint("  A:Add") + "  o/O:Sort  f:Filter  g:Tags  p:Presets  PgUp/Dn:Page  Tab:Households"))
 	}
 
 	return b.String()
@@ -246,12 +443,12 @@ func (v *CensusView) RenderDetail(resident *models.Resident, width int) string {
 	// Dates
 	b.WriteString(sectionStyle.Render("DATES"))
 	b.WriteString("\n")
-	b.WriteString(labelStyle.Render("Date of Birth:") + " " + valueStyle.Render(resident.DateOfBirth.Format("2006-01-02")) + "\n")
+	b.WriteString(labelStyle.Render("Date of Birth:") + " " + valueStyle.Render(util.FormatDate(resident.DateOfBirth)) + "\n")
 	b.WriteString(labelStyle.Render("Age:") + " " + valueStyle.Render(fmt.Sprintf("%d years", resident.Age(v.vaultTime))) + "\n")
 	b.WriteString(labelStyle.Render("Entry Type:") + " " + valueStyle.Render(string(resident.EntryType)) + "\n")
-	b.WriteString(labelStyle.Render("Entry Date:") + " " + valueStyle.Render(resident.EntryDate.Format("2006-01-02")) + "\n")
+	b.WriteString(labelStyle.Render("Entry Date:") + " " + valueStyle.Render(util.FormatDate(resident.EntryDate)) + "\n")
 	if resident.DateOfDeath != nil {
-		b.WriteString(labelStyle.Render("Date of Death:") + " " + valueStyle.Render(resident.DateOfDeath.Format("2006-01-02")) + "\n")
+		b.WriteString(labelStyle.Render("Date of Death:") + " " + valueStyle.Render(util.FormatDate(*resident.DateOfDeath)) + "\n")
 	}
 	b.WriteString("\n")
 
@@ -265,6 +462,35 @@ func (v *CensusView) RenderDetail(resident *models.Resident, width int) string {
 	}
 	b.WriteString("\n")
 
+	// Relationships, once loaded for this resident
+	if v.relationsOf == resident.ID && len(v.relationships) > 0 {
+		b.WriteString(sectionStyle.Render("RELATIONSHIPS"))
+		b.WriteString("\n")
+		for _, rel := range v.relationships {
+			value := fmt.Sprintf("%s %s, since %s", rel.Other.RegistryNumber, rel.Other.FullName(),
+				util.FormatDate(rel.StartDate))
+			if !rel.IsActive() {
+				value = fmt.Sprintf("%s %s, ended %s", rel.Other.RegistryNumber, rel.Other.FullName(),
+					util.FormatDate(*rel.EndDate))
+				if rel.EndReason != "" {
+					value += " (" + rel.EndReason + ")"
+				}
+				b.WriteString(labelStyle.Render(rel.Role+":") + " " + helpStyle.Render(value) + "\n")
+				continue
+			}
+			b.WriteString(labelStyle.Render(rel.Role+":") + " " + valueStyle.Render(value) + "\n")
+		}
+		b.WriteString("\n")
+	}
+
+	// Tags, once loaded for this resident
+	if v.relationsOf == resident.ID && len(v.tags) > 0 {
+		b.WriteString(sectionStyle.Render("TAGS"))
+		b.WriteString("\n")
+		b.WriteString(labelStyle.Render("") + valueStyle.Render(strings.Join(v.tags, ", ")))
+		b.WriteString("\n\n")
+	}
+
 	// Notes
 	if resident.Notes != "" {
 		b.WriteString(sectionStyle.Render("NOTES"))
@@ -274,9 +500,11 @@ func (v *CensusView) RenderDetail(resident *models.Resident, width int) string {
 	}
 
 	if width < 60 {
-		b.WriteString(helpStyle.Render("Esc:Back  e:Edit  d:Death"))
+		b.WriteString(helpStyle.Render("Esc:Back  i:Badge" + v.editHint("  e:Edit  d:Death")))
+	} else if v.split {
+		b.WriteString(helpStyle.Render("Tab/Esc:List  i:ID Badge" + v.editHint("  e:Edit  d:Death Record")))
 	} else {
-		b.WriteString(helpStyle.Render("Esc:Back  e:Edit  d:Death Record"))
+		b.WriteString(helpStyle.Render("Esc:Back  i:ID Badge" + v.editHint("  e:Edit  d:Death Record")))
 	}
 
 	return b.String()
@@ -0,0 +1,223 @@
+package population
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/tui/components"
+)
+
+// censusHouseholdTypes are the household type choices of the filter panel,
+// after "Any".
+var censusHouseholdTypes = []models.HouseholdType{
+	models.HouseholdTypeFamily,
+	models.HouseholdTypeIndividual,
+	models.HouseholdTypeCommunal,
+	models.HouseholdTypeTemporary,
+}
+
+// CensusFilterForm is the census filter panel: age and clearance ranges,
+// vocation, household type and quarters sector. A field left empty, or a
+// household type of Any, does not filter.
+type CensusFilterForm struct {
+	base models.ResidentFilter // The census filter the panel's fields replace
+
+	minAge       *components.Input
+	maxAge       *components.Input
+	minClearance *components.Input
+	maxClearance *components.Input
+	vocation     *components.Input
+	household    *components.Select
+	sector       *components.Input
+
+	fields     []components.FormField
+	focusIndex int
+	submitted  bool
+	cancelled  bool
+	err        string
+}
+
+// NewCensusFilterForm creates a filter panel showing the fields of filter.
+func NewCensusFilterForm(filter models.ResidentFilter) *CensusFilterForm {
+	options := []string{"Any"}
+	selected := 0
+	for i, t := range censusHouseholdTypes {
+		options = append(options, string(t))
+		if filter.HouseholdType != nil && *filter.HouseholdType == t {
+			selected = i + 1
+		}
+	}
+
+	f := &CensusFilterForm{
+		base:         filter,
+		minAge:       components.NewInput("Min age").SetWidth(4).SetMaxLength(3),
+		maxAge:       components.NewInput("Max age").SetWidth(4).SetMaxLength(3),
+		minClearance: components.NewInput("Min clearance").SetWidth(4).SetMaxLength(2).SetPlaceholder("1-10"),
+		maxClearance: components.NewInput("Max clearance").SetWidth(4).SetMaxLength(2).SetPlaceholder("1-10"),
+		vocation:     components.NewInput("Vocation").SetWidth(16).SetMaxLength(20).SetPlaceholder("code"),
+		household:    components.NewSelect("Household", options),
+		sector:       components.NewInput("Sector").SetWidth(4).SetMaxLength(10),
+	}
+	f.household.SetSelected(selected)
+	for input, value := range map[*components.Input]*int{
+		f.minAge:       filter.MinAge,
+		f.maxAge:       filter.MaxAge,
+		f.minClearance: filter.MinClearance,
+		f.maxClearance: filter.MaxClearance,
+	} {
+		if value != nil {
+			input.SetValue(strconv.Itoa(*value))
+		}
+	}
+	f.vocation.SetValue(filter.VocationCode)
+	f.sector.SetValue(filter.Sector)
+
+	f.fields = []components.FormField{
+		f.minAge, f.maxAge, f.minClearance, f.maxClearance,
+		f.vocation, f.household, f.sector,
+	}
+	f.fields[0].Focus(true)
+	return f
+}
+
+// HandleKey handles key input.
+func (f *CensusFilterForm) HandleKey(key string) {
+	switch key {
+	case "tab", "down":
+		f.focus(f.focusIndex + 1)
+	case "shift+tab", "up":
+		f.focus(f.focusIndex - 1)
+	case "ctrl+s":
+		f.submit()
+	case "ctrl+r":
+		f.clear()
+	case "esc":
+		f.cancelled = true
+	case "enter":
+		if f.focusIndex == len(f.fields)-1 {
+			f.submit()
+		} else {
+			f.focus(f.focusIndex + 1)
+		}
+	default:
+		f.fields[f.focusIndex].HandleKey(key)
+	}
+}
+
+// focus moves focus to field i, wrapping around.
+func (f *CensusFilterForm) focus(i int) {
+	f.fields[f.focusIndex].Focus(false)
+	f.focusIndex = (i + len(f.fields)) % len(f.fields)
+	f.fields[f.focusIndex].Focus(true)
+}
+
+// clear empties every field, lifting the panel's filters once applied.
+func (f *CensusFilterForm) clear() {
+	for _, input := range []*components.Input{f.minAge, f.maxAge, f.minClearance, f.maxClearance, f.vocation, f.sector} {
+		input.SetValue("").SetError("")
+	}
+	f.household.SetSelected(0)
+	f.err = ""
+}
+
+func (f *CensusFilterForm) submit() {
+	f.err = ""
+	valid := true
+	for _, number := range []*components.Input{f.minAge, f.maxAge, f.minClearance, f.maxClearance} {
+		if _, err := optionalInt(number); err != nil {
+			number.SetError("Whole number")
+			valid = false
+		} else {
+			number.SetError("")
+		}
+	}
+	if !valid {
+		f.err = "Please correct the highlighted fields"
+		return
+	}
+	filter := f.GetData()
+	if err := filter.Validate(); err != nil {
+		f.err = err.Error()
+		return
+	}
+	f.submitted = true
+}
+
+// IsSubmitted returns true if the form was submitted.
+func (f *CensusFilterForm) IsSubmitted() bool {
+	return f.submitted
+}
+
+// IsCancelled returns true if the form was cancelled.
+func (f *CensusFilterForm) IsCancelled() bool {
+	return f.cancelled
+}
+
+// GetData returns the census filter with the panel's fields replaced by
+// those entered, keeping its search, status and tags. Call it once
+// submitted.
+func (f *CensusFilterForm) GetData() models.ResidentFilter {
+	filter := f.base
+	filter.MinAge, _ = optionalInt(f.minAge)
+	filter.MaxAge, _ = optionalInt(f.maxAge)
+	filter.MinClearance, _ = optionalInt(f.minClearance)
+	filter.MaxClearance, _ = optionalInt(f.maxClearance)
+	filter.VocationCode = strings.ToUpper(strings.TrimSpace(f.vocation.Value()))
+	filter.Sector = strings.ToUpper(strings.TrimSpace(f.sector.Value()))
+	filter.HouseholdType = nil
+	if idx := f.household.SelectedIndex(); idx > 0 {
+		t := censusHouseholdTypes[idx-1]
+		filter.HouseholdType = &t
+	}
+	return filter
+}
+
+// RenderResponsive renders the form adapted to the given terminal width.
+func (f *CensusFilterForm) RenderResponsive(width int) string {
+	titleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#66FF66")).Bold(true)
+	mutedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#00AA00"))
+	errStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FF4444"))
+
+	labelWidth := 16
+	if width > 0 && width < 60 {
+		labelWidth = 10
+	}
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("═══ CENSUS FILTER ═══"))
+	b.WriteString("\n\n")
+	b.WriteString(mutedStyle.Render("Ages are reckoned at the vault date; sectors are those of residents' quarters"))
+	b.WriteString("\n\n")
+	for _, field := range f.fields {
+		b.WriteString(field.RenderWithLabelWidth(labelWidth))
+		b.WriteString("\n")
+	}
+	if f.err != "" {
+		b.WriteString("\n")
+		b.WriteString(errStyle.Render("Error: " + f.err))
+	}
+
+	b.WriteString("\n\n")
+	if width > 0 && width < 60 {
+		b.WriteString(mutedStyle.Render("Tab:Next  Ctrl+S:Apply  Esc:Cancel"))
+	} else {
+		b.WriteString(mutedStyle.Render("Tab/Down:Next  Shift+Tab/Up:Prev  ←/→:Choose  Ctrl+R:Clear  Ctrl+S:Apply  Esc:Cancel"))
+	}
+	return b.String()
+}
+
+// optionalInt parses an input holding a whole number, nil when it is empty.
+func optionalInt(input *components.Input) (*int, error) {
+	value := strings.TrimSpace(input.Value())
+	if value == "" {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid number %q", value)
+	}
+	return &n, nil
+}
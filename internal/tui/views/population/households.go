@@ -0,0 +1,212 @@
+package population
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/services/population"
+	"github.com/vtuos/vtuos/internal/tui/components"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// HouseholdsView displays the household list.
+type HouseholdsView struct {
+	service    *population.Service
+	table      *components.Table
+	households []*models.Household
+	page       models.Pagination
+	filter     models.HouseholdFilter
+	loader     components.Loader
+	err        error
+}
+
+// NewHouseholdsView creates a new households view showing active households.
+func NewHouseholdsView(service *population.Service) *HouseholdsView {
+	columns := []components.Column{
+		{Title: "Designation", Width: 11, Weight: 0, Priority: 10},
+		{Title: "Type", Width: 10, Weight: 1.0, Priority: 8},
+		{Title: "Members", Width: 7, Align: lipgloss.Right, Priority: 9},
+		{Title: "Ration", Width: 15, Weight: 1.0, Priority: 6},
+		{Title: "Status", Width: 9, Priority: 7},
+		{Title: "Formed", Width: 10, Priority: 4},
+		{Title: "Closed", Width: 10, Priority: 3},
+	}
+
+	table := components.NewTable(columns)
+	table.SetVisibleRows(25)
+	table.Focus(true)
+
+	active := models.HouseholdStatusActive
+	return &HouseholdsView{
+		service: service,
+		table:   table,
+		page:    models.Pagination{Page: 1, PageSize: 25},
+		filter:  models.HouseholdFilter{Status: &active},
+	}
+}
+
+// Loader returns the loader tracking the view's background loads.
+func (v *HouseholdsView) Loader() *components.Loader {
+	return &v.loader
+}
+
+// SetPlain turns screen reader mode on or off for the view's table and
+// loading spinner.
+func (v *HouseholdsView) SetPlain(on bool) {
+	v.table.SetPlain(on)
+	v.loader.SetPlain(on)
+}
+
+// Fetch returns a query for the current page that reads no view state, so
+// it can run in the background while the view changes.
+func (v *HouseholdsView) Fetch() func(context.Context) (*models.HouseholdList, error) {
+	filter, page := v.filter, v.page
+	return func(ctx context.Context) (*models.HouseholdList, error) {
+		return v.service.ListHouseholds(ctx, filter, page)
+	}
+}
+
+// SetResult shows a fetched page, or the error fetching it.
+func (v *HouseholdsView) SetResult(result *models.HouseholdList, err error) {
+	v.err = err
+	if err != nil {
+		return
+	}
+
+	v.households = result.Households
+
+	rows := make([][]string, len(v.households))
+	for i, h := range v.households {
+		closed := "-"
+		if h.DissolvedDate != nil {
+			closed = util.FormatDate(*h.DissolvedDate)
+		}
+		rows[i] = []string{
+			h.Designation,
+			string(h.HouseholdType),
+			fmt.Sprintf("%d", h.MemberCount),
+			string(h.RationClass),
+			string(h.Status),
+			util.FormatDate(h.FormedDate),
+			closed,
+		}
+	}
+
+	v.table.SetRows(rows)
+	v.table.SetPagination(result.Page, result.TotalPages, result.Total)
+}
+
+// ToggleClosed switches between active households and all households,
+// including dissolved and merged ones.
+func (v *HouseholdsView) ToggleClosed() {
+	if v.filter.Status == nil {
+		active := models.HouseholdStatusActive
+		v.filter.Status = &active
+	} else {
+		v.filter.Status = nil
+	}
+	v.page.Page = 1
+}
+
+// CycleSort moves to the next sort column, returning to the default order
+// after the last.
+func (v *HouseholdsView) CycleSort() {
+	v.page.Sort = v.page.Sort.Next(models.HouseholdSortColumns)
+	v.page.Page = 1
+}
+
+// ReverseSort flips the direction of the current sort column.
+func (v *HouseholdsView) ReverseSort() {
+	if v.page.Sort.IsSet() {
+		v.page.Sort = v.page.Sort.Reversed()
+		v.page.Page = 1
+	}
+}
+
+// SetVisibleRows sets the number of visible table rows.
+func (v *HouseholdsView) SetVisibleRows(n int) {
+	v.table.SetVisibleRows(n)
+}
+
+// NextPage moves to the next page.
+func (v *HouseholdsView) NextPage() {
+	v.page.Page++
+}
+
+// PrevPage moves to the previous page.
+func (v *HouseholdsView) PrevPage() {
+	if v.page.Page > 1 {
+		v.page.Page--
+	}
+}
+
+// MoveUp moves the selection up.
+func (v *HouseholdsView) MoveUp() {
+	v.table.MoveUp()
+}
+
+// MoveDown moves the selection down.
+func (v *HouseholdsView) MoveDown() {
+	v.table.MoveDown()
+}
+
+// SelectedHousehold returns the currently selected household.
+func (v *HouseholdsView) SelectedHousehold() *models.Household {
+	idx := v.table.Selected()
+	if idx >= 0 && idx < len(v.households) {
+		return v.households[idx]
+	}
+	return nil
+}
+
+// Render renders the households view, responsive to the given terminal dimensions.
+func (v *HouseholdsView) Render(width, height int) string {
+	titleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#66FF66")).Bold(true)
+	labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#00AA00"))
+	errStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FF4444"))
+	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#00AA00"))
+
+	var b strings.Builder
+
+	// Title, with the filter in place of a separate filter line
+	if v.filter.Status != nil {
+		b.WriteString(titleStyle.Render("═══ HOUSEHOLDS: ACTIVE ═══"))
+	} else {
+		b.WriteString(titleStyle.Render("═══ HOUSEHOLDS: ALL ═══"))
+	}
+	b.WriteString("\n\n")
+
+	if v.page.Sort.IsSet() {
+		b.WriteString(labelStyle.Render("Sort: " + v.page.Sort.String()))
+		b.WriteString("\n\n")
+	}
+
+	if v.err != nil {
+		b.WriteString(errStyle.Render("Error: " + v.err.Error()))
+		b.WriteString("\n\n")
+	}
+
+	if v.loader.Loading() {
+		b.WriteString(labelStyle.Render(v.loader.Spinner() + " Loading..."))
+		b.WriteString("\n")
+	}
+	switch {
+	case !v.table.Empty():
+		b.WriteString(v.table.RenderResponsive(width))
+	case !v.loader.Loading():
+		b.WriteString(labelStyle.Render("No households found."))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	if width < 60 {
+		b.WriteString(helpStyle.Render("↑↓:Nav  f:Filter  Tab:Census"))
+	} else {
+		b.WriteString(helpStyle.Render("Up/Down:Select  f:Active/All  o/O:Sort  PgUp/Dn:Page  Tab:Census"))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,329 @@
+package population
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/tui/components"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// Intake wizard steps.
+const (
+	intakeStepIdentity = iota
+	intakeStepQuarantine
+	intakeStepReview
+)
+
+// intakeStepTitles name the wizard's steps.
+var intakeStepTitles = []string{"Identity", "Quarantine", "Review"}
+
+// IntakeData is the admission entered in the intake wizard.
+type IntakeData struct {
+	Surname        string
+	GivenNames     string
+	DateOfBirth    time.Time
+	Sex            models.Sex
+	BloodType      models.BloodType
+	QuarantineDays int
+	Notes          string
+}
+
+// IntakeWizard walks the operator through admitting an outsider: who they
+// are, the quarantine they serve, and a review of the screenings that will
+// be scheduled before they are admitted.
+type IntakeWizard struct {
+	step  int
+	today time.Time
+
+	// Identity
+	surname    *components.Input
+	givenNames *components.Input
+	dobYear    *components.Input
+	dobMonth   *components.Input
+	dobDay     *components.Input
+	sex        *components.Select
+	bloodType  *components.Select
+
+	// Quarantine
+	days  *components.Input
+	notes *components.Input
+
+	focusIndex int
+	submitted  bool
+	cancelled  bool
+	err        string
+}
+
+// NewIntakeWizard creates an intake wizard for an admission on today's
+// vault date.
+func NewIntakeWizard(today time.Time) *IntakeWizard {
+	w := &IntakeWizard{
+		today:      today,
+		surname:    components.NewInput("Surname").SetRequired(true).SetWidth(25),
+		givenNames: components.NewInput("Given Names").SetRequired(true).SetWidth(25),
+		dobYear:    components.NewInput("Birth Year").SetRequired(true).SetWidth(6).SetMaxLength(4).SetPlaceholder("YYYY"),
+		dobMonth:   components.NewInput("Month").SetRequired(true).SetWidth(4).SetMaxLength(2).SetPlaceholder("MM"),
+		dobDay:     components.NewInput("Day").SetRequired(true).SetWidth(4).SetMaxLength(2).SetPlaceholder("DD"),
+		sex:        components.NewSelect("Sex", []string{"M", "F"}),
+		bloodType:  components.NewSelect("Blood Type", []string{"A+", "A-", "B+", "B-", "AB+", "AB-", "O+", "O-", "-"}),
+		days:       components.NewInput("Days").SetRequired(true).SetWidth(4).SetMaxLength(3).SetValue(strconv.Itoa(models.MinIntakeQuarantineDays)),
+		notes:      components.NewInput("Notes").SetWidth(40).SetPlaceholder("Where they were found, who vouches for them"),
+	}
+	w.fields()[0].Focus(true)
+	return w
+}
+
+// fields returns the fields of the current step; the review has none.
+func (w *IntakeWizard) fields() []components.FormField {
+	switch w.step {
+	case intakeStepIdentity:
+		return []components.FormField{w.surname, w.givenNames, w.dobYear, w.dobMonth, w.dobDay, w.sex, w.bloodType}
+	case intakeStepQuarantine:
+		return []components.FormField{w.days, w.notes}
+	default:
+		return nil
+	}
+}
+
+// HandleKey handles key input: Tab and Enter move through a step's fields
+// and on to the next step, Esc goes back a step or cancels on the first,
+// and Enter or Ctrl+S on the review admits.
+func (w *IntakeWizard) HandleKey(key string) {
+	fields := w.fields()
+	switch key {
+	case "esc":
+		w.back()
+	case "ctrl+s":
+		if w.step == intakeStepReview {
+			w.submitted = true
+		} else {
+			w.next()
+		}
+	case "enter":
+		if w.step == intakeStepReview {
+			w.submitted = true
+		} else if w.focusIndex == len(fields)-1 {
+			w.next()
+		} else {
+			w.moveFocus(1)
+		}
+	case "tab", "down":
+		w.moveFocus(1)
+	case "shift+tab", "up":
+		w.moveFocus(-1)
+	default:
+		if len(fields) > 0 {
+			fields[w.focusIndex].HandleKey(key)
+		}
+	}
+}
+
+func (w *IntakeWizard) moveFocus(delta int) {
+	fields := w.fields()
+	if len(fields) == 0 {
+		return
+	}
+	fields[w.focusIndex].Focus(false)
+	w.focusIndex = (w.focusIndex + delta + len(fields)) % len(fields)
+	fields[w.focusIndex].Focus(true)
+}
+
+// next validates the current step and moves to the next.
+func (w *IntakeWizard) next() {
+	if err := w.validateStep(); err != "" {
+		w.err = err
+		return
+	}
+	w.err = ""
+	w.setStep(w.step + 1)
+}
+
+// back returns to the previous step, or cancels on the first.
+func (w *IntakeWizard) back() {
+	w.err = ""
+	if w.step == intakeStepIdentity {
+		w.cancelled = true
+		return
+	}
+	w.setStep(w.step - 1)
+}
+
+func (w *IntakeWizard) setStep(step int) {
+	if fields := w.fields(); len(fields) > 0 {
+		fields[w.focusIndex].Focus(false)
+	}
+	w.step = step
+	w.focusIndex = 0
+	if fields := w.fields(); len(fields) > 0 {
+		fields[0].Focus(true)
+	}
+}
+
+// validateStep checks the current step's fields, returning a message for
+// the first problem found.
+func (w *IntakeWizard) validateStep() string {
+	switch w.step {
+	case intakeStepIdentity:
+		surnameOK, namesOK := w.surname.Validate(), w.givenNames.Validate()
+		if !surnameOK || !namesOK {
+			return "Please fill in all required fields"
+		}
+		if _, err := w.dateOfBirth(); err != nil {
+			return "Invalid date of birth"
+		}
+	case intakeStepQuarantine:
+		days, err := strconv.Atoi(w.days.Value())
+		if err != nil || days < models.MinIntakeQuarantineDays {
+			return fmt.Sprintf("Quarantine must be at least %d days", models.MinIntakeQuarantineDays)
+		}
+	}
+	return ""
+}
+
+func (w *IntakeWizard) dateOfBirth() (time.Time, error) {
+	return time.Parse("2006-01-02", fmt.Sprintf("%s-%s-%s", w.dobYear.Value(), w.dobMonth.Value(), w.dobDay.Value()))
+}
+
+// IsSubmitted returns true if the admission was confirmed on the review.
+func (w *IntakeWizard) IsSubmitted() bool {
+	return w.submitted
+}
+
+// IsCancelled returns true if the wizard was cancelled.
+func (w *IntakeWizard) IsCancelled() bool {
+	return w.cancelled
+}
+
+// SetError shows an error from admitting, returning to the review.
+func (w *IntakeWizard) SetError(err string) {
+	w.err = err
+	w.submitted = false
+}
+
+// GetData returns the admission entered. Call it once submitted.
+func (w *IntakeWizard) GetData() IntakeData {
+	dob, _ := w.dateOfBirth()
+	days, _ := strconv.Atoi(w.days.Value())
+	sex := models.SexMale
+	if w.sex.SelectedIndex() == 1 {
+		sex = models.SexFemale
+	}
+	bloodType := models.BloodType(w.bloodType.Value())
+	if bloodType == "-" {
+		bloodType = ""
+	}
+	return IntakeData{
+		Surname:        w.surname.Value(),
+		GivenNames:     w.givenNames.Value(),
+		DateOfBirth:    dob,
+		Sex:            sex,
+		BloodType:      bloodType,
+		QuarantineDays: days,
+		Notes:          w.notes.Value(),
+	}
+}
+
+// RenderResponsive renders the wizard's current step adapted to the given
+// terminal width.
+func (w *IntakeWizard) RenderResponsive(width int) string {
+	titleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#66FF66")).Bold(true)
+	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#00AA00"))
+	errStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FF4444"))
+	stepStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#00AA00"))
+	currentStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#66FF66")).Bold(true)
+
+	narrow := width > 0 && width < 60
+	labelWidth := 16
+	if narrow {
+		labelWidth = 10
+	}
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("═══ ADMISSION INTAKE ═══"))
+	b.WriteString("\n")
+	steps := make([]string, len(intakeStepTitles))
+	for i, title := range intakeStepTitles {
+		label := fmt.Sprintf("%d %s", i+1, title)
+		if i == w.step {
+			steps[i] = currentStyle.Render("[" + label + "]")
+		} else {
+			steps[i] = stepStyle.Render(" " + label + " ")
+		}
+	}
+	b.WriteString(strings.Join(steps, stepStyle.Render(" › ")))
+	b.WriteString("\n\n")
+
+	switch w.step {
+	case intakeStepIdentity:
+		b.WriteString(w.surname.RenderWithLabelWidth(labelWidth))
+		b.WriteString("\n")
+		b.WriteString(w.givenNames.RenderWithLabelWidth(labelWidth))
+		b.WriteString("\n\n")
+		dobLabel := lipgloss.NewStyle().Foreground(lipgloss.Color("#00AA00")).Width(labelWidth)
+		if narrow {
+			b.WriteString(dobLabel.Render("DOB:"))
+		} else {
+			b.WriteString(dobLabel.Render("Date of Birth:"))
+		}
+		b.WriteString(" ")
+		b.WriteString(w.dobYear.RenderWithLabelWidth(0))
+		b.WriteString(" - ")
+		b.WriteString(w.dobMonth.RenderWithLabelWidth(0))
+		b.WriteString(" - ")
+		b.WriteString(w.dobDay.RenderWithLabelWidth(0))
+		b.WriteString("\n\n")
+		b.WriteString(w.sex.RenderWithLabelWidth(labelWidth))
+		b.WriteString("\n")
+		b.WriteString(w.bloodType.RenderWithLabelWidth(labelWidth))
+		b.WriteString("\n")
+
+	case intakeStepQuarantine:
+		b.WriteString(stepStyle.Render(fmt.Sprintf("Admitted outsiders serve at least %d days in quarantine.", models.MinIntakeQuarantineDays)))
+		b.WriteString("\n\n")
+		b.WriteString(w.days.RenderWithLabelWidth(labelWidth))
+		b.WriteString("\n")
+		b.WriteString(w.notes.RenderWithLabelWidth(labelWidth))
+		b.WriteString("\n")
+
+	case intakeStepReview:
+		data := w.GetData()
+		end := w.today.AddDate(0, 0, data.QuarantineDays-1)
+		blood := string(data.BloodType)
+		if blood == "" {
+			blood = "unknown"
+		}
+		fmt.Fprintf(&b, "%s, %s  born %s  %s  blood %s\n", data.Surname, data.GivenNames,
+			util.FormatDate(data.DateOfBirth), data.Sex, blood)
+		fmt.Fprintf(&b, "Enters as ADMITTED, in QUARANTINE %s to %s (%d days)\n\n",
+			util.FormatDate(w.today), util.FormatDate(end), data.QuarantineDays)
+		b.WriteString(stepStyle.Render("Screenings to be scheduled:"))
+		b.WriteString("\n")
+		for _, screening := range models.IntakeScreenings {
+			fmt.Fprintf(&b, "  %-20s due %s\n", screening, util.FormatDate(screening.DueDate(w.today, end)))
+		}
+		b.WriteString("\n")
+		b.WriteString(stepStyle.Render("A medical officer clears the intake once every screening has passed."))
+		b.WriteString("\n")
+	}
+
+	if w.err != "" {
+		b.WriteString("\n")
+		b.WriteString(errStyle.Render("Error: " + w.err))
+	}
+
+	b.WriteString("\n\n")
+	switch {
+	case w.step == intakeStepReview:
+		b.WriteString(helpStyle.Render("Enter:Admit  Esc:Back"))
+	case narrow:
+		b.WriteString(helpStyle.Render("Tab:Next  Enter:Continue  Esc:Back"))
+	default:
+		b.WriteString(helpStyle.Render("Tab/Down:Next  Shift+Tab/Up:Prev  Enter:Continue  Esc:Back"))
+	}
+	return b.String()
+}
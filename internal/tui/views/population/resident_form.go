@@ -96,7 +96,16 @@ func (f *ResidentForm) SetResident(r *models.Resident) {
 		f.sex.SetSelected(1)
 	}
 
-	// Set blood type
+	// Set blood type; one redacted for the operator can only be kept
+	if r.BloodType == models.BloodType(models.Redacted) {
+		redacted := components.NewSelect("Blood Type", []string{models.Redacted})
+		for i, field := range f.fields {
+			if field == components.FormField(f.bloodType) {
+				f.fields[i] = redacted
+			}
+		}
+		f.bloodType = redacted
+	}
 	bloodTypes := []string{"A+", "A-", "B+", "B-", "AB+", "AB-", "O+", "O-"}
 	for i, bt := range bloodTypes {
 		if bt == string(r.BloodType) {
@@ -118,6 +127,18 @@ func (f *ResidentForm) SetResident(r *models.Resident) {
 	f.notes.SetValue(r.Notes)
 }
 
+// SetEntryType preselects the entry type of a new resident, e.g. VAULT_BORN
+// for a birth.
+func (f *ResidentForm) SetEntryType(entry models.EntryType) {
+	entryTypes := []string{"ORIGINAL", "VAULT_BORN", "ADMITTED"}
+	for i, et := range entryTypes {
+		if et == string(entry) {
+			f.entryType.SetSelected(i)
+			break
+		}
+	}
+}
+
 // HandleKey handles key input.
 func (f *ResidentForm) HandleKey(key string) {
 	switch key {
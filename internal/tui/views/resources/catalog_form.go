@@ -0,0 +1,421 @@
+package resources
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/vtuos/vtuos/internal/models"
+	"github.com/vtuos/vtuos/internal/tui/components"
+)
+
+// catalogForm is the field navigation and state shared by the category and
+// item forms.
+type catalogForm struct {
+	code       *components.Input // Nil when editing: codes are fixed
+	fields     []components.FormField
+	focusIndex int
+	submitted  bool
+	cancelled  bool
+	err        string
+}
+
+// handleKey moves between fields, submits with submit or cancels.
+func (f *catalogForm) handleKey(key string, submit func()) {
+	switch key {
+	case "tab", "down":
+		f.focus(f.focusIndex + 1)
+	case "shift+tab", "up":
+		f.focus(f.focusIndex - 1)
+	case "ctrl+s":
+		submit()
+	case "esc":
+		f.cancelled = true
+	case "enter":
+		if f.focusIndex == len(f.fields)-1 {
+			submit()
+		} else {
+			f.focus(f.focusIndex + 1)
+		}
+	default:
+		f.fields[f.focusIndex].HandleKey(key)
+	}
+}
+
+// focus moves focus to field i, wrapping around.
+func (f *catalogForm) focus(i int) {
+	f.fields[f.focusIndex].Focus(false)
+	f.focusIndex = (i + len(f.fields)) % len(f.fields)
+	f.fields[f.focusIndex].Focus(true)
+}
+
+// IsSubmitted returns true if the form was submitted.
+func (f *catalogForm) IsSubmitted() bool {
+	return f.submitted
+}
+
+// IsCancelled returns true if the form was cancelled.
+func (f *catalogForm) IsCancelled() bool {
+	return f.cancelled
+}
+
+// SetError shows an error from saving, keeping the form open.
+func (f *catalogForm) SetError(err string) {
+	f.err = err
+	f.submitted = false
+}
+
+// SetCodeError shows an error from saving against the code field, e.g. a
+// code already in use, and focuses it.
+func (f *catalogForm) SetCodeError(err string) {
+	if f.code == nil {
+		f.SetError(err)
+		return
+	}
+	f.code.SetError(err)
+	f.err = ""
+	f.submitted = false
+	for i, field := range f.fields {
+		if field == components.FormField(f.code) {
+			f.focus(i)
+		}
+	}
+}
+
+// codeValue returns the code entered, upper-cased.
+func (f *catalogForm) codeValue() string {
+	if f.code == nil {
+		return ""
+	}
+	return strings.ToUpper(strings.TrimSpace(f.code.Value()))
+}
+
+// render renders the form's title, fields in order, error and help.
+func (f *catalogForm) render(title string, width int) string {
+	titleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#66FF66")).Bold(true)
+	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#00AA00"))
+	errStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FF4444"))
+
+	labelWidth := 16
+	if width > 0 && width < 60 {
+		labelWidth = 10
+	}
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("═══ " + title + " ═══"))
+	b.WriteString("\n\n")
+	for _, field := range f.fields {
+		b.WriteString(field.RenderWithLabelWidth(labelWidth))
+		b.WriteString("\n")
+	}
+	if f.err != "" {
+		b.WriteString("\n")
+		b.WriteString(errStyle.Render("Error: " + f.err))
+	}
+
+	b.WriteString("\n\n")
+	if width > 0 && width < 60 {
+		b.WriteString(helpStyle.Render("Tab:Next  Ctrl+S:Save  Esc:Cancel"))
+	} else {
+		b.WriteString(helpStyle.Render("Tab/Down:Next  Shift+Tab/Up:Prev  ←/→:Choose  Ctrl+S:Save  Esc:Cancel"))
+	}
+	return b.String()
+}
+
+// yesNo are the options of a yes-or-no select.
+var yesNo = []string{"NO", "YES"}
+
+// newYesNo returns a yes-or-no select set to on.
+func newYesNo(label string, on bool) *components.Select {
+	s := components.NewSelect(label, yesNo)
+	if on {
+		s.SetSelected(1)
+	}
+	return s
+}
+
+// CategoryForm is a form for creating or editing a resource category.
+type CategoryForm struct {
+	catalogForm
+	category *models.ResourceCategory // Category edited, nil for a new one
+
+	name        *components.Input
+	unit        *components.Input
+	description *components.Input
+	consumable  *components.Select
+	critical    *components.Select
+}
+
+// NewCategoryForm creates a form for editing cat, or for a new category
+// when cat is nil.
+func NewCategoryForm(cat *models.ResourceCategory) *CategoryForm {
+	f := &CategoryForm{
+		category:    cat,
+		name:        components.NewInput("Name").SetRequired(true).SetWidth(30),
+		unit:        components.NewInput("Unit").SetRequired(true).SetWidth(10).SetMaxLength(20).SetPlaceholder("kg"),
+		description: components.NewInput("Description").SetWidth(40),
+		consumable:  newYesNo("Consumable", cat == nil || cat.IsConsumable),
+		critical:    newYesNo("Critical", cat != nil && cat.IsCritical),
+	}
+	if cat == nil {
+		f.code = components.NewInput("Code").SetRequired(true).SetWidth(20).SetMaxLength(20).SetPlaceholder("FOOD")
+		f.fields = append(f.fields, f.code)
+	} else {
+		f.name.SetValue(cat.Name)
+		f.unit.SetValue(cat.UnitOfMeasure)
+		f.description.SetValue(cat.Description)
+	}
+	f.fields = append(f.fields, f.name, f.unit, f.description, f.consumable, f.critical)
+	f.fields[0].Focus(true)
+	return f
+}
+
+// HandleKey handles key input.
+func (f *CategoryForm) HandleKey(key string) {
+	f.handleKey(key, f.submit)
+}
+
+func (f *CategoryForm) submit() {
+	f.err = ""
+	valid := f.name.Validate()
+	valid = f.unit.Validate() && valid
+	if f.code != nil {
+		valid = f.code.Validate() && valid
+	}
+	if !valid {
+		f.err = "Please fill in all required fields"
+		return
+	}
+	f.submitted = true
+}
+
+// GetData returns the category entered. An edited category keeps its ID
+// and code.
+func (f *CategoryForm) GetData() *models.ResourceCategory {
+	cat := &models.ResourceCategory{
+		Code:          f.codeValue(),
+		Name:          strings.TrimSpace(f.name.Value()),
+		Description:   strings.TrimSpace(f.description.Value()),
+		UnitOfMeasure: strings.TrimSpace(f.unit.Value()),
+		IsConsumable:  f.consumable.SelectedIndex() == 1,
+		IsCritical:    f.critical.SelectedIndex() == 1,
+	}
+	if f.category != nil {
+		cat.ID = f.category.ID
+		cat.Code = f.category.Code
+	}
+	return cat
+}
+
+// RenderResponsive renders the form adapted to the given terminal width.
+func (f *CategoryForm) RenderResponsive(width int) string {
+	title := "NEW CATEGORY"
+	if f.category != nil {
+		title = "EDIT CATEGORY " + f.category.Code
+	}
+	return f.render(title, width)
+}
+
+// ItemForm is a form for creating or editing a resource item.
+type ItemForm struct {
+	catalogForm
+	item       *models.ResourceItem // Item edited, nil for a new one
+	categories []*models.ResourceCategory
+
+	category    *components.Select
+	name        *components.Input
+	unit        *components.Input
+	description *components.Input
+	calories    *components.Input
+	nutrients   *components.Input
+	shelfLife   *components.Input
+	storage     *components.Input
+	producible  *components.Select
+	rate        *components.Input
+	minStock    *components.Input
+	targetStock *components.Input
+}
+
+// NewItemForm creates a form for editing item, or for a new item when item
+// is nil, in one of categories.
+func NewItemForm(categories []*models.ResourceCategory, item *models.ResourceItem) *ItemForm {
+	codes := make([]string, len(categories))
+	for i, cat := range categories {
+		codes[i] = cat.Code
+	}
+	f := &ItemForm{
+		item:        item,
+		categories:  categories,
+		category:    components.NewSelect("Category", codes),
+		name:        components.NewInput("Name").SetRequired(true).SetWidth(30),
+		unit:        components.NewInput("Unit").SetWidth(10).SetMaxLength(20).SetPlaceholder("category's"),
+		description: components.NewInput("Description").SetWidth(40),
+		calories:    components.NewInput("Calories/Unit").SetWidth(10).SetMaxLength(10),
+		nutrients:   components.NewInput("Nutrients").SetWidth(20).SetMaxLength(30).SetPlaceholder("P/C/F g / vit %"),
+		shelfLife:   components.NewInput("Shelf Life").SetWidth(10).SetMaxLength(6).SetPlaceholder("days"),
+		storage:     components.NewInput("Storage").SetWidth(30).SetPlaceholder("e.g. cool, dry"),
+		producible:  newYesNo("Producible", item != nil && item.IsProducible),
+		rate:        components.NewInput("Rate/Day").SetWidth(10).SetMaxLength(10),
+		minStock:    components.NewInput("Min Stock").SetWidth(10).SetMaxLength(10),
+		targetStock: components.NewInput("Target Stock").SetWidth(10).SetMaxLength(10).SetPlaceholder("minimum"),
+	}
+	if item == nil {
+		f.code = components.NewInput("Item Code").SetRequired(true).SetWidth(20).SetMaxLength(30).SetPlaceholder("FOOD-PROTEIN-001")
+		f.fields = append(f.fields, f.code)
+	} else {
+		for i, cat := range categories {
+			if cat.ID == item.CategoryID {
+				f.category.SetSelected(i)
+			}
+		}
+		f.name.SetValue(item.Name)
+		f.unit.SetValue(item.UnitOfMeasure)
+		f.description.SetValue(item.Description)
+		f.storage.SetValue(item.StorageRequirements)
+		if item.CaloriesPerUnit != nil {
+			f.calories.SetValue(strconv.FormatFloat(*item.CaloriesPerUnit, 'f', -1, 64))
+		}
+		if item.Nutrients != nil {
+			f.nutrients.SetValue(item.Nutrients.String())
+		}
+		if item.ShelfLifeDays != nil {
+			f.shelfLife.SetValue(strconv.Itoa(*item.ShelfLifeDays))
+		}
+		for input, value := range map[*components.Input]*float64{
+			f.rate:        item.ProductionRatePerDay,
+			f.minStock:    item.MinStock,
+			f.targetStock: item.TargetStock,
+		} {
+			if value != nil {
+				input.SetValue(strconv.FormatFloat(*value, 'f', -1, 64))
+			}
+		}
+	}
+	f.fields = append(f.fields, f.category, f.name, f.unit, f.description,
+		f.calories, f.nutrients, f.shelfLife, f.storage, f.producible, f.rate, f.minStock, f.targetStock)
+	f.fields[0].Focus(true)
+	return f
+}
+
+// HandleKey handles key input.
+func (f *ItemForm) HandleKey(key string) {
+	f.handleKey(key, f.submit)
+}
+
+func (f *ItemForm) submit() {
+	f.err = ""
+	valid := f.name.Validate()
+	if f.code != nil {
+		valid = f.code.Validate() && valid
+	}
+	if !valid {
+		f.err = "Please fill in all required fields"
+		return
+	}
+	for _, number := range []*components.Input{f.calories, f.rate, f.minStock, f.targetStock} {
+		if _, err := optionalFloat(number); err != nil {
+			number.SetError("Not a number")
+			valid = false
+		} else {
+			number.SetError("")
+		}
+	}
+	if _, err := optionalNutrients(f.nutrients); err != nil {
+		f.nutrients.SetError("e.g. 350/150/160/100")
+		valid = false
+	} else {
+		f.nutrients.SetError("")
+	}
+	if _, err := optionalInt(f.shelfLife); err != nil {
+		f.shelfLife.SetError("Whole days")
+		valid = false
+	} else {
+		f.shelfLife.SetError("")
+	}
+	if !valid {
+		f.err = "Please correct the highlighted fields"
+		return
+	}
+	f.submitted = true
+}
+
+// GetData returns the item entered. Call it once submitted. An edited item
+// keeps its ID and code; an item without a unit takes its category's.
+func (f *ItemForm) GetData() *models.ResourceItem {
+	item := &models.ResourceItem{
+		ItemCode:            f.codeValue(),
+		Name:                strings.TrimSpace(f.name.Value()),
+		Description:         strings.TrimSpace(f.description.Value()),
+		UnitOfMeasure:       strings.TrimSpace(f.unit.Value()),
+		StorageRequirements: strings.TrimSpace(f.storage.Value()),
+		IsProducible:        f.producible.SelectedIndex() == 1,
+	}
+	if idx := f.category.SelectedIndex(); idx < len(f.categories) {
+		cat := f.categories[idx]
+		item.CategoryID = cat.ID
+		if item.UnitOfMeasure == "" {
+			item.UnitOfMeasure = cat.UnitOfMeasure
+		}
+	}
+	item.CaloriesPerUnit, _ = optionalFloat(f.calories)
+	item.Nutrients, _ = optionalNutrients(f.nutrients)
+	item.ShelfLifeDays, _ = optionalInt(f.shelfLife)
+	item.ProductionRatePerDay, _ = optionalFloat(f.rate)
+	item.MinStock, _ = optionalFloat(f.minStock)
+	item.TargetStock, _ = optionalFloat(f.targetStock)
+	if f.item != nil {
+		item.ID = f.item.ID
+		item.ItemCode = f.item.ItemCode
+	}
+	return item
+}
+
+// RenderResponsive renders the form adapted to the given terminal width.
+func (f *ItemForm) RenderResponsive(width int) string {
+	title := "NEW ITEM"
+	if f.item != nil {
+		title = "EDIT ITEM " + f.item.ItemCode
+	}
+	return f.render(title, width)
+}
+
+// optionalFloat parses an input holding a number, nil when it is empty.
+func optionalFloat(input *components.Input) (*float64, error) {
+	value := strings.TrimSpace(input.Value())
+	if value == "" {
+		return nil, nil
+	}
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid number %q", value)
+	}
+	return &n, nil
+}
+
+// optionalNutrients parses an input holding nutrients per unit, nil when it
+// is empty.
+func optionalNutrients(input *components.Input) (*models.Nutrients, error) {
+	value := strings.TrimSpace(input.Value())
+	if value == "" {
+		return nil, nil
+	}
+	n, err := models.ParseNutrients(value)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// optionalInt parses an input holding a whole number, nil when it is empty.
+func optionalInt(input *components.Input) (*int, error) {
+	value := strings.TrimSpace(input.Value())
+	if value == "" {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid number %q", value)
+	}
+	return &n, nil
+}
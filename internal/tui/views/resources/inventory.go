@@ -11,8 +11,13 @@ import (
 	"github.com/vtuos/vtuos/internal/models"
 	"github.com/vtuos/vtuos/internal/services/resources"
 	"github.com/vtuos/vtuos/internal/tui/components"
+	"github.com/vtuos/vtuos/internal/util"
 )
 
+// maxQualityTests is how many of a lot's quality tests the detail view
+// lists.
+const maxQualityTests = 5
+
 // InventoryView displays the resource inventory list.
 type InventoryView struct {
 	service    *resources.Service
@@ -21,10 +26,16 @@ type InventoryView struct {
 	categories []*models.ResourceCategory
 	page       models.Pagination
 	filter     models.StockFilter
-	loading    bool
+	loader     components.Loader
 	err        error
 	search     string
 	vaultTime  time.Time
+	split      bool // Shown beside the detail pane
+
+	// Quality tests of the lot testsOf, most recent first, shown in the
+	// detail view
+	qualityTests []*models.QualityTest
+	testsOf      string
 
 	// Currently selected category (nil = all)
 	selectedCategory *string
@@ -54,34 +65,64 @@ func NewInventoryView(service *resources.Service) *InventoryView {
 	}
 }
 
-// Load fetches stocks from the database.
-func (v *InventoryView) Load(ctx context.Context) error {
-	v.loading = true
-	v.err = nil
+// InventoryPage is a fetched page of the inventory.
+type InventoryPage struct {
+	Stocks     *models.StockList
+	Categories []*models.ResourceCategory // Only fetched until first loaded
+}
 
-	// Load categories for display
-	if v.categories == nil {
-		cats, err := v.service.ListCategories(ctx)
-		if err == nil {
-			v.categories = cats
-		}
-	}
+// Loader returns the loader tracking the view's background loads.
+func (v *InventoryView) Loader() *components.Loader {
+	return &v.loader
+}
+
+// SetPlain turns screen reader mode on or off for the view's table and
+// loading spinner.
+func (v *InventoryView) SetPlain(on bool) {
+	v.table.SetPlain(on)
+	v.loader.SetPlain(on)
+}
 
+// Fetch returns a query for the current page that reads no view state, so
+// it can run in the background while the view changes.
+func (v *InventoryView) Fetch() func(context.Context) (*InventoryPage, error) {
 	// Apply category filter if selected
-	filter := v.filter
+	filter, page := v.filter, v.page
 	if v.selectedCategory != nil {
 		filter.CategoryID = *v.selectedCategory
 	}
+	needCategories := v.categories == nil
+
+	return func(ctx context.Context) (*InventoryPage, error) {
+		result := &InventoryPage{}
+		// Load categories for display
+		if needCategories {
+			if cats, err := v.service.ListCategories(ctx); err == nil {
+				result.Categories = cats
+			}
+		}
+
+		stocks, err := v.service.ListStocks(ctx, filter, page)
+		if err != nil {
+			return nil, err
+		}
+		result.Stocks = stocks
+		return result, nil
+	}
+}
 
-	result, err := v.service.ListStocks(ctx, filter, v.page)
+// SetResult shows a fetched page, or the error fetching it.
+func (v *InventoryView) SetResult(page *InventoryPage, err error) {
+	v.err = err
 	if err != nil {
-		v.loading = false
-		v.err = err
-		return err
+		return
 	}
 
+	if page.Categories != nil {
+		v.categories = page.Categories
+	}
+	result := page.Stocks
 	v.stocks = result.Stocks
-	v.loading = false
 
 	// Convert to table rows
 	rows := make([][]string, len(v.stocks))
@@ -118,7 +159,7 @@ func (v *InventoryView) Load(ctx context.Context) error {
 			} else if days < 30 {
 				expires = fmt.Sprintf("%dd", days)
 			} else {
-				expires = s.ExpirationDate.Format("2006-01-02")
+				expires = util.FormatDate(*s.ExpirationDate)
 			}
 		}
 
@@ -135,8 +176,6 @@ func (v *InventoryView) Load(ctx context.Context) error {
 
 	v.table.SetRows(rows)
 	v.table.SetPagination(result.Page, result.TotalPages, result.Total)
-
-	return nil
 }
 
 // SetVaultTime sets the current vault time.
@@ -144,12 +183,46 @@ func (v *InventoryView) SetVaultTime(t time.Time) {
 	v.vaultTime = t
 }
 
+// SetQualityTests sets the quality tests shown in the detail view of the
+// stock with the given ID.
+func (v *InventoryView) SetQualityTests(stockID string, tests []*models.QualityTest) {
+	v.testsOf = stockID
+	v.qualityTests = tests
+}
+
+// HasQualityTests reports whether the quality tests of the stock with the
+// given ID are loaded.
+func (v *InventoryView) HasQualityTests(stockID string) bool {
+	return v.testsOf == stockID
+}
+
+// SetSplit sets whether the list is shown beside the detail pane, where Tab
+// moves focus between them.
+func (v *InventoryView) SetSplit(split bool) {
+	v.split = split
+}
+
 // SetCategoryFilter sets the category filter.
 func (v *InventoryView) SetCategoryFilter(categoryID *string) {
 	v.selectedCategory = categoryID
 	v.page.Page = 1
 }
 
+// CycleSort moves to the next sort column, returning to the default order
+// after the last.
+func (v *InventoryView) CycleSort() {
+	v.page.Sort = v.page.Sort.Next(models.StockSortColumns)
+	v.page.Page = 1
+}
+
+// ReverseSort flips the direction of the current sort column.
+func (v *InventoryView) ReverseSort() {
+	if v.page.Sort.IsSet() {
+		v.page.Sort = v.page.Sort.Reversed()
+		v.page.Page = 1
+	}
+}
+
 // SetVisibleRows sets the number of visible table rows.
 func (v *InventoryView) SetVisibleRows(n int) {
 	v.table.SetVisibleRows(n)
@@ -186,6 +259,38 @@ func (v *InventoryView) SelectedStock() *models.ResourceStock {
 	return nil
 }
 
+// ToggleMark marks the selected stock for a batch action, or unmarks it.
+func (v *InventoryView) ToggleMark() {
+	v.table.ToggleMark()
+}
+
+// MarkAll marks every stock on the page, or unmarks them all.
+func (v *InventoryView) MarkAll() {
+	v.table.MarkAll()
+}
+
+// ClearMarks unmarks every stock.
+func (v *InventoryView) ClearMarks() {
+	v.table.ClearMarks()
+}
+
+// MarkedStocks returns the stocks marked for a batch action.
+func (v *InventoryView) MarkedStocks() []*models.ResourceStock {
+	var marked []*models.ResourceStock
+	for _, i := range v.table.Marked() {
+		if i < len(v.stocks) {
+			marked = append(marked, v.stocks[i])
+		}
+	}
+	return marked
+}
+
+// ReloadCategories fetches the categories again with the next page, e.g.
+// once one is added or edited.
+func (v *InventoryView) ReloadCategories() {
+	v.categories = nil
+}
+
 // GetCategories returns the available categories.
 func (v *InventoryView) GetCategories() []*models.ResourceCategory {
 	return v.categories
@@ -219,30 +324,46 @@ func (v *InventoryView) Render(width, height int) string {
 		b.WriteString("\n\n")
 	}
 
+	if v.page.Sort.IsSet() {
+		b.WriteString(labelStyle.Render("Sort: "))
+		b.WriteString(valueStyle.Render(v.page.Sort.String()))
+		b.WriteString("\n\n")
+	}
+
+	if marked := len(v.table.Marked()); marked > 0 {
+		b.WriteString(labelStyle.Render("Marked: "))
+		b.WriteString(valueStyle.Render(fmt.Sprintf("%d", marked)))
+		b.WriteString("\n\n")
+	}
+
 	// Error display
 	if v.err != nil {
 		b.WriteString(errStyle.Render("Error: " + v.err.Error()))
 		b.WriteString("\n\n")
 	}
 
-	// Loading indicator
-	if v.loading {
-		b.WriteString(labelStyle.Render("Loading..."))
-		b.WriteString("\n")
-	} else if v.table.Empty() {
-		b.WriteString(labelStyle.Render("No inventory found."))
+	// Loading indicator; the previous page stays up until the next arrives
+	if v.loader.Loading() {
+		b.WriteString(labelStyle.Render(v.loader.Spinner() + " Loading..."))
 		b.WriteString("\n")
-	} else {
+	}
+	switch {
+	case !v.table.Empty():
 		// Render table with responsive width
 		b.WriteString(v.table.RenderResponsive(width))
+	case !v.loader.Loading():
+		b.WriteString(labelStyle.Render("No inventory found."))
+		b.WriteString("\n")
 	}
 
 	// Help - adapt to width
 	b.WriteString("\n")
 	if width < 60 {
 		b.WriteString(helpStyle.Render("↑↓:Nav  Enter:View  c:Cat  PgUp/Dn"))
+	} else if v.split {
+		b.WriteString(helpStyle.Render("Up/Down:Select  Tab:Detail  Space/a:Mark  c:Category  o/O:Sort  PgUp/Dn:Page  </>:Resize"))
 	} else {
-		b.WriteString(helpStyle.Render("Up/Down:Select  Enter:Details  c:Category  PgUp/Dn:Page"))
+		b.WriteString(helpStyle.Render("Up/Down:Select  Enter:Details  Space/a:Mark  c:Category  o/O:Sort  PgUp/Dn:Page"))
 	}
 
 	return b.String()
@@ -283,6 +404,9 @@ func (v *InventoryView) RenderDetail(stock *models.ResourceStock, width int) str
 		if stock.Item.CaloriesPerUnit != nil && *stock.Item.CaloriesPerUnit > 0 {
 			b.WriteString(labelStyle.Render("Calories/Unit:") + " " + valueStyle.Render(fmt.Sprintf("%.0f", *stock.Item.CaloriesPerUnit)) + "\n")
 		}
+		if stock.Item.Nutrients != nil {
+			b.WriteString(labelStyle.Render("Nutrients/Unit:") + " " + valueStyle.Render(stock.Item.Nutrients.String()+" (P/C/F g, vit %)") + "\n")
+		}
 	}
 	b.WriteString("\n")
 
@@ -302,10 +426,10 @@ func (v *InventoryView) RenderDetail(stock *models.ResourceStock, width int) str
 	// Dates
 	b.WriteString(sectionStyle.Render("DATES"))
 	b.WriteString("\n")
-	b.WriteString(labelStyle.Render("Received:") + " " + valueStyle.Render(stock.ReceivedDate.Format("2006-01-02")) + "\n")
+	b.WriteString(labelStyle.Render("Received:") + " " + valueStyle.Render(util.FormatDate(stock.ReceivedDate)) + "\n")
 	if stock.ExpirationDate != nil {
 		days := stock.DaysUntilExpiration(v.vaultTime)
-		expStr := stock.ExpirationDate.Format("2006-01-02")
+		expStr := util.FormatDate(*stock.ExpirationDate)
 
 		var daysStr string
 		if days < 0 {
@@ -323,11 +447,37 @@ func (v *InventoryView) RenderDetail(stock *models.ResourceStock, width int) str
 		b.WriteString(labelStyle.Render("Expires:") + " " + valueStyle.Render(expStr) + " (" + daysStr + ")\n")
 	}
 	if stock.LastAuditDate != nil {
-		b.WriteString(labelStyle.Render("Last Audit:") + " " + valueStyle.Render(stock.LastAuditDate.Format("2006-01-02")) + "\n")
+		b.WriteString(labelStyle.Render("Last Audit:") + " " + valueStyle.Render(util.FormatDate(*stock.LastAuditDate)) + "\n")
+	}
+
+	// Quality tests, once loaded for this lot
+	if v.testsOf == stock.ID && len(v.qualityTests) > 0 {
+		b.WriteString("\n")
+		b.WriteString(sectionStyle.Render("QUALITY TESTS"))
+		b.WriteString("\n")
+		for i, test := range v.qualityTests {
+			if i == maxQualityTests {
+				b.WriteString(labelStyle.Render("") + " " + helpStyle.Render(fmt.Sprintf("%d earlier", len(v.qualityTests)-i)) + "\n")
+				break
+			}
+			result := valueStyle.Render(test.Result())
+			if !test.Passed {
+				result = critStyle.Render(test.Result())
+			}
+			value := fmt.Sprintf("%.2f ppm", test.ContaminationLevel)
+			if test.Notes != "" {
+				value += ", " + test.Notes
+			}
+			b.WriteString(labelStyle.Render(util.FormatDay(test.TestedAt)+":") + " " + result + " " + valueStyle.Render(value) + "\n")
+		}
 	}
 
 	b.WriteString("\n")
-	b.WriteString(helpStyle.Render("Esc:Back  a:Adjust  u:Audit"))
+	if v.split {
+		b.WriteString(helpStyle.Render("Tab/Esc:List  t:Quality test  x:Adjust  p:Split  d:Damaged  q:Quarantine"))
+	} else {
+		b.WriteString(helpStyle.Render("Esc:Back  t:Quality test  x:Adjust  p:Split  d:Damaged  q:Quarantine"))
+	}
 
 	return b.String()
 }
@@ -0,0 +1,191 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/vtuos/vtuos/internal/services/facilities"
+	facviews "github.com/vtuos/vtuos/internal/tui/views/facilities"
+	"github.com/vtuos/vtuos/internal/util"
+)
+
+// workOrdersMsg carries the open work orders for the work order screen.
+type workOrdersMsg struct {
+	orders []*facilities.WorkOrder
+	err    error
+}
+
+// workOrderSavedMsg is sent when a work order's lead technician has been
+// saved.
+type workOrderSavedMsg struct {
+	recordID string
+	lead     string // Registry number, "" when unassigned
+	err      error
+}
+
+// openWorkOrders switches to the work order screen of the facilities module.
+func (a *App) openWorkOrders() tea.Cmd {
+	a.currentModule = ModuleWorkOrders
+	return a.loadWorkOrders()
+}
+
+// loadWorkOrders loads the open work orders with their leads and
+// suggested technicians.
+func (a *App) loadWorkOrders() tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := a.reportOperation()
+		defer cancel()
+		orders, err := a.facilitySvc.WorkOrders(ctx)
+		return workOrdersMsg{orders: orders, err: err}
+	}
+}
+
+// handleWorkOrders stores the loaded work orders, keeping the selection in
+// range.
+func (a *App) handleWorkOrders(msg workOrdersMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		a.AddError("Failed to load work orders", msg.err)
+		return a, nil
+	}
+	a.workOrders = msg.orders
+	a.workOrderIndex = max(min(a.workOrderIndex, len(a.workOrders)-1), 0)
+	return a, nil
+}
+
+// handleWorkOrderKeys handles key presses on the work order screen.
+func (a *App) handleWorkOrderKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		if a.workOrderIndex > 0 {
+			a.workOrderIndex--
+		}
+	case "down", "j":
+		if a.workOrderIndex < len(a.workOrders)-1 {
+			a.workOrderIndex++
+		}
+	case "enter":
+		if a.denyReadOnly() || len(a.workOrders) == 0 {
+			return a, nil
+		}
+		a.workOrderForm = facviews.NewWorkOrderForm(a.workOrders[a.workOrderIndex])
+	case "r":
+		return a, a.loadWorkOrders()
+	}
+	return a, nil
+}
+
+// handleWorkOrderFormKeys handles key presses while the work order form is
+// open, saving it once submitted.
+func (a *App) handleWorkOrderFormKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	a.workOrderForm.HandleKey(msg.String())
+	if a.workOrderForm.IsCancelled() {
+		a.workOrderForm = nil
+		return a, nil
+	}
+	if !a.workOrderForm.IsSubmitted() {
+		return a, nil
+	}
+	recordID, lead := a.workOrderForm.RecordID(), a.workOrderForm.GetData()
+	return a, func() tea.Msg {
+		ctx, cancel := a.operation()
+		defer cancel()
+		err := a.facilitySvc.AssignWorkOrder(ctx, recordID, lead)
+		return workOrderSavedMsg{recordID: recordID, lead: lead, err: err}
+	}
+}
+
+// handleWorkOrderSaved closes the form once saved; an error stays on the
+// form to be corrected.
+func (a *App) handleWorkOrderSaved(msg workOrderSavedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		if a.workOrderForm == nil {
+			a.AddError("Failed to assign work order "+msg.recordID, msg.err)
+			return a, nil
+		}
+		a.workOrderForm.SetError(errorMessage(msg.err))
+		return a, nil
+	}
+	a.workOrderForm = nil
+	if msg.lead == "" {
+		a.AddAlert(AlertInfo, "Work order "+msg.recordID+" unassigned")
+	} else {
+		a.AddAlert(AlertInfo, "Work order "+msg.recordID+" led by "+msg.lead)
+	}
+	return a, a.loadWorkOrders()
+}
+
+// renderWorkOrders renders the work order screen: the open work orders with
+// their leads, then the technicians suggested for the selected one.
+func (a *App) renderWorkOrders() string {
+	if a.workOrderForm != nil {
+		return a.workOrderForm.RenderResponsive(a.width)
+	}
+
+	var b strings.Builder
+	b.WriteString(a.theme.Title.Render("═══ WORK ORDERS ═══"))
+	b.WriteString("\n\n")
+
+	if a.workOrders == nil {
+		b.WriteString(a.theme.Muted.Render("  Work orders loading..."))
+		return b.String()
+	}
+	if len(a.workOrders) == 0 {
+		b.WriteString(a.theme.Muted.Render("  No open work orders"))
+		return b.String()
+	}
+	width := a.width - 4
+
+	header := fmt.Sprintf("  %-10s %-16s %-11s %-12s %s", "SCHEDULED", "SYSTEM", "TYPE", "LEAD", "DESCRIPTION")
+	b.WriteString(a.theme.TableHeader.Render(Truncate(header, a.width-2)))
+	b.WriteString("\n")
+	for i, o := range a.workOrders {
+		scheduled, lead := "-", "-"
+		if o.Record.ScheduledDate != nil {
+			scheduled = util.FormatDate(*o.Record.ScheduledDate)
+		}
+		if o.Lead != nil {
+			lead = o.Lead.RegistryNumber
+		}
+		line := fmt.Sprintf("%-10s %-16s %-11s %-12s %s",
+			scheduled, o.System.SystemCode, o.Record.MaintenanceType, lead, o.Record.Description)
+		line = Truncate(line, width)
+		switch {
+		case i == a.workOrderIndex:
+			b.WriteString(a.theme.Selected.Render("> " + line))
+		case o.Lead == nil:
+			b.WriteString("  " + a.theme.Warning.Render(line))
+		default:
+			b.WriteString("  " + a.theme.Base.Render(line))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	o := a.workOrders[a.workOrderIndex]
+	b.WriteString(a.theme.Subtitle.Render(fmt.Sprintf("Suggested for %s (%s)", o.System.SystemCode, o.System.Category)))
+	b.WriteString("\n")
+	if len(o.Suggestions) == 0 {
+		b.WriteString(a.theme.Muted.Render("  No active technician is rated in " + string(o.System.Category)))
+		b.WriteString("\n")
+	}
+	for i, sg := range o.Suggestions {
+		if i == 5 {
+			break
+		}
+		shift := "off shift"
+		if sg.OnShift {
+			shift = "on shift"
+		}
+		r := sg.Technician.Resident
+		line := fmt.Sprintf("  %-12s %-24s skill %d  %d open  %-6s %-9s score %.1f",
+			r.RegistryNumber, Truncate(r.FullName(), 24), sg.Rating, sg.Workload, sg.Technician.Shift, shift, sg.Score)
+		b.WriteString(a.theme.Base.Render(Truncate(line, width)))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(a.theme.Muted.Render("  " + a.theme.Locale.T("↑/↓ select  Enter assign lead  r reload  Esc back")))
+	return b.String()
+}
@@ -0,0 +1,99 @@
+package util
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// Calendar decides how vault dates and times are shown: in the vault's
+// local time zone, with configured layouts, and optionally on the vault
+// calendar, which counts days since the vault was sealed.
+type Calendar struct {
+	Location   *time.Location // Zone times are shown in; nil for UTC
+	DateLayout string         // Go layout of Gregorian dates; empty for DateFormat
+	TimeLayout string         // Go layout of times of day; empty for 15:04:05
+	VaultDays  bool           // Show dates from the seal on as vault days
+	Sealed     time.Time      // Day 0 of the vault calendar
+	DayFormat  string         // Format of a vault day; empty for "Day %d"
+}
+
+// DefaultCalendar shows UTC times and ISO dates on the Gregorian calendar.
+var DefaultCalendar = Calendar{Location: time.UTC, DateLayout: DateFormat, TimeLayout: "15:04:05"}
+
+// calendar is the calendar the Format helpers use.
+var calendar atomic.Pointer[Calendar]
+
+// SetCalendar makes c the calendar every view and report shows dates and
+// times in. It may be called while formatting is under way.
+func SetCalendar(c Calendar) {
+	calendar.Store(&c)
+}
+
+// CurrentCalendar returns the calendar dates and times are shown in.
+func CurrentCalendar() Calendar {
+	if c := calendar.Load(); c != nil {
+		return *c
+	}
+	return DefaultCalendar
+}
+
+// Local returns t in the calendar's time zone.
+func (c Calendar) Local(t time.Time) time.Time {
+	if c.Location == nil {
+		return t.UTC()
+	}
+	return t.In(c.Location)
+}
+
+// VaultDay returns the vault day of a date: the days since the vault was
+// sealed, negative before.
+func (c Calendar) VaultDay(date time.Time) int {
+	return DaysSince(civilDate(c.Sealed), civilDate(date))
+}
+
+// Date formats a date, such as a birth or expiration date. Dates are
+// calendar days rather than instants, so they are not moved to the time
+// zone. On the vault calendar, dates from the seal on read "Day N" and
+// earlier dates stay Gregorian.
+func (c Calendar) Date(date time.Time) string {
+	if c.VaultDays {
+		if day := c.VaultDay(date); day >= 0 {
+			format := c.DayFormat
+			if format == "" {
+				format = "Day %d"
+			}
+			return fmt.Sprintf(format, day)
+		}
+	}
+	layout := c.DateLayout
+	if layout == "" {
+		layout = DateFormat
+	}
+	return date.Format(layout)
+}
+
+// Time formats the time of day of an instant in the calendar's time zone.
+func (c Calendar) Time(t time.Time) string {
+	layout := c.TimeLayout
+	if layout == "" {
+		layout = DefaultCalendar.TimeLayout
+	}
+	return c.Local(t).Format(layout)
+}
+
+// Day formats the date an instant falls on in the calendar's time zone.
+func (c Calendar) Day(t time.Time) string {
+	return c.Date(c.Local(t))
+}
+
+// DateTime formats an instant as its date and time of day in the
+// calendar's time zone.
+func (c Calendar) DateTime(t time.Time) string {
+	return c.Day(t) + " " + c.Time(t)
+}
+
+// civilDate returns midnight UTC of the date t shows.
+func civilDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
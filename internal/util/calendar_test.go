@@ -0,0 +1,64 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCalendarDate(t *testing.T) {
+	sealed := time.Date(2077, 10, 23, 9, 47, 0, 0, time.UTC)
+	vault := Calendar{VaultDays: true, Sealed: sealed}
+
+	tests := []struct {
+		name string
+		cal  Calendar
+		date time.Time
+		want string
+	}{
+		{"default layout", Calendar{}, time.Date(2080, 3, 1, 0, 0, 0, 0, time.UTC), "2080-03-01"},
+		{"custom layout", Calendar{DateLayout: "02 Jan 2006"}, time.Date(2080, 3, 1, 0, 0, 0, 0, time.UTC), "01 Mar 2080"},
+		{"seal day", vault, sealed, "Day 0"},
+		{"vault day", vault, time.Date(2078, 10, 23, 0, 0, 0, 0, time.UTC), "Day 365"},
+		{"before seal stays gregorian", vault, time.Date(2051, 6, 9, 0, 0, 0, 0, time.UTC), "2051-06-09"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cal.Date(tt.date); got != tt.want {
+				t.Errorf("Date = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalendarTimeZone(t *testing.T) {
+	denver, err := time.LoadLocation("America/Denver")
+	if err != nil {
+		t.Skip("no zone database:", err)
+	}
+	cal := Calendar{Location: denver, TimeLayout: "15:04"}
+	at := time.Date(2077, 10, 24, 3, 30, 0, 0, time.UTC)
+
+	if got, want := cal.DateTime(at), "2077-10-23 21:30"; got != want {
+		t.Errorf("DateTime = %q, want %q", got, want)
+	}
+	// Dates are calendar days and keep their day whatever the zone
+	if got, want := cal.Date(at), "2077-10-24"; got != want {
+		t.Errorf("Date = %q, want %q", got, want)
+	}
+	if got, want := cal.Day(at), "2077-10-23"; got != want {
+		t.Errorf("Day = %q, want %q", got, want)
+	}
+}
+
+func TestSetCalendar(t *testing.T) {
+	defer SetCalendar(DefaultCalendar)
+
+	at := time.Date(2077, 10, 25, 12, 0, 0, 0, time.UTC)
+	if got, want := FormatDateTime(at), "2077-10-25 12:00:00"; got != want {
+		t.Errorf("FormatDateTime = %q, want %q", got, want)
+	}
+	SetCalendar(Calendar{VaultDays: true, Sealed: time.Date(2077, 10, 23, 9, 47, 0, 0, time.UTC), TimeLayout: "15:04"})
+	if got, want := FormatDateTime(at), "Day 2 12:00"; got != want {
+		t.Errorf("FormatDateTime = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,61 @@
+package util
+
+import (
+	"context"
+	"time"
+)
+
+// WithTimeout returns a copy of ctx that is cancelled after d, as
+// context.WithTimeout does, or only when ctx is when d is not positive.
+func WithTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if d <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, d)
+}
+
+// operatorKey marks a context with the clearance of the operator it runs for.
+type operatorKey struct{}
+
+// WithOperatorClearance returns a copy of ctx run for a signed-on operator
+// holding clearance, 1 to 10.
+func WithOperatorClearance(ctx context.Context, clearance int) context.Context {
+	return context.WithValue(ctx, operatorKey{}, clearance)
+}
+
+// OperatorClearance returns the clearance of the operator ctx runs for, or
+// 0 when no operator is signed on.
+func OperatorClearance(ctx context.Context) int {
+	clearance, _ := ctx.Value(operatorKey{}).(int)
+	return clearance
+}
+
+// Cleared reports whether the operator ctx runs for holds the required
+// clearance. Every context is cleared for a requirement of 0.
+func Cleared(ctx context.Context, required int) bool {
+	return required <= 0 || OperatorClearance(ctx) >= required
+}
+
+// Countersign is a second operator's confirmation of an operation held for
+// two-person authorization.
+type Countersign struct {
+	RequestedBy string `json:"requested_by"` // Registry number of the operator making the change
+	By          string `json:"by"`           // Registry number of the operator confirming it
+	PIN         string `json:"-"`            // The confirming operator's PIN, never recorded
+	Replayed    bool   `json:"-"`            // Confirmed when recorded; the PIN is not checked again
+}
+
+// countersignKey marks a context with a countersign.
+type countersignKey struct{}
+
+// WithCountersign returns a copy of ctx whose operations are confirmed by
+// the countersign.
+func WithCountersign(ctx context.Context, c Countersign) context.Context {
+	return context.WithValue(ctx, countersignKey{}, c)
+}
+
+// CountersignOf returns the countersign of ctx, if it has one.
+func CountersignOf(ctx context.Context) (Countersign, bool) {
+	c, ok := ctx.Value(countersignKey{}).(Countersign)
+	return c, ok
+}
@@ -0,0 +1,23 @@
+package util
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCleared(t *testing.T) {
+	ctx := context.Background()
+	if !Cleared(ctx, 0) {
+		t.Error("Cleared() = false for no requirement")
+	}
+	if Cleared(ctx, 5) {
+		t.Error("Cleared() = true with no operator signed on")
+	}
+	ctx = WithOperatorClearance(ctx, 5)
+	if got := OperatorClearance(ctx); got != 5 {
+		t.Errorf("OperatorClearance() = %d, want 5", got)
+	}
+	if !Cleared(ctx, 5) || Cleared(ctx, 6) {
+		t.Error("Cleared() does not compare the operator's clearance with the requirement")
+	}
+}
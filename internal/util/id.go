@@ -6,75 +6,138 @@ import (
 	"encoding/binary"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 // IDGenerator provides thread-safe UUIDv7 generation with monotonic timestamps.
+//
+// Identifiers sort by creation time: the first 48 bits are the Unix time in
+// milliseconds and the next 12 a counter within the millisecond, so the
+// canonical strings of a process's identifiers sort in the order they were
+// generated. When the counter runs out, or the system clock steps back, the
+// generator carries on from its last timestamp rather than going back.
 type IDGenerator struct {
 	mu       sync.Mutex
 	lastTime int64
 	counter  uint16
 }
 
-// NewIDGenerator creates a new ID generator.
+// maxIDCounter is the largest counter held in the 12 bits after the version.
+const maxIDCounter = 0x0FFF
+
+var generator = &IDGenerator{}
+
+// NewIDGenerator returns the process's ID generator. Services share it, so
+// that the identifiers of every table sort in the order they were created
+// across services, not only within one.
 func NewIDGenerator() *IDGenerator {
-	return &IDGenerator{}
+	return generator
 }
 
 // NewID generates a new UUIDv7 identifier from this generator.
 func (g *IDGenerator) NewID() string {
+	return applyIDHook(g.next())
+}
+
+// next generates the generator's next UUIDv7.
+func (g *IDGenerator) next() string {
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
 	now := time.Now().UnixMilli()
-
-	if now == g.lastTime {
+	if now > g.lastTime {
+		g.lastTime = now
+		g.counter = 0
+	} else if g.counter < maxIDCounter {
 		g.counter++
-		if g.counter == 0 {
-			for now == g.lastTime {
-				time.Sleep(time.Microsecond * 100)
-				now = time.Now().UnixMilli()
-			}
-			g.counter = 0
-		}
 	} else {
-		g.lastTime = now
+		// Borrow the next millisecond; the clock catches up
+		g.lastTime++
 		g.counter = 0
 	}
 
-	return generateUUIDv7(now, g.counter)
+	return generateUUIDv7(g.lastTime, g.counter)
 }
 
-var generator = &IDGenerator{}
+// observe moves the generator past a UUIDv7, so that the identifiers it
+// generates next sort after it.
+func (g *IDGenerator) observe(unixMilli int64, counter uint16) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if unixMilli > g.lastTime || (unixMilli == g.lastTime && counter > g.counter) {
+		g.lastTime = unixMilli
+		g.counter = counter
+	}
+}
 
 // NewID generates a new UUIDv7 identifier.
 // UUIDv7 provides time-ordered identifiers for better database index locality.
 func NewID() string {
-	generator.mu.Lock()
-	defer generator.mu.Unlock()
+	return applyIDHook(generator.next())
+}
 
-	// Get current Unix milliseconds
-	now := time.Now().UnixMilli()
+// ObserveID moves ID generation past id, so that identifiers generated
+// from now on sort after it even if the system clock is behind the one
+// that generated it. Identifiers other than UUIDv7, e.g. random UUIDs
+// written by older releases, are ignored.
+func ObserveID(id string) {
+	u, err := uuid.Parse(id)
+	if err != nil || u.Version() != 7 {
+		return
+	}
+	generator.observe(uuidv7Time(u), binary.BigEndian.Uint16(u[6:8])&maxIDCounter)
+}
 
-	// Handle same-millisecond IDs with counter
-	if now == generator.lastTime {
-		generator.counter++
-		if generator.counter == 0 {
-			// Counter overflow, wait for next millisecond
-			for now == generator.lastTime {
-				time.Sleep(time.Microsecond * 100)
-				now = time.Now().UnixMilli()
-			}
-			generator.counter = 0
-		}
-	} else {
-		generator.lastTime = now
-		generator.counter = 0
+// IDTime returns the creation time of a UUIDv7 identifier, to the
+// millisecond, or false for an identifier of another kind, which carries
+// no time.
+func IDTime(id string) (time.Time, bool) {
+	u, err := uuid.Parse(id)
+	if err != nil || u.Version() != 7 {
+		return time.Time{}, false
+	}
+	return time.UnixMilli(uuidv7Time(u)).UTC(), true
+}
+
+// IsTimeOrderedID reports whether id is a UUIDv7, whose string form sorts
+// by creation time.
+func IsTimeOrderedID(id string) bool {
+	_, ok := IDTime(id)
+	return ok
+}
+
+// uuidv7Time returns the Unix time in milliseconds of a UUIDv7.
+func uuidv7Time(u uuid.UUID) int64 {
+	return int64(binary.BigEndian.Uint64(u[0:8]) >> 16)
+}
+
+// IDHook sees every identifier NewID hands out and returns the one to use
+// instead, e.g. to record the IDs of a session or to replay them.
+type IDHook func(id string) string
+
+// idHook is the installed IDHook, if any.
+var idHook atomic.Pointer[IDHook]
+
+// SetIDHook installs a hook on every generated identifier, replacing any
+// installed before. A nil hook removes it.
+func SetIDHook(hook IDHook) {
+	if hook == nil {
+		idHook.Store(nil)
+		return
 	}
+	idHook.Store(&hook)
+}
 
-	return generateUUIDv7(now, generator.counter)
+// applyIDHook passes a generated identifier through the installed hook.
+func applyIDHook(id string) string {
+	if hook := idHook.Load(); hook != nil {
+		return (*hook)(id)
+	}
+	return id
 }
 
 // generateUUIDv7 creates a UUIDv7 from a timestamp and counter.
@@ -85,7 +148,7 @@ func generateUUIDv7(unixMilli int64, counter uint16) string {
 	binary.BigEndian.PutUint32(id[0:4], uint32(unixMilli>>16))
 	binary.BigEndian.PutUint16(id[4:6], uint16(unixMilli))
 
-	// Version (4 bits) + random (12 bits)
+	// Version (4 bits) + counter (12 bits)
 	// Set version to 7
 	id[6] = 0x70 | (byte(counter>>8) & 0x0F)
 	id[7] = byte(counter)
@@ -111,7 +174,8 @@ func NewUUID() string {
 	return uuid.New().String()
 }
 
-// ParseID validates and parses a UUID string.
+// ParseID validates and parses a UUID string. Any UUID is accepted, so
+// the random identifiers of older databases keep working beside UUIDv7s.
 func ParseID(s string) (string, error) {
 	id, err := uuid.Parse(s)
 	if err != nil {
@@ -126,47 +190,6 @@ func IsValidID(s string) bool {
 	return err == nil
 }
 
-// RegistryNumber generates a vault registry number.
-// Format: V{vault_number}-{5-digit sequence}
-// Example: V076-00001
-type RegistryNumberGenerator struct {
-	mu          sync.Mutex
-	vaultNumber int
-	lastSeq     int
-}
-
-// NewRegistryNumberGenerator creates a new registry number generator.
-func NewRegistryNumberGenerator(vaultNumber int) *RegistryNumberGenerator {
-	return &RegistryNumberGenerator{
-		vaultNumber: vaultNumber,
-	}
-}
-
-// SetLastSequence sets the last used sequence number.
-// Call this after loading the highest existing registry number from the database.
-func (r *RegistryNumberGenerator) SetLastSequence(seq int) {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-	r.lastSeq = seq
-}
-
-// Next generates the next registry number.
-func (r *RegistryNumberGenerator) Next() string {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-	r.lastSeq++
-	return fmt.Sprintf("V%03d-%05d", r.vaultNumber, r.lastSeq)
-}
-
-// Parse extracts the vault number and sequence from a registry number.
-func ParseRegistryNumber(regNum string) (vaultNumber, sequence int, err error) {
-	_, err = fmt.Sscanf(regNum, "V%03d-%05d", &vaultNumber, &sequence)
-	if err != nil {
-		return 0, 0, fmt.Errorf("invalid registry number format: %w", err)
-	}
-	return vaultNumber, sequence, nil
-}
-
 // DeterministicID generates a deterministic ID for testing purposes.
 // DO NOT use in production - use NewID() instead.
 func DeterministicID(seed int64) string {
@@ -0,0 +1,50 @@
+package util
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestNewIDTimeOrdered(t *testing.T) {
+	// More than one millisecond's counter, so the generator borrows ahead
+	ids := make([]string, 3*maxIDCounter)
+	for i := range ids {
+		ids[i] = NewIDGenerator().NewID()
+	}
+	if !slices.IsSorted(ids) {
+		t.Fatal("identifiers do not sort in the order they were generated")
+	}
+	if len(slices.Compact(slices.Clone(ids))) != len(ids) {
+		t.Fatal("duplicate identifiers")
+	}
+	for _, id := range ids[:3] {
+		if !IsValidID(id) || !IsTimeOrderedID(id) {
+			t.Fatalf("%s is not a valid UUIDv7", id)
+		}
+	}
+}
+
+func TestIDTime(t *testing.T) {
+	before := time.Now().Add(-time.Millisecond)
+	at, ok := IDTime(NewID())
+	if !ok || at.Before(before) || at.After(time.Now().Add(time.Second)) {
+		t.Errorf("IDTime() = %v, %v, want about now", at, ok)
+	}
+	if _, ok := IDTime(NewUUID()); ok {
+		t.Error("IDTime() read a time from a random UUID")
+	}
+	if _, ok := IDTime("not-an-id"); ok {
+		t.Error("IDTime() read a time from an invalid ID")
+	}
+}
+
+func TestObserveID(t *testing.T) {
+	// An ID from a clock an hour ahead of this one
+	ahead := generateUUIDv7(time.Now().Add(time.Hour).UnixMilli(), 7)
+	ObserveID(ahead)
+	ObserveID(NewUUID()) // Ignored
+	if id := NewID(); id <= ahead {
+		t.Errorf("NewID() = %s, want after %s", id, ahead)
+	}
+}
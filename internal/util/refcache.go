@@ -0,0 +1,68 @@
+package util
+
+import (
+	"context"
+	"sync"
+)
+
+// RefCache holds an in-memory copy of a small reference table, such as
+// resource categories or vocations, that changes rarely but is read on every
+// render and simulation tick. The table is loaded on first use and reloaded
+// after Invalidate, which services call whenever they write to it.
+//
+// Cached values are shared between callers and must not be modified.
+type RefCache[T any] struct {
+	mu     sync.RWMutex
+	load   func(ctx context.Context) ([]T, error)
+	items  []T
+	loaded bool
+}
+
+// NewRefCache creates a cache filled by load.
+func NewRefCache[T any](load func(ctx context.Context) ([]T, error)) *RefCache[T] {
+	return &RefCache[T]{load: load}
+}
+
+// All returns every cached item, loading the table if needed.
+func (c *RefCache[T]) All(ctx context.Context) ([]T, error) {
+	c.mu.RLock()
+	if c.loaded {
+		items := c.items
+		c.mu.RUnlock()
+		return items, nil
+	}
+	c.mu.RUnlock()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.loaded {
+		items, err := c.load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		c.items, c.loaded = items, true
+	}
+	return c.items, nil
+}
+
+// Find returns the first cached item for which match is true.
+func (c *RefCache[T]) Find(ctx context.Context, match func(T) bool) (T, bool, error) {
+	var zero T
+	items, err := c.All(ctx)
+	if err != nil {
+		return zero, false, err
+	}
+	for _, item := range items {
+		if match(item) {
+			return item, true, nil
+		}
+	}
+	return zero, false, nil
+}
+
+// Invalidate drops the cached table so the next read reloads it.
+func (c *RefCache[T]) Invalidate() {
+	c.mu.Lock()
+	c.items, c.loaded = nil, false
+	c.mu.Unlock()
+}
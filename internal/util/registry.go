@@ -0,0 +1,234 @@
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultRegistryFormat is the registry number format of vaults that do
+// not configure one, e.g. V076-00001.
+const DefaultRegistryFormat = "V{vault:03d}-{seq:05d}"
+
+// registryFields are the placeholders a registry format may hold.
+var registryFields = []string{"vault", "year", "seq"}
+
+// registryPlaceholder matches a placeholder, e.g. {seq} or {seq:05d}.
+var registryPlaceholder = regexp.MustCompile(`\{([a-z]+)(?::0([1-9])d)?\}`)
+
+// registryPart is a literal or a placeholder of a registry format.
+type registryPart struct {
+	literal string
+	field   string // "" for a literal
+	width   int    // Zero-padded width of the field, 0 for none
+}
+
+// RegistryFormat is a template of registry numbers, e.g.
+// "V{vault:03d}-{seq:05d}" or "{vault}-{year}-{seq}". {vault} is the vault
+// number, {year} the year of issue and {seq} the vault's sequence, which
+// runs on across years. ":0Nd" pads a number with zeros to N digits, N
+// from 1 to 9.
+type RegistryFormat struct {
+	template string
+	parts    []registryPart
+	pattern  *regexp.Regexp
+}
+
+// ParseRegistryFormat parses a registry number template. It must hold
+// {seq} once, may hold {vault} and {year} once each, and needs literal text
+// between placeholders so that numbers can be read back.
+func ParseRegistryFormat(template string) (*RegistryFormat, error) {
+	f := &RegistryFormat{template: template}
+	seen := make(map[string]bool)
+	pattern := "^"
+	rest := template
+	for rest != "" {
+		loc := registryPlaceholder.FindStringSubmatchIndex(rest)
+		if loc == nil || loc[0] > 0 {
+			end := len(rest)
+			if loc != nil {
+				end = loc[0]
+			}
+			literal := rest[:end]
+			if strings.ContainsAny(literal, "{}") {
+				return nil, fmt.Errorf("registry format %q: invalid placeholder in %q", template, literal)
+			}
+			f.parts = append(f.parts, registryPart{literal: literal})
+			pattern += regexp.QuoteMeta(literal)
+			rest = rest[end:]
+			continue
+		}
+
+		field := rest[loc[2]:loc[3]]
+		if !slices.Contains(registryFields, field) {
+			return nil, fmt.Errorf("registry format %q: unknown placeholder {%s}", template, field)
+		}
+		if seen[field] {
+			return nil, fmt.Errorf("registry format %q: {%s} appears more than once", template, field)
+		}
+		seen[field] = true
+		if n := len(f.parts); n > 0 && f.parts[n-1].field != "" {
+			return nil, fmt.Errorf("registry format %q: placeholders must be separated by text", template)
+		}
+		part := registryPart{field: field}
+		if loc[4] >= 0 {
+			part.width, _ = strconv.Atoi(rest[loc[4]:loc[5]])
+		}
+		f.parts = append(f.parts, part)
+		pattern += `(\d+)`
+		rest = rest[loc[1]:]
+	}
+	if !seen["seq"] {
+		return nil, fmt.Errorf("registry format %q: {seq} is required", template)
+	}
+	f.pattern = regexp.MustCompile(pattern + "$")
+	return f, nil
+}
+
+// MustParseRegistryFormat parses a registry format known to be valid,
+// panicking if it is not.
+func MustParseRegistryFormat(template string) *RegistryFormat {
+	f, err := ParseRegistryFormat(template)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// String returns the format's template.
+func (f *RegistryFormat) String() string {
+	return f.template
+}
+
+// HasVault reports whether the format holds the vault number, which tells
+// apart the registry numbers of vaults sharing a database.
+func (f *RegistryFormat) HasVault() bool {
+	return slices.ContainsFunc(f.parts, func(p registryPart) bool { return p.field == "vault" })
+}
+
+// Format returns the registry number of a vault's sequence number, issued
+// in year.
+func (f *RegistryFormat) Format(vaultNumber, year, seq int) string {
+	var b strings.Builder
+	for _, p := range f.parts {
+		var n int
+		switch p.field {
+		case "":
+			b.WriteString(p.literal)
+			continue
+		case "vault":
+			n = vaultNumber
+		case "year":
+			n = year
+		case "seq":
+			n = seq
+		}
+		fmt.Fprintf(&b, "%0*d", p.width, n)
+	}
+	return b.String()
+}
+
+// Sequence returns the sequence number of a registry number in the format
+// issued by the vault, or false if it is not one.
+func (f *RegistryFormat) Sequence(regNum string, vaultNumber int) (int, bool) {
+	match := f.pattern.FindStringSubmatch(regNum)
+	if match == nil {
+		return 0, false
+	}
+	seq := 0
+	i := 1
+	for _, p := range f.parts {
+		if p.field == "" {
+			continue
+		}
+		n, err := strconv.Atoi(match[i])
+		i++
+		if err != nil {
+			return 0, false
+		}
+		switch p.field {
+		case "vault":
+			if n != vaultNumber {
+				return 0, false
+			}
+		case "seq":
+			seq = n
+		}
+	}
+	return seq, true
+}
+
+// RegistryNumberGenerator issues the registry numbers of a vault in its
+// format. It counts in memory from the last sequence set, for a single
+// writer such as the seed generator; services reserve sequence numbers
+// in the database and only format them here.
+type RegistryNumberGenerator struct {
+	mu          sync.Mutex
+	vaultNumber int
+	format      *RegistryFormat
+	lastSeq     int
+}
+
+// NewRegistryNumberGenerator creates a registry number generator in the
+// default format.
+func NewRegistryNumberGenerator(vaultNumber int) *RegistryNumberGenerator {
+	return &RegistryNumberGenerator{
+		vaultNumber: vaultNumber,
+		format:      MustParseRegistryFormat(DefaultRegistryFormat),
+	}
+}
+
+// SetFormat sets the format of the registry numbers issued.
+func (r *RegistryNumberGenerator) SetFormat(format *RegistryFormat) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.format = format
+}
+
+// Format returns the format of the registry numbers issued.
+func (r *RegistryNumberGenerator) Format() *RegistryFormat {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.format
+}
+
+// VaultNumber returns the number of the vault the numbers are issued by.
+func (r *RegistryNumberGenerator) VaultNumber() int {
+	return r.vaultNumber
+}
+
+// SetLastSequence sets the last used sequence number.
+func (r *RegistryNumberGenerator) SetLastSequence(seq int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lastSeq = seq
+}
+
+// LastSequence returns the last sequence number issued.
+func (r *RegistryNumberGenerator) LastSequence() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.lastSeq
+}
+
+// Next generates the next registry number, issued in year.
+func (r *RegistryNumberGenerator) Next(year int) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lastSeq++
+	return r.format.Format(r.vaultNumber, year, r.lastSeq)
+}
+
+// Number returns the registry number of a sequence number issued in year.
+func (r *RegistryNumberGenerator) Number(seq, year int) string {
+	return r.Format().Format(r.vaultNumber, year, seq)
+}
+
+// Sequence returns the sequence number of one of the vault's registry
+// numbers, or false if it is not in the format.
+func (r *RegistryNumberGenerator) Sequence(regNum string) (int, bool) {
+	return r.Format().Sequence(regNum, r.vaultNumber)
+}
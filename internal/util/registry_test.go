@@ -0,0 +1,72 @@
+package util
+
+import "testing"
+
+func TestRegistryFormat(t *testing.T) {
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{DefaultRegistryFormat, "V076-00042"},
+		{"{vault}-{year}-{seq}", "76-2079-42"},
+		{"R{year:04d}/{seq:06d}", "R2079/000042"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			f, err := ParseRegistryFormat(tt.template)
+			if err != nil {
+				t.Fatalf("ParseRegistryFormat() error = %v", err)
+			}
+			got := f.Format(76, 2079, 42)
+			if got != tt.want {
+				t.Errorf("Format() = %q, want %q", got, tt.want)
+			}
+			if seq, ok := f.Sequence(got, 76); !ok || seq != 42 {
+				t.Errorf("Sequence(%q) = %d, %v, want 42, true", got, seq, ok)
+			}
+		})
+	}
+
+	f := MustParseRegistryFormat(DefaultRegistryFormat)
+	if _, ok := f.Sequence("V081-00042", 76); ok {
+		t.Error("Sequence() read the number of another vault")
+	}
+	if _, ok := f.Sequence("X-00042", 76); ok {
+		t.Error("Sequence() read a number in another format")
+	}
+	if seq, ok := f.Sequence("V076-123456", 76); !ok || seq != 123456 {
+		t.Errorf("Sequence() of an overflowing number = %d, %v, want 123456, true", seq, ok)
+	}
+}
+
+func TestParseRegistryFormat_Invalid(t *testing.T) {
+	for _, template := range []string{
+		"",
+		"V{vault}",      // no sequence
+		"{seq}{year}",   // nothing between placeholders
+		"{seq}-{seq}",   // sequence twice
+		"{seq}-{month}", // unknown placeholder
+		"{seq:5d}",      // padding without a zero
+		"{seq}-{vault",  // unclosed placeholder
+		"{seq}}",        // stray brace
+	} {
+		if _, err := ParseRegistryFormat(template); err == nil {
+			t.Errorf("ParseRegistryFormat(%q) accepted an invalid format", template)
+		}
+	}
+}
+
+func TestRegistryNumberGenerator(t *testing.T) {
+	gen := NewRegistryNumberGenerator(76)
+	gen.SetLastSequence(9)
+	if got := gen.Next(2079); got != "V076-00010" {
+		t.Errorf("Next() = %q, want V076-00010", got)
+	}
+	gen.SetFormat(MustParseRegistryFormat("{vault}-{year}-{seq:03d}"))
+	if got := gen.Next(2080); got != "76-2080-011" {
+		t.Errorf("Next() = %q, want 76-2080-011", got)
+	}
+	if got := gen.LastSequence(); got != 11 {
+		t.Errorf("LastSequence() = %d, want 11", got)
+	}
+}
@@ -118,14 +118,26 @@ func (vc *VaultClock) SetTime(t time.Time) error {
 	return nil
 }
 
-// FormatDate formats a time as a date string.
+// FormatDate formats a date for display on the current calendar.
 func FormatDate(t time.Time) string {
-	return t.Format(DateFormat)
+	return CurrentCalendar().Date(t)
 }
 
-// FormatDateTime formats a time as a datetime string.
+// FormatDay formats the date an instant falls on in the vault's time zone.
+func FormatDay(t time.Time) string {
+	return CurrentCalendar().Day(t)
+}
+
+// FormatTime formats the time of day of an instant in the vault's time
+// zone.
+func FormatTime(t time.Time) string {
+	return CurrentCalendar().Time(t)
+}
+
+// FormatDateTime formats an instant for display on the current calendar,
+// in the vault's time zone.
 func FormatDateTime(t time.Time) string {
-	return t.Format(DateTimeFormat)
+	return CurrentCalendar().DateTime(t)
 }
 
 // FormatISO8601 formats a time as an ISO8601/RFC3339 string.
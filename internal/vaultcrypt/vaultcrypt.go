@@ -0,0 +1,257 @@
+// Package vaultcrypt encrypts sensitive text columns, such as resident
+// notes and medical findings, at rest. Values are sealed with AES-256-GCM
+// under a data key; data keys are stored in the database wrapped (sealed)
+// by a master key kept outside it, in the environment or a key file.
+// Values written before encryption was enabled are read as they are.
+package vaultcrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// KeySize is the size in bytes of master and data keys.
+const KeySize = 32
+
+// sealedPrefix starts every sealed value, followed by the data key ID, a
+// colon and the base64 nonce and ciphertext.
+const sealedPrefix = "vc1:"
+
+// wrapLabel is the additional data of wrapped data keys, so that a wrapped
+// key cannot pass for a sealed column value.
+const wrapLabel = "vtuos data key"
+
+var (
+	// ErrNoKey is returned when reading a sealed value without the data key
+	// that sealed it, e.g. with encryption disabled.
+	ErrNoKey = errors.New("value is encrypted and its key is not loaded")
+
+	// ErrWrongKey is returned when a master key does not open a data key
+	// or a data key does not open a value.
+	ErrWrongKey = errors.New("encryption key does not match")
+)
+
+// ParseMasterKey decodes a master key written as base64, as printed by
+// NewKeyString.
+func ParseMasterKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("master key is not base64: %w", err)
+	}
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("master key must be %d bytes, got %d", KeySize, len(key))
+	}
+	return key, nil
+}
+
+// NewKey returns a random key.
+func NewKey() ([]byte, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("generating key: %w", err)
+	}
+	return key, nil
+}
+
+// NewKeyString returns a random master key written as base64.
+func NewKeyString() (string, error) {
+	key, err := NewKey()
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// WrapKey seals a data key under a master key for storing.
+func WrapKey(master, dataKey []byte) (string, error) {
+	aead, err := newAEAD(master)
+	if err != nil {
+		return "", err
+	}
+	return seal(aead, dataKey, wrapLabel)
+}
+
+// UnwrapKey opens a data key wrapped by WrapKey.
+func UnwrapKey(master []byte, wrapped string) ([]byte, error) {
+	aead, err := newAEAD(master)
+	if err != nil {
+		return nil, err
+	}
+	return open(aead, wrapped, wrapLabel)
+}
+
+// Keyring holds the data keys of a database by ID. New values are sealed
+// with the primary key; any key held opens the values it sealed.
+type Keyring struct {
+	keys    map[string]cipher.AEAD
+	primary string
+}
+
+// NewKeyring creates an empty keyring.
+func NewKeyring() *Keyring {
+	return &Keyring{keys: make(map[string]cipher.AEAD)}
+}
+
+// Add adds a data key. The first key added becomes the primary key.
+func (k *Keyring) Add(id string, dataKey []byte) error {
+	if id == "" || strings.Contains(id, ":") {
+		return fmt.Errorf("invalid data key ID %q", id)
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return err
+	}
+	k.keys[id] = aead
+	if k.primary == "" {
+		k.primary = id
+	}
+	return nil
+}
+
+// SetPrimary makes a key held the one new values are sealed with.
+func (k *Keyring) SetPrimary(id string) error {
+	if _, ok := k.keys[id]; !ok {
+		return fmt.Errorf("data key %s is not in the keyring", id)
+	}
+	k.primary = id
+	return nil
+}
+
+// Primary returns the ID of the primary key, "" for an empty keyring.
+func (k *Keyring) Primary() string {
+	return k.primary
+}
+
+// Seal encrypts the value of a column, named "table.column", with the
+// primary key. The column is bound to the ciphertext, so a value copied
+// into another column does not open. An empty value stays empty.
+func (k *Keyring) Seal(column, plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+	aead, ok := k.keys[k.primary]
+	if !ok {
+		return "", errors.New("no data key to encrypt with")
+	}
+	sealed, err := seal(aead, []byte(plaintext), column)
+	if err != nil {
+		return "", err
+	}
+	return sealedPrefix + k.primary + ":" + sealed, nil
+}
+
+// Open decrypts a value of a column sealed by Seal. A value that is not
+// sealed, written before encryption was enabled, is returned as it is.
+func (k *Keyring) Open(column, value string) (string, error) {
+	id, sealed, ok := split(value)
+	if !ok {
+		return value, nil
+	}
+	aead, held := k.keys[id]
+	if !held {
+		return "", fmt.Errorf("%w: data key %s", ErrNoKey, id)
+	}
+	plaintext, err := open(aead, sealed, column)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", column, err)
+	}
+	return string(plaintext), nil
+}
+
+// IsSealed reports whether a stored value is sealed.
+func IsSealed(value string) bool {
+	_, _, ok := split(value)
+	return ok
+}
+
+// KeyID returns the ID of the data key that sealed a value, or false for a
+// value that is not sealed.
+func KeyID(value string) (string, bool) {
+	id, _, ok := split(value)
+	return id, ok
+}
+
+// split parses a sealed value into its data key ID and ciphertext.
+func split(value string) (id, sealed string, ok bool) {
+	rest, found := strings.CutPrefix(value, sealedPrefix)
+	if !found {
+		return "", "", false
+	}
+	return strings.Cut(rest, ":")
+}
+
+// installed is the keyring of the open database, if encryption is enabled.
+var installed atomic.Pointer[Keyring]
+
+// Install makes a keyring the one Seal and Open use, replacing any
+// installed before. A nil keyring removes it, disabling encryption.
+func Install(k *Keyring) {
+	installed.Store(k)
+}
+
+// Installed returns the installed keyring, nil when encryption is disabled.
+func Installed() *Keyring {
+	return installed.Load()
+}
+
+// Seal encrypts the value of a column with the installed keyring, or
+// returns it as it is when encryption is disabled.
+func Seal(column, plaintext string) (string, error) {
+	if k := installed.Load(); k != nil {
+		return k.Seal(column, plaintext)
+	}
+	return plaintext, nil
+}
+
+// Open decrypts the value of a column with the installed keyring. A value
+// that is not sealed is returned as it is; a sealed value fails with
+// ErrNoKey when encryption is disabled.
+func Open(column, value string) (string, error) {
+	if k := installed.Load(); k != nil {
+		return k.Open(column, value)
+	}
+	if IsSealed(value) {
+		return "", ErrNoKey
+	}
+	return value, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("key must be %d bytes, got %d", KeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts plaintext under a random nonce, returning the nonce and
+// ciphertext as base64.
+func seal(aead cipher.AEAD, plaintext []byte, label string) (string, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("generating nonce: %w", err)
+	}
+	return base64.RawStdEncoding.EncodeToString(aead.Seal(nonce, nonce, plaintext, []byte(label))), nil
+}
+
+// open decrypts the output of seal.
+func open(aead cipher.AEAD, sealed, label string) ([]byte, error) {
+	data, err := base64.RawStdEncoding.DecodeString(sealed)
+	if err != nil || len(data) < aead.NonceSize() {
+		return nil, fmt.Errorf("malformed encrypted value")
+	}
+	plaintext, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], []byte(label))
+	if err != nil {
+		return nil, ErrWrongKey
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,118 @@
+package vaultcrypt
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestKeyringSealOpen(t *testing.T) {
+	key, _ := NewKey()
+	k := NewKeyring()
+	if err := k.Add("k1", key); err != nil {
+		t.Fatal(err)
+	}
+
+	sealed, err := k.Seal("residents.notes", "Allergic to Nuka-Cola")
+	if err != nil {
+		t.Fatalf("Seal() error = %v", err)
+	}
+	if !strings.HasPrefix(sealed, "vc1:k1:") || strings.Contains(sealed, "Nuka") {
+		t.Fatalf("Seal() = %q, want an encrypted value of key k1", sealed)
+	}
+	if got, err := k.Open("residents.notes", sealed); err != nil || got != "Allergic to Nuka-Cola" {
+		t.Errorf("Open() = %q, %v", got, err)
+	}
+	if _, err := k.Open("medical_conditions.notes", sealed); !errors.Is(err, ErrWrongKey) {
+		t.Errorf("Open() in another column error = %v, want ErrWrongKey", err)
+	}
+	if got, err := k.Open("residents.notes", "written before encryption"); err != nil || got != "written before encryption" {
+		t.Errorf("Open() of plaintext = %q, %v", got, err)
+	}
+	if got, _ := k.Seal("residents.notes", ""); got != "" {
+		t.Errorf("Seal() of an empty value = %q, want empty", got)
+	}
+
+	// After rotation the old key still opens what it sealed
+	newKey, _ := NewKey()
+	if err := k.Add("k2", newKey); err != nil {
+		t.Fatal(err)
+	}
+	if err := k.SetPrimary("k2"); err != nil {
+		t.Fatal(err)
+	}
+	if id, _ := KeyID(mustSeal(t, k, "x")); id != "k2" {
+		t.Errorf("sealed with key %s, want k2", id)
+	}
+	if got, err := k.Open("residents.notes", sealed); err != nil || got != "Allergic to Nuka-Cola" {
+		t.Errorf("Open() with a retired key = %q, %v", got, err)
+	}
+	if _, err := NewKeyring().Open("residents.notes", sealed); !errors.Is(err, ErrNoKey) {
+		t.Errorf("Open() without the key error = %v, want ErrNoKey", err)
+	}
+}
+
+func TestWrapKey(t *testing.T) {
+	master, _ := NewKey()
+	other, _ := NewKey()
+	dataKey, _ := NewKey()
+
+	wrapped, err := WrapKey(master, dataKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := UnwrapKey(master, wrapped)
+	if err != nil || string(got) != string(dataKey) {
+		t.Errorf("UnwrapKey() = %x, %v, want %x", got, err, dataKey)
+	}
+	if _, err := UnwrapKey(other, wrapped); !errors.Is(err, ErrWrongKey) {
+		t.Errorf("UnwrapKey() with another master key error = %v, want ErrWrongKey", err)
+	}
+}
+
+func TestParseMasterKey(t *testing.T) {
+	encoded, err := NewKeyString()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseMasterKey(encoded + "\n"); err != nil {
+		t.Errorf("ParseMasterKey() error = %v", err)
+	}
+	for _, bad := range []string{"", "not base64!", "c2hvcnQ="} {
+		if _, err := ParseMasterKey(bad); err == nil {
+			t.Errorf("ParseMasterKey(%q) accepted an invalid key", bad)
+		}
+	}
+}
+
+func TestInstalled(t *testing.T) {
+	key, _ := NewKey()
+	k := NewKeyring()
+	if err := k.Add("k1", key); err != nil {
+		t.Fatal(err)
+	}
+	sealed := mustSeal(t, k, "notes")
+
+	Install(nil)
+	if got, _ := Seal("residents.notes", "notes"); got != "notes" {
+		t.Errorf("Seal() without a keyring = %q, want plaintext", got)
+	}
+	if _, err := Open("residents.notes", sealed); !errors.Is(err, ErrNoKey) {
+		t.Errorf("Open() without a keyring error = %v, want ErrNoKey", err)
+	}
+
+	Install(k)
+	defer Install(nil)
+	if got, err := Open("residents.notes", sealed); err != nil || got != "notes" {
+		t.Errorf("Open() = %q, %v", got, err)
+	}
+}
+
+func mustSeal(t *testing.T, k *Keyring, plaintext string) string {
+	t.Helper()
+	sealed, err := k.Seal("residents.notes", plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sealed
+}